@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
@@ -9,9 +10,43 @@ import (
 	"syscall"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/address"
+	"github.com/rbonfanti/shipping-calculator/internal/bootstrap"
+	"github.com/rbonfanti/shipping-calculator/internal/bulk"
+	"github.com/rbonfanti/shipping-calculator/internal/chaos"
+	"github.com/rbonfanti/shipping-calculator/internal/eta"
+	"github.com/rbonfanti/shipping-calculator/internal/events"
 	"github.com/rbonfanti/shipping-calculator/internal/handler"
+	"github.com/rbonfanti/shipping-calculator/internal/health"
+	"github.com/rbonfanti/shipping-calculator/internal/holiday"
+	"github.com/rbonfanti/shipping-calculator/internal/label"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/manifest"
+	"github.com/rbonfanti/shipping-calculator/internal/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/notification"
+	"github.com/rbonfanti/shipping-calculator/internal/packing"
+	"github.com/rbonfanti/shipping-calculator/internal/pickup"
+	"github.com/rbonfanti/shipping-calculator/internal/pricingreload"
+	"github.com/rbonfanti/shipping-calculator/internal/quotestats"
+	"github.com/rbonfanti/shipping-calculator/internal/reconciliation"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/repository/postgres"
+	"github.com/rbonfanti/shipping-calculator/internal/repricing"
+	"github.com/rbonfanti/shipping-calculator/internal/runtimestats"
+	"github.com/rbonfanti/shipping-calculator/internal/scheduler"
+	"github.com/rbonfanti/shipping-calculator/internal/secrets"
+	apiserver "github.com/rbonfanti/shipping-calculator/internal/server"
 	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/rbonfanti/shipping-calculator/internal/subscription"
+	"github.com/rbonfanti/shipping-calculator/internal/tracking"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	v2 "github.com/rbonfanti/shipping-calculator/internal/transport/v2"
+	"github.com/rbonfanti/shipping-calculator/internal/usage"
+	"github.com/rbonfanti/shipping-calculator/internal/warmup"
+	"github.com/rbonfanti/shipping-calculator/internal/webhook"
+	"github.com/rbonfanti/shipping-calculator/pkg/quotetoken"
 	"github.com/rbonfanti/shipping-calculator/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
@@ -24,72 +59,641 @@ func main() {
 	ctx := context.Background()
 
 	// Initialize OpenTelemetry
-	shutdown, err := telemetry.InitOpenTelemetry(ctx)
+	tel, err := telemetry.New(ctx)
 	if err != nil {
 		log.Fatalf("Failed to initialize OpenTelemetry: %v", err)
 	}
 	defer func() {
-		if err := shutdown(); err != nil {
+		if err := tel.Shutdown(context.Background()); err != nil {
 			log.Printf("Error shutting down OpenTelemetry: %v", err)
 		}
 	}()
+	metrics := tel.Metrics
 
 	// Initialize logger
-	logger, err := zap.NewProduction()
+	logConfig, err := logger.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid logger configuration: %v", err)
+	}
+	zapLogger, err := logger.New(logConfig)
 	if err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
-	defer logger.Sync()
+	defer zapLogger.Sync()
+	zap.ReplaceGlobals(zapLogger)
+
+	serverConfig, err := apiserver.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid server configuration", zap.Error(err))
+	}
+	debugConfig, err := apiserver.DebugConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid debug server configuration", zap.Error(err))
+	}
+
+	accessLogConfig, err := middleware.AccessLogConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid access log configuration", zap.Error(err))
+	}
+
+	corsConfig, err := middleware.CORSConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid CORS configuration", zap.Error(err))
+	}
+
+	compressionConfig, err := middleware.CompressionConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid compression configuration", zap.Error(err))
+	}
+	maxBodyBytes, err := middleware.MaxBodyBytesFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid request body limit", zap.Error(err))
+	}
+
+	timeoutConfig, err := middleware.TimeoutConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid request timeout configuration", zap.Error(err))
+	}
+
+	overloadConfig, err := middleware.OverloadConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid overload protection configuration", zap.Error(err))
+	}
+
+	slowQuoteConfig, err := middleware.SlowQuoteConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid slow quote log configuration", zap.Error(err))
+	}
+
+	strictSchemaConfig, err := middleware.StrictSchemaConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid strict schema configuration", zap.Error(err))
+	}
+
+	deprecationConfig, err := middleware.DeprecationConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid API deprecation configuration", zap.Error(err))
+	}
+
+	clientIDConfig := middleware.ClientIDConfigFromEnv()
+
+	adminConfig, err := middleware.AdminConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid admin configuration", zap.Error(err))
+	}
+
+	pricingReloadConfig, err := pricingreload.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid pricing reload configuration", zap.Error(err))
+	}
+
+	// Initialize the fault injection of the game days, wrapping the providers and the quote store
+	// only when enabled
+	chaosConfig, err := chaos.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid chaos configuration", zap.Error(err))
+	}
+	if chaosConfig.Enabled && !adminConfig.Enabled() {
+		zapLogger.Fatal("CHAOS_ENABLED requires ADMIN_TOKENS to control the faults")
+	}
+	var faults *chaos.Injector
+	if chaosConfig.Enabled {
+		faults = chaos.NewInjector()
+		zapLogger.Warn("Fault injection enabled, faults can be set through /admin/chaos")
+	}
+
+	// Initialize the quote store, shared by the quotes, the quote cache and the other state kept
+	// between requests
+	storeConfig, err := store.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid quote store configuration", zap.Error(err))
+	}
+	quoteStore, closeStore, err := store.New(ctx, storeConfig, tel.Tracer, metrics)
+	if err != nil {
+		zapLogger.Fatal("Failed to connect to the quote store", zap.Error(err))
+	}
+	if faults != nil {
+		quoteStore = chaos.WrapQuoteStore(quoteStore, faults)
+	}
+
+	// Initialize the shipping service (pricing, delivery estimates, holiday calendar and quote cache)
+	shipping, err := bootstrap.NewShipping(ctx, faults, quoteStore, metrics)
+	if err != nil {
+		zapLogger.Fatal("Failed to initialize shipping service", zap.Error(err))
+	}
+	shippingService := shipping.Service
+	if shipping.Determinism.Enabled() {
+		zapLogger.Warn("Deterministic mode enabled, quotes are stamped with a pinned clock and seeded IDs",
+			zap.Time("now", shipping.Clock.Now()),
+		)
+	}
+
+	// Initialize pickup points and lockers offered for pickup_point and locker delivery
+	pickupConfig := pickup.Config{}
+	if path := os.Getenv("PICKUP_POINTS_PATH"); path != "" {
+		if pickupConfig, err = pickup.LoadConfig(path); err != nil {
+			zapLogger.Fatal("Failed to load pickup points configuration", zap.Error(err))
+		}
+	}
+
+	// Initialize the box catalog used to suggest packing
+	packingConfig := packing.DefaultConfig()
+	if path := os.Getenv("PACKING_BOXES_PATH"); path != "" {
+		if packingConfig, err = packing.LoadConfig(path); err != nil {
+			zapLogger.Fatal("Failed to load box catalog", zap.Error(err))
+		}
+	}
+
+	// Initialize zipcode lookup used to validate addresses before quoting
+	addressConfig, err := address.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid address lookup configuration", zap.Error(err))
+	}
+
+	warmupConfig, err := warmup.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid warm-up configuration", zap.Error(err))
+	}
+
+	subscriptionConfig, err := subscription.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid price subscription configuration", zap.Error(err))
+	}
+
+	bulkConfig, err := bulk.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid bulk quoting configuration", zap.Error(err))
+	}
+
+	// Initialize quote, shipment and usage persistence in PostgreSQL when DATABASE_URL is set, or quotes
+	// in the configured store otherwise, encrypting sensitive fields when keys are configured
+	quoteConfig, err := repository.QuoteConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid quote configuration", zap.Error(err))
+	}
+	postgresConfig, err := postgres.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid PostgreSQL configuration", zap.Error(err))
+	}
+	var quotes repository.QuoteRepository = repository.NewStoreQuoteRepository(quoteStore, storeConfig.QuoteTTL)
+	var shipments repository.ShipmentRepository = repository.NewMemoryShipmentRepository()
+	var usageRepo repository.UsageRepository = repository.NewMemoryUsageRepository()
+	var pricingVersions repository.PricingVersionRepository = repository.NewMemoryPricingVersionRepository()
+	var labels repository.LabelRepository = repository.NewMemoryLabelRepository()
+	var manifests repository.ManifestRepository = repository.NewMemoryManifestRepository()
+	var webhooks repository.WebhookRepository = repository.NewMemoryWebhookRepository()
+	var jobRuns repository.JobRunRepository = repository.NewMemoryJobRunRepository()
+	var trackingRepo repository.TrackingRepository = repository.NewMemoryTrackingRepository()
+	var probes []health.Probe
+	if pinger, ok := quoteStore.(store.Pinger); ok && storeConfig.Backend == store.BackendRedis {
+		probes = append(probes, health.Probe{Name: "redis", Required: true, Check: pinger.Ping})
+	}
+	closeDatabase := func() {}
+	if postgresConfig.Enabled() {
+		pool, err := postgres.Open(ctx, postgresConfig)
+		if err != nil {
+			zapLogger.Fatal("Failed to connect to PostgreSQL", zap.Error(err))
+		}
+		closeDatabase = pool.Close
+		quotes = postgres.NewQuoteRepository(pool)
+		shipments = postgres.NewShipmentRepository(pool)
+		usageRepo = postgres.NewUsageRepository(pool)
+		pricingVersions = postgres.NewPricingVersionRepository(pool)
+		labels = postgres.NewLabelRepository(pool)
+		manifests = postgres.NewManifestRepository(pool)
+		webhooks = postgres.NewWebhookRepository(pool)
+		jobRuns = postgres.NewJobRunRepository(pool)
+		trackingRepo = postgres.NewTrackingRepository(pool)
+		probes = append(probes, health.Probe{Name: "postgres", Required: true, Check: pool.Ping})
+	}
+	if os.Getenv("QUOTE_ENCRYPTION_KEYS") != "" {
+		keyring, err := secrets.NewKeyring(ctx, secrets.EnvProvider{Variable: "QUOTE_ENCRYPTION_KEYS"})
+		if err != nil {
+			zapLogger.Fatal("Failed to load quote encryption keys", zap.Error(err))
+		}
+		quotes = repository.NewEncryptedQuoteRepository(quotes, keyring)
+	}
+	var quoteSigner handler.QuoteSigner
+	if os.Getenv("QUOTE_TOKEN_KEYS") != "" {
+		keys, err := secrets.EnvProvider{Variable: "QUOTE_TOKEN_KEYS"}.Keys(ctx)
+		if err != nil {
+			zapLogger.Fatal("Failed to load quote token keys", zap.Error(err))
+		}
+		tokenKeys := make([]quotetoken.Key, 0, len(keys))
+		for _, key := range keys {
+			tokenKeys = append(tokenKeys, quotetoken.Key{ID: key.ID, Secret: key.Material})
+		}
+		signer, err := quotetoken.NewSigner(tokenKeys)
+		if err != nil {
+			zapLogger.Fatal("Invalid quote token keys", zap.Error(err))
+		}
+		quoteSigner = signer
+	}
+
+	// Initialize shipment booking and tracking
+	trackingConfig, err := tracking.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid tracking configuration", zap.Error(err))
+	}
+	var trackingProvider tracking.TrackingProvider
+	if trackingConfig.ProviderURL != "" {
+		trackingProvider = tracking.NewHTTPProvider(trackingConfig)
+		if faults != nil {
+			trackingProvider = chaos.WrapTrackingProvider(trackingProvider, faults)
+		}
+	}
+	labelConfig, err := label.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid label provider configuration", zap.Error(err))
+	}
+	manifestConfig, err := manifest.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid manifest configuration", zap.Error(err))
+	}
+	var manifestSubmitter manifest.Submitter
+	if manifestConfig.Enabled() {
+		manifestSubmitter = manifest.NewHTTPSubmitter(manifestConfig)
+		if faults != nil {
+			manifestSubmitter = chaos.WrapManifestSubmitter(manifestSubmitter, faults)
+		}
+	}
+	eventsConfig, err := events.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid events configuration", zap.Error(err))
+	}
+	publisher, closePublisher, err := events.NewPublisher(eventsConfig, zapLogger)
+	if err != nil {
+		zapLogger.Fatal("Failed to connect to the events broker", zap.Error(err))
+	}
+	// Publish to the broker in the background, so that a slow broker does not delay the quotes
+	asyncPublisher := events.NewAsyncPublisher(publisher, eventsConfig.QueueSize, zapLogger)
+	publisher = asyncPublisher
+
+	// Notify the webhook subscriptions of the merchants of the events of their tenant
+	webhookConfig, err := webhook.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid webhook configuration", zap.Error(err))
+	}
+	// The subscription secrets are encrypted at rest; without keys merchants cannot subscribe
+	webhookKeysVariable := "WEBHOOK_ENCRYPTION_KEYS"
+	if os.Getenv(webhookKeysVariable) == "" {
+		webhookKeysVariable = "QUOTE_ENCRYPTION_KEYS"
+	}
+	webhookSubscriptions := os.Getenv(webhookKeysVariable) != ""
+	if webhookSubscriptions {
+		keyring, err := secrets.NewKeyring(ctx, secrets.EnvProvider{Variable: webhookKeysVariable})
+		if err != nil {
+			zapLogger.Fatal("Failed to load webhook encryption keys", zap.Error(err))
+		}
+		webhooks = repository.NewEncryptedWebhookRepository(webhooks, keyring)
+	} else {
+		zapLogger.Warn("Webhook subscriptions disabled: set WEBHOOK_ENCRYPTION_KEYS to encrypt their secrets")
+	}
+	webhookDispatcher := webhook.NewDispatcher(webhookConfig, webhooks, zapLogger)
+	publisher = webhook.NewPublisher(publisher, webhookDispatcher)
 
-	// Initialize services
-	shippingService := service.NewShippingService()
+	// Alert the channels configured for the tenants of the delivery exceptions of their shipments
+	notificationConfig, err := notification.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid notification configuration", zap.Error(err))
+	}
+	if err := notificationConfig.CheckTenants(shipping.Tenants); err != nil {
+		zapLogger.Fatal("Invalid notification configuration", zap.Error(err))
+	}
+	notifier := notification.NewNotifier(notificationConfig, zapLogger)
+	if notificationConfig.Enabled() {
+		publisher = notification.NewPublisher(publisher, notifier)
+	}
+
+	// Measure the deliveries against the delivery dates promised by their quotes
+	slaService := service.NewSLAService(shipments, quotes, trackingRepo, manifestConfig, metrics)
+	publisher = service.NewSLAPublisher(publisher, slaService)
+	// Book the scheduled pickups within the capacity of their windows, checked when quoting too
+	shipping.Scheduler.WithBookings(shipments)
+	shipmentService := service.NewShipmentService(quotes, shipments, publisher).
+		WithScheduler(shipping.Scheduler).
+		WithDeterminism(shipping.Clock, shipping.IDs)
+	var labelProvider label.LabelProvider = label.NewHTTPProvider(labelConfig)
+	if faults != nil {
+		labelProvider = chaos.WrapLabelProvider(labelProvider, faults)
+	}
+	labelService := service.NewLabelService(shipments, labels, labelProvider)
+	manifestService := service.NewManifestService(shipments, manifests, manifestConfig, manifestSubmitter)
+	trackingService := service.NewTrackingService(shipments, trackingRepo, trackingProvider, publisher)
+
+	// Initialize the usage of the tenants, counted towards their monthly quotas
+	usageMeter := usage.NewMeter(usageRepo, shipping.Tenants)
+
+	// Initialize invoice reconciliation
+	reconciliationConfig, err := reconciliation.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid reconciliation configuration", zap.Error(err))
+	}
+	reconciliationJobConfig, err := reconciliation.JobConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid reconciliation job configuration", zap.Error(err))
+	}
+	repricingConfig, err := repricing.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid repricing configuration", zap.Error(err))
+	}
+	reconciler := reconciliation.NewReconciler(reconciliation.QuoteBookings{Quotes: quotes}, reconciliationConfig)
+
+	// Initialize the background probes of the dependencies reported by /readyz; carrier APIs and the
+	// zipcode lookup are optional, quotes fall back to the local tables without them
+	healthConfig, err := health.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid health probe configuration", zap.Error(err))
+	}
+	probeClient := &http.Client{Timeout: healthConfig.Timeout}
+	if shipping.CarrierConfig.Enabled() {
+		probes = append(probes, health.Probe{Name: "carrier_rates", Check: health.HTTPCheck(probeClient, shipping.CarrierConfig.URL)})
+	}
+	if trackingConfig.ProviderURL != "" {
+		probes = append(probes, health.Probe{Name: "tracking_provider", Check: health.HTTPCheck(probeClient, trackingConfig.ProviderURL)})
+	}
+	if labelConfig.Enabled() {
+		probes = append(probes, health.Probe{Name: "label_provider", Check: health.HTTPCheck(probeClient, labelConfig.ProviderURL)})
+	}
+	if manifestConfig.Enabled() {
+		probes = append(probes, health.Probe{Name: "manifest_provider", Check: health.HTTPCheck(probeClient, manifestConfig.ProviderURL)})
+	}
+	probes = append(probes, health.Probe{Name: "address_lookup", Check: health.HTTPCheck(probeClient, addressConfig.BaseURL)})
+	monitor := health.NewMonitor(healthConfig, metrics, probes...)
+	runtimeStatsConfig, err := runtimestats.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid runtime stats configuration", zap.Error(err))
+	}
+	quoteStatsConfig, err := quotestats.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid quote stats configuration", zap.Error(err))
+	}
+
+	jobCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+	go monitor.Run(jobCtx, healthConfig.Interval, zapLogger)
+	go runtimestats.NewReporter(metrics).Run(jobCtx, runtimeStatsConfig.Interval)
+	go webhookDispatcher.Run(jobCtx)
+	if notificationConfig.Enabled() {
+		go notifier.Run(jobCtx)
+	}
+	if reconciliationJobConfig.InboxDir != "" {
+		go reconciliation.NewJob(reconciler, reconciliationJobConfig, zapLogger).Run(jobCtx)
+	}
+	go shipping.Calendar.Run(jobCtx, shipping.HolidayConfig.ReloadInterval, zapLogger)
+	pricingReloader := pricingreload.New(pricingReloadConfig, shippingService, publisher, metrics, zapLogger)
+	if err := pricingReloader.RecordVersions(ctx, pricingVersions); err != nil {
+		zapLogger.Error("Failed to record the pricing version in force", zap.Error(err))
+	}
+	go pricingReloader.Watch(jobCtx)
+	go reloadOnSignal(jobCtx, shipping.Calendar, shipping.TransitMatrix, pricingReloader, zapLogger)
+
+	// Cache zipcode lookups in the quote store, sharing a single provider call between concurrent
+	// lookups of the same zipcode
+	var addressProvider address.Provider = address.NewHTTPProvider(addressConfig)
+	if faults != nil {
+		addressProvider = chaos.WrapAddressProvider(addressProvider, faults)
+	}
+	if addressConfig.CacheTTL > 0 {
+		addressProvider = address.NewCachedProvider(addressProvider, quoteStore, addressConfig)
+	}
+
+	// Warm the quote cache with the hot quotes in the background at startup and after every pricing
+	// change, learning them from the quotes served
+	warmer := warmup.New(warmupConfig, shippingService, quoteStore, zapLogger)
+	if warmupConfig.Enabled() {
+		pricingReloader.OnChange(func(pricingreload.Revision) { warmer.Trigger() })
+		go warmer.Run(jobCtx)
+	}
+
+	// Reprice the price subscriptions after every pricing change, including fuel surcharge updates
+	subscriptionHub := subscription.New(subscriptionConfig, shippingService, quoteStore, zapLogger)
+	if subscriptionConfig.Enabled() {
+		pricingReloader.OnChange(func(pricingreload.Revision) { subscriptionHub.Trigger() })
+		go subscriptionHub.Run(jobCtx)
+	}
+
+	// Reprice the booked shipments on REPRICING_SCHEDULE, reporting the prices drifting from the
+	// booked ones. With a database, each activation runs on a single instance
+	if repricingConfig.Enabled() {
+		repricingJob := repricing.New(repricingConfig, shipments, shippingService, publisher, zapLogger)
+		jobScheduler := scheduler.New(zapLogger).WithClaimer(jobRuns)
+		jobScheduler.Add("repricing", *repricingConfig.Schedule, func(ctx context.Context) error {
+			_, err := repricingJob.Run(ctx)
+			return err
+		})
+		go jobScheduler.Run(jobCtx)
+	}
 
 	// Initialize handlers
-	shippingHandler := handler.NewShippingHandler(shippingService, logger)
+	quoteStats := quotestats.NewWindow(quoteStatsConfig.Window)
+	shippingHandler := handler.NewShippingHandler(warmer.Track(shippingService), quotes, quoteConfig, publisher, quoteSigner, pricingVersions, metrics, zapLogger).
+		WithDeterminism(shipping.Clock, shipping.IDs).
+		WithStats(quoteStats)
+	wellKnownHandler := handler.NewWellKnownHandler(shippingService, zapLogger).WithRateLimits(overloadConfig, shipping.Tenants)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciler, zapLogger)
+	bulkHandler := handler.NewBulkHandler(shippingService, bulkConfig, metrics, zapLogger)
+	explainHandler := handler.NewExplainHandler(shippingService, zapLogger)
+	packingHandler := handler.NewPackingHandler(shippingService, packingConfig, zapLogger)
+	pickupHandler := handler.NewPickupHandler(pickup.NewStaticProvider(pickupConfig), zapLogger)
+	addressHandler := handler.NewAddressHandler(addressProvider, addressConfig, zapLogger)
+	serviceabilityHandler := handler.NewServiceabilityHandler(shippingService, addressConfig, zapLogger)
+	shipmentHandler := handler.NewShipmentHandler(shipmentService, zapLogger)
+	trackingHandler := handler.NewTrackingHandler(trackingService, trackingConfig, zapLogger)
+	labelHandler := handler.NewLabelHandler(labelService, zapLogger)
+	manifestHandler := handler.NewManifestHandler(manifestService, zapLogger)
+	merchantWebhookHandler := handler.NewMerchantWebhookHandler(webhookDispatcher, zapLogger)
+	slaHandler := handler.NewSLAHandler(slaService, zapLogger)
+	statsHandler := handler.NewStatsHandler(quoteStats, zapLogger)
+	carrierWebhookHandler := handler.NewCarrierWebhookHandler(trackingService, tracking.NewWebhookVerifier(trackingConfig, quoteStore), zapLogger)
+	healthHandler := handler.NewHealthHandler(monitor, zapLogger)
+	adminHandler := handler.NewAdminHandler(pricingReloader, usageMeter, zapLogger)
+	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionHub, zapLogger)
 
 	// Setup router
 	r := chi.NewRouter()
-	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
-	r.Use(otelMiddleware)
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
-
-	// Register routes
-	r.Post("/calculate", shippingHandler.CalculateShipping)
-
-	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	if shipping.Determinism.Enabled() {
+		r.Use(middleware.RequestID(shipping.IDs))
+	} else {
+		r.Use(chimiddleware.RequestID)
 	}
+	r.Use(chimiddleware.RealIP)
+	r.Use(otelMiddleware(tel.Tracer))
+	r.Use(loggerMiddleware(zapLogger))
+	r.Use(middleware.AccessLog(zapLogger, accessLogConfig))
+	r.Use(middleware.CORS(corsConfig))
+	r.Use(middleware.Compress(compressionConfig))
+	r.Use(middleware.Recoverer(metrics, zapLogger))
+	r.Use(middleware.StrictSchema(strictSchemaConfig))
+	r.Use(middleware.ClientID(clientIDConfig))
+	r.Use(middleware.Tenant(shipping.Tenants))
 
-	server := &http.Server{
-		Addr:    ":" + port,
-		Handler: r,
+	// Register routes, each with its request deadline
+	timeout := func(route string) func(http.Handler) http.Handler {
+		return middleware.Timeout(timeoutConfig.For(route))
+	}
+	quota := middleware.Quota(usageMeter, zapLogger)
+	// The request bodies are limited as sent and decompressed; batch uploads to their own limit
+	decompress := middleware.DecompressRequest(maxBodyBytes)
+	// The quote routes share the in-flight count of the overload protection
+	overload := middleware.Overload(overloadConfig, metrics)
+	// The quotes slower than SLOW_QUOTE_THRESHOLD are logged with the time of each stage
+	slowQuotes := middleware.SlowQuotes(zapLogger, slowQuoteConfig)
+	// The quote routes reject requests with invalid zipcodes, weight or dimensions before pricing,
+	// reporting every invalid field. /calculate serves the deprecated v1 contract, like
+	// /v1/calculate, until clients move to /v2/calculate
+	calculateV1 := r.With(timeout("/calculate"), slowQuotes, middleware.Deprecated(deprecationConfig, "/v2/calculate"), overload, quota, decompress)
+	calculateV1.Method(http.MethodPost, "/calculate", shippingHandler.CalculateV1())
+	calculateV1.Method(http.MethodPost, "/v1/calculate", shippingHandler.CalculateV1())
+	r.With(timeout("/calculate"), slowQuotes, overload, quota, decompress,
+		middleware.RequireContentType(middleware.ContentTypeJSON, middleware.ContentTypeForm),
+		middleware.FormJSON[v2.CalculateShippingRequest](), middleware.ValidateBody(shippingHandler.RecordRejected)).
+		Post("/v2/calculate", shippingHandler.CalculateShippingV2)
+	r.With(timeout("/calculate/preview"), overload, quota, middleware.RequireContentType(middleware.ContentTypeJSON), decompress, middleware.ValidateBody[v1.CalculateShippingRequest](nil)).
+		Post("/calculate/preview", shippingHandler.PreviewShipping)
+	r.With(timeout("/calculate/explain"), overload, quota, middleware.RequireContentType(middleware.ContentTypeJSON), decompress, middleware.ValidateBody[v1.CalculateShippingRequest](nil)).
+		Post("/calculate/explain", explainHandler.ExplainShipping)
+	r.With(timeout("/calculate/csv"), overload, quota, middleware.RequireContentType(middleware.ContentTypeMultipart, middleware.ContentTypeMsgpack), middleware.DecompressRequest(bulkConfig.MaxUploadBytes)).
+		Post("/calculate/csv", bulkHandler.CalculateCSV)
+	r.With(timeout("/packing"), overload, quota, middleware.RequireContentType(middleware.ContentTypeJSON), decompress).
+		Post("/packing", packingHandler.SuggestPacking)
+	r.With(timeout("/quotes/{id}/revalidate"), decompress).Post("/quotes/{id}/revalidate", shippingHandler.RevalidateQuote)
+	r.With(timeout("/shipments"), middleware.RequireContentType(middleware.ContentTypeJSON), decompress).
+		Post("/shipments", shipmentHandler.BookShipment)
+	r.With(timeout("/shipments/{id}/tracking")).Get("/shipments/{id}/tracking", trackingHandler.GetTracking)
+	if labelConfig.Enabled() {
+		r.With(timeout("/shipments/{id}/label")).Post("/shipments/{id}/label", labelHandler.GenerateLabel)
+		r.With(timeout("/shipments/{id}/label/{format}")).Get("/shipments/{id}/label/{format}", labelHandler.DownloadLabel)
+	}
+	r.With(timeout("/shipments/{id}/tracking/events"), middleware.RequireContentType(middleware.ContentTypeJSON)).
+		Post("/shipments/{id}/tracking/events", trackingHandler.RecordTrackingEvents)
+	if webhookSubscriptions {
+		r.With(timeout("/webhook-subscriptions"), middleware.RequireTenantAPIKey, middleware.RequireContentType(middleware.ContentTypeJSON), decompress).
+			Post("/webhook-subscriptions", merchantWebhookHandler.Subscribe)
+		r.With(timeout("/webhook-subscriptions"), middleware.RequireTenantAPIKey).Get("/webhook-subscriptions", merchantWebhookHandler.ListSubscriptions)
+		r.With(timeout("/webhook-subscriptions/{id}"), middleware.RequireTenantAPIKey).Delete("/webhook-subscriptions/{id}", merchantWebhookHandler.Unsubscribe)
+	}
+	r.With(timeout("/webhooks/carriers/{carrier}")).Post("/webhooks/carriers/{carrier}", carrierWebhookHandler.ReceiveWebhook)
+	r.With(timeout(handler.WellKnownPath)).Get(handler.WellKnownPath, wellKnownHandler.GetCapabilities)
+	r.With(timeout(handler.ReadinessPath)).Get(handler.ReadinessPath, healthHandler.Readyz)
+	r.With(timeout("/pickup-points")).Get("/pickup-points", pickupHandler.GetPickupPoints)
+	r.With(timeout("/zipcodes/{zipcode}")).Get("/zipcodes/{zipcode}", addressHandler.GetZipcode)
+	r.With(timeout("/serviceability")).Get("/serviceability", serviceabilityHandler.GetServiceability)
+	if subscriptionConfig.Enabled() {
+		r.With(timeout("/price-subscriptions"), overload, quota, middleware.RequireContentType(middleware.ContentTypeJSON), decompress).
+			Post("/price-subscriptions", subscriptionHandler.Subscribe)
+		r.With(timeout("/price-subscriptions/{id}")).Get("/price-subscriptions/{id}", subscriptionHandler.PollSubscription)
+		r.With(timeout("/price-subscriptions/{id}")).Delete("/price-subscriptions/{id}", subscriptionHandler.Unsubscribe)
 	}
+	if adminConfig.Enabled() {
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(middleware.RequireAdmin(adminConfig))
+			r.With(timeout("/admin/pricing/reload"), decompress).Post("/pricing/reload", adminHandler.ReloadPricing)
+			r.With(timeout("/admin/pricing/fuel-surcharge"), decompress).Put("/pricing/fuel-surcharge", adminHandler.SetFuelSurcharge)
+			r.With(timeout("/admin/pricing/versions")).Get("/pricing/versions", adminHandler.GetPricingVersions)
+			r.With(timeout("/admin/usage")).Get("/usage", adminHandler.GetUsage)
+			r.With(timeout("/admin/webhooks/dead-letters")).Get("/webhooks/dead-letters", merchantWebhookHandler.GetDeadLetters)
+			r.With(timeout("/admin/sla")).Get("/sla", slaHandler.GetReport)
+			r.With(timeout("/admin/reconciliation/discrepancies")).Get("/reconciliation/discrepancies", reconciliationHandler.GetDiscrepancies)
+			r.With(timeout("/admin/manifests"), decompress).Post("/manifests", manifestHandler.CloseManifests)
+			r.With(timeout("/admin/manifests/{id}")).Get("/manifests/{id}", manifestHandler.GetManifest)
+			r.With(timeout("/admin/stats")).Get("/stats", statsHandler.GetStats)
+			if faults != nil {
+				chaosHandler := handler.NewChaosHandler(faults, zapLogger)
+				r.With(timeout("/admin/chaos")).Get("/chaos", chaosHandler.GetFaults)
+				r.With(timeout("/admin/chaos/{target}"), decompress).Put("/chaos/{target}", chaosHandler.SetFault)
+				r.With(timeout("/admin/chaos/{target}")).Delete("/chaos/{target}", chaosHandler.ClearFault)
+			}
+		})
+	}
+
+	// Start server
+	server := apiserver.New(serverConfig, r)
 
 	// Graceful shutdown
 	go func() {
-		logger.Info("Server starting", zap.String("port", port))
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatal("Server failed to start", zap.Error(err))
+		zapLogger.Info("Server starting", zap.String("addr", serverConfig.Addr), zap.Bool("tls", serverConfig.TLS()))
+		if err := apiserver.ListenAndServe(server, serverConfig); err != nil && err != http.ErrServerClosed {
+			zapLogger.Fatal("Server failed to start", zap.Error(err))
 		}
 	}()
 
+	// Profiles and runtime variables are served on a separate, internal-only port
+	var debugServer *http.Server
+	if debugConfig.Enabled {
+		debugServer = apiserver.NewDebug(debugConfig)
+		go func() {
+			zapLogger.Info("Debug server starting", zap.String("addr", debugConfig.Addr))
+			if err := debugServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				zapLogger.Error("Debug server failed", zap.Error(err))
+			}
+		}()
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	logger.Info("Server shutting down")
+	zapLogger.Info("Server shutting down")
+	stopJobs()
 	if err := server.Close(); err != nil {
-		logger.Error("Server forced to shutdown", zap.Error(err))
+		zapLogger.Error("Server forced to shutdown", zap.Error(err))
+	}
+	if debugServer != nil {
+		_ = debugServer.Close()
 	}
+	shippingService.WaitShadow()
+	asyncPublisher.Close()
+	if err := closePublisher(); err != nil {
+		zapLogger.Error("Failed to close the events broker connection", zap.Error(err))
+	}
+	if err := closeStore(); err != nil {
+		zapLogger.Error("Failed to close the quote store connection", zap.Error(err))
+	}
+	closeDatabase()
 
 	// Shutdown OpenTelemetry
-	if err := shutdown(); err != nil {
-		logger.Error("Error shutting down OpenTelemetry", zap.Error(err))
+	if err := tel.Shutdown(context.Background()); err != nil {
+		zapLogger.Error("Error shutting down OpenTelemetry", zap.Error(err))
+	}
+}
+
+// reloadOnSignal reloads the holiday calendar, the transit matrix, when configured, and the pricing
+// configuration on SIGHUP until ctx is cancelled
+func reloadOnSignal(ctx context.Context, calendar *holiday.Calendar, transitMatrix *eta.TransitMatrix, pricingReloader *pricingreload.Reloader, zapLogger *zap.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if err := calendar.Reload(ctx); err != nil {
+				zapLogger.Error("Failed to reload holiday calendar", zap.Error(err))
+			} else {
+				zapLogger.Info("Holiday calendar reloaded", zap.Int("holidays", calendar.Len()))
+			}
+
+			if transitMatrix != nil {
+				if err := transitMatrix.Reload(); err != nil {
+					zapLogger.Error("Failed to reload transit matrix", zap.Error(err))
+				} else {
+					zapLogger.Info("Transit matrix reloaded", zap.Int("routes", transitMatrix.Len()))
+				}
+			}
+
+			revision, changed, err := pricingReloader.Reload(ctx, pricingreload.SourceSignal, "")
+			switch {
+			case errors.Is(err, pricingreload.ErrNoConfigFile):
+			case err != nil:
+				zapLogger.Error("Failed to reload pricing configuration", zap.Error(err))
+			case !changed:
+				zapLogger.Info("Pricing configuration unchanged", zap.String("version", revision.Version))
+			}
+		}
 	}
 }
 
@@ -104,43 +708,54 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// otelMiddleware creates OpenTelemetry spans for HTTP requests
-func otelMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
+// otelMiddleware creates OpenTelemetry spans of tracer for HTTP requests
+func otelMiddleware(tracer trace.Tracer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
 
-		// Extract trace context from headers
-		ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+			// Extract trace context from headers
+			ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
 
-		// Get tracer
-		tracer := otel.Tracer("shipping-calculator")
+			// Start span
+			ctx, span := tracer.Start(ctx, r.Method+" "+r.URL.Path,
+				trace.WithAttributes(
+					semconv.HTTPMethod(r.Method),
+					semconv.HTTPURL(r.URL.String()),
+					semconv.HTTPRoute(r.URL.Path),
+				),
+			)
+			defer span.End()
 
-		// Start span
-		ctx, span := tracer.Start(ctx, r.Method+" "+r.URL.Path,
-			trace.WithAttributes(
-				semconv.HTTPMethod(r.Method),
-				semconv.HTTPURL(r.URL.String()),
-				semconv.HTTPRoute(r.URL.Path),
-			),
-		)
-		defer span.End()
+			// Add span to request context
+			r = r.WithContext(ctx)
 
-		// Add span to request context
-		r = r.WithContext(ctx)
+			// Wrap ResponseWriter to capture status code
+			wrapped := &responseWriter{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK, // default status
+			}
 
-		// Wrap ResponseWriter to capture status code
-		wrapped := &responseWriter{
-			ResponseWriter: w,
-			statusCode:     http.StatusOK, // default status
-		}
+			// Call next handler
+			next.ServeHTTP(wrapped, r)
 
-		// Call next handler
-		next.ServeHTTP(wrapped, r)
+			// Set span status based on response
+			span.SetAttributes(semconv.HTTPStatusCodeKey.Int(wrapped.statusCode))
+			if wrapped.statusCode >= 400 {
+				span.RecordError(nil)
+			}
+		})
+	}
+}
 
-		// Set span status based on response
-		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(wrapped.statusCode))
-		if wrapped.statusCode >= 400 {
-			span.RecordError(nil)
-		}
-	})
+// loggerMiddleware injects a request-scoped logger enriched with correlation_id, trace_id and span_id,
+// retrievable downstream with logger.FromContext. It must run after RequestID and otelMiddleware
+func loggerMiddleware(base *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			requestLogger := logger.WithTracingFields(base, ctx)
+			next.ServeHTTP(w, r.WithContext(logger.NewContext(ctx, requestLogger)))
+		})
+	}
 }
@@ -0,0 +1,168 @@
+// Command cli computes shipping quotes offline with pkg/engine, the same calculation as the API.
+//
+// Usage:
+//
+//	shipping-cli --origin 01310-100 --destination 04547-130 --weight 2.5 --length 30 --width 20 --height 15
+//	shipping-cli --file request.json --format table
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/rbonfanti/shipping-calculator/pkg/engine"
+)
+
+// Output formats
+const (
+	formatJSON  = "json"
+	formatTable = "table"
+)
+
+// Exit codes
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitInvalidArgs = 2
+)
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run parses the arguments, calculates the quote and writes it to stdout, returning the exit code
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("shipping-cli", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var body v1.CalculateShippingRequest
+	flags.StringVar(&body.OriginZipcode, "origin", "", "origin zipcode")
+	flags.StringVar(&body.DestinationZipcode, "destination", "", "destination zipcode")
+	flags.Float64Var(&body.Weight, "weight", 0, "package weight, in --weight-unit")
+	flags.Float64Var(&body.Dimensions.Length, "length", 0, "package length, in --dimension-unit")
+	flags.Float64Var(&body.Dimensions.Width, "width", 0, "package width, in --dimension-unit")
+	flags.Float64Var(&body.Dimensions.Height, "height", 0, "package height, in --dimension-unit")
+	flags.StringVar(&body.WeightUnit, "weight-unit", "", "weight unit: kg, g or lb (default kg)")
+	flags.StringVar(&body.DimensionUnit, "dimension-unit", "", "dimension unit: cm, m or in (default cm)")
+	flags.BoolVar(&body.IsExpress, "express", false, "quote express delivery")
+	flags.StringVar(&body.Optimize, "optimize", "", "select the cheapest, fastest or balanced option instead of --express")
+	flags.BoolVar(&body.AllowWeekendDelivery, "allow-weekend-delivery", false, "let the service levels configured for it deliver on weekends, with their surcharge")
+	flags.BoolVar(&body.IsReturn, "return", false, "quote the return of the package from the destination to the origin")
+	flags.StringVar(&body.PickupDate, "pickup-date", "", "scheduled pickup date (YYYY-MM-DD), together with --pickup-window")
+	flags.StringVar(&body.PickupWindow, "pickup-window", "", "scheduled pickup window, e.g. morning or afternoon")
+	flags.StringVar(&body.HSCode, "hs-code", "", "HS code of the goods of international shipments, together with --declared-value")
+	flags.Float64Var(&body.DeclaredValue, "declared-value", 0, "declared value of the goods in minor units of the quote currency")
+	flags.StringVar(&body.FreightClass, "freight-class", "", "freight class of heavy shipments, e.g. 70 or 125")
+	flags.StringVar(&body.DestinationCountry, "country", "", "destination country (ISO 3166-1 alpha-2, default BR)")
+	flags.StringVar(&body.Currency, "currency", "", "quote currency (ISO 4217, default: currency of the destination country)")
+	flags.StringVar(&body.PackageType, "package-type", "", "package type: standard, fragile, perishable or dangerous (default standard)")
+	flags.StringVar(&body.DeliveryType, "delivery-type", "", "delivery type: home, pickup_point or locker (default home)")
+	flags.StringVar(&body.PricingStrategy, "pricing-strategy", "", "pricing strategy: formula, table or carrier (default: configured per service level)")
+	services := flags.String("services", "", "comma-separated additional services: cod, signature, saturday_delivery")
+	file := flags.String("file", "", `JSON request file (same body as POST /calculate); "-" reads stdin. Overrides the package flags`)
+	format := flags.String("format", formatJSON, "output format: json or table")
+	etaConfigPath := flags.String("eta-config", os.Getenv("ETA_CONFIG_PATH"), "warehouse handling time configuration file")
+	transitMatrixPath := flags.String("transit-matrix", os.Getenv("TRANSIT_MATRIX_PATH"), "transit days between states (CSV)")
+	pricingConfigPath := flags.String("pricing-config", os.Getenv("PRICING_CONFIG_PATH"), "pricing configuration file")
+	holidaysPath := flags.String("holidays", os.Getenv("HOLIDAY_CALENDAR_PATH"), "holiday calendar file skipped by delivery estimates")
+	now := flags.String("now", os.Getenv("DETERMINISTIC_NOW"), "RFC 3339 time the quote is calculated at, for reproducible estimates (default: current time)")
+
+	if err := flags.Parse(args); err != nil {
+		return exitInvalidArgs
+	}
+	if *format != formatJSON && *format != formatTable {
+		fmt.Fprintf(stderr, "invalid --format %q: must be %s or %s\n", *format, formatJSON, formatTable)
+		return exitInvalidArgs
+	}
+
+	if *services != "" {
+		body.AdditionalServices = strings.Split(*services, ",")
+	}
+
+	if *file != "" {
+		if err := readRequest(*file, stdin, &body); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitInvalidArgs
+		}
+	}
+
+	cfg := engine.Config{
+		PricingConfigPath:   *pricingConfigPath,
+		ETAConfigPath:       *etaConfigPath,
+		TransitMatrixPath:   *transitMatrixPath,
+		HolidayCalendarPath: *holidaysPath,
+	}
+	if *now != "" {
+		at, err := time.Parse(time.RFC3339, *now)
+		if err != nil {
+			fmt.Fprintf(stderr, "invalid --now %q: must be an RFC 3339 timestamp\n", *now)
+			return exitInvalidArgs
+		}
+		cfg.Now = at
+	}
+
+	calculator, err := engine.New(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	response, err := calculator.Calculate(ctx, &body)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	if err := writeResponse(stdout, *format, response); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+// readRequest decodes the request body from a file, or from stdin when path is "-"
+func readRequest(path string, stdin io.Reader, body *v1.CalculateShippingRequest) error {
+	r := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open request file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	*body = v1.CalculateShippingRequest{}
+	if err := json.NewDecoder(r).Decode(body); err != nil {
+		return fmt.Errorf("invalid request file: %w", err)
+	}
+	return nil
+}
+
+// writeResponse writes the response in the requested format
+func writeResponse(w io.Writer, format string, response *v1.CalculateShippingResponse) error {
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(response)
+	case formatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "SERVICE\tCOST (%s)\tTIME\n", response.Currency)
+		for _, option := range response.ShippingOptions {
+			fmt.Fprintf(tw, "%s\t%.2f\t%s\n", option.Service, option.Cost/100, option.Time)
+		}
+		return tw.Flush()
+	default:
+		return errors.New("unsupported format " + format)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+var packageFlags = []string{
+	"--origin", "12345678",
+	"--destination", "12345678",
+	"--weight", "1",
+	"--length", "10", "--width", "10", "--height", "10",
+}
+
+func TestRun_FlagsJSON(t *testing.T) {
+	// Arrange
+	var stdout, stderr bytes.Buffer
+
+	// Act
+	code := run(context.Background(), packageFlags, nil, &stdout, &stderr)
+
+	// Assert
+	assert.Equal(t, exitOK, code, stderr.String())
+
+	var response v1.CalculateShippingResponse
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &response))
+	assert.InDelta(t, 1250.0, response.ShippingCost, 0.001)
+	assert.Equal(t, "2 dias", response.EstimatedDeliveryTime)
+	assert.Len(t, response.ShippingOptions, 2)
+}
+
+func TestRun_Table(t *testing.T) {
+	// Arrange
+	var stdout, stderr bytes.Buffer
+	args := append([]string{"--format", "table", "--express"}, packageFlags...)
+
+	// Act
+	code := run(context.Background(), args, nil, &stdout, &stderr)
+
+	// Assert
+	assert.Equal(t, exitOK, code, stderr.String())
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "COST (BRL)")
+	assert.Contains(t, lines[1], "standard")
+	assert.Contains(t, lines[1], "12.50")
+	assert.Contains(t, lines[2], "express")
+	assert.Contains(t, lines[2], "18.75")
+}
+
+func TestRun_Currency(t *testing.T) {
+	// Arrange
+	var stdout, stderr bytes.Buffer
+	args := append([]string{"--country", "US", "--format", "table"}, packageFlags...)
+
+	// Act
+	code := run(context.Background(), args, nil, &stdout, &stderr)
+
+	// Assert
+	assert.Equal(t, exitOK, code, stderr.String())
+	assert.Contains(t, stdout.String(), "COST (USD)")
+	assert.Contains(t, stdout.String(), "6.25")
+}
+
+func TestRun_DeliveryType(t *testing.T) {
+	// Arrange
+	var stdout, stderr bytes.Buffer
+	args := append([]string{"--delivery-type", "locker", "--format", "table"}, packageFlags...)
+
+	// Act
+	code := run(context.Background(), args, nil, &stdout, &stderr)
+
+	// Assert
+	assert.Equal(t, exitOK, code, stderr.String())
+	assert.Contains(t, stdout.String(), "10.00")
+}
+
+func TestRun_AdditionalServices(t *testing.T) {
+	// Arrange
+	var stdout, stderr bytes.Buffer
+	args := append([]string{"--services", "cod,saturday_delivery"}, packageFlags...)
+
+	// Act
+	code := run(context.Background(), args, nil, &stdout, &stderr)
+
+	// Assert
+	assert.Equal(t, exitOK, code, stderr.String())
+	var response v1.CalculateShippingResponse
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &response))
+	assert.InDelta(t, 2750.0, response.ShippingCost, 0.001)
+	assert.Len(t, response.Breakdown.AdditionalServices, 2)
+}
+
+func TestRun_File(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "request.json")
+	body := `{"origin_zipcode":"12345678","destination_zipcode":"12345678","weight":1,` +
+		`"dimensions":{"length":10,"width":10,"height":10},"is_express":true}`
+	assert.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+	var stdout, stderr bytes.Buffer
+
+	// Act
+	code := run(context.Background(), []string{"--file", path}, nil, &stdout, &stderr)
+
+	// Assert
+	assert.Equal(t, exitOK, code, stderr.String())
+	var response v1.CalculateShippingResponse
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &response))
+	assert.InDelta(t, 1875.0, response.ShippingCost, 0.001)
+}
+
+func TestRun_Stdin(t *testing.T) {
+	// Arrange
+	body := `{"origin_zipcode":"12345678","destination_zipcode":"12345678","weight":1,` +
+		`"dimensions":{"length":10,"width":10,"height":10}}`
+	var stdout, stderr bytes.Buffer
+
+	// Act
+	code := run(context.Background(), []string{"--file", "-"}, strings.NewReader(body), &stdout, &stderr)
+
+	// Assert
+	assert.Equal(t, exitOK, code, stderr.String())
+	assert.Contains(t, stdout.String(), `"shipping_cost"`)
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantErr  string
+	}{
+		{"unknown flag", []string{"--unknown"}, exitInvalidArgs, "flag provided but not defined"},
+		{"invalid format", []string{"--format", "xml"}, exitInvalidArgs, "invalid --format"},
+		{"invalid now", append([]string{"--now", "yesterday"}, packageFlags...), exitInvalidArgs, "invalid --now"},
+		{"missing file", []string{"--file", "/does/not/exist.json"}, exitInvalidArgs, "failed to open request file"},
+		{"validation error", []string{"--origin", "123"}, exitError, "invalid origin_zipcode"},
+		{"invalid eta config", append([]string{"--eta-config", "/does/not/exist.json"}, packageFlags...), exitError, "exist.json"},
+		{"invalid pricing config", append([]string{"--pricing-config", "/does/not/exist.json"}, packageFlags...), exitError, "pricing config"},
+		{"unsupported currency", append([]string{"--currency", "JPY"}, packageFlags...), exitError, "unsupported currency"},
+		{"dangerous express", append([]string{"--package-type", "dangerous", "--express"}, packageFlags...), exitError, "express delivery is not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			// Act
+			code := run(context.Background(), tt.args, nil, &stdout, &stderr)
+
+			// Assert
+			assert.Equal(t, tt.wantCode, code)
+			assert.Contains(t, stderr.String(), tt.wantErr)
+			assert.Empty(t, stdout.String())
+		})
+	}
+}
+
+func TestRun_Now(t *testing.T) {
+	// Arrange
+	args := append([]string{"--now", "2025-03-07T10:00:00-03:00"}, packageFlags...)
+	var first, second, stderr bytes.Buffer
+
+	// Act
+	firstCode := run(context.Background(), args, nil, &first, &stderr)
+	secondCode := run(context.Background(), args, nil, &second, &stderr)
+
+	// Assert
+	assert.Equal(t, exitOK, firstCode, stderr.String())
+	assert.Equal(t, exitOK, secondCode, stderr.String())
+	assert.Equal(t, first.String(), second.String())
+}
@@ -0,0 +1,324 @@
+// Command loadgen sends quote requests to a running API at a target rate and reports the achieved
+// throughput and latency percentiles, failing when they miss the performance budget.
+//
+// Usage:
+//
+//	shipping-loadgen --url http://localhost:8080 --rps 5000 --duration 1m --min-rps 5000 --max-p99 50ms
+//	shipping-loadgen --endpoint preview --file request.json --concurrency 32
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+)
+
+// Exit codes
+const (
+	exitOK             = 0
+	exitError          = 1
+	exitInvalidArgs    = 2
+	exitBudgetExceeded = 3
+)
+
+// endpoints are the quote endpoints under load, by --endpoint
+var endpoints = map[string]string{
+	"calculate": "/calculate",
+	"preview":   "/calculate/preview",
+}
+
+// defaultRequest is the quote sent without --file, a typical domestic parcel
+var defaultRequest = v1.CalculateShippingRequest{
+	OriginZipcode:      "01310-100",
+	DestinationZipcode: "20040-020",
+	Weight:             2.5,
+	Dimensions:         v1.PackageDimensions{Length: 30, Width: 20, Height: 15},
+}
+
+// budget is the performance budget of a run; zero values are not checked
+type budget struct {
+	minRPS       float64
+	maxP99       time.Duration
+	maxErrorRate float64
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run parses the arguments, generates the load and writes the report to stdout, returning the
+// exit code
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("shipping-loadgen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	baseURL := flags.String("url", "http://localhost:8080", "base URL of the API")
+	endpoint := flags.String("endpoint", "calculate", "endpoint under load: calculate or preview")
+	file := flags.String("file", "", "JSON request file (same body as POST /calculate); defaults to a domestic parcel")
+	rps := flags.Float64("rps", 1000, "target requests per second; 0 sends as fast as the workers allow")
+	duration := flags.Duration("duration", 30*time.Second, "duration of the run")
+	concurrency := flags.Int("concurrency", 64, "number of concurrent requests")
+	headers := make(http.Header)
+	flags.Func("header", `request header "Name: value", e.g. the API key of the tenant; can be repeated`, func(value string) error {
+		name, val, ok := strings.Cut(value, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("expected Name: value, got %q", value)
+		}
+		headers.Add(strings.TrimSpace(name), strings.TrimSpace(val))
+		return nil
+	})
+	var limits budget
+	flags.Float64Var(&limits.minRPS, "min-rps", 0, "budget: minimum achieved requests per second")
+	flags.DurationVar(&limits.maxP99, "max-p99", 0, "budget: maximum 99th percentile latency")
+	flags.Float64Var(&limits.maxErrorRate, "max-error-rate", 0.001, "budget: maximum fraction of failed requests")
+
+	if err := flags.Parse(args); err != nil {
+		return exitInvalidArgs
+	}
+	path, ok := endpoints[*endpoint]
+	if !ok {
+		fmt.Fprintf(stderr, "invalid --endpoint %q: must be calculate or preview\n", *endpoint)
+		return exitInvalidArgs
+	}
+	if *rps < 0 || *duration <= 0 || *concurrency <= 0 {
+		fmt.Fprintln(stderr, "--rps must not be negative, and --duration and --concurrency must be positive")
+		return exitInvalidArgs
+	}
+
+	body, err := requestBody(*file)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitInvalidArgs
+	}
+
+	gen := &generator{
+		url:         strings.TrimSuffix(*baseURL, "/") + path,
+		body:        body,
+		headers:     headers,
+		rps:         *rps,
+		concurrency: *concurrency,
+		client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{MaxIdleConns: *concurrency, MaxIdleConnsPerHost: *concurrency},
+		},
+	}
+	result := gen.run(ctx, *duration)
+	if result.requests == 0 {
+		fmt.Fprintln(stderr, "no requests were sent")
+		return exitError
+	}
+
+	violations := result.check(limits)
+	result.write(stdout, violations)
+	if len(violations) > 0 {
+		return exitBudgetExceeded
+	}
+	return exitOK
+}
+
+// requestBody returns the JSON request read from path, or the default request
+func requestBody(path string) ([]byte, error) {
+	if path == "" {
+		return json.Marshal(defaultRequest)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request file: %w", err)
+	}
+	if !json.Valid(data) {
+		return nil, errors.New("invalid request file: not JSON")
+	}
+	return data, nil
+}
+
+// generator sends the same quote request from concurrent workers, paced at rps
+type generator struct {
+	url         string
+	body        []byte
+	headers     http.Header
+	rps         float64
+	concurrency int
+	client      *http.Client
+}
+
+// run generates load for duration, or until ctx is cancelled, and returns the measurements
+func (g *generator) run(ctx context.Context, duration time.Duration) *result {
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	tokens := make(chan struct{}, g.concurrency)
+	go g.pace(ctx, tokens)
+
+	results := make([]*result, g.concurrency)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range results {
+		results[i] = &result{statuses: make(map[int]int)}
+		wg.Add(1)
+		go func(r *result) {
+			defer wg.Done()
+			for range tokens {
+				g.send(ctx, r)
+			}
+		}(results[i])
+	}
+	wg.Wait()
+
+	total := &result{statuses: make(map[int]int), elapsed: time.Since(start)}
+	for _, r := range results {
+		total.merge(r)
+	}
+	return total
+}
+
+// pace sends a token per request at the target rate until ctx is done, then closes tokens. The
+// schedule is fixed from the start, so requests delayed by busy workers are sent as soon as one
+// is free instead of lowering the rate
+func (g *generator) pace(ctx context.Context, tokens chan<- struct{}) {
+	defer close(tokens)
+	start := time.Now()
+	for sent := 0; ; sent++ {
+		if g.rps > 0 {
+			next := start.Add(time.Duration(float64(sent) / g.rps * float64(time.Second)))
+			if wait := time.Until(next); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case tokens <- struct{}{}:
+		}
+	}
+}
+
+// send sends a request and records its latency and outcome; requests interrupted by the end of
+// the run are not recorded
+func (g *generator) send(ctx context.Context, r *result) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.url, bytes.NewReader(g.body))
+	if err != nil {
+		r.requests++
+		r.errors++
+		return
+	}
+	req.Header = g.headers.Clone()
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := g.client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.requests++
+			r.errors++
+		}
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	r.requests++
+	r.statuses[resp.StatusCode]++
+	r.latencies = append(r.latencies, latency)
+	if resp.StatusCode != http.StatusOK {
+		r.errors++
+	}
+}
+
+// result holds the measurements of a run
+type result struct {
+	requests  int
+	errors    int
+	statuses  map[int]int
+	latencies []time.Duration
+	elapsed   time.Duration
+}
+
+func (r *result) merge(other *result) {
+	r.requests += other.requests
+	r.errors += other.errors
+	for status, count := range other.statuses {
+		r.statuses[status] += count
+	}
+	r.latencies = append(r.latencies, other.latencies...)
+}
+
+// rps returns the achieved requests per second
+func (r *result) rps() float64 {
+	return float64(r.requests) / r.elapsed.Seconds()
+}
+
+// errorRate returns the fraction of failed requests
+func (r *result) errorRate() float64 {
+	return float64(r.errors) / float64(r.requests)
+}
+
+// percentile returns the latency below which fall p percent of the responses
+func (r *result) percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	slices.Sort(r.latencies)
+	index := int(float64(len(r.latencies))*p/100+0.5) - 1
+	return r.latencies[min(max(index, 0), len(r.latencies)-1)]
+}
+
+// check returns the violations of the budget
+func (r *result) check(limits budget) []string {
+	var violations []string
+	if limits.minRPS > 0 && r.rps() < limits.minRPS {
+		violations = append(violations, fmt.Sprintf("throughput %.0f rps is below %.0f rps", r.rps(), limits.minRPS))
+	}
+	if p99 := r.percentile(99); limits.maxP99 > 0 && p99 > limits.maxP99 {
+		violations = append(violations, fmt.Sprintf("p99 latency %s is above %s", p99, limits.maxP99))
+	}
+	if r.errorRate() > limits.maxErrorRate {
+		violations = append(violations, fmt.Sprintf("error rate %.3f%% is above %.3f%%", r.errorRate()*100, limits.maxErrorRate*100))
+	}
+	return violations
+}
+
+// write writes the report of the run
+func (r *result) write(w io.Writer, violations []string) {
+	fmt.Fprintf(w, "requests:   %d in %s (%.0f rps)\n", r.requests, r.elapsed.Round(time.Millisecond), r.rps())
+	fmt.Fprintf(w, "errors:     %d (%.3f%%)\n", r.errors, r.errorRate()*100)
+	statuses := make([]int, 0, len(r.statuses))
+	for status := range r.statuses {
+		statuses = append(statuses, status)
+	}
+	slices.Sort(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(w, "status %d: %d\n", status, r.statuses[status])
+	}
+	latency := func(p float64) time.Duration { return r.percentile(p).Round(time.Microsecond) }
+	fmt.Fprintf(w, "latency:    p50 %s, p90 %s, p99 %s, max %s\n", latency(50), latency(90), latency(99), latency(100))
+	if len(violations) == 0 {
+		fmt.Fprintln(w, "budget:     ok")
+		return
+	}
+	for _, violation := range violations {
+		fmt.Fprintf(w, "budget:     FAILED, %s\n", violation)
+	}
+}
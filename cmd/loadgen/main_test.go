@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+// quoteServer answers the quote requests with status, counting them
+func quoteServer(t *testing.T, status int, requests *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body v1.CalculateShippingRequest
+		if r.URL.Path != "/calculate/preview" || r.Header.Get("X-API-Key") != "acme-key" || json.NewDecoder(r.Body).Decode(&body) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests.Add(1)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		budget     []string
+		wantCode   int
+		wantReport string
+	}{
+		{"within budget", http.StatusOK, []string{"--max-p99", "1s"}, exitOK, "budget:     ok"},
+		{"throughput below budget", http.StatusOK, []string{"--min-rps", "1000000"}, exitBudgetExceeded, "is below 1000000 rps"},
+		{"failed requests", http.StatusInternalServerError, nil, exitBudgetExceeded, "status 500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var requests atomic.Int32
+			server := quoteServer(t, tt.status, &requests)
+			args := append([]string{
+				"--url", server.URL + "/", "--endpoint", "preview", "--header", "X-API-Key: acme-key",
+				"--rps", "200", "--duration", "200ms", "--concurrency", "4",
+			}, tt.budget...)
+			var stdout, stderr bytes.Buffer
+
+			// Act
+			code := run(context.Background(), args, &stdout, &stderr)
+
+			// Assert
+			assert.Equal(t, tt.wantCode, code, stderr.String())
+			assert.Contains(t, stdout.String(), tt.wantReport)
+			assert.InDelta(t, 40, requests.Load(), 15, "requests are paced at the target rate")
+		})
+	}
+}
+
+func TestRun_InvalidArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown endpoint", []string{"--endpoint", "packing"}},
+		{"negative rate", []string{"--rps", "-1"}},
+		{"no concurrency", []string{"--concurrency", "0"}},
+		{"malformed header", []string{"--header", "X-API-Key"}},
+		{"missing request file", []string{"--file", "missing.json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var stdout, stderr bytes.Buffer
+
+			// Act
+			code := run(context.Background(), tt.args, &stdout, &stderr)
+
+			// Assert
+			assert.Equal(t, exitInvalidArgs, code)
+			assert.NotEmpty(t, stderr.String())
+		})
+	}
+}
+
+func TestResult_Percentile(t *testing.T) {
+	// Arrange
+	r := &result{}
+	for i := 100; i >= 1; i-- {
+		r.latencies = append(r.latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	// Act & Assert
+	assert.Equal(t, 50*time.Millisecond, r.percentile(50))
+	assert.Equal(t, 99*time.Millisecond, r.percentile(99))
+	assert.Equal(t, 100*time.Millisecond, r.percentile(100))
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rbonfanti/shipping-calculator/internal/bootstrap"
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/pricingreload"
+	"github.com/rbonfanti/shipping-calculator/internal/worker"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+	"go.uber.org/zap"
+)
+
+func main() {
+	ctx := context.Background()
+
+	// Initialize OpenTelemetry
+	tel, err := telemetry.New(ctx)
+	if err != nil {
+		log.Fatalf("Failed to initialize OpenTelemetry: %v", err)
+	}
+	defer func() {
+		if err := tel.Shutdown(context.Background()); err != nil {
+			log.Printf("Error shutting down OpenTelemetry: %v", err)
+		}
+	}()
+	metrics := tel.Metrics
+
+	// Initialize logger
+	logConfig, err := logger.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid logger configuration: %v", err)
+	}
+	zapLogger, err := logger.New(logConfig)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer zapLogger.Sync()
+	zap.ReplaceGlobals(zapLogger)
+
+	workerConfig, err := worker.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid worker configuration", zap.Error(err))
+	}
+
+	pricingReloadConfig, err := pricingreload.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid pricing reload configuration", zap.Error(err))
+	}
+
+	// Initialize the shipping service shared with the API, without fault injection: the faults are
+	// controlled through the admin routes of the API
+	shipping, err := bootstrap.NewShipping(ctx, nil, nil, metrics)
+	if err != nil {
+		zapLogger.Fatal("Failed to initialize shipping service", zap.Error(err))
+	}
+
+	// Results are published as quote.job_completed events
+	eventsConfig, err := events.ConfigFromEnv()
+	if err != nil {
+		zapLogger.Fatal("Invalid events configuration", zap.Error(err))
+	}
+	publisher, closePublisher, err := events.NewPublisher(eventsConfig, zapLogger)
+	if err != nil {
+		zapLogger.Fatal("Failed to connect to the events broker", zap.Error(err))
+	}
+
+	// One consumer per concurrent job, so that each holds a single unacknowledged message
+	consumers := make([]worker.Consumer, 0, workerConfig.Concurrency)
+	for range workerConfig.Concurrency {
+		consumer, err := worker.NewConsumer(workerConfig)
+		if err != nil {
+			zapLogger.Fatal("Failed to connect to the job queue", zap.Error(err))
+		}
+		consumers = append(consumers, consumer)
+	}
+
+	jobCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+	go shipping.Calendar.Run(jobCtx, shipping.HolidayConfig.ReloadInterval, zapLogger)
+	go pricingreload.New(pricingReloadConfig, shipping.Service, publisher, metrics, zapLogger).Watch(jobCtx)
+
+	processor := worker.NewProcessor(shipping.Service, publisher, tel.Tracer, metrics, zapLogger)
+	done := make(chan struct{})
+	go func() {
+		zapLogger.Info("Worker starting",
+			zap.String("broker", workerConfig.Broker),
+			zap.Int("concurrency", workerConfig.Concurrency),
+		)
+		worker.NewWorker(consumers, processor, workerConfig, zapLogger).Run(jobCtx)
+		close(done)
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	zapLogger.Info("Worker shutting down")
+	stopJobs()
+	<-done
+	for _, consumer := range consumers {
+		if err := consumer.Close(); err != nil {
+			zapLogger.Error("Failed to close the job queue connection", zap.Error(err))
+		}
+	}
+	shipping.Service.WaitShadow()
+	if err := closePublisher(); err != nil {
+		zapLogger.Error("Failed to close the events broker connection", zap.Error(err))
+	}
+
+	// Shutdown OpenTelemetry
+	if err := tel.Shutdown(context.Background()); err != nil {
+		zapLogger.Error("Error shutting down OpenTelemetry", zap.Error(err))
+	}
+}
@@ -0,0 +1,112 @@
+package address
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+	"golang.org/x/sync/singleflight"
+)
+
+// CachedProvider caches the addresses resolved by a Provider in a store.QuoteStore, shared by the
+// instances using the same store. Concurrent lookups of a zipcode missing from the cache share a
+// single provider call, so a burst of quotes for a new CEP does not fan out into identical calls.
+// Lookups fall back to the provider when the cache fails
+type CachedProvider struct {
+	provider    Provider
+	cache       store.QuoteStore
+	ttl         time.Duration
+	notFoundTTL time.Duration
+	group       singleflight.Group
+}
+
+// cachedAddress is the cache entry of a zipcode; Address is nil for unknown zipcodes
+type cachedAddress struct {
+	Address *Address `json:"address,omitempty"`
+}
+
+// NewCachedProvider caches the lookups of provider for CacheTTL, and unknown zipcodes for
+// NotFoundCacheTTL
+func NewCachedProvider(provider Provider, cache store.QuoteStore, cfg Config) *CachedProvider {
+	return &CachedProvider{
+		provider:    provider,
+		cache:       cache,
+		ttl:         cfg.CacheTTL,
+		notFoundTTL: cfg.NotFoundCacheTTL,
+	}
+}
+
+// LookupZipcode returns the cached address of the zipcode, or looks it up in the provider
+func (p *CachedProvider) LookupZipcode(ctx context.Context, value string) (*Address, error) {
+	normalized := zipcode.Normalize(value)
+	if entry, ok := p.cached(ctx, normalized); ok {
+		if entry.Address == nil {
+			return nil, ErrZipcodeNotFound
+		}
+		return entry.Address, nil
+	}
+
+	// The shared call outlives the caller that started it, so that its cancellation does not fail
+	// the callers waiting on the same zipcode; the provider timeout still bounds it
+	shared := context.WithoutCancel(ctx)
+	result := p.group.DoChan(normalized, func() (any, error) {
+		return p.lookup(shared, normalized)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-result:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		// Every caller gets its own copy of the shared address
+		address := *r.Val.(*Address)
+		return &address, nil
+	}
+}
+
+// lookup queries the provider and caches the address or the unknown zipcode; other errors are
+// not cached
+func (p *CachedProvider) lookup(ctx context.Context, normalized string) (*Address, error) {
+	address, err := p.provider.LookupZipcode(ctx, normalized)
+	switch {
+	case errors.Is(err, ErrZipcodeNotFound):
+		p.put(ctx, normalized, cachedAddress{}, p.notFoundTTL)
+	case err == nil:
+		p.put(ctx, normalized, cachedAddress{Address: address}, p.ttl)
+	}
+	return address, err
+}
+
+// cached returns the cache entry of a zipcode; ok is false when it is missing or unreadable
+func (p *CachedProvider) cached(ctx context.Context, normalized string) (cachedAddress, bool) {
+	var entry cachedAddress
+	data, err := p.cache.Get(ctx, cacheKey(normalized))
+	if err != nil {
+		return entry, false
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, false
+	}
+	return entry, true
+}
+
+// put caches the entry of a zipcode for ttl; a ttl of 0 does not cache it
+func (p *CachedProvider) put(ctx context.Context, normalized string, entry cachedAddress, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	_ = p.cache.Put(ctx, cacheKey(normalized), data, ttl)
+}
+
+// cacheKey returns the store key of a zipcode
+func cacheKey(normalized string) string {
+	return "address:" + normalized
+}
@@ -0,0 +1,137 @@
+package address
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingProvider counts the lookups, blocking each one until release is closed when set
+type countingProvider struct {
+	calls   atomic.Int32
+	release chan struct{}
+	address *Address
+	err     error
+}
+
+func (p *countingProvider) LookupZipcode(ctx context.Context, zipcode string) (*Address, error) {
+	p.calls.Add(1)
+	if p.release != nil {
+		<-p.release
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	address := *p.address
+	return &address, nil
+}
+
+var paulista = &Address{Zipcode: "01310100", Street: "Avenida Paulista", City: "São Paulo", State: "SP"}
+
+func cacheConfig() Config {
+	return Config{CacheTTL: time.Hour, NotFoundCacheTTL: time.Minute}
+}
+
+func TestCachedProvider_LookupZipcode(t *testing.T) {
+	// Arrange
+	provider := &countingProvider{address: paulista}
+	cached := NewCachedProvider(provider, store.NewMemoryStore(), cacheConfig())
+
+	// Act
+	first, firstErr := cached.LookupZipcode(context.Background(), "01310-100")
+	second, secondErr := cached.LookupZipcode(context.Background(), "01310100")
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	assert.Equal(t, paulista, first)
+	assert.Equal(t, paulista, second)
+	assert.Equal(t, int32(1), provider.calls.Load())
+}
+
+func TestCachedProvider_ConcurrentLookups(t *testing.T) {
+	// Arrange
+	provider := &countingProvider{address: paulista, release: make(chan struct{})}
+	cached := NewCachedProvider(provider, store.NewMemoryStore(), cacheConfig())
+	var wg sync.WaitGroup
+	results := make([]*Address, 50)
+
+	// Act
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = cached.LookupZipcode(context.Background(), "01310100")
+		}()
+	}
+	assert.Eventually(t, func() bool { return provider.calls.Load() == 1 }, time.Second, time.Millisecond)
+	close(provider.release)
+	wg.Wait()
+
+	// Assert
+	assert.Equal(t, int32(1), provider.calls.Load())
+	for _, address := range results {
+		assert.Equal(t, paulista, address)
+	}
+}
+
+func TestCachedProvider_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		cfg       Config
+		wantCalls int32
+	}{
+		{"unknown zipcode is cached", ErrZipcodeNotFound, cacheConfig(), 1},
+		{"unknown zipcode without not-found ttl", ErrZipcodeNotFound, Config{CacheTTL: time.Hour}, 2},
+		{"provider failure is not cached", errors.New("address lookup: unexpected status 500"), cacheConfig(), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			provider := &countingProvider{err: tt.err}
+			cached := NewCachedProvider(provider, store.NewMemoryStore(), tt.cfg)
+
+			// Act
+			_, firstErr := cached.LookupZipcode(context.Background(), "01310100")
+			_, secondErr := cached.LookupZipcode(context.Background(), "01310100")
+
+			// Assert
+			assert.ErrorIs(t, firstErr, tt.err)
+			assert.ErrorIs(t, secondErr, tt.err)
+			assert.Equal(t, tt.wantCalls, provider.calls.Load())
+		})
+	}
+}
+
+func TestCachedProvider_CallerCancelled(t *testing.T) {
+	// Arrange
+	provider := &countingProvider{address: paulista, release: make(chan struct{})}
+	cached := NewCachedProvider(provider, store.NewMemoryStore(), cacheConfig())
+	ctx, cancel := context.WithCancel(context.Background())
+	waiting := make(chan error)
+	go func() {
+		_, err := cached.LookupZipcode(ctx, "01310100")
+		waiting <- err
+	}()
+	assert.Eventually(t, func() bool { return provider.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	// Act
+	cancel()
+	cancelledErr := <-waiting
+	close(provider.release)
+	address, err := cached.LookupZipcode(context.Background(), "01310100")
+
+	// Assert
+	assert.ErrorIs(t, cancelledErr, context.Canceled)
+	assert.NoError(t, err)
+	assert.Equal(t, paulista, address)
+	assert.Equal(t, int32(1), provider.calls.Load())
+}
@@ -0,0 +1,66 @@
+package address
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPProvider_Contract(t *testing.T) {
+	tests := []struct {
+		name    string
+		zipcode string
+		want    *Address
+		wantErr error
+	}{
+		{"known zipcode", "20040-020", &Address{Zipcode: "20040020", Street: "Avenida Rio Branco", Neighborhood: "Centro", City: "Rio de Janeiro", State: "RJ"}, nil},
+		{"unknown zipcode", "99999999", nil, ErrZipcodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := testsupport.NewZipcodeServer(t)
+			provider := NewHTTPProvider(Config{BaseURL: server.URL, Timeout: time.Second})
+
+			// Act
+			address, err := provider.LookupZipcode(context.Background(), tt.zipcode)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, address)
+		})
+	}
+}
+
+func TestHTTPProvider_ContractFaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		fault   testsupport.Fault
+		wantErr string
+	}{
+		{"unavailable", testsupport.Fault{Status: http.StatusInternalServerError}, "unexpected status 500"},
+		{"malformed", testsupport.Fault{Malformed: true}, "invalid response body"},
+		{"connection reset", testsupport.Fault{Reset: true}, "address lookup: "},
+		{"slower than the timeout", testsupport.Fault{Latency: time.Second}, "Client.Timeout exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := testsupport.NewZipcodeServer(t)
+			server.Fail(tt.fault)
+			provider := NewHTTPProvider(Config{BaseURL: server.URL, Timeout: 50 * time.Millisecond})
+
+			// Act
+			_, err := provider.LookupZipcode(context.Background(), "01310100")
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Package address resolves Brazilian zipcodes (CEP) to addresses, caching the lookups, and checks
+// delivery coverage.
+package address
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// ErrZipcodeNotFound is returned when the zipcode does not exist
+var ErrZipcodeNotFound = errors.New("zipcode not found")
+
+// Address is the location of a zipcode
+type Address struct {
+	Zipcode      string `json:"zipcode"`
+	Street       string `json:"street"`
+	Neighborhood string `json:"neighborhood"`
+	City         string `json:"city"`
+	State        string `json:"state"`
+}
+
+// Provider resolves a normalized 8-digit zipcode to its address
+type Provider interface {
+	LookupZipcode(ctx context.Context, zipcode string) (*Address, error)
+}
+
+// Config configures the HTTP lookup provider and the delivery coverage
+type Config struct {
+	// BaseURL is the ViaCEP-compatible lookup API, queried at {BaseURL}/ws/{cep}/json/
+	BaseURL string
+	Timeout time.Duration
+	// UnservedZipcodePrefixes are CEP prefixes we do not deliver to
+	UnservedZipcodePrefixes []string
+	// CacheTTL is how long resolved zipcodes are cached; 0 disables the cache
+	CacheTTL time.Duration
+	// NotFoundCacheTTL is how long unknown zipcodes are cached; 0 does not cache them
+	NotFoundCacheTTL time.Duration
+}
+
+// ConfigFromEnv reads ADDRESS_LOOKUP_URL (default https://viacep.com.br), ADDRESS_LOOKUP_TIMEOUT
+// (default 3s), ADDRESS_UNSERVED_ZIPCODE_PREFIXES (comma-separated, default none),
+// ADDRESS_CACHE_TTL (default 24h) and ADDRESS_CACHE_NOT_FOUND_TTL (default 1h)
+func ConfigFromEnv() (Config, error) {
+	timeout, err := config.Duration("ADDRESS_LOOKUP_TIMEOUT", 3*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if timeout <= 0 {
+		return Config{}, fmt.Errorf("ADDRESS_LOOKUP_TIMEOUT must be positive")
+	}
+	cacheTTL, err := config.Duration("ADDRESS_CACHE_TTL", 24*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+	notFoundTTL, err := config.Duration("ADDRESS_CACHE_NOT_FOUND_TTL", time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+	if cacheTTL < 0 || notFoundTTL < 0 {
+		return Config{}, fmt.Errorf("ADDRESS_CACHE_TTL and ADDRESS_CACHE_NOT_FOUND_TTL must not be negative")
+	}
+	return Config{
+		BaseURL:                 strings.TrimRight(config.String("ADDRESS_LOOKUP_URL", "https://viacep.com.br"), "/"),
+		Timeout:                 timeout,
+		UnservedZipcodePrefixes: config.List("ADDRESS_UNSERVED_ZIPCODE_PREFIXES", nil),
+		CacheTTL:                cacheTTL,
+		NotFoundCacheTTL:        notFoundTTL,
+	}, nil
+}
+
+// Deliverable reports whether the zipcode is outside every unserved prefix
+func (c Config) Deliverable(value string) bool {
+	normalized := zipcode.Normalize(value)
+	for _, prefix := range c.UnservedZipcodePrefixes {
+		if prefix != "" && strings.HasPrefix(normalized, zipcode.Strip(prefix)) {
+			return false
+		}
+	}
+	return true
+}
+
+// HTTPProvider looks zipcodes up in a ViaCEP-compatible API
+type HTTPProvider struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewHTTPProvider creates a provider for the configured lookup API
+func NewHTTPProvider(cfg Config) *HTTPProvider {
+	return &HTTPProvider{
+		baseURL:    cfg.BaseURL,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// viaCEPResponse is the body returned by ViaCEP; unknown zipcodes return {"erro": true}
+type viaCEPResponse struct {
+	Zipcode      string          `json:"cep"`
+	Street       string          `json:"logradouro"`
+	Neighborhood string          `json:"bairro"`
+	City         string          `json:"localidade"`
+	State        string          `json:"uf"`
+	Error        json.RawMessage `json:"erro"`
+}
+
+// LookupZipcode queries the API, propagating the trace context of ctx
+func (p *HTTPProvider) LookupZipcode(ctx context.Context, value string) (*Address, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/ws/"+zipcode.Normalize(value)+"/json/", nil)
+	if err != nil {
+		return nil, fmt.Errorf("address lookup: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("address lookup: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest:
+		return nil, ErrZipcodeNotFound
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, fmt.Errorf("address lookup: unexpected status %d", resp.StatusCode)
+	}
+
+	var body viaCEPResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("address lookup: invalid response body: %w", err)
+	}
+	if len(body.Error) > 0 && string(body.Error) != "false" {
+		return nil, ErrZipcodeNotFound
+	}
+
+	return &Address{
+		Zipcode:      zipcode.Normalize(body.Zipcode),
+		Street:       body.Street,
+		Neighborhood: body.Neighborhood,
+		City:         body.City,
+		State:        body.State,
+	}, nil
+}
@@ -0,0 +1,150 @@
+package address
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newLookupServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ws/01310100/json/", r.URL.Path)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLookupZipcode(t *testing.T) {
+	// Arrange
+	server := newLookupServer(t, http.StatusOK,
+		`{"cep":"01310-100","logradouro":"Avenida Paulista","bairro":"Bela Vista","localidade":"São Paulo","uf":"SP"}`)
+	provider := NewHTTPProvider(Config{BaseURL: server.URL, Timeout: time.Second})
+
+	// Act
+	address, err := provider.LookupZipcode(context.Background(), "01310-100")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &Address{
+		Zipcode:      "01310100",
+		Street:       "Avenida Paulista",
+		Neighborhood: "Bela Vista",
+		City:         "São Paulo",
+		State:        "SP",
+	}, address)
+}
+
+func TestLookupZipcode_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantErr  error
+		contains string
+	}{
+		{"unknown zipcode", http.StatusOK, `{"erro": true}`, ErrZipcodeNotFound, ""},
+		{"unknown zipcode as string", http.StatusOK, `{"erro": "true"}`, ErrZipcodeNotFound, ""},
+		{"bad request", http.StatusBadRequest, ``, ErrZipcodeNotFound, ""},
+		{"server error", http.StatusInternalServerError, ``, nil, "unexpected status 500"},
+		{"invalid body", http.StatusOK, `{`, nil, "invalid response body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := newLookupServer(t, tt.status, tt.body)
+			provider := NewHTTPProvider(Config{BaseURL: server.URL, Timeout: time.Second})
+
+			// Act
+			address, err := provider.LookupZipcode(context.Background(), "01310100")
+
+			// Assert
+			assert.Nil(t, address)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.ErrorContains(t, err, tt.contains)
+			}
+		})
+	}
+}
+
+func TestDeliverable(t *testing.T) {
+	// Arrange
+	cfg := Config{UnservedZipcodePrefixes: []string{"69", "68900"}}
+
+	// Act & Assert
+	assert.True(t, cfg.Deliverable("01310-100"))
+	assert.False(t, cfg.Deliverable("69000-000"))
+	assert.False(t, cfg.Deliverable("68900-123"))
+	assert.True(t, cfg.Deliverable("68901-000"))
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// Arrange
+		t.Setenv("ADDRESS_LOOKUP_URL", "")
+		t.Setenv("ADDRESS_LOOKUP_TIMEOUT", "")
+		t.Setenv("ADDRESS_UNSERVED_ZIPCODE_PREFIXES", "")
+		t.Setenv("ADDRESS_CACHE_TTL", "")
+		t.Setenv("ADDRESS_CACHE_NOT_FOUND_TTL", "")
+
+		// Act
+		cfg, err := ConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Config{BaseURL: "https://viacep.com.br", Timeout: 3 * time.Second, CacheTTL: 24 * time.Hour, NotFoundCacheTTL: time.Hour}, cfg)
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		// Arrange
+		t.Setenv("ADDRESS_LOOKUP_URL", "http://cep.internal/")
+		t.Setenv("ADDRESS_LOOKUP_TIMEOUT", "500ms")
+		t.Setenv("ADDRESS_UNSERVED_ZIPCODE_PREFIXES", "69,689")
+		t.Setenv("ADDRESS_CACHE_TTL", "0s")
+		t.Setenv("ADDRESS_CACHE_NOT_FOUND_TTL", "5m")
+
+		// Act
+		cfg, err := ConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Config{
+			BaseURL:                 "http://cep.internal",
+			Timeout:                 500 * time.Millisecond,
+			UnservedZipcodePrefixes: []string{"69", "689"},
+			NotFoundCacheTTL:        5 * time.Minute,
+		}, cfg)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		// Arrange
+		t.Setenv("ADDRESS_LOOKUP_TIMEOUT", "0s")
+
+		// Act
+		_, err := ConfigFromEnv()
+
+		// Assert
+		assert.Error(t, err)
+	})
+
+	t.Run("negative cache ttl", func(t *testing.T) {
+		// Arrange
+		t.Setenv("ADDRESS_LOOKUP_TIMEOUT", "")
+		t.Setenv("ADDRESS_CACHE_TTL", "-1m")
+
+		// Act
+		_, err := ConfigFromEnv()
+
+		// Assert
+		assert.ErrorContains(t, err, "must not be negative")
+	})
+}
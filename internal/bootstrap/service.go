@@ -0,0 +1,234 @@
+// Package bootstrap builds the components shared by the API and the worker from the environment.
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/rbonfanti/shipping-calculator/internal/chaos"
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/customs"
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/rbonfanti/shipping-calculator/internal/eta"
+	"github.com/rbonfanti/shipping-calculator/internal/experiment"
+	"github.com/rbonfanti/shipping-calculator/internal/holiday"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/schedule"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/rbonfanti/shipping-calculator/internal/tax"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/rbonfanti/shipping-calculator/internal/timezone"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+)
+
+// Shipping is the shipping service configured from the environment, with the holiday calendar
+// its delivery estimates depend on
+type Shipping struct {
+	Service *service.ShippingService
+	// Calendar must be reloaded every HolidayConfig.ReloadInterval
+	Calendar      *holiday.Calendar
+	HolidayConfig holiday.Config
+	// TransitMatrix is the transit time matrix of the delivery estimates, reloaded on SIGHUP; nil
+	// when TRANSIT_MATRIX_PATH is not set
+	TransitMatrix *eta.TransitMatrix
+	// CarrierConfig is the carrier rate API quoted by the carrier strategy, when enabled
+	CarrierConfig pricing.CarrierConfig
+	// Tenants are the tenants priced with their own configuration, selected by the tenant middleware
+	Tenants tenant.Config
+	// Scheduler checks the scheduled pickups of the quotes against the pickup windows; the capacity
+	// of the windows is checked once it is given the bookings
+	Scheduler *schedule.Scheduler
+	// Determinism pins the clock and the identifiers of the quotes when enabled; Clock and IDs are
+	// the ones it configures, shared with the handlers
+	Determinism determinism.Config
+	Clock       determinism.Clock
+	IDs         determinism.IDGenerator
+}
+
+// NewShipping configures the shipping service from ETA_CONFIG_PATH, TRANSIT_MATRIX_PATH, the
+// holiday calendar, PICKUP_SCHEDULE_PATH, PRICING_CONFIG_PATH, TENANTS_CONFIG_PATH, CUSTOMS_TARIFFS_PATH, TAX_ENABLED,
+// TAX_RATES_PATH, QUOTE_CACHE_TTL, the pricing experiment, shadow pricing, carrier rates and
+// deterministic mode settings. The carrier rate API is wrapped with the faults of faults, unless
+// nil, the quotes are cached in cache, unless nil or QUOTE_CACHE_TTL is 0, and the shadow pricing
+// comparisons are recorded in metrics
+func NewShipping(ctx context.Context, faults *chaos.Injector, cache store.QuoteStore, metrics *telemetry.Metrics) (*Shipping, error) {
+	var err error
+
+	// Initialize the clock and the identifiers, pinned by the deterministic mode of tests and replays
+	determinismConfig, err := determinism.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid deterministic mode configuration: %w", err)
+	}
+	clock := determinismConfig.Clock()
+
+	// Initialize delivery estimator (warehouse handling time)
+	etaConfig := eta.Config{}
+	if path := os.Getenv("ETA_CONFIG_PATH"); path != "" {
+		if etaConfig, err = eta.LoadConfig(path); err != nil {
+			return nil, fmt.Errorf("failed to load ETA configuration: %w", err)
+		}
+	}
+
+	// Initialize the transit days between states, replacing the default days of domestic routes
+	var transitMatrix *eta.TransitMatrix
+	if path := os.Getenv("TRANSIT_MATRIX_PATH"); path != "" {
+		if transitMatrix, err = eta.LoadTransitMatrix(path); err != nil {
+			return nil, fmt.Errorf("failed to load transit matrix: %w", err)
+		}
+	}
+
+	// Initialize holiday calendar skipped by delivery estimates
+	holidayConfig, err := holiday.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid holiday calendar configuration: %w", err)
+	}
+	calendar := holiday.NewCalendar(holidayConfig.Sources()...)
+	if err := calendar.Reload(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load holiday calendar: %w", err)
+	}
+
+	// Initialize the pickup windows offered for scheduled pickups
+	scheduleConfig := schedule.DefaultConfig()
+	if path := os.Getenv("PICKUP_SCHEDULE_PATH"); path != "" {
+		if scheduleConfig, err = schedule.LoadConfig(path); err != nil {
+			return nil, fmt.Errorf("failed to load pickup schedule configuration: %w", err)
+		}
+	}
+
+	// Initialize the tariff tables of the import duties estimated for international shipments
+	customsConfig := customs.DefaultConfig()
+	if path := os.Getenv("CUSTOMS_TARIFFS_PATH"); path != "" {
+		if customsConfig, err = customs.LoadConfig(path); err != nil {
+			return nil, fmt.Errorf("failed to load customs tariff configuration: %w", err)
+		}
+	}
+
+	// Initialize the ICMS and ISS rates itemized in the freight of domestic routes, when enabled
+	taxEnabled, err := config.Bool("TAX_ENABLED", false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tax configuration: %w", err)
+	}
+	var taxCalculator *tax.Calculator
+	if path := os.Getenv("TAX_RATES_PATH"); taxEnabled || path != "" {
+		taxConfig := tax.DefaultConfig()
+		if path != "" {
+			if taxConfig, err = tax.LoadConfig(path); err != nil {
+				return nil, fmt.Errorf("failed to load tax configuration: %w", err)
+			}
+		}
+		taxCalculator = tax.NewCalculator(taxConfig)
+	}
+
+	// Initialize pricing (rates per currency and destination country)
+	pricingConfig := pricing.DefaultConfig()
+	if path := os.Getenv("PRICING_CONFIG_PATH"); path != "" {
+		if pricingConfig, err = pricing.LoadConfig(path); err != nil {
+			return nil, fmt.Errorf("failed to load pricing configuration: %w", err)
+		}
+	}
+
+	// Initialize the tenants, each priced with its own pricing configuration
+	var tenantConfig tenant.Config
+	tenantPricing := map[string]pricing.Config{}
+	if path := os.Getenv("TENANTS_CONFIG_PATH"); path != "" {
+		if tenantConfig, err = tenant.LoadConfig(path); err != nil {
+			return nil, fmt.Errorf("failed to load tenants configuration: %w", err)
+		}
+		for _, id := range tenantConfig.IDs() {
+			if tenantPricing[id], err = pricing.LoadConfig(tenantConfig.Tenants[id].PricingConfigPath); err != nil {
+				return nil, fmt.Errorf("failed to load pricing configuration of tenant %s: %w", id, err)
+			}
+		}
+	}
+
+	// Initialize the pricing experiment, routing a fraction of quotes to an alternative rate table
+	experimentConfig, err := experiment.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid pricing experiment configuration: %w", err)
+	}
+	var pricingExperiment *experiment.Experiment
+	if experimentConfig.Enabled() {
+		if pricingExperiment, err = experiment.Load(experimentConfig); err != nil {
+			return nil, fmt.Errorf("failed to load pricing experiment: %w", err)
+		}
+	}
+
+	// Initialize shadow pricing, comparing every quote with a secondary rate table
+	shadowConfig, err := experiment.ShadowConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid shadow pricing configuration: %w", err)
+	}
+	var shadowPricing *experiment.Shadow
+	if shadowConfig.Enabled() {
+		if shadowPricing, err = experiment.LoadShadow(shadowConfig); err != nil {
+			return nil, fmt.Errorf("failed to load shadow pricing: %w", err)
+		}
+	}
+
+	// Initialize the carrier pricing strategy, quoting freight with a carrier rate API
+	carrierConfig, err := pricing.CarrierConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid carrier rates configuration: %w", err)
+	}
+	strategies := map[string]pricing.Strategy{}
+	if carrierConfig.Enabled() {
+		var rates pricing.CarrierRates = pricing.NewHTTPCarrierRates(carrierConfig)
+		if faults != nil {
+			rates = chaos.WrapCarrierRates(rates, faults)
+		}
+		strategies[pricing.StrategyCarrier] = pricing.CarrierPricing{Rates: rates}
+	}
+	for level, name := range pricingConfig.Strategies {
+		if name == pricing.StrategyCarrier && !carrierConfig.Enabled() {
+			return nil, fmt.Errorf("carrier pricing strategy of service level %s requires CARRIER_RATES_URL", level)
+		}
+	}
+	for id, prices := range tenantPricing {
+		for level, name := range prices.Strategies {
+			if name == pricing.StrategyCarrier && !carrierConfig.Enabled() {
+				return nil, fmt.Errorf("carrier pricing strategy of service level %s of tenant %s requires CARRIER_RATES_URL", level, id)
+			}
+		}
+	}
+
+	// Initialize the quote cache, serving repeated quotes without pricing them again
+	cacheTTL, err := config.Duration("QUOTE_CACHE_TTL", 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quote cache configuration: %w", err)
+	}
+	if cacheTTL < 0 {
+		return nil, fmt.Errorf("QUOTE_CACHE_TTL must not be negative, got %s", cacheTTL)
+	}
+	if cacheTTL == 0 {
+		cache = nil
+	}
+
+	scheduler := schedule.NewSchedulerWithClock(scheduleConfig, clock)
+	return &Shipping{
+		Service: service.NewShippingServiceWithConfig(service.Config{
+			Estimator:  eta.NewEstimatorWithClock(etaConfig, calendar, clock).WithTransitMatrix(transitMatrix).WithTimezones(timezone.NewResolver()),
+			Pricing:    &pricingConfig,
+			Tenants:    tenantPricing,
+			Experiment: pricingExperiment,
+			Shadow:     shadowPricing,
+			Strategies: strategies,
+			Scheduler:  scheduler,
+			Customs:    customs.NewEstimator(customsConfig),
+			Tax:        taxCalculator,
+			Metrics:    metrics,
+			Cache:      cache,
+			CacheTTL:   cacheTTL,
+		}),
+		Calendar:      calendar,
+		HolidayConfig: holidayConfig,
+		TransitMatrix: transitMatrix,
+		CarrierConfig: carrierConfig,
+		Tenants:       tenantConfig,
+		Scheduler:     scheduler,
+		Determinism:   determinismConfig,
+		Clock:         clock,
+		IDs:           determinismConfig.IDGenerator(),
+	}, nil
+}
@@ -0,0 +1,60 @@
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewShipping(t *testing.T) {
+	// Arrange
+	for _, key := range []string{"ETA_CONFIG_PATH", "TRANSIT_MATRIX_PATH", "PRICING_CONFIG_PATH", "HOLIDAY_CALENDAR_PATH", "HOLIDAY_CALENDAR_URL", "CARRIER_RATES_URL"} {
+		t.Setenv(key, "")
+	}
+
+	// Act
+	shipping, err := NewShipping(context.Background(), nil, nil, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, shipping.Service)
+	assert.NotNil(t, shipping.Calendar)
+	assert.Positive(t, shipping.HolidayConfig.ReloadInterval)
+	assert.Nil(t, shipping.TransitMatrix)
+}
+
+func TestNewShipping_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		value    string
+		contains string
+	}{
+		{"missing ETA configuration", "ETA_CONFIG_PATH", "/nonexistent/eta.json", "failed to load ETA configuration"},
+		{"missing transit matrix", "TRANSIT_MATRIX_PATH", "/nonexistent/transit.csv", "failed to load transit matrix"},
+		{"missing pricing configuration", "PRICING_CONFIG_PATH", "/nonexistent/pricing.json", "failed to load pricing configuration"},
+		{"missing tenants configuration", "TENANTS_CONFIG_PATH", "/nonexistent/tenants.json", "failed to load tenants configuration"},
+		{"invalid holiday reload interval", "HOLIDAY_RELOAD_INTERVAL", "soon", "invalid holiday calendar configuration"},
+		{"missing tax configuration", "TAX_RATES_PATH", "/nonexistent/tax.json", "failed to load tax configuration"},
+		{"invalid tax switch", "TAX_ENABLED", "maybe", "invalid tax configuration"},
+		{"invalid deterministic time", "DETERMINISTIC_NOW", "yesterday", "invalid deterministic mode configuration"},
+		{"deterministic mode in production", "DETERMINISTIC_SEED", "42", "require DETERMINISTIC_MODE_ALLOWED=true"},
+		{"invalid quote cache TTL", "QUOTE_CACHE_TTL", "soon", "invalid quote cache configuration"},
+		{"negative quote cache TTL", "QUOTE_CACHE_TTL", "-1m", "QUOTE_CACHE_TTL must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv(tt.key, tt.value)
+
+			// Act
+			shipping, err := NewShipping(context.Background(), nil, nil, nil)
+
+			// Assert
+			assert.Nil(t, shipping)
+			assert.ErrorContains(t, err, tt.contains)
+		})
+	}
+}
@@ -0,0 +1,428 @@
+// Package bulk prices many shipments at once from CSV files.
+package bulk
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+)
+
+// Input columns. is_express, is_return, destination_country, currency, package_type, delivery_type,
+// pricing_strategy, pickup_date, pickup_window, freight_class, weight_unit, dimension_unit, optimize,
+// allow_weekend_delivery and additional_services (separated by ";") are optional; any other column is
+// copied to the output unchanged
+const (
+	columnOrigin      = "origin_zipcode"
+	columnDestination = "destination_zipcode"
+	columnWeight      = "weight"
+	columnLength      = "length"
+	columnWidth       = "width"
+	columnHeight      = "height"
+	columnExpress     = "is_express"
+	columnReturn      = "is_return"
+	columnCountry     = "destination_country"
+	columnCurrency    = "currency"
+	columnPackageType = "package_type"
+	columnServices    = "additional_services"
+	columnDelivery    = "delivery_type"
+	columnStrategy    = "pricing_strategy"
+	columnPickupDate  = "pickup_date"
+	columnWindow      = "pickup_window"
+	columnFreight     = "freight_class"
+	columnWeightUnit  = "weight_unit"
+	columnDimUnit     = "dimension_unit"
+	columnOptimize    = "optimize"
+	columnWeekend     = "allow_weekend_delivery"
+)
+
+// Output columns appended to each input row
+var resultColumns = []string{"quote_currency", "shipping_cost", "estimated_delivery_time", "pricing_version", "error"}
+
+var requiredColumns = []string{columnOrigin, columnDestination, columnWeight, columnLength, columnWidth, columnHeight}
+
+// errItemTimeout is the cause of the cancellation of a quote that ran out of Config.ItemTimeout
+var errItemTimeout = errors.New("bulk item timeout")
+
+// Calculator computes a single quote
+type Calculator interface {
+	CalculateShipping(ctx context.Context, req *model.CalculateShippingRequest) (*model.CalculateShippingResponse, error)
+}
+
+// Config limits the size of bulk requests and sets how rows are quoted
+type Config struct {
+	// MaxRows is the maximum number of data rows per file
+	MaxRows int
+	// MaxUploadBytes is the maximum size of an uploaded file
+	MaxUploadBytes int64
+	// Concurrency is the number of rows quoted at the same time; 0 quotes one row at a time
+	Concurrency int
+	// ItemTimeout is the deadline to quote each row; 0 means no deadline besides the request's
+	ItemTimeout time.Duration
+}
+
+// DefaultConfig returns a limit of 50000 rows and 20 MiB per file, quoting 8 rows at a time
+// with a deadline of 5 seconds each
+func DefaultConfig() Config {
+	return Config{
+		MaxRows:        50000,
+		MaxUploadBytes: 20 << 20,
+		Concurrency:    8,
+		ItemTimeout:    5 * time.Second,
+	}
+}
+
+// ConfigFromEnv reads BULK_MAX_ROWS, BULK_MAX_UPLOAD_BYTES, BULK_CONCURRENCY and BULK_ITEM_TIMEOUT,
+// falling back to DefaultConfig
+func ConfigFromEnv() (Config, error) {
+	cfg := DefaultConfig()
+
+	maxRows, err := config.Int("BULK_MAX_ROWS", cfg.MaxRows)
+	if err != nil {
+		return Config{}, err
+	}
+	maxUpload, err := config.Int("BULK_MAX_UPLOAD_BYTES", int(cfg.MaxUploadBytes))
+	if err != nil {
+		return Config{}, err
+	}
+	concurrency, err := config.Int("BULK_CONCURRENCY", cfg.Concurrency)
+	if err != nil {
+		return Config{}, err
+	}
+	itemTimeout, err := config.Duration("BULK_ITEM_TIMEOUT", cfg.ItemTimeout)
+	if err != nil {
+		return Config{}, err
+	}
+	if maxRows <= 0 || maxUpload <= 0 || concurrency <= 0 || itemTimeout <= 0 {
+		return Config{}, errors.New("bulk limits must be positive")
+	}
+
+	cfg.MaxRows = maxRows
+	cfg.MaxUploadBytes = int64(maxUpload)
+	cfg.Concurrency = concurrency
+	cfg.ItemTimeout = itemTimeout
+	return cfg, nil
+}
+
+// Summary counts the rows of a bulk run
+type Summary struct {
+	Rows      int
+	Succeeded int
+	Failed    int
+}
+
+// HeaderError means the input header is missing or invalid; nothing was written to the output
+type HeaderError struct {
+	Reason string
+}
+
+func (e *HeaderError) Error() string {
+	return "invalid CSV header: " + e.Reason
+}
+
+// Processor quotes the rows of CSV files concurrently
+type Processor struct {
+	calculator Calculator
+	cfg        Config
+	metrics    *telemetry.Metrics
+}
+
+// NewProcessor creates a processor using the given calculator and limits, recording the batch
+// sizes and item times in metrics
+func NewProcessor(calculator Calculator, cfg Config, metrics *telemetry.Metrics) *Processor {
+	return &Processor{calculator: calculator, cfg: cfg, metrics: metrics}
+}
+
+// Process reads shipments from r and streams one output row per input row to w, in input order,
+// flushing after every row. Up to Concurrency rows are quoted at the same time, each within
+// ItemTimeout. Invalid rows are reported in the "error" column without stopping the run.
+// A *HeaderError is returned when the header is invalid, before anything is written
+func (p *Processor) Process(ctx context.Context, r io.Reader, w io.Writer) (Summary, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return Summary{}, &HeaderError{Reason: "file is empty"}
+	}
+	if err != nil {
+		return Summary{}, &HeaderError{Reason: err.Error()}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range requiredColumns {
+		if _, ok := columns[required]; !ok {
+			return Summary{}, &HeaderError{Reason: fmt.Sprintf("missing column %q", required)}
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writeRow(writer, append(append([]string{}, header...), resultColumns...)); err != nil {
+		return Summary{}, err
+	}
+
+	next := func() (*item, error) {
+		record, err := reader.Read()
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			return nil, err
+		}
+		return &item{record: record, columns: columns, parseErr: err, done: make(chan result, 1)}, nil
+	}
+	write := func(job *item, res result) error {
+		if res.err != nil {
+			return writeRow(writer, resultRow(header, job.record, nil, res.err.Error()))
+		}
+		return writeRow(writer, resultRow(header, job.record, res.response, ""))
+	}
+	return p.run(ctx, next, write)
+}
+
+// run quotes the items returned by next until io.EOF and passes their results to write, in input
+// order. Up to Concurrency items are quoted at the same time; a write error stops the run
+func (p *Processor) run(ctx context.Context, next func() (*item, error), write func(*item, result) error) (Summary, error) {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := max(p.cfg.Concurrency, 1)
+	jobs := make(chan *item)
+	// pending holds the items in input order until their result is written
+	pending := make(chan *item, workers)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				response, err := p.quote(runCtx, job.record, job.columns, job.parseErr)
+				job.done <- result{response: response, err: err}
+			}
+		}()
+	}
+
+	var summary Summary
+	var writeErr error
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		for job := range pending {
+			res := <-job.done
+			if writeErr != nil {
+				continue
+			}
+			summary.Rows++
+			if res.err != nil {
+				summary.Failed++
+			} else {
+				summary.Succeeded++
+			}
+			if writeErr = write(job, res); writeErr != nil {
+				cancel()
+			}
+		}
+	}()
+
+	readErr := p.read(runCtx, next, jobs, pending)
+	close(jobs)
+	close(pending)
+	<-written
+	wg.Wait()
+
+	p.metrics.RecordBulkBatchSize(ctx, int64(summary.Rows))
+	if writeErr != nil {
+		return summary, writeErr
+	}
+	return summary, readErr
+}
+
+// item is a data row waiting for its quote. columns maps the column names to their index in
+// record; fields are the values of an item decoded from msgpack, echoed in its result
+type item struct {
+	record   []string
+	columns  map[string]int
+	fields   map[string]any
+	parseErr error
+	done     chan result
+}
+
+// result is the quote of a row
+type result struct {
+	response *model.CalculateShippingResponse
+	err      error
+}
+
+// read queues every item returned by next to be quoted by the workers and, in input order,
+// written. Items over MaxRows are not quoted: the first one is reported as exceeding the limit and
+// reading stops
+func (p *Processor) read(ctx context.Context, next func() (*item, error), jobs, pending chan<- *item) error {
+	for rows := 1; ; rows++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		job, err := next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if rows > p.cfg.MaxRows {
+			job.record = nil
+			job.fields = nil
+			job.done <- result{err: fmt.Errorf("row limit of %d exceeded", p.cfg.MaxRows)}
+			pending <- job
+			return nil
+		}
+
+		jobs <- job
+		pending <- job
+	}
+}
+
+// quote parses the record and calculates its quote
+func (p *Processor) quote(ctx context.Context, record []string, columns map[string]int, parseErr error) (*model.CalculateShippingResponse, error) {
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	req, err := parseRequest(record, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	itemCtx := ctx
+	if p.cfg.ItemTimeout > 0 {
+		var cancel context.CancelFunc
+		itemCtx, cancel = context.WithTimeoutCause(ctx, p.cfg.ItemTimeout, errItemTimeout)
+		defer cancel()
+	}
+	start := time.Now()
+	response, err := p.calculator.CalculateShipping(itemCtx, req)
+	p.metrics.RecordBulkItemTime(ctx, time.Since(start).Milliseconds())
+	if errors.Is(context.Cause(itemCtx), errItemTimeout) {
+		return nil, fmt.Errorf("quote timed out after %s", p.cfg.ItemTimeout)
+	}
+	if ctx.Err() != nil {
+		// The request itself was cancelled or ran out of time, not the quote
+		return nil, fmt.Errorf("quote not completed: %w", context.Cause(ctx))
+	}
+	return response, err
+}
+
+// parseRequest builds a calculation request from a CSV record
+func parseRequest(record []string, columns map[string]int) (*model.CalculateShippingRequest, error) {
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      field(record, columns, columnOrigin),
+		DestinationZipcode: field(record, columns, columnDestination),
+		DestinationCountry: field(record, columns, columnCountry),
+		Currency:           field(record, columns, columnCurrency),
+		PackageType:        field(record, columns, columnPackageType),
+		DeliveryType:       field(record, columns, columnDelivery),
+		PricingStrategy:    field(record, columns, columnStrategy),
+		PickupDate:         field(record, columns, columnPickupDate),
+		PickupWindow:       field(record, columns, columnWindow),
+		FreightClass:       field(record, columns, columnFreight),
+		WeightUnit:         field(record, columns, columnWeightUnit),
+		DimensionUnit:      field(record, columns, columnDimUnit),
+		Optimize:           field(record, columns, columnOptimize),
+	}
+
+	numbers := []struct {
+		column string
+		target *float64
+	}{
+		{columnWeight, &req.Weight},
+		{columnLength, &req.Dimensions.Length},
+		{columnWidth, &req.Dimensions.Width},
+		{columnHeight, &req.Dimensions.Height},
+	}
+	for _, n := range numbers {
+		if field(record, columns, n.column) == "" {
+			return nil, fmt.Errorf("%s is required", n.column)
+		}
+		value, err := strconv.ParseFloat(field(record, columns, n.column), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", n.column, field(record, columns, n.column))
+		}
+		*n.target = value
+	}
+
+	if express := field(record, columns, columnExpress); express != "" {
+		isExpress, err := strconv.ParseBool(express)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", columnExpress, express)
+		}
+		req.IsExpress = isExpress
+	}
+
+	if isReturn := field(record, columns, columnReturn); isReturn != "" {
+		value, err := strconv.ParseBool(isReturn)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", columnReturn, isReturn)
+		}
+		req.IsReturn = value
+	}
+
+	if weekend := field(record, columns, columnWeekend); weekend != "" {
+		value, err := strconv.ParseBool(weekend)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", columnWeekend, weekend)
+		}
+		req.AllowWeekendDelivery = value
+	}
+
+	if services := field(record, columns, columnServices); services != "" {
+		req.AdditionalServices = strings.Split(services, ";")
+	}
+	return req, nil
+}
+
+// field returns the trimmed value of the named column, or "" when the column is absent
+func field(record []string, columns map[string]int, name string) string {
+	i, ok := columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// resultRow copies the input record, padded or truncated to the header width, and appends the
+// result columns; response is nil for failed rows
+func resultRow(header, record []string, response *model.CalculateShippingResponse, errMsg string) []string {
+	row := make([]string, len(header), len(header)+len(resultColumns))
+	copy(row, record)
+	if response == nil {
+		return append(row, "", "", "", "", errMsg)
+	}
+	return append(row,
+		response.Currency,
+		strconv.FormatFloat(response.ShippingCost.Minor(), 'f', 2, 64),
+		response.EstimatedDeliveryTime,
+		response.PricingVersion,
+		errMsg,
+	)
+}
+
+func writeRow(writer *csv.Writer, row []string) error {
+	if err := writer.Write(row); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,520 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingCalculator always returns an error
+type failingCalculator struct{}
+
+func (failingCalculator) CalculateShipping(ctx context.Context, req *model.CalculateShippingRequest) (*model.CalculateShippingResponse, error) {
+	return nil, errors.New("calculator unavailable")
+}
+
+// slowCalculator echoes the weight of each shipment as its cost after sleeping that many
+// milliseconds, tracking how many quotes run at the same time
+type slowCalculator struct {
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (c *slowCalculator) CalculateShipping(ctx context.Context, req *model.CalculateShippingRequest) (*model.CalculateShippingResponse, error) {
+	current := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	for {
+		peak := c.maxInFlight.Load()
+		if current <= peak || c.maxInFlight.CompareAndSwap(peak, current) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(time.Duration(req.Weight) * time.Millisecond):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &model.CalculateShippingResponse{ShippingCost: money.FromMinor(req.Weight)}, nil
+}
+
+func readOutput(t *testing.T, out *bytes.Buffer) [][]string {
+	t.Helper()
+	reader := csv.NewReader(out)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	assert.NoError(t, err)
+	return rows
+}
+
+func TestProcess(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	input := "shipment,origin_zipcode,destination_zipcode,weight,length,width,height,is_express\n" +
+		"S1,12345678,12345678,1,10,10,10,false\n" +
+		"S2,12345678,12345678,1,10,10,10,true\n" +
+		"S3,123,12345678,1,10,10,10,\n" +
+		"S4,12345678,12345678,abc,10,10,10,\n" +
+		"S5,12345678,12345678,1,10,10,10,maybe\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 5, Succeeded: 2, Failed: 3}, summary)
+
+	rows := readOutput(t, &out)
+	version := pricing.DefaultConfig().VersionID()
+	assert.Len(t, rows, 6)
+	assert.Equal(t, []string{"shipment", "origin_zipcode", "destination_zipcode", "weight", "length", "width", "height",
+		"is_express", "quote_currency", "shipping_cost", "estimated_delivery_time", "pricing_version", "error"}, rows[0])
+	assert.Equal(t, []string{"S1", "BRL", "1250.00", "2 dias", version, ""}, append([]string{rows[1][0]}, rows[1][8:]...))
+	assert.Equal(t, []string{"S2", "BRL", "1875.00", "1 dia", version, ""}, append([]string{rows[2][0]}, rows[2][8:]...))
+	assert.Contains(t, rows[3][12], "invalid origin_zipcode")
+	assert.Equal(t, `invalid weight "abc"`, rows[4][12])
+	assert.Equal(t, `invalid is_express "maybe"`, rows[5][12])
+	assert.Empty(t, rows[5][9])
+}
+
+func TestProcess_Currency(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height,destination_country,currency\n" +
+		"12345678,12345678,1,10,10,10,US,\n" +
+		"12345678,12345678,1,10,10,10,BR,EUR\n" +
+		"12345678,12345678,1,10,10,10,AR,\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 3, Succeeded: 2, Failed: 1}, summary)
+	rows := readOutput(t, &out)
+	assert.Equal(t, []string{"USD", "625.00"}, rows[1][8:10])
+	assert.Equal(t, []string{"EUR", "563.00"}, rows[2][8:10])
+	assert.Contains(t, rows[3][12], "unsupported destination country")
+}
+
+func TestProcess_PackageAndDeliveryType(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height,is_express,package_type,delivery_type\n" +
+		"12345678,12345678,1,10,10,10,false,fragile,\n" +
+		"12345678,12345678,1,10,10,10,true,dangerous,\n" +
+		"12345678,12345678,1,10,10,10,false,,locker\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 3, Succeeded: 2, Failed: 1}, summary)
+	rows := readOutput(t, &out)
+	assert.Equal(t, "1437.50", rows[1][10])
+	assert.Contains(t, rows[2][13], "express delivery is not allowed")
+	assert.Equal(t, "1000.00", rows[3][10])
+}
+
+func TestProcess_PricingStrategy(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height,pricing_strategy\n" +
+		"12345678,12345678,1,10,10,10,formula\n" +
+		"12345678,12345678,1,10,10,10,auction\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 2, Succeeded: 1, Failed: 1}, summary)
+	rows := readOutput(t, &out)
+	assert.Equal(t, "1250.00", rows[1][8])
+	assert.Contains(t, rows[2][11], "unsupported pricing strategy")
+}
+
+func TestProcess_Optimize(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height,optimize\n" +
+		"12345678,12345678,1,10,10,10,fastest\n" +
+		"12345678,12345678,1,10,10,10,greenest\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 2, Succeeded: 1, Failed: 1}, summary)
+	rows := readOutput(t, &out)
+	assert.Equal(t, "1875.00", rows[1][8])
+	assert.Contains(t, rows[2][11], "unsupported optimize objective")
+}
+
+func TestProcess_Return(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height,is_express,is_return\n" +
+		"12345678,12345678,1,10,10,10,false,true\n" +
+		"12345678,12345678,1,10,10,10,true,true\n" +
+		"12345678,12345678,1,10,10,10,false,maybe\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 3, Succeeded: 1, Failed: 2}, summary)
+	rows := readOutput(t, &out)
+	assert.Equal(t, "1250.00", rows[1][9])
+	assert.Contains(t, rows[2][12], "service level is not offered for returns")
+	assert.Equal(t, `invalid is_return "maybe"`, rows[3][12])
+}
+
+func TestProcess_AllowWeekendDelivery(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	cfg.WeekendDelivery = map[string]pricing.WeekendDelivery{pricing.LevelStandard: {Saturday: true, SurchargeRate: 0.1}}
+	processor := NewProcessor(service.NewShippingService(service.WithPricingConfig(cfg)), DefaultConfig(), nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height,allow_weekend_delivery\n" +
+		"12345678,12345678,1,10,10,10,true\n" +
+		"12345678,12345678,1,10,10,10,\n" +
+		"12345678,12345678,1,10,10,10,sometimes\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 3, Succeeded: 2, Failed: 1}, summary)
+	rows := readOutput(t, &out)
+	assert.Equal(t, "1375.00", rows[1][8])
+	assert.Equal(t, "1250.00", rows[2][8])
+	assert.Equal(t, `invalid allow_weekend_delivery "sometimes"`, rows[3][11])
+}
+
+func TestProcess_Pickup(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height,pickup_date,pickup_window\n" +
+		"12345678,12345678,1,10,10,10,,\n" +
+		"12345678,12345678,1,10,10,10,,morning\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 2, Succeeded: 1, Failed: 1}, summary)
+	rows := readOutput(t, &out)
+	assert.Contains(t, rows[2][12], "pickup_date and pickup_window must be set together")
+}
+
+func TestProcess_Units(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height,weight_unit,dimension_unit\n" +
+		"12345678,12345678,1000,0.1,0.1,0.1,g,m\n" +
+		"12345678,12345678,1,10,10,10,oz,\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 2, Succeeded: 1, Failed: 1}, summary)
+	rows := readOutput(t, &out)
+	assert.Equal(t, "1250.00", rows[1][9])
+	assert.Contains(t, rows[2][12], "invalid weight_unit")
+}
+
+func TestProcess_EmptyNumbers(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height\n" +
+		"12345678,12345678,,10,10,10\n" +
+		"12345678,12345678,1,10,,10\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 2, Failed: 2}, summary)
+	rows := readOutput(t, &out)
+	assert.Equal(t, "weight is required", rows[1][10])
+	assert.Equal(t, "width is required", rows[2][10])
+}
+
+func TestProcess_AdditionalServices(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height,additional_services\n" +
+		"12345678,12345678,1,10,10,10,cod;signature\n" +
+		"12345678,12345678,1,10,10,10,insurance\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 2, Succeeded: 1, Failed: 1}, summary)
+	rows := readOutput(t, &out)
+	assert.Equal(t, "2050.00", rows[1][8])
+	assert.Contains(t, rows[2][11], "unsupported additional service")
+}
+
+func TestProcess_MalformedRow(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height\n" +
+		"12345678,\"1234\"5678,1,10,10,10\n" +
+		"12345678,12345678,1,10,10,10\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 2, Succeeded: 1, Failed: 1}, summary)
+	rows := readOutput(t, &out)
+	assert.Len(t, rows, 3)
+	assert.NotEmpty(t, rows[1][10])
+	assert.Empty(t, rows[2][10])
+}
+
+func TestProcess_CalculatorError(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(failingCalculator{}, DefaultConfig(), nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height\n12345678,12345678,1,10,10,10\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, "calculator unavailable", readOutput(t, &out)[1][10])
+}
+
+func TestProcess_RowLimit(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), Config{MaxRows: 1, MaxUploadBytes: 1024}, nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height\n" +
+		"12345678,12345678,1,10,10,10\n" +
+		"12345678,12345678,1,10,10,10\n" +
+		"12345678,12345678,1,10,10,10\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 2, Succeeded: 1, Failed: 1}, summary)
+	rows := readOutput(t, &out)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, "row limit of 1 exceeded", rows[2][10])
+}
+
+func TestProcess_Concurrency(t *testing.T) {
+	// Arrange
+	calculator := &slowCalculator{}
+	processor := NewProcessor(calculator, Config{MaxRows: 100, Concurrency: 3, ItemTimeout: time.Second}, nil)
+	weights := []int{40, 5, 30, 1, 20, 10, 35, 2, 15, 25}
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height\n"
+	for _, weight := range weights {
+		input += "12345678,12345678," + strconv.Itoa(weight) + ",10,10,10\n"
+	}
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: len(weights), Succeeded: len(weights)}, summary)
+	rows := readOutput(t, &out)
+	assert.Len(t, rows, len(weights)+1)
+	for i, weight := range weights {
+		assert.Equal(t, strconv.Itoa(weight), rows[i+1][2])
+		assert.Equal(t, strconv.Itoa(weight)+".00", rows[i+1][7])
+	}
+	assert.LessOrEqual(t, calculator.maxInFlight.Load(), int32(3))
+	assert.Greater(t, calculator.maxInFlight.Load(), int32(1))
+}
+
+func TestProcess_ItemTimeout(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(&slowCalculator{}, Config{MaxRows: 100, Concurrency: 2, ItemTimeout: 20 * time.Millisecond}, nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height\n" +
+		"12345678,12345678,1000,10,10,10\n" +
+		"12345678,12345678,1,10,10,10\n"
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.Process(context.Background(), strings.NewReader(input), &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 2, Succeeded: 1, Failed: 1}, summary)
+	rows := readOutput(t, &out)
+	assert.Equal(t, "quote timed out after 20ms", rows[1][10])
+	assert.Empty(t, rows[2][10])
+}
+
+func TestProcess_RequestDeadline(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(&slowCalculator{}, Config{MaxRows: 100, Concurrency: 1, ItemTimeout: time.Minute}, nil)
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height\n" +
+		"12345678,12345678,1000,10,10,10\n"
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var out bytes.Buffer
+
+	// Act
+	summary, _ := processor.Process(ctx, strings.NewReader(input), &out)
+
+	// Assert
+	assert.Equal(t, 1, summary.Failed)
+	rows := readOutput(t, &out)
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, "quote not completed: context deadline exceeded", rows[1][10])
+	}
+}
+
+func TestProcess_HeaderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"empty file", "", "file is empty"},
+		{"missing column", "origin_zipcode,destination_zipcode,weight,length,width\n", `missing column "height"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+			var out bytes.Buffer
+
+			// Act
+			_, err := processor.Process(context.Background(), strings.NewReader(tt.input), &out)
+
+			// Assert
+			var headerErr *HeaderError
+			assert.ErrorAs(t, err, &headerErr)
+			assert.ErrorContains(t, err, tt.wantErr)
+			assert.Empty(t, out.String())
+		})
+	}
+}
+
+func TestProcess_ContextCancelled(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	input := "origin_zipcode,destination_zipcode,weight,length,width,height\n12345678,12345678,1,10,10,10\n"
+
+	// Act
+	_, err := processor.Process(ctx, strings.NewReader(input), &bytes.Buffer{})
+
+	// Assert
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// Arrange
+		t.Setenv("BULK_MAX_ROWS", "")
+		t.Setenv("BULK_MAX_UPLOAD_BYTES", "")
+		t.Setenv("BULK_CONCURRENCY", "")
+		t.Setenv("BULK_ITEM_TIMEOUT", "")
+
+		// Act
+		cfg, err := ConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultConfig(), cfg)
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		// Arrange
+		t.Setenv("BULK_MAX_ROWS", "10")
+		t.Setenv("BULK_MAX_UPLOAD_BYTES", "2048")
+		t.Setenv("BULK_CONCURRENCY", "4")
+		t.Setenv("BULK_ITEM_TIMEOUT", "750ms")
+
+		// Act
+		cfg, err := ConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Config{MaxRows: 10, MaxUploadBytes: 2048, Concurrency: 4, ItemTimeout: 750 * time.Millisecond}, cfg)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		// Arrange
+		t.Setenv("BULK_MAX_ROWS", "0")
+
+		// Act
+		_, err := ConfigFromEnv()
+
+		// Assert
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid concurrency", func(t *testing.T) {
+		// Arrange
+		t.Setenv("BULK_MAX_ROWS", "")
+		t.Setenv("BULK_CONCURRENCY", "0")
+
+		// Act
+		_, err := ConfigFromEnv()
+
+		// Assert
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid item timeout", func(t *testing.T) {
+		// Arrange
+		t.Setenv("BULK_CONCURRENCY", "")
+		t.Setenv("BULK_ITEM_TIMEOUT", "soon")
+
+		// Act
+		_, err := ConfigFromEnv()
+
+		// Assert
+		assert.ErrorContains(t, err, "BULK_ITEM_TIMEOUT must be a duration")
+	})
+}
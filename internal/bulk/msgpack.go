@@ -0,0 +1,165 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/msgpack"
+)
+
+// BodyError means the msgpack body is not an array of items; nothing was written to the output
+type BodyError struct {
+	Reason string
+}
+
+func (e *BodyError) Error() string {
+	return "invalid msgpack body: " + e.Reason
+}
+
+// ProcessMsgpack quotes the shipments of a msgpack array read from r, each a map keyed by the CSV
+// column names, and streams to w a msgpack array with one map per item, in input order. Each map
+// echoes the item with quote_currency, shipping_cost, estimated_delivery_time and pricing_version
+// added, or error for items that failed. Values may be strings, numbers or booleans, and
+// additional_services an array of strings. Items are quoted as Process quotes rows; a *BodyError
+// is returned when the body is not an array, before anything is written. An item that cannot be
+// decoded, and every item after it, is answered with error and the decoding error is returned
+func (p *Processor) ProcessMsgpack(ctx context.Context, r io.Reader, w io.Writer) (Summary, error) {
+	decoder := msgpack.NewDecoder(r)
+	remaining, err := decoder.DecodeArrayLen()
+	if errors.Is(err, io.EOF) {
+		return Summary{}, &BodyError{Reason: "body is empty"}
+	}
+	if err != nil {
+		return Summary{}, &BodyError{Reason: err.Error()}
+	}
+
+	// Items over MaxRows are answered with a single row limit error
+	encoder := msgpack.NewEncoder(w)
+	if err := encoder.EncodeArrayLen(min(remaining, p.cfg.MaxRows+1)); err != nil {
+		return Summary{}, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return Summary{}, err
+	}
+
+	// Once an item fails to decode, the body cannot be read further: that item and the ones after
+	// it are answered with an error, so the output still holds the announced number of items
+	var decodeErr error
+	next := func() (*item, error) {
+		if remaining == 0 {
+			return nil, io.EOF
+		}
+		remaining--
+		job := &item{done: make(chan result, 1)}
+		if decodeErr != nil {
+			job.parseErr = fmt.Errorf("item could not be decoded: %w", decodeErr)
+			return job, nil
+		}
+		value, err := decoder.Decode()
+		if errors.Is(err, io.EOF) {
+			// The body ended before the items its array announced
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			decodeErr = err
+			job.parseErr = fmt.Errorf("item could not be decoded: %w", err)
+			return job, nil
+		}
+		fields, ok := value.(map[string]any)
+		if !ok {
+			job.parseErr = errors.New("item must be a map")
+			return job, nil
+		}
+		job.fields = fields
+		job.record, job.columns, job.parseErr = recordOf(fields)
+		return job, nil
+	}
+	write := func(job *item, res result) error {
+		if err := encoder.Encode(resultFields(job.fields, res)); err != nil {
+			return err
+		}
+		return encoder.Flush()
+	}
+	summary, err := p.run(ctx, next, write)
+	if err == nil && decodeErr != nil {
+		err = decodeErr
+	}
+	return summary, err
+}
+
+// recordOf converts the fields of an item into a record, with the values formatted as in a CSV
+// file and additional_services joined by ";"
+func recordOf(fields map[string]any) ([]string, map[string]int, error) {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	record := make([]string, len(names))
+	columns := make(map[string]int, len(names))
+	for i, name := range names {
+		value, err := formatValue(fields[name], name == columnServices)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		record[i] = value
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	return record, columns, nil
+}
+
+// formatValue formats a field value as the text of a CSV cell; list is true for the columns that
+// take an array of strings
+func formatValue(value any, list bool) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+	case uint64:
+		return strconv.FormatUint(value, 10), nil
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64), nil
+	case []any:
+		if !list {
+			break
+		}
+		items := make([]string, len(value))
+		for i, v := range value {
+			item, ok := v.(string)
+			if !ok {
+				return "", errors.New("must be an array of strings")
+			}
+			items[i] = item
+		}
+		return strings.Join(items, ";"), nil
+	}
+	return "", errors.New("must be a string, number or boolean")
+}
+
+// resultFields returns the fields of an item with the result of its quote
+func resultFields(fields map[string]any, res result) map[string]any {
+	out := make(map[string]any, len(fields)+len(resultColumns))
+	for name, value := range fields {
+		out[name] = value
+	}
+	if res.err != nil {
+		out["error"] = res.err.Error()
+		return out
+	}
+	out["quote_currency"] = res.response.Currency
+	out["shipping_cost"] = res.response.ShippingCost.Minor()
+	out["estimated_delivery_time"] = res.response.EstimatedDeliveryTime
+	out["pricing_version"] = res.response.PricingVersion
+	return out
+}
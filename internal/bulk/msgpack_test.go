@@ -0,0 +1,198 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/msgpack"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// encodeItems encodes the items of a msgpack batch
+func encodeItems(t *testing.T, items ...any) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	assert.NoError(t, encoder.Encode(items))
+	assert.NoError(t, encoder.Flush())
+	return &buf
+}
+
+// readMsgpackOutput decodes the msgpack array written by ProcessMsgpack
+func readMsgpackOutput(t *testing.T, out *bytes.Buffer) []map[string]any {
+	t.Helper()
+	value, err := msgpack.NewDecoder(out).Decode()
+	assert.NoError(t, err)
+	items, _ := value.([]any)
+	results := make([]map[string]any, len(items))
+	for i, item := range items {
+		results[i], _ = item.(map[string]any)
+	}
+	return results
+}
+
+// shipment returns the fields of a valid msgpack item
+func shipment(fields map[string]any) map[string]any {
+	item := map[string]any{
+		"origin_zipcode":      "12345678",
+		"destination_zipcode": "12345678",
+		"weight":              1,
+		"length":              10.0,
+		"width":               "10",
+		"height":              10,
+	}
+	for name, value := range fields {
+		item[name] = value
+	}
+	return item
+}
+
+func TestProcessMsgpack(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	body := encodeItems(t,
+		shipment(map[string]any{"shipment": "S1"}),
+		shipment(map[string]any{"shipment": "S2", "is_express": true}),
+		shipment(map[string]any{"shipment": "S3", "origin_zipcode": "123"}),
+		shipment(map[string]any{"shipment": "S4", "weight": "abc"}),
+		"not a map",
+		shipment(map[string]any{"shipment": "S6", "weight": []any{1}}),
+		map[string]any{"shipment": "S7"},
+	)
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.ProcessMsgpack(context.Background(), body, &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 7, Succeeded: 2, Failed: 5}, summary)
+
+	results := readMsgpackOutput(t, &out)
+	version := pricing.DefaultConfig().VersionID()
+	assert.Len(t, results, 7)
+	assert.Equal(t, "S1", results[0]["shipment"])
+	assert.Equal(t, "BRL", results[0]["quote_currency"])
+	assert.Equal(t, 1250.0, results[0]["shipping_cost"])
+	assert.Equal(t, "2 dias", results[0]["estimated_delivery_time"])
+	assert.Equal(t, version, results[0]["pricing_version"])
+	assert.NotContains(t, results[0], "error")
+	assert.Equal(t, 1875.0, results[1]["shipping_cost"])
+	assert.Equal(t, "1 dia", results[1]["estimated_delivery_time"])
+	assert.Contains(t, results[2]["error"], "invalid origin_zipcode")
+	assert.NotContains(t, results[2], "shipping_cost")
+	assert.Equal(t, `invalid weight "abc"`, results[3]["error"])
+	assert.Equal(t, map[string]any{"error": "item must be a map"}, results[4])
+	assert.Equal(t, "invalid weight: must be a string, number or boolean", results[5]["error"])
+	assert.Equal(t, "weight is required", results[6]["error"])
+}
+
+func TestProcessMsgpack_AdditionalServices(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	body := encodeItems(t,
+		shipment(map[string]any{"additional_services": []any{pricing.ServiceSignature}}),
+		shipment(map[string]any{"additional_services": pricing.ServiceSignature}),
+		shipment(map[string]any{"additional_services": []any{pricing.ServiceSignature, 1}}),
+	)
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.ProcessMsgpack(context.Background(), body, &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 3, Succeeded: 2, Failed: 1}, summary)
+	results := readMsgpackOutput(t, &out)
+	assert.Equal(t, results[0]["shipping_cost"], results[1]["shipping_cost"])
+	assert.Equal(t, []any{pricing.ServiceSignature}, results[0]["additional_services"])
+	assert.Equal(t, "invalid additional_services: must be an array of strings", results[2]["error"])
+}
+
+func TestProcessMsgpack_RowLimit(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), Config{MaxRows: 1, MaxUploadBytes: 1024}, nil)
+	body := encodeItems(t, shipment(nil), shipment(nil), shipment(nil))
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.ProcessMsgpack(context.Background(), body, &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Rows: 2, Succeeded: 1, Failed: 1}, summary)
+	results := readMsgpackOutput(t, &out)
+	assert.Len(t, results, 2)
+	assert.Equal(t, map[string]any{"error": "row limit of 1 exceeded"}, results[1])
+}
+
+func TestProcessMsgpack_BodyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"empty body", "", "body is empty"},
+		{"not an array", "\x81\xa1a\x01", "invalid msgpack body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+			var out bytes.Buffer
+
+			// Act
+			_, err := processor.ProcessMsgpack(context.Background(), strings.NewReader(tt.body), &out)
+
+			// Assert
+			var bodyErr *BodyError
+			assert.ErrorAs(t, err, &bodyErr)
+			assert.ErrorContains(t, err, tt.wantErr)
+			assert.Empty(t, out.String())
+		})
+	}
+}
+
+func TestProcessMsgpack_Truncated(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	body := encodeItems(t, shipment(nil), shipment(nil))
+	truncated := bytes.NewReader(body.Bytes()[:body.Len()-3])
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.ProcessMsgpack(context.Background(), truncated, &out)
+
+	// Assert
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, Summary{Rows: 2, Succeeded: 1, Failed: 1}, summary)
+	results := readMsgpackOutput(t, &out)
+	assert.Len(t, results, 2, "the output holds the announced number of items")
+	assert.Equal(t, map[string]any{"error": "item could not be decoded: unexpected EOF"}, results[1])
+}
+
+func TestProcessMsgpack_Undecodable(t *testing.T) {
+	// Arrange
+	processor := NewProcessor(service.NewShippingService(), DefaultConfig(), nil)
+	// An array of three items whose first item uses the reserved 0xc1 type
+	body := strings.NewReader("\x93\xc1")
+	var out bytes.Buffer
+
+	// Act
+	summary, err := processor.ProcessMsgpack(context.Background(), body, &out)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, Summary{Rows: 3, Failed: 3}, summary)
+	results := readMsgpackOutput(t, &out)
+	assert.Len(t, results, 3)
+	for _, result := range results {
+		assert.Contains(t, result["error"], "item could not be decoded")
+	}
+}
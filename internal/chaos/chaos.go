@@ -0,0 +1,164 @@
+// Package chaos injects faults into the carrier providers and the quote store to exercise the
+// resilience of the service in game days. Faults are set at runtime through the admin API and are
+// only wired when CHAOS_ENABLED is set, so production deployments never carry them.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+)
+
+// Targets of the faults
+const (
+	TargetCarrierRates     = "carrier_rates"
+	TargetTrackingProvider = "tracking_provider"
+	TargetLabelProvider    = "label_provider"
+	TargetManifestProvider = "manifest_provider"
+	TargetAddressLookup    = "address_lookup"
+	TargetQuoteStore       = "quote_store"
+)
+
+// maxLatency caps the latency injected into a call
+const maxLatency = 5 * time.Minute
+
+// Targets are the dependencies faults can be injected into, named as their /readyz probes
+var Targets = []string{
+	TargetCarrierRates,
+	TargetTrackingProvider,
+	TargetLabelProvider,
+	TargetManifestProvider,
+	TargetAddressLookup,
+	TargetQuoteStore,
+}
+
+var (
+	// ErrInjected is returned by the calls failed by a fault
+	ErrInjected = errors.New("chaos: injected fault")
+	// ErrUnknownTarget is returned when setting a fault on a target not in Targets
+	ErrUnknownTarget = errors.New("chaos: unknown target")
+	// ErrInvalidFault is returned when a fault is malformed
+	ErrInvalidFault = errors.New("chaos: invalid fault")
+)
+
+// Config configures the fault injection
+type Config struct {
+	// Enabled wires the fault injection into the providers and the quote store and exposes the
+	// /admin/chaos routes
+	Enabled bool
+}
+
+// ConfigFromEnv reads CHAOS_ENABLED (default false)
+func ConfigFromEnv() (Config, error) {
+	enabled, err := config.Bool("CHAOS_ENABLED", false)
+	if err != nil {
+		return Config{}, err
+	}
+	return Config{Enabled: enabled}, nil
+}
+
+// Fault slows down and fails the calls to a target
+type Fault struct {
+	// LatencyMs delays every call, in milliseconds
+	LatencyMs int64 `json:"latency_ms"`
+	// ErrorRate is the fraction of the calls failed with ErrInjected, from 0 to 1; below 1 it
+	// simulates partial failures
+	ErrorRate float64 `json:"error_rate"`
+}
+
+// Validate checks the latency and the error rate of the fault
+func (f Fault) Validate() error {
+	if f.LatencyMs < 0 || f.LatencyMs > maxLatency.Milliseconds() {
+		return fmt.Errorf("%w: latency_ms must be between 0 and %d, got %d", ErrInvalidFault, maxLatency.Milliseconds(), f.LatencyMs)
+	}
+	if f.ErrorRate < 0 || f.ErrorRate > 1 {
+		return fmt.Errorf("%w: error_rate must be between 0 and 1, got %g", ErrInvalidFault, f.ErrorRate)
+	}
+	return nil
+}
+
+// TargetFault is the fault set on a target
+type TargetFault struct {
+	Target string `json:"target"`
+	Fault
+}
+
+// Injector holds the faults of the targets. It is safe for concurrent use
+type Injector struct {
+	mu     sync.RWMutex
+	faults map[string]Fault
+	// random returns a number in [0, 1) deciding whether a call fails
+	random func() float64
+}
+
+// NewInjector creates an injector without faults
+func NewInjector() *Injector {
+	return &Injector{faults: make(map[string]Fault), random: rand.Float64}
+}
+
+// Set replaces the fault of target
+func (i *Injector) Set(target string, fault Fault) error {
+	if !slices.Contains(Targets, target) {
+		return fmt.Errorf("%w %q", ErrUnknownTarget, target)
+	}
+	if err := fault.Validate(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults[target] = fault
+	return nil
+}
+
+// Clear removes the fault of target, reporting whether there was one
+func (i *Injector) Clear(target string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	_, found := i.faults[target]
+	delete(i.faults, target)
+	return found
+}
+
+// Faults returns the faults set, sorted by target
+func (i *Injector) Faults() []TargetFault {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	faults := make([]TargetFault, 0, len(i.faults))
+	for target, fault := range i.faults {
+		faults = append(faults, TargetFault{Target: target, Fault: fault})
+	}
+	sort.Slice(faults, func(a, b int) bool { return faults[a].Target < faults[b].Target })
+	return faults
+}
+
+// Inject applies the fault of target to a call: it waits the latency, returning the context error
+// when ctx is done first, and then fails the call with ErrInjected at the error rate
+func (i *Injector) Inject(ctx context.Context, target string) error {
+	i.mu.RLock()
+	fault, found := i.faults[target]
+	i.mu.RUnlock()
+	if !found {
+		return nil
+	}
+
+	if fault.LatencyMs > 0 {
+		timer := time.NewTimer(time.Duration(fault.LatencyMs) * time.Millisecond)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if fault.ErrorRate > 0 && i.random() < fault.ErrorRate {
+		return fmt.Errorf("%w into %s", ErrInjected, target)
+	}
+	return nil
+}
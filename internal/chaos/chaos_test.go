@@ -0,0 +1,146 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	// Arrange
+	t.Setenv("CHAOS_ENABLED", "true")
+
+	// Act
+	cfg, err := ConfigFromEnv()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, cfg.Enabled)
+}
+
+func TestConfigFromEnv_Invalid(t *testing.T) {
+	// Arrange
+	t.Setenv("CHAOS_ENABLED", "sometimes")
+
+	// Act
+	_, err := ConfigFromEnv()
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestInjector_Set(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		fault   Fault
+		wantErr error
+	}{
+		{"latency", TargetCarrierRates, Fault{LatencyMs: 2000}, nil},
+		{"partial failures", TargetQuoteStore, Fault{ErrorRate: 0.3}, nil},
+		{"unknown target", "database", Fault{ErrorRate: 1}, ErrUnknownTarget},
+		{"negative latency", TargetLabelProvider, Fault{LatencyMs: -1}, ErrInvalidFault},
+		{"latency too long", TargetLabelProvider, Fault{LatencyMs: 600000}, ErrInvalidFault},
+		{"error rate above 1", TargetAddressLookup, Fault{ErrorRate: 1.5}, ErrInvalidFault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			injector := NewInjector()
+
+			// Act
+			err := injector.Set(tt.target, tt.fault)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				assert.Equal(t, []TargetFault{{Target: tt.target, Fault: tt.fault}}, injector.Faults())
+			} else {
+				assert.Empty(t, injector.Faults())
+			}
+		})
+	}
+}
+
+func TestInjector_Clear(t *testing.T) {
+	// Arrange
+	injector := NewInjector()
+	assert.NoError(t, injector.Set(TargetTrackingProvider, Fault{ErrorRate: 1}))
+	assert.NoError(t, injector.Set(TargetCarrierRates, Fault{ErrorRate: 1}))
+
+	// Act
+	cleared := injector.Clear(TargetTrackingProvider)
+	clearedAgain := injector.Clear(TargetTrackingProvider)
+
+	// Assert
+	assert.True(t, cleared)
+	assert.False(t, clearedAgain)
+	assert.Equal(t, []TargetFault{{Target: TargetCarrierRates, Fault: Fault{ErrorRate: 1}}}, injector.Faults())
+	assert.NoError(t, injector.Inject(context.Background(), TargetTrackingProvider))
+}
+
+func TestInjector_Inject(t *testing.T) {
+	tests := []struct {
+		name    string
+		fault   Fault
+		random  float64
+		wantErr bool
+	}{
+		{"always fails", Fault{ErrorRate: 1}, 0.99, true},
+		{"partial failure hit", Fault{ErrorRate: 0.3}, 0.2, true},
+		{"partial failure missed", Fault{ErrorRate: 0.3}, 0.3, false},
+		{"latency only", Fault{LatencyMs: 1}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			injector := NewInjector()
+			injector.random = func() float64 { return tt.random }
+			assert.NoError(t, injector.Set(TargetManifestProvider, tt.fault))
+
+			// Act
+			err := injector.Inject(context.Background(), TargetManifestProvider)
+
+			// Assert
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInjected)
+				assert.ErrorContains(t, err, TargetManifestProvider)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestInjector_InjectLatency(t *testing.T) {
+	// Arrange
+	injector := NewInjector()
+	assert.NoError(t, injector.Set(TargetAddressLookup, Fault{LatencyMs: 50}))
+	start := time.Now()
+
+	// Act
+	err := injector.Inject(context.Background(), TargetAddressLookup)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestInjector_InjectLatencyCancelled(t *testing.T) {
+	// Arrange
+	injector := NewInjector()
+	assert.NoError(t, injector.Set(TargetAddressLookup, Fault{LatencyMs: 60000}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Act
+	err := injector.Inject(ctx, TargetAddressLookup)
+
+	// Assert
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -0,0 +1,165 @@
+package chaos
+
+import (
+	"context"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/address"
+	"github.com/rbonfanti/shipping-calculator/internal/label"
+	"github.com/rbonfanti/shipping-calculator/internal/manifest"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/rbonfanti/shipping-calculator/internal/tracking"
+)
+
+// CarrierRates injects the faults of TargetCarrierRates into a carrier rate API
+type CarrierRates struct {
+	next     pricing.CarrierRates
+	injector *Injector
+}
+
+// WrapCarrierRates wraps next with the faults of injector
+func WrapCarrierRates(next pricing.CarrierRates, injector *Injector) *CarrierRates {
+	return &CarrierRates{next: next, injector: injector}
+}
+
+// Rate implements pricing.CarrierRates
+func (c *CarrierRates) Rate(ctx context.Context, shipment pricing.Shipment) (money.Amount, error) {
+	if err := c.injector.Inject(ctx, TargetCarrierRates); err != nil {
+		return 0, err
+	}
+	return c.next.Rate(ctx, shipment)
+}
+
+// TrackingProvider injects the faults of TargetTrackingProvider into a tracking provider
+type TrackingProvider struct {
+	next     tracking.TrackingProvider
+	injector *Injector
+}
+
+// WrapTrackingProvider wraps next with the faults of injector
+func WrapTrackingProvider(next tracking.TrackingProvider, injector *Injector) *TrackingProvider {
+	return &TrackingProvider{next: next, injector: injector}
+}
+
+// Track implements tracking.TrackingProvider
+func (p *TrackingProvider) Track(ctx context.Context, shipment *model.Shipment) ([]model.TrackingEvent, error) {
+	if err := p.injector.Inject(ctx, TargetTrackingProvider); err != nil {
+		return nil, err
+	}
+	return p.next.Track(ctx, shipment)
+}
+
+// LabelProvider injects the faults of TargetLabelProvider into a label provider
+type LabelProvider struct {
+	next     label.LabelProvider
+	injector *Injector
+}
+
+// WrapLabelProvider wraps next with the faults of injector
+func WrapLabelProvider(next label.LabelProvider, injector *Injector) *LabelProvider {
+	return &LabelProvider{next: next, injector: injector}
+}
+
+// Generate implements label.LabelProvider
+func (p *LabelProvider) Generate(ctx context.Context, shipment *model.Shipment, format, idempotencyKey string) (label.Document, error) {
+	if err := p.injector.Inject(ctx, TargetLabelProvider); err != nil {
+		return label.Document{}, err
+	}
+	return p.next.Generate(ctx, shipment, format, idempotencyKey)
+}
+
+// ManifestSubmitter injects the faults of TargetManifestProvider into a manifest submitter
+type ManifestSubmitter struct {
+	next     manifest.Submitter
+	injector *Injector
+}
+
+// WrapManifestSubmitter wraps next with the faults of injector
+func WrapManifestSubmitter(next manifest.Submitter, injector *Injector) *ManifestSubmitter {
+	return &ManifestSubmitter{next: next, injector: injector}
+}
+
+// Submit implements manifest.Submitter
+func (s *ManifestSubmitter) Submit(ctx context.Context, closed *model.Manifest, shipments []model.Shipment) (string, error) {
+	if err := s.injector.Inject(ctx, TargetManifestProvider); err != nil {
+		return "", err
+	}
+	return s.next.Submit(ctx, closed, shipments)
+}
+
+// AddressProvider injects the faults of TargetAddressLookup into a zipcode lookup provider
+type AddressProvider struct {
+	next     address.Provider
+	injector *Injector
+}
+
+// WrapAddressProvider wraps next with the faults of injector
+func WrapAddressProvider(next address.Provider, injector *Injector) *AddressProvider {
+	return &AddressProvider{next: next, injector: injector}
+}
+
+// LookupZipcode implements address.Provider
+func (p *AddressProvider) LookupZipcode(ctx context.Context, zipcode string) (*address.Address, error) {
+	if err := p.injector.Inject(ctx, TargetAddressLookup); err != nil {
+		return nil, err
+	}
+	return p.next.LookupZipcode(ctx, zipcode)
+}
+
+// QuoteStore injects the faults of TargetQuoteStore into every operation of a quote store
+type QuoteStore struct {
+	next     store.QuoteStore
+	injector *Injector
+}
+
+// WrapQuoteStore wraps next with the faults of injector
+func WrapQuoteStore(next store.QuoteStore, injector *Injector) *QuoteStore {
+	return &QuoteStore{next: next, injector: injector}
+}
+
+// Put implements store.QuoteStore
+func (s *QuoteStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := s.injector.Inject(ctx, TargetQuoteStore); err != nil {
+		return err
+	}
+	return s.next.Put(ctx, key, value, ttl)
+}
+
+// PutIfAbsent implements store.QuoteStore
+func (s *QuoteStore) PutIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	if err := s.injector.Inject(ctx, TargetQuoteStore); err != nil {
+		return false, err
+	}
+	return s.next.PutIfAbsent(ctx, key, value, ttl)
+}
+
+// Get implements store.QuoteStore
+func (s *QuoteStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := s.injector.Inject(ctx, TargetQuoteStore); err != nil {
+		return nil, err
+	}
+	return s.next.Get(ctx, key)
+}
+
+// Delete implements store.QuoteStore
+func (s *QuoteStore) Delete(ctx context.Context, key string) error {
+	if err := s.injector.Inject(ctx, TargetQuoteStore); err != nil {
+		return err
+	}
+	return s.next.Delete(ctx, key)
+}
+
+// Ping implements store.Pinger, failing with the faults of the store so that /readyz reports them;
+// stores without a server are always reachable
+func (s *QuoteStore) Ping(ctx context.Context) error {
+	if err := s.injector.Inject(ctx, TargetQuoteStore); err != nil {
+		return err
+	}
+	if pinger, ok := s.next.(store.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/address"
+	"github.com/rbonfanti/shipping-calculator/internal/label"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubProviders counts the calls reaching the wrapped providers
+type stubProviders struct {
+	calls int
+}
+
+func (s *stubProviders) Rate(ctx context.Context, shipment pricing.Shipment) (money.Amount, error) {
+	s.calls++
+	return 1500, nil
+}
+
+func (s *stubProviders) Track(ctx context.Context, shipment *model.Shipment) ([]model.TrackingEvent, error) {
+	s.calls++
+	return []model.TrackingEvent{{Status: model.TrackingStatusInTransit}}, nil
+}
+
+func (s *stubProviders) Generate(ctx context.Context, shipment *model.Shipment, format, idempotencyKey string) (label.Document, error) {
+	s.calls++
+	return label.Document{TrackingCode: "BR123"}, nil
+}
+
+func (s *stubProviders) Submit(ctx context.Context, closed *model.Manifest, shipments []model.Shipment) (string, error) {
+	s.calls++
+	return "M1", nil
+}
+
+func (s *stubProviders) LookupZipcode(ctx context.Context, zipcode string) (*address.Address, error) {
+	s.calls++
+	return &address.Address{Zipcode: zipcode}, nil
+}
+
+func TestWrappers(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		target string
+		call   func(next *stubProviders, injector *Injector) error
+	}{
+		{TargetCarrierRates, func(next *stubProviders, injector *Injector) error {
+			_, err := WrapCarrierRates(next, injector).Rate(ctx, pricing.Shipment{})
+			return err
+		}},
+		{TargetTrackingProvider, func(next *stubProviders, injector *Injector) error {
+			_, err := WrapTrackingProvider(next, injector).Track(ctx, &model.Shipment{})
+			return err
+		}},
+		{TargetLabelProvider, func(next *stubProviders, injector *Injector) error {
+			_, err := WrapLabelProvider(next, injector).Generate(ctx, &model.Shipment{}, model.LabelFormatPDF, "k1")
+			return err
+		}},
+		{TargetManifestProvider, func(next *stubProviders, injector *Injector) error {
+			_, err := WrapManifestSubmitter(next, injector).Submit(ctx, &model.Manifest{}, nil)
+			return err
+		}},
+		{TargetAddressLookup, func(next *stubProviders, injector *Injector) error {
+			_, err := WrapAddressProvider(next, injector).LookupZipcode(ctx, "01310100")
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			// Arrange
+			next := &stubProviders{}
+			injector := NewInjector()
+
+			// Act
+			healthyErr := tt.call(next, injector)
+			assert.NoError(t, injector.Set(tt.target, Fault{ErrorRate: 1}))
+			faultyErr := tt.call(next, injector)
+
+			// Assert
+			assert.NoError(t, healthyErr)
+			assert.ErrorIs(t, faultyErr, ErrInjected)
+			assert.Equal(t, 1, next.calls, "failed calls do not reach the provider")
+		})
+	}
+}
+
+func TestWrapQuoteStore(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	injector := NewInjector()
+	quoteStore := WrapQuoteStore(store.NewMemoryStore(), injector)
+	assert.NoError(t, quoteStore.Put(ctx, "q1", []byte("quote"), time.Minute))
+	assert.NoError(t, injector.Set(TargetQuoteStore, Fault{ErrorRate: 1}))
+
+	// Act
+	_, getErr := quoteStore.Get(ctx, "q1")
+	putErr := quoteStore.Put(ctx, "q2", []byte("quote"), time.Minute)
+	deleteErr := quoteStore.Delete(ctx, "q1")
+	_, claimErr := quoteStore.PutIfAbsent(ctx, "q3", []byte("quote"), time.Minute)
+	pingErr := quoteStore.Ping(ctx)
+
+	// Assert
+	assert.ErrorIs(t, getErr, ErrInjected)
+	assert.ErrorIs(t, claimErr, ErrInjected)
+	assert.ErrorIs(t, putErr, ErrInjected)
+	assert.ErrorIs(t, deleteErr, ErrInjected)
+	assert.ErrorIs(t, pingErr, ErrInjected)
+
+	injector.Clear(TargetQuoteStore)
+	value, err := quoteStore.Get(ctx, "q1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("quote"), value)
+	assert.NoError(t, quoteStore.Ping(ctx))
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestString(t *testing.T) {
+	// Arrange
+	t.Setenv("CONFIG_TEST_STRING", " value ")
+
+	// Act
+	result := String("CONFIG_TEST_STRING", "default")
+
+	// Assert
+	assert.Equal(t, "value", result)
+}
+
+func TestString_Default(t *testing.T) {
+	// Arrange
+	t.Setenv("CONFIG_TEST_STRING", "")
+
+	// Act
+	result := String("CONFIG_TEST_STRING", "default")
+
+	// Assert
+	assert.Equal(t, "default", result)
+}
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  int
+		expectErr bool
+	}{
+		{name: "valid value", value: "42", expected: 42},
+		{name: "unset uses default", value: "", expected: 7},
+		{name: "invalid value", value: "abc", expected: 7, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("CONFIG_TEST_INT", tt.value)
+
+			// Act
+			result, err := Int("CONFIG_TEST_INT", 7)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "CONFIG_TEST_INT")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFloat(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  float64
+		expectErr bool
+	}{
+		{name: "valid value", value: "0.25", expected: 0.25},
+		{name: "unset uses default", value: "", expected: 1.5},
+		{name: "invalid value", value: "abc", expected: 1.5, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("CONFIG_TEST_FLOAT", tt.value)
+
+			// Act
+			result, err := Float("CONFIG_TEST_FLOAT", 1.5)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.expectErr, err != nil)
+		})
+	}
+}
+
+func TestBool(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  bool
+		expectErr bool
+	}{
+		{name: "true value", value: "true", expected: true},
+		{name: "numeric false", value: "0", expected: false},
+		{name: "unset uses default", value: "", expected: true},
+		{name: "invalid value", value: "maybe", expected: true, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("CONFIG_TEST_BOOL", tt.value)
+
+			// Act
+			result, err := Bool("CONFIG_TEST_BOOL", true)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.expectErr, err != nil)
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  time.Duration
+		expectErr bool
+	}{
+		{name: "valid value", value: "250ms", expected: 250 * time.Millisecond},
+		{name: "unset uses default", value: "", expected: time.Second},
+		{name: "invalid value", value: "10", expected: time.Second, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("CONFIG_TEST_DURATION", tt.value)
+
+			// Act
+			result, err := Duration("CONFIG_TEST_DURATION", time.Second)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.expectErr, err != nil)
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	// Arrange
+	t.Setenv("CONFIG_TEST_LIST", " a, b ,,c ")
+
+	// Act
+	result := List("CONFIG_TEST_LIST", []string{"default"})
+
+	// Assert
+	assert.Equal(t, []string{"a", "b", "c"}, result)
+}
+
+func TestList_Default(t *testing.T) {
+	// Arrange
+	t.Setenv("CONFIG_TEST_LIST", "")
+
+	// Act
+	result := List("CONFIG_TEST_LIST", []string{"default"})
+
+	// Assert
+	assert.Equal(t, []string{"default"}, result)
+}
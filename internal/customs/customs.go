@@ -0,0 +1,188 @@
+// Package customs estimates the import duties and taxes of cross-border shipments from the tariff
+// table of the destination country.
+package customs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+)
+
+var (
+	// ErrInvalidDeclaration is returned when the HS code or the declared value of a shipment is invalid
+	ErrInvalidDeclaration = errors.New("invalid customs declaration")
+	// ErrNoTariff is returned when there is no tariff table for the destination country or its currency
+	ErrNoTariff = errors.New("no tariff table")
+)
+
+// Tariff is the tariff table of a destination country. Amounts are in minor units of Currency
+type Tariff struct {
+	// Currency is the ISO 4217 currency of the declared values the table applies to
+	Currency string `json:"currency"`
+	// DeMinimis is the declared value up to which no import duty is charged
+	DeMinimis money.Amount `json:"de_minimis,omitempty"`
+	// DutyRate is the import duty rate of goods without a rate in DutyRates
+	DutyRate float64 `json:"duty_rate"`
+	// DutyRates maps HS code prefixes (chapter "61", heading "6109" or subheading "610910") to
+	// their import duty rate; the longest matching prefix wins
+	DutyRates map[string]float64 `json:"duty_rates,omitempty"`
+	// TaxRate is the import tax (VAT, GST) rate, charged on the declared value, the freight and the duty
+	TaxRate float64 `json:"tax_rate,omitempty"`
+}
+
+// Config maps ISO 3166-1 alpha-2 destination countries to their tariff table
+type Config struct {
+	Countries map[string]Tariff `json:"countries"`
+}
+
+// DefaultConfig returns simplified tariff tables of the international destinations of the default
+// pricing configuration: the United States (duty-free up to 800 USD, 5% duty) and Germany (duty-free
+// up to 150 EUR, 4% duty, 19% VAT), with lower rates for books and phones
+func DefaultConfig() Config {
+	return Config{
+		Countries: map[string]Tariff{
+			"US": {
+				Currency:  "USD",
+				DeMinimis: money.FromMinor(80000),
+				DutyRate:  0.05,
+				DutyRates: map[string]float64{"4901": 0, "8517": 0, "6109": 0.165},
+			},
+			"DE": {
+				Currency:  "EUR",
+				DeMinimis: money.FromMinor(15000),
+				DutyRate:  0.04,
+				DutyRates: map[string]float64{"4901": 0, "8517": 0, "6109": 0.12},
+				TaxRate:   0.19,
+			},
+		},
+	}
+}
+
+// Validate checks that every tariff table has a currency, valid HS code prefixes and rates that
+// are not negative
+func (c Config) Validate() error {
+	for country, tariff := range c.Countries {
+		if strings.TrimSpace(tariff.Currency) == "" {
+			return fmt.Errorf("country %q: currency is required", country)
+		}
+		if tariff.DeMinimis < 0 || tariff.DutyRate < 0 || tariff.TaxRate < 0 {
+			return fmt.Errorf("country %q: de_minimis, duty_rate and tax_rate must not be negative", country)
+		}
+		for prefix, rate := range tariff.DutyRates {
+			if len(prefix) < 2 || !digits(prefix) {
+				return fmt.Errorf("country %q: HS code prefix %q must have at least 2 digits", country, prefix)
+			}
+			if rate < 0 {
+				return fmt.Errorf("country %q: duty rate of %q must not be negative", country, prefix)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads and validates the tariff tables from a JSON file
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read customs tariff config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse customs tariff config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid customs tariff config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Estimate is the estimated import duty and tax of a shipment, in minor units of the quote currency
+type Estimate struct {
+	HSCode   string
+	DutyRate float64
+	Duty     money.Amount
+	TaxRate  float64
+	Tax      money.Amount
+}
+
+// Total returns the duty plus the tax
+func (e Estimate) Total() money.Amount {
+	return e.Duty + e.Tax
+}
+
+// Estimator estimates duties and taxes with the tariff tables of its configuration
+type Estimator struct {
+	countries map[string]Tariff
+}
+
+// NewEstimator creates an estimator for the tariff tables of cfg
+func NewEstimator(cfg Config) *Estimator {
+	countries := make(map[string]Tariff, len(cfg.Countries))
+	for country, tariff := range cfg.Countries {
+		tariff.Currency = strings.ToUpper(strings.TrimSpace(tariff.Currency))
+		countries[strings.ToUpper(strings.TrimSpace(country))] = tariff
+	}
+	return &Estimator{countries: countries}
+}
+
+// Estimate estimates the import duty and tax of goods classified under hsCode and declared at
+// declaredValue, shipped to country for freight, both in minor units of currency. The duty is
+// charged on the declared value plus the freight (CIF) above the de minimis of the country, and
+// the tax on the declared value, the freight and the duty
+func (e *Estimator) Estimate(country, currency, hsCode string, declaredValue, freight money.Amount) (Estimate, error) {
+	hsCode = NormalizeHSCode(hsCode)
+	if len(hsCode) < 6 || len(hsCode) > 10 || !digits(hsCode) {
+		return Estimate{}, fmt.Errorf("%w: hs_code must have 6 to 10 digits", ErrInvalidDeclaration)
+	}
+	if declaredValue <= 0 {
+		return Estimate{}, fmt.Errorf("%w: declared_value must be positive", ErrInvalidDeclaration)
+	}
+
+	country = strings.ToUpper(strings.TrimSpace(country))
+	tariff, ok := e.countries[country]
+	if !ok {
+		return Estimate{}, fmt.Errorf("%w for destination country %q", ErrNoTariff, country)
+	}
+	if currency = strings.ToUpper(strings.TrimSpace(currency)); currency != tariff.Currency {
+		return Estimate{}, fmt.Errorf("%w for %q in %s: quote in %s", ErrNoTariff, country, currency, tariff.Currency)
+	}
+
+	estimate := Estimate{HSCode: hsCode, TaxRate: tariff.TaxRate}
+	customsValue := declaredValue + freight
+	if declaredValue > tariff.DeMinimis {
+		estimate.DutyRate = tariff.dutyRate(hsCode)
+		estimate.Duty = customsValue.MulRate(estimate.DutyRate)
+	}
+	estimate.Tax = (customsValue + estimate.Duty).MulRate(tariff.TaxRate)
+	return estimate, nil
+}
+
+// dutyRate returns the duty rate of the longest prefix of the HS code in DutyRates, or DutyRate
+func (t Tariff) dutyRate(hsCode string) float64 {
+	rate, longest := t.DutyRate, 0
+	for prefix, prefixRate := range t.DutyRates {
+		if len(prefix) > longest && strings.HasPrefix(hsCode, prefix) {
+			rate, longest = prefixRate, len(prefix)
+		}
+	}
+	return rate
+}
+
+// NormalizeHSCode removes the dots and spaces of an HS code, e.g. "6109.10.00" becomes "61091000"
+func NormalizeHSCode(hsCode string) string {
+	return strings.NewReplacer(".", "", " ", "").Replace(hsCode)
+}
+
+// digits reports whether s contains only ASCII digits
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
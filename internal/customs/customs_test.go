@@ -0,0 +1,162 @@
+package customs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig() Config {
+	return Config{
+		Countries: map[string]Tariff{
+			"de": {
+				Currency:  "eur",
+				DeMinimis: money.FromMinor(15000),
+				DutyRate:  0.04,
+				DutyRates: map[string]float64{"61": 0.10, "6109": 0.12, "4901": 0},
+				TaxRate:   0.19,
+			},
+		},
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		name         string
+		hsCode       string
+		declared     float64
+		wantHSCode   string
+		wantDutyRate float64
+		wantDuty     float64
+		wantTax      float64
+	}{
+		{"default duty rate", "950300", 20000, "950300", 0.04, 840, 4149.6},
+		{"heading rate", "6109.10.00", 20000, "61091000", 0.12, 2520, 4468.8},
+		{"chapter rate", "610342", 20000, "610342", 0.10, 2100, 4389},
+		{"duty-free heading", "490199", 20000, "490199", 0, 0, 3990},
+		{"below de minimis", "610910", 15000, "610910", 0, 0, 3040},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			estimator := NewEstimator(testConfig())
+
+			// Act
+			estimate, err := estimator.Estimate("DE", "EUR", tt.hsCode, money.FromMinor(tt.declared), money.FromMinor(1000))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHSCode, estimate.HSCode)
+			assert.Equal(t, tt.wantDutyRate, estimate.DutyRate)
+			assert.Equal(t, money.FromMinor(tt.wantDuty), estimate.Duty)
+			assert.Equal(t, 0.19, estimate.TaxRate)
+			assert.Equal(t, money.FromMinor(tt.wantTax), estimate.Tax)
+			assert.Equal(t, estimate.Duty+estimate.Tax, estimate.Total())
+		})
+	}
+}
+
+func TestEstimate_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		country  string
+		currency string
+		hsCode   string
+		declared float64
+		wantErr  error
+		wantMsg  string
+	}{
+		{"missing hs code", "DE", "EUR", "", 1000, ErrInvalidDeclaration, "hs_code must have 6 to 10 digits"},
+		{"short hs code", "DE", "EUR", "6109", 1000, ErrInvalidDeclaration, "hs_code must have 6 to 10 digits"},
+		{"non-numeric hs code", "DE", "EUR", "6109AB", 1000, ErrInvalidDeclaration, "hs_code must have 6 to 10 digits"},
+		{"missing declared value", "DE", "EUR", "610910", 0, ErrInvalidDeclaration, "declared_value must be positive"},
+		{"unknown country", "JP", "EUR", "610910", 1000, ErrNoTariff, `no tariff table for destination country "JP"`},
+		{"other currency", "DE", "USD", "610910", 1000, ErrNoTariff, `no tariff table for "DE" in USD: quote in EUR`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			estimator := NewEstimator(testConfig())
+
+			// Act
+			_, err := estimator.Estimate(tt.country, tt.currency, tt.hsCode, money.FromMinor(tt.declared), 0)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.ErrorContains(t, err, tt.wantMsg)
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tariff  Tariff
+		wantErr string
+	}{
+		{"valid", Tariff{Currency: "EUR", DutyRate: 0.04, DutyRates: map[string]float64{"61": 0.1}}, ""},
+		{"missing currency", Tariff{DutyRate: 0.04}, `country "DE": currency is required`},
+		{"negative rate", Tariff{Currency: "EUR", TaxRate: -0.19}, "must not be negative"},
+		{"short prefix", Tariff{Currency: "EUR", DutyRates: map[string]float64{"6": 0.1}}, `HS code prefix "6" must have at least 2 digits`},
+		{"invalid prefix", Tariff{Currency: "EUR", DutyRates: map[string]float64{"61.09": 0.1}}, `HS code prefix "61.09"`},
+		{"negative prefix rate", Tariff{Currency: "EUR", DutyRates: map[string]float64{"61": -0.1}}, `duty rate of "61" must not be negative`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := Config{Countries: map[string]Tariff{"DE": tt.tariff}}
+
+			// Act
+			err := cfg.Validate()
+
+			// Assert
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultConfig_IsValid(t *testing.T) {
+	// Act
+	err := DefaultConfig().Validate()
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestLoadConfig(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "tariffs.json")
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(valid, []byte(`{"countries": {
+		"GB": {"currency": "GBP", "de_minimis": 13500, "duty_rate": 0.02, "duty_rates": {"6109": 0.12}, "tax_rate": 0.2}
+	}}`), 0o600))
+	assert.NoError(t, os.WriteFile(invalid, []byte(`{"countries": {"GB": {"duty_rate": 0.02}}}`), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(valid)
+	_, invalidErr := LoadConfig(invalid)
+	_, missingErr := LoadConfig(filepath.Join(dir, "missing.json"))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Tariff{
+		Currency:  "GBP",
+		DeMinimis: money.FromMinor(13500),
+		DutyRate:  0.02,
+		DutyRates: map[string]float64{"6109": 0.12},
+		TaxRate:   0.2,
+	}, cfg.Countries["GB"])
+	assert.ErrorContains(t, invalidErr, "invalid customs tariff config")
+	assert.ErrorContains(t, missingErr, "failed to read customs tariff config")
+}
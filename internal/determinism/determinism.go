@@ -0,0 +1,133 @@
+// Package determinism abstracts the time and the identifiers the quotes depend on, so that
+// integration tests and replay tooling can pin them and produce identical outputs. In production
+// the system clock and random UUIDs are used.
+package determinism
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+)
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// IDGenerator generates unique identifiers, e.g. of quotes and requests
+type IDGenerator interface {
+	NewID() string
+}
+
+// SystemClock is the clock of the host
+type SystemClock struct{}
+
+// Now implements Clock
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always tells the same time
+type FixedClock struct {
+	Time time.Time
+}
+
+// Now implements Clock
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
+
+// UUIDGenerator generates random UUIDs
+type UUIDGenerator struct{}
+
+// NewID implements IDGenerator
+func (UUIDGenerator) NewID() string {
+	return uuid.NewString()
+}
+
+// SeededIDGenerator generates the same sequence of version 4 UUIDs for the same seed. It is safe
+// for concurrent use, but concurrent callers may receive the IDs in any order
+type SeededIDGenerator struct {
+	mu     sync.Mutex
+	source *rand.Rand
+}
+
+// NewSeededIDGenerator creates a generator of the UUID sequence of seed
+func NewSeededIDGenerator(seed int64) *SeededIDGenerator {
+	return &SeededIDGenerator{source: rand.New(rand.NewSource(seed))}
+}
+
+// NewID implements IDGenerator
+func (g *SeededIDGenerator) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	// Reading from a math/rand source never fails
+	id, _ := uuid.NewRandomFromReader(g.source)
+	return id.String()
+}
+
+// Config pins the clock and the identifiers of the quotes; the zero value uses the system clock
+// and random UUIDs
+type Config struct {
+	// Now, when set, is the time told by the clock
+	Now time.Time
+	// Seed, when set, seeds the identifiers
+	Seed *int64
+}
+
+// ConfigFromEnv reads DETERMINISTIC_NOW (an RFC 3339 timestamp) and DETERMINISTIC_SEED (an
+// integer), both unset by default. Since a pinned clock keeps quotes from expiring and seeded IDs
+// make quote IDs predictable, either one is refused unless DETERMINISTIC_MODE_ALLOWED is true,
+// which only test and replay environments set
+func ConfigFromEnv() (Config, error) {
+	var cfg Config
+	if value := config.String("DETERMINISTIC_NOW", ""); value != "" {
+		now, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return Config{}, fmt.Errorf("DETERMINISTIC_NOW must be an RFC 3339 timestamp, got %q", value)
+		}
+		cfg.Now = now
+	}
+	if value := config.String("DETERMINISTIC_SEED", ""); value != "" {
+		seed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("DETERMINISTIC_SEED must be an integer, got %q", value)
+		}
+		cfg.Seed = &seed
+	}
+	allowed, err := config.Bool("DETERMINISTIC_MODE_ALLOWED", false)
+	if err != nil {
+		return Config{}, err
+	}
+	if cfg.Enabled() && !allowed {
+		return Config{}, errors.New("DETERMINISTIC_NOW and DETERMINISTIC_SEED require DETERMINISTIC_MODE_ALLOWED=true, which must not be set in production")
+	}
+	return cfg, nil
+}
+
+// Enabled reports whether the clock or the identifiers are pinned
+func (c Config) Enabled() bool {
+	return !c.Now.IsZero() || c.Seed != nil
+}
+
+// Clock returns a FixedClock at Now when set, and the SystemClock otherwise
+func (c Config) Clock() Clock {
+	if c.Now.IsZero() {
+		return SystemClock{}
+	}
+	return FixedClock{Time: c.Now}
+}
+
+// IDGenerator returns a new SeededIDGenerator when Seed is set, and a UUIDGenerator otherwise
+func (c Config) IDGenerator() IDGenerator {
+	if c.Seed == nil {
+		return UUIDGenerator{}
+	}
+	return NewSeededIDGenerator(*c.Seed)
+}
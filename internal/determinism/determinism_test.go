@@ -0,0 +1,104 @@
+package determinism
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeededIDGenerator(t *testing.T) {
+	// Arrange
+	first, second := NewSeededIDGenerator(42), NewSeededIDGenerator(42)
+
+	// Act
+	ids := []string{first.NewID(), first.NewID()}
+
+	// Assert
+	assert.Equal(t, ids, []string{second.NewID(), second.NewID()})
+	assert.NotEqual(t, ids[0], ids[1])
+	assert.NotEqual(t, ids[0], NewSeededIDGenerator(43).NewID())
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	// Arrange
+	t.Setenv("DETERMINISTIC_NOW", "2025-03-05T10:00:00-03:00")
+	t.Setenv("DETERMINISTIC_SEED", "42")
+	t.Setenv("DETERMINISTIC_MODE_ALLOWED", "true")
+
+	// Act
+	cfg, err := ConfigFromEnv()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, time.Date(2025, 3, 5, 13, 0, 0, 0, time.UTC), cfg.Clock().Now().UTC())
+	assert.Equal(t, NewSeededIDGenerator(42).NewID(), cfg.IDGenerator().NewID())
+}
+
+func TestConfigFromEnv_Disabled(t *testing.T) {
+	// Arrange
+	t.Setenv("DETERMINISTIC_NOW", "")
+	t.Setenv("DETERMINISTIC_SEED", "")
+
+	// Act
+	cfg, err := ConfigFromEnv()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, cfg.Enabled())
+	assert.Equal(t, SystemClock{}, cfg.Clock())
+	assert.Equal(t, UUIDGenerator{}, cfg.IDGenerator())
+}
+
+func TestConfigFromEnv_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		value    string
+		contains string
+	}{
+		{"invalid now", "DETERMINISTIC_NOW", "yesterday", "DETERMINISTIC_NOW must be an RFC 3339 timestamp"},
+		{"invalid seed", "DETERMINISTIC_SEED", "abc", "DETERMINISTIC_SEED must be an integer"},
+		{"invalid switch", "DETERMINISTIC_MODE_ALLOWED", "maybe", "DETERMINISTIC_MODE_ALLOWED must be a boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("DETERMINISTIC_MODE_ALLOWED", "true")
+			t.Setenv(tt.key, tt.value)
+
+			// Act
+			_, err := ConfigFromEnv()
+
+			// Assert
+			assert.ErrorContains(t, err, tt.contains)
+		})
+	}
+}
+
+func TestConfigFromEnv_NotAllowed(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"pinned clock", "DETERMINISTIC_NOW", "2025-03-05T10:00:00-03:00"},
+		{"seeded identifiers", "DETERMINISTIC_SEED", "42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("DETERMINISTIC_MODE_ALLOWED", "")
+			t.Setenv(tt.key, tt.value)
+
+			// Act
+			_, err := ConfigFromEnv()
+
+			// Assert
+			assert.ErrorContains(t, err, "require DETERMINISTIC_MODE_ALLOWED=true")
+		})
+	}
+}
@@ -0,0 +1,287 @@
+// Package eta estimates delivery times by combining warehouse handling time with carrier transit time.
+package eta
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+)
+
+// Warehouse describes the handling time of an origin warehouse
+type Warehouse struct {
+	ID string `json:"id"`
+	// ZipcodePrefixes are the origin CEP prefixes shipped from this warehouse (longest prefix wins)
+	ZipcodePrefixes []string `json:"zipcode_prefixes"`
+	// HandlingDays is the processing time before the package is handed to the carrier
+	HandlingDays int `json:"handling_days"`
+	// DayOfWeekHandlingDays overrides HandlingDays for orders placed on a given weekday
+	// (keys "monday" to "sunday"), modelling reduced capacity such as weekend shifts
+	DayOfWeekHandlingDays map[string]int `json:"day_of_week_handling_days,omitempty"`
+}
+
+// Config holds the delivery estimation configuration
+type Config struct {
+	// DefaultHandlingDays applies to origins not served by any configured warehouse
+	DefaultHandlingDays int         `json:"default_handling_days"`
+	Warehouses          []Warehouse `json:"warehouses"`
+}
+
+// Validate checks the configuration for negative values and unknown weekdays
+func (c Config) Validate() error {
+	if c.DefaultHandlingDays < 0 {
+		return fmt.Errorf("default_handling_days must not be negative")
+	}
+	for _, w := range c.Warehouses {
+		if w.HandlingDays < 0 {
+			return fmt.Errorf("warehouse %q: handling_days must not be negative", w.ID)
+		}
+		if len(w.ZipcodePrefixes) == 0 {
+			return fmt.Errorf("warehouse %q: at least one zipcode prefix is required", w.ID)
+		}
+		for day, days := range w.DayOfWeekHandlingDays {
+			if _, ok := weekdays[strings.ToLower(day)]; !ok {
+				return fmt.Errorf("warehouse %q: unknown weekday %q", w.ID, day)
+			}
+			if days < 0 {
+				return fmt.Errorf("warehouse %q: handling days for %s must not be negative", w.ID, day)
+			}
+		}
+	}
+	return nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// LoadConfig reads and validates the delivery estimation configuration from a JSON file
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read eta config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse eta config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid eta config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Calendar reports the days without pickups or deliveries in a country or in the state of a
+// zipcode, e.g. a *holiday.Calendar
+type Calendar interface {
+	IsHoliday(country, zipcode string, day time.Time) bool
+}
+
+// Timezones resolves the time zone of a zipcode in a country
+type Timezones interface {
+	Location(country, zipcode string) *time.Location
+}
+
+// originCountry is the country of the origins: packages leave from Brazilian warehouses and
+// customers
+const originCountry = "BR"
+
+// maxHolidayDays bounds how many holidays and closed weekdays are skipped, guarding against a
+// misconfigured calendar that marks every day as a holiday
+const maxHolidayDays = 60
+
+// Estimator computes delivery days for a route
+type Estimator struct {
+	cfg      Config
+	calendar Calendar
+	transit  *TransitMatrix
+	zones    Timezones
+	now      func() time.Time
+}
+
+// NewEstimator creates an estimator with the given configuration
+func NewEstimator(cfg Config) *Estimator {
+	return &Estimator{cfg: cfg, now: time.Now}
+}
+
+// NewEstimatorWithCalendar creates an estimator that skips the holidays of the calendar
+func NewEstimatorWithCalendar(cfg Config, calendar Calendar) *Estimator {
+	return &Estimator{cfg: cfg, calendar: calendar, now: time.Now}
+}
+
+// NewEstimatorWithClock creates an estimator that skips the holidays of the calendar and places
+// the orders at the time told by clock, e.g. a determinism.FixedClock for reproducible estimates
+func NewEstimatorWithClock(cfg Config, calendar Calendar, clock determinism.Clock) *Estimator {
+	return &Estimator{cfg: cfg, calendar: calendar, now: clock.Now}
+}
+
+// WithTransitMatrix makes the estimator take the transit days of domestic routes from matrix
+func (e *Estimator) WithTransitMatrix(matrix *TransitMatrix) *Estimator {
+	e.transit = matrix
+	return e
+}
+
+// WithTimezones makes the estimator count the days in the local time of the origin and the
+// destination resolved by zones, instead of the time zone of its clock
+func (e *Estimator) WithTimezones(zones Timezones) *Estimator {
+	e.zones = zones
+	return e
+}
+
+// ServiceTransitDays returns the transit days of a service level between the states of two
+// Brazilian zipcodes from the transit matrix, or defaultDays when there is no matrix, the route
+// is international or the matrix has no entry for it
+func (e *Estimator) ServiceTransitDays(originZipcode, destinationZipcode, country, service string, defaultDays int) int {
+	if e.transit == nil || !strings.EqualFold(country, "BR") {
+		return defaultDays
+	}
+	days, ok := e.transit.Days(zipcode.State(originZipcode), zipcode.State(destinationZipcode), service)
+	if !ok {
+		return defaultDays
+	}
+	return days
+}
+
+// HandlingDays returns the handling time of the warehouse serving the origin zipcode
+// for an order placed now, on the local weekday of the origin
+func (e *Estimator) HandlingDays(originZipcode string) int {
+	warehouse := e.warehouseFor(originZipcode)
+	if warehouse == nil {
+		return e.cfg.DefaultHandlingDays
+	}
+
+	weekday := strings.ToLower(e.localNow(originCountry, originZipcode).Weekday().String())
+	for day, days := range warehouse.DayOfWeekHandlingDays {
+		if strings.ToLower(day) == weekday {
+			return days
+		}
+	}
+	return warehouse.HandlingDays
+}
+
+// EstimateDays adds the origin handling time to the carrier transit days
+func (e *Estimator) EstimateDays(originZipcode string, transitDays int) int {
+	return e.HandlingDays(originZipcode) + transitDays
+}
+
+// DeliveryDays returns the calendar days until delivery for an order placed now. Handling days
+// skip the holidays at the origin and transit days skip the holidays at the destination and the
+// closed weekdays, on which the carrier does not deliver
+func (e *Estimator) DeliveryDays(originZipcode, destinationZipcode, country string, transitDays int, closed ...time.Weekday) int {
+	return e.deliveryDays(e.localNow(originCountry, originZipcode), originZipcode, destinationZipcode, country, e.HandlingDays(originZipcode), transitDays, closed)
+}
+
+// TransitDays returns the calendar days until delivery of a package collected now from a
+// customer instead of a warehouse, e.g. a return: there is no handling time and transit days
+// skip the holidays at the destination and the closed weekdays
+func (e *Estimator) TransitDays(originZipcode, destinationZipcode, country string, transitDays int, closed ...time.Weekday) int {
+	return e.deliveryDays(e.localNow(originCountry, originZipcode), originZipcode, destinationZipcode, country, 0, transitDays, closed)
+}
+
+// DeliveryDaysFrom returns the calendar days from now until delivery of a package collected on
+// pickupDate: the days until the pickup plus the transit days after it, skipping the holidays at
+// the destination and the closed weekdays. The package is ready at the pickup, so there is no
+// handling time
+func (e *Estimator) DeliveryDaysFrom(pickupDate time.Time, originZipcode, destinationZipcode, country string, transitDays int, closed ...time.Weekday) int {
+	now := e.now().In(pickupDate.Location())
+	if e.zones != nil {
+		now = e.localNow(originCountry, originZipcode)
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, pickupDate.Location())
+	waiting := 0
+	if pickupDate.After(today) {
+		waiting = int(math.Round(pickupDate.Sub(today).Hours() / 24))
+	}
+	return waiting + e.deliveryDays(pickupDate, originZipcode, destinationZipcode, country, 0, transitDays, closed)
+}
+
+// DeliveryDate returns the day of a delivery days calendar days after today at the origin, as
+// the midnight of that day in the time zone of the destination. days are the ones returned by
+// DeliveryDays, TransitDays or DeliveryDaysFrom, which are counted from the same day
+func (e *Estimator) DeliveryDate(originZipcode, destinationZipcode, country string, days int) time.Time {
+	today := e.localNow(originCountry, originZipcode)
+	location := today.Location()
+	if e.zones != nil {
+		location = e.zones.Location(country, destinationZipcode)
+	}
+	return time.Date(today.Year(), today.Month(), today.Day()+days, 0, 0, 0, 0, location)
+}
+
+// LocalDate returns today's date ("YYYY-MM-DD") at the origin zipcode, the day the handling and
+// transit days of an order placed now are counted from
+func (e *Estimator) LocalDate(originZipcode string) string {
+	return e.localNow(originCountry, originZipcode).Format(time.DateOnly)
+}
+
+// localNow returns the current time in the time zone of the zipcode, or in the time zone of the
+// clock without time zones
+func (e *Estimator) localNow(country, zipcode string) time.Time {
+	if e.zones == nil {
+		return e.now()
+	}
+	return e.now().In(e.zones.Location(country, zipcode))
+}
+
+// deliveryDays returns the calendar days from start until the handling and transit working days
+// have elapsed; the closed weekdays only apply to the transit days
+func (e *Estimator) deliveryDays(start time.Time, originZipcode, destinationZipcode, country string, handlingDays, transitDays int, closed []time.Weekday) int {
+	if e.calendar == nil && len(closed) == 0 {
+		return handlingDays + transitDays
+	}
+
+	day := start
+	elapsed := 0
+	skipped := 0
+	advance := func(zipcode string, workingDays int, closed []time.Weekday) {
+		for workingDays > 0 {
+			day = day.AddDate(0, 0, 1)
+			elapsed++
+			if skipped < maxHolidayDays && e.isClosed(country, zipcode, day, closed) {
+				skipped++
+				continue
+			}
+			workingDays--
+		}
+	}
+	advance(originZipcode, handlingDays, nil)
+	advance(destinationZipcode, transitDays, closed)
+	return elapsed
+}
+
+// isClosed reports whether day is a holiday in the country or the state of the zipcode, or one
+// of the closed weekdays
+func (e *Estimator) isClosed(country, zipcode string, day time.Time, closed []time.Weekday) bool {
+	if slices.Contains(closed, day.Weekday()) {
+		return true
+	}
+	return e.calendar != nil && e.calendar.IsHoliday(country, zipcode, day)
+}
+
+// warehouseFor returns the warehouse with the longest prefix matching the zipcode, or nil
+func (e *Estimator) warehouseFor(value string) *Warehouse {
+	normalized := zipcode.Normalize(value)
+
+	var best *Warehouse
+	bestLength := -1
+	for i := range e.cfg.Warehouses {
+		for _, prefix := range e.cfg.Warehouses[i].ZipcodePrefixes {
+			if strings.HasPrefix(normalized, prefix) && len(prefix) > bestLength {
+				best = &e.cfg.Warehouses[i]
+				bestLength = len(prefix)
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,381 @@
+package eta
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEstimator(cfg Config, now time.Time) *Estimator {
+	e := NewEstimator(cfg)
+	e.now = func() time.Time { return now }
+	return e
+}
+
+var (
+	// 2025-01-06 is a Monday and 2025-01-11 a Saturday
+	monday   = time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	saturday = time.Date(2025, 1, 11, 10, 0, 0, 0, time.UTC)
+)
+
+func testConfig() Config {
+	return Config{
+		DefaultHandlingDays: 1,
+		Warehouses: []Warehouse{
+			{ID: "sp", ZipcodePrefixes: []string{"0"}, HandlingDays: 2},
+			{
+				ID:                    "sp-capital",
+				ZipcodePrefixes:       []string{"013"},
+				HandlingDays:          0,
+				DayOfWeekHandlingDays: map[string]int{"Saturday": 2},
+			},
+		},
+	}
+}
+
+func TestEstimator_HandlingDays_DefaultForUnknownOrigin(t *testing.T) {
+	// Arrange
+	e := newTestEstimator(testConfig(), monday)
+
+	// Act
+	result := e.HandlingDays("90000-000")
+
+	// Assert
+	assert.Equal(t, 1, result)
+}
+
+func TestEstimator_HandlingDays_LongestPrefixWins(t *testing.T) {
+	// Arrange
+	e := newTestEstimator(testConfig(), monday)
+
+	// Act
+	capital := e.HandlingDays("01310-100")
+	state := e.HandlingDays("04547-130")
+
+	// Assert
+	assert.Equal(t, 0, capital)
+	assert.Equal(t, 2, state)
+}
+
+func TestEstimator_HandlingDays_DayOfWeekOverride(t *testing.T) {
+	// Arrange
+	e := newTestEstimator(testConfig(), saturday)
+
+	// Act
+	result := e.HandlingDays("01310 100")
+
+	// Assert
+	assert.Equal(t, 2, result)
+}
+
+func TestEstimator_EstimateDays(t *testing.T) {
+	// Arrange
+	e := newTestEstimator(testConfig(), monday)
+
+	// Act
+	result := e.EstimateDays("04547130", 2)
+
+	// Assert
+	assert.Equal(t, 4, result)
+}
+
+func TestEstimator_EmptyConfig(t *testing.T) {
+	// Arrange
+	e := NewEstimator(Config{})
+
+	// Act
+	result := e.EstimateDays("01310100", 2)
+
+	// Assert
+	assert.Equal(t, 2, result)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "negative default", cfg: Config{DefaultHandlingDays: -1}},
+		{name: "negative warehouse days", cfg: Config{Warehouses: []Warehouse{{ID: "w", ZipcodePrefixes: []string{"0"}, HandlingDays: -1}}}},
+		{name: "missing prefixes", cfg: Config{Warehouses: []Warehouse{{ID: "w"}}}},
+		{name: "unknown weekday", cfg: Config{Warehouses: []Warehouse{{ID: "w", ZipcodePrefixes: []string{"0"}, DayOfWeekHandlingDays: map[string]int{"funday": 1}}}}},
+		{name: "negative weekday days", cfg: Config{Warehouses: []Warehouse{{ID: "w", ZipcodePrefixes: []string{"0"}, DayOfWeekHandlingDays: map[string]int{"monday": -1}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := tt.cfg.Validate()
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "eta.json")
+	content := `{"default_handling_days": 1, "warehouses": [{"id": "sp", "zipcode_prefixes": ["0"], "handling_days": 2, "day_of_week_handling_days": {"sunday": 3}}]}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cfg.DefaultHandlingDays)
+	assert.Len(t, cfg.Warehouses, 1)
+	assert.Equal(t, 3, cfg.Warehouses[0].DayOfWeekHandlingDays["sunday"])
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	dir := t.TempDir()
+	invalidJSON := filepath.Join(dir, "invalid.json")
+	invalidConfig := filepath.Join(dir, "negative.json")
+	assert.NoError(t, os.WriteFile(invalidJSON, []byte("{"), 0o600))
+	assert.NoError(t, os.WriteFile(invalidConfig, []byte(`{"default_handling_days": -2}`), 0o600))
+
+	for _, path := range []string{filepath.Join(dir, "missing.json"), invalidJSON, invalidConfig} {
+		// Act
+		_, err := LoadConfig(path)
+
+		// Assert
+		assert.Error(t, err, path)
+	}
+}
+
+// holidays is a Calendar backed by a set of "zipcode prefix|date" entries
+type holidays map[string]bool
+
+func (h holidays) IsHoliday(country, zipcode string, day time.Time) bool {
+	return h[zipcode[:1]+"|"+day.Format("2006-01-02")]
+}
+
+func TestEstimator_DeliveryDays_WithoutCalendar(t *testing.T) {
+	// Arrange
+	e := newTestEstimator(testConfig(), monday)
+
+	// Act
+	result := e.DeliveryDays("04547-130", "20040-002", "BR", 3)
+
+	// Assert
+	assert.Equal(t, 5, result)
+}
+
+func TestEstimator_DeliveryDays_SkipsHolidays(t *testing.T) {
+	tests := []struct {
+		name     string
+		calendar holidays
+		want     int
+	}{
+		{"no holidays", holidays{}, 5},
+		{"origin holiday during handling", holidays{"0|2025-01-07": true}, 6},
+		{"destination holiday during transit", holidays{"2|2025-01-10": true}, 6},
+		{"origin holiday during transit is ignored", holidays{"0|2025-01-10": true}, 5},
+		{"destination holiday during handling is ignored", holidays{"2|2025-01-07": true}, 5},
+		{"consecutive holidays", holidays{"2|2025-01-09": true, "2|2025-01-10": true}, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			e := NewEstimatorWithCalendar(testConfig(), tt.calendar)
+			e.now = func() time.Time { return monday }
+
+			// Act
+			result := e.DeliveryDays("04547-130", "20040-002", "BR", 3)
+
+			// Assert
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestEstimator_TransitDays(t *testing.T) {
+	tests := []struct {
+		name     string
+		calendar holidays
+		want     int
+	}{
+		{"no handling time", holidays{}, 3},
+		{"origin holiday is ignored", holidays{"0|2025-01-07": true}, 3},
+		{"destination holiday during transit", holidays{"2|2025-01-08": true}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			e := NewEstimatorWithCalendar(testConfig(), tt.calendar)
+			e.now = func() time.Time { return monday }
+
+			// Act
+			result := e.TransitDays("04547-130", "20040-002", "BR", 3)
+
+			// Assert
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+// everyDay is a misconfigured Calendar where every day is a holiday
+type everyDay struct{}
+
+func (everyDay) IsHoliday(country, zipcode string, day time.Time) bool { return true }
+
+func TestEstimator_DeliveryDays_BoundsHolidaySkipping(t *testing.T) {
+	// Arrange
+	e := NewEstimatorWithCalendar(testConfig(), everyDay{})
+	e.now = func() time.Time { return monday }
+
+	// Act
+	result := e.DeliveryDays("04547-130", "20040-002", "BR", 3)
+
+	// Assert
+	assert.Equal(t, 5+maxHolidayDays, result)
+}
+
+func TestEstimator_DeliveryDays_SkipsClosedWeekdays(t *testing.T) {
+	weekend := []time.Weekday{time.Saturday, time.Sunday}
+	tests := []struct {
+		name     string
+		now      time.Time
+		calendar Calendar
+		closed   []time.Weekday
+		want     int
+	}{
+		{"no closed weekdays", monday, nil, nil, 5},
+		{"weekend during transit", monday, nil, weekend, 7},
+		{"only sundays closed", monday, nil, []time.Weekday{time.Sunday}, 5},
+		{"only saturdays closed", monday, nil, []time.Weekday{time.Saturday}, 6},
+		{"holiday and weekend", monday, holidays{"2|2025-01-10": true}, weekend, 8},
+		{"weekend during handling is ignored", saturday, nil, weekend, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			e := NewEstimatorWithCalendar(testConfig(), tt.calendar)
+			e.now = func() time.Time { return tt.now }
+
+			// Act
+			result := e.DeliveryDays("04547-130", "20040-002", "BR", 3, tt.closed...)
+
+			// Assert
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestEstimator_DeliveryDaysFrom(t *testing.T) {
+	// 2025-01-08 is a Wednesday, two days after monday
+	pickup := time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		pickup   time.Time
+		calendar Calendar
+		want     int
+	}{
+		{"waits for the pickup", pickup, nil, 5},
+		{"same-day pickup", time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), nil, 3},
+		{"holiday after the pickup", pickup, holidays{"2|2025-01-10": true}, 6},
+		{"holiday before the pickup is ignored", pickup, holidays{"2|2025-01-07": true}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			e := NewEstimatorWithCalendar(testConfig(), tt.calendar)
+			e.now = func() time.Time { return monday }
+
+			// Act
+			result := e.DeliveryDaysFrom(tt.pickup, "04547-130", "20040-002", "BR", 3)
+
+			// Assert
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestEstimator_WithClock(t *testing.T) {
+	// Arrange
+	e := NewEstimatorWithClock(testConfig(), holidays{"2|2025-01-10": true}, determinism.FixedClock{Time: monday})
+
+	// Act
+	result := e.DeliveryDays("04547-130", "20040-002", "BR", 3)
+
+	// Assert
+	assert.Equal(t, 6, result)
+}
+
+// zones is a Timezones resolving the zipcodes by their first digit
+type zones map[string]*time.Location
+
+func (z zones) Location(country, zipcode string) *time.Location {
+	if location, ok := z[zipcode[:1]]; ok {
+		return location
+	}
+	return time.UTC
+}
+
+var (
+	saoPaulo = time.FixedZone("UTC-3", -3*60*60)
+	lisbon   = time.FixedZone("UTC+0", 0)
+	// 2025-01-11 01:30 UTC is still Friday 2025-01-10 22:30 in São Paulo
+	fridayNightInSaoPaulo = time.Date(2025, 1, 11, 1, 30, 0, 0, time.UTC)
+)
+
+func TestEstimator_WithTimezones_CountsLocalDays(t *testing.T) {
+	// Arrange
+	cfg := Config{Warehouses: []Warehouse{{
+		ID:                    "sp",
+		ZipcodePrefixes:       []string{"0"},
+		HandlingDays:          1,
+		DayOfWeekHandlingDays: map[string]int{"saturday": 3},
+	}}}
+	clock := determinism.FixedClock{Time: fridayNightInSaoPaulo}
+	local := NewEstimatorWithClock(cfg, holidays{"2|2025-01-12": true}, clock).WithTimezones(zones{"0": saoPaulo, "2": saoPaulo})
+	utc := NewEstimatorWithClock(cfg, holidays{"2|2025-01-12": true}, clock)
+
+	// Act
+	localDays := local.DeliveryDays("04547-130", "20040-002", "BR", 1)
+	utcDays := utc.DeliveryDays("04547-130", "20040-002", "BR", 1)
+
+	// Assert
+	assert.Equal(t, 1, local.HandlingDays("04547-130"), "orders are placed on Friday at the origin")
+	assert.Equal(t, 3, utc.HandlingDays("04547-130"))
+	assert.Equal(t, 3, localDays, "handed over on Saturday, the Sunday holiday is skipped")
+	assert.Equal(t, time.Date(2025, 1, 13, 0, 0, 0, 0, saoPaulo), local.DeliveryDate("04547-130", "20040-002", "BR", localDays))
+	assert.Equal(t, 4, utcDays)
+	assert.Equal(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), utc.DeliveryDate("04547-130", "20040-002", "BR", utcDays))
+}
+
+func TestEstimator_DeliveryDate_DestinationZone(t *testing.T) {
+	// Arrange
+	e := NewEstimatorWithClock(Config{}, nil, determinism.FixedClock{Time: fridayNightInSaoPaulo}).
+		WithTimezones(zones{"0": saoPaulo, "1": lisbon})
+
+	// Act
+	date := e.DeliveryDate("04547-130", "1100-148", "PT", 5)
+
+	// Assert
+	assert.Equal(t, time.Date(2025, 1, 15, 0, 0, 0, 0, lisbon), date, "counted from Friday at the origin, although it is Saturday in Lisbon")
+}
+
+func TestEstimator_DeliveryDaysFrom_WithTimezones(t *testing.T) {
+	// Arrange
+	e := NewEstimatorWithClock(Config{}, nil, determinism.FixedClock{Time: fridayNightInSaoPaulo}).
+		WithTimezones(zones{"0": saoPaulo})
+	pickup := time.Date(2025, 1, 13, 0, 0, 0, 0, saoPaulo)
+
+	// Act
+	days := e.DeliveryDaysFrom(pickup, "04547-130", "20040-002", "BR", 2)
+
+	// Assert
+	assert.Equal(t, 5, days, "3 days from Friday until the pickup plus 2 transit days")
+	assert.Equal(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), e.DeliveryDate("04547-130", "20040-002", "BR", days))
+}
@@ -0,0 +1,135 @@
+package eta
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+)
+
+// transitMatrixHeader is the header of the transit matrix CSV file
+var transitMatrixHeader = []string{"origin_state", "destination_state", "service", "days"}
+
+// TransitMatrix holds the carrier transit days between Brazilian states per service level, loaded
+// from a CSV file. It is safe for concurrent use and can be reloaded at runtime; a failed reload
+// keeps the previous matrix
+type TransitMatrix struct {
+	path string
+
+	mu   sync.RWMutex
+	days map[string]int
+}
+
+// NewTransitMatrix creates an empty transit matrix read from the CSV file at path; call Reload
+// to load it
+func NewTransitMatrix(path string) *TransitMatrix {
+	return &TransitMatrix{path: path, days: map[string]int{}}
+}
+
+// LoadTransitMatrix reads and validates the transit matrix CSV file at path
+func LoadTransitMatrix(path string) (*TransitMatrix, error) {
+	m := NewTransitMatrix(path)
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reload reads the file again and replaces the transit days atomically
+func (m *TransitMatrix) Reload() error {
+	file, err := os.Open(m.path)
+	if err != nil {
+		return fmt.Errorf("failed to read transit matrix: %w", err)
+	}
+	defer file.Close()
+
+	days, err := parseTransitMatrix(file)
+	if err != nil {
+		return fmt.Errorf("invalid transit matrix: %w", err)
+	}
+
+	m.mu.Lock()
+	m.days = days
+	m.mu.Unlock()
+	return nil
+}
+
+// Len returns the number of routes and service levels in the matrix
+func (m *TransitMatrix) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.days)
+}
+
+// Days returns the transit days of a service level from the origin state to the destination
+// state, or false when the matrix has no entry for them
+func (m *TransitMatrix) Days(originState, destinationState, service string) (int, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	days, ok := m.days[transitKey(originState, destinationState, service)]
+	return days, ok
+}
+
+// parseTransitMatrix reads the rows origin_state,destination_state,service,days after the header
+func parseTransitMatrix(r io.Reader) (map[string]int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(transitMatrixHeader)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("missing header")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, column := range transitMatrixHeader {
+		if strings.ToLower(strings.TrimSpace(header[i])) != column {
+			return nil, fmt.Errorf("header must be %s", strings.Join(transitMatrixHeader, ","))
+		}
+	}
+
+	days := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return days, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		origin, destination := strings.ToUpper(record[0]), strings.ToUpper(record[1])
+		if !zipcode.IsState(origin) {
+			return nil, fmt.Errorf("line %d: unknown origin state %q", line, record[0])
+		}
+		if !zipcode.IsState(destination) {
+			return nil, fmt.Errorf("line %d: unknown destination state %q", line, record[1])
+		}
+		service := strings.ToLower(strings.TrimSpace(record[2]))
+		if service == "" {
+			return nil, fmt.Errorf("line %d: service is required", line)
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(record[3]))
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("line %d: days must be a positive integer, got %q", line, record[3])
+		}
+
+		key := transitKey(origin, destination, service)
+		if _, ok := days[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate route %s-%s for service %s", line, origin, destination, service)
+		}
+		days[key] = value
+	}
+}
+
+func transitKey(originState, destinationState, service string) string {
+	return strings.ToUpper(originState) + "|" + strings.ToUpper(destinationState) + "|" + strings.ToLower(service)
+}
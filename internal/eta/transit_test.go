@@ -0,0 +1,135 @@
+package eta
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTransitMatrix = `origin_state,destination_state,service,days
+SP,SP,standard,1
+SP,AM,standard,9
+SP,AM,express,4
+`
+
+func writeTransitMatrix(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "transit.csv")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadTransitMatrix(t *testing.T) {
+	// Arrange
+	path := writeTransitMatrix(t, testTransitMatrix)
+
+	// Act
+	matrix, err := LoadTransitMatrix(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 3, matrix.Len())
+	days, ok := matrix.Days("sp", "am", "Express")
+	assert.True(t, ok)
+	assert.Equal(t, 4, days)
+	_, ok = matrix.Days("SP", "SP", "express")
+	assert.False(t, ok)
+}
+
+func TestLoadTransitMatrix_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		contains string
+	}{
+		{"empty file", "", "missing header"},
+		{"wrong header", "from,to,service,days\n", "header must be"},
+		{"missing column", "origin_state,destination_state,service,days\nSP,RJ,standard\n", "wrong number of fields"},
+		{"unknown origin state", "origin_state,destination_state,service,days\nXX,RJ,standard,2\n", `line 2: unknown origin state "XX"`},
+		{"unknown destination state", "origin_state,destination_state,service,days\nSP,north,standard,2\n", `line 2: unknown destination state "north"`},
+		{"missing service", "origin_state,destination_state,service,days\nSP,RJ,,2\n", "line 2: service is required"},
+		{"invalid days", "origin_state,destination_state,service,days\nSP,RJ,standard,two\n", "line 2: days must be a positive integer"},
+		{"zero days", "origin_state,destination_state,service,days\nSP,RJ,standard,0\n", "line 2: days must be a positive integer"},
+		{"duplicate route", "origin_state,destination_state,service,days\nSP,RJ,standard,2\nsp,rj,standard,3\n", "line 3: duplicate route SP-RJ for service standard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			path := writeTransitMatrix(t, tt.content)
+
+			// Act
+			matrix, err := LoadTransitMatrix(path)
+
+			// Assert
+			assert.Nil(t, matrix)
+			assert.ErrorContains(t, err, "invalid transit matrix")
+			assert.ErrorContains(t, err, tt.contains)
+		})
+	}
+}
+
+func TestTransitMatrix_Reload(t *testing.T) {
+	// Arrange
+	path := writeTransitMatrix(t, testTransitMatrix)
+	matrix, err := LoadTransitMatrix(path)
+	assert.NoError(t, err)
+
+	// Act
+	assert.NoError(t, os.WriteFile(path, []byte("origin_state,destination_state,service,days\nSP,AM,standard,7\n"), 0o600))
+	reloadErr := matrix.Reload()
+	assert.NoError(t, os.WriteFile(path, []byte("origin_state,destination_state,service,days\nSP,AM,standard,-1\n"), 0o600))
+	failedErr := matrix.Reload()
+
+	// Assert
+	assert.NoError(t, reloadErr)
+	assert.Error(t, failedErr)
+	assert.Equal(t, 1, matrix.Len())
+	days, ok := matrix.Days("SP", "AM", "standard")
+	assert.True(t, ok)
+	assert.Equal(t, 7, days)
+}
+
+func TestEstimator_ServiceTransitDays(t *testing.T) {
+	// Arrange
+	matrix, err := LoadTransitMatrix(writeTransitMatrix(t, testTransitMatrix))
+	assert.NoError(t, err)
+	e := NewEstimator(Config{}).WithTransitMatrix(matrix)
+
+	tests := []struct {
+		name        string
+		destination string
+		country     string
+		service     string
+		expected    int
+	}{
+		{"route in the matrix", "69005-040", "BR", "standard", 9},
+		{"service in the matrix", "69005-040", "br", "express", 4},
+		{"service missing from the matrix", "01310-200", "BR", "express", 2},
+		{"route missing from the matrix", "20040-020", "BR", "standard", 2},
+		{"international route", "10001", "US", "standard", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := e.ServiceTransitDays("01310-100", tt.destination, tt.country, tt.service, 2)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestEstimator_ServiceTransitDays_WithoutMatrix(t *testing.T) {
+	// Arrange
+	e := NewEstimator(Config{})
+
+	// Act
+	result := e.ServiceTransitDays("01310-100", "69005-040", "BR", "standard", 2)
+
+	// Assert
+	assert.Equal(t, 2, result)
+}
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// ErrQueueFull is returned by AsyncPublisher.Publish when its queue is full and the event is dropped
+var ErrQueueFull = errors.New("events queue is full")
+
+// queuedEvent is an event waiting in the queue of an AsyncPublisher with the context it was
+// published in, detached from its cancellation
+type queuedEvent struct {
+	ctx   context.Context
+	event Event
+}
+
+// AsyncPublisher publishes events to the next publisher in the background, through a bounded
+// queue, so that a slow broker does not add its latency to the requests publishing them. Events
+// are published in queue order by a single goroutine, preserving the order of the events of each
+// entity; when the queue is full, events are dropped. It is safe for concurrent use
+type AsyncPublisher struct {
+	next   Publisher
+	logger *zap.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan queuedEvent
+	done   chan struct{}
+}
+
+// NewAsyncPublisher creates a publisher queueing up to size events for next. Close must be called
+// on shutdown to publish the queued events
+func NewAsyncPublisher(next Publisher, size int, logger *zap.Logger) *AsyncPublisher {
+	p := &AsyncPublisher{
+		next:   next,
+		logger: logger,
+		queue:  make(chan queuedEvent, size),
+		done:   make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Publish implements Publisher, queueing the event. It returns ErrQueueFull when the queue is full
+// or the publisher is closed; failures of the next publisher are only logged
+func (p *AsyncPublisher) Publish(ctx context.Context, event Event) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return fmt.Errorf("failed to publish event %s: %w", event.Type, ErrQueueFull)
+	}
+	select {
+	case p.queue <- queuedEvent{ctx: context.WithoutCancel(ctx), event: event}:
+		return nil
+	default:
+		return fmt.Errorf("failed to publish event %s: %w", event.Type, ErrQueueFull)
+	}
+}
+
+// Close stops accepting events and waits for the queued ones to be published
+func (p *AsyncPublisher) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+	<-p.done
+}
+
+// run publishes the queued events until the queue is closed
+func (p *AsyncPublisher) run() {
+	defer close(p.done)
+	for queued := range p.queue {
+		if err := p.next.Publish(queued.ctx, queued.event); err != nil {
+			p.logger.Warn("Failed to publish event",
+				zap.String("event_type", queued.event.Type),
+				zap.String("subject", queued.event.Subject),
+				zap.Error(err),
+			)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"go.uber.org/zap"
+)
+
+// Supported brokers
+const (
+	BrokerLog      = "log"
+	BrokerKafka    = "kafka"
+	BrokerRabbitMQ = "rabbitmq"
+)
+
+// Config selects and configures the message broker events are published to
+type Config struct {
+	// Broker is log, kafka or rabbitmq
+	Broker           string
+	KafkaBrokers     []string
+	KafkaTopic       string
+	RabbitMQURL      string
+	RabbitMQExchange string
+	// PublishTimeout bounds each publish, including the broker acknowledgement
+	PublishTimeout time.Duration
+	// QueueSize is the number of events an AsyncPublisher queues before dropping them
+	QueueSize int
+}
+
+// ConfigFromEnv reads EVENTS_BROKER (default log), EVENTS_KAFKA_BROKERS (comma-separated host:port,
+// required for kafka), EVENTS_KAFKA_TOPIC (default shipping-events), EVENTS_RABBITMQ_URL (required
+// for rabbitmq), EVENTS_RABBITMQ_EXCHANGE (default shipping-events), EVENTS_PUBLISH_TIMEOUT (default 5s) and
+// EVENTS_QUEUE_SIZE (default 1000)
+func ConfigFromEnv() (Config, error) {
+	timeout, err := config.Duration("EVENTS_PUBLISH_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if timeout <= 0 {
+		return Config{}, fmt.Errorf("EVENTS_PUBLISH_TIMEOUT must be positive")
+	}
+	queueSize, err := config.Int("EVENTS_QUEUE_SIZE", 1000)
+	if err != nil {
+		return Config{}, err
+	}
+	if queueSize <= 0 {
+		return Config{}, fmt.Errorf("EVENTS_QUEUE_SIZE must be positive")
+	}
+
+	cfg := Config{
+		Broker:           strings.ToLower(config.String("EVENTS_BROKER", BrokerLog)),
+		KafkaBrokers:     config.List("EVENTS_KAFKA_BROKERS", nil),
+		KafkaTopic:       config.String("EVENTS_KAFKA_TOPIC", "shipping-events"),
+		RabbitMQURL:      config.String("EVENTS_RABBITMQ_URL", ""),
+		RabbitMQExchange: config.String("EVENTS_RABBITMQ_EXCHANGE", "shipping-events"),
+		PublishTimeout:   timeout,
+		QueueSize:        queueSize,
+	}
+	switch cfg.Broker {
+	case BrokerLog:
+	case BrokerKafka:
+		if len(cfg.KafkaBrokers) == 0 {
+			return Config{}, fmt.Errorf("EVENTS_KAFKA_BROKERS is required for the kafka broker")
+		}
+	case BrokerRabbitMQ:
+		if cfg.RabbitMQURL == "" {
+			return Config{}, fmt.Errorf("EVENTS_RABBITMQ_URL is required for the rabbitmq broker")
+		}
+	default:
+		return Config{}, fmt.Errorf("EVENTS_BROKER must be one of %s, %s, %s, got %q", BrokerLog, BrokerKafka, BrokerRabbitMQ, cfg.Broker)
+	}
+	return cfg, nil
+}
+
+// NewPublisher creates the publisher of the configured broker. The returned function closes the
+// connection to the broker and must be called on shutdown
+func NewPublisher(cfg Config, logger *zap.Logger) (Publisher, func() error, error) {
+	switch cfg.Broker {
+	case BrokerKafka:
+		publisher := NewKafkaPublisher(cfg)
+		return publisher, publisher.Close, nil
+	case BrokerRabbitMQ:
+		publisher, err := NewRabbitMQPublisher(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return publisher, publisher.Close, nil
+	default:
+		return NewLogPublisher(logger), func() error { return nil }, nil
+	}
+}
@@ -0,0 +1,156 @@
+// Package events publishes domain events for other systems to react to (e.g. label printing when
+// a shipment is booked, analytics streams).
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+)
+
+// Event types
+const (
+	// QuoteCreated is published with a model.QuoteCreated when a quote is calculated and stored
+	QuoteCreated = "quote.created"
+	// ShipmentBooked is published with the model.Shipment when a quote is booked
+	ShipmentBooked = "shipment.booked"
+	// TrackingUpdated is published with the model.ShipmentTracking when a shipment receives
+	// new tracking events
+	TrackingUpdated = "tracking.updated"
+	// QuoteJobCompleted is published with a worker.Result when the worker finishes a queued quote job
+	QuoteJobCompleted = "quote.job_completed"
+	// PricingConfigChanged is published with a pricingreload.Revision when a reload puts a new
+	// pricing configuration in force
+	PricingConfigChanged = "pricing.config_changed"
+	// ShipmentRepriced is published with a repricing.Difference when a booked shipment is priced
+	// differently by the pricing configuration in force
+	ShipmentRepriced = "shipment.repriced"
+	// RepricingCompleted is published with a repricing.Report when a repricing of the booked
+	// shipments finishes
+	RepricingCompleted = "repricing.completed"
+)
+
+// Message headers set by the broker publishers, besides the trace context
+const (
+	HeaderEventID   = "event_id"
+	HeaderEventType = "event_type"
+)
+
+// Event is something that happened to an entity of the service
+type Event struct {
+	// ID identifies the event for deduplication by consumers; publishers generate it when empty
+	ID string `json:"id"`
+	// Type names the event, e.g. shipment.booked
+	Type string `json:"type"`
+	// Subject is the ID of the entity the event is about
+	Subject string `json:"subject"`
+	// Tenant is the tenant the entity belongs to, whose webhook subscriptions are notified; empty
+	// for events of the service as a whole
+	Tenant     string    `json:"tenant,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data,omitempty"`
+}
+
+// Publisher delivers events to their consumers
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Message is an event encoded for a message broker
+type Message struct {
+	// Key is the event subject, so that brokers keep the events of an entity in order
+	Key string
+	// Headers carry the event ID and type and the trace context of the publishing request
+	Headers map[string]string
+	// Body is the JSON-encoded event
+	Body []byte
+}
+
+// Encode encodes the event as a broker message, generating its ID when empty and injecting
+// the trace context of ctx into the headers
+func Encode(ctx context.Context, event *Event) (Message, error) {
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to encode event %s: %w", event.Type, err)
+	}
+
+	headers := propagation.MapCarrier{
+		HeaderEventID:   event.ID,
+		HeaderEventType: event.Type,
+	}
+	otel.GetTextMapPropagator().Inject(ctx, headers)
+	return Message{Key: event.Subject, Headers: headers, Body: body}, nil
+}
+
+// LogPublisher publishes events as structured log entries, for log-based pipelines and when no
+// message broker is configured
+type LogPublisher struct {
+	logger *zap.Logger
+}
+
+// NewLogPublisher creates a publisher writing events to logger
+func NewLogPublisher(logger *zap.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+// Publish implements Publisher
+func (p *LogPublisher) Publish(ctx context.Context, event Event) error {
+	logger.LogRequest(p.logger, ctx, "Evento publicado",
+		zap.String("event_type", event.Type),
+		zap.String("subject", event.Subject),
+		zap.Time("occurred_at", event.OccurredAt),
+		zap.Any("data", event.Data),
+	)
+	return nil
+}
+
+// MemoryPublisher keeps the published events and their encoded messages, for tests. It is safe
+// for concurrent use
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	events   []Event
+	messages []Message
+}
+
+// NewMemoryPublisher creates an empty in-memory publisher
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publish implements Publisher
+func (p *MemoryPublisher) Publish(ctx context.Context, event Event) error {
+	message, err := Encode(ctx, &event)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	p.messages = append(p.messages, message)
+	return nil
+}
+
+// Events returns the published events, in publishing order
+func (p *MemoryPublisher) Events() []Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Event{}, p.events...)
+}
+
+// Messages returns the encoded messages of the published events, in publishing order
+func (p *MemoryPublisher) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message{}, p.messages...)
+}
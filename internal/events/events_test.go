@@ -0,0 +1,212 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogPublisher_Publish(t *testing.T) {
+	// Arrange
+	core, logs := observer.New(zapcore.InfoLevel)
+	publisher := NewLogPublisher(zap.New(core))
+	occurredAt := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	// Act
+	err := publisher.Publish(context.Background(), Event{
+		Type:       "shipment.booked",
+		Subject:    "s1",
+		OccurredAt: occurredAt,
+		Data:       map[string]string{"quote_id": "q1"},
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	entries := logs.FilterMessage("Evento publicado").All()
+	if assert.Len(t, entries, 1) {
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "shipment.booked", fields["event_type"])
+		assert.Equal(t, "s1", fields["subject"])
+		assert.Equal(t, occurredAt, fields["occurred_at"])
+		assert.Equal(t, map[string]string{"quote_id": "q1"}, fields["data"])
+	}
+}
+
+// tracedContext returns a context carrying a sampled span, with the W3C propagator installed
+// globally for the duration of the test
+func tracedContext(t *testing.T) context.Context {
+	t.Helper()
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+var testEvent = Event{
+	Type:       ShipmentBooked,
+	Subject:    "s1",
+	OccurredAt: time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC),
+	Data:       map[string]string{"quote_id": "q1"},
+}
+
+func TestEncode(t *testing.T) {
+	// Arrange
+	ctx := tracedContext(t)
+	event := testEvent
+
+	// Act
+	message, err := Encode(ctx, &event)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, event.ID)
+	assert.Equal(t, "s1", message.Key)
+	assert.Equal(t, map[string]string{
+		HeaderEventID:   event.ID,
+		HeaderEventType: ShipmentBooked,
+		"traceparent":   testTraceparent,
+	}, message.Headers)
+	assert.JSONEq(t, `{"id":"`+event.ID+`","type":"shipment.booked","subject":"s1",`+
+		`"occurred_at":"2025-03-10T15:00:00Z","data":{"quote_id":"q1"}}`, string(message.Body))
+}
+
+func TestEncode_KeepsID(t *testing.T) {
+	// Arrange
+	event := testEvent
+	event.ID = "e1"
+
+	// Act
+	message, err := Encode(context.Background(), &event)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "e1", event.ID)
+	assert.Equal(t, "e1", message.Headers[HeaderEventID])
+}
+
+func TestEncode_InvalidData(t *testing.T) {
+	// Arrange
+	event := testEvent
+	event.Data = make(chan int)
+
+	// Act
+	_, err := Encode(context.Background(), &event)
+
+	// Assert
+	assert.ErrorContains(t, err, "failed to encode event shipment.booked")
+}
+
+func TestMemoryPublisher(t *testing.T) {
+	// Arrange
+	publisher := NewMemoryPublisher()
+
+	// Act
+	err := publisher.Publish(tracedContext(t), testEvent)
+
+	// Assert
+	assert.NoError(t, err)
+	published := publisher.Events()
+	if assert.Len(t, published, 1) {
+		assert.NotEmpty(t, published[0].ID)
+		assert.Equal(t, ShipmentBooked, published[0].Type)
+	}
+	messages := publisher.Messages()
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, testTraceparent, messages[0].Headers["traceparent"])
+	}
+}
+
+// blockingPublisher blocks each publish until release is closed, then publishes to next
+type blockingPublisher struct {
+	next    Publisher
+	release chan struct{}
+}
+
+func (p *blockingPublisher) Publish(ctx context.Context, event Event) error {
+	<-p.release
+	return p.next.Publish(ctx, event)
+}
+
+// failingPublisher fails every publish
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(ctx context.Context, event Event) error {
+	return errors.New("broker unavailable")
+}
+
+func TestAsyncPublisher(t *testing.T) {
+	t.Run("publishes the queued events in order on close", func(t *testing.T) {
+		// Arrange
+		next := NewMemoryPublisher()
+		publisher := NewAsyncPublisher(next, 10, zaptest.NewLogger(t))
+		ctx, cancel := context.WithCancel(tracedContext(t))
+
+		// Act
+		for _, subject := range []string{"s1", "s2", "s3"} {
+			assert.NoError(t, publisher.Publish(ctx, Event{Type: ShipmentBooked, Subject: subject}))
+		}
+		cancel()
+		publisher.Close()
+
+		// Assert
+		published := next.Events()
+		if assert.Len(t, published, 3) {
+			assert.Equal(t, []string{"s1", "s2", "s3"}, []string{published[0].Subject, published[1].Subject, published[2].Subject})
+		}
+		assert.Equal(t, testTraceparent, next.Messages()[0].Headers["traceparent"], "the trace context outlives the request")
+	})
+
+	t.Run("drops the events when the queue is full", func(t *testing.T) {
+		// Arrange
+		next := NewMemoryPublisher()
+		blocking := &blockingPublisher{next: next, release: make(chan struct{})}
+		publisher := NewAsyncPublisher(blocking, 1, zaptest.NewLogger(t))
+
+		// Act
+		var errs []error
+		for range 3 {
+			errs = append(errs, publisher.Publish(context.Background(), testEvent))
+		}
+		close(blocking.release)
+		publisher.Close()
+		closedErr := publisher.Publish(context.Background(), testEvent)
+
+		// Assert
+		assert.ErrorIs(t, errors.Join(errs...), ErrQueueFull)
+		assert.Less(t, len(next.Events()), 3)
+		assert.ErrorIs(t, closedErr, ErrQueueFull)
+	})
+
+	t.Run("logs the failures", func(t *testing.T) {
+		// Arrange
+		core, logs := observer.New(zapcore.WarnLevel)
+		publisher := NewAsyncPublisher(failingPublisher{}, 1, zap.New(core))
+
+		// Act
+		err := publisher.Publish(context.Background(), testEvent)
+		publisher.Close()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, logs.FilterMessage("Failed to publish event").Len())
+	})
+}
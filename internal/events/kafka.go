@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaWriter is the subset of kafka.Writer used by KafkaPublisher
+type kafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// KafkaPublisher publishes events to a Kafka topic, keyed by subject so that the events of an
+// entity land on the same partition in order
+type KafkaPublisher struct {
+	writer kafkaWriter
+}
+
+// NewKafkaPublisher creates a publisher writing to cfg.KafkaTopic on cfg.KafkaBrokers. Writes are
+// synchronous and acknowledged by all in-sync replicas
+func NewKafkaPublisher(cfg Config) *KafkaPublisher {
+	return &KafkaPublisher{writer: &kafka.Writer{
+		Addr:         kafka.TCP(cfg.KafkaBrokers...),
+		Topic:        cfg.KafkaTopic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		BatchTimeout: 10 * time.Millisecond,
+		WriteTimeout: cfg.PublishTimeout,
+	}}
+}
+
+// Publish implements Publisher
+func (p *KafkaPublisher) Publish(ctx context.Context, event Event) error {
+	message, err := Encode(ctx, &event)
+	if err != nil {
+		return err
+	}
+
+	headers := make([]kafka.Header, 0, len(message.Headers))
+	for key, value := range message.Headers {
+		headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+	}
+	err = p.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(message.Key),
+		Value:   message.Body,
+		Headers: headers,
+		Time:    event.OccurredAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish event %s to kafka: %w", event.Type, err)
+	}
+	return nil
+}
+
+// Close flushes pending messages and closes the connections to the brokers
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// RabbitMQPublisher publishes events to a durable topic exchange, routed by event type
+type RabbitMQPublisher struct {
+	exchange string
+	// send publishes a message and waits for the broker confirmation
+	send  func(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error
+	close func() error
+}
+
+// NewRabbitMQPublisher connects to cfg.RabbitMQURL and declares cfg.RabbitMQExchange. The channel
+// is in confirm mode: Publish returns once the broker has taken responsibility for the message
+func NewRabbitMQPublisher(cfg Config) (*RabbitMQPublisher, error) {
+	conn, err := amqp.Dial(cfg.RabbitMQURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to rabbitmq: %w", err)
+	}
+	channel, err := conn.Channel()
+	if err == nil {
+		err = channel.ExchangeDeclare(cfg.RabbitMQExchange, amqp.ExchangeTopic, true, false, false, false, nil)
+	}
+	if err == nil {
+		err = channel.Confirm(false)
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to set up rabbitmq exchange %s: %w", cfg.RabbitMQExchange, err)
+	}
+
+	return &RabbitMQPublisher{
+		exchange: cfg.RabbitMQExchange,
+		send: func(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+			ctx, cancel := context.WithTimeout(ctx, cfg.PublishTimeout)
+			defer cancel()
+			confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, false, false, msg)
+			if err != nil {
+				return err
+			}
+			acked, err := confirmation.WaitContext(ctx)
+			if err != nil {
+				return err
+			}
+			if !acked {
+				return errors.New("message rejected by the broker")
+			}
+			return nil
+		},
+		close: conn.Close,
+	}, nil
+}
+
+// Publish implements Publisher
+func (p *RabbitMQPublisher) Publish(ctx context.Context, event Event) error {
+	message, err := Encode(ctx, &event)
+	if err != nil {
+		return err
+	}
+
+	headers := make(amqp.Table, len(message.Headers))
+	for key, value := range message.Headers {
+		headers[key] = value
+	}
+	err = p.send(ctx, p.exchange, event.Type, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    event.ID,
+		Timestamp:    event.OccurredAt,
+		Type:         event.Type,
+		Body:         message.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish event %s to rabbitmq: %w", event.Type, err)
+	}
+	return nil
+}
+
+// Close closes the connection to the broker
+func (p *RabbitMQPublisher) Close() error {
+	return p.close()
+}
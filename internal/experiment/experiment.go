@@ -0,0 +1,113 @@
+// Package experiment routes a fraction of quote traffic to an alternative rate table so pricing
+// changes can be A/B tested.
+package experiment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+)
+
+// Experiment arms
+const (
+	ArmControl   = "control"
+	ArmTreatment = "treatment"
+)
+
+// buckets is the resolution of the traffic split (0.01%)
+const buckets = 10000
+
+// Config holds the pricing experiment configuration
+type Config struct {
+	// Name identifies the experiment in responses, metrics and logs; empty disables it
+	Name string
+	// Fraction is the share of traffic, from 0 to 1, priced with the treatment rate table
+	Fraction float64
+	// PricingConfigPath is the rate table of the treatment arm
+	PricingConfigPath string
+}
+
+// ConfigFromEnv reads PRICING_EXPERIMENT_NAME, PRICING_EXPERIMENT_FRACTION (default 0) and
+// PRICING_EXPERIMENT_CONFIG_PATH
+func ConfigFromEnv() (Config, error) {
+	fraction, err := config.Float("PRICING_EXPERIMENT_FRACTION", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg := Config{
+		Name:              config.String("PRICING_EXPERIMENT_NAME", ""),
+		Fraction:          fraction,
+		PricingConfigPath: config.String("PRICING_EXPERIMENT_CONFIG_PATH", ""),
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate checks the traffic fraction and that an enabled experiment has a treatment rate table
+func (c Config) Validate() error {
+	if c.Fraction < 0 || c.Fraction > 1 {
+		return fmt.Errorf("PRICING_EXPERIMENT_FRACTION must be between 0 and 1, got %g", c.Fraction)
+	}
+	if c.Name != "" && c.PricingConfigPath == "" {
+		return errors.New("PRICING_EXPERIMENT_CONFIG_PATH is required when PRICING_EXPERIMENT_NAME is set")
+	}
+	return nil
+}
+
+// Enabled reports whether the experiment is configured
+func (c Config) Enabled() bool {
+	return c.Name != ""
+}
+
+// Load builds the experiment, reading the treatment rate table
+func Load(cfg Config) (*Experiment, error) {
+	treatment, err := pricing.LoadConfig(cfg.PricingConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("experiment %q: %w", cfg.Name, err)
+	}
+	return &Experiment{Name: cfg.Name, Fraction: cfg.Fraction, Treatment: treatment}, nil
+}
+
+// Experiment assigns quotes to the control or treatment arm
+type Experiment struct {
+	Name     string
+	Fraction float64
+	// Treatment is the rate table quoted to the treatment arm; the control arm keeps the
+	// service's pricing configuration
+	Treatment pricing.Config
+}
+
+// Assign returns the arm of the assignment key. The assignment is deterministic, so the same
+// key always lands in the same arm, and independent across experiments. An empty key is
+// assigned to the control arm
+func (e *Experiment) Assign(key string) string {
+	if key == "" || e.Fraction <= 0 {
+		return ArmControl
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(e.Name + ":" + key))
+	if float64(h.Sum32()%buckets) < e.Fraction*buckets {
+		return ArmTreatment
+	}
+	return ArmControl
+}
+
+type subjectKey struct{}
+
+// WithSubject returns a context whose quotes are assigned to an arm by subject, the ID of the
+// client requesting them, so that each client keeps its arm across requests
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// SubjectFromContext returns the subject of ctx, empty when the client was not identified
+func SubjectFromContext(ctx context.Context) string {
+	subject, _ := ctx.Value(subjectKey{}).(string)
+	return subject
+}
@@ -0,0 +1,137 @@
+package experiment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExperiment_Assign(t *testing.T) {
+	tests := []struct {
+		name     string
+		fraction float64
+		wantMin  int
+		wantMax  int
+	}{
+		{"disabled", 0, 0, 0},
+		{"everyone", 1, 10000, 10000},
+		{"ten percent", 0.1, 900, 1100},
+		{"half", 0.5, 4750, 5250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			e := &Experiment{Name: "volume-curve", Fraction: tt.fraction}
+
+			// Act
+			treatment := 0
+			for i := 0; i < 10000; i++ {
+				if e.Assign(fmt.Sprintf("request-%d", i)) == ArmTreatment {
+					treatment++
+				}
+			}
+
+			// Assert
+			assert.GreaterOrEqual(t, treatment, tt.wantMin)
+			assert.LessOrEqual(t, treatment, tt.wantMax)
+		})
+	}
+}
+
+func TestExperiment_Assign_IsDeterministic(t *testing.T) {
+	// Arrange
+	e := &Experiment{Name: "volume-curve", Fraction: 0.5}
+
+	// Act
+	first := e.Assign("request-42")
+	second := e.Assign("request-42")
+
+	// Assert
+	assert.Equal(t, first, second)
+}
+
+func TestExperiment_Assign_EmptyKeyIsControl(t *testing.T) {
+	// Arrange
+	e := &Experiment{Name: "volume-curve", Fraction: 1}
+
+	// Act
+	arm := e.Assign("")
+
+	// Assert
+	assert.Equal(t, ArmControl, arm)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		wantEnabled bool
+		wantErr     string
+	}{
+		{"disabled by default", map[string]string{}, false, ""},
+		{
+			"enabled",
+			map[string]string{
+				"PRICING_EXPERIMENT_NAME":        "volume-curve",
+				"PRICING_EXPERIMENT_FRACTION":    "0.1",
+				"PRICING_EXPERIMENT_CONFIG_PATH": "/etc/pricing-b.json",
+			},
+			true, "",
+		},
+		{"fraction out of range", map[string]string{"PRICING_EXPERIMENT_FRACTION": "1.5"}, false, "between 0 and 1"},
+		{"invalid fraction", map[string]string{"PRICING_EXPERIMENT_FRACTION": "half"}, false, "PRICING_EXPERIMENT_FRACTION"},
+		{"missing rate table", map[string]string{"PRICING_EXPERIMENT_NAME": "volume-curve"}, false, "PRICING_EXPERIMENT_CONFIG_PATH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for _, key := range []string{"PRICING_EXPERIMENT_NAME", "PRICING_EXPERIMENT_FRACTION", "PRICING_EXPERIMENT_CONFIG_PATH"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantEnabled, cfg.Enabled())
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "pricing-b.json")
+	content := `{
+		"version": "volume-curve-b",
+		"default_country": "BR",
+		"currencies": {"BRL": {"base_cost": 1000, "weight_unit_kg": 0.5, "volume_surcharge_rate": 0.08, "volume_unit_cm3": 1000}},
+		"countries": {"BR": "BRL"}
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	e, err := Load(Config{Name: "volume-curve", Fraction: 0.1, PricingConfigPath: path})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "volume-curve", e.Name)
+	assert.Equal(t, "volume-curve-b", e.Treatment.VersionID())
+}
+
+func TestLoad_InvalidRateTable(t *testing.T) {
+	// Act
+	_, err := Load(Config{Name: "volume-curve", Fraction: 0.1, PricingConfigPath: filepath.Join(t.TempDir(), "missing.json")})
+
+	// Assert
+	assert.ErrorContains(t, err, `experiment "volume-curve"`)
+}
@@ -0,0 +1,98 @@
+package experiment
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+)
+
+// Shadow comparison outcomes
+const (
+	ShadowMatch    = "match"
+	ShadowDiverged = "diverged"
+	ShadowError    = "error"
+	// ShadowDropped is the outcome of the quotes not compared because the shadow quotes in
+	// progress reached the concurrency limit
+	ShadowDropped = "dropped"
+)
+
+// DefaultShadowConcurrency is the number of shadow quotes priced at the same time when none is configured
+const DefaultShadowConcurrency = 8
+
+// ShadowConfig holds the shadow pricing configuration
+type ShadowConfig struct {
+	// PricingConfigPath is the secondary rate table every quote is also priced with; empty
+	// disables shadow pricing
+	PricingConfigPath string
+	// Threshold is the relative cost difference, as a fraction of the primary cost, above which
+	// a shadow quote is reported as diverged
+	Threshold float64
+	// Concurrency is the number of shadow quotes priced at the same time; quotes arriving while
+	// all are busy are not compared
+	Concurrency int
+}
+
+// ShadowConfigFromEnv reads PRICING_SHADOW_CONFIG_PATH, PRICING_SHADOW_THRESHOLD (default 0.01) and
+// PRICING_SHADOW_CONCURRENCY (default 8)
+func ShadowConfigFromEnv() (ShadowConfig, error) {
+	threshold, err := config.Float("PRICING_SHADOW_THRESHOLD", 0.01)
+	if err != nil {
+		return ShadowConfig{}, err
+	}
+	if threshold < 0 {
+		return ShadowConfig{}, fmt.Errorf("PRICING_SHADOW_THRESHOLD must not be negative, got %g", threshold)
+	}
+	concurrency, err := config.Int("PRICING_SHADOW_CONCURRENCY", DefaultShadowConcurrency)
+	if err != nil {
+		return ShadowConfig{}, err
+	}
+	if concurrency <= 0 {
+		return ShadowConfig{}, fmt.Errorf("PRICING_SHADOW_CONCURRENCY must be positive, got %d", concurrency)
+	}
+	return ShadowConfig{
+		PricingConfigPath: config.String("PRICING_SHADOW_CONFIG_PATH", ""),
+		Threshold:         threshold,
+		Concurrency:       concurrency,
+	}, nil
+}
+
+// Enabled reports whether shadow pricing is configured
+func (c ShadowConfig) Enabled() bool {
+	return c.PricingConfigPath != ""
+}
+
+// LoadShadow builds the shadow comparison, reading the secondary rate table
+func LoadShadow(cfg ShadowConfig) (*Shadow, error) {
+	secondary, err := pricing.LoadConfig(cfg.PricingConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("shadow pricing: %w", err)
+	}
+	return &Shadow{Pricing: secondary, Threshold: cfg.Threshold, Concurrency: cfg.Concurrency}, nil
+}
+
+// Shadow prices quotes with a secondary rate table for comparison only
+type Shadow struct {
+	Pricing   pricing.Config
+	Threshold float64
+	// Concurrency limits the shadow quotes priced at the same time; zero means DefaultShadowConcurrency
+	Concurrency int
+}
+
+// Compare returns the relative difference of the shadow cost to the primary cost and the outcome,
+// ShadowDiverged when the difference exceeds the threshold. A non-zero shadow cost for a free primary quote counts as a 100%
+// difference
+func (s *Shadow) Compare(primary, shadow float64) (float64, string) {
+	if primary == 0 {
+		if shadow == 0 {
+			return 0, ShadowMatch
+		}
+		return 1, ShadowDiverged
+	}
+	relative := (shadow - primary) / primary
+	if math.Abs(relative) > s.Threshold {
+		return relative, ShadowDiverged
+	}
+	return relative, ShadowMatch
+}
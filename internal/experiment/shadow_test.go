@@ -0,0 +1,137 @@
+package experiment
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShadow_Compare(t *testing.T) {
+	tests := []struct {
+		name         string
+		primary      float64
+		shadow       float64
+		wantRelative float64
+		wantOutcome  string
+	}{
+		{"equal", 1000, 1000, 0, ShadowMatch},
+		{"within threshold", 1000, 1040, 0.04, ShadowMatch},
+		{"above threshold", 1000, 1100, 0.1, ShadowDiverged},
+		{"below threshold", 1000, 900, -0.1, ShadowDiverged},
+		{"both free", 0, 0, 0, ShadowMatch},
+		{"free primary", 0, 500, 1, ShadowDiverged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			shadow := &Shadow{Threshold: 0.05}
+
+			// Act
+			relative, outcome := shadow.Compare(tt.primary, tt.shadow)
+
+			// Assert
+			assert.InDelta(t, tt.wantRelative, relative, 1e-9)
+			assert.Equal(t, tt.wantOutcome, outcome)
+		})
+	}
+}
+
+func TestShadowConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		threshold     string
+		wantEnabled   bool
+		wantThreshold float64
+		wantErr       bool
+	}{
+		{"disabled by default", "", "", false, 0.01, false},
+		{"enabled", "/etc/pricing-next.json", "0.02", true, 0.02, false},
+		{"negative threshold", "/etc/pricing-next.json", "-0.1", false, 0, true},
+		{"invalid threshold", "/etc/pricing-next.json", "abc", false, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("PRICING_SHADOW_CONFIG_PATH", tt.path)
+			t.Setenv("PRICING_SHADOW_THRESHOLD", tt.threshold)
+
+			// Act
+			cfg, err := ShadowConfigFromEnv()
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantEnabled, cfg.Enabled())
+			assert.Equal(t, tt.wantThreshold, cfg.Threshold)
+		})
+	}
+}
+
+func TestShadowConfigFromEnv_Concurrency(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"default", "", DefaultShadowConcurrency, false},
+		{"configured", "2", 2, false},
+		{"zero", "0", 0, true},
+		{"invalid", "abc", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("PRICING_SHADOW_CONCURRENCY", tt.value)
+
+			// Act
+			cfg, err := ShadowConfigFromEnv()
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Concurrency)
+		})
+	}
+}
+
+func TestLoadShadow(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "pricing-next.json")
+	content := `{
+		"version": "next",
+		"default_country": "BR",
+		"currencies": {"BRL": {"base_cost": 1000, "weight_unit_kg": 0.5, "volume_unit_cm3": 1000}},
+		"countries": {"BR": "BRL"}
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	shadow, err := LoadShadow(ShadowConfig{PricingConfigPath: path, Threshold: 0.02, Concurrency: 4})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "next", shadow.Pricing.VersionID())
+	assert.Equal(t, 0.02, shadow.Threshold)
+	assert.Equal(t, 4, shadow.Concurrency)
+}
+
+func TestLoadShadow_InvalidRateTable(t *testing.T) {
+	// Act
+	_, err := LoadShadow(ShadowConfig{PricingConfigPath: filepath.Join(t.TempDir(), "missing.json")})
+
+	// Assert
+	assert.ErrorContains(t, err, "shadow pricing")
+}
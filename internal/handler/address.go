@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/address"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/validator"
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+	"go.uber.org/zap"
+)
+
+// AddressResponse is the address of a zipcode and whether we deliver there
+type AddressResponse struct {
+	address.Address
+	Deliverable bool `json:"deliverable"`
+}
+
+// AddressHandler validates zipcodes before checkout asks for a quote
+type AddressHandler struct {
+	provider address.Provider
+	cfg      address.Config
+	logger   *zap.Logger
+}
+
+// NewAddressHandler creates a new address handler instance
+func NewAddressHandler(provider address.Provider, cfg address.Config, logger *zap.Logger) *AddressHandler {
+	return &AddressHandler{
+		provider: provider,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+// GetZipcode handles GET /zipcodes/{zipcode} requests
+func (h *AddressHandler) GetZipcode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	value := chi.URLParam(r, "zipcode")
+	if err := validator.ValidateZipcode(value, "zipcode"); err != nil {
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if len(zipcode.Strip(value)) != validator.ZipcodeLength {
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": "zipcode must have 8 digits"})
+		return
+	}
+
+	found, err := h.provider.LookupZipcode(ctx, value)
+	if errors.Is(err, address.ErrZipcodeNotFound) {
+		writeJSON(ctx, h.logger, w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro na consulta de CEP", err)
+		writeJSON(ctx, h.logger, w, http.StatusBadGateway, map[string]string{"error": "address lookup unavailable"})
+		return
+	}
+
+	writeJSON(ctx, h.logger, w, http.StatusOK, AddressResponse{
+		Address:     *found,
+		Deliverable: h.cfg.Deliverable(found.Zipcode),
+	})
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/address"
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// staticAddresses is an address.Provider backed by a map; lookups of "00000000" fail
+type staticAddresses map[string]address.Address
+
+func (s staticAddresses) LookupZipcode(ctx context.Context, value string) (*address.Address, error) {
+	normalized := zipcode.Normalize(value)
+	if normalized == "00000000" {
+		return nil, errors.New("connection refused")
+	}
+	found, ok := s[normalized]
+	if !ok {
+		return nil, address.ErrZipcodeNotFound
+	}
+	return &found, nil
+}
+
+func serveZipcode(t *testing.T, h *AddressHandler, zipcode string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := chi.NewRouter()
+	r.Get("/zipcodes/{zipcode}", h.GetZipcode)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/zipcodes/"+zipcode, nil))
+	return w
+}
+
+func TestGetZipcode(t *testing.T) {
+	// Arrange
+	provider := staticAddresses{
+		"01310100": {Zipcode: "01310100", Street: "Avenida Paulista", City: "São Paulo", State: "SP"},
+		"69900000": {Zipcode: "69900000", City: "Rio Branco", State: "AC"},
+	}
+	handler := NewAddressHandler(provider, address.Config{UnservedZipcodePrefixes: []string{"699"}}, zaptest.NewLogger(t))
+
+	tests := []struct {
+		name            string
+		zipcode         string
+		wantCity        string
+		wantDeliverable bool
+	}{
+		{"served zipcode", "01310-100", "São Paulo", true},
+		{"unserved zipcode", "69900000", "Rio Branco", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			w := serveZipcode(t, handler, tt.zipcode)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+			var response AddressResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantCity, response.City)
+			assert.Equal(t, tt.wantDeliverable, response.Deliverable)
+		})
+	}
+}
+
+func TestGetZipcode_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		zipcode    string
+		wantStatus int
+		wantErr    string
+	}{
+		{"invalid zipcode", "abc", http.StatusBadRequest, "valid zipcode"},
+		{"partial zipcode", "01310", http.StatusBadRequest, "8 digits"},
+		{"not found", "99999999", http.StatusNotFound, "zipcode not found"},
+		{"provider failure", "00000000", http.StatusBadGateway, "address lookup unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAddressHandler(staticAddresses{}, address.Config{}, zaptest.NewLogger(t))
+
+			// Act
+			w := serveZipcode(t, handler, tt.zipcode)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var errorResponse map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+			assert.Contains(t, errorResponse["error"], tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/pricingreload"
+	"github.com/rbonfanti/shipping-calculator/internal/usage"
+	"go.uber.org/zap"
+)
+
+// maxUsageDays is the longest period of a usage report
+const maxUsageDays = 366
+
+// PricingReloader reloads the pricing configuration, updates its fuel surcharge index and lists
+// the versions put in force
+type PricingReloader interface {
+	Reload(ctx context.Context, source, actor string) (pricingreload.Revision, bool, error)
+	SetFuelSurcharge(ctx context.Context, fuel pricing.FuelSurcharge, actor string) (pricingreload.Revision, bool, error)
+	History() []pricingreload.Revision
+}
+
+// PricingReloadResponse is the version in force after a reload and whether the reload changed it
+type PricingReloadResponse struct {
+	Changed  bool                   `json:"changed"`
+	Revision pricingreload.Revision `json:"revision"`
+}
+
+// PricingVersionsResponse lists the last pricing configuration versions, the one in force first
+type PricingVersionsResponse struct {
+	Versions []pricingreload.Revision `json:"versions"`
+}
+
+// UsageReporter reports the daily quote usage of the tenants and their monthly quotas
+type UsageReporter interface {
+	Daily(ctx context.Context, tenantID string, from, to time.Time) ([]model.UsageRecord, error)
+	Quotas(ctx context.Context, tenantID string) ([]usage.Quota, error)
+}
+
+// UsageResponse is the daily quote usage of a period and the monthly quotas of the tenants
+type UsageResponse struct {
+	From   string              `json:"from"`
+	To     string              `json:"to"`
+	Usage  []model.UsageRecord `json:"usage"`
+	Quotas []usage.Quota       `json:"quotas"`
+}
+
+// AdminHandler serves the admin API, authenticated by middleware.RequireAdmin
+type AdminHandler struct {
+	reloader PricingReloader
+	usage    UsageReporter
+	logger   *zap.Logger
+}
+
+// NewAdminHandler creates a new admin handler instance
+func NewAdminHandler(reloader PricingReloader, usage UsageReporter, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		reloader: reloader,
+		usage:    usage,
+		logger:   logger,
+	}
+}
+
+// ReloadPricing handles POST /admin/pricing/reload requests, putting the pricing configuration
+// file in force on behalf of the authenticated actor
+func (h *AdminHandler) ReloadPricing(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	actor := middleware.AdminActor(ctx)
+
+	revision, changed, err := h.reloader.Reload(ctx, pricingreload.SourceAdmin, actor)
+	switch {
+	case errors.Is(err, pricingreload.ErrNoConfigFile):
+		writeJSON(ctx, h.logger, w, http.StatusConflict, map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		logger.LogError(h.logger, ctx, "Erro ao recarregar as tarifas", err, zap.String("ator", actor))
+		writeJSON(ctx, h.logger, w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(ctx, h.logger, w, http.StatusOK, PricingReloadResponse{Changed: changed, Revision: revision})
+}
+
+// SetFuelSurcharge handles PUT /admin/pricing/fuel-surcharge requests, putting the weekly fuel
+// surcharge index in the body in force on behalf of the authenticated actor
+func (h *AdminHandler) SetFuelSurcharge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	actor := middleware.AdminActor(ctx)
+
+	var fuel pricing.FuelSurcharge
+	if err := decodeJSON(r, &fuel); err != nil {
+		writeJSON(ctx, h.logger, w, invalidBodyStatus(err), invalidBody(err))
+		return
+	}
+	if err := fuel.Validate(); err != nil {
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	revision, changed, err := h.reloader.SetFuelSurcharge(ctx, fuel, actor)
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao atualizar o acréscimo de combustível", err, zap.String("ator", actor))
+		writeJSON(ctx, h.logger, w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(ctx, h.logger, w, http.StatusOK, PricingReloadResponse{Changed: changed, Revision: revision})
+}
+
+// GetPricingVersions handles GET /admin/pricing/versions requests
+func (h *AdminHandler) GetPricingVersions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(r.Context(), h.logger, w, http.StatusOK, PricingVersionsResponse{Versions: h.reloader.History()})
+}
+
+// GetUsage handles GET /admin/usage requests. The optional "from" and "to" query parameters
+// (YYYY-MM-DD, inclusive) select the days, by default from the start of the current month to
+// today, and "tenant" filters by tenant
+func (h *AdminHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	tenantID := query.Get("tenant")
+
+	today := time.Now().UTC()
+	from, err := parseDay(query.Get("from"), time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": "invalid from: must be a YYYY-MM-DD date"})
+		return
+	}
+	to, err := parseDay(query.Get("to"), today)
+	if err != nil {
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": "invalid to: must be a YYYY-MM-DD date"})
+		return
+	}
+	if to.Before(from) || to.Sub(from) >= maxUsageDays*24*time.Hour {
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("to must not be before from, and the period at most %d days", maxUsageDays)})
+		return
+	}
+
+	records, err := h.usage.Daily(ctx, tenantID, from, to)
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao carregar o uso dos tenants", err)
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to load usage"})
+		return
+	}
+	quotas, err := h.usage.Quotas(ctx, tenantID)
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao carregar as cotas dos tenants", err)
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to load usage"})
+		return
+	}
+	writeJSON(ctx, h.logger, w, http.StatusOK, UsageResponse{
+		From:   from.Format(model.DayLayout),
+		To:     to.Format(model.DayLayout),
+		Usage:  records,
+		Quotas: quotas,
+	})
+}
+
+// parseDay parses a YYYY-MM-DD day, returning fallback for an empty value
+func parseDay(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return time.Parse(model.DayLayout, value)
+}
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/pricingreload"
+	"github.com/rbonfanti/shipping-calculator/internal/usage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// stubReloader is a PricingReloader returning a fixed result and recording the reload requests
+type stubReloader struct {
+	revision pricingreload.Revision
+	changed  bool
+	err      error
+	history  []pricingreload.Revision
+
+	source, actor string
+	fuel          pricing.FuelSurcharge
+}
+
+func (s *stubReloader) Reload(ctx context.Context, source, actor string) (pricingreload.Revision, bool, error) {
+	s.source, s.actor = source, actor
+	return s.revision, s.changed, s.err
+}
+
+func (s *stubReloader) SetFuelSurcharge(ctx context.Context, fuel pricing.FuelSurcharge, actor string) (pricingreload.Revision, bool, error) {
+	s.source, s.actor, s.fuel = pricingreload.SourceFuelSurcharge, actor, fuel
+	return s.revision, s.changed, s.err
+}
+
+func (s *stubReloader) History() []pricingreload.Revision {
+	return s.history
+}
+
+// stubUsage is a UsageReporter returning fixed records and quotas, failing with err when set, and
+// recording the requested period
+type stubUsage struct {
+	records []model.UsageRecord
+	quotas  []usage.Quota
+	err     error
+
+	tenantID string
+	from, to time.Time
+}
+
+func (s *stubUsage) Daily(ctx context.Context, tenantID string, from, to time.Time) ([]model.UsageRecord, error) {
+	s.tenantID, s.from, s.to = tenantID, from, to
+	return s.records, s.err
+}
+
+func (s *stubUsage) Quotas(ctx context.Context, tenantID string) ([]usage.Quota, error) {
+	return s.quotas, s.err
+}
+
+var testRevision = pricingreload.Revision{
+	Version:         "2025.04",
+	PreviousVersion: "2025.03",
+	Source:          pricingreload.SourceAdmin,
+	Actor:           "alice",
+	Timestamp:       time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC),
+	Changes:         []pricing.Change{{Path: "currencies.BRL.base_cost", Old: float64(1000), New: float64(1200)}},
+}
+
+func TestReloadPricing(t *testing.T) {
+	tests := []struct {
+		name       string
+		reloader   *stubReloader
+		wantStatus int
+		wantBody   string
+	}{
+		{"changed", &stubReloader{revision: testRevision, changed: true}, http.StatusOK, `"changed":true`},
+		{"unchanged", &stubReloader{revision: testRevision}, http.StatusOK, `"changed":false`},
+		{"built-in configuration", &stubReloader{err: pricingreload.ErrNoConfigFile}, http.StatusConflict, "PRICING_CONFIG_PATH is not set"},
+		{"invalid file", &stubReloader{err: errors.New("invalid pricing config: at least one currency is required")}, http.StatusUnprocessableEntity, "at least one currency is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAdminHandler(tt.reloader, &stubUsage{}, zaptest.NewLogger(t))
+			routed := middleware.RequireAdmin(middleware.AdminConfig{Tokens: []middleware.AdminToken{{Actor: "alice", Token: "s3cret"}}})(http.HandlerFunc(handler.ReloadPricing))
+			req := httptest.NewRequest(http.MethodPost, "/admin/pricing/reload", nil)
+			req.Header.Set("Authorization", "Bearer s3cret")
+			w := httptest.NewRecorder()
+
+			// Act
+			routed.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+			assert.Equal(t, pricingreload.SourceAdmin, tt.reloader.source)
+			assert.Equal(t, "alice", tt.reloader.actor)
+		})
+	}
+}
+
+func TestSetFuelSurcharge(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		reloader   *stubReloader
+		wantStatus int
+		wantBody   string
+		wantFuel   pricing.FuelSurcharge
+	}{
+		{"updated", `{"rate": 0.12, "effective_date": "2025-04-07"}`, &stubReloader{revision: testRevision, changed: true}, http.StatusOK, `"changed":true`, pricing.FuelSurcharge{Rate: 0.12, EffectiveDate: "2025-04-07"}},
+		{"invalid body", `{"rate":`, &stubReloader{}, http.StatusBadRequest, "invalid request body", pricing.FuelSurcharge{}},
+		{"percentage as a whole number", `{"rate": 12, "effective_date": "2025-04-07"}`, &stubReloader{}, http.StatusBadRequest, "rate must be between 0 and 1", pricing.FuelSurcharge{}},
+		{"missing effective date", `{"rate": 0.12}`, &stubReloader{}, http.StatusBadRequest, "effective_date must be a YYYY-MM-DD date", pricing.FuelSurcharge{}},
+		{"rejected", `{"rate": 0.12, "effective_date": "2025-04-07"}`, &stubReloader{err: errors.New("invalid pricing config")}, http.StatusUnprocessableEntity, "invalid pricing config", pricing.FuelSurcharge{Rate: 0.12, EffectiveDate: "2025-04-07"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAdminHandler(tt.reloader, &stubUsage{}, zaptest.NewLogger(t))
+			routed := middleware.RequireAdmin(middleware.AdminConfig{Tokens: []middleware.AdminToken{{Actor: "alice", Token: "s3cret"}}})(http.HandlerFunc(handler.SetFuelSurcharge))
+			req := httptest.NewRequest(http.MethodPut, "/admin/pricing/fuel-surcharge", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer s3cret")
+			w := httptest.NewRecorder()
+
+			// Act
+			routed.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.wantFuel, tt.reloader.fuel)
+		})
+	}
+}
+
+func TestGetPricingVersions(t *testing.T) {
+	// Arrange
+	startup := pricingreload.Revision{Version: "2025.03", Source: pricingreload.SourceStartup, Timestamp: testRevision.Timestamp.Add(-time.Hour), Changes: []pricing.Change{}}
+	handler := NewAdminHandler(&stubReloader{history: []pricingreload.Revision{testRevision, startup}}, &stubUsage{}, zaptest.NewLogger(t))
+	req := httptest.NewRequest(http.MethodGet, "/admin/pricing/versions", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetPricingVersions(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response PricingVersionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []pricingreload.Revision{testRevision, startup}, response.Versions)
+}
+
+func TestGetUsage(t *testing.T) {
+	// Arrange
+	records := []model.UsageRecord{{Day: "2025-03-10", Tenant: "acme", APIKey: "sha256:abc", Quotes: 42}}
+	quotas := []usage.Quota{{Tenant: "acme", MonthlyQuota: 1000, Used: 42, ResetsAt: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)}}
+	reporter := &stubUsage{records: records, quotas: quotas}
+	handler := NewAdminHandler(&stubReloader{}, reporter, zaptest.NewLogger(t))
+	req := httptest.NewRequest(http.MethodGet, "/admin/usage?from=2025-03-01&to=2025-03-31&tenant=acme", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetUsage(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response UsageResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, UsageResponse{From: "2025-03-01", To: "2025-03-31", Usage: records, Quotas: quotas}, response)
+	assert.Equal(t, "acme", reporter.tenantID)
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), reporter.from)
+	assert.Equal(t, time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), reporter.to)
+}
+
+func TestGetUsage_DefaultPeriod(t *testing.T) {
+	// Arrange
+	reporter := &stubUsage{}
+	handler := NewAdminHandler(&stubReloader{}, reporter, zaptest.NewLogger(t))
+	w := httptest.NewRecorder()
+	today := time.Now().UTC()
+
+	// Act
+	handler.GetUsage(w, httptest.NewRequest(http.MethodGet, "/admin/usage", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "", reporter.tenantID)
+	assert.Equal(t, time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC), reporter.from)
+	assert.Equal(t, today.Format(model.DayLayout), reporter.to.Format(model.DayLayout))
+}
+
+func TestGetUsage_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{"invalid from", "from=03/01/2025", nil, http.StatusBadRequest, "invalid from: must be a YYYY-MM-DD date"},
+		{"invalid to", "from=2025-03-01&to=tomorrow", nil, http.StatusBadRequest, "invalid to: must be a YYYY-MM-DD date"},
+		{"to before from", "from=2025-03-10&to=2025-03-01", nil, http.StatusBadRequest, "to must not be before from, and the period at most 366 days"},
+		{"period too long", "from=2023-01-01&to=2025-03-01", nil, http.StatusBadRequest, "to must not be before from, and the period at most 366 days"},
+		{"usage store unavailable", "from=2025-03-01&to=2025-03-31", errors.New("storage unavailable"), http.StatusInternalServerError, "failed to load usage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAdminHandler(&stubReloader{}, &stubUsage{err: tt.err}, zaptest.NewLogger(t))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.GetUsage(w, httptest.NewRequest(http.MethodGet, "/admin/usage?"+tt.query, nil))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var body map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantError, body["error"])
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/bulk"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/usage"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+	"go.uber.org/zap"
+)
+
+// bulkFormField is the multipart field holding the CSV file
+const bulkFormField = "file"
+
+// BulkHandler handles bulk quoting requests
+type BulkHandler struct {
+	processor *bulk.Processor
+	cfg       bulk.Config
+	logger    *zap.Logger
+}
+
+// NewBulkHandler creates a new bulk handler instance
+func NewBulkHandler(calculator bulk.Calculator, cfg bulk.Config, metrics *telemetry.Metrics, logger *zap.Logger) *BulkHandler {
+	return &BulkHandler{
+		processor: bulk.NewProcessor(calculator, cfg, metrics),
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// CalculateCSV handles POST /calculate/csv requests. The shipments are uploaded as a multipart
+// "file" field and the quotes are streamed back as CSV, one row per input row. Bodies sent as
+// application/msgpack are an array of shipments instead, answered with an array of quotes
+func (h *BulkHandler) CalculateCSV(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	r.Body = http.MaxBytesReader(w, r.Body, h.cfg.MaxUploadBytes)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if strings.EqualFold(mediaType, middleware.ContentTypeMsgpack) {
+		h.calculateMsgpack(w, r)
+		return
+	}
+
+	file, err := h.openFile(r)
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro no cálculo em lote: arquivo inválido", err)
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	out := &headerWriter{ResponseWriter: w, contentType: "text/csv; charset=utf-8", filename: "quotes.csv"}
+	summary, err := h.processor.Process(ctx, file, out)
+
+	var headerErr *bulk.HeaderError
+	switch {
+	case errors.As(err, &headerErr):
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": headerErr.Error()})
+		return
+	case err != nil:
+		// The response is already streaming, so the failure can only be logged
+		logger.LogError(h.logger, ctx, "Erro no cálculo em lote", err)
+	}
+	h.logSummary(ctx, summary)
+}
+
+// calculateMsgpack quotes a msgpack array of shipments, streaming back a msgpack array of quotes
+func (h *BulkHandler) calculateMsgpack(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	out := &headerWriter{ResponseWriter: w, contentType: middleware.ContentTypeMsgpack}
+	summary, err := h.processor.ProcessMsgpack(ctx, r.Body, out)
+
+	var bodyErr *bulk.BodyError
+	switch {
+	case errors.As(err, &bodyErr):
+		logger.LogError(h.logger, ctx, "Erro no cálculo em lote: corpo inválido", err)
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": bodyErr.Error()})
+		return
+	case err != nil:
+		// The response is already streaming, so the failure can only be logged
+		logger.LogError(h.logger, ctx, "Erro no cálculo em lote", err)
+	}
+	h.logSummary(ctx, summary)
+}
+
+// logSummary logs the outcome of a bulk request and reports its quotes for the usage of the tenant
+func (h *BulkHandler) logSummary(ctx context.Context, summary bulk.Summary) {
+	usage.SetQuotes(ctx, summary.Succeeded)
+	h.logger.Info("Cálculo em lote concluído",
+		zap.Int("linhas", summary.Rows),
+		zap.Int("sucesso", summary.Succeeded),
+		zap.Int("falhas", summary.Failed),
+	)
+}
+
+// openFile returns the reader of the multipart file field without buffering the upload
+func (h *BulkHandler) openFile(r *http.Request) (io.Reader, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, errors.New("request must be multipart/form-data")
+	}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New(`multipart field "file" is required`)
+		}
+		if err != nil {
+			return nil, errors.New("invalid multipart body")
+		}
+		if part.FormName() == bulkFormField {
+			return part, nil
+		}
+	}
+}
+
+// headerWriter sets the response headers on the first write and flushes every write to the
+// client; filename, when set, makes the response a download
+type headerWriter struct {
+	http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (w *headerWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.Header().Set("Content-Type", w.contentType)
+		if w.filename != "" {
+			w.Header().Set("Content-Disposition", `attachment; filename="`+w.filename+`"`)
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(p)
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
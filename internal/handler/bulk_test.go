@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/bulk"
+	"github.com/rbonfanti/shipping-calculator/internal/msgpack"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/usage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// newMultipartRequest builds a POST /calculate/csv request with the given form field
+func newMultipartRequest(t *testing.T, field, content string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, "shipments.csv")
+	assert.NoError(t, err)
+	_, _ = part.Write([]byte(content))
+	assert.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/calculate/csv", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestCalculateCSV(t *testing.T) {
+	// Arrange
+	handler := NewBulkHandler(service.NewShippingService(), bulk.DefaultConfig(), nil, zaptest.NewLogger(t))
+	req := newMultipartRequest(t, "file",
+		"origin_zipcode,destination_zipcode,weight,length,width,height\n12345678,12345678,1,10,10,10\n")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.CalculateCSV(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "12345678,12345678,1,10,10,10,BRL,1250.00,2 dias,"+pricing.DefaultConfig().VersionID()+",", lines[1])
+}
+
+func TestCalculateCSV_ReportsQuotes(t *testing.T) {
+	// Arrange
+	handler := NewBulkHandler(service.NewShippingService(), bulk.DefaultConfig(), nil, zaptest.NewLogger(t))
+	req := newMultipartRequest(t, "file", "origin_zipcode,destination_zipcode,weight,length,width,height\n"+
+		"12345678,12345678,1,10,10,10\n12345678,12345678,2,10,10,10\n12345678,12345678,-1,10,10,10\n")
+	ctx, quotes := usage.WithQuoteCount(req.Context())
+
+	// Act
+	handler.CalculateCSV(httptest.NewRecorder(), req.WithContext(ctx))
+
+	// Assert
+	assert.Equal(t, int64(2), quotes.Quotes(), "only the priced rows are counted")
+}
+
+func TestCalculateCSV_Msgpack(t *testing.T) {
+	// Arrange
+	handler := NewBulkHandler(service.NewShippingService(), bulk.DefaultConfig(), nil, zaptest.NewLogger(t))
+	var body bytes.Buffer
+	encoder := msgpack.NewEncoder(&body)
+	assert.NoError(t, encoder.Encode([]any{map[string]any{
+		"origin_zipcode": "12345678", "destination_zipcode": "12345678",
+		"weight": 1, "length": 10, "width": 10, "height": 10,
+	}}))
+	assert.NoError(t, encoder.Flush())
+	req := httptest.NewRequest(http.MethodPost, "/calculate/csv", &body)
+	req.Header.Set("Content-Type", "application/msgpack")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.CalculateCSV(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/msgpack", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+	value, err := msgpack.NewDecoder(w.Body).Decode()
+	assert.NoError(t, err)
+	quotes, _ := value.([]any)
+	assert.Len(t, quotes, 1)
+	quote, _ := quotes[0].(map[string]any)
+	assert.Equal(t, "BRL", quote["quote_currency"])
+	assert.Equal(t, 1250.0, quote["shipping_cost"])
+	assert.Equal(t, pricing.DefaultConfig().VersionID(), quote["pricing_version"])
+}
+
+func TestCalculateCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		request func(t *testing.T) *http.Request
+		wantErr string
+	}{
+		{
+			name: "not multipart",
+			request: func(t *testing.T) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/calculate/csv", strings.NewReader("a,b"))
+				req.Header.Set("Content-Type", "text/csv")
+				return req
+			},
+			wantErr: "request must be multipart/form-data",
+		},
+		{
+			name:    "missing file field",
+			request: func(t *testing.T) *http.Request { return newMultipartRequest(t, "other", "x") },
+			wantErr: `multipart field "file" is required`,
+		},
+		{
+			name:    "invalid header",
+			request: func(t *testing.T) *http.Request { return newMultipartRequest(t, "file", "origin_zipcode\n") },
+			wantErr: "invalid CSV header",
+		},
+		{
+			name: "msgpack body not an array",
+			request: func(t *testing.T) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/calculate/csv", strings.NewReader("\xc0"))
+				req.Header.Set("Content-Type", "application/msgpack")
+				return req
+			},
+			wantErr: "invalid msgpack body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewBulkHandler(service.NewShippingService(), bulk.DefaultConfig(), nil, zaptest.NewLogger(t))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.CalculateCSV(w, tt.request(t))
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var errorResponse map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+			assert.Contains(t, errorResponse["error"], tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/chaos"
+	"github.com/rbonfanti/shipping-calculator/internal/middleware"
+	"go.uber.org/zap"
+)
+
+// FaultInjector sets and clears the faults injected into the providers and the quote store
+type FaultInjector interface {
+	Set(target string, fault chaos.Fault) error
+	Clear(target string) bool
+	Faults() []chaos.TargetFault
+}
+
+// ChaosResponse lists the faults set, sorted by target
+type ChaosResponse struct {
+	Faults []chaos.TargetFault `json:"faults"`
+}
+
+// ChaosHandler serves the fault injection routes of the admin API
+type ChaosHandler struct {
+	injector FaultInjector
+	logger   *zap.Logger
+}
+
+// NewChaosHandler creates a new chaos handler instance
+func NewChaosHandler(injector FaultInjector, logger *zap.Logger) *ChaosHandler {
+	return &ChaosHandler{
+		injector: injector,
+		logger:   logger,
+	}
+}
+
+// GetFaults handles GET /admin/chaos requests
+func (h *ChaosHandler) GetFaults(w http.ResponseWriter, r *http.Request) {
+	writeJSON(r.Context(), h.logger, w, http.StatusOK, ChaosResponse{Faults: h.injector.Faults()})
+}
+
+// SetFault handles PUT /admin/chaos/{target} requests, replacing the fault of the target
+func (h *ChaosHandler) SetFault(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	target := chi.URLParam(r, "target")
+
+	var fault chaos.Fault
+	if err := decodeJSON(r, &fault); err != nil {
+		writeJSON(ctx, h.logger, w, invalidBodyStatus(err), invalidBody(err))
+		return
+	}
+	if err := h.injector.Set(target, fault); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, chaos.ErrUnknownTarget) {
+			status = http.StatusNotFound
+		}
+		writeJSON(ctx, h.logger, w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	h.logger.Warn("Falha injetada configurada",
+		zap.String("alvo", target),
+		zap.Int64("latencia_ms", fault.LatencyMs),
+		zap.Float64("taxa_erro", fault.ErrorRate),
+		zap.String("ator", middleware.AdminActor(ctx)),
+	)
+	writeJSON(ctx, h.logger, w, http.StatusOK, chaos.TargetFault{Target: target, Fault: fault})
+}
+
+// ClearFault handles DELETE /admin/chaos/{target} requests, returning 204 No Content once the
+// fault of the target is removed
+func (h *ChaosHandler) ClearFault(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	target := chi.URLParam(r, "target")
+
+	if !h.injector.Clear(target) {
+		writeJSON(ctx, h.logger, w, http.StatusNotFound, map[string]string{"error": "no fault set on " + target})
+		return
+	}
+	h.logger.Info("Falha injetada removida",
+		zap.String("alvo", target),
+		zap.String("ator", middleware.AdminActor(ctx)),
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/chaos"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func chaosRouter(h *ChaosHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/admin/chaos", h.GetFaults)
+	r.Put("/admin/chaos/{target}", h.SetFault)
+	r.Delete("/admin/chaos/{target}", h.ClearFault)
+	return r
+}
+
+func TestSetChaosFault(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		wantStatus int
+		wantFaults []chaos.TargetFault
+	}{
+		{"set", chaos.TargetCarrierRates, `{"latency_ms":2000,"error_rate":0.25}`, http.StatusOK, []chaos.TargetFault{{Target: chaos.TargetCarrierRates, Fault: chaos.Fault{LatencyMs: 2000, ErrorRate: 0.25}}}},
+		{"invalid body", chaos.TargetCarrierRates, `{"error_rate":`, http.StatusBadRequest, []chaos.TargetFault{}},
+		{"invalid fault", chaos.TargetCarrierRates, `{"error_rate":2}`, http.StatusBadRequest, []chaos.TargetFault{}},
+		{"unknown target", "database", `{"error_rate":1}`, http.StatusNotFound, []chaos.TargetFault{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			injector := chaos.NewInjector()
+			router := chaosRouter(NewChaosHandler(injector, zaptest.NewLogger(t)))
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/chaos/"+tt.target, strings.NewReader(tt.body)))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantFaults, injector.Faults())
+		})
+	}
+}
+
+func TestGetChaosFaults(t *testing.T) {
+	// Arrange
+	injector := chaos.NewInjector()
+	assert.NoError(t, injector.Set(chaos.TargetQuoteStore, chaos.Fault{ErrorRate: 0.5}))
+	router := chaosRouter(NewChaosHandler(injector, zaptest.NewLogger(t)))
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/chaos", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response ChaosResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []chaos.TargetFault{{Target: chaos.TargetQuoteStore, Fault: chaos.Fault{ErrorRate: 0.5}}}, response.Faults)
+}
+
+func TestClearChaosFault(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+	}{
+		{"cleared", chaos.TargetLabelProvider, http.StatusNoContent},
+		{"not set", chaos.TargetTrackingProvider, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			injector := chaos.NewInjector()
+			assert.NoError(t, injector.Set(chaos.TargetLabelProvider, chaos.Fault{ErrorRate: 1}))
+			router := chaosRouter(NewChaosHandler(injector, zaptest.NewLogger(t)))
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/chaos/"+tt.target, nil))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/rbonfanti/shipping-calculator/internal/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/testsupport"
+	"github.com/rbonfanti/shipping-calculator/pkg/quotetoken"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// contractV1Dir holds the v1 contract pairs: each <case>.request.json is sent to POST
+// /v1/calculate and the response compared with <case>.response.golden.json
+const contractV1Dir = "testdata/contract/v1"
+
+// contractHeaders are the response headers compared by the contract tests
+var contractHeaders = []string{"Content-Type", "Deprecation", "Link", "Vary"}
+
+// contractRequest is a request of the contract tests. A JSON string body is sent as is, e.g. a
+// form or XML body; any other body is sent as JSON
+type contractRequest struct {
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body"`
+}
+
+// contractResponse is a response of the contract tests; JSON bodies are kept as sent, other
+// bodies as a JSON string
+type contractResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    any               `json:"body"`
+}
+
+// newContractServer serves the v1 quote route as the API does, with the default pricing, a fixed
+// clock and seeded quote IDs, so that the responses are reproducible
+func newContractServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	clock := determinism.FixedClock{Time: time.Date(2025, 3, 7, 13, 0, 0, 0, time.UTC)}
+	signer, err := quotetoken.NewSigner([]quotetoken.Key{{ID: "contract", Secret: []byte("contract-tests-secret-of-32-bytes")}})
+	assert.NoError(t, err)
+	shippingHandler := NewShippingHandler(service.NewShippingService(service.WithClock(clock)), repository.NewMemoryQuoteRepository(),
+		repository.QuoteConfig{TTL: 30 * time.Minute}, nil, signer, nil, nil, zaptest.NewLogger(t)).
+		WithDeterminism(clock, determinism.NewSeededIDGenerator(1))
+
+	r := chi.NewRouter()
+	r.With(middleware.Deprecated(middleware.DeprecationConfig{}, "/v2/calculate")).
+		Method(http.MethodPost, "/v1/calculate", shippingHandler.CalculateV1())
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestCalculateV1_Contract replays the golden v1 requests against the server, so that refactors
+// cannot change what v1 clients see. Run with UPDATE_GOLDEN=true after an intended change
+func TestCalculateV1_Contract(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(contractV1Dir, "*.request.json"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".request.json")
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			server := newContractServer(t)
+			req := newContractRequest(t, server.URL+"/v1/calculate", path)
+
+			// Act
+			resp, err := server.Client().Do(req)
+
+			// Assert
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			testsupport.AssertGolden(t, filepath.Join(contractV1Dir, name+".response.golden.json"), encodeContractResponse(t, resp))
+		})
+	}
+}
+
+// newContractRequest builds the request described by the file at path
+func newContractRequest(t *testing.T, url, path string) *http.Request {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var spec contractRequest
+	assert.NoError(t, json.Unmarshal(data, &spec))
+
+	body := []byte(spec.Body)
+	var text string
+	if json.Unmarshal(spec.Body, &text) == nil {
+		body = []byte(text)
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	assert.NoError(t, err)
+	for name, value := range spec.Headers {
+		req.Header.Set(name, value)
+	}
+	return req
+}
+
+// encodeContractResponse encodes the status, the contract headers and the body of a response
+func encodeContractResponse(t *testing.T, resp *http.Response) []byte {
+	t.Helper()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	out := contractResponse{Status: resp.StatusCode, Headers: map[string]string{}}
+	for _, name := range contractHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			out.Headers[name] = value
+		}
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == middleware.ContentTypeJSON && json.Valid(body) {
+		out.Body = json.RawMessage(body)
+	} else {
+		out.Body = string(body)
+	}
+
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	assert.NoError(t, encoder.Encode(out))
+	return data.Bytes()
+}
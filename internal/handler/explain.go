@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/mapper"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"go.uber.org/zap"
+)
+
+// QuoteExplainer explains step by step how a quote is priced
+type QuoteExplainer interface {
+	Explain(ctx context.Context, req *model.CalculateShippingRequest) (*model.QuoteExplanation, error)
+}
+
+// ExplainHandler serves pricing explanations for customer support
+type ExplainHandler struct {
+	explainer QuoteExplainer
+	logger    *zap.Logger
+}
+
+// NewExplainHandler creates a new explain handler instance
+func NewExplainHandler(explainer QuoteExplainer, logger *zap.Logger) *ExplainHandler {
+	return &ExplainHandler{
+		explainer: explainer,
+		logger:    logger,
+	}
+}
+
+// ExplainShipping handles POST /calculate/explain requests: the body is the one of POST
+// /calculate, and the response explains the inputs, route and charges of the quote. Like
+// /calculate/preview, the quote is not persisted nor published
+func (h *ExplainHandler) ExplainShipping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body v1.CalculateShippingRequest
+	if err := decodeJSON(r, &body); err != nil {
+		logger.LogError(h.logger, ctx, "Erro na explicação de cotação: falha ao decodificar requisição", err)
+		writeJSON(ctx, h.logger, w, invalidBodyStatus(err), invalidBody(err))
+		return
+	}
+	req := mapper.RequestFromV1(&body)
+
+	explanation, err := h.explainer.Explain(ctx, req)
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro na explicação de cotação", err)
+		writeJSON(ctx, h.logger, w, calculationStatus(err), calculationError(err))
+		return
+	}
+
+	logger.LogRequest(h.logger, ctx, "Cotação explicada",
+		zap.String("origem", req.OriginZipcode),
+		zap.String("destino", req.DestinationZipcode),
+		zap.String("serviço", explanation.Service),
+		zap.String("estratégia", explanation.Strategy),
+		zap.Float64("custo_envio", explanation.Quote.ShippingCost.Minor()),
+	)
+	writeJSON(ctx, h.logger, w, http.StatusOK, mapper.ExplanationToV1(explanation))
+}
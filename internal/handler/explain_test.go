@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestExplainShipping(t *testing.T) {
+	// Arrange
+	handler := NewExplainHandler(service.NewShippingService(), zaptest.NewLogger(t))
+
+	bodyBytes, _ := json.Marshal(v1.CalculateShippingRequest{
+		OriginZipcode:      "12345-678",
+		DestinationZipcode: "12345678",
+		Weight:             1.0,
+		Dimensions:         v1.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+	})
+	req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate/explain", bytes.NewReader(bodyBytes)))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ExplainShipping(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var explanation v1.QuoteExplanation
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &explanation))
+	if assert.NotNil(t, explanation.Quote) {
+		assert.Equal(t, 1250.0, explanation.Quote.ShippingCost)
+		assert.Empty(t, explanation.Quote.QuoteID, "explained quotes are not persisted")
+	}
+	assert.Equal(t, "12345678", explanation.Inputs.OriginZipcode)
+	assert.Equal(t, "local", explanation.Route.Zone)
+	assert.Equal(t, "standard", explanation.Service)
+	assert.Equal(t, "formula", explanation.Strategy)
+
+	var total float64
+	for _, charge := range explanation.Charges {
+		total += charge.Amount
+	}
+	assert.Equal(t, 1250.0, total)
+	assert.NotEmpty(t, explanation.Decisions)
+}
+
+func TestExplainShipping_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantError string
+	}{
+		{"invalid body", "{", "invalid request body"},
+		{"invalid request", `{"origin_zipcode": "123", "destination_zipcode": "87654321"}`, "invalid origin_zipcode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewExplainHandler(service.NewShippingService(), zaptest.NewLogger(t))
+			req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate/explain", bytes.NewBufferString(tt.body)))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ExplainShipping(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantError)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/strictjson"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"go.uber.org/zap"
+)
+
+// Fuzz targets of POST /calculate: the body goes through the validation middleware, the JSON
+// decoding of the handler and the calculation. Their seeds run with go test; to fuzz, run e.g.
+// go test -run '^$' -fuzz FuzzCalculateShipping_Body -fuzztime 1m ./internal/handler/
+
+// fuzzCalculateHandler is the /calculate route as the API serves it, without persistence
+func fuzzCalculateHandler() http.Handler {
+	h := NewShippingHandler(service.NewShippingService(), nil, repository.QuoteConfig{}, nil, nil, nil, nil, zap.NewNop())
+	return middleware.ValidateBody(h.RecordRejected)(http.HandlerFunc(h.CalculateShipping))
+}
+
+// checkCalculateResponse fails unless the response is a quote with a finite, non-negative cost
+// or a JSON error with a client status
+func checkCalculateResponse(t *testing.T, w *httptest.ResponseRecorder) {
+	t.Helper()
+	switch w.Code {
+	case http.StatusOK:
+		var quote v1.CalculateShippingResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &quote); err != nil {
+			t.Fatalf("quote is not valid JSON: %v: %s", err, w.Body)
+		}
+		if math.IsNaN(quote.ShippingCost) || math.IsInf(quote.ShippingCost, 0) || quote.ShippingCost < 0 {
+			t.Fatalf("invalid shipping cost %v", quote.ShippingCost)
+		}
+		for _, option := range quote.ShippingOptions {
+			if math.IsNaN(option.Cost) || math.IsInf(option.Cost, 0) || option.Cost < 0 {
+				t.Fatalf("invalid cost %v of option %s", option.Cost, option.Service)
+			}
+		}
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body["error"] == nil {
+			t.Fatalf("error response without an error message: %s", w.Body)
+		}
+	default:
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body)
+	}
+}
+
+func FuzzCalculateShipping_Body(f *testing.F) {
+	f.Add([]byte(`{"origin_zipcode":"01310-100","destination_zipcode":"04547-130","weight":2.5,"dimensions":{"length":30,"width":20,"height":15}}`), false)
+	f.Add([]byte(`{"origin_zipcode":"01310100","destination_zipcode":"20040002","weight":1e308,"dimensions":{"length":1e308,"width":1e-308,"height":24},"declared_value":1e308,"hs_code":"8471","destination_country":"US"}`), false)
+	f.Add([]byte(`{"origin_zipcode":"０１３１０１００","destination_zipcode":"٠١٣١٠١٠٠","weight":1,"dimensions":{"length":1,"width":1,"height":1}}`), true)
+	f.Add([]byte(`{"origin_zipcode":"01310\u0000100","destination_zipcode":"0131𝟘100","weight":-0,"dimensions":null}`), false)
+	f.Add([]byte(`{"weight":1,"weight_unit":"lb","dimension_unit":"in","dimensions":{"length":9007199254740993,"width":1,"height":1},"origin_zipcode":"1414","destination_zipcode":"1428"}`), true)
+	f.Add([]byte(`{"extra":`+strings.Repeat(`[`, 5000)+strings.Repeat(`]`, 5000)+`}`), true)
+	f.Add([]byte(`{"dimensions":`+strings.Repeat(`{"dimensions":`, 1000)+`{}`+strings.Repeat(`}`, 1000)+`}`), true)
+	f.Add([]byte(`[]`), false)
+	f.Add([]byte(`null`), true)
+
+	handler := fuzzCalculateHandler()
+	f.Fuzz(func(t *testing.T, body []byte, strict bool) {
+		req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader(body))
+		req = req.WithContext(strictjson.NewContext(req.Context(), strict))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		checkCalculateResponse(t, w)
+	})
+}
+
+func FuzzCalculateShipping_Fields(f *testing.F) {
+	f.Add("01310-100", "04547-130", 2.5, 30.0, 20.0, 15.0, "", "", false)
+	f.Add("1414", "1428", 1e-300, 1e300, 1e300, 1e300, "g", "mm", true)
+	f.Add("ção", "01310100", -1.0, 0.0, -3.5, 1e308, "oz", "m", false)
+	f.Add("​01310100", "0131 0100", 70000.0, 24.0, 24.0, 24.0, "t", "ft", true)
+
+	handler := fuzzCalculateHandler()
+	f.Fuzz(func(t *testing.T, origin, destination string, weight, length, width, height float64, weightUnit, dimensionUnit string, express bool) {
+		body, err := json.Marshal(v1.CalculateShippingRequest{
+			OriginZipcode:      origin,
+			DestinationZipcode: destination,
+			Weight:             weight,
+			Dimensions:         v1.PackageDimensions{Length: length, Width: width, Height: height},
+			WeightUnit:         weightUnit,
+			DimensionUnit:      dimensionUnit,
+			IsExpress:          express,
+		})
+		if err != nil {
+			// JSON cannot carry NaN or infinities
+			t.Skip()
+		}
+		req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		checkCalculateResponse(t, w)
+	})
+}
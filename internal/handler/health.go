@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rbonfanti/shipping-calculator/internal/health"
+	"go.uber.org/zap"
+)
+
+// ReadinessPath is the readiness probe path for orchestrators and load balancers
+const ReadinessPath = "/readyz"
+
+// HealthReporter reports the availability of the service dependencies
+type HealthReporter interface {
+	Report() health.Report
+}
+
+// HealthHandler serves the readiness probe from the last dependency probes
+type HealthHandler struct {
+	reporter HealthReporter
+	logger   *zap.Logger
+}
+
+// NewHealthHandler creates a new health handler instance
+func NewHealthHandler(reporter HealthReporter, logger *zap.Logger) *HealthHandler {
+	return &HealthHandler{
+		reporter: reporter,
+		logger:   logger,
+	}
+}
+
+// Readyz handles GET /readyz requests: 200 while every required dependency is up, even when
+// optional ones are down, and 503 otherwise. Dependencies are not probed on each request, and the
+// probe errors are only logged, since the probe is not authenticated
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := h.reporter.Report()
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(r.Context(), h.logger, w, status, report.Redacted())
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/health"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestReadyz(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name       string
+		probes     []health.Probe
+		wantCode   int
+		wantStatus string
+	}{
+		{"all up", []health.Probe{{Name: "redis", Required: true, Check: up}, {Name: "cep", Check: up}}, http.StatusOK, health.StatusOK},
+		{"optional down", []health.Probe{{Name: "redis", Required: true, Check: up}, {Name: "cep", Check: down}}, http.StatusOK, health.StatusDegraded},
+		{"required down", []health.Probe{{Name: "redis", Required: true, Check: down}, {Name: "cep", Check: up}}, http.StatusServiceUnavailable, health.StatusUnavailable},
+		{"no dependencies", nil, http.StatusOK, health.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			monitor := health.NewMonitor(health.Config{Interval: time.Minute, Timeout: time.Second}, nil, tt.probes...)
+			monitor.ProbeAll(context.Background())
+			handler := NewHealthHandler(monitor, zaptest.NewLogger(t))
+			req := httptest.NewRequest(http.MethodGet, ReadinessPath, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.Readyz(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+			var report health.Report
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			assert.Equal(t, tt.wantStatus, report.Status)
+			assert.Len(t, report.Dependencies, len(tt.probes))
+			assert.NotContains(t, w.Body.String(), "connection refused")
+			assert.NotContains(t, w.Body.String(), `"error"`)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"go.uber.org/zap"
+)
+
+// labelContentTypes are the media types of the label documents by format
+var labelContentTypes = map[string]string{
+	model.LabelFormatPDF: "application/pdf",
+	model.LabelFormatZPL: "application/zpl",
+}
+
+// LabelGenerator generates and serves the shipping labels of shipments
+type LabelGenerator interface {
+	Generate(ctx context.Context, shipmentID, format string) (*model.ShipmentLabel, bool, error)
+	Download(ctx context.Context, shipmentID, format string) (*model.ShipmentLabel, []byte, error)
+}
+
+// LabelHandler handles HTTP requests for shipping labels
+type LabelHandler struct {
+	labels LabelGenerator
+	logger *zap.Logger
+}
+
+// NewLabelHandler creates a new label handler instance
+func NewLabelHandler(labels LabelGenerator, logger *zap.Logger) *LabelHandler {
+	return &LabelHandler{
+		labels: labels,
+		logger: logger,
+	}
+}
+
+// GenerateLabel handles POST /shipments/{id}/label requests, returning 201 Created with the label
+// generated by the carrier, or 200 OK with the label already generated in the format
+func (h *LabelHandler) GenerateLabel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	var req model.GenerateLabelRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r, &req); err != nil {
+			writeJSON(ctx, h.logger, w, invalidBodyStatus(err), invalidBody(err))
+			return
+		}
+	}
+
+	generated, created, err := h.labels.Generate(ctx, id, req.Format)
+	if err != nil {
+		h.writeLabelError(ctx, w, id, err)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeJSON(ctx, h.logger, w, status, generated)
+}
+
+// DownloadLabel handles GET /shipments/{id}/label/{format} requests, returning the label document
+func (h *LabelHandler) DownloadLabel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	stored, content, err := h.labels.Download(ctx, id, chi.URLParam(r, "format"))
+	if err != nil {
+		h.writeLabelError(ctx, w, id, err)
+		return
+	}
+	w.Header().Set("Content-Type", labelContentTypes[stored.Format])
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Header().Set("Content-Disposition", `attachment; filename="label-`+stored.ShipmentID+`.`+stored.Format+`"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(content); err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao enviar etiqueta", err, zap.String("shipment_id", id))
+	}
+}
+
+// writeLabelError maps label errors to HTTP responses
+func (h *LabelHandler) writeLabelError(ctx context.Context, w http.ResponseWriter, id string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidLabelFormat):
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, service.ErrShipmentNotFound), errors.Is(err, service.ErrLabelNotFound):
+		writeJSON(ctx, h.logger, w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, service.ErrLabelProvider):
+		logger.LogError(h.logger, ctx, "Erro da transportadora ao gerar etiqueta", err, zap.String("shipment_id", id))
+		writeJSON(ctx, h.logger, w, http.StatusBadGateway, map[string]string{"error": service.ErrLabelProvider.Error()})
+	default:
+		logger.LogError(h.logger, ctx, "Erro ao gerar etiqueta", err, zap.String("shipment_id", id))
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to generate label"})
+	}
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// stubLabels generates labels in the requested format, reporting them as created unless existing
+// is set, or fails with err
+type stubLabels struct {
+	existing bool
+	err      error
+}
+
+func (s stubLabels) Generate(ctx context.Context, shipmentID, format string) (*model.ShipmentLabel, bool, error) {
+	if s.err != nil {
+		return nil, false, s.err
+	}
+	if format == "" {
+		format = model.LabelFormatPDF
+	}
+	return &model.ShipmentLabel{ShipmentID: shipmentID, Format: format, DownloadURL: service.LabelDownloadPath(shipmentID, format)}, !s.existing, nil
+}
+
+func (s stubLabels) Download(ctx context.Context, shipmentID, format string) (*model.ShipmentLabel, []byte, error) {
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+	return &model.ShipmentLabel{ShipmentID: shipmentID, Format: format}, []byte("^XA^XZ"), nil
+}
+
+func labelRouter(h *LabelHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/shipments/{id}/label", h.GenerateLabel)
+	r.Get("/shipments/{id}/label/{format}", h.DownloadLabel)
+	return r
+}
+
+func TestGenerateLabel(t *testing.T) {
+	tests := []struct {
+		name       string
+		labels     stubLabels
+		body       string
+		wantStatus int
+		wantFormat string
+	}{
+		{"generated with the default format", stubLabels{}, "", http.StatusCreated, model.LabelFormatPDF},
+		{"generated in the requested format", stubLabels{}, `{"format":"zpl"}`, http.StatusCreated, model.LabelFormatZPL},
+		{"already generated", stubLabels{existing: true}, `{"format":"pdf"}`, http.StatusOK, model.LabelFormatPDF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			router := labelRouter(NewLabelHandler(tt.labels, zaptest.NewLogger(t)))
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/shipments/s1/label", strings.NewReader(tt.body)))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var label model.ShipmentLabel
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &label))
+			assert.Equal(t, tt.wantFormat, label.Format)
+			assert.Equal(t, "/shipments/s1/label/"+tt.wantFormat, label.DownloadURL)
+		})
+	}
+}
+
+func TestGenerateLabel_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{"invalid body", `{"format":`, nil, http.StatusBadRequest, "invalid request body"},
+		{"invalid format", `{"format":"png"}`, fmt.Errorf("%w: %q", service.ErrInvalidLabelFormat, "png"), http.StatusBadRequest, `invalid label format: "png"`},
+		{"unknown shipment", "", service.ErrShipmentNotFound, http.StatusNotFound, "shipment not found"},
+		{"carrier failure", "", fmt.Errorf("%w: label: unexpected status 500", service.ErrLabelProvider), http.StatusBadGateway, "carrier failed to generate the label"},
+		{"storage failure", "", fmt.Errorf("failed to save label: connection refused"), http.StatusInternalServerError, "failed to generate label"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			router := labelRouter(NewLabelHandler(stubLabels{err: tt.err}, zaptest.NewLogger(t)))
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/shipments/s1/label", strings.NewReader(tt.body)))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var body map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Contains(t, body["error"], tt.wantError)
+		})
+	}
+}
+
+func TestDownloadLabel(t *testing.T) {
+	// Arrange
+	router := labelRouter(NewLabelHandler(stubLabels{}, zaptest.NewLogger(t)))
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/shipments/s1/label/zpl", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/zpl", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="label-s1.zpl"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "^XA^XZ", w.Body.String())
+}
+
+func TestDownloadLabel_NotGenerated(t *testing.T) {
+	// Arrange
+	router := labelRouter(NewLabelHandler(stubLabels{err: service.ErrLabelNotFound}, zaptest.NewLogger(t)))
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/shipments/s1/label/pdf", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"label not found"}`, w.Body.String())
+}
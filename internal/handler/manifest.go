@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"go.uber.org/zap"
+)
+
+// ManifestCloser closes the day's shipments into carrier manifests
+type ManifestCloser interface {
+	Close(ctx context.Context, req *model.CloseManifestsRequest) (*model.CloseManifestsResponse, error)
+	Get(ctx context.Context, id string) (*model.Manifest, error)
+}
+
+// ManifestHandler handles HTTP requests for carrier manifests
+type ManifestHandler struct {
+	manifests ManifestCloser
+	logger    *zap.Logger
+}
+
+// NewManifestHandler creates a new manifest handler instance
+func NewManifestHandler(manifests ManifestCloser, logger *zap.Logger) *ManifestHandler {
+	return &ManifestHandler{
+		manifests: manifests,
+		logger:    logger,
+	}
+}
+
+// CloseManifests handles POST /admin/manifests requests, returning 201 Created with the manifests created
+// for the day's pending shipments, or 200 OK when every shipment was already in a manifest
+func (h *ManifestHandler) CloseManifests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req model.CloseManifestsRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r, &req); err != nil {
+			writeJSON(ctx, h.logger, w, invalidBodyStatus(err), invalidBody(err))
+			return
+		}
+	}
+
+	closed, err := h.manifests.Close(ctx, &req)
+	switch {
+	case err == nil && len(closed.Manifests) > 0:
+		writeJSON(ctx, h.logger, w, http.StatusCreated, closed)
+	case err == nil:
+		writeJSON(ctx, h.logger, w, http.StatusOK, closed)
+	case errors.Is(err, service.ErrInvalidManifest):
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		logger.LogError(h.logger, ctx, "Erro ao fechar manifestos", err, zap.String("data", req.Date))
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to close manifests"})
+	}
+}
+
+// GetManifest handles GET /admin/manifests/{id} requests
+func (h *ManifestHandler) GetManifest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	found, err := h.manifests.Get(ctx, id)
+	switch {
+	case err == nil:
+		writeJSON(ctx, h.logger, w, http.StatusOK, found)
+	case errors.Is(err, service.ErrManifestNotFound):
+		writeJSON(ctx, h.logger, w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		logger.LogError(h.logger, ctx, "Erro ao consultar manifesto", err, zap.String("manifest_id", id))
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to get manifest"})
+	}
+}
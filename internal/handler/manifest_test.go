@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// stubManifests closes the requested day into the manifests set, or fails with err
+type stubManifests struct {
+	manifests []model.Manifest
+	err       error
+}
+
+func (s stubManifests) Close(ctx context.Context, req *model.CloseManifestsRequest) (*model.CloseManifestsResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.CloseManifestsResponse{Date: req.Date, Manifests: s.manifests}, nil
+}
+
+func (s stubManifests) Get(ctx context.Context, id string) (*model.Manifest, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.Manifest{ID: id, Carrier: "correios", Status: model.ManifestStatusSubmitted}, nil
+}
+
+func manifestRouter(h *ManifestHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/manifests", h.CloseManifests)
+	r.Get("/manifests/{id}", h.GetManifest)
+	return r
+}
+
+func TestCloseManifests(t *testing.T) {
+	tests := []struct {
+		name          string
+		manifests     stubManifests
+		body          string
+		wantStatus    int
+		wantManifests int
+	}{
+		{"manifests created", stubManifests{manifests: []model.Manifest{{ID: "m1", Carrier: "correios"}}}, `{"date":"2025-03-10"}`, http.StatusCreated, 1},
+		{"nothing pending", stubManifests{manifests: []model.Manifest{}}, "", http.StatusOK, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			router := manifestRouter(NewManifestHandler(tt.manifests, zaptest.NewLogger(t)))
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/manifests", strings.NewReader(tt.body)))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var closed model.CloseManifestsResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &closed))
+			assert.Len(t, closed.Manifests, tt.wantManifests)
+		})
+	}
+}
+
+func TestCloseManifests_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{"invalid body", `{"date":`, nil, http.StatusBadRequest, "invalid request body"},
+		{"invalid date", `{"date":"2099-01-01"}`, fmt.Errorf("%w: date must not be in the future", service.ErrInvalidManifest), http.StatusBadRequest, "date must not be in the future"},
+		{"storage failure", "", errors.New("failed to save manifest: connection refused"), http.StatusInternalServerError, "failed to close manifests"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			router := manifestRouter(NewManifestHandler(stubManifests{err: tt.err}, zaptest.NewLogger(t)))
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/manifests", strings.NewReader(tt.body)))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var body map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Contains(t, body["error"], tt.wantError)
+		})
+	}
+}
+
+func TestGetManifest(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"found", nil, http.StatusOK},
+		{"not found", service.ErrManifestNotFound, http.StatusNotFound},
+		{"storage failure", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			router := manifestRouter(NewManifestHandler(stubManifests{err: tt.err}, zaptest.NewLogger(t)))
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/manifests/m1", nil))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/webhook"
+	"go.uber.org/zap"
+)
+
+// Dead letters returned by GET /admin/webhooks/dead-letters
+const (
+	defaultDeadLetters = 100
+	maxDeadLetters     = 1000
+)
+
+// WebhookSubscriber manages the webhook subscriptions of the tenants and their undelivered events
+type WebhookSubscriber interface {
+	Subscribe(ctx context.Context, req *model.CreateWebhookSubscriptionRequest) (*model.WebhookSubscription, error)
+	Subscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	Unsubscribe(ctx context.Context, id string) error
+	DeadLetters(ctx context.Context, tenantID string, limit int) ([]model.WebhookDeadLetter, error)
+}
+
+// MerchantWebhookHandler handles HTTP requests for the webhook subscriptions of merchants
+type MerchantWebhookHandler struct {
+	webhooks WebhookSubscriber
+	logger   *zap.Logger
+}
+
+// NewMerchantWebhookHandler creates a new merchant webhook handler instance
+func NewMerchantWebhookHandler(webhooks WebhookSubscriber, logger *zap.Logger) *MerchantWebhookHandler {
+	return &MerchantWebhookHandler{
+		webhooks: webhooks,
+		logger:   logger,
+	}
+}
+
+// Subscribe handles POST /webhook-subscriptions requests, returning 201 Created with the
+// subscription and its secret
+func (h *MerchantWebhookHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req model.CreateWebhookSubscriptionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeJSON(ctx, h.logger, w, invalidBodyStatus(err), invalidBody(err))
+		return
+	}
+
+	subscription, err := h.webhooks.Subscribe(ctx, &req)
+	switch {
+	case err == nil:
+		writeJSON(ctx, h.logger, w, http.StatusCreated, subscription)
+	case errors.Is(err, webhook.ErrInvalidSubscription):
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		logger.LogError(h.logger, ctx, "Erro ao registrar webhook", err)
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to create webhook subscription"})
+	}
+}
+
+// ListSubscriptions handles GET /webhook-subscriptions requests, listing the subscriptions of the
+// tenant without their secrets
+func (h *MerchantWebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subscriptions, err := h.webhooks.Subscriptions(ctx)
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao listar webhooks", err)
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to list webhook subscriptions"})
+		return
+	}
+	writeJSON(ctx, h.logger, w, http.StatusOK, model.WebhookSubscriptionsResponse{Subscriptions: subscriptions})
+}
+
+// Unsubscribe handles DELETE /webhook-subscriptions/{id} requests
+func (h *MerchantWebhookHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	err := h.webhooks.Unsubscribe(ctx, id)
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, webhook.ErrNotFound):
+		writeJSON(ctx, h.logger, w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		logger.LogError(h.logger, ctx, "Erro ao remover webhook", err, zap.String("subscription_id", id))
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to delete webhook subscription"})
+	}
+}
+
+// GetDeadLetters handles GET /admin/webhooks/dead-letters requests. The optional "tenant" query
+// parameter filters by tenant and "limit" (default 100, at most 1000) bounds how many of the most
+// recent dead letters are returned
+func (h *MerchantWebhookHandler) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	limit := defaultDeadLetters
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > maxDeadLetters {
+			writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid limit: must be between 1 and %d", maxDeadLetters)})
+			return
+		}
+		limit = parsed
+	}
+
+	letters, err := h.webhooks.DeadLetters(ctx, query.Get("tenant"), limit)
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao listar webhooks não entregues", err)
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to list webhook dead letters"})
+		return
+	}
+	writeJSON(ctx, h.logger, w, http.StatusOK, model.WebhookDeadLettersResponse{DeadLetters: letters})
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/webhook"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// stubWebhooks manages subscriptions of the "acme" tenant, or fails with err, recording the dead
+// letter queries
+type stubWebhooks struct {
+	err         error
+	deadLetters *[]string
+}
+
+func (s stubWebhooks) Subscribe(ctx context.Context, req *model.CreateWebhookSubscriptionRequest) (*model.WebhookSubscription, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.WebhookSubscription{ID: "w1", Tenant: "acme", URL: req.URL, Events: webhook.EventTypes, Secret: "generated-secret"}, nil
+}
+
+func (s stubWebhooks) Subscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []model.WebhookSubscription{{ID: "w1", Tenant: "acme", URL: "https://acme.example.com/hooks"}}, nil
+}
+
+func (s stubWebhooks) Unsubscribe(ctx context.Context, id string) error {
+	return s.err
+}
+
+func (s stubWebhooks) DeadLetters(ctx context.Context, tenantID string, limit int) ([]model.WebhookDeadLetter, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.deadLetters != nil {
+		*s.deadLetters = append(*s.deadLetters, fmt.Sprintf("%s:%d", tenantID, limit))
+	}
+	return []model.WebhookDeadLetter{{ID: "d1", Tenant: tenantID}}, nil
+}
+
+func merchantWebhookRouter(h *MerchantWebhookHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/webhook-subscriptions", h.Subscribe)
+	r.Get("/webhook-subscriptions", h.ListSubscriptions)
+	r.Delete("/webhook-subscriptions/{id}", h.Unsubscribe)
+	r.Get("/admin/webhooks/dead-letters", h.GetDeadLetters)
+	return r
+}
+
+func TestSubscribeWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{"subscribed", `{"url":"https://acme.example.com/hooks"}`, nil, http.StatusCreated},
+		{"invalid body", `{"url":`, nil, http.StatusBadRequest},
+		{"invalid subscription", `{"url":"/hooks"}`, fmt.Errorf("%w: url must be an absolute http or https URL", webhook.ErrInvalidSubscription), http.StatusBadRequest},
+		{"storage failure", `{"url":"https://acme.example.com/hooks"}`, errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			router := merchantWebhookRouter(NewMerchantWebhookHandler(stubWebhooks{err: tt.err}, zaptest.NewLogger(t)))
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/webhook-subscriptions", strings.NewReader(tt.body)))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusCreated {
+				var subscription model.WebhookSubscription
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &subscription))
+				assert.Equal(t, "generated-secret", subscription.Secret, "the secret is returned on creation")
+			}
+		})
+	}
+}
+
+func TestSubscribeWebhook_BodyTooLarge(t *testing.T) {
+	// Arrange
+	router := middleware.DecompressRequest(1024)(merchantWebhookRouter(NewMerchantWebhookHandler(stubWebhooks{}, zaptest.NewLogger(t))))
+	body := `{"url":"https://acme.example.com/` + strings.Repeat("a", 2048) + `"}`
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/webhook-subscriptions", strings.NewReader(body)))
+
+	// Assert
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.JSONEq(t, `{"error":"request body exceeds 1024 bytes"}`, w.Body.String())
+}
+
+func TestListWebhookSubscriptions(t *testing.T) {
+	// Arrange
+	router := merchantWebhookRouter(NewMerchantWebhookHandler(stubWebhooks{}, zaptest.NewLogger(t)))
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/webhook-subscriptions", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var listed model.WebhookSubscriptionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	assert.Len(t, listed.Subscriptions, 1)
+}
+
+func TestUnsubscribeWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"deleted", nil, http.StatusNoContent},
+		{"not found", webhook.ErrNotFound, http.StatusNotFound},
+		{"storage failure", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			router := merchantWebhookRouter(NewMerchantWebhookHandler(stubWebhooks{err: tt.err}, zaptest.NewLogger(t)))
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/webhook-subscriptions/w1", nil))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestGetWebhookDeadLetters(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantQuery  []string
+	}{
+		{"defaults", "", http.StatusOK, []string{":100"}},
+		{"filtered", "?tenant=acme&limit=5", http.StatusOK, []string{"acme:5"}},
+		{"invalid limit", "?limit=0", http.StatusBadRequest, nil},
+		{"limit too large", "?limit=1001", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var queries []string
+			router := merchantWebhookRouter(NewMerchantWebhookHandler(stubWebhooks{deadLetters: &queries}, zaptest.NewLogger(t)))
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/webhooks/dead-letters"+tt.query, nil))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantQuery, queries)
+		})
+	}
+}
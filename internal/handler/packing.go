@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/mapper"
+	"github.com/rbonfanti/shipping-calculator/internal/packing"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"go.uber.org/zap"
+)
+
+// PackingHandler suggests the boxes for a set of items and quotes them
+type PackingHandler struct {
+	suggester *packing.Suggester
+	logger    *zap.Logger
+}
+
+// NewPackingHandler creates a new packing handler instance packing with the box catalog of cfg
+func NewPackingHandler(calculator packing.Calculator, cfg packing.Config, logger *zap.Logger) *PackingHandler {
+	return &PackingHandler{
+		suggester: packing.NewSuggester(calculator, cfg),
+		logger:    logger,
+	}
+}
+
+// SuggestPacking handles POST /packing requests. The items are packed into boxes of the catalog
+// and every box is quoted as a package; the quotes are not persisted
+func (h *PackingHandler) SuggestPacking(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body v1.PackingRequest
+	if err := decodeJSON(r, &body); err != nil {
+		logger.LogError(h.logger, ctx, "Erro na sugestão de embalagem: falha ao decodificar requisição", err)
+		writeJSON(ctx, h.logger, w, invalidBodyStatus(err), invalidBody(err))
+		return
+	}
+
+	req, items := mapper.PackingRequestFromV1(&body)
+	suggestion, err := h.suggester.Suggest(ctx, req, items)
+	if errors.Is(err, packing.ErrInvalidItems) || errors.Is(err, packing.ErrItemTooLarge) {
+		logger.LogError(h.logger, ctx, "Erro na sugestão de embalagem", err)
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro na sugestão de embalagem", err)
+		writeJSON(ctx, h.logger, w, calculationStatus(err), calculationError(err))
+		return
+	}
+
+	response := mapper.SuggestionToV1(suggestion)
+	logger.LogRequest(h.logger, ctx, "Sugestão de embalagem",
+		zap.Int("itens", len(items)),
+		zap.Int("caixas", response.BoxCount),
+		zap.Float64("custo", response.ShippingCost),
+	)
+	writeJSON(ctx, h.logger, w, http.StatusOK, response)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/packing"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSuggestPacking(t *testing.T) {
+	// Arrange
+	mockService := new(MockShippingService)
+	mockService.On("CalculateShipping", mock.Anything, &model.CalculateShippingRequest{
+		OriginZipcode:      "01310100",
+		DestinationZipcode: "20040020",
+		Weight:             1,
+		Dimensions:         model.PackageDimensions{Length: 20, Width: 15, Height: 10},
+		PackageType:        "fragile",
+	}).Return(&model.CalculateShippingResponse{
+		Currency:          "BRL",
+		ShippingCost:      money.FromMinor(1550),
+		AvailableServices: []string{model.ServiceStandard},
+	}, nil)
+	handler := NewPackingHandler(mockService, packing.DefaultConfig(), zaptest.NewLogger(t))
+	body := `{"origin_zipcode": "01310100", "destination_zipcode": "20040020", "package_type": "fragile",
+		"items": [{"sku": "mug", "length": 10, "width": 10, "height": 10, "weight": 0.5, "quantity": 2}]}`
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.SuggestPacking(w, httptest.NewRequest(http.MethodPost, "/packing", strings.NewReader(body)))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response v1.PackingResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "BRL", response.Currency)
+	assert.Equal(t, 1550.0, response.ShippingCost)
+	assert.Equal(t, 1, response.BoxCount)
+	assert.Equal(t, "small", response.Boxes[0].Box.ID)
+	assert.Equal(t, []v1.PackedItem{{SKU: "mug", Quantity: 2}}, response.Boxes[0].Items)
+	assert.InDelta(t, 1.0, response.Boxes[0].Weight, 1e-9)
+	assert.InDelta(t, 2.0/3.0, response.Boxes[0].FillRate, 1e-9)
+	assert.Equal(t, 1550.0, response.Boxes[0].Quote.ShippingCost)
+	mockService.AssertExpectations(t)
+}
+
+func TestSuggestPacking_Errors(t *testing.T) {
+	items := `"items": [{"sku": "mug", "length": 10, "width": 10, "height": 10, "weight": 1}]`
+	tests := []struct {
+		name       string
+		body       string
+		calcErr    error
+		wantStatus int
+		wantErr    string
+	}{
+		{"invalid body", `{`, nil, http.StatusBadRequest, "invalid request body"},
+		{"no items", `{"origin_zipcode": "01310100", "destination_zipcode": "20040020"}`, nil, http.StatusBadRequest, "at least one item is required"},
+		{"item too large", `{"items": [{"sku": "rod", "length": 200, "width": 5, "height": 5, "weight": 1}]}`, nil, http.StatusBadRequest, "item does not fit in any box: rod"},
+		{"invalid request", `{` + items + `}`,
+			&service.ValidationError{Field: "origin_zipcode", Err: errors.New("origin_zipcode is required")}, http.StatusBadRequest, "origin_zipcode is required"},
+		{"not serviceable", `{` + items + `}`,
+			&service.ValidationError{Field: "destination_zipcode", Err: service.ErrNotServiceable}, http.StatusUnprocessableEntity, service.ErrNotServiceable.Error()},
+		{"provider timeout", `{` + items + `}`, context.DeadlineExceeded, http.StatusGatewayTimeout, "shipping calculation timed out"},
+		{"internal error", `{` + items + `}`, errors.New("connection refused"), http.StatusInternalServerError, "failed to calculate shipping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockShippingService)
+			mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(nil, tt.calcErr)
+			handler := NewPackingHandler(mockService, packing.DefaultConfig(), zaptest.NewLogger(t))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.SuggestPacking(w, httptest.NewRequest(http.MethodPost, "/packing", strings.NewReader(tt.body)))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var response map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Contains(t, response["error"], tt.wantErr)
+			assert.NotContains(t, response["error"], "connection refused")
+		})
+	}
+}
+
+func TestSuggestPacking_BodyTooLarge(t *testing.T) {
+	// Arrange
+	handler := NewPackingHandler(new(MockShippingService), packing.DefaultConfig(), zaptest.NewLogger(t))
+	body := `{"origin_zipcode": "01310100", "destination_zipcode": "` + strings.Repeat("0", 2048) + `"}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/packing", strings.NewReader(body))
+	req.Body = http.MaxBytesReader(w, req.Body, 1024)
+
+	// Act
+	handler.SuggestPacking(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.JSONEq(t, `{"error":"request body exceeds 1024 bytes"}`, w.Body.String())
+}
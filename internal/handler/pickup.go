@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/pickup"
+	"github.com/rbonfanti/shipping-calculator/internal/validator"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPickupPointsLimit = 10
+	maxPickupPointsLimit     = 50
+)
+
+// PickupPointsResponse lists the pickup locations near a zipcode, closest first
+type PickupPointsResponse struct {
+	Zipcode      string               `json:"zipcode"`
+	Count        int                  `json:"count"`
+	PickupPoints []pickup.PickupPoint `json:"pickup_points"`
+}
+
+// PickupHandler serves the pickup points and lockers available for a destination
+type PickupHandler struct {
+	provider pickup.PickupPointProvider
+	logger   *zap.Logger
+}
+
+// NewPickupHandler creates a new pickup handler instance
+func NewPickupHandler(provider pickup.PickupPointProvider, logger *zap.Logger) *PickupHandler {
+	return &PickupHandler{
+		provider: provider,
+		logger:   logger,
+	}
+}
+
+// GetPickupPoints handles GET /pickup-points?zipcode= requests.
+// The optional "limit" query parameter caps the number of locations (default 10, max 50)
+func (h *PickupHandler) GetPickupPoints(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	zipcode := r.URL.Query().Get("zipcode")
+	if err := validator.ValidateZipcode(zipcode, "zipcode"); err != nil {
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	limit := defaultPickupPointsLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPickupPointsLimit {
+			writeJSON(ctx, h.logger, w, http.StatusBadRequest,
+				map[string]string{"error": "limit must be an integer between 1 and " + strconv.Itoa(maxPickupPointsLimit)})
+			return
+		}
+		limit = parsed
+	}
+
+	points, err := h.provider.NearbyPickupPoints(ctx, zipcode, limit)
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao listar pontos de retirada", err)
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to list pickup points"})
+		return
+	}
+	if points == nil {
+		points = []pickup.PickupPoint{}
+	}
+
+	writeJSON(ctx, h.logger, w, http.StatusOK, PickupPointsResponse{
+		Zipcode:      zipcode,
+		Count:        len(points),
+		PickupPoints: points,
+	})
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/pickup"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// failingPickupProvider always returns an error
+type failingPickupProvider struct{}
+
+func (failingPickupProvider) NearbyPickupPoints(ctx context.Context, zipcode string, limit int) ([]pickup.PickupPoint, error) {
+	return nil, errors.New("provider unavailable")
+}
+
+func TestGetPickupPoints(t *testing.T) {
+	// Arrange
+	provider := pickup.NewStaticProvider(pickup.Config{Points: []pickup.PickupPoint{
+		{ID: "L1", Type: pickup.TypeLocker, Zipcode: "01310100"},
+		{ID: "P1", Type: pickup.TypePickupPoint, Zipcode: "01310900"},
+	}})
+	handler := NewPickupHandler(provider, zaptest.NewLogger(t))
+
+	tests := []struct {
+		name    string
+		url     string
+		wantIDs []string
+	}{
+		{"nearby points", "/pickup-points?zipcode=01310-150", []string{"L1", "P1"}},
+		{"limited", "/pickup-points?zipcode=01310150&limit=1", []string{"L1"}},
+		{"none nearby", "/pickup-points?zipcode=90000000", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.GetPickupPoints(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+			var response PickupPointsResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, len(tt.wantIDs), response.Count)
+			ids := make([]string, 0, len(response.PickupPoints))
+			for _, p := range response.PickupPoints {
+				ids = append(ids, p.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestGetPickupPoints_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   pickup.PickupPointProvider
+		url        string
+		wantStatus int
+		wantErr    string
+	}{
+		{"missing zipcode", pickup.NewStaticProvider(pickup.Config{}), "/pickup-points", http.StatusBadRequest, "zipcode is required"},
+		{"invalid zipcode", pickup.NewStaticProvider(pickup.Config{}), "/pickup-points?zipcode=12", http.StatusBadRequest, "valid zipcode"},
+		{"invalid limit", pickup.NewStaticProvider(pickup.Config{}), "/pickup-points?zipcode=01310100&limit=0", http.StatusBadRequest, "limit must be"},
+		{"provider error", failingPickupProvider{}, "/pickup-points?zipcode=01310100", http.StatusInternalServerError, "failed to list pickup points"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewPickupHandler(tt.provider, zaptest.NewLogger(t))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.GetPickupPoints(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var errorResponse map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+			assert.Contains(t, errorResponse["error"], tt.wantErr)
+		})
+	}
+}
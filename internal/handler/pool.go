@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+)
+
+// maxPooledBufferSize is the capacity above which an encoding buffer is dropped instead of pooled,
+// so that a few large responses (e.g. bulk quotes) do not keep their memory for the small ones
+const maxPooledBufferSize = 64 << 10
+
+// Pools of the transport models and encoding buffers of the quote hot path. Only values that do not
+// outlive the request are pooled: the domain requests and responses are kept by the persisted quote,
+// the published events and shadow pricing, so they are allocated for every quote
+var (
+	requestPool = sync.Pool{New: func() any { return new(v1.CalculateShippingRequest) }}
+	encoderPool = sync.Pool{New: func() any {
+		e := &jsonEncoder{}
+		e.encoder = json.NewEncoder(&e.buf)
+		return e
+	}}
+)
+
+// getRequest returns an empty request body to decode into; it must be returned with putRequest
+func getRequest() *v1.CalculateShippingRequest {
+	return requestPool.Get().(*v1.CalculateShippingRequest)
+}
+
+// putRequest clears a request body, dropping its references, and returns it to the pool
+func putRequest(body *v1.CalculateShippingRequest) {
+	*body = v1.CalculateShippingRequest{}
+	requestPool.Put(body)
+}
+
+// jsonEncoder is a JSON encoder writing to its own buffer
+type jsonEncoder struct {
+	buf     bytes.Buffer
+	encoder *json.Encoder
+}
+
+// getEncoder returns an encoder with an empty buffer; it must be returned with putEncoder
+func getEncoder() *jsonEncoder {
+	return encoderPool.Get().(*jsonEncoder)
+}
+
+// putEncoder returns an encoder to the pool, unless its buffer grew too large
+func putEncoder(e *jsonEncoder) {
+	if e.buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	e.buf.Reset()
+	encoderPool.Put(e)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestPutRequest(t *testing.T) {
+	// Arrange
+	body := getRequest()
+	body.OriginZipcode = "01310100"
+	body.AdditionalServices = []string{"signature"}
+
+	// Act
+	putRequest(body)
+
+	// Assert
+	assert.Equal(t, v1.CalculateShippingRequest{}, *body, "pooled bodies keep no data nor references")
+}
+
+func TestPutEncoder(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       int
+		wantPooled bool
+	}{
+		{"small buffer is reset", 1 << 10, true},
+		{"large buffer is dropped", maxPooledBufferSize + 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			e := getEncoder()
+			e.buf.Write(bytes.Repeat([]byte("x"), tt.size))
+
+			// Act
+			putEncoder(e)
+
+			// Assert
+			assert.Equal(t, tt.wantPooled, e.buf.Len() == 0)
+		})
+	}
+}
+
+func TestWriteJSON_ReusesEncoder(t *testing.T) {
+	// Arrange
+	first, second := httptest.NewRecorder(), httptest.NewRecorder()
+
+	// Act
+	writeJSON(context.Background(), zap.NewNop(), first, http.StatusOK, map[string]string{"service": "express"})
+	writeJSON(context.Background(), zap.NewNop(), second, http.StatusCreated, map[string]string{"service": "standard"})
+
+	// Assert
+	assert.Equal(t, "{\"service\":\"express\"}\n", first.Body.String())
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, "{\"service\":\"standard\"}\n", second.Body.String(), "a reused buffer keeps nothing from the previous response")
+}
+
+func BenchmarkWriteJSON(b *testing.B) {
+	response := &v1.CalculateShippingResponse{
+		ShippingCost:          1875,
+		EstimatedDeliveryTime: "2 dias",
+		AvailableServices:     []string{"standard", "express"},
+		ShippingOptions: []v1.ShippingOption{
+			{Service: "standard", Cost: 1250, Time: "2 dias", EstimatedDays: 2},
+			{Service: "express", Cost: 1875, Time: "1 dia", EstimatedDays: 1},
+		},
+	}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		w := httptest.NewRecorder()
+		for pb.Next() {
+			w.Body.Reset()
+			writeJSON(context.Background(), zap.NewNop(), w, http.StatusOK, response)
+		}
+	})
+}
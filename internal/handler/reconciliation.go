@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rbonfanti/shipping-calculator/internal/reconciliation"
+	"go.uber.org/zap"
+)
+
+// DiscrepancyLister provides the discrepancies found by invoice reconciliation
+type DiscrepancyLister interface {
+	Discrepancies() []reconciliation.Discrepancy
+}
+
+// DiscrepanciesResponse is the discrepancies report
+type DiscrepanciesResponse struct {
+	Count         int                          `json:"count"`
+	Discrepancies []reconciliation.Discrepancy `json:"discrepancies"`
+}
+
+// ReconciliationHandler serves the invoice reconciliation report
+type ReconciliationHandler struct {
+	lister DiscrepancyLister
+	logger *zap.Logger
+}
+
+// NewReconciliationHandler creates a new reconciliation handler instance
+func NewReconciliationHandler(lister DiscrepancyLister, logger *zap.Logger) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		lister: lister,
+		logger: logger,
+	}
+}
+
+// GetDiscrepancies handles GET /admin/reconciliation/discrepancies requests.
+// The optional "kind" query parameter filters by discrepancy kind
+func (h *ReconciliationHandler) GetDiscrepancies(w http.ResponseWriter, r *http.Request) {
+	kind := r.URL.Query().Get("kind")
+
+	discrepancies := make([]reconciliation.Discrepancy, 0)
+	for _, d := range h.lister.Discrepancies() {
+		if kind == "" || d.Kind == kind {
+			discrepancies = append(discrepancies, d)
+		}
+	}
+
+	writeJSON(r.Context(), h.logger, w, http.StatusOK, DiscrepanciesResponse{
+		Count:         len(discrepancies),
+		Discrepancies: discrepancies,
+	})
+}
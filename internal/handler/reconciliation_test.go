@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/reconciliation"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// staticDiscrepancies is a DiscrepancyLister returning a fixed list
+type staticDiscrepancies []reconciliation.Discrepancy
+
+func (s staticDiscrepancies) Discrepancies() []reconciliation.Discrepancy {
+	return s
+}
+
+func TestGetDiscrepancies(t *testing.T) {
+	// Arrange
+	lister := staticDiscrepancies{
+		{ShipmentID: "quote-1", Kind: reconciliation.KindAmountMismatch, BookedCents: 1000, InvoicedCents: 1200, DifferenceCents: 200},
+		{ShipmentID: "quote-2", Kind: reconciliation.KindUnmatched, InvoicedCents: 500, DifferenceCents: 500},
+	}
+	handler := NewReconciliationHandler(lister, zaptest.NewLogger(t))
+
+	tests := []struct {
+		name    string
+		url     string
+		wantIDs []string
+	}{
+		{"all", "/admin/reconciliation/discrepancies", []string{"quote-1", "quote-2"}},
+		{"filtered by kind", "/admin/reconciliation/discrepancies?kind=unmatched", []string{"quote-2"}},
+		{"no match", "/admin/reconciliation/discrepancies?kind=other", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.GetDiscrepancies(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response DiscrepanciesResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, len(tt.wantIDs), response.Count)
+			ids := make([]string, 0, len(response.Discrepancies))
+			for _, d := range response.Discrepancies {
+				ids = append(ids, d.ShipmentID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestGetDiscrepancies_RequiresAdmin(t *testing.T) {
+	// Arrange
+	handler := NewReconciliationHandler(staticDiscrepancies{{ShipmentID: "quote-1", Kind: reconciliation.KindUnmatched}}, zaptest.NewLogger(t))
+	routed := middleware.RequireAdmin(middleware.AdminConfig{Tokens: []middleware.AdminToken{{Actor: "alice", Token: "s3cret"}}})(http.HandlerFunc(handler.GetDiscrepancies))
+
+	tests := []struct {
+		name       string
+		auth       string
+		wantStatus int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer other", http.StatusUnauthorized},
+		{"admin token", "Bearer s3cret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/reconciliation/discrepancies", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			routed.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.NotContains(t, w.Body.String(), "quote-1")
+			}
+		})
+	}
+}
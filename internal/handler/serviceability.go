@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rbonfanti/shipping-calculator/internal/address"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"go.uber.org/zap"
+)
+
+// restrictionUnservedDestination is reported when the destination zipcode is outside our coverage
+const restrictionUnservedDestination = "destination zipcode is not served"
+
+// ServiceabilityChecker reports the service levels available for a route
+type ServiceabilityChecker interface {
+	Serviceability(ctx context.Context, req *model.ServiceabilityRequest) (*model.ServiceabilityResponse, error)
+}
+
+// ServiceabilityHandler serves delivery availability for product pages
+type ServiceabilityHandler struct {
+	checker  ServiceabilityChecker
+	coverage address.Config
+	logger   *zap.Logger
+}
+
+// NewServiceabilityHandler creates a new serviceability handler instance; coverage defines the
+// unserved destination zipcodes
+func NewServiceabilityHandler(checker ServiceabilityChecker, coverage address.Config, logger *zap.Logger) *ServiceabilityHandler {
+	return &ServiceabilityHandler{
+		checker:  checker,
+		coverage: coverage,
+		logger:   logger,
+	}
+}
+
+// GetServiceability handles GET /serviceability?origin=&destination= requests.
+// The optional destination_country and package_type query parameters refine the check
+func (h *ServiceabilityHandler) GetServiceability(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	req := &model.ServiceabilityRequest{
+		OriginZipcode:      query.Get("origin"),
+		DestinationZipcode: query.Get("destination"),
+		DestinationCountry: query.Get("destination_country"),
+		PackageType:        query.Get("package_type"),
+	}
+
+	response, err := h.checker.Serviceability(ctx, req)
+	if err != nil {
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if !h.coverage.Deliverable(req.DestinationZipcode) {
+		response.Serviceable = false
+		response.Restrictions = append(response.Restrictions, restrictionUnservedDestination)
+		for i := range response.Services {
+			response.Services[i].Available = false
+		}
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	writeJSON(ctx, h.logger, w, http.StatusOK, response)
+}
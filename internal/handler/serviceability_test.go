@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/address"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestGetServiceability(t *testing.T) {
+	tests := []struct {
+		name             string
+		url              string
+		wantServiceable  bool
+		wantAvailable    map[string]bool
+		wantRestrictions []string
+	}{
+		{
+			name:            "all services",
+			url:             "/serviceability?origin=01310100&destination=04547130",
+			wantServiceable: true,
+			wantAvailable:   map[string]bool{"standard": true, "express": true},
+		},
+		{
+			name:            "dangerous goods without express",
+			url:             "/serviceability?origin=01310100&destination=04547130&package_type=dangerous",
+			wantServiceable: true,
+			wantAvailable:   map[string]bool{"standard": true, "express": false},
+		},
+		{
+			name:             "unserved destination",
+			url:              "/serviceability?origin=01310100&destination=69900000",
+			wantServiceable:  false,
+			wantAvailable:    map[string]bool{"standard": false, "express": false},
+			wantRestrictions: []string{"destination zipcode is not served"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			coverage := address.Config{UnservedZipcodePrefixes: []string{"699"}}
+			handler := NewServiceabilityHandler(service.NewShippingService(), coverage, zaptest.NewLogger(t))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.GetServiceability(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+			var response model.ServiceabilityResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantServiceable, response.Serviceable)
+			assert.Equal(t, tt.wantRestrictions, response.Restrictions)
+			available := make(map[string]bool)
+			for _, s := range response.Services {
+				available[s.Service] = s.Available
+			}
+			assert.Equal(t, tt.wantAvailable, available)
+		})
+	}
+}
+
+func TestGetServiceability_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{"missing origin", "/serviceability?destination=04547130", "invalid origin_zipcode"},
+		{"invalid destination", "/serviceability?origin=01310100&destination=12", "invalid destination_zipcode"},
+		{"unknown country", "/serviceability?origin=01310100&destination=04547130&destination_country=AR", "invalid destination_country"},
+		{"unknown package type", "/serviceability?origin=01310100&destination=04547130&package_type=x", "invalid package_type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewServiceabilityHandler(service.NewShippingService(), address.Config{}, zaptest.NewLogger(t))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.GetServiceability(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var errorResponse map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+			assert.Contains(t, errorResponse["error"], tt.wantErr)
+		})
+	}
+}
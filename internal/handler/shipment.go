@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/mapper"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"go.uber.org/zap"
+)
+
+// ShipmentBooker books shipments from quotes
+type ShipmentBooker interface {
+	Book(ctx context.Context, req *model.BookShipmentRequest) (*model.Shipment, error)
+}
+
+// ShipmentHandler handles HTTP requests for shipments
+type ShipmentHandler struct {
+	booker ShipmentBooker
+	logger *zap.Logger
+}
+
+// NewShipmentHandler creates a new shipment handler instance
+func NewShipmentHandler(booker ShipmentBooker, logger *zap.Logger) *ShipmentHandler {
+	return &ShipmentHandler{
+		booker: booker,
+		logger: logger,
+	}
+}
+
+// BookShipment handles POST /shipments requests, booking a shipment from a stored quote
+func (h *ShipmentHandler) BookShipment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body v1.BookShipmentRequest
+	if err := decodeJSON(r, &body); err != nil {
+		writeJSON(ctx, h.logger, w, invalidBodyStatus(err), invalidBody(err))
+		return
+	}
+
+	req := mapper.BookingFromV1(&body)
+	shipment, err := h.booker.Book(ctx, req)
+	switch {
+	case err == nil:
+		writeJSON(ctx, h.logger, w, http.StatusCreated, mapper.ShipmentToV1(shipment))
+	case errors.Is(err, service.ErrQuoteNotFound):
+		writeJSON(ctx, h.logger, w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, service.ErrQuoteExpired), errors.Is(err, service.ErrQuoteAlreadyBooked), errors.Is(err, service.ErrPickupUnavailable):
+		writeJSON(ctx, h.logger, w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidBooking):
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		logger.LogError(h.logger, ctx, "Erro ao reservar envio", err, zap.String("quote_id", req.QuoteID))
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to book shipment"})
+	}
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// stubBooker books every request with the quote ID as shipment ID, or fails with err
+type stubBooker struct {
+	err error
+}
+
+func (s stubBooker) Book(ctx context.Context, req *model.BookShipmentRequest) (*model.Shipment, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.Shipment{ID: "s-" + req.QuoteID, QuoteID: req.QuoteID, Status: model.ShipmentStatusBooked, Service: req.Service, Cost: money.FromMinor(1400)}, nil
+}
+
+func TestBookShipment(t *testing.T) {
+	// Arrange
+	handler := NewShipmentHandler(stubBooker{}, zaptest.NewLogger(t))
+	req := httptest.NewRequest(http.MethodPost, "/shipments", strings.NewReader(`{"quote_id":"q1","service":"standard"}`))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.BookShipment(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var shipment v1.Shipment
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &shipment))
+	assert.Equal(t, "s-q1", shipment.ID)
+	assert.Equal(t, model.ShipmentStatusBooked, shipment.Status)
+	assert.Equal(t, "standard", shipment.Service)
+	assert.Equal(t, 1400.0, shipment.Cost)
+}
+
+func TestBookShipment_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{"invalid body", `{"quote_id":`, nil, http.StatusBadRequest, "invalid request body"},
+		{"invalid booking", `{}`, fmt.Errorf("%w: quote_id is required", service.ErrInvalidBooking), http.StatusBadRequest, "invalid booking: quote_id is required"},
+		{"unknown quote", `{"quote_id":"q1"}`, service.ErrQuoteNotFound, http.StatusNotFound, "quote not found"},
+		{"expired quote", `{"quote_id":"q1"}`, service.ErrQuoteExpired, http.StatusConflict, "quote expired"},
+		{"quote already booked", `{"quote_id":"q1"}`, service.ErrQuoteAlreadyBooked, http.StatusConflict, "quote already booked"},
+		{"storage failure", `{"quote_id":"q1"}`, errors.New("storage unavailable"), http.StatusInternalServerError, "failed to book shipment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewShipmentHandler(stubBooker{err: tt.err}, zaptest.NewLogger(t))
+			req := httptest.NewRequest(http.MethodPost, "/shipments", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.BookShipment(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var body map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantError, body["error"])
+		})
+	}
+}
+
+func TestBookShipment_BodyTooLarge(t *testing.T) {
+	// Arrange
+	handler := middleware.DecompressRequest(1024)(http.HandlerFunc(NewShipmentHandler(stubBooker{}, zaptest.NewLogger(t)).BookShipment))
+	body := `{"quote_id":"` + strings.Repeat("0", 2048) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/shipments", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.JSONEq(t, `{"error":"request body exceeds 1024 bytes"}`, w.Body.String())
+}
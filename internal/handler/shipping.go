@@ -3,46 +3,138 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/rbonfanti/shipping-calculator/internal/events"
 	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/mapper"
 	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
 	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/strictjson"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/rbonfanti/shipping-calculator/internal/validator"
+	"github.com/rbonfanti/shipping-calculator/pkg/quotetoken"
 	"github.com/rbonfanti/shipping-calculator/telemetry"
 	"go.uber.org/zap"
 )
 
+// errorCodeNotServiceable is the code of the errors of destinations that a restricted area excludes
+const errorCodeNotServiceable = "NOT_SERVICEABLE"
+
 // ShippingHandler handles HTTP requests for shipping calculations
 type ShippingHandler struct {
-	service service.ShippingServiceInterface
-	logger  *zap.Logger
+	service     service.ShippingServiceInterface
+	quotes      repository.QuoteRepository
+	quoteConfig repository.QuoteConfig
+	events      events.Publisher
+	signer      QuoteSigner
+	versions    repository.PricingVersionRepository
+	clock       determinism.Clock
+	ids         determinism.IDGenerator
+	metrics     *telemetry.Metrics
+	stats       QuoteStatsRecorder
+	logger      *zap.Logger
+}
+
+// QuoteStatsRecorder aggregates the calculations of the quote routes, e.g. a *quotestats.Window
+type QuoteStatsRecorder interface {
+	RecordQuote(region, currency string, cost money.Amount)
+	RecordError()
+}
+
+// QuoteSigner signs the quoted terms of a shipping option, e.g. a *quotetoken.Signer
+type QuoteSigner interface {
+	Sign(claims quotetoken.Claims) (string, error)
 }
 
-// NewShippingHandler creates a new shipping handler instance
-func NewShippingHandler(shippingService service.ShippingServiceInterface, logger *zap.Logger) *ShippingHandler {
+// NewShippingHandler creates a new shipping handler instance.
+// Calculated quotes are persisted in quotes; a nil repository disables persistence.
+// Quotes expire after quoteConfig.TTL; a zero TTL disables expiration.
+// Persisted quotes are published as quote.created events to publisher, unless it is nil.
+// The options of persisted quotes carry a token signed by signer, unless it is nil.
+// Quotes are repriced as of a past date with the pricing versions recorded in versions; a nil
+// repository disables the as_of parameter.
+// The calculations are recorded in metrics; nil metrics record nothing
+func NewShippingHandler(shippingService service.ShippingServiceInterface, quotes repository.QuoteRepository, quoteConfig repository.QuoteConfig, publisher events.Publisher, signer QuoteSigner, versions repository.PricingVersionRepository, metrics *telemetry.Metrics, logger *zap.Logger) *ShippingHandler {
 	return &ShippingHandler{
-		service: shippingService,
-		logger:  logger,
+		service:     shippingService,
+		quotes:      quotes,
+		quoteConfig: quoteConfig,
+		events:      publisher,
+		signer:      signer,
+		versions:    versions,
+		clock:       determinism.SystemClock{},
+		ids:         determinism.UUIDGenerator{},
+		metrics:     metrics,
+		logger:      logger,
 	}
 }
 
-// CalculateShipping handles POST /calculate requests
+// WithDeterminism stamps the quotes with the time told by clock and identifies them with ids
+// instead of the system clock and random UUIDs, as in the deterministic mode of integration tests
+// and replays, and returns the handler
+func (h *ShippingHandler) WithDeterminism(clock determinism.Clock, ids determinism.IDGenerator) *ShippingHandler {
+	h.clock = clock
+	h.ids = ids
+	return h
+}
+
+// WithStats also records the calculations in stats, served by GET /admin/stats, and returns the
+// handler
+func (h *ShippingHandler) WithStats(stats QuoteStatsRecorder) *ShippingHandler {
+	h.stats = stats
+	return h
+}
+
+// recordCalculation records a calculation of req in the quote metrics and, unless nil, in the quote
+// stats, as service.RecordCalculation does
+func (h *ShippingHandler) recordCalculation(ctx context.Context, req *model.CalculateShippingRequest, response *model.CalculateShippingResponse, err error, elapsed time.Duration) {
+	service.RecordCalculation(ctx, h.metrics, req, response, err, elapsed)
+	switch {
+	case h.stats == nil:
+	case err != nil:
+		h.stats.RecordError()
+	default:
+		h.stats.RecordQuote(service.DestinationRegion(req), response.Currency, response.ShippingCost)
+	}
+}
+
+// CalculateShipping handles POST /calculate and POST /v1/calculate requests. With the as_of query
+// parameter the request is repriced with the pricing configuration in force at that date instead
 func (h *ShippingHandler) CalculateShipping(w http.ResponseWriter, r *http.Request) {
+	h.calculate(w, r, func(response *model.CalculateShippingResponse) any { return mapper.ResponseToV1(response) })
+}
+
+// CalculateShippingV2 handles POST /v2/calculate requests, priced like CalculateShipping and
+// answered with the v2 contract
+func (h *ShippingHandler) CalculateShippingV2(w http.ResponseWriter, r *http.Request) {
+	h.calculate(w, r, func(response *model.CalculateShippingResponse) any { return mapper.ResponseToV2(response) })
+}
+
+// calculate prices the request body and writes the quote encoded by encode, the transport model
+// of the API version served
+func (h *ShippingHandler) calculate(w http.ResponseWriter, r *http.Request, encode func(*model.CalculateShippingResponse) any) {
 	ctx := r.Context()
 	startTime := time.Now()
 
-	// Record request metric
-	telemetry.IncrementShipmentCalculate(ctx)
-
-	// Decode request body
-	var req model.CalculateShippingRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		telemetry.IncrementShipmentCalculateError(ctx)
+	// Decode request body into the v1 transport model
+	body := getRequest()
+	defer putRequest(body)
+	if err := decodeJSON(r, body); err != nil {
+		h.recordCalculation(ctx, nil, nil, err, time.Since(startTime))
 		logger.LogError(h.logger, ctx, "Erro no serviço de cálculo: falha ao decodificar requisição", err)
-		h.writeJSON(ctx, w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		h.writeJSON(ctx, w, invalidBodyStatus(err), invalidBody(err))
 		return
 	}
+	req := mapper.RequestFromV1(body)
 
 	// Calculate volume for logging
 	volume := req.Dimensions.Length * req.Dimensions.Width * req.Dimensions.Height
@@ -55,29 +147,378 @@ func (h *ShippingHandler) CalculateShipping(w http.ResponseWriter, r *http.Reque
 		zap.Float64("volume", volume),
 	)
 
+	// Reprice with the configuration in force at a past date, e.g. for disputes and refunds
+	if value := r.URL.Query().Get("as_of"); value != "" {
+		h.calculateAsOf(ctx, w, req, value, encode)
+		return
+	}
+
 	// Calculate shipping
-	response, err := h.service.CalculateShipping(ctx, &req)
+	response, err := h.service.CalculateShipping(ctx, req)
 	if err != nil {
-		telemetry.IncrementShipmentCalculateError(ctx)
+		h.recordCalculation(ctx, req, nil, err, time.Since(startTime))
 		logger.LogError(h.logger, ctx, "Erro no serviço de cálculo", err)
-		h.writeJSON(ctx, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		h.writeJSON(ctx, w, calculationStatus(err), calculationError(err))
 		return
 	}
 
 	// Record success metrics
-	elapsed := time.Since(startTime)
-	telemetry.RecordShipmentCalculateTime(ctx, elapsed.Milliseconds())
-	telemetry.RecordShipmentCalculateCostDistribution(ctx, response.ShippingCost)
+	h.recordCalculation(ctx, req, response, nil, time.Since(startTime))
+	if response.Experiment != nil {
+		h.metrics.RecordPricingExperimentQuote(ctx, response.Experiment.Name, response.Experiment.Arm, response.ShippingCost.Minor())
+	}
+
+	// Persist quote so it can be referenced later (e.g. invoice reconciliation)
+	now := h.clock.Now().UTC()
+	h.setExpiration(response, now)
+	h.saveQuote(ctx, req, response, now)
+	h.signOptions(ctx, response, now)
 
 	// Return response
-	h.writeJSON(ctx, w, http.StatusOK, response)
+	h.writeJSON(ctx, w, http.StatusOK, encode(response))
+}
+
+// calculateAsOf prices req with the pricing configuration of the default tenant in force at the
+// as_of date, as a dry run: the quote is neither persisted nor signed, since its price is no
+// longer offered
+func (h *ShippingHandler) calculateAsOf(ctx context.Context, w http.ResponseWriter, req *model.CalculateShippingRequest, value string, encode func(*model.CalculateShippingResponse) any) {
+	at, err := parseAsOf(value, h.clock.Now())
+	if err != nil {
+		h.writeJSON(ctx, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if h.versions == nil {
+		h.writeJSON(ctx, w, http.StatusBadRequest, map[string]string{"error": "as_of is not available: pricing versions are not recorded"})
+		return
+	}
+	if tenant.FromContext(ctx) != tenant.Default {
+		h.writeJSON(ctx, w, http.StatusBadRequest, map[string]string{"error": "as_of is only available for the default tenant"})
+		return
+	}
+
+	version, err := h.versions.AsOf(ctx, at)
+	if errors.Is(err, repository.ErrNotFound) {
+		h.writeJSON(ctx, w, http.StatusUnprocessableEntity, map[string]string{"error": "no pricing version recorded as of " + at.Format(time.RFC3339)})
+		return
+	}
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao carregar versão das tarifas", err, zap.Time("as_of", at))
+		h.writeJSON(ctx, w, http.StatusInternalServerError, map[string]string{"error": "failed to load pricing version"})
+		return
+	}
+
+	ctx, _ = service.WithDryRun(service.WithHistoricalPricing(ctx, version.Config))
+	response, err := h.service.CalculateShipping(ctx, req)
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro no recálculo com tarifas anteriores", err, zap.String("versão_tarifas", version.Version))
+		h.writeJSON(ctx, w, calculationStatus(err), calculationError(err))
+		return
+	}
+
+	logger.LogRequest(h.logger, ctx, "Cotação recalculada com tarifas anteriores",
+		zap.Time("as_of", at),
+		zap.String("versão_tarifas", version.Version),
+		zap.Float64("custo_envio", response.ShippingCost.Minor()),
+	)
+	h.writeJSON(ctx, w, http.StatusOK, encode(response))
+}
+
+// parseAsOf parses the as_of query parameter: a date (YYYY-MM-DD), meaning its start in UTC, or an
+// RFC 3339 timestamp, not later than now
+func parseAsOf(value string, now time.Time) (time.Time, error) {
+	at, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		if at, err = time.Parse(time.RFC3339, value); err != nil {
+			return time.Time{}, errors.New("as_of must be a YYYY-MM-DD date or an RFC 3339 timestamp")
+		}
+	}
+	if at.After(now) {
+		return time.Time{}, errors.New("as_of must not be in the future")
+	}
+	return at.UTC(), nil
+}
+
+// RecordRejected records in the quote metrics and stats a POST /calculate request rejected by the
+// validation of its fields before reaching CalculateShipping, e.g. by middleware.ValidateBody
+func (h *ShippingHandler) RecordRejected(r *http.Request, body *v1.CalculateShippingRequest, err validator.FieldErrors) {
+	h.recordCalculation(r.Context(), mapper.RequestFromV1(body), nil, &service.ValidationError{Field: err[0].Field, Err: err}, 0)
+}
+
+// PreviewShipping handles POST /calculate/preview requests: the quote is priced like in
+// CalculateShipping and returned with the decisions taken to price it, as a dry run that is not
+// persisted, published nor counted in the quote metrics
+func (h *ShippingHandler) PreviewShipping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body := getRequest()
+	defer putRequest(body)
+	if err := decodeJSON(r, body); err != nil {
+		logger.LogError(h.logger, ctx, "Erro na simulação de cotação: falha ao decodificar requisição", err)
+		h.writeJSON(ctx, w, invalidBodyStatus(err), invalidBody(err))
+		return
+	}
+	req := mapper.RequestFromV1(body)
+
+	ctx, trace := service.WithDryRun(ctx)
+	response, err := h.service.CalculateShipping(ctx, req)
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro na simulação de cotação", err)
+		h.writeJSON(ctx, w, calculationStatus(err), calculationError(err))
+		return
+	}
+
+	logger.LogRequest(h.logger, ctx, "Cotação simulada",
+		zap.String("origem", req.OriginZipcode),
+		zap.String("destino", req.DestinationZipcode),
+		zap.Float64("custo_envio", response.ShippingCost.Minor()),
+		zap.Int("decisões", len(trace.Steps())),
+	)
+	h.writeJSON(ctx, w, http.StatusOK, mapper.PreviewToV1(&model.QuotePreview{Quote: response, Trace: trace.Steps()}))
+}
+
+// RevalidateQuote handles POST /quotes/{id}/revalidate requests: the persisted quote is repriced
+// with the current rates, in the experiment arm it was priced in, and saved as a new quote with a
+// renewed expiration; the original quote is left unchanged, and the response reports whether the
+// price changed
+func (h *ShippingHandler) RevalidateQuote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	if h.quotes == nil {
+		h.writeJSON(ctx, w, http.StatusNotFound, map[string]string{"error": "quote not found"})
+		return
+	}
+	quote, err := h.quotes.Get(ctx, id)
+	if err == nil && !quote.BelongsTo(tenant.FromContext(ctx)) {
+		err = repository.ErrNotFound
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		h.writeJSON(ctx, w, http.StatusNotFound, map[string]string{"error": "quote not found"})
+		return
+	}
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao carregar cotação", err, zap.String("quote_id", id))
+		h.writeJSON(ctx, w, http.StatusInternalServerError, map[string]string{"error": "failed to load quote"})
+		return
+	}
+
+	// Reprice in the experiment arm the quote was first priced in, so revalidation cannot move it
+	response, err := h.service.CalculateShipping(service.WithExperimentArm(ctx, quote.Response.Experiment), &quote.Request)
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao revalidar cotação", err, zap.String("quote_id", id))
+		h.writeJSON(ctx, w, calculationStatus(err), calculationError(err))
+		return
+	}
+
+	// The repriced quote is saved as a new quote, keeping the original as it was priced
+	now := h.clock.Now().UTC()
+	previous := quote.Response
+	h.setExpiration(response, now)
+	h.saveQuote(ctx, &quote.Request, response, now)
+	h.signOptions(ctx, response, now)
+	revalidation := &model.QuoteRevalidation{
+		QuoteID:      quote.ID,
+		Expired:      quote.Expired(now),
+		PriceChanged: response.ShippingCost != previous.ShippingCost || response.Currency != previous.Currency,
+		PreviousCost: previous.ShippingCost,
+		Quote:        response,
+	}
+
+	logger.LogRequest(h.logger, ctx, "Cotação revalidada",
+		zap.String("quote_id", quote.ID),
+		zap.String("nova_cotação", response.QuoteID),
+		zap.Bool("expirada", revalidation.Expired),
+		zap.Bool("preço_alterado", revalidation.PriceChanged),
+		zap.Float64("custo_anterior", previous.ShippingCost.Minor()),
+		zap.Float64("custo_atual", response.ShippingCost.Minor()),
+	)
+	h.writeJSON(ctx, w, http.StatusOK, mapper.RevalidationToV1(revalidation))
+}
+
+// setExpiration sets the expiration of a quote calculated at now, unless expiration is disabled
+func (h *ShippingHandler) setExpiration(response *model.CalculateShippingResponse, now time.Time) {
+	if h.quoteConfig.TTL <= 0 {
+		return
+	}
+	expiresAt := now.Add(h.quoteConfig.TTL)
+	response.ExpiresAt = &expiresAt
+}
+
+// expiration returns the expiration of the response, or the zero time when it does not expire
+func expiration(response *model.CalculateShippingResponse) time.Time {
+	if response.ExpiresAt == nil {
+		return time.Time{}
+	}
+	return *response.ExpiresAt
+}
+
+// saveQuote persists the quote calculated at now, sets its ID on the response and publishes it.
+// Persistence and publishing failures are logged without failing the request; a quote that could
+// not be persisted is returned without an ID. Degraded quotes, priced by the formula alone while
+// the API sheds load, are not persisted and cannot be booked
+func (h *ShippingHandler) saveQuote(ctx context.Context, req *model.CalculateShippingRequest, response *model.CalculateShippingResponse, now time.Time) {
+	if h.quotes == nil || response.Degraded {
+		return
+	}
+
+	quote := &repository.Quote{
+		ID:             h.ids.NewID(),
+		Request:        *req,
+		Response:       *response,
+		CreatedAt:      now,
+		ExpiresAt:      expiration(response),
+		PricingVersion: response.PricingVersion,
+		FuelIndex:      response.FuelIndex,
+		Tenant:         tenant.FromContext(ctx),
+	}
+	if err := h.quotes.Save(ctx, quote); err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao salvar cotação", err)
+		return
+	}
+	response.QuoteID = quote.ID
+
+	if h.events == nil {
+		return
+	}
+	event := events.Event{
+		Type:       events.QuoteCreated,
+		Subject:    quote.ID,
+		Tenant:     quote.Tenant,
+		OccurredAt: now,
+		Data:       model.QuoteCreated{Request: *req, Response: *response},
+	}
+	if err := h.events.Publish(ctx, event); err != nil {
+		logger.LogWarning(h.logger, ctx, "Falha ao publicar evento",
+			zap.String("event_type", event.Type),
+			zap.String("quote_id", quote.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// signOptions sets the token of each option of a persisted quote signed at now, so that the order
+// service can check the price it receives was quoted. A failure is logged and leaves the options
+// without tokens
+func (h *ShippingHandler) signOptions(ctx context.Context, response *model.CalculateShippingResponse, now time.Time) {
+	if h.signer == nil || response.QuoteID == "" || response.Degraded {
+		return
+	}
+
+	for i, option := range response.ShippingOptions {
+		token, err := h.signer.Sign(quotetoken.Claims{
+			QuoteID:   response.QuoteID,
+			Tenant:    tenant.FromContext(ctx),
+			Service:   option.Service,
+			Currency:  response.Currency,
+			Price:     quotetoken.Price(option.Cost),
+			IssuedAt:  now,
+			ExpiresAt: expiration(response),
+		})
+		if err != nil {
+			logger.LogError(h.logger, ctx, "Erro ao assinar cotação", err, zap.String("quote_id", response.QuoteID))
+			for j := range response.ShippingOptions {
+				response.ShippingOptions[j].Token = ""
+			}
+			return
+		}
+		response.ShippingOptions[i].Token = token
+	}
 }
 
 // writeJSON is a helper function to write JSON responses
 func (h *ShippingHandler) writeJSON(ctx context.Context, w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(ctx, h.logger, w, status, data)
+}
+
+// decodeJSON decodes the JSON body of r into v. Requests in strict schema mode reject unknown
+// fields with a *strictjson.UnknownFieldError
+func decodeJSON(r *http.Request, v any) error {
+	if strictjson.FromContext(r.Context()) {
+		return strictjson.Decode(r.Body, v)
+	}
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// calculationStatus returns the status of a failed calculation: 422 for destinations that a
+// restricted area excludes, which the client cannot fix by correcting the request, 400 for other
+// invalid requests, 504 and 502 for providers that timed out or failed and 500 otherwise
+func calculationStatus(err error) int {
+	if errors.Is(err, service.ErrNotServiceable) {
+		return http.StatusUnprocessableEntity
+	}
+	switch category, _ := service.ClassifyError(err); category {
+	case service.ErrorValidation:
+		return http.StatusBadRequest
+	case service.ErrorProviderTimeout:
+		return http.StatusGatewayTimeout
+	case service.ErrorProvider:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// calculationError returns the body of a failed calculation, with the NOT_SERVICEABLE code for
+// destinations that a restricted area excludes. Only the errors of invalid requests are returned
+// as they are; the others, logged by the caller, get a generic message
+func calculationError(err error) map[string]string {
+	if errors.Is(err, service.ErrNotServiceable) {
+		return map[string]string{"error": err.Error(), "code": errorCodeNotServiceable}
+	}
+	switch calculationStatus(err) {
+	case http.StatusBadRequest:
+		return map[string]string{"error": err.Error()}
+	case http.StatusGatewayTimeout:
+		return map[string]string{"error": "shipping calculation timed out"}
+	case http.StatusBadGateway:
+		return map[string]string{"error": "carrier rate unavailable"}
+	default:
+		return map[string]string{"error": "failed to calculate shipping"}
+	}
+}
+
+// invalidBodyStatus is the status of a body decodeJSON rejected: 413 for bodies over the limit of
+// middleware.DecompressRequest and 400 otherwise
+func invalidBodyStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// invalidBody is the error response of a body decodeJSON rejected: bodies over the limit are
+// reported with the limit, unknown fields with the closest known field, any other error as an
+// invalid body
+func invalidBody(err error) map[string]string {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return map[string]string{"error": fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit)}
+	}
+	var unknown *strictjson.UnknownFieldError
+	if !errors.As(err, &unknown) {
+		return map[string]string{"error": "invalid request body"}
+	}
+	body := map[string]string{"error": unknown.Error(), "field": unknown.Field}
+	if unknown.Suggestion != "" {
+		body["suggestion"] = unknown.Suggestion
+	}
+	return body
+}
+
+// writeJSON writes data as a JSON response, logging encoding failures. The response is encoded in
+// a pooled buffer and written at once
+func writeJSON(ctx context.Context, l *zap.Logger, w http.ResponseWriter, status int, data interface{}) {
+	e := getEncoder()
+	defer putEncoder(e)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		logger.LogError(h.logger, ctx, "Erro ao codificar resposta JSON", err)
+	if err := e.encoder.Encode(data); err != nil {
+		logger.LogError(l, ctx, "Erro ao codificar resposta JSON", err)
+		return
+	}
+	if _, err := w.Write(e.buf.Bytes()); err != nil {
+		logger.LogError(l, ctx, "Erro ao escrever resposta JSON", err)
 	}
 }
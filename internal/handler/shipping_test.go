@@ -5,15 +5,30 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/experiment"
 	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/quotestats"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
 	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/strictjson"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/rbonfanti/shipping-calculator/pkg/quotetoken"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
 )
 
@@ -59,7 +74,7 @@ func TestNewShippingHandler(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 
 	// Act
-	handler := NewShippingHandler(shippingService, logger)
+	handler := NewShippingHandler(shippingService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, logger)
 
 	// Assert
 	assert.NotNil(t, handler)
@@ -71,7 +86,7 @@ func TestCalculateShipping_ValidRequest_Standard(t *testing.T) {
 	// Arrange
 	mockService := new(MockShippingService)
 	logger := zaptest.NewLogger(t)
-	handler := NewShippingHandler(mockService, logger)
+	handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, logger)
 
 	reqBody := model.CalculateShippingRequest{
 		OriginZipcode:      "12345678",
@@ -90,12 +105,12 @@ func TestCalculateShipping_ValidRequest_Standard(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	expectedResponse := &model.CalculateShippingResponse{
-		ShippingCost:          1250.0,
+		ShippingCost:          money.FromMinor(1250),
 		EstimatedDeliveryTime: "2 dias",
 		AvailableServices:     []string{"standard", "express"},
 		ShippingOptions: []model.ShippingOption{
-			{Service: "standard", Cost: 1250.0, Time: "2 dias"},
-			{Service: "express", Cost: 1875.0, Time: "1 dia"},
+			{Service: "standard", Cost: money.FromMinor(1250), Time: "2 dias"},
+			{Service: "express", Cost: money.FromMinor(1875), Time: "1 dia"},
 		},
 	}
 
@@ -120,11 +135,53 @@ func TestCalculateShipping_ValidRequest_Standard(t *testing.T) {
 	assert.Equal(t, expectedResponse.EstimatedDeliveryTime, response.EstimatedDeliveryTime)
 }
 
+func TestCalculateShippingV2(t *testing.T) {
+	// Arrange
+	mockService := new(MockShippingService)
+	handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, zaptest.NewLogger(t))
+	body := `{"origin_zipcode": "12345678", "destination_zipcode": "87654321", "weight": 1, "dimensions": {"length": 10, "width": 10, "height": 10}}`
+	req := addRequestID(httptest.NewRequest(http.MethodPost, "/v2/calculate", bytes.NewReader([]byte(body))))
+	w := httptest.NewRecorder()
+
+	mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(&model.CalculateShippingResponse{
+		Currency:              "BRL",
+		ShippingCost:          money.FromMinor(1250),
+		EstimatedDeliveryTime: "2 dias",
+		AvailableServices:     []string{"standard"},
+		ShippingOptions:       []model.ShippingOption{{Service: "standard", Cost: money.FromMinor(1250), Time: "2 dias", EstimatedDays: 2}},
+		Breakdown:             &model.CostBreakdown{BaseCost: money.FromMinor(1000), WeightSurcharge: money.FromMinor(250), Total: money.FromMinor(1250)},
+		SelectedService:       "standard",
+	}, nil).Once()
+
+	// Act
+	handler.CalculateShippingV2(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+	assert.JSONEq(t, `{
+		"total": {"amount": 1250, "currency": "BRL"},
+		"selected_service": "standard",
+		"estimated_delivery_time": "2 dias",
+		"service_levels": [{"service": "standard", "price": {"amount": 1250, "currency": "BRL"}, "delivery_time": "2 dias", "estimated_days": 2}],
+		"breakdown": {
+			"base_cost": {"amount": 1000, "currency": "BRL"},
+			"weight_surcharge": {"amount": 250, "currency": "BRL"},
+			"volume_surcharge": {"amount": 0, "currency": "BRL"},
+			"package_type_surcharge": {"amount": 0, "currency": "BRL"},
+			"delivery_type_adjustment": {"amount": 0, "currency": "BRL"},
+			"express_surcharge": {"amount": 0, "currency": "BRL"},
+			"unrounded_total": {"amount": 0, "currency": "BRL"},
+			"total": {"amount": 1250, "currency": "BRL"}
+		}
+	}`, w.Body.String())
+}
+
 func TestCalculateShipping_InvalidJSON(t *testing.T) {
 	// Arrange
 	mockService := new(MockShippingService)
 	logger := zaptest.NewLogger(t)
-	handler := NewShippingHandler(mockService, logger)
+	handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, logger)
 
 	req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader([]byte("invalid json")))
 	req = addRequestID(req)
@@ -143,11 +200,105 @@ func TestCalculateShipping_InvalidJSON(t *testing.T) {
 	assert.Equal(t, "invalid request body", errorResponse["error"])
 }
 
+func TestCalculateShipping_StrictSchema(t *testing.T) {
+	tests := []struct {
+		name      string
+		strict    bool
+		body      string
+		wantCalls int
+		wantBody  map[string]string
+	}{
+		{
+			name:      "typo with suggestion",
+			strict:    true,
+			body:      `{"origin_zipcode": "12345678", "destination_zipcode": "12345678", "weigth": 1}`,
+			wantBody:  map[string]string{"error": `unknown field "weigth", did you mean "weight"?`, "field": "weigth", "suggestion": "weight"},
+			wantCalls: 0,
+		},
+		{
+			name:      "nested typo",
+			strict:    true,
+			body:      `{"weight": 1, "dimensions": {"lenght": 10}}`,
+			wantBody:  map[string]string{"error": `unknown field "dimensions.lenght", did you mean "dimensions.length"?`, "field": "dimensions.lenght", "suggestion": "dimensions.length"},
+			wantCalls: 0,
+		},
+		{
+			name:      "unknown field without suggestion",
+			strict:    true,
+			body:      `{"weight": 1, "gift_message": "parabéns"}`,
+			wantBody:  map[string]string{"error": `unknown field "gift_message"`, "field": "gift_message"},
+			wantCalls: 0,
+		},
+		{
+			name:      "lenient mode ignores unknown fields",
+			strict:    false,
+			body:      `{"weight": 1, "weigth": 1}`,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockShippingService)
+			mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(nil, &service.ValidationError{Field: "origin_zipcode", Err: errors.New("invalid origin_zipcode")})
+			handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, zaptest.NewLogger(t))
+
+			req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewBufferString(tt.body)))
+			req = req.WithContext(strictjson.NewContext(req.Context(), tt.strict))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.CalculateShipping(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertNumberOfCalls(t, "CalculateShipping", tt.wantCalls)
+			if tt.wantBody != nil {
+				var errorResponse map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+				assert.Equal(t, tt.wantBody, errorResponse)
+			}
+		})
+	}
+}
+
+func TestCalculateShipping_MissingFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantError string
+	}{
+		{"missing weight", `{"origin_zipcode": "12345678", "destination_zipcode": "12345678", "dimensions": {"length": 10, "width": 10, "height": 10}}`, "invalid weight: weight is required"},
+		{"zero weight", `{"origin_zipcode": "12345678", "destination_zipcode": "12345678", "weight": 0, "dimensions": {"length": 10, "width": 10, "height": 10}}`, "invalid weight: weight must be greater than 0"},
+		{"missing dimensions", `{"origin_zipcode": "12345678", "destination_zipcode": "12345678", "weight": 1}`, "invalid dimensions: dimensions is required"},
+		{"missing height", `{"origin_zipcode": "12345678", "destination_zipcode": "12345678", "weight": 1, "dimensions": {"length": 10, "width": 10}}`, "invalid dimensions: dimensions.height is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewShippingHandler(service.NewShippingService(), nil, repository.QuoteConfig{}, nil, nil, nil, nil, zaptest.NewLogger(t))
+			req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewBufferString(tt.body)))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.CalculateShipping(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var errorResponse map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+			assert.Equal(t, tt.wantError, errorResponse["error"])
+		})
+	}
+}
+
 func TestCalculateShipping_EmptyBody(t *testing.T) {
 	// Arrange
 	mockService := new(MockShippingService)
 	logger := zaptest.NewLogger(t)
-	handler := NewShippingHandler(mockService, logger)
+	handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, logger)
 
 	req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader([]byte("")))
 	req = addRequestID(req)
@@ -170,7 +321,7 @@ func TestCalculateShipping_ServiceError(t *testing.T) {
 	// Arrange
 	mockService := new(MockShippingService)
 	logger := zaptest.NewLogger(t)
-	handler := NewShippingHandler(mockService, logger)
+	handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, logger)
 
 	reqBody := model.CalculateShippingRequest{
 		OriginZipcode:      "12345678",
@@ -188,7 +339,7 @@ func TestCalculateShipping_ServiceError(t *testing.T) {
 	req = addRequestID(req)
 	w := httptest.NewRecorder()
 
-	expectedError := errors.New("invalid origin_zipcode: origin_zipcode is required")
+	expectedError := &service.ValidationError{Field: "origin_zipcode", Err: errors.New("invalid origin_zipcode: origin_zipcode is required")}
 	mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
 
 	// Act
@@ -204,11 +355,95 @@ func TestCalculateShipping_ServiceError(t *testing.T) {
 	assert.Equal(t, expectedError.Error(), errorResponse["error"])
 }
 
+func TestCalculateShipping_NotServiceable(t *testing.T) {
+	// Arrange
+	mockService := new(MockShippingService)
+	handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, zaptest.NewLogger(t))
+	req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewBufferString(`{"destination_zipcode":"53990000"}`)))
+	w := httptest.NewRecorder()
+
+	serviceErr := &service.ValidationError{Field: "destination_zipcode", Err: fmt.Errorf("%w by standard: restricted area noronha", service.ErrNotServiceable)}
+	mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(nil, serviceErr).Once()
+
+	// Act
+	handler.CalculateShipping(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var errorResponse map[string]string
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+	assert.Equal(t, map[string]string{
+		"error": "destination is not serviceable by standard: restricted area noronha",
+		"code":  "NOT_SERVICEABLE",
+	}, errorResponse)
+}
+
+func TestCalculateShipping_ErrorStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   map[string]string
+	}{
+		{
+			name:       "validation",
+			err:        &service.ValidationError{Field: "weight", Err: errors.New("invalid weight: weight must be positive")},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   map[string]string{"error": "invalid weight: weight must be positive"},
+		},
+		{
+			name:       "not serviceable",
+			err:        &service.ValidationError{Field: "destination_zipcode", Err: fmt.Errorf("%w by standard: restricted area north", service.ErrNotServiceable)},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   map[string]string{"error": "destination is not serviceable by standard: restricted area north", "code": errorCodeNotServiceable},
+		},
+		{
+			name:       "provider timeout",
+			err:        fmt.Errorf("carrier pricing failed: %w", context.DeadlineExceeded),
+			wantStatus: http.StatusGatewayTimeout,
+			wantBody:   map[string]string{"error": "shipping calculation timed out"},
+		},
+		{
+			name:       "provider error",
+			err:        fmt.Errorf("carrier pricing failed: %w: unexpected status 503", pricing.ErrCarrierRate),
+			wantStatus: http.StatusBadGateway,
+			wantBody:   map[string]string{"error": "carrier rate unavailable"},
+		},
+		{
+			name:       "internal",
+			err:        errors.New("rate table without currency BRL"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   map[string]string{"error": "failed to calculate shipping"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockShippingService)
+			mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(nil, tt.err)
+			handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, zaptest.NewLogger(t))
+			body := `{"origin_zipcode":"12345678","destination_zipcode":"87654321","weight":1,"dimensions":{"length":10,"width":10,"height":10}}`
+			req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewBufferString(body)))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.CalculateShipping(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var errorResponse map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+			assert.Equal(t, tt.wantBody, errorResponse)
+		})
+	}
+}
+
 func TestCalculateShipping_ValidationError(t *testing.T) {
 	// Arrange
 	mockService := new(MockShippingService)
 	logger := zaptest.NewLogger(t)
-	handler := NewShippingHandler(mockService, logger)
+	handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, logger)
 
 	reqBody := model.CalculateShippingRequest{
 		OriginZipcode:      "",
@@ -226,7 +461,7 @@ func TestCalculateShipping_ValidationError(t *testing.T) {
 	req = addRequestID(req)
 	w := httptest.NewRecorder()
 
-	expectedError := errors.New("invalid origin_zipcode: origin_zipcode is required")
+	expectedError := &service.ValidationError{Field: "origin_zipcode", Err: errors.New("invalid origin_zipcode: origin_zipcode is required")}
 	mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
 
 	// Act
@@ -246,7 +481,7 @@ func TestCalculateShipping_ExpressShipping(t *testing.T) {
 	// Arrange
 	mockService := new(MockShippingService)
 	logger := zaptest.NewLogger(t)
-	handler := NewShippingHandler(mockService, logger)
+	handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, logger)
 
 	reqBody := model.CalculateShippingRequest{
 		OriginZipcode:      "12345678",
@@ -265,12 +500,12 @@ func TestCalculateShipping_ExpressShipping(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	expectedResponse := &model.CalculateShippingResponse{
-		ShippingCost:          1875.0,
+		ShippingCost:          money.FromMinor(1875),
 		EstimatedDeliveryTime: "1 dia",
 		AvailableServices:     []string{"standard", "express"},
 		ShippingOptions: []model.ShippingOption{
-			{Service: "standard", Cost: 1250.0, Time: "2 dias"},
-			{Service: "express", Cost: 1875.0, Time: "1 dia"},
+			{Service: "standard", Cost: money.FromMinor(1250), Time: "2 dias"},
+			{Service: "express", Cost: money.FromMinor(1875), Time: "1 dia"},
 		},
 	}
 
@@ -296,7 +531,7 @@ func TestCalculateShipping_NilBody(t *testing.T) {
 	// Arrange
 	mockService := new(MockShippingService)
 	logger := zaptest.NewLogger(t)
-	handler := NewShippingHandler(mockService, logger)
+	handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, logger)
 
 	req := httptest.NewRequest(http.MethodPost, "/calculate", nil)
 	req = addRequestID(req)
@@ -319,7 +554,7 @@ func TestWriteJSON_ErrorEncoding(t *testing.T) {
 	// Arrange
 	mockService := new(MockShippingService)
 	logger := zaptest.NewLogger(t)
-	handler := NewShippingHandler(mockService, logger)
+	handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, logger)
 	ctx := context.Background()
 	w := httptest.NewRecorder()
 	invalidData := make(chan int)
@@ -330,3 +565,694 @@ func TestWriteJSON_ErrorEncoding(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+func TestCalculateShipping_PersistsQuote(t *testing.T) {
+	// Arrange
+	mockService := new(MockShippingService)
+	quotes := repository.NewMemoryQuoteRepository()
+	handler := NewShippingHandler(mockService, quotes, repository.QuoteConfig{}, nil, nil, nil, nil, zaptest.NewLogger(t))
+
+	reqBody := model.CalculateShippingRequest{
+		OriginZipcode:      "12345678",
+		DestinationZipcode: "87654321",
+		Weight:             1.0,
+		Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+	}
+	bodyBytes, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader(bodyBytes))
+	req = addRequestID(req)
+	w := httptest.NewRecorder()
+
+	mockService.On("CalculateShipping", mock.Anything, mock.Anything).
+		Return(&model.CalculateShippingResponse{ShippingCost: money.FromMinor(1250), PricingVersion: "2025.03", FuelIndex: &model.FuelIndex{Rate: 0.12, EffectiveDate: "2025-04-07"}}, nil).Once()
+
+	// Act
+	handler.CalculateShipping(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response model.CalculateShippingResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.QuoteID)
+
+	quote, err := quotes.Get(context.Background(), response.QuoteID)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMinor(1250), quote.Response.ShippingCost)
+	assert.Equal(t, "12345678", quote.Request.OriginZipcode)
+	assert.Equal(t, "2025.03", quote.PricingVersion)
+	assert.Equal(t, &model.FuelIndex{Rate: 0.12, EffectiveDate: "2025-04-07"}, quote.FuelIndex)
+}
+
+func TestCalculateShipping_PublishesQuoteCreated(t *testing.T) {
+	// Arrange
+	mockService := new(MockShippingService)
+	publisher := events.NewMemoryPublisher()
+	handler := NewShippingHandler(mockService, repository.NewMemoryQuoteRepository(), repository.QuoteConfig{}, publisher, nil, nil, nil, zaptest.NewLogger(t))
+
+	bodyBytes, _ := json.Marshal(model.CalculateShippingRequest{OriginZipcode: "12345678", DestinationZipcode: "87654321"})
+	req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader(bodyBytes))
+	req = addRequestID(req)
+	w := httptest.NewRecorder()
+
+	mockService.On("CalculateShipping", mock.Anything, mock.Anything).
+		Return(&model.CalculateShippingResponse{ShippingCost: money.FromMinor(1250)}, nil).Once()
+
+	// Act
+	handler.CalculateShipping(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response model.CalculateShippingResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	published := publisher.Events()
+	if assert.Len(t, published, 1) {
+		assert.Equal(t, events.QuoteCreated, published[0].Type)
+		assert.Equal(t, response.QuoteID, published[0].Subject)
+		data := published[0].Data.(model.QuoteCreated)
+		assert.Equal(t, "87654321", data.Request.DestinationZipcode)
+		assert.Equal(t, response.QuoteID, data.Response.QuoteID)
+		assert.Equal(t, money.FromMinor(1250), data.Response.ShippingCost)
+	}
+}
+
+func TestCalculateShipping_QuotePersistenceFailure(t *testing.T) {
+	// Arrange
+	mockService := new(MockShippingService)
+	publisher := events.NewMemoryPublisher()
+	handler := NewShippingHandler(mockService, failingQuoteRepository{}, repository.QuoteConfig{}, publisher, nil, nil, nil, zaptest.NewLogger(t))
+
+	bodyBytes, _ := json.Marshal(model.CalculateShippingRequest{OriginZipcode: "12345678"})
+	req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader(bodyBytes))
+	req = addRequestID(req)
+	w := httptest.NewRecorder()
+
+	mockService.On("CalculateShipping", mock.Anything, mock.Anything).
+		Return(&model.CalculateShippingResponse{ShippingCost: money.FromMinor(1250)}, nil).Once()
+
+	// Act
+	handler.CalculateShipping(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response model.CalculateShippingResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.QuoteID)
+	assert.Equal(t, money.FromMinor(1250), response.ShippingCost)
+	assert.Empty(t, publisher.Events(), "quotes that could not be stored are not published")
+}
+
+func TestCalculateShipping_SetsExpiration(t *testing.T) {
+	// Arrange
+	mockService := new(MockShippingService)
+	quotes := repository.NewMemoryQuoteRepository()
+	handler := NewShippingHandler(mockService, quotes, repository.QuoteConfig{TTL: 30 * time.Minute}, nil, nil, nil, nil, zaptest.NewLogger(t))
+
+	bodyBytes, _ := json.Marshal(model.CalculateShippingRequest{OriginZipcode: "12345678"})
+	req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader(bodyBytes))
+	req = addRequestID(req)
+	w := httptest.NewRecorder()
+
+	mockService.On("CalculateShipping", mock.Anything, mock.Anything).
+		Return(&model.CalculateShippingResponse{ShippingCost: money.FromMinor(1250)}, nil).Once()
+	before := time.Now()
+
+	// Act
+	handler.CalculateShipping(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response model.CalculateShippingResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.NotNil(t, response.ExpiresAt) {
+		assert.WithinRange(t, *response.ExpiresAt, before.Add(30*time.Minute).Truncate(time.Second), time.Now().Add(30*time.Minute))
+	}
+
+	quote, err := quotes.Get(context.Background(), response.QuoteID)
+	assert.NoError(t, err)
+	assert.True(t, quote.ExpiresAt.Equal(*response.ExpiresAt))
+}
+
+func TestCalculateShipping_Deterministic(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 3, 5, 13, 0, 0, 0, time.UTC)
+	calculate := func() (model.CalculateShippingResponse, *repository.Quote) {
+		mockService := new(MockShippingService)
+		mockService.On("CalculateShipping", mock.Anything, mock.Anything).
+			Return(&model.CalculateShippingResponse{ShippingCost: money.FromMinor(1250)}, nil).Once()
+		quotes := repository.NewMemoryQuoteRepository()
+		handler := NewShippingHandler(mockService, quotes, repository.QuoteConfig{TTL: 30 * time.Minute}, nil, nil, nil, nil, zaptest.NewLogger(t)).
+			WithDeterminism(determinism.FixedClock{Time: now}, determinism.NewSeededIDGenerator(42))
+
+		bodyBytes, _ := json.Marshal(model.CalculateShippingRequest{OriginZipcode: "12345678"})
+		req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader(bodyBytes)))
+		w := httptest.NewRecorder()
+
+		handler.CalculateShipping(w, req)
+
+		var response model.CalculateShippingResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		quote, err := quotes.Get(context.Background(), response.QuoteID)
+		assert.NoError(t, err)
+		return response, quote
+	}
+
+	// Act
+	first, quote := calculate()
+	second, _ := calculate()
+
+	// Assert
+	assert.Equal(t, first, second)
+	assert.Equal(t, determinism.NewSeededIDGenerator(42).NewID(), first.QuoteID)
+	assert.True(t, quote.CreatedAt.Equal(now))
+	if assert.NotNil(t, first.ExpiresAt) {
+		assert.True(t, first.ExpiresAt.Equal(now.Add(30*time.Minute)))
+	}
+}
+
+func TestCalculateShipping_RecordsStats(t *testing.T) {
+	// Arrange
+	mockService := new(MockShippingService)
+	mockService.On("CalculateShipping", mock.Anything, mock.MatchedBy(func(req *model.CalculateShippingRequest) bool {
+		return req.DestinationZipcode == "20040-020"
+	})).Return(&model.CalculateShippingResponse{ShippingCost: money.FromMinor(1250), Currency: "BRL"}, nil).Once()
+	mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(nil, errors.New("invalid weight: weight must be positive")).Once()
+	stats := quotestats.NewWindow(time.Minute)
+	handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, nil, nil, zaptest.NewLogger(t)).WithStats(stats)
+
+	// Act
+	for _, body := range []string{
+		`{"origin_zipcode":"01310-100","destination_zipcode":"20040-020","weight":1}`,
+		`{"origin_zipcode":"01310-100","destination_zipcode":"20040-020","weight":-1}`,
+		`{invalid`,
+	} {
+		req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewBufferString(body)))
+		handler.CalculateShippingV2(httptest.NewRecorder(), req)
+	}
+
+	// Assert
+	mockService.AssertExpectations(t)
+	snapshot := stats.Snapshot()
+	assert.Equal(t, int64(1), snapshot.Quotes)
+	assert.Equal(t, int64(2), snapshot.Errors)
+	assert.Equal(t, []quotestats.CurrencyCost{{Currency: "BRL", Quotes: 1, Average: money.FromMinor(1250)}}, snapshot.AverageCost)
+	assert.Equal(t, []quotestats.RegionCount{{Region: "RJ", Quotes: 1}}, snapshot.TopRegions)
+}
+
+func TestPreviewShipping(t *testing.T) {
+	// Arrange
+	publisher := events.NewMemoryPublisher()
+	handler := NewShippingHandler(service.NewShippingService(), failingQuoteRepository{}, repository.QuoteConfig{TTL: 30 * time.Minute}, publisher, nil, nil, nil, zaptest.NewLogger(t))
+
+	bodyBytes, _ := json.Marshal(v1.CalculateShippingRequest{
+		OriginZipcode:      "12345678",
+		DestinationZipcode: "12345678",
+		Weight:             1.0,
+		Dimensions:         v1.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+	})
+	req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate/preview", bytes.NewReader(bodyBytes)))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.PreviewShipping(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var preview v1.QuotePreview
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	if assert.NotNil(t, preview.Quote) {
+		assert.Equal(t, 1250.0, preview.Quote.ShippingCost)
+		assert.Empty(t, preview.Quote.QuoteID, "previews are not persisted")
+		assert.Nil(t, preview.Quote.ExpiresAt)
+		assert.NotNil(t, preview.Quote.Breakdown)
+	}
+	assert.Contains(t, preview.Trace, v1.DecisionStep{Step: service.StepStrategy, Detail: "standard priced with formula"})
+	assert.Empty(t, publisher.Events())
+}
+
+func TestPreviewShipping_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantError string
+	}{
+		{"invalid body", "{", "invalid request body"},
+		{"invalid request", `{"origin_zipcode": "123", "destination_zipcode": "87654321"}`, "invalid origin_zipcode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewShippingHandler(service.NewShippingService(), nil, repository.QuoteConfig{}, nil, nil, nil, nil, zaptest.NewLogger(t))
+			req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate/preview", bytes.NewBufferString(tt.body)))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.PreviewShipping(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantError)
+		})
+	}
+}
+
+func serveRevalidation(t *testing.T, h *ShippingHandler, id string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := chi.NewRouter()
+	r.Post("/quotes/{id}/revalidate", h.RevalidateQuote)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, addRequestID(httptest.NewRequest(http.MethodPost, "/quotes/"+id+"/revalidate", nil)))
+	return w
+}
+
+func TestRevalidateQuote(t *testing.T) {
+	tests := []struct {
+		name             string
+		expiresAt        time.Time
+		currentCost      float64
+		wantExpired      bool
+		wantPriceChanged bool
+	}{
+		{"valid quote with the same price", time.Now().Add(time.Hour), 1250, false, false},
+		{"expired quote with the same price", time.Now().Add(-time.Hour), 1250, true, false},
+		{"expired quote with a new price", time.Now().Add(-time.Hour), 1400, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockShippingService)
+			quotes := repository.NewMemoryQuoteRepository()
+			handler := NewShippingHandler(mockService, quotes, repository.QuoteConfig{TTL: 30 * time.Minute}, nil, nil, nil, nil, zaptest.NewLogger(t))
+			_ = quotes.Save(context.Background(), &repository.Quote{
+				ID:             "q1",
+				Request:        model.CalculateShippingRequest{OriginZipcode: "12345678", DestinationZipcode: "87654321"},
+				Response:       model.CalculateShippingResponse{QuoteID: "q1", ShippingCost: money.FromMinor(1250), PricingVersion: "2025.03"},
+				ExpiresAt:      tt.expiresAt,
+				PricingVersion: "2025.03",
+			})
+
+			mockService.On("CalculateShipping", mock.Anything, mock.MatchedBy(func(req *model.CalculateShippingRequest) bool {
+				return req.OriginZipcode == "12345678" && req.DestinationZipcode == "87654321"
+			})).Return(&model.CalculateShippingResponse{ShippingCost: money.FromMinor(tt.currentCost), PricingVersion: "2025.04"}, nil).Once()
+
+			// Act
+			w := serveRevalidation(t, handler, "q1")
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var revalidation v1.QuoteRevalidation
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &revalidation))
+			assert.Equal(t, "q1", revalidation.QuoteID)
+			assert.Equal(t, tt.wantExpired, revalidation.Expired)
+			assert.Equal(t, tt.wantPriceChanged, revalidation.PriceChanged)
+			assert.Equal(t, 1250.0, revalidation.PreviousCost)
+			assert.Equal(t, tt.currentCost, revalidation.Quote.ShippingCost)
+			assert.NotEmpty(t, revalidation.Quote.QuoteID)
+			assert.NotEqual(t, "q1", revalidation.Quote.QuoteID)
+			assert.NotNil(t, revalidation.Quote.ExpiresAt)
+
+			original, err := quotes.Get(context.Background(), "q1")
+			assert.NoError(t, err)
+			assert.Equal(t, money.FromMinor(1250), original.Response.ShippingCost)
+			assert.Equal(t, "2025.03", original.PricingVersion)
+			assert.Equal(t, tt.expiresAt, original.ExpiresAt)
+
+			revalidated, err := quotes.Get(context.Background(), revalidation.Quote.QuoteID)
+			assert.NoError(t, err)
+			assert.Equal(t, money.FromMinor(tt.currentCost), revalidated.Response.ShippingCost)
+			assert.Equal(t, "2025.04", revalidated.PricingVersion)
+			assert.Equal(t, original.Request, revalidated.Request)
+			assert.False(t, revalidated.Expired(time.Now()))
+		})
+	}
+}
+
+func TestRevalidateQuote_PinsExperimentArm(t *testing.T) {
+	// Arrange
+	mockService := new(MockShippingService)
+	quotes := repository.NewMemoryQuoteRepository()
+	handler := NewShippingHandler(mockService, quotes, repository.QuoteConfig{}, nil, nil, nil, nil, zaptest.NewLogger(t))
+	arm := &model.ExperimentAssignment{Name: "volume-curve", Arm: experiment.ArmTreatment}
+	_ = quotes.Save(context.Background(), &repository.Quote{ID: "q1", Response: model.CalculateShippingResponse{Experiment: arm}})
+
+	var pinned context.Context
+	mockService.On("CalculateShipping", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		pinned = args.Get(0).(context.Context)
+	}).Return(&model.CalculateShippingResponse{ShippingCost: money.FromMinor(1250), Experiment: arm}, nil).Once()
+
+	// Act
+	w := serveRevalidation(t, handler, "q1")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.NotNil(t, pinned) {
+		shippingService := service.NewShippingServiceWithConfig(service.Config{
+			Experiment: &experiment.Experiment{Name: "volume-curve", Fraction: 0, Treatment: pricing.DefaultConfig()},
+		})
+		response, err := shippingService.CalculateShipping(pinned, &model.CalculateShippingRequest{
+			OriginZipcode:      "12345678",
+			DestinationZipcode: "12345678",
+			Weight:             1,
+			Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, arm, response.Experiment)
+	}
+}
+
+func TestRevalidateQuote_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		quotes     repository.QuoteRepository
+		serviceErr error
+		wantStatus int
+		wantError  string
+	}{
+		{"unknown quote", repository.NewMemoryQuoteRepository(), nil, http.StatusNotFound, "quote not found"},
+		{"persistence disabled", nil, nil, http.StatusNotFound, "quote not found"},
+		{"quote can no longer be priced", nil, &service.ValidationError{Field: "destination_zipcode", Err: errors.New("invalid destination_zipcode")}, http.StatusBadRequest, "invalid destination_zipcode"},
+		{"destination no longer served", nil, &service.ValidationError{Field: "destination_zipcode", Err: service.ErrNotServiceable}, http.StatusUnprocessableEntity, service.ErrNotServiceable.Error()},
+		{"provider timeout", nil, context.DeadlineExceeded, http.StatusGatewayTimeout, "shipping calculation timed out"},
+		{"internal error", nil, errors.New("connection refused"), http.StatusInternalServerError, "failed to calculate shipping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockShippingService)
+			quotes := tt.quotes
+			if tt.serviceErr != nil {
+				memory := repository.NewMemoryQuoteRepository()
+				_ = memory.Save(context.Background(), &repository.Quote{ID: "q1"})
+				quotes = memory
+				mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(nil, tt.serviceErr).Once()
+			}
+			handler := NewShippingHandler(mockService, quotes, repository.QuoteConfig{TTL: time.Minute}, nil, nil, nil, nil, zaptest.NewLogger(t))
+
+			// Act
+			w := serveRevalidation(t, handler, "q1")
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var body map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantError, body["error"])
+		})
+	}
+}
+
+func TestRevalidateQuote_OtherTenant(t *testing.T) {
+	tests := []struct {
+		name        string
+		quoteTenant string
+		tenantID    string
+		wantStatus  int
+	}{
+		{"quote of the tenant", "acme", "acme", http.StatusOK},
+		{"quote of another tenant", "acme", "globex", http.StatusNotFound},
+		{"quote of a tenant requested without tenant", "acme", tenant.Default, http.StatusNotFound},
+		{"quote saved before tenants", "", tenant.Default, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockShippingService)
+			mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(&model.CalculateShippingResponse{ShippingCost: money.FromMinor(1250)}, nil).Maybe()
+			quotes := repository.NewMemoryQuoteRepository()
+			_ = quotes.Save(context.Background(), &repository.Quote{ID: "q1", Tenant: tt.quoteTenant})
+			handler := NewShippingHandler(mockService, quotes, repository.QuoteConfig{}, nil, nil, nil, nil, zaptest.NewLogger(t))
+
+			r := chi.NewRouter()
+			r.Post("/quotes/{id}/revalidate", handler.RevalidateQuote)
+			req := addRequestID(httptest.NewRequest(http.MethodPost, "/quotes/q1/revalidate", nil))
+			req = req.WithContext(tenant.NewContext(req.Context(), tt.tenantID))
+			w := httptest.NewRecorder()
+
+			// Act
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestCalculateShipping_SavesQuoteTenant(t *testing.T) {
+	// Arrange
+	mockService := new(MockShippingService)
+	mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(&model.CalculateShippingResponse{ShippingCost: money.FromMinor(1250)}, nil).Once()
+	quotes := repository.NewMemoryQuoteRepository()
+	handler := NewShippingHandler(mockService, quotes, repository.QuoteConfig{}, nil, nil, nil, nil, zaptest.NewLogger(t))
+	body := `{"origin_zipcode":"12345678","destination_zipcode":"87654321","weight":1,"dimensions":{"length":10,"width":10,"height":10}}`
+	req := addRequestID(httptest.NewRequest(http.MethodPost, "/shipping/calculate", bytes.NewBufferString(body)))
+	req = req.WithContext(tenant.NewContext(req.Context(), "acme"))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.CalculateShipping(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response v1.CalculateShippingResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	quote, err := quotes.Get(context.Background(), response.QuoteID)
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", quote.Tenant)
+}
+
+func TestCalculateShipping_SignsOptions(t *testing.T) {
+	// Arrange
+	signer, _ := quotetoken.NewSigner([]quotetoken.Key{{ID: "k1", Secret: bytes.Repeat([]byte("s"), quotetoken.MinSecretSize)}})
+	mockService := new(MockShippingService)
+	mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(&model.CalculateShippingResponse{
+		Currency:     "BRL",
+		ShippingCost: money.FromMinor(1250),
+		ShippingOptions: []model.ShippingOption{
+			{Service: "standard", Cost: money.FromMinor(1250), Time: "5 days"},
+			{Service: "express", Cost: money.FromMinor(1950), Time: "2 days"},
+		},
+	}, nil).Once()
+	handler := NewShippingHandler(mockService, repository.NewMemoryQuoteRepository(), repository.QuoteConfig{TTL: 30 * time.Minute}, nil, signer, nil, nil, zaptest.NewLogger(t))
+	req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewBufferString(`{"origin_zipcode":"12345678"}`)))
+	req = req.WithContext(tenant.NewContext(req.Context(), "acme"))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.CalculateShipping(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response v1.CalculateShippingResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.ShippingOptions, 2) {
+		claims, err := signer.Verify(response.ShippingOptions[1].Token, time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, response.QuoteID, claims.QuoteID)
+		assert.Equal(t, "acme", claims.Tenant)
+		assert.Equal(t, "express", claims.Service)
+		assert.Equal(t, "BRL", claims.Currency)
+		assert.Equal(t, quotetoken.PriceFromMinor(1950), claims.Price)
+		assert.Equal(t, response.ExpiresAt.Unix(), claims.ExpiresAt.Unix())
+		assert.NotEqual(t, response.ShippingOptions[0].Token, response.ShippingOptions[1].Token)
+	}
+}
+
+func TestCalculateShipping_DegradedQuoteNotPersisted(t *testing.T) {
+	// Arrange
+	signer, _ := quotetoken.NewSigner([]quotetoken.Key{{ID: "k1", Secret: bytes.Repeat([]byte("s"), quotetoken.MinSecretSize)}})
+	mockService := new(MockShippingService)
+	mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(&model.CalculateShippingResponse{
+		ShippingCost:    money.FromMinor(1250),
+		ShippingOptions: []model.ShippingOption{{Service: "standard", Cost: money.FromMinor(1250), Time: "5 days"}},
+		Degraded:        true,
+	}, nil).Once()
+	publisher := events.NewMemoryPublisher()
+	handler := NewShippingHandler(mockService, repository.NewMemoryQuoteRepository(), repository.QuoteConfig{}, publisher, signer, nil, nil, zaptest.NewLogger(t))
+	req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewBufferString(`{"origin_zipcode":"12345678"}`)))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.CalculateShipping(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response v1.CalculateShippingResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Degraded)
+	assert.Empty(t, response.QuoteID, "degraded quotes cannot be booked")
+	if assert.Len(t, response.ShippingOptions, 1) {
+		assert.Empty(t, response.ShippingOptions[0].Token)
+	}
+	assert.Empty(t, publisher.Events())
+}
+
+func TestCalculateShipping_WithoutTokens(t *testing.T) {
+	signer, _ := quotetoken.NewSigner([]quotetoken.Key{{ID: "k1", Secret: bytes.Repeat([]byte("s"), quotetoken.MinSecretSize)}})
+
+	tests := []struct {
+		name   string
+		quotes repository.QuoteRepository
+		signer QuoteSigner
+	}{
+		{"signing disabled", repository.NewMemoryQuoteRepository(), nil},
+		{"persistence disabled", nil, signer},
+		{"quote not persisted", failingQuoteRepository{}, signer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockShippingService)
+			mockService.On("CalculateShipping", mock.Anything, mock.Anything).Return(&model.CalculateShippingResponse{
+				ShippingCost:    money.FromMinor(1250),
+				ShippingOptions: []model.ShippingOption{{Service: "standard", Cost: money.FromMinor(1250), Time: "5 days"}},
+			}, nil).Once()
+			handler := NewShippingHandler(mockService, tt.quotes, repository.QuoteConfig{}, nil, tt.signer, nil, nil, zaptest.NewLogger(t))
+			req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewBufferString(`{"origin_zipcode":"12345678"}`)))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.CalculateShipping(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+			var response v1.CalculateShippingResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if assert.Len(t, response.ShippingOptions, 1) {
+				assert.Empty(t, response.ShippingOptions[0].Token)
+			}
+		})
+	}
+}
+
+func TestCalculateShipping_AsOf(t *testing.T) {
+	// Arrange
+	versions := repository.NewMemoryPricingVersionRepository()
+	old := service.NewShippingService().Pricing()
+	old.Version = "2024.01"
+	_ = versions.Save(context.Background(), repository.PricingVersion{Version: "2024.01", EffectiveFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Config: old})
+	_ = versions.Save(context.Background(), repository.PricingVersion{Version: "2024.06", EffectiveFrom: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Config: old})
+	mockService := new(MockShippingService)
+	mockService.On("CalculateShipping", mock.MatchedBy(func(ctx context.Context) bool { return service.IsDryRun(ctx) }), mock.Anything).
+		Return(&model.CalculateShippingResponse{ShippingCost: money.FromMinor(1100), PricingVersion: "2024.01"}, nil).Once()
+	quotes := repository.NewMemoryQuoteRepository()
+	handler := NewShippingHandler(mockService, quotes, repository.QuoteConfig{}, nil, nil, versions, nil, zaptest.NewLogger(t))
+	body := `{"origin_zipcode":"12345678","destination_zipcode":"87654321","weight":1,"dimensions":{"length":10,"width":10,"height":10}}`
+	req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate?as_of=2024-05-01", bytes.NewBufferString(body)))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.CalculateShipping(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response v1.CalculateShippingResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "2024.01", response.PricingVersion)
+	assert.Empty(t, response.QuoteID, "repriced quotes are not persisted")
+	mockService.AssertExpectations(t)
+}
+
+func TestCalculateShipping_AsOfErrors(t *testing.T) {
+	versions := repository.NewMemoryPricingVersionRepository()
+	_ = versions.Save(context.Background(), repository.PricingVersion{Version: "2024.01", EffectiveFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Config: service.NewShippingService().Pricing()})
+
+	tests := []struct {
+		name       string
+		asOf       string
+		tenantID   string
+		versions   repository.PricingVersionRepository
+		wantStatus int
+	}{
+		{"invalid date", "05/01/2024", tenant.Default, versions, http.StatusBadRequest},
+		{"future date", time.Now().AddDate(1, 0, 0).Format(time.DateOnly), tenant.Default, versions, http.StatusBadRequest},
+		{"other tenant", "2024-05-01", "acme", versions, http.StatusBadRequest},
+		{"versions not recorded", "2024-05-01", tenant.Default, nil, http.StatusBadRequest},
+		{"before the first version", "2023-12-31T23:59:59Z", tenant.Default, versions, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockShippingService)
+			handler := NewShippingHandler(mockService, nil, repository.QuoteConfig{}, nil, nil, tt.versions, nil, zaptest.NewLogger(t))
+			req := addRequestID(httptest.NewRequest(http.MethodPost, "/calculate?as_of="+tt.asOf, bytes.NewBufferString(`{"origin_zipcode":"12345678"}`)))
+			req = req.WithContext(tenant.NewContext(req.Context(), tt.tenantID))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.CalculateShipping(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertNotCalled(t, "CalculateShipping", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// failingQuoteRepository is a QuoteRepository whose writes always fail
+type failingQuoteRepository struct{}
+
+func (failingQuoteRepository) Save(ctx context.Context, quote *repository.Quote) error {
+	return errors.New("storage unavailable")
+}
+
+func (failingQuoteRepository) Get(ctx context.Context, id string) (*repository.Quote, error) {
+	return nil, repository.ErrNotFound
+}
+
+func BenchmarkShippingHandler(b *testing.B) {
+	body, _ := json.Marshal(v1.CalculateShippingRequest{
+		OriginZipcode:      "01310-100",
+		DestinationZipcode: "20040-020",
+		Weight:             2.5,
+		Dimensions:         v1.PackageDimensions{Length: 30, Width: 20, Height: 15},
+	})
+	handler := NewShippingHandler(service.NewShippingService(), repository.NewMemoryQuoteRepository(), repository.QuoteConfig{TTL: 30 * time.Minute}, nil, nil, nil, nil, zap.NewNop())
+	benchmarks := []struct {
+		name    string
+		path    string
+		handler http.HandlerFunc
+	}{
+		{"calculate", "/calculate", handler.CalculateShipping},
+		{"preview", "/calculate/preview", handler.PreviewShipping},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				w := httptest.NewRecorder()
+				bm.handler(w, httptest.NewRequest(http.MethodPost, bm.path, bytes.NewReader(body)))
+				if w.Code != http.StatusOK {
+					b.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+				}
+			}
+		})
+		// Under concurrent load, where the pooled request bodies and encoders are shared between
+		// goroutines
+		b.Run(bm.name+" parallel", func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					w := httptest.NewRecorder()
+					bm.handler(w, httptest.NewRequest(http.MethodPost, bm.path, bytes.NewReader(body)))
+					if w.Code != http.StatusOK {
+						b.Errorf("unexpected status %d: %s", w.Code, w.Body.String())
+						return
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"go.uber.org/zap"
+)
+
+// maxSLADays is the longest period of an SLA report
+const maxSLADays = 366
+
+// SLAReporter reports the SLA attainment of the delivered shipments by carrier and route
+type SLAReporter interface {
+	Report(ctx context.Context, req service.SLAReportRequest) (*model.SLAReport, error)
+}
+
+// SLAHandler handles HTTP requests for the SLA attainment of the carriers
+type SLAHandler struct {
+	sla    SLAReporter
+	logger *zap.Logger
+}
+
+// NewSLAHandler creates a new SLA handler instance
+func NewSLAHandler(sla SLAReporter, logger *zap.Logger) *SLAHandler {
+	return &SLAHandler{
+		sla:    sla,
+		logger: logger,
+	}
+}
+
+// GetReport handles GET /admin/sla requests. The optional "from" and "to" query parameters
+// (YYYY-MM-DD, inclusive) select the delivery days, by default from the start of the current
+// month to today, and "carrier" and "tenant" filter the shipments
+func (h *SLAHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	today := time.Now().UTC()
+	from, err := parseDay(query.Get("from"), time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": "invalid from: must be a YYYY-MM-DD date"})
+		return
+	}
+	to, err := parseDay(query.Get("to"), today)
+	if err != nil {
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": "invalid to: must be a YYYY-MM-DD date"})
+		return
+	}
+	if to.Before(from) || to.Sub(from) >= maxSLADays*24*time.Hour {
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("to must not be before from, and the period at most %d days", maxSLADays)})
+		return
+	}
+
+	report, err := h.sla.Report(ctx, service.SLAReportRequest{From: from, To: to, Carrier: query.Get("carrier"), Tenant: query.Get("tenant")})
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao gerar o relatório de SLA", err)
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to build SLA report"})
+		return
+	}
+	writeJSON(ctx, h.logger, w, http.StatusOK, report)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// stubSLA returns report or err, recording the request
+type stubSLA struct {
+	report *model.SLAReport
+	err    error
+	req    service.SLAReportRequest
+}
+
+func (s *stubSLA) Report(ctx context.Context, req service.SLAReportRequest) (*model.SLAReport, error) {
+	s.req = req
+	return s.report, s.err
+}
+
+func TestGetSLAReport(t *testing.T) {
+	// Arrange
+	report := &model.SLAReport{
+		From:    "2025-03-01",
+		To:      "2025-03-31",
+		Overall: model.SLAStats{Delivered: 4, OnTime: 3, Late: 1, Attainment: 0.75, AverageDelayDays: 2},
+		Routes: []model.SLARoute{
+			{Carrier: "correios", Origin: "SP", Destination: "RJ", SLAStats: model.SLAStats{Delivered: 4, OnTime: 3, Late: 1, Attainment: 0.75, AverageDelayDays: 2}},
+		},
+	}
+	reporter := &stubSLA{report: report}
+	handler := NewSLAHandler(reporter, zaptest.NewLogger(t))
+	req := httptest.NewRequest(http.MethodGet, "/admin/sla?from=2025-03-01&to=2025-03-31&carrier=correios&tenant=acme", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetReport(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response model.SLAReport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, *report, response)
+	assert.Equal(t, service.SLAReportRequest{
+		From:    time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		Carrier: "correios",
+		Tenant:  "acme",
+	}, reporter.req)
+}
+
+func TestGetSLAReport_DefaultPeriod(t *testing.T) {
+	// Arrange
+	reporter := &stubSLA{report: &model.SLAReport{}}
+	handler := NewSLAHandler(reporter, zaptest.NewLogger(t))
+	w := httptest.NewRecorder()
+	today := time.Now().UTC()
+
+	// Act
+	handler.GetReport(w, httptest.NewRequest(http.MethodGet, "/admin/sla", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC), reporter.req.From)
+	assert.Equal(t, today.Format(model.DayLayout), reporter.req.To.Format(model.DayLayout))
+}
+
+func TestGetSLAReport_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{"invalid from", "from=03/01/2025", nil, http.StatusBadRequest, "invalid from: must be a YYYY-MM-DD date"},
+		{"invalid to", "from=2025-03-01&to=tomorrow", nil, http.StatusBadRequest, "invalid to: must be a YYYY-MM-DD date"},
+		{"to before from", "from=2025-03-10&to=2025-03-01", nil, http.StatusBadRequest, "to must not be before from, and the period at most 366 days"},
+		{"period too long", "from=2023-01-01&to=2025-03-01", nil, http.StatusBadRequest, "to must not be before from, and the period at most 366 days"},
+		{"store unavailable", "from=2025-03-01&to=2025-03-31", errors.New("storage unavailable"), http.StatusInternalServerError, "failed to build SLA report"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewSLAHandler(&stubSLA{err: tt.err}, zaptest.NewLogger(t))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.GetReport(w, httptest.NewRequest(http.MethodGet, "/admin/sla?"+tt.query, nil))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var body map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantError, body["error"])
+		})
+	}
+}
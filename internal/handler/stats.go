@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rbonfanti/shipping-calculator/internal/quotestats"
+	"go.uber.org/zap"
+)
+
+// QuoteStats aggregates the recent calculations of the quote routes, e.g. a *quotestats.Window
+type QuoteStats interface {
+	Snapshot() quotestats.Stats
+}
+
+// StatsHandler handles HTTP requests for the rolling quote stats of the instance
+type StatsHandler struct {
+	stats  QuoteStats
+	logger *zap.Logger
+}
+
+// NewStatsHandler creates a new stats handler instance
+func NewStatsHandler(stats QuoteStats, logger *zap.Logger) *StatsHandler {
+	return &StatsHandler{
+		stats:  stats,
+		logger: logger,
+	}
+}
+
+// GetStats handles GET /admin/stats requests: the quotes per minute, average cost, error rate and
+// top destination regions of the quotes calculated by the instance in the stats window
+func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(r.Context(), h.logger, w, http.StatusOK, h.stats.Snapshot())
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/quotestats"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// stubStats returns stats
+type stubStats struct {
+	stats quotestats.Stats
+}
+
+func (s stubStats) Snapshot() quotestats.Stats {
+	return s.stats
+}
+
+func TestGetStats(t *testing.T) {
+	// Arrange
+	from := time.Date(2025, 3, 10, 14, 55, 0, 0, time.UTC)
+	stats := quotestats.Stats{
+		From:            from,
+		To:              from.Add(5 * time.Minute),
+		Quotes:          9,
+		Errors:          1,
+		QuotesPerMinute: 1.8,
+		ErrorRate:       0.1,
+		AverageCost:     []quotestats.CurrencyCost{{Currency: "BRL", Quotes: 9, Average: money.FromMinor(2350)}},
+		TopRegions:      []quotestats.RegionCount{{Region: "SP", Quotes: 6}, {Region: "RJ", Quotes: 3}},
+	}
+	handler := NewStatsHandler(stubStats{stats: stats}, zaptest.NewLogger(t))
+	req := httptest.NewRequest(http.MethodGet, "/admin/stats", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetStats(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response quotestats.Stats
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, stats, response)
+}
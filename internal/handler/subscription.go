@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/mapper"
+	"github.com/rbonfanti/shipping-calculator/internal/subscription"
+	"go.uber.org/zap"
+)
+
+// pollDeadlineMargin is the time left before the request deadline to answer a poll without a change
+const pollDeadlineMargin = time.Second
+
+// SubscriptionHandler serves the price subscriptions of dashboards following live freight prices
+type SubscriptionHandler struct {
+	hub    *subscription.Hub
+	logger *zap.Logger
+}
+
+// NewSubscriptionHandler creates a new price subscription handler instance
+func NewSubscriptionHandler(hub *subscription.Hub, logger *zap.Logger) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		hub:    hub,
+		logger: logger,
+	}
+}
+
+// Subscribe handles POST /price-subscriptions requests: the body is a quote request, whose route
+// and package are priced and followed for price changes
+func (h *SubscriptionHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body := getRequest()
+	defer putRequest(body)
+	if err := decodeJSON(r, body); err != nil {
+		logger.LogError(h.logger, ctx, "Erro na assinatura de preço: falha ao decodificar requisição", err)
+		writeJSON(ctx, h.logger, w, invalidBodyStatus(err), invalidBody(err))
+		return
+	}
+	req := mapper.RequestFromV1(body)
+
+	created, err := h.hub.Subscribe(ctx, req)
+	if errors.Is(err, subscription.ErrLimitReached) {
+		writeJSON(ctx, h.logger, w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro na assinatura de preço", err)
+		writeJSON(ctx, h.logger, w, calculationStatus(err), calculationError(err))
+		return
+	}
+
+	logger.LogRequest(h.logger, ctx, "Assinatura de preço criada",
+		zap.String("subscription_id", created.ID),
+		zap.String("origem", req.OriginZipcode),
+		zap.String("destino", req.DestinationZipcode),
+	)
+	writeJSON(ctx, h.logger, w, http.StatusCreated, mapper.PriceSubscriptionToV1(&created))
+}
+
+// PollSubscription handles GET /price-subscriptions/{id} requests by long polling: the
+// subscription is returned as soon as its version is above the "version" query parameter
+// (default 0, returning at once), or 204 No Content after "wait" (a duration, default and
+// maximum PRICE_SUBSCRIPTION_MAX_WAIT) without a price change
+func (h *SubscriptionHandler) PollSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	query := r.URL.Query()
+
+	since := 0
+	if value := query.Get("version"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": "version must be a non-negative integer"})
+			return
+		}
+		since = parsed
+	}
+	wait := h.hub.MaxWait()
+	if value := query.Get("wait"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": "wait must be a non-negative duration, e.g. 30s"})
+			return
+		}
+		wait = parsed
+	}
+	// Answer before the request deadline rather than time out
+	if deadline, ok := ctx.Deadline(); ok {
+		wait = min(wait, time.Until(deadline)-pollDeadlineMargin)
+	}
+
+	current, err := h.hub.Poll(ctx, id, since, wait)
+	if errors.Is(err, subscription.ErrNotFound) {
+		writeJSON(ctx, h.logger, w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao consultar assinatura de preço", err, zap.String("subscription_id", id))
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to poll subscription"})
+		return
+	}
+	if current.Version <= since {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(ctx, h.logger, w, http.StatusOK, mapper.PriceSubscriptionToV1(&current))
+}
+
+// Unsubscribe handles DELETE /price-subscriptions/{id} requests
+func (h *SubscriptionHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	err := h.hub.Unsubscribe(ctx, id)
+	if errors.Is(err, subscription.ErrNotFound) {
+		writeJSON(ctx, h.logger, w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.LogError(h.logger, ctx, "Erro ao cancelar assinatura de preço", err, zap.String("subscription_id", id))
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to unsubscribe"})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/rbonfanti/shipping-calculator/internal/subscription"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+const subscriptionBody = `{"origin_zipcode":"01310-100","destination_zipcode":"01311-000","weight":2.5,"dimensions":{"length":30,"width":20,"height":15}}`
+
+func subscriptionRouter(h *SubscriptionHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/price-subscriptions", h.Subscribe)
+	r.Get("/price-subscriptions/{id}", h.PollSubscription)
+	r.Delete("/price-subscriptions/{id}", h.Unsubscribe)
+	return r
+}
+
+func subscribe(t *testing.T, router http.Handler) v1.PriceSubscription {
+	t.Helper()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/price-subscriptions", strings.NewReader(subscriptionBody)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created v1.PriceSubscription
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	return created
+}
+
+func TestSubscribe(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"valid request", subscriptionBody, http.StatusCreated},
+		{"invalid body", `{"weight":`, http.StatusBadRequest},
+		{"invalid weight", `{"origin_zipcode":"01310100","destination_zipcode":"20040020","weight":0,"dimensions":{"length":1,"width":1,"height":1}}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			hub := subscription.New(subscription.Config{MaxSubscriptions: 10, TTL: time.Minute, MaxWait: time.Second}, service.NewShippingService(), store.NewMemoryStore(), zaptest.NewLogger(t))
+			router := subscriptionRouter(NewSubscriptionHandler(hub, zaptest.NewLogger(t)))
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/price-subscriptions", strings.NewReader(tt.body)))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+			var created v1.PriceSubscription
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+			assert.NotEmpty(t, created.SubscriptionID)
+			assert.Equal(t, 1, created.Version)
+			assert.Positive(t, created.Quote.ShippingCost)
+			assert.Empty(t, created.Quote.QuoteID, "subscriptions do not persist quotes")
+		})
+	}
+}
+
+func TestSubscribe_LimitReached(t *testing.T) {
+	// Arrange
+	hub := subscription.New(subscription.Config{MaxSubscriptions: 1, TTL: time.Minute, MaxWait: time.Second}, service.NewShippingService(), store.NewMemoryStore(), zaptest.NewLogger(t))
+	router := subscriptionRouter(NewSubscriptionHandler(hub, zaptest.NewLogger(t)))
+	subscribe(t, router)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/price-subscriptions", strings.NewReader(subscriptionBody)))
+
+	// Assert
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"error":"subscription limit reached"}`, w.Body.String())
+}
+
+func TestPollSubscription(t *testing.T) {
+	// Arrange
+	shippingService := service.NewShippingService()
+	hub := subscription.New(subscription.Config{MaxSubscriptions: 10, TTL: time.Minute, MaxWait: 5 * time.Second}, shippingService, store.NewMemoryStore(), zaptest.NewLogger(t))
+	router := subscriptionRouter(NewSubscriptionHandler(hub, zaptest.NewLogger(t)))
+	created := subscribe(t, router)
+	polled := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/price-subscriptions/"+created.SubscriptionID+"?version=1", nil))
+		polled <- w
+	}()
+
+	// Act
+	cfg := shippingService.Pricing()
+	cfg.FuelSurcharge = &pricing.FuelSurcharge{Rate: 0.1, EffectiveDate: "2026-01-01"}
+	assert.NoError(t, shippingService.SetPricing(cfg))
+	hub.Refresh(context.Background())
+
+	// Assert
+	select {
+	case w := <-polled:
+		assert.Equal(t, http.StatusOK, w.Code)
+		var updated v1.PriceSubscription
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&updated))
+		assert.Equal(t, 2, updated.Version)
+		assert.Greater(t, updated.Quote.ShippingCost, created.Quote.ShippingCost)
+	case <-time.After(5 * time.Second):
+		t.Fatal("poll was not answered after the price change")
+	}
+}
+
+func TestPollSubscription_Responses(t *testing.T) {
+	hub := subscription.New(subscription.Config{MaxSubscriptions: 10, TTL: time.Minute, MaxWait: time.Second}, service.NewShippingService(), store.NewMemoryStore(), zaptest.NewLogger(t))
+	router := subscriptionRouter(NewSubscriptionHandler(hub, zaptest.NewLogger(t)))
+	created := subscribe(t, router)
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+	}{
+		{"current version", "/price-subscriptions/" + created.SubscriptionID, http.StatusOK},
+		{"no change before wait", "/price-subscriptions/" + created.SubscriptionID + "?version=1&wait=10ms", http.StatusNoContent},
+		{"unknown subscription", "/price-subscriptions/unknown", http.StatusNotFound},
+		{"invalid version", "/price-subscriptions/" + created.SubscriptionID + "?version=-1", http.StatusBadRequest},
+		{"invalid wait", "/price-subscriptions/" + created.SubscriptionID + "?wait=soon", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	// Arrange
+	hub := subscription.New(subscription.Config{MaxSubscriptions: 10, TTL: time.Minute, MaxWait: time.Second}, service.NewShippingService(), store.NewMemoryStore(), zaptest.NewLogger(t))
+	router := subscriptionRouter(NewSubscriptionHandler(hub, zaptest.NewLogger(t)))
+	created := subscribe(t, router)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/price-subscriptions/"+created.SubscriptionID, nil))
+	again := httptest.NewRecorder()
+	router.ServeHTTP(again, httptest.NewRequest(http.MethodDelete, "/price-subscriptions/"+created.SubscriptionID, nil))
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, http.StatusNotFound, again.Code)
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/tracking"
+	"go.uber.org/zap"
+)
+
+// WebhookSecretHeader carries the shared secret of carriers pushing tracking events
+const WebhookSecretHeader = "X-Webhook-Secret"
+
+// ShipmentTracker reads and records the tracking of shipments
+type ShipmentTracker interface {
+	Get(ctx context.Context, shipmentID string) (*model.ShipmentTracking, error)
+	Record(ctx context.Context, shipmentID string, events []model.TrackingEvent) (*model.ShipmentTracking, error)
+}
+
+// TrackingEventsRequest is the body of a tracking webhook
+type TrackingEventsRequest struct {
+	Events []model.TrackingEvent `json:"events"`
+}
+
+// TrackingHandler serves the tracking of shipments and receives carrier tracking webhooks
+type TrackingHandler struct {
+	tracker       ShipmentTracker
+	webhookSecret string
+	logger        *zap.Logger
+}
+
+// NewTrackingHandler creates a new tracking handler instance. Webhook calls are authenticated
+// with trackingConfig.WebhookSecret and all rejected when it is empty
+func NewTrackingHandler(tracker ShipmentTracker, trackingConfig tracking.Config, logger *zap.Logger) *TrackingHandler {
+	return &TrackingHandler{
+		tracker:       tracker,
+		webhookSecret: trackingConfig.WebhookSecret,
+		logger:        logger,
+	}
+}
+
+// GetTracking handles GET /shipments/{id}/tracking requests
+func (h *TrackingHandler) GetTracking(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	result, err := h.tracker.Get(ctx, id)
+	if err != nil {
+		writeTrackingError(ctx, h.logger, w, id, err)
+		return
+	}
+	writeJSON(ctx, h.logger, w, http.StatusOK, result)
+}
+
+// RecordTrackingEvents handles POST /shipments/{id}/tracking/events requests, the webhook
+// carriers call to push status events; bodies over maxWebhookBodyBytes are rejected with 413
+func (h *TrackingHandler) RecordTrackingEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	secret := r.Header.Get(WebhookSecretHeader)
+	if h.webhookSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(h.webhookSecret)) != 1 {
+		writeJSON(ctx, h.logger, w, http.StatusUnauthorized, map[string]string{"error": "invalid webhook secret"})
+		return
+	}
+
+	var body TrackingEventsRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes)).Decode(&body); err != nil {
+		writeJSON(ctx, h.logger, w, invalidBodyStatus(err), invalidBody(err))
+		return
+	}
+
+	result, err := h.tracker.Record(ctx, id, body.Events)
+	if err != nil {
+		writeTrackingError(ctx, h.logger, w, id, err)
+		return
+	}
+	logger.LogRequest(h.logger, ctx, "Eventos de rastreamento recebidos",
+		zap.String("shipment_id", id),
+		zap.Int("eventos", len(body.Events)),
+		zap.String("status", result.Status),
+	)
+	writeJSON(ctx, h.logger, w, http.StatusOK, result)
+}
+
+// writeTrackingError maps tracking errors to HTTP responses
+func writeTrackingError(ctx context.Context, l *zap.Logger, w http.ResponseWriter, shipmentID string, err error) {
+	switch {
+	case errors.Is(err, service.ErrShipmentNotFound):
+		writeJSON(ctx, l, w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidTrackingEvent):
+		writeJSON(ctx, l, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		logger.LogError(l, ctx, "Erro no rastreamento do envio", err, zap.String("shipment_id", shipmentID))
+		writeJSON(ctx, l, w, http.StatusInternalServerError, map[string]string{"error": "failed to load tracking"})
+	}
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/tracking"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// stubTracker returns the tracking of the shipment with the received events, or fails with err
+type stubTracker struct {
+	err      error
+	recorded []model.TrackingEvent
+}
+
+func (s *stubTracker) Get(ctx context.Context, shipmentID string) (*model.ShipmentTracking, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.ShipmentTracking{ShipmentID: shipmentID, Status: model.ShipmentStatusBooked, Events: []model.TrackingEvent{}}, nil
+}
+
+func (s *stubTracker) Record(ctx context.Context, shipmentID string, events []model.TrackingEvent) (*model.ShipmentTracking, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.recorded = events
+	return &model.ShipmentTracking{ShipmentID: shipmentID, Status: events[len(events)-1].Status, Events: events}, nil
+}
+
+func serveTracking(t *testing.T, h *TrackingHandler, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	r := chi.NewRouter()
+	r.Get("/shipments/{id}/tracking", h.GetTracking)
+	r.Post("/shipments/{id}/tracking/events", h.RecordTrackingEvents)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetTracking(t *testing.T) {
+	// Arrange
+	handler := NewTrackingHandler(&stubTracker{}, tracking.Config{}, zaptest.NewLogger(t))
+
+	// Act
+	w := serveTracking(t, handler, httptest.NewRequest(http.MethodGet, "/shipments/s1/tracking", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"shipment_id":"s1","status":"booked","events":[]}`, w.Body.String())
+}
+
+func TestGetTracking_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{"unknown shipment", service.ErrShipmentNotFound, http.StatusNotFound, "shipment not found"},
+		{"storage failure", errors.New("storage unavailable"), http.StatusInternalServerError, "failed to load tracking"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewTrackingHandler(&stubTracker{err: tt.err}, tracking.Config{}, zaptest.NewLogger(t))
+
+			// Act
+			w := serveTracking(t, handler, httptest.NewRequest(http.MethodGet, "/shipments/s1/tracking", nil))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var body map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantError, body["error"])
+		})
+	}
+}
+
+func TestRecordTrackingEvents(t *testing.T) {
+	// Arrange
+	tracker := &stubTracker{}
+	handler := NewTrackingHandler(tracker, tracking.Config{WebhookSecret: "s3cret"}, zaptest.NewLogger(t))
+	req := httptest.NewRequest(http.MethodPost, "/shipments/s1/tracking/events",
+		strings.NewReader(`{"events":[{"status":"in_transit","location":"Rio de Janeiro/RJ","occurred_at":"2025-03-11T08:00:00Z"}]}`))
+	req.Header.Set(WebhookSecretHeader, "s3cret")
+
+	// Act
+	w := serveTracking(t, handler, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []model.TrackingEvent{{
+		Status:     model.TrackingStatusInTransit,
+		Location:   "Rio de Janeiro/RJ",
+		OccurredAt: time.Date(2025, 3, 11, 8, 0, 0, 0, time.UTC),
+	}}, tracker.recorded)
+
+	var result model.ShipmentTracking
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, model.TrackingStatusInTransit, result.Status)
+}
+
+func TestRecordTrackingEvents_Errors(t *testing.T) {
+	validBody := `{"events":[{"status":"posted","occurred_at":"2025-03-10T18:00:00Z"}]}`
+	tests := []struct {
+		name          string
+		webhookSecret string
+		secret        string
+		body          string
+		err           error
+		wantStatus    int
+		wantError     string
+	}{
+		{"webhook disabled", "", "", validBody, nil, http.StatusUnauthorized, "invalid webhook secret"},
+		{"missing secret", "s3cret", "", validBody, nil, http.StatusUnauthorized, "invalid webhook secret"},
+		{"wrong secret", "s3cret", "guess", validBody, nil, http.StatusUnauthorized, "invalid webhook secret"},
+		{"invalid body", "s3cret", "s3cret", `{"events":`, nil, http.StatusBadRequest, "invalid request body"},
+		{"body too large", "s3cret", "s3cret", `{"events":[` + strings.Repeat(`{"status":"posted"},`, maxWebhookBodyBytes/10) + `{}]}`, nil,
+			http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", maxWebhookBodyBytes)},
+		{"invalid event", "s3cret", "s3cret", validBody, fmt.Errorf("%w: events[0].occurred_at is required", service.ErrInvalidTrackingEvent),
+			http.StatusBadRequest, "invalid tracking event: events[0].occurred_at is required"},
+		{"unknown shipment", "s3cret", "s3cret", validBody, service.ErrShipmentNotFound, http.StatusNotFound, "shipment not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewTrackingHandler(&stubTracker{err: tt.err}, tracking.Config{WebhookSecret: tt.webhookSecret}, zaptest.NewLogger(t))
+			req := httptest.NewRequest(http.MethodPost, "/shipments/s1/tracking/events", strings.NewReader(tt.body))
+			req.Header.Set(WebhookSecretHeader, tt.secret)
+
+			// Act
+			w := serveTracking(t, handler, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var body map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantError, body["error"])
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/mapper"
+	"github.com/rbonfanti/shipping-calculator/internal/middleware"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+)
+
+// CalculateV1 serves the v1 contract of POST /calculate and POST /v1/calculate: CalculateShipping
+// behind the request and response encodings v1 clients depend on (JSON, form, XML and protobuf)
+// and the validation of the request fields. The server and the v1 contract tests serve this same
+// handler, so that the golden responses cover everything clients see of the route besides the
+// deadlines, quotas and compression applied to every route
+func (h *ShippingHandler) CalculateV1() http.Handler {
+	return chi.Chain(
+		middleware.NegotiateProtobuf(mapper.ResponseToProto),
+		middleware.NegotiateXML[v1.CalculateShippingRequest, v1.CalculateShippingResponse]("shipping_quote"),
+		middleware.RequireContentType(middleware.ContentTypeJSON, middleware.ContentTypeForm, middleware.ContentTypeXML, middleware.ContentTypeTextXML),
+		middleware.FormJSON[v1.CalculateShippingRequest](),
+		middleware.ValidateBody(h.RecordRejected),
+	).HandlerFunc(h.CalculateShipping)
+}
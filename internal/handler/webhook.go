@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/tracking"
+	"go.uber.org/zap"
+)
+
+// maxWebhookBodyBytes caps the size of carrier webhook payloads
+const maxWebhookBodyBytes = 1 << 20
+
+// WebhookVerifier authenticates carrier webhooks
+type WebhookVerifier interface {
+	Verify(ctx context.Context, carrier, timestamp, signature string, body []byte) error
+}
+
+// CarrierWebhookHandler receives the tracking webhooks of carriers in their own payload formats
+type CarrierWebhookHandler struct {
+	tracker  ShipmentTracker
+	verifier WebhookVerifier
+	logger   *zap.Logger
+}
+
+// NewCarrierWebhookHandler creates a new carrier webhook handler instance
+func NewCarrierWebhookHandler(tracker ShipmentTracker, verifier WebhookVerifier, logger *zap.Logger) *CarrierWebhookHandler {
+	return &CarrierWebhookHandler{
+		tracker:  tracker,
+		verifier: verifier,
+		logger:   logger,
+	}
+}
+
+// ReceiveWebhook handles POST /webhooks/carriers/{carrier} requests: the signature is verified,
+// the payload normalized into tracking events and the events recorded for the shipment
+func (h *CarrierWebhookHandler) ReceiveWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	carrier := strings.ToLower(chi.URLParam(r, "carrier"))
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		writeJSON(ctx, h.logger, w, http.StatusRequestEntityTooLarge, map[string]string{"error": "request body too large"})
+		return
+	}
+
+	err = h.verifier.Verify(ctx, carrier, r.Header.Get(tracking.TimestampHeader), r.Header.Get(tracking.SignatureHeader), body)
+	switch {
+	case errors.Is(err, tracking.ErrUnknownCarrier):
+		writeJSON(ctx, h.logger, w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, tracking.ErrReplayedWebhook):
+		logger.LogWarning(h.logger, ctx, "Webhook de transportadora repetido", zap.String("transportadora", carrier))
+		writeJSON(ctx, h.logger, w, http.StatusConflict, map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, tracking.ErrInvalidSignature):
+		logger.LogWarning(h.logger, ctx, "Webhook de transportadora com assinatura inválida", zap.String("transportadora", carrier), zap.Error(err))
+		writeJSON(ctx, h.logger, w, http.StatusUnauthorized, map[string]string{"error": tracking.ErrInvalidSignature.Error()})
+		return
+	case err != nil:
+		logger.LogError(h.logger, ctx, "Erro ao verificar webhook de transportadora", err, zap.String("transportadora", carrier))
+		writeJSON(ctx, h.logger, w, http.StatusInternalServerError, map[string]string{"error": "failed to verify webhook"})
+		return
+	}
+
+	update, err := tracking.Normalize(carrier, body)
+	if errors.Is(err, tracking.ErrUnknownCarrier) {
+		writeJSON(ctx, h.logger, w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(ctx, h.logger, w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	logger.LogRequest(h.logger, ctx, "Webhook de transportadora recebido",
+		zap.String("transportadora", carrier),
+		zap.String("shipment_id", update.ShipmentID),
+		zap.Int("eventos", len(update.Events)),
+	)
+	// Carriers also notify events we do not track; acknowledge them so they are not retried
+	if len(update.Events) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	result, err := h.tracker.Record(ctx, update.ShipmentID, update.Events)
+	if err != nil {
+		writeTrackingError(ctx, h.logger, w, update.ShipmentID, err)
+		return
+	}
+	writeJSON(ctx, h.logger, w, http.StatusOK, result)
+}
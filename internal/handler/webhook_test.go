@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/rbonfanti/shipping-calculator/internal/tracking"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+const jadlogWebhook = `{"shipmentId":"s1","status":"ENTREGUE","timestamp":1741629600,"local":"Rio de Janeiro - RJ"}`
+
+func newWebhookHandler(t *testing.T, tracker ShipmentTracker) *CarrierWebhookHandler {
+	t.Helper()
+	verifier := tracking.NewWebhookVerifier(tracking.Config{
+		CarrierSecrets:   map[string]string{"jadlog": "s3cret", "fedex": "s3cret"},
+		WebhookTolerance: 5 * time.Minute,
+	}, store.NewMemoryStore())
+	return NewCarrierWebhookHandler(tracker, verifier, zaptest.NewLogger(t))
+}
+
+func signedWebhook(carrier, secret, body string) *http.Request {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/carriers/"+carrier, strings.NewReader(body))
+	req.Header.Set(tracking.TimestampHeader, timestamp)
+	req.Header.Set(tracking.SignatureHeader, "sha256="+hex.EncodeToString(tracking.Sign(secret, timestamp, []byte(body))))
+	return req
+}
+
+func serveWebhook(t *testing.T, h *CarrierWebhookHandler, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	r := chi.NewRouter()
+	r.Post("/webhooks/carriers/{carrier}", h.ReceiveWebhook)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestReceiveWebhook(t *testing.T) {
+	// Arrange
+	tracker := &stubTracker{}
+	handler := newWebhookHandler(t, tracker)
+
+	// Act
+	w := serveWebhook(t, handler, signedWebhook("Jadlog", "s3cret", jadlogWebhook))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []model.TrackingEvent{{
+		Status:     model.TrackingStatusDelivered,
+		Location:   "Rio de Janeiro - RJ",
+		OccurredAt: time.Unix(1741629600, 0).UTC(),
+	}}, tracker.recorded)
+
+	var result model.ShipmentTracking
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, "s1", result.ShipmentID)
+	assert.Equal(t, model.TrackingStatusDelivered, result.Status)
+}
+
+func TestReceiveWebhook_UntrackedEvent(t *testing.T) {
+	// Arrange
+	tracker := &stubTracker{}
+	handler := newWebhookHandler(t, tracker)
+	body := `{"shipmentId":"s1","status":"AGUARDANDO COLETA","timestamp":1741629600}`
+
+	// Act
+	w := serveWebhook(t, handler, signedWebhook("jadlog", "s3cret", body))
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Nil(t, tracker.recorded)
+}
+
+// failingVerifier fails every verification with err
+type failingVerifier struct{ err error }
+
+func (v failingVerifier) Verify(ctx context.Context, carrier, timestamp, signature string, body []byte) error {
+	return v.err
+}
+
+func TestReceiveWebhook_VerifierFailure(t *testing.T) {
+	// Arrange
+	tracker := &stubTracker{}
+	handler := NewCarrierWebhookHandler(tracker, failingVerifier{err: errors.New("failed to record webhook signature: connection refused")}, zaptest.NewLogger(t))
+
+	// Act
+	w := serveWebhook(t, handler, signedWebhook("jadlog", "s3cret", jadlogWebhook))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Code, "a store outage is not reported as an invalid signature")
+	assert.JSONEq(t, `{"error":"failed to verify webhook"}`, w.Body.String())
+	assert.Nil(t, tracker.recorded)
+}
+
+func TestReceiveWebhook_Replay(t *testing.T) {
+	// Arrange
+	handler := newWebhookHandler(t, &stubTracker{})
+	req := signedWebhook("jadlog", "s3cret", jadlogWebhook)
+	replay := httptest.NewRequest(http.MethodPost, "/webhooks/carriers/jadlog", strings.NewReader(jadlogWebhook))
+	replay.Header = req.Header.Clone()
+
+	// Act
+	first := serveWebhook(t, handler, req)
+	second := serveWebhook(t, handler, replay)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusConflict, second.Code)
+}
+
+func TestReceiveWebhook_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *http.Request
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{"carrier without secret", signedWebhook("correios", "s3cret", `{}`), nil, http.StatusNotFound, "unknown carrier"},
+		{"carrier without normalizer", signedWebhook("fedex", "s3cret", `{}`), nil, http.StatusNotFound, "unknown carrier"},
+		{"invalid signature", signedWebhook("jadlog", "guess", jadlogWebhook), nil, http.StatusUnauthorized, "invalid webhook signature"},
+		{"unsigned", httptest.NewRequest(http.MethodPost, "/webhooks/carriers/jadlog", strings.NewReader(jadlogWebhook)), nil,
+			http.StatusUnauthorized, "invalid webhook signature"},
+		{"invalid payload", signedWebhook("jadlog", "s3cret", `{"shipmentId":`), nil, http.StatusBadRequest, "invalid jadlog payload"},
+		{"unknown shipment", signedWebhook("jadlog", "s3cret", jadlogWebhook), service.ErrShipmentNotFound, http.StatusNotFound, "shipment not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := newWebhookHandler(t, &stubTracker{err: tt.err})
+
+			// Act
+			w := serveWebhook(t, handler, tt.req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var body map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Contains(t, body["error"], tt.wantError)
+		})
+	}
+}
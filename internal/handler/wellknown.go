@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"net/http"
+
+	"github.com/rbonfanti/shipping-calculator/internal/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"go.uber.org/zap"
+)
+
+// WellKnownPath is the discovery document path for client SDKs
+const WellKnownPath = "/.well-known/shipping-calculator"
+
+// CapabilitiesProvider describes the service limits and features
+type CapabilitiesProvider interface {
+	Capabilities(ctx context.Context) model.ServiceCapabilities
+}
+
+// WellKnownHandler serves the service discovery document
+type WellKnownHandler struct {
+	provider CapabilitiesProvider
+	logger   *zap.Logger
+	overload *middleware.OverloadConfig
+	tenants  tenant.Config
+}
+
+// NewWellKnownHandler creates a new well-known handler instance
+func NewWellKnownHandler(provider CapabilitiesProvider, logger *zap.Logger) *WellKnownHandler {
+	return &WellKnownHandler{
+		provider: provider,
+		logger:   logger,
+	}
+}
+
+// WithRateLimits reports the request limits of the quote routes in the document: the overload
+// protection shared by every client and the monthly quota of the tenant of the request
+func (h *WellKnownHandler) WithRateLimits(overload middleware.OverloadConfig, tenants tenant.Config) *WellKnownHandler {
+	h.overload = &overload
+	h.tenants = tenants
+	return h
+}
+
+// GetCapabilities handles GET /.well-known/shipping-calculator requests. The document depends on
+// the tenant, so shared caches keep one copy per tenant header and API key
+func (h *WellKnownHandler) GetCapabilities(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Header().Add("Vary", middleware.TenantHeader+", "+middleware.APIKeyHeader)
+	ctx := r.Context()
+	capabilities := h.provider.Capabilities(ctx)
+	if h.overload != nil {
+		capabilities.RateLimit = &model.RateLimitInfo{
+			MonthlyQuota:      h.tenants.MonthlyQuota(tenant.FromContext(ctx)),
+			MaxInFlight:       h.overload.MaxInFlight,
+			RetryAfterSeconds: int(math.Ceil(h.overload.RetryAfter.Seconds())),
+		}
+	}
+	writeJSON(ctx, h.logger, w, http.StatusOK, capabilities)
+}
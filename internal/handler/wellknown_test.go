@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestNewWellKnownHandler(t *testing.T) {
+	// Act
+	handler := NewWellKnownHandler(service.NewShippingService(), zaptest.NewLogger(t))
+
+	// Assert
+	assert.NotNil(t, handler)
+	assert.NotNil(t, handler.provider)
+	assert.NotNil(t, handler.logger)
+}
+
+func TestGetCapabilities(t *testing.T) {
+	// Arrange
+	handler := NewWellKnownHandler(service.NewShippingService(), zaptest.NewLogger(t))
+	req := httptest.NewRequest(http.MethodGet, WellKnownPath, nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetCapabilities(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Cache-Control"), "max-age")
+	assert.Equal(t, "X-Tenant-ID, X-API-Key", w.Header().Get("Vary"))
+
+	var capabilities model.ServiceCapabilities
+	err := json.Unmarshal(w.Body.Bytes(), &capabilities)
+	assert.NoError(t, err)
+	assert.Contains(t, capabilities.SupportedCountries, "BR")
+	assert.Contains(t, capabilities.SupportedCurrencies, "BRL")
+	assert.Equal(t, "BRL", capabilities.Units.Currency)
+	assert.Equal(t, "kg", capabilities.Units.Weight)
+	assert.Equal(t, "cm", capabilities.Units.Dimensions)
+	assert.Equal(t, 15000.0, capabilities.Limits.MaxVolumeCm3)
+	assert.Equal(t, []string{"standard", "express"}, capabilities.AvailableServices)
+	assert.Equal(t, []string{"v1", "v2"}, capabilities.APIVersions)
+	assert.Nil(t, capabilities.RateLimit, "no rate limits are reported unless configured")
+}
+
+func TestGetCapabilities_RateLimits(t *testing.T) {
+	// Arrange
+	overload := middleware.OverloadConfig{MaxInFlight: 200, RetryAfter: 1500 * time.Millisecond}
+	tenants := tenant.Config{Tenants: map[string]tenant.Tenant{"acme": {MonthlyQuota: 10000}}}
+	handler := NewWellKnownHandler(service.NewShippingService(), zaptest.NewLogger(t)).WithRateLimits(overload, tenants)
+
+	tests := []struct {
+		name   string
+		tenant string
+		want   model.RateLimitInfo
+	}{
+		{"tenant with a quota", "acme", model.RateLimitInfo{MonthlyQuota: 10000, MaxInFlight: 200, RetryAfterSeconds: 2}},
+		{"default tenant", tenant.Default, model.RateLimitInfo{MaxInFlight: 200, RetryAfterSeconds: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, WellKnownPath, nil)
+			req = req.WithContext(tenant.NewContext(req.Context(), tt.tenant))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.GetCapabilities(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+			var capabilities model.ServiceCapabilities
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &capabilities))
+			assert.Equal(t, &tt.want, capabilities.RateLimit)
+		})
+	}
+}
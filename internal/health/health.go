@@ -0,0 +1,223 @@
+// Package health probes the dependencies of the service in the background, recording their
+// availability and latency, and reports whether the service is ready to take traffic.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+	"go.uber.org/zap"
+)
+
+// Overall statuses of a Report
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+)
+
+// Config sets how often and for how long dependencies are probed
+type Config struct {
+	Interval time.Duration
+	// Timeout bounds each probe; a dependency slower than that is down
+	Timeout time.Duration
+}
+
+// ConfigFromEnv reads HEALTH_PROBE_INTERVAL (default 15s) and HEALTH_PROBE_TIMEOUT (default 2s)
+func ConfigFromEnv() (Config, error) {
+	interval, err := config.Duration("HEALTH_PROBE_INTERVAL", 15*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	timeout, err := config.Duration("HEALTH_PROBE_TIMEOUT", 2*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if interval <= 0 || timeout <= 0 || timeout > interval {
+		return Config{}, errors.New("HEALTH_PROBE_INTERVAL and HEALTH_PROBE_TIMEOUT must be positive, with the timeout at most the interval")
+	}
+	return Config{Interval: interval, Timeout: timeout}, nil
+}
+
+// Probe checks that a dependency is available
+type Probe struct {
+	Name string
+	// Required dependencies make the service unavailable while down; the others only degrade it
+	Required bool
+	Check    func(ctx context.Context) error
+}
+
+// HTTPCheck returns a check that a server answers a GET of url. Any response below 500 counts as
+// available: the probe verifies that the server is up, not that the request is valid
+func HTTPCheck(client *http.Client, url string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// DependencyStatus is the result of the last probe of a dependency
+type DependencyStatus struct {
+	Name      string    `json:"name"`
+	Required  bool      `json:"required"`
+	Up        bool      `json:"up"`
+	LatencyMs int64     `json:"latency_ms"`
+	CheckedAt time.Time `json:"checked_at,omitzero"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Report is the availability of the service and of each of its dependencies
+type Report struct {
+	// Status is ok when every dependency is up, degraded when only optional ones are down and
+	// unavailable when a required one is down or was not probed yet
+	Status       string             `json:"status"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// Ready reports whether the service can take traffic, i.e. no required dependency is down
+func (r Report) Ready() bool {
+	return r.Status != StatusUnavailable
+}
+
+// Redacted returns the report without the probe errors, which may reveal hosts, addresses and
+// other internals of the dependencies, for callers that are not trusted with them; Run logs the
+// errors instead
+func (r Report) Redacted() Report {
+	dependencies := make([]DependencyStatus, len(r.Dependencies))
+	for i, status := range r.Dependencies {
+		status.Error = ""
+		dependencies[i] = status
+	}
+	return Report{Status: r.Status, Dependencies: dependencies}
+}
+
+// Monitor probes dependencies and keeps the result of the last probe of each one, safe for
+// concurrent use
+type Monitor struct {
+	probes  []Probe
+	timeout time.Duration
+	metrics *telemetry.Metrics
+
+	mu       sync.RWMutex
+	statuses []DependencyStatus
+}
+
+// NewMonitor creates a monitor for the probes, recording their availability and latency in
+// metrics; dependencies are down until first probed
+func NewMonitor(cfg Config, metrics *telemetry.Metrics, probes ...Probe) *Monitor {
+	statuses := make([]DependencyStatus, len(probes))
+	for i, probe := range probes {
+		statuses[i] = DependencyStatus{Name: probe.Name, Required: probe.Required, Error: "not probed yet"}
+	}
+	return &Monitor{probes: probes, timeout: cfg.Timeout, metrics: metrics, statuses: statuses}
+}
+
+// ProbeAll probes every dependency concurrently, records their availability and latency metrics
+// and returns the dependencies whose availability changed
+func (m *Monitor) ProbeAll(ctx context.Context) []DependencyStatus {
+	results := make([]DependencyStatus, len(m.probes))
+	var wg sync.WaitGroup
+	for i, probe := range m.probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = m.probe(ctx, probe)
+		}()
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var changed []DependencyStatus
+	for i, result := range results {
+		if previous := m.statuses[i]; previous.CheckedAt.IsZero() || previous.Up != result.Up {
+			changed = append(changed, result)
+		}
+		m.statuses[i] = result
+	}
+	return changed
+}
+
+// probe runs a single check within the timeout
+func (m *Monitor) probe(ctx context.Context, probe Probe) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := probe.Check(ctx)
+	latency := time.Since(start).Milliseconds()
+	m.metrics.RecordDependencyProbe(ctx, probe.Name, err == nil, latency)
+
+	status := DependencyStatus{
+		Name:      probe.Name,
+		Required:  probe.Required,
+		Up:        err == nil,
+		LatencyMs: latency,
+		CheckedAt: start.UTC(),
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// Report returns the result of the last probe of every dependency
+func (m *Monitor) Report() Report {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	report := Report{Status: StatusOK, Dependencies: append([]DependencyStatus{}, m.statuses...)}
+	for _, status := range m.statuses {
+		switch {
+		case status.Up:
+		case status.Required:
+			report.Status = StatusUnavailable
+		case report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// Run probes the dependencies right away and then every interval until ctx is cancelled, logging
+// every change of availability
+func (m *Monitor) Run(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, status := range m.ProbeAll(ctx) {
+			if status.Up {
+				logger.Info("Dependency is up", zap.String("dependency", status.Name), zap.Int64("latency_ms", status.LatencyMs))
+				continue
+			}
+			logger.Warn("Dependency is down",
+				zap.String("dependency", status.Name),
+				zap.Bool("required", status.Required),
+				zap.String("error", status.Error),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func up(ctx context.Context) error { return nil }
+
+func down(ctx context.Context) error { return errors.New("connection refused") }
+
+func testConfig() Config {
+	return Config{Interval: time.Minute, Timeout: 50 * time.Millisecond}
+}
+
+func TestMonitor_Report(t *testing.T) {
+	tests := []struct {
+		name       string
+		probes     []Probe
+		wantStatus string
+		wantReady  bool
+	}{
+		{"all up", []Probe{{Name: "postgres", Required: true, Check: up}, {Name: "carrier", Check: up}}, StatusOK, true},
+		{"optional down", []Probe{{Name: "postgres", Required: true, Check: up}, {Name: "carrier", Check: down}}, StatusDegraded, true},
+		{"required down", []Probe{{Name: "postgres", Required: true, Check: down}, {Name: "carrier", Check: down}}, StatusUnavailable, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			monitor := NewMonitor(testConfig(), nil, tt.probes...)
+
+			// Act
+			monitor.ProbeAll(context.Background())
+			report := monitor.Report()
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, report.Status)
+			assert.Equal(t, tt.wantReady, report.Ready())
+			assert.Len(t, report.Dependencies, len(tt.probes))
+			for i, status := range report.Dependencies {
+				assert.Equal(t, tt.probes[i].Name, status.Name)
+				assert.False(t, status.CheckedAt.IsZero())
+				assert.Equal(t, status.Up, status.Error == "")
+			}
+		})
+	}
+}
+
+func TestReport_Redacted(t *testing.T) {
+	// Arrange
+	monitor := NewMonitor(testConfig(), nil, Probe{Name: "postgres", Required: true, Check: up}, Probe{Name: "carrier", Check: down})
+	monitor.ProbeAll(context.Background())
+	report := monitor.Report()
+
+	// Act
+	redacted := report.Redacted()
+
+	// Assert
+	assert.Equal(t, StatusDegraded, redacted.Status)
+	assert.Equal(t, "carrier", redacted.Dependencies[1].Name)
+	assert.False(t, redacted.Dependencies[1].Up)
+	assert.Empty(t, redacted.Dependencies[1].Error)
+	assert.NotEmpty(t, report.Dependencies[1].Error, "the report itself keeps the errors")
+}
+
+func TestMonitor_NotProbedYet(t *testing.T) {
+	// Arrange
+	monitor := NewMonitor(testConfig(), nil, Probe{Name: "redis", Required: true, Check: up})
+
+	// Act
+	report := monitor.Report()
+
+	// Assert
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, "not probed yet", report.Dependencies[0].Error)
+}
+
+func TestMonitor_Timeout(t *testing.T) {
+	// Arrange
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	monitor := NewMonitor(testConfig(), nil, Probe{Name: "redis", Required: true, Check: slow})
+
+	// Act
+	monitor.ProbeAll(context.Background())
+	report := monitor.Report()
+
+	// Assert
+	assert.False(t, report.Dependencies[0].Up)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Dependencies[0].Error)
+	assert.GreaterOrEqual(t, report.Dependencies[0].LatencyMs, int64(50))
+}
+
+func TestMonitor_ProbeAll_Changes(t *testing.T) {
+	// Arrange
+	var failing atomic.Bool
+	check := func(ctx context.Context) error {
+		if failing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	monitor := NewMonitor(testConfig(), nil, Probe{Name: "redis", Check: check}, Probe{Name: "cep", Check: up})
+
+	// Act
+	first := monitor.ProbeAll(context.Background())
+	unchanged := monitor.ProbeAll(context.Background())
+	failing.Store(true)
+	wentDown := monitor.ProbeAll(context.Background())
+
+	// Assert
+	assert.Len(t, first, 2)
+	assert.Empty(t, unchanged)
+	assert.Len(t, wentDown, 1)
+	assert.Equal(t, "redis", wentDown[0].Name)
+	assert.False(t, wentDown[0].Up)
+}
+
+func TestMonitor_Run(t *testing.T) {
+	// Arrange
+	var probes atomic.Int32
+	check := func(ctx context.Context) error {
+		probes.Add(1)
+		return nil
+	}
+	monitor := NewMonitor(testConfig(), nil, Probe{Name: "redis", Required: true, Check: check})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Act
+	go func() {
+		monitor.Run(ctx, 10*time.Millisecond, zaptest.NewLogger(t))
+		close(done)
+	}()
+	assert.Eventually(t, func() bool { return probes.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	cancel()
+
+	// Assert
+	assert.Eventually(t, func() bool {
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, monitor.Report().Ready())
+}
+
+func TestHTTPCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr string
+	}{
+		{"ok", http.StatusOK, ""},
+		{"client error still answers", http.StatusMethodNotAllowed, ""},
+		{"server error", http.StatusBadGateway, "unexpected status 502"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+			check := HTTPCheck(server.Client(), server.URL)
+
+			// Act
+			err := check(context.Background())
+
+			// Assert
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHTTPCheck_Unreachable(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	// Act
+	err := HTTPCheck(http.DefaultClient, url)(context.Background())
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval string
+		timeout  string
+		want     Config
+		wantErr  bool
+	}{
+		{"defaults", "", "", Config{Interval: 15 * time.Second, Timeout: 2 * time.Second}, false},
+		{"custom values", "1m", "5s", Config{Interval: time.Minute, Timeout: 5 * time.Second}, false},
+		{"timeout over interval", "1s", "2s", Config{}, true},
+		{"invalid interval", "often", "", Config{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("HEALTH_PROBE_INTERVAL", tt.interval)
+			t.Setenv("HEALTH_PROBE_TIMEOUT", tt.timeout)
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
@@ -0,0 +1,238 @@
+// Package holiday keeps the national and regional holiday calendar used to estimate delivery dates.
+package holiday
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+)
+
+// dateLayout is the format of holiday dates
+const dateLayout = "2006-01-02"
+
+// Holiday is a day without pickups or deliveries in a country, or only in one of its states
+type Holiday struct {
+	Date    string `json:"date"`
+	Name    string `json:"name"`
+	Country string `json:"country"`
+	// State restricts the holiday to a state (e.g. "SP"); empty means a national holiday
+	State string `json:"state,omitempty"`
+}
+
+// File is the JSON document served by the file and remote sources
+type File struct {
+	Holidays []Holiday `json:"holidays"`
+}
+
+// Validate checks that every holiday has a valid date and a country
+func (f File) Validate() error {
+	for _, h := range f.Holidays {
+		if _, err := time.Parse(dateLayout, h.Date); err != nil {
+			return fmt.Errorf("holiday %q: invalid date %q", h.Name, h.Date)
+		}
+		if h.Country == "" {
+			return fmt.Errorf("holiday %q on %s: country is required", h.Name, h.Date)
+		}
+	}
+	return nil
+}
+
+// Source provides holidays
+type Source interface {
+	Holidays(ctx context.Context) ([]Holiday, error)
+}
+
+// FileSource reads holidays from a JSON file
+type FileSource struct {
+	Path string
+}
+
+// Holidays reads and validates the file
+func (s FileSource) Holidays(ctx context.Context) ([]Holiday, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read holiday calendar: %w", err)
+	}
+	return decode(data)
+}
+
+// HTTPSource fetches holidays from a remote JSON document with the same format as the file
+type HTTPSource struct {
+	URL    string
+	Client *http.Client
+}
+
+// Holidays downloads and validates the document
+func (s HTTPSource) Holidays(ctx context.Context) ([]Holiday, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch holiday calendar: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch holiday calendar: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch holiday calendar: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch holiday calendar: %w", err)
+	}
+	return decode(data)
+}
+
+func decode(data []byte) ([]Holiday, error) {
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse holiday calendar: %w", err)
+	}
+	if err := file.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid holiday calendar: %w", err)
+	}
+	return file.Holidays, nil
+}
+
+// Config configures the calendar sources
+type Config struct {
+	// Path is the static holiday file; URL is an optional remote source merged with it
+	Path           string
+	URL            string
+	ReloadInterval time.Duration
+}
+
+// ConfigFromEnv reads HOLIDAY_CALENDAR_PATH, HOLIDAY_CALENDAR_URL and HOLIDAY_RELOAD_INTERVAL (default 24h)
+func ConfigFromEnv() (Config, error) {
+	interval, err := config.Duration("HOLIDAY_RELOAD_INTERVAL", 24*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+	if interval <= 0 {
+		return Config{}, errors.New("HOLIDAY_RELOAD_INTERVAL must be positive")
+	}
+	return Config{
+		Path:           config.String("HOLIDAY_CALENDAR_PATH", ""),
+		URL:            config.String("HOLIDAY_CALENDAR_URL", ""),
+		ReloadInterval: interval,
+	}, nil
+}
+
+// Sources returns the configured sources; the calendar is empty when none is configured
+func (c Config) Sources() []Source {
+	var sources []Source
+	if c.Path != "" {
+		sources = append(sources, FileSource{Path: c.Path})
+	}
+	if c.URL != "" {
+		sources = append(sources, HTTPSource{URL: c.URL, Client: &http.Client{Timeout: 10 * time.Second}})
+	}
+	return sources
+}
+
+// Calendar answers holiday queries from the merged sources. It is safe for concurrent use and
+// can be reloaded at runtime; a failed reload keeps the previous holidays
+type Calendar struct {
+	sources []Source
+
+	mu   sync.RWMutex
+	days map[string]struct{}
+}
+
+// NewCalendar creates an empty calendar; call Reload to load the sources
+func NewCalendar(sources ...Source) *Calendar {
+	return &Calendar{sources: sources, days: map[string]struct{}{}}
+}
+
+// Reload loads every source and replaces the holidays atomically
+func (c *Calendar) Reload(ctx context.Context) error {
+	days := make(map[string]struct{})
+	for _, source := range c.sources {
+		holidays, err := source.Holidays(ctx)
+		if err != nil {
+			return err
+		}
+		for _, h := range holidays {
+			days[key(h.Country, h.State, h.Date)] = struct{}{}
+		}
+	}
+
+	c.mu.Lock()
+	c.days = days
+	c.mu.Unlock()
+	return nil
+}
+
+// Len returns the number of loaded holidays
+func (c *Calendar) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.days)
+}
+
+// IsHoliday reports whether day is a national holiday in the country or a holiday of the state
+// (UF) that the CEP belongs to, resolved by zipcode.State. Only Brazilian holidays are regional;
+// other countries have national holidays alone
+func (c *Calendar) IsHoliday(country, cep string, day time.Time) bool {
+	country = strings.ToUpper(country)
+	date := day.Format(dateLayout)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if _, ok := c.days[key(country, "", date)]; ok {
+		return true
+	}
+	if country != "BR" {
+		return false
+	}
+	state := zipcode.State(cep)
+	if state == "" {
+		return false
+	}
+	_, ok := c.days[key(country, state, date)]
+	return ok
+}
+
+// Run reloads the calendar on every interval until ctx is cancelled, logging failures
+func (c *Calendar) Run(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Reload(ctx); err != nil {
+				logger.Error("Failed to reload holiday calendar", zap.Error(err))
+				continue
+			}
+			logger.Info("Holiday calendar reloaded", zap.Int("holidays", c.Len()))
+		}
+	}
+}
+
+func key(country, state, date string) string {
+	return strings.ToUpper(country) + "|" + strings.ToUpper(state) + "|" + date
+}
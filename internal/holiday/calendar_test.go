@@ -0,0 +1,181 @@
+package holiday
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// staticSource is a Source returning fixed holidays or an error
+type staticSource struct {
+	holidays []Holiday
+	err      error
+}
+
+func (s *staticSource) Holidays(ctx context.Context) ([]Holiday, error) {
+	return s.holidays, s.err
+}
+
+var carnaval = time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)
+
+func TestCalendar_IsHoliday(t *testing.T) {
+	// Arrange
+	calendar := NewCalendar(&staticSource{holidays: []Holiday{
+		{Date: "2025-03-04", Name: "Carnaval", Country: "BR"},
+		{Date: "2025-07-09", Name: "Revolução Constitucionalista", Country: "BR", State: "SP"},
+		{Date: "2025-07-04", Name: "Independence Day", Country: "US"},
+	}})
+	assert.NoError(t, calendar.Reload(context.Background()))
+
+	tests := []struct {
+		name    string
+		country string
+		zipcode string
+		day     time.Time
+		want    bool
+	}{
+		{"national holiday", "BR", "20040-002", carnaval, true},
+		{"lowercase country", "br", "20040-002", carnaval, true},
+		{"regular day", "BR", "20040-002", carnaval.AddDate(0, 0, 2), false},
+		{"state holiday in the state", "BR", "01310-100", time.Date(2025, 7, 9, 15, 0, 0, 0, time.UTC), true},
+		{"state holiday in another state", "BR", "20040-002", time.Date(2025, 7, 9, 0, 0, 0, 0, time.UTC), false},
+		{"holiday of another country", "BR", "01310-100", time.Date(2025, 7, 4, 0, 0, 0, 0, time.UTC), false},
+		{"holiday of the country", "US", "10001", time.Date(2025, 7, 4, 0, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := calendar.IsHoliday(tt.country, tt.zipcode, tt.day)
+
+			// Assert
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestCalendar_Reload_KeepsHolidaysOnError(t *testing.T) {
+	// Arrange
+	source := &staticSource{holidays: []Holiday{{Date: "2025-03-04", Name: "Carnaval", Country: "BR"}}}
+	calendar := NewCalendar(source)
+	assert.NoError(t, calendar.Reload(context.Background()))
+	source.err = errors.New("unavailable")
+
+	// Act
+	err := calendar.Reload(context.Background())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, 1, calendar.Len())
+	assert.True(t, calendar.IsHoliday("BR", "01310100", carnaval))
+}
+
+func TestCalendar_Reload_ReplacesHolidays(t *testing.T) {
+	// Arrange
+	source := &staticSource{holidays: []Holiday{{Date: "2025-03-04", Name: "Carnaval", Country: "BR"}}}
+	calendar := NewCalendar(source)
+	assert.NoError(t, calendar.Reload(context.Background()))
+	source.holidays = []Holiday{{Date: "2025-03-03", Name: "Carnaval", Country: "BR"}}
+
+	// Act
+	err := calendar.Reload(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, calendar.IsHoliday("BR", "01310100", carnaval))
+	assert.True(t, calendar.IsHoliday("BR", "01310100", carnaval.AddDate(0, 0, -1)))
+}
+
+func TestFileSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"valid file", `{"holidays": [{"date": "2025-03-04", "name": "Carnaval", "country": "BR"}]}`, ""},
+		{"invalid json", `{`, "failed to parse"},
+		{"invalid date", `{"holidays": [{"date": "04/03/2025", "name": "Carnaval", "country": "BR"}]}`, "invalid date"},
+		{"missing country", `{"holidays": [{"date": "2025-03-04", "name": "Carnaval"}]}`, "country is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), "holidays.json")
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			// Act
+			holidays, err := FileSource{Path: path}.Holidays(context.Background())
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, holidays, 1)
+		})
+	}
+}
+
+func TestHTTPSource(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"holidays": [{"date": "2025-03-04", "name": "Carnaval", "country": "BR"}]}`))
+	}))
+	defer server.Close()
+
+	// Act
+	holidays, err := HTTPSource{URL: server.URL}.Holidays(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []Holiday{{Date: "2025-03-04", Name: "Carnaval", Country: "BR"}}, holidays)
+}
+
+func TestHTTPSource_UnexpectedStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// Act
+	_, err := HTTPSource{URL: server.URL}.Holidays(context.Background())
+
+	// Assert
+	assert.ErrorContains(t, err, "unexpected status 503")
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	// Arrange
+	t.Setenv("HOLIDAY_CALENDAR_PATH", "/etc/holidays.json")
+	t.Setenv("HOLIDAY_CALENDAR_URL", "https://example.com/holidays.json")
+	t.Setenv("HOLIDAY_RELOAD_INTERVAL", "1h")
+
+	// Act
+	cfg, err := ConfigFromEnv()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, cfg.ReloadInterval)
+	assert.Len(t, cfg.Sources(), 2)
+}
+
+func TestConfigFromEnv_InvalidInterval(t *testing.T) {
+	// Arrange
+	t.Setenv("HOLIDAY_RELOAD_INTERVAL", "0s")
+
+	// Act
+	_, err := ConfigFromEnv()
+
+	// Assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,66 @@
+package label
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPProvider_Contract(t *testing.T) {
+	// Arrange
+	server := testsupport.NewLabelServer(t)
+	provider := NewHTTPProvider(Config{ProviderURL: server.URL, Timeout: time.Second})
+
+	// Act
+	document, err := provider.Generate(context.Background(), shipment, model.LabelFormatPDF, "s1:pdf")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "LBL-7781", document.LabelID)
+	assert.Equal(t, "BR123456789BR", document.TrackingCode)
+	assert.Equal(t, "%PDF-1.4", string(document.Content[:8]))
+	requests := server.Requests()
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "s1:pdf", requests[0].Header.Get(IdempotencyKeyHeader))
+	testsupport.AssertGolden(t, "testdata/label_request.golden.json", requests[0].Body)
+}
+
+func TestHTTPProvider_ContractFaults(t *testing.T) {
+	tests := []struct {
+		name         string
+		fault        testsupport.Fault
+		wantErr      string
+		wantAttempts int
+	}{
+		{"recovers from a transient outage", testsupport.Fault{Status: http.StatusServiceUnavailable, Times: 1}, "", 2},
+		{"rate limited", testsupport.Fault{Status: http.StatusTooManyRequests}, "unexpected status 429", 3},
+		{"rejected", testsupport.Fault{Status: http.StatusUnprocessableEntity, Body: []byte(`{"error":"invalid package"}`)}, "unexpected status 422", 1},
+		{"malformed", testsupport.Fault{Malformed: true}, "invalid response body", 1},
+		{"connection reset", testsupport.Fault{Reset: true}, "label: ", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := testsupport.NewLabelServer(t)
+			server.Fail(tt.fault)
+			provider := NewHTTPProvider(Config{ProviderURL: server.URL, Timeout: time.Second, Retries: 2, RetryBackoff: time.Millisecond})
+
+			// Act
+			_, err := provider.Generate(context.Background(), shipment, model.LabelFormatPDF, "s1:pdf")
+
+			// Assert
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+			assert.Len(t, server.Requests(), tt.wantAttempts)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Package label requests the shipping labels of booked shipments from the carrier label API.
+package label
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// IdempotencyKeyHeader carries the key the carrier deduplicates label requests by, so that a
+// retried request does not generate, and bill, a second label
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxRetryBackoff caps the wait between attempts
+const maxRetryBackoff = 5 * time.Second
+
+// Document is a label generated by the carrier
+type Document struct {
+	// LabelID identifies the label at the carrier
+	LabelID      string
+	TrackingCode string
+	Content      []byte
+}
+
+// LabelProvider generates shipping labels at the carrier. Requests with the same idempotency key
+// return the same label
+type LabelProvider interface {
+	Generate(ctx context.Context, shipment *model.Shipment, format, idempotencyKey string) (Document, error)
+}
+
+// Config configures the carrier label API
+type Config struct {
+	// ProviderURL is the carrier label API, called at {ProviderURL}/labels; empty disables labels
+	ProviderURL string
+	// Timeout bounds each attempt
+	Timeout time.Duration
+	// Retries is how many times a request failing with a network error, 429 or 5xx is retried
+	Retries int
+	// RetryBackoff is the wait before the first retry; it doubles on every retry up to 5 seconds
+	RetryBackoff time.Duration
+}
+
+// Enabled reports whether labels can be generated
+func (c Config) Enabled() bool {
+	return c.ProviderURL != ""
+}
+
+// ConfigFromEnv reads LABEL_PROVIDER_URL (default none), LABEL_PROVIDER_TIMEOUT (default 10s),
+// LABEL_PROVIDER_RETRIES (default 2) and LABEL_PROVIDER_RETRY_BACKOFF (default 200ms)
+func ConfigFromEnv() (Config, error) {
+	timeout, err := config.Duration("LABEL_PROVIDER_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if timeout <= 0 {
+		return Config{}, fmt.Errorf("LABEL_PROVIDER_TIMEOUT must be positive, got %s", timeout)
+	}
+	retries, err := config.Int("LABEL_PROVIDER_RETRIES", 2)
+	if err != nil {
+		return Config{}, err
+	}
+	if retries < 0 {
+		return Config{}, fmt.Errorf("LABEL_PROVIDER_RETRIES must not be negative, got %d", retries)
+	}
+	backoff, err := config.Duration("LABEL_PROVIDER_RETRY_BACKOFF", 200*time.Millisecond)
+	if err != nil {
+		return Config{}, err
+	}
+	if backoff <= 0 {
+		return Config{}, fmt.Errorf("LABEL_PROVIDER_RETRY_BACKOFF must be positive, got %s", backoff)
+	}
+	return Config{
+		ProviderURL:  strings.TrimRight(config.String("LABEL_PROVIDER_URL", ""), "/"),
+		Timeout:      timeout,
+		Retries:      retries,
+		RetryBackoff: backoff,
+	}, nil
+}
+
+// HTTPProvider generates labels with a carrier label API
+type HTTPProvider struct {
+	cfg        Config
+	httpClient *http.Client
+}
+
+// NewHTTPProvider creates a provider for the configured label API
+func NewHTTPProvider(cfg Config) *HTTPProvider {
+	return &HTTPProvider{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// labelRequest is the body sent to the label API
+type labelRequest struct {
+	ShipmentID string                `json:"shipment_id"`
+	Service    string                `json:"service"`
+	Format     string                `json:"format"`
+	Package    model.ShipmentPackage `json:"package"`
+}
+
+// labelResponse is the body returned by the label API; content is base64-encoded
+type labelResponse struct {
+	LabelID      string `json:"label_id"`
+	TrackingCode string `json:"tracking_code"`
+	Content      []byte `json:"content"`
+}
+
+// retryableError is a failure worth retrying: a network error, 429 or 5xx
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+
+func (e *retryableError) Unwrap() error { return e.err }
+
+// Generate requests a label, propagating the trace context of ctx and sending idempotencyKey in the
+// Idempotency-Key header. Failed attempts are retried with the same key
+func (p *HTTPProvider) Generate(ctx context.Context, shipment *model.Shipment, format, idempotencyKey string) (Document, error) {
+	body, err := json.Marshal(labelRequest{ShipmentID: shipment.ID, Service: shipment.Service, Format: format, Package: shipment.Package})
+	if err != nil {
+		return Document{}, fmt.Errorf("label: %w", err)
+	}
+
+	backoff := p.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		document, err := p.generate(ctx, body, idempotencyKey)
+		var retryable *retryableError
+		if err == nil || !errors.As(err, &retryable) || attempt >= p.cfg.Retries {
+			return document, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Document{}, fmt.Errorf("label: %w", ctx.Err())
+		case <-timer.C:
+		}
+		backoff = min(2*backoff, maxRetryBackoff)
+	}
+}
+
+// generate makes a single attempt
+func (p *HTTPProvider) generate(ctx context.Context, body []byte, idempotencyKey string) (Document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.ProviderURL+"/labels", bytes.NewReader(body))
+	if err != nil {
+		return Document{}, fmt.Errorf("label: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, idempotencyKey)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return Document{}, &retryableError{fmt.Errorf("label: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return Document{}, &retryableError{fmt.Errorf("label: unexpected status %d", resp.StatusCode)}
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return Document{}, fmt.Errorf("label: unexpected status %d", resp.StatusCode)
+	}
+
+	var decoded labelResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return Document{}, fmt.Errorf("label: invalid response body: %w", err)
+	}
+	if len(decoded.Content) == 0 {
+		return Document{}, errors.New("label: empty label content")
+	}
+	return Document{LabelID: decoded.LabelID, TrackingCode: decoded.TrackingCode, Content: decoded.Content}, nil
+}
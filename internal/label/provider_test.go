@@ -0,0 +1,135 @@
+package label
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var shipment = &model.Shipment{
+	ID:      "s1",
+	Service: model.ServiceStandard,
+	Package: model.ShipmentPackage{OriginZipcode: "01310100", DestinationZipcode: "20040020", Weight: 1.5},
+}
+
+// newLabelServer answers the label requests with the statuses in order, the last one repeated,
+// and counts the attempts
+func newLabelServer(t *testing.T, attempts *atomic.Int32, statuses ...int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(attempts.Add(1))
+		assert.Equal(t, "/labels", r.URL.Path)
+		assert.Equal(t, "s1:pdf", r.Header.Get(IdempotencyKeyHeader), "every attempt carries the same key")
+		var body labelRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, labelRequest{ShipmentID: "s1", Service: model.ServiceStandard, Format: "pdf", Package: shipment.Package}, body)
+
+		status := statuses[min(n, len(statuses))-1]
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			// "JVBERi0=" is "%PDF-" base64-encoded
+			_, _ = w.Write([]byte(`{"label_id":"lbl-1","tracking_code":"BR123456789","content":"JVBERi0="}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{"defaults", map[string]string{}, Config{Timeout: 10 * time.Second, Retries: 2, RetryBackoff: 200 * time.Millisecond}, ""},
+		{
+			"custom values",
+			map[string]string{"LABEL_PROVIDER_URL": "https://labels.example.com/", "LABEL_PROVIDER_TIMEOUT": "3s", "LABEL_PROVIDER_RETRIES": "0", "LABEL_PROVIDER_RETRY_BACKOFF": "1s"},
+			Config{ProviderURL: "https://labels.example.com", Timeout: 3 * time.Second, RetryBackoff: time.Second},
+			"",
+		},
+		{"zero timeout", map[string]string{"LABEL_PROVIDER_TIMEOUT": "0s"}, Config{}, "LABEL_PROVIDER_TIMEOUT"},
+		{"negative retries", map[string]string{"LABEL_PROVIDER_RETRIES": "-1"}, Config{}, "LABEL_PROVIDER_RETRIES"},
+		{"zero backoff", map[string]string{"LABEL_PROVIDER_RETRY_BACKOFF": "0s"}, Config{}, "LABEL_PROVIDER_RETRY_BACKOFF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for _, key := range []string{"LABEL_PROVIDER_URL", "LABEL_PROVIDER_TIMEOUT", "LABEL_PROVIDER_RETRIES", "LABEL_PROVIDER_RETRY_BACKOFF"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantAttempts int32
+		wantErr      string
+	}{
+		{"generated", []int{http.StatusOK}, 1, ""},
+		{"retried after server errors", []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}, 3, ""},
+		{"retried after rate limiting", []int{http.StatusTooManyRequests, http.StatusOK}, 2, ""},
+		{"retries exhausted", []int{http.StatusInternalServerError}, 3, "unexpected status 500"},
+		{"rejected request not retried", []int{http.StatusBadRequest}, 1, "unexpected status 400"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var attempts atomic.Int32
+			server := newLabelServer(t, &attempts, tt.statuses...)
+			provider := NewHTTPProvider(Config{ProviderURL: server.URL, Timeout: time.Second, Retries: 2, RetryBackoff: time.Millisecond})
+
+			// Act
+			document, err := provider.Generate(context.Background(), shipment, model.LabelFormatPDF, "s1:pdf")
+
+			// Assert
+			assert.Equal(t, tt.wantAttempts, attempts.Load())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, Document{LabelID: "lbl-1", TrackingCode: "BR123456789", Content: []byte("%PDF-")}, document)
+		})
+	}
+}
+
+func TestGenerate_CancelledDuringBackoff(t *testing.T) {
+	// Arrange
+	var attempts atomic.Int32
+	server := newLabelServer(t, &attempts, http.StatusServiceUnavailable)
+	provider := NewHTTPProvider(Config{ProviderURL: server.URL, Timeout: time.Second, Retries: 5, RetryBackoff: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Act
+	_, err := provider.Generate(ctx, shipment, model.LabelFormatPDF, "s1:pdf")
+
+	// Assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), attempts.Load())
+}
@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// EncodingJSON emits one JSON object per log entry (default, parsed by the log pipeline)
+	EncodingJSON = "json"
+	// EncodingConsole emits human-readable entries for local development
+	EncodingConsole = "console"
+
+	redactedValue = "[REDACTED]"
+)
+
+// defaultRedactFields are field keys whose values are never written to the logs
+var defaultRedactFields = []string{
+	"api_key",
+	"authorization",
+	"password",
+	"secret",
+	"token",
+	"address",
+	"full_address",
+	"street",
+}
+
+// Config holds the logger configuration
+type Config struct {
+	Level              zapcore.Level
+	Encoding           string
+	SamplingEnabled    bool
+	SamplingInitial    int
+	SamplingThereafter int
+	RedactFields       []string
+}
+
+// DefaultConfig returns the production defaults: INFO level, JSON encoding and sampling enabled
+func DefaultConfig() Config {
+	return Config{
+		Level:              zapcore.InfoLevel,
+		Encoding:           EncodingJSON,
+		SamplingEnabled:    true,
+		SamplingInitial:    100,
+		SamplingThereafter: 100,
+		RedactFields:       defaultRedactFields,
+	}
+}
+
+// ConfigFromEnv builds the logger configuration from environment variables:
+// - LOG_LEVEL: debug, info, warn or error (default: info)
+// - LOG_ENCODING: json or console (default: json)
+// - LOG_SAMPLING_ENABLED: enables sampling of repeated entries (default: true)
+// - LOG_SAMPLING_INITIAL / LOG_SAMPLING_THEREAFTER: sampling parameters per second (default: 100/100)
+// - LOG_REDACT_FIELDS: comma-separated field keys redacted in addition to the defaults
+func ConfigFromEnv() (Config, error) {
+	cfg := DefaultConfig()
+
+	level, err := zapcore.ParseLevel(config.String("LOG_LEVEL", cfg.Level.String()))
+	if err != nil {
+		return cfg, fmt.Errorf("LOG_LEVEL: %w", err)
+	}
+	cfg.Level = level
+
+	cfg.Encoding = strings.ToLower(config.String("LOG_ENCODING", cfg.Encoding))
+	if cfg.Encoding != EncodingJSON && cfg.Encoding != EncodingConsole {
+		return cfg, fmt.Errorf("LOG_ENCODING must be %q or %q, got %q", EncodingJSON, EncodingConsole, cfg.Encoding)
+	}
+
+	if cfg.SamplingEnabled, err = config.Bool("LOG_SAMPLING_ENABLED", cfg.SamplingEnabled); err != nil {
+		return cfg, err
+	}
+	if cfg.SamplingInitial, err = config.Int("LOG_SAMPLING_INITIAL", cfg.SamplingInitial); err != nil {
+		return cfg, err
+	}
+	if cfg.SamplingThereafter, err = config.Int("LOG_SAMPLING_THEREAFTER", cfg.SamplingThereafter); err != nil {
+		return cfg, err
+	}
+
+	cfg.RedactFields = append(append([]string{}, defaultRedactFields...), config.List("LOG_REDACT_FIELDS", nil)...)
+
+	return cfg, nil
+}
+
+// New creates a zap logger writing to stderr according to the given configuration
+func New(cfg Config) (*zap.Logger, error) {
+	return build(cfg, zapcore.Lock(os.Stderr))
+}
+
+// build creates the logger core chain: encoder -> redaction -> sampling
+func build(cfg Config, sink zapcore.WriteSyncer) (*zap.Logger, error) {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	var encoder zapcore.Encoder
+	switch cfg.Encoding {
+	case EncodingJSON, "":
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case EncodingConsole:
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return nil, fmt.Errorf("unsupported log encoding %q", cfg.Encoding)
+	}
+
+	var core zapcore.Core = zapcore.NewCore(encoder, sink, zap.NewAtomicLevelAt(cfg.Level))
+	if len(cfg.RedactFields) > 0 {
+		core = newRedactingCore(core, cfg.RedactFields)
+	}
+	if cfg.SamplingEnabled {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, cfg.SamplingInitial, cfg.SamplingThereafter)
+	}
+
+	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel)), nil
+}
+
+// redactingCore replaces the values of sensitive fields before they reach the encoder
+type redactingCore struct {
+	zapcore.Core
+	fields map[string]struct{}
+}
+
+func newRedactingCore(core zapcore.Core, fields []string) *redactingCore {
+	set := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		set[strings.ToLower(f)] = struct{}{}
+	}
+	return &redactingCore{Core: core, fields: set}
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.redact(fields)), fields: c.fields}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.redact(fields))
+}
+
+// redact returns a copy of fields with sensitive values replaced, leaving the input untouched
+func (c *redactingCore) redact(fields []zapcore.Field) []zapcore.Field {
+	var redacted []zapcore.Field
+	for i, f := range fields {
+		if _, ok := c.fields[strings.ToLower(f.Key)]; !ok {
+			continue
+		}
+		if redacted == nil {
+			redacted = append([]zapcore.Field{}, fields...)
+		}
+		redacted[i] = zap.String(f.Key, redactedValue)
+	}
+	if redacted == nil {
+		return fields
+	}
+	return redacted
+}
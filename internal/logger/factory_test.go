@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func newBufferedLogger(t *testing.T, cfg Config) (*zap.Logger, *bytes.Buffer) {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	l, err := build(cfg, zapcore.AddSync(buf))
+	assert.NoError(t, err)
+	return l, buf
+}
+
+func TestDefaultConfig(t *testing.T) {
+	// Act
+	cfg := DefaultConfig()
+
+	// Assert
+	assert.Equal(t, zapcore.InfoLevel, cfg.Level)
+	assert.Equal(t, EncodingJSON, cfg.Encoding)
+	assert.True(t, cfg.SamplingEnabled)
+	assert.Contains(t, cfg.RedactFields, "api_key")
+}
+
+func TestConfigFromEnv_Defaults(t *testing.T) {
+	// Arrange
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_ENCODING", "")
+	t.Setenv("LOG_SAMPLING_ENABLED", "")
+	t.Setenv("LOG_REDACT_FIELDS", "")
+
+	// Act
+	cfg, err := ConfigFromEnv()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), cfg)
+}
+
+func TestConfigFromEnv_Overrides(t *testing.T) {
+	// Arrange
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("LOG_ENCODING", "console")
+	t.Setenv("LOG_SAMPLING_ENABLED", "false")
+	t.Setenv("LOG_REDACT_FIELDS", "cpf, phone")
+
+	// Act
+	cfg, err := ConfigFromEnv()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, zapcore.WarnLevel, cfg.Level)
+	assert.Equal(t, EncodingConsole, cfg.Encoding)
+	assert.False(t, cfg.SamplingEnabled)
+	assert.Contains(t, cfg.RedactFields, "api_key")
+	assert.Contains(t, cfg.RedactFields, "cpf")
+	assert.Contains(t, cfg.RedactFields, "phone")
+}
+
+func TestConfigFromEnv_InvalidLevel(t *testing.T) {
+	// Arrange
+	t.Setenv("LOG_LEVEL", "verbose")
+
+	// Act
+	_, err := ConfigFromEnv()
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "LOG_LEVEL")
+}
+
+func TestConfigFromEnv_InvalidEncoding(t *testing.T) {
+	// Arrange
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_ENCODING", "xml")
+
+	// Act
+	_, err := ConfigFromEnv()
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "LOG_ENCODING")
+}
+
+func TestNew(t *testing.T) {
+	// Act
+	l, err := New(DefaultConfig())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, l)
+}
+
+func TestBuild_UnsupportedEncoding(t *testing.T) {
+	// Arrange
+	cfg := DefaultConfig()
+	cfg.Encoding = "xml"
+
+	// Act
+	l, err := build(cfg, zapcore.AddSync(&bytes.Buffer{}))
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, l)
+}
+
+func TestBuild_LevelFiltering(t *testing.T) {
+	// Arrange
+	cfg := DefaultConfig()
+	cfg.Level = zapcore.WarnLevel
+	l, buf := newBufferedLogger(t, cfg)
+
+	// Act
+	l.Info("info message")
+	l.Warn("warn message")
+
+	// Assert
+	assert.NotContains(t, buf.String(), "info message")
+	assert.Contains(t, buf.String(), "warn message")
+}
+
+func TestBuild_JSONEncoding(t *testing.T) {
+	// Arrange
+	l, buf := newBufferedLogger(t, DefaultConfig())
+
+	// Act
+	l.Info("json message", zap.String("origem", "01310100"))
+
+	// Assert
+	var entry map[string]interface{}
+	err := json.Unmarshal(buf.Bytes(), &entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "json message", entry["msg"])
+	assert.Equal(t, "01310100", entry["origem"])
+}
+
+func TestBuild_ConsoleEncoding(t *testing.T) {
+	// Arrange
+	cfg := DefaultConfig()
+	cfg.Encoding = EncodingConsole
+	l, buf := newBufferedLogger(t, cfg)
+
+	// Act
+	l.Info("console message")
+
+	// Assert
+	assert.Contains(t, buf.String(), "INFO")
+	assert.Contains(t, buf.String(), "console message")
+	assert.False(t, strings.HasPrefix(buf.String(), "{"))
+}
+
+func TestBuild_RedactsSensitiveFields(t *testing.T) {
+	// Arrange
+	l, buf := newBufferedLogger(t, DefaultConfig())
+
+	// Act
+	l.With(zap.String("Authorization", "Bearer abc")).Info("request",
+		zap.String("api_key", "secret-key"),
+		zap.String("address", "Av. Paulista, 1000"),
+		zap.String("destino", "04547130"),
+	)
+
+	// Assert
+	output := buf.String()
+	assert.NotContains(t, output, "secret-key")
+	assert.NotContains(t, output, "Bearer abc")
+	assert.NotContains(t, output, "Paulista")
+	assert.Contains(t, output, "04547130")
+	assert.Equal(t, 3, strings.Count(output, redactedValue))
+}
+
+func TestBuild_Sampling(t *testing.T) {
+	// Arrange
+	cfg := DefaultConfig()
+	cfg.SamplingInitial = 2
+	cfg.SamplingThereafter = 1000
+	l, buf := newBufferedLogger(t, cfg)
+
+	// Act
+	for i := 0; i < 10; i++ {
+		l.Info("repeated message")
+	}
+
+	// Assert
+	assert.Equal(t, 2, strings.Count(buf.String(), "repeated message"))
+}
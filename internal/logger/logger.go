@@ -8,6 +8,13 @@ import (
 	"go.uber.org/zap"
 )
 
+// contextKey is the unexported type for values stored by this package in a context,
+// preventing collisions with keys defined in other packages
+type contextKey struct{}
+
+// loggerKey is the context key for the request-scoped logger
+var loggerKey = contextKey{}
+
 // GetCorrelationID extracts correlation_id from context (from chi middleware.RequestID)
 func GetCorrelationID(ctx context.Context) string {
 	if reqID := middleware.GetReqID(ctx); reqID != "" {
@@ -83,13 +90,25 @@ func LogError(logger *zap.Logger, ctx context.Context, message string, err error
 	logger.Error(message, allFields...)
 }
 
-// GetLoggerFromContext extracts logger from context or returns the default logger
+// NewContext returns a copy of ctx carrying the given logger
+func NewContext(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// FromContext returns the request-scoped logger stored in ctx by NewContext.
+// When none is present it falls back to the global zap logger enriched with the tracing fields from ctx
+func FromContext(ctx context.Context) *zap.Logger {
+	if l, ok := ctx.Value(loggerKey).(*zap.Logger); ok && l != nil {
+		return l
+	}
+	return WithTracingFields(zap.L(), ctx)
+}
+
+// GetLoggerFromContext extracts logger from context or returns the default logger (deprecated, use FromContext)
 // The returned logger includes trace_id and span_id from the context
 func GetLoggerFromContext(ctx context.Context, defaultLogger *zap.Logger) *zap.Logger {
-	if ctxLogger := ctx.Value("logger"); ctxLogger != nil {
-		if l, ok := ctxLogger.(*zap.Logger); ok {
-			return WithTracingFields(l, ctx)
-		}
+	if l, ok := ctx.Value(loggerKey).(*zap.Logger); ok && l != nil {
+		return l
 	}
 	return WithTracingFields(defaultLogger, ctx)
 }
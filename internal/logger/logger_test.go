@@ -240,13 +240,13 @@ func TestGetLoggerFromContext_WithLoggerInContext(t *testing.T) {
 	// Arrange
 	defaultLogger := zaptest.NewLogger(t)
 	ctxLogger := zaptest.NewLogger(t)
-	ctx := context.WithValue(context.Background(), "logger", ctxLogger)
+	ctx := NewContext(context.Background(), ctxLogger)
 
 	// Act
 	result := GetLoggerFromContext(ctx, defaultLogger)
 
 	// Assert
-	assert.NotNil(t, result)
+	assert.Same(t, ctxLogger, result)
 }
 
 func TestGetLoggerFromContext_WithoutLoggerInContext(t *testing.T) {
@@ -261,22 +261,25 @@ func TestGetLoggerFromContext_WithoutLoggerInContext(t *testing.T) {
 	assert.NotNil(t, result)
 }
 
-func TestGetLoggerFromContext_WithInvalidLoggerType(t *testing.T) {
+func TestGetLoggerFromContext_IgnoresStringKey(t *testing.T) {
 	// Arrange
 	defaultLogger := zaptest.NewLogger(t)
-	ctx := context.WithValue(context.Background(), "logger", "not-a-logger")
+	ctxLogger := zaptest.NewLogger(t)
+	type foreignKey string
+	ctx := context.WithValue(context.Background(), foreignKey("logger"), ctxLogger)
 
 	// Act
 	result := GetLoggerFromContext(ctx, defaultLogger)
 
 	// Assert
 	assert.NotNil(t, result)
+	assert.NotSame(t, ctxLogger, result)
 }
 
 func TestGetLoggerFromContext_WithNilLogger(t *testing.T) {
 	// Arrange
 	defaultLogger := zaptest.NewLogger(t)
-	ctx := context.WithValue(context.Background(), "logger", nil)
+	ctx := NewContext(context.Background(), nil)
 
 	// Act
 	result := GetLoggerFromContext(ctx, defaultLogger)
@@ -285,6 +288,40 @@ func TestGetLoggerFromContext_WithNilLogger(t *testing.T) {
 	assert.NotNil(t, result)
 }
 
+func TestNewContext_FromContext(t *testing.T) {
+	// Arrange
+	ctxLogger := zaptest.NewLogger(t)
+
+	// Act
+	ctx := NewContext(context.Background(), ctxLogger)
+	result := FromContext(ctx)
+
+	// Assert
+	assert.Same(t, ctxLogger, result)
+}
+
+func TestFromContext_WithoutLogger(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	// Act
+	result := FromContext(ctx)
+
+	// Assert
+	assert.NotNil(t, result)
+}
+
+func TestFromContext_WithNilLogger(t *testing.T) {
+	// Arrange
+	ctx := NewContext(context.Background(), nil)
+
+	// Act
+	result := FromContext(ctx)
+
+	// Assert
+	assert.NotNil(t, result)
+}
+
 func TestWithTracingFields_WithCorrelationIDOnly(t *testing.T) {
 	// Arrange
 	logger := zaptest.NewLogger(t)
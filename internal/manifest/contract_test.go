@@ -0,0 +1,63 @@
+package manifest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+var contractShipments = []model.Shipment{
+	{ID: "s1", Service: model.ServiceStandard, Package: model.ShipmentPackage{OriginZipcode: "01310100", DestinationZipcode: "20040020", Weight: 1.5, Dimensions: model.PackageDimensions{Length: 20, Width: 15, Height: 10}}},
+	{ID: "s2", Service: model.ServiceStandard, Package: model.ShipmentPackage{OriginZipcode: "01310100", DestinationZipcode: "10001", DestinationCountry: "US", Weight: 0.8, Dimensions: model.PackageDimensions{Length: 10, Width: 10, Height: 5}}},
+}
+
+func TestHTTPSubmitter_Contract(t *testing.T) {
+	// Arrange
+	server := testsupport.NewManifestServer(t)
+	submitter := NewHTTPSubmitter(Config{ProviderURL: server.URL, Timeout: time.Second})
+	manifest := &model.Manifest{ID: "m1", Carrier: "correios", Date: "2025-03-05", ShipmentIDs: []string{"s1", "s2"}}
+
+	// Act
+	carrierID, err := submitter.Submit(context.Background(), manifest, contractShipments)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "PLP-20250305-0042", carrierID)
+	requests := server.Requests()
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "m1", requests[0].Header.Get(IdempotencyKeyHeader))
+	testsupport.AssertGolden(t, "testdata/manifest_request.golden.json", requests[0].Body)
+}
+
+func TestHTTPSubmitter_ContractFaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		fault   testsupport.Fault
+		wantErr string
+	}{
+		{"unavailable", testsupport.Fault{Status: http.StatusServiceUnavailable}, "unexpected status 503"},
+		{"malformed", testsupport.Fault{Malformed: true}, "invalid response body"},
+		{"connection reset", testsupport.Fault{Reset: true}, "manifest: "},
+		{"slower than the timeout", testsupport.Fault{Latency: time.Second}, "Client.Timeout exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := testsupport.NewManifestServer(t)
+			server.Fail(tt.fault)
+			submitter := NewHTTPSubmitter(Config{ProviderURL: server.URL, Timeout: 50 * time.Millisecond})
+
+			// Act
+			_, err := submitter.Submit(context.Background(), &model.Manifest{ID: "m1", Carrier: "correios"}, contractShipments)
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
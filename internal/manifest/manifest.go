@@ -0,0 +1,162 @@
+// Package manifest submits the end-of-day dispatch manifests of booked shipments to the carriers.
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// IdempotencyKeyHeader carries the ID of the manifest, which the carrier deduplicates submissions by
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// DefaultCarrier is the carrier of the service levels without one in MANIFEST_CARRIERS
+const DefaultCarrier = "default"
+
+// Submitter submits manifests to their carrier, returning the carrier manifest ID
+type Submitter interface {
+	Submit(ctx context.Context, manifest *model.Manifest, shipments []model.Shipment) (string, error)
+}
+
+// Config configures the closing of the manifests
+type Config struct {
+	// ProviderURL is the carrier manifest API, called at {ProviderURL}/manifests; empty generates
+	// the manifests without submitting them
+	ProviderURL string
+	// Timeout bounds each submission
+	Timeout time.Duration
+	// Carriers maps service levels to the carrier shipping them
+	Carriers map[string]string
+}
+
+// Enabled reports whether manifests are submitted to the carriers
+func (c Config) Enabled() bool {
+	return c.ProviderURL != ""
+}
+
+// Carrier returns the carrier shipping a service level, DefaultCarrier when not configured
+func (c Config) Carrier(service string) string {
+	if carrier, ok := c.Carriers[service]; ok {
+		return carrier
+	}
+	return DefaultCarrier
+}
+
+// ConfigFromEnv reads MANIFEST_PROVIDER_URL (default none), MANIFEST_PROVIDER_TIMEOUT (default 30s)
+// and MANIFEST_CARRIERS, comma-separated service=carrier entries, e.g. "express=jadlog" (default
+// none, every service level ships with DefaultCarrier)
+func ConfigFromEnv() (Config, error) {
+	timeout, err := config.Duration("MANIFEST_PROVIDER_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if timeout <= 0 {
+		return Config{}, fmt.Errorf("MANIFEST_PROVIDER_TIMEOUT must be positive, got %s", timeout)
+	}
+
+	entries := config.List("MANIFEST_CARRIERS", nil)
+	carriers := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		service, carrier, ok := strings.Cut(entry, "=")
+		service = strings.ToLower(strings.TrimSpace(service))
+		carrier = strings.ToLower(strings.TrimSpace(carrier))
+		if !ok || service == "" || carrier == "" {
+			return Config{}, fmt.Errorf("MANIFEST_CARRIERS: entry must be formatted as service=carrier, got %q", entry)
+		}
+		carriers[service] = carrier
+	}
+	return Config{
+		ProviderURL: strings.TrimRight(config.String("MANIFEST_PROVIDER_URL", ""), "/"),
+		Timeout:     timeout,
+		Carriers:    carriers,
+	}, nil
+}
+
+// HTTPSubmitter submits manifests to a carrier manifest API
+type HTTPSubmitter struct {
+	cfg        Config
+	httpClient *http.Client
+}
+
+// NewHTTPSubmitter creates a submitter for the configured manifest API
+func NewHTTPSubmitter(cfg Config) *HTTPSubmitter {
+	return &HTTPSubmitter{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// manifestShipment is a shipment listed in a submitted manifest
+type manifestShipment struct {
+	ShipmentID string                `json:"shipment_id"`
+	Service    string                `json:"service"`
+	Package    model.ShipmentPackage `json:"package"`
+}
+
+// manifestRequest is the body sent to the manifest API
+type manifestRequest struct {
+	ManifestID string             `json:"manifest_id"`
+	Carrier    string             `json:"carrier"`
+	Date       string             `json:"date"`
+	Shipments  []manifestShipment `json:"shipments"`
+}
+
+// manifestResponse is the body returned by the manifest API
+type manifestResponse struct {
+	ManifestID string `json:"manifest_id"`
+}
+
+// Submit sends the manifest and its shipments, propagating the trace context of ctx and sending the
+// manifest ID in the Idempotency-Key header
+func (s *HTTPSubmitter) Submit(ctx context.Context, manifest *model.Manifest, shipments []model.Shipment) (string, error) {
+	request := manifestRequest{
+		ManifestID: manifest.ID,
+		Carrier:    manifest.Carrier,
+		Date:       manifest.Date,
+		Shipments:  make([]manifestShipment, 0, len(shipments)),
+	}
+	for _, shipment := range shipments {
+		request.Shipments = append(request.Shipments, manifestShipment{ShipmentID: shipment.ID, Service: shipment.Service, Package: shipment.Package})
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("manifest: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.ProviderURL+"/manifests", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("manifest: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, manifest.ID)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("manifest: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("manifest: unexpected status %d", resp.StatusCode)
+	}
+
+	var decoded manifestResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return "", fmt.Errorf("manifest: invalid response body: %w", err)
+	}
+	if decoded.ManifestID == "" {
+		return "", errors.New("manifest: missing manifest_id")
+	}
+	return decoded.ManifestID, nil
+}
@@ -0,0 +1,112 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{"defaults", map[string]string{}, Config{Timeout: 30 * time.Second, Carriers: map[string]string{}}, ""},
+		{
+			"custom values",
+			map[string]string{"MANIFEST_PROVIDER_URL": "https://manifests.example.com/", "MANIFEST_PROVIDER_TIMEOUT": "5s", "MANIFEST_CARRIERS": "Standard=Correios, express=jadlog"},
+			Config{ProviderURL: "https://manifests.example.com", Timeout: 5 * time.Second, Carriers: map[string]string{"standard": "correios", "express": "jadlog"}},
+			"",
+		},
+		{"zero timeout", map[string]string{"MANIFEST_PROVIDER_TIMEOUT": "0s"}, Config{}, "MANIFEST_PROVIDER_TIMEOUT"},
+		{"malformed carrier", map[string]string{"MANIFEST_CARRIERS": "express"}, Config{}, "MANIFEST_CARRIERS"},
+		{"empty carrier", map[string]string{"MANIFEST_CARRIERS": "express="}, Config{}, "MANIFEST_CARRIERS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for _, key := range []string{"MANIFEST_PROVIDER_URL", "MANIFEST_PROVIDER_TIMEOUT", "MANIFEST_CARRIERS"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestConfig_Carrier(t *testing.T) {
+	// Arrange
+	cfg := Config{Carriers: map[string]string{model.ServiceExpress: "jadlog"}}
+
+	// Act & Assert
+	assert.Equal(t, "jadlog", cfg.Carrier(model.ServiceExpress))
+	assert.Equal(t, DefaultCarrier, cfg.Carrier(model.ServiceStandard))
+}
+
+func TestSubmit(t *testing.T) {
+	manifest := &model.Manifest{ID: "m1", Carrier: "correios", Date: "2025-03-10", ShipmentIDs: []string{"s1"}}
+	shipments := []model.Shipment{{ID: "s1", Service: model.ServiceStandard, Package: model.ShipmentPackage{OriginZipcode: "01310100", DestinationZipcode: "20040020", Weight: 1.5}}}
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		wantErr string
+	}{
+		{"accepted", http.StatusCreated, `{"manifest_id":"cm-1"}`, "cm-1", ""},
+		{"rejected", http.StatusUnprocessableEntity, "", "", "unexpected status 422"},
+		{"missing manifest id", http.StatusOK, `{}`, "", "missing manifest_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/manifests", r.URL.Path)
+				assert.Equal(t, "m1", r.Header.Get(IdempotencyKeyHeader))
+				var body manifestRequest
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, manifestRequest{
+					ManifestID: "m1",
+					Carrier:    "correios",
+					Date:       "2025-03-10",
+					Shipments:  []manifestShipment{{ShipmentID: "s1", Service: model.ServiceStandard, Package: shipments[0].Package}},
+				}, body)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			submitter := NewHTTPSubmitter(Config{ProviderURL: server.URL, Timeout: time.Second})
+
+			// Act
+			id, err := submitter.Submit(context.Background(), manifest, shipments)
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, id)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package mapper
+
+import (
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/packing"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+)
+
+// PackingRequestFromV1 converts a v1 packing request into the calculation request of its route and
+// options, without a package, and the items to pack
+func PackingRequestFromV1(in *v1.PackingRequest) (*model.CalculateShippingRequest, []packing.Item) {
+	if in == nil {
+		return nil, nil
+	}
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      in.OriginZipcode,
+		DestinationZipcode: in.DestinationZipcode,
+		IsExpress:          in.IsExpress,
+		DestinationCountry: in.DestinationCountry,
+		Currency:           in.Currency,
+		PackageType:        in.PackageType,
+		AdditionalServices: copyStrings(in.AdditionalServices),
+		DeliveryType:       in.DeliveryType,
+		PricingStrategy:    in.PricingStrategy,
+	}
+	var items []packing.Item
+	if in.Items != nil {
+		items = make([]packing.Item, len(in.Items))
+		for i, item := range in.Items {
+			items[i] = packing.Item{
+				SKU:      item.SKU,
+				Length:   item.Length,
+				Width:    item.Width,
+				Height:   item.Height,
+				Weight:   item.Weight,
+				Quantity: item.Quantity,
+			}
+		}
+	}
+	return req, items
+}
+
+// PackingRequestToV1 converts the route and options of a calculation request and the items to pack
+// into the v1 transport model; the package of the request is not part of it
+func PackingRequestToV1(req *model.CalculateShippingRequest, items []packing.Item) *v1.PackingRequest {
+	if req == nil {
+		return nil
+	}
+	out := &v1.PackingRequest{
+		OriginZipcode:      req.OriginZipcode,
+		DestinationZipcode: req.DestinationZipcode,
+		IsExpress:          req.IsExpress,
+		DestinationCountry: req.DestinationCountry,
+		Currency:           req.Currency,
+		PackageType:        req.PackageType,
+		AdditionalServices: copyStrings(req.AdditionalServices),
+		DeliveryType:       req.DeliveryType,
+		PricingStrategy:    req.PricingStrategy,
+	}
+	if items != nil {
+		out.Items = make([]v1.PackingItem, len(items))
+		for i, item := range items {
+			out.Items[i] = v1.PackingItem{
+				SKU:      item.SKU,
+				Length:   item.Length,
+				Width:    item.Width,
+				Height:   item.Height,
+				Weight:   item.Weight,
+				Quantity: item.Quantity,
+			}
+		}
+	}
+	return out
+}
+
+// SuggestionFromV1 converts a v1 packing response into the domain packing suggestion
+func SuggestionFromV1(in *v1.PackingResponse) *packing.Suggestion {
+	if in == nil {
+		return nil
+	}
+	out := &packing.Suggestion{
+		Currency:     in.Currency,
+		ShippingCost: money.FromMinor(in.ShippingCost),
+		Boxes:        make([]packing.QuotedBox, len(in.Boxes)),
+	}
+	for i, box := range in.Boxes {
+		out.Boxes[i] = packing.QuotedBox{
+			PackedBox: packing.PackedBox{
+				Box: packing.Box{
+					ID:        box.Box.ID,
+					Name:      box.Box.Name,
+					Length:    box.Box.Length,
+					Width:     box.Box.Width,
+					Height:    box.Box.Height,
+					MaxWeight: box.Box.MaxWeight,
+				},
+				Weight:   box.Weight,
+				FillRate: box.FillRate,
+			},
+			Quote: ResponseFromV1(box.Quote),
+		}
+		if box.Items != nil {
+			out.Boxes[i].Items = make([]packing.ItemCount, len(box.Items))
+			for j, item := range box.Items {
+				out.Boxes[i].Items[j] = packing.ItemCount{SKU: item.SKU, Quantity: item.Quantity}
+			}
+		}
+	}
+	return out
+}
+
+// SuggestionToV1 converts a domain packing suggestion into the v1 transport model
+func SuggestionToV1(in *packing.Suggestion) *v1.PackingResponse {
+	if in == nil {
+		return nil
+	}
+	out := &v1.PackingResponse{
+		Currency:     in.Currency,
+		ShippingCost: in.ShippingCost.Minor(),
+		BoxCount:     len(in.Boxes),
+		Boxes:        make([]v1.PackedBox, len(in.Boxes)),
+	}
+	for i, box := range in.Boxes {
+		out.Boxes[i] = v1.PackedBox{
+			Box: v1.PackingBox{
+				ID:        box.Box.ID,
+				Name:      box.Box.Name,
+				Length:    box.Box.Length,
+				Width:     box.Box.Width,
+				Height:    box.Box.Height,
+				MaxWeight: box.Box.MaxWeight,
+			},
+			Weight:   box.Weight,
+			FillRate: box.FillRate,
+			Quote:    ResponseToV1(box.Quote),
+		}
+		if box.Items != nil {
+			out.Boxes[i].Items = make([]v1.PackedItem, len(box.Items))
+			for j, item := range box.Items {
+				out.Boxes[i].Items[j] = v1.PackedItem{SKU: item.SKU, Quantity: item.Quantity}
+			}
+		}
+	}
+	return out
+}
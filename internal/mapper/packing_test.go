@@ -0,0 +1,79 @@
+package mapper
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/packing"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackingRequestV1_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(21))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in v1.PackingRequest
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := PackingRequestToV1(PackingRequestFromV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestSuggestionV1_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(22))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in v1.PackingResponse
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+		// The box count is derived from the boxes
+		in.BoxCount = len(in.Boxes)
+
+		// Act
+		out := SuggestionToV1(SuggestionFromV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestSuggestionDomain_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(23))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in packing.Suggestion
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := SuggestionFromV1(SuggestionToV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestPackingMappers_NilInput(t *testing.T) {
+	// Act
+	req, items := PackingRequestFromV1(nil)
+
+	// Assert
+	assert.Nil(t, req)
+	assert.Nil(t, items)
+	assert.Nil(t, PackingRequestToV1(nil, nil))
+	assert.Nil(t, SuggestionFromV1(nil))
+	assert.Nil(t, SuggestionToV1(nil))
+}
+
+func TestSuggestionToV1_EmptyBoxes(t *testing.T) {
+	// Act
+	out := SuggestionToV1(&packing.Suggestion{})
+
+	// Assert
+	assert.NotNil(t, out.Boxes)
+	assert.Zero(t, out.BoxCount)
+}
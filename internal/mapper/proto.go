@@ -0,0 +1,213 @@
+package mapper
+
+import (
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	v1pb "github.com/rbonfanti/shipping-calculator/internal/transport/v1/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ResponseToProto converts a v1 calculation response into its protobuf message
+func ResponseToProto(in *v1.CalculateShippingResponse) *v1pb.ShippingQuote {
+	if in == nil {
+		return nil
+	}
+	out := &v1pb.ShippingQuote{
+		QuoteId:               in.QuoteID,
+		Currency:              in.Currency,
+		PricingVersion:        in.PricingVersion,
+		ShippingCost:          in.ShippingCost,
+		EstimatedDeliveryTime: in.EstimatedDeliveryTime,
+		DeliveryDate:          in.DeliveryDate,
+		AvailableServices:     copyStrings(in.AvailableServices),
+		SelectedService:       in.SelectedService,
+		Degraded:              in.Degraded,
+	}
+	if in.ExpiresAt != nil {
+		out.ExpiresAt = timestamppb.New(*in.ExpiresAt)
+	}
+	if in.ShippingOptions != nil {
+		out.ShippingOptions = make([]*v1pb.ShippingOption, len(in.ShippingOptions))
+		for i, opt := range in.ShippingOptions {
+			out.ShippingOptions[i] = &v1pb.ShippingOption{
+				Service:         opt.Service,
+				Cost:            opt.Cost,
+				Time:            opt.Time,
+				EstimatedDays:   int32(opt.EstimatedDays),
+				DeliveryDate:    opt.DeliveryDate,
+				WeekendDelivery: opt.WeekendDelivery,
+				Cheapest:        opt.Cheapest,
+				Fastest:         opt.Fastest,
+				Token:           opt.Token,
+			}
+		}
+	}
+	if in.Breakdown != nil {
+		out.Breakdown = &v1pb.CostBreakdown{
+			BaseCost:                in.Breakdown.BaseCost,
+			WeightSurcharge:         in.Breakdown.WeightSurcharge,
+			VolumeSurcharge:         in.Breakdown.VolumeSurcharge,
+			PackageTypeSurcharge:    in.Breakdown.PackageTypeSurcharge,
+			DeliveryTypeAdjustment:  in.Breakdown.DeliveryTypeAdjustment,
+			ExpressSurcharge:        in.Breakdown.ExpressSurcharge,
+			ReturnAdjustment:        in.Breakdown.ReturnAdjustment,
+			PickupSurcharge:         in.Breakdown.PickupSurcharge,
+			RestrictedArea:          in.Breakdown.RestrictedArea,
+			RestrictedAreaSurcharge: in.Breakdown.RestrictedAreaSurcharge,
+			RemoteAreas:             in.Breakdown.RemoteAreas,
+			RemoteAreaSurcharge:     in.Breakdown.RemoteAreaSurcharge,
+			WeekendSurcharge:        in.Breakdown.WeekendSurcharge,
+			FuelSurcharge:           in.Breakdown.FuelSurcharge,
+			PriceLimit:              in.Breakdown.PriceLimit,
+			PriceLimitAdjustment:    in.Breakdown.PriceLimitAdjustment,
+			UnroundedTotal:          in.Breakdown.UnroundedTotal,
+			RoundingAdjustment:      in.Breakdown.RoundingAdjustment,
+			Total:                   in.Breakdown.Total,
+			LandedCost:              in.Breakdown.LandedCost,
+		}
+		if in.Breakdown.AdditionalServices != nil {
+			out.Breakdown.AdditionalServices = make([]*v1pb.ServiceFee, len(in.Breakdown.AdditionalServices))
+			for i, fee := range in.Breakdown.AdditionalServices {
+				out.Breakdown.AdditionalServices[i] = &v1pb.ServiceFee{Service: fee.Service, Fee: fee.Fee}
+			}
+		}
+		if in.Breakdown.Duties != nil {
+			out.Breakdown.Duties = make([]*v1pb.DutyCharge, len(in.Breakdown.Duties))
+			for i, duty := range in.Breakdown.Duties {
+				out.Breakdown.Duties[i] = &v1pb.DutyCharge{Name: duty.Name, Rate: duty.Rate, Amount: duty.Amount}
+			}
+		}
+		if tax := in.Breakdown.Tax; tax != nil {
+			out.Breakdown.Tax = &v1pb.FreightTax{
+				Tax:              tax.Tax,
+				Rate:             tax.Rate,
+				OriginState:      tax.OriginState,
+				DestinationState: tax.DestinationState,
+				Municipality:     tax.Municipality,
+				Gross:            tax.Gross,
+				Amount:           tax.Amount,
+				Net:              tax.Net,
+			}
+		}
+	}
+	if in.Experiment != nil {
+		out.Experiment = &v1pb.ExperimentAssignment{Name: in.Experiment.Name, Arm: in.Experiment.Arm}
+	}
+	if in.Package != nil {
+		out.Package = &v1pb.PackageMeasures{
+			WeightKg: in.Package.WeightKg,
+			DimensionsCm: &v1pb.PackageDimensions{
+				Length: in.Package.DimensionsCm.Length,
+				Width:  in.Package.DimensionsCm.Width,
+				Height: in.Package.DimensionsCm.Height,
+			},
+		}
+	}
+	if in.FuelIndex != nil {
+		out.FuelIndex = &v1pb.FuelIndex{Rate: in.FuelIndex.Rate, EffectiveDate: in.FuelIndex.EffectiveDate}
+	}
+	return out
+}
+
+// ResponseFromProto converts a protobuf calculation response into the v1 transport model
+func ResponseFromProto(in *v1pb.ShippingQuote) *v1.CalculateShippingResponse {
+	if in == nil {
+		return nil
+	}
+	out := &v1.CalculateShippingResponse{
+		QuoteID:               in.GetQuoteId(),
+		Currency:              in.GetCurrency(),
+		PricingVersion:        in.GetPricingVersion(),
+		ShippingCost:          in.GetShippingCost(),
+		EstimatedDeliveryTime: in.GetEstimatedDeliveryTime(),
+		DeliveryDate:          in.GetDeliveryDate(),
+		AvailableServices:     copyStrings(in.GetAvailableServices()),
+		SelectedService:       in.GetSelectedService(),
+		Degraded:              in.GetDegraded(),
+	}
+	if in.ExpiresAt != nil {
+		expiresAt := in.ExpiresAt.AsTime()
+		out.ExpiresAt = &expiresAt
+	}
+	if in.ShippingOptions != nil {
+		out.ShippingOptions = make([]v1.ShippingOption, len(in.ShippingOptions))
+		for i, opt := range in.ShippingOptions {
+			out.ShippingOptions[i] = v1.ShippingOption{
+				Service:         opt.GetService(),
+				Cost:            opt.GetCost(),
+				Time:            opt.GetTime(),
+				EstimatedDays:   int(opt.GetEstimatedDays()),
+				DeliveryDate:    opt.GetDeliveryDate(),
+				WeekendDelivery: opt.GetWeekendDelivery(),
+				Cheapest:        opt.GetCheapest(),
+				Fastest:         opt.GetFastest(),
+				Token:           opt.GetToken(),
+			}
+		}
+	}
+	if breakdown := in.Breakdown; breakdown != nil {
+		out.Breakdown = &v1.CostBreakdown{
+			BaseCost:                breakdown.GetBaseCost(),
+			WeightSurcharge:         breakdown.GetWeightSurcharge(),
+			VolumeSurcharge:         breakdown.GetVolumeSurcharge(),
+			PackageTypeSurcharge:    breakdown.GetPackageTypeSurcharge(),
+			DeliveryTypeAdjustment:  breakdown.GetDeliveryTypeAdjustment(),
+			ExpressSurcharge:        breakdown.GetExpressSurcharge(),
+			ReturnAdjustment:        breakdown.GetReturnAdjustment(),
+			PickupSurcharge:         breakdown.GetPickupSurcharge(),
+			RestrictedArea:          breakdown.GetRestrictedArea(),
+			RestrictedAreaSurcharge: breakdown.GetRestrictedAreaSurcharge(),
+			RemoteAreas:             breakdown.GetRemoteAreas(),
+			RemoteAreaSurcharge:     breakdown.GetRemoteAreaSurcharge(),
+			WeekendSurcharge:        breakdown.GetWeekendSurcharge(),
+			FuelSurcharge:           breakdown.GetFuelSurcharge(),
+			PriceLimit:              breakdown.GetPriceLimit(),
+			PriceLimitAdjustment:    breakdown.GetPriceLimitAdjustment(),
+			UnroundedTotal:          breakdown.GetUnroundedTotal(),
+			RoundingAdjustment:      breakdown.GetRoundingAdjustment(),
+			Total:                   breakdown.GetTotal(),
+			LandedCost:              breakdown.GetLandedCost(),
+		}
+		if breakdown.AdditionalServices != nil {
+			out.Breakdown.AdditionalServices = make([]v1.ServiceFee, len(breakdown.AdditionalServices))
+			for i, fee := range breakdown.AdditionalServices {
+				out.Breakdown.AdditionalServices[i] = v1.ServiceFee{Service: fee.GetService(), Fee: fee.GetFee()}
+			}
+		}
+		if breakdown.Duties != nil {
+			out.Breakdown.Duties = make([]v1.DutyCharge, len(breakdown.Duties))
+			for i, duty := range breakdown.Duties {
+				out.Breakdown.Duties[i] = v1.DutyCharge{Name: duty.GetName(), Rate: duty.GetRate(), Amount: duty.GetAmount()}
+			}
+		}
+		if tax := breakdown.Tax; tax != nil {
+			out.Breakdown.Tax = &v1.FreightTax{
+				Tax:              tax.GetTax(),
+				Rate:             tax.GetRate(),
+				OriginState:      tax.GetOriginState(),
+				DestinationState: tax.GetDestinationState(),
+				Municipality:     tax.GetMunicipality(),
+				Gross:            tax.GetGross(),
+				Amount:           tax.GetAmount(),
+				Net:              tax.GetNet(),
+			}
+		}
+	}
+	if in.Experiment != nil {
+		out.Experiment = &v1.ExperimentAssignment{Name: in.Experiment.GetName(), Arm: in.Experiment.GetArm()}
+	}
+	if in.Package != nil {
+		dimensions := in.Package.GetDimensionsCm()
+		out.Package = &v1.PackageMeasures{
+			WeightKg: in.Package.GetWeightKg(),
+			DimensionsCm: v1.PackageDimensions{
+				Length: dimensions.GetLength(),
+				Width:  dimensions.GetWidth(),
+				Height: dimensions.GetHeight(),
+			},
+		}
+	}
+	if in.FuelIndex != nil {
+		out.FuelIndex = &v1.FuelIndex{Rate: in.FuelIndex.GetRate(), EffectiveDate: in.FuelIndex.GetEffectiveDate()}
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package mapper
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	v1pb "github.com/rbonfanti/shipping-calculator/internal/transport/v1/pb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestResponseProto_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in v1.CalculateShippingResponse
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+		expiresAt := time.Date(2025, 3, 7, 13, 0, 0, rnd.Intn(1_000_000_000), time.UTC)
+		in.ExpiresAt = &expiresAt
+
+		// Act
+		data, err := proto.Marshal(ResponseToProto(&in))
+		var decoded v1pb.ShippingQuote
+		unmarshalErr := proto.Unmarshal(data, &decoded)
+		out := ResponseFromProto(&decoded)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, unmarshalErr)
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestResponseProto_NilInput(t *testing.T) {
+	// Act & Assert
+	assert.Nil(t, ResponseToProto(nil))
+	assert.Nil(t, ResponseFromProto(nil))
+}
@@ -0,0 +1,105 @@
+package mapper
+
+import (
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+)
+
+// BookingFromV1 converts a v1 booking request into the domain model
+func BookingFromV1(in *v1.BookShipmentRequest) *model.BookShipmentRequest {
+	if in == nil {
+		return nil
+	}
+	return &model.BookShipmentRequest{QuoteID: in.QuoteID, Service: in.Service}
+}
+
+// BookingToV1 converts a domain booking request into the v1 transport model
+func BookingToV1(in *model.BookShipmentRequest) *v1.BookShipmentRequest {
+	if in == nil {
+		return nil
+	}
+	return &v1.BookShipmentRequest{QuoteID: in.QuoteID, Service: in.Service}
+}
+
+// ShipmentFromV1 converts a v1 shipment into the domain model
+func ShipmentFromV1(in *v1.Shipment) *model.Shipment {
+	if in == nil {
+		return nil
+	}
+	return &model.Shipment{
+		ID:                    in.ID,
+		QuoteID:               in.QuoteID,
+		Status:                in.Status,
+		Service:               in.Service,
+		Tenant:                in.Tenant,
+		Currency:              in.Currency,
+		Cost:                  money.FromMinor(in.Cost),
+		EstimatedDeliveryTime: in.EstimatedDeliveryTime,
+		PricingVersion:        in.PricingVersion,
+		PromisedDate:          in.PromisedDate,
+		Package: model.ShipmentPackage{
+			OriginZipcode:      in.Package.OriginZipcode,
+			DestinationZipcode: in.Package.DestinationZipcode,
+			DestinationCountry: in.Package.DestinationCountry,
+			Weight:             in.Package.Weight,
+			Dimensions: model.PackageDimensions{
+				Length: in.Package.Dimensions.Length,
+				Width:  in.Package.Dimensions.Width,
+				Height: in.Package.Dimensions.Height,
+			},
+			PackageType:        in.Package.PackageType,
+			DeliveryType:       in.Package.DeliveryType,
+			AdditionalServices: copyStrings(in.Package.AdditionalServices),
+			IsReturn:           in.Package.IsReturn,
+			FreightClass:       in.Package.FreightClass,
+			PricingStrategy:    in.Package.PricingStrategy,
+			WeightUnit:         in.Package.WeightUnit,
+			DimensionUnit:      in.Package.DimensionUnit,
+			PickupDate:         in.Package.PickupDate,
+			PickupWindow:       in.Package.PickupWindow,
+		},
+		BookedAt: in.BookedAt,
+	}
+}
+
+// ShipmentToV1 converts a domain shipment into the v1 transport model
+func ShipmentToV1(in *model.Shipment) *v1.Shipment {
+	if in == nil {
+		return nil
+	}
+	return &v1.Shipment{
+		ID:                    in.ID,
+		QuoteID:               in.QuoteID,
+		Status:                in.Status,
+		Service:               in.Service,
+		Tenant:                in.Tenant,
+		Currency:              in.Currency,
+		Cost:                  in.Cost.Minor(),
+		EstimatedDeliveryTime: in.EstimatedDeliveryTime,
+		PricingVersion:        in.PricingVersion,
+		PromisedDate:          in.PromisedDate,
+		Package: v1.ShipmentPackage{
+			OriginZipcode:      in.Package.OriginZipcode,
+			DestinationZipcode: in.Package.DestinationZipcode,
+			DestinationCountry: in.Package.DestinationCountry,
+			Weight:             in.Package.Weight,
+			Dimensions: v1.PackageDimensions{
+				Length: in.Package.Dimensions.Length,
+				Width:  in.Package.Dimensions.Width,
+				Height: in.Package.Dimensions.Height,
+			},
+			PackageType:        in.Package.PackageType,
+			DeliveryType:       in.Package.DeliveryType,
+			AdditionalServices: copyStrings(in.Package.AdditionalServices),
+			IsReturn:           in.Package.IsReturn,
+			FreightClass:       in.Package.FreightClass,
+			PricingStrategy:    in.Package.PricingStrategy,
+			WeightUnit:         in.Package.WeightUnit,
+			DimensionUnit:      in.Package.DimensionUnit,
+			PickupDate:         in.Package.PickupDate,
+			PickupWindow:       in.Package.PickupWindow,
+		},
+		BookedAt: in.BookedAt,
+	}
+}
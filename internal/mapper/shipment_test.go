@@ -0,0 +1,79 @@
+package mapper
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookingV1_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(24))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in v1.BookShipmentRequest
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := BookingToV1(BookingFromV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestBookingDomain_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(25))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in model.BookShipmentRequest
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := BookingFromV1(BookingToV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestShipmentV1_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(26))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in v1.Shipment
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := ShipmentToV1(ShipmentFromV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestShipmentDomain_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(27))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in model.Shipment
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := ShipmentFromV1(ShipmentToV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestShipmentMappers_NilInput(t *testing.T) {
+	// Act & Assert
+	assert.Nil(t, BookingFromV1(nil))
+	assert.Nil(t, BookingToV1(nil))
+	assert.Nil(t, ShipmentFromV1(nil))
+	assert.Nil(t, ShipmentToV1(nil))
+}
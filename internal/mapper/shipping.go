@@ -0,0 +1,431 @@
+// Package mapper converts between the versioned transport models and the internal domain model.
+// Every field of a transport model must be mapped in both directions; the round-trip tests
+// in this package fail when a field is added to either side without updating the mapper.
+package mapper
+
+import (
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+)
+
+// RequestFromV1 converts a v1 calculation request into the domain model
+func RequestFromV1(in *v1.CalculateShippingRequest) *model.CalculateShippingRequest {
+	if in == nil {
+		return nil
+	}
+	return &model.CalculateShippingRequest{
+		OriginZipcode:      in.OriginZipcode,
+		DestinationZipcode: in.DestinationZipcode,
+		Weight:             in.Weight,
+		Dimensions: model.PackageDimensions{
+			Length: in.Dimensions.Length,
+			Width:  in.Dimensions.Width,
+			Height: in.Dimensions.Height,
+		},
+		IsExpress:            in.IsExpress,
+		DestinationCountry:   in.DestinationCountry,
+		Currency:             in.Currency,
+		PackageType:          in.PackageType,
+		AdditionalServices:   copyStrings(in.AdditionalServices),
+		DeliveryType:         in.DeliveryType,
+		PricingStrategy:      in.PricingStrategy,
+		IsReturn:             in.IsReturn,
+		PickupDate:           in.PickupDate,
+		PickupWindow:         in.PickupWindow,
+		HSCode:               in.HSCode,
+		DeclaredValue:        money.FromMinor(in.DeclaredValue),
+		FreightClass:         in.FreightClass,
+		WeightUnit:           in.WeightUnit,
+		DimensionUnit:        in.DimensionUnit,
+		Optimize:             in.Optimize,
+		AllowWeekendDelivery: in.AllowWeekendDelivery,
+		MissingFields:        copyStrings(in.MissingFields),
+	}
+}
+
+// RequestToV1 converts a domain calculation request into the v1 transport model
+func RequestToV1(in *model.CalculateShippingRequest) *v1.CalculateShippingRequest {
+	if in == nil {
+		return nil
+	}
+	return &v1.CalculateShippingRequest{
+		OriginZipcode:      in.OriginZipcode,
+		DestinationZipcode: in.DestinationZipcode,
+		Weight:             in.Weight,
+		Dimensions: v1.PackageDimensions{
+			Length: in.Dimensions.Length,
+			Width:  in.Dimensions.Width,
+			Height: in.Dimensions.Height,
+		},
+		IsExpress:            in.IsExpress,
+		DestinationCountry:   in.DestinationCountry,
+		Currency:             in.Currency,
+		PackageType:          in.PackageType,
+		AdditionalServices:   copyStrings(in.AdditionalServices),
+		DeliveryType:         in.DeliveryType,
+		PricingStrategy:      in.PricingStrategy,
+		IsReturn:             in.IsReturn,
+		PickupDate:           in.PickupDate,
+		PickupWindow:         in.PickupWindow,
+		HSCode:               in.HSCode,
+		DeclaredValue:        in.DeclaredValue.Minor(),
+		FreightClass:         in.FreightClass,
+		WeightUnit:           in.WeightUnit,
+		DimensionUnit:        in.DimensionUnit,
+		Optimize:             in.Optimize,
+		AllowWeekendDelivery: in.AllowWeekendDelivery,
+		MissingFields:        copyStrings(in.MissingFields),
+	}
+}
+
+// ResponseFromV1 converts a v1 calculation response into the domain model
+func ResponseFromV1(in *v1.CalculateShippingResponse) *model.CalculateShippingResponse {
+	if in == nil {
+		return nil
+	}
+	out := &model.CalculateShippingResponse{
+		QuoteID:               in.QuoteID,
+		Currency:              in.Currency,
+		PricingVersion:        in.PricingVersion,
+		ExpiresAt:             copyTime(in.ExpiresAt),
+		ShippingCost:          money.FromMinor(in.ShippingCost),
+		EstimatedDeliveryTime: in.EstimatedDeliveryTime,
+		DeliveryDate:          in.DeliveryDate,
+		AvailableServices:     copyStrings(in.AvailableServices),
+		SelectedService:       in.SelectedService,
+		Degraded:              in.Degraded,
+	}
+	if in.ShippingOptions != nil {
+		out.ShippingOptions = make([]model.ShippingOption, len(in.ShippingOptions))
+		for i, opt := range in.ShippingOptions {
+			out.ShippingOptions[i] = model.ShippingOption{
+				Service:         opt.Service,
+				Cost:            money.FromMinor(opt.Cost),
+				Time:            opt.Time,
+				EstimatedDays:   opt.EstimatedDays,
+				DeliveryDate:    opt.DeliveryDate,
+				WeekendDelivery: opt.WeekendDelivery,
+				Cheapest:        opt.Cheapest,
+				Fastest:         opt.Fastest,
+				Token:           opt.Token,
+			}
+		}
+	}
+	if in.Breakdown != nil {
+		out.Breakdown = &model.CostBreakdown{
+			BaseCost:                money.FromMinor(in.Breakdown.BaseCost),
+			WeightSurcharge:         money.FromMinor(in.Breakdown.WeightSurcharge),
+			VolumeSurcharge:         money.FromMinor(in.Breakdown.VolumeSurcharge),
+			PackageTypeSurcharge:    money.FromMinor(in.Breakdown.PackageTypeSurcharge),
+			DeliveryTypeAdjustment:  money.FromMinor(in.Breakdown.DeliveryTypeAdjustment),
+			ExpressSurcharge:        money.FromMinor(in.Breakdown.ExpressSurcharge),
+			ReturnAdjustment:        money.FromMinor(in.Breakdown.ReturnAdjustment),
+			PickupSurcharge:         money.FromMinor(in.Breakdown.PickupSurcharge),
+			RestrictedArea:          in.Breakdown.RestrictedArea,
+			RestrictedAreaSurcharge: money.FromMinor(in.Breakdown.RestrictedAreaSurcharge),
+			RemoteAreas:             in.Breakdown.RemoteAreas,
+			RemoteAreaSurcharge:     money.FromMinor(in.Breakdown.RemoteAreaSurcharge),
+			WeekendSurcharge:        money.FromMinor(in.Breakdown.WeekendSurcharge),
+			FuelSurcharge:           money.FromMinor(in.Breakdown.FuelSurcharge),
+			PriceLimit:              in.Breakdown.PriceLimit,
+			PriceLimitAdjustment:    money.FromMinor(in.Breakdown.PriceLimitAdjustment),
+			UnroundedTotal:          money.FromMinor(in.Breakdown.UnroundedTotal),
+			RoundingAdjustment:      money.FromMinor(in.Breakdown.RoundingAdjustment),
+			Total:                   money.FromMinor(in.Breakdown.Total),
+			LandedCost:              money.FromMinor(in.Breakdown.LandedCost),
+		}
+		if in.Breakdown.AdditionalServices != nil {
+			out.Breakdown.AdditionalServices = make([]model.ServiceFee, len(in.Breakdown.AdditionalServices))
+			for i, fee := range in.Breakdown.AdditionalServices {
+				out.Breakdown.AdditionalServices[i] = model.ServiceFee{Service: fee.Service, Fee: money.FromMinor(fee.Fee)}
+			}
+		}
+		if in.Breakdown.Duties != nil {
+			out.Breakdown.Duties = make([]model.DutyCharge, len(in.Breakdown.Duties))
+			for i, duty := range in.Breakdown.Duties {
+				out.Breakdown.Duties[i] = model.DutyCharge{Name: duty.Name, Rate: duty.Rate, Amount: money.FromMinor(duty.Amount)}
+			}
+		}
+		if tax := in.Breakdown.Tax; tax != nil {
+			out.Breakdown.Tax = &model.FreightTax{
+				Tax:              tax.Tax,
+				Rate:             tax.Rate,
+				OriginState:      tax.OriginState,
+				DestinationState: tax.DestinationState,
+				Municipality:     tax.Municipality,
+				Gross:            money.FromMinor(tax.Gross),
+				Amount:           money.FromMinor(tax.Amount),
+				Net:              money.FromMinor(tax.Net),
+			}
+		}
+	}
+	if in.Experiment != nil {
+		out.Experiment = &model.ExperimentAssignment{Name: in.Experiment.Name, Arm: in.Experiment.Arm}
+	}
+	if in.Package != nil {
+		out.Package = &model.PackageMeasures{WeightKg: in.Package.WeightKg, DimensionsCm: model.PackageDimensions(in.Package.DimensionsCm)}
+	}
+	if in.FuelIndex != nil {
+		out.FuelIndex = &model.FuelIndex{Rate: in.FuelIndex.Rate, EffectiveDate: in.FuelIndex.EffectiveDate}
+	}
+	return out
+}
+
+// ResponseToV1 converts a domain calculation response into the v1 transport model
+func ResponseToV1(in *model.CalculateShippingResponse) *v1.CalculateShippingResponse {
+	if in == nil {
+		return nil
+	}
+	out := &v1.CalculateShippingResponse{
+		QuoteID:               in.QuoteID,
+		Currency:              in.Currency,
+		PricingVersion:        in.PricingVersion,
+		ExpiresAt:             copyTime(in.ExpiresAt),
+		ShippingCost:          in.ShippingCost.Minor(),
+		EstimatedDeliveryTime: in.EstimatedDeliveryTime,
+		DeliveryDate:          in.DeliveryDate,
+		AvailableServices:     copyStrings(in.AvailableServices),
+		SelectedService:       in.SelectedService,
+		Degraded:              in.Degraded,
+	}
+	if in.ShippingOptions != nil {
+		out.ShippingOptions = make([]v1.ShippingOption, len(in.ShippingOptions))
+		for i, opt := range in.ShippingOptions {
+			out.ShippingOptions[i] = v1.ShippingOption{
+				Service:         opt.Service,
+				Cost:            opt.Cost.Minor(),
+				Time:            opt.Time,
+				EstimatedDays:   opt.EstimatedDays,
+				DeliveryDate:    opt.DeliveryDate,
+				WeekendDelivery: opt.WeekendDelivery,
+				Cheapest:        opt.Cheapest,
+				Fastest:         opt.Fastest,
+				Token:           opt.Token,
+			}
+		}
+	}
+	if in.Breakdown != nil {
+		out.Breakdown = &v1.CostBreakdown{
+			BaseCost:                in.Breakdown.BaseCost.Minor(),
+			WeightSurcharge:         in.Breakdown.WeightSurcharge.Minor(),
+			VolumeSurcharge:         in.Breakdown.VolumeSurcharge.Minor(),
+			PackageTypeSurcharge:    in.Breakdown.PackageTypeSurcharge.Minor(),
+			DeliveryTypeAdjustment:  in.Breakdown.DeliveryTypeAdjustment.Minor(),
+			ExpressSurcharge:        in.Breakdown.ExpressSurcharge.Minor(),
+			ReturnAdjustment:        in.Breakdown.ReturnAdjustment.Minor(),
+			PickupSurcharge:         in.Breakdown.PickupSurcharge.Minor(),
+			RestrictedArea:          in.Breakdown.RestrictedArea,
+			RestrictedAreaSurcharge: in.Breakdown.RestrictedAreaSurcharge.Minor(),
+			RemoteAreas:             in.Breakdown.RemoteAreas,
+			RemoteAreaSurcharge:     in.Breakdown.RemoteAreaSurcharge.Minor(),
+			WeekendSurcharge:        in.Breakdown.WeekendSurcharge.Minor(),
+			FuelSurcharge:           in.Breakdown.FuelSurcharge.Minor(),
+			PriceLimit:              in.Breakdown.PriceLimit,
+			PriceLimitAdjustment:    in.Breakdown.PriceLimitAdjustment.Minor(),
+			UnroundedTotal:          in.Breakdown.UnroundedTotal.Minor(),
+			RoundingAdjustment:      in.Breakdown.RoundingAdjustment.Minor(),
+			Total:                   in.Breakdown.Total.Minor(),
+			LandedCost:              in.Breakdown.LandedCost.Minor(),
+		}
+		if in.Breakdown.AdditionalServices != nil {
+			out.Breakdown.AdditionalServices = make([]v1.ServiceFee, len(in.Breakdown.AdditionalServices))
+			for i, fee := range in.Breakdown.AdditionalServices {
+				out.Breakdown.AdditionalServices[i] = v1.ServiceFee{Service: fee.Service, Fee: fee.Fee.Minor()}
+			}
+		}
+		if in.Breakdown.Duties != nil {
+			out.Breakdown.Duties = make([]v1.DutyCharge, len(in.Breakdown.Duties))
+			for i, duty := range in.Breakdown.Duties {
+				out.Breakdown.Duties[i] = v1.DutyCharge{Name: duty.Name, Rate: duty.Rate, Amount: duty.Amount.Minor()}
+			}
+		}
+		if tax := in.Breakdown.Tax; tax != nil {
+			out.Breakdown.Tax = &v1.FreightTax{
+				Tax:              tax.Tax,
+				Rate:             tax.Rate,
+				OriginState:      tax.OriginState,
+				DestinationState: tax.DestinationState,
+				Municipality:     tax.Municipality,
+				Gross:            tax.Gross.Minor(),
+				Amount:           tax.Amount.Minor(),
+				Net:              tax.Net.Minor(),
+			}
+		}
+	}
+	if in.Experiment != nil {
+		out.Experiment = &v1.ExperimentAssignment{Name: in.Experiment.Name, Arm: in.Experiment.Arm}
+	}
+	if in.Package != nil {
+		out.Package = &v1.PackageMeasures{WeightKg: in.Package.WeightKg, DimensionsCm: v1.PackageDimensions(in.Package.DimensionsCm)}
+	}
+	if in.FuelIndex != nil {
+		out.FuelIndex = &v1.FuelIndex{Rate: in.FuelIndex.Rate, EffectiveDate: in.FuelIndex.EffectiveDate}
+	}
+	return out
+}
+
+// RevalidationFromV1 converts a v1 quote revalidation into the domain model
+func RevalidationFromV1(in *v1.QuoteRevalidation) *model.QuoteRevalidation {
+	if in == nil {
+		return nil
+	}
+	return &model.QuoteRevalidation{
+		QuoteID:      in.QuoteID,
+		Expired:      in.Expired,
+		PriceChanged: in.PriceChanged,
+		PreviousCost: money.FromMinor(in.PreviousCost),
+		Quote:        ResponseFromV1(in.Quote),
+	}
+}
+
+// RevalidationToV1 converts a domain quote revalidation into the v1 transport model
+func RevalidationToV1(in *model.QuoteRevalidation) *v1.QuoteRevalidation {
+	if in == nil {
+		return nil
+	}
+	return &v1.QuoteRevalidation{
+		QuoteID:      in.QuoteID,
+		Expired:      in.Expired,
+		PriceChanged: in.PriceChanged,
+		PreviousCost: in.PreviousCost.Minor(),
+		Quote:        ResponseToV1(in.Quote),
+	}
+}
+
+// PriceSubscriptionFromV1 converts a v1 price subscription into the domain model
+func PriceSubscriptionFromV1(in *v1.PriceSubscription) *model.PriceSubscription {
+	if in == nil {
+		return nil
+	}
+	return &model.PriceSubscription{
+		ID:        in.SubscriptionID,
+		Version:   in.Version,
+		ExpiresAt: in.ExpiresAt,
+		Quote:     ResponseFromV1(in.Quote),
+	}
+}
+
+// PriceSubscriptionToV1 converts a domain price subscription into the v1 transport model
+func PriceSubscriptionToV1(in *model.PriceSubscription) *v1.PriceSubscription {
+	if in == nil {
+		return nil
+	}
+	return &v1.PriceSubscription{
+		SubscriptionID: in.ID,
+		Version:        in.Version,
+		ExpiresAt:      in.ExpiresAt,
+		Quote:          ResponseToV1(in.Quote),
+	}
+}
+
+// PreviewFromV1 converts a v1 quote preview into the domain model
+func PreviewFromV1(in *v1.QuotePreview) *model.QuotePreview {
+	if in == nil {
+		return nil
+	}
+	out := &model.QuotePreview{Quote: ResponseFromV1(in.Quote), Trace: make([]model.DecisionStep, len(in.Trace))}
+	for i, step := range in.Trace {
+		out.Trace[i] = model.DecisionStep{Step: step.Step, Detail: step.Detail}
+	}
+	return out
+}
+
+// PreviewToV1 converts a domain quote preview into the v1 transport model
+func PreviewToV1(in *model.QuotePreview) *v1.QuotePreview {
+	if in == nil {
+		return nil
+	}
+	out := &v1.QuotePreview{Quote: ResponseToV1(in.Quote), Trace: make([]v1.DecisionStep, len(in.Trace))}
+	for i, step := range in.Trace {
+		out.Trace[i] = v1.DecisionStep{Step: step.Step, Detail: step.Detail}
+	}
+	return out
+}
+
+// ExplanationFromV1 converts a v1 quote explanation into the domain model
+func ExplanationFromV1(in *v1.QuoteExplanation) *model.QuoteExplanation {
+	if in == nil {
+		return nil
+	}
+	out := &model.QuoteExplanation{
+		Quote:     ResponseFromV1(in.Quote),
+		Inputs:    model.ExplainedInputs(in.Inputs),
+		Route:     model.ExplainedRoute(in.Route),
+		Service:   in.Service,
+		Strategy:  in.Strategy,
+		Charges:   make([]model.ExplainedCharge, len(in.Charges)),
+		Decisions: make([]model.DecisionStep, len(in.Decisions)),
+	}
+	for i, charge := range in.Charges {
+		out.Charges[i] = model.ExplainedCharge{
+			Name:       charge.Name,
+			Amount:     money.FromMinor(charge.Amount),
+			Formula:    charge.Formula,
+			Parameters: copyParameters(charge.Parameters),
+		}
+	}
+	for i, step := range in.Decisions {
+		out.Decisions[i] = model.DecisionStep(step)
+	}
+	return out
+}
+
+// ExplanationToV1 converts a domain quote explanation into the v1 transport model
+func ExplanationToV1(in *model.QuoteExplanation) *v1.QuoteExplanation {
+	if in == nil {
+		return nil
+	}
+	out := &v1.QuoteExplanation{
+		Quote:     ResponseToV1(in.Quote),
+		Inputs:    v1.ExplainedInputs(in.Inputs),
+		Route:     v1.ExplainedRoute(in.Route),
+		Service:   in.Service,
+		Strategy:  in.Strategy,
+		Charges:   make([]v1.ExplainedCharge, len(in.Charges)),
+		Decisions: make([]v1.DecisionStep, len(in.Decisions)),
+	}
+	for i, charge := range in.Charges {
+		out.Charges[i] = v1.ExplainedCharge{
+			Name:       charge.Name,
+			Amount:     charge.Amount.Minor(),
+			Formula:    charge.Formula,
+			Parameters: copyParameters(charge.Parameters),
+		}
+	}
+	for i, step := range in.Decisions {
+		out.Decisions[i] = v1.DecisionStep(step)
+	}
+	return out
+}
+
+// copyParameters returns a copy of the map, preserving nil
+func copyParameters(in map[string]float64) map[string]float64 {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]float64, len(in))
+	for key, value := range in {
+		out[key] = value
+	}
+	return out
+}
+
+// copyTime returns a copy of the time, preserving nil
+func copyTime(in *time.Time) *time.Time {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
+
+// copyStrings returns a copy of the slice, preserving nil
+func copyStrings(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	return append([]string{}, in...)
+}
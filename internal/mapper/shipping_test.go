@@ -0,0 +1,320 @@
+package mapper
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+// fillNonZero sets every exported field reachable from v to a random non-zero value,
+// so a round trip through the mappers exposes any field that is not copied
+func fillNonZero(t *testing.T, v reflect.Value, rnd *rand.Rand) {
+	t.Helper()
+	switch v.Kind() {
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillNonZero(t, v.Elem(), rnd)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fillNonZero(t, v.Field(i), rnd)
+			}
+		}
+	case reflect.Slice:
+		n := 1 + rnd.Intn(3)
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := 0; i < n; i++ {
+			fillNonZero(t, v.Index(i), rnd)
+		}
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		fillNonZero(t, key, rnd)
+		value := reflect.New(v.Type().Elem()).Elem()
+		fillNonZero(t, value, rnd)
+		v.SetMapIndex(key, value)
+	case reflect.String:
+		v.SetString(fmt.Sprintf("s%d", 1+rnd.Intn(1_000_000)))
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(1 + rnd.Intn(100)))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(1 + rnd.Intn(100)))
+	case reflect.Float32, reflect.Float64:
+		// four decimal places, the precision of money.Amount, so costs survive the round trip
+		v.SetFloat(math.Round((1+rnd.Float64()*1000)*money.Scale) / money.Scale)
+	default:
+		t.Fatalf("fillNonZero: unsupported kind %s", v.Kind())
+	}
+}
+
+func TestRequestV1_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in v1.CalculateShippingRequest
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := RequestToV1(RequestFromV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestRequestDomain_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in model.CalculateShippingRequest
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := RequestFromV1(RequestToV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestResponseV1_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in v1.CalculateShippingResponse
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := ResponseToV1(ResponseFromV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestResponseDomain_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(4))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in model.CalculateShippingResponse
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := ResponseFromV1(ResponseToV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestRevalidationV1_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in v1.QuoteRevalidation
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := RevalidationToV1(RevalidationFromV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestRevalidationDomain_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(6))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in model.QuoteRevalidation
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := RevalidationFromV1(RevalidationToV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestPreviewV1_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in v1.QuotePreview
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := PreviewToV1(PreviewFromV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestPreviewDomain_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(8))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in model.QuotePreview
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := PreviewFromV1(PreviewToV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestExplanationV1_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(9))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in v1.QuoteExplanation
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := ExplanationToV1(ExplanationFromV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestExplanationDomain_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(10))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in model.QuoteExplanation
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := ExplanationFromV1(ExplanationToV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestPriceSubscriptionV1_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(11))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in v1.PriceSubscription
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := PriceSubscriptionToV1(PriceSubscriptionFromV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestPriceSubscriptionDomain_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(12))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in model.PriceSubscription
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+
+		// Act
+		out := PriceSubscriptionFromV1(PriceSubscriptionToV1(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestMappers_NilInput(t *testing.T) {
+	// Act & Assert
+	assert.Nil(t, RequestFromV1(nil))
+	assert.Nil(t, RequestToV1(nil))
+	assert.Nil(t, ResponseFromV1(nil))
+	assert.Nil(t, ResponseToV1(nil))
+	assert.Nil(t, RevalidationFromV1(nil))
+	assert.Nil(t, RevalidationToV1(nil))
+	assert.Nil(t, PreviewFromV1(nil))
+	assert.Nil(t, PreviewToV1(nil))
+	assert.Nil(t, ExplanationFromV1(nil))
+	assert.Nil(t, ExplanationToV1(nil))
+	assert.Nil(t, PriceSubscriptionFromV1(nil))
+	assert.Nil(t, PriceSubscriptionToV1(nil))
+}
+
+func TestResponseToV1_PreservesNilSlices(t *testing.T) {
+	// Arrange
+	in := &model.CalculateShippingResponse{ShippingCost: money.FromMinor(1000)}
+
+	// Act
+	out := ResponseToV1(in)
+
+	// Assert
+	assert.Nil(t, out.AvailableServices)
+	assert.Nil(t, out.ShippingOptions)
+}
+
+func TestResponseToV1_DoesNotAliasSlices(t *testing.T) {
+	// Arrange
+	in := &model.CalculateShippingResponse{AvailableServices: []string{"standard"}}
+
+	// Act
+	out := ResponseToV1(in)
+	out.AvailableServices[0] = "changed"
+
+	// Assert
+	assert.Equal(t, "standard", in.AvailableServices[0])
+}
+
+func FuzzRequestV1_RoundTrip(f *testing.F) {
+	f.Add("01310-100", "04547-130", 2.5, 30.0, 20.0, 15.0, false)
+	f.Add("1414", "1428", 0.5, 15.0, 8.0, 2.0, true)
+	f.Add("", "ção", -1.0, 0.0, -3.5, 1e308, false)
+
+	f.Fuzz(func(t *testing.T, origin, destination string, weight, length, width, height float64, express bool) {
+		in := &v1.CalculateShippingRequest{
+			OriginZipcode:      origin,
+			DestinationZipcode: destination,
+			Weight:             weight,
+			Dimensions:         v1.PackageDimensions{Length: length, Width: width, Height: height},
+			IsExpress:          express,
+		}
+
+		out := RequestToV1(RequestFromV1(in))
+
+		// reflect.DeepEqual treats NaN as different from itself, so compare via formatting
+		if fmt.Sprintf("%#v", in) != fmt.Sprintf("%#v", out) {
+			t.Fatalf("round trip mismatch: %#v != %#v", in, out)
+		}
+	})
+}
+
+func FuzzResponseV1_RoundTrip(f *testing.F) {
+	f.Add(1250.0, "2 dias", "standard", 1250.0, "2 dias")
+	f.Add(0.0, "", "", -1.0, "ç")
+
+	f.Fuzz(func(t *testing.T, cost float64, eta, service string, optionCost float64, optionTime string) {
+		in := &v1.CalculateShippingResponse{
+			ShippingCost:          cost,
+			EstimatedDeliveryTime: eta,
+			AvailableServices:     []string{service},
+			ShippingOptions:       []v1.ShippingOption{{Service: service, Cost: optionCost, Time: optionTime}},
+		}
+
+		out := ResponseToV1(ResponseFromV1(in))
+
+		if fmt.Sprintf("%#v", in) != fmt.Sprintf("%#v", out) {
+			t.Fatalf("round trip mismatch: %#v != %#v", in, out)
+		}
+	})
+}
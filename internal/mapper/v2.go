@@ -0,0 +1,198 @@
+package mapper
+
+import (
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	v2 "github.com/rbonfanti/shipping-calculator/internal/transport/v2"
+)
+
+// ResponseToV2 converts a domain calculation response into the v2 transport model; every amount
+// is tagged with the currency of the quote
+func ResponseToV2(in *model.CalculateShippingResponse) *v2.CalculateShippingResponse {
+	if in == nil {
+		return nil
+	}
+	price := func(amount money.Amount) v2.Money {
+		return v2.Money{Amount: amount, Currency: in.Currency}
+	}
+	out := &v2.CalculateShippingResponse{
+		QuoteID:               in.QuoteID,
+		PricingVersion:        in.PricingVersion,
+		ExpiresAt:             copyTime(in.ExpiresAt),
+		Total:                 price(in.ShippingCost),
+		SelectedService:       in.SelectedService,
+		EstimatedDeliveryTime: in.EstimatedDeliveryTime,
+		DeliveryDate:          in.DeliveryDate,
+		Degraded:              in.Degraded,
+	}
+	if in.ShippingOptions != nil {
+		out.ServiceLevels = make([]v2.ServiceLevel, len(in.ShippingOptions))
+		for i, opt := range in.ShippingOptions {
+			out.ServiceLevels[i] = v2.ServiceLevel{
+				Service:         opt.Service,
+				Price:           price(opt.Cost),
+				DeliveryTime:    opt.Time,
+				EstimatedDays:   opt.EstimatedDays,
+				DeliveryDate:    opt.DeliveryDate,
+				WeekendDelivery: opt.WeekendDelivery,
+				Cheapest:        opt.Cheapest,
+				Fastest:         opt.Fastest,
+				Token:           opt.Token,
+			}
+		}
+	}
+	if in.Breakdown != nil {
+		out.Breakdown = &v2.CostBreakdown{
+			BaseCost:                price(in.Breakdown.BaseCost),
+			WeightSurcharge:         price(in.Breakdown.WeightSurcharge),
+			VolumeSurcharge:         price(in.Breakdown.VolumeSurcharge),
+			PackageTypeSurcharge:    price(in.Breakdown.PackageTypeSurcharge),
+			DeliveryTypeAdjustment:  price(in.Breakdown.DeliveryTypeAdjustment),
+			ExpressSurcharge:        price(in.Breakdown.ExpressSurcharge),
+			ReturnAdjustment:        price(in.Breakdown.ReturnAdjustment),
+			PickupSurcharge:         price(in.Breakdown.PickupSurcharge),
+			RestrictedArea:          in.Breakdown.RestrictedArea,
+			RestrictedAreaSurcharge: price(in.Breakdown.RestrictedAreaSurcharge),
+			RemoteAreas:             in.Breakdown.RemoteAreas,
+			RemoteAreaSurcharge:     price(in.Breakdown.RemoteAreaSurcharge),
+			WeekendSurcharge:        price(in.Breakdown.WeekendSurcharge),
+			FuelSurcharge:           price(in.Breakdown.FuelSurcharge),
+			PriceLimit:              in.Breakdown.PriceLimit,
+			PriceLimitAdjustment:    price(in.Breakdown.PriceLimitAdjustment),
+			UnroundedTotal:          price(in.Breakdown.UnroundedTotal),
+			RoundingAdjustment:      price(in.Breakdown.RoundingAdjustment),
+			Total:                   price(in.Breakdown.Total),
+			LandedCost:              price(in.Breakdown.LandedCost),
+		}
+		if in.Breakdown.AdditionalServices != nil {
+			out.Breakdown.AdditionalServices = make([]v2.ServiceFee, len(in.Breakdown.AdditionalServices))
+			for i, fee := range in.Breakdown.AdditionalServices {
+				out.Breakdown.AdditionalServices[i] = v2.ServiceFee{Service: fee.Service, Fee: price(fee.Fee)}
+			}
+		}
+		if in.Breakdown.Duties != nil {
+			out.Breakdown.Duties = make([]v2.DutyCharge, len(in.Breakdown.Duties))
+			for i, duty := range in.Breakdown.Duties {
+				out.Breakdown.Duties[i] = v2.DutyCharge{Name: duty.Name, Rate: duty.Rate, Amount: price(duty.Amount)}
+			}
+		}
+		if tax := in.Breakdown.Tax; tax != nil {
+			out.Breakdown.Tax = &v2.FreightTax{
+				Tax:              tax.Tax,
+				Rate:             tax.Rate,
+				OriginState:      tax.OriginState,
+				DestinationState: tax.DestinationState,
+				Municipality:     tax.Municipality,
+				Gross:            price(tax.Gross),
+				Amount:           price(tax.Amount),
+				Net:              price(tax.Net),
+			}
+		}
+	}
+	if in.Experiment != nil {
+		out.Experiment = &v2.Experiment{Name: in.Experiment.Name, Arm: in.Experiment.Arm}
+	}
+	if in.Package != nil {
+		out.Package = &v2.Package{WeightKg: in.Package.WeightKg, DimensionsCm: v1.PackageDimensions(in.Package.DimensionsCm)}
+	}
+	if in.FuelIndex != nil {
+		out.FuelIndex = &v2.FuelIndex{Rate: in.FuelIndex.Rate, EffectiveDate: in.FuelIndex.EffectiveDate}
+	}
+	return out
+}
+
+// ResponseFromV2 converts a v2 calculation response into the domain model. The currency of the
+// quote is the currency of Total, and the available services are the services of the levels
+func ResponseFromV2(in *v2.CalculateShippingResponse) *model.CalculateShippingResponse {
+	if in == nil {
+		return nil
+	}
+	out := &model.CalculateShippingResponse{
+		QuoteID:               in.QuoteID,
+		Currency:              in.Total.Currency,
+		PricingVersion:        in.PricingVersion,
+		ExpiresAt:             copyTime(in.ExpiresAt),
+		ShippingCost:          in.Total.Amount,
+		EstimatedDeliveryTime: in.EstimatedDeliveryTime,
+		DeliveryDate:          in.DeliveryDate,
+		SelectedService:       in.SelectedService,
+		Degraded:              in.Degraded,
+	}
+	if in.ServiceLevels != nil {
+		out.AvailableServices = make([]string, len(in.ServiceLevels))
+		out.ShippingOptions = make([]model.ShippingOption, len(in.ServiceLevels))
+		for i, level := range in.ServiceLevels {
+			out.AvailableServices[i] = level.Service
+			out.ShippingOptions[i] = model.ShippingOption{
+				Service:         level.Service,
+				Cost:            level.Price.Amount,
+				Time:            level.DeliveryTime,
+				EstimatedDays:   level.EstimatedDays,
+				DeliveryDate:    level.DeliveryDate,
+				WeekendDelivery: level.WeekendDelivery,
+				Cheapest:        level.Cheapest,
+				Fastest:         level.Fastest,
+				Token:           level.Token,
+			}
+		}
+	}
+	if in.Breakdown != nil {
+		out.Breakdown = &model.CostBreakdown{
+			BaseCost:                in.Breakdown.BaseCost.Amount,
+			WeightSurcharge:         in.Breakdown.WeightSurcharge.Amount,
+			VolumeSurcharge:         in.Breakdown.VolumeSurcharge.Amount,
+			PackageTypeSurcharge:    in.Breakdown.PackageTypeSurcharge.Amount,
+			DeliveryTypeAdjustment:  in.Breakdown.DeliveryTypeAdjustment.Amount,
+			ExpressSurcharge:        in.Breakdown.ExpressSurcharge.Amount,
+			ReturnAdjustment:        in.Breakdown.ReturnAdjustment.Amount,
+			PickupSurcharge:         in.Breakdown.PickupSurcharge.Amount,
+			RestrictedArea:          in.Breakdown.RestrictedArea,
+			RestrictedAreaSurcharge: in.Breakdown.RestrictedAreaSurcharge.Amount,
+			RemoteAreas:             in.Breakdown.RemoteAreas,
+			RemoteAreaSurcharge:     in.Breakdown.RemoteAreaSurcharge.Amount,
+			WeekendSurcharge:        in.Breakdown.WeekendSurcharge.Amount,
+			FuelSurcharge:           in.Breakdown.FuelSurcharge.Amount,
+			PriceLimit:              in.Breakdown.PriceLimit,
+			PriceLimitAdjustment:    in.Breakdown.PriceLimitAdjustment.Amount,
+			UnroundedTotal:          in.Breakdown.UnroundedTotal.Amount,
+			RoundingAdjustment:      in.Breakdown.RoundingAdjustment.Amount,
+			Total:                   in.Breakdown.Total.Amount,
+			LandedCost:              in.Breakdown.LandedCost.Amount,
+		}
+		if in.Breakdown.AdditionalServices != nil {
+			out.Breakdown.AdditionalServices = make([]model.ServiceFee, len(in.Breakdown.AdditionalServices))
+			for i, fee := range in.Breakdown.AdditionalServices {
+				out.Breakdown.AdditionalServices[i] = model.ServiceFee{Service: fee.Service, Fee: fee.Fee.Amount}
+			}
+		}
+		if in.Breakdown.Duties != nil {
+			out.Breakdown.Duties = make([]model.DutyCharge, len(in.Breakdown.Duties))
+			for i, duty := range in.Breakdown.Duties {
+				out.Breakdown.Duties[i] = model.DutyCharge{Name: duty.Name, Rate: duty.Rate, Amount: duty.Amount.Amount}
+			}
+		}
+		if tax := in.Breakdown.Tax; tax != nil {
+			out.Breakdown.Tax = &model.FreightTax{
+				Tax:              tax.Tax,
+				Rate:             tax.Rate,
+				OriginState:      tax.OriginState,
+				DestinationState: tax.DestinationState,
+				Municipality:     tax.Municipality,
+				Gross:            tax.Gross.Amount,
+				Amount:           tax.Amount.Amount,
+				Net:              tax.Net.Amount,
+			}
+		}
+	}
+	if in.Experiment != nil {
+		out.Experiment = &model.ExperimentAssignment{Name: in.Experiment.Name, Arm: in.Experiment.Arm}
+	}
+	if in.Package != nil {
+		out.Package = &model.PackageMeasures{WeightKg: in.Package.WeightKg, DimensionsCm: model.PackageDimensions(in.Package.DimensionsCm)}
+	}
+	if in.FuelIndex != nil {
+		out.FuelIndex = &model.FuelIndex{Rate: in.FuelIndex.Rate, EffectiveDate: in.FuelIndex.EffectiveDate}
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package mapper
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseDomainV2_RoundTrip_AllFields(t *testing.T) {
+	rnd := rand.New(rand.NewSource(8))
+	for i := 0; i < 50; i++ {
+		// Arrange
+		var in model.CalculateShippingResponse
+		fillNonZero(t, reflect.ValueOf(&in).Elem(), rnd)
+		// v2 lists the available services as the service levels
+		in.AvailableServices = make([]string, len(in.ShippingOptions))
+		for i, opt := range in.ShippingOptions {
+			in.AvailableServices[i] = opt.Service
+		}
+
+		// Act
+		out := ResponseFromV2(ResponseToV2(&in))
+
+		// Assert
+		assert.Equal(t, &in, out)
+	}
+}
+
+func TestResponseToV2_TagsAmountsWithCurrency(t *testing.T) {
+	// Arrange
+	in := &model.CalculateShippingResponse{
+		Currency:        "EUR",
+		ShippingCost:    money.FromMinor(1437.5),
+		ShippingOptions: []model.ShippingOption{{Service: "standard", Cost: money.FromMinor(1437.5)}},
+		Breakdown: &model.CostBreakdown{
+			Total:              money.FromMinor(1437.5),
+			AdditionalServices: []model.ServiceFee{{Service: "signature", Fee: money.FromMinor(300)}},
+		},
+	}
+
+	// Act
+	out := ResponseToV2(in)
+
+	// Assert
+	assert.Equal(t, "EUR", out.Total.Currency)
+	assert.Equal(t, money.FromMinor(1437.5), out.Total.Amount)
+	assert.Equal(t, "EUR", out.ServiceLevels[0].Price.Currency)
+	assert.Equal(t, "EUR", out.Breakdown.Total.Currency)
+	assert.Equal(t, "EUR", out.Breakdown.AdditionalServices[0].Fee.Currency)
+}
+
+func TestResponseV2_NilInput(t *testing.T) {
+	// Act & Assert
+	assert.Nil(t, ResponseToV2(nil))
+	assert.Nil(t, ResponseFromV2(nil))
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"go.uber.org/zap"
+)
+
+// sampleFloat returns a pseudo-random number in [0.0, 1.0), replaceable in tests
+var sampleFloat = rand.Float64
+
+// AccessLogConfig holds the access log configuration
+type AccessLogConfig struct {
+	// SampleRate is the fraction (0.0 to 1.0) of non-error requests that are logged.
+	// Requests answered with 5xx are always logged
+	SampleRate float64
+	// ExcludePaths are request paths never logged, such as health checks
+	ExcludePaths []string
+}
+
+// DefaultAccessLogConfig logs every request except the health endpoints
+func DefaultAccessLogConfig() AccessLogConfig {
+	return AccessLogConfig{
+		SampleRate:   1.0,
+		ExcludePaths: []string{"/health", "/healthz", "/livez", "/readyz"},
+	}
+}
+
+// AccessLogConfigFromEnv builds the access log configuration from environment variables:
+// - ACCESS_LOG_SAMPLE_RATE: fraction of non-error requests logged (default: 1.0)
+// - ACCESS_LOG_EXCLUDE_PATHS: comma-separated paths never logged (default: health endpoints)
+func AccessLogConfigFromEnv() (AccessLogConfig, error) {
+	cfg := DefaultAccessLogConfig()
+
+	rate, err := config.Float("ACCESS_LOG_SAMPLE_RATE", cfg.SampleRate)
+	if err != nil {
+		return cfg, err
+	}
+	if rate < 0 || rate > 1 {
+		return cfg, fmt.Errorf("ACCESS_LOG_SAMPLE_RATE must be between 0 and 1, got %v", rate)
+	}
+	cfg.SampleRate = rate
+	cfg.ExcludePaths = config.List("ACCESS_LOG_EXCLUDE_PATHS", cfg.ExcludePaths)
+
+	return cfg, nil
+}
+
+// AccessLog emits one structured log entry per request with method, route, status, latency,
+// response size, correlation_id and trace_id. It must run after RequestID and the tracing middleware
+func AccessLog(l *zap.Logger, cfg AccessLogConfig) func(http.Handler) http.Handler {
+	excluded := make(map[string]struct{}, len(cfg.ExcludePaths))
+	for _, path := range cfg.ExcludePaths {
+		excluded[strings.TrimRight(path, "/")] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := excluded[strings.TrimRight(r.URL.Path, "/")]; ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status < http.StatusInternalServerError && sampleFloat() >= cfg.SampleRate {
+				return
+			}
+
+			fields := []zap.Field{
+				zap.String("method", r.Method),
+				zap.String("route", routePattern(r)),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", status),
+				zap.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000.0),
+				zap.Int("bytes", ww.BytesWritten()),
+				zap.String("remote_ip", r.RemoteAddr),
+				zap.String("user_agent", r.UserAgent()),
+			}
+
+			requestLogger := logger.WithTracingFields(l, r.Context())
+			switch {
+			case status >= http.StatusInternalServerError:
+				requestLogger.Error("Requisição HTTP", fields...)
+			case status >= http.StatusBadRequest:
+				requestLogger.Warn("Requisição HTTP", fields...)
+			default:
+				requestLogger.Info("Requisição HTTP", fields...)
+			}
+		})
+	}
+}
+
+// routePattern returns the matched chi route pattern, falling back to the raw path
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return r.URL.Path
+}
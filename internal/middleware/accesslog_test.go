@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newAccessLogRouter(cfg AccessLogConfig, status int) (http.Handler, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	r := chi.NewRouter()
+	r.Use(chimiddleware.RequestID)
+	r.Use(AccessLog(zap.New(core), cfg))
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("hello"))
+	}
+	r.Get("/items/{id}", handler)
+	r.Get("/healthz", handler)
+	return r, logs
+}
+
+func TestAccessLog_LogsStructuredFields(t *testing.T) {
+	// Arrange
+	router, logs := newAccessLogRouter(DefaultAccessLogConfig(), http.StatusOK)
+	req := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, zapcore.InfoLevel, entry.Level)
+	fields := entry.ContextMap()
+	assert.Equal(t, "GET", fields["method"])
+	assert.Equal(t, "/items/{id}", fields["route"])
+	assert.Equal(t, "/items/42", fields["path"])
+	assert.Equal(t, int64(http.StatusOK), fields["status"])
+	assert.Equal(t, int64(5), fields["bytes"])
+	assert.Contains(t, fields, "latency_ms")
+	assert.NotEmpty(t, fields["correlation_id"])
+}
+
+func TestAccessLog_LevelByStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		expected zapcore.Level
+	}{
+		{name: "client error", status: http.StatusBadRequest, expected: zapcore.WarnLevel},
+		{name: "server error", status: http.StatusInternalServerError, expected: zapcore.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			router, logs := newAccessLogRouter(DefaultAccessLogConfig(), tt.status)
+			req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+
+			// Act
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			// Assert
+			assert.Equal(t, 1, logs.Len())
+			assert.Equal(t, tt.expected, logs.All()[0].Level)
+		})
+	}
+}
+
+func TestAccessLog_ExcludesHealthEndpoints(t *testing.T) {
+	// Arrange
+	router, logs := newAccessLogRouter(DefaultAccessLogConfig(), http.StatusOK)
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+
+	// Act
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	assert.Equal(t, 0, logs.Len())
+}
+
+func TestAccessLog_Sampling(t *testing.T) {
+	// Arrange
+	original := sampleFloat
+	defer func() { sampleFloat = original }()
+	sampleFloat = func() float64 { return 0.5 }
+
+	cfg := DefaultAccessLogConfig()
+	cfg.SampleRate = 0.1
+	okRouter, okLogs := newAccessLogRouter(cfg, http.StatusOK)
+	errRouter, errLogs := newAccessLogRouter(cfg, http.StatusInternalServerError)
+
+	// Act
+	okRouter.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/1", nil))
+	errRouter.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/1", nil))
+
+	// Assert
+	assert.Equal(t, 0, okLogs.Len())
+	assert.Equal(t, 1, errLogs.Len())
+}
+
+func TestAccessLogConfigFromEnv(t *testing.T) {
+	// Arrange
+	t.Setenv("ACCESS_LOG_SAMPLE_RATE", "0.25")
+	t.Setenv("ACCESS_LOG_EXCLUDE_PATHS", "/ping,/metrics")
+
+	// Act
+	cfg, err := AccessLogConfigFromEnv()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 0.25, cfg.SampleRate)
+	assert.Equal(t, []string{"/ping", "/metrics"}, cfg.ExcludePaths)
+}
+
+func TestAccessLogConfigFromEnv_InvalidRate(t *testing.T) {
+	// Arrange
+	t.Setenv("ACCESS_LOG_SAMPLE_RATE", "2")
+
+	// Act
+	_, err := AccessLogConfigFromEnv()
+
+	// Assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+)
+
+// AdminToken is a bearer token of the admin API and the actor it identifies in audit logs
+type AdminToken struct {
+	Actor string
+	Token string
+}
+
+// AdminConfig holds the tokens accepted by the admin API
+type AdminConfig struct {
+	// Tokens authenticate admin requests; the admin API is disabled without tokens
+	Tokens []AdminToken
+}
+
+// AdminConfigFromEnv reads ADMIN_TOKENS, a comma-separated list of actor:token pairs (default:
+// none, the admin API is disabled)
+func AdminConfigFromEnv() (AdminConfig, error) {
+	var cfg AdminConfig
+	for _, entry := range config.List("ADMIN_TOKENS", nil) {
+		actor, token, ok := strings.Cut(entry, ":")
+		actor, token = strings.TrimSpace(actor), strings.TrimSpace(token)
+		if !ok || actor == "" || token == "" {
+			return AdminConfig{}, fmt.Errorf("ADMIN_TOKENS entries must be actor:token, got an entry without actor or token")
+		}
+		cfg.Tokens = append(cfg.Tokens, AdminToken{Actor: actor, Token: token})
+	}
+	return cfg, nil
+}
+
+// Enabled reports whether admin tokens are configured
+func (c AdminConfig) Enabled() bool {
+	return len(c.Tokens) > 0
+}
+
+type adminActorKey struct{}
+
+// AdminActor returns the actor authenticated by RequireAdmin, or "" outside admin requests
+func AdminActor(ctx context.Context) string {
+	actor, _ := ctx.Value(adminActorKey{}).(string)
+	return actor
+}
+
+// RequireAdmin rejects requests without an admin bearer token in the Authorization header with
+// 401 and records the actor of the token in the request context
+func RequireAdmin(cfg AdminConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, bearer := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			actor := ""
+			for _, admin := range cfg.Tokens {
+				// Compare with every token, so the response time does not reveal which one matched
+				if subtle.ConstantTimeCompare([]byte(token), []byte(admin.Token)) == 1 {
+					actor = admin.Actor
+				}
+			}
+			if !bearer || token == "" || actor == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid admin token"})
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), adminActorKey{}, actor)))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    AdminConfig
+		wantErr bool
+	}{
+		{"disabled", "", AdminConfig{}, false},
+		{"tokens", "alice:s3cret, deploy-bot:t0ken", AdminConfig{Tokens: []AdminToken{{Actor: "alice", Token: "s3cret"}, {Actor: "deploy-bot", Token: "t0ken"}}}, false},
+		{"token without actor", "s3cret", AdminConfig{}, true},
+		{"empty token", "alice:", AdminConfig{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("ADMIN_TOKENS", tt.value)
+
+			// Act
+			cfg, err := AdminConfigFromEnv()
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+			assert.Equal(t, len(tt.want.Tokens) > 0, cfg.Enabled())
+		})
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	cfg := AdminConfig{Tokens: []AdminToken{{Actor: "alice", Token: "s3cret"}, {Actor: "deploy-bot", Token: "t0ken"}}}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantActor     string
+	}{
+		{"first token", "Bearer s3cret", http.StatusOK, "alice"},
+		{"second token", "Bearer t0ken", http.StatusOK, "deploy-bot"},
+		{"unknown token", "Bearer guess", http.StatusUnauthorized, ""},
+		{"without scheme", "s3cret", http.StatusUnauthorized, ""},
+		{"missing", "", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var actor string
+			handler := RequireAdmin(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actor = AdminActor(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/admin/pricing/reload", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantActor, actor)
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// bufferedWriter holds the response of a handler so that it can be re-encoded before it is sent,
+// e.g. for clients that negotiated XML or protobuf
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+// newBufferedWriter buffers the response written to w
+func newBufferedWriter(w http.ResponseWriter) *bufferedWriter {
+	return &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (bw *bufferedWriter) WriteHeader(code int) {
+	bw.status = code
+}
+
+func (bw *bufferedWriter) Write(b []byte) (int, error) {
+	return bw.buf.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (bw *bufferedWriter) Unwrap() http.ResponseWriter {
+	return bw.ResponseWriter
+}
+
+// isJSON reports whether the handler responded with JSON
+func (bw *bufferedWriter) isJSON() bool {
+	mediaType, _, _ := mime.ParseMediaType(bw.ResponseWriter.Header().Get("Content-Type"))
+	return strings.EqualFold(mediaType, ContentTypeJSON)
+}
+
+// flush sends the response as the handler wrote it
+func (bw *bufferedWriter) flush() {
+	bw.ResponseWriter.WriteHeader(bw.status)
+	_, _ = bw.ResponseWriter.Write(bw.buf.Bytes())
+}
+
+// replace sends body with the status of the handler in place of its response
+func (bw *bufferedWriter) replace(contentType string, body []byte) {
+	header := bw.ResponseWriter.Header()
+	header.Set("Content-Type", contentType)
+	header.Del("Content-Length")
+	bw.ResponseWriter.WriteHeader(bw.status)
+	_, _ = bw.ResponseWriter.Write(body)
+}
+
+// varyAccept marks a negotiated response as varying by Accept, once
+func varyAccept(header http.Header) {
+	for _, value := range header.Values("Vary") {
+		if strings.EqualFold(value, "Accept") {
+			return
+		}
+	}
+	header.Add("Vary", "Accept")
+}
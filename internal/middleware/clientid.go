@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/experiment"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+)
+
+// ClientIDHeader identifies the API client a request is attributed to in the metrics
+const ClientIDHeader = "X-Client-ID"
+
+// ClientIDConfig holds the clients the metrics are sliced by
+type ClientIDConfig struct {
+	// KnownClients are the client IDs used as metric attributes; other IDs are attributed to
+	// telemetry.OtherClient, so that callers cannot grow the number of time series at will
+	KnownClients []string
+}
+
+// ClientIDConfigFromEnv reads METRICS_CLIENT_IDS, a comma-separated list of client IDs (default: none)
+func ClientIDConfigFromEnv() ClientIDConfig {
+	return ClientIDConfig{KnownClients: config.List("METRICS_CLIENT_IDS", nil)}
+}
+
+// ClientID attributes the metrics of each request to the client of ClientIDHeader:
+// telemetry.UnknownClient without the header, the client ID when it is known and
+// telemetry.OtherClient otherwise. Every client ID, known or not, is also the subject its quotes
+// are assigned to a pricing experiment arm by
+func ClientID(cfg ClientIDConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientID := telemetry.UnknownClient
+			header := strings.TrimSpace(r.Header.Get(ClientIDHeader))
+			if header != "" {
+				clientID = telemetry.OtherClient
+				if slices.Contains(cfg.KnownClients, header) {
+					clientID = header
+				}
+			}
+			ctx := experiment.WithSubject(telemetry.ContextWithClientID(r.Context(), clientID), header)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
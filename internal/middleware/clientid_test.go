@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/experiment"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIDConfigFromEnv(t *testing.T) {
+	// Arrange
+	t.Setenv("METRICS_CLIENT_IDS", "loja-1, marketplace")
+
+	// Act
+	cfg := ClientIDConfigFromEnv()
+
+	// Assert
+	assert.Equal(t, ClientIDConfig{KnownClients: []string{"loja-1", "marketplace"}}, cfg)
+}
+
+func TestClientID(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		want        string
+		wantSubject string
+	}{
+		{"without header", "", telemetry.UnknownClient, ""},
+		{"known client", "loja-1", "loja-1", "loja-1"},
+		{"known client with spaces", " marketplace ", "marketplace", "marketplace"},
+		{"unknown client", "curioso", telemetry.OtherClient, "curioso"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var clientID, subject string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				clientID = telemetry.ClientIDFromContext(r.Context())
+				subject = experiment.SubjectFromContext(r.Context())
+			})
+			req := httptest.NewRequest(http.MethodPost, "/calculate", nil)
+			if tt.header != "" {
+				req.Header.Set(ClientIDHeader, tt.header)
+			}
+
+			// Act
+			ClientID(ClientIDConfig{KnownClients: []string{"loja-1", "marketplace"}})(next).ServeHTTP(httptest.NewRecorder(), req)
+
+			// Assert
+			assert.Equal(t, tt.want, clientID)
+			assert.Equal(t, tt.wantSubject, subject)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+)
+
+// CompressionConfig holds the response compression configuration
+type CompressionConfig struct {
+	// Level is the gzip compression level, from 1 (fastest) to 9 (smallest); 0 disables compression
+	Level int
+	// ContentTypes are the response media types that are compressed
+	ContentTypes []string
+}
+
+// DefaultCompressionConfig returns gzip compression of JSON and CSV responses
+func DefaultCompressionConfig() CompressionConfig {
+	return CompressionConfig{
+		Level:        5,
+		ContentTypes: []string{ContentTypeJSON, "text/csv", "text/plain"},
+	}
+}
+
+// CompressionConfigFromEnv builds the compression configuration from environment variables:
+// - COMPRESSION_LEVEL: gzip level from 1 to 9, 0 disables compression (default: 5)
+// - COMPRESSION_CONTENT_TYPES: comma-separated response media types to compress
+func CompressionConfigFromEnv() (CompressionConfig, error) {
+	cfg := DefaultCompressionConfig()
+	cfg.ContentTypes = config.List("COMPRESSION_CONTENT_TYPES", cfg.ContentTypes)
+
+	level, err := config.Int("COMPRESSION_LEVEL", cfg.Level)
+	if err != nil {
+		return cfg, err
+	}
+	if level < 0 || level > 9 {
+		return cfg, fmt.Errorf("COMPRESSION_LEVEL must be between 0 and 9, got %d", level)
+	}
+	cfg.Level = level
+
+	return cfg, nil
+}
+
+// Compress gzip-encodes responses of the configured media types for clients that send
+// Accept-Encoding: gzip
+func Compress(cfg CompressionConfig) func(http.Handler) http.Handler {
+	if cfg.Level == 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return chimiddleware.Compress(cfg.Level, cfg.ContentTypes...)
+}
+
+// DefaultMaxBodyBytes is the default limit of the JSON, form and XML request bodies
+const DefaultMaxBodyBytes = 1 << 20
+
+// MaxBodyBytesFromEnv reads REQUEST_MAX_BODY_BYTES, the limit of the JSON, form and XML request
+// bodies, both as sent and decompressed (default: 1 MiB)
+func MaxBodyBytesFromEnv() (int64, error) {
+	limit, err := config.Int("REQUEST_MAX_BODY_BYTES", DefaultMaxBodyBytes)
+	if err != nil {
+		return 0, err
+	}
+	if limit <= 0 {
+		return 0, fmt.Errorf("REQUEST_MAX_BODY_BYTES must be positive, got %d", limit)
+	}
+	return int64(limit), nil
+}
+
+// DecompressRequest decodes gzip request bodies (Content-Encoding: gzip) so clients can compress
+// large batch uploads. Bodies are limited to maxBytes both as sent and, for gzip bodies, once
+// decoded, so that a small compressed body cannot expand without bound; reading past the limit
+// fails with an *http.MaxBytesError, which the readers of the body report as 413 Request Entity
+// Too Large. Other encodings are rejected with 415 Unsupported Media Type
+func DecompressRequest(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+			switch encoding {
+			case "", "identity":
+				r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+				next.ServeHTTP(w, r)
+				return
+			case "gzip", "x-gzip":
+			default:
+				writeJSON(w, http.StatusUnsupportedMediaType, map[string]string{
+					"error": fmt.Sprintf("unsupported Content-Encoding %q, expected gzip", encoding),
+				})
+				return
+			}
+
+			reader, err := gzip.NewReader(http.MaxBytesReader(w, r.Body, maxBytes))
+			if err != nil {
+				writeBodyError(w, err, "invalid gzip request body")
+				return
+			}
+			defer reader.Close()
+
+			r.Body = http.MaxBytesReader(w, reader, maxBytes)
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeBodyError reports a failure to read the request body: 413 Request Entity Too Large for
+// bodies over the limit of DecompressRequest, otherwise 400 Bad Request with message
+func writeBodyError(w http.ResponseWriter, err error, message string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit)})
+		return
+	}
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": message})
+}
@@ -0,0 +1,289 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func gzipBytes(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(data))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func newCompressRouter(cfg CompressionConfig, body string) http.Handler {
+	r := chi.NewRouter()
+	r.Use(Compress(cfg))
+	r.Get("/quotes", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		_, _ = w.Write([]byte(body))
+	})
+	return r
+}
+
+func TestCompress(t *testing.T) {
+	body := `{"shipping_cost":` + strings.Repeat("1", 2048) + `}`
+
+	tests := []struct {
+		name           string
+		cfg            CompressionConfig
+		acceptEncoding string
+		wantEncoding   string
+	}{
+		{"gzip accepted", DefaultCompressionConfig(), "gzip, deflate", "gzip"},
+		{"no accept encoding", DefaultCompressionConfig(), "", ""},
+		{"compression disabled", CompressionConfig{Level: 0}, "gzip", ""},
+		{"media type not compressed", CompressionConfig{Level: 5, ContentTypes: []string{"text/csv"}}, "gzip", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			router := newCompressRouter(tt.cfg, body)
+			req := httptest.NewRequest(http.MethodGet, "/quotes", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantEncoding, w.Header().Get("Content-Encoding"))
+			responseBody := w.Body.Bytes()
+			if tt.wantEncoding == "gzip" {
+				zr, err := gzip.NewReader(w.Body)
+				assert.NoError(t, err)
+				responseBody, err = io.ReadAll(zr)
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, body, string(responseBody))
+		})
+	}
+}
+
+func TestDecompressRequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		encoding     string
+		body         []byte
+		wantEncoding string
+	}{
+		{"gzip body", "gzip", gzipBytes(t, "origin,destination\n"), ""},
+		{"x-gzip body", "x-gzip", gzipBytes(t, "origin,destination\n"), ""},
+		{"plain body", "", []byte("origin,destination\n"), ""},
+		{"identity body", "identity", []byte("origin,destination\n"), "identity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var received, encoding string
+			handler := DecompressRequest(DefaultMaxBodyBytes)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				received = string(data)
+				encoding = r.Header.Get("Content-Encoding")
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/calculate/csv", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "origin,destination\n", received)
+			assert.Equal(t, tt.wantEncoding, encoding)
+		})
+	}
+}
+
+func TestDecompressRequest_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		encoding   string
+		body       string
+		wantStatus int
+		wantErr    string
+	}{
+		{"invalid gzip", "gzip", "not gzip", http.StatusBadRequest, "invalid gzip request body"},
+		{"unsupported encoding", "br", "", http.StatusUnsupportedMediaType, `unsupported Content-Encoding "br"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			called := false
+			handler := DecompressRequest(DefaultMaxBodyBytes)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/calculate/csv", strings.NewReader(tt.body))
+			req.Header.Set("Content-Encoding", tt.encoding)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.False(t, called)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var errorResponse map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+			assert.Contains(t, errorResponse["error"], tt.wantErr)
+		})
+	}
+}
+
+func TestDecompressRequest_BodyTooLarge(t *testing.T) {
+	bomb := gzipBytes(t, strings.Repeat("0", 64<<10))
+	incompressible := make([]byte, 2048)
+	_, _ = rand.Read(incompressible)
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+	}{
+		{"decoded body over the limit", "gzip", bomb},
+		{"compressed body over the limit", "gzip", gzipBytes(t, string(incompressible))},
+		{"plain body over the limit", "", []byte(strings.Repeat("0", 2048))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := DecompressRequest(1024)(ValidateBody[struct{}](nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("the handler must not be called")
+			})))
+			req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+			assert.JSONEq(t, `{"error":"request body exceeds 1024 bytes"}`, w.Body.String())
+		})
+	}
+}
+
+func TestDecompressRequest_BodyTooLarge_ConvertedBodies(t *testing.T) {
+	type request struct {
+		Weight float64 `json:"weight"`
+	}
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		convert     func(http.Handler) http.Handler
+	}{
+		{"form", ContentTypeForm, "weight=1&note=" + strings.Repeat("0", 2048), FormJSON[request]()},
+		{"xml", ContentTypeXML, "<quote><weight>1</weight><note>" + strings.Repeat("0", 2048) + "</note></quote>", NegotiateXML[request, request]("quote")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := DecompressRequest(1024)(tt.convert(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("the handler must not be called")
+			})))
+			req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader(gzipBytes(t, tt.body)))
+			req.Header.Set("Content-Encoding", "gzip")
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		})
+	}
+}
+
+func TestMaxBodyBytesFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int64
+		wantErr string
+	}{
+		{"default", "", DefaultMaxBodyBytes, ""},
+		{"custom", "4096", 4096, ""},
+		{"zero", "0", 0, "REQUEST_MAX_BODY_BYTES must be positive"},
+		{"invalid", "big", 0, "REQUEST_MAX_BODY_BYTES"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("REQUEST_MAX_BODY_BYTES", tt.value)
+
+			// Act
+			limit, err := MaxBodyBytesFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, limit)
+		})
+	}
+}
+
+func TestCompressionConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     string
+		wantLevel int
+		wantErr   bool
+	}{
+		{"default", "", 5, false},
+		{"disabled", "0", 0, false},
+		{"best compression", "9", 9, false},
+		{"out of range", "10", 0, true},
+		{"not a number", "fast", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("COMPRESSION_LEVEL", tt.level)
+
+			// Act
+			cfg, err := CompressionConfigFromEnv()
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantLevel, cfg.Level)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Media types accepted by the endpoints
+const (
+	ContentTypeJSON      = "application/json"
+	ContentTypeMultipart = "multipart/form-data"
+	// ContentTypeForm is sent by integrations that cannot send JSON, see FormJSON
+	ContentTypeForm = "application/x-www-form-urlencoded"
+	// ContentTypeMsgpack carries the MessagePack batches of /calculate/csv
+	ContentTypeMsgpack = "application/msgpack"
+)
+
+// contentTypeError is the body returned when the request media type is not supported
+type contentTypeError struct {
+	Error          string   `json:"error"`
+	SupportedTypes []string `json:"supported_types"`
+}
+
+// RequireContentType rejects requests whose Content-Type is not one of the supported media types
+// with 415 Unsupported Media Type, listing the accepted types so clients can fix the request
+// instead of receiving a generic decoding error
+func RequireContentType(supported ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentType := r.Header.Get("Content-Type")
+			if contentType == "" {
+				writeUnsupportedMediaType(w, "Content-Type header is required", supported)
+				return
+			}
+
+			mediaType, _, err := mime.ParseMediaType(contentType)
+			if err != nil {
+				writeUnsupportedMediaType(w, fmt.Sprintf("malformed Content-Type %q", contentType), supported)
+				return
+			}
+
+			for _, s := range supported {
+				if strings.EqualFold(mediaType, s) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			writeUnsupportedMediaType(w, fmt.Sprintf("unsupported Content-Type %q, expected one of: %s",
+				mediaType, strings.Join(supported, ", ")), supported)
+		})
+	}
+}
+
+// writeUnsupportedMediaType writes a 415 JSON response with the list of supported media types
+func writeUnsupportedMediaType(w http.ResponseWriter, message string, supported []string) {
+	writeJSON(w, http.StatusUnsupportedMediaType, contentTypeError{
+		Error:          message,
+		SupportedTypes: supported,
+	})
+}
+
+// writeJSON is a helper function to write JSON responses from middlewares
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", ContentTypeJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// contentTypeErrorBody mirrors the 415 response body for assertions
+type contentTypeErrorBody struct {
+	Error          string   `json:"error"`
+	SupportedTypes []string `json:"supported_types"`
+}
+
+func newContentTypeTestHandler(called *bool) http.Handler {
+	return RequireContentType(ContentTypeJSON)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestRequireContentType_JSON(t *testing.T) {
+	// Arrange
+	called := false
+	handler := newContentTypeTestHandler(&called)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader([]byte("{}")))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	assert.True(t, called)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireContentType_JSONWithCharset(t *testing.T) {
+	// Arrange
+	called := false
+	handler := newContentTypeTestHandler(&called)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader([]byte("{}")))
+	req.Header.Set("Content-Type", "Application/JSON; charset=utf-8")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	assert.True(t, called)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireContentType_UnsupportedType(t *testing.T) {
+	// Arrange
+	called := false
+	handler := newContentTypeTestHandler(&called)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader([]byte("weight=1")))
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	assert.False(t, called)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var body contentTypeErrorBody
+	err := json.Unmarshal(w.Body.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Contains(t, body.Error, `"text/plain"`)
+	assert.Equal(t, []string{"application/json"}, body.SupportedTypes)
+}
+
+func TestRequireContentType_MissingHeader(t *testing.T) {
+	// Arrange
+	called := false
+	handler := newContentTypeTestHandler(&called)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader([]byte("{}")))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	assert.False(t, called)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	var body contentTypeErrorBody
+	err := json.Unmarshal(w.Body.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Equal(t, "Content-Type header is required", body.Error)
+	assert.Equal(t, []string{"application/json"}, body.SupportedTypes)
+}
+
+func TestRequireContentType_MalformedHeader(t *testing.T) {
+	// Arrange
+	called := false
+	handler := newContentTypeTestHandler(&called)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", bytes.NewReader([]byte("{}")))
+	req.Header.Set("Content-Type", "application/json; charset")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	assert.False(t, called)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	var body contentTypeErrorBody
+	err := json.Unmarshal(w.Body.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Contains(t, body.Error, "malformed Content-Type")
+}
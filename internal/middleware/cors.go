@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+)
+
+// CORSConfig holds the Cross-Origin Resource Sharing configuration
+type CORSConfig struct {
+	// AllowedOrigins lists the origins allowed to call the API. "*" allows any origin and
+	// "https://*.example.com" allows any subdomain. An empty list disables CORS
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	ExposedHeaders []string
+	MaxAge         time.Duration
+}
+
+// DefaultCORSConfig returns CORS disabled with sensible methods and headers for when origins are configured
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: nil,
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodOptions},
+		AllowedHeaders: []string{"Content-Type", "Authorization", "X-Request-Id", StrictSchemaHeader, ClientIDHeader, TenantHeader, APIKeyHeader, "traceparent", "tracestate"},
+		ExposedHeaders: []string{"X-Request-Id"},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+// CORSConfigFromEnv builds the CORS configuration from environment variables:
+// - CORS_ALLOWED_ORIGINS: comma-separated allowed origins (default: none, CORS disabled)
+// - CORS_ALLOWED_METHODS: comma-separated allowed methods (default: GET,POST,OPTIONS)
+// - CORS_ALLOWED_HEADERS: comma-separated allowed request headers
+// - CORS_EXPOSED_HEADERS: comma-separated response headers exposed to the browser
+// - CORS_MAX_AGE: how long browsers may cache preflight responses (default: 10m)
+func CORSConfigFromEnv() (CORSConfig, error) {
+	cfg := DefaultCORSConfig()
+	cfg.AllowedOrigins = config.List("CORS_ALLOWED_ORIGINS", cfg.AllowedOrigins)
+	cfg.AllowedMethods = config.List("CORS_ALLOWED_METHODS", cfg.AllowedMethods)
+	cfg.AllowedHeaders = config.List("CORS_ALLOWED_HEADERS", cfg.AllowedHeaders)
+	cfg.ExposedHeaders = config.List("CORS_EXPOSED_HEADERS", cfg.ExposedHeaders)
+
+	maxAge, err := config.Duration("CORS_MAX_AGE", cfg.MaxAge)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.MaxAge = maxAge
+
+	return cfg, nil
+}
+
+// CORS answers preflight requests and adds CORS headers to responses for allowed origins.
+// It must be registered with Use on the router so preflight OPTIONS requests are answered
+// before route matching
+func CORS(cfg CORSConfig) func(http.Handler) http.Handler {
+	allowedMethods := strings.Join(cfg.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(cfg.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(cfg.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		if len(cfg.AllowedOrigins) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			if !originAllowed(cfg.AllowedOrigins, origin) {
+				if preflight {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+
+			if preflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+				w.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+				w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+				w.Header().Set("Access-Control-Max-Age", maxAge)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if exposedHeaders != "" {
+				w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// originAllowed reports whether origin matches one of the allowed origins
+func originAllowed(allowed []string, origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if pattern == "*" || pattern == origin {
+			return true
+		}
+		if scheme, host, ok := strings.Cut(pattern, "://*."); ok {
+			if strings.HasPrefix(origin, scheme+"://") && strings.HasSuffix(origin, "."+host) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCORSRouter(cfg CORSConfig) http.Handler {
+	r := chi.NewRouter()
+	r.Use(CORS(cfg))
+	r.Post("/calculate", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return r
+}
+
+func newCORSTestConfig(origins ...string) CORSConfig {
+	cfg := DefaultCORSConfig()
+	cfg.AllowedOrigins = origins
+	return cfg
+}
+
+func TestCORS_Preflight_AllowedOrigin(t *testing.T) {
+	// Arrange
+	router := newCORSRouter(newCORSTestConfig("https://loja.example.com"))
+	req := httptest.NewRequest(http.MethodOptions, "/calculate", nil)
+	req.Header.Set("Origin", "https://loja.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://loja.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Content-Type")
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	assert.Contains(t, w.Header().Values("Vary"), "Origin")
+}
+
+func TestCORS_Preflight_DisallowedOrigin(t *testing.T) {
+	// Arrange
+	router := newCORSRouter(newCORSTestConfig("https://loja.example.com"))
+	req := httptest.NewRequest(http.MethodOptions, "/calculate", nil)
+	req.Header.Set("Origin", "https://evil.example.org")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_SimpleRequest_AllowedOrigin(t *testing.T) {
+	// Arrange
+	router := newCORSRouter(newCORSTestConfig("https://loja.example.com"))
+	req := httptest.NewRequest(http.MethodPost, "/calculate", nil)
+	req.Header.Set("Origin", "https://loja.example.com")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://loja.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Request-Id", w.Header().Get("Access-Control-Expose-Headers"))
+}
+
+func TestCORS_SimpleRequest_DisallowedOrigin(t *testing.T) {
+	// Arrange
+	router := newCORSRouter(newCORSTestConfig("https://loja.example.com"))
+	req := httptest.NewRequest(http.MethodPost, "/calculate", nil)
+	req.Header.Set("Origin", "https://evil.example.org")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_Disabled(t *testing.T) {
+	// Arrange
+	router := newCORSRouter(DefaultCORSConfig())
+	req := httptest.NewRequest(http.MethodOptions, "/calculate", nil)
+	req.Header.Set("Origin", "https://loja.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestOriginAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		allowed  []string
+		origin   string
+		expected bool
+	}{
+		{name: "exact match", allowed: []string{"https://loja.com"}, origin: "https://loja.com", expected: true},
+		{name: "case insensitive", allowed: []string{"https://Loja.com"}, origin: "https://loja.COM", expected: true},
+		{name: "wildcard", allowed: []string{"*"}, origin: "https://any.com", expected: true},
+		{name: "subdomain wildcard", allowed: []string{"https://*.loja.com"}, origin: "https://br.loja.com", expected: true},
+		{name: "subdomain wildcard wrong scheme", allowed: []string{"https://*.loja.com"}, origin: "http://br.loja.com", expected: false},
+		{name: "subdomain wildcard bare domain", allowed: []string{"https://*.loja.com"}, origin: "https://loja.com", expected: false},
+		{name: "suffix attack", allowed: []string{"https://*.loja.com"}, origin: "https://evilloja.com", expected: false},
+		{name: "no match", allowed: []string{"https://loja.com"}, origin: "https://other.com", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := originAllowed(tt.allowed, tt.origin)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCORSConfigFromEnv(t *testing.T) {
+	// Arrange
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://a.com, https://b.com")
+	t.Setenv("CORS_ALLOWED_METHODS", "POST")
+	t.Setenv("CORS_MAX_AGE", "1h")
+
+	// Act
+	cfg, err := CORSConfigFromEnv()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://a.com", "https://b.com"}, cfg.AllowedOrigins)
+	assert.Equal(t, []string{"POST"}, cfg.AllowedMethods)
+	assert.Equal(t, time.Hour, cfg.MaxAge)
+}
+
+func TestCORSConfigFromEnv_InvalidMaxAge(t *testing.T) {
+	// Arrange
+	t.Setenv("CORS_MAX_AGE", "forever")
+
+	// Act
+	_, err := CORSConfigFromEnv()
+
+	// Assert
+	assert.Error(t, err)
+}
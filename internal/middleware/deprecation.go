@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+)
+
+// DeprecationConfig holds the deprecation schedule of the v1 API
+type DeprecationConfig struct {
+	// Sunset is the date v1 is expected to be removed; zero when not scheduled
+	Sunset time.Time
+}
+
+// DeprecationConfigFromEnv reads API_V1_SUNSET, a YYYY-MM-DD date (default unset)
+func DeprecationConfigFromEnv() (DeprecationConfig, error) {
+	value := config.String("API_V1_SUNSET", "")
+	if value == "" {
+		return DeprecationConfig{}, nil
+	}
+	sunset, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return DeprecationConfig{}, fmt.Errorf("invalid API_V1_SUNSET %q: must be a YYYY-MM-DD date", value)
+	}
+	return DeprecationConfig{Sunset: sunset}, nil
+}
+
+// Deprecated marks the responses of a deprecated API version with the Deprecation header and a
+// Link to the successor route, so that clients notice before the version is removed, and with the
+// Sunset header (RFC 8594) when its removal is scheduled
+func Deprecated(cfg DeprecationConfig, successor string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			header.Set("Deprecation", "true")
+			header.Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+			if !cfg.Sunset.IsZero() {
+				header.Set("Sunset", cfg.Sunset.UTC().Format(http.TimeFormat))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecationConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    DeprecationConfig
+		wantErr bool
+	}{
+		{"default", "", DeprecationConfig{}, false},
+		{"scheduled", "2027-06-30", DeprecationConfig{Sunset: time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)}, false},
+		{"invalid", "next year", DeprecationConfig{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("API_V1_SUNSET", tt.value)
+
+			// Act
+			cfg, err := DeprecationConfigFromEnv()
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        DeprecationConfig
+		wantSunset string
+	}{
+		{"without sunset", DeprecationConfig{}, ""},
+		{"with sunset", DeprecationConfig{Sunset: time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)}, "Wed, 30 Jun 2027 00:00:00 GMT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := Deprecated(tt.cfg, "/v2/calculate")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/calculate", nil))
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "true", w.Header().Get("Deprecation"))
+			assert.Equal(t, `</v2/calculate>; rel="successor-version"`, w.Header().Get("Link"))
+			assert.Equal(t, tt.wantSunset, w.Header().Get("Sunset"))
+		})
+	}
+}
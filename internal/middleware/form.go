@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// fieldValueError is a form field or XML element whose value does not fit the type of its JSON
+// field
+type fieldValueError struct {
+	field   string
+	message string
+}
+
+func (e *fieldValueError) Error() string {
+	return e.field + " " + e.message
+}
+
+// FormJSON converts form-encoded bodies into the JSON body of T, so that the handlers and the
+// validation of JSON requests serve them unchanged; JSON bodies pass through. Form fields are
+// named like the JSON fields of T, nested fields with a dot or brackets ("dimensions.length" or
+// "dimensions[length]"). Numbers and booleans are parsed, lists take repeated or comma-separated
+// values, and empty values are left out as if the field were absent. Fields T does not declare
+// are kept as strings, so that strict schema mode still reports them. Values that do not parse
+// are rejected with 400 Bad Request
+func FormJSON[T any]() func(http.Handler) http.Handler {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if !strings.EqualFold(mediaType, ContentTypeForm) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeBodyError(w, err, "invalid request body")
+				return
+			}
+			values, err := url.ParseQuery(string(data))
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid form body"})
+				return
+			}
+			body, err := formToJSON(t, values)
+			var valueErr *fieldValueError
+			if errors.As(err, &valueErr) {
+				writeJSON(w, http.StatusBadRequest, map[string]string{
+					"error": fmt.Sprintf("invalid form field %q: %s", valueErr.field, valueErr.message),
+					"field": valueErr.field,
+				})
+				return
+			}
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid form body"})
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.Header.Set("Content-Type", ContentTypeJSON)
+			r.Header.Del("Content-Length")
+			r.ContentLength = int64(len(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// formToJSON encodes the form values, keyed by field path, as a JSON object of type t
+func formToJSON(t reflect.Type, values url.Values) ([]byte, error) {
+	object := map[string]any{}
+	for key, fieldValues := range values {
+		path := strings.Split(strings.NewReplacer("[", ".", "]", "").Replace(key), ".")
+		if err := setFormValue(object, t, path, strings.Join(path, "."), fieldValues); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(object)
+}
+
+// setFormValue sets the values of the form field at path into object, a JSON object of type t
+func setFormValue(object map[string]any, t reflect.Type, path []string, field string, values []string) error {
+	name := path[0]
+	fieldType, known := jsonFieldType(t, name)
+	if len(path) > 1 {
+		nested, _ := object[name].(map[string]any)
+		if nested == nil {
+			nested = map[string]any{}
+			object[name] = nested
+		}
+		if !known || fieldType.Kind() != reflect.Struct {
+			// The fields of unknown objects are kept as strings too
+			fieldType = reflect.TypeOf(struct{}{})
+		}
+		return setFormValue(nested, fieldType, path[1:], field, values)
+	}
+
+	if !known {
+		object[name] = values[len(values)-1]
+		return nil
+	}
+	if fieldType.Kind() == reflect.Slice {
+		var list []string
+		for _, value := range values {
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+		}
+		if len(list) > 0 {
+			object[name] = list
+		}
+		return nil
+	}
+	if len(values) > 1 {
+		return &fieldValueError{field: field, message: "must be sent once"}
+	}
+	value := strings.TrimSpace(values[0])
+	if value == "" {
+		return nil
+	}
+	switch fieldType.Kind() {
+	case reflect.String:
+		object[name] = values[0]
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return &fieldValueError{field: field, message: "must be true or false"}
+		}
+		object[name] = parsed
+	case reflect.Float32, reflect.Float64, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+			return &fieldValueError{field: field, message: "must be a finite number"}
+		}
+		object[name] = parsed
+	default:
+		return &fieldValueError{field: field, message: "must have nested fields"}
+	}
+	return nil
+}
+
+// jsonFieldType returns the type of the field of struct t encoded with the JSON name
+func jsonFieldType(t reflect.Type, name string) (reflect.Type, bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || jsonName == "-" {
+			continue
+		}
+		if jsonName == "" {
+			jsonName = field.Name
+		}
+		if strings.EqualFold(jsonName, name) {
+			return field.Type, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantBody    string
+		wantError   string
+	}{
+		{
+			name:        "form fields are converted to JSON",
+			contentType: ContentTypeForm,
+			body:        "origin_zipcode=01310-100&destination_zipcode=04547130&weight=1.5&dimensions.length=10&dimensions[width]=20&dimensions.height=30&is_express=true",
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"destination_zipcode":"04547130","dimensions":{"height":30,"length":10,"width":20},"is_express":true,"origin_zipcode":"01310-100","weight":1.5}`,
+		},
+		{
+			name:        "lists take repeated and comma-separated values",
+			contentType: ContentTypeForm + "; charset=utf-8",
+			body:        "additional_services=insurance,signature&additional_services=fragile",
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"additional_services":["insurance","signature","fragile"]}`,
+		},
+		{
+			name:        "empty values are left out",
+			contentType: ContentTypeForm,
+			body:        "origin_zipcode=01310100&weight=&dimensions.length=",
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"dimensions":{},"origin_zipcode":"01310100"}`,
+		},
+		{
+			name:        "unknown fields are kept as strings",
+			contentType: ContentTypeForm,
+			body:        "wieght=1&dimensions.depth=2",
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"dimensions":{"depth":"2"},"wieght":"1"}`,
+		},
+		{
+			name:        "JSON bodies pass through",
+			contentType: ContentTypeJSON,
+			body:        `{"weight":1}`,
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"weight":1}`,
+		},
+		{
+			name:        "invalid number",
+			contentType: ContentTypeForm,
+			body:        "weight=heavy",
+			wantStatus:  http.StatusBadRequest,
+			wantError:   `invalid form field "weight": must be a finite number`,
+		},
+		{
+			name:        "non-finite number",
+			contentType: ContentTypeForm,
+			body:        "dimensions[height]=Inf",
+			wantStatus:  http.StatusBadRequest,
+			wantError:   `invalid form field "dimensions.height": must be a finite number`,
+		},
+		{
+			name:        "invalid boolean",
+			contentType: ContentTypeForm,
+			body:        "is_express=maybe",
+			wantStatus:  http.StatusBadRequest,
+			wantError:   `invalid form field "is_express": must be true or false`,
+		},
+		{
+			name:        "repeated scalar field",
+			contentType: ContentTypeForm,
+			body:        "weight=1&weight=2",
+			wantStatus:  http.StatusBadRequest,
+			wantError:   `invalid form field "weight": must be sent once`,
+		},
+		{
+			name:        "malformed form body",
+			contentType: ContentTypeForm,
+			body:        "weight=%zz",
+			wantStatus:  http.StatusBadRequest,
+			wantError:   "invalid form body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var received, receivedType string
+			handler := FormJSON[v1.CalculateShippingRequest]()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				received = string(data)
+				receivedType = r.Header.Get("Content-Type")
+				assert.Equal(t, int64(len(data)), r.ContentLength)
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/calculate", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.JSONEq(t, tt.wantBody, received)
+				assert.Equal(t, ContentTypeJSON, receivedType)
+				return
+			}
+			assert.Empty(t, received)
+			var response map[string]string
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantError, response["error"])
+		})
+	}
+}
+
+func TestFormJSON_ValidateBody(t *testing.T) {
+	// Arrange
+	handler := FormJSON[v1.CalculateShippingRequest]()(ValidateBody[v1.CalculateShippingRequest](nil)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })))
+	req := httptest.NewRequest(http.MethodPost, "/calculate",
+		strings.NewReader("origin_zipcode=01310100&destination_zipcode=04547130&dimensions.length=10&dimensions.width=10"))
+	req.Header.Set("Content-Type", ContentTypeForm)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var response validationError
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "weight", response.Field)
+	assert.Len(t, response.Fields, 2)
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+)
+
+// Outcomes of the requests over an overload threshold, counted in the
+// shipping.calculate.overload metric
+const (
+	// OverloadDegraded is a request served with degraded pricing
+	OverloadDegraded = "degraded"
+	// OverloadShed is a request rejected with 503
+	OverloadShed = "shed"
+)
+
+// OverloadConfig holds the in-flight thresholds of the overload protection; a zero threshold is
+// disabled
+type OverloadConfig struct {
+	// DegradeAt is the number of requests in flight above which quotes are priced in degraded mode
+	DegradeAt int
+	// MaxInFlight is the number of requests in flight above which requests are rejected
+	MaxInFlight int
+	// RetryAfter is the wait suggested to the rejected requests
+	RetryAfter time.Duration
+}
+
+// Enabled reports whether any threshold is set
+func (c OverloadConfig) Enabled() bool {
+	return c.DegradeAt > 0 || c.MaxInFlight > 0
+}
+
+// OverloadConfigFromEnv builds the overload protection from environment variables:
+// - OVERLOAD_DEGRADE_AT: requests in flight above which quotes are priced in degraded mode (default: 0, disabled)
+// - OVERLOAD_MAX_IN_FLIGHT: requests in flight above which requests are rejected with 503 (default: 0, disabled)
+// - OVERLOAD_RETRY_AFTER: wait suggested in the Retry-After header of the rejected requests (default: 1s)
+func OverloadConfigFromEnv() (OverloadConfig, error) {
+	degradeAt, err := config.Int("OVERLOAD_DEGRADE_AT", 0)
+	if err != nil {
+		return OverloadConfig{}, err
+	}
+	maxInFlight, err := config.Int("OVERLOAD_MAX_IN_FLIGHT", 0)
+	if err != nil {
+		return OverloadConfig{}, err
+	}
+	retryAfter, err := config.Duration("OVERLOAD_RETRY_AFTER", time.Second)
+	if err != nil {
+		return OverloadConfig{}, err
+	}
+	if degradeAt < 0 || maxInFlight < 0 {
+		return OverloadConfig{}, fmt.Errorf("OVERLOAD_DEGRADE_AT and OVERLOAD_MAX_IN_FLIGHT must not be negative")
+	}
+	if degradeAt > 0 && maxInFlight > 0 && degradeAt >= maxInFlight {
+		return OverloadConfig{}, fmt.Errorf("OVERLOAD_DEGRADE_AT (%d) must be below OVERLOAD_MAX_IN_FLIGHT (%d)", degradeAt, maxInFlight)
+	}
+	if retryAfter <= 0 {
+		return OverloadConfig{}, fmt.Errorf("OVERLOAD_RETRY_AFTER must be positive")
+	}
+	return OverloadConfig{DegradeAt: degradeAt, MaxInFlight: maxInFlight, RetryAfter: retryAfter}, nil
+}
+
+// Overload protects the quote routes it wraps from overload, counting the requests in flight
+// across all of them: above DegradeAt quotes are priced in degraded mode, with the formula strategy
+// only, and above MaxInFlight requests are rejected with 503 and a Retry-After header, so that the
+// requests already accepted keep their latency. Degraded and rejected requests are counted by route
+// in metrics
+func Overload(cfg OverloadConfig, metrics *telemetry.Metrics) func(http.Handler) http.Handler {
+	var inFlight atomic.Int64
+	retryAfter := strconv.Itoa(int(math.Ceil(cfg.RetryAfter.Seconds())))
+
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled() {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+
+			ctx := r.Context()
+			switch {
+			case cfg.MaxInFlight > 0 && n > int64(cfg.MaxInFlight):
+				metrics.IncrementOverload(ctx, routePattern(r), OverloadShed)
+				w.Header().Set("Retry-After", retryAfter)
+				writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "server overloaded, retry later"})
+				return
+			case cfg.DegradeAt > 0 && n > int64(cfg.DegradeAt):
+				metrics.IncrementOverload(ctx, routePattern(r), OverloadDegraded)
+				r = r.WithContext(service.WithDegradedPricing(ctx))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverloadConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    OverloadConfig
+		wantErr bool
+	}{
+		{"defaults", map[string]string{}, OverloadConfig{RetryAfter: time.Second}, false},
+		{"custom", map[string]string{"OVERLOAD_DEGRADE_AT": "200", "OVERLOAD_MAX_IN_FLIGHT": "400", "OVERLOAD_RETRY_AFTER": "3s"}, OverloadConfig{DegradeAt: 200, MaxInFlight: 400, RetryAfter: 3 * time.Second}, false},
+		{"shedding only", map[string]string{"OVERLOAD_MAX_IN_FLIGHT": "400"}, OverloadConfig{MaxInFlight: 400, RetryAfter: time.Second}, false},
+		{"degrade above max", map[string]string{"OVERLOAD_DEGRADE_AT": "400", "OVERLOAD_MAX_IN_FLIGHT": "200"}, OverloadConfig{}, true},
+		{"negative threshold", map[string]string{"OVERLOAD_DEGRADE_AT": "-1"}, OverloadConfig{}, true},
+		{"zero retry after", map[string]string{"OVERLOAD_RETRY_AFTER": "0s"}, OverloadConfig{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for _, key := range []string{"OVERLOAD_DEGRADE_AT", "OVERLOAD_MAX_IN_FLIGHT", "OVERLOAD_RETRY_AFTER"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// Act
+			cfg, err := OverloadConfigFromEnv()
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestOverload(t *testing.T) {
+	// Arrange
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var degraded []bool
+	overload := Overload(OverloadConfig{DegradeAt: 1, MaxInFlight: 2, RetryAfter: 1500 * time.Millisecond}, nil)
+	// Both routes count towards the same requests in flight
+	calculate := overload(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		degraded = append(degraded, service.IsDegraded(r.Context()))
+		mu.Unlock()
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	preview := overload(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			calculate.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/calculate", nil))
+		}()
+	}
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(degraded) == 2
+	}, time.Second, time.Millisecond)
+
+	// Act
+	shed := httptest.NewRecorder()
+	preview.ServeHTTP(shed, httptest.NewRequest(http.MethodPost, "/calculate/preview", nil))
+	close(release)
+	wg.Wait()
+	served := httptest.NewRecorder()
+	preview.ServeHTTP(served, httptest.NewRequest(http.MethodPost, "/calculate/preview", nil))
+
+	// Assert
+	assert.ElementsMatch(t, []bool{false, true}, degraded, "only the request over DegradeAt is degraded")
+	assert.Equal(t, http.StatusServiceUnavailable, shed.Code)
+	assert.Equal(t, "2", shed.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":"server overloaded, retry later"}`, shed.Body.String())
+	assert.Equal(t, http.StatusOK, served.Code, "finished requests leave the in-flight count")
+}
+
+func TestOverload_Disabled(t *testing.T) {
+	// Arrange
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.False(t, service.IsDegraded(r.Context()))
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := Overload(OverloadConfig{RetryAfter: time.Second}, nil)(next)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/calculate", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
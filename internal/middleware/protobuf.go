@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ContentTypeProtobuf is the media type of protobuf-encoded responses
+const ContentTypeProtobuf = "application/x-protobuf"
+
+// NegotiateProtobuf sends the successful JSON responses of a route as protobuf messages to clients
+// whose Accept header prefers application/x-protobuf to application/json, e.g. high-volume internal
+// callers: the response is decoded into a Resp and encoded as the message convert returns. Errors
+// are still sent as JSON, with their JSON Content-Type, since their bodies vary by route and
+// middleware
+func NegotiateProtobuf[Resp any, M proto.Message](convert func(*Resp) M) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			varyAccept(w.Header())
+			if !prefersProtobuf(r.Header.Get("Accept")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			bw := newBufferedWriter(w)
+			next.ServeHTTP(bw, r)
+			if bw.status >= http.StatusBadRequest || !bw.isJSON() {
+				bw.flush()
+				return
+			}
+			body := new(Resp)
+			if err := json.Unmarshal(bw.buf.Bytes(), body); err != nil {
+				bw.flush()
+				return
+			}
+			data, err := proto.Marshal(convert(body))
+			if err != nil {
+				bw.flush()
+				return
+			}
+			bw.replace(ContentTypeProtobuf, data)
+		})
+	}
+}
+
+// prefersProtobuf reports whether the Accept header prefers protobuf to JSON
+func prefersProtobuf(accept string) bool {
+	if accept == "" {
+		return false
+	}
+	return acceptQuality(accept, ContentTypeProtobuf) > acceptQuality(accept, ContentTypeJSON)
+}
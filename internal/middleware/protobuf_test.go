@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/mapper"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	v1pb "github.com/rbonfanti/shipping-calculator/internal/transport/v1/pb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNegotiateProtobuf(t *testing.T) {
+	quote := v1.CalculateShippingResponse{
+		ShippingCost:          25.9,
+		EstimatedDeliveryTime: "2 dias",
+		AvailableServices:     []string{"standard", "express"},
+		ShippingOptions:       []v1.ShippingOption{{Service: "standard", Cost: 25.9, Time: "2 dias", EstimatedDays: 2}},
+	}
+	tests := []struct {
+		name      string
+		accept    string
+		status    int
+		wantType  string
+		wantProto bool
+	}{
+		{"no Accept", "", http.StatusOK, ContentTypeJSON, false},
+		{"protobuf accepted", ContentTypeProtobuf, http.StatusOK, ContentTypeProtobuf, true},
+		{"protobuf preferred", "application/json;q=0.5, application/x-protobuf", http.StatusOK, ContentTypeProtobuf, true},
+		{"JSON preferred", "application/json, application/x-protobuf;q=0.5", http.StatusOK, ContentTypeJSON, false},
+		{"any type", "*/*", http.StatusOK, ContentTypeJSON, false},
+		{"errors stay JSON", ContentTypeProtobuf, http.StatusBadRequest, ContentTypeJSON, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NegotiateProtobuf(mapper.ResponseToProto)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status != http.StatusOK {
+					writeJSON(w, tt.status, map[string]string{"error": "invalid weight"})
+					return
+				}
+				writeJSON(w, http.StatusOK, quote)
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/calculate", strings.NewReader(`{}`))
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.status, rec.Code)
+			assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), tt.wantType), rec.Header().Get("Content-Type"))
+			assert.Equal(t, []string{"Accept"}, rec.Header().Values("Vary"))
+			if !tt.wantProto {
+				return
+			}
+			var decoded v1pb.ShippingQuote
+			assert.NoError(t, proto.Unmarshal(rec.Body.Bytes(), &decoded))
+			assert.Equal(t, &quote, mapper.ResponseFromProto(&decoded))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/rbonfanti/shipping-calculator/internal/usage"
+	"go.uber.org/zap"
+)
+
+// UsageMeter counts the quote requests of the tenant of a request and enforces its quota, e.g.
+// usage.Meter
+type UsageMeter interface {
+	Check(ctx context.Context) error
+	Record(ctx context.Context, quotes int64) error
+	ResetAt() time.Time
+}
+
+// Quota counts the quote requests of each tenant, as resolved by Tenant, and rejects the requests
+// of tenants that used their monthly quota with 429 and a Retry-After header until the quota is
+// renewed. Only successful requests are counted, each as one quote unless the handler reports the
+// quotes it priced with usage.SetQuotes, as the batches do for their rows. Failures of the usage
+// store are logged and the request is served, so that an outage of the store does not stop quoting
+func Quota(meter UsageMeter, l *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			err := meter.Check(ctx)
+			switch {
+			case errors.Is(err, usage.ErrQuotaExceeded):
+				retryAfter := math.Ceil(time.Until(meter.ResetAt()).Seconds())
+				w.Header().Set("Retry-After", strconv.Itoa(int(max(retryAfter, 1))))
+				writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+				return
+			case err != nil:
+				logger.LogError(l, ctx, "Erro ao verificar a cota do tenant", err, zap.String("tenant", tenant.FromContext(ctx)))
+			}
+
+			ctx, quotes := usage.WithQuoteCount(ctx)
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+			if ww.Status() >= http.StatusBadRequest {
+				return
+			}
+			if err := meter.Record(ctx, quotes.Quotes()); err != nil {
+				logger.LogError(l, ctx, "Erro ao registrar o uso do tenant", err, zap.String("tenant", tenant.FromContext(ctx)))
+			}
+		})
+	}
+}
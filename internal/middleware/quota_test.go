@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/usage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// stubMeter fails Check with checkErr and Record with recordErr, counting the recorded quotes
+type stubMeter struct {
+	checkErr  error
+	recordErr error
+	recorded  int
+	resetAt   time.Time
+}
+
+func (m *stubMeter) Check(ctx context.Context) error { return m.checkErr }
+
+func (m *stubMeter) Record(ctx context.Context, quotes int64) error {
+	m.recorded += int(quotes)
+	return m.recordErr
+}
+
+func (m *stubMeter) ResetAt() time.Time { return m.resetAt }
+
+func TestQuota(t *testing.T) {
+	tests := []struct {
+		name         string
+		checkErr     error
+		recordErr    error
+		status       int
+		wantStatus   int
+		wantRecorded int
+	}{
+		{"under quota", nil, nil, http.StatusOK, http.StatusOK, 1},
+		{"failed request is not counted", nil, nil, http.StatusUnprocessableEntity, http.StatusUnprocessableEntity, 0},
+		{"quota exceeded", fmt.Errorf("%w: tenant acme", usage.ErrQuotaExceeded), nil, http.StatusOK, http.StatusTooManyRequests, 0},
+		{"usage store unavailable on check", errors.New("storage unavailable"), nil, http.StatusOK, http.StatusOK, 1},
+		{"usage store unavailable on record", nil, errors.New("storage unavailable"), http.StatusOK, http.StatusOK, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			meter := &stubMeter{checkErr: tt.checkErr, recordErr: tt.recordErr, resetAt: time.Now().Add(time.Hour)}
+			handler := Quota(meter, zaptest.NewLogger(t))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/calculate", nil))
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantRecorded, meter.recorded)
+		})
+	}
+}
+
+func TestQuota_ReportedQuotes(t *testing.T) {
+	// Arrange
+	meter := &stubMeter{}
+	handler := Quota(meter, zaptest.NewLogger(t))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usage.SetQuotes(r.Context(), 500)
+	}))
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/calculate/csv", nil))
+
+	// Assert
+	assert.Equal(t, 500, meter.recorded, "a batch is counted by its quotes")
+}
+
+func TestQuota_RetryAfter(t *testing.T) {
+	// Arrange
+	meter := &stubMeter{checkErr: usage.ErrQuotaExceeded, resetAt: time.Now().Add(90 * time.Minute)}
+	handler := Quota(meter, zaptest.NewLogger(t))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/calculate", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	assert.NoError(t, err)
+	assert.InDelta(t, 5400, retryAfter, 5)
+	assert.JSONEq(t, `{"error": "monthly quota exceeded"}`, rec.Body.String())
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// errorResponse is the structured body returned for server-side failures
+type errorResponse struct {
+	Error         string `json:"error"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// Recoverer recovers from panics in downstream handlers, returning a structured 500 JSON error,
+// recording the panic on the active span, incrementing the panic counter of metrics and logging the
+// stack. It must run inside the access log and tracing middlewares so they observe the 500 status
+func Recoverer(metrics *telemetry.Metrics, l *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					// Sentinel used by net/http to abort a response; must not be suppressed
+					panic(rec)
+				}
+
+				ctx := r.Context()
+				stack := debug.Stack()
+				route := routePattern(r)
+
+				span := trace.SpanFromContext(ctx)
+				span.RecordError(fmt.Errorf("panic: %v", rec), trace.WithAttributes(
+					attribute.String("exception.stacktrace", string(stack)),
+				))
+				span.SetStatus(codes.Error, "panic recovered")
+
+				metrics.IncrementShipmentCalculatePanic(ctx, r.Method, route)
+
+				logger.WithTracingFields(l, ctx).Error("Pânico recuperado durante a requisição",
+					zap.Any("panic", rec),
+					zap.String("method", r.Method),
+					zap.String("route", route),
+					zap.ByteString("stack", stack),
+				)
+
+				writeJSON(w, http.StatusInternalServerError, errorResponse{
+					Error:         "internal server error",
+					CorrelationID: logger.GetCorrelationID(ctx),
+				})
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+)
+
+// RequestID replaces chi's RequestID in the deterministic mode: the request ID is taken from the
+// X-Request-Id header like chi does, or generated with ids otherwise, so that the request IDs and
+// the experiment arms assigned from them are reproducible
+func RequestID(ids determinism.IDGenerator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(chimiddleware.RequestIDHeader)
+			if requestID == "" {
+				requestID = ids.NewID()
+			}
+			ctx := context.WithValue(r.Context(), chimiddleware.RequestIDKey, requestID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
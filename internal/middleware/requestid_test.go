@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"from header", "replay-42", "replay-42"},
+		{"generated", "", determinism.NewSeededIDGenerator(7).NewID()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var requestID string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestID = chimiddleware.GetReqID(r.Context())
+			})
+			req := httptest.NewRequest(http.MethodPost, "/calculate", nil)
+			if tt.header != "" {
+				req.Header.Set(chimiddleware.RequestIDHeader, tt.header)
+			}
+
+			// Act
+			RequestID(determinism.NewSeededIDGenerator(7))(next).ServeHTTP(httptest.NewRecorder(), req)
+
+			// Assert
+			assert.Equal(t, tt.want, requestID)
+		})
+	}
+}
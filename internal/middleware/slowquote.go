@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+	"go.uber.org/zap"
+)
+
+// StageOther is the time of a slow quote spent outside the stages of the calculation, e.g.
+// decoding the request, persisting and signing the quote
+const StageOther = "other"
+
+// SlowQuoteConfig holds the threshold of the slow quote log
+type SlowQuoteConfig struct {
+	// Threshold is the duration above which a quote request is logged; zero disables the log
+	Threshold time.Duration
+}
+
+// Enabled reports whether the slow quotes are logged
+func (c SlowQuoteConfig) Enabled() bool {
+	return c.Threshold > 0
+}
+
+// SlowQuoteConfigFromEnv builds the slow quote log from environment variables:
+// - SLOW_QUOTE_THRESHOLD: duration above which a quote request is logged as slow (default: 0, disabled)
+func SlowQuoteConfigFromEnv() (SlowQuoteConfig, error) {
+	threshold, err := config.Duration("SLOW_QUOTE_THRESHOLD", 0)
+	if err != nil {
+		return SlowQuoteConfig{}, err
+	}
+	if threshold < 0 {
+		return SlowQuoteConfig{}, fmt.Errorf("SLOW_QUOTE_THRESHOLD must not be negative")
+	}
+	return SlowQuoteConfig{Threshold: threshold}, nil
+}
+
+// SlowQuotes logs a warning for the quote requests it wraps that take longer than the threshold,
+// with the time spent in each stage of the calculation (see service.WithTiming), the slowest stage
+// and the decisions taken to price the quote. The time spent outside the calculation is reported as
+// StageOther. It must run after RequestID, the tracing middleware and Tenant
+func SlowQuotes(l *zap.Logger, cfg SlowQuoteConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled() {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx, timing := service.WithTiming(r.Context())
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			elapsed := time.Since(start)
+			if elapsed <= cfg.Threshold {
+				return
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			stages := make(map[string]float64)
+			slowest, slowestTime, other := StageOther, time.Duration(0), elapsed
+			for _, stage := range timing.Stages() {
+				stages[stage.Stage] = milliseconds(stage.Duration)
+				other -= stage.Duration
+				if stage.Duration > slowestTime {
+					slowest, slowestTime = stage.Stage, stage.Duration
+				}
+			}
+			if other > slowestTime {
+				slowest = StageOther
+			}
+			stages[StageOther] = milliseconds(max(other, 0))
+			steps := timing.Steps()
+			decisions := make([]string, 0, len(steps))
+			for _, step := range steps {
+				decisions = append(decisions, step.Step+": "+step.Detail)
+			}
+
+			logger.WithTracingFields(l, ctx).Warn("Cotação lenta",
+				zap.String("method", r.Method),
+				zap.String("route", routePattern(r)),
+				zap.Int("status", status),
+				zap.Float64("latency_ms", milliseconds(elapsed)),
+				zap.Float64("threshold_ms", milliseconds(cfg.Threshold)),
+				zap.String("slowest_stage", slowest),
+				zap.Any("stages_ms", stages),
+				zap.Strings("decisions", decisions),
+				zap.String("tenant", tenant.FromContext(ctx)),
+				zap.String("client_id", telemetry.ClientIDFromContext(ctx)),
+			)
+		})
+	}
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000.0
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newSlowQuoteRouter serves POST /calculate with a quote priced by the shipping service, after
+// waiting for delay
+func newSlowQuoteRouter(cfg SlowQuoteConfig, delay time.Duration) (http.Handler, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	shipping := service.NewShippingService()
+	r := chi.NewRouter()
+	r.Use(chimiddleware.RequestID)
+	r.With(SlowQuotes(zap.New(core), cfg)).Post("/calculate", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		_, err := shipping.CalculateShipping(r.Context(), &model.CalculateShippingRequest{
+			OriginZipcode:      "01310100",
+			DestinationZipcode: "20040020",
+			Weight:             1,
+			Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+		})
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	return r, logs
+}
+
+func TestSlowQuotes_LogsSlowQuote(t *testing.T) {
+	// Arrange
+	router, logs := newSlowQuoteRouter(SlowQuoteConfig{Threshold: 10 * time.Millisecond}, 20*time.Millisecond)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", nil)
+
+	// Act
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	assert.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, zapcore.WarnLevel, entry.Level)
+	fields := entry.ContextMap()
+	assert.Equal(t, "/calculate", fields["route"])
+	assert.Equal(t, int64(http.StatusOK), fields["status"])
+	assert.Equal(t, 10.0, fields["threshold_ms"])
+	assert.GreaterOrEqual(t, fields["latency_ms"], 20.0)
+	assert.Equal(t, StageOther, fields["slowest_stage"], "the wait is outside the calculation")
+	assert.Equal(t, "default", fields["tenant"])
+	assert.NotEmpty(t, fields["correlation_id"])
+	stages, ok := fields["stages_ms"].(map[string]float64)
+	if assert.True(t, ok) {
+		assert.Contains(t, stages, service.StageValidation)
+		assert.Contains(t, stages, service.StageDistance)
+		assert.Contains(t, stages, service.StageDelivery)
+		assert.GreaterOrEqual(t, stages[StageOther], 20.0)
+	}
+	assert.Contains(t, fields["decisions"], "strategy: standard priced with formula")
+}
+
+func TestSlowQuotes_SkipsFastQuotes(t *testing.T) {
+	// Arrange
+	router, logs := newSlowQuoteRouter(SlowQuoteConfig{Threshold: time.Minute}, 0)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", nil)
+
+	// Act
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	assert.Zero(t, logs.Len())
+}
+
+func TestSlowQuotes_Disabled(t *testing.T) {
+	// Arrange
+	router, logs := newSlowQuoteRouter(SlowQuoteConfig{}, time.Millisecond)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", nil)
+
+	// Act
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	assert.Zero(t, logs.Len())
+}
+
+func TestSlowQuoteConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold string
+		want      SlowQuoteConfig
+		wantErr   string
+	}{
+		{"default", "", SlowQuoteConfig{}, ""},
+		{"custom", "750ms", SlowQuoteConfig{Threshold: 750 * time.Millisecond}, ""},
+		{"negative", "-1s", SlowQuoteConfig{}, "SLOW_QUOTE_THRESHOLD must not be negative"},
+		{"invalid", "slow", SlowQuoteConfig{}, "SLOW_QUOTE_THRESHOLD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("SLOW_QUOTE_THRESHOLD", tt.threshold)
+
+			// Act
+			cfg, err := SlowQuoteConfigFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
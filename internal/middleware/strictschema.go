@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/strictjson"
+)
+
+// StrictSchemaHeader turns strict decoding of the request body on ("true") or off ("false") for a
+// request, overriding the configured default
+const StrictSchemaHeader = "X-Strict-Schema"
+
+// StrictSchemaConfig holds the strict decoding configuration
+type StrictSchemaConfig struct {
+	// Default decodes request bodies strictly, rejecting unknown fields, unless a request sends
+	// StrictSchemaHeader: false
+	Default bool
+	// Clients are the client IDs, sent in ClientIDHeader, whose request bodies are decoded
+	// strictly unless a request sends StrictSchemaHeader: false, whatever the Default
+	Clients []string
+}
+
+// StrictSchemaConfigFromEnv reads STRICT_SCHEMA (default false) and STRICT_SCHEMA_CLIENTS, a
+// comma-separated list of client IDs (default: none)
+func StrictSchemaConfigFromEnv() (StrictSchemaConfig, error) {
+	strict, err := config.Bool("STRICT_SCHEMA", false)
+	if err != nil {
+		return StrictSchemaConfig{}, err
+	}
+	return StrictSchemaConfig{Default: strict, Clients: config.List("STRICT_SCHEMA_CLIENTS", nil)}, nil
+}
+
+// StrictSchema marks the request context for strict decoding according to StrictSchemaHeader or,
+// without it, the configuration of the client of ClientIDHeader or the default. Handlers decoding
+// in strict mode reject unknown fields with the closest known field, so clients find typos instead
+// of being quoted without the field
+func StrictSchema(cfg StrictSchemaConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			strict := cfg.Default || slices.Contains(cfg.Clients, strings.TrimSpace(r.Header.Get(ClientIDHeader)))
+			if value := r.Header.Get(StrictSchemaHeader); value != "" {
+				parsed, err := strconv.ParseBool(value)
+				if err != nil {
+					writeJSON(w, http.StatusBadRequest, map[string]string{
+						"error": fmt.Sprintf("invalid %s header %q: must be true or false", StrictSchemaHeader, value),
+					})
+					return
+				}
+				strict = parsed
+			}
+			next.ServeHTTP(w, r.WithContext(strictjson.NewContext(r.Context(), strict)))
+		})
+	}
+}
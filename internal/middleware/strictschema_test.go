@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/strictjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictSchemaConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		clients string
+		want    StrictSchemaConfig
+		wantErr bool
+	}{
+		{"default", "", "", StrictSchemaConfig{}, false},
+		{"enabled", "true", "", StrictSchemaConfig{Default: true}, false},
+		{"clients", "", "checkout, erp", StrictSchemaConfig{Clients: []string{"checkout", "erp"}}, false},
+		{"invalid", "strict", "", StrictSchemaConfig{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("STRICT_SCHEMA", tt.value)
+			t.Setenv("STRICT_SCHEMA_CLIENTS", tt.clients)
+
+			// Act
+			cfg, err := StrictSchemaConfigFromEnv()
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestStrictSchema(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        StrictSchemaConfig
+		header     string
+		clientID   string
+		wantStatus int
+		wantStrict bool
+	}{
+		{"lenient by default", StrictSchemaConfig{}, "", "", http.StatusOK, false},
+		{"strict by default", StrictSchemaConfig{Default: true}, "", "", http.StatusOK, true},
+		{"client opts in", StrictSchemaConfig{}, "true", "", http.StatusOK, true},
+		{"client opts out", StrictSchemaConfig{Default: true}, "false", "", http.StatusOK, false},
+		{"strict client", StrictSchemaConfig{Clients: []string{"checkout"}}, "", "checkout", http.StatusOK, true},
+		{"other client", StrictSchemaConfig{Clients: []string{"checkout"}}, "", "erp", http.StatusOK, false},
+		{"strict client opts out", StrictSchemaConfig{Clients: []string{"checkout"}}, "false", "checkout", http.StatusOK, false},
+		{"invalid header", StrictSchemaConfig{}, "always", "", http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var strict bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				strict = strictjson.FromContext(r.Context())
+			})
+			req := httptest.NewRequest(http.MethodPost, "/calculate", nil)
+			if tt.header != "" {
+				req.Header.Set(StrictSchemaHeader, tt.header)
+			}
+			if tt.clientID != "" {
+				req.Header.Set(ClientIDHeader, tt.clientID)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			StrictSchema(tt.cfg)(next).ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantStrict, strict)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+)
+
+// TenantHeader selects the tenant a request is quoted for
+const TenantHeader = "X-Tenant-ID"
+
+// APIKeyHeader carries the API key of a tenant
+const APIKeyHeader = "X-API-Key"
+
+// Tenant resolves the tenant of each request and stores it in the request context: the tenant of
+// TenantHeader or, without it, the tenant of the APIKeyHeader key, otherwise tenant.Default. API
+// keys of no tenant are ignored, as they were before tenants existed. Unknown tenants are rejected
+// with 400 and tenants with API keys selected without one of them with 401. The tenant.KeyID of a
+// key of the tenant is also stored, attributing the usage of the tenant to its key
+func Tenant(cfg tenant.Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := strings.TrimSpace(r.Header.Get(TenantHeader))
+			keyTenant := ""
+			if key := r.Header.Get(APIKeyHeader); key != "" {
+				keyTenant, _ = cfg.ForAPIKey(key)
+			}
+
+			switch {
+			case id == "" && keyTenant != "":
+				id = keyTenant
+			case id == "":
+				id = tenant.Default
+			case !cfg.Known(id):
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("unknown tenant %q", id)})
+				return
+			case cfg.RequiresAPIKey(id) && keyTenant != id:
+				writeJSON(w, http.StatusUnauthorized, map[string]string{"error": fmt.Sprintf("tenant %q requires one of its API keys in the %s header", id, APIKeyHeader)})
+				return
+			}
+			ctx := tenant.NewContext(r.Context(), id)
+			if keyTenant != "" && keyTenant == id {
+				ctx = tenant.NewAPIKeyContext(ctx, tenant.KeyID(r.Header.Get(APIKeyHeader)))
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireTenantAPIKey rejects with 401 the requests not authenticated by an API key of their
+// tenant, including every request of tenant.Default, which has no keys. It must run after Tenant
+func RequireTenantAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if tenant.FromContext(ctx) == tenant.Default || tenant.APIKeyFromContext(ctx) == "" {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": fmt.Sprintf("an API key of the tenant is required in the %s header", APIKeyHeader)})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
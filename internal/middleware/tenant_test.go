@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenant(t *testing.T) {
+	cfg := tenant.Config{Tenants: map[string]tenant.Tenant{
+		"marketplace-b": {PricingConfigPath: "b.json", APIKeys: []string{"key-b"}},
+		"outlet":        {PricingConfigPath: "outlet.json", APIKeys: []string{"key-outlet"}},
+		"open":          {PricingConfigPath: "open.json"},
+	}}
+
+	tests := []struct {
+		name       string
+		tenant     string
+		apiKey     string
+		wantStatus int
+		wantTenant string
+		wantKeyID  string
+	}{
+		{"default tenant", "", "", http.StatusOK, tenant.Default, ""},
+		{"by api key", "", "key-b", http.StatusOK, "marketplace-b", tenant.KeyID("key-b")},
+		{"by header and api key", "marketplace-b", "key-b", http.StatusOK, "marketplace-b", tenant.KeyID("key-b")},
+		{"by header without keys", "open", "", http.StatusOK, "open", ""},
+		{"explicit default", tenant.Default, "", http.StatusOK, tenant.Default, ""},
+		{"unknown tenant", "marketplace-c", "", http.StatusBadRequest, "", ""},
+		{"api key of no tenant", "", "guess", http.StatusOK, tenant.Default, ""},
+		{"missing api key", "marketplace-b", "", http.StatusUnauthorized, "", ""},
+		{"api key of another tenant", "marketplace-b", "key-outlet", http.StatusUnauthorized, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var got, gotKeyID string
+			handler := Tenant(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, gotKeyID = tenant.FromContext(r.Context()), tenant.APIKeyFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/calculate", nil)
+			if tt.tenant != "" {
+				req.Header.Set(TenantHeader, tt.tenant)
+			}
+			if tt.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantTenant, got)
+			assert.Equal(t, tt.wantKeyID, gotKeyID)
+		})
+	}
+}
+
+func TestRequireTenantAPIKey(t *testing.T) {
+	cfg := tenant.Config{Tenants: map[string]tenant.Tenant{
+		"marketplace-b": {PricingConfigPath: "b.json", APIKeys: []string{"key-b"}},
+		"open":          {PricingConfigPath: "open.json"},
+	}}
+
+	tests := []struct {
+		name       string
+		tenant     string
+		apiKey     string
+		wantStatus int
+	}{
+		{"api key of the tenant", "", "key-b", http.StatusOK},
+		{"default tenant", "", "", http.StatusUnauthorized},
+		{"tenant without keys", "open", "", http.StatusUnauthorized},
+		{"api key of no tenant", "", "guess", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := Tenant(cfg)(RequireTenantAPIKey(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+			req := httptest.NewRequest(http.MethodPost, "/webhook-subscriptions", nil)
+			if tt.tenant != "" {
+				req.Header.Set(TenantHeader, tt.tenant)
+			}
+			if tt.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
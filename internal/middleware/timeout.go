@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+)
+
+// timeoutWriteGrace is the time left after the request deadline to write the timeout response
+const timeoutWriteGrace = 5 * time.Second
+
+// TimeoutConfig holds the request deadlines
+type TimeoutConfig struct {
+	// Default is the deadline of routes without their own
+	Default time.Duration
+	// Routes are the deadlines by route pattern, e.g. "/calculate/csv"
+	Routes map[string]time.Duration
+}
+
+// DefaultTimeoutConfig returns a 10s deadline, with 2m for batch quoting, 1m for long polling
+// the price subscriptions, closing the manifests and reporting the SLA, and 30s for generating
+// labels
+func DefaultTimeoutConfig() TimeoutConfig {
+	return TimeoutConfig{
+		Default: 10 * time.Second,
+		Routes: map[string]time.Duration{
+			"/calculate/csv":            2 * time.Minute,
+			"/price-subscriptions/{id}": time.Minute,
+			"/shipments/{id}/label":     30 * time.Second,
+			"/admin/manifests":          time.Minute,
+			"/admin/sla":                time.Minute,
+		},
+	}
+}
+
+// For returns the deadline of a route pattern
+func (c TimeoutConfig) For(route string) time.Duration {
+	if timeout, ok := c.Routes[route]; ok {
+		return timeout
+	}
+	return c.Default
+}
+
+// TimeoutConfigFromEnv builds the request deadlines from environment variables:
+// - REQUEST_TIMEOUT: deadline of every route (default: 10s)
+// - REQUEST_TIMEOUT_ROUTES: comma-separated route=duration deadlines overriding REQUEST_TIMEOUT,
+// e.g. "/calculate/csv=5m" (default: /calculate/csv=2m,/price-subscriptions/{id}=1m,/shipments/{id}/label=30s,/admin/manifests=1m,/admin/sla=1m)
+func TimeoutConfigFromEnv() (TimeoutConfig, error) {
+	cfg := DefaultTimeoutConfig()
+
+	timeout, err := config.Duration("REQUEST_TIMEOUT", cfg.Default)
+	if err != nil {
+		return cfg, err
+	}
+	if timeout <= 0 {
+		return cfg, fmt.Errorf("REQUEST_TIMEOUT must be positive")
+	}
+	cfg.Default = timeout
+
+	entries := config.List("REQUEST_TIMEOUT_ROUTES", nil)
+	if len(entries) == 0 {
+		return cfg, nil
+	}
+	cfg.Routes = make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		route, value, ok := strings.Cut(entry, "=")
+		route = strings.TrimSpace(route)
+		if !ok || route == "" {
+			return cfg, fmt.Errorf("REQUEST_TIMEOUT_ROUTES: entry must be formatted as route=duration")
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || timeout <= 0 {
+			return cfg, fmt.Errorf("REQUEST_TIMEOUT_ROUTES: invalid duration for %s: %q", route, value)
+		}
+		cfg.Routes[route] = timeout
+	}
+	return cfg, nil
+}
+
+// Timeout bounds the handling of a request: the deadline is set on the request context, so that
+// provider calls made with it abort, and on reading the request body. A handler that responds after
+// the deadline has passed has its response replaced by 504 Gateway Timeout with a JSON error. The
+// write deadline of the connection is moved to just after the request deadline, so that routes may
+// take longer than the server write timeout
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			// Deadlines are not supported by every writer (e.g. in tests); the context still applies
+			deadline, _ := ctx.Deadline()
+			controller := http.NewResponseController(w)
+			_ = controller.SetReadDeadline(deadline)
+			_ = controller.SetWriteDeadline(deadline.Add(timeoutWriteGrace))
+
+			tw := &timeoutWriter{ResponseWriter: w, ctx: ctx}
+			next.ServeHTTP(tw, r.WithContext(ctx))
+			if !tw.wroteHeader && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				tw.timeout()
+			}
+		})
+	}
+}
+
+// timeoutWriter replaces the response with 504 Gateway Timeout when it starts after the deadline
+type timeoutWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	if tw.wroteHeader {
+		return
+	}
+	if errors.Is(tw.ctx.Err(), context.DeadlineExceeded) {
+		tw.timeout()
+		return
+	}
+	tw.wroteHeader = true
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	if !tw.wroteHeader {
+		tw.WriteHeader(http.StatusOK)
+	}
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	return tw.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+// timeout writes the 504 response in place of the response of the handler
+func (tw *timeoutWriter) timeout() {
+	tw.wroteHeader = true
+	tw.timedOut = true
+	tw.ResponseWriter.Header().Del("Content-Length")
+	writeJSON(tw.ResponseWriter, http.StatusGatewayTimeout, map[string]string{"error": "request timed out"})
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "responds within the deadline",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte("created"))
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   "created",
+		},
+		{
+			name: "aborts and responds with its own error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+				w.Header().Set("Content-Length", "22")
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"error":"cancelled"}` + "\n"))
+			},
+			wantStatus: http.StatusGatewayTimeout,
+			wantBody:   `{"error":"request timed out"}` + "\n",
+		},
+		{
+			name: "aborts without responding",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			},
+			wantStatus: http.StatusGatewayTimeout,
+			wantBody:   `{"error":"request timed out"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := Timeout(10 * time.Millisecond)(tt.handler)
+			req := httptest.NewRequest(http.MethodPost, "/calculate", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+			if tt.wantStatus == http.StatusGatewayTimeout {
+				assert.Equal(t, ContentTypeJSON, w.Header().Get("Content-Type"))
+				assert.Empty(t, w.Header().Get("Content-Length"))
+			}
+		})
+	}
+}
+
+func TestTimeout_SetsContextDeadline(t *testing.T) {
+	// Arrange
+	var remaining time.Duration
+	handler := Timeout(time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		assert.True(t, ok)
+		remaining = time.Until(deadline)
+	}))
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/serviceability", nil))
+
+	// Assert
+	assert.InDelta(t, time.Minute, remaining, float64(time.Second))
+}
+
+func TestTimeout_ExtendsServerWriteTimeout(t *testing.T) {
+	// Arrange
+	server := httptest.NewUnstartedServer(Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		writeJSON(w, http.StatusOK, map[string]string{"status": "done"})
+	})))
+	server.Config.WriteTimeout = 20 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	// Act
+	resp, err := http.Get(server.URL)
+
+	// Assert
+	assert.NoError(t, err)
+	if assert.NotNil(t, resp) {
+		defer resp.Body.Close()
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "done", body["status"])
+	}
+}
+
+func TestTimeoutConfig_For(t *testing.T) {
+	// Arrange
+	cfg := DefaultTimeoutConfig()
+
+	// Act & Assert
+	assert.Equal(t, 2*time.Minute, cfg.For("/calculate/csv"))
+	assert.Equal(t, time.Minute, cfg.For("/price-subscriptions/{id}"))
+	assert.Equal(t, 30*time.Second, cfg.For("/shipments/{id}/label"))
+	assert.Equal(t, time.Minute, cfg.For("/admin/manifests"))
+	assert.Equal(t, time.Minute, cfg.For("/admin/sla"))
+	assert.Equal(t, 10*time.Second, cfg.For("/calculate"))
+}
+
+func TestTimeoutConfigFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// Arrange
+		t.Setenv("REQUEST_TIMEOUT", "")
+		t.Setenv("REQUEST_TIMEOUT_ROUTES", "")
+
+		// Act
+		cfg, err := TimeoutConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultTimeoutConfig(), cfg)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		// Arrange
+		t.Setenv("REQUEST_TIMEOUT", "3s")
+		t.Setenv("REQUEST_TIMEOUT_ROUTES", "/calculate/csv=5m, /shipments/{id}/tracking = 8s")
+
+		// Act
+		cfg, err := TimeoutConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, TimeoutConfig{
+			Default: 3 * time.Second,
+			Routes: map[string]time.Duration{
+				"/calculate/csv":           5 * time.Minute,
+				"/shipments/{id}/tracking": 8 * time.Second,
+			},
+		}, cfg)
+	})
+
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{"invalid timeout", map[string]string{"REQUEST_TIMEOUT": "soon"}},
+		{"zero timeout", map[string]string{"REQUEST_TIMEOUT": "0s"}},
+		{"route without duration", map[string]string{"REQUEST_TIMEOUT_ROUTES": "/calculate/csv"}},
+		{"invalid route duration", map[string]string{"REQUEST_TIMEOUT_ROUTES": "/calculate/csv=-1m"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			// Act
+			_, err := TimeoutConfigFromEnv()
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rbonfanti/shipping-calculator/internal/validator"
+)
+
+// validationError is the body returned when request fields break the rules of their
+// validator.Tag: Error and Field describe the first one, as the handlers report a single invalid
+// field, and Fields lists all of them
+type validationError struct {
+	Error  string           `json:"error"`
+	Field  string           `json:"field"`
+	Fields []fieldViolation `json:"fields"`
+}
+
+// fieldViolation is a field that breaks a rule, by its path, e.g. "dimensions.height"
+type fieldViolation struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	Error string `json:"error"`
+}
+
+// ValidateBody decodes the JSON body into a T and rejects with 400 Bad Request the requests whose
+// fields break the validator.Tag rules of T, reporting every invalid field by its path so clients
+// fix them at once. The body is restored for the handler, which still decodes it: bodies that are
+// not valid JSON for T are passed on for the handler to report. rejected, when set, is called
+// with each rejected request, e.g. to record it in the metrics of the route
+func ValidateBody[T any](rejected func(r *http.Request, body *T, err validator.FieldErrors)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeBodyError(w, err, "invalid request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(data))
+
+			body := new(T)
+			if err := json.Unmarshal(data, body); err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			var fieldErrs validator.FieldErrors
+			if !errors.As(validator.Struct(body), &fieldErrs) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if rejected != nil {
+				rejected(r, body, fieldErrs)
+			}
+			response := validationError{
+				Error:  fmt.Sprintf("invalid %s: %s", fieldErrs[0].Field, fieldErrs[0]),
+				Field:  fieldErrs[0].Field,
+				Fields: make([]fieldViolation, len(fieldErrs)),
+			}
+			for i, fieldErr := range fieldErrs {
+				response.Fields[i] = fieldViolation{Field: fieldErr.Field, Rule: fieldErr.Rule, Error: fieldErr.Error()}
+			}
+			writeJSON(w, http.StatusBadRequest, response)
+		})
+	}
+}
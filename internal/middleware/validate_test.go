@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/rbonfanti/shipping-calculator/internal/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantError  string
+		wantFields []string
+	}{
+		{
+			name:       "valid request",
+			body:       `{"origin_zipcode":"01310-100","destination_zipcode":"04547130","weight":1,"dimensions":{"length":10,"width":10,"height":10}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid JSON is left to the handler",
+			body:       `{"weight":`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid zipcode",
+			body:       `{"origin_zipcode":"123","destination_zipcode":"04547130","weight":1,"dimensions":{"length":10,"width":10,"height":10}}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid origin_zipcode: origin_zipcode must be a valid zipcode format (4-8 digits)",
+			wantFields: []string{"origin_zipcode"},
+		},
+		{
+			name:       "missing weight and nested dimensions",
+			body:       `{"origin_zipcode":"01310100","destination_zipcode":"04547130","dimensions":{"length":10,"width":0}}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid weight: weight is required",
+			wantFields: []string{"weight", "dimensions.width", "dimensions.height"},
+		},
+		{
+			name:       "missing dimensions",
+			body:       `{"origin_zipcode":"01310100","destination_zipcode":"04547130","weight":-1}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid weight: weight must be greater than 0",
+			wantFields: []string{"weight", "dimensions"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var received string
+			var rejected []string
+			handler := ValidateBody(func(r *http.Request, body *v1.CalculateShippingRequest, err validator.FieldErrors) {
+				for _, fieldErr := range err {
+					rejected = append(rejected, fieldErr.Field)
+				}
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				received = string(data)
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/calculate", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.body, received, "the body is restored for the handler")
+				assert.Empty(t, rejected)
+				return
+			}
+			var body validationError
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantError, body.Error)
+			assert.Equal(t, tt.wantFields[0], body.Field)
+			fields := make([]string, len(body.Fields))
+			for i, field := range body.Fields {
+				fields[i] = field.Field
+			}
+			assert.Equal(t, tt.wantFields, fields)
+			assert.Equal(t, tt.wantFields, rejected)
+			assert.Empty(t, received)
+		})
+	}
+}
+
+func TestValidateBody_WithoutRejected(t *testing.T) {
+	// Arrange
+	handler := ValidateBody[v1.CalculateShippingRequest](nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/calculate/preview", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{
+		"error": "invalid origin_zipcode: origin_zipcode is required",
+		"field": "origin_zipcode",
+		"fields": [
+			{"field": "origin_zipcode", "rule": "required", "error": "origin_zipcode is required"},
+			{"field": "destination_zipcode", "rule": "required", "error": "destination_zipcode is required"},
+			{"field": "weight", "rule": "required", "error": "weight is required"},
+			{"field": "dimensions", "rule": "required", "error": "dimensions is required"}
+		]
+	}`, w.Body.String())
+}
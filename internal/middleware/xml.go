@@ -0,0 +1,293 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// XML media types; responses negotiated as XML are sent with the type the client asked for
+const (
+	ContentTypeXML     = "application/xml"
+	ContentTypeTextXML = "text/xml"
+)
+
+// xmlErrorRoot is the root element of the XML error responses
+const xmlErrorRoot = "error_response"
+
+// NegotiateXML serves the JSON route to clients that speak XML, e.g. legacy warehouse systems.
+// XML bodies are converted into the JSON body of Req, so that the handlers and the validation of
+// JSON requests serve them unchanged: elements are named like the JSON fields of Req, nested
+// fields are child elements, and lists are either child elements of any name or comma-separated
+// text. Numbers and booleans are parsed and empty elements are left out as if absent. Responses
+// are sent as XML when Accept prefers application/xml or text/xml to application/json, or when an
+// XML body is sent without Accept: successful JSON responses are re-encoded from Resp under the
+// root element, with the xml struct tags of Resp, and errors under <error_response>
+func NegotiateXML[Req, Resp any](root string) func(http.Handler) http.Handler {
+	t := reflect.TypeOf((*Req)(nil)).Elem()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			varyAccept(w.Header())
+			mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			xmlBody := isXML(mediaType)
+			if responseType, ok := negotiateXML(r.Header.Get("Accept"), xmlBody); ok {
+				bw := newBufferedWriter(w)
+				serveXMLBody(bw, r, t, xmlBody, next)
+				writeXML(bw, responseType, root, new(Resp))
+				return
+			}
+			serveXMLBody(w, r, t, xmlBody, next)
+		})
+	}
+}
+
+// serveXMLBody converts an XML body into the JSON body of type t and serves the request with it;
+// other bodies are served as sent
+func serveXMLBody(w http.ResponseWriter, r *http.Request, t reflect.Type, xmlBody bool, next http.Handler) {
+	if !xmlBody {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	body, err := xmlToJSON(t, r.Body)
+	var valueErr *fieldValueError
+	if errors.As(err, &valueErr) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid XML element %q: %s", valueErr.field, valueErr.message),
+			"field": valueErr.field,
+		})
+		return
+	}
+	if err != nil {
+		writeBodyError(w, err, "invalid XML body")
+		return
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.Header.Set("Content-Type", ContentTypeJSON)
+	r.Header.Del("Content-Length")
+	r.ContentLength = int64(len(body))
+	next.ServeHTTP(w, r)
+}
+
+// isXML reports whether a media type is one of the XML media types
+func isXML(mediaType string) bool {
+	return strings.EqualFold(mediaType, ContentTypeXML) || strings.EqualFold(mediaType, ContentTypeTextXML)
+}
+
+// negotiateXML returns the XML media type to respond with, when the Accept header prefers XML to
+// JSON. Without a preference, e.g. no Accept or */*, requests with XML bodies get XML
+func negotiateXML(accept string, xmlBody bool) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return ContentTypeXML, xmlBody
+	}
+	jsonQuality := acceptQuality(accept, ContentTypeJSON)
+	responseType, xmlQuality := ContentTypeXML, acceptQuality(accept, ContentTypeXML)
+	if q := acceptQuality(accept, ContentTypeTextXML); q > xmlQuality {
+		responseType, xmlQuality = ContentTypeTextXML, q
+	}
+	if xmlQuality == 0 {
+		return "", false
+	}
+	return responseType, xmlQuality > jsonQuality || (xmlQuality == jsonQuality && xmlBody)
+}
+
+// acceptQuality returns the quality the Accept header gives a media type, from its most specific
+// matching range, e.g. application/xml before application/* and */*; 0 when none matches
+func acceptQuality(accept, mediaType string) float64 {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	quality, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		rangeType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		rangeMain, rangeSub, _ := strings.Cut(rangeType, "/")
+		var rank int
+		switch {
+		case rangeMain == typ && rangeSub == subtype:
+			rank = 2
+		case rangeMain == typ && rangeSub == "*":
+			rank = 1
+		case rangeMain == "*" && rangeSub == "*":
+			rank = 0
+		default:
+			continue
+		}
+		if rank <= specificity {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				q = 0
+			}
+		}
+		quality, specificity = q, rank
+	}
+	return quality
+}
+
+// xmlNode is an element of an XML body
+type xmlNode struct {
+	name     string
+	text     string
+	children []*xmlNode
+}
+
+// xmlToJSON encodes the XML document read from body as a JSON object of type t; the name of the
+// root element is not checked
+func xmlToJSON(t reflect.Type, body io.Reader) ([]byte, error) {
+	root, err := parseXML(body)
+	if err != nil {
+		return nil, err
+	}
+	values := url.Values{}
+	flattenXML(root.children, t, "", values)
+	return formToJSON(t, values)
+}
+
+// parseXML reads the root element of an XML document
+func parseXML(body io.Reader) (*xmlNode, error) {
+	decoder := xml.NewDecoder(body)
+	var stack []*xmlNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, errors.New("xml: no root element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: token.Name.Local}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(token)
+			}
+		case xml.EndElement:
+			node := stack[len(stack)-1]
+			if stack = stack[:len(stack)-1]; len(stack) == 0 {
+				return node, nil
+			}
+		}
+	}
+}
+
+// flattenXML adds the text of the leaf elements to values by the path of their JSON field, e.g.
+// "dimensions.length"; the children of list elements are values of the list
+func flattenXML(nodes []*xmlNode, t reflect.Type, prefix string, values url.Values) {
+	for _, node := range nodes {
+		path := prefix + node.name
+		fieldType, known := jsonFieldType(t, node.name)
+		switch {
+		case known && fieldType.Kind() == reflect.Slice:
+			if len(node.children) == 0 {
+				values.Add(path, node.text)
+			}
+			for _, item := range node.children {
+				values.Add(path, item.text)
+			}
+		case len(node.children) > 0:
+			if !known || fieldType.Kind() != reflect.Struct {
+				fieldType = reflect.TypeOf(struct{}{})
+			}
+			flattenXML(node.children, fieldType, path+".", values)
+		default:
+			values.Add(path, node.text)
+		}
+	}
+}
+
+// writeXML writes the buffered response of a request negotiated as XML, re-encoding JSON as XML:
+// successful responses are decoded into body and encoded under root, errors under xmlErrorRoot.
+// Other responses are written as is
+func writeXML(bw *bufferedWriter, mediaType, root string, body any) {
+	if !bw.isJSON() {
+		bw.flush()
+		return
+	}
+
+	var out bytes.Buffer
+	out.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&out)
+	var err error
+	switch {
+	case bw.status >= http.StatusBadRequest:
+		err = encodeJSONAsXML(encoder, xmlErrorRoot, bw.buf.Bytes())
+	case json.Unmarshal(bw.buf.Bytes(), body) == nil:
+		err = encoder.EncodeElement(body, xml.StartElement{Name: xml.Name{Local: root}})
+	default:
+		err = encodeJSONAsXML(encoder, root, bw.buf.Bytes())
+	}
+	if err == nil {
+		err = encoder.Flush()
+	}
+	if err != nil {
+		// The response cannot be re-encoded: send it as the handler wrote it
+		bw.flush()
+		return
+	}
+	bw.replace(mediaType+"; charset=utf-8", out.Bytes())
+}
+
+// encodeJSONAsXML encodes a JSON document under an element named name: object members are child
+// elements named by their keys, in key order, and array items <item> elements
+func encodeJSONAsXML(encoder *xml.Encoder, name string, data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	return encodeValueAsXML(encoder, name, value)
+}
+
+// encodeValueAsXML encodes a decoded JSON value as an element named name
+func encodeValueAsXML(encoder *xml.Encoder, name string, value any) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	switch value := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := encodeValueAsXML(encoder, key, value[key]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range value {
+			if err := encodeValueAsXML(encoder, "item", item); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := encoder.EncodeToken(xml.CharData(fmt.Sprint(value))); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
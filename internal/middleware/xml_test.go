@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateXML_Request(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+		wantError  string
+	}{
+		{
+			name: "elements are converted to JSON",
+			body: `<?xml version="1.0"?>
+<calculate_shipping_request>
+  <origin_zipcode>01310-100</origin_zipcode>
+  <destination_zipcode>04547130</destination_zipcode>
+  <weight>1.5</weight>
+  <dimensions><length>10</length><width>20</width><height>30</height></dimensions>
+  <is_express>true</is_express>
+</calculate_shipping_request>`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"destination_zipcode":"04547130","dimensions":{"height":30,"length":10,"width":20},"is_express":true,"origin_zipcode":"01310-100","weight":1.5}`,
+		},
+		{
+			name:       "lists take child elements or comma-separated text",
+			body:       `<request><additional_services><service>insurance</service><service>fragile</service></additional_services></request>`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"additional_services":["insurance","fragile"]}`,
+		},
+		{
+			name:       "comma-separated list",
+			body:       `<request><additional_services>insurance,fragile</additional_services></request>`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"additional_services":["insurance","fragile"]}`,
+		},
+		{
+			name:       "empty elements are left out",
+			body:       `<request><weight/><dimensions><length></length></dimensions></request>`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"dimensions":{}}`,
+		},
+		{
+			name:       "unknown elements are kept as strings",
+			body:       `<request><wieght>1</wieght><dimensions><depth>2</depth></dimensions></request>`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"dimensions":{"depth":"2"},"wieght":"1"}`,
+		},
+		{
+			name:       "invalid number",
+			body:       `<request><dimensions><height>tall</height></dimensions></request>`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  `invalid XML element "dimensions.height": must be a finite number`,
+		},
+		{
+			name:       "malformed XML",
+			body:       `<request><weight>1</request>`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid XML body",
+		},
+		{
+			name:       "empty body",
+			body:       "",
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid XML body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var received, receivedType string
+			handler := NegotiateXML[v1.CalculateShippingRequest, v1.CalculateShippingResponse]("shipping_quote")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				received = string(data)
+				receivedType = r.Header.Get("Content-Type")
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/calculate", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", ContentTypeXML)
+			req.Header.Set("Accept", ContentTypeJSON)
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.JSONEq(t, tt.wantBody, received)
+				assert.Equal(t, ContentTypeJSON, receivedType)
+				return
+			}
+			assert.Empty(t, received)
+			var response map[string]string
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantError, response["error"])
+		})
+	}
+}
+
+func TestNegotiateXML_Response(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		accept      string
+		wantType    string
+	}{
+		{"JSON without Accept", ContentTypeJSON, "", ContentTypeJSON},
+		{"JSON accepting XML", ContentTypeJSON, ContentTypeXML, ContentTypeXML},
+		{"JSON accepting text/xml", ContentTypeJSON, "text/xml, */*;q=0.1", ContentTypeTextXML},
+		{"JSON preferred over XML", ContentTypeJSON, "application/json, application/xml;q=0.9", ContentTypeJSON},
+		{"XML preferred over JSON", ContentTypeJSON, "application/json;q=0.5, application/xml", ContentTypeXML},
+		{"XML excluded", ContentTypeJSON, "*/*, application/xml;q=0", ContentTypeJSON},
+		{"XML body without Accept", ContentTypeXML, "", ContentTypeXML},
+		{"XML body accepting anything", ContentTypeXML, "*/*", ContentTypeXML},
+		{"XML body accepting JSON", ContentTypeXML, ContentTypeJSON, ContentTypeJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NegotiateXML[v1.CalculateShippingRequest, v1.CalculateShippingResponse]("shipping_quote")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, http.StatusOK, v1.CalculateShippingResponse{ShippingCost: 12.5})
+			}))
+			body := `{}`
+			if tt.contentType == ContentTypeXML {
+				body = `<request/>`
+			}
+			req := httptest.NewRequest(http.MethodPost, "/calculate", strings.NewReader(body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), tt.wantType), rec.Header().Get("Content-Type"))
+			assert.Contains(t, rec.Header().Values("Vary"), "Accept")
+		})
+	}
+}
+
+func TestNegotiateXML_Quote(t *testing.T) {
+	// Arrange
+	quote := v1.CalculateShippingResponse{
+		ShippingCost:          25.9,
+		EstimatedDeliveryTime: "2 dias",
+		AvailableServices:     []string{"standard", "express"},
+		ShippingOptions: []v1.ShippingOption{
+			{Service: "standard", Cost: 25.9, Time: "5 dias", EstimatedDays: 5, Cheapest: true},
+			{Service: "express", Cost: 40, Time: "2 dias", EstimatedDays: 2, Fastest: true},
+		},
+	}
+	handler := NegotiateXML[v1.CalculateShippingRequest, v1.CalculateShippingResponse]("shipping_quote")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, quote)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/calculate", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	req.Header.Set("Accept", ContentTypeXML)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), xml.Header))
+	assert.Contains(t, rec.Body.String(), "<shipping_quote><shipping_cost>25.9</shipping_cost>")
+	assert.Contains(t, rec.Body.String(), "<available_services><service>standard</service><service>express</service></available_services>")
+	var decoded v1.CalculateShippingResponse
+	assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &decoded))
+	assert.Equal(t, quote, decoded)
+}
+
+func TestNegotiateXML_Error(t *testing.T) {
+	// Arrange
+	handler := NegotiateXML[v1.CalculateShippingRequest, v1.CalculateShippingResponse]("shipping_quote")(
+		ValidateBody[v1.CalculateShippingRequest](nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})))
+	req := httptest.NewRequest(http.MethodPost, "/calculate",
+		strings.NewReader(`<request><origin_zipcode>123</origin_zipcode><destination_zipcode>04547130</destination_zipcode><weight>1</weight>`+
+			`<dimensions><length>10</length><width>10</width><height>10</height></dimensions></request>`))
+	req.Header.Set("Content-Type", ContentTypeTextXML)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", rec.Header().Get("Content-Type"))
+	var response struct {
+		XMLName xml.Name `xml:"error_response"`
+		Error   string   `xml:"error"`
+		Field   string   `xml:"field"`
+		Fields  []struct {
+			Field string `xml:"field"`
+			Rule  string `xml:"rule"`
+		} `xml:"fields>item"`
+	}
+	assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "invalid origin_zipcode: origin_zipcode must be a valid zipcode format (4-8 digits)", response.Error)
+	assert.Equal(t, "origin_zipcode", response.Field)
+	if assert.Len(t, response.Fields, 1) {
+		assert.Equal(t, "zipcode", response.Fields[0].Rule)
+	}
+}
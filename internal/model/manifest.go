@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// Manifest statuses
+const (
+	// ManifestStatusGenerated is a manifest closed without a carrier manifest API to submit it to
+	ManifestStatusGenerated = "generated"
+	// ManifestStatusSubmitted is a manifest accepted by the carrier
+	ManifestStatusSubmitted = "submitted"
+	// ManifestStatusFailed is a manifest the carrier did not accept; its shipments are included in
+	// the next closing of the day
+	ManifestStatusFailed = "failed"
+)
+
+// CloseManifestsRequest asks for the end-of-day closing of the booked shipments
+type CloseManifestsRequest struct {
+	// Date is the booking day to close, as YYYY-MM-DD in UTC; empty closes today
+	Date string `json:"date,omitempty"`
+	// Carrier restricts the closing to a carrier; empty closes every carrier
+	Carrier string `json:"carrier,omitempty"`
+}
+
+// Manifest is the dispatch file of the shipments booked with a carrier on a day
+type Manifest struct {
+	ID      string `json:"id"`
+	Carrier string `json:"carrier"`
+	// Date is the booking day of the shipments, as YYYY-MM-DD in UTC
+	Date        string   `json:"date"`
+	Status      string   `json:"status"`
+	ShipmentIDs []string `json:"shipment_ids"`
+	// TotalWeight is the sum of the package weights, in kilograms
+	TotalWeight float64 `json:"total_weight"`
+	// CarrierManifestID identifies the manifest at the carrier once submitted
+	CarrierManifestID string `json:"carrier_manifest_id,omitempty"`
+	// Error is why the carrier did not accept a failed manifest
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+}
+
+// CloseManifestsResponse lists the manifests created by a closing
+type CloseManifestsResponse struct {
+	Date      string     `json:"date"`
+	Manifests []Manifest `json:"manifests"`
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+)
+
+// Shipment statuses
+const (
+	ShipmentStatusBooked = "booked"
+)
+
+// BookShipmentRequest converts a quote into a shipment
+type BookShipmentRequest struct {
+	QuoteID string `json:"quote_id"`
+	// Service is the quoted service level to ship with; empty uses the level selected in the quote
+	Service string `json:"service,omitempty"`
+}
+
+// Shipment is a booked shipment, priced by the quote it was booked from
+type Shipment struct {
+	ID      string `json:"id"`
+	QuoteID string `json:"quote_id"`
+	Status  string `json:"status"`
+	Service string `json:"service"`
+	// Tenant is the tenant the quote was priced for; empty for shipments booked before tenants
+	// were recorded
+	Tenant string `json:"tenant,omitempty"`
+	// Currency and Cost are the price of Service in the quote, in minor units
+	Currency              string       `json:"currency,omitempty"`
+	Cost                  money.Amount `json:"cost"`
+	EstimatedDeliveryTime string       `json:"estimated_delivery_time"`
+	PricingVersion        string       `json:"pricing_version,omitempty"`
+	// PromisedDate is the delivery date ("YYYY-MM-DD", UTC) promised by the quote for Service, the
+	// quote day plus its estimated days; empty when the quote did not estimate them
+	PromisedDate string          `json:"promised_date,omitempty"`
+	Package      ShipmentPackage `json:"package"`
+	BookedAt     time.Time       `json:"booked_at"`
+}
+
+// ShipmentPackage describes the shipped package and route, as quoted, so that the shipment can be
+// priced again after its quote expires
+type ShipmentPackage struct {
+	OriginZipcode      string            `json:"origin_zipcode"`
+	DestinationZipcode string            `json:"destination_zipcode"`
+	DestinationCountry string            `json:"destination_country,omitempty"`
+	Weight             float64           `json:"weight"`
+	Dimensions         PackageDimensions `json:"dimensions"`
+	PackageType        string            `json:"package_type,omitempty"`
+	DeliveryType       string            `json:"delivery_type,omitempty"`
+	AdditionalServices []string          `json:"additional_services,omitempty"`
+	IsReturn           bool              `json:"is_return,omitempty"`
+	FreightClass       string            `json:"freight_class,omitempty"`
+	PricingStrategy    string            `json:"pricing_strategy,omitempty"`
+	// WeightUnit and DimensionUnit are the units of Weight and Dimensions; empty for kg and cm
+	WeightUnit    string `json:"weight_unit,omitempty"`
+	DimensionUnit string `json:"dimension_unit,omitempty"`
+	// PickupDate ("YYYY-MM-DD") and PickupWindow are the scheduled pickup of the package, taking a
+	// slot of the window; empty when the package is not collected on a schedule
+	PickupDate   string `json:"pickup_date,omitempty"`
+	PickupWindow string `json:"pickup_window,omitempty"`
+}
+
+// Label formats
+const (
+	LabelFormatPDF = "pdf"
+	LabelFormatZPL = "zpl"
+)
+
+// GenerateLabelRequest asks for the shipping label of a shipment
+type GenerateLabelRequest struct {
+	// Format is pdf (default) or zpl, for thermal printers
+	Format string `json:"format,omitempty"`
+}
+
+// ShipmentLabel describes the shipping label generated by the carrier of a shipment
+type ShipmentLabel struct {
+	ShipmentID string `json:"shipment_id"`
+	Format     string `json:"format"`
+	// CarrierLabelID identifies the label at the carrier
+	CarrierLabelID string `json:"carrier_label_id,omitempty"`
+	TrackingCode   string `json:"tracking_code,omitempty"`
+	// Size is the size of the label document in bytes
+	Size int `json:"size"`
+	// DownloadURL is the path the label document is downloaded from
+	DownloadURL string    `json:"download_url"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -1,5 +1,28 @@
 package model
 
+import (
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+)
+
+// Service level names
+const (
+	ServiceStandard = "standard"
+	ServiceExpress  = "express"
+	// ServiceFreight ships heavy and oversized packages by freight class
+	ServiceFreight = "freight"
+)
+
+// Objectives of the optimize parameter, which orders the shipping options and selects the first
+const (
+	OptimizeCheapest = "cheapest"
+	OptimizeFastest  = "fastest"
+	// OptimizeBalanced weighs the cost and the delivery days of each option relative to the
+	// cheapest and the fastest
+	OptimizeBalanced = "balanced"
+)
+
 // CalculateShippingRequest represents the input for shipping calculation
 type CalculateShippingRequest struct {
 	OriginZipcode      string            `json:"origin_zipcode"`
@@ -7,36 +30,393 @@ type CalculateShippingRequest struct {
 	Weight             float64           `json:"weight"`
 	Dimensions         PackageDimensions `json:"dimensions"`
 	IsExpress          bool              `json:"is_express"`
+	// DestinationCountry is the ISO 3166-1 alpha-2 destination country (default: BR)
+	DestinationCountry string `json:"destination_country,omitempty"`
+	// Currency is the ISO 4217 quote currency; defaults to the currency of the destination country
+	Currency string `json:"currency,omitempty"`
+	// PackageType is standard, fragile, perishable or dangerous (default: standard)
+	PackageType string `json:"package_type,omitempty"`
+	// AdditionalServices are optional services charged on top of freight: cod, signature, saturday_delivery
+	AdditionalServices []string `json:"additional_services,omitempty"`
+	// DeliveryType is home, pickup_point or locker (default: home)
+	DeliveryType string `json:"delivery_type,omitempty"`
+	// PricingStrategy prices every service level with formula, table or carrier instead of the
+	// configured strategies
+	PricingStrategy string `json:"pricing_strategy,omitempty"`
+	// IsReturn quotes the return of the package, from the destination back to the origin, with
+	// the return adjustment and service levels of the pricing configuration
+	IsReturn bool `json:"is_return,omitempty"`
+	// PickupDate ("YYYY-MM-DD") and PickupWindow schedule the collection of the package by the
+	// carrier; the delivery estimate starts on the pickup date
+	PickupDate   string `json:"pickup_date,omitempty"`
+	PickupWindow string `json:"pickup_window,omitempty"`
+	// HSCode (Harmonized System code) and DeclaredValue (in minor units of the quote currency)
+	// describe the goods of international shipments, whose import duties and taxes are estimated
+	HSCode        string       `json:"hs_code,omitempty"`
+	DeclaredValue money.Amount `json:"declared_value,omitempty"`
+	// FreightClass is the NMFC-like freight class ("50" to "500") of heavy shipments, quoted as
+	// freight in addition to the parcel service levels
+	FreightClass string `json:"freight_class,omitempty"`
+	// WeightUnit (kg, g or lb; default kg) and DimensionUnit (cm, m or in; default cm) are the
+	// units of Weight and Dimensions, converted to kilograms and centimeters before validation
+	WeightUnit    string `json:"weight_unit,omitempty"`
+	DimensionUnit string `json:"dimension_unit,omitempty"`
+	// Optimize (cheapest, fastest or balanced) orders ShippingOptions by the objective and
+	// selects the first option instead of IsExpress
+	Optimize string `json:"optimize,omitempty"`
+	// AllowWeekendDelivery lets the service levels configured to deliver on Saturdays or Sundays
+	// count those days in their delivery estimate, charging their weekend surcharge
+	AllowWeekendDelivery bool `json:"allow_weekend_delivery,omitempty"`
+	// MissingFields lists the required fields absent from the request body ("weight",
+	// "dimensions" or "dimensions.length"), reported as required instead of as zero values
+	MissingFields []string `json:"-"`
 }
 
-// PackageDimensions represents package dimensions in centimeters
+// PackageDimensions represents package dimensions in centimeters, unless a request sets another
+// dimension unit
 type PackageDimensions struct {
 	Length float64 `json:"length"`
 	Width  float64 `json:"width"`
 	Height float64 `json:"height"`
 }
 
-// CalculateShippingResponse represents the output of shipping calculation
+// CalculateShippingResponse represents the output of shipping calculation. Costs are fixed-point
+// amounts in minor units of Currency, encoded in JSON as numbers
 type CalculateShippingResponse struct {
-	ShippingCost          float64          `json:"shipping_cost"`
+	QuoteID        string `json:"quote_id,omitempty"`
+	Currency       string `json:"currency,omitempty"`
+	PricingVersion string `json:"pricing_version,omitempty"`
+	// ExpiresAt is when the quoted price stops being honored; expired quotes must be revalidated
+	ExpiresAt             *time.Time       `json:"expires_at,omitempty"`
+	ShippingCost          money.Amount     `json:"shipping_cost"`
 	EstimatedDeliveryTime string           `json:"estimated_delivery_time"`
 	AvailableServices     []string         `json:"available_services"`
 	ShippingOptions       []ShippingOption `json:"shipping_options"`
+	Breakdown             *CostBreakdown   `json:"breakdown,omitempty"`
+	// DeliveryDate is the local date ("YYYY-MM-DD") at the destination of the delivery of the
+	// selected service
+	DeliveryDate string `json:"delivery_date,omitempty"`
+	// SelectedService is the service level of ShippingCost and Breakdown
+	SelectedService string `json:"selected_service,omitempty"`
+	// Experiment is set while a pricing experiment runs, tagging the arm that priced the quote
+	Experiment *ExperimentAssignment `json:"experiment,omitempty"`
+	// Package echoes the weight and dimensions priced, in kilograms and centimeters
+	Package *PackageMeasures `json:"package,omitempty"`
+	// FuelIndex is the fuel surcharge index the quote was priced with; nil without fuel surcharge
+	FuelIndex *FuelIndex `json:"fuel_index,omitempty"`
+	// Degraded is set when the quote was priced while the API shed load, with the formula
+	// strategy on every service level instead of the table or carrier rates
+	Degraded bool `json:"degraded,omitempty"`
+}
+
+// FuelIndex is the rate and effective date ("YYYY-MM-DD") of a fuel surcharge index
+type FuelIndex struct {
+	Rate          float64 `json:"rate"`
+	EffectiveDate string  `json:"effective_date"`
+}
+
+// PackageMeasures are the weight and dimensions of a package in canonical units
+type PackageMeasures struct {
+	WeightKg     float64           `json:"weight_kg"`
+	DimensionsCm PackageDimensions `json:"dimensions_cm"`
+}
+
+// QuoteRevalidation is the result of repricing a persisted quote
+type QuoteRevalidation struct {
+	// QuoteID identifies the quote repriced; Quote is saved as a new quote with its own ID
+	QuoteID string `json:"quote_id"`
+	// Expired reports whether the quote had expired when it was revalidated
+	Expired bool `json:"expired"`
+	// PriceChanged reports whether the current ShippingCost differs from PreviousCost
+	PriceChanged bool                       `json:"price_changed"`
+	PreviousCost money.Amount               `json:"previous_cost"`
+	Quote        *CalculateShippingResponse `json:"quote"`
+}
+
+// PriceSubscription is the quote of a route and package followed for price changes: Version
+// increases every time the price of Quote changes, and the subscription is dropped after ExpiresAt
+// unless polled
+type PriceSubscription struct {
+	ID        string                     `json:"subscription_id"`
+	Version   int                        `json:"version"`
+	ExpiresAt time.Time                  `json:"expires_at"`
+	Quote     *CalculateShippingResponse `json:"quote"`
+}
+
+// QuotePreview is a quote priced as a dry run, with the decisions taken to price it. It is not
+// persisted and cannot be booked
+type QuotePreview struct {
+	Quote *CalculateShippingResponse `json:"quote"`
+	Trace []DecisionStep             `json:"trace"`
+}
+
+// DecisionStep is a rule applied while pricing a quote: Step names the decision, e.g. strategy or
+// price_limit, and Detail describes its outcome
+type DecisionStep struct {
+	Step   string `json:"step"`
+	Detail string `json:"detail"`
+}
+
+// QuoteExplanation explains step by step how the selected service of a quote was priced, to
+// answer customers asking why a shipment costs what it does. Like QuotePreview, it is a dry run
+type QuoteExplanation struct {
+	Quote  *CalculateShippingResponse `json:"quote"`
+	Inputs ExplainedInputs            `json:"inputs"`
+	Route  ExplainedRoute             `json:"route"`
+	// Service is the service level explained: the one requested, or freight for freight-only quotes
+	Service  string `json:"service"`
+	Strategy string `json:"strategy"`
+	// Charges add up, in order, to the cost of the selected service; discounts are negative
+	Charges   []ExplainedCharge `json:"charges"`
+	Decisions []DecisionStep    `json:"decisions"`
+}
+
+// ExplainedInputs are the request values as used by the calculation, after normalization and
+// defaults
+type ExplainedInputs struct {
+	OriginZipcode      string  `json:"origin_zipcode"`
+	DestinationZipcode string  `json:"destination_zipcode"`
+	DestinationCountry string  `json:"destination_country"`
+	Currency           string  `json:"currency"`
+	WeightKg           float64 `json:"weight_kg"`
+	VolumeCm3          float64 `json:"volume_cm3"`
+	PackageType        string  `json:"package_type"`
+	DeliveryType       string  `json:"delivery_type"`
+	IsExpress          bool    `json:"is_express"`
+	IsReturn           bool    `json:"is_return"`
+}
+
+// ExplainedRoute is the route priced; returns travel from the destination back to the origin.
+// Distance is the zipcode distance of the formula strategy, 0 when it cannot be computed
+type ExplainedRoute struct {
+	OriginZipcode      string  `json:"origin_zipcode"`
+	DestinationZipcode string  `json:"destination_zipcode"`
+	Zone               string  `json:"zone"`
+	Distance           float64 `json:"distance"`
+}
+
+// ExplainedCharge is a line of the cost with the formula and parameters that produced it
+type ExplainedCharge struct {
+	Name       string             `json:"name"`
+	Amount     money.Amount       `json:"amount"`
+	Formula    string             `json:"formula"`
+	Parameters map[string]float64 `json:"parameters,omitempty"`
+}
+
+// QuoteCreated is the data of the quote.created event: the package and route quoted and the
+// prices offered, without the sensitive fields of the quote
+type QuoteCreated struct {
+	Request  CalculateShippingRequest  `json:"request"`
+	Response CalculateShippingResponse `json:"response"`
+}
+
+// ExperimentAssignment identifies the pricing experiment arm a quote was assigned to
+type ExperimentAssignment struct {
+	Name string `json:"name"`
+	Arm  string `json:"arm"`
 }
 
 // ShippingOption represents a shipping service option
 type ShippingOption struct {
-	Service string  `json:"service"`
-	Cost    float64 `json:"cost"`
-	Time    string  `json:"time"`
+	Service string       `json:"service"`
+	Cost    money.Amount `json:"cost"`
+	Time    string       `json:"time"`
+	// EstimatedDays are the delivery days of Time
+	EstimatedDays int `json:"estimated_days"`
+	// DeliveryDate is the local date ("YYYY-MM-DD") at the destination EstimatedDays after today
+	// at the origin
+	DeliveryDate string `json:"delivery_date,omitempty"`
+	// WeekendDelivery marks the options that deliver on weekends, as the request allows, with
+	// their weekend surcharge included in Cost
+	WeekendDelivery bool `json:"weekend_delivery,omitempty"`
+	// Cheapest and Fastest mark the options with the lowest cost and the fewest delivery days;
+	// several options are marked on a tie
+	Cheapest bool `json:"cheapest,omitempty"`
+	Fastest  bool `json:"fastest,omitempty"`
+	// Token is the signed quote token of the option, set on persisted quotes when QUOTE_TOKEN_KEYS
+	// is configured
+	Token string `json:"token,omitempty"`
+}
+
+// CostBreakdown itemizes the cost of the selected service; Total equals ShippingCost after rounding
+type CostBreakdown struct {
+	BaseCost               money.Amount `json:"base_cost"`
+	WeightSurcharge        money.Amount `json:"weight_surcharge"`
+	VolumeSurcharge        money.Amount `json:"volume_surcharge"`
+	PackageTypeSurcharge   money.Amount `json:"package_type_surcharge"`
+	DeliveryTypeAdjustment money.Amount `json:"delivery_type_adjustment"`
+	ExpressSurcharge       money.Amount `json:"express_surcharge"`
+	// ReturnAdjustment is the return discount (negative) or surcharge (positive) of return quotes
+	ReturnAdjustment money.Amount `json:"return_adjustment,omitempty"`
+	// PickupSurcharge is charged for scheduled pickups in premium windows or for the same day
+	PickupSurcharge money.Amount `json:"pickup_surcharge,omitempty"`
+	// RestrictedArea names the restricted area of the destination, charged RestrictedAreaSurcharge
+	RestrictedArea          string       `json:"restricted_area,omitempty"`
+	RestrictedAreaSurcharge money.Amount `json:"restricted_area_surcharge,omitempty"`
+	// RemoteAreas names the remote areas of the destination, charged RemoteAreaSurcharge together
+	RemoteAreas         []string     `json:"remote_areas,omitempty"`
+	RemoteAreaSurcharge money.Amount `json:"remote_area_surcharge,omitempty"`
+	// FuelSurcharge is the fuel surcharge of the index in FuelIndex of the response
+	FuelSurcharge money.Amount `json:"fuel_surcharge,omitempty"`
+	// WeekendSurcharge is charged when the selected service delivers on weekends
+	WeekendSurcharge money.Amount `json:"weekend_surcharge,omitempty"`
+	// PriceLimit is "floor" or "ceiling" when the freight was clamped to a configured price
+	// limit, and PriceLimitAdjustment the amount added (positive) or removed (negative) to reach it
+	PriceLimit           string       `json:"price_limit,omitempty"`
+	PriceLimitAdjustment money.Amount `json:"price_limit_adjustment,omitempty"`
+	AdditionalServices   []ServiceFee `json:"additional_services,omitempty"`
+	// UnroundedTotal is the cost before the rounding policy of the currency, and
+	// RoundingAdjustment the difference the rounding made to reach Total
+	UnroundedTotal     money.Amount `json:"unrounded_total"`
+	RoundingAdjustment money.Amount `json:"rounding_adjustment,omitempty"`
+	Total              money.Amount `json:"total"`
+	// Duties are the estimated import duty and tax of international shipments, paid at the
+	// destination and not included in Total; LandedCost is the declared value plus Total and Duties
+	Duties     []DutyCharge `json:"duties,omitempty"`
+	LandedCost money.Amount `json:"landed_cost,omitempty"`
+	// Tax is the ICMS or ISS included in Total for domestic routes, set when taxes are enabled
+	Tax *FreightTax `json:"tax,omitempty"`
+}
+
+// FreightTax is the ICMS or ISS included in the freight of a route: Gross is the freight charged,
+// Amount the tax it includes at Rate and Net the freight without the tax
+type FreightTax struct {
+	Tax              string  `json:"tax"`
+	Rate             float64 `json:"rate"`
+	OriginState      string  `json:"origin_state"`
+	DestinationState string  `json:"destination_state"`
+	// Municipality is the municipality of routes paying ISS
+	Municipality string       `json:"municipality,omitempty"`
+	Gross        money.Amount `json:"gross"`
+	Amount       money.Amount `json:"amount"`
+	Net          money.Amount `json:"net"`
+}
+
+// Duty charges of the estimate of an international shipment
+const (
+	DutyImportDuty = "import_duty"
+	DutyImportTax  = "import_tax"
+)
+
+// DutyCharge is an estimated import duty or tax and the rate it was charged at
+type DutyCharge struct {
+	Name   string       `json:"name"`
+	Rate   float64      `json:"rate"`
+	Amount money.Amount `json:"amount"`
+}
+
+// ServiceFee is the fee charged for an additional service
+type ServiceFee struct {
+	Service string       `json:"service"`
+	Fee     money.Amount `json:"fee"`
 }
 
 // ShippingCalculationDetails holds internal calculation details
 type ShippingCalculationDetails struct {
-	BaseCost         float64
-	WeightSurcharge  float64
-	VolumeSurcharge  float64
-	ExpressSurcharge float64
-	TotalCost        float64
-	EstimatedDays    int
+	BaseCost               money.Amount
+	WeightSurcharge        money.Amount
+	VolumeSurcharge        money.Amount
+	PackageTypeSurcharge   money.Amount
+	DeliveryTypeAdjustment money.Amount
+	ExpressSurcharge       money.Amount
+	ReturnAdjustment       money.Amount
+	PickupSurcharge        money.Amount
+	RestrictedArea         string
+	AreaSurcharge          money.Amount
+	RemoteAreas            []string
+	RemoteSurcharge        money.Amount
+	WeekendDelivery        bool
+	WeekendSurcharge       money.Amount
+	FuelSurcharge          money.Amount
+	PriceLimit             string
+	PriceLimitAdjustment   money.Amount
+	TotalCost              money.Amount
+	EstimatedDays          int
+	HandlingDays           int
+	StandardDays           int
+	ExpressDays            int
+	ExpressProhibited      bool
+	AdditionalServices     []ServiceFee
+}
+
+// ServiceabilityRequest identifies a route whose available service levels are checked
+type ServiceabilityRequest struct {
+	OriginZipcode      string
+	DestinationZipcode string
+	DestinationCountry string
+	PackageType        string
+}
+
+// ServiceabilityResponse lists the service levels available for a route, without prices
+type ServiceabilityResponse struct {
+	OriginZipcode      string                `json:"origin_zipcode"`
+	DestinationZipcode string                `json:"destination_zipcode"`
+	Serviceable        bool                  `json:"serviceable"`
+	Services           []ServiceAvailability `json:"services"`
+	// Restrictions explain why the route is not serviceable
+	Restrictions []string `json:"restrictions,omitempty"`
+}
+
+// ServiceAvailability describes a service level on a route and its lead time
+type ServiceAvailability struct {
+	Service               string `json:"service"`
+	Available             bool   `json:"available"`
+	EstimatedDays         int    `json:"estimated_days"`
+	EstimatedDeliveryTime string `json:"estimated_delivery_time"`
+	Restriction           string `json:"restriction,omitempty"`
+}
+
+// ServiceCapabilities describes the limits and features of the service so clients can self-configure
+type ServiceCapabilities struct {
+	SupportedCountries  []string `json:"supported_countries"`
+	SupportedCurrencies []string `json:"supported_currencies"`
+	PackageTypes        []string `json:"package_types"`
+	AdditionalServices  []string `json:"additional_services"`
+	DeliveryTypes       []string `json:"delivery_types"`
+	PricingVersion      string   `json:"pricing_version"`
+	PricingStrategies   []string `json:"pricing_strategies"`
+	Units               Units    `json:"units"`
+	Limits              Limits   `json:"limits"`
+	AvailableServices   []string `json:"available_services"`
+	APIVersions         []string `json:"api_versions"`
+	// RateLimit describes the request limits of the quote routes for the tenant of the request
+	RateLimit *RateLimitInfo `json:"rate_limit,omitempty"`
+}
+
+// Units describes the units of measure used by the API: Weight and Dimensions are the default
+// units, WeightUnits and DimensionUnits the values accepted in weight_unit and dimension_unit, and
+// Currency the default quote currency
+type Units struct {
+	Weight         string   `json:"weight"`
+	Dimensions     string   `json:"dimensions"`
+	WeightUnits    []string `json:"weight_units"`
+	DimensionUnits []string `json:"dimension_units"`
+	Currency       string   `json:"currency"`
+	CostUnit       string   `json:"cost_unit"`
+}
+
+// Limits describes the input limits enforced by validation
+type Limits struct {
+	MinWeightExclusive float64 `json:"min_weight_exclusive"`
+	MaxVolumeCm3       float64 `json:"max_volume_cm3"`
+	ZipcodeMinDigits   int     `json:"zipcode_min_digits"`
+	ZipcodeMaxDigits   int     `json:"zipcode_max_digits"`
+	// MaxWeightKg is the heaviest package priced by the table strategy in the default currency,
+	// the last bracket of its weight table; omitted without a weight table, as the formula and
+	// carrier strategies price any weight
+	MaxWeightKg float64 `json:"max_weight_kg,omitempty"`
+	// MaxFreightWeightKg is the heaviest shipment quoted as freight in the default currency;
+	// omitted when freight is not offered
+	MaxFreightWeightKg float64 `json:"max_freight_weight_kg,omitempty"`
+}
+
+// RateLimitInfo describes the request limits of the quote routes; a zero limit is not enforced
+type RateLimitInfo struct {
+	// MonthlyQuota is how many quote requests the tenant can make per calendar month (UTC)
+	MonthlyQuota int64 `json:"monthly_quota"`
+	// MaxInFlight is the number of quote requests in flight, across all clients, above which
+	// requests are rejected with 503
+	MaxInFlight int `json:"max_in_flight"`
+	// RetryAfterSeconds is the wait suggested to the requests rejected for overload
+	RetryAfterSeconds int `json:"retry_after_seconds"`
 }
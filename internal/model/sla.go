@@ -0,0 +1,35 @@
+package model
+
+// SLAStats summarizes how many delivered shipments met the delivery date promised by their quote
+type SLAStats struct {
+	Delivered int `json:"delivered"`
+	OnTime    int `json:"on_time"`
+	Late      int `json:"late"`
+	// Attainment is the share of the deliveries made on time, from 0 to 1
+	Attainment float64 `json:"attainment"`
+	// AverageDelayDays is the average delay of the late deliveries, in days
+	AverageDelayDays float64 `json:"average_delay_days"`
+}
+
+// SLARoute is the SLA attainment of a carrier on a route
+type SLARoute struct {
+	Carrier string `json:"carrier"`
+	// Origin is the origin state, e.g. "SP"
+	Origin string `json:"origin"`
+	// Destination is the destination state of Brazilian shipments and the destination country of
+	// international ones, e.g. "RJ" or "US"
+	Destination string `json:"destination"`
+	SLAStats
+}
+
+// SLAReport is the SLA attainment of the shipments delivered in a period, overall and by carrier
+// and route
+type SLAReport struct {
+	From    string     `json:"from"`
+	To      string     `json:"to"`
+	Overall SLAStats   `json:"overall"`
+	Routes  []SLARoute `json:"routes"`
+	// Unmeasured counts the delivered shipments without a promised date, e.g. because their quote
+	// is no longer stored
+	Unmeasured int `json:"unmeasured"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Tracking statuses reported by carriers, in the order a shipment goes through them
+const (
+	TrackingStatusPosted    = "posted"
+	TrackingStatusInTransit = "in_transit"
+	TrackingStatusDelivered = "delivered"
+)
+
+// Tracking statuses of delivery exceptions: a failed delivery attempt, and a shipment returned to
+// the sender
+const (
+	TrackingStatusDeliveryFailed = "delivery_failed"
+	TrackingStatusReturned       = "returned"
+)
+
+// TrackingStatuses lists the supported tracking statuses
+var TrackingStatuses = []string{TrackingStatusPosted, TrackingStatusInTransit, TrackingStatusDelivered, TrackingStatusDeliveryFailed, TrackingStatusReturned}
+
+// TrackingExceptionStatuses lists the tracking statuses of delivery exceptions
+var TrackingExceptionStatuses = []string{TrackingStatusDeliveryFailed, TrackingStatusReturned}
+
+// TrackingEvent is a status update of a shipment reported by the carrier
+type TrackingEvent struct {
+	Status      string    `json:"status"`
+	Description string    `json:"description,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// ShipmentTracking is the tracking history of a shipment
+type ShipmentTracking struct {
+	ShipmentID string `json:"shipment_id"`
+	// Status is the status of the latest event, or booked while the carrier reported none
+	Status string `json:"status"`
+	// Events are ordered by OccurredAt, oldest first
+	Events []TrackingEvent `json:"events"`
+}
@@ -0,0 +1,13 @@
+package model
+
+// DayLayout is the format of the days of usage records, e.g. "2025-03-10"
+const DayLayout = "2006-01-02"
+
+// UsageRecord is the number of quote requests of a tenant, with one of its API keys, on a day (UTC)
+type UsageRecord struct {
+	Day    string `json:"day"`
+	Tenant string `json:"tenant"`
+	// APIKey is the tenant.KeyID of the key; empty for requests without a key of the tenant
+	APIKey string `json:"api_key,omitempty"`
+	Quotes int64  `json:"quotes"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreateWebhookSubscriptionRequest registers a merchant URL for the events of its tenant
+type CreateWebhookSubscriptionRequest struct {
+	URL string `json:"url"`
+	// Events are the event types to notify; empty notifies every supported type
+	Events []string `json:"events,omitempty"`
+	// Secret signs the callbacks; empty generates one, returned only on creation
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookSubscription is a merchant URL notified of the events of a tenant
+type WebhookSubscription struct {
+	ID     string   `json:"id"`
+	Tenant string   `json:"tenant"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	// Secret signs the callbacks; it is only returned when the subscription is created
+	Secret string `json:"secret,omitempty"`
+	// EncryptedSecret is the Secret encrypted at rest by repository.EncryptedWebhookRepository;
+	// it is never returned
+	EncryptedSecret string    `json:"encrypted_secret,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// WebhookSubscriptionsResponse lists the webhook subscriptions of a tenant
+type WebhookSubscriptionsResponse struct {
+	Subscriptions []WebhookSubscription `json:"subscriptions"`
+}
+
+// WebhookDeadLetter is an event that could not be delivered to a webhook subscription
+type WebhookDeadLetter struct {
+	ID             string `json:"id"`
+	SubscriptionID string `json:"subscription_id"`
+	Tenant         string `json:"tenant"`
+	URL            string `json:"url"`
+	EventID        string `json:"event_id"`
+	EventType      string `json:"event_type"`
+	// Event is the undelivered callback body
+	Event    json.RawMessage `json:"event"`
+	Attempts int             `json:"attempts"`
+	// Error is the failure of the last attempt
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// WebhookDeadLettersResponse lists undelivered webhook events, the most recent first
+type WebhookDeadLettersResponse struct {
+	DeadLetters []WebhookDeadLetter `json:"dead_letters"`
+}
@@ -0,0 +1,135 @@
+// Package money represents amounts of money as fixed-point integers, so surcharges and totals
+// add up exactly instead of accumulating floating point drift.
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of Amount units in a minor currency unit: amounts keep four decimal places
+// of a cent, enough for percentage surcharges on fractional costs
+const Scale = 10000
+
+// scaleDigits is the number of decimal places of a minor unit kept by Scale
+const scaleDigits = 4
+
+// Amount is an amount of money in 1/Scale of the minor unit (e.g. cents) of its currency. It is
+// encoded in JSON as a number of minor units, e.g. 1437.5
+type Amount int64
+
+// MaxAmount bounds the amounts converted from floating point, far above any price yet low enough
+// that adding up the charges of a quote cannot overflow
+const MaxAmount = Amount(math.MaxInt64 >> 8)
+
+// FromMinor converts a number of minor units to an Amount, rounding half away from zero to the
+// nearest 1/Scale of a minor unit
+func FromMinor(minor float64) Amount {
+	return fromFloat(math.Round(minor * Scale))
+}
+
+// Minor returns the amount in minor units
+func (a Amount) Minor() float64 {
+	return float64(a) / Scale
+}
+
+// MulRate multiplies the amount by a rate (e.g. 0.15 for a 15% surcharge), rounding the result
+// half away from zero to the nearest 1/Scale of a minor unit
+func (a Amount) MulRate(rate float64) Amount {
+	return fromFloat(math.Round(float64(a) * rate))
+}
+
+// fromFloat converts a whole number of Amount units, saturating at ±MaxAmount; NaN, which no
+// input should produce, converts to 0 instead of an arbitrary amount
+func fromFloat(units float64) Amount {
+	switch {
+	case math.IsNaN(units):
+		return 0
+	case units >= float64(MaxAmount):
+		return MaxAmount
+	case units <= -float64(MaxAmount):
+		return -MaxAmount
+	}
+	return Amount(units)
+}
+
+// RoundHalfUp rounds to the nearest multiple of increment, halves away from zero; a non-positive
+// increment leaves the amount unchanged
+func (a Amount) RoundHalfUp(increment Amount) Amount {
+	return a.round(increment, func(q, r Amount) bool { return 2*r >= increment })
+}
+
+// RoundHalfEven rounds to the nearest multiple of increment, halves to the even multiple
+// (banker's rounding); a non-positive increment leaves the amount unchanged
+func (a Amount) RoundHalfEven(increment Amount) Amount {
+	return a.round(increment, func(q, r Amount) bool { return 2*r > increment || (2*r == increment && q%2 != 0) })
+}
+
+// RoundUp rounds up to the next multiple of increment; a non-positive increment leaves the amount unchanged
+func (a Amount) RoundUp(increment Amount) Amount {
+	if increment <= 0 {
+		return a
+	}
+	q, r := a/increment, a%increment
+	if r > 0 {
+		q++
+	}
+	return q * increment
+}
+
+// round rounds the magnitude of the amount to a multiple of increment, moving away from zero when
+// away reports so for the truncated quotient q and the remainder r
+func (a Amount) round(increment Amount, away func(q, r Amount) bool) Amount {
+	if increment <= 0 {
+		return a
+	}
+	sign := Amount(1)
+	if a < 0 {
+		sign, a = -1, -a
+	}
+	q, r := a/increment, a%increment
+	if r != 0 && away(q, r) {
+		q++
+	}
+	return sign * q * increment
+}
+
+// String formats the amount in minor units with up to four decimal places, e.g. "1437.5", using
+// integer arithmetic only so no amount is ever printed with floating point artifacts
+func (a Amount) String() string {
+	sign := ""
+	if a < 0 {
+		sign, a = "-", -a
+	}
+	whole, frac, digits := int64(a/Scale), int64(a%Scale), scaleDigits
+	if frac == 0 {
+		return sign + strconv.FormatInt(whole, 10)
+	}
+	for frac%10 == 0 {
+		frac /= 10
+		digits--
+	}
+	decimals := strconv.FormatInt(frac, 10)
+	return sign + strconv.FormatInt(whole, 10) + "." + strings.Repeat("0", digits-len(decimals)) + decimals
+}
+
+// MarshalJSON encodes the amount as a number of minor units
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON decodes a number of minor units
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var minor float64
+	if err := json.Unmarshal(data, &minor); err != nil {
+		return fmt.Errorf("invalid amount %s: %w", data, err)
+	}
+	*a = FromMinor(minor)
+	return nil
+}
@@ -0,0 +1,153 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromMinor(t *testing.T) {
+	tests := []struct {
+		name  string
+		minor float64
+		want  Amount
+	}{
+		{"whole cents", 1250, 12500000},
+		{"fractional cents", 1437.5, 14375000},
+		{"float artifacts are dropped", 1112.0000000002, 11120000},
+		{"negative", -250.25, -2502500},
+		{"NaN", math.NaN(), 0},
+		{"infinity saturates", math.Inf(1), MaxAmount},
+		{"negative infinity saturates", math.Inf(-1), -MaxAmount},
+		{"overflow saturates", 1e300, MaxAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.want, FromMinor(tt.minor))
+		})
+	}
+}
+
+func TestAmount_MulRate(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount Amount
+		rate   float64
+		want   Amount
+	}{
+		{"ten percent", FromMinor(1000), 0.10, FromMinor(100)},
+		{"fifteen percent of fractional cents", FromMinor(1250), 0.15, FromMinor(187.5)},
+		{"negative rate", FromMinor(1000), -0.20, FromMinor(-200)},
+		{"repeated surcharges stay exact", FromMinor(0.1), 3, FromMinor(0.3)},
+		{"NaN rate", FromMinor(1000), math.NaN(), 0},
+		{"overflow saturates", FromMinor(1000), 1e300, MaxAmount},
+		{"the maximum stays the maximum", MaxAmount, 1, MaxAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.want, tt.amount.MulRate(tt.rate))
+		})
+	}
+}
+
+func TestAmount_Round(t *testing.T) {
+	tests := []struct {
+		name      string
+		round     func(Amount, Amount) Amount
+		amount    float64
+		increment float64
+		want      float64
+	}{
+		{"half up below half", Amount.RoundHalfUp, 1232.4, 5, 1230},
+		{"half up at half", Amount.RoundHalfUp, 1232.5, 5, 1235},
+		{"half up negative", Amount.RoundHalfUp, -1232.5, 5, -1235},
+		{"half even to even multiple", Amount.RoundHalfEven, 1250, 100, 1200},
+		{"half even from odd multiple", Amount.RoundHalfEven, 1350, 100, 1400},
+		{"half even above half", Amount.RoundHalfEven, 1251, 100, 1300},
+		{"up", Amount.RoundUp, 1201, 100, 1300},
+		{"up exact multiple", Amount.RoundUp, 1200, 100, 1200},
+		{"up keeps a cost without float artifacts", Amount.RoundUp, 1112.0000000002, 1, 1112},
+		{"zero increment disables rounding", Amount.RoundHalfUp, 1234.5678, 0, 1234.5678},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := tt.round(FromMinor(tt.amount), FromMinor(tt.increment))
+
+			// Assert
+			assert.Equal(t, FromMinor(tt.want), got)
+		})
+	}
+}
+
+func TestAmount_JSON(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		json   string
+	}{
+		{FromMinor(1250), "1250"},
+		{FromMinor(1437.5), "1437.5"},
+		{FromMinor(2156.25), "2156.25"},
+		{FromMinor(0.0001), "0.0001"},
+		{FromMinor(-0.5), "-0.5"},
+		{0, "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			// Act
+			data, err := json.Marshal(tt.amount)
+			var decoded Amount
+			decodeErr := json.Unmarshal(data, &decoded)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.NoError(t, decodeErr)
+			assert.Equal(t, tt.json, string(data))
+			assert.Equal(t, tt.amount, decoded)
+		})
+	}
+}
+
+func TestAmount_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount Amount
+		want   string
+	}{
+		{"whole", 12500000, "1250"},
+		{"leading zero decimals", 12500101, "1250.0101"},
+		{"trailing zero decimals", 12501000, "1250.1"},
+		{"negative fraction", -1, "-0.0001"},
+		{"beyond float precision", 90071992547409931, "9007199254740.9931"},
+		{"max amount", MaxAmount, "3602879701896.3967"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := tt.amount.String()
+
+			// Assert
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAmount_UnmarshalJSON_Invalid(t *testing.T) {
+	// Arrange
+	var amount Amount
+
+	// Act
+	err := json.Unmarshal([]byte(`"ten"`), &amount)
+
+	// Assert
+	assert.ErrorContains(t, err, "invalid amount")
+}
@@ -0,0 +1,424 @@
+// Package msgpack encodes and decodes the subset of MessagePack (https://msgpack.org) used by the
+// batch endpoint: nil, booleans, integers, floats, strings, binary, arrays and maps. Values decode
+// to nil, bool, int64, uint64, float64, string, []byte, []any and map[string]any; extension types
+// and maps with non-string keys are rejected. Arrays and maps can be read and written one element
+// at a time, so that large batches are streamed instead of held in memory.
+package msgpack
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// maxPrealloc bounds the capacity allocated up front for a declared array, map, string or binary
+// length, so that a forged length cannot exhaust memory before the data is read
+const maxPrealloc = 4096
+
+// maxDepth bounds the nesting of arrays and maps, so that a forged value cannot exhaust the stack
+const maxDepth = 64
+
+// ErrType is returned when the next value is not of the type expected
+var ErrType = errors.New("msgpack: unexpected type")
+
+// Decoder reads MessagePack values from a stream
+type Decoder struct {
+	r     *bufio.Reader
+	depth int
+}
+
+// NewDecoder creates a decoder reading from r
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// DecodeArrayLen reads the header of an array and returns its number of elements, which are
+// then read with Decode
+func (d *Decoder) DecodeArrayLen() (int, error) {
+	code, err := d.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case code&0xf0 == 0x90:
+		return int(code & 0x0f), nil
+	case code == 0xdc:
+		return d.readLen(2)
+	case code == 0xdd:
+		return d.readLen(4)
+	}
+	return 0, fmt.Errorf("%w: 0x%02x is not an array", ErrType, code)
+}
+
+// Decode reads the next value
+func (d *Decoder) Decode() (any, error) {
+	code, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case code <= 0x7f:
+		return int64(code), nil
+	case code >= 0xe0:
+		return int64(int8(code)), nil
+	case code&0xf0 == 0x80:
+		return d.decodeMap(int(code & 0x0f))
+	case code&0xf0 == 0x90:
+		return d.decodeArray(int(code & 0x0f))
+	case code&0xe0 == 0xa0:
+		return d.decodeString(int(code & 0x1f))
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.readLen(1 << (code - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return d.readBytes(n)
+	case 0xca:
+		b, err := d.readN(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 0xcb:
+		b, err := d.readN(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		b, err := d.readN(1 << (code - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		value := readUint(b)
+		if value <= math.MaxInt64 {
+			return int64(value), nil
+		}
+		return value, nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		b, err := d.readN(1 << (code - 0xd0))
+		if err != nil {
+			return nil, err
+		}
+		return readInt(b), nil
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.readLen(1 << (code - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeString(n)
+	case 0xdc, 0xdd:
+		n, err := d.readLen(2 << (code - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(n)
+	case 0xde, 0xdf:
+		n, err := d.readLen(2 << (code - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(n)
+	}
+	return nil, fmt.Errorf("%w: 0x%02x is not supported", ErrType, code)
+}
+
+func (d *Decoder) decodeArray(n int) ([]any, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+	values := make([]any, 0, min(n, maxPrealloc))
+	for range n {
+		value, err := d.Decode()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func (d *Decoder) decodeMap(n int) (map[string]any, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+	values := make(map[string]any, min(n, maxPrealloc))
+	for range n {
+		key, err := d.Decode()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		name, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: map key %T is not a string", ErrType, key)
+		}
+		if values[name], err = d.Decode(); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+	}
+	return values, nil
+}
+
+// enter descends into an array or map, failing beyond maxDepth
+func (d *Decoder) enter() error {
+	if d.depth++; d.depth > maxDepth {
+		return fmt.Errorf("msgpack: nesting deeper than %d", maxDepth)
+	}
+	return nil
+}
+
+func (d *Decoder) leave() {
+	d.depth--
+}
+
+func (d *Decoder) decodeString(n int) (string, error) {
+	b, err := d.readBytes(n)
+	return string(b), err
+}
+
+// readLen reads a big-endian length of size bytes
+func (d *Decoder) readLen(size int) (int, error) {
+	b, err := d.readN(size)
+	if err != nil {
+		return 0, err
+	}
+	n := readUint(b)
+	if n > math.MaxInt32 {
+		return 0, fmt.Errorf("msgpack: length %d is too large", n)
+	}
+	return int(n), nil
+}
+
+// readBytes reads n bytes, growing the buffer as the data arrives
+func (d *Decoder) readBytes(n int) ([]byte, error) {
+	b := make([]byte, 0, min(n, maxPrealloc))
+	for len(b) < n {
+		chunk := min(n-len(b), maxPrealloc)
+		start := len(b)
+		b = append(b, make([]byte, chunk)...)
+		if _, err := io.ReadFull(d.r, b[start:]); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+	}
+	return b, nil
+}
+
+// readN reads the n bytes of a fixed-size value
+func (d *Decoder) readN(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return b, nil
+}
+
+// unexpectedEOF reports a value cut short as io.ErrUnexpectedEOF, keeping io.EOF for the end of
+// the stream between values
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func readUint(b []byte) uint64 {
+	var value uint64
+	for _, c := range b {
+		value = value<<8 | uint64(c)
+	}
+	return value
+}
+
+func readInt(b []byte) int64 {
+	value := int64(int8(b[0]))
+	for _, c := range b[1:] {
+		value = value<<8 | int64(c)
+	}
+	return value
+}
+
+// Encoder writes MessagePack values to a stream; call Flush after the last value
+type Encoder struct {
+	w   *bufio.Writer
+	buf [9]byte
+}
+
+// NewEncoder creates an encoder writing to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w)}
+}
+
+// Flush writes the buffered values to the underlying writer
+func (e *Encoder) Flush() error {
+	return e.w.Flush()
+}
+
+// EncodeArrayLen writes the header of an array of n elements, which are then written with Encode
+func (e *Encoder) EncodeArrayLen(n int) error {
+	return e.writeLen(n, 0x90, 0x0f, 0xdc)
+}
+
+// EncodeMapLen writes the header of a map of n pairs, whose keys and values are then written with
+// Encode
+func (e *Encoder) EncodeMapLen(n int) error {
+	return e.writeLen(n, 0x80, 0x0f, 0xde)
+}
+
+// Encode writes v: nil, a boolean, an integer, a float, a string, a []byte, or a slice or a map
+// with string keys of those. Map keys are written in sorted order
+func (e *Encoder) Encode(v any) error {
+	switch v := v.(type) {
+	case nil:
+		return e.w.WriteByte(0xc0)
+	case bool:
+		if v {
+			return e.w.WriteByte(0xc3)
+		}
+		return e.w.WriteByte(0xc2)
+	case string:
+		if err := e.writeLen(len(v), 0xa0, 0x1f, 0xd9); err != nil {
+			return err
+		}
+		_, err := e.w.WriteString(v)
+		return err
+	case []byte:
+		if err := e.writeLen(len(v), 0, 0, 0xc4); err != nil {
+			return err
+		}
+		_, err := e.w.Write(v)
+		return err
+	case float64:
+		e.buf[0] = 0xcb
+		binary.BigEndian.PutUint64(e.buf[1:], math.Float64bits(v))
+		_, err := e.w.Write(e.buf[:9])
+		return err
+	case float32:
+		e.buf[0] = 0xca
+		binary.BigEndian.PutUint32(e.buf[1:], math.Float32bits(v))
+		_, err := e.w.Write(e.buf[:5])
+		return err
+	}
+
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return e.encodeInt(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return e.encodeUint(value.Uint())
+	case reflect.Slice, reflect.Array:
+		if err := e.EncodeArrayLen(value.Len()); err != nil {
+			return err
+		}
+		for i := range value.Len() {
+			if err := e.Encode(value.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			break
+		}
+		keys := make([]string, 0, value.Len())
+		for _, key := range value.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		if err := e.EncodeMapLen(len(keys)); err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := e.Encode(key); err != nil {
+				return err
+			}
+			if err := e.Encode(value.MapIndex(reflect.ValueOf(key).Convert(value.Type().Key())).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: cannot encode %T", ErrType, v)
+}
+
+func (e *Encoder) encodeInt(v int64) error {
+	switch {
+	case v >= 0:
+		return e.encodeUint(uint64(v))
+	case v >= -32:
+		return e.w.WriteByte(byte(int8(v)))
+	case v >= math.MinInt8:
+		return e.writeFixed(0xd0, uint64(uint8(v)), 1)
+	case v >= math.MinInt16:
+		return e.writeFixed(0xd1, uint64(uint16(v)), 2)
+	case v >= math.MinInt32:
+		return e.writeFixed(0xd2, uint64(uint32(v)), 4)
+	}
+	return e.writeFixed(0xd3, uint64(v), 8)
+}
+
+func (e *Encoder) encodeUint(v uint64) error {
+	switch {
+	case v <= 0x7f:
+		return e.w.WriteByte(byte(v))
+	case v <= math.MaxUint8:
+		return e.writeFixed(0xcc, v, 1)
+	case v <= math.MaxUint16:
+		return e.writeFixed(0xcd, v, 2)
+	case v <= math.MaxUint32:
+		return e.writeFixed(0xce, v, 4)
+	}
+	return e.writeFixed(0xcf, v, 8)
+}
+
+// writeLen writes the header of a value of length n: the fix format code|n when n fits fixMax
+// (fixMax 0 has no fix format), otherwise code8 with a 1-byte length for strings and binary, and
+// the 2- and 4-byte formats that follow code8 in the specification
+func (e *Encoder) writeLen(n int, fixCode, fixMax byte, code8 byte) error {
+	if n < 0 || uint64(n) > math.MaxUint32 {
+		return fmt.Errorf("msgpack: length %d is too large", n)
+	}
+	switch {
+	case fixMax > 0 && n <= int(fixMax):
+		return e.w.WriteByte(fixCode | byte(n))
+	case code8 != 0xdc && code8 != 0xde && n <= math.MaxUint8:
+		return e.writeFixed(code8, uint64(n), 1)
+	case n <= math.MaxUint16:
+		return e.writeFixed(code8+hasLen8(code8), uint64(n), 2)
+	}
+	return e.writeFixed(code8+hasLen8(code8)+1, uint64(n), 4)
+}
+
+// hasLen8 is 1 for the formats with a 1-byte length (str8 and bin8), whose 2-byte format is the
+// next code, and 0 for arrays and maps, whose first format has a 2-byte length
+func hasLen8(code8 byte) byte {
+	if code8 == 0xdc || code8 == 0xde {
+		return 0
+	}
+	return 1
+}
+
+// writeFixed writes a format code followed by v as a big-endian integer of size bytes
+func (e *Encoder) writeFixed(code byte, v uint64, size int) error {
+	e.buf[0] = code
+	for i := range size {
+		e.buf[size-i] = byte(v >> (8 * i))
+	}
+	_, err := e.w.Write(e.buf[:size+1])
+	return err
+}
@@ -0,0 +1,178 @@
+package msgpack
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"nil", nil, "c0"},
+		{"false", false, "c2"},
+		{"true", true, "c3"},
+		{"positive fixint", 127, "7f"},
+		{"negative fixint", -32, "e0"},
+		{"uint8", 200, "ccc8"},
+		{"uint16", 1000, "cd03e8"},
+		{"uint32", 100000, "ce000186a0"},
+		{"uint64", uint64(math.MaxUint64), "cfffffffffffffffff"},
+		{"int8", -100, "d09c"},
+		{"int16", -1000, "d1fc18"},
+		{"int32", -100000, "d2fffe7960"},
+		{"int64", int64(math.MinInt64), "d38000000000000000"},
+		{"float64", 1.5, "cb3ff8000000000000"},
+		{"fixstr", "abc", "a3616263"},
+		{"str8", strings.Repeat("a", 32), "d920" + strings.Repeat("61", 32)},
+		{"bin8", []byte{1, 2}, "c4020102"},
+		{"fixarray", []any{1, "a"}, "9201a161"},
+		{"fixmap with sorted keys", map[string]any{"b": 2, "a": 1}, "82a16101a16202"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+			encoder := NewEncoder(&buf)
+
+			// Act
+			err := encoder.Encode(tt.value)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.NoError(t, encoder.Flush())
+			assert.Equal(t, tt.want, hex.EncodeToString(buf.Bytes()))
+		})
+	}
+}
+
+func TestEncode_LongHeaders(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	encoder := NewEncoder(&buf)
+
+	// Act
+	arrayErr := encoder.EncodeArrayLen(70000)
+	mapErr := encoder.EncodeMapLen(16)
+	strErr := encoder.Encode(strings.Repeat("a", 300))
+
+	// Assert
+	assert.NoError(t, arrayErr)
+	assert.NoError(t, mapErr)
+	assert.NoError(t, strErr)
+	assert.NoError(t, encoder.Flush())
+	assert.Equal(t, "dd00011170"+"de0010"+"da012c", hex.EncodeToString(buf.Bytes()[:11]))
+}
+
+func TestEncode_Unsupported(t *testing.T) {
+	// Act
+	err := NewEncoder(io.Discard).Encode(struct{}{})
+
+	// Assert
+	assert.True(t, errors.Is(err, ErrType))
+}
+
+func TestDecode_RoundTrip(t *testing.T) {
+	// Arrange
+	value := map[string]any{
+		"origin_zipcode":      "01310100",
+		"weight":              1.5,
+		"quantity":            int64(-70000),
+		"big":                 uint64(math.MaxUint64),
+		"is_express":          true,
+		"additional_services": []any{"insurance", "fragile"},
+		"notes":               nil,
+		"payload":             []byte("raw"),
+		"nested":              map[string]any{"long": strings.Repeat("x", 70000)},
+	}
+	var buf bytes.Buffer
+	encoder := NewEncoder(&buf)
+	assert.NoError(t, encoder.Encode(value))
+	assert.NoError(t, encoder.Flush())
+
+	// Act
+	decoded, err := NewDecoder(&buf).Decode()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, value, decoded)
+}
+
+func TestDecodeArrayLen_Streaming(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	encoder := NewEncoder(&buf)
+	assert.NoError(t, encoder.EncodeArrayLen(20))
+	for i := range 20 {
+		assert.NoError(t, encoder.Encode(map[string]any{"row": i}))
+	}
+	assert.NoError(t, encoder.Flush())
+	decoder := NewDecoder(&buf)
+
+	// Act
+	n, err := decoder.DecodeArrayLen()
+	var rows []any
+	for range n {
+		row, err := decoder.Decode()
+		assert.NoError(t, err)
+		rows = append(rows, row)
+	}
+	_, endErr := decoder.Decode()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, rows, 20)
+	assert.Equal(t, map[string]any{"row": int64(19)}, rows[19])
+	assert.ErrorIs(t, endErr, io.EOF)
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want error
+	}{
+		{"truncated string", "a36162", io.ErrUnexpectedEOF},
+		{"truncated float", "cb3ff8", io.ErrUnexpectedEOF},
+		{"truncated map", "82a161", io.ErrUnexpectedEOF},
+		{"forged string length", "db7fffff00", io.ErrUnexpectedEOF},
+		{"non-string key", "810102", ErrType},
+		{"extension type", "d40100", ErrType},
+		{"nesting too deep", strings.Repeat("91", maxDepth+1) + "c0", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			data, err := hex.DecodeString(tt.data)
+			assert.NoError(t, err)
+
+			// Act
+			_, err = NewDecoder(bytes.NewReader(data)).Decode()
+
+			// Assert
+			assert.Error(t, err)
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeArrayLen_NotAnArray(t *testing.T) {
+	// Act
+	_, err := NewDecoder(bytes.NewReader([]byte{0x80})).DecodeArrayLen()
+
+	// Assert
+	assert.ErrorIs(t, err, ErrType)
+}
@@ -0,0 +1,145 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// EmailChannel sends the alerts by e-mail through an SMTP server
+type EmailChannel struct {
+	smtp SMTPConfig
+	to   []string
+	now  func() time.Time
+}
+
+// NewEmailChannel creates a channel sending the alerts to the recipients through server
+func NewEmailChannel(server SMTPConfig, to []string) *EmailChannel {
+	return &EmailChannel{smtp: server, to: to, now: time.Now}
+}
+
+// Name implements Channel
+func (c *EmailChannel) Name() string { return "email" }
+
+// Send delivers the message to every recipient in a single SMTP transaction, bounded by ctx
+func (c *EmailChannel) Send(ctx context.Context, message Message) error {
+	body, err := c.encode(message)
+	if err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.smtp.Addr)
+	if err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	host, _, _ := net.SplitHostPort(c.smtp.Addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("email: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("email: starttls: %w", err)
+		}
+	}
+	if c.smtp.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.smtp.Username, c.smtp.Password, host)); err != nil {
+			return fmt.Errorf("email: auth: %w", err)
+		}
+	}
+	if err := client.Mail(c.smtp.From); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	for _, recipient := range c.to {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("email: recipient %s: %w", recipient, err)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	if _, err := writer.Write(body); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	return client.Quit()
+}
+
+// encode builds a plain-text UTF-8 message, quoted-printable encoded
+func (c *EmailChannel) encode(message Message) ([]byte, error) {
+	var buf bytes.Buffer
+	headers := []string{
+		"From: " + c.smtp.From,
+		"To: " + strings.Join(c.to, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date: " + c.now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding: quoted-printable",
+	}
+	buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+	writer := quotedprintable.NewWriter(&buf)
+	if _, err := writer.Write([]byte(strings.ReplaceAll(message.Text, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SlackChannel posts the alerts to a Slack incoming webhook
+type SlackChannel struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewSlackChannel creates a channel posting the alerts to the incoming webhook URL
+func NewSlackChannel(webhookURL string) *SlackChannel {
+	return &SlackChannel{url: webhookURL, httpClient: &http.Client{}}
+}
+
+// Name implements Channel
+func (c *SlackChannel) Name() string { return "slack" }
+
+// Send posts the message, with its subject in bold, bounded by ctx
+func (c *SlackChannel) Send(ctx context.Context, message Message) error {
+	payload, err := json.Marshal(map[string]string{"text": "*" + message.Subject + "*\n" + message.Text})
+	if err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("slack: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
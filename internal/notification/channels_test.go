@@ -0,0 +1,140 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSMTPServer accepts a single SMTP session and sends the received DATA on the returned channel
+func fakeSMTPServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch command := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL"), strings.HasPrefix(command, "RCPT"):
+				data.WriteString(strings.TrimSpace(line) + "\n")
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				received <- data.String()
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestEmailChannel_Send(t *testing.T) {
+	// Arrange
+	addr, received := fakeSMTPServer(t)
+	channel := NewEmailChannel(SMTPConfig{Addr: addr, From: "alertas@example.com"}, []string{"ops@acme.example.com", "logistica@acme.example.com"})
+	channel.now = func() time.Time { return time.Date(2025, 3, 11, 15, 0, 0, 0, time.UTC) }
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Act
+	err := channel.Send(ctx, Message{Subject: "Falha na entrega do envio s1", Text: "Envio: s1\nOcorrência: Destinatário ausente"})
+
+	// Assert
+	assert.NoError(t, err)
+	session := <-received
+	assert.Contains(t, session, "MAIL FROM:<alertas@example.com>")
+	assert.Contains(t, session, "RCPT TO:<ops@acme.example.com>")
+	assert.Contains(t, session, "RCPT TO:<logistica@acme.example.com>")
+	assert.Contains(t, session, "To: ops@acme.example.com, logistica@acme.example.com\r\n")
+	assert.Contains(t, session, "Subject: Falha na entrega do envio s1\r\n")
+	assert.Contains(t, session, "Date: Tue, 11 Mar 2025 15:00:00 +0000\r\n")
+	assert.Contains(t, session, "Envio: s1\r\nOcorr=C3=AAncia: Destinat=C3=A1rio ausente")
+}
+
+func TestEmailChannel_SendUnreachableServer(t *testing.T) {
+	// Arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+	channel := NewEmailChannel(SMTPConfig{Addr: addr, From: "alertas@example.com"}, []string{"ops@acme.example.com"})
+
+	// Act
+	err = channel.Send(context.Background(), Message{Subject: "s", Text: "t"})
+
+	// Assert
+	assert.ErrorContains(t, err, "email:")
+}
+
+func TestSlackChannel_Send(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr string
+	}{
+		{"accepted", http.StatusOK, ""},
+		{"rejected", http.StatusForbidden, "slack: unexpected status 403"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var payload map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+			channel := NewSlackChannel(server.URL)
+
+			// Act
+			err := channel.Send(context.Background(), Message{Subject: "Envio s1 devolvido ao remetente", Text: "Envio: s1"})
+
+			// Assert
+			assert.Equal(t, map[string]string{"text": "*Envio s1 devolvido ao remetente*\nEnvio: s1"}, payload)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
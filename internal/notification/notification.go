@@ -0,0 +1,313 @@
+// Package notification alerts the channels configured for a tenant, e-mail and Slack, when the
+// tracking of one of its shipments reports a delivery exception: a failed delivery attempt or a
+// shipment returned to the sender.
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"go.uber.org/zap"
+)
+
+// Message is an alert sent to the channels of a tenant
+type Message struct {
+	Subject string
+	Text    string
+}
+
+// Channel delivers alerts to a destination
+type Channel interface {
+	// Name identifies the channel in logs
+	Name() string
+	Send(ctx context.Context, message Message) error
+}
+
+// TenantConfig holds the channels alerted for the shipments of a tenant
+type TenantConfig struct {
+	// Emails are the recipients of the alerts, sent through the SMTP server
+	Emails []string `json:"emails,omitempty"`
+	// SlackWebhookURL is a Slack incoming webhook the alerts are posted to
+	SlackWebhookURL string `json:"slack_webhook_url,omitempty"`
+	// Statuses are the exception statuses alerted; empty alerts every
+	// model.TrackingExceptionStatuses
+	Statuses []string `json:"statuses,omitempty"`
+}
+
+// SMTPConfig is the server the e-mail alerts are sent through
+type SMTPConfig struct {
+	// Addr is the host:port of the server; STARTTLS is used when the server supports it
+	Addr string
+	// Username and Password authenticate with PLAIN when Username is set
+	Username string
+	Password string
+	From     string
+}
+
+// Config configures the alerts of each tenant
+type Config struct {
+	// Tenants are the alerted channels by tenant ID, including tenant.Default
+	Tenants map[string]TenantConfig `json:"tenants"`
+	SMTP    SMTPConfig              `json:"-"`
+	// Timeout bounds the delivery of an alert to each channel
+	Timeout time.Duration `json:"-"`
+	// QueueSize is how many alerts may wait to be sent; alerts raised with the queue full are
+	// dropped
+	QueueSize int `json:"-"`
+}
+
+// ConfigFromEnv reads the channels of the tenants from the JSON file at NOTIFICATION_CONFIG_PATH,
+// when set, the SMTP server from NOTIFICATION_SMTP_ADDR, NOTIFICATION_SMTP_USERNAME,
+// NOTIFICATION_SMTP_PASSWORD and NOTIFICATION_SMTP_FROM, NOTIFICATION_TIMEOUT (default 10s) and
+// NOTIFICATION_QUEUE_SIZE (default 100)
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		SMTP: SMTPConfig{
+			Addr:     config.String("NOTIFICATION_SMTP_ADDR", ""),
+			Username: config.String("NOTIFICATION_SMTP_USERNAME", ""),
+			Password: config.String("NOTIFICATION_SMTP_PASSWORD", ""),
+			From:     config.String("NOTIFICATION_SMTP_FROM", ""),
+		},
+	}
+	if path := os.Getenv("NOTIFICATION_CONFIG_PATH"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to read notification config: %w", err)
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return Config{}, fmt.Errorf("failed to parse notification config: %w", err)
+		}
+	}
+
+	var err error
+	if cfg.Timeout, err = config.Duration("NOTIFICATION_TIMEOUT", 10*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.Timeout <= 0 {
+		return Config{}, fmt.Errorf("NOTIFICATION_TIMEOUT must be positive, got %s", cfg.Timeout)
+	}
+	if cfg.QueueSize, err = config.Int("NOTIFICATION_QUEUE_SIZE", 100); err != nil {
+		return Config{}, err
+	}
+	if cfg.QueueSize <= 0 {
+		return Config{}, fmt.Errorf("NOTIFICATION_QUEUE_SIZE must be positive, got %d", cfg.QueueSize)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid notification config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Enabled reports whether any tenant is alerted
+func (c Config) Enabled() bool {
+	return len(c.Tenants) > 0
+}
+
+// Validate checks that every tenant has a valid channel and alerts exception statuses only, and
+// that the SMTP server is configured when a tenant is alerted by e-mail
+func (c Config) Validate() error {
+	for _, id := range c.tenantIDs() {
+		tenantConfig := c.Tenants[id]
+		if len(tenantConfig.Emails) == 0 && tenantConfig.SlackWebhookURL == "" {
+			return fmt.Errorf("tenant %q: emails or slack_webhook_url is required", id)
+		}
+		for _, email := range tenantConfig.Emails {
+			if _, err := mail.ParseAddress(email); err != nil {
+				return fmt.Errorf("tenant %q: invalid email %q", id, email)
+			}
+		}
+		if len(tenantConfig.Emails) > 0 && (c.SMTP.Addr == "" || c.SMTP.From == "") {
+			return fmt.Errorf("tenant %q: NOTIFICATION_SMTP_ADDR and NOTIFICATION_SMTP_FROM are required for emails", id)
+		}
+		if tenantConfig.SlackWebhookURL != "" {
+			target, err := url.Parse(tenantConfig.SlackWebhookURL)
+			if err != nil || target.Scheme != "https" || target.Host == "" {
+				return fmt.Errorf("tenant %q: slack_webhook_url must be an absolute https URL", id)
+			}
+		}
+		for _, status := range tenantConfig.Statuses {
+			if !slices.Contains(model.TrackingExceptionStatuses, status) {
+				return fmt.Errorf("tenant %q: unsupported status %q, must be one of %s", id, status, strings.Join(model.TrackingExceptionStatuses, ", "))
+			}
+		}
+	}
+	if c.SMTP.From != "" {
+		if _, err := mail.ParseAddress(c.SMTP.From); err != nil {
+			return fmt.Errorf("invalid NOTIFICATION_SMTP_FROM %q", c.SMTP.From)
+		}
+	}
+	return nil
+}
+
+// CheckTenants checks that the alerted tenants are known
+func (c Config) CheckTenants(tenants tenant.Config) error {
+	for _, id := range c.tenantIDs() {
+		if !tenants.Known(id) {
+			return fmt.Errorf("notification config: unknown tenant %q", id)
+		}
+	}
+	return nil
+}
+
+// tenantIDs returns the alerted tenant IDs, sorted
+func (c Config) tenantIDs() []string {
+	ids := make([]string, 0, len(c.Tenants))
+	for id := range c.Tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// route is where and when the shipments of a tenant are alerted
+type route struct {
+	channels []Channel
+	statuses []string
+}
+
+// alert is a message waiting to be sent to the channels of a tenant
+type alert struct {
+	shipmentID string
+	channels   []Channel
+	message    Message
+}
+
+// Notifier alerts the channels of the tenants of delivery exceptions. It is safe for concurrent use
+type Notifier struct {
+	routes  map[string]route
+	timeout time.Duration
+	queue   chan alert
+	logger  *zap.Logger
+}
+
+// NewNotifier creates a notifier alerting the channels of cfg. Run sends the alerts
+func NewNotifier(cfg Config, logger *zap.Logger) *Notifier {
+	routes := make(map[string]route, len(cfg.Tenants))
+	for id, tenantConfig := range cfg.Tenants {
+		var channels []Channel
+		if len(tenantConfig.Emails) > 0 {
+			channels = append(channels, NewEmailChannel(cfg.SMTP, tenantConfig.Emails))
+		}
+		if tenantConfig.SlackWebhookURL != "" {
+			channels = append(channels, NewSlackChannel(tenantConfig.SlackWebhookURL))
+		}
+		statuses := tenantConfig.Statuses
+		if len(statuses) == 0 {
+			statuses = model.TrackingExceptionStatuses
+		}
+		routes[id] = route{channels: channels, statuses: statuses}
+	}
+	return &Notifier{
+		routes:  routes,
+		timeout: cfg.Timeout,
+		queue:   make(chan alert, cfg.QueueSize),
+		logger:  logger,
+	}
+}
+
+// Notify queues an alert when a events.TrackingUpdated event reports an exception status alerted
+// for its tenant; other events are ignored. It does not block: alerts raised with the queue full
+// are dropped
+func (n *Notifier) Notify(event events.Event) {
+	if event.Type != events.TrackingUpdated {
+		return
+	}
+	shipmentTracking, ok := event.Data.(*model.ShipmentTracking)
+	if !ok || len(shipmentTracking.Events) == 0 {
+		return
+	}
+	tenantRoute, ok := n.routes[event.Tenant]
+	if !ok || !slices.Contains(tenantRoute.statuses, shipmentTracking.Status) {
+		return
+	}
+
+	next := alert{
+		shipmentID: shipmentTracking.ShipmentID,
+		channels:   tenantRoute.channels,
+		message:    exceptionMessage(event.Tenant, shipmentTracking),
+	}
+	select {
+	case n.queue <- next:
+	default:
+		n.logger.Warn("Notification queue full, delivery exception alert dropped",
+			zap.String("shipment_id", next.shipmentID),
+			zap.String("tenant", event.Tenant),
+		)
+	}
+}
+
+// Run sends the queued alerts until ctx is done. Failures are logged and not retried
+func (n *Notifier) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case next := <-n.queue:
+			for _, channel := range next.channels {
+				sendCtx, cancel := context.WithTimeout(ctx, n.timeout)
+				err := channel.Send(sendCtx, next.message)
+				cancel()
+				if err != nil {
+					n.logger.Warn("Failed to send delivery exception alert",
+						zap.String("channel", channel.Name()),
+						zap.String("shipment_id", next.shipmentID),
+						zap.Error(err),
+					)
+				}
+			}
+		}
+	}
+}
+
+// exceptionMessage describes the latest event of a shipment with a delivery exception
+func exceptionMessage(tenantID string, shipmentTracking *model.ShipmentTracking) Message {
+	latest := shipmentTracking.Events[len(shipmentTracking.Events)-1]
+	subject := fmt.Sprintf("Falha na entrega do envio %s", shipmentTracking.ShipmentID)
+	if shipmentTracking.Status == model.TrackingStatusReturned {
+		subject = fmt.Sprintf("Envio %s devolvido ao remetente", shipmentTracking.ShipmentID)
+	}
+
+	lines := []string{
+		"Envio: " + shipmentTracking.ShipmentID,
+		"Tenant: " + tenantID,
+		"Status: " + shipmentTracking.Status,
+	}
+	if latest.Description != "" {
+		lines = append(lines, "Ocorrência: "+latest.Description)
+	}
+	if latest.Location != "" {
+		lines = append(lines, "Local: "+latest.Location)
+	}
+	lines = append(lines, "Data: "+latest.OccurredAt.UTC().Format(time.RFC3339))
+	return Message{Subject: subject, Text: strings.Join(lines, "\n")}
+}
+
+// Publisher publishes events to another publisher and alerts the delivery exceptions they report
+type Publisher struct {
+	next     events.Publisher
+	notifier *Notifier
+}
+
+// NewPublisher creates a publisher wrapping next
+func NewPublisher(next events.Publisher, notifier *Notifier) *Publisher {
+	return &Publisher{next: next, notifier: notifier}
+}
+
+// Publish implements events.Publisher
+func (p *Publisher) Publish(ctx context.Context, event events.Event) error {
+	err := p.next.Publish(ctx, event)
+	p.notifier.Notify(event)
+	return err
+}
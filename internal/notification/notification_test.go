@@ -0,0 +1,251 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+var failedDelivery = &model.ShipmentTracking{
+	ShipmentID: "s1",
+	Status:     model.TrackingStatusDeliveryFailed,
+	Events: []model.TrackingEvent{
+		{Status: model.TrackingStatusInTransit, OccurredAt: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)},
+		{Status: model.TrackingStatusDeliveryFailed, Description: "Destinatário ausente", Location: "Rio de Janeiro/RJ", OccurredAt: time.Date(2025, 3, 11, 15, 0, 0, 0, time.UTC)},
+	},
+}
+
+// fakeChannel records the messages sent to it
+type fakeChannel struct {
+	sent chan Message
+	err  error
+}
+
+func newFakeChannel(err error) *fakeChannel {
+	return &fakeChannel{sent: make(chan Message, 10), err: err}
+}
+
+func (c *fakeChannel) Name() string { return "fake" }
+
+func (c *fakeChannel) Send(ctx context.Context, message Message) error {
+	c.sent <- message
+	return c.err
+}
+
+func newTestNotifier(t *testing.T, routes map[string]route) *Notifier {
+	t.Helper()
+	notifier := NewNotifier(Config{Timeout: time.Second, QueueSize: 10}, zaptest.NewLogger(t))
+	notifier.routes = routes
+	return notifier
+}
+
+// runNotifier runs the notifier until the test ends
+func runNotifier(t *testing.T, notifier *Notifier) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		notifier.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	keys := []string{"NOTIFICATION_CONFIG_PATH", "NOTIFICATION_SMTP_ADDR", "NOTIFICATION_SMTP_USERNAME", "NOTIFICATION_SMTP_PASSWORD", "NOTIFICATION_SMTP_FROM", "NOTIFICATION_TIMEOUT", "NOTIFICATION_QUEUE_SIZE"}
+	smtpEnv := map[string]string{"NOTIFICATION_SMTP_ADDR": "smtp.example.com:587", "NOTIFICATION_SMTP_FROM": "alertas@example.com"}
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{"defaults", "", map[string]string{}, Config{Timeout: 10 * time.Second, QueueSize: 100}, ""},
+		{
+			"tenants",
+			`{"tenants": {"acme": {"emails": ["ops@acme.example.com"], "slack_webhook_url": "https://hooks.slack.com/services/T/B/X", "statuses": ["returned"]}}}`,
+			map[string]string{"NOTIFICATION_SMTP_ADDR": "smtp.example.com:587", "NOTIFICATION_SMTP_USERNAME": "user", "NOTIFICATION_SMTP_PASSWORD": "secret", "NOTIFICATION_SMTP_FROM": "alertas@example.com", "NOTIFICATION_TIMEOUT": "5s", "NOTIFICATION_QUEUE_SIZE": "20"},
+			Config{
+				Tenants: map[string]TenantConfig{"acme": {Emails: []string{"ops@acme.example.com"}, SlackWebhookURL: "https://hooks.slack.com/services/T/B/X", Statuses: []string{"returned"}}},
+				SMTP:    SMTPConfig{Addr: "smtp.example.com:587", Username: "user", Password: "secret", From: "alertas@example.com"},
+				Timeout: 5 * time.Second, QueueSize: 20,
+			},
+			"",
+		},
+		{"malformed file", `{"tenants": [`, map[string]string{}, Config{}, "failed to parse notification config"},
+		{"tenant without channels", `{"tenants": {"acme": {}}}`, map[string]string{}, Config{}, "emails or slack_webhook_url is required"},
+		{"invalid email", `{"tenants": {"acme": {"emails": ["ops"]}}}`, smtpEnv, Config{}, "invalid email"},
+		{"emails without SMTP server", `{"tenants": {"acme": {"emails": ["ops@acme.example.com"]}}}`, map[string]string{}, Config{}, "NOTIFICATION_SMTP_ADDR"},
+		{"insecure slack URL", `{"tenants": {"acme": {"slack_webhook_url": "http://hooks.slack.com/x"}}}`, map[string]string{}, Config{}, "absolute https URL"},
+		{"status without exception", `{"tenants": {"acme": {"slack_webhook_url": "https://hooks.slack.com/x", "statuses": ["delivered"]}}}`, map[string]string{}, Config{}, "unsupported status"},
+		{"zero timeout", "", map[string]string{"NOTIFICATION_TIMEOUT": "0s"}, Config{}, "NOTIFICATION_TIMEOUT"},
+		{"zero queue", "", map[string]string{"NOTIFICATION_QUEUE_SIZE": "0"}, Config{}, "NOTIFICATION_QUEUE_SIZE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for _, key := range keys {
+				t.Setenv(key, tt.env[key])
+			}
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), "notifications.json")
+				assert.NoError(t, os.WriteFile(path, []byte(tt.file), 0o600))
+				t.Setenv("NOTIFICATION_CONFIG_PATH", path)
+			}
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestConfig_CheckTenants(t *testing.T) {
+	// Arrange
+	tenants := tenant.Config{Tenants: map[string]tenant.Tenant{"acme": {PricingConfigPath: "acme.json"}}}
+	known := Config{Tenants: map[string]TenantConfig{tenant.Default: {}, "acme": {}}}
+	unknown := Config{Tenants: map[string]TenantConfig{"globex": {}}}
+
+	// Act
+	knownErr := known.CheckTenants(tenants)
+	unknownErr := unknown.CheckTenants(tenants)
+
+	// Assert
+	assert.NoError(t, knownErr)
+	assert.ErrorContains(t, unknownErr, `unknown tenant "globex"`)
+}
+
+func TestNotifier_AlertsDeliveryExceptions(t *testing.T) {
+	// Arrange
+	channel := newFakeChannel(nil)
+	failing := newFakeChannel(errors.New("unavailable"))
+	notifier := newTestNotifier(t, map[string]route{"acme": {channels: []Channel{failing, channel}, statuses: model.TrackingExceptionStatuses}})
+	runNotifier(t, notifier)
+
+	// Act
+	notifier.Notify(events.Event{Type: events.TrackingUpdated, Subject: "s1", Tenant: "acme", Data: failedDelivery})
+
+	// Assert
+	select {
+	case message := <-channel.sent:
+		assert.Equal(t, "Falha na entrega do envio s1", message.Subject)
+		assert.Equal(t, "Envio: s1\nTenant: acme\nStatus: delivery_failed\nOcorrência: Destinatário ausente\nLocal: Rio de Janeiro/RJ\nData: 2025-03-11T15:00:00Z", message.Text)
+	case <-time.After(time.Second):
+		t.Fatal("alert not sent")
+	}
+	assert.Len(t, failing.sent, 1, "a failing channel does not prevent the others from being alerted")
+}
+
+func TestNotifier_IgnoresOtherEvents(t *testing.T) {
+	returned := &model.ShipmentTracking{ShipmentID: "s1", Status: model.TrackingStatusReturned, Events: []model.TrackingEvent{{Status: model.TrackingStatusReturned}}}
+	delivered := &model.ShipmentTracking{ShipmentID: "s1", Status: model.TrackingStatusDelivered, Events: []model.TrackingEvent{{Status: model.TrackingStatusDelivered}}}
+	tests := []struct {
+		name  string
+		event events.Event
+	}{
+		{"other event type", events.Event{Type: events.ShipmentBooked, Tenant: "acme", Data: failedDelivery}},
+		{"status without exception", events.Event{Type: events.TrackingUpdated, Tenant: "acme", Data: delivered}},
+		{"status not alerted for the tenant", events.Event{Type: events.TrackingUpdated, Tenant: "acme", Data: returned}},
+		{"tenant without channels", events.Event{Type: events.TrackingUpdated, Tenant: "globex", Data: failedDelivery}},
+		{"unexpected data", events.Event{Type: events.TrackingUpdated, Tenant: "acme", Data: map[string]string{"status": "delivery_failed"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			channel := newFakeChannel(nil)
+			notifier := newTestNotifier(t, map[string]route{"acme": {channels: []Channel{channel}, statuses: []string{model.TrackingStatusDeliveryFailed}}})
+
+			// Act
+			notifier.Notify(tt.event)
+
+			// Assert
+			assert.Empty(t, notifier.queue)
+		})
+	}
+}
+
+func TestNotifier_DropsAlertsWithQueueFull(t *testing.T) {
+	// Arrange
+	channel := newFakeChannel(nil)
+	notifier := NewNotifier(Config{Timeout: time.Second, QueueSize: 1}, zaptest.NewLogger(t))
+	notifier.routes = map[string]route{"acme": {channels: []Channel{channel}, statuses: model.TrackingExceptionStatuses}}
+	event := events.Event{Type: events.TrackingUpdated, Tenant: "acme", Data: failedDelivery}
+
+	// Act
+	notifier.Notify(event)
+	notifier.Notify(event)
+
+	// Assert
+	assert.Len(t, notifier.queue, 1)
+}
+
+func TestNewNotifier_Routes(t *testing.T) {
+	// Arrange
+	cfg := Config{
+		Tenants: map[string]TenantConfig{
+			"acme":   {Emails: []string{"ops@acme.example.com"}, SlackWebhookURL: "https://hooks.slack.com/x"},
+			"globex": {SlackWebhookURL: "https://hooks.slack.com/y", Statuses: []string{model.TrackingStatusReturned}},
+		},
+		SMTP:      SMTPConfig{Addr: "smtp.example.com:587", From: "alertas@example.com"},
+		Timeout:   time.Second,
+		QueueSize: 10,
+	}
+
+	// Act
+	notifier := NewNotifier(cfg, zaptest.NewLogger(t))
+
+	// Assert
+	assert.Len(t, notifier.routes["acme"].channels, 2)
+	assert.Equal(t, "email", notifier.routes["acme"].channels[0].Name())
+	assert.Equal(t, model.TrackingExceptionStatuses, notifier.routes["acme"].statuses)
+	assert.Len(t, notifier.routes["globex"].channels, 1)
+	assert.Equal(t, []string{model.TrackingStatusReturned}, notifier.routes["globex"].statuses)
+}
+
+type recordingPublisher struct {
+	published []events.Event
+	err       error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event events.Event) error {
+	p.published = append(p.published, event)
+	return p.err
+}
+
+func TestPublisher(t *testing.T) {
+	// Arrange
+	next := &recordingPublisher{err: errors.New("broker down")}
+	notifier := newTestNotifier(t, map[string]route{"acme": {channels: []Channel{newFakeChannel(nil)}, statuses: model.TrackingExceptionStatuses}})
+	publisher := NewPublisher(next, notifier)
+	event := events.Event{Type: events.TrackingUpdated, Tenant: "acme", Data: failedDelivery}
+
+	// Act
+	err := publisher.Publish(context.Background(), event)
+
+	// Assert
+	assert.EqualError(t, err, "broker down")
+	assert.Len(t, next.published, 1)
+	assert.Len(t, notifier.queue, 1, "the alert is raised even when the broker fails")
+}
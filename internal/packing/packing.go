@@ -0,0 +1,393 @@
+// Package packing suggests the boxes to ship a set of items in and quotes the packed boxes.
+package packing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+)
+
+var (
+	// ErrInvalidItems is returned when the items to pack are missing or malformed
+	ErrInvalidItems = errors.New("invalid items")
+	// ErrItemTooLarge is returned when an item fits in no box of the catalog
+	ErrItemTooLarge = errors.New("item does not fit in any box")
+)
+
+// Box is a box size of the catalog. Dimensions are inner dimensions in centimeters
+type Box struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name,omitempty"`
+	Length float64 `json:"length"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	// MaxWeight is the maximum weight of the contents in kilograms; 0 means no limit
+	MaxWeight float64 `json:"max_weight,omitempty"`
+}
+
+// Volume returns the inner volume of the box in cm³
+func (b Box) Volume() float64 {
+	return b.Length * b.Width * b.Height
+}
+
+// Item is a product to pack; Quantity units of it are packed (default: 1)
+type Item struct {
+	SKU      string  `json:"sku"`
+	Length   float64 `json:"length"`
+	Width    float64 `json:"width"`
+	Height   float64 `json:"height"`
+	Weight   float64 `json:"weight"`
+	Quantity int     `json:"quantity,omitempty"`
+}
+
+// ItemCount is the number of units of an item packed in a box
+type ItemCount struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// PackedBox is a box of the suggestion with its contents
+type PackedBox struct {
+	Box   Box
+	Items []ItemCount
+	// Weight is the weight of the contents in kilograms
+	Weight float64
+	// FillRate is the share of the box volume taken by the contents, from 0 to 1
+	FillRate float64
+}
+
+// Config holds the box catalog and the size limit of packing requests
+type Config struct {
+	Boxes []Box `json:"boxes"`
+	// MaxUnits is the maximum number of units, summing the quantities of every item, per request
+	MaxUnits int `json:"max_units,omitempty"`
+}
+
+// DefaultConfig returns a catalog of four box sizes, up to 30 kg, and a limit of 500 units per request
+func DefaultConfig() Config {
+	return Config{
+		Boxes: []Box{
+			{ID: "small", Name: "Caixa P", Length: 20, Width: 15, Height: 10, MaxWeight: 5},
+			{ID: "medium", Name: "Caixa M", Length: 30, Width: 20, Height: 15, MaxWeight: 10},
+			{ID: "large", Name: "Caixa G", Length: 40, Width: 30, Height: 25, MaxWeight: 20},
+			{ID: "xlarge", Name: "Caixa GG", Length: 60, Width: 40, Height: 40, MaxWeight: 30},
+		},
+		MaxUnits: 500,
+	}
+}
+
+// Validate checks that the catalog has at least one box, every box has a unique id and positive
+// dimensions, and the unit limit is not negative
+func (c Config) Validate() error {
+	if len(c.Boxes) == 0 {
+		return errors.New("at least one box is required")
+	}
+	seen := make(map[string]bool, len(c.Boxes))
+	for _, b := range c.Boxes {
+		if b.ID == "" {
+			return fmt.Errorf("box %q: id is required", b.Name)
+		}
+		if seen[b.ID] {
+			return fmt.Errorf("box %q: duplicate id", b.ID)
+		}
+		seen[b.ID] = true
+		if b.Length <= 0 || b.Width <= 0 || b.Height <= 0 {
+			return fmt.Errorf("box %q: dimensions must be positive", b.ID)
+		}
+		if b.MaxWeight < 0 {
+			return fmt.Errorf("box %q: max_weight must not be negative", b.ID)
+		}
+	}
+	if c.MaxUnits < 0 {
+		return errors.New("max_units must not be negative")
+	}
+	return nil
+}
+
+// LoadConfig reads and validates the box catalog from a JSON file; a missing max_units keeps
+// the default limit
+func LoadConfig(path string) (Config, error) {
+	cfg := Config{MaxUnits: DefaultConfig().MaxUnits}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read box catalog: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse box catalog: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid box catalog: %w", err)
+	}
+	return cfg, nil
+}
+
+// Packer assigns items to boxes of the catalog with first-fit decreasing bin packing.
+// Boxes are filled by volume and weight: every unit must fit in the box in some orientation,
+// but the arrangement of the units inside the box is not checked
+type Packer struct {
+	// boxes are ordered from the smallest to the largest volume
+	boxes    []Box
+	maxUnits int
+}
+
+// NewPacker creates a packer for the box catalog of cfg
+func NewPacker(cfg Config) *Packer {
+	boxes := append([]Box{}, cfg.Boxes...)
+	sort.SliceStable(boxes, func(i, j int) bool { return boxes[i].Volume() < boxes[j].Volume() })
+	return &Packer{boxes: boxes, maxUnits: cfg.MaxUnits}
+}
+
+// unit is a single unit of an item
+type unit struct {
+	sku    string
+	dims   [3]float64
+	volume float64
+	weight float64
+}
+
+// bin is a box being filled
+type bin struct {
+	box    Box
+	units  []unit
+	volume float64
+	weight float64
+}
+
+// Pack suggests the boxes for the items. Units are taken from the largest to the smallest and
+// each one goes into the first open box with room for it; when none has room, the largest box
+// the unit fits in is opened. Each box is then swapped for the smallest box of the catalog that
+// still holds its contents
+func (p *Packer) Pack(items []Item) ([]PackedBox, error) {
+	units, err := p.units(items)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(units, func(i, j int) bool {
+		if units[i].volume != units[j].volume {
+			return units[i].volume > units[j].volume
+		}
+		return units[i].weight > units[j].weight
+	})
+
+	var bins []*bin
+	for _, u := range units {
+		placed := false
+		for _, b := range bins {
+			if b.fits(b.box, u) {
+				b.add(u)
+				placed = true
+				break
+			}
+		}
+		if placed {
+			continue
+		}
+		box, ok := p.largestFor(u)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrItemTooLarge, u.sku)
+		}
+		b := &bin{box: box}
+		b.add(u)
+		bins = append(bins, b)
+	}
+
+	packed := make([]PackedBox, 0, len(bins))
+	for _, b := range bins {
+		b.box = p.smallestFor(b)
+		packed = append(packed, b.packed())
+	}
+	return packed, nil
+}
+
+// units validates the items and expands them into units
+func (p *Packer) units(items []Item) ([]unit, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: at least one item is required", ErrInvalidItems)
+	}
+	var units []unit
+	for i, item := range items {
+		sku := strings.TrimSpace(item.SKU)
+		if sku == "" {
+			return nil, fmt.Errorf("%w: items[%d]: sku is required", ErrInvalidItems, i)
+		}
+		if item.Length <= 0 || item.Width <= 0 || item.Height <= 0 {
+			return nil, fmt.Errorf("%w: %s: dimensions must be positive", ErrInvalidItems, sku)
+		}
+		if item.Weight <= 0 {
+			return nil, fmt.Errorf("%w: %s: weight must be positive", ErrInvalidItems, sku)
+		}
+		quantity := item.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		if quantity < 0 {
+			return nil, fmt.Errorf("%w: %s: quantity must be positive", ErrInvalidItems, sku)
+		}
+		if p.maxUnits > 0 && len(units)+quantity > p.maxUnits {
+			return nil, fmt.Errorf("%w: at most %d units per request", ErrInvalidItems, p.maxUnits)
+		}
+
+		u := unit{
+			sku:    sku,
+			dims:   sortedDims(item.Length, item.Width, item.Height),
+			volume: item.Length * item.Width * item.Height,
+			weight: item.Weight,
+		}
+		for range quantity {
+			units = append(units, u)
+		}
+	}
+	return units, nil
+}
+
+// largestFor returns the largest box a unit fits in on its own
+func (p *Packer) largestFor(u unit) (Box, bool) {
+	empty := &bin{}
+	for i := len(p.boxes) - 1; i >= 0; i-- {
+		if empty.fits(p.boxes[i], u) {
+			return p.boxes[i], true
+		}
+	}
+	return Box{}, false
+}
+
+// smallestFor returns the smallest box holding the contents of b, which is at most b.box
+func (p *Packer) smallestFor(b *bin) Box {
+	for _, box := range p.boxes {
+		if box.Volume() > b.box.Volume() {
+			break
+		}
+		if b.holds(box) {
+			return box
+		}
+	}
+	return b.box
+}
+
+// fits reports whether u can be added to the contents of b inside box
+func (b *bin) fits(box Box, u unit) bool {
+	if !dimsFit(u.dims, box) {
+		return false
+	}
+	if b.volume+u.volume > box.Volume() {
+		return false
+	}
+	return box.MaxWeight == 0 || b.weight+u.weight <= box.MaxWeight
+}
+
+// holds reports whether every unit of b fits in box together
+func (b *bin) holds(box Box) bool {
+	if b.volume > box.Volume() || (box.MaxWeight > 0 && b.weight > box.MaxWeight) {
+		return false
+	}
+	for _, u := range b.units {
+		if !dimsFit(u.dims, box) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *bin) add(u unit) {
+	b.units = append(b.units, u)
+	b.volume += u.volume
+	b.weight += u.weight
+}
+
+// packed returns the box with its contents counted by SKU, in packing order
+func (b *bin) packed() PackedBox {
+	var items []ItemCount
+	index := make(map[string]int)
+	for _, u := range b.units {
+		if i, ok := index[u.sku]; ok {
+			items[i].Quantity++
+			continue
+		}
+		index[u.sku] = len(items)
+		items = append(items, ItemCount{SKU: u.sku, Quantity: 1})
+	}
+	return PackedBox{
+		Box:      b.box,
+		Items:    items,
+		Weight:   b.weight,
+		FillRate: b.volume / b.box.Volume(),
+	}
+}
+
+// dimsFit reports whether dimensions sorted from the largest fit in box in some orientation
+func dimsFit(dims [3]float64, box Box) bool {
+	inner := sortedDims(box.Length, box.Width, box.Height)
+	for i := range dims {
+		if dims[i] > inner[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedDims(a, b, c float64) [3]float64 {
+	dims := []float64{a, b, c}
+	sort.Sort(sort.Reverse(sort.Float64Slice(dims)))
+	return [3]float64{dims[0], dims[1], dims[2]}
+}
+
+// Calculator computes a single quote
+type Calculator interface {
+	CalculateShipping(ctx context.Context, req *model.CalculateShippingRequest) (*model.CalculateShippingResponse, error)
+}
+
+// QuotedBox is a packed box and the quote for shipping it
+type QuotedBox struct {
+	PackedBox
+	Quote *model.CalculateShippingResponse
+}
+
+// Suggestion is the packed configuration of a shipment and its quote. ShippingCost is the sum of
+// the costs of the boxes, in minor units of Currency
+type Suggestion struct {
+	Boxes        []QuotedBox
+	Currency     string
+	ShippingCost money.Amount
+}
+
+// Suggester packs items and quotes each packed box as a package of the shipment
+type Suggester struct {
+	packer     *Packer
+	calculator Calculator
+}
+
+// NewSuggester creates a suggester packing with the catalog of cfg and quoting with calculator
+func NewSuggester(calculator Calculator, cfg Config) *Suggester {
+	return &Suggester{packer: NewPacker(cfg), calculator: calculator}
+}
+
+// Suggest packs items and quotes every box with the route and options of req; the weight and
+// dimensions of req are replaced by those of each box
+func (s *Suggester) Suggest(ctx context.Context, req *model.CalculateShippingRequest, items []Item) (*Suggestion, error) {
+	packed, err := s.packer.Pack(items)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion := &Suggestion{Boxes: make([]QuotedBox, 0, len(packed))}
+	for i, box := range packed {
+		boxReq := *req
+		boxReq.Weight = box.Weight
+		boxReq.Dimensions = model.PackageDimensions{Length: box.Box.Length, Width: box.Box.Width, Height: box.Box.Height}
+		quote, err := s.calculator.CalculateShipping(ctx, &boxReq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to quote box %d (%s): %w", i+1, box.Box.ID, err)
+		}
+		suggestion.Boxes = append(suggestion.Boxes, QuotedBox{PackedBox: box, Quote: quote})
+		suggestion.Currency = quote.Currency
+		suggestion.ShippingCost += quote.ShippingCost
+	}
+	return suggestion, nil
+}
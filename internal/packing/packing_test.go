@@ -0,0 +1,231 @@
+package packing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPack(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     []Item
+		wantBoxes []string
+		wantItems [][]ItemCount
+	}{
+		{
+			"single small item",
+			[]Item{{SKU: "mug", Length: 10, Width: 10, Height: 5, Weight: 1}},
+			[]string{"small"},
+			[][]ItemCount{{{SKU: "mug", Quantity: 1}}},
+		},
+		{
+			"units consolidated and box downsized",
+			[]Item{{SKU: "shoe", Length: 30, Width: 20, Height: 15, Weight: 2, Quantity: 2}},
+			[]string{"large"},
+			[][]ItemCount{{{SKU: "shoe", Quantity: 2}}},
+		},
+		{
+			"item rotated to fit",
+			[]Item{{SKU: "poster", Length: 10, Width: 35, Height: 5, Weight: 0.5}},
+			[]string{"large"},
+			[][]ItemCount{{{SKU: "poster", Quantity: 1}}},
+		},
+		{
+			"weight limit opens another box",
+			[]Item{{SKU: "weight", Length: 10, Width: 10, Height: 10, Weight: 9, Quantity: 4}},
+			[]string{"xlarge", "medium"},
+			[][]ItemCount{{{SKU: "weight", Quantity: 3}}, {{SKU: "weight", Quantity: 1}}},
+		},
+		{
+			"largest items packed first",
+			[]Item{
+				{SKU: "pen", Length: 15, Width: 2, Height: 2, Weight: 0.1, Quantity: 2},
+				{SKU: "book", Length: 25, Width: 18, Height: 4, Weight: 0.8},
+			},
+			[]string{"medium"},
+			[][]ItemCount{{{SKU: "book", Quantity: 1}, {SKU: "pen", Quantity: 2}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			packer := NewPacker(DefaultConfig())
+
+			// Act
+			boxes, err := packer.Pack(tt.items)
+
+			// Assert
+			assert.NoError(t, err)
+			ids := make([]string, 0, len(boxes))
+			items := make([][]ItemCount, 0, len(boxes))
+			for _, box := range boxes {
+				ids = append(ids, box.Box.ID)
+				items = append(items, box.Items)
+			}
+			assert.Equal(t, tt.wantBoxes, ids)
+			assert.Equal(t, tt.wantItems, items)
+		})
+	}
+}
+
+func TestPack_WeightAndFillRate(t *testing.T) {
+	// Arrange
+	packer := NewPacker(DefaultConfig())
+
+	// Act
+	boxes, err := packer.Pack([]Item{{SKU: "mug", Length: 10, Width: 10, Height: 5, Weight: 0.4, Quantity: 3}})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, boxes, 1)
+	assert.Equal(t, "small", boxes[0].Box.ID)
+	assert.InDelta(t, 1.2, boxes[0].Weight, 1e-9)
+	assert.InDelta(t, 0.5, boxes[0].FillRate, 1e-9)
+}
+
+func TestPack_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []Item
+		wantErr error
+		wantMsg string
+	}{
+		{"no items", nil, ErrInvalidItems, "at least one item is required"},
+		{"missing sku", []Item{{Length: 1, Width: 1, Height: 1, Weight: 1}}, ErrInvalidItems, "items[0]: sku is required"},
+		{"invalid dimensions", []Item{{SKU: "a", Length: 0, Width: 1, Height: 1, Weight: 1}}, ErrInvalidItems, "dimensions must be positive"},
+		{"invalid weight", []Item{{SKU: "a", Length: 1, Width: 1, Height: 1}}, ErrInvalidItems, "weight must be positive"},
+		{"negative quantity", []Item{{SKU: "a", Length: 1, Width: 1, Height: 1, Weight: 1, Quantity: -1}}, ErrInvalidItems, "quantity must be positive"},
+		{"too many units", []Item{{SKU: "a", Length: 1, Width: 1, Height: 1, Weight: 1, Quantity: 501}}, ErrInvalidItems, "at most 500 units"},
+		{"too long", []Item{{SKU: "rod", Length: 70, Width: 5, Height: 5, Weight: 1}}, ErrItemTooLarge, "rod"},
+		{"too heavy", []Item{{SKU: "anvil", Length: 10, Width: 10, Height: 10, Weight: 31}}, ErrItemTooLarge, "anvil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			packer := NewPacker(DefaultConfig())
+
+			// Act
+			boxes, err := packer.Pack(tt.items)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.ErrorContains(t, err, tt.wantMsg)
+			assert.Nil(t, boxes)
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"default", DefaultConfig(), ""},
+		{"no boxes", Config{}, "at least one box is required"},
+		{"missing id", Config{Boxes: []Box{{Name: "P", Length: 1, Width: 1, Height: 1}}}, "id is required"},
+		{"duplicate id", Config{Boxes: []Box{{ID: "p", Length: 1, Width: 1, Height: 1}, {ID: "p", Length: 2, Width: 2, Height: 2}}}, "duplicate id"},
+		{"invalid dimensions", Config{Boxes: []Box{{ID: "p", Length: 1, Width: 1}}}, "dimensions must be positive"},
+		{"negative max weight", Config{Boxes: []Box{{ID: "p", Length: 1, Width: 1, Height: 1, MaxWeight: -1}}}, "max_weight must not be negative"},
+		{"negative max units", Config{Boxes: []Box{{ID: "p", Length: 1, Width: 1, Height: 1}}, MaxUnits: -1}, "max_units must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := tt.cfg.Validate()
+
+			// Assert
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "boxes.json")
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(valid, []byte(`{"boxes": [
+		{"id": "envelope", "length": 30, "width": 20, "height": 2, "max_weight": 1}
+	]}`), 0o600))
+	assert.NoError(t, os.WriteFile(invalid, []byte(`{"boxes": []}`), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(valid)
+	_, invalidErr := LoadConfig(invalid)
+	_, missingErr := LoadConfig(filepath.Join(dir, "missing.json"))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []Box{{ID: "envelope", Length: 30, Width: 20, Height: 2, MaxWeight: 1}}, cfg.Boxes)
+	assert.Equal(t, 500, cfg.MaxUnits)
+	assert.ErrorContains(t, invalidErr, "invalid box catalog")
+	assert.ErrorContains(t, missingErr, "failed to read box catalog")
+}
+
+// weightCalculator quotes 1000 minor units per kilogram, recording the requests
+type weightCalculator struct {
+	requests []model.CalculateShippingRequest
+	err      error
+}
+
+func (c *weightCalculator) CalculateShipping(ctx context.Context, req *model.CalculateShippingRequest) (*model.CalculateShippingResponse, error) {
+	c.requests = append(c.requests, *req)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &model.CalculateShippingResponse{Currency: "BRL", ShippingCost: money.FromMinor(req.Weight * 1000)}, nil
+}
+
+func TestSuggest(t *testing.T) {
+	// Arrange
+	calculator := &weightCalculator{}
+	suggester := NewSuggester(calculator, DefaultConfig())
+	req := &model.CalculateShippingRequest{OriginZipcode: "01310100", DestinationZipcode: "20040020", IsExpress: true}
+	items := []Item{{SKU: "weight", Length: 10, Width: 10, Height: 10, Weight: 9, Quantity: 4}}
+
+	// Act
+	suggestion, err := suggester.Suggest(context.Background(), req, items)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, suggestion.Boxes, 2)
+	assert.Equal(t, "BRL", suggestion.Currency)
+	assert.Equal(t, money.FromMinor(36000), suggestion.ShippingCost)
+	assert.Equal(t, []model.CalculateShippingRequest{
+		{OriginZipcode: "01310100", DestinationZipcode: "20040020", IsExpress: true, Weight: 27,
+			Dimensions: model.PackageDimensions{Length: 60, Width: 40, Height: 40}},
+		{OriginZipcode: "01310100", DestinationZipcode: "20040020", IsExpress: true, Weight: 9,
+			Dimensions: model.PackageDimensions{Length: 30, Width: 20, Height: 15}},
+	}, calculator.requests)
+	assert.Equal(t, money.FromMinor(27000), suggestion.Boxes[0].Quote.ShippingCost)
+	assert.Equal(t, model.CalculateShippingRequest{OriginZipcode: "01310100", DestinationZipcode: "20040020", IsExpress: true}, *req)
+}
+
+func TestSuggest_CalculationError(t *testing.T) {
+	// Arrange
+	calculator := &weightCalculator{err: errors.New("origin_zipcode is required")}
+	suggester := NewSuggester(calculator, DefaultConfig())
+	items := []Item{{SKU: "mug", Length: 10, Width: 10, Height: 5, Weight: 1}}
+
+	// Act
+	suggestion, err := suggester.Suggest(context.Background(), &model.CalculateShippingRequest{}, items)
+
+	// Assert
+	assert.ErrorContains(t, err, "failed to quote box 1 (small): origin_zipcode is required")
+	assert.Nil(t, suggestion)
+}
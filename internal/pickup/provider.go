@@ -0,0 +1,137 @@
+// Package pickup lists the pickup points and parcel lockers where customers can collect packages.
+package pickup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+)
+
+// Pickup location types, matching the pickup_point and locker delivery types
+const (
+	TypePickupPoint = "pickup_point"
+	TypeLocker      = "locker"
+)
+
+// PickupPoint is a location where the customer collects the package
+type PickupPoint struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Address string `json:"address"`
+	City    string `json:"city"`
+	State   string `json:"state"`
+	Zipcode string `json:"zipcode"`
+	// OpeningHours is a free-form description, e.g. "seg-sex 08h-20h"
+	OpeningHours string `json:"opening_hours,omitempty"`
+}
+
+// PickupPointProvider lists pickup locations near a destination zipcode, closest first
+type PickupPointProvider interface {
+	NearbyPickupPoints(ctx context.Context, zipcode string, limit int) ([]PickupPoint, error)
+}
+
+// Config holds the pickup locations served by the static provider
+type Config struct {
+	Points []PickupPoint `json:"points"`
+}
+
+// Validate checks that every location has an id, a known type and a numeric 8-digit zipcode
+func (c Config) Validate() error {
+	seen := make(map[string]bool, len(c.Points))
+	for _, p := range c.Points {
+		if p.ID == "" {
+			return fmt.Errorf("pickup point %q: id is required", p.Name)
+		}
+		if seen[p.ID] {
+			return fmt.Errorf("pickup point %q: duplicate id", p.ID)
+		}
+		seen[p.ID] = true
+		if p.Type != TypePickupPoint && p.Type != TypeLocker {
+			return fmt.Errorf("pickup point %q: type must be %s or %s", p.ID, TypePickupPoint, TypeLocker)
+		}
+		if _, err := zipcodeNumber(p.Zipcode); err != nil || len(zipcode.Strip(p.Zipcode)) != zipcode.Length {
+			return fmt.Errorf("pickup point %q: invalid zipcode %q", p.ID, p.Zipcode)
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads and validates the pickup locations from a JSON file
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read pickup points config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse pickup points config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid pickup points config: %w", err)
+	}
+	return cfg, nil
+}
+
+// StaticProvider serves pickup locations from a fixed list. Locations sharing the first
+// three CEP digits with the destination are nearby; they are ordered by CEP distance
+type StaticProvider struct {
+	points []PickupPoint
+}
+
+// NewStaticProvider creates a provider for the configured locations
+func NewStaticProvider(cfg Config) *StaticProvider {
+	return &StaticProvider{points: append([]PickupPoint{}, cfg.Points...)}
+}
+
+// NearbyPickupPoints returns up to limit locations near the zipcode; limit <= 0 means no limit
+func (p *StaticProvider) NearbyPickupPoints(ctx context.Context, value string, limit int) ([]PickupPoint, error) {
+	target, err := zipcodeNumber(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zipcode %q", value)
+	}
+	sector := zipcode.Sector(value)
+
+	type candidate struct {
+		point    PickupPoint
+		distance int
+	}
+	var candidates []candidate
+	for _, point := range p.points {
+		if zipcode.Sector(point.Zipcode) != sector {
+			continue
+		}
+		number, _ := zipcodeNumber(point.Zipcode)
+		distance := number - target
+		if distance < 0 {
+			distance = -distance
+		}
+		candidates = append(candidates, candidate{point: point, distance: distance})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	points := make([]PickupPoint, len(candidates))
+	for i, c := range candidates {
+		points[i] = c.point
+	}
+	return points, nil
+}
+
+// zipcodeNumber converts a zipcode to a number right-padded to 8 digits, so prefixes compare
+// as the start of their range
+func zipcodeNumber(value string) (int, error) {
+	normalized := zipcode.Normalize(value)
+	if normalized == "" || len(normalized) > zipcode.Length {
+		return 0, fmt.Errorf("invalid zipcode %q", value)
+	}
+	return strconv.Atoi(normalized + strings.Repeat("0", zipcode.Length-len(normalized)))
+}
@@ -0,0 +1,120 @@
+package pickup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig() Config {
+	return Config{Points: []PickupPoint{
+		{ID: "far", Name: "Agência Paulista", Type: TypePickupPoint, Zipcode: "01399-000"},
+		{ID: "near", Name: "Locker Consolação", Type: TypeLocker, Zipcode: "01310-200"},
+		{ID: "other-region", Name: "Locker Pinheiros", Type: TypeLocker, Zipcode: "05422-000"},
+		{ID: "exact", Name: "Loja Augusta", Type: TypePickupPoint, Zipcode: "01310100"},
+	}}
+}
+
+func TestNearbyPickupPoints(t *testing.T) {
+	// Arrange
+	provider := NewStaticProvider(testConfig())
+
+	// Act
+	points, err := provider.NearbyPickupPoints(context.Background(), "01310-100", 0)
+
+	// Assert
+	assert.NoError(t, err)
+	ids := make([]string, len(points))
+	for i, p := range points {
+		ids[i] = p.ID
+	}
+	assert.Equal(t, []string{"exact", "near", "far"}, ids)
+}
+
+func TestNearbyPickupPoints_Limit(t *testing.T) {
+	// Arrange
+	provider := NewStaticProvider(testConfig())
+
+	// Act
+	points, err := provider.NearbyPickupPoints(context.Background(), "01310100", 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, points, 1)
+	assert.Equal(t, "exact", points[0].ID)
+}
+
+func TestNearbyPickupPoints_NoneNearby(t *testing.T) {
+	// Arrange
+	provider := NewStaticProvider(testConfig())
+
+	// Act
+	points, err := provider.NearbyPickupPoints(context.Background(), "90000000", 10)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, points)
+}
+
+func TestNearbyPickupPoints_InvalidZipcode(t *testing.T) {
+	// Arrange
+	provider := NewStaticProvider(testConfig())
+
+	// Act
+	_, err := provider.NearbyPickupPoints(context.Background(), "abc", 10)
+
+	// Assert
+	assert.ErrorContains(t, err, "invalid zipcode")
+}
+
+func TestValidate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		point   PickupPoint
+		wantErr string
+	}{
+		{"missing id", PickupPoint{Name: "x", Type: TypeLocker, Zipcode: "01310100"}, "id is required"},
+		{"duplicate id", PickupPoint{ID: "exact", Type: TypeLocker, Zipcode: "01310100"}, "duplicate id"},
+		{"unknown type", PickupPoint{ID: "x", Type: "store", Zipcode: "01310100"}, "type must be"},
+		{"invalid zipcode", PickupPoint{ID: "x", Type: TypeLocker, Zipcode: "0131"}, "invalid zipcode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := testConfig()
+			cfg.Points = append(cfg.Points, tt.point)
+
+			// Act
+			err := cfg.Validate()
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "points.json")
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(valid, []byte(`{"points": [
+		{"id": "L1", "name": "Locker Paulista", "type": "locker", "zipcode": "01310-100"}
+	]}`), 0o600))
+	assert.NoError(t, os.WriteFile(invalid, []byte(`{"points": [{"id": "L1", "type": "van"}]}`), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(valid)
+	_, invalidErr := LoadConfig(invalid)
+	_, missingErr := LoadConfig(filepath.Join(dir, "missing.json"))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Points, 1)
+	assert.ErrorContains(t, invalidErr, "invalid pickup points config")
+	assert.ErrorContains(t, missingErr, "failed to read pickup points config")
+}
@@ -0,0 +1,114 @@
+package pricing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// CarrierConfig configures the carrier rate API used by CarrierPricing
+type CarrierConfig struct {
+	// URL receives a POST with the shipment and answers {"cost": <minor units>}; empty disables
+	// the carrier strategy
+	URL     string
+	Timeout time.Duration
+}
+
+// CarrierConfigFromEnv reads CARRIER_RATES_URL and CARRIER_RATES_TIMEOUT (default 5s)
+func CarrierConfigFromEnv() (CarrierConfig, error) {
+	timeout, err := config.Duration("CARRIER_RATES_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return CarrierConfig{}, err
+	}
+	if timeout <= 0 {
+		return CarrierConfig{}, fmt.Errorf("CARRIER_RATES_TIMEOUT must be positive, got %s", timeout)
+	}
+	return CarrierConfig{
+		URL:     config.String("CARRIER_RATES_URL", ""),
+		Timeout: timeout,
+	}, nil
+}
+
+// Enabled reports whether a carrier rate API is configured
+func (c CarrierConfig) Enabled() bool {
+	return c.URL != ""
+}
+
+// HTTPCarrierRates quotes shipments with a carrier rate API over HTTP
+type HTTPCarrierRates struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewHTTPCarrierRates creates a carrier rate client from the configuration
+func NewHTTPCarrierRates(cfg CarrierConfig) *HTTPCarrierRates {
+	return &HTTPCarrierRates{URL: cfg.URL, Client: &http.Client{Timeout: cfg.Timeout}}
+}
+
+type carrierRateRequest struct {
+	OriginZipcode      string  `json:"origin_zipcode"`
+	DestinationZipcode string  `json:"destination_zipcode"`
+	DestinationCountry string  `json:"destination_country,omitempty"`
+	Currency           string  `json:"currency"`
+	Service            string  `json:"service"`
+	Weight             float64 `json:"weight"`
+	Volume             float64 `json:"volume"`
+}
+
+type carrierRateResponse struct {
+	Cost *money.Amount `json:"cost"`
+}
+
+// Rate implements CarrierRates
+func (c *HTTPCarrierRates) Rate(ctx context.Context, shipment Shipment) (money.Amount, error) {
+	body, err := json.Marshal(carrierRateRequest{
+		OriginZipcode:      shipment.OriginZipcode,
+		DestinationZipcode: shipment.DestinationZipcode,
+		DestinationCountry: shipment.DestinationCountry,
+		Currency:           shipment.Currency,
+		Service:            shipment.Level,
+		Weight:             shipment.Weight,
+		Volume:             shipment.Volume,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode carrier rate request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to request carrier rate: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to request carrier rate: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to request carrier rate: unexpected status %d", resp.StatusCode)
+	}
+
+	var rate carrierRateResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&rate); err != nil {
+		return 0, fmt.Errorf("failed to parse carrier rate: %w", err)
+	}
+	if rate.Cost == nil {
+		return 0, fmt.Errorf("failed to parse carrier rate: missing cost")
+	}
+	return *rate.Cost, nil
+}
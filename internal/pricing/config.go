@@ -0,0 +1,622 @@
+// Package pricing holds the rate configuration used to quote shipments.
+package pricing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+)
+
+// ErrUnsupportedCurrency is returned when no rates are configured for the requested currency
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// ErrUnsupportedCountry is returned when the destination country is not served
+var ErrUnsupportedCountry = errors.New("unsupported destination country")
+
+// ErrUnsupportedPackageType is returned when no rule is configured for the package type
+var ErrUnsupportedPackageType = errors.New("unsupported package type")
+
+// ErrUnsupportedAdditionalService is returned when no fee is configured for the additional service
+var ErrUnsupportedAdditionalService = errors.New("unsupported additional service")
+
+// Additional services
+const (
+	ServiceCashOnDelivery   = "cod"
+	ServiceSignature        = "signature"
+	ServiceSaturdayDelivery = "saturday_delivery"
+)
+
+// ErrUnsupportedDeliveryType is returned when no rule is configured for the delivery type
+var ErrUnsupportedDeliveryType = errors.New("unsupported delivery type")
+
+// Delivery types
+const (
+	DeliveryHome        = "home"
+	DeliveryPickupPoint = "pickup_point"
+	DeliveryLocker      = "locker"
+)
+
+// DeliveryType holds the price adjustment of a delivery type
+type DeliveryType struct {
+	// CostAdjustmentRate is the fraction of the subtotal (base, weight, volume and package type)
+	// added to the cost; negative values are discounts, e.g. -0.20 for lockers
+	CostAdjustmentRate float64 `json:"cost_adjustment_rate"`
+}
+
+// ReturnPolicy holds the pricing of return shipments, from the customer back to the origin
+type ReturnPolicy struct {
+	// CostAdjustmentRate is the fraction of the subtotal (base, weight, volume, package type and
+	// delivery type) added to the cost of returns; negative values are discounts
+	CostAdjustmentRate float64 `json:"cost_adjustment_rate"`
+	// Services are the service levels offered for returns; standard is always offered
+	Services []string `json:"services"`
+}
+
+// Offers reports whether returns can be shipped with a service level
+func (p ReturnPolicy) Offers(level string) bool {
+	for _, service := range p.Services {
+		if service == level {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultReturnPolicy returns the built-in return policy: returns are priced as outbound
+// shipments and offered only with standard delivery
+func DefaultReturnPolicy() *ReturnPolicy {
+	return &ReturnPolicy{Services: []string{LevelStandard}}
+}
+
+// Package types
+const (
+	PackageStandard   = "standard"
+	PackageFragile    = "fragile"
+	PackagePerishable = "perishable"
+	PackageDangerous  = "dangerous"
+)
+
+// PackageType holds the surcharge and handling restrictions of a package type
+type PackageType struct {
+	// SurchargeRate is the fraction of the subtotal (base, weight and volume) added as handling fee
+	SurchargeRate float64 `json:"surcharge_rate"`
+	// ExpressProhibited forbids express delivery, e.g. for dangerous goods that cannot fly
+	ExpressProhibited bool `json:"express_prohibited"`
+}
+
+// Rounding modes of the final costs
+const (
+	// RoundHalfUp rounds to the nearest increment, halves away from zero
+	RoundHalfUp = "half_up"
+	// RoundHalfEven rounds to the nearest increment, halves to the even multiple (banker's rounding)
+	RoundHalfEven = "half_even"
+	// RoundUp rounds up to the next increment
+	RoundUp = "up"
+)
+
+// Rates holds the pricing parameters of a currency. Costs are in minor units (e.g. cents)
+type Rates struct {
+	// BaseCost is the cost of a shipment within the same region
+	BaseCost money.Amount `json:"base_cost"`
+	// WeightSurchargeRate is the fraction of the base cost charged per WeightUnitKg
+	WeightSurchargeRate float64 `json:"weight_surcharge_rate"`
+	WeightUnitKg        float64 `json:"weight_unit_kg"`
+	// VolumeSurchargeRate is the fraction of the base cost charged per VolumeUnitCm3
+	VolumeSurchargeRate float64 `json:"volume_surcharge_rate"`
+	VolumeUnitCm3       float64 `json:"volume_unit_cm3"`
+	// ExpressSurchargeRate is the fraction of the subtotal added for express delivery
+	ExpressSurchargeRate float64 `json:"express_surcharge_rate"`
+	// RoundingIncrement rounds final costs to a multiple of this many minor units (e.g. 5 rounds
+	// to 0.05, 100 to whole units) following RoundingMode; 0 disables rounding
+	RoundingIncrement money.Amount `json:"rounding_increment"`
+	// RoundingMode is RoundHalfUp (default), RoundHalfEven or RoundUp
+	RoundingMode string `json:"rounding_mode,omitempty"`
+	// AdditionalServices maps the optional services offered in this currency to their fixed fee
+	AdditionalServices map[string]money.Amount `json:"additional_services,omitempty"`
+	// WeightTable holds the weight brackets used by TablePricing, in ascending order of weight
+	WeightTable []WeightBracket `json:"weight_table,omitempty"`
+	// PriceLimits are the minimum and maximum freight per service level and route zone
+	PriceLimits []PriceLimit `json:"price_limits,omitempty"`
+	// Regions are the base costs and transit days of Brazilian routes by state or macro-region
+	Regions []RegionalRate `json:"regions,omitempty"`
+	// Freight prices heavy shipments by freight class; nil does not offer freight
+	Freight *FreightRates `json:"freight,omitempty"`
+}
+
+// WeightBracket is the cost of shipments weighing up to MaxWeightKg
+type WeightBracket struct {
+	MaxWeightKg float64      `json:"max_weight_kg"`
+	Cost        money.Amount `json:"cost"`
+}
+
+// Round applies the rounding rule of the currency to a cost
+func (r Rates) Round(cost money.Amount) money.Amount {
+	switch r.RoundingMode {
+	case RoundHalfEven:
+		return cost.RoundHalfEven(r.RoundingIncrement)
+	case RoundUp:
+		return cost.RoundUp(r.RoundingIncrement)
+	default:
+		return cost.RoundHalfUp(r.RoundingIncrement)
+	}
+}
+
+// Validate checks that the rates are usable
+func (r Rates) Validate() error {
+	if r.BaseCost < 0 {
+		return errors.New("base_cost must not be negative")
+	}
+	if r.WeightSurchargeRate < 0 || r.VolumeSurchargeRate < 0 || r.ExpressSurchargeRate < 0 {
+		return errors.New("surcharge rates must not be negative")
+	}
+	if r.WeightUnitKg <= 0 || r.VolumeUnitCm3 <= 0 {
+		return errors.New("weight_unit_kg and volume_unit_cm3 must be positive")
+	}
+	if r.RoundingIncrement < 0 {
+		return errors.New("rounding_increment must not be negative")
+	}
+	switch r.RoundingMode {
+	case "", RoundHalfUp, RoundHalfEven, RoundUp:
+	default:
+		return fmt.Errorf("unsupported rounding_mode %q", r.RoundingMode)
+	}
+	for service, fee := range r.AdditionalServices {
+		if fee < 0 {
+			return fmt.Errorf("additional service %q: fee must not be negative", service)
+		}
+	}
+	for i, bracket := range r.WeightTable {
+		if bracket.MaxWeightKg <= 0 || bracket.Cost < 0 {
+			return fmt.Errorf("weight_table[%d]: max_weight_kg must be positive and cost must not be negative", i)
+		}
+		if i > 0 && bracket.MaxWeightKg <= r.WeightTable[i-1].MaxWeightKg {
+			return fmt.Errorf("weight_table[%d]: brackets must be in ascending order of max_weight_kg", i)
+		}
+	}
+	if err := validatePriceLimits(r.PriceLimits); err != nil {
+		return err
+	}
+	if err := validateFreight(r.Freight); err != nil {
+		return err
+	}
+	return validateRegions(r.Regions)
+}
+
+// AdditionalServiceFee returns the fee of an additional service
+func (r Rates) AdditionalServiceFee(service string) (money.Amount, error) {
+	fee, ok := r.AdditionalServices[strings.ToLower(strings.TrimSpace(service))]
+	if !ok {
+		return 0, fmt.Errorf("%w %q", ErrUnsupportedAdditionalService, service)
+	}
+	return fee, nil
+}
+
+// Config holds the rates per currency and the currency used for each destination country
+type Config struct {
+	// Version identifies the rate table, e.g. "2025.03"; when empty VersionID derives one from
+	// the content of the configuration
+	Version string `json:"version,omitempty"`
+	// DefaultCountry is assumed when the request has no destination country
+	DefaultCountry string `json:"default_country"`
+	// Currencies maps ISO 4217 codes to their rates
+	Currencies map[string]Rates `json:"currencies"`
+	// Countries maps ISO 3166-1 alpha-2 destination countries to the currency they are quoted in
+	Countries map[string]string `json:"countries"`
+	// PackageTypes maps package types to their surcharge and restrictions; when absent from a
+	// configuration file the defaults of DefaultPackageTypes are used
+	PackageTypes map[string]PackageType `json:"package_types,omitempty"`
+	// DeliveryTypes maps delivery types to their price adjustment; when absent from a
+	// configuration file the defaults of DefaultDeliveryTypes are used
+	DeliveryTypes map[string]DeliveryType `json:"delivery_types,omitempty"`
+	// Strategies maps service levels ("standard", "express") to the strategy that prices them
+	// ("formula", "table" or "carrier"); levels not listed use "formula"
+	Strategies map[string]string `json:"strategies,omitempty"`
+	// Returns holds the adjustment and service levels of return quotes; when absent from a
+	// configuration file the defaults of DefaultReturnPolicy are used
+	Returns *ReturnPolicy `json:"returns,omitempty"`
+	// RestrictedAreas are the destination CEP ranges not served, or served with a surcharge, by
+	// some or all service levels
+	RestrictedAreas []RestrictedArea `json:"restricted_areas,omitempty"`
+	// RemoteAreas are the destination CEP ranges of difficult access served with a surcharge and
+	// extra transit days by some or all service levels
+	RemoteAreas []RemoteArea `json:"remote_areas,omitempty"`
+	// FuelSurcharge is the fuel surcharge index charged on every service level; nil charges none
+	FuelSurcharge *FuelSurcharge `json:"fuel_surcharge,omitempty"`
+	// WeekendDelivery maps service levels ("standard", "express", "freight") to the weekend days
+	// they deliver on when a request allows weekend delivery; when set, Saturdays and Sundays are
+	// not delivery days otherwise. Nil counts every day as a delivery day
+	WeekendDelivery map[string]WeekendDelivery `json:"weekend_delivery,omitempty"`
+}
+
+// DefaultConfig returns the built-in rates: BRL for Brazil, USD for the United States and EUR
+// for the main euro area destinations
+func DefaultConfig() Config {
+	return Config{
+		DefaultCountry: "BR",
+		Currencies: map[string]Rates{
+			"BRL": DefaultRates(),
+			"USD": {
+				BaseCost:             money.FromMinor(500),
+				WeightSurchargeRate:  0.10,
+				WeightUnitKg:         0.5,
+				VolumeSurchargeRate:  0.05,
+				VolumeUnitCm3:        1000,
+				ExpressSurchargeRate: 0.50,
+				RoundingIncrement:    money.FromMinor(1),
+				AdditionalServices: map[string]money.Amount{
+					ServiceCashOnDelivery:   money.FromMinor(250),
+					ServiceSignature:        money.FromMinor(150),
+					ServiceSaturdayDelivery: money.FromMinor(500),
+				},
+			},
+			"EUR": {
+				BaseCost:             money.FromMinor(450),
+				WeightSurchargeRate:  0.10,
+				WeightUnitKg:         0.5,
+				VolumeSurchargeRate:  0.05,
+				VolumeUnitCm3:        1000,
+				ExpressSurchargeRate: 0.50,
+				RoundingIncrement:    money.FromMinor(1),
+				AdditionalServices: map[string]money.Amount{
+					ServiceCashOnDelivery:   money.FromMinor(250),
+					ServiceSignature:        money.FromMinor(150),
+					ServiceSaturdayDelivery: money.FromMinor(450),
+				},
+			},
+		},
+		Countries: map[string]string{
+			"BR": "BRL",
+			"US": "USD",
+			"DE": "EUR",
+			"ES": "EUR",
+			"FR": "EUR",
+			"IT": "EUR",
+			"NL": "EUR",
+			"PT": "EUR",
+		},
+		PackageTypes:  DefaultPackageTypes(),
+		DeliveryTypes: DefaultDeliveryTypes(),
+		Returns:       DefaultReturnPolicy(),
+	}
+}
+
+// DefaultDeliveryTypes returns the built-in delivery types: home, pickup point (-10%) and locker (-20%)
+func DefaultDeliveryTypes() map[string]DeliveryType {
+	return map[string]DeliveryType{
+		DeliveryHome:        {},
+		DeliveryPickupPoint: {CostAdjustmentRate: -0.10},
+		DeliveryLocker:      {CostAdjustmentRate: -0.20},
+	}
+}
+
+// DefaultPackageTypes returns the built-in package types: fragile (+15%), perishable (+20%)
+// and dangerous (+30%, no express delivery)
+func DefaultPackageTypes() map[string]PackageType {
+	return map[string]PackageType{
+		PackageStandard:   {},
+		PackageFragile:    {SurchargeRate: 0.15},
+		PackagePerishable: {SurchargeRate: 0.20},
+		PackageDangerous:  {SurchargeRate: 0.30, ExpressProhibited: true},
+	}
+}
+
+// DefaultRates returns the BRL rates: 10.00 BRL base cost, 10% per 0.5 kg, 5% per 1000 cm³,
+// 50% for express delivery and 5.00, 3.00 and 10.00 BRL for cash on delivery, signature and
+// Saturday delivery, without price limits
+func DefaultRates() Rates {
+	return Rates{
+		BaseCost:             money.FromMinor(1000),
+		WeightSurchargeRate:  0.10,
+		WeightUnitKg:         0.5,
+		VolumeSurchargeRate:  0.05,
+		VolumeUnitCm3:        1000,
+		ExpressSurchargeRate: 0.50,
+		AdditionalServices: map[string]money.Amount{
+			ServiceCashOnDelivery:   money.FromMinor(500),
+			ServiceSignature:        money.FromMinor(300),
+			ServiceSaturdayDelivery: money.FromMinor(1000),
+		},
+	}
+}
+
+// Validate checks that every currency has valid rates and every country maps to a configured currency
+func (c Config) Validate() error {
+	if len(c.Currencies) == 0 {
+		return errors.New("at least one currency is required")
+	}
+	for code, rates := range c.Currencies {
+		if err := rates.Validate(); err != nil {
+			return fmt.Errorf("currency %q: %w", code, err)
+		}
+	}
+	for country, currency := range c.Countries {
+		if _, ok := c.Currencies[currency]; !ok {
+			return fmt.Errorf("country %q: currency %q is not configured", country, currency)
+		}
+	}
+	if _, ok := c.Countries[c.DefaultCountry]; !ok {
+		return fmt.Errorf("default_country %q is not configured", c.DefaultCountry)
+	}
+	if _, ok := c.PackageTypes[PackageStandard]; !ok {
+		return fmt.Errorf("package type %q is required", PackageStandard)
+	}
+	for name, packageType := range c.PackageTypes {
+		if packageType.SurchargeRate < 0 {
+			return fmt.Errorf("package type %q: surcharge_rate must not be negative", name)
+		}
+	}
+	if _, ok := c.DeliveryTypes[DeliveryHome]; !ok {
+		return fmt.Errorf("delivery type %q is required", DeliveryHome)
+	}
+	for name, deliveryType := range c.DeliveryTypes {
+		if deliveryType.CostAdjustmentRate < -1 {
+			return fmt.Errorf("delivery type %q: cost_adjustment_rate must not be below -1", name)
+		}
+	}
+	for level, strategy := range c.Strategies {
+		if level != LevelStandard && level != LevelExpress {
+			return fmt.Errorf("strategies: unknown service level %q", level)
+		}
+		if strategy != StrategyFormula && strategy != StrategyTable && strategy != StrategyCarrier {
+			return fmt.Errorf("strategies: service level %q: %w %q", level, ErrUnsupportedStrategy, strategy)
+		}
+	}
+	if c.Returns != nil {
+		if c.Returns.CostAdjustmentRate < -1 {
+			return errors.New("returns: cost_adjustment_rate must not be below -1")
+		}
+		if !c.Returns.Offers(LevelStandard) {
+			return fmt.Errorf("returns: service level %q is required", LevelStandard)
+		}
+		for _, level := range c.Returns.Services {
+			if level != LevelStandard && level != LevelExpress {
+				return fmt.Errorf("returns: unknown service level %q", level)
+			}
+		}
+	}
+	if c.FuelSurcharge != nil {
+		if err := c.FuelSurcharge.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := validateWeekendDelivery(c.WeekendDelivery); err != nil {
+		return err
+	}
+	if err := validateRestrictedAreas(c.RestrictedAreas); err != nil {
+		return err
+	}
+	return validateRemoteAreas(c.RemoteAreas)
+}
+
+// LoadConfig reads and validates the pricing configuration from a JSON file
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read pricing config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse pricing config: %w", err)
+	}
+	cfg = cfg.normalized()
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid pricing config: %w", err)
+	}
+	return cfg, nil
+}
+
+// VersionID identifies the rate table in force so quotes can be traced to it: the configured
+// Version or, when absent, "sha256:" followed by the first 12 hex digits of the hash of the
+// configuration content
+func (c Config) VersionID() string {
+	if c.Version != "" {
+		return c.Version
+	}
+	// encoding/json sorts map keys, so equal configurations always hash the same
+	data, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])[:12]
+}
+
+// Resolve selects the currency and rates of a quote. An explicit currency takes precedence;
+// otherwise the currency of the destination country (or the default country) is used
+func (c Config) Resolve(country, currency string) (string, Rates, error) {
+	country = c.Country(country)
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+
+	countryCurrency, ok := c.Countries[country]
+	if !ok {
+		return "", Rates{}, fmt.Errorf("%w %q", ErrUnsupportedCountry, country)
+	}
+	if currency == "" {
+		currency = countryCurrency
+	}
+
+	rates, ok := c.Currencies[currency]
+	if !ok {
+		return "", Rates{}, fmt.Errorf("%w %q", ErrUnsupportedCurrency, currency)
+	}
+	return currency, rates, nil
+}
+
+// Country normalizes a destination country code, defaulting to DefaultCountry when empty
+func (c Config) Country(country string) string {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" {
+		return c.DefaultCountry
+	}
+	return country
+}
+
+// ResolvePackageType returns the rule of the package type; an empty name means standard
+func (c Config) ResolvePackageType(name string) (PackageType, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = PackageStandard
+	}
+	packageType, ok := c.PackageTypes[name]
+	if !ok {
+		return PackageType{}, fmt.Errorf("%w %q", ErrUnsupportedPackageType, name)
+	}
+	return packageType, nil
+}
+
+// ResolveDeliveryType returns the rule of the delivery type; an empty name means home delivery
+func (c Config) ResolveDeliveryType(name string) (DeliveryType, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = DeliveryHome
+	}
+	deliveryType, ok := c.DeliveryTypes[name]
+	if !ok {
+		return DeliveryType{}, fmt.Errorf("%w %q", ErrUnsupportedDeliveryType, name)
+	}
+	return deliveryType, nil
+}
+
+// ResolveReturns returns the return policy, or the default policy when none is configured
+func (c Config) ResolveReturns() ReturnPolicy {
+	if c.Returns == nil {
+		return *DefaultReturnPolicy()
+	}
+	return *c.Returns
+}
+
+// SupportedDeliveryTypes returns the configured delivery types, sorted
+func (c Config) SupportedDeliveryTypes() []string {
+	return sortedKeys(c.DeliveryTypes)
+}
+
+// SupportedPackageTypes returns the configured package types, sorted
+func (c Config) SupportedPackageTypes() []string {
+	return sortedKeys(c.PackageTypes)
+}
+
+// SupportedAdditionalServices returns the additional services offered in any currency, sorted
+func (c Config) SupportedAdditionalServices() []string {
+	services := make(map[string]struct{})
+	for _, rates := range c.Currencies {
+		for service := range rates.AdditionalServices {
+			services[service] = struct{}{}
+		}
+	}
+	return sortedKeys(services)
+}
+
+// DefaultCurrency returns the currency of the default country
+func (c Config) DefaultCurrency() string {
+	return c.Countries[c.DefaultCountry]
+}
+
+// SupportedCountries returns the configured destination countries, sorted
+func (c Config) SupportedCountries() []string {
+	return sortedKeys(c.Countries)
+}
+
+// SupportedCurrencies returns the configured currencies, sorted
+func (c Config) SupportedCurrencies() []string {
+	return sortedKeys(c.Currencies)
+}
+
+// normalized upper-cases country and currency codes, lower-cases package types, delivery types,
+// additional services and service levels so lookups are case-insensitive and normalizes the CEPs
+// of the restricted areas, filling in the default package types, delivery types and return policy
+// when none are configured
+func (c Config) normalized() Config {
+	out := Config{
+		Version:        strings.TrimSpace(c.Version),
+		DefaultCountry: strings.ToUpper(c.DefaultCountry),
+		Currencies:     make(map[string]Rates, len(c.Currencies)),
+		Countries:      make(map[string]string, len(c.Countries)),
+	}
+	for code, rates := range c.Currencies {
+		if rates.AdditionalServices != nil {
+			services := make(map[string]money.Amount, len(rates.AdditionalServices))
+			for service, fee := range rates.AdditionalServices {
+				services[strings.ToLower(service)] = fee
+			}
+			rates.AdditionalServices = services
+		}
+		rates.RoundingMode = strings.ToLower(strings.TrimSpace(rates.RoundingMode))
+		if rates.PriceLimits != nil {
+			limits := make([]PriceLimit, len(rates.PriceLimits))
+			for i, limit := range rates.PriceLimits {
+				limit.Service = strings.ToLower(strings.TrimSpace(limit.Service))
+				limit.Zone = strings.ToLower(strings.TrimSpace(limit.Zone))
+				limits[i] = limit
+			}
+			rates.PriceLimits = limits
+		}
+		if rates.Regions != nil {
+			regions := make([]RegionalRate, len(rates.Regions))
+			for i, rate := range rates.Regions {
+				rate.Origin = normalizeRegion(rate.Origin)
+				rate.Destination = normalizeRegion(rate.Destination)
+				regions[i] = rate
+			}
+			rates.Regions = regions
+		}
+		out.Currencies[strings.ToUpper(code)] = rates
+	}
+	for country, currency := range c.Countries {
+		out.Countries[strings.ToUpper(country)] = strings.ToUpper(currency)
+	}
+	if len(c.PackageTypes) == 0 {
+		out.PackageTypes = DefaultPackageTypes()
+	} else {
+		out.PackageTypes = make(map[string]PackageType, len(c.PackageTypes))
+		for name, packageType := range c.PackageTypes {
+			out.PackageTypes[strings.ToLower(name)] = packageType
+		}
+	}
+	if len(c.DeliveryTypes) == 0 {
+		out.DeliveryTypes = DefaultDeliveryTypes()
+	} else {
+		out.DeliveryTypes = make(map[string]DeliveryType, len(c.DeliveryTypes))
+		for name, deliveryType := range c.DeliveryTypes {
+			out.DeliveryTypes[strings.ToLower(name)] = deliveryType
+		}
+	}
+	if len(c.Strategies) > 0 {
+		out.Strategies = make(map[string]string, len(c.Strategies))
+		for level, strategy := range c.Strategies {
+			out.Strategies[strings.ToLower(level)] = strings.ToLower(strategy)
+		}
+	}
+	if c.Returns == nil {
+		out.Returns = DefaultReturnPolicy()
+	} else {
+		out.Returns = &ReturnPolicy{CostAdjustmentRate: c.Returns.CostAdjustmentRate}
+		for _, level := range c.Returns.Services {
+			out.Returns.Services = append(out.Returns.Services, strings.ToLower(strings.TrimSpace(level)))
+		}
+	}
+	for _, area := range c.RestrictedAreas {
+		out.RestrictedAreas = append(out.RestrictedAreas, normalizeRestrictedArea(area))
+	}
+	for _, area := range c.RemoteAreas {
+		out.RemoteAreas = append(out.RemoteAreas, normalizeRemoteArea(area))
+	}
+	out.FuelSurcharge = normalizeFuelSurcharge(c.FuelSurcharge)
+	out.WeekendDelivery = normalizeWeekendDelivery(c.WeekendDelivery)
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
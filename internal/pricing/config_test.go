@@ -0,0 +1,401 @@
+package pricing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultConfig_IsValid(t *testing.T) {
+	// Act
+	err := DefaultConfig().Validate()
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name         string
+		country      string
+		currency     string
+		wantCurrency string
+		wantErr      error
+	}{
+		{"default country", "", "", "BRL", nil},
+		{"destination country", "US", "", "USD", nil},
+		{"lower case codes", "fr", "", "EUR", nil},
+		{"explicit currency", "BR", "eur", "EUR", nil},
+		{"explicit currency without country", "", "USD", "USD", nil},
+		{"unknown country", "JP", "", "", ErrUnsupportedCountry},
+		{"unknown currency", "", "JPY", "", ErrUnsupportedCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			currency, rates, err := DefaultConfig().Resolve(tt.country, tt.currency)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantCurrency, currency)
+			if tt.wantErr == nil {
+				assert.Greater(t, rates.BaseCost, money.Amount(0))
+			}
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		name      string
+		increment float64
+		mode      string
+		cost      float64
+		want      float64
+	}{
+		{"disabled", 0, "", 1234.5678, 1234.5678},
+		{"disabled removes float artifacts", 0, "", 1112.0000000002, 1112},
+		{"nearest cent", 1, "", 1234.5, 1235},
+		{"nearest cent down", 1, "", 1234.4999, 1234},
+		{"nearest five cents", 5, "", 1232.4, 1230},
+		{"nearest five cents up", 5, "", 1232.5, 1235},
+		{"whole units", 100, "", 1250, 1300},
+		{"half up explicit", 100, RoundHalfUp, 1350, 1400},
+		{"half even rounds halves to even", 100, RoundHalfEven, 1250, 1200},
+		{"half even rounds odd halves up", 100, RoundHalfEven, 1350, 1400},
+		{"half even rounds non halves to nearest", 100, RoundHalfEven, 1251, 1300},
+		{"up to whole units", 100, RoundUp, 1201, 1300},
+		{"up keeps exact multiples", 100, RoundUp, 1200, 1200},
+		{"up ignores float artifacts", 100, RoundUp, 1200.0000000002, 1200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := Rates{RoundingIncrement: money.FromMinor(tt.increment), RoundingMode: tt.mode}.Round(money.FromMinor(tt.cost))
+
+			// Assert
+			assert.Equal(t, money.FromMinor(tt.want), got)
+		})
+	}
+}
+
+func TestResolvePackageType(t *testing.T) {
+	tests := []struct {
+		name        string
+		packageType string
+		want        PackageType
+		wantErr     error
+	}{
+		{"empty means standard", "", PackageType{}, nil},
+		{"fragile", "fragile", PackageType{SurchargeRate: 0.15}, nil},
+		{"case-insensitive", " Dangerous ", PackageType{SurchargeRate: 0.30, ExpressProhibited: true}, nil},
+		{"unknown type", "explosive", PackageType{}, ErrUnsupportedPackageType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			packageType, err := DefaultConfig().ResolvePackageType(tt.packageType)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, packageType)
+		})
+	}
+}
+
+func TestResolveDeliveryType(t *testing.T) {
+	tests := []struct {
+		name         string
+		deliveryType string
+		want         DeliveryType
+		wantErr      error
+	}{
+		{"empty means home", "", DeliveryType{}, nil},
+		{"pickup point", "pickup_point", DeliveryType{CostAdjustmentRate: -0.10}, nil},
+		{"case-insensitive", "LOCKER", DeliveryType{CostAdjustmentRate: -0.20}, nil},
+		{"unknown type", "drone", DeliveryType{}, ErrUnsupportedDeliveryType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			deliveryType, err := DefaultConfig().ResolveDeliveryType(tt.deliveryType)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, deliveryType)
+		})
+	}
+}
+
+func TestAdditionalServiceFee(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		wantFee float64
+		wantErr error
+	}{
+		{"cash on delivery", "cod", 500, nil},
+		{"case-insensitive", " Saturday_Delivery ", 1000, nil},
+		{"unknown service", "insurance", 0, ErrUnsupportedAdditionalService},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			fee, err := DefaultRates().AdditionalServiceFee(tt.service)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, money.FromMinor(tt.wantFee), fee)
+		})
+	}
+}
+
+func TestValidate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr string
+	}{
+		{"no currencies", func(c *Config) { c.Currencies = nil }, "at least one currency"},
+		{"negative base cost", func(c *Config) {
+			r := c.Currencies["BRL"]
+			r.BaseCost = money.FromMinor(-1)
+			c.Currencies["BRL"] = r
+		}, "base_cost"},
+		{"negative rate", func(c *Config) {
+			r := c.Currencies["BRL"]
+			r.ExpressSurchargeRate = -0.1
+			c.Currencies["BRL"] = r
+		}, "surcharge rates"},
+		{"zero weight unit", func(c *Config) {
+			r := c.Currencies["BRL"]
+			r.WeightUnitKg = 0
+			c.Currencies["BRL"] = r
+		}, "weight_unit_kg"},
+		{"negative rounding", func(c *Config) {
+			r := c.Currencies["BRL"]
+			r.RoundingIncrement = money.FromMinor(-1)
+			c.Currencies["BRL"] = r
+		}, "rounding_increment"},
+		{"unknown rounding mode", func(c *Config) {
+			r := c.Currencies["BRL"]
+			r.RoundingMode = "floor"
+			c.Currencies["BRL"] = r
+		}, `unsupported rounding_mode "floor"`},
+		{"country with unknown currency", func(c *Config) { c.Countries["AR"] = "ARS" }, `currency "ARS" is not configured`},
+		{"unknown default country", func(c *Config) { c.DefaultCountry = "AR" }, "default_country"},
+		{"negative additional service fee", func(c *Config) {
+			c.Currencies["BRL"].AdditionalServices[ServiceSignature] = money.FromMinor(-1)
+		}, `additional service "signature"`},
+		{"missing home delivery type", func(c *Config) { delete(c.DeliveryTypes, DeliveryHome) }, `delivery type "home" is required`},
+		{"discount above 100%", func(c *Config) {
+			c.DeliveryTypes[DeliveryLocker] = DeliveryType{CostAdjustmentRate: -1.5}
+		}, `delivery type "locker"`},
+		{"missing standard package type", func(c *Config) { delete(c.PackageTypes, PackageStandard) }, `package type "standard" is required`},
+		{"negative package surcharge", func(c *Config) {
+			c.PackageTypes[PackageFragile] = PackageType{SurchargeRate: -0.1}
+		}, `package type "fragile"`},
+		{"weight brackets out of order", func(c *Config) {
+			r := c.Currencies["BRL"]
+			r.WeightTable = []WeightBracket{{MaxWeightKg: 5, Cost: money.FromMinor(2000)}, {MaxWeightKg: 1, Cost: money.FromMinor(1000)}}
+			c.Currencies["BRL"] = r
+		}, "ascending order"},
+		{"ceiling below floor", func(c *Config) {
+			r := c.Currencies["BRL"]
+			r.PriceLimits = []PriceLimit{{MinCost: money.FromMinor(500), MaxCost: money.FromMinor(100)}}
+			c.Currencies["BRL"] = r
+		}, "max_cost must not be below min_cost"},
+		{"unknown price limit zone", func(c *Config) {
+			r := c.Currencies["BRL"]
+			r.PriceLimits = []PriceLimit{{Zone: "international", MinCost: money.FromMinor(500)}}
+			c.Currencies["BRL"] = r
+		}, `unknown zone "international"`},
+		{"duplicate price limit", func(c *Config) {
+			r := c.Currencies["BRL"]
+			r.PriceLimits = []PriceLimit{{Service: LevelExpress, MinCost: money.FromMinor(500)}, {Service: LevelExpress, MinCost: money.FromMinor(800)}}
+			c.Currencies["BRL"] = r
+		}, "duplicate limit"},
+		{"unknown strategy", func(c *Config) { c.Strategies = map[string]string{LevelExpress: "auction"} }, "unsupported pricing strategy"},
+		{"unknown service level", func(c *Config) { c.Strategies = map[string]string{"overnight": StrategyTable} }, `unknown service level "overnight"`},
+		{"return discount above 100%", func(c *Config) {
+			c.Returns = &ReturnPolicy{CostAdjustmentRate: -1.5, Services: []string{LevelStandard}}
+		}, "returns: cost_adjustment_rate"},
+		{"return without standard", func(c *Config) { c.Returns = &ReturnPolicy{Services: []string{LevelExpress}} }, `returns: service level "standard" is required`},
+		{"unknown return service level", func(c *Config) {
+			c.Returns = &ReturnPolicy{Services: []string{LevelStandard, "overnight"}}
+		}, `returns: unknown service level "overnight"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := DefaultConfig()
+			tt.mutate(&cfg)
+
+			// Act
+			err := cfg.Validate()
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	content := `{
+		"default_country": "br",
+		"currencies": {
+			"brl": {"base_cost": 1200, "weight_surcharge_rate": 0.1, "weight_unit_kg": 0.5,
+				"volume_surcharge_rate": 0.05, "volume_unit_cm3": 1000, "express_surcharge_rate": 0.5, "rounding_increment": 5}
+		},
+		"countries": {"br": "brl"}
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "BR", cfg.DefaultCountry)
+	assert.Equal(t, "BRL", cfg.DefaultCurrency())
+	assert.Equal(t, money.FromMinor(1200), cfg.Currencies["BRL"].BaseCost)
+	assert.Equal(t, []string{"BR"}, cfg.SupportedCountries())
+	assert.Equal(t, []string{"BRL"}, cfg.SupportedCurrencies())
+	assert.Equal(t, DefaultPackageTypes(), cfg.PackageTypes)
+	assert.Equal(t, DefaultDeliveryTypes(), cfg.DeliveryTypes)
+	assert.Equal(t, DefaultReturnPolicy(), cfg.Returns)
+}
+
+func TestLoadConfig_Returns(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	content := `{
+		"default_country": "BR",
+		"currencies": {"BRL": {"base_cost": 1000, "weight_unit_kg": 0.5, "volume_unit_cm3": 1000}},
+		"countries": {"BR": "BRL"},
+		"returns": {"cost_adjustment_rate": -0.25, "services": ["Standard", " express "]}
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(path)
+
+	// Assert
+	assert.NoError(t, err)
+	returns := cfg.ResolveReturns()
+	assert.Equal(t, ReturnPolicy{CostAdjustmentRate: -0.25, Services: []string{LevelStandard, LevelExpress}}, returns)
+	assert.True(t, returns.Offers(LevelExpress))
+}
+
+func TestResolveReturns_Default(t *testing.T) {
+	// Act
+	returns := Config{}.ResolveReturns()
+
+	// Assert
+	assert.Equal(t, ReturnPolicy{Services: []string{LevelStandard}}, returns)
+	assert.True(t, returns.Offers(LevelStandard))
+	assert.False(t, returns.Offers(LevelExpress))
+}
+
+func TestLoadConfig_PackageTypes(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	content := `{
+		"default_country": "BR",
+		"currencies": {
+			"BRL": {"base_cost": 1000, "weight_unit_kg": 0.5, "volume_unit_cm3": 1000,
+				"additional_services": {"COD": 700}}
+		},
+		"countries": {"BR": "BRL"},
+		"package_types": {
+			"Standard": {},
+			"Batteries": {"surcharge_rate": 0.4, "express_prohibited": true}
+		}
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"batteries", "standard"}, cfg.SupportedPackageTypes())
+	assert.Equal(t, PackageType{SurchargeRate: 0.4, ExpressProhibited: true}, cfg.PackageTypes["batteries"])
+	assert.Equal(t, []string{"cod"}, cfg.SupportedAdditionalServices())
+}
+
+func TestConfig_VersionID(t *testing.T) {
+	// Arrange
+	explicit := DefaultConfig()
+	explicit.Version = "2025.03"
+	changed := DefaultConfig()
+	changed.Currencies["BRL"] = Rates{BaseCost: money.FromMinor(1100), WeightUnitKg: 0.5, VolumeUnitCm3: 1000}
+
+	// Act
+	defaultVersion := DefaultConfig().VersionID()
+	changedVersion := changed.VersionID()
+
+	// Assert
+	assert.Equal(t, "2025.03", explicit.VersionID())
+	assert.Regexp(t, `^sha256:[0-9a-f]{12}$`, defaultVersion)
+	assert.Equal(t, defaultVersion, DefaultConfig().VersionID())
+	assert.NotEqual(t, defaultVersion, changedVersion)
+}
+
+func TestLoadConfig_Version(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	content := `{
+		"version": " 2025.03 ",
+		"default_country": "BR",
+		"currencies": {"BRL": {"base_cost": 1000, "weight_unit_kg": 0.5, "volume_unit_cm3": 1000}},
+		"countries": {"BR": "BRL"}
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "2025.03", cfg.VersionID())
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	dir := t.TempDir()
+	invalidJSON := filepath.Join(dir, "invalid.json")
+	invalidConfig := filepath.Join(dir, "config.json")
+	assert.NoError(t, os.WriteFile(invalidJSON, []byte("{"), 0o600))
+	assert.NoError(t, os.WriteFile(invalidConfig, []byte(`{"currencies": {}}`), 0o600))
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{"missing file", filepath.Join(dir, "missing.json"), "failed to read pricing config"},
+		{"invalid json", invalidJSON, "failed to parse pricing config"},
+		{"invalid config", invalidConfig, "invalid pricing config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := LoadConfig(tt.path)
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
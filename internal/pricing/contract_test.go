@@ -0,0 +1,64 @@
+package pricing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPCarrierRates_Contract(t *testing.T) {
+	// Arrange
+	server := testsupport.NewCarrierRatesServer(t)
+	rates := NewHTTPCarrierRates(CarrierConfig{URL: server.URL + testsupport.CarrierRatesPath, Timeout: time.Second})
+
+	// Act
+	cost, err := rates.Rate(context.Background(), Shipment{
+		OriginZipcode:      "01310100",
+		DestinationZipcode: "20040020",
+		Currency:           "BRL",
+		Level:              LevelExpress,
+		Weight:             2,
+		Volume:             1000,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMinor(1830), cost)
+	requests := server.Requests()
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "application/json", requests[0].Header.Get("Content-Type"))
+	testsupport.AssertGolden(t, "testdata/carrier_rate_request.golden.json", requests[0].Body)
+}
+
+func TestHTTPCarrierRates_ContractFaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		fault   testsupport.Fault
+		wantErr string
+	}{
+		{"unavailable", testsupport.Fault{Status: http.StatusServiceUnavailable}, "unexpected status 503"},
+		{"malformed", testsupport.Fault{Malformed: true}, "failed to parse carrier rate"},
+		{"connection reset", testsupport.Fault{Reset: true}, "failed to request carrier rate"},
+		{"slower than the timeout", testsupport.Fault{Latency: time.Second}, "Client.Timeout exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := testsupport.NewCarrierRatesServer(t)
+			server.Fail(tt.fault)
+			rates := NewHTTPCarrierRates(CarrierConfig{URL: server.URL + testsupport.CarrierRatesPath, Timeout: 50 * time.Millisecond})
+
+			// Act
+			_, err := rates.Rate(context.Background(), Shipment{Currency: "BRL", Level: LevelStandard})
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Change is a setting that differs between two pricing configurations. Old is nil for added
+// settings and New is nil for removed ones
+type Change struct {
+	// Path locates the setting with its JSON field names, e.g. "currencies.BRL.base_cost" or
+	// "currencies.BRL.price_limits[0].min_cost"
+	Path string `json:"path"`
+	Old  any    `json:"old"`
+	New  any    `json:"new"`
+}
+
+// Diff lists the settings that differ from old to new, sorted by path
+func Diff(old, new Config) []Change {
+	var changes []Change
+	diffValues("", jsonValue(old), jsonValue(new), &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// jsonValue decodes the JSON form of a configuration into maps, slices and scalars
+func jsonValue(cfg Config) any {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+	return value
+}
+
+func diffValues(path string, old, new any, changes *[]Change) {
+	oldObject, oldIsObject := old.(map[string]any)
+	newObject, newIsObject := new.(map[string]any)
+	if oldIsObject && newIsObject {
+		for key, value := range oldObject {
+			diffValues(joinPath(path, key), value, newObject[key], changes)
+		}
+		for key, value := range newObject {
+			if _, ok := oldObject[key]; !ok {
+				diffValues(joinPath(path, key), nil, value, changes)
+			}
+		}
+		return
+	}
+
+	oldList, oldIsList := old.([]any)
+	newList, newIsList := new.([]any)
+	if oldIsList && newIsList {
+		for i := 0; i < max(len(oldList), len(newList)); i++ {
+			var oldItem, newItem any
+			if i < len(oldList) {
+				oldItem = oldList[i]
+			}
+			if i < len(newList) {
+				newItem = newList[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), oldItem, newItem, changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		*changes = append(*changes, Change{Path: path, Old: old, New: new})
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
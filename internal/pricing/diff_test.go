@@ -0,0 +1,53 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	// Arrange
+	old := DefaultConfig()
+	brl := old.Currencies["BRL"]
+	brl.PriceLimits = []PriceLimit{{MinCost: money.FromMinor(800)}}
+	old.Currencies["BRL"] = brl
+
+	updated := DefaultConfig()
+	updated.Version = "2025.04"
+	brl.BaseCost = money.FromMinor(1200)
+	brl.PriceLimits = []PriceLimit{{MinCost: money.FromMinor(900)}, {Service: LevelExpress, MinCost: money.FromMinor(1500)}}
+	updated.Currencies["BRL"] = brl
+	delete(updated.Currencies, "EUR")
+
+	// Act
+	changes := Diff(old, updated)
+
+	// Assert
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	assert.Equal(t, []string{
+		"currencies.BRL.base_cost",
+		"currencies.BRL.price_limits[0].min_cost",
+		"currencies.BRL.price_limits[1]",
+		"currencies.EUR",
+		"version",
+	}, paths)
+	assert.Equal(t, Change{Path: "currencies.BRL.base_cost", Old: float64(1000), New: float64(1200)}, changes[0])
+	assert.Equal(t, Change{Path: "currencies.BRL.price_limits[0].min_cost", Old: float64(800), New: float64(900)}, changes[1])
+	assert.Nil(t, changes[2].Old)
+	assert.NotNil(t, changes[3].Old)
+	assert.Nil(t, changes[3].New)
+	assert.Equal(t, Change{Path: "version", Old: nil, New: "2025.04"}, changes[4])
+}
+
+func TestDiff_Equal(t *testing.T) {
+	// Act
+	changes := Diff(DefaultConfig(), DefaultConfig())
+
+	// Assert
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,87 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+)
+
+// ErrFreightNotOffered is returned when a freight class is requested in a currency without
+// freight rates, or for a shipment outside the freight weight limits
+var ErrFreightNotOffered = errors.New("freight is not offered")
+
+// ErrUnsupportedFreightClass is returned when no rate is configured for the freight class
+var ErrUnsupportedFreightClass = errors.New("unsupported freight class")
+
+// FreightRates price heavy shipments (pallets, LTL) by freight class instead of as parcels.
+// Shipments weighing from MinWeightKg to MaxWeightKg are offered freight, and may exceed the parcel
+// volume limit up to MaxVolumeCm3
+type FreightRates struct {
+	MinWeightKg  float64 `json:"min_weight_kg"`
+	MaxWeightKg  float64 `json:"max_weight_kg"`
+	MaxVolumeCm3 float64 `json:"max_volume_cm3"`
+	// Classes maps NMFC-like freight classes ("50" to "500", denser and sturdier goods in the
+	// lower classes) to their rate per kilogram
+	Classes map[string]money.Amount `json:"classes"`
+	// DefaultClass prices shipments without a freight class; empty offers freight only when the
+	// class is informed
+	DefaultClass string `json:"default_class,omitempty"`
+	// MinimumCharge is the least a freight shipment costs
+	MinimumCharge money.Amount `json:"minimum_charge,omitempty"`
+	// TransitDays is the carrier transit time of freight shipments
+	TransitDays int `json:"transit_days"`
+}
+
+// Applies reports whether a shipment of the weight is within the freight weight limits
+func (f FreightRates) Applies(weightKg float64) bool {
+	return weightKg >= f.MinWeightKg && weightKg <= f.MaxWeightKg
+}
+
+// Price returns the cost of shipping weightKg as freight of the class (DefaultClass when empty),
+// never less than MinimumCharge
+func (f FreightRates) Price(class string, weightKg float64) (money.Amount, error) {
+	class = strings.TrimSpace(class)
+	if class == "" {
+		class = f.DefaultClass
+	}
+	rate, ok := f.Classes[class]
+	if !ok {
+		return 0, fmt.Errorf("%w %q", ErrUnsupportedFreightClass, class)
+	}
+	cost := rate.MulRate(weightKg)
+	if cost < f.MinimumCharge {
+		cost = f.MinimumCharge
+	}
+	return cost, nil
+}
+
+// validateFreight checks the weight and volume limits, the class rates and the transit time of
+// the freight rates
+func validateFreight(f *FreightRates) error {
+	if f == nil {
+		return nil
+	}
+	if f.MinWeightKg <= 0 || f.MaxWeightKg < f.MinWeightKg {
+		return errors.New("freight: min_weight_kg must be positive and max_weight_kg at least min_weight_kg")
+	}
+	if f.MaxVolumeCm3 <= 0 {
+		return errors.New("freight: max_volume_cm3 must be positive")
+	}
+	if len(f.Classes) == 0 {
+		return errors.New("freight: at least one class is required")
+	}
+	for class, rate := range f.Classes {
+		if rate <= 0 {
+			return fmt.Errorf("freight: class %q: rate must be positive", class)
+		}
+	}
+	if _, ok := f.Classes[f.DefaultClass]; f.DefaultClass != "" && !ok {
+		return fmt.Errorf("freight: default_class %q is not configured", f.DefaultClass)
+	}
+	if f.MinimumCharge < 0 || f.TransitDays < 0 {
+		return errors.New("freight: minimum_charge and transit_days must not be negative")
+	}
+	return nil
+}
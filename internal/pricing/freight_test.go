@@ -0,0 +1,109 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func testFreightRates() FreightRates {
+	return FreightRates{
+		MinWeightKg:   50,
+		MaxWeightKg:   1000,
+		MaxVolumeCm3:  2000000,
+		Classes:       map[string]money.Amount{"70": money.FromMinor(150), "125": money.FromMinor(300)},
+		DefaultClass:  "125",
+		MinimumCharge: money.FromMinor(15000),
+		TransitDays:   5,
+	}
+}
+
+func TestFreightRates_Applies(t *testing.T) {
+	tests := []struct {
+		name   string
+		weight float64
+		want   bool
+	}{
+		{"below the minimum weight", 49.9, false},
+		{"minimum weight", 50, true},
+		{"maximum weight", 1000, true},
+		{"above the maximum weight", 1000.1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.want, testFreightRates().Applies(tt.weight))
+		})
+	}
+}
+
+func TestFreightRates_Price(t *testing.T) {
+	tests := []struct {
+		name   string
+		class  string
+		weight float64
+		want   float64
+	}{
+		{"class rate per kg", "70", 200, 30000},
+		{"default class", "", 200, 60000},
+		{"minimum charge", "70", 60, 15000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			cost, err := testFreightRates().Price(tt.class, tt.weight)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, money.FromMinor(tt.want), cost)
+		})
+	}
+}
+
+func TestFreightRates_Price_UnsupportedClass(t *testing.T) {
+	// Act
+	cost, err := testFreightRates().Price("85", 200)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrUnsupportedFreightClass)
+	assert.ErrorContains(t, err, `"85"`)
+	assert.Zero(t, cost)
+}
+
+func TestValidateFreight(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(f *FreightRates)
+		wantErr string
+	}{
+		{"valid", func(f *FreightRates) {}, ""},
+		{"no minimum weight", func(f *FreightRates) { f.MinWeightKg = 0 }, "min_weight_kg must be positive"},
+		{"maximum below minimum", func(f *FreightRates) { f.MaxWeightKg = 10 }, "max_weight_kg at least min_weight_kg"},
+		{"no maximum volume", func(f *FreightRates) { f.MaxVolumeCm3 = 0 }, "max_volume_cm3 must be positive"},
+		{"no classes", func(f *FreightRates) { f.Classes = nil; f.DefaultClass = "" }, "at least one class is required"},
+		{"zero rate", func(f *FreightRates) { f.Classes["70"] = 0 }, `class "70": rate must be positive`},
+		{"unknown default class", func(f *FreightRates) { f.DefaultClass = "500" }, `default_class "500" is not configured`},
+		{"negative transit days", func(f *FreightRates) { f.TransitDays = -1 }, "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			freight := testFreightRates()
+			tt.mutate(&freight)
+
+			// Act
+			err := validateFreight(&freight)
+
+			// Assert
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
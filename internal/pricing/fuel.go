@@ -0,0 +1,48 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxFuelSurchargeRate bounds the fuel surcharge, guarding against a percentage entered as a
+// whole number, e.g. 12 instead of 0.12
+const MaxFuelSurchargeRate = 1
+
+// FuelSurcharge is the fuel surcharge index in force, updated weekly as the fuel price changes:
+// a fraction of the freight charged on every service level
+type FuelSurcharge struct {
+	// Rate is the fraction of the freight, after the express and restricted area surcharges, added
+	// as fuel surcharge, e.g. 0.12 for 12%
+	Rate float64 `json:"rate"`
+	// EffectiveDate ("YYYY-MM-DD") is when Rate came into force
+	EffectiveDate string `json:"effective_date"`
+}
+
+// Validate checks the rate and the effective date of the index
+func (f FuelSurcharge) Validate() error {
+	if f.Rate < 0 || f.Rate > MaxFuelSurchargeRate {
+		return fmt.Errorf("fuel_surcharge: rate must be between 0 and %d, got %g", MaxFuelSurchargeRate, f.Rate)
+	}
+	if _, err := time.Parse(time.DateOnly, f.EffectiveDate); err != nil {
+		return fmt.Errorf("fuel_surcharge: effective_date must be a YYYY-MM-DD date, got %q", f.EffectiveDate)
+	}
+	return nil
+}
+
+// Supersedes reports whether the index came into force after other
+func (f FuelSurcharge) Supersedes(other FuelSurcharge) bool {
+	// YYYY-MM-DD dates sort chronologically as strings
+	return f.EffectiveDate > other.EffectiveDate
+}
+
+// normalizeFuelSurcharge trims the effective date of the index
+func normalizeFuelSurcharge(fuel *FuelSurcharge) *FuelSurcharge {
+	if fuel == nil {
+		return nil
+	}
+	out := *fuel
+	out.EffectiveDate = strings.TrimSpace(out.EffectiveDate)
+	return &out
+}
@@ -0,0 +1,68 @@
+package pricing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuelSurcharge_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		fuel    FuelSurcharge
+		wantErr string
+	}{
+		{"valid", FuelSurcharge{Rate: 0.12, EffectiveDate: "2025-04-07"}, ""},
+		{"zero rate", FuelSurcharge{EffectiveDate: "2025-04-07"}, ""},
+		{"negative rate", FuelSurcharge{Rate: -0.01, EffectiveDate: "2025-04-07"}, "rate must be between 0 and 1"},
+		{"percentage as a whole number", FuelSurcharge{Rate: 12, EffectiveDate: "2025-04-07"}, "rate must be between 0 and 1"},
+		{"missing effective date", FuelSurcharge{Rate: 0.12}, "effective_date must be a YYYY-MM-DD date"},
+		{"brazilian date format", FuelSurcharge{Rate: 0.12, EffectiveDate: "07/04/2025"}, "effective_date must be a YYYY-MM-DD date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := tt.fuel.Validate()
+
+			// Assert
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFuelSurcharge_Supersedes(t *testing.T) {
+	// Arrange
+	previous := FuelSurcharge{Rate: 0.10, EffectiveDate: "2025-03-31"}
+	current := FuelSurcharge{Rate: 0.12, EffectiveDate: "2025-04-07"}
+
+	// Act & Assert
+	assert.True(t, current.Supersedes(previous))
+	assert.False(t, previous.Supersedes(current))
+	assert.False(t, current.Supersedes(current))
+}
+
+func TestLoadConfig_FuelSurcharge(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	content := `{
+		"default_country": "BR",
+		"currencies": {"BRL": {"base_cost": 1000, "weight_unit_kg": 0.5, "volume_unit_cm3": 1000}},
+		"countries": {"BR": "BRL"},
+		"fuel_surcharge": {"rate": 0.12, "effective_date": " 2025-04-07 "}
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &FuelSurcharge{Rate: 0.12, EffectiveDate: "2025-04-07"}, cfg.FuelSurcharge)
+}
@@ -0,0 +1,106 @@
+package pricing
+
+import (
+	"fmt"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+)
+
+// Route zones, from the CEP structure: the first digit is the postal region and the first three
+// digits the sector
+const (
+	ZoneLocal    = "local"
+	ZoneRegional = "regional"
+	ZoneNational = "national"
+)
+
+// Price limit kinds reported in the cost breakdown
+const (
+	LimitFloor   = "floor"
+	LimitCeiling = "ceiling"
+)
+
+// ZoneOf returns the zone of a route: ZoneLocal within the same CEP sector, ZoneRegional
+// within the same postal region and ZoneNational otherwise
+func ZoneOf(originZipcode, destinationZipcode string) string {
+	sector := zipcode.Sector(originZipcode)
+	region := zipcode.Region(originZipcode)
+	switch {
+	case sector != "" && sector == zipcode.Sector(destinationZipcode):
+		return ZoneLocal
+	case region != "" && region == zipcode.Region(destinationZipcode):
+		return ZoneRegional
+	default:
+		return ZoneNational
+	}
+}
+
+// PriceLimit bounds the freight of a service level after all surcharges, before additional
+// service fees. Empty Service or Zone match every service level or zone
+type PriceLimit struct {
+	Service string       `json:"service,omitempty"`
+	Zone    string       `json:"zone,omitempty"`
+	MinCost money.Amount `json:"min_cost,omitempty"`
+	// MaxCost of 0 leaves the freight without ceiling
+	MaxCost money.Amount `json:"max_cost,omitempty"`
+}
+
+// Clamp bounds a cost to the limit, returning the clamped cost and LimitFloor or LimitCeiling
+// when it was changed
+func (l PriceLimit) Clamp(cost money.Amount) (money.Amount, string) {
+	if cost < l.MinCost {
+		return l.MinCost, LimitFloor
+	}
+	if l.MaxCost > 0 && cost > l.MaxCost {
+		return l.MaxCost, LimitCeiling
+	}
+	return cost, ""
+}
+
+// PriceLimitFor returns the most specific limit of a service level and zone: one naming both,
+// then one naming the service level, then one naming the zone, then one naming neither
+func (r Rates) PriceLimitFor(service, zone string) (PriceLimit, bool) {
+	best, bestRank := PriceLimit{}, -1
+	for _, limit := range r.PriceLimits {
+		if (limit.Service != "" && limit.Service != service) || (limit.Zone != "" && limit.Zone != zone) {
+			continue
+		}
+		rank := 0
+		if limit.Service != "" {
+			rank += 2
+		}
+		if limit.Zone != "" {
+			rank++
+		}
+		if rank > bestRank {
+			best, bestRank = limit, rank
+		}
+	}
+	return best, bestRank >= 0
+}
+
+// validatePriceLimits checks the bounds and that no two limits apply to the same service level and zone
+func validatePriceLimits(limits []PriceLimit) error {
+	seen := make(map[[2]string]bool, len(limits))
+	for i, limit := range limits {
+		if limit.Service != "" && limit.Service != LevelStandard && limit.Service != LevelExpress {
+			return fmt.Errorf("price_limits[%d]: unknown service level %q", i, limit.Service)
+		}
+		if limit.Zone != "" && limit.Zone != ZoneLocal && limit.Zone != ZoneRegional && limit.Zone != ZoneNational {
+			return fmt.Errorf("price_limits[%d]: unknown zone %q", i, limit.Zone)
+		}
+		if limit.MinCost < 0 || limit.MaxCost < 0 {
+			return fmt.Errorf("price_limits[%d]: min_cost and max_cost must not be negative", i)
+		}
+		if limit.MaxCost > 0 && limit.MaxCost < limit.MinCost {
+			return fmt.Errorf("price_limits[%d]: max_cost must not be below min_cost", i)
+		}
+		key := [2]string{limit.Service, limit.Zone}
+		if seen[key] {
+			return fmt.Errorf("price_limits[%d]: duplicate limit for service %q and zone %q", i, limit.Service, limit.Zone)
+		}
+		seen[key] = true
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZoneOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		origin      string
+		destination string
+		want        string
+	}{
+		{"same sub-region", "01310-100", "01311-000", ZoneLocal},
+		{"same postal region", "01310-100", "04547-130", ZoneRegional},
+		{"different postal regions", "01310-100", "20040-020", ZoneNational},
+		{"short zipcodes in the same region", "1414", "1428", ZoneRegional},
+		{"empty zipcode", "", "01310-100", ZoneNational},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.want, ZoneOf(tt.origin, tt.destination))
+		})
+	}
+}
+
+func TestPriceLimit_Clamp(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    PriceLimit
+		cost     float64
+		want     float64
+		wantKind string
+	}{
+		{"within limits", PriceLimit{MinCost: money.FromMinor(500), MaxCost: money.FromMinor(5000)}, 1250, 1250, ""},
+		{"below floor", PriceLimit{MinCost: money.FromMinor(500), MaxCost: money.FromMinor(5000)}, 2, 500, LimitFloor},
+		{"above ceiling", PriceLimit{MinCost: money.FromMinor(500), MaxCost: money.FromMinor(5000)}, 4000000, 5000, LimitCeiling},
+		{"no ceiling", PriceLimit{MinCost: money.FromMinor(500)}, 4000000, 4000000, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			cost, kind := tt.limit.Clamp(money.FromMinor(tt.cost))
+
+			// Assert
+			assert.Equal(t, money.FromMinor(tt.want), cost)
+			assert.Equal(t, tt.wantKind, kind)
+		})
+	}
+}
+
+func TestRates_PriceLimitFor(t *testing.T) {
+	rates := Rates{PriceLimits: []PriceLimit{
+		{MinCost: money.FromMinor(100)},
+		{Zone: ZoneNational, MinCost: money.FromMinor(200)},
+		{Service: LevelExpress, MinCost: money.FromMinor(300)},
+		{Service: LevelExpress, Zone: ZoneNational, MinCost: money.FromMinor(400)},
+	}}
+
+	tests := []struct {
+		name    string
+		service string
+		zone    string
+		want    float64
+	}{
+		{"default limit", LevelStandard, ZoneLocal, 100},
+		{"zone limit", LevelStandard, ZoneNational, 200},
+		{"service limit", LevelExpress, ZoneLocal, 300},
+		{"service and zone limit", LevelExpress, ZoneNational, 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			limit, ok := rates.PriceLimitFor(tt.service, tt.zone)
+
+			// Assert
+			assert.True(t, ok)
+			assert.Equal(t, money.FromMinor(tt.want), limit.MinCost)
+		})
+	}
+}
+
+func TestRates_PriceLimitFor_NoLimits(t *testing.T) {
+	// Act
+	_, ok := Rates{}.PriceLimitFor(LevelStandard, ZoneLocal)
+
+	// Assert
+	assert.False(t, ok)
+}
@@ -0,0 +1,98 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+)
+
+// regionalCountry is the destination country whose routes are priced by region, from the state
+// of their CEPs
+const regionalCountry = "BR"
+
+// RegionalRate sets the base cost and transit days of the Brazilian routes between two regions.
+// Origin and Destination are a state ("SP"), a macro-region ("southeast") or empty for any
+type RegionalRate struct {
+	Origin      string `json:"origin,omitempty"`
+	Destination string `json:"destination,omitempty"`
+	// BaseCost replaces the distance-based base cost of FormulaPricing; 0 keeps it
+	BaseCost money.Amount `json:"base_cost,omitempty"`
+	// StandardDays and ExpressDays replace the default transit days of the service levels; 0 keeps them
+	StandardDays int `json:"standard_days,omitempty"`
+	ExpressDays  int `json:"express_days,omitempty"`
+}
+
+// RegionalRateFor returns the most specific regional rate of a route to the destination country:
+// states rank above macro-regions, which rank above any region, origin and destination adding up;
+// on a tie the first listed wins. Only Brazilian routes whose CEPs resolve to a state are matched
+func (r Rates) RegionalRateFor(country, originZipcode, destinationZipcode string) (RegionalRate, bool) {
+	if len(r.Regions) == 0 || country != regionalCountry {
+		return RegionalRate{}, false
+	}
+	originState, destinationState := zipcode.State(originZipcode), zipcode.State(destinationZipcode)
+	if originState == "" || destinationState == "" {
+		return RegionalRate{}, false
+	}
+
+	best, bestRank := RegionalRate{}, -1
+	for _, rate := range r.Regions {
+		originRank, ok := regionRank(rate.Origin, originState)
+		if !ok {
+			continue
+		}
+		destinationRank, ok := regionRank(rate.Destination, destinationState)
+		if !ok {
+			continue
+		}
+		if rank := originRank + destinationRank; rank > bestRank {
+			best, bestRank = rate, rank
+		}
+	}
+	return best, bestRank >= 0
+}
+
+// regionRank reports whether a region of a regional rate matches a state and how specifically
+func regionRank(region, state string) (int, bool) {
+	switch region {
+	case "":
+		return 0, true
+	case state:
+		return 2, true
+	case zipcode.StateMacroRegion(state):
+		return 1, true
+	default:
+		return 0, false
+	}
+}
+
+// validateRegions checks the regions and values and that no two rates apply to the same route
+func validateRegions(rates []RegionalRate) error {
+	seen := make(map[[2]string]bool, len(rates))
+	for i, rate := range rates {
+		for _, region := range []string{rate.Origin, rate.Destination} {
+			if region != "" && !zipcode.IsState(region) && !zipcode.IsMacroRegion(region) {
+				return fmt.Errorf("regions[%d]: unknown state or macro-region %q", i, region)
+			}
+		}
+		if rate.BaseCost < 0 || rate.StandardDays < 0 || rate.ExpressDays < 0 {
+			return fmt.Errorf("regions[%d]: base_cost, standard_days and express_days must not be negative", i)
+		}
+		key := [2]string{rate.Origin, rate.Destination}
+		if seen[key] {
+			return fmt.Errorf("regions[%d]: duplicate rate from %q to %q", i, rate.Origin, rate.Destination)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// normalizeRegion upper-cases states and lower-cases macro-regions
+func normalizeRegion(region string) string {
+	region = strings.TrimSpace(region)
+	if upper := strings.ToUpper(region); zipcode.IsState(upper) {
+		return upper
+	}
+	return strings.ToLower(region)
+}
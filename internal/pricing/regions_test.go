@@ -0,0 +1,169 @@
+package pricing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func regionalRates() Rates {
+	rates := DefaultRates()
+	rates.Regions = []RegionalRate{
+		{BaseCost: money.FromMinor(3000), StandardDays: 8, ExpressDays: 4},
+		{Origin: "southeast", Destination: "southeast", BaseCost: money.FromMinor(1500), StandardDays: 3},
+		{Origin: "SP", Destination: "SP", BaseCost: money.FromMinor(1000), StandardDays: 2},
+		{Origin: "SP", Destination: "north", BaseCost: money.FromMinor(4500), StandardDays: 10, ExpressDays: 5},
+	}
+	return rates
+}
+
+func TestRates_RegionalRateFor(t *testing.T) {
+	tests := []struct {
+		name         string
+		country      string
+		origin       string
+		destination  string
+		wantOK       bool
+		wantBaseCost float64
+	}{
+		{"same state", "BR", "01310-100", "04547-130", true, 1000},
+		{"same macro-region", "BR", "01310-100", "20040-020", true, 1500},
+		{"state to macro-region", "BR", "01310-100", "69005-040", true, 4500},
+		{"any region", "BR", "20040-020", "69005-040", true, 3000},
+		{"lost leading zero", "BR", "1310100", "4547130", true, 1000},
+		{"foreign destination", "US", "01310-100", "04547-130", false, 0},
+		{"zipcode without state", "BR", "1414", "04547-130", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			rate, ok := regionalRates().RegionalRateFor(tt.country, tt.origin, tt.destination)
+
+			// Assert
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, money.FromMinor(tt.wantBaseCost), rate.BaseCost)
+		})
+	}
+}
+
+func TestRates_RegionalRateFor_NoRegions(t *testing.T) {
+	// Act
+	_, ok := DefaultRates().RegionalRateFor("BR", "01310-100", "69005-040")
+
+	// Assert
+	assert.False(t, ok)
+}
+
+func TestFormulaPricing_Price_RegionalBaseCost(t *testing.T) {
+	tests := []struct {
+		name         string
+		destination  string
+		wantBaseCost float64
+	}{
+		{"SP to RJ", "20040-020", 1500},
+		{"SP to AM", "69005-040", 4500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			shipment := Shipment{
+				OriginZipcode:      "01310-100",
+				DestinationZipcode: tt.destination,
+				DestinationCountry: "BR",
+				Weight:             0.5,
+				Volume:             1000,
+				Rates:              regionalRates(),
+			}
+
+			// Act
+			freight, err := FormulaPricing{}.Price(context.Background(), shipment)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, money.FromMinor(tt.wantBaseCost), freight.BaseCost)
+			assert.Equal(t, money.FromMinor(tt.wantBaseCost*0.1), freight.WeightSurcharge)
+		})
+	}
+}
+
+func TestFormulaPricing_Price_RegionalRateWithoutBaseCost(t *testing.T) {
+	// Arrange
+	rates := DefaultRates()
+	rates.Regions = []RegionalRate{{Origin: "SP", Destination: "SP", StandardDays: 1}}
+	shipment := Shipment{
+		OriginZipcode:      "01310-100",
+		DestinationZipcode: "01310-200",
+		DestinationCountry: "BR",
+		Weight:             1.0,
+		Volume:             1000,
+		Rates:              rates,
+	}
+
+	// Act
+	freight, err := FormulaPricing{}.Price(context.Background(), shipment)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMinor(1000), freight.BaseCost)
+}
+
+func TestValidate_RegionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		regions []RegionalRate
+		wantErr string
+	}{
+		{"unknown state", []RegionalRate{{Origin: "XX"}}, "unknown state or macro-region"},
+		{"unknown macro-region", []RegionalRate{{Destination: "center"}}, "unknown state or macro-region"},
+		{"negative base cost", []RegionalRate{{Origin: "SP", BaseCost: money.FromMinor(-1)}}, "must not be negative"},
+		{"negative days", []RegionalRate{{Origin: "SP", ExpressDays: -1}}, "must not be negative"},
+		{"duplicate route", []RegionalRate{{Origin: "SP", Destination: "north"}, {Origin: "SP", Destination: "north"}}, "duplicate rate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			rates := DefaultRates()
+			rates.Regions = tt.regions
+
+			// Act
+			err := rates.Validate()
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig_Regions(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	content := `{
+		"default_country": "BR",
+		"currencies": {
+			"BRL": {"base_cost": 1000, "weight_unit_kg": 0.5, "volume_unit_cm3": 1000,
+				"regions": [
+					{"origin": "sp", "destination": "Southeast", "base_cost": 1500, "standard_days": 3},
+					{"origin": "SE", "destination": "NORTH", "base_cost": 4000}
+				]}
+		},
+		"countries": {"BR": "BRL"}
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []RegionalRate{
+		{Origin: "SP", Destination: "southeast", BaseCost: money.FromMinor(1500), StandardDays: 3},
+		{Origin: "SE", Destination: "north", BaseCost: money.FromMinor(4000)},
+	}, cfg.Currencies["BRL"].Regions)
+}
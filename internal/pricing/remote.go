@@ -0,0 +1,107 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+)
+
+// RemoteArea is a range of destination CEPs of difficult access that the carriers serve with a
+// surcharge and longer transit, e.g. river communities and remote islands. Unlike restricted
+// areas, every remote area containing a destination applies: their surcharge rates and extra
+// days add up
+type RemoteArea struct {
+	// Name identifies the area in the breakdown, e.g. "ribeirinhas-am"
+	Name string `json:"name"`
+	// From and To are the first and last CEPs of the area, inclusive
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Services are the service levels charged ("standard", "express" or "freight"), and so the
+	// carriers shipping them; empty charges every level
+	Services []string `json:"services,omitempty"`
+	// SurchargeRate is the fraction of the subtotal added to the service levels of the area
+	SurchargeRate float64 `json:"surcharge_rate,omitempty"`
+	// ExtraDays are added to the transit days of the service levels of the area
+	ExtraDays int `json:"extra_days,omitempty"`
+}
+
+// Contains reports whether a destination zipcode is within the area
+func (a RemoteArea) Contains(destinationZipcode string) bool {
+	normalized := zipcode.Normalize(destinationZipcode)
+	return len(normalized) == zipcode.Length && normalized >= a.From && normalized <= a.To
+}
+
+// Charges reports whether the area applies to a service level
+func (a RemoteArea) Charges(level string) bool {
+	if len(a.Services) == 0 {
+		return true
+	}
+	for _, service := range a.Services {
+		if service == level {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteAreasFor returns the remote areas of a destination zipcode and service level, in the order
+// they are listed
+func (c Config) RemoteAreasFor(destinationZipcode, level string) []RemoteArea {
+	var areas []RemoteArea
+	for _, area := range c.RemoteAreas {
+		if area.Charges(level) && area.Contains(destinationZipcode) {
+			areas = append(areas, area)
+		}
+	}
+	return areas
+}
+
+// validateRemoteAreas checks the names, ranges, service levels, surcharges and extra days of the areas
+func validateRemoteAreas(areas []RemoteArea) error {
+	names := make(map[string]bool, len(areas))
+	for i, area := range areas {
+		if area.Name == "" {
+			return fmt.Errorf("remote_areas[%d]: name is required", i)
+		}
+		if names[area.Name] {
+			return fmt.Errorf("remote_areas[%d]: duplicate area %q", i, area.Name)
+		}
+		names[area.Name] = true
+		for _, cep := range []string{area.From, area.To} {
+			if len(cep) != zipcode.Length || !zipcode.Numeric(cep) {
+				return fmt.Errorf("remote area %q: %q is not an 8-digit CEP", area.Name, cep)
+			}
+		}
+		if area.From > area.To {
+			return fmt.Errorf("remote area %q: from %s is after to %s", area.Name, area.From, area.To)
+		}
+		for _, level := range area.Services {
+			if level != LevelStandard && level != LevelExpress && level != LevelFreight {
+				return fmt.Errorf("remote area %q: unknown service level %q", area.Name, level)
+			}
+		}
+		if area.SurchargeRate < 0 || area.ExtraDays < 0 {
+			return fmt.Errorf("remote area %q: surcharge_rate and extra_days must not be negative", area.Name)
+		}
+		if area.SurchargeRate == 0 && area.ExtraDays == 0 {
+			return fmt.Errorf("remote area %q: surcharge_rate or extra_days is required", area.Name)
+		}
+	}
+	return nil
+}
+
+// normalizeRemoteArea normalizes the CEPs and lower-cases the service levels of an area
+func normalizeRemoteArea(area RemoteArea) RemoteArea {
+	area.Name = strings.TrimSpace(area.Name)
+	area.From = zipcode.Normalize(area.From)
+	area.To = zipcode.Normalize(area.To)
+	if area.Services != nil {
+		services := make([]string, len(area.Services))
+		for i, level := range area.Services {
+			services[i] = strings.ToLower(strings.TrimSpace(level))
+		}
+		area.Services = services
+	}
+	return area
+}
@@ -0,0 +1,103 @@
+package pricing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func remoteConfig() Config {
+	cfg := DefaultConfig()
+	cfg.RemoteAreas = []RemoteArea{
+		{Name: "interior-am", From: "69400000", To: "69899999", SurchargeRate: 0.20, ExtraDays: 2},
+		{Name: "ribeirinhas-am", From: "69470000", To: "69479999", Services: []string{LevelStandard}, SurchargeRate: 0.15, ExtraDays: 3},
+		{Name: "noronha", From: "53990000", To: "53990999", ExtraDays: 5},
+	}
+	return cfg
+}
+
+func TestConfig_RemoteAreasFor(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		level       string
+		want        []string
+	}{
+		{"outside every area", "01310-100", LevelStandard, nil},
+		{"one area", "69400-000", LevelExpress, []string{"interior-am"}},
+		{"overlapping areas all apply", "69470-500", LevelStandard, []string{"interior-am", "ribeirinhas-am"}},
+		{"area of another service level", "69470-500", LevelExpress, []string{"interior-am"}},
+		{"extra days only", "53990999", LevelFreight, []string{"noronha"}},
+		{"partial zipcode", "6940", LevelStandard, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			areas := remoteConfig().RemoteAreasFor(tt.destination, tt.level)
+
+			// Assert
+			var names []string
+			for _, area := range areas {
+				names = append(names, area.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestValidate_RemoteAreaErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		areas   []RemoteArea
+		wantErr string
+	}{
+		{"missing name", []RemoteArea{{From: "69000000", To: "69999999", ExtraDays: 1}}, "name is required"},
+		{"duplicate name", []RemoteArea{{Name: "a", From: "69000000", To: "69999999", ExtraDays: 1}, {Name: "a", From: "53990000", To: "53990999", ExtraDays: 1}}, "duplicate area"},
+		{"partial CEP", []RemoteArea{{Name: "a", From: "69", To: "69999999", ExtraDays: 1}}, "not an 8-digit CEP"},
+		{"inverted range", []RemoteArea{{Name: "a", From: "69999999", To: "69000000", ExtraDays: 1}}, "is after"},
+		{"unknown service level", []RemoteArea{{Name: "a", From: "69000000", To: "69999999", Services: []string{"overnight"}, ExtraDays: 1}}, "unknown service level"},
+		{"negative surcharge", []RemoteArea{{Name: "a", From: "69000000", To: "69999999", SurchargeRate: -0.1}}, "must not be negative"},
+		{"negative extra days", []RemoteArea{{Name: "a", From: "69000000", To: "69999999", ExtraDays: -1}}, "must not be negative"},
+		{"neither surcharge nor extra days", []RemoteArea{{Name: "a", From: "69000000", To: "69999999"}}, "surcharge_rate or extra_days is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := DefaultConfig()
+			cfg.RemoteAreas = tt.areas
+
+			// Act
+			err := cfg.Validate()
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig_RemoteAreas(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	content := `{
+		"default_country": "BR",
+		"currencies": {"BRL": {"base_cost": 1000, "weight_unit_kg": 0.5, "volume_unit_cm3": 1000}},
+		"countries": {"BR": "BRL"},
+		"remote_areas": [
+			{"name": " interior-am ", "from": "69400-000", "to": "69899-999", "services": ["Standard"], "surcharge_rate": 0.2, "extra_days": 2}
+		]
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []RemoteArea{
+		{Name: "interior-am", From: "69400000", To: "69899999", Services: []string{LevelStandard}, SurchargeRate: 0.2, ExtraDays: 2},
+	}, cfg.RemoteAreas)
+}
@@ -0,0 +1,116 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+)
+
+// LevelFreight is the freight service level, which restricted areas can restrict alongside the
+// parcel service levels
+const LevelFreight = "freight"
+
+// RestrictedArea is a range of destination CEPs that the carrier contracts exclude from some or
+// all service levels, or serve with a surcharge, e.g. risk areas and remote islands
+type RestrictedArea struct {
+	// Name identifies the area in errors and logs, e.g. "fernando-de-noronha"
+	Name string `json:"name"`
+	// From and To are the first and last CEPs of the area, inclusive
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Services are the service levels restricted ("standard", "express" or "freight"); empty
+	// restricts every level
+	Services []string `json:"services,omitempty"`
+	// SurchargeRate is the fraction of the subtotal added to the restricted service levels; 0
+	// does not serve them
+	SurchargeRate float64 `json:"surcharge_rate,omitempty"`
+}
+
+// Blocked reports whether the area is not served by its service levels, rather than surcharged
+func (a RestrictedArea) Blocked() bool {
+	return a.SurchargeRate == 0
+}
+
+// Contains reports whether a destination zipcode is within the area
+func (a RestrictedArea) Contains(destinationZipcode string) bool {
+	normalized := zipcode.Normalize(destinationZipcode)
+	return len(normalized) == zipcode.Length && normalized >= a.From && normalized <= a.To
+}
+
+// Restricts reports whether the area restricts a service level
+func (a RestrictedArea) Restricts(level string) bool {
+	if len(a.Services) == 0 {
+		return true
+	}
+	for _, service := range a.Services {
+		if service == level {
+			return true
+		}
+	}
+	return false
+}
+
+// RestrictionFor returns the restricted area of a destination zipcode and service level. Areas
+// that block the level rank above areas that surcharge it; on a tie the first listed wins
+func (c Config) RestrictionFor(destinationZipcode, level string) (RestrictedArea, bool) {
+	found, ok := RestrictedArea{}, false
+	for _, area := range c.RestrictedAreas {
+		if !area.Restricts(level) || !area.Contains(destinationZipcode) {
+			continue
+		}
+		if area.Blocked() {
+			return area, true
+		}
+		if !ok {
+			found, ok = area, true
+		}
+	}
+	return found, ok
+}
+
+// validateRestrictedAreas checks the names, ranges, service levels and surcharges of the areas
+func validateRestrictedAreas(areas []RestrictedArea) error {
+	names := make(map[string]bool, len(areas))
+	for i, area := range areas {
+		if area.Name == "" {
+			return fmt.Errorf("restricted_areas[%d]: name is required", i)
+		}
+		if names[area.Name] {
+			return fmt.Errorf("restricted_areas[%d]: duplicate area %q", i, area.Name)
+		}
+		names[area.Name] = true
+		for _, cep := range []string{area.From, area.To} {
+			if len(cep) != zipcode.Length || !zipcode.Numeric(cep) {
+				return fmt.Errorf("restricted area %q: %q is not an 8-digit CEP", area.Name, cep)
+			}
+		}
+		if area.From > area.To {
+			return fmt.Errorf("restricted area %q: from %s is after to %s", area.Name, area.From, area.To)
+		}
+		for _, level := range area.Services {
+			if level != LevelStandard && level != LevelExpress && level != LevelFreight {
+				return fmt.Errorf("restricted area %q: unknown service level %q", area.Name, level)
+			}
+		}
+		if area.SurchargeRate < 0 {
+			return fmt.Errorf("restricted area %q: surcharge_rate must not be negative", area.Name)
+		}
+	}
+	return nil
+}
+
+// normalizeRestrictedArea normalizes the CEPs and lower-cases the service levels of an area
+func normalizeRestrictedArea(area RestrictedArea) RestrictedArea {
+	area.Name = strings.TrimSpace(area.Name)
+	area.From = zipcode.Normalize(area.From)
+	area.To = zipcode.Normalize(area.To)
+	if area.Services != nil {
+		services := make([]string, len(area.Services))
+		for i, level := range area.Services {
+			services[i] = strings.ToLower(strings.TrimSpace(level))
+		}
+		area.Services = services
+	}
+	return area
+}
@@ -0,0 +1,104 @@
+package pricing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func restrictedConfig() Config {
+	cfg := DefaultConfig()
+	cfg.RestrictedAreas = []RestrictedArea{
+		{Name: "remote-north", From: "69000000", To: "69999999", SurchargeRate: 0.30},
+		{Name: "noronha", From: "53990000", To: "53990999"},
+		{Name: "north-express", From: "69000000", To: "69099999", Services: []string{LevelExpress}},
+		{Name: "amazonas-freight", From: "69000000", To: "69999999", Services: []string{LevelFreight}, SurchargeRate: 0.50},
+	}
+	return cfg
+}
+
+func TestConfig_RestrictionFor(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		level       string
+		wantOK      bool
+		wantArea    string
+	}{
+		{"outside every area", "01310-100", LevelStandard, false, ""},
+		{"surcharged", "69900-000", LevelStandard, true, "remote-north"},
+		{"blocked", "53990-000", LevelExpress, true, "noronha"},
+		{"range end", "53990999", LevelStandard, true, "noronha"},
+		{"blocking area ranks above a surcharge", "69005-040", LevelExpress, true, "north-express"},
+		{"area of another service level", "69005-040", LevelStandard, true, "remote-north"},
+		{"first listed surcharge wins", "69005-040", LevelFreight, true, "remote-north"},
+		{"partial zipcode", "6900", LevelStandard, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			area, ok := restrictedConfig().RestrictionFor(tt.destination, tt.level)
+
+			// Assert
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantArea, area.Name)
+		})
+	}
+}
+
+func TestValidate_RestrictedAreaErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		areas   []RestrictedArea
+		wantErr string
+	}{
+		{"missing name", []RestrictedArea{{From: "69000000", To: "69999999"}}, "name is required"},
+		{"duplicate name", []RestrictedArea{{Name: "a", From: "69000000", To: "69999999"}, {Name: "a", From: "53990000", To: "53990999"}}, "duplicate area"},
+		{"partial CEP", []RestrictedArea{{Name: "a", From: "69", To: "69999999"}}, "not an 8-digit CEP"},
+		{"inverted range", []RestrictedArea{{Name: "a", From: "69999999", To: "69000000"}}, "is after"},
+		{"unknown service level", []RestrictedArea{{Name: "a", From: "69000000", To: "69999999", Services: []string{"overnight"}}}, "unknown service level"},
+		{"negative surcharge", []RestrictedArea{{Name: "a", From: "69000000", To: "69999999", SurchargeRate: -0.1}}, "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := DefaultConfig()
+			cfg.RestrictedAreas = tt.areas
+
+			// Act
+			err := cfg.Validate()
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig_RestrictedAreas(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	content := `{
+		"default_country": "BR",
+		"currencies": {"BRL": {"base_cost": 1000, "weight_unit_kg": 0.5, "volume_unit_cm3": 1000}},
+		"countries": {"BR": "BRL"},
+		"restricted_areas": [
+			{"name": "noronha", "from": "53990-000", "to": "5399.0999"},
+			{"name": " remote-north ", "from": "69000-000", "to": "69999-999", "services": ["Express"], "surcharge_rate": 0.3}
+		]
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []RestrictedArea{
+		{Name: "noronha", From: "53990000", To: "53990999"},
+		{Name: "remote-north", From: "69000000", To: "69999999", Services: []string{LevelExpress}, SurchargeRate: 0.3},
+	}, cfg.RestrictedAreas)
+}
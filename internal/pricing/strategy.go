@@ -0,0 +1,225 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+)
+
+// Pricing strategies
+const (
+	StrategyFormula = "formula"
+	StrategyTable   = "table"
+	StrategyCarrier = "carrier"
+)
+
+// Service levels a strategy can be configured for
+const (
+	LevelStandard = "standard"
+	LevelExpress  = "express"
+)
+
+// ErrUnsupportedStrategy is returned when no pricing strategy is registered under the requested name
+var ErrUnsupportedStrategy = errors.New("unsupported pricing strategy")
+
+// ErrCarrierRate is returned by CarrierPricing when the carrier fails to quote a shipment
+var ErrCarrierRate = errors.New("carrier rate")
+
+// ErrWeightAboveTable is returned by TablePricing when the weight exceeds the heaviest bracket
+var ErrWeightAboveTable = errors.New("weight exceeds the rate table")
+
+// Shipment is what a pricing strategy quotes
+type Shipment struct {
+	OriginZipcode      string
+	DestinationZipcode string
+	// DestinationCountry is the ISO 3166-1 alpha-2 code, upper-case and defaulted by Config.Country
+	DestinationCountry string
+	Currency           string
+	// Level is the service level being priced, LevelStandard or LevelExpress
+	Level string
+	// Weight in kg and Volume in cm³
+	Weight float64
+	Volume float64
+	// Rates are the rates of the quote currency
+	Rates Rates
+}
+
+// Freight is the transport cost of a shipment, before the package type, delivery type and
+// express adjustments that apply to every strategy
+type Freight struct {
+	BaseCost        money.Amount
+	WeightSurcharge money.Amount
+	VolumeSurcharge money.Amount
+}
+
+// Total returns the sum of the freight components
+func (f Freight) Total() money.Amount {
+	return f.BaseCost + f.WeightSurcharge + f.VolumeSurcharge
+}
+
+// Strategy prices the freight of a shipment
+type Strategy interface {
+	Price(ctx context.Context, shipment Shipment) (Freight, error)
+}
+
+// DistanceResolver measures the distance between two zipcodes for FormulaPricing; ok is false
+// when it is unknown
+type DistanceResolver interface {
+	Distance(originZipcode, destinationZipcode string) (distance float64, ok bool)
+}
+
+// DistanceFunc adapts a function to DistanceResolver
+type DistanceFunc func(originZipcode, destinationZipcode string) (float64, bool)
+
+// Distance implements DistanceResolver
+func (f DistanceFunc) Distance(originZipcode, destinationZipcode string) (float64, bool) {
+	return f(originZipcode, destinationZipcode)
+}
+
+// FormulaPricing prices by distance: the base cost is the one of the regional rate of the route
+// or, without one, grows with the distance between the zipcodes; weight and volume add a
+// fraction of the base cost per unit
+type FormulaPricing struct {
+	// Distance measures the routes; nil uses ZipcodeDistance, the numeric difference between the
+	// zipcodes
+	Distance DistanceResolver
+}
+
+// Price implements Strategy
+func (p FormulaPricing) Price(_ context.Context, shipment Shipment) (Freight, error) {
+	resolver := p.Distance
+	if resolver == nil {
+		resolver = DistanceFunc(ZipcodeDistance)
+	}
+	distance, ok := resolver.Distance(shipment.OriginZipcode, shipment.DestinationZipcode)
+	baseCost := DistanceBaseCost(shipment.Rates, distance, ok)
+	regional, ok := shipment.Rates.RegionalRateFor(shipment.DestinationCountry, shipment.OriginZipcode, shipment.DestinationZipcode)
+	if ok && regional.BaseCost > 0 {
+		baseCost = regional.BaseCost
+	}
+	return FormulaFreight(shipment.Rates, baseCost, shipment.Weight, shipment.Volume), nil
+}
+
+// FormulaBaseCost calculates the base shipping cost based on distance between zipcodes
+func FormulaBaseCost(rates Rates, originZipcode, destinationZipcode string) money.Amount {
+	distance, ok := ZipcodeDistance(originZipcode, destinationZipcode)
+	return DistanceBaseCost(rates, distance, ok)
+}
+
+// DistanceBaseCost calculates the base shipping cost of a route of the given distance; ok is
+// false when the distance is unknown
+func DistanceBaseCost(rates Rates, distance float64, ok bool) money.Amount {
+	// If the distance is unknown, use default base cost
+	if !ok {
+		return rates.BaseCost
+	}
+
+	// Base cost increases with distance
+	// For same region (distance < 1000): base cost
+	// For different regions: base cost * (1 + distance/10000)
+	// This provides a simple distance-based pricing model
+	if distance < 1000 {
+		return rates.BaseCost
+	}
+
+	// Scale factor: 1% increase per 1000 units of distance difference
+	distanceFactor := 1.0 + (distance / 10000.0)
+	return rates.BaseCost.MulRate(distanceFactor)
+}
+
+// ZipcodeDistance returns the distance used by FormulaBaseCost, the absolute difference of the
+// normalized zipcodes as numbers; ok is false when a zipcode is not numeric
+func ZipcodeDistance(originZipcode, destinationZipcode string) (distance float64, ok bool) {
+	// Normalize zipcodes (remove separators, restore the leading zero of 7-digit CEPs)
+	originNormalized := zipcode.Normalize(originZipcode)
+	destNormalized := zipcode.Normalize(destinationZipcode)
+
+	// Convert to numbers (use first 4-8 digits)
+	originNum, err1 := strconv.ParseFloat(originNormalized, 64)
+	destNum, err2 := strconv.ParseFloat(destNormalized, 64)
+	if err1 != nil || err2 != nil {
+		return 0, false
+	}
+
+	// Calculate distance as absolute difference
+	distance = originNum - destNum
+	if distance < 0 {
+		distance = -distance
+	}
+	return distance, true
+}
+
+// FormulaFreight adds the weight and volume surcharges to a base cost
+func FormulaFreight(rates Rates, baseCost money.Amount, weight, volume float64) Freight {
+	// Weight surcharge: percentage of base cost per weight unit
+	weightMultiplier := weight / rates.WeightUnitKg
+	weightSurcharge := baseCost.MulRate(rates.WeightSurchargeRate * weightMultiplier)
+
+	// Volume surcharge: percentage of base cost per volume unit
+	volumeMultiplier := volume / rates.VolumeUnitCm3
+	volumeSurcharge := baseCost.MulRate(rates.VolumeSurchargeRate * volumeMultiplier)
+
+	return Freight{
+		BaseCost:        baseCost,
+		WeightSurcharge: weightSurcharge,
+		VolumeSurcharge: volumeSurcharge,
+	}
+}
+
+// TablePricing prices by weight bracket: the base cost is the cost of the lightest bracket of the
+// currency's WeightTable that fits the weight, and volume adds a fraction of it per unit as in
+// FormulaPricing. Distance is not taken into account
+type TablePricing struct{}
+
+// Price implements Strategy
+func (TablePricing) Price(_ context.Context, shipment Shipment) (Freight, error) {
+	if len(shipment.Rates.WeightTable) == 0 {
+		return Freight{}, fmt.Errorf("no weight_table configured for currency %q", shipment.Currency)
+	}
+	for _, bracket := range shipment.Rates.WeightTable {
+		if shipment.Weight <= bracket.MaxWeightKg {
+			return FormulaFreight(shipment.Rates, bracket.Cost, 0, shipment.Volume), nil
+		}
+	}
+	heaviest := shipment.Rates.WeightTable[len(shipment.Rates.WeightTable)-1]
+	return Freight{}, fmt.Errorf("%w: %g kg is above %g kg", ErrWeightAboveTable, shipment.Weight, heaviest.MaxWeightKg)
+}
+
+// CarrierRates quotes the freight of a shipment with a carrier, in minor units of the shipment currency
+type CarrierRates interface {
+	Rate(ctx context.Context, shipment Shipment) (money.Amount, error)
+}
+
+// CarrierPricing prices with the rate quoted by a carrier, which already includes weight and volume
+type CarrierPricing struct {
+	Rates CarrierRates
+}
+
+// Price implements Strategy
+func (p CarrierPricing) Price(ctx context.Context, shipment Shipment) (Freight, error) {
+	cost, err := p.Rates.Rate(ctx, shipment)
+	if err != nil {
+		return Freight{}, fmt.Errorf("%w: %w", ErrCarrierRate, err)
+	}
+	if cost < 0 {
+		return Freight{}, fmt.Errorf("%w: negative cost %s", ErrCarrierRate, cost)
+	}
+	return Freight{BaseCost: cost}, nil
+}
+
+// StrategyFor returns the name of the strategy that prices a service level: the per-request
+// override when given, otherwise the one configured for the level, otherwise StrategyFormula
+func (c Config) StrategyFor(level, override string) string {
+	if name := strings.ToLower(strings.TrimSpace(override)); name != "" {
+		return name
+	}
+	if name, ok := c.Strategies[level]; ok {
+		return name
+	}
+	return StrategyFormula
+}
@@ -0,0 +1,360 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormulaBaseCost_SameRegion(t *testing.T) {
+	// Arrange
+	originZipcode := "1414"
+	destinationZipcode := "1428"
+
+	// Act
+	baseCost := FormulaBaseCost(DefaultRates(), originZipcode, destinationZipcode)
+
+	// Assert
+	// Distance is 14 (< 1000), so should return base cost
+	assert.Equal(t, money.FromMinor(1000), baseCost)
+}
+
+func TestFormulaBaseCost_DifferentRegions(t *testing.T) {
+	// Arrange
+	originZipcode := "01000-000"
+	destinationZipcode := "20000-000"
+
+	// Act
+	baseCost := FormulaBaseCost(DefaultRates(), originZipcode, destinationZipcode)
+
+	// Assert
+	// Distance is 10000, so should have increased base cost
+	assert.Greater(t, baseCost, money.FromMinor(1000))
+}
+
+func TestZipcodeDistance(t *testing.T) {
+	tests := []struct {
+		name         string
+		origin       string
+		destination  string
+		wantDistance float64
+		wantOK       bool
+	}{
+		{"formatted zipcodes", "01000-000", "20000-000", 19000000, true},
+		{"symmetric", "20000000", "01000000", 19000000, true},
+		{"non numeric", "abc", "01000000", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			distance, ok := ZipcodeDistance(tt.origin, tt.destination)
+
+			// Assert
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantDistance, distance)
+		})
+	}
+}
+
+func TestFormulaBaseCost_InvalidZipcode_NonNumeric(t *testing.T) {
+	// Arrange
+	originZipcode := "abc"
+	destinationZipcode := "def"
+
+	// Act
+	baseCost := FormulaBaseCost(DefaultRates(), originZipcode, destinationZipcode)
+
+	// Assert
+	// Should return default base cost when conversion fails
+	assert.Equal(t, money.FromMinor(1000), baseCost)
+}
+
+func TestFormulaBaseCost_InvalidZipcode_Empty(t *testing.T) {
+	// Arrange
+	originZipcode := ""
+	destinationZipcode := ""
+
+	// Act
+	baseCost := FormulaBaseCost(DefaultRates(), originZipcode, destinationZipcode)
+
+	// Assert
+	// Should return default base cost when conversion fails
+	assert.Equal(t, money.FromMinor(1000), baseCost)
+}
+
+func TestFormulaBaseCost_NegativeDistance(t *testing.T) {
+	// Arrange
+	originZipcode := "20000"
+	destinationZipcode := "10000"
+
+	// Act
+	baseCost := FormulaBaseCost(DefaultRates(), originZipcode, destinationZipcode)
+
+	// Assert
+	// Distance is 10000, should have increased base cost
+	assert.Greater(t, baseCost, money.FromMinor(1000))
+}
+
+func TestFormulaBaseCost_WithHyphensAndSpaces(t *testing.T) {
+	// Arrange
+	originZipcode := "12 345-678"
+	destinationZipcode := "87-654 321"
+
+	// Act
+	baseCost := FormulaBaseCost(DefaultRates(), originZipcode, destinationZipcode)
+
+	// Assert
+	// Should normalize and calculate correctly
+	assert.Greater(t, baseCost, money.Amount(0))
+}
+
+func TestFormulaPricing_Price(t *testing.T) {
+	// Arrange
+	shipment := Shipment{
+		OriginZipcode:      "01310-100",
+		DestinationZipcode: "01310-200",
+		Weight:             1.0,
+		Volume:             1000,
+		Rates:              DefaultRates(),
+	}
+
+	// Act
+	freight, err := FormulaPricing{}.Price(context.Background(), shipment)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Freight{BaseCost: money.FromMinor(1000), WeightSurcharge: money.FromMinor(200), VolumeSurcharge: money.FromMinor(50)}, freight)
+	assert.Equal(t, money.FromMinor(1250), freight.Total())
+}
+
+func TestFormulaPricing_Price_Distance(t *testing.T) {
+	tests := []struct {
+		name     string
+		distance float64
+		ok       bool
+		want     money.Amount
+	}{
+		{"same region", 500, true, money.FromMinor(1000)},
+		{"distant route", 20000, true, money.FromMinor(3000)},
+		{"unknown distance", 0, false, money.FromMinor(1000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var origin, destination string
+			strategy := FormulaPricing{Distance: DistanceFunc(func(o, d string) (float64, bool) {
+				origin, destination = o, d
+				return tt.distance, tt.ok
+			})}
+			shipment := Shipment{OriginZipcode: "01310100", DestinationZipcode: "01310200", Weight: 1, Volume: 1000, Rates: DefaultRates()}
+
+			// Act
+			freight, err := strategy.Price(context.Background(), shipment)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, freight.BaseCost)
+			assert.Equal(t, "01310100", origin)
+			assert.Equal(t, "01310200", destination)
+		})
+	}
+}
+
+func TestTablePricing_Price(t *testing.T) {
+	rates := DefaultRates()
+	rates.WeightTable = []WeightBracket{
+		{MaxWeightKg: 1, Cost: money.FromMinor(1500)},
+		{MaxWeightKg: 5, Cost: money.FromMinor(2500)},
+		{MaxWeightKg: 30, Cost: money.FromMinor(6000)},
+	}
+
+	tests := []struct {
+		name    string
+		weight  float64
+		volume  float64
+		want    Freight
+		wantErr error
+	}{
+		{"lightest bracket", 0.5, 0, Freight{BaseCost: money.FromMinor(1500)}, nil},
+		{"bracket upper bound is inclusive", 1, 0, Freight{BaseCost: money.FromMinor(1500)}, nil},
+		{"middle bracket", 3, 0, Freight{BaseCost: money.FromMinor(2500)}, nil},
+		{"volume surcharge on bracket cost", 3, 2000, Freight{BaseCost: money.FromMinor(2500), VolumeSurcharge: money.FromMinor(250)}, nil},
+		{"above heaviest bracket", 31, 0, Freight{}, ErrWeightAboveTable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			freight, err := TablePricing{}.Price(context.Background(), Shipment{Weight: tt.weight, Volume: tt.volume, Rates: rates})
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, freight)
+		})
+	}
+}
+
+func TestTablePricing_Price_NoTable(t *testing.T) {
+	// Act
+	_, err := TablePricing{}.Price(context.Background(), Shipment{Currency: "BRL", Weight: 1, Rates: DefaultRates()})
+
+	// Assert
+	assert.ErrorContains(t, err, `no weight_table configured for currency "BRL"`)
+}
+
+type stubCarrierRates struct {
+	cost money.Amount
+	err  error
+}
+
+func (s stubCarrierRates) Rate(context.Context, Shipment) (money.Amount, error) {
+	return s.cost, s.err
+}
+
+func TestCarrierPricing_Price(t *testing.T) {
+	tests := []struct {
+		name    string
+		rates   stubCarrierRates
+		want    Freight
+		wantErr string
+	}{
+		{"carrier cost is the base cost", stubCarrierRates{cost: money.FromMinor(1830)}, Freight{BaseCost: money.FromMinor(1830)}, ""},
+		{"carrier failure", stubCarrierRates{err: errors.New("timeout")}, Freight{}, "carrier rate: timeout"},
+		{"negative cost", stubCarrierRates{cost: money.FromMinor(-1)}, Freight{}, "negative cost"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			freight, err := CarrierPricing{Rates: tt.rates}.Price(context.Background(), Shipment{})
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, freight)
+		})
+	}
+}
+
+func TestHTTPCarrierRates_Rate(t *testing.T) {
+	// Arrange
+	var got carrierRateRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_, _ = w.Write([]byte(`{"cost": 1830}`))
+	}))
+	defer server.Close()
+	rates := &HTTPCarrierRates{URL: server.URL}
+
+	// Act
+	cost, err := rates.Rate(context.Background(), Shipment{
+		OriginZipcode:      "01310-100",
+		DestinationZipcode: "20040-020",
+		Currency:           "BRL",
+		Level:              LevelExpress,
+		Weight:             2,
+		Volume:             1000,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMinor(1830), cost)
+	assert.Equal(t, LevelExpress, got.Service)
+	assert.Equal(t, "BRL", got.Currency)
+	assert.Equal(t, 2.0, got.Weight)
+}
+
+func TestHTTPCarrierRates_Rate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"unexpected status", http.StatusBadGateway, `{}`, "unexpected status 502"},
+		{"invalid body", http.StatusOK, `not json`, "failed to parse carrier rate"},
+		{"missing cost", http.StatusOK, `{}`, "missing cost"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			// Act
+			_, err := (&HTTPCarrierRates{URL: server.URL}).Rate(context.Background(), Shipment{})
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestConfig_StrategyFor(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Strategies = map[string]string{LevelExpress: StrategyCarrier}
+
+	tests := []struct {
+		name     string
+		level    string
+		override string
+		want     string
+	}{
+		{"unconfigured level uses formula", LevelStandard, "", StrategyFormula},
+		{"configured level", LevelExpress, "", StrategyCarrier},
+		{"request override wins", LevelExpress, " Table ", StrategyTable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.want, cfg.StrategyFor(tt.level, tt.override))
+		})
+	}
+}
+
+func TestCarrierConfigFromEnv(t *testing.T) {
+	// Arrange
+	t.Setenv("CARRIER_RATES_URL", "http://carrier.local/rates")
+	t.Setenv("CARRIER_RATES_TIMEOUT", "2s")
+
+	// Act
+	cfg, err := CarrierConfigFromEnv()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, "http://carrier.local/rates", cfg.URL)
+	assert.Equal(t, "2s", cfg.Timeout.String())
+}
+
+func TestCarrierConfigFromEnv_InvalidTimeout(t *testing.T) {
+	// Arrange
+	t.Setenv("CARRIER_RATES_TIMEOUT", "0s")
+
+	// Act
+	_, err := CarrierConfigFromEnv()
+
+	// Assert
+	assert.ErrorContains(t, err, "CARRIER_RATES_TIMEOUT")
+}
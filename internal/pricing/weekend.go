@@ -0,0 +1,78 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WeekendDelivery sets the weekend days a service level delivers on for requests that allow
+// weekend delivery, and the surcharge of the option
+type WeekendDelivery struct {
+	Saturday bool `json:"saturday"`
+	Sunday   bool `json:"sunday"`
+	// SurchargeRate is the fraction of the subtotal of the service level, including the pickup and
+	// restricted area surcharges, added when the option delivers on weekends; it is not subject to
+	// the express surcharge
+	SurchargeRate float64 `json:"surcharge_rate,omitempty"`
+}
+
+// Delivers reports whether the service level delivers on Saturdays or Sundays
+func (w WeekendDelivery) Delivers() bool {
+	return w.Saturday || w.Sunday
+}
+
+// WeekendDeliveryFor returns the weekend delivery of a service level for a request that allows
+// weekend delivery or not, and whether the level delivers on weekends for it. Without weekend
+// delivery configured no level does
+func (c Config) WeekendDeliveryFor(level string, allowed bool) (WeekendDelivery, bool) {
+	weekend, ok := c.WeekendDelivery[level]
+	if !allowed || !ok || !weekend.Delivers() {
+		return WeekendDelivery{}, false
+	}
+	return weekend, true
+}
+
+// NonDeliveryDays returns the weekend days a service level does not deliver on, skipped by its
+// transit days. Without weekend delivery configured there are none, every day being a delivery
+// day; with it Saturdays and Sundays are skipped unless the request allows weekend delivery and
+// the level delivers on them
+func (c Config) NonDeliveryDays(level string, allowed bool) []time.Weekday {
+	if c.WeekendDelivery == nil {
+		return nil
+	}
+	weekend, _ := c.WeekendDeliveryFor(level, allowed)
+	var days []time.Weekday
+	if !weekend.Saturday {
+		days = append(days, time.Saturday)
+	}
+	if !weekend.Sunday {
+		days = append(days, time.Sunday)
+	}
+	return days
+}
+
+// validateWeekendDelivery checks the service levels and surcharges of the weekend delivery
+func validateWeekendDelivery(levels map[string]WeekendDelivery) error {
+	for level, weekend := range levels {
+		if level != LevelStandard && level != LevelExpress && level != LevelFreight {
+			return fmt.Errorf("weekend_delivery: unknown service level %q", level)
+		}
+		if weekend.SurchargeRate < 0 {
+			return fmt.Errorf("weekend_delivery: service level %q: surcharge_rate must not be negative", level)
+		}
+	}
+	return nil
+}
+
+// normalizeWeekendDelivery lower-cases the service levels of the weekend delivery
+func normalizeWeekendDelivery(levels map[string]WeekendDelivery) map[string]WeekendDelivery {
+	if levels == nil {
+		return nil
+	}
+	out := make(map[string]WeekendDelivery, len(levels))
+	for level, weekend := range levels {
+		out[strings.ToLower(strings.TrimSpace(level))] = weekend
+	}
+	return out
+}
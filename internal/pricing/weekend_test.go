@@ -0,0 +1,122 @@
+package pricing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func weekendConfig() Config {
+	cfg := DefaultConfig()
+	cfg.WeekendDelivery = map[string]WeekendDelivery{
+		LevelExpress:  {Saturday: true, Sunday: true, SurchargeRate: 0.2},
+		LevelFreight:  {Saturday: true},
+		LevelStandard: {},
+	}
+	return cfg
+}
+
+func TestConfig_WeekendDeliveryFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		level   string
+		allowed bool
+		want    WeekendDelivery
+		wantOK  bool
+	}{
+		{"delivers on weekends", weekendConfig(), LevelExpress, true, WeekendDelivery{Saturday: true, Sunday: true, SurchargeRate: 0.2}, true},
+		{"request does not allow it", weekendConfig(), LevelExpress, false, WeekendDelivery{}, false},
+		{"level without weekend days", weekendConfig(), LevelStandard, true, WeekendDelivery{}, false},
+		{"not configured", DefaultConfig(), LevelExpress, true, WeekendDelivery{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			weekend, ok := tt.cfg.WeekendDeliveryFor(tt.level, tt.allowed)
+
+			// Assert
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, weekend)
+		})
+	}
+}
+
+func TestConfig_NonDeliveryDays(t *testing.T) {
+	weekend := []time.Weekday{time.Saturday, time.Sunday}
+	tests := []struct {
+		name    string
+		cfg     Config
+		level   string
+		allowed bool
+		want    []time.Weekday
+	}{
+		{"not configured", DefaultConfig(), LevelStandard, true, nil},
+		{"delivers on both days", weekendConfig(), LevelExpress, true, nil},
+		{"delivers on saturdays", weekendConfig(), LevelFreight, true, []time.Weekday{time.Sunday}},
+		{"request does not allow it", weekendConfig(), LevelExpress, false, weekend},
+		{"level without weekend days", weekendConfig(), LevelStandard, true, weekend},
+		{"level not listed", weekendConfig(), "overnight", true, weekend},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			days := tt.cfg.NonDeliveryDays(tt.level, tt.allowed)
+
+			// Assert
+			assert.Equal(t, tt.want, days)
+		})
+	}
+}
+
+func TestValidate_WeekendDeliveryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		weekend map[string]WeekendDelivery
+		wantErr string
+	}{
+		{"unknown service level", map[string]WeekendDelivery{"overnight": {Saturday: true}}, "unknown service level"},
+		{"negative surcharge", map[string]WeekendDelivery{LevelExpress: {Saturday: true, SurchargeRate: -0.1}}, "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := DefaultConfig()
+			cfg.WeekendDelivery = tt.weekend
+
+			// Act
+			err := cfg.Validate()
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig_WeekendDelivery(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	content := `{
+		"default_country": "BR",
+		"currencies": {"BRL": {"base_cost": 1000, "weight_unit_kg": 0.5, "volume_unit_cm3": 1000}},
+		"countries": {"BR": "BRL"},
+		"weekend_delivery": {"Express": {"saturday": true, "surcharge_rate": 0.2}, "standard": {}}
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]WeekendDelivery{
+		LevelExpress:  {Saturday: true, SurchargeRate: 0.2},
+		LevelStandard: {},
+	}, cfg.WeekendDelivery)
+}
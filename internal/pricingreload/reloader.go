@@ -0,0 +1,325 @@
+// Package pricingreload puts changes of the pricing configuration file in force at runtime, keeps
+// an audit trail of the versions in force and records them for repricing as of a past date.
+package pricingreload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+	"go.uber.org/zap"
+)
+
+// Sources of a pricing configuration version
+const (
+	// SourceStartup is the configuration loaded when the process started
+	SourceStartup = "startup"
+	// SourceFile is a change of the configuration file found by Watch
+	SourceFile = "file"
+	// SourceSignal is a reload requested with SIGHUP
+	SourceSignal = "signal"
+	// SourceAdmin is a reload requested through the admin API, by an actor
+	SourceAdmin = "admin"
+	// SourceFuelSurcharge is a fuel surcharge index set through the admin API, by an actor
+	SourceFuelSurcharge = "fuel_surcharge"
+)
+
+// Outcomes of a reload, counted in the shipping.calculate.pricing.reload metric
+const (
+	// OutcomeChanged is a reload that put a new configuration in force
+	OutcomeChanged = "changed"
+	// OutcomeUnchanged is a reload of the configuration in force
+	OutcomeUnchanged = "unchanged"
+	// OutcomeFailed is a reload rejected with the configuration in force kept
+	OutcomeFailed = "failed"
+)
+
+// ErrNoConfigFile is returned by Reload when the built-in pricing configuration is in force,
+// since it cannot change at runtime
+var ErrNoConfigFile = errors.New("PRICING_CONFIG_PATH is not set")
+
+// Config configures the reloads of the pricing configuration
+type Config struct {
+	// Path is the pricing configuration file; empty when the built-in configuration is used
+	Path string
+	// WatchInterval is how often Watch checks the file for changes; 0 disables watching
+	WatchInterval time.Duration
+	// HistorySize is how many versions History keeps, including the one in force
+	HistorySize int
+}
+
+// ConfigFromEnv reads PRICING_CONFIG_PATH, PRICING_CONFIG_WATCH_INTERVAL (default 0, disabled)
+// and PRICING_CONFIG_HISTORY_SIZE (default 10)
+func ConfigFromEnv() (Config, error) {
+	interval, err := config.Duration("PRICING_CONFIG_WATCH_INTERVAL", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	if interval < 0 {
+		return Config{}, fmt.Errorf("PRICING_CONFIG_WATCH_INTERVAL must not be negative, got %s", interval)
+	}
+	historySize, err := config.Int("PRICING_CONFIG_HISTORY_SIZE", 10)
+	if err != nil {
+		return Config{}, err
+	}
+	if historySize < 1 {
+		return Config{}, fmt.Errorf("PRICING_CONFIG_HISTORY_SIZE must be at least 1, got %d", historySize)
+	}
+	return Config{
+		Path:          config.String("PRICING_CONFIG_PATH", ""),
+		WatchInterval: interval,
+		HistorySize:   historySize,
+	}, nil
+}
+
+// Target holds the pricing configuration in force, e.g. the shipping service
+type Target interface {
+	Pricing() pricing.Config
+	SetPricing(cfg pricing.Config) error
+}
+
+// Revision is a pricing configuration version put in force, with what changed from the previous one
+type Revision struct {
+	Version         string `json:"version"`
+	PreviousVersion string `json:"previous_version,omitempty"`
+	Source          string `json:"source"`
+	// Actor is who requested an admin reload or set the fuel surcharge
+	Actor     string           `json:"actor,omitempty"`
+	Timestamp time.Time        `json:"timestamp"`
+	Changes   []pricing.Change `json:"changes"`
+}
+
+// Reloader loads the pricing configuration file into a target when asked to or when the file
+// changes, auditing every change. It is safe for concurrent use; reloads run one at a time
+type Reloader struct {
+	cfg       Config
+	target    Target
+	publisher events.Publisher
+	metrics   *telemetry.Metrics
+	logger    *zap.Logger
+	now       func() time.Time
+
+	mu      sync.Mutex
+	history []Revision
+	modTime time.Time
+	// fuel is the last fuel surcharge index set with SetFuelSurcharge, kept on reloads until the
+	// file sets a later one
+	fuel *pricing.FuelSurcharge
+	// listeners are called with every revision put in force
+	listeners []func(Revision)
+	// versions records the configurations put in force; nil when they are not recorded
+	versions repository.PricingVersionRepository
+}
+
+// New creates a reloader of the configuration in force in target, recording it as the first version.
+// Reloads are counted in metrics
+func New(cfg Config, target Target, publisher events.Publisher, metrics *telemetry.Metrics, logger *zap.Logger) *Reloader {
+	r := &Reloader{cfg: cfg, target: target, publisher: publisher, metrics: metrics, logger: logger, now: time.Now}
+	r.history = []Revision{{
+		Version:   target.Pricing().VersionID(),
+		Source:    SourceStartup,
+		Timestamp: r.now().UTC(),
+		Changes:   []pricing.Change{},
+	}}
+	if cfg.Path != "" {
+		if info, err := os.Stat(cfg.Path); err == nil {
+			r.modTime = info.ModTime()
+		}
+	}
+	return r
+}
+
+// Reload loads the configuration file and puts it in force when it differs from the one in force,
+// logging an audit entry and publishing a pricing.config_changed event. It returns the revision in
+// force and whether it changed. Actor is who requested the reload, empty unless source is SourceAdmin.
+// A fuel surcharge index set with SetFuelSurcharge replaces the one of the file unless the file's
+// came into force later
+func (r *Reloader) Reload(ctx context.Context, source, actor string) (revision Revision, changed bool, err error) {
+	if r.cfg.Path == "" {
+		return Revision{}, false, ErrNoConfigFile
+	}
+	defer func() { r.countReload(ctx, source, changed, err) }()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if info, err := os.Stat(r.cfg.Path); err == nil {
+		r.modTime = info.ModTime()
+	}
+	loaded, err := pricing.LoadConfig(r.cfg.Path)
+	if err != nil {
+		return Revision{}, false, err
+	}
+	if r.fuel != nil && (loaded.FuelSurcharge == nil || !loaded.FuelSurcharge.Supersedes(*r.fuel)) {
+		loaded.FuelSurcharge = r.fuel
+	}
+	return r.apply(ctx, loaded, source, actor)
+}
+
+// SetFuelSurcharge puts a fuel surcharge index in force on behalf of actor, auditing it like a
+// reload with source SourceFuelSurcharge. The index is kept on later reloads until the
+// configuration file sets one with a later effective date
+func (r *Reloader) SetFuelSurcharge(ctx context.Context, fuel pricing.FuelSurcharge, actor string) (revision Revision, changed bool, err error) {
+	defer func() { r.countReload(ctx, SourceFuelSurcharge, changed, err) }()
+	if err := fuel.Validate(); err != nil {
+		return Revision{}, false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg := r.target.Pricing()
+	cfg.FuelSurcharge = &fuel
+	revision, changed, err = r.apply(ctx, cfg, SourceFuelSurcharge, actor)
+	if err != nil {
+		return Revision{}, false, err
+	}
+	r.fuel = &fuel
+	return revision, changed, nil
+}
+
+// apply puts cfg in force when it differs from the configuration in force, recording and
+// auditing the revision. The caller holds r.mu
+func (r *Reloader) apply(ctx context.Context, loaded pricing.Config, source, actor string) (Revision, bool, error) {
+	current := r.history[len(r.history)-1]
+	changes := pricing.Diff(r.target.Pricing(), loaded)
+	if len(changes) == 0 {
+		return current, false, nil
+	}
+	if err := r.target.SetPricing(loaded); err != nil {
+		return Revision{}, false, fmt.Errorf("invalid pricing config: %w", err)
+	}
+
+	revision := Revision{
+		Version:         loaded.VersionID(),
+		PreviousVersion: current.Version,
+		Source:          source,
+		Actor:           actor,
+		Timestamp:       r.now().UTC(),
+		Changes:         changes,
+	}
+	if r.versions != nil {
+		version := repository.PricingVersion{Version: revision.Version, EffectiveFrom: revision.Timestamp, Config: loaded}
+		if err := r.versions.Save(ctx, version); err != nil {
+			logger.LogError(r.logger, ctx, "Erro ao registrar versão das tarifas", err, zap.String("versão_tarifas", revision.Version))
+		}
+	}
+	r.history = append(r.history, revision)
+	if len(r.history) > r.cfg.HistorySize {
+		r.history = r.history[len(r.history)-r.cfg.HistorySize:]
+	}
+
+	logger.LogRequest(r.logger, ctx, "Configuração de tarifas alterada",
+		zap.String("auditoria", events.PricingConfigChanged),
+		zap.String("versão_tarifas", revision.Version),
+		zap.String("versão_anterior", revision.PreviousVersion),
+		zap.String("origem", revision.Source),
+		zap.String("ator", revision.Actor),
+		zap.Time("horário", revision.Timestamp),
+		zap.Any("alterações", revision.Changes),
+	)
+	event := events.Event{Type: events.PricingConfigChanged, Subject: revision.Version, OccurredAt: revision.Timestamp, Data: revision}
+	if err := r.publisher.Publish(ctx, event); err != nil {
+		logger.LogError(r.logger, ctx, "Falha ao publicar alteração das tarifas", err)
+	}
+	for _, listener := range r.listeners {
+		listener(revision)
+	}
+	return revision, true, nil
+}
+
+// countReload counts a reload from source by its outcome
+func (r *Reloader) countReload(ctx context.Context, source string, changed bool, err error) {
+	r.metrics.IncrementPricingReload(ctx, source, reloadOutcome(changed, err))
+}
+
+// reloadOutcome returns the outcome of a reload
+func reloadOutcome(changed bool, err error) string {
+	switch {
+	case err != nil:
+		return OutcomeFailed
+	case changed:
+		return OutcomeChanged
+	default:
+		return OutcomeUnchanged
+	}
+}
+
+// OnChange registers fn to be called with every revision put in force from now on, e.g. to warm
+// the caches priced with the previous configuration. fn is called while the reloader is locked and
+// must not block
+func (r *Reloader) OnChange(fn func(Revision)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// RecordVersions saves the configuration in force, and every one put in force from now on, in
+// versions, so that quotes can be repriced with the configuration in force at a past date. The
+// configuration in force is saved as effective now, unless it is the latest version saved, e.g. by
+// a previous run. Failures to save a later version are logged and do not fail its reload
+func (r *Reloader) RecordVersions(ctx context.Context, versions repository.PricingVersionRepository) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.versions = versions
+	cfg := r.target.Pricing()
+	return versions.Save(ctx, repository.PricingVersion{Version: cfg.VersionID(), EffectiveFrom: r.now().UTC(), Config: cfg})
+}
+
+// History returns the versions put in force, the one in force first
+func (r *Reloader) History() []Revision {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	history := make([]Revision, 0, len(r.history))
+	for i := len(r.history) - 1; i >= 0; i-- {
+		history = append(history, r.history[i])
+	}
+	return history
+}
+
+// Watch reloads the configuration file whenever its modification time changes, checking every
+// WatchInterval until ctx is cancelled. It returns at once when watching is disabled. A failed
+// reload keeps the configuration in force
+func (r *Reloader) Watch(ctx context.Context) {
+	if r.cfg.Path == "" || r.cfg.WatchInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(r.cfg.WatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !r.modified() {
+				continue
+			}
+			if _, _, err := r.Reload(ctx, SourceFile, ""); err != nil {
+				r.logger.Error("Failed to reload pricing configuration", zap.String("path", r.cfg.Path), zap.Error(err))
+			}
+		}
+	}
+}
+
+// modified reports whether the configuration file changed since it was last loaded
+func (r *Reloader) modified() bool {
+	info, err := os.Stat(r.cfg.Path)
+	if err != nil {
+		r.logger.Error("Failed to check pricing configuration", zap.String("path", r.cfg.Path), zap.Error(err))
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !info.ModTime().Equal(r.modTime)
+}
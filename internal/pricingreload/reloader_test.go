@@ -0,0 +1,350 @@
+package pricingreload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// writeConfig writes the default pricing configuration with the BRL base cost to path
+func writeConfig(t *testing.T, path string, baseCost int64) {
+	t.Helper()
+	cfg := pricing.DefaultConfig()
+	brl := cfg.Currencies["BRL"]
+	brl.BaseCost = money.FromMinor(float64(baseCost))
+	cfg.Currencies["BRL"] = brl
+	data, err := json.Marshal(cfg)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func newTestReloader(t *testing.T, historySize int) (*Reloader, *service.ShippingService, *events.MemoryPublisher, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	writeConfig(t, path, 1000)
+	shipping := service.NewShippingService()
+	publisher := events.NewMemoryPublisher()
+	reloader := New(Config{Path: path, HistorySize: historySize}, shipping, publisher, nil, zaptest.NewLogger(t))
+	return reloader, shipping, publisher, path
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr bool
+	}{
+		{"defaults", map[string]string{}, Config{HistorySize: 10}, false},
+		{"custom", map[string]string{"PRICING_CONFIG_PATH": "pricing.json", "PRICING_CONFIG_WATCH_INTERVAL": "30s", "PRICING_CONFIG_HISTORY_SIZE": "5"}, Config{Path: "pricing.json", WatchInterval: 30 * time.Second, HistorySize: 5}, false},
+		{"negative interval", map[string]string{"PRICING_CONFIG_WATCH_INTERVAL": "-1s"}, Config{}, true},
+		{"empty history", map[string]string{"PRICING_CONFIG_HISTORY_SIZE": "0"}, Config{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for _, key := range []string{"PRICING_CONFIG_PATH", "PRICING_CONFIG_WATCH_INTERVAL", "PRICING_CONFIG_HISTORY_SIZE"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	// Arrange
+	reloader, shipping, publisher, path := newTestReloader(t, 10)
+	startup := shipping.Pricing().VersionID()
+	writeConfig(t, path, 1200)
+
+	// Act
+	revision, changed, err := reloader.Reload(context.Background(), SourceAdmin, "alice")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, shipping.Pricing().VersionID(), revision.Version)
+	assert.Equal(t, startup, revision.PreviousVersion)
+	assert.Equal(t, SourceAdmin, revision.Source)
+	assert.Equal(t, "alice", revision.Actor)
+	assert.NotZero(t, revision.Timestamp)
+	assert.Equal(t, []pricing.Change{{Path: "currencies.BRL.base_cost", Old: float64(1000), New: float64(1200)}}, revision.Changes)
+	assert.Equal(t, money.FromMinor(1200), shipping.Pricing().Currencies["BRL"].BaseCost)
+
+	history := reloader.History()
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, revision, history[0])
+		assert.Equal(t, SourceStartup, history[1].Source)
+		assert.Equal(t, startup, history[1].Version)
+	}
+	if assert.Len(t, publisher.Events(), 1) {
+		event := publisher.Events()[0]
+		assert.Equal(t, events.PricingConfigChanged, event.Type)
+		assert.Equal(t, revision.Version, event.Subject)
+		assert.Equal(t, revision, event.Data)
+	}
+}
+
+func TestReload_Unchanged(t *testing.T) {
+	// Arrange
+	reloader, _, publisher, _ := newTestReloader(t, 10)
+
+	// Act
+	revision, changed, err := reloader.Reload(context.Background(), SourceSignal, "")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, SourceStartup, revision.Source)
+	assert.Len(t, reloader.History(), 1)
+	assert.Empty(t, publisher.Events())
+}
+
+func TestOnChange(t *testing.T) {
+	// Arrange
+	reloader, _, _, path := newTestReloader(t, 10)
+	var notified []Revision
+	reloader.OnChange(func(revision Revision) { notified = append(notified, revision) })
+
+	// Act
+	_, _, unchangedErr := reloader.Reload(context.Background(), SourceSignal, "")
+	writeConfig(t, path, 1200)
+	revision, _, changedErr := reloader.Reload(context.Background(), SourceSignal, "")
+
+	// Assert
+	assert.NoError(t, unchangedErr)
+	assert.NoError(t, changedErr)
+	assert.Equal(t, []Revision{revision}, notified)
+}
+
+func TestRecordVersions(t *testing.T) {
+	// Arrange
+	reloader, shipping, _, path := newTestReloader(t, 10)
+	startup := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	reloader.now = func() time.Time { return startup }
+	versions := repository.NewMemoryPricingVersionRepository()
+	initial := shipping.Pricing()
+
+	// Act
+	recordErr := reloader.RecordVersions(context.Background(), versions)
+	reloader.now = func() time.Time { return startup.Add(time.Hour) }
+	writeConfig(t, path, 1200)
+	revision, _, reloadErr := reloader.Reload(context.Background(), SourceSignal, "")
+	before, beforeErr := versions.AsOf(context.Background(), startup.Add(time.Minute))
+	after, afterErr := versions.AsOf(context.Background(), startup.Add(2*time.Hour))
+
+	// Assert
+	assert.NoError(t, recordErr)
+	assert.NoError(t, reloadErr)
+	assert.NoError(t, beforeErr)
+	assert.NoError(t, afterErr)
+	assert.Equal(t, repository.PricingVersion{Version: initial.VersionID(), EffectiveFrom: startup, Config: initial}, before)
+	assert.Equal(t, revision.Version, after.Version)
+	assert.Equal(t, revision.Timestamp, after.EffectiveFrom)
+	assert.Equal(t, shipping.Pricing(), after.Config)
+}
+
+func TestReload_InvalidConfig(t *testing.T) {
+	// Arrange
+	reloader, shipping, publisher, path := newTestReloader(t, 10)
+	before := shipping.Pricing().VersionID()
+	assert.NoError(t, os.WriteFile(path, []byte(`{"currencies": {}}`), 0o600))
+
+	// Act
+	_, changed, err := reloader.Reload(context.Background(), SourceSignal, "")
+
+	// Assert
+	assert.Error(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, before, shipping.Pricing().VersionID())
+	assert.Len(t, reloader.History(), 1)
+	assert.Empty(t, publisher.Events())
+}
+
+func TestReload_UnregisteredStrategy(t *testing.T) {
+	// Arrange
+	reloader, shipping, _, path := newTestReloader(t, 10)
+	before := shipping.Pricing().VersionID()
+	cfg := pricing.DefaultConfig()
+	cfg.Strategies = map[string]string{pricing.LevelExpress: pricing.StrategyCarrier}
+	data, _ := json.Marshal(cfg)
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+
+	// Act
+	_, _, err := reloader.Reload(context.Background(), SourceSignal, "")
+
+	// Assert
+	assert.ErrorIs(t, err, pricing.ErrUnsupportedStrategy)
+	assert.Equal(t, before, shipping.Pricing().VersionID())
+}
+
+func TestReload_NoConfigFile(t *testing.T) {
+	// Arrange
+	reloader := New(Config{HistorySize: 10}, service.NewShippingService(), events.NewMemoryPublisher(), nil, zaptest.NewLogger(t))
+
+	// Act
+	_, _, err := reloader.Reload(context.Background(), SourceSignal, "")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNoConfigFile)
+}
+
+func TestReload_HistorySize(t *testing.T) {
+	// Arrange
+	reloader, _, _, path := newTestReloader(t, 2)
+
+	// Act
+	for _, baseCost := range []int64{1100, 1200, 1300} {
+		writeConfig(t, path, baseCost)
+		_, _, err := reloader.Reload(context.Background(), SourceSignal, "")
+		assert.NoError(t, err)
+	}
+
+	// Assert
+	history := reloader.History()
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, float64(1300), history[0].Changes[0].New)
+		assert.Equal(t, float64(1200), history[1].Changes[0].New)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	writeConfig(t, path, 1000)
+	shipping := service.NewShippingService()
+	reloader := New(Config{Path: path, WatchInterval: 10 * time.Millisecond, HistorySize: 10}, shipping, events.NewMemoryPublisher(), nil, zaptest.NewLogger(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Act
+	go reloader.Watch(ctx)
+	writeConfig(t, path, 1500)
+	modified := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(path, modified, modified))
+
+	// Assert
+	assert.Eventually(t, func() bool {
+		return shipping.Pricing().Currencies["BRL"].BaseCost == money.FromMinor(1500)
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, SourceFile, reloader.History()[0].Source)
+}
+
+// writeFuelConfig writes the default pricing configuration with a fuel surcharge index to path
+func writeFuelConfig(t *testing.T, path string, fuel *pricing.FuelSurcharge) {
+	t.Helper()
+	cfg := pricing.DefaultConfig()
+	cfg.FuelSurcharge = fuel
+	data, err := json.Marshal(cfg)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func TestSetFuelSurcharge(t *testing.T) {
+	// Arrange
+	reloader, shipping, publisher, _ := newTestReloader(t, 10)
+	fuel := pricing.FuelSurcharge{Rate: 0.12, EffectiveDate: "2025-04-07"}
+
+	// Act
+	revision, changed, err := reloader.SetFuelSurcharge(context.Background(), fuel, "alice")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, SourceFuelSurcharge, revision.Source)
+	assert.Equal(t, "alice", revision.Actor)
+	assert.Equal(t, []pricing.Change{{Path: "fuel_surcharge", Old: nil, New: map[string]any{"rate": 0.12, "effective_date": "2025-04-07"}}}, revision.Changes)
+	assert.Equal(t, &fuel, shipping.Pricing().FuelSurcharge)
+	assert.Len(t, publisher.Events(), 1)
+}
+
+func TestSetFuelSurcharge_Invalid(t *testing.T) {
+	// Arrange
+	reloader, shipping, _, _ := newTestReloader(t, 10)
+
+	// Act
+	_, changed, err := reloader.SetFuelSurcharge(context.Background(), pricing.FuelSurcharge{Rate: 12, EffectiveDate: "2025-04-07"}, "alice")
+
+	// Assert
+	assert.ErrorContains(t, err, "rate must be between 0 and 1")
+	assert.False(t, changed)
+	assert.Nil(t, shipping.Pricing().FuelSurcharge)
+	assert.Len(t, reloader.History(), 1)
+}
+
+func TestReload_KeepsFuelSurcharge(t *testing.T) {
+	set := pricing.FuelSurcharge{Rate: 0.12, EffectiveDate: "2025-04-07"}
+	tests := []struct {
+		name     string
+		fileFuel *pricing.FuelSurcharge
+		want     pricing.FuelSurcharge
+	}{
+		{"file without index", nil, set},
+		{"earlier index in the file", &pricing.FuelSurcharge{Rate: 0.10, EffectiveDate: "2025-03-31"}, set},
+		{"same week in the file", &pricing.FuelSurcharge{Rate: 0.10, EffectiveDate: "2025-04-07"}, set},
+		{"later index in the file", &pricing.FuelSurcharge{Rate: 0.14, EffectiveDate: "2025-04-14"}, pricing.FuelSurcharge{Rate: 0.14, EffectiveDate: "2025-04-14"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			reloader, shipping, _, path := newTestReloader(t, 10)
+			_, _, err := reloader.SetFuelSurcharge(context.Background(), set, "alice")
+			assert.NoError(t, err)
+			writeFuelConfig(t, path, tt.fileFuel)
+
+			// Act
+			_, _, reloadErr := reloader.Reload(context.Background(), SourceSignal, "")
+
+			// Assert
+			assert.NoError(t, reloadErr)
+			assert.Equal(t, &tt.want, shipping.Pricing().FuelSurcharge)
+		})
+	}
+}
+
+func TestReloadOutcome(t *testing.T) {
+	tests := []struct {
+		name    string
+		changed bool
+		err     error
+		want    string
+	}{
+		{"new configuration", true, nil, OutcomeChanged},
+		{"same configuration", false, nil, OutcomeUnchanged},
+		{"rejected configuration", false, errors.New("invalid pricing config"), OutcomeFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			outcome := reloadOutcome(tt.changed, tt.err)
+
+			// Assert
+			assert.Equal(t, tt.want, outcome)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+// Package quotestats aggregates the quotes of the last minutes in memory, such as the quotes per
+// minute, the average cost and the error rate, for operational checks without a metrics backend.
+package quotestats
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+)
+
+// MaxWindow bounds the aggregated period, whose quotes are kept in one bucket per second
+const MaxWindow = time.Hour
+
+// topRegions is the number of destination regions reported by Snapshot
+const topRegions = 5
+
+// Config sets the period aggregated by the window
+type Config struct {
+	Window time.Duration
+}
+
+// ConfigFromEnv reads QUOTE_STATS_WINDOW (default 5m)
+func ConfigFromEnv() (Config, error) {
+	window, err := config.Duration("QUOTE_STATS_WINDOW", 5*time.Minute)
+	if err != nil {
+		return Config{}, err
+	}
+	if window < time.Second || window > MaxWindow {
+		return Config{}, fmt.Errorf("QUOTE_STATS_WINDOW must be between 1s and %s, got %s", MaxWindow, window)
+	}
+	return Config{Window: window}, nil
+}
+
+// Stats are the aggregates of the quotes calculated from From to To
+type Stats struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Quotes are the priced quotes and Errors the calculations that failed, e.g. for an invalid
+	// zipcode; ErrorRate is the fraction of the calculations that failed
+	Quotes          int64   `json:"quotes"`
+	Errors          int64   `json:"errors"`
+	QuotesPerMinute float64 `json:"quotes_per_minute"`
+	ErrorRate       float64 `json:"error_rate"`
+	// AverageCost is the average shipping cost of the quotes of each currency, sorted by currency
+	AverageCost []CurrencyCost `json:"average_cost"`
+	// TopRegions are the destination regions with the most quotes, most quoted first
+	TopRegions []RegionCount `json:"top_regions"`
+}
+
+// CurrencyCost is the average shipping cost of the quotes in a currency
+type CurrencyCost struct {
+	Currency string       `json:"currency"`
+	Quotes   int64        `json:"quotes"`
+	Average  money.Amount `json:"average"`
+}
+
+// RegionCount is the number of quotes to a destination region: the destination state of
+// Brazilian shipments or the destination country of international ones
+type RegionCount struct {
+	Region string `json:"region"`
+	Quotes int64  `json:"quotes"`
+}
+
+// bucket counts the calculations of one second
+type bucket struct {
+	// second is the Unix time of the second counted, 0 for a bucket never used
+	second  int64
+	quotes  int64
+	errors  int64
+	costs   map[string]costSum
+	regions map[string]int64
+}
+
+// costSum adds up the shipping costs of the quotes in a currency
+type costSum struct {
+	total money.Amount
+	count int64
+}
+
+// Window aggregates the calculations of the last Config.Window in a ring of per-second buckets,
+// reused as time passes. It is safe for concurrent use
+type Window struct {
+	size    time.Duration
+	now     func() time.Time
+	started time.Time
+
+	mu      sync.Mutex
+	buckets []bucket
+}
+
+// NewWindow creates a window aggregating the calculations of the last size, rounded up to a
+// whole second
+func NewWindow(size time.Duration) *Window {
+	seconds := int((size + time.Second - 1) / time.Second)
+	now := time.Now
+	return &Window{size: time.Duration(seconds) * time.Second, now: now, started: now(), buckets: make([]bucket, seconds)}
+}
+
+// RecordQuote counts a quote priced at cost in currency to a destination region
+func (w *Window) RecordQuote(region, currency string, cost money.Amount) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	b := w.current()
+	b.quotes++
+	if b.costs == nil {
+		b.costs = make(map[string]costSum)
+		b.regions = make(map[string]int64)
+	}
+	sum := b.costs[currency]
+	sum.total += cost
+	sum.count++
+	b.costs[currency] = sum
+	b.regions[region]++
+}
+
+// RecordError counts a calculation that failed
+func (w *Window) RecordError() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.current().errors++
+}
+
+// current returns the bucket of the current second, reset when it last counted an older second.
+// w.mu must be held
+func (w *Window) current() *bucket {
+	second := w.now().Unix()
+	b := &w.buckets[second%int64(len(w.buckets))]
+	if b.second != second {
+		*b = bucket{second: second}
+	}
+	return b
+}
+
+// Snapshot returns the aggregates of the last Config.Window, or of the time since the window was
+// created when shorter
+func (w *Window) Snapshot() Stats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	to := w.now().UTC()
+	from := to.Add(-w.size)
+	if w.started.After(from) {
+		from = w.started.UTC()
+	}
+	oldest := to.Unix() - int64(len(w.buckets))
+
+	stats := Stats{From: from, To: to, AverageCost: []CurrencyCost{}, TopRegions: []RegionCount{}}
+	costs := make(map[string]costSum)
+	regions := make(map[string]int64)
+	for _, b := range w.buckets {
+		if b.second <= oldest || b.second > to.Unix() {
+			continue
+		}
+		stats.Quotes += b.quotes
+		stats.Errors += b.errors
+		for currency, sum := range b.costs {
+			total := costs[currency]
+			total.total += sum.total
+			total.count += sum.count
+			costs[currency] = total
+		}
+		for region, count := range b.regions {
+			regions[region] += count
+		}
+	}
+
+	if minutes := to.Sub(from).Minutes(); minutes > 0 {
+		stats.QuotesPerMinute = float64(stats.Quotes) / minutes
+	}
+	if calculations := stats.Quotes + stats.Errors; calculations > 0 {
+		stats.ErrorRate = float64(stats.Errors) / float64(calculations)
+	}
+	for currency, sum := range costs {
+		stats.AverageCost = append(stats.AverageCost, CurrencyCost{Currency: currency, Quotes: sum.count, Average: sum.total / money.Amount(sum.count)})
+	}
+	sort.Slice(stats.AverageCost, func(i, j int) bool { return stats.AverageCost[i].Currency < stats.AverageCost[j].Currency })
+	for region, count := range regions {
+		stats.TopRegions = append(stats.TopRegions, RegionCount{Region: region, Quotes: count})
+	}
+	sort.Slice(stats.TopRegions, func(i, j int) bool {
+		if stats.TopRegions[i].Quotes != stats.TopRegions[j].Quotes {
+			return stats.TopRegions[i].Quotes > stats.TopRegions[j].Quotes
+		}
+		return stats.TopRegions[i].Region < stats.TopRegions[j].Region
+	})
+	if len(stats.TopRegions) > topRegions {
+		stats.TopRegions = stats.TopRegions[:topRegions]
+	}
+	return stats
+}
@@ -0,0 +1,173 @@
+package quotestats
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+var statsStart = time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+
+// newTestWindow creates a window started at statsStart whose clock is advanced by the returned
+// function
+func newTestWindow(size time.Duration) (*Window, func(time.Duration)) {
+	now := statsStart
+	window := NewWindow(size)
+	window.now = func() time.Time { return now }
+	window.started = statsStart
+	return window, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestWindow_Snapshot(t *testing.T) {
+	// Arrange
+	window, advance := newTestWindow(5 * time.Minute)
+	window.RecordQuote("SP", "BRL", money.FromMinor(1000))
+	window.RecordQuote("SP", "BRL", money.FromMinor(2000))
+	advance(30 * time.Second)
+	window.RecordQuote("RJ", "BRL", money.FromMinor(3000))
+	window.RecordQuote("US", "USD", money.FromMinor(500))
+	window.RecordError()
+	advance(30 * time.Second)
+
+	// Act
+	stats := window.Snapshot()
+
+	// Assert
+	assert.Equal(t, statsStart, stats.From)
+	assert.Equal(t, statsStart.Add(time.Minute), stats.To)
+	assert.Equal(t, int64(4), stats.Quotes)
+	assert.Equal(t, int64(1), stats.Errors)
+	assert.InDelta(t, 4.0, stats.QuotesPerMinute, 1e-9)
+	assert.InDelta(t, 0.2, stats.ErrorRate, 1e-9)
+	assert.Equal(t, []CurrencyCost{
+		{Currency: "BRL", Quotes: 3, Average: money.FromMinor(2000)},
+		{Currency: "USD", Quotes: 1, Average: money.FromMinor(500)},
+	}, stats.AverageCost)
+	assert.Equal(t, []RegionCount{{Region: "SP", Quotes: 2}, {Region: "RJ", Quotes: 1}, {Region: "US", Quotes: 1}}, stats.TopRegions)
+}
+
+func TestWindow_Snapshot_DropsExpiredSeconds(t *testing.T) {
+	// Arrange
+	window, advance := newTestWindow(time.Minute)
+	window.RecordQuote("SP", "BRL", money.FromMinor(1000))
+	window.RecordError()
+	advance(45 * time.Second)
+	window.RecordQuote("RJ", "BRL", money.FromMinor(3000))
+	advance(30 * time.Second)
+
+	// Act
+	stats := window.Snapshot()
+
+	// Assert
+	assert.Equal(t, statsStart.Add(15*time.Second), stats.From)
+	assert.Equal(t, int64(1), stats.Quotes)
+	assert.Equal(t, int64(0), stats.Errors)
+	assert.InDelta(t, 1.0, stats.QuotesPerMinute, 1e-9)
+	assert.Equal(t, []RegionCount{{Region: "RJ", Quotes: 1}}, stats.TopRegions)
+}
+
+func TestWindow_Snapshot_ReusesBuckets(t *testing.T) {
+	// Arrange
+	window, advance := newTestWindow(10 * time.Second)
+	window.RecordQuote("SP", "BRL", money.FromMinor(1000))
+	advance(10 * time.Second)
+	window.RecordQuote("RJ", "BRL", money.FromMinor(3000))
+
+	// Act
+	stats := window.Snapshot()
+
+	// Assert
+	assert.Equal(t, int64(1), stats.Quotes)
+	assert.Equal(t, []CurrencyCost{{Currency: "BRL", Quotes: 1, Average: money.FromMinor(3000)}}, stats.AverageCost)
+	assert.Equal(t, []RegionCount{{Region: "RJ", Quotes: 1}}, stats.TopRegions)
+}
+
+func TestWindow_Snapshot_TopRegions(t *testing.T) {
+	// Arrange
+	window, _ := newTestWindow(time.Minute)
+	for i, region := range []string{"SP", "RJ", "MG", "PR", "RS", "BA"} {
+		for range 6 - i {
+			window.RecordQuote(region, "BRL", money.FromMinor(1000))
+		}
+	}
+
+	// Act
+	stats := window.Snapshot()
+
+	// Assert
+	assert.Equal(t, []RegionCount{
+		{Region: "SP", Quotes: 6},
+		{Region: "RJ", Quotes: 5},
+		{Region: "MG", Quotes: 4},
+		{Region: "PR", Quotes: 3},
+		{Region: "RS", Quotes: 2},
+	}, stats.TopRegions)
+}
+
+func TestWindow_Snapshot_Empty(t *testing.T) {
+	// Arrange
+	window, _ := newTestWindow(time.Minute)
+
+	// Act
+	stats := window.Snapshot()
+
+	// Assert
+	assert.Equal(t, Stats{From: statsStart, To: statsStart, AverageCost: []CurrencyCost{}, TopRegions: []RegionCount{}}, stats)
+}
+
+func TestWindow_ConcurrentRecords(t *testing.T) {
+	// Arrange
+	window := NewWindow(time.Minute)
+	var wg sync.WaitGroup
+
+	// Act
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				window.RecordQuote("SP", "BRL", money.FromMinor(1000))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Equal(t, int64(1000), window.Snapshot().Quotes)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  string
+		want    Config
+		wantErr string
+	}{
+		{"default", "", Config{Window: 5 * time.Minute}, ""},
+		{"custom", "15m", Config{Window: 15 * time.Minute}, ""},
+		{"too short", "500ms", Config{}, "QUOTE_STATS_WINDOW must be between 1s and 1h0m0s"},
+		{"too long", "2h", Config{}, "QUOTE_STATS_WINDOW must be between 1s and 1h0m0s"},
+		{"invalid", "often", Config{}, "QUOTE_STATS_WINDOW"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("QUOTE_STATS_WINDOW", tt.window)
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Package reconciliation compares booked shipment costs with what carriers actually invoice.
+package reconciliation
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Invoice CSV columns; shipment_id and amount are required
+const (
+	columnShipmentID    = "shipment_id"
+	columnAmount        = "amount"
+	columnCarrier       = "carrier"
+	columnInvoiceNumber = "invoice_number"
+)
+
+// InvoiceLine is a single charge of a carrier invoice
+type InvoiceLine struct {
+	InvoiceNumber string
+	Carrier       string
+	// ShipmentID is the quote ID returned by POST /calculate when the shipment was booked
+	ShipmentID string
+	// AmountCents is the invoiced amount in cents (BRL)
+	AmountCents float64
+	// Line is the 1-based line in the source file, used in error messages
+	Line int
+}
+
+// ParseInvoiceCSV reads carrier invoice lines from a CSV file with a header row.
+// Amounts are in reais and accept either "12.50" or "12,50"
+func ParseInvoiceCSV(r io.Reader) ([]InvoiceLine, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("invoice file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read invoice header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{columnShipmentID, columnAmount} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("invoice header must contain column %q", required)
+		}
+	}
+
+	var lines []InvoiceLine
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		shipmentID := field(record, columns, columnShipmentID)
+		if shipmentID == "" {
+			return nil, fmt.Errorf("line %d: %s is required", line, columnShipmentID)
+		}
+		amount, err := parseAmountCents(field(record, columns, columnAmount))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		lines = append(lines, InvoiceLine{
+			InvoiceNumber: field(record, columns, columnInvoiceNumber),
+			Carrier:       field(record, columns, columnCarrier),
+			ShipmentID:    shipmentID,
+			AmountCents:   amount,
+			Line:          line,
+		})
+	}
+	return lines, nil
+}
+
+// field returns the trimmed value of the named column, or "" when the column is absent
+func field(record []string, columns map[string]int, name string) string {
+	i, ok := columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// parseAmountCents converts an amount in reais to cents
+func parseAmountCents(value string) (float64, error) {
+	if value == "" {
+		return 0, fmt.Errorf("%s is required", columnAmount)
+	}
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("invalid %s %q", columnAmount, value)
+	}
+	if amount < 0 {
+		return 0, fmt.Errorf("%s must not be negative", columnAmount)
+	}
+	return math.Round(amount * 100), nil
+}
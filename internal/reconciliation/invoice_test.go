@@ -0,0 +1,64 @@
+package reconciliation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInvoiceCSV(t *testing.T) {
+	// Arrange
+	input := "invoice_number,carrier,shipment_id,amount\n" +
+		"NF-1,acme,quote-1,12.50\n" +
+		"NF-1,acme,quote-2,\"7,35\"\n"
+
+	// Act
+	lines, err := ParseInvoiceCSV(strings.NewReader(input))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []InvoiceLine{
+		{InvoiceNumber: "NF-1", Carrier: "acme", ShipmentID: "quote-1", AmountCents: 1250, Line: 2},
+		{InvoiceNumber: "NF-1", Carrier: "acme", ShipmentID: "quote-2", AmountCents: 735, Line: 3},
+	}, lines)
+}
+
+func TestParseInvoiceCSV_OptionalColumns(t *testing.T) {
+	// Act
+	lines, err := ParseInvoiceCSV(strings.NewReader("Shipment_ID, Amount\nquote-1, 10\n"))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, lines, 1)
+	assert.Equal(t, "quote-1", lines[0].ShipmentID)
+	assert.Equal(t, 1000.0, lines[0].AmountCents)
+	assert.Empty(t, lines[0].Carrier)
+}
+
+func TestParseInvoiceCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"empty file", "", "empty"},
+		{"missing amount column", "shipment_id\nquote-1\n", `column "amount"`},
+		{"missing shipment id", "shipment_id,amount\n,10\n", "line 2: shipment_id is required"},
+		{"missing amount", "shipment_id,amount\nquote-1,\n", "line 2: amount is required"},
+		{"invalid amount", "shipment_id,amount\nquote-1,abc\n", `line 2: invalid amount "abc"`},
+		{"negative amount", "shipment_id,amount\nquote-1,-1\n", "line 2: amount must not be negative"},
+		{"wrong field count", "shipment_id,amount\nquote-1,1,2\n", "line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			lines, err := ParseInvoiceCSV(strings.NewReader(tt.input))
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+			assert.Nil(t, lines)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package reconciliation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"go.uber.org/zap"
+)
+
+// processedSuffix is appended to invoice files once they have been reconciled
+const processedSuffix = ".processed"
+
+// JobConfig configures the background invoice import
+type JobConfig struct {
+	// InboxDir is scanned for *.csv invoice files; the job is disabled when empty
+	InboxDir string
+	Interval time.Duration
+}
+
+// JobConfigFromEnv reads RECONCILIATION_INBOX_DIR and RECONCILIATION_INTERVAL (default 1h)
+func JobConfigFromEnv() (JobConfig, error) {
+	interval, err := config.Duration("RECONCILIATION_INTERVAL", time.Hour)
+	if err != nil {
+		return JobConfig{}, err
+	}
+	if interval <= 0 {
+		return JobConfig{}, fmt.Errorf("RECONCILIATION_INTERVAL must be positive")
+	}
+	return JobConfig{
+		InboxDir: config.String("RECONCILIATION_INBOX_DIR", ""),
+		Interval: interval,
+	}, nil
+}
+
+// Job periodically imports carrier invoices from a directory and reconciles them
+type Job struct {
+	reconciler *Reconciler
+	cfg        JobConfig
+	logger     *zap.Logger
+}
+
+// NewJob creates a reconciliation job
+func NewJob(reconciler *Reconciler, cfg JobConfig, logger *zap.Logger) *Job {
+	return &Job{
+		reconciler: reconciler,
+		cfg:        cfg,
+		logger:     logger,
+	}
+}
+
+// Run processes the inbox immediately and then on every interval until ctx is cancelled
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.ProcessInbox(ctx); err != nil {
+			j.logger.Error("Failed to process invoice inbox", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessInbox reconciles every pending invoice file and renames it with the ".processed" suffix.
+// Invalid files are logged and left in place so they can be fixed and retried
+func (j *Job) ProcessInbox(ctx context.Context) error {
+	files, err := filepath.Glob(filepath.Join(j.cfg.InboxDir, "*.csv"))
+	if err != nil {
+		return fmt.Errorf("failed to list invoice files: %w", err)
+	}
+	sort.Strings(files)
+
+	for _, path := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		result, err := j.processFile(ctx, path)
+		if err != nil {
+			j.logger.Error("Failed to reconcile invoice", zap.String("file", path), zap.Error(err))
+			continue
+		}
+		if err := os.Rename(path, path+processedSuffix); err != nil {
+			return fmt.Errorf("failed to mark invoice %s as processed: %w", path, err)
+		}
+
+		j.logger.Info("Invoice reconciled",
+			zap.String("file", path),
+			zap.Int("lines", result.Lines),
+			zap.Int("matched", result.Matched),
+			zap.Int("discrepancies", result.Discrepancies),
+		)
+	}
+	return nil
+}
+
+func (j *Job) processFile(ctx context.Context, path string) (Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer f.Close()
+
+	lines, err := ParseInvoiceCSV(f)
+	if err != nil {
+		return Result{}, err
+	}
+	return j.reconciler.Reconcile(ctx, lines)
+}
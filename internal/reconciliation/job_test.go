@@ -0,0 +1,117 @@
+package reconciliation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestProcessInbox(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "a.csv")
+	invalid := filepath.Join(dir, "b.csv")
+	assert.NoError(t, os.WriteFile(valid, []byte("shipment_id,amount\nquote-1,10\nquote-2,20\n"), 0o600))
+	assert.NoError(t, os.WriteFile(invalid, []byte("shipment_id\nquote-1\n"), 0o600))
+
+	reconciler := NewReconciler(staticBookings(map[string]float64{"quote-1": 1000}), DefaultConfig())
+	job := NewJob(reconciler, JobConfig{InboxDir: dir, Interval: time.Hour}, zaptest.NewLogger(t))
+
+	// Act
+	err := job.ProcessInbox(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.FileExists(t, valid+processedSuffix)
+	assert.NoFileExists(t, valid)
+	assert.FileExists(t, invalid, "invalid files are left in place for retry")
+
+	discrepancies := reconciler.Discrepancies()
+	assert.Len(t, discrepancies, 1)
+	assert.Equal(t, "quote-2", discrepancies[0].ShipmentID)
+	assert.Equal(t, KindUnmatched, discrepancies[0].Kind)
+}
+
+func TestProcessInbox_SkipsProcessedFiles(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.csv"+processedSuffix), []byte("shipment_id,amount\nquote-1,10\n"), 0o600))
+	reconciler := NewReconciler(staticBookings(nil), DefaultConfig())
+	job := NewJob(reconciler, JobConfig{InboxDir: dir, Interval: time.Hour}, zaptest.NewLogger(t))
+
+	// Act
+	err := job.ProcessInbox(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, reconciler.Discrepancies())
+}
+
+func TestRun_StopsOnCancel(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.csv"), []byte("shipment_id,amount\nquote-1,10\n"), 0o600))
+	reconciler := NewReconciler(staticBookings(nil), DefaultConfig())
+	job := NewJob(reconciler, JobConfig{InboxDir: dir, Interval: time.Hour}, zaptest.NewLogger(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Act
+	go func() {
+		job.Run(ctx)
+		close(done)
+	}()
+	assert.Eventually(t, func() bool { return len(reconciler.Discrepancies()) == 1 }, time.Second, 10*time.Millisecond)
+	cancel()
+
+	// Assert
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop after cancel")
+	}
+}
+
+func TestJobConfigFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// Arrange
+		t.Setenv("RECONCILIATION_INBOX_DIR", "")
+		t.Setenv("RECONCILIATION_INTERVAL", "")
+
+		// Act
+		cfg, err := JobConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, JobConfig{Interval: time.Hour}, cfg)
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		// Arrange
+		t.Setenv("RECONCILIATION_INBOX_DIR", "/var/invoices")
+		t.Setenv("RECONCILIATION_INTERVAL", "15m")
+
+		// Act
+		cfg, err := JobConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, JobConfig{InboxDir: "/var/invoices", Interval: 15 * time.Minute}, cfg)
+	})
+
+	t.Run("non-positive interval", func(t *testing.T) {
+		// Arrange
+		t.Setenv("RECONCILIATION_INTERVAL", "0s")
+
+		// Act
+		_, err := JobConfigFromEnv()
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,177 @@
+package reconciliation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+)
+
+// ErrBookingNotFound is returned by a BookingSource when the shipment was never booked
+var ErrBookingNotFound = errors.New("booking not found")
+
+// Discrepancy kinds
+const (
+	// KindUnmatched means the invoice references a shipment we have no booking for
+	KindUnmatched = "unmatched"
+	// KindAmountMismatch means the invoiced amount differs from the booked cost beyond tolerance
+	KindAmountMismatch = "amount_mismatch"
+)
+
+// BookingSource looks up the cost booked for a shipment, in cents
+type BookingSource interface {
+	BookedCost(ctx context.Context, shipmentID string) (float64, error)
+}
+
+// QuoteBookings is a BookingSource backed by the persisted quotes
+type QuoteBookings struct {
+	Quotes repository.QuoteRepository
+}
+
+// BookedCost returns the shipping cost of the quote with the given ID
+func (b QuoteBookings) BookedCost(ctx context.Context, shipmentID string) (float64, error) {
+	quote, err := b.Quotes.Get(ctx, shipmentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, ErrBookingNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return quote.Response.ShippingCost.Minor(), nil
+}
+
+// Config holds the reconciliation tolerance. A difference is accepted when it is within
+// either the absolute or the relative tolerance
+type Config struct {
+	ToleranceCents   float64
+	TolerancePercent float64
+}
+
+// DefaultConfig returns a tolerance of 50 cents or 2%
+func DefaultConfig() Config {
+	return Config{
+		ToleranceCents:   50,
+		TolerancePercent: 0.02,
+	}
+}
+
+// ConfigFromEnv reads RECONCILIATION_TOLERANCE_CENTS and RECONCILIATION_TOLERANCE_PERCENT
+// (fraction, e.g. 0.02), falling back to DefaultConfig
+func ConfigFromEnv() (Config, error) {
+	cfg := DefaultConfig()
+
+	var err error
+	if cfg.ToleranceCents, err = config.Float("RECONCILIATION_TOLERANCE_CENTS", cfg.ToleranceCents); err != nil {
+		return Config{}, err
+	}
+	if cfg.TolerancePercent, err = config.Float("RECONCILIATION_TOLERANCE_PERCENT", cfg.TolerancePercent); err != nil {
+		return Config{}, err
+	}
+	if cfg.ToleranceCents < 0 || cfg.TolerancePercent < 0 {
+		return Config{}, errors.New("reconciliation tolerance must not be negative")
+	}
+	return cfg, nil
+}
+
+// Discrepancy is an invoice line that does not match its booking
+type Discrepancy struct {
+	ShipmentID      string    `json:"shipment_id"`
+	InvoiceNumber   string    `json:"invoice_number,omitempty"`
+	Carrier         string    `json:"carrier,omitempty"`
+	Kind            string    `json:"kind"`
+	BookedCents     float64   `json:"booked_cents"`
+	InvoicedCents   float64   `json:"invoiced_cents"`
+	DifferenceCents float64   `json:"difference_cents"`
+	DetectedAt      time.Time `json:"detected_at"`
+}
+
+// Result summarizes a reconciliation run
+type Result struct {
+	Lines         int
+	Matched       int
+	Discrepancies int
+}
+
+// Reconciler matches invoice lines against bookings and keeps the discrepancies found.
+// It is safe for concurrent use
+type Reconciler struct {
+	bookings BookingSource
+	cfg      Config
+	now      func() time.Time
+
+	mu            sync.RWMutex
+	discrepancies []Discrepancy
+}
+
+// NewReconciler creates a reconciler using the given booking source and tolerance
+func NewReconciler(bookings BookingSource, cfg Config) *Reconciler {
+	return &Reconciler{
+		bookings: bookings,
+		cfg:      cfg,
+		now:      time.Now,
+	}
+}
+
+// Reconcile compares every invoice line with its booking and records the discrepancies.
+// Lookup failures other than ErrBookingNotFound abort the run
+func (r *Reconciler) Reconcile(ctx context.Context, lines []InvoiceLine) (Result, error) {
+	result := Result{Lines: len(lines)}
+	var found []Discrepancy
+
+	for _, line := range lines {
+		booked, err := r.bookings.BookedCost(ctx, line.ShipmentID)
+		switch {
+		case errors.Is(err, ErrBookingNotFound):
+			found = append(found, r.discrepancy(line, KindUnmatched, 0))
+			continue
+		case err != nil:
+			return Result{}, fmt.Errorf("failed to look up shipment %s: %w", line.ShipmentID, err)
+		}
+
+		if r.withinTolerance(booked, line.AmountCents) {
+			result.Matched++
+			continue
+		}
+		found = append(found, r.discrepancy(line, KindAmountMismatch, booked))
+	}
+
+	r.mu.Lock()
+	r.discrepancies = append(r.discrepancies, found...)
+	r.mu.Unlock()
+
+	result.Discrepancies = len(found)
+	return result, nil
+}
+
+// Discrepancies returns a copy of all discrepancies found so far
+func (r *Reconciler) Discrepancies() []Discrepancy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Discrepancy, len(r.discrepancies))
+	copy(out, r.discrepancies)
+	return out
+}
+
+// withinTolerance reports whether the invoiced amount is close enough to the booked cost
+func (r *Reconciler) withinTolerance(booked, invoiced float64) bool {
+	diff := math.Abs(invoiced - booked)
+	return diff <= r.cfg.ToleranceCents || diff <= booked*r.cfg.TolerancePercent
+}
+
+func (r *Reconciler) discrepancy(line InvoiceLine, kind string, booked float64) Discrepancy {
+	return Discrepancy{
+		ShipmentID:      line.ShipmentID,
+		InvoiceNumber:   line.InvoiceNumber,
+		Carrier:         line.Carrier,
+		Kind:            kind,
+		BookedCents:     booked,
+		InvoicedCents:   line.AmountCents,
+		DifferenceCents: line.AmountCents - booked,
+		DetectedAt:      r.now().UTC(),
+	}
+}
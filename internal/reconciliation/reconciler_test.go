@@ -0,0 +1,189 @@
+package reconciliation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// bookingsFunc adapts a function to BookingSource
+type bookingsFunc func(ctx context.Context, shipmentID string) (float64, error)
+
+func (f bookingsFunc) BookedCost(ctx context.Context, shipmentID string) (float64, error) {
+	return f(ctx, shipmentID)
+}
+
+func staticBookings(costs map[string]float64) BookingSource {
+	return bookingsFunc(func(ctx context.Context, shipmentID string) (float64, error) {
+		cost, ok := costs[shipmentID]
+		if !ok {
+			return 0, ErrBookingNotFound
+		}
+		return cost, nil
+	})
+}
+
+func TestReconcile(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	reconciler := NewReconciler(staticBookings(map[string]float64{
+		"quote-1": 1000,
+		"quote-2": 1000,
+		"quote-3": 1000,
+	}), DefaultConfig())
+	reconciler.now = func() time.Time { return now }
+
+	lines := []InvoiceLine{
+		{ShipmentID: "quote-1", AmountCents: 1040, InvoiceNumber: "NF-1"},
+		{ShipmentID: "quote-2", AmountCents: 1100, InvoiceNumber: "NF-1", Carrier: "acme"},
+		{ShipmentID: "quote-3", AmountCents: 950},
+		{ShipmentID: "quote-x", AmountCents: 500},
+	}
+
+	// Act
+	result, err := reconciler.Reconcile(context.Background(), lines)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Lines: 4, Matched: 2, Discrepancies: 2}, result)
+	assert.Equal(t, []Discrepancy{
+		{
+			ShipmentID:      "quote-2",
+			InvoiceNumber:   "NF-1",
+			Carrier:         "acme",
+			Kind:            KindAmountMismatch,
+			BookedCents:     1000,
+			InvoicedCents:   1100,
+			DifferenceCents: 100,
+			DetectedAt:      now,
+		},
+		{
+			ShipmentID:      "quote-x",
+			Kind:            KindUnmatched,
+			InvoicedCents:   500,
+			DifferenceCents: 500,
+			DetectedAt:      now,
+		},
+	}, reconciler.Discrepancies())
+}
+
+func TestReconcile_TolerancePercent(t *testing.T) {
+	// Arrange
+	reconciler := NewReconciler(staticBookings(map[string]float64{"quote-1": 10000}),
+		Config{ToleranceCents: 0, TolerancePercent: 0.02})
+
+	// Act
+	result, err := reconciler.Reconcile(context.Background(), []InvoiceLine{
+		{ShipmentID: "quote-1", AmountCents: 10200},
+		{ShipmentID: "quote-1", AmountCents: 10201},
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Matched)
+	assert.Equal(t, 1, result.Discrepancies)
+}
+
+func TestReconcile_LookupError(t *testing.T) {
+	// Arrange
+	lookupErr := errors.New("database unavailable")
+	reconciler := NewReconciler(bookingsFunc(func(ctx context.Context, shipmentID string) (float64, error) {
+		return 0, lookupErr
+	}), DefaultConfig())
+
+	// Act
+	_, err := reconciler.Reconcile(context.Background(), []InvoiceLine{{ShipmentID: "quote-1", AmountCents: 100}})
+
+	// Assert
+	assert.ErrorIs(t, err, lookupErr)
+	assert.Empty(t, reconciler.Discrepancies())
+}
+
+func TestDiscrepancies_ReturnsCopy(t *testing.T) {
+	// Arrange
+	reconciler := NewReconciler(staticBookings(nil), DefaultConfig())
+	_, _ = reconciler.Reconcile(context.Background(), []InvoiceLine{{ShipmentID: "quote-1"}})
+
+	// Act
+	discrepancies := reconciler.Discrepancies()
+	discrepancies[0].ShipmentID = "changed"
+
+	// Assert
+	assert.Equal(t, "quote-1", reconciler.Discrepancies()[0].ShipmentID)
+}
+
+func TestQuoteBookings(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	quotes := repository.NewMemoryQuoteRepository()
+	_ = quotes.Save(ctx, &repository.Quote{
+		ID:       "quote-1",
+		Response: model.CalculateShippingResponse{ShippingCost: money.FromMinor(1250)},
+	})
+	bookings := QuoteBookings{Quotes: quotes}
+
+	// Act
+	cost, err := bookings.BookedCost(ctx, "quote-1")
+	_, missingErr := bookings.BookedCost(ctx, "missing")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1250.0, cost)
+	assert.ErrorIs(t, missingErr, ErrBookingNotFound)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// Arrange
+		t.Setenv("RECONCILIATION_TOLERANCE_CENTS", "")
+		t.Setenv("RECONCILIATION_TOLERANCE_PERCENT", "")
+
+		// Act
+		cfg, err := ConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultConfig(), cfg)
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		// Arrange
+		t.Setenv("RECONCILIATION_TOLERANCE_CENTS", "10")
+		t.Setenv("RECONCILIATION_TOLERANCE_PERCENT", "0.05")
+
+		// Act
+		cfg, err := ConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Config{ToleranceCents: 10, TolerancePercent: 0.05}, cfg)
+	})
+
+	t.Run("negative tolerance", func(t *testing.T) {
+		// Arrange
+		t.Setenv("RECONCILIATION_TOLERANCE_CENTS", "-1")
+
+		// Act
+		_, err := ConfigFromEnv()
+
+		// Assert
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		// Arrange
+		t.Setenv("RECONCILIATION_TOLERANCE_PERCENT", "abc")
+
+		// Act
+		_, err := ConfigFromEnv()
+
+		// Assert
+		assert.ErrorContains(t, err, "RECONCILIATION_TOLERANCE_PERCENT")
+	})
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/secrets"
+)
+
+// EncryptedQuoteRepository transparently encrypts the sensitive fields of quotes with
+// AES-GCM before delegating to the underlying repository, and decrypts them on read.
+// The quote ID is authenticated with the ciphertext so encrypted data cannot be moved between records
+type EncryptedQuoteRepository struct {
+	next    QuoteRepository
+	keyring *secrets.Keyring
+}
+
+// NewEncryptedQuoteRepository wraps a repository with field-level encryption
+func NewEncryptedQuoteRepository(next QuoteRepository, keyring *secrets.Keyring) *EncryptedQuoteRepository {
+	return &EncryptedQuoteRepository{next: next, keyring: keyring}
+}
+
+// Save encrypts the sensitive data and stores the quote without the clear-text fields
+func (r *EncryptedQuoteRepository) Save(ctx context.Context, quote *Quote) error {
+	stored := copyQuote(quote)
+	if quote.Sensitive != nil {
+		plaintext, err := json.Marshal(quote.Sensitive)
+		if err != nil {
+			return fmt.Errorf("failed to encode sensitive data: %w", err)
+		}
+		ciphertext, err := r.keyring.Encrypt(plaintext, []byte(quote.ID))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt sensitive data: %w", err)
+		}
+		stored.Sensitive = nil
+		stored.EncryptedSensitive = ciphertext
+	}
+	return r.next.Save(ctx, &stored)
+}
+
+// Get loads the quote and decrypts its sensitive data
+func (r *EncryptedQuoteRepository) Get(ctx context.Context, id string) (*Quote, error) {
+	quote, err := r.next.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if quote.EncryptedSensitive == "" {
+		return quote, nil
+	}
+
+	plaintext, err := r.keyring.Decrypt(quote.EncryptedSensitive, []byte(quote.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt sensitive data of quote %s: %w", id, err)
+	}
+	var sensitive SensitiveData
+	if err := json.Unmarshal(plaintext, &sensitive); err != nil {
+		return nil, fmt.Errorf("failed to decode sensitive data of quote %s: %w", id, err)
+	}
+	quote.Sensitive = &sensitive
+	quote.EncryptedSensitive = ""
+	return quote, nil
+}
+
+// EncryptedWebhookRepository encrypts the secrets of the webhook subscriptions with AES-GCM before
+// delegating to the underlying repository, and decrypts them on read. The subscription ID is
+// authenticated with the ciphertext. Subscriptions stored before the encryption are read as stored
+type EncryptedWebhookRepository struct {
+	WebhookRepository
+	keyring *secrets.Keyring
+}
+
+// NewEncryptedWebhookRepository wraps a repository with the encryption of the subscription secrets
+func NewEncryptedWebhookRepository(next WebhookRepository, keyring *secrets.Keyring) *EncryptedWebhookRepository {
+	return &EncryptedWebhookRepository{WebhookRepository: next, keyring: keyring}
+}
+
+// Save encrypts the secret and stores the subscription without the clear-text secret
+func (r *EncryptedWebhookRepository) Save(ctx context.Context, subscription *model.WebhookSubscription) error {
+	stored := *subscription
+	if subscription.Secret != "" {
+		ciphertext, err := r.keyring.Encrypt([]byte(subscription.Secret), []byte(subscription.ID))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+		}
+		stored.Secret = ""
+		stored.EncryptedSecret = ciphertext
+	}
+	return r.WebhookRepository.Save(ctx, &stored)
+}
+
+// ListByTenant loads the subscriptions of the tenant and decrypts their secrets
+func (r *EncryptedWebhookRepository) ListByTenant(ctx context.Context, tenantID string) ([]model.WebhookSubscription, error) {
+	subscriptions, err := r.WebhookRepository.ListByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		if subscriptions[i].EncryptedSecret == "" {
+			continue
+		}
+		plaintext, err := r.keyring.Decrypt(subscriptions[i].EncryptedSecret, []byte(subscriptions[i].ID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt secret of webhook subscription %s: %w", subscriptions[i].ID, err)
+		}
+		subscriptions[i].Secret = string(plaintext)
+		subscriptions[i].EncryptedSecret = ""
+	}
+	return subscriptions, nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/secrets"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestKeyring(t *testing.T, keys ...secrets.Key) *secrets.Keyring {
+	t.Helper()
+	if len(keys) == 0 {
+		keys = []secrets.Key{{ID: "k1", Material: bytes.Repeat([]byte{1}, 32)}}
+	}
+	keyring, err := secrets.NewKeyring(context.Background(), secrets.StaticProvider(keys))
+	assert.NoError(t, err)
+	return keyring
+}
+
+func TestEncryptedQuoteRepository_StoresCiphertextOnly(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	inner := NewMemoryQuoteRepository()
+	repo := NewEncryptedQuoteRepository(inner, newTestKeyring(t))
+
+	// Act
+	err := repo.Save(ctx, newTestQuote("q1"))
+	stored, _ := inner.Get(ctx, "q1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, stored.Sensitive)
+	assert.NotEmpty(t, stored.EncryptedSensitive)
+	assert.NotContains(t, stored.EncryptedSensitive, "Paulista")
+	assert.Equal(t, "01310100", stored.Request.OriginZipcode)
+}
+
+func TestEncryptedQuoteRepository_RoundTrip(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewEncryptedQuoteRepository(NewMemoryQuoteRepository(), newTestKeyring(t))
+	quote := newTestQuote("q1")
+
+	// Act
+	_ = repo.Save(ctx, quote)
+	result, err := repo.Get(ctx, "q1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, quote, result)
+}
+
+func TestEncryptedQuoteRepository_DoesNotMutateInput(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewEncryptedQuoteRepository(NewMemoryQuoteRepository(), newTestKeyring(t))
+	quote := newTestQuote("q1")
+
+	// Act
+	_ = repo.Save(ctx, quote)
+
+	// Assert
+	assert.NotNil(t, quote.Sensitive)
+	assert.Empty(t, quote.EncryptedSensitive)
+}
+
+func TestEncryptedQuoteRepository_WithoutSensitiveData(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewEncryptedQuoteRepository(NewMemoryQuoteRepository(), newTestKeyring(t))
+	quote := newTestQuote("q1")
+	quote.Sensitive = nil
+
+	// Act
+	_ = repo.Save(ctx, quote)
+	result, err := repo.Get(ctx, "q1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, result.Sensitive)
+}
+
+func TestEncryptedQuoteRepository_ReadsAfterKeyRotation(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	inner := NewMemoryQuoteRepository()
+	oldKey := secrets.Key{ID: "k1", Material: bytes.Repeat([]byte{1}, 32)}
+	newKey := secrets.Key{ID: "k2", Material: bytes.Repeat([]byte{2}, 32)}
+	_ = NewEncryptedQuoteRepository(inner, newTestKeyring(t, oldKey)).Save(ctx, newTestQuote("q1"))
+	rotated := NewEncryptedQuoteRepository(inner, newTestKeyring(t, newKey, oldKey))
+
+	// Act
+	result, err := rotated.Get(ctx, "q1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Av. Paulista, 1000", result.Sensitive.OriginAddress)
+}
+
+func TestEncryptedQuoteRepository_RejectsMovedCiphertext(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	inner := NewMemoryQuoteRepository()
+	repo := NewEncryptedQuoteRepository(inner, newTestKeyring(t))
+	_ = repo.Save(ctx, newTestQuote("q1"))
+	stored, _ := inner.Get(ctx, "q1")
+	stored.ID = "q2"
+	_ = inner.Save(ctx, stored)
+
+	// Act
+	result, err := repo.Get(ctx, "q2")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestEncryptedQuoteRepository_Get_NotFound(t *testing.T) {
+	// Arrange
+	repo := NewEncryptedQuoteRepository(NewMemoryQuoteRepository(), newTestKeyring(t))
+
+	// Act
+	_, err := repo.Get(context.Background(), "missing")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestEncryptedWebhookRepository_RoundTrip(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	inner := NewMemoryWebhookRepository()
+	repo := NewEncryptedWebhookRepository(inner, newTestKeyring(t))
+	subscription := &model.WebhookSubscription{ID: "w1", Tenant: "acme", URL: "https://acme.example.com/hooks", Events: []string{"tracking.updated"}, Secret: "0123456789abcdef"}
+
+	// Act
+	err := repo.Save(ctx, subscription)
+	stored, _ := inner.ListByTenant(ctx, "acme")
+	result, listErr := repo.ListByTenant(ctx, "acme")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "0123456789abcdef", subscription.Secret, "the input is not mutated")
+	assert.Empty(t, stored[0].Secret)
+	assert.NotEmpty(t, stored[0].EncryptedSecret)
+	assert.NoError(t, listErr)
+	assert.Equal(t, []model.WebhookSubscription{*subscription}, result)
+}
+
+func TestEncryptedWebhookRepository_ReadsClearTextSecrets(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	inner := NewMemoryWebhookRepository()
+	_ = inner.Save(ctx, &model.WebhookSubscription{ID: "w1", Tenant: "acme", Secret: "0123456789abcdef"})
+	repo := NewEncryptedWebhookRepository(inner, newTestKeyring(t))
+
+	// Act
+	result, err := repo.ListByTenant(ctx, "acme")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "0123456789abcdef", result[0].Secret)
+}
+
+func TestEncryptedWebhookRepository_RejectsMovedCiphertext(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	inner := NewMemoryWebhookRepository()
+	repo := NewEncryptedWebhookRepository(inner, newTestKeyring(t))
+	_ = repo.Save(ctx, &model.WebhookSubscription{ID: "w1", Tenant: "acme", Secret: "0123456789abcdef"})
+	stored, _ := inner.ListByTenant(ctx, "acme")
+	stored[0].ID = "w2"
+	_ = inner.Save(ctx, &stored[0])
+
+	// Act
+	_, err := repo.ListByTenant(ctx, "acme")
+
+	// Assert
+	assert.Error(t, err)
+}
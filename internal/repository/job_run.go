@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// JobRunRepository records the runs of the scheduled jobs, so that each activation of a job runs
+// on a single instance
+type JobRunRepository interface {
+	// Claim records the run of job scheduled at scheduledAt, reporting false when it was already
+	// claimed, e.g. by another instance
+	Claim(ctx context.Context, job string, scheduledAt time.Time) (bool, error)
+}
+
+// jobRunKey identifies an activation of a job
+type jobRunKey struct {
+	job         string
+	scheduledAt time.Time
+}
+
+// MemoryJobRunRepository is an in-memory JobRunRepository, safe for concurrent use. Runs are
+// recorded per instance, so every instance runs every activation
+type MemoryJobRunRepository struct {
+	mu   sync.Mutex
+	runs map[jobRunKey]bool
+}
+
+// NewMemoryJobRunRepository creates an empty in-memory job run repository
+func NewMemoryJobRunRepository() *MemoryJobRunRepository {
+	return &MemoryJobRunRepository{runs: make(map[jobRunKey]bool)}
+}
+
+// Claim records the run of job scheduled at scheduledAt
+func (r *MemoryJobRunRepository) Claim(ctx context.Context, job string, scheduledAt time.Time) (bool, error) {
+	key := jobRunKey{job: job, scheduledAt: scheduledAt.UTC()}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.runs[key] {
+		return false, nil
+	}
+	r.runs[key] = true
+	return true, nil
+}
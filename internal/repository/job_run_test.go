@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryJobRunRepository_Claim(t *testing.T) {
+	// Arrange
+	repo := NewMemoryJobRunRepository()
+	ctx := context.Background()
+	scheduledAt := time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC)
+
+	// Act
+	first, firstErr := repo.Claim(ctx, "repricing", scheduledAt)
+	again, againErr := repo.Claim(ctx, "repricing", scheduledAt.In(time.FixedZone("BRT", -3*3600)))
+	next, nextErr := repo.Claim(ctx, "repricing", scheduledAt.Add(24*time.Hour))
+	other, otherErr := repo.Claim(ctx, "other", scheduledAt)
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.NoError(t, againErr)
+	assert.NoError(t, nextErr)
+	assert.NoError(t, otherErr)
+	assert.True(t, first)
+	assert.False(t, again, "an activation is claimed once")
+	assert.True(t, next)
+	assert.True(t, other)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+)
+
+// LabelRepository defines the contract for the persistence of shipping labels and their
+// documents. A shipment has at most one label per format: saving a second one fails with
+// ErrAlreadyExists
+type LabelRepository interface {
+	Save(ctx context.Context, label *model.ShipmentLabel, content []byte) error
+	// Get returns the label of the shipment in the format and its document, or ErrNotFound
+	Get(ctx context.Context, shipmentID, format string) (*model.ShipmentLabel, []byte, error)
+}
+
+// labelKey identifies the label of a shipment in a format
+type labelKey struct {
+	shipmentID string
+	format     string
+}
+
+// storedLabel is a label with its document
+type storedLabel struct {
+	label   model.ShipmentLabel
+	content []byte
+}
+
+// MemoryLabelRepository is an in-memory LabelRepository, safe for concurrent use
+type MemoryLabelRepository struct {
+	mu     sync.RWMutex
+	labels map[labelKey]storedLabel
+}
+
+// NewMemoryLabelRepository creates an empty in-memory label repository
+func NewMemoryLabelRepository() *MemoryLabelRepository {
+	return &MemoryLabelRepository{labels: make(map[labelKey]storedLabel)}
+}
+
+// Save stores a copy of the label and its document
+func (r *MemoryLabelRepository) Save(ctx context.Context, label *model.ShipmentLabel, content []byte) error {
+	key := labelKey{shipmentID: label.ShipmentID, format: label.Format}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.labels[key]; ok {
+		return ErrAlreadyExists
+	}
+	r.labels[key] = storedLabel{label: *label, content: append([]byte{}, content...)}
+	return nil
+}
+
+// Get returns copies of the label of the shipment in the format and its document
+func (r *MemoryLabelRepository) Get(ctx context.Context, shipmentID, format string) (*model.ShipmentLabel, []byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	stored, ok := r.labels[labelKey{shipmentID: shipmentID, format: format}]
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	label := stored.label
+	return &label, append([]byte{}, stored.content...), nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLabelRepository(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryLabelRepository()
+	label := &model.ShipmentLabel{ShipmentID: "s1", Format: model.LabelFormatPDF, Size: 5, CreatedAt: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)}
+	content := []byte("%PDF-")
+
+	// Act
+	saveErr := repo.Save(ctx, label, content)
+	duplicateErr := repo.Save(ctx, label, []byte("%PDF-2"))
+	content[0] = 'X'
+	got, gotContent, getErr := repo.Get(ctx, "s1", model.LabelFormatPDF)
+	_, _, otherFormatErr := repo.Get(ctx, "s1", model.LabelFormatZPL)
+
+	// Assert
+	assert.NoError(t, saveErr)
+	assert.ErrorIs(t, duplicateErr, ErrAlreadyExists)
+	assert.NoError(t, getErr)
+	assert.Equal(t, label, got)
+	assert.Equal(t, []byte("%PDF-"), gotContent, "the stored document is a copy")
+	assert.ErrorIs(t, otherFormatErr, ErrNotFound)
+}
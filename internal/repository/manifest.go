@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+)
+
+// ManifestRepository defines the contract for the persistence of the carrier manifests
+type ManifestRepository interface {
+	// Save stores the manifest, replacing any manifest with the same ID
+	Save(ctx context.Context, manifest *model.Manifest) error
+	Get(ctx context.Context, id string) (*model.Manifest, error)
+	// ListByDate returns the manifests of a booking day (YYYY-MM-DD), ordered by creation
+	ListByDate(ctx context.Context, date string) ([]model.Manifest, error)
+	// LockDate holds, across instances, the lock of the closings of a booking day (YYYY-MM-DD)
+	// until unlock is called, so that a shipment is not listed in two manifests
+	LockDate(ctx context.Context, date string) (unlock func(), err error)
+}
+
+// MemoryManifestRepository is an in-memory ManifestRepository, safe for concurrent use
+type MemoryManifestRepository struct {
+	mu        sync.RWMutex
+	manifests map[string]model.Manifest
+	// closing is the lock of the closings of every day
+	closing sync.Mutex
+}
+
+// NewMemoryManifestRepository creates an empty in-memory manifest repository
+func NewMemoryManifestRepository() *MemoryManifestRepository {
+	return &MemoryManifestRepository{manifests: make(map[string]model.Manifest)}
+}
+
+// Save stores a copy of the manifest
+func (r *MemoryManifestRepository) Save(ctx context.Context, manifest *model.Manifest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.manifests[manifest.ID] = copyManifest(manifest)
+	return nil
+}
+
+// Get returns a copy of the manifest with the given ID
+func (r *MemoryManifestRepository) Get(ctx context.Context, id string) (*model.Manifest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	manifest, ok := r.manifests[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	out := copyManifest(&manifest)
+	return &out, nil
+}
+
+// ListByDate returns copies of the manifests of the day, ordered by creation and then ID
+func (r *MemoryManifestRepository) ListByDate(ctx context.Context, date string) ([]model.Manifest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	manifests := make([]model.Manifest, 0)
+	for _, manifest := range r.manifests {
+		if manifest.Date == date {
+			manifests = append(manifests, copyManifest(&manifest))
+		}
+	}
+	sort.Slice(manifests, func(i, j int) bool {
+		if !manifests[i].CreatedAt.Equal(manifests[j].CreatedAt) {
+			return manifests[i].CreatedAt.Before(manifests[j].CreatedAt)
+		}
+		return manifests[i].ID < manifests[j].ID
+	})
+	return manifests, nil
+}
+
+// LockDate holds the lock of the closings until unlock is called; the days share a single lock, as
+// the memory repository is not shared between instances
+func (r *MemoryManifestRepository) LockDate(ctx context.Context, date string) (func(), error) {
+	r.closing.Lock()
+	return r.closing.Unlock, nil
+}
+
+// copyManifest copies the manifest so callers cannot mutate stored records
+func copyManifest(manifest *model.Manifest) model.Manifest {
+	out := *manifest
+	out.ShipmentIDs = append([]string{}, manifest.ShipmentIDs...)
+	if manifest.SubmittedAt != nil {
+		submittedAt := *manifest.SubmittedAt
+		out.SubmittedAt = &submittedAt
+	}
+	return out
+}
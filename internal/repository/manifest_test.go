@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryManifestRepository(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryManifestRepository()
+	createdAt := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+	first := &model.Manifest{ID: "m2", Carrier: "correios", Date: "2025-03-10", Status: model.ManifestStatusFailed, ShipmentIDs: []string{"s1"}, CreatedAt: createdAt}
+	second := &model.Manifest{ID: "m1", Carrier: "correios", Date: "2025-03-10", Status: model.ManifestStatusSubmitted, ShipmentIDs: []string{"s1", "s2"}, CreatedAt: createdAt.Add(time.Hour)}
+	otherDay := &model.Manifest{ID: "m3", Carrier: "jadlog", Date: "2025-03-11", Status: model.ManifestStatusGenerated, ShipmentIDs: []string{"s3"}, CreatedAt: createdAt}
+
+	// Act
+	for _, manifest := range []*model.Manifest{first, second, otherDay} {
+		assert.NoError(t, repo.Save(ctx, manifest))
+	}
+	second.ShipmentIDs[0] = "changed"
+	got, getErr := repo.Get(ctx, "m1")
+	listed, listErr := repo.ListByDate(ctx, "2025-03-10")
+	empty, emptyErr := repo.ListByDate(ctx, "2025-03-12")
+	_, missingErr := repo.Get(ctx, "missing")
+
+	// Assert
+	assert.NoError(t, getErr)
+	assert.Equal(t, []string{"s1", "s2"}, got.ShipmentIDs, "the stored manifest is a copy")
+	assert.NoError(t, listErr)
+	assert.Len(t, listed, 2)
+	assert.Equal(t, "m2", listed[0].ID, "manifests are listed by creation")
+	assert.Equal(t, "m1", listed[1].ID)
+	assert.NoError(t, emptyErr)
+	assert.Empty(t, empty)
+	assert.ErrorIs(t, missingErr, ErrNotFound)
+}
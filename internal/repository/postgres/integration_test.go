@@ -0,0 +1,408 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// newTestPool starts a PostgreSQL container and returns a migrated pool connected to it. The test
+// is skipped in short mode and when Docker is not available
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping PostgreSQL integration test in short mode")
+	}
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+	container, err := tcpostgres.Run(ctx, "postgres:16-alpine",
+		tcpostgres.WithDatabase("shipping"),
+		tcpostgres.WithUsername("shipping"),
+		tcpostgres.WithPassword("shipping"),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	testcontainers.CleanupContainer(t, container)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	url, err := container.ConnectionString(ctx, "sslmode=disable")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	pool, err := Open(ctx, Config{
+		URL:             url,
+		MaxConns:        4,
+		MaxConnLifetime: time.Hour,
+		MaxConnIdleTime: time.Minute,
+		ConnectTimeout:  10 * time.Second,
+		Migrate:         true,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestPostgres(t *testing.T) {
+	pool := newTestPool(t)
+	ctx := context.Background()
+
+	t.Run("migrations are idempotent", func(t *testing.T) {
+		// Act
+		err := Migrate(ctx, pool)
+		var applied int
+		countErr := pool.QueryRow(ctx, "SELECT count(*) FROM schema_migrations").Scan(&applied)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, countErr)
+		all, _ := loadMigrations(migrationFiles)
+		assert.Equal(t, len(all), applied)
+	})
+
+	t.Run("quotes", func(t *testing.T) {
+		// Arrange
+		repo := NewQuoteRepository(pool)
+		createdAt := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+		quote := &repository.Quote{
+			ID: "q1",
+			Request: model.CalculateShippingRequest{
+				OriginZipcode:      "01310100",
+				DestinationZipcode: "04547130",
+				Weight:             1.5,
+			},
+			Response:       model.CalculateShippingResponse{ShippingCost: money.FromMinor(1250), Currency: "BRL"},
+			CreatedAt:      createdAt,
+			ExpiresAt:      createdAt.Add(30 * time.Minute),
+			PricingVersion: "2025.01",
+			Tenant:         "acme",
+			FuelIndex:      &model.FuelIndex{Rate: 0.12, EffectiveDate: "2025-01-06"},
+			Sensitive:      &repository.SensitiveData{OriginAddress: "Av. Paulista, 1000", DeclaredValue: 350},
+		}
+
+		// Act
+		saveErr := repo.Save(ctx, quote)
+		quote.PricingVersion = "2025.02"
+		updateErr := repo.Save(ctx, quote)
+		got, getErr := repo.Get(ctx, "q1")
+		_, missingErr := repo.Get(ctx, "missing")
+
+		// Assert
+		assert.NoError(t, saveErr)
+		assert.NoError(t, updateErr)
+		assert.NoError(t, getErr)
+		assert.Equal(t, quote, got)
+		assert.ErrorIs(t, missingErr, repository.ErrNotFound)
+	})
+
+	t.Run("shipments", func(t *testing.T) {
+		// Arrange
+		repo := NewShipmentRepository(pool)
+		shipment := &model.Shipment{
+			ID:       "s1",
+			QuoteID:  "q1",
+			Status:   model.ShipmentStatusBooked,
+			Service:  "standard",
+			Cost:     money.FromMinor(1250),
+			Package:  model.ShipmentPackage{OriginZipcode: "01310100", DestinationZipcode: "04547130", AdditionalServices: []string{"insurance"}},
+			BookedAt: time.Date(2025, 1, 10, 12, 5, 0, 0, time.UTC),
+		}
+
+		// Act
+		saveErr := repo.Save(ctx, shipment)
+		got, getErr := repo.Get(ctx, "s1")
+		duplicateErr := repo.Save(ctx, &model.Shipment{ID: "s2", QuoteID: "q1", Status: model.ShipmentStatusBooked})
+		_, missingErr := repo.Get(ctx, "missing")
+		booked, listErr := repo.ListByStatus(ctx, model.ShipmentStatusBooked, "", 10)
+		after, afterErr := repo.ListByStatus(ctx, model.ShipmentStatusBooked, "s1", 10)
+		bookedOnDay, dayErr := repo.ListBookedBetween(ctx, model.ShipmentStatusBooked, time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC), "", 10)
+		bookedOnOtherDay, otherDayErr := repo.ListBookedBetween(ctx, model.ShipmentStatusBooked, time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC), "", 10)
+
+		// Assert
+		assert.NoError(t, saveErr)
+		assert.NoError(t, getErr)
+		assert.Equal(t, shipment, got)
+		assert.ErrorIs(t, duplicateErr, repository.ErrAlreadyExists)
+		assert.ErrorIs(t, missingErr, repository.ErrNotFound)
+		assert.NoError(t, listErr)
+		assert.Equal(t, []model.Shipment{*shipment}, booked)
+		assert.NoError(t, afterErr)
+		assert.Empty(t, after)
+		assert.NoError(t, dayErr)
+		assert.Equal(t, []model.Shipment{*shipment}, bookedOnDay)
+		assert.NoError(t, otherDayErr)
+		assert.Empty(t, bookedOnOtherDay)
+	})
+
+	t.Run("shipment status", func(t *testing.T) {
+		// Arrange
+		repo := NewShipmentRepository(pool)
+		_ = repo.Save(ctx, &model.Shipment{ID: "s-status", QuoteID: "q-status", Status: model.ShipmentStatusBooked})
+
+		// Act
+		updated, updateErr := repo.UpdateStatus(ctx, "s-status", model.ShipmentStatusBooked, model.TrackingStatusInTransit)
+		stale, staleErr := repo.UpdateStatus(ctx, "s-status", model.ShipmentStatusBooked, model.TrackingStatusPosted)
+		_, missingErr := repo.UpdateStatus(ctx, "missing", model.ShipmentStatusBooked, model.TrackingStatusPosted)
+		got, getErr := repo.Get(ctx, "s-status")
+
+		// Assert
+		assert.NoError(t, updateErr)
+		assert.True(t, updated)
+		assert.NoError(t, staleErr)
+		assert.False(t, stale)
+		assert.ErrorIs(t, missingErr, repository.ErrNotFound)
+		assert.NoError(t, getErr)
+		assert.Equal(t, model.TrackingStatusInTransit, got.Status)
+	})
+
+	t.Run("shipment pickup capacity", func(t *testing.T) {
+		// Arrange
+		repo := NewShipmentRepository(pool)
+		pickup := func(id string) *model.Shipment {
+			return &model.Shipment{
+				ID:       id,
+				QuoteID:  "q-" + id,
+				Status:   model.ShipmentStatusBooked,
+				Package:  model.ShipmentPackage{PickupDate: "2025-01-13", PickupWindow: "morning"},
+				BookedAt: time.Date(2025, 1, 10, 12, 5, 0, 0, time.UTC),
+			}
+		}
+
+		// Act
+		firstErr := repo.SaveWithinCapacity(ctx, pickup("pickup-1"), 1)
+		fullErr := repo.SaveWithinCapacity(ctx, pickup("pickup-2"), 1)
+		booked, countErr := repo.CountPickups(ctx, "2025-01-13", "morning")
+
+		// Assert
+		assert.NoError(t, firstErr)
+		assert.ErrorIs(t, fullErr, repository.ErrCapacityExceeded)
+		assert.NoError(t, countErr)
+		assert.Equal(t, 1, booked)
+	})
+
+	t.Run("tracking events", func(t *testing.T) {
+		// Arrange
+		repo := NewTrackingRepository(pool)
+		posted := model.TrackingEvent{Status: "posted", Location: "Sao Paulo/SP", OccurredAt: time.Date(2025, 1, 10, 15, 0, 0, 0, time.UTC)}
+		delivered := model.TrackingEvent{Status: "delivered", Location: "Rio de Janeiro/RJ", OccurredAt: time.Date(2025, 1, 12, 9, 0, 0, 0, time.UTC)}
+
+		// Act
+		first, firstErr := repo.Append(ctx, "s1", []model.TrackingEvent{delivered, posted})
+		second, secondErr := repo.Append(ctx, "s1", []model.TrackingEvent{posted, posted})
+		got, listErr := repo.List(ctx, "s1")
+		empty, emptyErr := repo.List(ctx, "s2")
+
+		// Assert
+		assert.NoError(t, firstErr)
+		assert.NoError(t, secondErr)
+		assert.NoError(t, listErr)
+		assert.NoError(t, emptyErr)
+		assert.Equal(t, 2, first)
+		assert.Equal(t, 0, second)
+		assert.Equal(t, []model.TrackingEvent{posted, delivered}, got)
+		assert.Empty(t, empty)
+	})
+
+	t.Run("labels", func(t *testing.T) {
+		// Arrange
+		repo := NewLabelRepository(pool)
+		label := &model.ShipmentLabel{
+			ShipmentID:     "s1",
+			Format:         model.LabelFormatZPL,
+			CarrierLabelID: "lbl-1",
+			Size:           3,
+			DownloadURL:    "/shipments/s1/label/zpl",
+			CreatedAt:      time.Date(2025, 1, 10, 12, 10, 0, 0, time.UTC),
+		}
+
+		// Act
+		saveErr := repo.Save(ctx, label, []byte("^XA"))
+		duplicateErr := repo.Save(ctx, label, []byte("^XA^XZ"))
+		got, content, getErr := repo.Get(ctx, "s1", model.LabelFormatZPL)
+		_, _, missingErr := repo.Get(ctx, "s1", model.LabelFormatPDF)
+
+		// Assert
+		assert.NoError(t, saveErr)
+		assert.ErrorIs(t, duplicateErr, repository.ErrAlreadyExists)
+		assert.NoError(t, getErr)
+		assert.Equal(t, label, got)
+		assert.Equal(t, []byte("^XA"), content)
+		assert.ErrorIs(t, missingErr, repository.ErrNotFound)
+	})
+
+	t.Run("manifests", func(t *testing.T) {
+		// Arrange
+		repo := NewManifestRepository(pool)
+		submittedAt := time.Date(2025, 1, 10, 18, 5, 0, 0, time.UTC)
+		manifest := &model.Manifest{
+			ID:          "m1",
+			Carrier:     "correios",
+			Date:        "2025-01-10",
+			Status:      model.ManifestStatusFailed,
+			ShipmentIDs: []string{"s1", "s2"},
+			TotalWeight: 3.5,
+			Error:       "manifest: unexpected status 503",
+			CreatedAt:   time.Date(2025, 1, 10, 18, 0, 0, 0, time.UTC),
+		}
+
+		// Act
+		saveErr := repo.Save(ctx, manifest)
+		manifest.Status = model.ManifestStatusSubmitted
+		manifest.Error = ""
+		manifest.CarrierManifestID = "cm-1"
+		manifest.SubmittedAt = &submittedAt
+		updateErr := repo.Save(ctx, manifest)
+		got, getErr := repo.Get(ctx, "m1")
+		listed, listErr := repo.ListByDate(ctx, "2025-01-10")
+		otherDay, otherDayErr := repo.ListByDate(ctx, "2025-01-11")
+		unlock, lockErr := repo.LockDate(ctx, "2025-01-10")
+		if lockErr == nil {
+			unlock()
+		}
+
+		// Assert
+		assert.NoError(t, saveErr)
+		assert.NoError(t, updateErr)
+		assert.NoError(t, getErr)
+		assert.Equal(t, manifest, got)
+		assert.NoError(t, listErr)
+		assert.Equal(t, []model.Manifest{*manifest}, listed)
+		assert.NoError(t, otherDayErr)
+		assert.Empty(t, otherDay)
+		assert.NoError(t, lockErr)
+	})
+
+	t.Run("webhooks", func(t *testing.T) {
+		// Arrange
+		repo := NewWebhookRepository(pool)
+		subscription := &model.WebhookSubscription{
+			ID:        "w1",
+			Tenant:    "acme",
+			URL:       "https://acme.example.com/hooks",
+			Events:    []string{"tracking.updated"},
+			Secret:    "whsec",
+			CreatedAt: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+		}
+		letter := &model.WebhookDeadLetter{
+			ID:             "d1",
+			SubscriptionID: "w1",
+			Tenant:         "acme",
+			URL:            subscription.URL,
+			EventID:        "e1",
+			EventType:      "tracking.updated",
+			Event:          json.RawMessage(`{"id":"e1"}`),
+			Attempts:       5,
+			Error:          "unexpected status 503",
+			FailedAt:       time.Date(2025, 1, 10, 12, 5, 0, 0, time.UTC),
+		}
+
+		// Act
+		saveErr := repo.Save(ctx, subscription)
+		listed, listErr := repo.ListByTenant(ctx, "acme")
+		otherTenantErr := repo.Delete(ctx, "globex", "w1")
+		deleteErr := repo.Delete(ctx, "acme", "w1")
+		afterDelete, _ := repo.ListByTenant(ctx, "acme")
+		addErr := repo.AddDeadLetter(ctx, letter)
+		letters, lettersErr := repo.ListDeadLetters(ctx, "", 10)
+		otherLetters, _ := repo.ListDeadLetters(ctx, "globex", 10)
+
+		// Assert
+		assert.NoError(t, saveErr)
+		assert.NoError(t, listErr)
+		assert.Equal(t, []model.WebhookSubscription{*subscription}, listed)
+		assert.ErrorIs(t, otherTenantErr, repository.ErrNotFound)
+		assert.NoError(t, deleteErr)
+		assert.Empty(t, afterDelete)
+		assert.NoError(t, addErr)
+		assert.NoError(t, lettersErr)
+		assert.Len(t, letters, 1)
+		assert.JSONEq(t, `{"id":"e1"}`, string(letters[0].Event))
+		assert.Equal(t, letter.Error, letters[0].Error)
+		assert.Empty(t, otherLetters)
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		// Arrange
+		repo := NewUsageRepository(pool)
+		day := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+
+		// Act
+		firstErr := repo.Increment(ctx, "acme", "sha256:abc", 1, day)
+		secondErr := repo.Increment(ctx, "acme", "sha256:abc", 1, day.Add(time.Hour))
+		otherErr := repo.Increment(ctx, "globex", "", 1, day.AddDate(0, 0, 1))
+		records, dailyErr := repo.Daily(ctx, "", day, day.AddDate(0, 0, 1))
+		total, totalErr := repo.Total(ctx, "acme", day.AddDate(0, 0, -9), day.AddDate(0, 0, 20))
+
+		// Assert
+		assert.NoError(t, firstErr)
+		assert.NoError(t, secondErr)
+		assert.NoError(t, otherErr)
+		assert.NoError(t, dailyErr)
+		assert.NoError(t, totalErr)
+		assert.Equal(t, []model.UsageRecord{
+			{Day: "2025-03-10", Tenant: "acme", APIKey: "sha256:abc", Quotes: 2},
+			{Day: "2025-03-11", Tenant: "globex", Quotes: 1},
+		}, records)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("job runs", func(t *testing.T) {
+		// Arrange
+		repo := NewJobRunRepository(pool)
+		scheduledAt := time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC)
+
+		// Act
+		first, firstErr := repo.Claim(ctx, "repricing", scheduledAt)
+		again, againErr := repo.Claim(ctx, "repricing", scheduledAt)
+		next, nextErr := repo.Claim(ctx, "repricing", scheduledAt.Add(24*time.Hour))
+
+		// Assert
+		assert.NoError(t, firstErr)
+		assert.NoError(t, againErr)
+		assert.NoError(t, nextErr)
+		assert.True(t, first)
+		assert.False(t, again)
+		assert.True(t, next)
+	})
+
+	t.Run("pricing versions", func(t *testing.T) {
+		// Arrange
+		repo := NewPricingVersionRepository(pool)
+		march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		may := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+		first := repository.PricingVersion{Version: pricing.DefaultConfig().VersionID(), EffectiveFrom: march, Config: pricing.DefaultConfig()}
+		second := repository.PricingVersion{Version: "2025.05", EffectiveFrom: may, Config: pricing.Config{Version: "2025.05", DefaultCountry: "BR"}}
+
+		// Act
+		firstErr := repo.Save(ctx, first)
+		secondErr := repo.Save(ctx, second)
+		repeatedErr := repo.Save(ctx, repository.PricingVersion{Version: "2025.05", EffectiveFrom: may.AddDate(0, 1, 0), Config: second.Config})
+		between, betweenErr := repo.AsOf(ctx, may.Add(-time.Second))
+		latest, latestErr := repo.AsOf(ctx, may.AddDate(1, 0, 0))
+		_, missingErr := repo.AsOf(ctx, march.Add(-time.Second))
+
+		// Assert
+		assert.NoError(t, firstErr)
+		assert.NoError(t, secondErr)
+		assert.NoError(t, repeatedErr)
+		assert.NoError(t, betweenErr)
+		assert.NoError(t, latestErr)
+		assert.Equal(t, first.Version, between.Config.VersionID())
+		assert.Equal(t, first, between)
+		assert.Equal(t, may, latest.EffectiveFrom, "the latest version is not recorded twice")
+		assert.ErrorIs(t, missingErr, repository.ErrNotFound)
+	})
+}
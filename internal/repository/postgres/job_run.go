@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// JobRunRepository is a repository.JobRunRepository backed by the job_runs table, one row per
+// activation of a job, so that the instances run each activation once
+type JobRunRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewJobRunRepository creates a job run repository using the pool
+func NewJobRunRepository(pool *pgxpool.Pool) *JobRunRepository {
+	return &JobRunRepository{pool: pool}
+}
+
+// Claim records the run of job scheduled at scheduledAt, reporting false when another instance
+// already claimed it
+func (r *JobRunRepository) Claim(ctx context.Context, job string, scheduledAt time.Time) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		INSERT INTO job_runs (job, scheduled_at, claimed_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (job, scheduled_at) DO NOTHING`,
+		job, scheduledAt.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to claim run of job %s: %w", job, err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
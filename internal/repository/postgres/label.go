@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+)
+
+// LabelRepository is a repository.LabelRepository backed by the shipment_labels table, keyed by
+// shipment and format
+type LabelRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewLabelRepository creates a label repository using the pool
+func NewLabelRepository(pool *pgxpool.Pool) *LabelRepository {
+	return &LabelRepository{pool: pool}
+}
+
+// Save stores the label and its document. Saving a second label for the shipment in the same
+// format fails with repository.ErrAlreadyExists
+func (r *LabelRepository) Save(ctx context.Context, label *model.ShipmentLabel, content []byte) error {
+	data, err := json.Marshal(label)
+	if err != nil {
+		return fmt.Errorf("failed to encode label of shipment %s: %w", label.ShipmentID, err)
+	}
+
+	tag, err := r.pool.Exec(ctx, `
+		INSERT INTO shipment_labels (shipment_id, format, label, content, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (shipment_id, format) DO NOTHING`,
+		label.ShipmentID, label.Format, data, content, label.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save label of shipment %s: %w", label.ShipmentID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrAlreadyExists
+	}
+	return nil
+}
+
+// Get returns the label of the shipment in the format and its document
+func (r *LabelRepository) Get(ctx context.Context, shipmentID, format string) (*model.ShipmentLabel, []byte, error) {
+	var data, content []byte
+	err := r.pool.QueryRow(ctx, "SELECT label, content FROM shipment_labels WHERE shipment_id = $1 AND format = $2", shipmentID, format).Scan(&data, &content)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load label of shipment %s: %w", shipmentID, err)
+	}
+
+	var label model.ShipmentLabel
+	if err := json.Unmarshal(data, &label); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode label of shipment %s: %w", shipmentID, err)
+	}
+	return &label, content, nil
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+)
+
+// manifestLock is the advisory lock class of the closings of a booking day, the second key being
+// the hash of the day
+const manifestLock int32 = 731_815_010
+
+// ManifestRepository is a repository.ManifestRepository backed by the manifests table
+type ManifestRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewManifestRepository creates a manifest repository using the pool
+func NewManifestRepository(pool *pgxpool.Pool) *ManifestRepository {
+	return &ManifestRepository{pool: pool}
+}
+
+// Save stores the manifest, replacing any manifest with the same ID
+func (r *ManifestRepository) Save(ctx context.Context, manifest *model.Manifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest %s: %w", manifest.ID, err)
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO manifests (id, carrier, date, status, manifest, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			carrier = EXCLUDED.carrier,
+			date = EXCLUDED.date,
+			status = EXCLUDED.status,
+			manifest = EXCLUDED.manifest,
+			created_at = EXCLUDED.created_at`,
+		manifest.ID, manifest.Carrier, manifest.Date, manifest.Status, data, manifest.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save manifest %s: %w", manifest.ID, err)
+	}
+	return nil
+}
+
+// Get returns the manifest with the given ID
+func (r *ManifestRepository) Get(ctx context.Context, id string) (*model.Manifest, error) {
+	var data []byte
+	err := r.pool.QueryRow(ctx, "SELECT manifest FROM manifests WHERE id = $1", id).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manifest %s: %w", id, err)
+	}
+
+	var manifest model.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest %s: %w", id, err)
+	}
+	return &manifest, nil
+}
+
+// ListByDate returns the manifests of the booking day, ordered by creation and then ID
+func (r *ManifestRepository) ListByDate(ctx context.Context, date string) ([]model.Manifest, error) {
+	rows, err := r.pool.Query(ctx, "SELECT manifest FROM manifests WHERE date = $1 ORDER BY created_at, id", date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manifests of %s: %w", date, err)
+	}
+	defer rows.Close()
+
+	manifests := make([]model.Manifest, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read manifest: %w", err)
+		}
+		var manifest model.Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to decode manifest: %w", err)
+		}
+		manifests = append(manifests, manifest)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list manifests of %s: %w", date, err)
+	}
+	return manifests, nil
+}
+
+// LockDate holds a transaction-level advisory lock on the booking day until unlock is called, so
+// that the instances close the day one at a time. The transaction keeps a connection of the pool
+// busy while the lock is held
+func (r *ManifestRepository) LockDate(ctx context.Context, date string) (func(), error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock manifests of %s: %w", date, err)
+	}
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1, hashtext($2))", manifestLock, date); err != nil {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+		return nil, fmt.Errorf("failed to lock manifests of %s: %w", date, err)
+	}
+	return func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }, nil
+}
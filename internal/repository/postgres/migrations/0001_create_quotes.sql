@@ -0,0 +1,12 @@
+CREATE TABLE quotes (
+    id                  TEXT PRIMARY KEY,
+    request             JSONB NOT NULL,
+    response            JSONB NOT NULL,
+    created_at          TIMESTAMPTZ NOT NULL,
+    expires_at          TIMESTAMPTZ,
+    pricing_version     TEXT NOT NULL DEFAULT '',
+    sensitive           JSONB,
+    encrypted_sensitive TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX quotes_created_at_idx ON quotes (created_at);
@@ -0,0 +1,7 @@
+CREATE TABLE shipments (
+    id        TEXT PRIMARY KEY,
+    quote_id  TEXT NOT NULL UNIQUE,
+    status    TEXT NOT NULL,
+    shipment  JSONB NOT NULL,
+    booked_at TIMESTAMPTZ NOT NULL
+);
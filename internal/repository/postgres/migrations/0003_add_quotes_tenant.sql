@@ -0,0 +1 @@
+ALTER TABLE quotes ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
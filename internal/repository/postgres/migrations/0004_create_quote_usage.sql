@@ -0,0 +1,7 @@
+CREATE TABLE quote_usage (
+    day     DATE NOT NULL,
+    tenant  TEXT NOT NULL,
+    api_key TEXT NOT NULL DEFAULT '',
+    quotes  BIGINT NOT NULL,
+    PRIMARY KEY (day, tenant, api_key)
+);
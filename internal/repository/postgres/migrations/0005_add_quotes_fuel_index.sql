@@ -0,0 +1 @@
+ALTER TABLE quotes ADD COLUMN fuel_index JSONB;
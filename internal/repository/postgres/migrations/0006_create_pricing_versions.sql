@@ -0,0 +1,5 @@
+CREATE TABLE pricing_versions (
+    effective_from TIMESTAMPTZ NOT NULL PRIMARY KEY,
+    version        TEXT NOT NULL,
+    config         JSONB NOT NULL
+);
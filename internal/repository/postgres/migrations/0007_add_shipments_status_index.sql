@@ -0,0 +1 @@
+CREATE INDEX shipments_status_id ON shipments (status, id);
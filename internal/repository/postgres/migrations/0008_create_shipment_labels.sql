@@ -0,0 +1,8 @@
+CREATE TABLE shipment_labels (
+    shipment_id TEXT NOT NULL,
+    format      TEXT NOT NULL,
+    label       JSONB NOT NULL,
+    content     BYTEA NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (shipment_id, format)
+);
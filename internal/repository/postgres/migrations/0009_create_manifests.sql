@@ -0,0 +1,10 @@
+CREATE TABLE manifests (
+    id         TEXT PRIMARY KEY,
+    carrier    TEXT NOT NULL,
+    date       DATE NOT NULL,
+    status     TEXT NOT NULL,
+    manifest   JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX manifests_date_idx ON manifests (date, created_at);
@@ -0,0 +1,18 @@
+CREATE TABLE webhook_subscriptions (
+    id           TEXT PRIMARY KEY,
+    tenant       TEXT NOT NULL,
+    subscription JSONB NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX webhook_subscriptions_tenant_idx ON webhook_subscriptions (tenant, created_at);
+
+CREATE TABLE webhook_dead_letters (
+    seq         BIGSERIAL PRIMARY KEY,
+    id          TEXT NOT NULL UNIQUE,
+    tenant      TEXT NOT NULL,
+    dead_letter JSONB NOT NULL,
+    failed_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX webhook_dead_letters_tenant_idx ON webhook_dead_letters (tenant, seq);
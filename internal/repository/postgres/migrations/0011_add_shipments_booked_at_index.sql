@@ -0,0 +1 @@
+CREATE INDEX shipments_status_booked_at ON shipments (status, booked_at, id);
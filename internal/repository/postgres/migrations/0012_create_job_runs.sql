@@ -0,0 +1,6 @@
+CREATE TABLE job_runs (
+    job          TEXT NOT NULL,
+    scheduled_at TIMESTAMPTZ NOT NULL,
+    claimed_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (job, scheduled_at)
+);
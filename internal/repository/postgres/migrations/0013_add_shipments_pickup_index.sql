@@ -0,0 +1 @@
+CREATE INDEX shipments_pickup ON shipments ((shipment->'package'->>'pickup_date'), (shipment->'package'->>'pickup_window'));
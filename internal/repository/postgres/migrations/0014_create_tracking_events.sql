@@ -0,0 +1,9 @@
+CREATE TABLE tracking_events (
+    seq         BIGSERIAL PRIMARY KEY,
+    shipment_id TEXT NOT NULL,
+    status      TEXT NOT NULL,
+    description TEXT NOT NULL,
+    location    TEXT NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (shipment_id, status, occurred_at, location, description)
+);
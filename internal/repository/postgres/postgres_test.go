@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{},
+			want: Config{MaxConns: 10, MaxConnLifetime: time.Hour, MaxConnIdleTime: 30 * time.Minute, ConnectTimeout: 5 * time.Second, Migrate: true},
+		},
+		{
+			name: "custom values",
+			env: map[string]string{
+				"DATABASE_URL":                "postgres://shipping@db:5432/shipping",
+				"POSTGRES_MAX_CONNS":          "20",
+				"POSTGRES_MIN_CONNS":          "2",
+				"POSTGRES_MAX_CONN_LIFETIME":  "15m",
+				"POSTGRES_MAX_CONN_IDLE_TIME": "5m",
+				"POSTGRES_CONNECT_TIMEOUT":    "2s",
+				"POSTGRES_MIGRATE":            "false",
+			},
+			want: Config{
+				URL:             "postgres://shipping@db:5432/shipping",
+				MaxConns:        20,
+				MinConns:        2,
+				MaxConnLifetime: 15 * time.Minute,
+				MaxConnIdleTime: 5 * time.Minute,
+				ConnectTimeout:  2 * time.Second,
+			},
+		},
+		{name: "min over max", env: map[string]string{"POSTGRES_MAX_CONNS": "2", "POSTGRES_MIN_CONNS": "3"}, wantErr: "POSTGRES_MIN_CONNS between 0 and POSTGRES_MAX_CONNS"},
+		{name: "zero max", env: map[string]string{"POSTGRES_MAX_CONNS": "0"}, wantErr: "POSTGRES_MAX_CONNS must be positive"},
+		{name: "invalid timeout", env: map[string]string{"POSTGRES_CONNECT_TIMEOUT": "0s"}, wantErr: "must be positive"},
+		{name: "invalid migrate", env: map[string]string{"POSTGRES_MIGRATE": "sometimes"}, wantErr: "POSTGRES_MIGRATE must be a boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for _, key := range []string{"DATABASE_URL", "POSTGRES_MAX_CONNS", "POSTGRES_MIN_CONNS", "POSTGRES_MAX_CONN_LIFETIME", "POSTGRES_MAX_CONN_IDLE_TIME", "POSTGRES_CONNECT_TIMEOUT", "POSTGRES_MIGRATE"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+			assert.Equal(t, tt.want.URL != "", cfg.Enabled())
+		})
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	// Arrange
+	files := fstest.MapFS{
+		"migrations/0010_add_index.sql":     {Data: []byte("CREATE INDEX ...")},
+		"migrations/0002_create_b.sql":      {Data: []byte("CREATE TABLE b ()")},
+		"migrations/0001_create_a.sql":      {Data: []byte("CREATE TABLE a ()")},
+		"migrations/README.md":              {Data: []byte("ignored")},
+		"migrations/nested/0003_nested.sql": {Data: []byte("ignored")},
+	}
+
+	// Act
+	migrations, err := loadMigrations(files)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []migration{
+		{version: 1, name: "create_a", sql: "CREATE TABLE a ()"},
+		{version: 2, name: "create_b", sql: "CREATE TABLE b ()"},
+		{version: 10, name: "add_index", sql: "CREATE INDEX ..."},
+	}, migrations)
+}
+
+func TestLoadMigrations_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		wantErr string
+	}{
+		{"missing version", fstest.MapFS{"migrations/create_a.sql": {}}, "must be named NNNN_name.sql"},
+		{"missing name", fstest.MapFS{"migrations/0001.sql": {}}, "must be named NNNN_name.sql"},
+		{"zero version", fstest.MapFS{"migrations/0000_init.sql": {}}, "must be named NNNN_name.sql"},
+		{"duplicate version", fstest.MapFS{"migrations/0001_a.sql": {}, "migrations/1_b.sql": {}}, "have the same version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := loadMigrations(tt.files)
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	// Act
+	migrations, err := loadMigrations(migrationFiles)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.version, "migration versions must be sequential")
+	}
+}
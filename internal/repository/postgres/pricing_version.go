@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+)
+
+// PricingVersionRepository is a repository.PricingVersionRepository backed by the pricing_versions
+// table, one row per configuration put in force, stored as JSONB, so that every instance reprices
+// with the same history
+type PricingVersionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPricingVersionRepository creates a pricing version repository using the pool
+func NewPricingVersionRepository(pool *pgxpool.Pool) *PricingVersionRepository {
+	return &PricingVersionRepository{pool: pool}
+}
+
+// Save records a configuration coming into force, unless its version is already the latest
+func (r *PricingVersionRepository) Save(ctx context.Context, version repository.PricingVersion) error {
+	config, err := json.Marshal(version.Config)
+	if err != nil {
+		return fmt.Errorf("failed to encode pricing version %s: %w", version.Version, err)
+	}
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO pricing_versions (effective_from, version, config)
+		SELECT $1, $2, $3
+		WHERE $2 IS DISTINCT FROM (SELECT version FROM pricing_versions ORDER BY effective_from DESC LIMIT 1)
+		ON CONFLICT (effective_from) DO NOTHING`,
+		version.EffectiveFrom, version.Version, config)
+	if err != nil {
+		return fmt.Errorf("failed to save pricing version %s: %w", version.Version, err)
+	}
+	return nil
+}
+
+// AsOf returns the version in force at at
+func (r *PricingVersionRepository) AsOf(ctx context.Context, at time.Time) (repository.PricingVersion, error) {
+	var (
+		version repository.PricingVersion
+		config  []byte
+	)
+	err := r.pool.QueryRow(ctx, `
+		SELECT effective_from, version, config
+		FROM pricing_versions
+		WHERE effective_from <= $1
+		ORDER BY effective_from DESC
+		LIMIT 1`, at).
+		Scan(&version.EffectiveFrom, &version.Version, &config)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.PricingVersion{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return repository.PricingVersion{}, fmt.Errorf("failed to load pricing version as of %s: %w", at.Format(time.RFC3339), err)
+	}
+	if err := json.Unmarshal(config, &version.Config); err != nil {
+		return repository.PricingVersion{}, fmt.Errorf("failed to decode pricing version %s: %w", version.Version, err)
+	}
+	version.EffectiveFrom = version.EffectiveFrom.UTC()
+	return version, nil
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+)
+
+// QuoteRepository is a repository.QuoteRepository backed by the quotes table. The request and
+// response are stored as JSONB
+type QuoteRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewQuoteRepository creates a quote repository using the pool
+func NewQuoteRepository(pool *pgxpool.Pool) *QuoteRepository {
+	return &QuoteRepository{pool: pool}
+}
+
+// Save stores the quote, replacing any quote with the same ID
+func (r *QuoteRepository) Save(ctx context.Context, quote *repository.Quote) error {
+	if quote.ID == "" {
+		return errors.New("quote id is required")
+	}
+	request, err := json.Marshal(quote.Request)
+	if err != nil {
+		return fmt.Errorf("failed to encode request of quote %s: %w", quote.ID, err)
+	}
+	response, err := json.Marshal(quote.Response)
+	if err != nil {
+		return fmt.Errorf("failed to encode response of quote %s: %w", quote.ID, err)
+	}
+	var sensitive []byte
+	if quote.Sensitive != nil {
+		if sensitive, err = json.Marshal(quote.Sensitive); err != nil {
+			return fmt.Errorf("failed to encode sensitive data of quote %s: %w", quote.ID, err)
+		}
+	}
+	var fuelIndex []byte
+	if quote.FuelIndex != nil {
+		if fuelIndex, err = json.Marshal(quote.FuelIndex); err != nil {
+			return fmt.Errorf("failed to encode fuel index of quote %s: %w", quote.ID, err)
+		}
+	}
+	var expiresAt *time.Time
+	if !quote.ExpiresAt.IsZero() {
+		expiresAt = &quote.ExpiresAt
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO quotes (id, request, response, created_at, expires_at, pricing_version, sensitive, encrypted_sensitive, tenant, fuel_index)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE SET
+			request = EXCLUDED.request,
+			response = EXCLUDED.response,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at,
+			pricing_version = EXCLUDED.pricing_version,
+			sensitive = EXCLUDED.sensitive,
+			encrypted_sensitive = EXCLUDED.encrypted_sensitive,
+			tenant = EXCLUDED.tenant,
+			fuel_index = EXCLUDED.fuel_index`,
+		quote.ID, request, response, quote.CreatedAt, expiresAt, quote.PricingVersion, sensitive, quote.EncryptedSensitive, quote.Tenant, fuelIndex)
+	if err != nil {
+		return fmt.Errorf("failed to save quote %s: %w", quote.ID, err)
+	}
+	return nil
+}
+
+// Get returns the quote with the given ID
+func (r *QuoteRepository) Get(ctx context.Context, id string) (*repository.Quote, error) {
+	var (
+		quote              = repository.Quote{ID: id}
+		request, response  []byte
+		sensitive          []byte
+		fuelIndex          []byte
+		createdAt          time.Time
+		expiresAt          *time.Time
+		pricingVersion     string
+		encryptedSensitive string
+		tenant             string
+	)
+	err := r.pool.QueryRow(ctx, `
+		SELECT request, response, created_at, expires_at, pricing_version, sensitive, encrypted_sensitive, tenant, fuel_index
+		FROM quotes WHERE id = $1`, id).
+		Scan(&request, &response, &createdAt, &expiresAt, &pricingVersion, &sensitive, &encryptedSensitive, &tenant, &fuelIndex)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quote %s: %w", id, err)
+	}
+
+	if err := json.Unmarshal(request, &quote.Request); err != nil {
+		return nil, fmt.Errorf("failed to decode request of quote %s: %w", id, err)
+	}
+	if err := json.Unmarshal(response, &quote.Response); err != nil {
+		return nil, fmt.Errorf("failed to decode response of quote %s: %w", id, err)
+	}
+	if sensitive != nil {
+		quote.Sensitive = &repository.SensitiveData{}
+		if err := json.Unmarshal(sensitive, quote.Sensitive); err != nil {
+			return nil, fmt.Errorf("failed to decode sensitive data of quote %s: %w", id, err)
+		}
+	}
+	if fuelIndex != nil {
+		quote.FuelIndex = &model.FuelIndex{}
+		if err := json.Unmarshal(fuelIndex, quote.FuelIndex); err != nil {
+			return nil, fmt.Errorf("failed to decode fuel index of quote %s: %w", id, err)
+		}
+	}
+	quote.CreatedAt = createdAt.UTC()
+	if expiresAt != nil {
+		quote.ExpiresAt = expiresAt.UTC()
+	}
+	quote.PricingVersion = pricingVersion
+	quote.EncryptedSensitive = encryptedSensitive
+	quote.Tenant = tenant
+	return &quote, nil
+}
@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+)
+
+// uniqueViolation is the SQLSTATE of a unique constraint violation
+const uniqueViolation = "23505"
+
+// ShipmentRepository is a repository.ShipmentRepository backed by the shipments table. The unique
+// quote_id column enforces that a quote is booked only once
+type ShipmentRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewShipmentRepository creates a shipment repository using the pool
+func NewShipmentRepository(pool *pgxpool.Pool) *ShipmentRepository {
+	return &ShipmentRepository{pool: pool}
+}
+
+// pickupLock is the advisory lock class of the bookings of a pickup window, the second key being
+// the hash of the date and window
+const pickupLock int32 = 731_815_011
+
+// querier runs statements on the pool or in a transaction
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Save stores the shipment, replacing any shipment with the same ID. Saving a shipment for a quote
+// already booked by another shipment fails with repository.ErrAlreadyExists
+func (r *ShipmentRepository) Save(ctx context.Context, shipment *model.Shipment) error {
+	return save(ctx, r.pool, shipment)
+}
+
+// SaveWithinCapacity stores the shipment unless capacity shipments are already booked for its
+// pickup date and window. A transaction-level advisory lock on the window serializes the bookings
+// of the instances between counting and saving
+func (r *ShipmentRepository) SaveWithinCapacity(ctx context.Context, shipment *model.Shipment, capacity int) error {
+	if capacity <= 0 {
+		return r.Save(ctx, shipment)
+	}
+	date, window := shipment.Package.PickupDate, shipment.Package.PickupWindow
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to save shipment %s: %w", shipment.ID, err)
+	}
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1, hashtext($2))", pickupLock, date+"/"+window); err != nil {
+		return fmt.Errorf("failed to lock the %s pickups of %s: %w", window, date, err)
+	}
+	booked, err := countPickups(ctx, tx, date, window)
+	if err != nil {
+		return err
+	}
+	if booked >= capacity {
+		return repository.ErrCapacityExceeded
+	}
+	if err := save(ctx, tx, shipment); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to save shipment %s: %w", shipment.ID, err)
+	}
+	return nil
+}
+
+// CountPickups returns the number of shipments booked for pickup on date in window
+func (r *ShipmentRepository) CountPickups(ctx context.Context, date, window string) (int, error) {
+	return countPickups(ctx, r.pool, date, window)
+}
+
+// countPickups counts the shipments booked for pickup on date in window
+func countPickups(ctx context.Context, q querier, date, window string) (int, error) {
+	var count int
+	err := q.QueryRow(ctx, `
+		SELECT count(*) FROM shipments
+		WHERE shipment->'package'->>'pickup_date' = $1 AND shipment->'package'->>'pickup_window' = $2`,
+		date, window).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count the %s pickups of %s: %w", window, date, err)
+	}
+	return count, nil
+}
+
+// save stores the shipment through q, replacing any shipment with the same ID
+func save(ctx context.Context, q querier, shipment *model.Shipment) error {
+	if shipment.ID == "" {
+		return errors.New("shipment id is required")
+	}
+	data, err := json.Marshal(shipment)
+	if err != nil {
+		return fmt.Errorf("failed to encode shipment %s: %w", shipment.ID, err)
+	}
+
+	_, err = q.Exec(ctx, `
+		INSERT INTO shipments (id, quote_id, status, shipment, booked_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			quote_id = EXCLUDED.quote_id,
+			status = EXCLUDED.status,
+			shipment = EXCLUDED.shipment,
+			booked_at = EXCLUDED.booked_at`,
+		shipment.ID, shipment.QuoteID, shipment.Status, data, shipment.BookedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return repository.ErrAlreadyExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save shipment %s: %w", shipment.ID, err)
+	}
+	return nil
+}
+
+// Get returns the shipment with the given ID
+func (r *ShipmentRepository) Get(ctx context.Context, id string) (*model.Shipment, error) {
+	var data []byte
+	err := r.pool.QueryRow(ctx, "SELECT shipment FROM shipments WHERE id = $1", id).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load shipment %s: %w", id, err)
+	}
+
+	var shipment model.Shipment
+	if err := json.Unmarshal(data, &shipment); err != nil {
+		return nil, fmt.Errorf("failed to decode shipment %s: %w", id, err)
+	}
+	return &shipment, nil
+}
+
+// UpdateStatus sets the status of the shipment, in its column and its JSON, to to if it is still
+// from
+func (r *ShipmentRepository) UpdateStatus(ctx context.Context, id, from, to string) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE shipments
+		SET status = $3, shipment = jsonb_set(shipment, '{status}', to_jsonb($3::text))
+		WHERE id = $1 AND status = $2`,
+		id, from, to)
+	if err != nil {
+		return false, fmt.Errorf("failed to update the status of shipment %s: %w", id, err)
+	}
+	if tag.RowsAffected() > 0 {
+		return true, nil
+	}
+	var exists bool
+	if err := r.pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM shipments WHERE id = $1)", id).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to update the status of shipment %s: %w", id, err)
+	}
+	if !exists {
+		return false, repository.ErrNotFound
+	}
+	return false, nil
+}
+
+// ListByStatus returns up to limit shipments with the status, ordered by ID, after afterID
+func (r *ShipmentRepository) ListByStatus(ctx context.Context, status, afterID string, limit int) ([]model.Shipment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT shipment FROM shipments
+		WHERE status = $1 AND id > $2
+		ORDER BY id
+		LIMIT $3`,
+		status, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s shipments: %w", status, err)
+	}
+	defer rows.Close()
+
+	var shipments []model.Shipment
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read shipment: %w", err)
+		}
+		var shipment model.Shipment
+		if err := json.Unmarshal(data, &shipment); err != nil {
+			return nil, fmt.Errorf("failed to decode shipment: %w", err)
+		}
+		shipments = append(shipments, shipment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list %s shipments: %w", status, err)
+	}
+	return shipments, nil
+}
+
+// ListBookedBetween returns up to limit shipments with the status booked in [from, to), ordered by
+// ID, after afterID
+func (r *ShipmentRepository) ListBookedBetween(ctx context.Context, status string, from, to time.Time, afterID string, limit int) ([]model.Shipment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT shipment FROM shipments
+		WHERE status = $1 AND booked_at >= $2 AND booked_at < $3 AND id > $4
+		ORDER BY id
+		LIMIT $5`,
+		status, from, to, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s shipments: %w", status, err)
+	}
+	defer rows.Close()
+
+	var shipments []model.Shipment
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read shipment: %w", err)
+		}
+		var shipment model.Shipment
+		if err := json.Unmarshal(data, &shipment); err != nil {
+			return nil, fmt.Errorf("failed to decode shipment: %w", err)
+		}
+		shipments = append(shipments, shipment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list %s shipments: %w", status, err)
+	}
+	return shipments, nil
+}
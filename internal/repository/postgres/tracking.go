@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+)
+
+// TrackingRepository is a repository.TrackingRepository backed by the tracking_events table. An
+// event is identified by its shipment, status, time, location and description, so events the
+// carrier reports again are not stored twice
+type TrackingRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTrackingRepository creates a tracking repository using the pool
+func NewTrackingRepository(pool *pgxpool.Pool) *TrackingRepository {
+	return &TrackingRepository{pool: pool}
+}
+
+// Append stores the events of the shipment in a single transaction, returning how many of them
+// were new
+func (r *TrackingRepository) Append(ctx context.Context, shipmentID string, events []model.TrackingEvent) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to append tracking events of shipment %s: %w", shipmentID, err)
+	}
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	added := 0
+	for _, event := range events {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO tracking_events (shipment_id, status, description, location, occurred_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (shipment_id, status, occurred_at, location, description) DO NOTHING`,
+			shipmentID, event.Status, event.Description, event.Location, event.OccurredAt)
+		if err != nil {
+			return 0, fmt.Errorf("failed to append tracking events of shipment %s: %w", shipmentID, err)
+		}
+		added += int(tag.RowsAffected())
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to append tracking events of shipment %s: %w", shipmentID, err)
+	}
+	return added, nil
+}
+
+// List returns the events of the shipment ordered by OccurredAt, oldest first, and then in the
+// order they were stored
+func (r *TrackingRepository) List(ctx context.Context, shipmentID string) ([]model.TrackingEvent, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT status, description, location, occurred_at FROM tracking_events
+		WHERE shipment_id = $1
+		ORDER BY occurred_at, seq`,
+		shipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracking events of shipment %s: %w", shipmentID, err)
+	}
+	defer rows.Close()
+
+	events := make([]model.TrackingEvent, 0)
+	for rows.Next() {
+		var event model.TrackingEvent
+		if err := rows.Scan(&event.Status, &event.Description, &event.Location, &event.OccurredAt); err != nil {
+			return nil, fmt.Errorf("failed to read tracking event: %w", err)
+		}
+		event.OccurredAt = event.OccurredAt.UTC()
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tracking events of shipment %s: %w", shipmentID, err)
+	}
+	return events, nil
+}
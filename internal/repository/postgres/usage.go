@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+)
+
+// UsageRepository is a repository.UsageRepository backed by the quote_usage table, one row per
+// day, tenant and API key, so that every instance counts towards the same quotas
+type UsageRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewUsageRepository creates a usage repository using the pool
+func NewUsageRepository(pool *pgxpool.Pool) *UsageRepository {
+	return &UsageRepository{pool: pool}
+}
+
+// Increment counts quotes quote requests of the tenant and API key on the day of at
+func (r *UsageRepository) Increment(ctx context.Context, tenantID, apiKey string, quotes int64, at time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO quote_usage (day, tenant, api_key, quotes)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (day, tenant, api_key) DO UPDATE SET quotes = quote_usage.quotes + EXCLUDED.quotes`,
+		at.UTC().Format(model.DayLayout), tenantID, apiKey, quotes)
+	if err != nil {
+		return fmt.Errorf("failed to record usage of tenant %s: %w", tenantID, err)
+	}
+	return nil
+}
+
+// Daily returns the usage of the days from from to to, inclusive
+func (r *UsageRepository) Daily(ctx context.Context, tenantID string, from, to time.Time) ([]model.UsageRecord, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT to_char(day, 'YYYY-MM-DD'), tenant, api_key, quotes
+		FROM quote_usage
+		WHERE day BETWEEN $1 AND $2 AND ($3 = '' OR tenant = $3)
+		ORDER BY day, tenant, api_key`,
+		from.UTC().Format(model.DayLayout), to.UTC().Format(model.DayLayout), tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load usage: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]model.UsageRecord, 0)
+	for rows.Next() {
+		var record model.UsageRecord
+		if err := rows.Scan(&record.Day, &record.Tenant, &record.APIKey, &record.Quotes); err != nil {
+			return nil, fmt.Errorf("failed to load usage: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load usage: %w", err)
+	}
+	return records, nil
+}
+
+// Total returns the quote requests of the tenant from from to to, inclusive
+func (r *UsageRepository) Total(ctx context.Context, tenantID string, from, to time.Time) (int64, error) {
+	var total int64
+	err := r.pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(quotes), 0)::BIGINT
+		FROM quote_usage
+		WHERE day BETWEEN $1 AND $2 AND tenant = $3`,
+		from.UTC().Format(model.DayLayout), to.UTC().Format(model.DayLayout), tenantID).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load usage of tenant %s: %w", tenantID, err)
+	}
+	return total, nil
+}
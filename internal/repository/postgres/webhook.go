@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+)
+
+// WebhookRepository is a repository.WebhookRepository backed by the webhook_subscriptions and
+// webhook_dead_letters tables
+type WebhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWebhookRepository creates a webhook repository using the pool
+func NewWebhookRepository(pool *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{pool: pool}
+}
+
+// Save stores the subscription, replacing any subscription with the same ID
+func (r *WebhookRepository) Save(ctx context.Context, subscription *model.WebhookSubscription) error {
+	data, err := json.Marshal(subscription)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook subscription %s: %w", subscription.ID, err)
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO webhook_subscriptions (id, tenant, subscription, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET
+			tenant = EXCLUDED.tenant,
+			subscription = EXCLUDED.subscription,
+			created_at = EXCLUDED.created_at`,
+		subscription.ID, subscription.Tenant, data, subscription.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save webhook subscription %s: %w", subscription.ID, err)
+	}
+	return nil
+}
+
+// Delete removes a subscription of the tenant
+func (r *WebhookRepository) Delete(ctx context.Context, tenantID, id string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1 AND tenant = $2", id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription %s: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListByTenant returns the subscriptions of the tenant, ordered by creation and then ID
+func (r *WebhookRepository) ListByTenant(ctx context.Context, tenantID string) ([]model.WebhookSubscription, error) {
+	rows, err := r.pool.Query(ctx, "SELECT subscription FROM webhook_subscriptions WHERE tenant = $1 ORDER BY created_at, id", tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions of %s: %w", tenantID, err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]model.WebhookSubscription, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read webhook subscription: %w", err)
+		}
+		var subscription model.WebhookSubscription
+		if err := json.Unmarshal(data, &subscription); err != nil {
+			return nil, fmt.Errorf("failed to decode webhook subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions of %s: %w", tenantID, err)
+	}
+	return subscriptions, nil
+}
+
+// AddDeadLetter stores the dead letter
+func (r *WebhookRepository) AddDeadLetter(ctx context.Context, letter *model.WebhookDeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook dead letter %s: %w", letter.ID, err)
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO webhook_dead_letters (id, tenant, dead_letter, failed_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO NOTHING`,
+		letter.ID, letter.Tenant, data, letter.FailedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save webhook dead letter %s: %w", letter.ID, err)
+	}
+	return nil
+}
+
+// ListDeadLetters returns up to limit dead letters, the most recently added first
+func (r *WebhookRepository) ListDeadLetters(ctx context.Context, tenantID string, limit int) ([]model.WebhookDeadLetter, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT dead_letter FROM webhook_dead_letters
+		WHERE $1 = '' OR tenant = $1
+		ORDER BY seq DESC
+		LIMIT $2`,
+		tenantID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	letters := make([]model.WebhookDeadLetter, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read webhook dead letter: %w", err)
+		}
+		var letter model.WebhookDeadLetter
+		if err := json.Unmarshal(data, &letter); err != nil {
+			return nil, fmt.Errorf("failed to decode webhook dead letter: %w", err)
+		}
+		letters = append(letters, letter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list webhook dead letters: %w", err)
+	}
+	return letters, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+)
+
+// PricingVersion is a pricing configuration of the default tenant and when it came into force
+type PricingVersion struct {
+	Version       string
+	EffectiveFrom time.Time
+	Config        pricing.Config
+}
+
+// PricingVersionRepository defines the contract for the persistence of the pricing configurations
+// put in force, so that quotes can be repriced with the configuration in force at a past date
+type PricingVersionRepository interface {
+	// Save records a configuration coming into force, unless its version is already the latest
+	// recorded, e.g. when another instance or a restart recorded it first
+	Save(ctx context.Context, version PricingVersion) error
+	// AsOf returns the version in force at at, the latest one effective from at or before it, or
+	// ErrNotFound when none was recorded yet at that time
+	AsOf(ctx context.Context, at time.Time) (PricingVersion, error)
+}
+
+// MemoryPricingVersionRepository is an in-memory PricingVersionRepository, safe for concurrent use.
+// Versions are kept per instance and lost on restart
+type MemoryPricingVersionRepository struct {
+	mu sync.RWMutex
+	// versions are sorted by EffectiveFrom
+	versions []PricingVersion
+}
+
+// NewMemoryPricingVersionRepository creates an empty in-memory pricing version repository
+func NewMemoryPricingVersionRepository() *MemoryPricingVersionRepository {
+	return &MemoryPricingVersionRepository{}
+}
+
+// Save records a configuration coming into force, unless its version is already the latest
+func (r *MemoryPricingVersionRepository) Save(ctx context.Context, version PricingVersion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n := len(r.versions); n > 0 && r.versions[n-1].Version == version.Version {
+		return nil
+	}
+	i := sort.Search(len(r.versions), func(i int) bool { return r.versions[i].EffectiveFrom.After(version.EffectiveFrom) })
+	r.versions = append(r.versions, PricingVersion{})
+	copy(r.versions[i+1:], r.versions[i:])
+	r.versions[i] = version
+	return nil
+}
+
+// AsOf returns the version in force at at
+func (r *MemoryPricingVersionRepository) AsOf(ctx context.Context, at time.Time) (PricingVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := sort.Search(len(r.versions), func(i int) bool { return r.versions[i].EffectiveFrom.After(at) })
+	if i == 0 {
+		return PricingVersion{}, ErrNotFound
+	}
+	return r.versions[i-1], nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryPricingVersionRepository(t *testing.T) {
+	// Arrange
+	repo := NewMemoryPricingVersionRepository()
+	ctx := context.Background()
+	march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	may := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	first := PricingVersion{Version: "2025.03", EffectiveFrom: march, Config: pricing.Config{Version: "2025.03"}}
+	second := PricingVersion{Version: "2025.05", EffectiveFrom: may, Config: pricing.Config{Version: "2025.05"}}
+	_ = repo.Save(ctx, second)
+	_ = repo.Save(ctx, first)
+	_ = repo.Save(ctx, PricingVersion{Version: "2025.05", EffectiveFrom: may.AddDate(0, 1, 0)})
+
+	tests := []struct {
+		name    string
+		at      time.Time
+		want    PricingVersion
+		wantErr error
+	}{
+		{"before the first version", march.Add(-time.Second), PricingVersion{}, ErrNotFound},
+		{"when a version came into force", march, first, nil},
+		{"between versions", may.Add(-time.Second), first, nil},
+		{"after the latest version", may.AddDate(1, 0, 0), second, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := repo.AsOf(ctx, tt.at)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	assert.Len(t, repo.versions, 2, "the latest version is not recorded twice")
+}
@@ -0,0 +1,134 @@
+// Package repository provides persistence for quotes.
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+)
+
+// ErrNotFound is returned when the requested record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// Quote is the persisted record of a calculated quote
+type Quote struct {
+	ID        string
+	Request   model.CalculateShippingRequest
+	Response  model.CalculateShippingResponse
+	CreatedAt time.Time
+	// ExpiresAt is when the quoted price stops being honored; POST /quotes/{id}/revalidate reprices
+	// the quote as a new one
+	ExpiresAt time.Time
+	// PricingVersion identifies the rate table the quote was priced with
+	PricingVersion string
+	// FuelIndex is the fuel surcharge index the quote was priced with; nil without fuel surcharge
+	FuelIndex *model.FuelIndex
+	// Tenant is the tenant the quote was priced for; empty for quotes saved before tenants
+	// existed, which belong to the default tenant
+	Tenant string
+
+	// Sensitive holds personal and commercial data (full addresses, declared value).
+	// Repositories wrapped with NewEncryptedQuoteRepository never store it in clear text
+	Sensitive *SensitiveData
+	// EncryptedSensitive is the at-rest representation of Sensitive
+	EncryptedSensitive string
+}
+
+// Expired reports whether the quote has expired at now. Quotes without ExpiresAt never expire
+func (q *Quote) Expired(now time.Time) bool {
+	return !q.ExpiresAt.IsZero() && !now.Before(q.ExpiresAt)
+}
+
+// BelongsTo reports whether the quote was priced for the tenant. Callers report quotes of other
+// tenants as not found, so that quote IDs do not leak across tenants
+func (q *Quote) BelongsTo(tenantID string) bool {
+	if q.Tenant == "" {
+		return tenantID == tenant.Default
+	}
+	return q.Tenant == tenantID
+}
+
+// QuoteConfig configures the validity of calculated quotes
+type QuoteConfig struct {
+	// TTL is how long a quoted price is honored after calculation
+	TTL time.Duration
+}
+
+// QuoteConfigFromEnv reads QUOTE_TTL (default 30m)
+func QuoteConfigFromEnv() (QuoteConfig, error) {
+	ttl, err := config.Duration("QUOTE_TTL", 30*time.Minute)
+	if err != nil {
+		return QuoteConfig{}, err
+	}
+	if ttl <= 0 {
+		return QuoteConfig{}, fmt.Errorf("QUOTE_TTL must be positive, got %s", ttl)
+	}
+	return QuoteConfig{TTL: ttl}, nil
+}
+
+// SensitiveData groups the quote fields that must be encrypted at rest
+type SensitiveData struct {
+	OriginAddress      string  `json:"origin_address,omitempty"`
+	DestinationAddress string  `json:"destination_address,omitempty"`
+	DeclaredValue      float64 `json:"declared_value,omitempty"`
+}
+
+// QuoteRepository defines the contract for quote persistence
+type QuoteRepository interface {
+	Save(ctx context.Context, quote *Quote) error
+	Get(ctx context.Context, id string) (*Quote, error)
+}
+
+// MemoryQuoteRepository is an in-memory QuoteRepository, safe for concurrent use
+type MemoryQuoteRepository struct {
+	mu     sync.RWMutex
+	quotes map[string]Quote
+}
+
+// NewMemoryQuoteRepository creates an empty in-memory quote repository
+func NewMemoryQuoteRepository() *MemoryQuoteRepository {
+	return &MemoryQuoteRepository{quotes: make(map[string]Quote)}
+}
+
+// Save stores a copy of the quote, replacing any quote with the same ID
+func (r *MemoryQuoteRepository) Save(ctx context.Context, quote *Quote) error {
+	if quote.ID == "" {
+		return errors.New("quote id is required")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.quotes[quote.ID] = copyQuote(quote)
+	return nil
+}
+
+// Get returns a copy of the quote with the given ID
+func (r *MemoryQuoteRepository) Get(ctx context.Context, id string) (*Quote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	quote, ok := r.quotes[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	out := copyQuote(&quote)
+	return &out, nil
+}
+
+// copyQuote copies the quote so callers cannot mutate stored records
+func copyQuote(quote *Quote) Quote {
+	out := *quote
+	if quote.Sensitive != nil {
+		sensitive := *quote.Sensitive
+		out.Sensitive = &sensitive
+	}
+	if quote.FuelIndex != nil {
+		fuel := *quote.FuelIndex
+		out.FuelIndex = &fuel
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+)
+
+// StoreQuoteRepository is a QuoteRepository persisting quotes as JSON in a store.QuoteStore,
+// so that quotes can be retrieved from any instance sharing the store
+type StoreQuoteRepository struct {
+	store store.QuoteStore
+	ttl   time.Duration
+}
+
+// NewStoreQuoteRepository creates a repository keeping each quote in the store for ttl after it
+// is saved. ttl should outlast QuoteConfig.TTL so that expired quotes can still be revalidated
+func NewStoreQuoteRepository(quoteStore store.QuoteStore, ttl time.Duration) *StoreQuoteRepository {
+	return &StoreQuoteRepository{store: quoteStore, ttl: ttl}
+}
+
+// Save stores the quote, replacing any quote with the same ID
+func (r *StoreQuoteRepository) Save(ctx context.Context, quote *Quote) error {
+	if quote.ID == "" {
+		return errors.New("quote id is required")
+	}
+	data, err := json.Marshal(quote)
+	if err != nil {
+		return fmt.Errorf("failed to encode quote %s: %w", quote.ID, err)
+	}
+	return r.store.Put(ctx, quoteKey(quote.ID), data, r.ttl)
+}
+
+// Get returns the quote with the given ID
+func (r *StoreQuoteRepository) Get(ctx context.Context, id string) (*Quote, error) {
+	data, err := r.store.Get(ctx, quoteKey(id))
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var quote Quote
+	if err := json.Unmarshal(data, &quote); err != nil {
+		return nil, fmt.Errorf("failed to decode quote %s: %w", id, err)
+	}
+	return &quote, nil
+}
+
+// quoteKey returns the store key of a quote
+func quoteKey(id string) string {
+	return "quote:" + id
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingStore fails every operation
+type failingStore struct{}
+
+func (failingStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("store unavailable")
+}
+
+func (failingStore) PutIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return false, errors.New("store unavailable")
+}
+
+func (failingStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, errors.New("store unavailable")
+}
+
+func (failingStore) Delete(ctx context.Context, key string) error {
+	return errors.New("store unavailable")
+}
+
+func TestStoreQuoteRepository_SaveAndGet(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	quoteStore := store.NewMemoryStore()
+	repo := NewStoreQuoteRepository(quoteStore, time.Hour)
+	quote := newTestQuote("q1")
+	quote.ExpiresAt = quote.CreatedAt.Add(30 * time.Minute)
+
+	// Act
+	err := repo.Save(ctx, quote)
+	result, getErr := repo.Get(ctx, "q1")
+	_, storeErr := quoteStore.Get(ctx, "quote:q1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, getErr)
+	assert.Equal(t, quote, result)
+	assert.NoError(t, storeErr)
+}
+
+func TestStoreQuoteRepository_Get_NotFound(t *testing.T) {
+	// Arrange
+	repo := NewStoreQuoteRepository(store.NewMemoryStore(), time.Hour)
+
+	// Act
+	result, err := repo.Get(context.Background(), "missing")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, result)
+}
+
+func TestStoreQuoteRepository_Save_RequiresID(t *testing.T) {
+	// Arrange
+	repo := NewStoreQuoteRepository(store.NewMemoryStore(), time.Hour)
+
+	// Act
+	err := repo.Save(context.Background(), &Quote{})
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestStoreQuoteRepository_StoreErrors(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewStoreQuoteRepository(failingStore{}, time.Hour)
+
+	// Act
+	saveErr := repo.Save(ctx, newTestQuote("q1"))
+	_, getErr := repo.Get(ctx, "q1")
+
+	// Assert
+	assert.ErrorContains(t, saveErr, "store unavailable")
+	assert.ErrorContains(t, getErr, "store unavailable")
+	assert.NotErrorIs(t, getErr, ErrNotFound)
+}
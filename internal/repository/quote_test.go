@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestQuote(id string) *Quote {
+	return &Quote{
+		ID: id,
+		Request: model.CalculateShippingRequest{
+			OriginZipcode:      "01310100",
+			DestinationZipcode: "04547130",
+			Weight:             1.0,
+		},
+		Response:  model.CalculateShippingResponse{ShippingCost: money.FromMinor(1250)},
+		CreatedAt: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+		Sensitive: &SensitiveData{
+			OriginAddress:      "Av. Paulista, 1000",
+			DestinationAddress: "Rua Funchal, 200",
+			DeclaredValue:      350.0,
+		},
+	}
+}
+
+func TestMemoryQuoteRepository_SaveAndGet(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryQuoteRepository()
+	quote := newTestQuote("q1")
+
+	// Act
+	err := repo.Save(ctx, quote)
+	result, getErr := repo.Get(ctx, "q1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, getErr)
+	assert.Equal(t, quote, result)
+}
+
+func TestMemoryQuoteRepository_Get_NotFound(t *testing.T) {
+	// Arrange
+	repo := NewMemoryQuoteRepository()
+
+	// Act
+	result, err := repo.Get(context.Background(), "missing")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, result)
+}
+
+func TestMemoryQuoteRepository_Save_RequiresID(t *testing.T) {
+	// Arrange
+	repo := NewMemoryQuoteRepository()
+
+	// Act
+	err := repo.Save(context.Background(), &Quote{})
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestMemoryQuoteRepository_StoresCopies(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryQuoteRepository()
+	quote := newTestQuote("q1")
+	_ = repo.Save(ctx, quote)
+
+	// Act
+	quote.Sensitive.OriginAddress = "changed after save"
+	first, _ := repo.Get(ctx, "q1")
+	first.Sensitive.DestinationAddress = "changed after get"
+	second, _ := repo.Get(ctx, "q1")
+
+	// Assert
+	assert.Equal(t, "Av. Paulista, 1000", second.Sensitive.OriginAddress)
+	assert.Equal(t, "Rua Funchal, 200", second.Sensitive.DestinationAddress)
+}
+
+func TestQuote_Expired(t *testing.T) {
+	expiresAt := time.Date(2025, 1, 10, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		now       time.Time
+		want      bool
+	}{
+		{"before expiration", expiresAt, expiresAt.Add(-time.Second), false},
+		{"at expiration", expiresAt, expiresAt, true},
+		{"after expiration", expiresAt, expiresAt.Add(time.Hour), true},
+		{"without expiration", time.Time{}, expiresAt.Add(time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			quote := &Quote{ExpiresAt: tt.expiresAt}
+
+			// Act & Assert
+			assert.Equal(t, tt.want, quote.Expired(tt.now))
+		})
+	}
+}
+
+func TestQuote_BelongsTo(t *testing.T) {
+	tests := []struct {
+		name        string
+		quoteTenant string
+		tenantID    string
+		want        bool
+	}{
+		{"same tenant", "acme", "acme", true},
+		{"other tenant", "acme", "globex", false},
+		{"default tenant of another tenant's quote", "acme", "default", false},
+		{"quote saved before tenants, default tenant", "", "default", true},
+		{"quote saved before tenants, other tenant", "", "acme", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			quote := &Quote{Tenant: tt.quoteTenant}
+
+			// Act & Assert
+			assert.Equal(t, tt.want, quote.BelongsTo(tt.tenantID))
+		})
+	}
+}
+
+func TestQuoteConfigFromEnv(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		// Arrange
+		t.Setenv("QUOTE_TTL", "")
+
+		// Act
+		cfg, err := QuoteConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, QuoteConfig{TTL: 30 * time.Minute}, cfg)
+	})
+
+	t.Run("custom value", func(t *testing.T) {
+		// Arrange
+		t.Setenv("QUOTE_TTL", "2h")
+
+		// Act
+		cfg, err := QuoteConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, QuoteConfig{TTL: 2 * time.Hour}, cfg)
+	})
+
+	t.Run("not positive", func(t *testing.T) {
+		// Arrange
+		t.Setenv("QUOTE_TTL", "0s")
+
+		// Act
+		_, err := QuoteConfigFromEnv()
+
+		// Assert
+		assert.ErrorContains(t, err, "QUOTE_TTL must be positive")
+	})
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+)
+
+// ErrAlreadyExists is returned when saving a record that conflicts with an existing one
+var ErrAlreadyExists = errors.New("record already exists")
+
+// ErrCapacityExceeded is returned when saving a shipment whose pickup window has no slots left
+var ErrCapacityExceeded = errors.New("pickup window capacity exceeded")
+
+// ShipmentRepository defines the contract for shipment persistence. A quote can be booked
+// only once: saving a second shipment for the same quote fails with ErrAlreadyExists
+type ShipmentRepository interface {
+	Save(ctx context.Context, shipment *model.Shipment) error
+	// SaveWithinCapacity saves a new shipment unless capacity shipments are already booked for its
+	// pickup date and window, failing with ErrCapacityExceeded; a capacity of 0 means no limit.
+	// Concurrent saves for the same window are serialized, so the capacity is never exceeded
+	SaveWithinCapacity(ctx context.Context, shipment *model.Shipment, capacity int) error
+	// CountPickups returns the number of shipments booked for pickup on date in window
+	CountPickups(ctx context.Context, date, window string) (int, error)
+	Get(ctx context.Context, id string) (*model.Shipment, error)
+	// UpdateStatus sets the status of a shipment to to only if it is still from, reporting whether
+	// it was updated, so that concurrent updates do not overwrite each other. The other fields of
+	// the shipment are not changed; a missing shipment is ErrNotFound
+	UpdateStatus(ctx context.Context, id, from, to string) (bool, error)
+	// ListByStatus returns up to limit shipments with the status, ordered by ID, starting after
+	// the ID afterID (empty for the first page)
+	ListByStatus(ctx context.Context, status, afterID string, limit int) ([]model.Shipment, error)
+	// ListBookedBetween returns up to limit shipments with the status booked in [from, to), ordered
+	// by ID, starting after the ID afterID (empty for the first page)
+	ListBookedBetween(ctx context.Context, status string, from, to time.Time, afterID string, limit int) ([]model.Shipment, error)
+}
+
+// MemoryShipmentRepository is an in-memory ShipmentRepository, safe for concurrent use
+type MemoryShipmentRepository struct {
+	mu        sync.RWMutex
+	shipments map[string]model.Shipment
+	// byQuote maps quote IDs to the ID of the shipment booked from them
+	byQuote map[string]string
+}
+
+// NewMemoryShipmentRepository creates an empty in-memory shipment repository
+func NewMemoryShipmentRepository() *MemoryShipmentRepository {
+	return &MemoryShipmentRepository{
+		shipments: make(map[string]model.Shipment),
+		byQuote:   make(map[string]string),
+	}
+}
+
+// Save stores a copy of the shipment, replacing any shipment with the same ID
+func (r *MemoryShipmentRepository) Save(ctx context.Context, shipment *model.Shipment) error {
+	if shipment.ID == "" {
+		return errors.New("shipment id is required")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id, ok := r.byQuote[shipment.QuoteID]; ok && id != shipment.ID {
+		return ErrAlreadyExists
+	}
+	if previous, ok := r.shipments[shipment.ID]; ok {
+		delete(r.byQuote, previous.QuoteID)
+	}
+	r.shipments[shipment.ID] = copyShipment(shipment)
+	r.byQuote[shipment.QuoteID] = shipment.ID
+	return nil
+}
+
+// SaveWithinCapacity stores a copy of the shipment unless capacity shipments are already booked for
+// its pickup date and window
+func (r *MemoryShipmentRepository) SaveWithinCapacity(ctx context.Context, shipment *model.Shipment, capacity int) error {
+	if shipment.ID == "" {
+		return errors.New("shipment id is required")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if capacity > 0 && r.countPickups(shipment.Package.PickupDate, shipment.Package.PickupWindow) >= capacity {
+		return ErrCapacityExceeded
+	}
+	if id, ok := r.byQuote[shipment.QuoteID]; ok && id != shipment.ID {
+		return ErrAlreadyExists
+	}
+	r.shipments[shipment.ID] = copyShipment(shipment)
+	r.byQuote[shipment.QuoteID] = shipment.ID
+	return nil
+}
+
+// CountPickups returns the number of shipments booked for pickup on date in window
+func (r *MemoryShipmentRepository) CountPickups(ctx context.Context, date, window string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.countPickups(date, window), nil
+}
+
+// countPickups counts the shipments booked for pickup on date in window; r.mu must be held
+func (r *MemoryShipmentRepository) countPickups(date, window string) int {
+	count := 0
+	for _, shipment := range r.shipments {
+		if shipment.Package.PickupDate == date && shipment.Package.PickupWindow == window {
+			count++
+		}
+	}
+	return count
+}
+
+// Get returns a copy of the shipment with the given ID
+func (r *MemoryShipmentRepository) Get(ctx context.Context, id string) (*model.Shipment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	shipment, ok := r.shipments[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	out := copyShipment(&shipment)
+	return &out, nil
+}
+
+// UpdateStatus sets the status of the shipment to to if it is still from
+func (r *MemoryShipmentRepository) UpdateStatus(ctx context.Context, id, from, to string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	shipment, ok := r.shipments[id]
+	if !ok {
+		return false, ErrNotFound
+	}
+	if shipment.Status != from {
+		return false, nil
+	}
+	shipment.Status = to
+	r.shipments[id] = shipment
+	return true, nil
+}
+
+// ListByStatus returns copies of up to limit shipments with the status, ordered by ID, after afterID
+func (r *MemoryShipmentRepository) ListByStatus(ctx context.Context, status, afterID string, limit int) ([]model.Shipment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, 0, len(r.shipments))
+	for id, shipment := range r.shipments {
+		if shipment.Status == status && id > afterID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	shipments := make([]model.Shipment, 0, len(ids))
+	for _, id := range ids {
+		shipment := r.shipments[id]
+		shipments = append(shipments, copyShipment(&shipment))
+	}
+	return shipments, nil
+}
+
+// ListBookedBetween returns copies of up to limit shipments with the status booked in [from, to),
+// ordered by ID, after afterID
+func (r *MemoryShipmentRepository) ListBookedBetween(ctx context.Context, status string, from, to time.Time, afterID string, limit int) ([]model.Shipment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, 0)
+	for id, shipment := range r.shipments {
+		if shipment.Status == status && id > afterID && !shipment.BookedAt.Before(from) && shipment.BookedAt.Before(to) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	shipments := make([]model.Shipment, 0, len(ids))
+	for _, id := range ids {
+		shipment := r.shipments[id]
+		shipments = append(shipments, copyShipment(&shipment))
+	}
+	return shipments, nil
+}
+
+// copyShipment copies the shipment so callers cannot mutate stored records
+func copyShipment(shipment *model.Shipment) model.Shipment {
+	out := *shipment
+	if shipment.Package.AdditionalServices != nil {
+		out.Package.AdditionalServices = append([]string{}, shipment.Package.AdditionalServices...)
+	}
+	return out
+}
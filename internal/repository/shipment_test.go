@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestShipment(id, quoteID string) *model.Shipment {
+	return &model.Shipment{
+		ID:      id,
+		QuoteID: quoteID,
+		Status:  model.ShipmentStatusBooked,
+		Service: model.ServiceStandard,
+		Cost:    money.FromMinor(1250),
+		Package: model.ShipmentPackage{
+			OriginZipcode:      "01310100",
+			DestinationZipcode: "04547130",
+			Weight:             1.0,
+			AdditionalServices: []string{"signature"},
+		},
+		BookedAt: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestMemoryShipmentRepository_SaveAndGet(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryShipmentRepository()
+	shipment := newTestShipment("s1", "q1")
+
+	// Act
+	err := repo.Save(ctx, shipment)
+	result, getErr := repo.Get(ctx, "s1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, getErr)
+	assert.Equal(t, shipment, result)
+}
+
+func TestMemoryShipmentRepository_Get_NotFound(t *testing.T) {
+	// Arrange
+	repo := NewMemoryShipmentRepository()
+
+	// Act
+	result, err := repo.Get(context.Background(), "missing")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, result)
+}
+
+func TestMemoryShipmentRepository_Save_RequiresID(t *testing.T) {
+	// Arrange
+	repo := NewMemoryShipmentRepository()
+
+	// Act
+	err := repo.Save(context.Background(), &model.Shipment{QuoteID: "q1"})
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestMemoryShipmentRepository_Save_QuoteBookedOnce(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryShipmentRepository()
+	_ = repo.Save(ctx, newTestShipment("s1", "q1"))
+
+	// Act
+	duplicateErr := repo.Save(ctx, newTestShipment("s2", "q1"))
+	updateErr := repo.Save(ctx, newTestShipment("s1", "q1"))
+
+	// Assert
+	assert.ErrorIs(t, duplicateErr, ErrAlreadyExists)
+	assert.NoError(t, updateErr)
+}
+
+func TestMemoryShipmentRepository_SaveWithinCapacity(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryShipmentRepository()
+	pickup := func(id, window string) *model.Shipment {
+		shipment := newTestShipment(id, "q-"+id)
+		shipment.Package.PickupDate, shipment.Package.PickupWindow = "2025-01-13", window
+		return shipment
+	}
+	_ = repo.Save(ctx, newTestShipment("unscheduled", "q-unscheduled"))
+
+	// Act
+	firstErr := repo.SaveWithinCapacity(ctx, pickup("s1", "morning"), 2)
+	secondErr := repo.SaveWithinCapacity(ctx, pickup("s2", "morning"), 2)
+	fullErr := repo.SaveWithinCapacity(ctx, pickup("s3", "morning"), 2)
+	otherWindowErr := repo.SaveWithinCapacity(ctx, pickup("s4", "afternoon"), 2)
+	unlimitedErr := repo.SaveWithinCapacity(ctx, pickup("s5", "morning"), 0)
+	morning, countErr := repo.CountPickups(ctx, "2025-01-13", "morning")
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	assert.ErrorIs(t, fullErr, ErrCapacityExceeded)
+	assert.NoError(t, otherWindowErr)
+	assert.NoError(t, unlimitedErr)
+	assert.NoError(t, countErr)
+	assert.Equal(t, 3, morning)
+	_, getErr := repo.Get(ctx, "s3")
+	assert.ErrorIs(t, getErr, ErrNotFound)
+}
+
+func TestMemoryShipmentRepository_UpdateStatus(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryShipmentRepository()
+	_ = repo.Save(ctx, newTestShipment("s1", "q1"))
+
+	// Act
+	updated, err := repo.UpdateStatus(ctx, "s1", model.ShipmentStatusBooked, "in_transit")
+	stale, staleErr := repo.UpdateStatus(ctx, "s1", model.ShipmentStatusBooked, "posted")
+	_, missingErr := repo.UpdateStatus(ctx, "missing", model.ShipmentStatusBooked, "posted")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.NoError(t, staleErr)
+	assert.False(t, stale)
+	assert.ErrorIs(t, missingErr, ErrNotFound)
+	shipment, _ := repo.Get(ctx, "s1")
+	assert.Equal(t, "in_transit", shipment.Status)
+	assert.Equal(t, newTestShipment("s1", "q1").Package, shipment.Package)
+}
+
+func TestMemoryShipmentRepository_StoresCopies(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryShipmentRepository()
+	shipment := newTestShipment("s1", "q1")
+	_ = repo.Save(ctx, shipment)
+
+	// Act
+	shipment.Package.AdditionalServices[0] = "changed after save"
+	first, _ := repo.Get(ctx, "s1")
+	first.Package.AdditionalServices[0] = "changed after get"
+	second, _ := repo.Get(ctx, "s1")
+
+	// Assert
+	assert.Equal(t, []string{"signature"}, second.Package.AdditionalServices)
+}
+
+func TestMemoryShipmentRepository_ListByStatus(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryShipmentRepository()
+	for _, id := range []string{"s3", "s1", "s2", "s4"} {
+		_ = repo.Save(ctx, newTestShipment(id, "q-"+id))
+	}
+	cancelled := newTestShipment("s0", "q-s0")
+	cancelled.Status = "cancelled"
+	_ = repo.Save(ctx, cancelled)
+
+	// Act
+	first, firstErr := repo.ListByStatus(ctx, model.ShipmentStatusBooked, "", 3)
+	second, secondErr := repo.ListByStatus(ctx, model.ShipmentStatusBooked, first[len(first)-1].ID, 3)
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	var ids []string
+	for _, shipment := range append(first, second...) {
+		ids = append(ids, shipment.ID)
+	}
+	assert.Equal(t, []string{"s1", "s2", "s3", "s4"}, ids)
+}
+
+func TestMemoryShipmentRepository_ListBookedBetween(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryShipmentRepository()
+	day := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	for i, bookedAt := range []time.Time{day.Add(-time.Second), day, day.Add(12 * time.Hour), day.Add(24*time.Hour - time.Second), day.Add(24 * time.Hour)} {
+		shipment := newTestShipment("s"+strconv.Itoa(i), "q"+strconv.Itoa(i))
+		shipment.BookedAt = bookedAt
+		_ = repo.Save(ctx, shipment)
+	}
+	cancelled := newTestShipment("s9", "q9")
+	cancelled.Status = "cancelled"
+	_ = repo.Save(ctx, cancelled)
+
+	// Act
+	first, firstErr := repo.ListBookedBetween(ctx, model.ShipmentStatusBooked, day, day.Add(24*time.Hour), "", 2)
+	second, secondErr := repo.ListBookedBetween(ctx, model.ShipmentStatusBooked, day, day.Add(24*time.Hour), first[len(first)-1].ID, 2)
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	var ids []string
+	for _, shipment := range append(first, second...) {
+		ids = append(ids, shipment.ID)
+	}
+	assert.Equal(t, []string{"s1", "s2", "s3"}, ids)
+}
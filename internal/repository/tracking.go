@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+)
+
+// TrackingRepository defines the contract for the tracking history of shipments. Carriers may
+// report the same event more than once (webhook retries, overlapping polls), so appending an
+// event already stored is a no-op
+type TrackingRepository interface {
+	// Append stores the events of a shipment, returning how many of them were new
+	Append(ctx context.Context, shipmentID string, events []model.TrackingEvent) (int, error)
+	// List returns the events of a shipment ordered by OccurredAt, oldest first; a shipment
+	// without events has an empty history
+	List(ctx context.Context, shipmentID string) ([]model.TrackingEvent, error)
+}
+
+// MemoryTrackingRepository is an in-memory TrackingRepository, safe for concurrent use
+type MemoryTrackingRepository struct {
+	mu     sync.RWMutex
+	events map[string][]model.TrackingEvent
+}
+
+// NewMemoryTrackingRepository creates an empty in-memory tracking repository
+func NewMemoryTrackingRepository() *MemoryTrackingRepository {
+	return &MemoryTrackingRepository{events: make(map[string][]model.TrackingEvent)}
+}
+
+// Append implements TrackingRepository
+func (r *MemoryTrackingRepository) Append(ctx context.Context, shipmentID string, events []model.TrackingEvent) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	history := r.events[shipmentID]
+	added := 0
+	for _, event := range events {
+		if containsEvent(history, event) {
+			continue
+		}
+		history = append(history, event)
+		added++
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].OccurredAt.Before(history[j].OccurredAt) })
+	r.events[shipmentID] = history
+	return added, nil
+}
+
+// List implements TrackingRepository
+func (r *MemoryTrackingRepository) List(ctx context.Context, shipmentID string) ([]model.TrackingEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]model.TrackingEvent{}, r.events[shipmentID]...), nil
+}
+
+// containsEvent reports whether history already has the event
+func containsEvent(history []model.TrackingEvent, event model.TrackingEvent) bool {
+	for _, stored := range history {
+		if stored.Status == event.Status && stored.OccurredAt.Equal(event.OccurredAt) &&
+			stored.Location == event.Location && stored.Description == event.Description {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	postedEvent = model.TrackingEvent{
+		Status:     model.TrackingStatusPosted,
+		Location:   "São Paulo/SP",
+		OccurredAt: time.Date(2025, 1, 10, 15, 0, 0, 0, time.UTC),
+	}
+	inTransitEvent = model.TrackingEvent{
+		Status:     model.TrackingStatusInTransit,
+		Location:   "Rio de Janeiro/RJ",
+		OccurredAt: time.Date(2025, 1, 11, 8, 0, 0, 0, time.UTC),
+	}
+)
+
+func TestMemoryTrackingRepository_AppendAndList(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryTrackingRepository()
+
+	// Act
+	added, err := repo.Append(ctx, "s1", []model.TrackingEvent{inTransitEvent, postedEvent})
+	events, listErr := repo.List(ctx, "s1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, listErr)
+	assert.Equal(t, 2, added)
+	assert.Equal(t, []model.TrackingEvent{postedEvent, inTransitEvent}, events, "events are ordered by occurrence")
+}
+
+func TestMemoryTrackingRepository_Append_IgnoresDuplicates(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryTrackingRepository()
+	_, _ = repo.Append(ctx, "s1", []model.TrackingEvent{postedEvent})
+	redelivered := postedEvent
+	redelivered.OccurredAt = postedEvent.OccurredAt.In(time.FixedZone("BRT", -3*60*60))
+
+	// Act
+	added, err := repo.Append(ctx, "s1", []model.TrackingEvent{redelivered, inTransitEvent})
+	events, _ := repo.List(ctx, "s1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, added)
+	assert.Len(t, events, 2)
+}
+
+func TestMemoryTrackingRepository_List_Empty(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryTrackingRepository()
+	_, _ = repo.Append(ctx, "s1", []model.TrackingEvent{postedEvent})
+
+	// Act
+	events, err := repo.List(ctx, "s2")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestMemoryTrackingRepository_List_ReturnsCopy(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryTrackingRepository()
+	_, _ = repo.Append(ctx, "s1", []model.TrackingEvent{postedEvent})
+
+	// Act
+	events, _ := repo.List(ctx, "s1")
+	events[0].Status = model.TrackingStatusDelivered
+	stored, _ := repo.List(ctx, "s1")
+
+	// Assert
+	assert.Equal(t, model.TrackingStatusPosted, stored[0].Status)
+}
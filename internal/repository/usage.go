@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+)
+
+// UsageRepository defines the contract for the persistence of the daily quote usage of tenants.
+// Days are calendar days in UTC
+type UsageRepository interface {
+	// Increment counts quotes quote requests of the tenant and API key on the day of at
+	Increment(ctx context.Context, tenantID, apiKey string, quotes int64, at time.Time) error
+	// Daily returns the usage of the days from from to to, inclusive, sorted by day, tenant and
+	// API key; an empty tenantID returns every tenant
+	Daily(ctx context.Context, tenantID string, from, to time.Time) ([]model.UsageRecord, error)
+	// Total returns the quote requests of the tenant, with any API key, from from to to, inclusive
+	Total(ctx context.Context, tenantID string, from, to time.Time) (int64, error)
+}
+
+// usageKey identifies the counter of a tenant and API key on a day
+type usageKey struct {
+	day    string
+	tenant string
+	apiKey string
+}
+
+// MemoryUsageRepository is an in-memory UsageRepository, safe for concurrent use. Counts are
+// kept per instance and lost on restart
+type MemoryUsageRepository struct {
+	mu     sync.RWMutex
+	counts map[usageKey]int64
+}
+
+// NewMemoryUsageRepository creates an empty in-memory usage repository
+func NewMemoryUsageRepository() *MemoryUsageRepository {
+	return &MemoryUsageRepository{counts: make(map[usageKey]int64)}
+}
+
+// Increment counts quotes quote requests of the tenant and API key on the day of at
+func (r *MemoryUsageRepository) Increment(ctx context.Context, tenantID, apiKey string, quotes int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counts[usageKey{day: at.UTC().Format(model.DayLayout), tenant: tenantID, apiKey: apiKey}] += quotes
+	return nil
+}
+
+// Daily returns the usage of the days from from to to, inclusive
+func (r *MemoryUsageRepository) Daily(ctx context.Context, tenantID string, from, to time.Time) ([]model.UsageRecord, error) {
+	first, last := from.UTC().Format(model.DayLayout), to.UTC().Format(model.DayLayout)
+	r.mu.RLock()
+	records := make([]model.UsageRecord, 0)
+	for key, quotes := range r.counts {
+		if key.day < first || key.day > last || tenantID != "" && key.tenant != tenantID {
+			continue
+		}
+		records = append(records, model.UsageRecord{Day: key.day, Tenant: key.tenant, APIKey: key.apiKey, Quotes: quotes})
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Tenant != b.Tenant {
+			return a.Tenant < b.Tenant
+		}
+		return a.APIKey < b.APIKey
+	})
+	return records, nil
+}
+
+// Total returns the quote requests of the tenant from from to to, inclusive
+func (r *MemoryUsageRepository) Total(ctx context.Context, tenantID string, from, to time.Time) (int64, error) {
+	records, err := r.Daily(ctx, tenantID, from, to)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, record := range records {
+		total += record.Quotes
+	}
+	return total, nil
+}
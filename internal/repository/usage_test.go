@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryUsageRepository(t *testing.T) {
+	// Arrange
+	repo := NewMemoryUsageRepository()
+	ctx := context.Background()
+	day := time.Date(2025, 3, 10, 23, 30, 0, 0, time.UTC)
+	_ = repo.Increment(ctx, "acme", "sha256:abc", 1, day)
+	_ = repo.Increment(ctx, "acme", "sha256:abc", 1, day.Add(10*time.Minute))
+	_ = repo.Increment(ctx, "acme", "", 1, day.Add(time.Hour))
+	_ = repo.Increment(ctx, "globex", "", 1, day)
+	_ = repo.Increment(ctx, "acme", "", 1, day.AddDate(0, 0, -30))
+
+	// Act
+	all, allErr := repo.Daily(ctx, "", day, day.AddDate(0, 0, 1))
+	acme, acmeErr := repo.Daily(ctx, "acme", day.AddDate(0, 0, 1), day.AddDate(0, 0, 1))
+	total, totalErr := repo.Total(ctx, "acme", day.AddDate(0, 0, -9), day.AddDate(0, 0, 1))
+
+	// Assert
+	assert.NoError(t, allErr)
+	assert.NoError(t, acmeErr)
+	assert.NoError(t, totalErr)
+	assert.Equal(t, []model.UsageRecord{
+		{Day: "2025-03-10", Tenant: "acme", APIKey: "sha256:abc", Quotes: 2},
+		{Day: "2025-03-10", Tenant: "globex", Quotes: 1},
+		{Day: "2025-03-11", Tenant: "acme", Quotes: 1},
+	}, all)
+	assert.Equal(t, []model.UsageRecord{{Day: "2025-03-11", Tenant: "acme", Quotes: 1}}, acme)
+	assert.Equal(t, int64(3), total)
+}
+
+func TestMemoryUsageRepository_Empty(t *testing.T) {
+	// Arrange
+	repo := NewMemoryUsageRepository()
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	records, err := repo.Daily(context.Background(), "", day, day)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+	assert.NotNil(t, records)
+}
+
+func TestMemoryUsageRepository_ConcurrentIncrements(t *testing.T) {
+	// Arrange
+	repo := NewMemoryUsageRepository()
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+
+	// Act
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = repo.Increment(context.Background(), "acme", "", 1, day)
+		}()
+	}
+	wg.Wait()
+	total, err := repo.Total(context.Background(), "acme", day, day)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(50), total)
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+)
+
+// WebhookRepository defines the contract for the persistence of the merchant webhook subscriptions
+// and of the events that could not be delivered to them
+type WebhookRepository interface {
+	Save(ctx context.Context, subscription *model.WebhookSubscription) error
+	// Delete removes a subscription of the tenant, or returns ErrNotFound
+	Delete(ctx context.Context, tenantID, id string) error
+	// ListByTenant returns the subscriptions of the tenant, ordered by creation
+	ListByTenant(ctx context.Context, tenantID string) ([]model.WebhookSubscription, error)
+	AddDeadLetter(ctx context.Context, letter *model.WebhookDeadLetter) error
+	// ListDeadLetters returns up to limit dead letters of the tenant, or of every tenant when
+	// tenantID is empty, the most recent first
+	ListDeadLetters(ctx context.Context, tenantID string, limit int) ([]model.WebhookDeadLetter, error)
+}
+
+// MemoryWebhookRepository is an in-memory WebhookRepository, safe for concurrent use
+type MemoryWebhookRepository struct {
+	mu            sync.RWMutex
+	subscriptions map[string]model.WebhookSubscription
+	deadLetters   []model.WebhookDeadLetter
+}
+
+// NewMemoryWebhookRepository creates an empty in-memory webhook repository
+func NewMemoryWebhookRepository() *MemoryWebhookRepository {
+	return &MemoryWebhookRepository{subscriptions: make(map[string]model.WebhookSubscription)}
+}
+
+// Save stores a copy of the subscription, replacing any subscription with the same ID
+func (r *MemoryWebhookRepository) Save(ctx context.Context, subscription *model.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *subscription
+	stored.Events = append([]string{}, subscription.Events...)
+	r.subscriptions[subscription.ID] = stored
+	return nil
+}
+
+// Delete removes a subscription of the tenant
+func (r *MemoryWebhookRepository) Delete(ctx context.Context, tenantID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subscription, ok := r.subscriptions[id]
+	if !ok || subscription.Tenant != tenantID {
+		return ErrNotFound
+	}
+	delete(r.subscriptions, id)
+	return nil
+}
+
+// ListByTenant returns copies of the subscriptions of the tenant, ordered by creation and then ID
+func (r *MemoryWebhookRepository) ListByTenant(ctx context.Context, tenantID string) ([]model.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	subscriptions := make([]model.WebhookSubscription, 0)
+	for _, subscription := range r.subscriptions {
+		if subscription.Tenant == tenantID {
+			subscription.Events = append([]string{}, subscription.Events...)
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if !subscriptions[i].CreatedAt.Equal(subscriptions[j].CreatedAt) {
+			return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+		}
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+	return subscriptions, nil
+}
+
+// AddDeadLetter stores a copy of the dead letter
+func (r *MemoryWebhookRepository) AddDeadLetter(ctx context.Context, letter *model.WebhookDeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *letter
+	stored.Event = append(json.RawMessage{}, letter.Event...)
+	r.deadLetters = append(r.deadLetters, stored)
+	return nil
+}
+
+// ListDeadLetters returns copies of up to limit dead letters, the most recently added first
+func (r *MemoryWebhookRepository) ListDeadLetters(ctx context.Context, tenantID string, limit int) ([]model.WebhookDeadLetter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	letters := make([]model.WebhookDeadLetter, 0, min(limit, len(r.deadLetters)))
+	for i := len(r.deadLetters) - 1; i >= 0 && len(letters) < limit; i-- {
+		letter := r.deadLetters[i]
+		if tenantID != "" && letter.Tenant != tenantID {
+			continue
+		}
+		letter.Event = append(json.RawMessage{}, letter.Event...)
+		letters = append(letters, letter)
+	}
+	return letters, nil
+}
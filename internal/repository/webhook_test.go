@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryWebhookRepository_Subscriptions(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryWebhookRepository()
+	createdAt := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	first := &model.WebhookSubscription{ID: "w2", Tenant: "acme", URL: "https://acme.example.com/hooks", Events: []string{"tracking.updated"}, Secret: "s1", CreatedAt: createdAt}
+	second := &model.WebhookSubscription{ID: "w1", Tenant: "acme", URL: "https://acme.example.com/quotes", Events: []string{"quote.created"}, Secret: "s2", CreatedAt: createdAt.Add(time.Minute)}
+	other := &model.WebhookSubscription{ID: "w3", Tenant: "globex", URL: "https://globex.example.com/hooks", Events: []string{"shipment.booked"}, Secret: "s3", CreatedAt: createdAt}
+
+	// Act
+	for _, subscription := range []*model.WebhookSubscription{first, second, other} {
+		assert.NoError(t, repo.Save(ctx, subscription))
+	}
+	first.Events[0] = "changed"
+	listed, listErr := repo.ListByTenant(ctx, "acme")
+	otherTenantErr := repo.Delete(ctx, "acme", "w3")
+	deleteErr := repo.Delete(ctx, "acme", "w2")
+	afterDelete, _ := repo.ListByTenant(ctx, "acme")
+
+	// Assert
+	assert.NoError(t, listErr)
+	assert.Len(t, listed, 2)
+	assert.Equal(t, "w2", listed[0].ID, "subscriptions are listed by creation")
+	assert.Equal(t, []string{"tracking.updated"}, listed[0].Events, "the stored subscription is a copy")
+	assert.ErrorIs(t, otherTenantErr, ErrNotFound, "subscriptions of other tenants are not deleted")
+	assert.NoError(t, deleteErr)
+	assert.Len(t, afterDelete, 1)
+	assert.Equal(t, "w1", afterDelete[0].ID)
+}
+
+func TestMemoryWebhookRepository_DeadLetters(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewMemoryWebhookRepository()
+	for _, letter := range []model.WebhookDeadLetter{
+		{ID: "d1", Tenant: "acme", EventID: "e1", Event: json.RawMessage(`{"id":"e1"}`)},
+		{ID: "d2", Tenant: "globex", EventID: "e2", Event: json.RawMessage(`{"id":"e2"}`)},
+		{ID: "d3", Tenant: "acme", EventID: "e3", Event: json.RawMessage(`{"id":"e3"}`)},
+	} {
+		assert.NoError(t, repo.AddDeadLetter(ctx, &letter))
+	}
+
+	// Act
+	all, allErr := repo.ListDeadLetters(ctx, "", 10)
+	acme, acmeErr := repo.ListDeadLetters(ctx, "acme", 10)
+	latest, latestErr := repo.ListDeadLetters(ctx, "", 1)
+
+	// Assert
+	assert.NoError(t, allErr)
+	assert.Equal(t, []string{"d3", "d2", "d1"}, deadLetterIDs(all))
+	assert.NoError(t, acmeErr)
+	assert.Equal(t, []string{"d3", "d1"}, deadLetterIDs(acme))
+	assert.NoError(t, latestErr)
+	assert.Equal(t, []string{"d3"}, deadLetterIDs(latest))
+	assert.JSONEq(t, `{"id":"e3"}`, string(latest[0].Event))
+}
+
+func deadLetterIDs(letters []model.WebhookDeadLetter) []string {
+	ids := make([]string, 0, len(letters))
+	for _, letter := range letters {
+		ids = append(ids, letter.ID)
+	}
+	return ids
+}
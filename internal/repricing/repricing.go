@@ -0,0 +1,230 @@
+// Package repricing reprices the booked shipments with the pricing configuration in force on a
+// schedule, reporting the shipments whose price drifted from the booked one, e.g. after a contract
+// renegotiation or a pricing reload.
+package repricing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/scheduler"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"go.uber.org/zap"
+)
+
+// reportSubject is the subject of the RepricingCompleted events
+const reportSubject = "shipments"
+
+// Config configures the scheduled repricing
+type Config struct {
+	// Schedule is when the shipments are repriced; nil disables the job
+	Schedule *scheduler.Schedule
+	// BatchSize is how many shipments are loaded at a time
+	BatchSize int
+}
+
+// Enabled reports whether the shipments are repriced
+func (c Config) Enabled() bool {
+	return c.Schedule != nil
+}
+
+// ConfigFromEnv reads REPRICING_SCHEDULE (a cron expression in UTC, default none) and
+// REPRICING_BATCH_SIZE (default 100)
+func ConfigFromEnv() (Config, error) {
+	batchSize, err := config.Int("REPRICING_BATCH_SIZE", 100)
+	if err != nil {
+		return Config{}, err
+	}
+	if batchSize <= 0 {
+		return Config{}, fmt.Errorf("REPRICING_BATCH_SIZE must be positive, got %d", batchSize)
+	}
+	cfg := Config{BatchSize: batchSize}
+	if expr := config.String("REPRICING_SCHEDULE", ""); expr != "" {
+		schedule, err := scheduler.Parse(expr)
+		if err != nil {
+			return Config{}, fmt.Errorf("REPRICING_SCHEDULE: %w", err)
+		}
+		cfg.Schedule = &schedule
+	}
+	return cfg, nil
+}
+
+// Difference is the price of a booked shipment with the pricing configuration in force, published
+// with a ShipmentRepriced event when it differs from the booked price
+type Difference struct {
+	ShipmentID string       `json:"shipment_id"`
+	QuoteID    string       `json:"quote_id"`
+	Tenant     string       `json:"tenant"`
+	Service    string       `json:"service"`
+	Currency   string       `json:"currency,omitempty"`
+	BookedCost money.Amount `json:"booked_cost"`
+	// CurrentCost is the price with the pricing in force; zero when Available is false
+	CurrentCost money.Amount `json:"current_cost"`
+	// Difference is CurrentCost minus BookedCost
+	Difference            money.Amount `json:"difference"`
+	BookedPricingVersion  string       `json:"booked_pricing_version,omitempty"`
+	CurrentPricingVersion string       `json:"current_pricing_version,omitempty"`
+	// Available is false when the service of the shipment is no longer offered for its route and
+	// package
+	Available  bool      `json:"available"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// Report summarizes a repricing run, published with a RepricingCompleted event
+type Report struct {
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	// Shipments is how many booked shipments were checked
+	Shipments int `json:"shipments"`
+	// Changed is how many are priced differently, including those no longer available
+	Changed     int `json:"changed"`
+	Unavailable int `json:"unavailable"`
+	// Skipped is how many could not be repriced because they were booked before their package was
+	// recorded
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// Job reprices the booked shipments
+type Job struct {
+	cfg        Config
+	shipments  repository.ShipmentRepository
+	calculator service.ShippingServiceInterface
+	publisher  events.Publisher
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// New creates a repricing job pricing the shipments with calculator, with their package and
+// tenant, and publishing the differences to publisher
+func New(cfg Config, shipments repository.ShipmentRepository, calculator service.ShippingServiceInterface, publisher events.Publisher, logger *zap.Logger) *Job {
+	return &Job{
+		cfg:        cfg,
+		shipments:  shipments,
+		calculator: calculator,
+		publisher:  publisher,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Run reprices every booked shipment as a dry run and publishes a ShipmentRepriced event for each
+// price difference and a RepricingCompleted event with the report. Shipments failing to be
+// repriced are logged and counted; only a failure to list the shipments stops the run
+func (j *Job) Run(ctx context.Context) (Report, error) {
+	report := Report{StartedAt: j.now().UTC()}
+	afterID := ""
+	for {
+		page, err := j.shipments.ListByStatus(ctx, model.ShipmentStatusBooked, afterID, j.cfg.BatchSize)
+		if err != nil {
+			return report, fmt.Errorf("failed to list booked shipments: %w", err)
+		}
+		for i := range page {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			j.reprice(ctx, &page[i], &report)
+		}
+		if len(page) < j.cfg.BatchSize {
+			break
+		}
+		afterID = page[len(page)-1].ID
+	}
+	report.CompletedAt = j.now().UTC()
+
+	j.logger.Info("Shipments repriced",
+		zap.Int("shipments", report.Shipments),
+		zap.Int("changed", report.Changed),
+		zap.Int("unavailable", report.Unavailable),
+		zap.Int("skipped", report.Skipped),
+		zap.Int("failed", report.Failed),
+	)
+	j.publish(ctx, events.RepricingCompleted, reportSubject, report.CompletedAt, report)
+	return report, nil
+}
+
+// reprice prices a shipment with the pricing in force and publishes its difference, if any
+func (j *Job) reprice(ctx context.Context, shipment *model.Shipment, report *Report) {
+	report.Shipments++
+	if shipment.Package.OriginZipcode == "" {
+		report.Skipped++
+		return
+	}
+
+	tenantID := shipment.Tenant
+	if tenantID == "" {
+		tenantID = tenant.Default
+	}
+	dryRun, _ := service.WithDryRun(tenant.NewContext(ctx, tenantID))
+	response, err := j.calculator.CalculateShipping(dryRun, repricingRequest(shipment))
+	if err != nil {
+		report.Failed++
+		j.logger.Warn("Failed to reprice shipment", zap.String("shipment_id", shipment.ID), zap.Error(err))
+		return
+	}
+
+	difference := Difference{
+		ShipmentID:            shipment.ID,
+		QuoteID:               shipment.QuoteID,
+		Tenant:                tenantID,
+		Service:               shipment.Service,
+		Currency:              shipment.Currency,
+		BookedCost:            shipment.Cost,
+		BookedPricingVersion:  shipment.PricingVersion,
+		CurrentPricingVersion: response.PricingVersion,
+		DetectedAt:            j.now().UTC(),
+	}
+	for _, option := range response.ShippingOptions {
+		if option.Service == shipment.Service {
+			difference.Available = true
+			difference.CurrentCost = option.Cost
+			difference.Difference = option.Cost - shipment.Cost
+		}
+	}
+	if difference.Available && difference.Difference == 0 {
+		return
+	}
+
+	report.Changed++
+	if !difference.Available {
+		report.Unavailable++
+	}
+	j.publish(ctx, events.ShipmentRepriced, shipment.ID, difference.DetectedAt, difference)
+}
+
+// repricingRequest is the quote request of the package of a shipment. It does not select the
+// service level, so that a level no longer offered is reported as unavailable
+func repricingRequest(shipment *model.Shipment) *model.CalculateShippingRequest {
+	pkg := shipment.Package
+	return &model.CalculateShippingRequest{
+		OriginZipcode:      pkg.OriginZipcode,
+		DestinationZipcode: pkg.DestinationZipcode,
+		DestinationCountry: pkg.DestinationCountry,
+		Weight:             pkg.Weight,
+		Dimensions:         pkg.Dimensions,
+		Currency:           shipment.Currency,
+		PackageType:        pkg.PackageType,
+		DeliveryType:       pkg.DeliveryType,
+		AdditionalServices: pkg.AdditionalServices,
+		PricingStrategy:    pkg.PricingStrategy,
+		IsReturn:           pkg.IsReturn,
+		FreightClass:       pkg.FreightClass,
+		WeightUnit:         pkg.WeightUnit,
+		DimensionUnit:      pkg.DimensionUnit,
+	}
+}
+
+// publish publishes an event, logging failures: the report is kept in the logs regardless
+func (j *Job) publish(ctx context.Context, eventType, subject string, at time.Time, data any) {
+	err := j.publisher.Publish(ctx, events.Event{Type: eventType, Subject: subject, OccurredAt: at, Data: data})
+	if err != nil {
+		j.logger.Warn("Failed to publish repricing event", zap.String("event_type", eventType), zap.String("subject", subject), zap.Error(err))
+	}
+}
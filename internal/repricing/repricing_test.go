@@ -0,0 +1,205 @@
+package repricing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// routeCalculator prices each destination zipcode at a fixed standard cost, recording the tenants
+// and dry runs; destinations without a cost fail
+type routeCalculator struct {
+	costs   map[string]money.Amount
+	tenants []string
+	dryRuns []bool
+}
+
+func (c *routeCalculator) CalculateShipping(ctx context.Context, req *model.CalculateShippingRequest) (*model.CalculateShippingResponse, error) {
+	c.tenants = append(c.tenants, tenant.FromContext(ctx))
+	c.dryRuns = append(c.dryRuns, service.IsDryRun(ctx))
+	cost, ok := c.costs[req.DestinationZipcode]
+	if !ok {
+		return nil, errors.New("route not serviceable")
+	}
+	return &model.CalculateShippingResponse{
+		PricingVersion:  "2025.04",
+		ShippingCost:    cost,
+		ShippingOptions: []model.ShippingOption{{Service: model.ServiceStandard, Cost: cost}},
+	}, nil
+}
+
+// book stores a shipment of the tenant to destination booked at cost, whose quote is no longer stored
+func book(t *testing.T, shipments repository.ShipmentRepository, id, tenantID, destination, service string, cost money.Amount) {
+	t.Helper()
+	assert.NoError(t, shipments.Save(context.Background(), &model.Shipment{
+		ID:             id,
+		QuoteID:        "q-" + id,
+		Status:         model.ShipmentStatusBooked,
+		Service:        service,
+		Tenant:         tenantID,
+		Currency:       "BRL",
+		Cost:           cost,
+		PricingVersion: "2025.03",
+		Package:        model.ShipmentPackage{OriginZipcode: "01310100", DestinationZipcode: destination, Weight: 1},
+	}))
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name         string
+		env          map[string]string
+		wantEnabled  bool
+		wantBatch    int
+		wantSchedule string
+		wantErr      string
+	}{
+		{"defaults", map[string]string{}, false, 100, "", ""},
+		{"scheduled", map[string]string{"REPRICING_SCHEDULE": "0 3 * * *", "REPRICING_BATCH_SIZE": "500"}, true, 500, "0 3 * * *", ""},
+		{"invalid schedule", map[string]string{"REPRICING_SCHEDULE": "every night"}, false, 0, "", "REPRICING_SCHEDULE"},
+		{"invalid batch size", map[string]string{"REPRICING_BATCH_SIZE": "0"}, false, 0, "", "REPRICING_BATCH_SIZE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for _, key := range []string{"REPRICING_SCHEDULE", "REPRICING_BATCH_SIZE"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantEnabled, cfg.Enabled())
+			assert.Equal(t, tt.wantBatch, cfg.BatchSize)
+			if tt.wantEnabled {
+				assert.Equal(t, tt.wantSchedule, cfg.Schedule.String())
+			}
+		})
+	}
+}
+
+func TestJob_Run(t *testing.T) {
+	// Arrange
+	shipments := repository.NewMemoryShipmentRepository()
+	book(t, shipments, "s1", "", "20040020", model.ServiceStandard, money.FromMinor(1500))
+	book(t, shipments, "s2", "acme", "30130000", model.ServiceStandard, money.FromMinor(2000))
+	book(t, shipments, "s3", "", "20040020", model.ServiceExpress, money.FromMinor(2500))
+	book(t, shipments, "s4", "", "99999999", model.ServiceStandard, money.FromMinor(1000))
+	// Booked before the package of the shipments was recorded
+	assert.NoError(t, shipments.Save(context.Background(), &model.Shipment{ID: "s5", QuoteID: "q-s5", Status: model.ShipmentStatusBooked}))
+	calculator := &routeCalculator{costs: map[string]money.Amount{"20040020": money.FromMinor(1500), "30130000": money.FromMinor(2150)}}
+	publisher := events.NewMemoryPublisher()
+	// Pages of two shipments
+	job := New(Config{BatchSize: 2}, shipments, calculator, publisher, zaptest.NewLogger(t))
+	now := time.Date(2025, 4, 1, 3, 0, 0, 0, time.UTC)
+	job.now = func() time.Time { return now }
+
+	// Act
+	report, err := job.Run(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Report{StartedAt: now, CompletedAt: now, Shipments: 5, Changed: 2, Unavailable: 1, Skipped: 1, Failed: 1}, report)
+	assert.Equal(t, []string{tenant.Default, "acme", tenant.Default, tenant.Default}, calculator.tenants, "shipments are repriced with their tenant")
+	assert.Equal(t, []bool{true, true, true, true}, calculator.dryRuns, "repriced quotes are not persisted")
+
+	published := publisher.Events()
+	if assert.Len(t, published, 3) {
+		assert.Equal(t, events.ShipmentRepriced, published[0].Type)
+		assert.Equal(t, "s2", published[0].Subject)
+		assert.Equal(t, Difference{
+			ShipmentID:            "s2",
+			QuoteID:               "q-s2",
+			Tenant:                "acme",
+			Service:               model.ServiceStandard,
+			Currency:              "BRL",
+			BookedCost:            money.FromMinor(2000),
+			CurrentCost:           money.FromMinor(2150),
+			Difference:            money.FromMinor(150),
+			BookedPricingVersion:  "2025.03",
+			CurrentPricingVersion: "2025.04",
+			Available:             true,
+			DetectedAt:            now,
+		}, published[0].Data)
+
+		assert.Equal(t, "s3", published[1].Subject)
+		assert.False(t, published[1].Data.(Difference).Available, "express is no longer offered")
+
+		assert.Equal(t, events.RepricingCompleted, published[2].Type)
+		assert.Equal(t, report, published[2].Data)
+	}
+}
+
+func TestJob_Run_ListFailure(t *testing.T) {
+	// Arrange
+	job := New(Config{BatchSize: 10}, failingShipmentRepository{}, &routeCalculator{}, events.NewMemoryPublisher(), zaptest.NewLogger(t))
+
+	// Act
+	_, err := job.Run(context.Background())
+
+	// Assert
+	assert.ErrorContains(t, err, "failed to list booked shipments")
+}
+
+// failingShipmentRepository is a ShipmentRepository whose reads always fail
+type failingShipmentRepository struct {
+	repository.ShipmentRepository
+}
+
+func (failingShipmentRepository) ListByStatus(ctx context.Context, status, afterID string, limit int) ([]model.Shipment, error) {
+	return nil, errors.New("database unavailable")
+}
+
+func TestRepricingRequest(t *testing.T) {
+	// Arrange
+	shipment := &model.Shipment{
+		Service:  model.ServiceExpress,
+		Currency: "USD",
+		Package: model.ShipmentPackage{
+			OriginZipcode:      "01310100",
+			DestinationZipcode: "10001",
+			DestinationCountry: "US",
+			Weight:             2.2,
+			Dimensions:         model.PackageDimensions{Length: 10, Width: 8, Height: 4},
+			PackageType:        "fragile",
+			AdditionalServices: []string{"insurance"},
+			IsReturn:           true,
+			WeightUnit:         "lb",
+			DimensionUnit:      "in",
+		},
+	}
+
+	// Act
+	req := repricingRequest(shipment)
+
+	// Assert
+	assert.Equal(t, &model.CalculateShippingRequest{
+		OriginZipcode:      "01310100",
+		DestinationZipcode: "10001",
+		DestinationCountry: "US",
+		Weight:             2.2,
+		Dimensions:         model.PackageDimensions{Length: 10, Width: 8, Height: 4},
+		Currency:           "USD",
+		PackageType:        "fragile",
+		AdditionalServices: []string{"insurance"},
+		IsReturn:           true,
+		WeightUnit:         "lb",
+		DimensionUnit:      "in",
+	}, req)
+}
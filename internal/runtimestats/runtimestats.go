@@ -0,0 +1,90 @@
+// Package runtimestats periodically records the memory, garbage collection and goroutine
+// statistics of the Go runtime as metrics.
+package runtimestats
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+)
+
+// Config sets how often the runtime statistics are recorded
+type Config struct {
+	Interval time.Duration
+}
+
+// ConfigFromEnv reads RUNTIME_STATS_INTERVAL (default 15s)
+func ConfigFromEnv() (Config, error) {
+	interval, err := config.Duration("RUNTIME_STATS_INTERVAL", 15*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if interval <= 0 {
+		return Config{}, fmt.Errorf("RUNTIME_STATS_INTERVAL must be positive, got %s", interval)
+	}
+	return Config{Interval: interval}, nil
+}
+
+// Reporter records the runtime statistics, keeping track of the garbage collections already
+// recorded so that each pause is recorded once. It is not safe for concurrent use
+type Reporter struct {
+	metrics *telemetry.Metrics
+	numGC   uint32
+}
+
+// NewReporter creates a reporter recording in metrics; pauses of collections that ran before it are
+// not recorded
+func NewReporter(metrics *telemetry.Metrics) *Reporter {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return &Reporter{metrics: metrics, numGC: stats.NumGC}
+}
+
+// Report records the heap and non-heap memory in use, the goroutine count and the pauses of the
+// garbage collections since the last report
+func (r *Reporter) Report(ctx context.Context) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	r.metrics.RecordMemoryHeapServer(ctx, int64(stats.HeapAlloc))
+	r.metrics.RecordMemoryNoHeapServer(ctx, int64(stats.Sys-stats.HeapSys))
+	r.metrics.RecordGoroutines(ctx, int64(runtime.NumGoroutine()))
+	for _, pause := range pausesSince(&stats, r.numGC) {
+		r.metrics.RecordGCPause(ctx, int64(pause/uint64(time.Microsecond)))
+	}
+	r.numGC = stats.NumGC
+}
+
+// pausesSince returns the pause times, in nanoseconds, of the collections after the first last
+// ones. The runtime keeps only the latest len(stats.PauseNs) pauses, older ones are lost
+func pausesSince(stats *runtime.MemStats, last uint32) []uint64 {
+	count := stats.NumGC - last
+	if size := uint32(len(stats.PauseNs)); count > size {
+		count = size
+	}
+	pauses := make([]uint64, 0, count)
+	for gc := stats.NumGC - count; gc < stats.NumGC; gc++ {
+		// The pause of the n-th collection (1-based) is at PauseNs[(n+255)%256]
+		pauses = append(pauses, stats.PauseNs[gc%uint32(len(stats.PauseNs))])
+	}
+	return pauses
+}
+
+// Run records the statistics right away and then every interval until ctx is cancelled
+func (r *Reporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.Report(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package runtimestats
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+	"github.com/stretchr/testify/assert"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestPausesSince(t *testing.T) {
+	// Arrange
+	var stats runtime.MemStats
+	for i := range stats.PauseNs {
+		stats.PauseNs[i] = uint64(i)
+	}
+
+	tests := []struct {
+		name  string
+		numGC uint32
+		last  uint32
+		want  []uint64
+	}{
+		{"no collection", 3, 3, []uint64{}},
+		{"new collections", 3, 1, []uint64{1, 2}},
+		{"wraps around the buffer", 258, 255, []uint64{255, 0, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats.NumGC = tt.numGC
+
+			// Act
+			pauses := pausesSince(&stats, tt.last)
+
+			// Assert
+			assert.Equal(t, tt.want, pauses)
+		})
+	}
+}
+
+func TestPausesSince_Overflow(t *testing.T) {
+	// Arrange
+	var stats runtime.MemStats
+	for i := range stats.PauseNs {
+		stats.PauseNs[i] = uint64(i)
+	}
+	stats.NumGC = 600
+
+	// Act
+	pauses := pausesSince(&stats, 0)
+
+	// Assert
+	assert.Len(t, pauses, len(stats.PauseNs))
+	assert.Equal(t, uint64(600%256), pauses[0])
+	assert.Equal(t, uint64(599%256), pauses[len(pauses)-1])
+}
+
+func TestReporter_Report(t *testing.T) {
+	// Arrange
+	reader := sdkmetric.NewManualReader()
+	metrics, err := telemetry.NewMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"))
+	assert.NoError(t, err)
+	reporter := NewReporter(metrics)
+	runtime.GC()
+
+	// Act
+	reporter.Report(context.Background())
+
+	// Assert
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	assert.GreaterOrEqual(t, stats.NumGC, reporter.numGC)
+	assert.NotZero(t, reporter.numGC)
+
+	var collected metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &collected))
+	var names []string
+	for _, scope := range collected.ScopeMetrics {
+		for _, recorded := range scope.Metrics {
+			names = append(names, recorded.Name)
+		}
+	}
+	assert.Contains(t, names, "shipping.calculate.runtime.goroutines")
+	assert.Contains(t, names, "shipping.calculate.runtime.gc.pause")
+}
+
+func TestReporter_Run(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Act
+	go func() {
+		NewReporter(nil).Run(ctx, 5*time.Millisecond)
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	// Assert
+	assert.Eventually(t, func() bool {
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval string
+		want     Config
+		wantErr  string
+	}{
+		{"default", "", Config{Interval: 15 * time.Second}, ""},
+		{"custom", "1m", Config{Interval: time.Minute}, ""},
+		{"zero", "0s", Config{}, "RUNTIME_STATS_INTERVAL must be positive"},
+		{"invalid", "often", Config{}, "RUNTIME_STATS_INTERVAL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("RUNTIME_STATS_INTERVAL", tt.interval)
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
@@ -0,0 +1,286 @@
+// Package schedule validates the pickup windows merchants can schedule for the carrier to collect packages.
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+)
+
+// DateLayout is the format of pickup dates
+const DateLayout = "2006-01-02"
+
+// ErrInvalidPickup is returned when a pickup cannot be scheduled for the requested date and window
+var ErrInvalidPickup = errors.New("invalid pickup")
+
+// ErrWindowFull is returned, wrapped in ErrInvalidPickup, when every pickup slot of a window is
+// booked for the day
+var ErrWindowFull = errors.New("no pickup slots left")
+
+// Bookings counts the pickups booked for each day and window, against the capacity of the windows
+type Bookings interface {
+	// CountPickups returns the number of pickups booked on date ("YYYY-MM-DD") in the named window
+	CountPickups(ctx context.Context, date, window string) (int, error)
+}
+
+// Window is a pickup time slot, e.g. morning from 08:00 to 12:00
+type Window struct {
+	Name string `json:"name"`
+	// Start and End are the local times of the slot, "HH:MM"
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Weekdays are the days ("monday" to "sunday") the window is offered; empty means Monday to Friday
+	Weekdays []string `json:"weekdays,omitempty"`
+	// MaxWeightKg is the largest package the collection vehicle of the window takes; 0 means no limit
+	MaxWeightKg float64 `json:"max_weight_kg,omitempty"`
+	// SurchargeRate is the fraction of the subtotal added for pickups in this window
+	SurchargeRate float64 `json:"surcharge_rate,omitempty"`
+	// Capacity is the number of pickups the window takes each day; 0 means no limit
+	Capacity int `json:"capacity,omitempty"`
+}
+
+// Config holds the pickup windows and the scheduling rules
+type Config struct {
+	Windows []Window `json:"windows"`
+	// UTCOffsetHours is the offset of the local time of the windows (default: -3, Brasília)
+	UTCOffsetHours *int `json:"utc_offset_hours,omitempty"`
+	// CutoffMinutes is how long before the start of a window the pickup must be scheduled
+	CutoffMinutes int `json:"cutoff_minutes"`
+	// MaxAdvanceDays is how many days ahead a pickup can be scheduled
+	MaxAdvanceDays int `json:"max_advance_days"`
+	// SameDaySurchargeRate is the fraction of the subtotal added for pickups scheduled for today
+	SameDaySurchargeRate float64 `json:"same_day_surcharge_rate"`
+}
+
+// DefaultConfig returns morning (08:00-12:00) and afternoon (13:00-18:00) windows on weekdays,
+// a cutoff of 2 hours, up to 14 days ahead and a 15% same-day pickup surcharge
+func DefaultConfig() Config {
+	return Config{
+		Windows: []Window{
+			{Name: "morning", Start: "08:00", End: "12:00"},
+			{Name: "afternoon", Start: "13:00", End: "18:00"},
+		},
+		CutoffMinutes:        120,
+		MaxAdvanceDays:       14,
+		SameDaySurchargeRate: 0.15,
+	}
+}
+
+// Validate checks that every window has a unique name, a valid time range and known weekdays,
+// and that the rules are not negative
+func (c Config) Validate() error {
+	if len(c.Windows) == 0 {
+		return errors.New("at least one window is required")
+	}
+	seen := make(map[string]bool, len(c.Windows))
+	for _, w := range c.Windows {
+		name := strings.ToLower(strings.TrimSpace(w.Name))
+		if name == "" {
+			return errors.New("window name is required")
+		}
+		if seen[name] {
+			return fmt.Errorf("window %q: duplicate name", w.Name)
+		}
+		seen[name] = true
+		start, err := clock(w.Start)
+		if err != nil {
+			return fmt.Errorf("window %q: invalid start: %w", w.Name, err)
+		}
+		end, err := clock(w.End)
+		if err != nil {
+			return fmt.Errorf("window %q: invalid end: %w", w.Name, err)
+		}
+		if end <= start {
+			return fmt.Errorf("window %q: end must be after start", w.Name)
+		}
+		for _, day := range w.Weekdays {
+			if _, ok := weekdays[strings.ToLower(day)]; !ok {
+				return fmt.Errorf("window %q: unknown weekday %q", w.Name, day)
+			}
+		}
+		if w.MaxWeightKg < 0 || w.SurchargeRate < 0 || w.Capacity < 0 {
+			return fmt.Errorf("window %q: max_weight_kg, surcharge_rate and capacity must not be negative", w.Name)
+		}
+	}
+	if c.UTCOffsetHours != nil && (*c.UTCOffsetHours < -12 || *c.UTCOffsetHours > 14) {
+		return errors.New("utc_offset_hours must be between -12 and 14")
+	}
+	if c.CutoffMinutes < 0 || c.MaxAdvanceDays < 0 || c.SameDaySurchargeRate < 0 {
+		return errors.New("cutoff_minutes, max_advance_days and same_day_surcharge_rate must not be negative")
+	}
+	return nil
+}
+
+// LoadConfig reads and validates the pickup windows from a JSON file
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read pickup schedule config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse pickup schedule config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid pickup schedule config: %w", err)
+	}
+	return cfg, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// clock parses "HH:MM" into the time elapsed since midnight
+func clock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Pickup is a scheduled pickup
+type Pickup struct {
+	// Date is the midnight of the pickup day in the local time of the windows
+	Date   time.Time
+	Window Window
+	// SameDay reports whether the pickup is scheduled for today
+	SameDay bool
+}
+
+// SurchargeRate returns the fraction of the subtotal added for the pickup: the surcharge of the
+// window plus, for same-day pickups, the same-day surcharge
+func (p Pickup) SurchargeRate(cfg Config) float64 {
+	rate := p.Window.SurchargeRate
+	if p.SameDay {
+		rate += cfg.SameDaySurchargeRate
+	}
+	return rate
+}
+
+// Scheduler checks pickup requests against the windows, cutoff times and capacity of the configuration
+type Scheduler struct {
+	cfg      Config
+	location *time.Location
+	now      func() time.Time
+	bookings Bookings
+}
+
+// NewScheduler creates a scheduler for the windows of cfg
+func NewScheduler(cfg Config) *Scheduler {
+	return NewSchedulerWithClock(cfg, determinism.SystemClock{})
+}
+
+// NewSchedulerWithClock creates a scheduler for the windows of cfg checking the cutoff times
+// against the time told by clock
+func NewSchedulerWithClock(cfg Config, clock determinism.Clock) *Scheduler {
+	offset := -3
+	if cfg.UTCOffsetHours != nil {
+		offset = *cfg.UTCOffsetHours
+	}
+	return &Scheduler{
+		cfg:      cfg,
+		location: time.FixedZone(fmt.Sprintf("UTC%+d", offset), offset*int(time.Hour/time.Second)),
+		now:      clock.Now,
+	}
+}
+
+// WithBookings makes the scheduler check the capacity of the windows against the pickups counted by
+// bookings; without it the capacity is not checked
+func (s *Scheduler) WithBookings(bookings Bookings) *Scheduler {
+	s.bookings = bookings
+	return s
+}
+
+// Config returns the configuration of the scheduler
+func (s *Scheduler) Config() Config {
+	return s.cfg
+}
+
+// Schedule validates a pickup of a package weighing weightKg on date ("YYYY-MM-DD") in the named
+// window. The date must be between today and MaxAdvanceDays ahead, the window must be offered on
+// that weekday, scheduled at least CutoffMinutes before it starts, able to take the weight and, with
+// bookings, have a slot left on that day. Errors other than ErrInvalidPickup are failures counting
+// the bookings
+func (s *Scheduler) Schedule(ctx context.Context, date, window string, weightKg float64) (Pickup, error) {
+	date, window = strings.TrimSpace(date), strings.ToLower(strings.TrimSpace(window))
+	if date == "" || window == "" {
+		return Pickup{}, fmt.Errorf("%w: pickup_date and pickup_window must be set together", ErrInvalidPickup)
+	}
+	day, err := time.ParseInLocation(DateLayout, date, s.location)
+	if err != nil {
+		return Pickup{}, fmt.Errorf("%w: pickup_date must be YYYY-MM-DD", ErrInvalidPickup)
+	}
+
+	now := s.now().In(s.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location)
+	if day.Before(today) {
+		return Pickup{}, fmt.Errorf("%w: pickup_date %s is in the past", ErrInvalidPickup, date)
+	}
+	if day.After(today.AddDate(0, 0, s.cfg.MaxAdvanceDays)) {
+		return Pickup{}, fmt.Errorf("%w: pickup_date must be at most %d days ahead", ErrInvalidPickup, s.cfg.MaxAdvanceDays)
+	}
+
+	w, ok := s.window(window)
+	if !ok {
+		return Pickup{}, fmt.Errorf("%w: unknown pickup_window %q", ErrInvalidPickup, window)
+	}
+	if !offeredOn(w, day.Weekday()) {
+		return Pickup{}, fmt.Errorf("%w: pickup_window %q is not offered on %s", ErrInvalidPickup, w.Name, strings.ToLower(day.Weekday().String()))
+	}
+	start, _ := clock(w.Start)
+	cutoff := day.Add(start - time.Duration(s.cfg.CutoffMinutes)*time.Minute)
+	if now.After(cutoff) {
+		return Pickup{}, fmt.Errorf("%w: the cutoff for the %s window on %s was %s", ErrInvalidPickup, w.Name, date, cutoff.Format("15:04"))
+	}
+	if w.MaxWeightKg > 0 && weightKg > w.MaxWeightKg {
+		return Pickup{}, fmt.Errorf("%w: pickup_window %q takes packages up to %g kg", ErrInvalidPickup, w.Name, w.MaxWeightKg)
+	}
+	if w.Capacity > 0 && s.bookings != nil {
+		booked, err := s.bookings.CountPickups(ctx, date, w.Name)
+		if err != nil {
+			return Pickup{}, fmt.Errorf("failed to count the pickups of the %s window on %s: %w", w.Name, date, err)
+		}
+		if booked >= w.Capacity {
+			return Pickup{}, fmt.Errorf("%w: pickup_window %q on %s: %w", ErrInvalidPickup, w.Name, date, ErrWindowFull)
+		}
+	}
+
+	return Pickup{Date: day, Window: w, SameDay: day.Equal(today)}, nil
+}
+
+// window returns the window with the name, case-insensitively
+func (s *Scheduler) window(name string) (Window, bool) {
+	for _, w := range s.cfg.Windows {
+		if strings.ToLower(strings.TrimSpace(w.Name)) == name {
+			return w, true
+		}
+	}
+	return Window{}, false
+}
+
+// offeredOn reports whether the window is offered on a weekday
+func offeredOn(w Window, day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return day != time.Saturday && day != time.Sunday
+	}
+	for _, name := range w.Weekdays {
+		if weekdays[strings.ToLower(name)] == day {
+			return true
+		}
+	}
+	return false
+}
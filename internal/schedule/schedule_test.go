@@ -0,0 +1,245 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 2025-01-06 is a Monday; 09:00 in Brasília
+var monday = time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)
+
+func newTestScheduler(cfg Config, now time.Time) *Scheduler {
+	s := NewScheduler(cfg)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.Windows = append(cfg.Windows,
+		Window{Name: "Evening", Start: "18:00", End: "21:00", Weekdays: []string{"Monday", "saturday"}, MaxWeightKg: 10, SurchargeRate: 0.05},
+	)
+	return cfg
+}
+
+func TestSchedule(t *testing.T) {
+	brasilia := time.FixedZone("UTC-3", -3*60*60)
+	tests := []struct {
+		name        string
+		date        string
+		window      string
+		wantDate    time.Time
+		wantWindow  string
+		wantSameDay bool
+		wantRate    float64
+	}{
+		{"same day before the cutoff", "2025-01-06", "afternoon", time.Date(2025, 1, 6, 0, 0, 0, 0, brasilia), "afternoon", true, 0.15},
+		{"next day", "2025-01-07", "morning", time.Date(2025, 1, 7, 0, 0, 0, 0, brasilia), "morning", false, 0},
+		{"window names are case-insensitive", " 2025-01-11 ", "EVENING", time.Date(2025, 1, 11, 0, 0, 0, 0, brasilia), "Evening", false, 0.05},
+		{"same day with window surcharge", "2025-01-06", "evening", time.Date(2025, 1, 6, 0, 0, 0, 0, brasilia), "Evening", true, 0.20},
+		{"last day ahead", "2025-01-20", "morning", time.Date(2025, 1, 20, 0, 0, 0, 0, brasilia), "morning", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := testConfig()
+			scheduler := newTestScheduler(cfg, monday)
+
+			// Act
+			pickup, err := scheduler.Schedule(context.Background(), tt.date, tt.window, 5)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.True(t, tt.wantDate.Equal(pickup.Date))
+			assert.Equal(t, tt.wantWindow, pickup.Window.Name)
+			assert.Equal(t, tt.wantSameDay, pickup.SameDay)
+			assert.InDelta(t, tt.wantRate, pickup.SurchargeRate(cfg), 1e-9)
+		})
+	}
+}
+
+func TestSchedule_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		date    string
+		window  string
+		weight  float64
+		wantErr string
+	}{
+		{"window without date", "", "morning", 1, "pickup_date and pickup_window must be set together"},
+		{"date without window", "2025-01-07", "", 1, "pickup_date and pickup_window must be set together"},
+		{"invalid date", "07/01/2025", "morning", 1, "pickup_date must be YYYY-MM-DD"},
+		{"past date", "2025-01-05", "morning", 1, "pickup_date 2025-01-05 is in the past"},
+		{"too far ahead", "2025-01-21", "morning", 1, "at most 14 days ahead"},
+		{"unknown window", "2025-01-07", "night", 1, `unknown pickup_window "night"`},
+		{"weekend", "2025-01-11", "morning", 1, `pickup_window "morning" is not offered on saturday`},
+		{"weekday not listed", "2025-01-07", "evening", 1, `pickup_window "Evening" is not offered on tuesday`},
+		{"cutoff passed", "2025-01-06", "morning", 1, "the cutoff for the morning window on 2025-01-06 was 06:00"},
+		{"over the weight limit", "2025-01-06", "evening", 12, `pickup_window "Evening" takes packages up to 10 kg`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			scheduler := newTestScheduler(testConfig(), monday)
+
+			// Act
+			_, err := scheduler.Schedule(context.Background(), tt.date, tt.window, tt.weight)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidPickup)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+// countedBookings counts the pickups of each date and window, failing with err when set
+type countedBookings struct {
+	counts map[string]int
+	err    error
+}
+
+func (b countedBookings) CountPickups(ctx context.Context, date, window string) (int, error) {
+	return b.counts[date+"/"+window], b.err
+}
+
+func TestSchedule_Capacity(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   string
+		bookings Bookings
+		wantErr  error
+	}{
+		{"slots left", "morning", countedBookings{counts: map[string]int{"2025-01-07/morning": 1}}, nil},
+		{"window full", "morning", countedBookings{counts: map[string]int{"2025-01-07/morning": 2}}, ErrWindowFull},
+		{"window without capacity", "afternoon", countedBookings{counts: map[string]int{"2025-01-07/afternoon": 100}}, nil},
+		{"bookings not counted", "morning", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := DefaultConfig()
+			cfg.Windows[0].Capacity = 2
+			scheduler := newTestScheduler(cfg, monday).WithBookings(tt.bookings)
+
+			// Act
+			_, err := scheduler.Schedule(context.Background(), "2025-01-07", tt.window, 1)
+
+			// Assert
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidPickup)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorContains(t, err, `pickup_window "morning" on 2025-01-07`)
+			}
+		})
+	}
+}
+
+func TestSchedule_CountFailure(t *testing.T) {
+	// Arrange
+	cfg := DefaultConfig()
+	cfg.Windows[0].Capacity = 2
+	scheduler := newTestScheduler(cfg, monday).WithBookings(countedBookings{err: errors.New("connection refused")})
+
+	// Act
+	_, err := scheduler.Schedule(context.Background(), "2025-01-07", "morning", 1)
+
+	// Assert
+	assert.ErrorContains(t, err, "connection refused")
+	assert.NotErrorIs(t, err, ErrInvalidPickup)
+}
+
+func TestSchedule_UTCOffset(t *testing.T) {
+	// Arrange
+	cfg := DefaultConfig()
+	offset := 0
+	cfg.UTCOffsetHours = &offset
+	// 02:00 UTC on Tuesday is still Monday in Brasília, but Tuesday in UTC
+	scheduler := newTestScheduler(cfg, time.Date(2025, 1, 7, 2, 0, 0, 0, time.UTC))
+
+	// Act
+	pickup, err := scheduler.Schedule(context.Background(), "2025-01-07", "morning", 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, pickup.SameDay)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	offset := 15
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr string
+	}{
+		{"default", func(c *Config) {}, ""},
+		{"no windows", func(c *Config) { c.Windows = nil }, "at least one window is required"},
+		{"missing name", func(c *Config) { c.Windows[0].Name = " " }, "window name is required"},
+		{"duplicate name", func(c *Config) { c.Windows[1].Name = "Morning" }, `window "Morning": duplicate name`},
+		{"invalid start", func(c *Config) { c.Windows[0].Start = "8h" }, `window "morning": invalid start`},
+		{"end before start", func(c *Config) { c.Windows[0].End = "07:00" }, "end must be after start"},
+		{"unknown weekday", func(c *Config) { c.Windows[0].Weekdays = []string{"funday"} }, `unknown weekday "funday"`},
+		{"negative surcharge", func(c *Config) { c.Windows[0].SurchargeRate = -0.1 }, "must not be negative"},
+		{"negative capacity", func(c *Config) { c.Windows[0].Capacity = -1 }, "must not be negative"},
+		{"invalid offset", func(c *Config) { c.UTCOffsetHours = &offset }, "utc_offset_hours"},
+		{"negative cutoff", func(c *Config) { c.CutoffMinutes = -1 }, "cutoff_minutes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := DefaultConfig()
+			tt.mutate(&cfg)
+
+			// Act
+			err := cfg.Validate()
+
+			// Assert
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "schedule.json")
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(valid, []byte(`{
+		"windows": [{"name": "morning", "start": "09:00", "end": "12:00", "weekdays": ["monday", "saturday"]}],
+		"cutoff_minutes": 60,
+		"max_advance_days": 7,
+		"same_day_surcharge_rate": 0.2
+	}`), 0o600))
+	assert.NoError(t, os.WriteFile(invalid, []byte(`{"windows": []}`), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(valid)
+	_, invalidErr := LoadConfig(invalid)
+	_, missingErr := LoadConfig(filepath.Join(dir, "missing.json"))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Config{
+		Windows:              []Window{{Name: "morning", Start: "09:00", End: "12:00", Weekdays: []string{"monday", "saturday"}}},
+		CutoffMinutes:        60,
+		MaxAdvanceDays:       7,
+		SameDaySurchargeRate: 0.2,
+	}, cfg)
+	assert.ErrorContains(t, invalidErr, "invalid pickup schedule config")
+	assert.ErrorContains(t, missingErr, "failed to read pickup schedule config")
+}
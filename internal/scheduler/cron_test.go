@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr string
+	}{
+		{"empty", "", "expected 5 fields"},
+		{"too few fields", "0 3 * *", "expected 5 fields"},
+		{"unknown macro", "@every", "expected 5 fields"},
+		{"minute out of range", "60 * * * *", "minute field"},
+		{"hour out of range", "0 24 * * *", "hour field"},
+		{"day of month zero", "0 0 0 * *", "day of month field"},
+		{"weekday out of range", "0 0 * * 8", "day of week field"},
+		{"reversed range", "0 10-5 * * *", "invalid range"},
+		{"zero step", "*/0 * * * *", "invalid step"},
+		{"not a number", "a * * * *", "minute field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := Parse(tt.expr)
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// Wednesday, 10:07:30 UTC
+	from := time.Date(2025, 1, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2025, 1, 15, 10, 8, 0, 0, time.UTC)},
+		{"every 15 minutes", "*/15 * * * *", time.Date(2025, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"list of minutes", "5,40 * * * *", time.Date(2025, 1, 15, 10, 40, 0, 0, time.UTC)},
+		{"daily later today", "30 22 * * *", time.Date(2025, 1, 15, 22, 30, 0, 0, time.UTC)},
+		{"daily tomorrow", "0 3 * * *", time.Date(2025, 1, 16, 3, 0, 0, 0, time.UTC)},
+		{"hours with step from a start", "0 1/6 * * *", time.Date(2025, 1, 15, 13, 0, 0, 0, time.UTC)},
+		{"weekdays", "0 3 * * 1-5", time.Date(2025, 1, 16, 3, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 3 * * 7", time.Date(2025, 1, 19, 3, 0, 0, 0, time.UTC)},
+		{"day of month", "0 0 1 * *", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"day of month or weekday", "0 0 20 * 5", time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"next year", "0 0 1 1 *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"macro", "@hourly", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"never", "0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			schedule, err := Parse(tt.expr)
+			assert.NoError(t, err)
+
+			// Act
+			next := schedule.Next(from)
+
+			// Assert
+			assert.Equal(t, tt.want, next)
+			assert.Equal(t, tt.expr, schedule.String())
+		})
+	}
+}
+
+func TestSchedule_Next_ExactActivation(t *testing.T) {
+	// Arrange
+	schedule, _ := Parse("0 3 * * *")
+
+	// Act
+	next := schedule.Next(time.Date(2025, 1, 15, 3, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.Equal(t, time.Date(2025, 1, 16, 3, 0, 0, 0, time.UTC), next, "the activation is after, not at, the given time")
+}
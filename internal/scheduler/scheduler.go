@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// job is a function run on a schedule
+type job struct {
+	name     string
+	schedule Schedule
+	run      func(ctx context.Context) error
+}
+
+// Claimer claims the activations of the jobs, so that the instances sharing it run each one once
+type Claimer interface {
+	// Claim reports whether the activation of job at scheduledAt is claimed by the caller, false
+	// when another instance already claimed it
+	Claim(ctx context.Context, job string, scheduledAt time.Time) (bool, error)
+}
+
+// Scheduler runs jobs on their cron schedules, evaluated in UTC. A job does not overlap with
+// itself: the activations missed while it runs are skipped
+type Scheduler struct {
+	logger  *zap.Logger
+	now     func() time.Time
+	jobs    []job
+	claimer Claimer
+}
+
+// New creates a scheduler without jobs
+func New(logger *zap.Logger) *Scheduler {
+	return &Scheduler{logger: logger, now: time.Now}
+}
+
+// WithClaimer makes the scheduler run only the activations it claims from claimer, e.g. shared by
+// the replicas of the service so that each activation runs on one of them
+func (s *Scheduler) WithClaimer(claimer Claimer) *Scheduler {
+	s.claimer = claimer
+	return s
+}
+
+// Add registers a job named name to run on schedule. Jobs must be added before Run
+func (s *Scheduler) Add(name string, schedule Schedule, run func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, job{name: name, schedule: schedule, run: run})
+}
+
+// Run runs the jobs on their schedules until ctx is cancelled, waiting for the running ones to
+// return
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, j := range s.jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}()
+	}
+	wg.Wait()
+}
+
+// loop runs j at each activation of its schedule until ctx is cancelled
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	for {
+		next := j.schedule.Next(s.now().UTC())
+		if next.IsZero() {
+			s.logger.Warn("Scheduled job has no next run", zap.String("job", j.name), zap.Stringer("schedule", j.schedule))
+			return
+		}
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.runOnce(ctx, j, next)
+	}
+}
+
+// runOnce runs the activation of j at scheduledAt, logging its duration and error. With a claimer,
+// activations claimed by another instance or failing to be claimed are skipped
+func (s *Scheduler) runOnce(ctx context.Context, j job, scheduledAt time.Time) {
+	if s.claimer != nil {
+		claimed, err := s.claimer.Claim(ctx, j.name, scheduledAt)
+		if err != nil {
+			s.logger.Error("Failed to claim scheduled job", zap.String("job", j.name), zap.Time("scheduled_at", scheduledAt), zap.Error(err))
+			return
+		}
+		if !claimed {
+			s.logger.Info("Scheduled job claimed by another instance", zap.String("job", j.name), zap.Time("scheduled_at", scheduledAt))
+			return
+		}
+	}
+	started := s.now()
+	if err := j.run(ctx); err != nil {
+		s.logger.Error("Scheduled job failed", zap.String("job", j.name), zap.Duration("duration", s.now().Sub(started)), zap.Error(err))
+		return
+	}
+	s.logger.Info("Scheduled job completed", zap.String("job", j.name), zap.Duration("duration", s.now().Sub(started)))
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestScheduler_Run(t *testing.T) {
+	// Arrange
+	s := New(zaptest.NewLogger(t))
+	// The next minute starts in a few milliseconds
+	s.now = func() time.Time { return time.Now().Truncate(time.Minute).Add(time.Minute - 20*time.Millisecond) }
+	schedule, _ := Parse("* * * * *")
+	var runs atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Add("ok", schedule, func(ctx context.Context) error {
+		if runs.Add(1) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	s.Add("failing", schedule, func(ctx context.Context) error { return errors.New("carrier unavailable") })
+	done := make(chan struct{})
+
+	// Act
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	// Assert
+	select {
+	case <-done:
+		assert.Equal(t, int32(2), runs.Load())
+	case <-time.After(5 * time.Second):
+		cancel()
+		t.Fatal("the job did not run on its schedule")
+	}
+}
+
+func TestScheduler_Run_NoNextActivation(t *testing.T) {
+	// Arrange
+	s := New(zaptest.NewLogger(t))
+	schedule, _ := Parse("0 0 30 2 *")
+	s.Add("never", schedule, func(ctx context.Context) error {
+		t.Error("the job must not run")
+		return nil
+	})
+	done := make(chan struct{})
+
+	// Act
+	go func() {
+		s.Run(context.Background())
+		close(done)
+	}()
+
+	// Assert
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return without activations")
+	}
+}
+
+// stubClaimer claims each activation once, or fails with err
+type stubClaimer struct {
+	claimed map[time.Time]bool
+	err     error
+}
+
+func (c *stubClaimer) Claim(ctx context.Context, job string, scheduledAt time.Time) (bool, error) {
+	if c.err != nil {
+		return false, c.err
+	}
+	if c.claimed[scheduledAt] {
+		return false, nil
+	}
+	c.claimed[scheduledAt] = true
+	return true, nil
+}
+
+func TestScheduler_RunOnce_Claimer(t *testing.T) {
+	tests := []struct {
+		name     string
+		claimErr error
+		wantRuns int
+	}{
+		{"each activation runs on one instance", nil, 1},
+		{"activations failing to be claimed are skipped", errors.New("database unavailable"), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			claimer := &stubClaimer{claimed: make(map[time.Time]bool), err: tt.claimErr}
+			runs := 0
+			j := job{name: "repricing", run: func(ctx context.Context) error {
+				runs++
+				return nil
+			}}
+			scheduledAt := time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC)
+			first := New(zaptest.NewLogger(t)).WithClaimer(claimer)
+			second := New(zaptest.NewLogger(t)).WithClaimer(claimer)
+
+			// Act
+			first.runOnce(context.Background(), j, scheduledAt)
+			second.runOnce(context.Background(), j, scheduledAt)
+
+			// Assert
+			assert.Equal(t, tt.wantRuns, runs)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Package secrets provides encryption keys and authenticated encryption for data at rest.
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+)
+
+// ErrUnknownKey is returned when a ciphertext references a key that is not in the keyring
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Key is a named AES key (16, 24 or 32 bytes)
+type Key struct {
+	ID       string
+	Material []byte
+}
+
+// Provider supplies the encryption keys. The first key is the active one, used for new
+// encryptions; the remaining keys are kept to decrypt data written before a rotation
+type Provider interface {
+	Keys(ctx context.Context) ([]Key, error)
+}
+
+// StaticProvider is a Provider backed by a fixed list of keys
+type StaticProvider []Key
+
+// Keys returns the static keys
+func (p StaticProvider) Keys(ctx context.Context) ([]Key, error) {
+	return p, nil
+}
+
+// EnvProvider reads keys from an environment variable formatted as "id:base64key,id:base64key",
+// where the first entry is the active key
+type EnvProvider struct {
+	Variable string
+}
+
+// Keys parses the keys from the environment variable
+func (p EnvProvider) Keys(ctx context.Context) ([]Key, error) {
+	entries := config.List(p.Variable, nil)
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s is not set", p.Variable)
+	}
+
+	keys := make([]Key, 0, len(entries))
+	for _, entry := range entries {
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("%s: entry must be formatted as id:base64key", p.Variable)
+		}
+		material, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %q is not valid base64: %w", p.Variable, id, err)
+		}
+		keys = append(keys, Key{ID: id, Material: material})
+	}
+	return keys, nil
+}
+
+// Keyring encrypts with the active key and decrypts with any known key
+type Keyring struct {
+	provider Provider
+
+	mu       sync.RWMutex
+	activeID string
+	aeads    map[string]cipher.AEAD
+}
+
+// NewKeyring loads the keys from the provider
+func NewKeyring(ctx context.Context, provider Provider) (*Keyring, error) {
+	k := &Keyring{provider: provider}
+	if err := k.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Reload fetches the keys from the provider again, allowing key rotation without a restart
+func (k *Keyring) Reload(ctx context.Context) error {
+	keys, err := k.provider.Keys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load encryption keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return errors.New("at least one encryption key is required")
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for _, key := range keys {
+		if key.ID == "" || strings.Contains(key.ID, ":") {
+			return fmt.Errorf("invalid key id %q", key.ID)
+		}
+		block, err := aes.NewCipher(key.Material)
+		if err != nil {
+			return fmt.Errorf("key %q: %w", key.ID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return fmt.Errorf("key %q: %w", key.ID, err)
+		}
+		aeads[key.ID] = aead
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.activeID = keys[0].ID
+	k.aeads = aeads
+	return nil
+}
+
+// ActiveKeyID returns the ID of the key used for new encryptions
+func (k *Keyring) ActiveKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.activeID
+}
+
+// Encrypt seals plaintext with AES-GCM using the active key. The additional data is authenticated
+// but not stored, binding the ciphertext to its context (e.g. the record ID).
+// The result is formatted as "keyID:base64(nonce|ciphertext)"
+func (k *Keyring) Encrypt(plaintext, additionalData []byte) (string, error) {
+	k.mu.RLock()
+	activeID, aead := k.activeID, k.aeads[k.activeID]
+	k.mu.RUnlock()
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, additionalData)
+	return activeID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt with the key referenced in it
+func (k *Keyring) Decrypt(ciphertext string, additionalData []byte) ([]byte, error) {
+	keyID, encoded, ok := strings.Cut(ciphertext, ":")
+	if !ok {
+		return nil, errors.New("malformed ciphertext")
+	}
+
+	k.mu.RLock()
+	aead, found := k.aeads[keyID]
+	k.mu.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("malformed ciphertext: %w", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("malformed ciphertext: too short")
+	}
+
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plaintext, nil
+}
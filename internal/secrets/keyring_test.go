@@ -0,0 +1,153 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testKey(id string, b byte) Key {
+	return Key{ID: id, Material: bytes.Repeat([]byte{b}, 32)}
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	// Arrange
+	keyring, err := NewKeyring(context.Background(), StaticProvider{testKey("k1", 1)})
+	assert.NoError(t, err)
+
+	// Act
+	ciphertext, err := keyring.Encrypt([]byte("Av. Paulista, 1000"), []byte("quote-1"))
+	assert.NoError(t, err)
+	plaintext, err := keyring.Decrypt(ciphertext, []byte("quote-1"))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Av. Paulista, 1000", string(plaintext))
+	assert.NotContains(t, ciphertext, "Paulista")
+	assert.True(t, len(ciphertext) > 3 && ciphertext[:3] == "k1:")
+}
+
+func TestKeyring_Decrypt_WrongAdditionalData(t *testing.T) {
+	// Arrange
+	keyring, _ := NewKeyring(context.Background(), StaticProvider{testKey("k1", 1)})
+	ciphertext, _ := keyring.Encrypt([]byte("secret"), []byte("quote-1"))
+
+	// Act
+	_, err := keyring.Decrypt(ciphertext, []byte("quote-2"))
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	provider := &StaticProvider{testKey("k1", 1)}
+	keyring, _ := NewKeyring(ctx, provider)
+	oldCiphertext, _ := keyring.Encrypt([]byte("old"), nil)
+
+	// Act
+	*provider = StaticProvider{testKey("k2", 2), testKey("k1", 1)}
+	err := keyring.Reload(ctx)
+	assert.NoError(t, err)
+	newCiphertext, _ := keyring.Encrypt([]byte("new"), nil)
+	oldPlaintext, oldErr := keyring.Decrypt(oldCiphertext, nil)
+
+	// Assert
+	assert.Equal(t, "k2", keyring.ActiveKeyID())
+	assert.True(t, newCiphertext[:3] == "k2:")
+	assert.NoError(t, oldErr)
+	assert.Equal(t, "old", string(oldPlaintext))
+}
+
+func TestKeyring_Decrypt_UnknownKey(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	writer, _ := NewKeyring(ctx, StaticProvider{testKey("k1", 1)})
+	reader, _ := NewKeyring(ctx, StaticProvider{testKey("k2", 2)})
+	ciphertext, _ := writer.Encrypt([]byte("secret"), nil)
+
+	// Act
+	_, err := reader.Decrypt(ciphertext, nil)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyring_Decrypt_Malformed(t *testing.T) {
+	// Arrange
+	keyring, _ := NewKeyring(context.Background(), StaticProvider{testKey("k1", 1)})
+
+	// Act & Assert
+	for _, ciphertext := range []string{"no-separator", "k1:***", "k1:AAAA"} {
+		_, err := keyring.Decrypt(ciphertext, nil)
+		assert.Error(t, err, ciphertext)
+	}
+}
+
+func TestNewKeyring_InvalidKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider Provider
+	}{
+		{name: "no keys", provider: StaticProvider{}},
+		{name: "invalid key size", provider: StaticProvider{{ID: "k1", Material: []byte("short")}}},
+		{name: "empty key id", provider: StaticProvider{testKey("", 1)}},
+		{name: "key id with separator", provider: StaticProvider{testKey("k:1", 1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			keyring, err := NewKeyring(context.Background(), tt.provider)
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, keyring)
+		})
+	}
+}
+
+func TestEnvProvider_Keys(t *testing.T) {
+	// Arrange
+	k2 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+	k1 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 16))
+	t.Setenv("TEST_ENCRYPTION_KEYS", "k2:"+k2+",k1:"+k1)
+
+	// Act
+	keys, err := EnvProvider{Variable: "TEST_ENCRYPTION_KEYS"}.Keys(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, "k2", keys[0].ID)
+	assert.Len(t, keys[0].Material, 32)
+	assert.Equal(t, "k1", keys[1].ID)
+}
+
+func TestEnvProvider_Keys_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "unset", value: ""},
+		{name: "missing id", value: "bm9pZA=="},
+		{name: "invalid base64", value: "k1:***"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("TEST_ENCRYPTION_KEYS", tt.value)
+
+			// Act
+			_, err := EnvProvider{Variable: "TEST_ENCRYPTION_KEYS"}.Keys(context.Background())
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
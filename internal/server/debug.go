@@ -0,0 +1,57 @@
+package server
+
+import (
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+)
+
+// DebugConfig holds the configuration of the debug server, which exposes the pprof profiles and
+// the expvar variables on a port separate from the API
+type DebugConfig struct {
+	Enabled bool
+	// Addr should only be reachable from inside the cluster, e.g. "127.0.0.1:6060"
+	Addr string
+}
+
+// DebugConfigFromEnv reads DEBUG_ENDPOINTS (default false) and DEBUG_ADDR (default 127.0.0.1:6060)
+func DebugConfigFromEnv() (DebugConfig, error) {
+	enabled, err := config.Bool("DEBUG_ENDPOINTS", false)
+	if err != nil {
+		return DebugConfig{}, err
+	}
+	cfg := DebugConfig{Enabled: enabled, Addr: config.String("DEBUG_ADDR", "127.0.0.1:6060")}
+	if _, _, err := net.SplitHostPort(cfg.Addr); err != nil {
+		return DebugConfig{}, fmt.Errorf("DEBUG_ADDR must be host:port, got %q: %w", cfg.Addr, err)
+	}
+	return cfg, nil
+}
+
+// DebugHandler serves the pprof profiles under /debug/pprof/ and the expvar variables, including
+// the runtime memory statistics, at /debug/vars
+func DebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// NewDebug creates the debug server. It has no write timeout, since CPU profiles and traces
+// stream for the number of seconds requested
+func NewDebug(cfg DebugConfig) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           DebugHandler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    DebugConfig
+		wantErr bool
+	}{
+		{"defaults", map[string]string{}, DebugConfig{Addr: "127.0.0.1:6060"}, false},
+		{"enabled", map[string]string{"DEBUG_ENDPOINTS": "true", "DEBUG_ADDR": ":9090"}, DebugConfig{Enabled: true, Addr: ":9090"}, false},
+		{"invalid flag", map[string]string{"DEBUG_ENDPOINTS": "yes please"}, DebugConfig{}, true},
+		{"address without port", map[string]string{"DEBUG_ADDR": "localhost"}, DebugConfig{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for _, key := range []string{"DEBUG_ENDPOINTS", "DEBUG_ADDR"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// Act
+			cfg, err := DebugConfigFromEnv()
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestDebugHandler(t *testing.T) {
+	tests := []struct {
+		path         string
+		wantStatus   int
+		wantContains string
+	}{
+		{"/debug/pprof/", http.StatusOK, "goroutine"},
+		{"/debug/pprof/heap?debug=1", http.StatusOK, "heap profile"},
+		{"/debug/pprof/cmdline", http.StatusOK, ""},
+		{"/debug/vars", http.StatusOK, `"memstats"`},
+		{"/calculate", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			// Act
+			DebugHandler().ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantContains)
+		})
+	}
+}
+
+func TestNewDebug(t *testing.T) {
+	// Act
+	server := NewDebug(DebugConfig{Enabled: true, Addr: "127.0.0.1:6060"})
+
+	// Assert
+	assert.Equal(t, "127.0.0.1:6060", server.Addr)
+	assert.Zero(t, server.WriteTimeout)
+	assert.NotZero(t, server.ReadHeaderTimeout)
+}
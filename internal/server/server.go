@@ -0,0 +1,149 @@
+// Package server configures the HTTP server of the API: timeouts, HTTP/2 and TLS.
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Config holds the HTTP server configuration
+type Config struct {
+	// Addr is the address the server listens on, e.g. ":8080"
+	Addr string
+	// ReadHeaderTimeout bounds the time to read request headers, protecting against slowloris
+	ReadHeaderTimeout time.Duration
+	// ReadTimeout bounds the time to read a whole request, including the body
+	ReadTimeout time.Duration
+	// WriteTimeout bounds the time from the end of the request headers to the end of the response
+	WriteTimeout time.Duration
+	// IdleTimeout bounds how long keep-alive connections wait for the next request
+	IdleTimeout time.Duration
+	// TLSCertFile and TLSKeyFile are the PEM certificate and key served over TLS
+	TLSCertFile string
+	TLSKeyFile  string
+	// AutocertDomains are the host names whose certificates are obtained from Let's Encrypt
+	// instead of TLSCertFile/TLSKeyFile
+	AutocertDomains []string
+	// AutocertCacheDir stores the obtained certificates across restarts
+	AutocertCacheDir string
+	// H2C enables HTTP/2 without TLS, for deployments behind a proxy that terminates TLS
+	H2C bool
+}
+
+// DefaultConfig returns a plain HTTP server on :8080 with conservative timeouts
+func DefaultConfig() Config {
+	return Config{
+		Addr:              ":8080",
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		AutocertCacheDir:  "autocert-cache",
+	}
+}
+
+// TLS reports whether the server is served over HTTPS
+func (c Config) TLS() bool {
+	return c.TLSCertFile != "" || len(c.AutocertDomains) > 0
+}
+
+// ConfigFromEnv builds the server configuration from environment variables:
+// - PORT: listening port (default: 8080)
+// - SERVER_READ_HEADER_TIMEOUT, SERVER_READ_TIMEOUT, SERVER_WRITE_TIMEOUT, SERVER_IDLE_TIMEOUT:
+// server timeouts (default: 5s, 30s, 60s, 120s)
+// - TLS_CERT_FILE / TLS_KEY_FILE: PEM certificate and key enabling HTTPS
+// - TLS_AUTOCERT_DOMAINS: comma-separated domains enabling HTTPS with Let's Encrypt certificates
+// - TLS_AUTOCERT_CACHE_DIR: directory of the obtained certificates (default: autocert-cache)
+// - SERVER_H2C: serve HTTP/2 without TLS (default: false)
+func ConfigFromEnv() (Config, error) {
+	cfg := DefaultConfig()
+	cfg.Addr = ":" + config.String("PORT", "8080")
+
+	timeouts := []struct {
+		key    string
+		target *time.Duration
+	}{
+		{"SERVER_READ_HEADER_TIMEOUT", &cfg.ReadHeaderTimeout},
+		{"SERVER_READ_TIMEOUT", &cfg.ReadTimeout},
+		{"SERVER_WRITE_TIMEOUT", &cfg.WriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", &cfg.IdleTimeout},
+	}
+	for _, timeout := range timeouts {
+		value, err := config.Duration(timeout.key, *timeout.target)
+		if err != nil {
+			return cfg, err
+		}
+		if value <= 0 {
+			return cfg, fmt.Errorf("%s must be positive", timeout.key)
+		}
+		*timeout.target = value
+	}
+
+	h2c, err := config.Bool("SERVER_H2C", cfg.H2C)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.H2C = h2c
+
+	cfg.TLSCertFile = config.String("TLS_CERT_FILE", "")
+	cfg.TLSKeyFile = config.String("TLS_KEY_FILE", "")
+	cfg.AutocertDomains = config.List("TLS_AUTOCERT_DOMAINS", nil)
+	cfg.AutocertCacheDir = config.String("TLS_AUTOCERT_CACHE_DIR", cfg.AutocertCacheDir)
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return cfg, fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if cfg.TLSCertFile != "" && len(cfg.AutocertDomains) > 0 {
+		return cfg, fmt.Errorf("TLS_CERT_FILE and TLS_AUTOCERT_DOMAINS are mutually exclusive")
+	}
+
+	return cfg, nil
+}
+
+// New creates a server for handler with the configured timeouts and protocols. HTTP/2 is
+// negotiated over TLS, and also served in cleartext when H2C is enabled
+func New(cfg Config, handler http.Handler) *http.Server {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(cfg.H2C)
+
+	server := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		Protocols:         protocols,
+	}
+	if len(cfg.AutocertDomains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.AutocertCacheDir),
+		}
+		server.TLSConfig = manager.TLSConfig()
+	}
+	if server.TLSConfig == nil && cfg.TLS() {
+		server.TLSConfig = &tls.Config{}
+	}
+	if server.TLSConfig != nil {
+		server.TLSConfig.MinVersion = tls.VersionTLS12
+	}
+	return server
+}
+
+// ListenAndServe serves HTTPS when TLS is configured and plain HTTP otherwise. Like
+// http.Server.ListenAndServe, it returns http.ErrServerClosed after the server is closed
+func ListenAndServe(server *http.Server, cfg Config) error {
+	if cfg.TLS() {
+		// Certificates come from TLSConfig.GetCertificate when autocert is enabled
+		return server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+	}
+	return server.ListenAndServe()
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// Arrange
+		for _, key := range []string{"PORT", "SERVER_READ_HEADER_TIMEOUT", "SERVER_READ_TIMEOUT", "SERVER_WRITE_TIMEOUT",
+			"SERVER_IDLE_TIMEOUT", "SERVER_H2C", "TLS_CERT_FILE", "TLS_KEY_FILE", "TLS_AUTOCERT_DOMAINS", "TLS_AUTOCERT_CACHE_DIR"} {
+			t.Setenv(key, "")
+		}
+
+		// Act
+		cfg, err := ConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultConfig(), cfg)
+		assert.False(t, cfg.TLS())
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		// Arrange
+		t.Setenv("PORT", "8443")
+		t.Setenv("SERVER_READ_HEADER_TIMEOUT", "2s")
+		t.Setenv("SERVER_READ_TIMEOUT", "10s")
+		t.Setenv("SERVER_WRITE_TIMEOUT", "5m")
+		t.Setenv("SERVER_IDLE_TIMEOUT", "1m")
+		t.Setenv("SERVER_H2C", "true")
+		t.Setenv("TLS_CERT_FILE", "/etc/tls/tls.crt")
+		t.Setenv("TLS_KEY_FILE", "/etc/tls/tls.key")
+		t.Setenv("TLS_AUTOCERT_DOMAINS", "")
+
+		// Act
+		cfg, err := ConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Config{
+			Addr:              ":8443",
+			ReadHeaderTimeout: 2 * time.Second,
+			ReadTimeout:       10 * time.Second,
+			WriteTimeout:      5 * time.Minute,
+			IdleTimeout:       time.Minute,
+			TLSCertFile:       "/etc/tls/tls.crt",
+			TLSKeyFile:        "/etc/tls/tls.key",
+			AutocertCacheDir:  "autocert-cache",
+			H2C:               true,
+		}, cfg)
+		assert.True(t, cfg.TLS())
+	})
+
+	t.Run("autocert", func(t *testing.T) {
+		// Arrange
+		t.Setenv("TLS_CERT_FILE", "")
+		t.Setenv("TLS_KEY_FILE", "")
+		t.Setenv("TLS_AUTOCERT_DOMAINS", "frete.example.com, api.example.com")
+		t.Setenv("TLS_AUTOCERT_CACHE_DIR", "/var/cache/autocert")
+
+		// Act
+		cfg, err := ConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"frete.example.com", "api.example.com"}, cfg.AutocertDomains)
+		assert.Equal(t, "/var/cache/autocert", cfg.AutocertCacheDir)
+		assert.True(t, cfg.TLS())
+	})
+
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{"invalid timeout", map[string]string{"SERVER_READ_TIMEOUT": "soon"}},
+		{"zero timeout", map[string]string{"SERVER_WRITE_TIMEOUT": "0s"}},
+		{"invalid h2c", map[string]string{"SERVER_H2C": "maybe"}},
+		{"certificate without key", map[string]string{"TLS_CERT_FILE": "tls.crt", "TLS_KEY_FILE": ""}},
+		{"certificate and autocert", map[string]string{"TLS_CERT_FILE": "tls.crt", "TLS_KEY_FILE": "tls.key", "TLS_AUTOCERT_DOMAINS": "frete.example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			// Act
+			_, err := ConfigFromEnv()
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Run("plain http", func(t *testing.T) {
+		// Act
+		server := New(DefaultConfig(), http.NotFoundHandler())
+
+		// Assert
+		assert.Equal(t, ":8080", server.Addr)
+		assert.Equal(t, 5*time.Second, server.ReadHeaderTimeout)
+		assert.Equal(t, 30*time.Second, server.ReadTimeout)
+		assert.Equal(t, 60*time.Second, server.WriteTimeout)
+		assert.Equal(t, 120*time.Second, server.IdleTimeout)
+		assert.True(t, server.Protocols.HTTP2())
+		assert.False(t, server.Protocols.UnencryptedHTTP2())
+		assert.Nil(t, server.TLSConfig)
+	})
+
+	t.Run("autocert", func(t *testing.T) {
+		// Arrange
+		cfg := DefaultConfig()
+		cfg.AutocertDomains = []string{"frete.example.com"}
+		cfg.AutocertCacheDir = t.TempDir()
+
+		// Act
+		server := New(cfg, http.NotFoundHandler())
+
+		// Assert
+		assert.NotNil(t, server.TLSConfig.GetCertificate)
+		assert.Contains(t, server.TLSConfig.NextProtos, "h2")
+		assert.Equal(t, uint16(tls.VersionTLS12), server.TLSConfig.MinVersion)
+	})
+}
+
+func TestNew_ServesCleartextHTTP2(t *testing.T) {
+	// Arrange
+	cfg := DefaultConfig()
+	cfg.H2C = true
+	server := New(cfg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+	}))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+
+	// Act
+	resp, err := client.Get("http://" + listener.Addr().String())
+
+	// Assert
+	assert.NoError(t, err)
+	if assert.NotNil(t, resp) {
+		defer resp.Body.Close()
+		assert.Equal(t, "HTTP/2.0", resp.Header.Get("X-Proto"))
+	}
+}
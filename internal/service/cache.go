@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+)
+
+type cacheWarmupKey struct{}
+
+// WithCacheWarmup returns a context whose quotes are priced to fill the quote cache ahead of the
+// requests: they are cached like any other quote, but shadow pricing is skipped
+func WithCacheWarmup(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheWarmupKey{}, true)
+}
+
+// isCacheWarmup reports whether ctx prices quotes to fill the quote cache
+func isCacheWarmup(ctx context.Context) bool {
+	warmup, _ := ctx.Value(cacheWarmupKey{}).(bool)
+	return warmup
+}
+
+// cachedRequest identifies a quote in the cache: the same request of the same tenant priced with
+// the same rate table on the same day at the origin gets the same quote
+type cachedRequest struct {
+	Tenant         string                          `json:"tenant"`
+	PricingVersion string                          `json:"pricing_version"`
+	Date           string                          `json:"date"`
+	Request        *model.CalculateShippingRequest `json:"request"`
+	MissingFields  []string                        `json:"missing_fields,omitempty"`
+}
+
+// quoteCacheKey returns the cache key of a quote; ok is false when the cache is disabled or the
+// quote must be priced anyway: dry runs record their decisions, degraded and historical quotes
+// are priced differently, experiment arms depend on the client and scheduled pickups on
+// the time of day, through their cutoff and same-day surcharge. The other quotes only change with
+// the day at the origin, which delivery estimates are counted from
+func (s *ShippingService) quoteCacheKey(ctx context.Context, req *model.CalculateShippingRequest) (string, bool) {
+	if s.cache == nil || s.cacheTTL <= 0 || req == nil {
+		return "", false
+	}
+	if IsDryRun(ctx) || IsDegraded(ctx) || isHistorical(ctx) || req.PickupDate != "" {
+		return "", false
+	}
+	id := tenant.FromContext(ctx)
+	if s.experiment != nil && id == tenant.Default {
+		return "", false
+	}
+	table, err := s.tenantTable(ctx)
+	if err != nil {
+		return "", false
+	}
+	origin := req.OriginZipcode
+	if req.IsReturn {
+		// Returns are collected at the destination
+		origin = req.DestinationZipcode
+	}
+	data, err := json.Marshal(cachedRequest{Tenant: id, PricingVersion: table.version, Date: s.estimator.LocalDate(origin), Request: req, MissingFields: req.MissingFields})
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	return "quote-cache:" + hex.EncodeToString(sum[:]), true
+}
+
+// cachedQuote returns the cached quote of key; ok is false when it is missing or unreadable
+func (s *ShippingService) cachedQuote(ctx context.Context, key string) (*model.CalculateShippingResponse, bool) {
+	data, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return nil, false
+	}
+	var response model.CalculateShippingResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, false
+	}
+	return &response, true
+}
+
+// cacheQuote caches a quote for the cache TTL; failures only cost a new pricing later
+func (s *ShippingService) cacheQuote(ctx context.Context, key string, response *model.CalculateShippingResponse) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	_ = s.cache.Put(ctx, key, data, s.cacheTTL)
+}
+
+// routeDistance measures a route with the distance resolver of the formula strategy
+func (s *ShippingService) routeDistance(originZipcode, destinationZipcode string) (float64, bool) {
+	if s.distance == nil {
+		return pricing.ZipcodeDistance(originZipcode, destinationZipcode)
+	}
+	return s.distance.Distance(originZipcode, destinationZipcode)
+}
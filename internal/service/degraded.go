@@ -0,0 +1,18 @@
+package service
+
+import "context"
+
+type degradedKey struct{}
+
+// WithDegradedPricing returns a context whose quotes are priced in degraded mode: every service
+// level is priced with the formula strategy, which needs no rate table lookup nor carrier call,
+// and shadow pricing is skipped. The API prices in degraded mode while it sheds load
+func WithDegradedPricing(ctx context.Context) context.Context {
+	return context.WithValue(ctx, degradedKey{}, true)
+}
+
+// IsDegraded reports whether ctx prices quotes in degraded mode
+func IsDegraded(ctx context.Context) bool {
+	degraded, _ := ctx.Value(degradedKey{}).(bool)
+	return degraded
+}
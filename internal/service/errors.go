@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+)
+
+// Categories of calculation errors, the error.category attribute of the shipment error metric,
+// telling client mistakes from outages of the service and its providers
+const (
+	// ErrorValidation is a request the client must fix
+	ErrorValidation = "validation"
+	// ErrorProviderTimeout is a provider, such as the carrier rate API, that did not answer in time
+	ErrorProviderTimeout = "provider_timeout"
+	// ErrorProvider is a provider that failed or answered with an unusable response
+	ErrorProvider = "provider_error"
+	// ErrorInternal is any other failure, e.g. an inconsistent rate table
+	ErrorInternal = "internal"
+)
+
+// ValidationError is a request rejected because of the value of one of its fields
+type ValidationError struct {
+	// Field is the request field that failed, e.g. "weight" or "destination_zipcode"
+	Field string
+	Err   error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// invalidField reports an invalid field as "invalid <field>: <err>"
+func invalidField(field string, err error) error {
+	return &ValidationError{Field: field, Err: fmt.Errorf("invalid %s: %w", field, err)}
+}
+
+// ClassifyError returns the category of a calculation error and, for validation errors, the
+// field that failed
+func ClassifyError(err error) (category, field string) {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return ErrorValidation, validationErr.Field
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorProviderTimeout, ""
+	}
+	if errors.Is(err, pricing.ErrCarrierRate) {
+		return ErrorProvider, ""
+	}
+	return ErrorInternal, ""
+}
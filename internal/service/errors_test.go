@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/stretchr/testify/assert"
+)
+
+type carrierRatesFunc func(ctx context.Context, shipment pricing.Shipment) (money.Amount, error)
+
+func (f carrierRatesFunc) Rate(ctx context.Context, shipment pricing.Shipment) (money.Amount, error) {
+	return f(ctx, shipment)
+}
+
+func TestClassifyError(t *testing.T) {
+	failingCarrier := func(err error) carrierRatesFunc {
+		return func(context.Context, pricing.Shipment) (money.Amount, error) { return 0, err }
+	}
+
+	tests := []struct {
+		name         string
+		carrier      carrierRatesFunc
+		modify       func(req *model.CalculateShippingRequest)
+		wantCategory string
+		wantField    string
+	}{
+		{"invalid zipcode", nil, func(req *model.CalculateShippingRequest) { req.DestinationZipcode = "123" }, ErrorValidation, "destination_zipcode"},
+		{"missing weight", nil, func(req *model.CalculateShippingRequest) {
+			req.Weight, req.MissingFields = 0, []string{"weight"}
+		}, ErrorValidation, "weight"},
+		{"unknown strategy", nil, func(req *model.CalculateShippingRequest) { req.PricingStrategy = "auction" }, ErrorValidation, "pricing_strategy"},
+		{"carrier timeout", failingCarrier(fmt.Errorf("failed to request carrier rate: %w", context.DeadlineExceeded)), nil, ErrorProviderTimeout, ""},
+		{"carrier failure", failingCarrier(errors.New("failed to request carrier rate: unexpected status 502")), nil, ErrorProvider, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := Config{}
+			req := shadowRequest()
+			if tt.carrier != nil {
+				req.PricingStrategy = pricing.StrategyCarrier
+				cfg.Strategies = map[string]pricing.Strategy{pricing.StrategyCarrier: pricing.CarrierPricing{Rates: tt.carrier}}
+			}
+			if tt.modify != nil {
+				tt.modify(req)
+			}
+
+			// Act
+			_, err := NewShippingServiceWithConfig(cfg).CalculateShipping(context.Background(), req)
+			category, field := ClassifyError(err)
+
+			// Assert
+			assert.Error(t, err)
+			assert.Equal(t, tt.wantCategory, category)
+			assert.Equal(t, tt.wantField, field)
+		})
+	}
+}
+
+func TestClassifyError_Internal(t *testing.T) {
+	// Act
+	category, field := ClassifyError(errors.New(`no weight_table configured for currency "BRL"`))
+
+	// Assert
+	assert.Equal(t, ErrorInternal, category)
+	assert.Empty(t, field)
+}
+
+func TestValidationError_KeepsMessage(t *testing.T) {
+	// Act
+	err := invalidField("package_type", ErrExpressNotAllowed)
+
+	// Assert
+	assert.EqualError(t, err, "invalid package_type: express delivery is not allowed for this package type")
+	assert.ErrorIs(t, err, ErrExpressNotAllowed)
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+)
+
+type pinnedArmKey struct{}
+
+// WithExperimentArm returns a context whose quotes are priced in the arm of assignment while its
+// experiment runs, instead of being assigned one, e.g. to reprice a quote in the arm it was first
+// priced in. A nil assignment, or one of another experiment, pins the control arm, so that quotes
+// priced outside of the running experiment are not moved into it
+func WithExperimentArm(ctx context.Context, assignment *model.ExperimentAssignment) context.Context {
+	return context.WithValue(ctx, pinnedArmKey{}, assignment)
+}
+
+// pinnedArm returns the assignment pinned in ctx; ok is false when no arm is pinned
+func pinnedArm(ctx context.Context) (assignment *model.ExperimentAssignment, ok bool) {
+	assignment, ok = ctx.Value(pinnedArmKey{}).(*model.ExperimentAssignment)
+	return assignment, ok
+}
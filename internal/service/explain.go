@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/validator"
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+)
+
+// Explain prices the request as a dry run and explains the cost of the selected service: the
+// normalized inputs, the route zone and distance, each charge with its formula and parameters,
+// and the decisions taken along the way
+func (s *ShippingService) Explain(ctx context.Context, req *model.CalculateShippingRequest) (*model.QuoteExplanation, error) {
+	// The quote and its explanation read the same rates even if a reload happens in between;
+	// unknown tenants fail in CalculateShipping
+	if pinned, err := s.withPinnedTable(ctx); err == nil {
+		ctx = pinned
+	}
+	ctx, trace := WithDryRun(ctx)
+	response, err := s.CalculateShipping(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The calculation succeeded, so the measures convert and the pinned rate table resolves the
+	// same way again
+	req, _ = canonicalMeasures(logger.FromContext(ctx), req)
+	prices, _, _, _ := s.pricingFor(ctx)
+	_, rates, _ := prices.Resolve(req.DestinationCountry, req.Currency)
+	packageType, _ := prices.ResolvePackageType(req.PackageType)
+	deliveryType, _ := prices.ResolveDeliveryType(req.DeliveryType)
+
+	origin, destination := zipcode.Normalize(req.OriginZipcode), zipcode.Normalize(req.DestinationZipcode)
+	if req.IsReturn {
+		origin, destination = destination, origin
+	}
+	distance, _ := s.routeDistance(origin, destination)
+	zone := pricing.ZoneOf(origin, destination)
+	country := prices.Country(req.DestinationCountry)
+
+	level, strategy := response.SelectedService, model.ServiceFreight
+	if level != model.ServiceFreight {
+		strategy = prices.StrategyFor(level, req.PricingStrategy)
+	}
+
+	explanation := &model.QuoteExplanation{
+		Quote: response,
+		Inputs: model.ExplainedInputs{
+			OriginZipcode:      zipcode.Normalize(req.OriginZipcode),
+			DestinationZipcode: zipcode.Normalize(req.DestinationZipcode),
+			DestinationCountry: country,
+			Currency:           response.Currency,
+			WeightKg:           req.Weight,
+			VolumeCm3:          validator.CalculateVolume(req.Dimensions.Length, req.Dimensions.Width, req.Dimensions.Height),
+			PackageType:        orDefault(req.PackageType, pricing.PackageStandard),
+			DeliveryType:       orDefault(req.DeliveryType, pricing.DeliveryHome),
+			IsExpress:          req.IsExpress,
+			IsReturn:           req.IsReturn,
+		},
+		Route:     model.ExplainedRoute{OriginZipcode: origin, DestinationZipcode: destination, Zone: zone, Distance: distance},
+		Service:   level,
+		Strategy:  strategy,
+		Decisions: trace.Steps(),
+	}
+
+	breakdown := response.Breakdown
+	freight := breakdown.BaseCost + breakdown.WeightSurcharge + breakdown.VolumeSurcharge
+	charges := &explainedCharges{}
+	charges.add("base_cost", breakdown.BaseCost, baseCostFormula(rates, strategy, country, origin, destination, distance))
+	if strategy == pricing.StrategyFormula || strategy == pricing.StrategyTable {
+		if strategy == pricing.StrategyFormula {
+			charges.add("weight_surcharge", breakdown.WeightSurcharge, explainedCharge{
+				"base_cost × weight_surcharge_rate × weight_kg / weight_unit_kg",
+				map[string]float64{"weight_surcharge_rate": rates.WeightSurchargeRate, "weight_kg": req.Weight, "weight_unit_kg": rates.WeightUnitKg},
+			})
+		}
+		charges.add("volume_surcharge", breakdown.VolumeSurcharge, explainedCharge{
+			"base_cost × volume_surcharge_rate × volume_cm3 / volume_unit_cm3",
+			map[string]float64{"volume_surcharge_rate": rates.VolumeSurchargeRate, "volume_cm3": explanation.Inputs.VolumeCm3, "volume_unit_cm3": rates.VolumeUnitCm3},
+		})
+	}
+	charges.addNonZero("package_type_surcharge", breakdown.PackageTypeSurcharge, explainedCharge{
+		"(base_cost + weight_surcharge + volume_surcharge) × surcharge_rate",
+		map[string]float64{"surcharge_rate": packageType.SurchargeRate},
+	})
+	charges.addNonZero("delivery_type_adjustment", breakdown.DeliveryTypeAdjustment, explainedCharge{
+		"(freight + package_type_surcharge) × cost_adjustment_rate",
+		map[string]float64{"cost_adjustment_rate": deliveryType.CostAdjustmentRate},
+	})
+	charges.addNonZero("return_adjustment", breakdown.ReturnAdjustment, explainedCharge{
+		"(freight + package_type_surcharge + delivery_type_adjustment) × return_cost_adjustment_rate",
+		map[string]float64{"return_cost_adjustment_rate": prices.ResolveReturns().CostAdjustmentRate},
+	})
+	subtotal := freight + breakdown.PackageTypeSurcharge + breakdown.DeliveryTypeAdjustment + breakdown.ReturnAdjustment
+	charges.addNonZero("pickup_surcharge", breakdown.PickupSurcharge, explainedCharge{
+		"subtotal × pickup_surcharge_rate",
+		map[string]float64{"subtotal": subtotal.Minor(), "pickup_surcharge_rate": rateOf(breakdown.PickupSurcharge, subtotal)},
+	})
+	charges.addNonZero("express_surcharge", breakdown.ExpressSurcharge, explainedCharge{
+		"subtotal × express_surcharge_rate, without the pickup surcharge",
+		map[string]float64{"subtotal": subtotal.Minor(), "express_surcharge_rate": rates.ExpressSurchargeRate},
+	})
+	surchargedSubtotal := subtotal + breakdown.PickupSurcharge
+	charges.addNonZero("restricted_area_surcharge", breakdown.RestrictedAreaSurcharge, explainedCharge{
+		"(subtotal + pickup_surcharge) × surcharge_rate of restricted area " + breakdown.RestrictedArea,
+		map[string]float64{"subtotal": subtotal.Minor(), "surcharge_rate": rateOf(breakdown.RestrictedAreaSurcharge, surchargedSubtotal)},
+	})
+	remoteSubtotal := surchargedSubtotal + breakdown.RestrictedAreaSurcharge
+	charges.addNonZero("remote_area_surcharge", breakdown.RemoteAreaSurcharge, explainedCharge{
+		"(subtotal + pickup_surcharge + restricted_area_surcharge) × sum of the surcharge_rate of remote areas " + strings.Join(breakdown.RemoteAreas, ", "),
+		map[string]float64{"subtotal": remoteSubtotal.Minor(), "surcharge_rate": rateOf(breakdown.RemoteAreaSurcharge, remoteSubtotal)},
+	})
+	weekendSubtotal := remoteSubtotal + breakdown.RemoteAreaSurcharge
+	charges.addNonZero("weekend_surcharge", breakdown.WeekendSurcharge, explainedCharge{
+		"(subtotal + pickup_surcharge + restricted_area_surcharge + remote_area_surcharge) × weekend surcharge_rate of " + level,
+		map[string]float64{"subtotal": weekendSubtotal.Minor(), "surcharge_rate": rateOf(breakdown.WeekendSurcharge, weekendSubtotal)},
+	})
+	if fuel := response.FuelIndex; fuel != nil {
+		surcharged := weekendSubtotal + breakdown.ExpressSurcharge + breakdown.WeekendSurcharge
+		charges.addNonZero("fuel_surcharge", breakdown.FuelSurcharge, explainedCharge{
+			"(subtotal + pickup_surcharge + express_surcharge + restricted_area_surcharge + remote_area_surcharge + weekend_surcharge) × fuel_surcharge_rate effective " + fuel.EffectiveDate,
+			map[string]float64{"surcharged_subtotal": surcharged.Minor(), "fuel_surcharge_rate": fuel.Rate},
+		})
+	}
+	if breakdown.PriceLimit != "" {
+		limit, _ := rates.PriceLimitFor(level, zone)
+		charges.add("price_limit_adjustment", breakdown.PriceLimitAdjustment, explainedCharge{
+			"freight after surcharges clamped to the " + breakdown.PriceLimit + " of zone " + zone,
+			map[string]float64{"min_cost": limit.MinCost.Minor(), "max_cost": limit.MaxCost.Minor()},
+		})
+	}
+	for _, fee := range breakdown.AdditionalServices {
+		charges.add("additional_service:"+fee.Service, fee.Fee, explainedCharge{"fixed fee of the " + fee.Service + " service", nil})
+	}
+	charges.addNonZero("rounding_adjustment", breakdown.RoundingAdjustment, explainedCharge{
+		"total rounded " + orDefault(rates.RoundingMode, pricing.RoundHalfUp) + " to a multiple of rounding_increment",
+		map[string]float64{"rounding_increment": rates.RoundingIncrement.Minor()},
+	})
+	explanation.Charges = charges.list
+	return explanation, nil
+}
+
+// explainedCharge is the formula of a charge and its parameters
+type explainedCharge struct {
+	formula    string
+	parameters map[string]float64
+}
+
+// explainedCharges accumulates the charges of an explanation
+type explainedCharges struct {
+	list []model.ExplainedCharge
+}
+
+func (c *explainedCharges) add(name string, amount money.Amount, charge explainedCharge) {
+	c.list = append(c.list, model.ExplainedCharge{Name: name, Amount: amount, Formula: charge.formula, Parameters: charge.parameters})
+}
+
+// addNonZero adds the charge unless it is zero, i.e. its rule did not apply
+func (c *explainedCharges) addNonZero(name string, amount money.Amount, charge explainedCharge) {
+	if amount != 0 {
+		c.add(name, amount, charge)
+	}
+}
+
+// baseCostFormula explains the base cost of a strategy
+func baseCostFormula(rates pricing.Rates, strategy, country, origin, destination string, distance float64) explainedCharge {
+	switch strategy {
+	case pricing.StrategyFormula:
+		if regional, ok := rates.RegionalRateFor(country, origin, destination); ok && regional.BaseCost > 0 {
+			return explainedCharge{"regional base cost of the route", map[string]float64{"regional_base_cost": regional.BaseCost.Minor()}}
+		}
+		return explainedCharge{
+			"base_cost × (1 + distance / 10000), or base_cost when distance < 1000",
+			map[string]float64{"base_cost": rates.BaseCost.Minor(), "distance": distance},
+		}
+	case pricing.StrategyTable:
+		return explainedCharge{"cost of the lightest weight_table bracket that fits the weight", nil}
+	case pricing.StrategyCarrier:
+		return explainedCharge{"rate quoted by the carrier, including weight and volume", nil}
+	case model.ServiceFreight:
+		return explainedCharge{"freight class rate for the weight", nil}
+	default:
+		return explainedCharge{"priced by the " + strategy + " strategy", nil}
+	}
+}
+
+// rateOf returns the fraction of base that amount represents
+func rateOf(amount, base money.Amount) float64 {
+	if base == 0 {
+		return 0
+	}
+	return float64(amount) / float64(base)
+}
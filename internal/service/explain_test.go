@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "01310-100",
+		DestinationZipcode: "20040-020",
+		Weight:             2.0,
+		Dimensions:         model.PackageDimensions{Length: 20.0, Width: 10.0, Height: 10.0},
+		IsExpress:          true,
+		PackageType:        "Fragile",
+		DeliveryType:       "locker",
+		AdditionalServices: []string{"signature"},
+	}
+
+	// Act
+	explanation, err := service.Explain(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, model.ExplainedInputs{
+		OriginZipcode:      "01310100",
+		DestinationZipcode: "20040020",
+		DestinationCountry: "BR",
+		Currency:           "BRL",
+		WeightKg:           2.0,
+		VolumeCm3:          2000,
+		PackageType:        "fragile",
+		DeliveryType:       "locker",
+		IsExpress:          true,
+	}, explanation.Inputs)
+	assert.Equal(t, model.ExplainedRoute{OriginZipcode: "01310100", DestinationZipcode: "20040020", Zone: pricing.ZoneNational, Distance: 18729920}, explanation.Route)
+	assert.Equal(t, pricing.LevelExpress, explanation.Service)
+	assert.Equal(t, pricing.StrategyFormula, explanation.Strategy)
+	assert.NotEmpty(t, explanation.Decisions)
+
+	var names []string
+	var total money.Amount
+	for _, charge := range explanation.Charges {
+		names = append(names, charge.Name)
+		total += charge.Amount
+	}
+	assert.Equal(t, []string{
+		"base_cost", "weight_surcharge", "volume_surcharge", "package_type_surcharge",
+		"delivery_type_adjustment", "express_surcharge", "additional_service:signature",
+	}, names)
+	assert.Equal(t, explanation.Quote.ShippingCost, total, "charges add up to the quoted cost")
+	assert.Equal(t, map[string]float64{"base_cost": 1000, "distance": 18729920}, explanation.Charges[0].Parameters)
+	assert.Equal(t, map[string]float64{"surcharge_rate": 0.15}, explanation.Charges[3].Parameters)
+	assert.Negative(t, explanation.Charges[4].Amount, "the locker discount is negative")
+}
+
+func TestExplain_PriceLimitAndRounding(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	rates := cfg.Currencies["BRL"]
+	rates.PriceLimits = []pricing.PriceLimit{{MinCost: money.FromMinor(1512)}}
+	rates.RoundingIncrement = money.FromMinor(10)
+	cfg.Currencies["BRL"] = rates
+	service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+
+	// Act
+	explanation, err := service.Explain(context.Background(), shadowRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	var total money.Amount
+	charges := map[string]model.ExplainedCharge{}
+	for _, charge := range explanation.Charges {
+		charges[charge.Name] = charge
+		total += charge.Amount
+	}
+	assert.Equal(t, money.FromMinor(1510), explanation.Quote.ShippingCost)
+	assert.Equal(t, explanation.Quote.ShippingCost, total)
+	assert.Equal(t, money.FromMinor(262), charges["price_limit_adjustment"].Amount)
+	assert.Equal(t, 1512.0, charges["price_limit_adjustment"].Parameters["min_cost"])
+	assert.Equal(t, money.FromMinor(-2), charges["rounding_adjustment"].Amount)
+}
+
+func TestExplain_RestrictedAreaSurcharge(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	cfg.RestrictedAreas = []pricing.RestrictedArea{{Name: "remote", From: "12000000", To: "12999999", SurchargeRate: 0.2}}
+	service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+
+	// Act
+	explanation, err := service.Explain(context.Background(), shadowRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	var total money.Amount
+	charges := map[string]model.ExplainedCharge{}
+	for _, charge := range explanation.Charges {
+		charges[charge.Name] = charge
+		total += charge.Amount
+	}
+	assert.Equal(t, explanation.Quote.ShippingCost, total)
+	assert.Equal(t, money.FromMinor(250), charges["restricted_area_surcharge"].Amount)
+	assert.Equal(t, map[string]float64{"subtotal": 1250, "surcharge_rate": 0.2}, charges["restricted_area_surcharge"].Parameters)
+	assert.Contains(t, explanation.Decisions, model.DecisionStep{Step: StepRestricted, Detail: "standard restricted by remote, surcharge rate 0.2"})
+}
+
+func TestExplain_RemoteAreaSurcharge(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	cfg.RestrictedAreas = []pricing.RestrictedArea{{Name: "risk", From: "12000000", To: "12999999", SurchargeRate: 0.2}}
+	cfg.RemoteAreas = []pricing.RemoteArea{
+		{Name: "vale", From: "12000000", To: "12999999", SurchargeRate: 0.1},
+		{Name: "serra", From: "12000000", To: "12999999", SurchargeRate: 0.1, ExtraDays: 2},
+	}
+	service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+
+	// Act
+	explanation, err := service.Explain(context.Background(), shadowRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	var total money.Amount
+	charges := map[string]model.ExplainedCharge{}
+	for _, charge := range explanation.Charges {
+		charges[charge.Name] = charge
+		total += charge.Amount
+	}
+	assert.Equal(t, explanation.Quote.ShippingCost, total)
+	assert.Equal(t, money.FromMinor(300), charges["remote_area_surcharge"].Amount)
+	assert.Equal(t, map[string]float64{"subtotal": 1500, "surcharge_rate": 0.2}, charges["remote_area_surcharge"].Parameters)
+	assert.Contains(t, explanation.Decisions, model.DecisionStep{Step: StepRemote, Detail: "standard in remote area serra, surcharge rate 0.1, extra days 2"})
+}
+
+func TestExplain_FuelSurcharge(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	cfg.RestrictedAreas = []pricing.RestrictedArea{{Name: "remote", From: "12000000", To: "12999999", SurchargeRate: 0.2}}
+	cfg.FuelSurcharge = &pricing.FuelSurcharge{Rate: 0.1, EffectiveDate: "2025-04-07"}
+	service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+
+	// Act
+	explanation, err := service.Explain(context.Background(), shadowRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	var total money.Amount
+	charges := map[string]model.ExplainedCharge{}
+	for _, charge := range explanation.Charges {
+		charges[charge.Name] = charge
+		total += charge.Amount
+	}
+	assert.Equal(t, explanation.Quote.ShippingCost, total)
+	assert.Equal(t, money.FromMinor(150), charges["fuel_surcharge"].Amount)
+	assert.Equal(t, map[string]float64{"surcharged_subtotal": 1500, "fuel_surcharge_rate": 0.1}, charges["fuel_surcharge"].Parameters)
+	assert.Contains(t, charges["fuel_surcharge"].Formula, "effective 2025-04-07")
+	assert.Contains(t, explanation.Decisions, model.DecisionStep{Step: StepFuel, Detail: "rate 0.1 effective 2025-04-07"})
+}
+
+func TestExplain_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+
+	// Act
+	_, err := service.Explain(context.Background(), &model.CalculateShippingRequest{OriginZipcode: "123"})
+
+	// Assert
+	assert.ErrorContains(t, err, "invalid origin_zipcode")
+}
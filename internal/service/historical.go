@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+)
+
+type historicalKey struct{}
+
+// WithHistoricalPricing returns a context whose quotes are priced with cfg, a pricing
+// configuration in force at a past date, instead of the rate table in force for the tenant.
+// Pricing experiments do not apply to historical quotes, which reprice disputed shipments and
+// refunds as they were priced back then
+func WithHistoricalPricing(ctx context.Context, cfg pricing.Config) context.Context {
+	ctx = context.WithValue(ctx, historicalKey{}, true)
+	return context.WithValue(ctx, pinnedTableKey{}, &rateTable{config: cfg, version: cfg.VersionID()})
+}
+
+// isHistorical reports whether ctx prices quotes with a past pricing configuration
+func isHistorical(ctx context.Context) bool {
+	historical, _ := ctx.Value(historicalKey{}).(bool)
+	return historical
+}
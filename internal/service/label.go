@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/label"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidLabelFormat is returned when a label is requested in an unsupported format
+	ErrInvalidLabelFormat = errors.New("invalid label format")
+	// ErrLabelNotFound is returned when the label of a shipment has not been generated
+	ErrLabelNotFound = errors.New("label not found")
+	// ErrLabelProvider is returned when the carrier fails to generate a label
+	ErrLabelProvider = errors.New("carrier failed to generate the label")
+)
+
+// LabelService generates the shipping labels of booked shipments at their carrier and keeps them
+// for download
+type LabelService struct {
+	shipments repository.ShipmentRepository
+	labels    repository.LabelRepository
+	provider  label.LabelProvider
+	now       func() time.Time
+}
+
+// NewLabelService creates a label service requesting the labels of shipments from provider and
+// storing them in labels
+func NewLabelService(shipments repository.ShipmentRepository, labels repository.LabelRepository, provider label.LabelProvider) *LabelService {
+	return &LabelService{
+		shipments: shipments,
+		labels:    labels,
+		provider:  provider,
+		now:       time.Now,
+	}
+}
+
+// LabelDownloadPath returns the path the label of a shipment in a format is downloaded from
+func LabelDownloadPath(shipmentID, format string) string {
+	return "/shipments/" + url.PathEscape(shipmentID) + "/label/" + format
+}
+
+// Generate returns the label of a shipment of the tenant of ctx in format (pdf when empty),
+// requesting it from the carrier unless it was already generated; created reports whether this
+// call generated it. Shipments of other tenants are reported as ErrShipmentNotFound. The
+// request is idempotent: the shipment and format are the idempotency key sent to the carrier, so
+// concurrent and retried calls get a single label
+func (s *LabelService) Generate(ctx context.Context, shipmentID, format string) (*model.ShipmentLabel, bool, error) {
+	format, err := labelFormat(format)
+	if err != nil {
+		return nil, false, err
+	}
+	shipment, err := s.shipment(ctx, shipmentID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, _, err := s.labels.Get(ctx, shipment.ID, format)
+	if err == nil {
+		return existing, false, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, false, fmt.Errorf("failed to load label: %w", err)
+	}
+
+	document, err := s.provider.Generate(ctx, shipment, format, shipment.ID+":"+format)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrLabelProvider, err)
+	}
+	generated := &model.ShipmentLabel{
+		ShipmentID:     shipment.ID,
+		Format:         format,
+		CarrierLabelID: document.LabelID,
+		TrackingCode:   document.TrackingCode,
+		Size:           len(document.Content),
+		DownloadURL:    LabelDownloadPath(shipment.ID, format),
+		CreatedAt:      s.now().UTC(),
+	}
+	if err := s.labels.Save(ctx, generated, document.Content); err != nil {
+		if !errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, false, fmt.Errorf("failed to save label: %w", err)
+		}
+		// A concurrent call saved the label first, the same one for the idempotency key
+		existing, _, err := s.labels.Get(ctx, shipment.ID, format)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to load label: %w", err)
+		}
+		return existing, false, nil
+	}
+
+	logger.FromContext(ctx).Info("Etiqueta gerada",
+		zap.String("shipment_id", shipment.ID),
+		zap.String("formato", format),
+		zap.String("carrier_label_id", generated.CarrierLabelID),
+		zap.Int("tamanho", generated.Size),
+	)
+	return generated, true, nil
+}
+
+// Download returns the label of a shipment of the tenant of ctx in format and its document, or
+// ErrLabelNotFound when it has not been generated
+func (s *LabelService) Download(ctx context.Context, shipmentID, format string) (*model.ShipmentLabel, []byte, error) {
+	format, err := labelFormat(format)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.shipment(ctx, shipmentID); err != nil {
+		return nil, nil, err
+	}
+	stored, content, err := s.labels.Get(ctx, shipmentID, format)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, ErrLabelNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load label: %w", err)
+	}
+	return stored, content, nil
+}
+
+// shipment loads a shipment of the tenant of ctx; the shipments of other tenants are not found,
+// so that their IDs are not disclosed
+func (s *LabelService) shipment(ctx context.Context, id string) (*model.Shipment, error) {
+	shipment, err := s.shipments.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrShipmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load shipment: %w", err)
+	}
+	if shipmentTenant(shipment) != tenant.FromContext(ctx) {
+		return nil, ErrShipmentNotFound
+	}
+	return shipment, nil
+}
+
+// labelFormat normalizes a label format, pdf when empty
+func labelFormat(format string) (string, error) {
+	switch normalized := strings.ToLower(strings.TrimSpace(format)); normalized {
+	case "":
+		return model.LabelFormatPDF, nil
+	case model.LabelFormatPDF, model.LabelFormatZPL:
+		return normalized, nil
+	default:
+		return "", fmt.Errorf("%w: %q, must be %s or %s", ErrInvalidLabelFormat, format, model.LabelFormatPDF, model.LabelFormatZPL)
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/label"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubLabelProvider returns a label with the format as content, or fails with err when set,
+// recording the idempotency keys
+type stubLabelProvider struct {
+	err  error
+	keys []string
+}
+
+func (p *stubLabelProvider) Generate(ctx context.Context, shipment *model.Shipment, format, idempotencyKey string) (label.Document, error) {
+	p.keys = append(p.keys, idempotencyKey)
+	if p.err != nil {
+		return label.Document{}, p.err
+	}
+	return label.Document{LabelID: "lbl-" + shipment.ID, TrackingCode: "BR123456789", Content: []byte(format + " label")}, nil
+}
+
+func newLabelService(t *testing.T, provider label.LabelProvider) (*LabelService, *repository.MemoryLabelRepository) {
+	t.Helper()
+	shipments := repository.NewMemoryShipmentRepository()
+	_ = shipments.Save(context.Background(), &model.Shipment{ID: "s1", QuoteID: "q1", Status: model.ShipmentStatusBooked})
+	_ = shipments.Save(context.Background(), &model.Shipment{ID: "s2", QuoteID: "q2", Status: model.ShipmentStatusBooked, Tenant: "acme"})
+	labels := repository.NewMemoryLabelRepository()
+	service := NewLabelService(shipments, labels, provider)
+	service.now = func() time.Time { return time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC) }
+	return service, labels
+}
+
+func TestLabelService_Generate(t *testing.T) {
+	// Arrange
+	provider := &stubLabelProvider{}
+	service, _ := newLabelService(t, provider)
+
+	// Act
+	generated, created, err := service.Generate(context.Background(), "s1", "")
+	again, createdAgain, againErr := service.Generate(context.Background(), "s1", "PDF")
+	zpl, createdZPL, zplErr := service.Generate(context.Background(), "s1", "zpl")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, &model.ShipmentLabel{
+		ShipmentID:     "s1",
+		Format:         model.LabelFormatPDF,
+		CarrierLabelID: "lbl-s1",
+		TrackingCode:   "BR123456789",
+		Size:           len("pdf label"),
+		DownloadURL:    "/shipments/s1/label/pdf",
+		CreatedAt:      time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC),
+	}, generated)
+	assert.NoError(t, againErr)
+	assert.False(t, createdAgain, "the label is generated once per format")
+	assert.Equal(t, generated, again)
+	assert.NoError(t, zplErr)
+	assert.True(t, createdZPL)
+	assert.Equal(t, "/shipments/s1/label/zpl", zpl.DownloadURL)
+	assert.Equal(t, []string{"s1:pdf", "s1:zpl"}, provider.keys)
+}
+
+func TestLabelService_Generate_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		shipmentID string
+		format     string
+		provider   *stubLabelProvider
+		wantErr    error
+	}{
+		{"invalid format", "s1", "png", &stubLabelProvider{}, ErrInvalidLabelFormat},
+		{"unknown shipment", "missing", "pdf", &stubLabelProvider{}, ErrShipmentNotFound},
+		{"shipment of another tenant", "s2", "pdf", &stubLabelProvider{}, ErrShipmentNotFound},
+		{"carrier failure", "s1", "pdf", &stubLabelProvider{err: errors.New("label: unexpected status 500")}, ErrLabelProvider},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service, labels := newLabelService(t, tt.provider)
+
+			// Act
+			_, _, err := service.Generate(context.Background(), tt.shipmentID, tt.format)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			_, _, getErr := labels.Get(context.Background(), tt.shipmentID, model.LabelFormatPDF)
+			assert.ErrorIs(t, getErr, repository.ErrNotFound, "no label is stored")
+		})
+	}
+}
+
+func TestLabelService_Download(t *testing.T) {
+	// Arrange
+	service, _ := newLabelService(t, &stubLabelProvider{})
+	_, _, _ = service.Generate(context.Background(), "s1", model.LabelFormatZPL)
+
+	// Act
+	stored, content, err := service.Download(context.Background(), "s1", "zpl")
+	_, _, missingErr := service.Download(context.Background(), "s1", "pdf")
+	_, _, invalidErr := service.Download(context.Background(), "s1", "png")
+	_, _, otherTenantErr := service.Download(tenant.NewContext(context.Background(), "acme"), "s1", "zpl")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, model.LabelFormatZPL, stored.Format)
+	assert.Equal(t, []byte("zpl label"), content)
+	assert.ErrorIs(t, missingErr, ErrLabelNotFound)
+	assert.ErrorIs(t, invalidErr, ErrInvalidLabelFormat)
+	assert.ErrorIs(t, otherTenantErr, ErrShipmentNotFound, "labels of other tenants are not found")
+}
+
+func TestLabelService_Generate_TenantShipment(t *testing.T) {
+	// Arrange
+	provider := &stubLabelProvider{}
+	service, _ := newLabelService(t, provider)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	// Act
+	generated, created, err := service.Generate(ctx, "s2", "")
+	stored, _, downloadErr := service.Download(ctx, "s2", "pdf")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.NoError(t, downloadErr)
+	assert.Equal(t, generated, stored)
+}
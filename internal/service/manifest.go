@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/manifest"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"go.uber.org/zap"
+)
+
+// manifestBatchSize is how many booked shipments are loaded at a time when closing the manifests
+const manifestBatchSize = 500
+
+var (
+	// ErrInvalidManifest is returned when a closing request is malformed
+	ErrInvalidManifest = errors.New("invalid manifest request")
+	// ErrManifestNotFound is returned when the manifest does not exist
+	ErrManifestNotFound = errors.New("manifest not found")
+)
+
+// ManifestService closes the day's booked shipments into one manifest per carrier and submits them
+type ManifestService struct {
+	shipments repository.ShipmentRepository
+	manifests repository.ManifestRepository
+	cfg       manifest.Config
+	submitter manifest.Submitter
+	now       func() time.Time
+}
+
+// NewManifestService creates a manifest service grouping shipments by the carriers in cfg and
+// submitting the manifests with submitter, or only generating them when submitter is nil
+func NewManifestService(shipments repository.ShipmentRepository, manifests repository.ManifestRepository, cfg manifest.Config, submitter manifest.Submitter) *ManifestService {
+	return &ManifestService{
+		shipments: shipments,
+		manifests: manifests,
+		cfg:       cfg,
+		submitter: submitter,
+		now:       time.Now,
+	}
+}
+
+// Close creates a manifest per carrier with the shipments booked on the day (today when empty) and
+// not yet listed in a generated or submitted manifest of the day, and submits it to the carrier.
+// A manifest the carrier does not accept is stored as failed, and its shipments are listed again by
+// the next closing. Carriers without pending shipments get no manifest
+func (s *ManifestService) Close(ctx context.Context, req *model.CloseManifestsRequest) (*model.CloseManifestsResponse, error) {
+	now := s.now().UTC()
+	date := now.Format(time.DateOnly)
+	if value := strings.TrimSpace(req.Date); value != "" {
+		day, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: date must be formatted as YYYY-MM-DD", ErrInvalidManifest)
+		}
+		if day.After(now) {
+			return nil, fmt.Errorf("%w: date must not be in the future", ErrInvalidManifest)
+		}
+		date = day.Format(time.DateOnly)
+	}
+	carrier := strings.ToLower(strings.TrimSpace(req.Carrier))
+
+	// The closings of the day are serialized across instances, so that a shipment is not listed in
+	// two manifests
+	unlock, err := s.manifests.LockDate(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	pending, err := s.pendingShipments(ctx, date, carrier)
+	if err != nil {
+		return nil, err
+	}
+	carriers := make([]string, 0, len(pending))
+	for name := range pending {
+		carriers = append(carriers, name)
+	}
+	sort.Strings(carriers)
+
+	response := &model.CloseManifestsResponse{Date: date, Manifests: make([]model.Manifest, 0, len(carriers))}
+	for _, name := range carriers {
+		closed, err := s.close(ctx, name, date, pending[name])
+		if err != nil {
+			return nil, err
+		}
+		response.Manifests = append(response.Manifests, *closed)
+	}
+	return response, nil
+}
+
+// Get returns the manifest with the given ID
+func (s *ManifestService) Get(ctx context.Context, id string) (*model.Manifest, error) {
+	found, err := s.manifests.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrManifestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manifest: %w", err)
+	}
+	return found, nil
+}
+
+// pendingShipments returns the shipments booked on the day and not yet in a manifest, by carrier,
+// restricted to carrier when not empty
+func (s *ManifestService) pendingShipments(ctx context.Context, date, carrier string) (map[string][]model.Shipment, error) {
+	existing, err := s.manifests.ListByDate(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manifests: %w", err)
+	}
+	manifested := make(map[string]bool)
+	for _, previous := range existing {
+		if previous.Status == model.ManifestStatusFailed {
+			continue
+		}
+		for _, id := range previous.ShipmentIDs {
+			manifested[id] = true
+		}
+	}
+
+	from, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: date must be formatted as YYYY-MM-DD", ErrInvalidManifest)
+	}
+	to := from.AddDate(0, 0, 1)
+
+	pending := make(map[string][]model.Shipment)
+	afterID := ""
+	for {
+		page, err := s.shipments.ListBookedBetween(ctx, model.ShipmentStatusBooked, from, to, afterID, manifestBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list booked shipments: %w", err)
+		}
+		for _, shipment := range page {
+			if manifested[shipment.ID] {
+				continue
+			}
+			name := s.cfg.Carrier(shipment.Service)
+			if carrier != "" && name != carrier {
+				continue
+			}
+			pending[name] = append(pending[name], shipment)
+		}
+		if len(page) < manifestBatchSize {
+			return pending, nil
+		}
+		afterID = page[len(page)-1].ID
+	}
+}
+
+// close stores the manifest of a carrier's shipments and submits it
+func (s *ManifestService) close(ctx context.Context, carrier, date string, shipments []model.Shipment) (*model.Manifest, error) {
+	zapLogger := logger.FromContext(ctx)
+
+	closed := &model.Manifest{
+		ID:          uuid.NewString(),
+		Carrier:     carrier,
+		Date:        date,
+		Status:      model.ManifestStatusGenerated,
+		ShipmentIDs: make([]string, 0, len(shipments)),
+		CreatedAt:   s.now().UTC(),
+	}
+	for _, shipment := range shipments {
+		closed.ShipmentIDs = append(closed.ShipmentIDs, shipment.ID)
+		closed.TotalWeight += shipment.Package.Weight
+	}
+
+	if s.submitter != nil {
+		carrierID, err := s.submitter.Submit(ctx, closed, shipments)
+		if err != nil {
+			closed.Status = model.ManifestStatusFailed
+			closed.Error = err.Error()
+			zapLogger.Warn("Falha ao enviar manifesto à transportadora",
+				zap.String("manifest_id", closed.ID),
+				zap.String("transportadora", carrier),
+				zap.Error(err),
+			)
+		} else {
+			submittedAt := s.now().UTC()
+			closed.Status = model.ManifestStatusSubmitted
+			closed.CarrierManifestID = carrierID
+			closed.SubmittedAt = &submittedAt
+		}
+	}
+	if err := s.manifests.Save(ctx, closed); err != nil {
+		return nil, fmt.Errorf("failed to save manifest: %w", err)
+	}
+
+	zapLogger.Info("Manifesto fechado",
+		zap.String("manifest_id", closed.ID),
+		zap.String("transportadora", carrier),
+		zap.String("data", date),
+		zap.String("status", closed.Status),
+		zap.Int("envios", len(closed.ShipmentIDs)),
+	)
+	return closed, nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/manifest"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubSubmitter accepts manifests as "cm-" and their carrier, or fails with err when set,
+// recording the submitted shipments by carrier
+type stubSubmitter struct {
+	err       error
+	submitted map[string][]string
+}
+
+func (s *stubSubmitter) Submit(ctx context.Context, submitted *model.Manifest, shipments []model.Shipment) (string, error) {
+	if s.submitted == nil {
+		s.submitted = make(map[string][]string)
+	}
+	for _, shipment := range shipments {
+		s.submitted[submitted.Carrier] = append(s.submitted[submitted.Carrier], shipment.ID)
+	}
+	if s.err != nil {
+		return "", s.err
+	}
+	return "cm-" + submitted.Carrier, nil
+}
+
+var manifestNow = time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+
+func newManifestService(t *testing.T, submitter manifest.Submitter) (*ManifestService, *repository.MemoryShipmentRepository) {
+	t.Helper()
+	shipments := repository.NewMemoryShipmentRepository()
+	for _, shipment := range []model.Shipment{
+		{ID: "s1", QuoteID: "q1", Service: model.ServiceStandard, Package: model.ShipmentPackage{Weight: 1.5}, BookedAt: manifestNow.Add(-8 * time.Hour)},
+		{ID: "s2", QuoteID: "q2", Service: model.ServiceExpress, Package: model.ShipmentPackage{Weight: 2}, BookedAt: manifestNow.Add(-2 * time.Hour)},
+		{ID: "s3", QuoteID: "q3", Service: model.ServiceFreight, Package: model.ShipmentPackage{Weight: 0.5}, BookedAt: manifestNow.Add(-time.Hour)},
+		{ID: "s4", QuoteID: "q4", Service: model.ServiceStandard, Package: model.ShipmentPackage{Weight: 3}, BookedAt: manifestNow.Add(-24 * time.Hour)},
+	} {
+		shipment.Status = model.ShipmentStatusBooked
+		assert.NoError(t, shipments.Save(context.Background(), &shipment))
+	}
+	cfg := manifest.Config{Carriers: map[string]string{model.ServiceStandard: "correios", model.ServiceFreight: "correios", model.ServiceExpress: "jadlog"}}
+	service := NewManifestService(shipments, repository.NewMemoryManifestRepository(), cfg, submitter)
+	service.now = func() time.Time { return manifestNow }
+	return service, shipments
+}
+
+func TestManifestService_Close(t *testing.T) {
+	// Arrange
+	submitter := &stubSubmitter{}
+	service, _ := newManifestService(t, submitter)
+
+	// Act
+	closed, err := service.Close(context.Background(), &model.CloseManifestsRequest{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-03-10", closed.Date)
+	assert.Len(t, closed.Manifests, 2)
+	correios, jadlog := closed.Manifests[0], closed.Manifests[1]
+	assert.Equal(t, "correios", correios.Carrier)
+	assert.Equal(t, model.ManifestStatusSubmitted, correios.Status)
+	assert.Equal(t, []string{"s1", "s3"}, correios.ShipmentIDs, "shipments booked on other days are left out")
+	assert.Equal(t, 2.0, correios.TotalWeight)
+	assert.Equal(t, "cm-correios", correios.CarrierManifestID)
+	assert.Equal(t, &manifestNow, correios.SubmittedAt)
+	assert.Equal(t, "jadlog", jadlog.Carrier)
+	assert.Equal(t, []string{"s2"}, jadlog.ShipmentIDs)
+	assert.Equal(t, map[string][]string{"correios": {"s1", "s3"}, "jadlog": {"s2"}}, submitter.submitted)
+
+	stored, getErr := service.Get(context.Background(), correios.ID)
+	assert.NoError(t, getErr)
+	assert.Equal(t, &correios, stored)
+}
+
+func TestManifestService_Close_OnlyPendingShipments(t *testing.T) {
+	// Arrange
+	service, shipments := newManifestService(t, &stubSubmitter{})
+	_, _ = service.Close(context.Background(), &model.CloseManifestsRequest{})
+	late := model.Shipment{ID: "s5", QuoteID: "q5", Status: model.ShipmentStatusBooked, Service: model.ServiceExpress, BookedAt: manifestNow.Add(time.Minute)}
+	_ = shipments.Save(context.Background(), &late)
+	service.now = func() time.Time { return manifestNow.Add(time.Hour) }
+
+	// Act
+	closed, err := service.Close(context.Background(), &model.CloseManifestsRequest{})
+	again, againErr := service.Close(context.Background(), &model.CloseManifestsRequest{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, closed.Manifests, 1)
+	assert.Equal(t, "jadlog", closed.Manifests[0].Carrier)
+	assert.Equal(t, []string{"s5"}, closed.Manifests[0].ShipmentIDs)
+	assert.NoError(t, againErr)
+	assert.Empty(t, again.Manifests)
+}
+
+func TestManifestService_Close_Failed(t *testing.T) {
+	// Arrange
+	submitter := &stubSubmitter{err: errors.New("manifest: unexpected status 503")}
+	service, _ := newManifestService(t, submitter)
+
+	// Act
+	failed, err := service.Close(context.Background(), &model.CloseManifestsRequest{Carrier: "Jadlog"})
+	submitter.err = nil
+	retried, retryErr := service.Close(context.Background(), &model.CloseManifestsRequest{Date: "2025-03-10", Carrier: "jadlog"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, failed.Manifests, 1)
+	assert.Equal(t, model.ManifestStatusFailed, failed.Manifests[0].Status)
+	assert.Equal(t, "manifest: unexpected status 503", failed.Manifests[0].Error)
+	assert.Nil(t, failed.Manifests[0].SubmittedAt)
+	assert.NoError(t, retryErr)
+	assert.Len(t, retried.Manifests, 1)
+	assert.Equal(t, model.ManifestStatusSubmitted, retried.Manifests[0].Status)
+	assert.Equal(t, []string{"s2"}, retried.Manifests[0].ShipmentIDs, "the shipments of failed manifests are listed again")
+}
+
+func TestManifestService_Close_WithoutSubmitter(t *testing.T) {
+	// Arrange
+	service, _ := newManifestService(t, nil)
+
+	// Act
+	closed, err := service.Close(context.Background(), &model.CloseManifestsRequest{Date: "2025-03-09"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, closed.Manifests, 1)
+	assert.Equal(t, model.ManifestStatusGenerated, closed.Manifests[0].Status)
+	assert.Equal(t, []string{"s4"}, closed.Manifests[0].ShipmentIDs)
+	assert.Empty(t, closed.Manifests[0].CarrierManifestID)
+}
+
+func TestManifestService_Close_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		date string
+	}{
+		{"malformed date", "10/03/2025"},
+		{"future date", "2025-03-11"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service, _ := newManifestService(t, &stubSubmitter{})
+
+			// Act
+			_, err := service.Close(context.Background(), &model.CloseManifestsRequest{Date: tt.date})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidManifest)
+		})
+	}
+}
+
+func TestManifestService_Get_NotFound(t *testing.T) {
+	// Arrange
+	service, _ := newManifestService(t, nil)
+
+	// Act
+	_, err := service.Get(context.Background(), "missing")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrManifestNotFound)
+}
+
+// unavailableLockRepository is a ManifestRepository whose day locks cannot be taken
+type unavailableLockRepository struct {
+	repository.ManifestRepository
+}
+
+func (unavailableLockRepository) LockDate(ctx context.Context, date string) (func(), error) {
+	return nil, errors.New("database unavailable")
+}
+
+func TestManifestService_Close_LockUnavailable(t *testing.T) {
+	// Arrange
+	submitter := &stubSubmitter{}
+	service, _ := newManifestService(t, submitter)
+	service.manifests = unavailableLockRepository{service.manifests}
+
+	// Act
+	_, err := service.Close(context.Background(), &model.CloseManifestsRequest{})
+
+	// Assert
+	assert.EqualError(t, err, "database unavailable")
+	assert.Empty(t, submitter.submitted, "nothing is submitted without the lock of the day")
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+)
+
+// Results of a quote calculation, the shipment.result attribute of the shipment metrics
+const (
+	// ResultOK is a priced quote
+	ResultOK = "ok"
+	// ResultInvalidBody is a request whose body could not be decoded
+	ResultInvalidBody = "invalid_body"
+	// ResultRejected is a request whose calculation failed, e.g. because of an invalid zipcode or
+	// a carrier outage
+	ResultRejected = "rejected"
+)
+
+// unknownAttribute is the service level and destination region of requests that could not be
+// decoded or whose destination is not recognized
+const unknownAttribute = "unknown"
+
+// RecordCalculation records in metrics the shipment metrics of a calculation of req, attributed to its
+// service level, destination region, client, tenant and result: the calculation counter, the calculation
+// time and either the cost of the quote or, when err is not nil, the error counter with the
+// category of err. req is nil when the body could not be decoded, a validation error of the body
+func RecordCalculation(ctx context.Context, metrics *telemetry.Metrics, req *model.CalculateShippingRequest, response *model.CalculateShippingResponse, err error, elapsed time.Duration) {
+	result := ResultOK
+	switch {
+	case req == nil:
+		result = ResultInvalidBody
+	case err != nil:
+		result = ResultRejected
+	}
+
+	level, region, clientID, tenantID := serviceLevelOf(req, response), DestinationRegion(req), telemetry.ClientIDFromContext(ctx), tenant.FromContext(ctx)
+	metrics.IncrementShipmentCalculate(ctx, level, region, clientID, tenantID, result)
+	metrics.RecordShipmentCalculateTime(ctx, elapsed.Milliseconds(), level, region, clientID, tenantID, result)
+	if result == ResultOK {
+		metrics.RecordShipmentCalculateCostDistribution(ctx, response.ShippingCost.Minor(), level, region, clientID, tenantID, result)
+		return
+	}
+
+	category, field := ErrorValidation, "body"
+	if result != ResultInvalidBody {
+		category, field = ClassifyError(err)
+	}
+	metrics.IncrementShipmentCalculateError(ctx, level, region, clientID, tenantID, result, category, field)
+}
+
+// serviceLevelOf is the service level of a calculation: the level selected by the response, freight
+// for freight-only quotes, otherwise the level requested
+func serviceLevelOf(req *model.CalculateShippingRequest, response *model.CalculateShippingResponse) string {
+	switch {
+	case req == nil:
+		return unknownAttribute
+	case response != nil && response.SelectedService != "":
+		return response.SelectedService
+	case response != nil && len(response.AvailableServices) == 1 && response.AvailableServices[0] == model.ServiceFreight:
+		return model.ServiceFreight
+	case req.IsExpress:
+		return model.ServiceExpress
+	default:
+		return model.ServiceStandard
+	}
+}
+
+// DestinationRegion is the destination state of Brazilian shipments (e.g. "SP") and the
+// destination country of international ones (e.g. "US"), a bounded set of values
+func DestinationRegion(req *model.CalculateShippingRequest) string {
+	if req == nil {
+		return unknownAttribute
+	}
+	if country := strings.ToUpper(strings.TrimSpace(req.DestinationCountry)); country != "" && country != "BR" {
+		if len(country) != 2 {
+			return unknownAttribute
+		}
+		return country
+	}
+	if state := zipcode.State(req.DestinationZipcode); state != "" {
+		return state
+	}
+	return unknownAttribute
+}
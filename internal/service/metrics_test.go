@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+	"github.com/stretchr/testify/assert"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestServiceLevelOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *model.CalculateShippingRequest
+		response *model.CalculateShippingResponse
+		want     string
+	}{
+		{"undecoded request", nil, nil, "unknown"},
+		{"standard", &model.CalculateShippingRequest{}, nil, model.ServiceStandard},
+		{"express", &model.CalculateShippingRequest{IsExpress: true}, nil, model.ServiceExpress},
+		{"freight alongside parcels", &model.CalculateShippingRequest{}, &model.CalculateShippingResponse{AvailableServices: []string{model.ServiceStandard, model.ServiceFreight}}, model.ServiceStandard},
+		{"freight only", &model.CalculateShippingRequest{}, &model.CalculateShippingResponse{AvailableServices: []string{model.ServiceFreight}}, model.ServiceFreight},
+		{"selected by optimize", &model.CalculateShippingRequest{Optimize: model.OptimizeFastest}, &model.CalculateShippingResponse{SelectedService: model.ServiceExpress}, model.ServiceExpress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.want, serviceLevelOf(tt.req, tt.response))
+		})
+	}
+}
+
+func TestDestinationRegion(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *model.CalculateShippingRequest
+		want string
+	}{
+		{"undecoded request", nil, "unknown"},
+		{"brazilian state", &model.CalculateShippingRequest{DestinationZipcode: "20040-020"}, "RJ"},
+		{"explicit brazil", &model.CalculateShippingRequest{DestinationZipcode: "01310100", DestinationCountry: "br"}, "SP"},
+		{"unknown zipcode", &model.CalculateShippingRequest{DestinationZipcode: "123"}, "unknown"},
+		{"international", &model.CalculateShippingRequest{DestinationZipcode: "10001", DestinationCountry: "us"}, "US"},
+		{"invalid country", &model.CalculateShippingRequest{DestinationCountry: "united states"}, "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.want, DestinationRegion(tt.req))
+		})
+	}
+}
+
+func TestRecordCalculation(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	req := shadowRequest()
+	reader := sdkmetric.NewManualReader()
+	metrics, err := telemetry.NewMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"))
+	assert.NoError(t, err)
+
+	// Act
+	RecordCalculation(ctx, metrics, req, &model.CalculateShippingResponse{ShippingCost: money.FromMinor(1250)}, nil, 15*time.Millisecond)
+	RecordCalculation(ctx, metrics, req, nil, invalidField("weight", errors.New("weight is required")), time.Millisecond)
+	RecordCalculation(ctx, metrics, nil, nil, errors.New("unexpected EOF"), 0)
+	RecordCalculation(ctx, nil, req, nil, errors.New("unexpected EOF"), 0)
+
+	// Assert
+	var collected metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(ctx, &collected))
+	counts := map[string]int64{}
+	for _, scope := range collected.ScopeMetrics {
+		for _, recorded := range scope.Metrics {
+			if sum, ok := recorded.Data.(metricdata.Sum[int64]); ok {
+				for _, point := range sum.DataPoints {
+					counts[recorded.Name] += point.Value
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{"shipping.calculate": 3, "shipping.calculate.error": 2}, counts)
+}
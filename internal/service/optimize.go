@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+)
+
+// ErrUnsupportedOptimization is returned when a quote is requested with an unknown optimize objective
+var ErrUnsupportedOptimization = errors.New("unsupported optimize objective")
+
+// ErrOptimizeWithExpress is returned when a quote selects its service level both with optimize and is_express
+var ErrOptimizeWithExpress = errors.New("optimize selects the service level and cannot be combined with is_express")
+
+// resolveOptimization normalizes the optimize objective of a request; "" keeps the selection of
+// is_express
+func resolveOptimization(optimize string, isExpress bool) (string, error) {
+	objective := strings.ToLower(strings.TrimSpace(optimize))
+	switch objective {
+	case "":
+		return "", nil
+	case model.OptimizeCheapest, model.OptimizeFastest, model.OptimizeBalanced:
+		if isExpress {
+			return "", ErrOptimizeWithExpress
+		}
+		return objective, nil
+	default:
+		return "", fmt.Errorf("%w %q, expected cheapest, fastest or balanced", ErrUnsupportedOptimization, optimize)
+	}
+}
+
+// markHints flags the options with the lowest cost and the fewest delivery days
+func markHints(options []model.ShippingOption) {
+	if len(options) == 0 {
+		return
+	}
+	cheapest, fastest := options[0].Cost, options[0].EstimatedDays
+	for _, option := range options[1:] {
+		cheapest = min(cheapest, option.Cost)
+		fastest = min(fastest, option.EstimatedDays)
+	}
+	for i := range options {
+		options[i].Cheapest = options[i].Cost == cheapest
+		options[i].Fastest = options[i].EstimatedDays == fastest
+	}
+}
+
+// orderOptions sorts the options by an objective, best first. Cheapest breaks ties by delivery
+// days, fastest and balanced by cost; balanced ranks by the sum of the cost relative to the
+// cheapest option and the delivery days relative to the fastest. Options still tied keep their order
+func orderOptions(options []model.ShippingOption, objective string) {
+	if len(options) == 0 {
+		return
+	}
+	cheapest, fastest := options[0].Cost.Minor(), float64(options[0].EstimatedDays)
+	for _, option := range options[1:] {
+		cheapest = min(cheapest, option.Cost.Minor())
+		fastest = min(fastest, float64(option.EstimatedDays))
+	}
+	score := func(option model.ShippingOption) float64 {
+		return relative(option.Cost.Minor(), cheapest) + relative(float64(option.EstimatedDays), fastest)
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		a, b := options[i], options[j]
+		switch objective {
+		case model.OptimizeCheapest:
+			if a.Cost != b.Cost {
+				return a.Cost < b.Cost
+			}
+			return a.EstimatedDays < b.EstimatedDays
+		case model.OptimizeFastest:
+			if a.EstimatedDays != b.EstimatedDays {
+				return a.EstimatedDays < b.EstimatedDays
+			}
+			return a.Cost < b.Cost
+		default:
+			if scoreA, scoreB := score(a), score(b); scoreA != scoreB {
+				return scoreA < scoreB
+			}
+			return a.Cost < b.Cost
+		}
+	})
+}
+
+// relative is value as a multiple of best, or value itself when best is not positive
+func relative(value, best float64) float64 {
+	if best <= 0 {
+		return value
+	}
+	return value / best
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func optimizeOptions() []model.ShippingOption {
+	return []model.ShippingOption{
+		{Service: model.ServiceStandard, Cost: money.FromMinor(1000), EstimatedDays: 5},
+		{Service: model.ServiceExpress, Cost: money.FromMinor(1500), EstimatedDays: 1},
+		{Service: model.ServiceFreight, Cost: money.FromMinor(1000), EstimatedDays: 4},
+	}
+}
+
+func TestOrderOptions(t *testing.T) {
+	tests := []struct {
+		objective string
+		want      []string
+	}{
+		{model.OptimizeCheapest, []string{model.ServiceFreight, model.ServiceStandard, model.ServiceExpress}},
+		{model.OptimizeFastest, []string{model.ServiceExpress, model.ServiceFreight, model.ServiceStandard}},
+		// 1.5 + 1 for express, 1 + 4 for freight and 1 + 5 for standard
+		{model.OptimizeBalanced, []string{model.ServiceExpress, model.ServiceFreight, model.ServiceStandard}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.objective, func(t *testing.T) {
+			// Arrange
+			options := optimizeOptions()
+
+			// Act
+			orderOptions(options, tt.objective)
+
+			// Assert
+			services := make([]string, len(options))
+			for i, option := range options {
+				services[i] = option.Service
+			}
+			assert.Equal(t, tt.want, services)
+		})
+	}
+}
+
+func TestMarkHints(t *testing.T) {
+	// Arrange
+	options := optimizeOptions()
+
+	// Act
+	markHints(options)
+
+	// Assert
+	assert.Equal(t, []bool{true, false, true}, []bool{options[0].Cheapest, options[1].Cheapest, options[2].Cheapest})
+	assert.Equal(t, []bool{false, true, false}, []bool{options[0].Fastest, options[1].Fastest, options[2].Fastest})
+}
+
+func TestResolveOptimization(t *testing.T) {
+	tests := []struct {
+		name      string
+		optimize  string
+		isExpress bool
+		want      string
+		wantErr   error
+	}{
+		{"not requested", "", false, "", nil},
+		{"normalized", " Fastest ", false, model.OptimizeFastest, nil},
+		{"unknown objective", "greenest", false, "", ErrUnsupportedOptimization},
+		{"with is_express", model.OptimizeCheapest, true, "", ErrOptimizeWithExpress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := resolveOptimization(tt.optimize, tt.isExpress)
+
+			// Assert
+			assert.Equal(t, tt.want, got)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
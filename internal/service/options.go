@@ -0,0 +1,56 @@
+package service
+
+import (
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+)
+
+// Option customizes a shipping service created by NewShippingService
+type Option func(*Config)
+
+// WithPricingConfig prices with the rates of cfg instead of pricing.DefaultConfig
+func WithPricingConfig(cfg pricing.Config) Option {
+	return func(c *Config) {
+		c.Pricing = &cfg
+	}
+}
+
+// WithDistanceResolver measures the routes priced by the formula strategy with resolver, e.g.
+// a geocoding service, instead of the numeric difference between the zipcodes
+func WithDistanceResolver(resolver pricing.DistanceResolver) Option {
+	return func(c *Config) {
+		c.Distance = resolver
+	}
+}
+
+// WithClock estimates deliveries and checks pickup cutoffs at the time told by clock, e.g. a
+// determinism.FixedClock in tests
+func WithClock(clock determinism.Clock) Option {
+	return func(c *Config) {
+		c.Clock = clock
+	}
+}
+
+// WithCache keeps the quotes in cache for ttl
+func WithCache(cache store.QuoteStore, ttl time.Duration) Option {
+	return func(c *Config) {
+		c.Cache = cache
+		c.CacheTTL = ttl
+	}
+}
+
+// WithProviders registers pricing strategies by name, e.g. pricing.StrategyCarrier backed by a
+// carrier rate API; a provider replaces the built-in strategy of the same name
+func WithProviders(providers map[string]pricing.Strategy) Option {
+	return func(c *Config) {
+		if c.Strategies == nil {
+			c.Strategies = make(map[string]pricing.Strategy, len(providers))
+		}
+		for name, strategy := range providers {
+			c.Strategies[name] = strategy
+		}
+	}
+}
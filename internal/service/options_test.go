@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func optionsRequest() *model.CalculateShippingRequest {
+	return &model.CalculateShippingRequest{
+		OriginZipcode:      "01310100",
+		DestinationZipcode: "04547130",
+		Weight:             1,
+		Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+	}
+}
+
+// countingStrategy prices every shipment with a fixed base cost, counting the shipments priced
+type countingStrategy struct {
+	calls int
+}
+
+func (s *countingStrategy) Price(_ context.Context, _ pricing.Shipment) (pricing.Freight, error) {
+	s.calls++
+	return pricing.Freight{BaseCost: money.FromMinor(2000)}, nil
+}
+
+func TestNewShippingService_WithPricingConfig(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	rates := cfg.Currencies["BRL"]
+	rates.BaseCost = money.FromMinor(2000)
+	cfg.Currencies["BRL"] = rates
+
+	// Act
+	defaults, defaultsErr := NewShippingService().CalculateShipping(context.Background(), optionsRequest())
+	custom, customErr := NewShippingService(WithPricingConfig(cfg)).CalculateShipping(context.Background(), optionsRequest())
+
+	// Assert
+	assert.NoError(t, defaultsErr)
+	assert.NoError(t, customErr)
+	assert.Greater(t, custom.ShippingCost, defaults.ShippingCost)
+	assert.NotEqual(t, defaults.PricingVersion, custom.PricingVersion)
+}
+
+func TestNewShippingService_WithDistanceResolver(t *testing.T) {
+	// Arrange
+	resolver := pricing.DistanceFunc(func(_, _ string) (float64, bool) {
+		return 20000, true
+	})
+	service := NewShippingService(WithDistanceResolver(resolver))
+
+	// Act
+	explanation, err := service.Explain(context.Background(), optionsRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 20000.0, explanation.Route.Distance)
+	assert.Equal(t, money.FromMinor(3000), explanation.Quote.Breakdown.BaseCost)
+}
+
+func TestNewShippingService_WithClock(t *testing.T) {
+	// Arrange
+	clock := determinism.FixedClock{Time: time.Date(2025, 3, 7, 13, 0, 0, 0, time.UTC)}
+	req := optionsRequest()
+	req.PickupDate = "2025-03-10"
+	req.PickupWindow = "morning"
+
+	// Act
+	pinned, pinnedErr := NewShippingService(WithClock(clock)).CalculateShipping(context.Background(), req)
+	_, systemErr := NewShippingService().CalculateShipping(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, pinnedErr)
+	assert.NotNil(t, pinned)
+	assert.ErrorContains(t, systemErr, "in the past")
+}
+
+func TestNewShippingService_WithProviders(t *testing.T) {
+	// Arrange
+	provider := &countingStrategy{}
+	service := NewShippingService(WithProviders(map[string]pricing.Strategy{"partner": provider}))
+	req := optionsRequest()
+	req.PricingStrategy = "partner"
+
+	// Act
+	response, err := service.CalculateShipping(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, provider.calls, "standard and express")
+	assert.Equal(t, money.FromMinor(2000), response.Breakdown.BaseCost)
+	assert.Contains(t, service.supportedStrategies(), "partner")
+}
+
+func TestNewShippingService_WithCache(t *testing.T) {
+	// Arrange
+	provider := &countingStrategy{}
+	service := NewShippingService(
+		WithProviders(map[string]pricing.Strategy{pricing.StrategyFormula: provider}),
+		WithCache(store.NewMemoryStore(), time.Minute),
+	)
+
+	// Act
+	first, firstErr := service.CalculateShipping(context.Background(), optionsRequest())
+	second, secondErr := service.CalculateShipping(context.Background(), optionsRequest())
+	other := optionsRequest()
+	other.Weight = 2
+	_, otherErr := service.CalculateShipping(context.Background(), other)
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	assert.NoError(t, otherErr)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 4, provider.calls, "the repeated request is served from the cache")
+}
+
+func TestNewShippingService_WithCache_NewRateTable(t *testing.T) {
+	// Arrange
+	service := NewShippingService(WithCache(store.NewMemoryStore(), time.Minute))
+	before, err := service.CalculateShipping(context.Background(), optionsRequest())
+	assert.NoError(t, err)
+	cfg := pricing.DefaultConfig()
+	rates := cfg.Currencies["BRL"]
+	rates.BaseCost = money.FromMinor(2000)
+	cfg.Currencies["BRL"] = rates
+	assert.NoError(t, service.SetPricing(cfg))
+
+	// Act
+	after, err := service.CalculateShipping(context.Background(), optionsRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEqual(t, before.PricingVersion, after.PricingVersion)
+	assert.Greater(t, after.ShippingCost, before.ShippingCost)
+}
+
+// steppingClock tells a time that the test moves forward
+type steppingClock struct {
+	now time.Time
+}
+
+func (c *steppingClock) Now() time.Time {
+	return c.now
+}
+
+func TestNewShippingService_WithCache_NotCached(t *testing.T) {
+	tests := []struct {
+		name    string
+		request func() *model.CalculateShippingRequest
+		advance time.Duration
+	}{
+		{
+			name:    "next day at the origin",
+			request: optionsRequest,
+			// From 23:00 to 01:00 in São Paulo
+			advance: 2 * time.Hour,
+		},
+		{
+			name: "scheduled pickup",
+			request: func() *model.CalculateShippingRequest {
+				req := optionsRequest()
+				req.PickupDate, req.PickupWindow = "2025-03-11", "morning"
+				return req
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			provider := &countingStrategy{}
+			// Monday, 23:00 in São Paulo
+			clock := &steppingClock{now: time.Date(2025, 3, 11, 2, 0, 0, 0, time.UTC)}
+			service := NewShippingService(
+				WithClock(clock),
+				WithProviders(map[string]pricing.Strategy{pricing.StrategyFormula: provider}),
+				WithCache(store.NewMemoryStore(), time.Hour),
+			)
+			_, err := service.CalculateShipping(context.Background(), tt.request())
+			assert.NoError(t, err)
+			clock.now = clock.now.Add(tt.advance)
+
+			// Act
+			_, err = service.CalculateShipping(context.Background(), tt.request())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 4, provider.calls, "the quote is priced again")
+		})
+	}
+}
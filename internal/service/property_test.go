@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+// Property-based tests of the cost: quick generates the packages, and the properties must hold
+// for every one of them
+
+// specialFloats are inputs the random generator is unlikely to produce
+var specialFloats = []float64{math.NaN(), math.Inf(1), math.Inf(-1), 0, -1, math.MaxFloat64, math.SmallestNonzeroFloat64}
+
+// packageMeasures generates the weight and dimensions of a parcel: up to maxWeight kg and sides of
+// up to 24 cm, within the parcel volume limit
+func packageMeasures(maxWeight float64) func(args []reflect.Value, r *rand.Rand) {
+	return func(args []reflect.Value, r *rand.Rand) {
+		args[0] = reflect.ValueOf(0.001 + r.Float64()*maxWeight)
+		for i := 1; i < len(args); i++ {
+			args[i] = reflect.ValueOf(0.1 + r.Float64()*24)
+		}
+	}
+}
+
+func propertyRequest(weight, length, width, height float64) *model.CalculateShippingRequest {
+	return &model.CalculateShippingRequest{
+		OriginZipcode:      "01310100",
+		DestinationZipcode: "20040002",
+		Weight:             weight,
+		Dimensions:         model.PackageDimensions{Length: length, Width: width, Height: height},
+	}
+}
+
+// validCost reports whether a quote and each of its options cost a non-negative amount within
+// money.MaxAmount
+func validCost(response *model.CalculateShippingResponse) bool {
+	if response.ShippingCost < 0 || response.ShippingCost >= money.MaxAmount {
+		return false
+	}
+	for _, option := range response.ShippingOptions {
+		if option.Cost < 0 || option.Cost >= money.MaxAmount {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCalculateShipping_Property_ArbitraryInputs(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	cfg := &quick.Config{
+		MaxCount: 500,
+		Values: func(args []reflect.Value, r *rand.Rand) {
+			for i := range args {
+				value := r.NormFloat64() * math.Pow(10, float64(r.Intn(12)))
+				if r.Intn(4) == 0 {
+					value = specialFloats[r.Intn(len(specialFloats))]
+				}
+				args[i] = reflect.ValueOf(value)
+			}
+		},
+	}
+
+	// Act
+	err := quick.Check(func(weight, length, width, height float64) bool {
+		response, err := service.CalculateShipping(context.Background(), propertyRequest(weight, length, width, height))
+		if err != nil {
+			return true
+		}
+		return validCost(response)
+	}, cfg)
+
+	// Assert
+	assert.NoError(t, err, "every accepted package has a finite, non-negative cost")
+}
+
+func TestCalculateShipping_Property_NonFiniteInputsRejected(t *testing.T) {
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		requests := []*model.CalculateShippingRequest{
+			propertyRequest(value, 10, 10, 10),
+			propertyRequest(1, value, 10, 10),
+			propertyRequest(1, 10, value, 10),
+			propertyRequest(1, 10, 10, value),
+		}
+		for _, req := range requests {
+			// Act
+			response, err := NewShippingService().CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.Nil(t, response)
+			var validationErr *ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		}
+	}
+}
+
+func TestCalculateShipping_Property_MonotonicInWeight(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	cfg := &quick.Config{MaxCount: 300, Values: packageMeasures(1000)}
+
+	// Act
+	err := quick.Check(func(weight, length, width, height float64) bool {
+		lighter, lighterErr := service.CalculateShipping(context.Background(), propertyRequest(weight, length, width, height))
+		heavier, heavierErr := service.CalculateShipping(context.Background(), propertyRequest(weight*1.5, length, width, height))
+		if lighterErr != nil || heavierErr != nil {
+			return false
+		}
+		return validCost(lighter) && validCost(heavier) && lighter.ShippingCost <= heavier.ShippingCost
+	}, cfg)
+
+	// Assert
+	assert.NoError(t, err, "a heavier package never costs less")
+}
+
+func TestCalculateShipping_Property_MonotonicInVolume(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	cfg := &quick.Config{MaxCount: 300, Values: packageMeasures(30)}
+
+	// Act
+	err := quick.Check(func(weight, length, width, height float64) bool {
+		smaller, smallerErr := service.CalculateShipping(context.Background(), propertyRequest(weight, length*0.5, width, height))
+		larger, largerErr := service.CalculateShipping(context.Background(), propertyRequest(weight, length, width, height))
+		if smallerErr != nil || largerErr != nil {
+			return false
+		}
+		return validCost(smaller) && validCost(larger) && smaller.ShippingCost <= larger.ShippingCost
+	}, cfg)
+
+	// Assert
+	assert.NoError(t, err, "a larger package never costs less")
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rbonfanti/shipping-calculator/internal/experiment"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"go.uber.org/zap"
+)
+
+// shadowQuote prices the request with the shadow rate table in the background and reports
+// differences from the primary response. The shadow result is never returned to the caller, and
+// dry runs, degraded, historical and cache warm-up quotes and quotes of tenants other than the
+// default are not compared. At most Shadow.Concurrency shadow quotes run at a time; quotes arriving while all are
+// busy are counted as dropped and not compared
+func (s *ShippingService) shadowQuote(ctx context.Context, req *model.CalculateShippingRequest, primary *model.CalculateShippingResponse) {
+	if s.shadow == nil || IsDryRun(ctx) || IsDegraded(ctx) || isHistorical(ctx) || isCacheWarmup(ctx) || tenant.FromContext(ctx) != tenant.Default {
+		return
+	}
+
+	zapLogger := logger.FromContext(ctx)
+	// The shadow quote outlives the request and must neither repeat the primary calculation logs
+	// nor add to its timing
+	shadowCtx := logger.NewContext(withoutTiming(context.WithoutCancel(ctx)), zap.NewNop())
+	request := *req
+	primaryCost, primaryCurrency := primary.ShippingCost.Minor(), primary.Currency
+
+	select {
+	case s.shadowSlots <- struct{}{}:
+	default:
+		s.metrics.IncrementPricingShadowComparison(shadowCtx, experiment.ShadowDropped)
+		return
+	}
+	s.shadowWG.Add(1)
+	go func() {
+		defer s.shadowWG.Done()
+		defer func() { <-s.shadowSlots }()
+
+		response, err := s.shadow.CalculateShipping(shadowCtx, &request)
+		if err != nil {
+			s.metrics.IncrementPricingShadowComparison(shadowCtx, experiment.ShadowError)
+			zapLogger.Warn("Falha no cálculo sombra", zap.Error(err))
+			return
+		}
+		if response.Currency != primaryCurrency {
+			err := fmt.Errorf("shadow currency %s differs from primary currency %s", response.Currency, primaryCurrency)
+			s.metrics.IncrementPricingShadowComparison(shadowCtx, experiment.ShadowError)
+			zapLogger.Warn("Falha no cálculo sombra", zap.Error(err))
+			return
+		}
+
+		shadowCost := response.ShippingCost.Minor()
+		difference, outcome := s.shadowCompare.Compare(primaryCost, shadowCost)
+		s.metrics.IncrementPricingShadowComparison(shadowCtx, outcome)
+		s.metrics.RecordPricingShadowDifference(shadowCtx, difference)
+		if outcome == experiment.ShadowDiverged {
+			zapLogger.Warn("Divergência no cálculo sombra",
+				zap.Float64("custo_principal", primaryCost),
+				zap.Float64("custo_sombra", shadowCost),
+				zap.Float64("diferença_relativa", difference),
+				zap.String("versão_tarifas", primary.PricingVersion),
+				zap.String("versão_tarifas_sombra", response.PricingVersion),
+			)
+		}
+	}()
+}
+
+// WaitShadow blocks until the pending shadow quotes finish, e.g. during shutdown
+func (s *ShippingService) WaitShadow() {
+	s.shadowWG.Wait()
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/experiment"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func shadowRequest() *model.CalculateShippingRequest {
+	return &model.CalculateShippingRequest{
+		OriginZipcode:      "12345678",
+		DestinationZipcode: "12345678",
+		Weight:             1,
+		Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+	}
+}
+
+func TestCalculateShipping_Shadow(t *testing.T) {
+	tests := []struct {
+		name         string
+		baseCost     float64
+		currency     string
+		wantWarnings []string
+	}{
+		{"matching shadow", 1000, "BRL", nil},
+		{"diverging shadow", 2000, "BRL", []string{"Divergência no cálculo sombra"}},
+		{"shadow in another currency", 1000, "USD", []string{"Falha no cálculo sombra"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			secondary := pricing.DefaultConfig()
+			secondary.Version = "next"
+			rates := secondary.Currencies["BRL"]
+			rates.BaseCost = money.FromMinor(tt.baseCost)
+			secondary.Currencies["BRL"] = rates
+			secondary.Countries["BR"] = tt.currency
+			service := NewShippingServiceWithConfig(Config{Shadow: &experiment.Shadow{Pricing: secondary, Threshold: 0.05}})
+
+			core, logs := observer.New(zapcore.WarnLevel)
+			ctx := logger.NewContext(context.Background(), zap.New(core))
+
+			// Act
+			response, err := service.CalculateShipping(ctx, shadowRequest())
+			service.WaitShadow()
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, money.FromMinor(1250), response.ShippingCost)
+			assert.Equal(t, pricing.DefaultConfig().VersionID(), response.PricingVersion)
+			var warnings []string
+			for _, entry := range logs.All() {
+				warnings = append(warnings, entry.Message)
+			}
+			assert.Equal(t, tt.wantWarnings, warnings)
+		})
+	}
+}
+
+func TestCalculateShipping_ShadowDivergenceFields(t *testing.T) {
+	// Arrange
+	secondary := pricing.DefaultConfig()
+	secondary.Version = "next"
+	rates := secondary.Currencies["BRL"]
+	rates.BaseCost = money.FromMinor(2000)
+	secondary.Currencies["BRL"] = rates
+	service := NewShippingServiceWithConfig(Config{Shadow: &experiment.Shadow{Pricing: secondary, Threshold: 0.05}})
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	ctx := logger.NewContext(context.Background(), zap.New(core))
+
+	// Act
+	_, err := service.CalculateShipping(ctx, shadowRequest())
+	service.WaitShadow()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, 1250.0, fields["custo_principal"])
+	assert.Equal(t, 2500.0, fields["custo_sombra"])
+	assert.Equal(t, 1.0, fields["diferença_relativa"])
+	assert.Equal(t, "next", fields["versão_tarifas_sombra"])
+}
+
+func TestCalculateShipping_ShadowSaturated(t *testing.T) {
+	// Arrange
+	secondary := pricing.DefaultConfig()
+	secondary.Version = "next"
+	rates := secondary.Currencies["BRL"]
+	rates.BaseCost = money.FromMinor(2000)
+	secondary.Currencies["BRL"] = rates
+	service := NewShippingServiceWithConfig(Config{Shadow: &experiment.Shadow{Pricing: secondary, Threshold: 0.05, Concurrency: 1}})
+	service.shadowSlots <- struct{}{}
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	ctx := logger.NewContext(context.Background(), zap.New(core))
+
+	// Act
+	_, err := service.CalculateShipping(ctx, shadowRequest())
+	service.WaitShadow()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Zero(t, logs.Len(), "the quote is not compared while the shadow quotes are busy")
+	assert.Len(t, service.shadowSlots, 1)
+}
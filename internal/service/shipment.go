@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/schedule"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/rbonfanti/shipping-calculator/internal/units"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidBooking is returned when a booking request is malformed or asks for a service the quote does not offer
+	ErrInvalidBooking = errors.New("invalid booking")
+	// ErrQuoteNotFound is returned when the quote to book does not exist
+	ErrQuoteNotFound = errors.New("quote not found")
+	// ErrQuoteExpired is returned when the quote to book has expired and must be revalidated
+	ErrQuoteExpired = errors.New("quote expired")
+	// ErrQuoteAlreadyBooked is returned when a shipment was already booked from the quote
+	ErrQuoteAlreadyBooked = errors.New("quote already booked")
+	// ErrPickupUnavailable is returned when the pickup scheduled by the quote can no longer be
+	// booked, e.g. its window is full or its cutoff has passed, and the quote must be revalidated
+	ErrPickupUnavailable = errors.New("pickup unavailable")
+)
+
+// ShipmentService books shipments from persisted quotes
+type ShipmentService struct {
+	quotes    repository.QuoteRepository
+	shipments repository.ShipmentRepository
+	events    events.Publisher
+	scheduler *schedule.Scheduler
+	clock     determinism.Clock
+	ids       determinism.IDGenerator
+}
+
+// NewShipmentService creates a shipment service booking quotes from quotes into shipments and
+// publishing the booking events to publisher
+func NewShipmentService(quotes repository.QuoteRepository, shipments repository.ShipmentRepository, publisher events.Publisher) *ShipmentService {
+	return &ShipmentService{
+		quotes:    quotes,
+		shipments: shipments,
+		events:    publisher,
+		scheduler: schedule.NewScheduler(schedule.DefaultConfig()),
+		clock:     determinism.SystemClock{},
+		ids:       determinism.UUIDGenerator{},
+	}
+}
+
+// WithScheduler makes the service book the pickups scheduled by the quotes in the windows of
+// scheduler, the one the quotes were priced with
+func (s *ShipmentService) WithScheduler(scheduler *schedule.Scheduler) *ShipmentService {
+	s.scheduler = scheduler
+	return s
+}
+
+// WithDeterminism stamps the shipments with the time told by clock and identifies them with ids
+// instead of the system clock and random UUIDs, as the quotes of the deterministic mode are, and
+// returns the service
+func (s *ShipmentService) WithDeterminism(clock determinism.Clock, ids determinism.IDGenerator) *ShipmentService {
+	s.clock = clock
+	s.ids = ids
+	return s
+}
+
+// Book converts a quote into a booked shipment at the quoted price. The quote must not have
+// expired, and can be booked only once; its scheduled pickup, if any, takes a slot of its window
+func (s *ShipmentService) Book(ctx context.Context, req *model.BookShipmentRequest) (*model.Shipment, error) {
+	zapLogger := logger.FromContext(ctx)
+
+	if strings.TrimSpace(req.QuoteID) == "" {
+		return nil, fmt.Errorf("%w: quote_id is required", ErrInvalidBooking)
+	}
+	quote, err := s.quotes.Get(ctx, req.QuoteID)
+	if err == nil && !quote.BelongsTo(tenant.FromContext(ctx)) {
+		err = repository.ErrNotFound
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrQuoteNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quote: %w", err)
+	}
+
+	now := s.clock.Now().UTC()
+	if quote.Expired(now) {
+		zapLogger.Warn("Reserva de envio com cotação vencida",
+			zap.String("quote_id", quote.ID),
+			zap.Time("expires_at", quote.ExpiresAt),
+		)
+		return nil, ErrQuoteExpired
+	}
+
+	service := strings.ToLower(strings.TrimSpace(req.Service))
+	if service == "" {
+		service = quote.Response.SelectedService
+	}
+	if service == "" {
+		// Quotes stored before the selected service was recorded
+		service = model.ServiceStandard
+		if quote.Request.IsExpress {
+			service = model.ServiceExpress
+		}
+	}
+	option, ok := quotedOption(quote.Response.ShippingOptions, service)
+	if !ok {
+		return nil, fmt.Errorf("%w: service %q was not quoted", ErrInvalidBooking, service)
+	}
+
+	shipment := &model.Shipment{
+		ID:                    s.ids.NewID(),
+		QuoteID:               quote.ID,
+		Status:                model.ShipmentStatusBooked,
+		Service:               service,
+		Tenant:                tenant.FromContext(ctx),
+		Currency:              quote.Response.Currency,
+		Cost:                  option.Cost,
+		EstimatedDeliveryTime: option.Time,
+		PricingVersion:        quote.PricingVersion,
+		PromisedDate:          promisedDate(quote, option, now),
+		Package: model.ShipmentPackage{
+			OriginZipcode:      quote.Request.OriginZipcode,
+			DestinationZipcode: quote.Request.DestinationZipcode,
+			DestinationCountry: quote.Request.DestinationCountry,
+			Weight:             quote.Request.Weight,
+			Dimensions:         quote.Request.Dimensions,
+			PackageType:        quote.Request.PackageType,
+			DeliveryType:       quote.Request.DeliveryType,
+			AdditionalServices: quote.Request.AdditionalServices,
+			IsReturn:           quote.Request.IsReturn,
+			FreightClass:       quote.Request.FreightClass,
+			PricingStrategy:    quote.Request.PricingStrategy,
+			WeightUnit:         quote.Request.WeightUnit,
+			DimensionUnit:      quote.Request.DimensionUnit,
+		},
+		BookedAt: now,
+	}
+	capacity, err := s.schedulePickup(ctx, quote, shipment)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.shipments.SaveWithinCapacity(ctx, shipment, capacity); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, ErrQuoteAlreadyBooked
+		}
+		if errors.Is(err, repository.ErrCapacityExceeded) {
+			zapLogger.Warn("Reserva de envio com janela de coleta lotada",
+				zap.String("quote_id", quote.ID),
+				zap.String("data_coleta", shipment.Package.PickupDate),
+				zap.String("janela_coleta", shipment.Package.PickupWindow),
+			)
+			return nil, fmt.Errorf("%w: pickup_window %q on %s: %w", ErrPickupUnavailable, shipment.Package.PickupWindow, shipment.Package.PickupDate, schedule.ErrWindowFull)
+		}
+		return nil, fmt.Errorf("failed to save shipment: %w", err)
+	}
+
+	zapLogger.Info("Envio reservado",
+		zap.String("shipment_id", shipment.ID),
+		zap.String("quote_id", shipment.QuoteID),
+		zap.String("serviço", shipment.Service),
+		zap.Float64("custo", shipment.Cost.Minor()),
+	)
+
+	// The shipment is booked even if the event cannot be published; consumers can catch up from the repository
+	event := events.Event{Type: events.ShipmentBooked, Subject: shipment.ID, Tenant: shipment.Tenant, OccurredAt: now, Data: shipment}
+	if err := s.events.Publish(ctx, event); err != nil {
+		zapLogger.Warn("Falha ao publicar evento",
+			zap.String("event_type", event.Type),
+			zap.String("shipment_id", shipment.ID),
+			zap.Error(err),
+		)
+	}
+	return shipment, nil
+}
+
+// schedulePickup checks the pickup scheduled by the quote again, as its window may have filled up or
+// its cutoff passed since quoting, and records it on the shipment. It returns the capacity of the
+// window, 0 when the quote schedules no pickup or the window has no limit
+func (s *ShipmentService) schedulePickup(ctx context.Context, quote *repository.Quote, shipment *model.Shipment) (int, error) {
+	if quote.Request.PickupDate == "" && quote.Request.PickupWindow == "" {
+		return 0, nil
+	}
+	// The weight was validated when quoting
+	weight, _ := units.ToKilograms(quote.Request.Weight, quote.Request.WeightUnit)
+	pickup, err := s.scheduler.Schedule(ctx, quote.Request.PickupDate, quote.Request.PickupWindow, weight)
+	if errors.Is(err, schedule.ErrInvalidPickup) {
+		logger.FromContext(ctx).Warn("Reserva de envio com coleta indisponível",
+			zap.String("quote_id", quote.ID),
+			zap.String("data_coleta", quote.Request.PickupDate),
+			zap.String("janela_coleta", quote.Request.PickupWindow),
+			zap.Error(err),
+		)
+		return 0, fmt.Errorf("%w: %w", ErrPickupUnavailable, err)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to schedule pickup: %w", err)
+	}
+	shipment.Package.PickupDate = pickup.Date.Format(schedule.DateLayout)
+	shipment.Package.PickupWindow = pickup.Window.Name
+	return pickup.Window.Capacity, nil
+}
+
+// quotedOption returns the shipping option of a service level
+func quotedOption(options []model.ShippingOption, service string) (model.ShippingOption, bool) {
+	for _, option := range options {
+		if option.Service == service {
+			return option, true
+		}
+	}
+	return model.ShippingOption{}, false
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/schedule"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// recordingPublisher keeps the published events, failing with err when set
+type recordingPublisher struct {
+	events []events.Event
+	err    error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event events.Event) error {
+	p.events = append(p.events, event)
+	return p.err
+}
+
+var bookingNow = time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+
+func newBookingService(t *testing.T, publisher events.Publisher) (*ShipmentService, *repository.MemoryShipmentRepository) {
+	t.Helper()
+	quotes := repository.NewMemoryQuoteRepository()
+	_ = quotes.Save(context.Background(), &repository.Quote{
+		ID: "q1",
+		Request: model.CalculateShippingRequest{
+			OriginZipcode:      "01310100",
+			DestinationZipcode: "04547130",
+			Weight:             2.5,
+			Dimensions:         model.PackageDimensions{Length: 30, Width: 20, Height: 15},
+			IsExpress:          true,
+			PackageType:        "fragile",
+			AdditionalServices: []string{"signature"},
+		},
+		Response: model.CalculateShippingResponse{
+			Currency: "BRL",
+			ShippingOptions: []model.ShippingOption{
+				{Service: model.ServiceStandard, Cost: money.FromMinor(1400), Time: "2 dias", EstimatedDays: 2},
+				{Service: model.ServiceExpress, Cost: money.FromMinor(1950), Time: "1 dia", EstimatedDays: 1},
+			},
+		},
+		CreatedAt:      bookingNow.Add(-24 * time.Hour),
+		ExpiresAt:      bookingNow.Add(time.Minute),
+		PricingVersion: "2025.03",
+	})
+	optimized, _ := quotes.Get(context.Background(), "q1")
+	optimized.ID = "optimized"
+	optimized.Request.IsExpress = false
+	optimized.Request.Optimize = model.OptimizeFastest
+	optimized.Response.SelectedService = model.ServiceExpress
+	_ = quotes.Save(context.Background(), optimized)
+	_ = quotes.Save(context.Background(), &repository.Quote{ID: "expired", ExpiresAt: bookingNow.Add(-time.Minute)})
+
+	shipments := repository.NewMemoryShipmentRepository()
+	service := NewShipmentService(quotes, shipments, publisher)
+	service.WithDeterminism(determinism.FixedClock{Time: bookingNow}, determinism.UUIDGenerator{})
+	return service, shipments
+}
+
+func TestBook(t *testing.T) {
+	tests := []struct {
+		name        string
+		quoteID     string
+		service     string
+		wantService string
+		wantCost    float64
+		wantTime    string
+		wantPromise string
+	}{
+		{"service selected in the quote", "q1", "", model.ServiceExpress, 1950, "1 dia", "2025-03-10"},
+		{"service selected by optimize", "optimized", "", model.ServiceExpress, 1950, "1 dia", "2025-03-10"},
+		{"chosen service", "q1", " Standard ", model.ServiceStandard, 1400, "2 dias", "2025-03-11"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			publisher := &recordingPublisher{}
+			service, shipments := newBookingService(t, publisher)
+
+			// Act
+			shipment, err := service.Book(context.Background(), &model.BookShipmentRequest{QuoteID: tt.quoteID, Service: tt.service})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.NotEmpty(t, shipment.ID)
+			assert.Equal(t, &model.Shipment{
+				ID:                    shipment.ID,
+				QuoteID:               tt.quoteID,
+				Status:                model.ShipmentStatusBooked,
+				Service:               tt.wantService,
+				Tenant:                tenant.Default,
+				Currency:              "BRL",
+				Cost:                  money.FromMinor(tt.wantCost),
+				EstimatedDeliveryTime: tt.wantTime,
+				PricingVersion:        "2025.03",
+				PromisedDate:          tt.wantPromise,
+				Package: model.ShipmentPackage{
+					OriginZipcode:      "01310100",
+					DestinationZipcode: "04547130",
+					Weight:             2.5,
+					Dimensions:         model.PackageDimensions{Length: 30, Width: 20, Height: 15},
+					PackageType:        "fragile",
+					AdditionalServices: []string{"signature"},
+				},
+				BookedAt: bookingNow,
+			}, shipment)
+
+			stored, getErr := shipments.Get(context.Background(), shipment.ID)
+			assert.NoError(t, getErr)
+			assert.Equal(t, shipment, stored)
+
+			assert.Equal(t, []events.Event{{Type: events.ShipmentBooked, Subject: shipment.ID, Tenant: tenant.Default, OccurredAt: bookingNow, Data: shipment}}, publisher.events)
+		})
+	}
+}
+
+func TestBook_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     model.BookShipmentRequest
+		wantErr error
+	}{
+		{"missing quote id", model.BookShipmentRequest{}, ErrInvalidBooking},
+		{"unknown quote", model.BookShipmentRequest{QuoteID: "missing"}, ErrQuoteNotFound},
+		{"expired quote", model.BookShipmentRequest{QuoteID: "expired"}, ErrQuoteExpired},
+		{"service not quoted", model.BookShipmentRequest{QuoteID: "q1", Service: "same_day"}, ErrInvalidBooking},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			publisher := &recordingPublisher{}
+			service, _ := newBookingService(t, publisher)
+
+			// Act
+			shipment, err := service.Book(context.Background(), &tt.req)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, shipment)
+			assert.Empty(t, publisher.events)
+		})
+	}
+}
+
+func TestBook_QuoteOfAnotherTenant(t *testing.T) {
+	// Arrange
+	publisher := &recordingPublisher{}
+	service, _ := newBookingService(t, publisher)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	// Act
+	shipment, err := service.Book(ctx, &model.BookShipmentRequest{QuoteID: "q1"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrQuoteNotFound)
+	assert.Nil(t, shipment)
+	assert.Empty(t, publisher.events)
+}
+
+func TestBook_QuoteBookedOnce(t *testing.T) {
+	// Arrange
+	service, _ := newBookingService(t, &recordingPublisher{})
+	_, _ = service.Book(context.Background(), &model.BookShipmentRequest{QuoteID: "q1"})
+
+	// Act
+	shipment, err := service.Book(context.Background(), &model.BookShipmentRequest{QuoteID: "q1", Service: model.ServiceStandard})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrQuoteAlreadyBooked)
+	assert.Nil(t, shipment)
+}
+
+func TestBook_Deterministic(t *testing.T) {
+	// Arrange
+	book := func() *model.Shipment {
+		service, _ := newBookingService(t, &recordingPublisher{})
+		service.WithDeterminism(determinism.FixedClock{Time: bookingNow}, determinism.NewSeededIDGenerator(42))
+		shipment, err := service.Book(context.Background(), &model.BookShipmentRequest{QuoteID: "q1"})
+		assert.NoError(t, err)
+		return shipment
+	}
+
+	// Act
+	first, second := book(), book()
+
+	// Assert
+	assert.Equal(t, determinism.NewSeededIDGenerator(42).NewID(), first.ID, "the shipment is identified by the seeded generator")
+	assert.Equal(t, first, second, "the same seed and clock book the same shipment")
+	assert.Equal(t, bookingNow, first.BookedAt)
+}
+
+func TestBook_PublishFailure(t *testing.T) {
+	// Arrange
+	service, shipments := newBookingService(t, &recordingPublisher{err: errors.New("broker unavailable")})
+	core, logs := observer.New(zapcore.WarnLevel)
+	ctx := logger.NewContext(context.Background(), zap.New(core))
+
+	// Act
+	shipment, err := service.Book(ctx, &model.BookShipmentRequest{QuoteID: "q1"})
+
+	// Assert
+	assert.NoError(t, err)
+	_, getErr := shipments.Get(context.Background(), shipment.ID)
+	assert.NoError(t, getErr)
+	assert.Equal(t, 1, logs.FilterMessage("Falha ao publicar evento").Len())
+}
+
+func TestBook_PickupCapacity(t *testing.T) {
+	// Arrange
+	service, shipments := newBookingService(t, &recordingPublisher{})
+	cfg := schedule.DefaultConfig()
+	cfg.Windows[0].Capacity = 1
+	service.WithScheduler(schedule.NewSchedulerWithClock(cfg, determinism.FixedClock{Time: bookingNow}).WithBookings(shipments))
+	original, _ := service.quotes.Get(context.Background(), "q1")
+	for _, id := range []string{"pickup-1", "pickup-2", "pickup-late"} {
+		quote := *original
+		quote.ID = id
+		quote.Request.PickupDate, quote.Request.PickupWindow = "2025-03-11", "MORNING"
+		if id == "pickup-late" {
+			quote.Request.PickupDate = "2025-03-10"
+		}
+		_ = service.quotes.Save(context.Background(), &quote)
+	}
+
+	// Act
+	first, firstErr := service.Book(context.Background(), &model.BookShipmentRequest{QuoteID: "pickup-1"})
+	second, secondErr := service.Book(context.Background(), &model.BookShipmentRequest{QuoteID: "pickup-2"})
+	late, lateErr := service.Book(context.Background(), &model.BookShipmentRequest{QuoteID: "pickup-late"})
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.Equal(t, "2025-03-11", first.Package.PickupDate)
+	assert.Equal(t, "morning", first.Package.PickupWindow)
+	assert.ErrorIs(t, secondErr, ErrPickupUnavailable)
+	assert.ErrorIs(t, secondErr, schedule.ErrWindowFull)
+	assert.Nil(t, second)
+	assert.ErrorIs(t, lateErr, ErrPickupUnavailable)
+	assert.ErrorContains(t, lateErr, "the cutoff for the morning window")
+	assert.Nil(t, late)
+	booked, _ := shipments.CountPickups(context.Background(), "2025-03-11", "morning")
+	assert.Equal(t, 1, booked)
+}
@@ -2,35 +2,58 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/rbonfanti/shipping-calculator/internal/customs"
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/rbonfanti/shipping-calculator/internal/eta"
+	"github.com/rbonfanti/shipping-calculator/internal/experiment"
 	"github.com/rbonfanti/shipping-calculator/internal/logger"
 	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/schedule"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/rbonfanti/shipping-calculator/internal/tax"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/rbonfanti/shipping-calculator/internal/timezone"
+	"github.com/rbonfanti/shipping-calculator/internal/units"
 	"github.com/rbonfanti/shipping-calculator/internal/validator"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
 	"go.uber.org/zap"
 )
 
+// Rates (base cost and surcharges) are configured per currency in the pricing package
 const (
-	// Base shipping cost in cents (10.00 BRL = 1000 cents)
-	baseCostCents = 1000.0
+	// Estimated delivery days of the routes missing from the transit matrix
+	standardDeliveryDays = 2
+	expressDeliveryDays  = 1
+)
 
-	// Weight surcharge: 10% of base cost per 0.5 kg
-	weightSurchargeRate = 0.10
-	weightUnit          = 0.5 // kg
+// ErrExpressNotAllowed is returned when express delivery is requested for a package type that prohibits it
+var ErrExpressNotAllowed = errors.New("express delivery is not allowed for this package type")
 
-	// Volume surcharge: 5% of base cost per 1.000 cm³
-	volumeSurchargeRate = 0.05
-	volumeUnit          = 1000.0 // cm³
+// ErrReturnServiceNotOffered is returned when a return is quoted with a service level the return policy does not offer
+var ErrReturnServiceNotOffered = errors.New("service level is not offered for returns")
 
-	// Express shipping surcharge: 50% of subtotal
-	expressSurchargeRate = 0.50
+// ErrUnknownTenant is returned when a quote is requested for a tenant without a pricing configuration
+var ErrUnknownTenant = errors.New("unknown tenant")
 
-	// Estimated delivery days
-	standardDeliveryDays = 2
-	expressDeliveryDays  = 1
-)
+// ErrFreightOnly is returned when express delivery is quoted for a package over the parcel volume limit
+var ErrFreightOnly = errors.New("packages over the parcel volume limit ship only as freight")
+
+// ErrNotServiceable is returned when a restricted area excludes the destination from the requested service level
+var ErrNotServiceable = errors.New("destination is not serviceable")
+
+// ErrCostOutOfRange is returned when a package is too heavy or large for its cost to be represented
+var ErrCostOutOfRange = errors.New("package exceeds the maximum quotable cost")
 
 // ShippingServiceInterface defines the contract for shipping calculation service
 type ShippingServiceInterface interface {
@@ -38,133 +61,957 @@ type ShippingServiceInterface interface {
 }
 
 // ShippingService handles shipping calculation business logic
-type ShippingService struct{}
+type ShippingService struct {
+	estimator        *eta.Estimator
+	table            atomic.Pointer[rateTable]
+	tenants          map[string]*rateTable
+	experiment       *experiment.Experiment
+	treatmentVersion string
+	shadow           *ShippingService
+	shadowCompare    *experiment.Shadow
+	shadowWG         sync.WaitGroup
+	shadowSlots      chan struct{}
+	strategies       map[string]pricing.Strategy
+	scheduler        *schedule.Scheduler
+	customs          *customs.Estimator
+	tax              *tax.Calculator
+	metrics          *telemetry.Metrics
+	distance         pricing.DistanceResolver
+	cache            store.QuoteStore
+	cacheTTL         time.Duration
+}
 
-// NewShippingService creates a new shipping service instance
-func NewShippingService() *ShippingService {
-	return &ShippingService{}
+// rateTable is a snapshot of the pricing configuration in force and its version. Snapshots are
+// never modified: a new configuration is validated and put in force by swapping the whole
+// snapshot, so quotes read it without locks and each quote is priced with a single one
+type rateTable struct {
+	config  pricing.Config
+	version string
+}
+
+// Config holds the dependencies of the shipping service; nil fields use the defaults
+type Config struct {
+	// Estimator adds warehouse handling time to carrier transit time; nil counts the days in the
+	// time zones of the origin and the destination, without handling time or holidays
+	Estimator *eta.Estimator
+	// Pricing holds the rates per currency and destination country
+	Pricing *pricing.Config
+	// Tenants holds the pricing configuration of each tenant besides tenant.Default, which is
+	// priced with Pricing. Experiments and shadow pricing only apply to the default tenant
+	Tenants map[string]pricing.Config
+	// Experiment, when set, prices a fraction of the quotes with an alternative rate table
+	Experiment *experiment.Experiment
+	// Shadow, when set, also prices every quote with a secondary rate table in the background
+	// and reports differences, without changing the returned quote
+	Shadow *experiment.Shadow
+	// Strategies registers pricing strategies by name on top of the built-in formula and table
+	// strategies, e.g. pricing.StrategyCarrier backed by a carrier rate API
+	Strategies map[string]pricing.Strategy
+	// Scheduler validates scheduled pickups against the pickup windows
+	Scheduler *schedule.Scheduler
+	// Customs estimates the import duties and taxes of international shipments
+	Customs *customs.Estimator
+	// Tax, when set, itemizes the ICMS or ISS included in the freight of domestic routes
+	Tax *tax.Calculator
+	// Metrics records the shadow pricing comparisons; nil records nothing
+	Metrics *telemetry.Metrics
+	// Distance measures the routes priced by the formula strategy; nil uses the numeric
+	// difference between the zipcodes
+	Distance pricing.DistanceResolver
+	// Clock tells the time of the default Estimator and Scheduler; nil uses the system clock
+	Clock determinism.Clock
+	// Cache, when set, keeps the quotes for CacheTTL, so that repeated requests for the same
+	// package and route with the same rate table are not priced again
+	Cache    store.QuoteStore
+	CacheTTL time.Duration
+}
+
+// NewShippingService creates a new shipping service instance with the default configuration
+// customized by opts
+func NewShippingService(opts ...Option) *ShippingService {
+	var cfg Config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return NewShippingServiceWithConfig(cfg)
+}
+
+// NewShippingServiceWithConfig creates a new shipping service instance with the given dependencies
+func NewShippingServiceWithConfig(cfg Config) *ShippingService {
+	if cfg.Clock == nil {
+		cfg.Clock = determinism.SystemClock{}
+	}
+	if cfg.Estimator == nil {
+		cfg.Estimator = eta.NewEstimatorWithClock(eta.Config{}, nil, cfg.Clock).WithTimezones(timezone.NewResolver())
+	}
+	if cfg.Pricing == nil {
+		defaults := pricing.DefaultConfig()
+		cfg.Pricing = &defaults
+	}
+	if cfg.Scheduler == nil {
+		cfg.Scheduler = schedule.NewSchedulerWithClock(schedule.DefaultConfig(), cfg.Clock)
+	}
+	if cfg.Customs == nil {
+		cfg.Customs = customs.NewEstimator(customs.DefaultConfig())
+	}
+	s := &ShippingService{
+		estimator:  cfg.Estimator,
+		experiment: cfg.Experiment,
+		scheduler:  cfg.Scheduler,
+		customs:    cfg.Customs,
+		tax:        cfg.Tax,
+		metrics:    cfg.Metrics,
+		distance:   cfg.Distance,
+		cache:      cfg.Cache,
+		cacheTTL:   cfg.CacheTTL,
+		strategies: map[string]pricing.Strategy{
+			pricing.StrategyFormula: pricing.FormulaPricing{Distance: cfg.Distance},
+			pricing.StrategyTable:   pricing.TablePricing{},
+		},
+	}
+	for name, strategy := range cfg.Strategies {
+		s.strategies[name] = strategy
+	}
+	s.table.Store(&rateTable{config: *cfg.Pricing, version: cfg.Pricing.VersionID()})
+	s.tenants = make(map[string]*rateTable, len(cfg.Tenants))
+	for id, prices := range cfg.Tenants {
+		s.tenants[id] = &rateTable{config: prices, version: prices.VersionID()}
+	}
+	if cfg.Experiment != nil {
+		s.treatmentVersion = cfg.Experiment.Treatment.VersionID()
+	}
+	if cfg.Shadow != nil {
+		s.shadow = NewShippingServiceWithConfig(Config{Estimator: cfg.Estimator, Pricing: &cfg.Shadow.Pricing, Strategies: cfg.Strategies, Scheduler: cfg.Scheduler, Customs: cfg.Customs, Distance: cfg.Distance})
+		s.shadowCompare = cfg.Shadow
+		concurrency := cfg.Shadow.Concurrency
+		if concurrency <= 0 {
+			concurrency = experiment.DefaultShadowConcurrency
+		}
+		s.shadowSlots = make(chan struct{}, concurrency)
+	}
+	return s
 }
 
 // CalculateShipping calculates shipping cost and delivery time based on package details
 func (s *ShippingService) CalculateShipping(ctx context.Context, req *model.CalculateShippingRequest) (*model.CalculateShippingResponse, error) {
-	// Get logger from context with correlation_id
-	zapLogger := logger.GetLoggerFromContext(ctx, zap.L())
-
-	// Validate request
-	if err := validator.ValidateZipcode(req.OriginZipcode, "origin_zipcode"); err != nil {
-		logger.LogWarning(zapLogger, ctx, "Solicitação com parâmetros inválidos",
-			zap.String("param", "origin_zipcode"),
-			zap.String("valor", req.OriginZipcode),
-			zap.Error(err),
+	key, ok := s.quoteCacheKey(ctx, req)
+	if !ok {
+		return s.calculateShipping(ctx, req)
+	}
+	timing, start := timingFrom(ctx), time.Now()
+	response, ok := s.cachedQuote(ctx, key)
+	timing.since(StageCache, start)
+	if ok {
+		if trace := traceFrom(ctx); trace != nil {
+			trace.record(StepCache, "served from the quote cache with version %s", response.PricingVersion)
+		}
+		logger.FromContext(ctx).Debug("Cotação obtida do cache",
+			zap.String("versão_tarifas", response.PricingVersion),
 		)
-		return nil, fmt.Errorf("invalid origin_zipcode: %w", err)
+		return response, nil
 	}
+	response, err := s.calculateShipping(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	start = time.Now()
+	s.cacheQuote(ctx, key, response)
+	timing.since(StageCache, start)
+	return response, nil
+}
 
-	if err := validator.ValidateZipcode(req.DestinationZipcode, "destination_zipcode"); err != nil {
-		logger.LogWarning(zapLogger, ctx, "Solicitação com parâmetros inválidos",
-			zap.String("param", "destination_zipcode"),
-			zap.String("valor", req.DestinationZipcode),
-			zap.Error(err),
-		)
-		return nil, fmt.Errorf("invalid destination_zipcode: %w", err)
+// calculateShipping prices a quote, without the cache
+func (s *ShippingService) calculateShipping(ctx context.Context, req *model.CalculateShippingRequest) (*model.CalculateShippingResponse, error) {
+	// Get request-scoped logger (already carries correlation_id, trace_id and span_id)
+	zapLogger := logger.FromContext(ctx)
+
+	// Time the validation up to the pricing of the freight, also of the requests it rejects
+	timing, start := timingFrom(ctx), time.Now()
+	validated := false
+	defer func() {
+		if !validated {
+			timing.since(StageValidation, start)
+		}
+	}()
+
+	// Convert weight and dimensions to kilograms and centimeters; the rest of the calculation only
+	// sees canonical units
+	req, err := canonicalMeasures(zapLogger, req)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := validator.ValidateWeight(req.Weight); err != nil {
-		logger.LogWarning(zapLogger, ctx, "Solicitação com parâmetros inválidos",
+	// Validate request
+	if err := validateRoute(zapLogger, req.OriginZipcode, req.DestinationZipcode); err != nil {
+		return nil, err
+	}
+
+	weightErr := validator.ValidateRequired(req.MissingFields, "weight")
+	if weightErr == nil {
+		weightErr = validator.ValidateWeight(req.Weight)
+	}
+	if err := weightErr; err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
 			zap.String("param", "weight"),
 			zap.Float64("valor", req.Weight),
 			zap.Error(err),
 		)
-		return nil, fmt.Errorf("invalid weight: %w", err)
+		return nil, invalidField("weight", err)
 	}
 
 	volume := validator.CalculateVolume(req.Dimensions.Length, req.Dimensions.Width, req.Dimensions.Height)
-	if err := validator.ValidateDimensions(req.Dimensions.Length, req.Dimensions.Width, req.Dimensions.Height); err != nil {
-		logger.LogWarning(zapLogger, ctx, "Solicitação com parâmetros inválidos",
+	dimensionsErr := validator.ValidateRequired(req.MissingFields, "dimensions", "dimensions.length", "dimensions.width", "dimensions.height")
+	if dimensionsErr == nil {
+		dimensionsErr = validator.ValidatePositiveDimensions(req.Dimensions.Length, req.Dimensions.Width, req.Dimensions.Height)
+	}
+	if err := dimensionsErr; err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
 			zap.String("param", "dimensions"),
 			zap.Float64("volume", volume),
 			zap.Error(err),
 		)
-		return nil, fmt.Errorf("invalid dimensions: %w", err)
+		return nil, invalidField("dimensions", err)
+	}
+	// Packages over the parcel volume limit can only ship as freight, checked once the rates are known
+	volumeErr := validator.ValidateVolume(volume, validator.MaxVolumeCm3)
+
+	optimize, err := resolveOptimization(req.Optimize, req.IsExpress)
+	if err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "optimize"),
+			zap.String("valor", req.Optimize),
+			zap.Bool("expresso", req.IsExpress),
+			zap.Error(err),
+		)
+		return nil, invalidField("optimize", err)
+	}
+
+	// Select the rate table of the tenant; quotes in the treatment arm of a pricing experiment use its rates
+	trace := traceFrom(ctx)
+	prices, pricingVersion, assignment, err := s.pricingFor(ctx)
+	if err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "tenant"),
+			zap.String("valor", tenant.FromContext(ctx)),
+		)
+		return nil, err
+	}
+	if id := tenant.FromContext(ctx); id != tenant.Default {
+		trace.record(StepRateTable, "version %s of tenant %s", pricingVersion, id)
+	} else {
+		trace.record(StepRateTable, "version %s", pricingVersion)
+	}
+	if assignment != nil {
+		trace.record(StepExperiment, "%s assigned arm %s", assignment.Name, assignment.Arm)
+		zapLogger.Info("Atribuição de experimento de preço",
+			zap.String("experimento", assignment.Name),
+			zap.String("braço", assignment.Arm),
+			zap.String("versão_tarifas", pricingVersion),
+		)
+	}
+
+	// Select currency and rates by explicit currency or destination country
+	currency, rates, err := prices.Resolve(req.DestinationCountry, req.Currency)
+	if err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "currency"),
+			zap.String("país_destino", req.DestinationCountry),
+			zap.String("moeda", req.Currency),
+			zap.Error(err),
+		)
+		return nil, invalidField("currency", err)
+	}
+	trace.record(StepCurrency, "%s for destination country %s", currency, prices.Country(req.DestinationCountry))
+
+	// Package type adds a handling surcharge and may restrict express delivery
+	packageType, err := prices.ResolvePackageType(req.PackageType)
+	if err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "package_type"),
+			zap.String("valor", req.PackageType),
+			zap.Error(err),
+		)
+		return nil, invalidField("package_type", err)
+	}
+	if req.IsExpress && packageType.ExpressProhibited {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "package_type"),
+			zap.String("valor", req.PackageType),
+			zap.Bool("expresso", req.IsExpress),
+		)
+		return nil, invalidField("package_type", ErrExpressNotAllowed)
+	}
+	trace.record(StepPackageType, "%s: surcharge rate %g, express prohibited %t",
+		orDefault(req.PackageType, pricing.PackageStandard), packageType.SurchargeRate, packageType.ExpressProhibited)
+
+	// Delivery type adjusts the price, e.g. lockers are cheaper than home delivery
+	deliveryType, err := prices.ResolveDeliveryType(req.DeliveryType)
+	if err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "delivery_type"),
+			zap.String("valor", req.DeliveryType),
+			zap.Error(err),
+		)
+		return nil, invalidField("delivery_type", err)
+	}
+	trace.record(StepDeliveryType, "%s: cost adjustment rate %g", orDefault(req.DeliveryType, pricing.DeliveryHome), deliveryType.CostAdjustmentRate)
+
+	// Additional services are charged as fixed fees in the quote currency
+	additionalServices, err := resolveAdditionalServices(rates, req.AdditionalServices)
+	if err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "additional_services"),
+			zap.Strings("valor", req.AdditionalServices),
+			zap.Error(err),
+		)
+		return nil, invalidField("additional_services", err)
 	}
 
-	// Calculate base cost based on distance between zipcodes
-	baseCost := s.calculateBaseCost(req.OriginZipcode, req.DestinationZipcode)
+	// Returns travel from the destination back to the origin, with the adjustment and service
+	// levels of the return policy
+	origin, destination := req.OriginZipcode, req.DestinationZipcode
+	var returns pricing.ReturnPolicy
+	if req.IsReturn {
+		origin, destination = destination, origin
+		returns = prices.ResolveReturns()
+		if req.IsExpress && !returns.Offers(pricing.LevelExpress) {
+			zapLogger.Warn("Solicitação com parâmetros inválidos",
+				zap.String("param", "is_express"),
+				zap.Bool("expresso", req.IsExpress),
+				zap.Bool("devolução", req.IsReturn),
+			)
+			return nil, invalidField("is_express", ErrReturnServiceNotOffered)
+		}
+		trace.record(StepReturnPolicy, "cost adjustment rate %g, services %v", returns.CostAdjustmentRate, returns.Services)
+	}
+	offerExpress := !packageType.ExpressProhibited && (!req.IsReturn || returns.Offers(pricing.LevelExpress))
+
+	// A scheduled pickup starts the delivery estimate on the pickup date and may add the surcharge
+	// of its window or of a same-day pickup
+	var pickup *schedule.Pickup
+	var pickupRate float64
+	if req.PickupDate != "" || req.PickupWindow != "" {
+		scheduled, err := s.scheduler.Schedule(ctx, req.PickupDate, req.PickupWindow, req.Weight)
+		if err != nil && !errors.Is(err, schedule.ErrInvalidPickup) {
+			return nil, fmt.Errorf("failed to schedule pickup: %w", err)
+		}
+		if err != nil {
+			zapLogger.Warn("Solicitação com parâmetros inválidos",
+				zap.String("param", "pickup"),
+				zap.String("data_coleta", req.PickupDate),
+				zap.String("janela_coleta", req.PickupWindow),
+				zap.Error(err),
+			)
+			return nil, &ValidationError{Field: "pickup", Err: err}
+		}
+		pickup = &scheduled
+		pickupRate = scheduled.SurchargeRate(s.scheduler.Config())
+		trace.record(StepPickup, "%s window %s, same day %t, surcharge rate %g",
+			scheduled.Date.Format(time.DateOnly), scheduled.Window.Name, scheduled.SameDay, pickupRate)
+	}
 
-	// Calculate shipping cost
-	details := s.calculateShippingDetails(baseCost, req.Weight, volume, req.IsExpress)
+	// Heavy shipments are also quoted as freight of their class; oversized ones only as freight,
+	// in place of the parcel service levels
+	freight, err := freightDetails(rates, currency, req, pickupRate)
+	if err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "freight_class"),
+			zap.String("valor", req.FreightClass),
+			zap.Float64("peso", req.Weight),
+			zap.Error(err),
+		)
+		return nil, invalidField("freight_class", err)
+	}
+	freightOnly := volumeErr != nil
+	if freightOnly {
+		if freight != nil {
+			volumeErr = validator.ValidateVolume(volume, rates.Freight.MaxVolumeCm3)
+		}
+		if freight == nil || volumeErr != nil {
+			zapLogger.Warn("Solicitação com parâmetros inválidos",
+				zap.String("param", "dimensions"),
+				zap.Float64("volume", volume),
+				zap.Error(volumeErr),
+			)
+			return nil, invalidField("dimensions", volumeErr)
+		}
+		if req.IsExpress {
+			zapLogger.Warn("Solicitação com parâmetros inválidos",
+				zap.String("param", "is_express"),
+				zap.Bool("expresso", req.IsExpress),
+				zap.Float64("volume", volume),
+			)
+			return nil, invalidField("is_express", ErrFreightOnly)
+		}
+		trace.record(StepFreight, "freight only: volume %g cm³ over the parcel limit", volume)
+	} else if freight != nil {
+		trace.record(StepFreight, "freight offered alongside the parcel service levels")
+	}
+
+	// Restricted areas of the carrier contracts exclude the customer address, the destination also
+	// of returns, from service levels or add a surcharge to them
+	areas := restrictedAreas(prices, req.DestinationZipcode)
+	for _, level := range restrictableLevels {
+		if area, ok := areas[level]; ok {
+			trace.record(StepRestricted, "%s restricted by %s, surcharge rate %g", level, area.Name, area.SurchargeRate)
+		}
+	}
+	// Remote areas of difficult access add their surcharge and transit days to the service levels
+	// serving the customer address, every area containing it adding up
+	remote := remoteAreas(prices, req.DestinationZipcode)
+	extraDays := remoteExtraDays(remote)
+	for _, level := range restrictableLevels {
+		for _, area := range remote[level] {
+			trace.record(StepRemote, "%s in remote area %s, surcharge rate %g, extra days %d", level, area.Name, area.SurchargeRate, area.ExtraDays)
+		}
+	}
+	selectedLevel := pricing.LevelStandard
+	if freightOnly {
+		selectedLevel = pricing.LevelFreight
+	}
+	if area, ok := areas[selectedLevel]; ok && area.Blocked() {
+		return nil, notServiceable(zapLogger, "destination_zipcode", req.DestinationZipcode, selectedLevel, area)
+	}
+	if area, ok := areas[pricing.LevelExpress]; ok && area.Blocked() {
+		if req.IsExpress {
+			return nil, notServiceable(zapLogger, "is_express", req.DestinationZipcode, pricing.LevelExpress, area)
+		}
+		offerExpress = false
+	}
+	// Service levels configured to deliver on weekends count those days when the request allows
+	// it, charging their weekend surcharge only when their transit window does count one; the
+	// other levels skip them when weekend delivery is configured
+	nonDelivery := nonDeliveryDays(prices, req.AllowWeekendDelivery)
+	weekendCounted := s.weekendCounted(prices, rates, origin, destination, prices.Country(req.DestinationCountry), req, pickup, extraDays, nonDelivery)
+	for _, level := range restrictableLevels {
+		if weekend, ok := prices.WeekendDeliveryFor(level, req.AllowWeekendDelivery); ok {
+			trace.record(StepWeekend, "%s delivers on saturday %t, sunday %t, surcharge rate %g, weekend in transit %t", level, weekend.Saturday, weekend.Sunday, weekend.SurchargeRate, weekendCounted[level])
+		}
+	}
+	if area, ok := areas[pricing.LevelFreight]; ok && area.Blocked() {
+		freight = nil
+	} else if freight != nil {
+		applyAreaSurcharge(freight, area)
+		applyRemoteSurcharge(freight, remote[pricing.LevelFreight])
+		applyWeekendSurcharge(freight, prices, pricing.LevelFreight, weekendCounted[pricing.LevelFreight])
+		applyFuelSurcharge(freight, prices.FuelSurcharge)
+	}
+	if fuel := prices.FuelSurcharge; fuel != nil {
+		trace.record(StepFuel, "rate %g effective %s", fuel.Rate, fuel.EffectiveDate)
+	}
+
+	validated = true
+	timing.since(StageValidation, start)
+
+	// Price the freight of each service level with its strategy, then apply the package type,
+	// delivery type, return and express adjustments
+	shipment := pricing.Shipment{
+		OriginZipcode:      origin,
+		DestinationZipcode: destination,
+		DestinationCountry: prices.Country(req.DestinationCountry),
+		Currency:           currency,
+		Weight:             req.Weight,
+		Volume:             volume,
+		Rates:              rates,
+	}
+	var standard, express *model.ShippingCalculationDetails
+	if freightOnly {
+		// A copy, so the freight option appended to the response is not priced twice
+		freightOnlyDetails := *freight
+		standard = &freightOnlyDetails
+	} else {
+		standardFreight, err := s.priceFreight(ctx, prices, shipment, pricing.LevelStandard, req.PricingStrategy)
+		if err != nil {
+			return nil, err
+		}
+		standard = s.calculateShippingDetails(rates, packageType, deliveryType, returns.CostAdjustmentRate, standardFreight, false)
+		applyPickupSurcharge(standard, pickupRate)
+		applyAreaSurcharge(standard, areas[pricing.LevelStandard])
+		applyRemoteSurcharge(standard, remote[pricing.LevelStandard])
+		applyWeekendSurcharge(standard, prices, pricing.LevelStandard, weekendCounted[pricing.LevelStandard])
+		applyFuelSurcharge(standard, prices.FuelSurcharge)
+		zone := pricing.ZoneOf(origin, destination)
+		applyPriceLimit(zapLogger, trace, rates, pricing.LevelStandard, zone, standard)
+		if offerExpress {
+			expressFreight, err := s.priceFreight(ctx, prices, shipment, pricing.LevelExpress, req.PricingStrategy)
+			if err != nil {
+				return nil, err
+			}
+			express = s.calculateShippingDetails(rates, packageType, deliveryType, returns.CostAdjustmentRate, expressFreight, true)
+			applyPickupSurcharge(express, pickupRate)
+			applyAreaSurcharge(express, areas[pricing.LevelExpress])
+			applyRemoteSurcharge(express, remote[pricing.LevelExpress])
+			applyWeekendSurcharge(express, prices, pricing.LevelExpress, weekendCounted[pricing.LevelExpress])
+			applyFuelSurcharge(express, prices.FuelSurcharge)
+			applyPriceLimit(zapLogger, trace, rates, pricing.LevelExpress, zone, express)
+		}
+	}
+
+	// Additional services and delivery days are shared by the service levels; buildResponse reads
+	// them from the standard details
+	standard.AdditionalServices = additionalServices
+	standard.TotalCost += totalFees(additionalServices)
+	// Saturated amounts mean a package too heavy or large to quote rather than a price
+	for _, level := range []*model.ShippingCalculationDetails{standard, express, freight} {
+		if level != nil && level.TotalCost >= money.MaxAmount {
+			return nil, invalidField("weight", ErrCostOutOfRange)
+		}
+	}
+	deliveryStart := time.Now()
+	// Add origin warehouse handling time to carrier transit time, skipping holidays; returns are
+	// collected from the customer and scheduled pickups are ready on the pickup date, without
+	// handling time
+	if !req.IsReturn && pickup == nil {
+		standard.HandlingDays = s.estimator.HandlingDays(origin)
+	}
+	standard.StandardDays, standard.ExpressDays = s.deliveryDays(rates, origin, destination, shipment.DestinationCountry, req.IsReturn, pickup, extraDays, nonDelivery)
+	var freightDays int
+	if freight != nil {
+		freightDays = s.freightDays(rates.Freight, origin, destination, shipment.DestinationCountry, pickup, extraDays[pricing.LevelFreight], nonDelivery[pricing.LevelFreight])
+		if freightOnly {
+			standard.StandardDays = freightDays
+		}
+	}
+	timing.since(StageDelivery, deliveryStart)
+
+	details := standard
+	if req.IsExpress {
+		details = express
+		details.AdditionalServices = additionalServices
+		details.TotalCost += totalFees(additionalServices)
+		details.HandlingDays = standard.HandlingDays
+	}
 
 	// Log calculation details with structured fields
-	logger.LogRequest(zapLogger, ctx, "Detalhes do cálculo",
-		zap.Float64("custo_base", details.BaseCost),
-		zap.Float64("acréscimo_peso", details.WeightSurcharge),
-		zap.Float64("acréscimo_volume", details.VolumeSurcharge),
+	zapLogger.Info("Detalhes do cálculo",
+		zap.Float64("custo_base", details.BaseCost.Minor()),
+		zap.Float64("acréscimo_peso", details.WeightSurcharge.Minor()),
+		zap.Float64("acréscimo_volume", details.VolumeSurcharge.Minor()),
+		zap.Float64("acréscimo_embalagem", details.PackageTypeSurcharge.Minor()),
+		zap.Float64("ajuste_entrega", details.DeliveryTypeAdjustment.Minor()),
+		zap.Float64("acréscimo_expresso", details.ExpressSurcharge.Minor()),
+		zap.Float64("ajuste_devolução", details.ReturnAdjustment.Minor()),
+		zap.Float64("acréscimo_coleta", details.PickupSurcharge.Minor()),
+		zap.Float64("acréscimo_área_restrita", details.AreaSurcharge.Minor()),
+		zap.Float64("acréscimo_área_remota", details.RemoteSurcharge.Minor()),
+		zap.Float64("acréscimo_fim_de_semana", details.WeekendSurcharge.Minor()),
+		zap.Float64("acréscimo_combustível", details.FuelSurcharge.Minor()),
+		zap.Float64("serviços_adicionais", totalFees(details.AdditionalServices).Minor()),
+		zap.Int("dias_manuseio", details.HandlingDays),
 	)
 
 	// Build response
-	response := s.buildResponse(details, req.IsExpress)
+	response := s.buildResponse(rates, standard, express, req.IsExpress)
+	response.Currency = currency
+	response.PricingVersion = pricingVersion
+	response.Experiment = assignment
+	response.Package = &model.PackageMeasures{WeightKg: req.Weight, DimensionsCm: req.Dimensions}
+	response.Degraded = IsDegraded(ctx)
+	if fuel := prices.FuelSurcharge; fuel != nil {
+		response.FuelIndex = &model.FuelIndex{Rate: fuel.Rate, EffectiveDate: fuel.EffectiveDate}
+	}
+	if freightOnly {
+		response.ShippingOptions[0].Service = model.ServiceFreight
+		response.AvailableServices = []string{model.ServiceFreight}
+		response.SelectedService = model.ServiceFreight
+	} else if freight != nil {
+		response.ShippingOptions = append(response.ShippingOptions, model.ShippingOption{
+			Service:         model.ServiceFreight,
+			Cost:            rates.Round(subtotalOf(freight) + totalFees(additionalServices)),
+			Time:            formatDays(freightDays),
+			EstimatedDays:   freightDays,
+			WeekendDelivery: freight.WeekendDelivery,
+		})
+		response.AvailableServices = append(response.AvailableServices, model.ServiceFreight)
+	}
+
+	// Flag the cheapest and fastest options; with an optimize objective the options are ordered by
+	// it and the best one is selected instead of the level of is_express
+	markHints(response.ShippingOptions)
+	if optimize != "" {
+		orderOptions(response.ShippingOptions, optimize)
+		if best := response.ShippingOptions[0].Service; best != response.SelectedService {
+			var selected *model.CalculateShippingResponse
+			if best == model.ServiceFreight {
+				freightDetails := *freight
+				freightDetails.AdditionalServices = additionalServices
+				freightDetails.TotalCost += totalFees(additionalServices)
+				freightDetails.StandardDays = freightDays
+				selected = s.buildResponse(rates, &freightDetails, nil, false)
+			} else {
+				selected = s.buildResponse(rates, standard, express, best == model.ServiceExpress)
+			}
+			response.ShippingCost = selected.ShippingCost
+			response.EstimatedDeliveryTime = selected.EstimatedDeliveryTime
+			response.Breakdown = selected.Breakdown
+			response.SelectedService = best
+		}
+		trace.record(StepOptimize, "%s selected %s", optimize, response.SelectedService)
+	}
+
+	// Delivery dates are the local dates at the destination, counted from today at the origin
+	deliveryStart = time.Now()
+	for i, opt := range response.ShippingOptions {
+		date := s.estimator.DeliveryDate(origin, destination, shipment.DestinationCountry, opt.EstimatedDays).Format(schedule.DateLayout)
+		response.ShippingOptions[i].DeliveryDate = date
+		if opt.Service == response.SelectedService {
+			response.DeliveryDate = date
+		}
+	}
+	timing.since(StageDelivery, deliveryStart)
+
+	// Domestic freight includes the ICMS or ISS of its route, itemized for finance when taxes are enabled
+	if s.tax != nil && shipment.DestinationCountry == tax.Country {
+		if computed, ok := s.tax.Compute(origin, destination, response.ShippingCost); ok {
+			response.Breakdown.Tax = &model.FreightTax{
+				Tax:              computed.Tax,
+				Rate:             computed.Rate,
+				OriginState:      computed.OriginState,
+				DestinationState: computed.DestinationState,
+				Municipality:     computed.Municipality,
+				Gross:            computed.Gross,
+				Amount:           computed.Amount,
+				Net:              computed.Net,
+			}
+			trace.record(StepTax, "%s from %s to %s at rate %g", computed.Tax, computed.OriginState, computed.DestinationState, computed.Rate)
+		}
+	}
+
+	// International shipments with declared goods get the estimated import duties and taxes,
+	// charged on the cost of the selected service; returns are not estimated
+	if (req.HSCode != "" || req.DeclaredValue != 0) && !req.IsReturn && shipment.DestinationCountry != prices.DefaultCountry {
+		estimate, err := s.customs.Estimate(shipment.DestinationCountry, currency, req.HSCode, req.DeclaredValue, response.ShippingCost)
+		if err != nil {
+			zapLogger.Warn("Solicitação com parâmetros inválidos",
+				zap.String("param", "hs_code"),
+				zap.String("valor", req.HSCode),
+				zap.String("país_destino", shipment.DestinationCountry),
+				zap.Error(err),
+			)
+			return nil, &ValidationError{Field: "hs_code", Err: err}
+		}
+		applyDuties(response.Breakdown, estimate, req.DeclaredValue)
+		trace.record(StepCustoms, "HS code %s: duty rate %g, tax rate %g", estimate.HSCode, estimate.DutyRate, estimate.TaxRate)
+		zapLogger.Info("Estimativa de impostos de importação",
+			zap.String("código_hs", estimate.HSCode),
+			zap.Float64("imposto_importação", estimate.Duty.Minor()),
+			zap.Float64("tributos", estimate.Tax.Minor()),
+		)
+	}
+	s.shadowQuote(ctx, req, response)
 
 	// Log result with structured fields
-	logger.LogRequest(zapLogger, ctx, "Resultado do cálculo",
-		zap.Float64("custo_envio", response.ShippingCost),
+	zapLogger.Info("Resultado do cálculo",
+		zap.Float64("custo_envio", response.ShippingCost.Minor()),
 		zap.String("tempo_estimado", response.EstimatedDeliveryTime),
+		zap.String("moeda", response.Currency),
+		zap.String("versão_tarifas", response.PricingVersion),
 	)
 
 	return response, nil
 }
 
-// calculateBaseCost calculates the base shipping cost based on distance between zipcodes
-func (s *ShippingService) calculateBaseCost(originZipcode, destinationZipcode string) float64 {
-	// Normalize zipcodes (remove hyphens and spaces)
-	originNormalized := strings.ReplaceAll(strings.ReplaceAll(originZipcode, "-", ""), " ", "")
-	destNormalized := strings.ReplaceAll(strings.ReplaceAll(destinationZipcode, "-", ""), " ", "")
+// Serviceability reports the service levels available for a route and their lead times without
+// pricing it, so product pages can show delivery options before a cart exists
+func (s *ShippingService) Serviceability(ctx context.Context, req *model.ServiceabilityRequest) (*model.ServiceabilityResponse, error) {
+	zapLogger := logger.FromContext(ctx)
 
-	// Convert to numbers (use first 4-8 digits)
-	originNum, err1 := strconv.ParseFloat(originNormalized, 64)
-	destNum, err2 := strconv.ParseFloat(destNormalized, 64)
+	if err := validateRoute(zapLogger, req.OriginZipcode, req.DestinationZipcode); err != nil {
+		return nil, err
+	}
 
-	// If conversion fails, use default base cost
-	if err1 != nil || err2 != nil {
-		return baseCostCents
+	table, err := s.tenantTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prices := table.config
+	_, rates, err := prices.Resolve(req.DestinationCountry, "")
+	if err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "destination_country"),
+			zap.String("valor", req.DestinationCountry),
+			zap.Error(err),
+		)
+		return nil, invalidField("destination_country", err)
 	}
 
-	// Calculate distance as absolute difference
-	distance := originNum - destNum
-	if distance < 0 {
-		distance = -distance
+	packageType, err := prices.ResolvePackageType(req.PackageType)
+	if err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "package_type"),
+			zap.String("valor", req.PackageType),
+			zap.Error(err),
+		)
+		return nil, invalidField("package_type", err)
 	}
 
-	// Base cost increases with distance
-	// For same region (distance < 1000): base cost
-	// For different regions: base cost * (1 + distance/10000)
-	// This provides a simple distance-based pricing model
-	if distance < 1000 {
-		return baseCostCents
+	areas := restrictedAreas(prices, req.DestinationZipcode)
+	extraDays := remoteExtraDays(remoteAreas(prices, req.DestinationZipcode))
+	standardDays, expressDays := s.deliveryDays(rates, req.OriginZipcode, req.DestinationZipcode, prices.Country(req.DestinationCountry), false, nil, extraDays, nonDeliveryDays(prices, false))
+	express := model.ServiceAvailability{
+		Service:               model.ServiceExpress,
+		Available:             true,
+		EstimatedDays:         expressDays,
+		EstimatedDeliveryTime: formatDays(expressDays),
+	}
+	if packageType.ExpressProhibited {
+		express.Available = false
+		express.Restriction = ErrExpressNotAllowed.Error()
+	} else if area, ok := areas[pricing.LevelExpress]; ok && area.Blocked() {
+		express.Available = false
+		express.Restriction = fmt.Sprintf("%s by %s: restricted area %s", ErrNotServiceable, pricing.LevelExpress, area.Name)
 	}
 
-	// Scale factor: 1% increase per 1000 units of distance difference
-	distanceFactor := 1.0 + (distance / 10000.0)
-	return baseCostCents * distanceFactor
+	response := &model.ServiceabilityResponse{
+		OriginZipcode:      req.OriginZipcode,
+		DestinationZipcode: req.DestinationZipcode,
+		Serviceable:        true,
+		Services: []model.ServiceAvailability{
+			{
+				Service:               model.ServiceStandard,
+				Available:             true,
+				EstimatedDays:         standardDays,
+				EstimatedDeliveryTime: formatDays(standardDays),
+			},
+			express,
+		},
+	}
+	if area, ok := areas[pricing.LevelStandard]; ok && area.Blocked() {
+		response.Serviceable = false
+		response.Restrictions = append(response.Restrictions, fmt.Sprintf("%s: restricted area %s", ErrNotServiceable, area.Name))
+		for i := range response.Services {
+			response.Services[i].Available = false
+		}
+	}
+	return response, nil
 }
 
-// calculateShippingDetails performs the actual shipping cost calculation
-func (s *ShippingService) calculateShippingDetails(baseCost, weight, volume float64, isExpress bool) *model.ShippingCalculationDetails {
+// pricingFor returns the rate table and its version for the request: the one of its tenant or,
+// for the default tenant while a pricing experiment runs, the one of its arm with the assignment.
+// Historical quotes are priced with their past configuration, outside of the experiment, and
+// quotes with an arm pinned by WithExperimentArm keep it
+func (s *ShippingService) pricingFor(ctx context.Context) (pricing.Config, string, *model.ExperimentAssignment, error) {
+	table, err := s.tenantTable(ctx)
+	if err != nil {
+		return pricing.Config{}, "", nil, err
+	}
+	if s.experiment == nil || tenant.FromContext(ctx) != tenant.Default || isHistorical(ctx) {
+		return table.config, table.version, nil, nil
+	}
+	assignment := &model.ExperimentAssignment{Name: s.experiment.Name, Arm: experiment.ArmControl}
+	if pinned, ok := pinnedArm(ctx); !ok {
+		assignment.Arm = s.experiment.Assign(experiment.SubjectFromContext(ctx))
+	} else if pinned != nil && pinned.Name == s.experiment.Name {
+		assignment.Arm = pinned.Arm
+	}
+	if assignment.Arm == experiment.ArmTreatment {
+		return s.experiment.Treatment, s.treatmentVersion, assignment, nil
+	}
+	return table.config, table.version, assignment, nil
+}
 
-	// Weight surcharge: 10% of base cost per 0.5 kg
-	weightMultiplier := weight / weightUnit
-	weightSurcharge := baseCost * weightSurchargeRate * weightMultiplier
+type pinnedTableKey struct{}
 
-	// Volume surcharge: 5% of base cost per 1000 cm³
-	volumeMultiplier := volume / volumeUnit
-	volumeSurcharge := baseCost * volumeSurchargeRate * volumeMultiplier
+// withPinnedTable returns a context whose quotes are priced with the rate table in force now for
+// its tenant, even if another one is put in force meanwhile, so that several reads of the rates
+// for the same quote agree
+func (s *ShippingService) withPinnedTable(ctx context.Context) (context.Context, error) {
+	table, err := s.tenantTable(ctx)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, pinnedTableKey{}, table), nil
+}
+
+// tenantTable returns the rate table pinned in ctx or, without one, the rate table in force for
+// the tenant of ctx
+func (s *ShippingService) tenantTable(ctx context.Context) (*rateTable, error) {
+	if table, ok := ctx.Value(pinnedTableKey{}).(*rateTable); ok {
+		return table, nil
+	}
+	id := tenant.FromContext(ctx)
+	if id == tenant.Default {
+		return s.table.Load(), nil
+	}
+	table, ok := s.tenants[id]
+	if !ok {
+		return nil, invalidField("tenant", fmt.Errorf("%w %q", ErrUnknownTenant, id))
+	}
+	return table, nil
+}
+
+// Pricing returns the pricing configuration in force for the default tenant
+func (s *ShippingService) Pricing() pricing.Config {
+	return s.table.Load().config
+}
+
+// SetPricing puts a pricing configuration in force for the following quotes of the default
+// tenant; quotes in progress finish with the previous one. The rate tables of the other tenants,
+// of the experiment treatment and of shadow pricing are not affected. It fails, keeping the
+// configuration in force, when cfg is invalid or a service level is configured with an
+// unregistered strategy
+func (s *ShippingService) SetPricing(cfg pricing.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	for level, name := range cfg.Strategies {
+		if _, ok := s.strategies[name]; !ok {
+			return fmt.Errorf("strategies: service level %q: %w %q", level, pricing.ErrUnsupportedStrategy, name)
+		}
+	}
+	s.table.Store(&rateTable{config: cfg, version: cfg.VersionID()})
+	return nil
+}
+
+// deliveryDays returns the standard and express delivery days of a route to a normalized
+// country, including the origin handling time unless the package is a return collected from the
+// customer, and skipping the holidays of the destination country. With a scheduled pickup the
+// days are counted from the pickup date instead. Transit days come from the regional rate of the
+// route when it sets them, else from the transit matrix of the estimator between the states of
+// the route, plus the extra days of the remote areas of each service level, and skip the
+// weekend days each service level does not deliver on
+func (s *ShippingService) deliveryDays(rates pricing.Rates, originZipcode, destinationZipcode, country string, isReturn bool, pickup *schedule.Pickup, extraDays map[string]int, nonDelivery map[string][]time.Weekday) (int, int) {
+	standardTransit := s.estimator.ServiceTransitDays(originZipcode, destinationZipcode, country, pricing.LevelStandard, standardDeliveryDays)
+	expressTransit := s.estimator.ServiceTransitDays(originZipcode, destinationZipcode, country, pricing.LevelExpress, expressDeliveryDays)
+	if regional, ok := rates.RegionalRateFor(country, originZipcode, destinationZipcode); ok {
+		if regional.StandardDays > 0 {
+			standardTransit = regional.StandardDays
+		}
+		if regional.ExpressDays > 0 {
+			expressTransit = regional.ExpressDays
+		}
+	}
+	standardTransit += extraDays[pricing.LevelStandard]
+	expressTransit += extraDays[pricing.LevelExpress]
+	if pickup != nil {
+		standard := s.estimator.DeliveryDaysFrom(pickup.Date, originZipcode, destinationZipcode, country, standardTransit, nonDelivery[pricing.LevelStandard]...)
+		express := s.estimator.DeliveryDaysFrom(pickup.Date, originZipcode, destinationZipcode, country, expressTransit, nonDelivery[pricing.LevelExpress]...)
+		return standard, express
+	}
+	if isReturn {
+		standard := s.estimator.TransitDays(originZipcode, destinationZipcode, country, standardTransit, nonDelivery[pricing.LevelStandard]...)
+		express := s.estimator.TransitDays(originZipcode, destinationZipcode, country, expressTransit, nonDelivery[pricing.LevelExpress]...)
+		return standard, express
+	}
+	standard := s.estimator.DeliveryDays(originZipcode, destinationZipcode, country, standardTransit, nonDelivery[pricing.LevelStandard]...)
+	express := s.estimator.DeliveryDays(originZipcode, destinationZipcode, country, expressTransit, nonDelivery[pricing.LevelExpress]...)
+	return standard, express
+}
+
+// canonicalMeasures returns a copy of req with its weight in kilograms and its dimensions in
+// centimeters, logging an unknown unit
+func canonicalMeasures(zapLogger *zap.Logger, req *model.CalculateShippingRequest) (*model.CalculateShippingRequest, error) {
+	weight, err := units.ToKilograms(req.Weight, req.WeightUnit)
+	if err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "weight_unit"),
+			zap.String("valor", req.WeightUnit),
+			zap.Error(err),
+		)
+		return nil, invalidField("weight_unit", err)
+	}
+	dimensions := make([]float64, 3)
+	for i, length := range []float64{req.Dimensions.Length, req.Dimensions.Width, req.Dimensions.Height} {
+		if dimensions[i], err = units.ToCentimeters(length, req.DimensionUnit); err != nil {
+			zapLogger.Warn("Solicitação com parâmetros inválidos",
+				zap.String("param", "dimension_unit"),
+				zap.String("valor", req.DimensionUnit),
+				zap.Error(err),
+			)
+			return nil, invalidField("dimension_unit", err)
+		}
+	}
+
+	canonical := *req
+	canonical.Weight, canonical.WeightUnit = weight, units.Kilogram
+	canonical.Dimensions = model.PackageDimensions{Length: dimensions[0], Width: dimensions[1], Height: dimensions[2]}
+	canonical.DimensionUnit = units.Centimeter
+	return &canonical, nil
+}
+
+// validateRoute validates the origin and destination zipcodes, logging the invalid parameter
+func validateRoute(zapLogger *zap.Logger, originZipcode, destinationZipcode string) error {
+	if err := validator.ValidateZipcode(originZipcode, "origin_zipcode"); err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "origin_zipcode"),
+			zap.String("valor", originZipcode),
+			zap.Error(err),
+		)
+		return invalidField("origin_zipcode", err)
+	}
+
+	if err := validator.ValidateZipcode(destinationZipcode, "destination_zipcode"); err != nil {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "destination_zipcode"),
+			zap.String("valor", destinationZipcode),
+			zap.Error(err),
+		)
+		return invalidField("destination_zipcode", err)
+	}
+	return nil
+}
+
+// priceFreight prices the freight of a service level with the strategy requested or configured
+// for it, or with the formula strategy in degraded mode
+func (s *ShippingService) priceFreight(ctx context.Context, prices pricing.Config, shipment pricing.Shipment, level, override string) (pricing.Freight, error) {
+	zapLogger := logger.FromContext(ctx)
+
+	name := prices.StrategyFor(level, override)
+	if IsDegraded(ctx) {
+		name = pricing.StrategyFormula
+	}
+	strategy, ok := s.strategies[name]
+	if !ok {
+		zapLogger.Warn("Solicitação com parâmetros inválidos",
+			zap.String("param", "pricing_strategy"),
+			zap.String("valor", name),
+			zap.String("serviço", level),
+		)
+		return pricing.Freight{}, invalidField("pricing_strategy", fmt.Errorf("%w %q", pricing.ErrUnsupportedStrategy, name))
+	}
+
+	if trace := traceFrom(ctx); trace != nil {
+		trace.record(StepStrategy, "%s priced with %s", level, name)
+	}
+	shipment.Level = level
+	start := time.Now()
+	freight, err := strategy.Price(ctx, shipment)
+	timingFrom(ctx).since(strategyStage(name), start)
+	if err != nil {
+		zapLogger.Warn("Falha na precificação do frete",
+			zap.String("estratégia", name),
+			zap.String("serviço", level),
+			zap.Error(err),
+		)
+		err = fmt.Errorf("%s pricing failed: %w", name, err)
+		if errors.Is(err, pricing.ErrWeightAboveTable) {
+			return pricing.Freight{}, &ValidationError{Field: "weight", Err: err}
+		}
+		return pricing.Freight{}, err
+	}
+	return freight, nil
+}
+
+// calculateShippingDetails applies the package type, delivery type, return adjustment (returnRate
+// is 0 for outbound shipments) and, for express, the express surcharge to the freight priced by a strategy
+func (s *ShippingService) calculateShippingDetails(rates pricing.Rates, packageType pricing.PackageType, deliveryType pricing.DeliveryType, returnRate float64, freight pricing.Freight, isExpress bool) *model.ShippingCalculationDetails {
+
+	// Package type surcharge: percentage of base, weight and volume costs
+	packageTypeSurcharge := freight.Total().MulRate(packageType.SurchargeRate)
+
+	// Delivery type adjustment: percentage of the subtotal, negative for discounts
+	deliveryTypeAdjustment := (freight.Total() + packageTypeSurcharge).MulRate(deliveryType.CostAdjustmentRate)
+
+	// Return adjustment: percentage of the subtotal, negative for discounts
+	returnAdjustment := (freight.Total() + packageTypeSurcharge + deliveryTypeAdjustment).MulRate(returnRate)
 
 	// Subtotal before express surcharge
-	subtotal := baseCost + weightSurcharge + volumeSurcharge
+	subtotal := freight.Total() + packageTypeSurcharge + deliveryTypeAdjustment + returnAdjustment
 
-	// Express surcharge: 50% of subtotal if express
-	var expressSurcharge float64
+	// Express surcharge: percentage of subtotal if express
+	var expressSurcharge money.Amount
 	if isExpress {
-		expressSurcharge = subtotal * expressSurchargeRate
+		expressSurcharge = subtotal.MulRate(rates.ExpressSurchargeRate)
 	}
 
 	// Total cost
@@ -177,55 +1024,439 @@ func (s *ShippingService) calculateShippingDetails(baseCost, weight, volume floa
 	}
 
 	return &model.ShippingCalculationDetails{
-		BaseCost:         baseCost,
-		WeightSurcharge:  weightSurcharge,
-		VolumeSurcharge:  volumeSurcharge,
-		ExpressSurcharge: expressSurcharge,
-		TotalCost:        totalCost,
-		EstimatedDays:    estimatedDays,
+		BaseCost:               freight.BaseCost,
+		WeightSurcharge:        freight.WeightSurcharge,
+		VolumeSurcharge:        freight.VolumeSurcharge,
+		PackageTypeSurcharge:   packageTypeSurcharge,
+		DeliveryTypeAdjustment: deliveryTypeAdjustment,
+		ExpressSurcharge:       expressSurcharge,
+		ReturnAdjustment:       returnAdjustment,
+		TotalCost:              totalCost,
+		EstimatedDays:          estimatedDays,
+		ExpressProhibited:      packageType.ExpressProhibited,
 	}
 }
 
-// buildResponse constructs the response with all shipping options
-func (s *ShippingService) buildResponse(details *model.ShippingCalculationDetails, isExpress bool) *model.CalculateShippingResponse {
-	// Calculate standard shipping cost (without express surcharge)
-	standardCost := details.BaseCost + details.WeightSurcharge + details.VolumeSurcharge
+// buildResponse constructs the response with all shipping options from the details of each
+// service level. Additional services and delivery days are read from the standard details;
+// express is nil when the package type prohibits it
+func (s *ShippingService) buildResponse(rates pricing.Rates, standard, express *model.ShippingCalculationDetails, isExpress bool) *model.CalculateShippingResponse {
+	// Additional service fees are added to every option and are not subject to the express surcharge
+	servicesFee := totalFees(standard.AdditionalServices)
+	standardRaw := subtotalOf(standard) + standard.PriceLimitAdjustment + servicesFee
+	standardCost := rates.Round(standardRaw)
 
-	// Calculate express shipping cost (with express surcharge)
-	expressCost := standardCost * (1 + expressSurchargeRate)
+	// Delivery days include the origin warehouse handling time and skip holidays
+	standardTime := formatDays(standard.StandardDays)
+	expressTime := formatDays(standard.ExpressDays)
+
+	// Build shipping options; express is not offered for package types that prohibit it. The
+	// slices have room for every service level, freight included, so that they are allocated once
+	shippingOptions := make([]model.ShippingOption, 0, 3)
+	shippingOptions = append(shippingOptions, model.ShippingOption{
+		Service:         model.ServiceStandard,
+		Cost:            standardCost,
+		Time:            standardTime,
+		EstimatedDays:   standard.StandardDays,
+		WeekendDelivery: standard.WeekendDelivery,
+	})
+	availableServices := make([]string, 0, 3)
+	availableServices = append(availableServices, model.ServiceStandard)
+	var expressRaw, expressCost money.Amount
+	if express != nil {
+		expressRaw = subtotalOf(express) + express.ExpressSurcharge + express.PriceLimitAdjustment + servicesFee
+		expressCost = rates.Round(expressRaw)
+		shippingOptions = append(shippingOptions, model.ShippingOption{
+			Service:         model.ServiceExpress,
+			Cost:            expressCost,
+			Time:            expressTime,
+			EstimatedDays:   standard.ExpressDays,
+			WeekendDelivery: express.WeekendDelivery,
+		})
+		availableServices = append(availableServices, model.ServiceExpress)
+	}
 
 	// Determine which cost to return based on request
-	var shippingCost float64
-	var estimatedTime string
+	selected, selectedService := standard, model.ServiceStandard
+	unroundedCost, shippingCost := standardRaw, standardCost
+	estimatedTime := standardTime
 	if isExpress {
-		shippingCost = expressCost
-		estimatedTime = fmt.Sprintf("%d dia", expressDeliveryDays)
-		if expressDeliveryDays > 1 {
-			estimatedTime = fmt.Sprintf("%d dias", expressDeliveryDays)
-		}
-	} else {
-		shippingCost = standardCost
-		estimatedTime = fmt.Sprintf("%d dias", standardDeliveryDays)
+		selected, selectedService = express, model.ServiceExpress
+		unroundedCost, shippingCost = expressRaw, expressCost
+		estimatedTime = expressTime
 	}
 
-	// Build shipping options
-	shippingOptions := []model.ShippingOption{
-		{
-			Service: "standard",
-			Cost:    standardCost,
-			Time:    fmt.Sprintf("%d dias", standardDeliveryDays),
-		},
-		{
-			Service: "express",
-			Cost:    expressCost,
-			Time:    fmt.Sprintf("%d dia", expressDeliveryDays),
-		},
+	// Itemize the cost of the selected service
+	breakdown := &model.CostBreakdown{
+		BaseCost:                selected.BaseCost,
+		WeightSurcharge:         selected.WeightSurcharge,
+		VolumeSurcharge:         selected.VolumeSurcharge,
+		PackageTypeSurcharge:    selected.PackageTypeSurcharge,
+		DeliveryTypeAdjustment:  selected.DeliveryTypeAdjustment,
+		ExpressSurcharge:        selected.ExpressSurcharge,
+		ReturnAdjustment:        selected.ReturnAdjustment,
+		PickupSurcharge:         selected.PickupSurcharge,
+		RestrictedArea:          selected.RestrictedArea,
+		RestrictedAreaSurcharge: selected.AreaSurcharge,
+		RemoteAreas:             selected.RemoteAreas,
+		RemoteAreaSurcharge:     selected.RemoteSurcharge,
+		WeekendSurcharge:        selected.WeekendSurcharge,
+		FuelSurcharge:           selected.FuelSurcharge,
+		PriceLimit:              selected.PriceLimit,
+		PriceLimitAdjustment:    selected.PriceLimitAdjustment,
+		AdditionalServices:      standard.AdditionalServices,
+		UnroundedTotal:          unroundedCost,
+		RoundingAdjustment:      shippingCost - unroundedCost,
+		Total:                   shippingCost,
 	}
 
 	return &model.CalculateShippingResponse{
 		ShippingCost:          shippingCost,
 		EstimatedDeliveryTime: estimatedTime,
-		AvailableServices:     []string{"standard", "express"},
+		AvailableServices:     availableServices,
 		ShippingOptions:       shippingOptions,
+		Breakdown:             breakdown,
+		SelectedService:       selectedService,
+	}
+}
+
+// applyPriceLimit clamps the freight of a service level, after all surcharges, to the price limit
+// configured for its route zone, recording the adjustment in the details
+func applyPriceLimit(zapLogger *zap.Logger, trace *DecisionTrace, rates pricing.Rates, level, zone string, details *model.ShippingCalculationDetails) {
+	limit, ok := rates.PriceLimitFor(level, zone)
+	if !ok {
+		return
+	}
+	freight := subtotalOf(details) + details.ExpressSurcharge
+	clamped, kind := limit.Clamp(freight)
+	if kind == "" {
+		return
+	}
+	details.PriceLimit = kind
+	details.PriceLimitAdjustment = clamped - freight
+	details.TotalCost += details.PriceLimitAdjustment
+	if trace != nil {
+		trace.record(StepPriceLimit, "%s clamped to the %s of zone %s: %s to %s", level, kind, zone, freight, clamped)
+	}
+
+	zapLogger.Info("Frete ajustado ao limite de preço",
+		zap.String("serviço", level),
+		zap.String("zona", zone),
+		zap.String("limite", kind),
+		zap.Float64("frete_calculado", freight.Minor()),
+		zap.Float64("frete_limitado", clamped.Minor()),
+	)
+}
+
+// applyPickupSurcharge adds the surcharge of a scheduled pickup, a fraction of the subtotal of the
+// service level; it is not subject to the express surcharge
+func applyPickupSurcharge(details *model.ShippingCalculationDetails, rate float64) {
+	if rate == 0 {
+		return
+	}
+	details.PickupSurcharge = subtotalOf(details).MulRate(rate)
+	details.TotalCost += details.PickupSurcharge
+}
+
+// applyAreaSurcharge adds the surcharge of the restricted area of the destination, a fraction of
+// the subtotal of the service level including the pickup surcharge; it is not subject to the
+// express surcharge
+func applyAreaSurcharge(details *model.ShippingCalculationDetails, area pricing.RestrictedArea) {
+	if area.SurchargeRate == 0 {
+		return
+	}
+	details.RestrictedArea = area.Name
+	details.AreaSurcharge = subtotalOf(details).MulRate(area.SurchargeRate)
+	details.TotalCost += details.AreaSurcharge
+}
+
+// applyRemoteSurcharge adds the surcharge of the remote areas of the destination, the sum of their
+// rates applied to the subtotal of the service level including the pickup and restricted area
+// surcharges; it is not subject to the express surcharge
+func applyRemoteSurcharge(details *model.ShippingCalculationDetails, areas []pricing.RemoteArea) {
+	rate := 0.0
+	for _, area := range areas {
+		if area.SurchargeRate > 0 {
+			details.RemoteAreas = append(details.RemoteAreas, area.Name)
+			rate += area.SurchargeRate
+		}
+	}
+	if rate == 0 {
+		return
+	}
+	details.RemoteSurcharge = subtotalOf(details).MulRate(rate)
+	details.TotalCost += details.RemoteSurcharge
+}
+
+// applyWeekendSurcharge marks the service level as delivering on weekends when its transit window
+// counts a weekend day, adding its weekend surcharge: a fraction of the subtotal including the
+// pickup, restricted and remote area surcharges, not subject to the express surcharge
+func applyWeekendSurcharge(details *model.ShippingCalculationDetails, prices pricing.Config, level string, counted bool) {
+	weekend, ok := prices.WeekendDeliveryFor(level, counted)
+	if !ok {
+		return
+	}
+	details.WeekendDelivery = true
+	details.WeekendSurcharge = subtotalOf(details).MulRate(weekend.SurchargeRate)
+	details.TotalCost += details.WeekendSurcharge
+}
+
+// applyFuelSurcharge adds the fuel surcharge of the index in force, a fraction of the freight of
+// the service level after the express, restricted area, remote area and weekend surcharges
+func applyFuelSurcharge(details *model.ShippingCalculationDetails, fuel *pricing.FuelSurcharge) {
+	if fuel == nil || fuel.Rate == 0 {
+		return
+	}
+	details.FuelSurcharge = (subtotalOf(details) + details.ExpressSurcharge).MulRate(fuel.Rate)
+	details.TotalCost += details.FuelSurcharge
+}
+
+// notServiceable logs and returns the error of a destination that a restricted area excludes from
+// a service level, reported on field
+func notServiceable(zapLogger *zap.Logger, field, destinationZipcode, level string, area pricing.RestrictedArea) error {
+	zapLogger.Warn("Destino não atendido",
+		zap.String("param", field),
+		zap.String("destino", destinationZipcode),
+		zap.String("serviço", level),
+		zap.String("área_restrita", area.Name),
+	)
+	return &ValidationError{Field: field, Err: fmt.Errorf("%w by %s: restricted area %s", ErrNotServiceable, level, area.Name)}
+}
+
+// freightDetails prices a shipment as freight of its class, with the pickup surcharge. It returns
+// nil when freight is not offered: without freight rates in the currency, for returns, outside the
+// freight weight limits or without a class and a default class. Requesting a class where freight
+// is not offered is an error
+func freightDetails(rates pricing.Rates, currency string, req *model.CalculateShippingRequest, pickupRate float64) (*model.ShippingCalculationDetails, error) {
+	freight := rates.Freight
+	switch {
+	case freight == nil:
+		if req.FreightClass != "" {
+			return nil, fmt.Errorf("%w in %s", pricing.ErrFreightNotOffered, currency)
+		}
+		return nil, nil
+	case req.IsReturn:
+		if req.FreightClass != "" {
+			return nil, fmt.Errorf("%w for returns", pricing.ErrFreightNotOffered)
+		}
+		return nil, nil
+	case !freight.Applies(req.Weight):
+		if req.FreightClass != "" {
+			return nil, fmt.Errorf("%w for %g kg: weight must be between %g and %g kg", pricing.ErrFreightNotOffered, req.Weight, freight.MinWeightKg, freight.MaxWeightKg)
+		}
+		return nil, nil
+	case req.FreightClass == "" && freight.DefaultClass == "":
+		return nil, nil
+	}
+
+	charge, err := freight.Price(req.FreightClass, req.Weight)
+	if err != nil {
+		return nil, err
+	}
+	details := &model.ShippingCalculationDetails{BaseCost: charge, TotalCost: charge}
+	applyPickupSurcharge(details, pickupRate)
+	return details, nil
+}
+
+// freightDays returns the delivery days of a freight shipment: the freight transit days plus the
+// extra days of its remote areas, with the origin warehouse handling time or from the pickup
+// date like the parcel service levels, skipping the weekend days freight does not deliver on
+func (s *ShippingService) freightDays(freight *pricing.FreightRates, originZipcode, destinationZipcode, country string, pickup *schedule.Pickup, extraDays int, nonDelivery []time.Weekday) int {
+	transitDays := freight.TransitDays + extraDays
+	if pickup != nil {
+		return s.estimator.DeliveryDaysFrom(pickup.Date, originZipcode, destinationZipcode, country, transitDays, nonDelivery...)
+	}
+	return s.estimator.DeliveryDays(originZipcode, destinationZipcode, country, transitDays, nonDelivery...)
+}
+
+// restrictableLevels are the service levels restricted areas apply to, in the order they are traced
+var restrictableLevels = []string{pricing.LevelStandard, pricing.LevelExpress, pricing.LevelFreight}
+
+// restrictedAreas returns the restricted area of a destination zipcode for each service level it
+// restricts
+func restrictedAreas(prices pricing.Config, destinationZipcode string) map[string]pricing.RestrictedArea {
+	areas := make(map[string]pricing.RestrictedArea)
+	for _, level := range restrictableLevels {
+		if area, ok := prices.RestrictionFor(destinationZipcode, level); ok {
+			areas[level] = area
+		}
+	}
+	return areas
+}
+
+// remoteAreas returns the remote areas of a destination zipcode for each service level they charge
+func remoteAreas(prices pricing.Config, destinationZipcode string) map[string][]pricing.RemoteArea {
+	areas := make(map[string][]pricing.RemoteArea)
+	for _, level := range restrictableLevels {
+		if found := prices.RemoteAreasFor(destinationZipcode, level); len(found) > 0 {
+			areas[level] = found
+		}
+	}
+	return areas
+}
+
+// remoteExtraDays returns the transit days the remote areas add to each service level
+func remoteExtraDays(areas map[string][]pricing.RemoteArea) map[string]int {
+	days := make(map[string]int)
+	for level, found := range areas {
+		for _, area := range found {
+			days[level] += area.ExtraDays
+		}
+	}
+	return days
+}
+
+// weekendCounted reports, for each service level delivering on weekends for the request, whether
+// its transit window counts a weekend day: skipping the weekend days would deliver it later
+func (s *ShippingService) weekendCounted(prices pricing.Config, rates pricing.Rates, originZipcode, destinationZipcode, country string, req *model.CalculateShippingRequest, pickup *schedule.Pickup, extraDays map[string]int, nonDelivery map[string][]time.Weekday) map[string]bool {
+	counted := make(map[string]bool)
+	delivers := false
+	for _, level := range restrictableLevels {
+		if _, ok := prices.WeekendDeliveryFor(level, req.AllowWeekendDelivery); ok {
+			delivers = true
+		}
+	}
+	if !delivers {
+		return counted
+	}
+
+	weekdays := nonDeliveryDays(prices, false)
+	standard, express := s.deliveryDays(rates, originZipcode, destinationZipcode, country, req.IsReturn, pickup, extraDays, nonDelivery)
+	weekdayStandard, weekdayExpress := s.deliveryDays(rates, originZipcode, destinationZipcode, country, req.IsReturn, pickup, extraDays, weekdays)
+	counted[pricing.LevelStandard] = standard < weekdayStandard
+	counted[pricing.LevelExpress] = express < weekdayExpress
+	if rates.Freight != nil {
+		freight := s.freightDays(rates.Freight, originZipcode, destinationZipcode, country, pickup, extraDays[pricing.LevelFreight], nonDelivery[pricing.LevelFreight])
+		counted[pricing.LevelFreight] = freight < s.freightDays(rates.Freight, originZipcode, destinationZipcode, country, pickup, extraDays[pricing.LevelFreight], weekdays[pricing.LevelFreight])
+	}
+	return counted
+}
+
+// nonDeliveryDays returns the weekend days each service level does not deliver on, for a request
+// that allows weekend delivery or not
+func nonDeliveryDays(prices pricing.Config, allowed bool) map[string][]time.Weekday {
+	days := make(map[string][]time.Weekday)
+	for _, level := range restrictableLevels {
+		if closed := prices.NonDeliveryDays(level, allowed); len(closed) > 0 {
+			days[level] = closed
+		}
+	}
+	return days
+}
+
+// applyDuties itemizes the estimated import duty and tax in the breakdown and adds the landed cost
+func applyDuties(breakdown *model.CostBreakdown, estimate customs.Estimate, declaredValue money.Amount) {
+	breakdown.Duties = []model.DutyCharge{
+		{Name: model.DutyImportDuty, Rate: estimate.DutyRate, Amount: estimate.Duty},
+		{Name: model.DutyImportTax, Rate: estimate.TaxRate, Amount: estimate.Tax},
+	}
+	breakdown.LandedCost = declaredValue + breakdown.Total + estimate.Total()
+}
+
+// subtotalOf returns the cost of a service level without the express surcharge, the price limit
+// adjustment and additional services
+func subtotalOf(details *model.ShippingCalculationDetails) money.Amount {
+	return details.BaseCost + details.WeightSurcharge + details.VolumeSurcharge +
+		details.PackageTypeSurcharge + details.DeliveryTypeAdjustment + details.ReturnAdjustment +
+		details.PickupSurcharge + details.AreaSurcharge + details.RemoteSurcharge + details.WeekendSurcharge + details.FuelSurcharge
+}
+
+// resolveAdditionalServices looks up the fee of each requested additional service
+func resolveAdditionalServices(rates pricing.Rates, services []string) ([]model.ServiceFee, error) {
+	if len(services) == 0 {
+		return nil, nil
+	}
+	fees := make([]model.ServiceFee, 0, len(services))
+	seen := make(map[string]bool, len(services))
+	for _, service := range services {
+		service = strings.ToLower(strings.TrimSpace(service))
+		if seen[service] {
+			return nil, fmt.Errorf("duplicate additional service %q", service)
+		}
+		seen[service] = true
+
+		fee, err := rates.AdditionalServiceFee(service)
+		if err != nil {
+			return nil, err
+		}
+		fees = append(fees, model.ServiceFee{Service: service, Fee: fee})
+	}
+	return fees, nil
+}
+
+// totalFees sums the fees of the additional services
+func totalFees(fees []model.ServiceFee) money.Amount {
+	var total money.Amount
+	for _, fee := range fees {
+		total += fee.Fee
+	}
+	return total
+}
+
+// orDefault returns the trimmed, lowercase value, or def when it is empty
+func orDefault(value, def string) string {
+	if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+		return value
+	}
+	return def
+}
+
+// formatDays formats a number of days in Portuguese ("1 dia", "2 dias")
+func formatDays(days int) string {
+	if days == 1 {
+		return "1 dia"
+	}
+	return strconv.Itoa(days) + " dias"
+}
+
+// supportedStrategies returns the names of the registered pricing strategies in alphabetical order
+func (s *ShippingService) supportedStrategies() []string {
+	names := make([]string, 0, len(s.strategies))
+	for name := range s.strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Capabilities describes the limits and service levels supported by the service for the tenant
+// of ctx; unknown tenants are described with the rates of the default tenant
+func (s *ShippingService) Capabilities(ctx context.Context) model.ServiceCapabilities {
+	table, err := s.tenantTable(ctx)
+	if err != nil {
+		table = s.table.Load()
+	}
+	limits := model.Limits{
+		MinWeightExclusive: 0,
+		MaxVolumeCm3:       validator.MaxVolumeCm3,
+		ZipcodeMinDigits:   validator.MinZipcodeLength,
+		ZipcodeMaxDigits:   validator.ZipcodeLength,
+	}
+	rates := table.config.Currencies[table.config.DefaultCurrency()]
+	if n := len(rates.WeightTable); n > 0 {
+		limits.MaxWeightKg = rates.WeightTable[n-1].MaxWeightKg
+	}
+	if rates.Freight != nil {
+		limits.MaxFreightWeightKg = rates.Freight.MaxWeightKg
+	}
+	return model.ServiceCapabilities{
+		SupportedCountries:  table.config.SupportedCountries(),
+		SupportedCurrencies: table.config.SupportedCurrencies(),
+		PackageTypes:        table.config.SupportedPackageTypes(),
+		AdditionalServices:  table.config.SupportedAdditionalServices(),
+		DeliveryTypes:       table.config.SupportedDeliveryTypes(),
+		PricingVersion:      table.version,
+		PricingStrategies:   s.supportedStrategies(),
+		Units: model.Units{
+			Weight:         units.Kilogram,
+			Dimensions:     units.Centimeter,
+			WeightUnits:    units.WeightUnits(),
+			DimensionUnits: units.DimensionUnits(),
+			Currency:       table.config.DefaultCurrency(),
+			CostUnit:       "cents",
+		},
+		Limits:            limits,
+		AvailableServices: []string{model.ServiceStandard, model.ServiceExpress},
+		APIVersions:       []string{"v1", "v2"},
 	}
 }
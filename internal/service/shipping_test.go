@@ -2,9 +2,22 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/rbonfanti/shipping-calculator/internal/customs"
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/rbonfanti/shipping-calculator/internal/eta"
+	"github.com/rbonfanti/shipping-calculator/internal/experiment"
 	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/schedule"
+	"github.com/rbonfanti/shipping-calculator/internal/tax"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -41,7 +54,7 @@ func TestCalculateShipping_ValidRequest_Standard(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
-	assert.Greater(t, response.ShippingCost, 0.0)
+	assert.Greater(t, response.ShippingCost, money.FromMinor(0))
 	assert.Equal(t, "2 dias", response.EstimatedDeliveryTime)
 	assert.Equal(t, []string{"standard", "express"}, response.AvailableServices)
 	assert.Len(t, response.ShippingOptions, 2)
@@ -69,7 +82,7 @@ func TestCalculateShipping_ValidRequest_Express(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
-	assert.Greater(t, response.ShippingCost, 0.0)
+	assert.Greater(t, response.ShippingCost, money.FromMinor(0))
 	assert.Equal(t, "1 dia", response.EstimatedDeliveryTime)
 	assert.Equal(t, []string{"standard", "express"}, response.AvailableServices)
 	assert.Len(t, response.ShippingOptions, 2)
@@ -203,20 +216,20 @@ func TestCalculateShipping_VolumeExceedsLimit(t *testing.T) {
 func TestCalculateShippingDetails_StandardShipping(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 1.0
 	volume := 1000.0
 	isExpress := false
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	assert.NotNil(t, details)
-	assert.Equal(t, 1000.0, details.BaseCost)
-	assert.Greater(t, details.WeightSurcharge, 0.0)
-	assert.Greater(t, details.VolumeSurcharge, 0.0)
-	assert.Equal(t, 0.0, details.ExpressSurcharge)
+	assert.Equal(t, money.FromMinor(1000), details.BaseCost)
+	assert.Greater(t, details.WeightSurcharge, money.FromMinor(0))
+	assert.Greater(t, details.VolumeSurcharge, money.FromMinor(0))
+	assert.Equal(t, money.FromMinor(0), details.ExpressSurcharge)
 	assert.Greater(t, details.TotalCost, details.BaseCost)
 	assert.Equal(t, 2, details.EstimatedDays)
 }
@@ -224,20 +237,20 @@ func TestCalculateShippingDetails_StandardShipping(t *testing.T) {
 func TestCalculateShippingDetails_ExpressShipping(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 1.0
 	volume := 1000.0
 	isExpress := true
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	assert.NotNil(t, details)
-	assert.Equal(t, 1000.0, details.BaseCost)
-	assert.Greater(t, details.WeightSurcharge, 0.0)
-	assert.Greater(t, details.VolumeSurcharge, 0.0)
-	assert.Greater(t, details.ExpressSurcharge, 0.0)
+	assert.Equal(t, money.FromMinor(1000), details.BaseCost)
+	assert.Greater(t, details.WeightSurcharge, money.FromMinor(0))
+	assert.Greater(t, details.VolumeSurcharge, money.FromMinor(0))
+	assert.Greater(t, details.ExpressSurcharge, money.FromMinor(0))
 	assert.Greater(t, details.TotalCost, details.BaseCost)
 	assert.Equal(t, 1, details.EstimatedDays)
 }
@@ -245,20 +258,20 @@ func TestCalculateShippingDetails_ExpressShipping(t *testing.T) {
 func TestCalculateShippingDetails_HeavyPackage(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 5.0
 	volume := 1000.0
 	isExpress := false
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	assert.NotNil(t, details)
-	assert.Equal(t, 1000.0, details.BaseCost)
-	assert.Greater(t, details.WeightSurcharge, 0.0)
-	assert.Greater(t, details.VolumeSurcharge, 0.0)
-	assert.Equal(t, 0.0, details.ExpressSurcharge)
+	assert.Equal(t, money.FromMinor(1000), details.BaseCost)
+	assert.Greater(t, details.WeightSurcharge, money.FromMinor(0))
+	assert.Greater(t, details.VolumeSurcharge, money.FromMinor(0))
+	assert.Equal(t, money.FromMinor(0), details.ExpressSurcharge)
 	assert.Greater(t, details.TotalCost, details.BaseCost)
 	assert.Equal(t, 2, details.EstimatedDays)
 }
@@ -266,20 +279,20 @@ func TestCalculateShippingDetails_HeavyPackage(t *testing.T) {
 func TestCalculateShippingDetails_LargeVolume(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 1.0
 	volume := 5000.0
 	isExpress := false
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	assert.NotNil(t, details)
-	assert.Equal(t, 1000.0, details.BaseCost)
-	assert.Greater(t, details.WeightSurcharge, 0.0)
-	assert.Greater(t, details.VolumeSurcharge, 0.0)
-	assert.Equal(t, 0.0, details.ExpressSurcharge)
+	assert.Equal(t, money.FromMinor(1000), details.BaseCost)
+	assert.Greater(t, details.WeightSurcharge, money.FromMinor(0))
+	assert.Greater(t, details.VolumeSurcharge, money.FromMinor(0))
+	assert.Equal(t, money.FromMinor(0), details.ExpressSurcharge)
 	assert.Greater(t, details.TotalCost, details.BaseCost)
 	assert.Equal(t, 2, details.EstimatedDays)
 }
@@ -288,26 +301,29 @@ func TestBuildResponse_StandardShipping(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
 	details := &model.ShippingCalculationDetails{
-		BaseCost:         1000.0,
-		WeightSurcharge:  200.0,
-		VolumeSurcharge:  50.0,
-		ExpressSurcharge: 0.0,
-		TotalCost:        1250.0,
+		BaseCost:         money.FromMinor(1000),
+		WeightSurcharge:  money.FromMinor(200),
+		VolumeSurcharge:  money.FromMinor(50),
+		ExpressSurcharge: money.FromMinor(0),
+		TotalCost:        money.FromMinor(1250),
 		EstimatedDays:    2,
+		StandardDays:     2,
+		ExpressDays:      1,
 	}
 	isExpress := false
 
 	// Act
-	response := service.buildResponse(details, isExpress)
+	standard, express := serviceLevels(pricing.DefaultRates(), details)
+	response := service.buildResponse(pricing.DefaultRates(), standard, express, isExpress)
 
 	// Assert
 	assert.NotNil(t, response)
-	assert.Equal(t, 1250.0, response.ShippingCost)
+	assert.Equal(t, money.FromMinor(1250), response.ShippingCost)
 	assert.Equal(t, "2 dias", response.EstimatedDeliveryTime)
 	assert.Equal(t, []string{"standard", "express"}, response.AvailableServices)
 	assert.Len(t, response.ShippingOptions, 2)
 	assert.Equal(t, "standard", response.ShippingOptions[0].Service)
-	assert.Equal(t, 1250.0, response.ShippingOptions[0].Cost)
+	assert.Equal(t, money.FromMinor(1250), response.ShippingOptions[0].Cost)
 	assert.Equal(t, "2 dias", response.ShippingOptions[0].Time)
 	assert.Equal(t, "express", response.ShippingOptions[1].Service)
 	assert.Greater(t, response.ShippingOptions[1].Cost, response.ShippingOptions[0].Cost)
@@ -318,50 +334,53 @@ func TestBuildResponse_ExpressShipping(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
 	details := &model.ShippingCalculationDetails{
-		BaseCost:         1000.0,
-		WeightSurcharge:  200.0,
-		VolumeSurcharge:  50.0,
-		ExpressSurcharge: 625.0,
-		TotalCost:        1875.0,
+		BaseCost:         money.FromMinor(1000),
+		WeightSurcharge:  money.FromMinor(200),
+		VolumeSurcharge:  money.FromMinor(50),
+		ExpressSurcharge: money.FromMinor(625),
+		TotalCost:        money.FromMinor(1875),
 		EstimatedDays:    1,
+		StandardDays:     2,
+		ExpressDays:      1,
 	}
 	isExpress := true
 
 	// Act
-	response := service.buildResponse(details, isExpress)
+	standard, express := serviceLevels(pricing.DefaultRates(), details)
+	response := service.buildResponse(pricing.DefaultRates(), standard, express, isExpress)
 
 	// Assert
 	assert.NotNil(t, response)
 	expectedExpressCost := 1250.0 * (1 + 0.50) // 50% surcharge
-	assert.Equal(t, expectedExpressCost, response.ShippingCost)
+	assert.Equal(t, money.FromMinor(expectedExpressCost), response.ShippingCost)
 	assert.Equal(t, "1 dia", response.EstimatedDeliveryTime)
 	assert.Equal(t, []string{"standard", "express"}, response.AvailableServices)
 	assert.Len(t, response.ShippingOptions, 2)
 	assert.Equal(t, "standard", response.ShippingOptions[0].Service)
-	assert.Equal(t, 1250.0, response.ShippingOptions[0].Cost)
+	assert.Equal(t, money.FromMinor(1250), response.ShippingOptions[0].Cost)
 	assert.Equal(t, "2 dias", response.ShippingOptions[0].Time)
 	assert.Equal(t, "express", response.ShippingOptions[1].Service)
-	assert.Equal(t, expectedExpressCost, response.ShippingOptions[1].Cost)
+	assert.Equal(t, money.FromMinor(expectedExpressCost), response.ShippingOptions[1].Cost)
 	assert.Equal(t, "1 dia", response.ShippingOptions[1].Time)
 }
 
 func TestCalculateShippingDetails_ZeroWeight(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 0.1
 	volume := 1000.0
 	isExpress := false
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	assert.NotNil(t, details)
-	assert.Equal(t, 1000.0, details.BaseCost)
-	assert.GreaterOrEqual(t, details.WeightSurcharge, 0.0)
-	assert.Greater(t, details.VolumeSurcharge, 0.0)
-	assert.Equal(t, 0.0, details.ExpressSurcharge)
+	assert.Equal(t, money.FromMinor(1000), details.BaseCost)
+	assert.GreaterOrEqual(t, details.WeightSurcharge, money.FromMinor(0))
+	assert.Greater(t, details.VolumeSurcharge, money.FromMinor(0))
+	assert.Equal(t, money.FromMinor(0), details.ExpressSurcharge)
 	assert.Greater(t, details.TotalCost, details.BaseCost)
 	assert.Equal(t, 2, details.EstimatedDays)
 }
@@ -369,20 +388,20 @@ func TestCalculateShippingDetails_ZeroWeight(t *testing.T) {
 func TestCalculateShippingDetails_ZeroVolume(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 1.0
 	volume := 100.0
 	isExpress := false
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	assert.NotNil(t, details)
-	assert.Equal(t, 1000.0, details.BaseCost)
-	assert.Greater(t, details.WeightSurcharge, 0.0)
-	assert.GreaterOrEqual(t, details.VolumeSurcharge, 0.0)
-	assert.Equal(t, 0.0, details.ExpressSurcharge)
+	assert.Equal(t, money.FromMinor(1000), details.BaseCost)
+	assert.Greater(t, details.WeightSurcharge, money.FromMinor(0))
+	assert.GreaterOrEqual(t, details.VolumeSurcharge, money.FromMinor(0))
+	assert.Equal(t, money.FromMinor(0), details.ExpressSurcharge)
 	assert.Greater(t, details.TotalCost, details.BaseCost)
 	assert.Equal(t, 2, details.EstimatedDays)
 }
@@ -390,20 +409,20 @@ func TestCalculateShippingDetails_ZeroVolume(t *testing.T) {
 func TestCalculateShippingDetails_ExpressWithHeavyPackage(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 10.0
 	volume := 5000.0
 	isExpress := true
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	assert.NotNil(t, details)
-	assert.Equal(t, 1000.0, details.BaseCost)
-	assert.Greater(t, details.WeightSurcharge, 0.0)
-	assert.Greater(t, details.VolumeSurcharge, 0.0)
-	assert.Greater(t, details.ExpressSurcharge, 0.0)
+	assert.Equal(t, money.FromMinor(1000), details.BaseCost)
+	assert.Greater(t, details.WeightSurcharge, money.FromMinor(0))
+	assert.Greater(t, details.VolumeSurcharge, money.FromMinor(0))
+	assert.Greater(t, details.ExpressSurcharge, money.FromMinor(0))
 	assert.Greater(t, details.TotalCost, details.BaseCost)
 	assert.Equal(t, 1, details.EstimatedDays)
 }
@@ -430,7 +449,7 @@ func TestCalculateShipping_WithDifferentZipcodeFormats(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
-	assert.Greater(t, response.ShippingCost, 0.0)
+	assert.Greater(t, response.ShippingCost, money.FromMinor(0))
 }
 
 func TestCalculateShipping_WithSpacesInZipcode(t *testing.T) {
@@ -455,7 +474,7 @@ func TestCalculateShipping_WithSpacesInZipcode(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
-	assert.Greater(t, response.ShippingCost, 0.0)
+	assert.Greater(t, response.ShippingCost, money.FromMinor(0))
 }
 
 func TestCalculateShipping_ExampleCase(t *testing.T) {
@@ -485,129 +504,101 @@ func TestCalculateShipping_ExampleCase(t *testing.T) {
 	// Volume surcharge: 240cm³ / 1000 = 0.24 → 1000 * 0.05 * 0.24 = 12
 	// Standard cost: 1000 + 100 + 12 = 1112
 	expectedStandardCost := 1112.0
-	assert.Equal(t, expectedStandardCost, response.ShippingCost)
+	assert.Equal(t, money.FromMinor(expectedStandardCost), response.ShippingCost)
 	assert.Equal(t, "2 dias", response.EstimatedDeliveryTime)
 	assert.Equal(t, []string{"standard", "express"}, response.AvailableServices)
 	assert.Len(t, response.ShippingOptions, 2)
 	assert.Equal(t, "standard", response.ShippingOptions[0].Service)
-	assert.Equal(t, expectedStandardCost, response.ShippingOptions[0].Cost)
+	assert.Equal(t, money.FromMinor(expectedStandardCost), response.ShippingOptions[0].Cost)
 	assert.Equal(t, "2 dias", response.ShippingOptions[0].Time)
 	assert.Equal(t, "express", response.ShippingOptions[1].Service)
 	expectedExpressCost := expectedStandardCost * 1.5 // 50% surcharge
-	assert.Equal(t, expectedExpressCost, response.ShippingOptions[1].Cost)
+	assert.Equal(t, money.FromMinor(expectedExpressCost), response.ShippingOptions[1].Cost)
 	assert.Equal(t, "1 dia", response.ShippingOptions[1].Time)
 }
 
-func TestCalculateBaseCost_SameRegion(t *testing.T) {
-	// Arrange
-	service := NewShippingService()
-	originZipcode := "1414"
-	destinationZipcode := "1428"
-
-	// Act
-	baseCost := service.calculateBaseCost(originZipcode, destinationZipcode)
-
-	// Assert
-	// Distance is 14 (< 1000), so should return base cost
-	assert.Equal(t, 1000.0, baseCost)
-}
-
-func TestCalculateBaseCost_DifferentRegions(t *testing.T) {
-	// Arrange
-	service := NewShippingService()
-	originZipcode := "01000-000"
-	destinationZipcode := "20000-000"
-
-	// Act
-	baseCost := service.calculateBaseCost(originZipcode, destinationZipcode)
-
-	// Assert
-	// Distance is 10000, so should have increased base cost
-	assert.Greater(t, baseCost, 1000.0)
-}
-
 func TestCalculateShippingDetails_WeightSurcharge_10PercentPerHalfKg(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 1.0 // 1 kg = 2 units of 0.5 kg
 	volume := 1000.0
 	isExpress := false
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	// Weight multiplier: 1.0 / 0.5 = 2.0
 	// Weight surcharge: 1000 * 0.10 * 2.0 = 200
 	expectedWeightSurcharge := 200.0
-	assert.Equal(t, expectedWeightSurcharge, details.WeightSurcharge)
+	assert.Equal(t, money.FromMinor(expectedWeightSurcharge), details.WeightSurcharge)
 }
 
 func TestCalculateShippingDetails_WeightSurcharge_MultipleHalfKgs(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 2.5 // 2.5 kg = 5 units of 0.5 kg
 	volume := 1000.0
 	isExpress := false
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	// Weight multiplier: 2.5 / 0.5 = 5.0
 	// Weight surcharge: 1000 * 0.10 * 5.0 = 500
 	expectedWeightSurcharge := 500.0
-	assert.Equal(t, expectedWeightSurcharge, details.WeightSurcharge)
+	assert.Equal(t, money.FromMinor(expectedWeightSurcharge), details.WeightSurcharge)
 }
 
 func TestCalculateShippingDetails_VolumeSurcharge_5PercentPer1000Cm3(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 1.0
 	volume := 2000.0 // 2000 cm³ = 2 units of 1000 cm³
 	isExpress := false
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	// Volume multiplier: 2000 / 1000 = 2.0
 	// Volume surcharge: 1000 * 0.05 * 2.0 = 100
 	expectedVolumeSurcharge := 100.0
-	assert.Equal(t, expectedVolumeSurcharge, details.VolumeSurcharge)
+	assert.Equal(t, money.FromMinor(expectedVolumeSurcharge), details.VolumeSurcharge)
 }
 
 func TestCalculateShippingDetails_VolumeSurcharge_Multiple1000Cm3(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 1.0
 	volume := 5000.0 // 5000 cm³ = 5 units of 1000 cm³
 	isExpress := false
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	// Volume multiplier: 5000 / 1000 = 5.0
 	// Volume surcharge: 1000 * 0.05 * 5.0 = 250
 	expectedVolumeSurcharge := 250.0
-	assert.Equal(t, expectedVolumeSurcharge, details.VolumeSurcharge)
+	assert.Equal(t, money.FromMinor(expectedVolumeSurcharge), details.VolumeSurcharge)
 }
 
 func TestCalculateShippingDetails_ExpressSurcharge_50Percent(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 1.0
 	volume := 1000.0
 	isExpress := true
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	// Weight surcharge: 1000 * 0.10 * 2.0 = 200
@@ -616,7 +607,7 @@ func TestCalculateShippingDetails_ExpressSurcharge_50Percent(t *testing.T) {
 	// Express surcharge: 1250 * 0.50 = 625
 	expectedSubtotal := 1250.0
 	expectedExpressSurcharge := expectedSubtotal * 0.50
-	assert.Equal(t, expectedExpressSurcharge, details.ExpressSurcharge)
+	assert.Equal(t, money.FromMinor(expectedExpressSurcharge), details.ExpressSurcharge)
 }
 
 func TestCalculateShipping_CompleteCalculation_Standard(t *testing.T) {
@@ -645,7 +636,7 @@ func TestCalculateShipping_CompleteCalculation_Standard(t *testing.T) {
 	// Weight surcharge: baseCost * 0.10 * 3.0
 	// Volume surcharge: baseCost * 0.05 * 3.0
 	// Standard cost should be greater than base cost
-	assert.Greater(t, response.ShippingCost, 1000.0)
+	assert.Greater(t, response.ShippingCost, money.FromMinor(1000))
 	assert.Equal(t, "2 dias", response.EstimatedDeliveryTime)
 }
 
@@ -674,157 +665,101 @@ func TestCalculateShipping_CompleteCalculation_Express(t *testing.T) {
 	// Express cost should be 50% more than standard
 	standardCost := response.ShippingOptions[0].Cost
 	expressCost := response.ShippingOptions[1].Cost
-	expectedExpressCost := standardCost * 1.5
+	expectedExpressCost := standardCost.MulRate(1.5)
 	assert.Equal(t, expectedExpressCost, expressCost)
 	assert.Equal(t, expectedExpressCost, response.ShippingCost)
 	assert.Equal(t, "1 dia", response.EstimatedDeliveryTime)
 }
 
-func TestCalculateBaseCost_InvalidZipcode_NonNumeric(t *testing.T) {
-	// Arrange
-	service := NewShippingService()
-	originZipcode := "abc"
-	destinationZipcode := "def"
-
-	// Act
-	baseCost := service.calculateBaseCost(originZipcode, destinationZipcode)
-
-	// Assert
-	// Should return default base cost when conversion fails
-	assert.Equal(t, 1000.0, baseCost)
-}
-
-func TestCalculateBaseCost_InvalidZipcode_Empty(t *testing.T) {
-	// Arrange
-	service := NewShippingService()
-	originZipcode := ""
-	destinationZipcode := ""
-
-	// Act
-	baseCost := service.calculateBaseCost(originZipcode, destinationZipcode)
-
-	// Assert
-	// Should return default base cost when conversion fails
-	assert.Equal(t, 1000.0, baseCost)
-}
-
-func TestCalculateBaseCost_NegativeDistance(t *testing.T) {
-	// Arrange
-	service := NewShippingService()
-	originZipcode := "20000"
-	destinationZipcode := "10000"
-
-	// Act
-	baseCost := service.calculateBaseCost(originZipcode, destinationZipcode)
-
-	// Assert
-	// Distance is 10000, should have increased base cost
-	assert.Greater(t, baseCost, 1000.0)
-}
-
-func TestCalculateBaseCost_WithHyphensAndSpaces(t *testing.T) {
-	// Arrange
-	service := NewShippingService()
-	originZipcode := "12 345-678"
-	destinationZipcode := "87-654 321"
-
-	// Act
-	baseCost := service.calculateBaseCost(originZipcode, destinationZipcode)
-
-	// Assert
-	// Should normalize and calculate correctly
-	assert.Greater(t, baseCost, 0.0)
-}
-
 func TestCalculateShippingDetails_WeightSurcharge_ExactHalfKg(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 0.5 // Exactly 0.5 kg = 1 unit
 	volume := 1000.0
 	isExpress := false
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	// Weight multiplier: 0.5 / 0.5 = 1.0
 	// Weight surcharge: 1000 * 0.10 * 1.0 = 100
 	expectedWeightSurcharge := 100.0
-	assert.Equal(t, expectedWeightSurcharge, details.WeightSurcharge)
+	assert.Equal(t, money.FromMinor(expectedWeightSurcharge), details.WeightSurcharge)
 }
 
 func TestCalculateShippingDetails_WeightSurcharge_LessThanHalfKg(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 0.25 // Less than 0.5 kg
 	volume := 1000.0
 	isExpress := false
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	// Weight multiplier: 0.25 / 0.5 = 0.5
 	// Weight surcharge: 1000 * 0.10 * 0.5 = 50
 	expectedWeightSurcharge := 50.0
-	assert.Equal(t, expectedWeightSurcharge, details.WeightSurcharge)
+	assert.Equal(t, money.FromMinor(expectedWeightSurcharge), details.WeightSurcharge)
 }
 
 func TestCalculateShippingDetails_VolumeSurcharge_Exact1000Cm3(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 1.0
 	volume := 1000.0 // Exactly 1000 cm³ = 1 unit
 	isExpress := false
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	// Volume multiplier: 1000 / 1000 = 1.0
 	// Volume surcharge: 1000 * 0.05 * 1.0 = 50
 	expectedVolumeSurcharge := 50.0
-	assert.Equal(t, expectedVolumeSurcharge, details.VolumeSurcharge)
+	assert.Equal(t, money.FromMinor(expectedVolumeSurcharge), details.VolumeSurcharge)
 }
 
 func TestCalculateShippingDetails_VolumeSurcharge_LessThan1000Cm3(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 1000.0
+	baseCost := money.FromMinor(1000)
 	weight := 1.0
 	volume := 500.0 // Less than 1000 cm³
 	isExpress := false
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
 	// Volume multiplier: 500 / 1000 = 0.5
 	// Volume surcharge: 1000 * 0.05 * 0.5 = 25
 	expectedVolumeSurcharge := 25.0
-	assert.Equal(t, expectedVolumeSurcharge, details.VolumeSurcharge)
+	assert.Equal(t, money.FromMinor(expectedVolumeSurcharge), details.VolumeSurcharge)
 }
 
 func TestCalculateShippingDetails_ExpressSurcharge_ZeroSubtotal(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
-	baseCost := 0.0
+	baseCost := money.Amount(0)
 	weight := 0.0
 	volume := 0.0
 	isExpress := true
 
 	// Act
-	details := service.calculateShippingDetails(baseCost, weight, volume, isExpress)
+	details := service.calculateShippingDetails(pricing.DefaultRates(), pricing.PackageType{}, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), baseCost, weight, volume), isExpress)
 
 	// Assert
-	assert.Equal(t, 0.0, details.BaseCost)
-	assert.Equal(t, 0.0, details.WeightSurcharge)
-	assert.Equal(t, 0.0, details.VolumeSurcharge)
-	assert.Equal(t, 0.0, details.ExpressSurcharge)
-	assert.Equal(t, 0.0, details.TotalCost)
+	assert.Equal(t, money.FromMinor(0), details.BaseCost)
+	assert.Equal(t, money.FromMinor(0), details.WeightSurcharge)
+	assert.Equal(t, money.FromMinor(0), details.VolumeSurcharge)
+	assert.Equal(t, money.FromMinor(0), details.ExpressSurcharge)
+	assert.Equal(t, money.FromMinor(0), details.TotalCost)
 	assert.Equal(t, 1, details.EstimatedDays)
 }
 
@@ -832,17 +767,20 @@ func TestBuildResponse_StandardShipping_PluralDays(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
 	details := &model.ShippingCalculationDetails{
-		BaseCost:         1000.0,
-		WeightSurcharge:  200.0,
-		VolumeSurcharge:  50.0,
-		ExpressSurcharge: 0.0,
-		TotalCost:        1250.0,
+		BaseCost:         money.FromMinor(1000),
+		WeightSurcharge:  money.FromMinor(200),
+		VolumeSurcharge:  money.FromMinor(50),
+		ExpressSurcharge: money.FromMinor(0),
+		TotalCost:        money.FromMinor(1250),
 		EstimatedDays:    2,
+		StandardDays:     2,
+		ExpressDays:      1,
 	}
 	isExpress := false
 
 	// Act
-	response := service.buildResponse(details, isExpress)
+	standard, express := serviceLevels(pricing.DefaultRates(), details)
+	response := service.buildResponse(pricing.DefaultRates(), standard, express, isExpress)
 
 	// Assert
 	assert.NotNil(t, response)
@@ -854,17 +792,20 @@ func TestBuildResponse_ExpressShipping_SingularDay(t *testing.T) {
 	// Arrange
 	service := NewShippingService()
 	details := &model.ShippingCalculationDetails{
-		BaseCost:         1000.0,
-		WeightSurcharge:  200.0,
-		VolumeSurcharge:  50.0,
-		ExpressSurcharge: 625.0,
-		TotalCost:        1875.0,
+		BaseCost:         money.FromMinor(1000),
+		WeightSurcharge:  money.FromMinor(200),
+		VolumeSurcharge:  money.FromMinor(50),
+		ExpressSurcharge: money.FromMinor(625),
+		TotalCost:        money.FromMinor(1875),
 		EstimatedDays:    1,
+		StandardDays:     2,
+		ExpressDays:      1,
 	}
 	isExpress := true
 
 	// Act
-	response := service.buildResponse(details, isExpress)
+	standard, express := serviceLevels(pricing.DefaultRates(), details)
+	response := service.buildResponse(pricing.DefaultRates(), standard, express, isExpress)
 
 	// Assert
 	assert.NotNil(t, response)
@@ -971,3 +912,2150 @@ func TestCalculateShipping_InvalidDimensions_NegativeHeight(t *testing.T) {
 	assert.Nil(t, response)
 	assert.Contains(t, err.Error(), "invalid dimensions")
 }
+
+func TestNewShippingServiceWithConfig_DefaultEstimator(t *testing.T) {
+	// Act
+	service := NewShippingServiceWithConfig(Config{})
+
+	// Assert
+	assert.NotNil(t, service)
+	assert.NotNil(t, service.estimator)
+}
+
+func TestCalculateShipping_WithWarehouseHandlingTime(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	estimator := eta.NewEstimator(eta.Config{
+		Warehouses: []eta.Warehouse{{ID: "sp", ZipcodePrefixes: []string{"123"}, HandlingDays: 1}},
+	})
+	service := NewShippingServiceWithConfig(Config{Estimator: estimator})
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "12345678",
+		DestinationZipcode: "87654321",
+		Weight:             1.0,
+		Dimensions: model.PackageDimensions{
+			Length: 10.0,
+			Width:  10.0,
+			Height: 10.0,
+		},
+		IsExpress: true,
+	}
+
+	// Act
+	response, err := service.CalculateShipping(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "2 dias", response.EstimatedDeliveryTime)
+	assert.Equal(t, "3 dias", response.ShippingOptions[0].Time)
+	assert.Equal(t, "2 dias", response.ShippingOptions[1].Time)
+}
+
+func TestBuildResponse_WithHandlingDays(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	details := &model.ShippingCalculationDetails{
+		BaseCost:      money.FromMinor(1000),
+		TotalCost:     money.FromMinor(1000),
+		EstimatedDays: 4,
+		StandardDays:  4,
+		ExpressDays:   3,
+		HandlingDays:  2,
+	}
+
+	// Act
+	standard, express := serviceLevels(pricing.DefaultRates(), details)
+	response := service.buildResponse(pricing.DefaultRates(), standard, express, false)
+
+	// Assert
+	assert.Equal(t, "4 dias", response.EstimatedDeliveryTime)
+	assert.Equal(t, "4 dias", response.ShippingOptions[0].Time)
+	assert.Equal(t, "3 dias", response.ShippingOptions[1].Time)
+}
+
+func TestFormatDays(t *testing.T) {
+	// Act & Assert
+	assert.Equal(t, "1 dia", formatDays(1))
+	assert.Equal(t, "2 dias", formatDays(2))
+	assert.Equal(t, "0 dias", formatDays(0))
+}
+
+func TestCapabilities(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+
+	// Act
+	capabilities := service.Capabilities(context.Background())
+
+	// Assert
+	assert.Equal(t, []string{"BR", "DE", "ES", "FR", "IT", "NL", "PT", "US"}, capabilities.SupportedCountries)
+	assert.Equal(t, []string{"BRL", "EUR", "USD"}, capabilities.SupportedCurrencies)
+	assert.Equal(t, []string{"dangerous", "fragile", "perishable", "standard"}, capabilities.PackageTypes)
+	assert.Equal(t, []string{"cod", "saturday_delivery", "signature"}, capabilities.AdditionalServices)
+	assert.Equal(t, []string{"home", "locker", "pickup_point"}, capabilities.DeliveryTypes)
+	assert.Equal(t, pricing.DefaultConfig().VersionID(), capabilities.PricingVersion)
+	assert.Equal(t, []string{"formula", "table"}, capabilities.PricingStrategies)
+	assert.Equal(t, "BRL", capabilities.Units.Currency)
+	assert.Equal(t, []string{"g", "kg", "lb"}, capabilities.Units.WeightUnits)
+	assert.Equal(t, []string{"cm", "in", "m"}, capabilities.Units.DimensionUnits)
+	assert.Equal(t, 15000.0, capabilities.Limits.MaxVolumeCm3)
+	assert.Equal(t, 4, capabilities.Limits.ZipcodeMinDigits)
+	assert.Equal(t, 8, capabilities.Limits.ZipcodeMaxDigits)
+	assert.Zero(t, capabilities.Limits.MaxWeightKg, "the default rates have no weight table")
+	assert.Zero(t, capabilities.Limits.MaxFreightWeightKg, "the default rates offer no freight")
+	assert.Equal(t, []string{"standard", "express"}, capabilities.AvailableServices)
+}
+
+func TestCapabilities_MaxWeight(t *testing.T) {
+	// Arrange
+	cfg := freightConfig()
+	rates := cfg.Currencies["BRL"]
+	rates.WeightTable = []pricing.WeightBracket{{MaxWeightKg: 2, Cost: money.FromMinor(1500)}, {MaxWeightKg: 30, Cost: money.FromMinor(4500)}}
+	cfg.Currencies["BRL"] = rates
+	service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+
+	// Act
+	capabilities := service.Capabilities(context.Background())
+
+	// Assert
+	assert.Equal(t, 30.0, capabilities.Limits.MaxWeightKg)
+	assert.Equal(t, 1000.0, capabilities.Limits.MaxFreightWeightKg)
+}
+
+func TestCalculateShipping_PricingVersion(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	cfg.Version = "2025.03"
+	service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "12345678",
+		DestinationZipcode: "12345678",
+		Weight:             1,
+		Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+	}
+
+	// Act
+	response, err := service.CalculateShipping(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "2025.03", response.PricingVersion)
+}
+
+func TestCalculateShipping_PricingExperiment(t *testing.T) {
+	treatment := pricing.DefaultConfig()
+	treatment.Version = "volume-curve-b"
+	rates := treatment.Currencies["BRL"]
+	rates.BaseCost = money.FromMinor(2000)
+	treatment.Currencies["BRL"] = rates
+
+	tests := []struct {
+		name         string
+		fraction     float64
+		withClientID bool
+		wantArm      string
+		wantCost     float64
+		wantVersion  string
+	}{
+		{"treatment arm", 1, true, experiment.ArmTreatment, 2500.0, "volume-curve-b"},
+		{"control arm", 0, true, experiment.ArmControl, 1250.0, pricing.DefaultConfig().VersionID()},
+		{"no client id", 1, false, experiment.ArmControl, 1250.0, pricing.DefaultConfig().VersionID()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingServiceWithConfig(Config{
+				Experiment: &experiment.Experiment{Name: "volume-curve", Fraction: tt.fraction, Treatment: treatment},
+			})
+			ctx := context.Background()
+			if tt.withClientID {
+				ctx = experiment.WithSubject(ctx, "acme-store")
+			}
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "12345678",
+				DestinationZipcode: "12345678",
+				Weight:             1,
+				Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+			}
+
+			// Act
+			response, err := service.CalculateShipping(ctx, req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, &model.ExperimentAssignment{Name: "volume-curve", Arm: tt.wantArm}, response.Experiment)
+			assert.Equal(t, money.FromMinor(tt.wantCost), response.ShippingCost)
+			assert.Equal(t, tt.wantVersion, response.PricingVersion)
+		})
+	}
+}
+
+func TestCalculateShipping_PinnedExperimentArm(t *testing.T) {
+	treatment := pricing.DefaultConfig()
+	treatment.Version = "volume-curve-b"
+	rates := treatment.Currencies["BRL"]
+	rates.BaseCost = money.FromMinor(2000)
+	treatment.Currencies["BRL"] = rates
+
+	tests := []struct {
+		name     string
+		fraction float64
+		pinned   *model.ExperimentAssignment
+		wantArm  string
+		wantCost float64
+	}{
+		{"pinned treatment arm", 0, &model.ExperimentAssignment{Name: "volume-curve", Arm: experiment.ArmTreatment}, experiment.ArmTreatment, 2500.0},
+		{"pinned control arm", 1, &model.ExperimentAssignment{Name: "volume-curve", Arm: experiment.ArmControl}, experiment.ArmControl, 1250.0},
+		{"arm of another experiment", 1, &model.ExperimentAssignment{Name: "old-curve", Arm: experiment.ArmTreatment}, experiment.ArmControl, 1250.0},
+		{"quote priced outside of the experiment", 1, nil, experiment.ArmControl, 1250.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingServiceWithConfig(Config{
+				Experiment: &experiment.Experiment{Name: "volume-curve", Fraction: tt.fraction, Treatment: treatment},
+			})
+			ctx := WithExperimentArm(experiment.WithSubject(context.Background(), "acme-store"), tt.pinned)
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "12345678",
+				DestinationZipcode: "12345678",
+				Weight:             1,
+				Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+			}
+
+			// Act
+			response, err := service.CalculateShipping(ctx, req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, &model.ExperimentAssignment{Name: "volume-curve", Arm: tt.wantArm}, response.Experiment)
+			assert.Equal(t, money.FromMinor(tt.wantCost), response.ShippingCost)
+		})
+	}
+}
+
+func TestCalculateShipping_WithoutExperiment(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "12345678",
+		DestinationZipcode: "12345678",
+		Weight:             1,
+		Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+	}
+
+	// Act
+	response, err := service.CalculateShipping(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, response.Experiment)
+}
+
+func TestCalculateShipping_CurrencySelection(t *testing.T) {
+	tests := []struct {
+		name         string
+		country      string
+		currency     string
+		wantCurrency string
+		wantCost     float64
+	}{
+		{"default country", "", "", "BRL", 1250.0},
+		{"by destination country", "US", "", "USD", 625.0},
+		{"country is case-insensitive", "de", "", "EUR", 563.0},
+		{"explicit currency wins", "BR", "USD", "USD", 625.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingService()
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "12345678",
+				DestinationZipcode: "12345678",
+				Weight:             1.0,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				DestinationCountry: tt.country,
+				Currency:           tt.currency,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCurrency, response.Currency)
+			assert.Equal(t, money.FromMinor(tt.wantCost), response.ShippingCost)
+		})
+	}
+}
+
+func TestCalculateShipping_UnsupportedCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		country  string
+		currency string
+		wantErr  error
+	}{
+		{"unknown country", "AR", "", pricing.ErrUnsupportedCountry},
+		{"unknown currency", "BR", "JPY", pricing.ErrUnsupportedCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingService()
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "12345678",
+				DestinationZipcode: "12345678",
+				Weight:             1.0,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				DestinationCountry: tt.country,
+				Currency:           tt.currency,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.Nil(t, response)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), "invalid currency")
+		})
+	}
+}
+
+func TestBuildResponse_RoundsPerCurrency(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	rates := pricing.DefaultRates()
+	rates.RoundingIncrement = money.FromMinor(5)
+	details := &model.ShippingCalculationDetails{
+		BaseCost:        money.FromMinor(1000),
+		WeightSurcharge: money.FromMinor(12.4),
+		EstimatedDays:   2,
+		StandardDays:    2,
+		ExpressDays:     1,
+	}
+
+	// Act
+	standard, express := serviceLevels(rates, details)
+	response := service.buildResponse(rates, standard, express, false)
+
+	// Assert
+	assert.Equal(t, money.FromMinor(1010), response.ShippingOptions[0].Cost)
+	assert.Equal(t, money.FromMinor(1520), response.ShippingOptions[1].Cost)
+}
+
+func TestCalculateShipping_RoundingPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		increment      float64
+		mode           string
+		wantCost       float64
+		wantAdjustment float64
+	}{
+		{"disabled", 0, "", 1437.5, 0},
+		{"nearest five cents", 5, pricing.RoundHalfUp, 1440, 2.5},
+		{"banker's rounding to cents", 1, pricing.RoundHalfEven, 1438, 0.5},
+		{"up to whole reais", 100, pricing.RoundUp, 1500, 62.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := pricing.DefaultConfig()
+			rates := cfg.Currencies["BRL"]
+			rates.RoundingIncrement = money.FromMinor(tt.increment)
+			rates.RoundingMode = tt.mode
+			cfg.Currencies["BRL"] = rates
+			service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "12345678",
+				DestinationZipcode: "12345678",
+				Weight:             1.0,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				PackageType:        "fragile",
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, money.FromMinor(tt.wantCost), response.ShippingCost)
+			assert.Equal(t, money.FromMinor(1437.5), response.Breakdown.UnroundedTotal)
+			assert.Equal(t, money.FromMinor(tt.wantAdjustment), response.Breakdown.RoundingAdjustment)
+			assert.Equal(t, response.ShippingCost, response.Breakdown.Total)
+		})
+	}
+}
+
+func TestCalculateShipping_PackageType(t *testing.T) {
+	tests := []struct {
+		name         string
+		packageType  string
+		wantCost     float64
+		wantServices []string
+	}{
+		{"default is standard", "", 1250.0, []string{"standard", "express"}},
+		{"fragile", "fragile", 1437.5, []string{"standard", "express"}},
+		{"perishable", "perishable", 1500.0, []string{"standard", "express"}},
+		{"dangerous has no express", "dangerous", 1625.0, []string{"standard"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingService()
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "12345678",
+				DestinationZipcode: "12345678",
+				Weight:             1.0,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				PackageType:        tt.packageType,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, money.FromMinor(tt.wantCost), response.ShippingCost)
+			assert.Equal(t, tt.wantServices, response.AvailableServices)
+			assert.Len(t, response.ShippingOptions, len(tt.wantServices))
+		})
+	}
+}
+
+// fixedFreight is a pricing strategy that quotes the same freight for every shipment
+type fixedFreight pricing.Freight
+
+func (f fixedFreight) Price(context.Context, pricing.Shipment) (pricing.Freight, error) {
+	return pricing.Freight(f), nil
+}
+
+func TestCalculateShipping_StrategyPerServiceLevel(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	cfg.Strategies = map[string]string{pricing.LevelExpress: pricing.StrategyCarrier}
+	service := NewShippingServiceWithConfig(Config{
+		Pricing:    &cfg,
+		Strategies: map[string]pricing.Strategy{pricing.StrategyCarrier: fixedFreight{BaseCost: money.FromMinor(2000)}},
+	})
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "12345678",
+		DestinationZipcode: "12345678",
+		Weight:             1.0,
+		Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+		IsExpress:          true,
+	}
+
+	// Act
+	response, err := service.CalculateShipping(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	// Standard keeps the formula (1000 + 200 + 50); express uses the carrier freight plus 50%
+	assert.Equal(t, money.FromMinor(1250), response.ShippingOptions[0].Cost)
+	assert.Equal(t, money.FromMinor(3000), response.ShippingOptions[1].Cost)
+	assert.Equal(t, money.FromMinor(3000), response.ShippingCost)
+	assert.Equal(t, money.FromMinor(2000), response.Breakdown.BaseCost)
+	assert.Equal(t, money.FromMinor(0), response.Breakdown.WeightSurcharge)
+	assert.Equal(t, money.FromMinor(1000), response.Breakdown.ExpressSurcharge)
+}
+
+func TestCalculateShipping_PricingStrategyOverride(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	rates := cfg.Currencies["BRL"]
+	rates.WeightTable = []pricing.WeightBracket{{MaxWeightKg: 2, Cost: money.FromMinor(1500)}}
+	cfg.Currencies["BRL"] = rates
+	service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "12345678",
+		DestinationZipcode: "12345678",
+		Weight:             1.0,
+		Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+		PricingStrategy:    "table",
+	}
+
+	// Act
+	response, err := service.CalculateShipping(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	// Bracket cost 1500 plus 5% per 1000 cm³
+	assert.Equal(t, money.FromMinor(1575), response.ShippingOptions[0].Cost)
+	assert.Equal(t, money.FromMinor(2362.5), response.ShippingOptions[1].Cost)
+}
+
+func TestCalculateShipping_Degraded(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	cfg.Strategies = map[string]string{pricing.LevelExpress: pricing.StrategyCarrier}
+	service := NewShippingServiceWithConfig(Config{
+		Pricing:    &cfg,
+		Strategies: map[string]pricing.Strategy{pricing.StrategyCarrier: fixedFreight{BaseCost: money.FromMinor(2000)}},
+	})
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "12345678",
+		DestinationZipcode: "12345678",
+		Weight:             1.0,
+		Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+		PricingStrategy:    pricing.StrategyTable,
+	}
+	ctx, trace := WithDryRun(WithDegradedPricing(context.Background()))
+
+	// Act
+	response, err := service.CalculateShipping(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, response.Degraded)
+	// Both levels use the formula (1000 + 200 + 50) despite the override and the carrier strategy
+	assert.Equal(t, money.FromMinor(1250), response.ShippingOptions[0].Cost)
+	assert.Equal(t, money.FromMinor(1875), response.ShippingOptions[1].Cost)
+	assert.Contains(t, trace.Steps(), model.DecisionStep{Step: StepStrategy, Detail: "express priced with formula"})
+}
+
+func TestCalculateShipping_HistoricalPricing(t *testing.T) {
+	// Arrange
+	treatment := pricing.DefaultConfig()
+	service := NewShippingServiceWithConfig(Config{
+		Experiment: &experiment.Experiment{Name: "volume-curve", Fraction: 1, Treatment: treatment},
+	})
+	past := pricing.DefaultConfig()
+	past.Version = "2024.05"
+	brl := past.Currencies["BRL"]
+	brl.BaseCost = money.FromMinor(800)
+	past.Currencies["BRL"] = brl
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "12345678",
+		DestinationZipcode: "12345678",
+		Weight:             1.0,
+		Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+	}
+
+	// Act
+	current, currentErr := service.CalculateShipping(context.Background(), req)
+	historical, historicalErr := service.CalculateShipping(WithHistoricalPricing(context.Background(), past), req)
+
+	// Assert
+	assert.NoError(t, currentErr)
+	assert.NoError(t, historicalErr)
+	assert.NotNil(t, current.Experiment)
+	assert.Nil(t, historical.Experiment, "historical quotes are not assigned to experiments")
+	assert.Equal(t, "2024.05", historical.PricingVersion)
+	// The formula with the past base cost: 800 + 160 + 40
+	assert.Equal(t, money.FromMinor(1000), historical.ShippingCost)
+	assert.Equal(t, money.FromMinor(1250), current.ShippingCost)
+}
+
+func TestCalculateShipping_PricingStrategyErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		weight   float64
+		wantErr  error
+	}{
+		{"unknown strategy", "auction", 1.0, pricing.ErrUnsupportedStrategy},
+		{"carrier not registered", "carrier", 1.0, pricing.ErrUnsupportedStrategy},
+		{"weight above rate table", "table", 5.0, pricing.ErrWeightAboveTable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := pricing.DefaultConfig()
+			rates := cfg.Currencies["BRL"]
+			rates.WeightTable = []pricing.WeightBracket{{MaxWeightKg: 2, Cost: money.FromMinor(1500)}}
+			cfg.Currencies["BRL"] = rates
+			service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "12345678",
+				DestinationZipcode: "12345678",
+				Weight:             tt.weight,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				PricingStrategy:    tt.strategy,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, response)
+		})
+	}
+}
+
+func TestCalculateShipping_PriceLimits(t *testing.T) {
+	tests := []struct {
+		name           string
+		limits         []pricing.PriceLimit
+		isExpress      bool
+		wantCost       float64
+		wantLimit      string
+		wantAdjustment float64
+	}{
+		{"within limits", []pricing.PriceLimit{{MinCost: money.FromMinor(500), MaxCost: money.FromMinor(5000)}}, false, 1250.0, "", 0},
+		{"floor", []pricing.PriceLimit{{MinCost: money.FromMinor(1500)}}, false, 1500.0, "floor", 250.0},
+		{"ceiling", []pricing.PriceLimit{{MaxCost: money.FromMinor(1000)}}, false, 1000.0, "ceiling", -250.0},
+		{"express limit of the route zone", []pricing.PriceLimit{
+			{MaxCost: money.FromMinor(1000)},
+			{Service: "express", Zone: "local", MaxCost: money.FromMinor(1800)},
+		}, true, 1800.0, "ceiling", -75.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := pricing.DefaultConfig()
+			rates := cfg.Currencies["BRL"]
+			rates.PriceLimits = tt.limits
+			cfg.Currencies["BRL"] = rates
+			service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "12345678",
+				DestinationZipcode: "12345678",
+				Weight:             1.0,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				IsExpress:          tt.isExpress,
+				AdditionalServices: []string{"signature"},
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			// Additional service fees (300) are added after the limit
+			assert.Equal(t, money.FromMinor(tt.wantCost+300), response.ShippingCost)
+			assert.Equal(t, tt.wantLimit, response.Breakdown.PriceLimit)
+			assert.Equal(t, money.FromMinor(tt.wantAdjustment), response.Breakdown.PriceLimitAdjustment)
+			assert.Equal(t, response.ShippingCost, response.Breakdown.Total)
+		})
+	}
+}
+
+func TestCalculateShipping_RestrictedAreas(t *testing.T) {
+	tests := []struct {
+		name          string
+		area          pricing.RestrictedArea
+		isExpress     bool
+		wantCost      float64
+		wantSurcharge float64
+		wantServices  []string
+	}{
+		{"surcharged", pricing.RestrictedArea{Name: "remote", From: "69000000", To: "69999999", SurchargeRate: 0.2}, false, 1500.0, 250.0, []string{"standard", "express"}},
+		{"express surcharged without the express surcharge", pricing.RestrictedArea{Name: "remote", From: "69000000", To: "69999999", SurchargeRate: 0.2}, true, 2125.0, 250.0, []string{"standard", "express"}},
+		{"express not served", pricing.RestrictedArea{Name: "remote", From: "69000000", To: "69999999", Services: []string{"express"}}, false, 1250.0, 0, []string{"standard"}},
+		{"area of another service level", pricing.RestrictedArea{Name: "remote", From: "69000000", To: "69999999", Services: []string{"express"}, SurchargeRate: 0.2}, false, 1250.0, 0, []string{"standard", "express"}},
+		{"outside the area", pricing.RestrictedArea{Name: "remote", From: "69100000", To: "69999999"}, false, 1250.0, 0, []string{"standard", "express"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := pricing.DefaultConfig()
+			cfg.RestrictedAreas = []pricing.RestrictedArea{tt.area}
+			service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "69005040",
+				DestinationZipcode: "69005040",
+				Weight:             1.0,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				IsExpress:          tt.isExpress,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, money.FromMinor(tt.wantCost), response.ShippingCost)
+			assert.Equal(t, money.FromMinor(tt.wantSurcharge), response.Breakdown.RestrictedAreaSurcharge)
+			if tt.wantSurcharge != 0 {
+				assert.Equal(t, "remote", response.Breakdown.RestrictedArea)
+			}
+			assert.Equal(t, tt.wantServices, response.AvailableServices)
+			assert.Equal(t, response.ShippingCost, response.Breakdown.Total)
+		})
+	}
+}
+
+func TestCalculateShipping_FuelSurcharge(t *testing.T) {
+	fuel := &pricing.FuelSurcharge{Rate: 0.1, EffectiveDate: "2025-04-07"}
+	tests := []struct {
+		name          string
+		fuel          *pricing.FuelSurcharge
+		isExpress     bool
+		wantCost      float64
+		wantSurcharge float64
+		wantOptions   []float64
+		wantIndex     *model.FuelIndex
+	}{
+		{"without index", nil, false, 1250, 0, []float64{1250, 1875}, nil},
+		{"standard", fuel, false, 1375, 125, []float64{1375, 2062.5}, &model.FuelIndex{Rate: 0.1, EffectiveDate: "2025-04-07"}},
+		{"express, including the express surcharge", fuel, true, 2062.5, 187.5, []float64{1375, 2062.5}, &model.FuelIndex{Rate: 0.1, EffectiveDate: "2025-04-07"}},
+		{"zero rate", &pricing.FuelSurcharge{EffectiveDate: "2025-04-07"}, false, 1250, 0, []float64{1250, 1875}, &model.FuelIndex{EffectiveDate: "2025-04-07"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := pricing.DefaultConfig()
+			cfg.FuelSurcharge = tt.fuel
+			service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "12345678",
+				DestinationZipcode: "12345678",
+				Weight:             1.0,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				IsExpress:          tt.isExpress,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, money.FromMinor(tt.wantCost), response.ShippingCost)
+			assert.Equal(t, money.FromMinor(tt.wantSurcharge), response.Breakdown.FuelSurcharge)
+			assert.Equal(t, response.ShippingCost, response.Breakdown.Total)
+			for i, cost := range tt.wantOptions {
+				assert.Equal(t, money.FromMinor(cost), response.ShippingOptions[i].Cost)
+			}
+			assert.Equal(t, tt.wantIndex, response.FuelIndex)
+		})
+	}
+}
+
+func TestCalculateShipping_Tax(t *testing.T) {
+	tests := []struct {
+		name       string
+		calculator *tax.Calculator
+		country    string
+		isReturn   bool
+		wantTax    string
+		wantRate   float64
+		wantStates [2]string
+	}{
+		{"disabled", nil, "", false, "", 0, [2]string{}},
+		{"interstate", tax.NewCalculator(tax.DefaultConfig()), "", false, tax.ICMS, 0.07, [2]string{"SP", "BA"}},
+		{"return from the customer", tax.NewCalculator(tax.DefaultConfig()), "", true, tax.ICMS, 0.12, [2]string{"BA", "SP"}},
+		{"international", tax.NewCalculator(tax.DefaultConfig()), "US", false, "", 0, [2]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingServiceWithConfig(Config{Tax: tt.calculator})
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "01310100",
+				DestinationZipcode: "40010000",
+				DestinationCountry: tt.country,
+				Weight:             1.0,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				IsReturn:           tt.isReturn,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			if tt.wantTax == "" {
+				assert.Nil(t, response.Breakdown.Tax)
+				return
+			}
+			freightTax := response.Breakdown.Tax
+			if assert.NotNil(t, freightTax) {
+				assert.Equal(t, tt.wantTax, freightTax.Tax)
+				assert.Equal(t, tt.wantRate, freightTax.Rate)
+				assert.Equal(t, tt.wantStates, [2]string{freightTax.OriginState, freightTax.DestinationState})
+				assert.Equal(t, response.ShippingCost, freightTax.Gross)
+				assert.Equal(t, response.ShippingCost.MulRate(tt.wantRate), freightTax.Amount)
+				assert.Equal(t, freightTax.Gross-freightTax.Amount, freightTax.Net)
+			}
+		})
+	}
+}
+
+func TestCalculateShipping_RemoteAreas(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	cfg.RemoteAreas = []pricing.RemoteArea{
+		{Name: "interior-am", From: "69000000", To: "69999999", SurchargeRate: 0.20, ExtraDays: 1},
+		{Name: "ribeirinhas-am", From: "69000000", To: "69099999", Services: []string{"standard"}, SurchargeRate: 0.10, ExtraDays: 3},
+	}
+	service := NewShippingServiceWithConfig(Config{Pricing: &cfg, Clock: determinism.FixedClock{Time: time.Date(2025, 3, 7, 13, 0, 0, 0, time.UTC)}})
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "69005040",
+		DestinationZipcode: "69005040",
+		Weight:             1.0,
+		Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+	}
+
+	// Act
+	response, err := service.CalculateShipping(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMinor(1625), response.ShippingCost)
+	assert.Equal(t, []string{"interior-am", "ribeirinhas-am"}, response.Breakdown.RemoteAreas)
+	assert.Equal(t, money.FromMinor(375), response.Breakdown.RemoteAreaSurcharge)
+	assert.Zero(t, response.Breakdown.RestrictedAreaSurcharge)
+	assert.Equal(t, []model.ShippingOption{
+		{Service: "standard", Cost: money.FromMinor(1625), Time: "6 dias", EstimatedDays: 6, DeliveryDate: "2025-03-13", Cheapest: true},
+		{Service: "express", Cost: money.FromMinor(2125), Time: "2 dias", EstimatedDays: 2, DeliveryDate: "2025-03-09", Fastest: true},
+	}, response.ShippingOptions)
+}
+
+func TestCalculateShipping_RemoteAreaExtraDaysOnly(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	cfg.RemoteAreas = []pricing.RemoteArea{{Name: "ribeirinhas", From: "69000000", To: "69999999", Services: []string{"standard"}, ExtraDays: 3}}
+	service := NewShippingServiceWithConfig(Config{Pricing: &cfg, Clock: determinism.FixedClock{Time: time.Date(2025, 3, 7, 13, 0, 0, 0, time.UTC)}})
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "69005040",
+		DestinationZipcode: "69005040",
+		Weight:             1.0,
+		Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+	}
+
+	// Act
+	response, err := service.CalculateShipping(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMinor(1250), response.ShippingCost)
+	assert.Nil(t, response.Breakdown.RemoteAreas)
+	assert.Zero(t, response.Breakdown.RemoteAreaSurcharge)
+	assert.Equal(t, []model.ShippingOption{
+		{Service: "standard", Cost: money.FromMinor(1250), Time: "5 dias", EstimatedDays: 5, DeliveryDate: "2025-03-12", Cheapest: true},
+		{Service: "express", Cost: money.FromMinor(1875), Time: "1 dia", EstimatedDays: 1, DeliveryDate: "2025-03-08", Fastest: true},
+	}, response.ShippingOptions)
+	assert.Equal(t, "2025-03-12", response.DeliveryDate)
+}
+
+func TestCalculateShipping_CostOutOfRange(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "01310100",
+		DestinationZipcode: "04547130",
+		Weight:             1e300,
+		Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+	}
+
+	// Act
+	response, err := service.CalculateShipping(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrCostOutOfRange)
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "weight", validationErr.Field)
+}
+
+func TestCalculateShipping_NotServiceable(t *testing.T) {
+	tests := []struct {
+		name      string
+		services  []string
+		isExpress bool
+		wantField string
+	}{
+		{"destination not served", nil, false, "destination_zipcode"},
+		{"standard not served", []string{"standard"}, false, "destination_zipcode"},
+		{"express requested where it is not served", []string{"express"}, true, "is_express"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := pricing.DefaultConfig()
+			cfg.RestrictedAreas = []pricing.RestrictedArea{{Name: "noronha", From: "53990000", To: "53990999", Services: tt.services}}
+			service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "01310100",
+				DestinationZipcode: "53990-000",
+				Weight:             1.0,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				IsExpress:          tt.isExpress,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.Nil(t, response)
+			assert.ErrorIs(t, err, ErrNotServiceable)
+			assert.ErrorContains(t, err, "restricted area noronha")
+			category, field := ClassifyError(err)
+			assert.Equal(t, ErrorValidation, category)
+			assert.Equal(t, tt.wantField, field)
+		})
+	}
+}
+
+func TestCalculateShipping_PackageTypeErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		packageType string
+		isExpress   bool
+		wantErr     error
+	}{
+		{"unknown package type", "explosive", false, pricing.ErrUnsupportedPackageType},
+		{"dangerous goods cannot go express", "dangerous", true, ErrExpressNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingService()
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "12345678",
+				DestinationZipcode: "12345678",
+				Weight:             1.0,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				IsExpress:          tt.isExpress,
+				PackageType:        tt.packageType,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.Nil(t, response)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), "invalid package_type")
+		})
+	}
+}
+
+func TestCalculateShippingDetails_PackageTypeSurcharge(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	packageType := pricing.PackageType{SurchargeRate: 0.15}
+
+	// Act
+	details := service.calculateShippingDetails(pricing.DefaultRates(), packageType, pricing.DeliveryType{}, 0, pricing.FormulaFreight(pricing.DefaultRates(), money.FromMinor(1000), 1.0, 1000.0), true)
+
+	// Assert
+	assert.Equal(t, money.FromMinor(187.5), details.PackageTypeSurcharge)
+	assert.Equal(t, money.FromMinor(718.75), details.ExpressSurcharge)
+	assert.Equal(t, money.FromMinor(2156.25), details.TotalCost)
+}
+
+func TestCalculateShipping_AdditionalServices(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "12345678",
+		DestinationZipcode: "12345678",
+		Weight:             1.0,
+		Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+		IsExpress:          true,
+		AdditionalServices: []string{"cod", "Signature"},
+	}
+
+	// Act
+	response, err := service.CalculateShipping(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMinor(2675), response.ShippingCost)
+	assert.Equal(t, money.FromMinor(2050), response.ShippingOptions[0].Cost)
+	assert.Equal(t, money.FromMinor(2675), response.ShippingOptions[1].Cost)
+	assert.Equal(t, &model.CostBreakdown{
+		BaseCost:         money.FromMinor(1000),
+		WeightSurcharge:  money.FromMinor(200),
+		VolumeSurcharge:  money.FromMinor(50),
+		ExpressSurcharge: money.FromMinor(625),
+		AdditionalServices: []model.ServiceFee{
+			{Service: "cod", Fee: money.FromMinor(500)},
+			{Service: "signature", Fee: money.FromMinor(300)},
+		},
+		UnroundedTotal: money.FromMinor(2675),
+		Total:          money.FromMinor(2675),
+	}, response.Breakdown)
+}
+
+func TestCalculateShipping_AdditionalServicesErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []string
+		wantErr  string
+	}{
+		{"unknown service", []string{"insurance"}, "unsupported additional service"},
+		{"duplicate service", []string{"cod", "COD"}, "duplicate additional service"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingService()
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "12345678",
+				DestinationZipcode: "12345678",
+				Weight:             1.0,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				AdditionalServices: tt.services,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.Nil(t, response)
+			assert.ErrorContains(t, err, "invalid additional_services")
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBuildResponse_Breakdown_Standard(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	details := &model.ShippingCalculationDetails{
+		BaseCost:             money.FromMinor(1000),
+		WeightSurcharge:      money.FromMinor(200),
+		PackageTypeSurcharge: money.FromMinor(180),
+		EstimatedDays:        2,
+		StandardDays:         2,
+		ExpressDays:          1,
+	}
+
+	// Act
+	standard, express := serviceLevels(pricing.DefaultRates(), details)
+	response := service.buildResponse(pricing.DefaultRates(), standard, express, false)
+
+	// Assert
+	assert.Equal(t, money.FromMinor(0), response.Breakdown.ExpressSurcharge)
+	assert.Equal(t, money.FromMinor(180), response.Breakdown.PackageTypeSurcharge)
+	assert.Equal(t, response.ShippingCost, response.Breakdown.Total)
+	assert.Empty(t, response.Breakdown.AdditionalServices)
+}
+
+func TestCalculateShipping_DeliveryType(t *testing.T) {
+	tests := []struct {
+		name           string
+		deliveryType   string
+		isExpress      bool
+		wantCost       float64
+		wantAdjustment float64
+	}{
+		{"default is home", "", false, 1250.0, 0},
+		{"pickup point", "pickup_point", false, 1125.0, -125.0},
+		{"locker is 20% cheaper", "locker", false, 1000.0, -250.0},
+		{"locker express", "locker", true, 1500.0, -250.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingService()
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "12345678",
+				DestinationZipcode: "12345678",
+				Weight:             1.0,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				IsExpress:          tt.isExpress,
+				DeliveryType:       tt.deliveryType,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, money.FromMinor(tt.wantCost), response.ShippingCost)
+			assert.Equal(t, money.FromMinor(tt.wantAdjustment), response.Breakdown.DeliveryTypeAdjustment)
+		})
+	}
+}
+
+func TestCalculateShipping_UnsupportedDeliveryType(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "12345678",
+		DestinationZipcode: "12345678",
+		Weight:             1.0,
+		Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+		DeliveryType:       "drone",
+	}
+
+	// Act
+	response, err := service.CalculateShipping(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, pricing.ErrUnsupportedDeliveryType)
+	assert.Contains(t, err.Error(), "invalid delivery_type")
+}
+
+func TestServiceability(t *testing.T) {
+	// Arrange
+	service := NewShippingServiceWithConfig(Config{Estimator: eta.NewEstimator(eta.Config{DefaultHandlingDays: 1})})
+	req := &model.ServiceabilityRequest{OriginZipcode: "01310-100", DestinationZipcode: "04547-130"}
+
+	// Act
+	response, err := service.Serviceability(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, response.Serviceable)
+	assert.Equal(t, []model.ServiceAvailability{
+		{Service: "standard", Available: true, EstimatedDays: 3, EstimatedDeliveryTime: "3 dias"},
+		{Service: "express", Available: true, EstimatedDays: 2, EstimatedDeliveryTime: "2 dias"},
+	}, response.Services)
+}
+
+func TestServiceability_RegionalTransitDays(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	rates := cfg.Currencies["BRL"]
+	rates.Regions = []pricing.RegionalRate{{Origin: "SP", Destination: "north", StandardDays: 9, ExpressDays: 4}}
+	cfg.Currencies["BRL"] = rates
+	service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+	req := &model.ServiceabilityRequest{OriginZipcode: "01310-100", DestinationZipcode: "69005-040"}
+
+	// Act
+	response, err := service.Serviceability(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 9, response.Services[0].EstimatedDays)
+	assert.Equal(t, 4, response.Services[1].EstimatedDays)
+}
+
+func TestCalculateShipping_TransitMatrix(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "transit.csv")
+	content := "origin_state,destination_state,service,days\nSP,AM,standard,8\nSP,AM,express,3\nSP,RJ,standard,4\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	matrix, err := eta.LoadTransitMatrix(path)
+	assert.NoError(t, err)
+	cfg := pricing.DefaultConfig()
+	rates := cfg.Currencies["BRL"]
+	rates.Regions = []pricing.RegionalRate{{Origin: "southeast", Destination: "southeast", StandardDays: 3}}
+	cfg.Currencies["BRL"] = rates
+	service := NewShippingServiceWithConfig(Config{Estimator: eta.NewEstimator(eta.Config{}).WithTransitMatrix(matrix), Pricing: &cfg})
+	newRequest := func(destination string) *model.CalculateShippingRequest {
+		return &model.CalculateShippingRequest{
+			OriginZipcode:      "01310-100",
+			DestinationZipcode: destination,
+			Weight:             0.5,
+			Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+		}
+	}
+
+	// Act
+	toAM, errAM := service.CalculateShipping(context.Background(), newRequest("69005-040"))
+	toRJ, errRJ := service.CalculateShipping(context.Background(), newRequest("20040-020"))
+	toPR, errPR := service.CalculateShipping(context.Background(), newRequest("80010-000"))
+
+	// Assert
+	assert.NoError(t, errAM)
+	assert.NoError(t, errRJ)
+	assert.NoError(t, errPR)
+	assert.Equal(t, 8, toAM.ShippingOptions[0].EstimatedDays)
+	assert.Equal(t, 3, toAM.ShippingOptions[1].EstimatedDays)
+	assert.Equal(t, 3, toRJ.ShippingOptions[0].EstimatedDays, "the regional rate wins over the matrix")
+	assert.Equal(t, 1, toRJ.ShippingOptions[1].EstimatedDays)
+	assert.Equal(t, 2, toPR.ShippingOptions[0].EstimatedDays)
+}
+
+func TestCalculateShipping_RegionalPricing(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	rates := cfg.Currencies["BRL"]
+	rates.Regions = []pricing.RegionalRate{
+		{Origin: "southeast", Destination: "southeast", BaseCost: money.FromMinor(1500), StandardDays: 3},
+		{Origin: "southeast", Destination: "north", BaseCost: money.FromMinor(4500), StandardDays: 10},
+	}
+	cfg.Currencies["BRL"] = rates
+	service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+	newRequest := func(destination string) *model.CalculateShippingRequest {
+		return &model.CalculateShippingRequest{
+			OriginZipcode:      "01310-100",
+			DestinationZipcode: destination,
+			Weight:             0.5,
+			Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+		}
+	}
+
+	// Act
+	toRJ, errRJ := service.CalculateShipping(context.Background(), newRequest("20040-020"))
+	toAM, errAM := service.CalculateShipping(context.Background(), newRequest("69005-040"))
+
+	// Assert
+	assert.NoError(t, errRJ)
+	assert.NoError(t, errAM)
+	assert.Equal(t, money.FromMinor(1500), toRJ.Breakdown.BaseCost)
+	assert.Equal(t, money.FromMinor(4500), toAM.Breakdown.BaseCost)
+	assert.Equal(t, "3 dias", toRJ.EstimatedDeliveryTime)
+	assert.Equal(t, "10 dias", toAM.EstimatedDeliveryTime)
+}
+
+func TestCalculateShipping_Return(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	rates := cfg.Currencies["BRL"]
+	rates.Regions = []pricing.RegionalRate{
+		{Origin: "southeast", Destination: "north", BaseCost: money.FromMinor(4500), StandardDays: 10},
+		{Origin: "north", Destination: "southeast", BaseCost: money.FromMinor(3000), StandardDays: 8},
+	}
+	cfg.Currencies["BRL"] = rates
+	cfg.Returns = &pricing.ReturnPolicy{CostAdjustmentRate: -0.20, Services: []string{pricing.LevelStandard}}
+	estimator := eta.NewEstimator(eta.Config{
+		Warehouses: []eta.Warehouse{{ID: "sp", ZipcodePrefixes: []string{"01"}, HandlingDays: 2}},
+	})
+	service := NewShippingServiceWithConfig(Config{Estimator: estimator, Pricing: &cfg})
+	newRequest := func(isReturn bool) *model.CalculateShippingRequest {
+		return &model.CalculateShippingRequest{
+			OriginZipcode:      "01310-100",
+			DestinationZipcode: "69005-040",
+			Weight:             0.5,
+			Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+			IsReturn:           isReturn,
+		}
+	}
+
+	// Act
+	outbound, outboundErr := service.CalculateShipping(context.Background(), newRequest(false))
+	inbound, inboundErr := service.CalculateShipping(context.Background(), newRequest(true))
+
+	// Assert
+	assert.NoError(t, outboundErr)
+	assert.NoError(t, inboundErr)
+
+	assert.Equal(t, money.FromMinor(4500), outbound.Breakdown.BaseCost)
+	assert.Zero(t, outbound.Breakdown.ReturnAdjustment)
+	assert.Equal(t, "12 dias", outbound.EstimatedDeliveryTime)
+	assert.Equal(t, []string{model.ServiceStandard, model.ServiceExpress}, outbound.AvailableServices)
+
+	freight := inbound.Breakdown.BaseCost + inbound.Breakdown.WeightSurcharge + inbound.Breakdown.VolumeSurcharge
+	assert.Equal(t, money.FromMinor(3000), inbound.Breakdown.BaseCost)
+	assert.Equal(t, freight.MulRate(-0.20), inbound.Breakdown.ReturnAdjustment)
+	assert.Equal(t, freight+inbound.Breakdown.ReturnAdjustment, inbound.ShippingCost)
+	assert.Equal(t, "8 dias", inbound.EstimatedDeliveryTime)
+	assert.Equal(t, []string{model.ServiceStandard}, inbound.AvailableServices)
+}
+
+func TestCalculateShipping_ReturnExpress(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []string
+		wantErr  error
+	}{
+		{"not offered", []string{pricing.LevelStandard}, ErrReturnServiceNotOffered},
+		{"offered", []string{pricing.LevelStandard, pricing.LevelExpress}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := pricing.DefaultConfig()
+			cfg.Returns = &pricing.ReturnPolicy{Services: tt.services}
+			service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "01310100",
+				DestinationZipcode: "04547130",
+				Weight:             1,
+				Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+				IsExpress:          true,
+				IsReturn:           true,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, response)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []string{model.ServiceStandard, model.ServiceExpress}, response.AvailableServices)
+			assert.Equal(t, response.ShippingOptions[1].Cost, response.ShippingCost)
+		})
+	}
+}
+
+func TestCalculateShipping_ScheduledPickup(t *testing.T) {
+	// Arrange
+	cfg := schedule.DefaultConfig()
+	cfg.Windows = []schedule.Window{{
+		Name: "morning", Start: "08:00", End: "12:00", SurchargeRate: 0.10,
+		Weekdays: []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"},
+	}}
+	scheduler := schedule.NewScheduler(cfg)
+	service := NewShippingServiceWithConfig(Config{
+		Estimator: eta.NewEstimator(eta.Config{DefaultHandlingDays: 1}),
+		Scheduler: scheduler,
+	})
+	pickupDate := time.Now().In(time.FixedZone("UTC-3", -3*60*60)).AddDate(0, 0, 2).Format(schedule.DateLayout)
+	newRequest := func(date, window string) *model.CalculateShippingRequest {
+		return &model.CalculateShippingRequest{
+			OriginZipcode:      "01310100",
+			DestinationZipcode: "04547130",
+			Weight:             1,
+			Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+			PickupDate:         date,
+			PickupWindow:       window,
+		}
+	}
+
+	// Act
+	dropOff, dropOffErr := service.CalculateShipping(context.Background(), newRequest("", ""))
+	pickup, pickupErr := service.CalculateShipping(context.Background(), newRequest(pickupDate, "Morning"))
+
+	// Assert
+	assert.NoError(t, dropOffErr)
+	assert.NoError(t, pickupErr)
+	assert.Zero(t, dropOff.Breakdown.PickupSurcharge)
+	assert.Equal(t, "3 dias", dropOff.EstimatedDeliveryTime)
+
+	assert.Equal(t, dropOff.ShippingCost.MulRate(0.10), pickup.Breakdown.PickupSurcharge)
+	assert.Equal(t, dropOff.ShippingCost+pickup.Breakdown.PickupSurcharge, pickup.ShippingCost)
+	assert.Equal(t, "4 dias", pickup.EstimatedDeliveryTime)
+}
+
+func TestCalculateShipping_InvalidPickup(t *testing.T) {
+	tests := []struct {
+		name    string
+		date    string
+		window  string
+		wantErr string
+	}{
+		{"window without date", "", "morning", "pickup_date and pickup_window must be set together"},
+		{"invalid date", "tomorrow", "morning", "pickup_date must be YYYY-MM-DD"},
+		{"past date", "2020-01-06", "morning", "is in the past"},
+		{"unknown window", time.Now().AddDate(0, 0, 3).Format(schedule.DateLayout), "night", `unknown pickup_window "night"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingService()
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "01310100",
+				DestinationZipcode: "04547130",
+				Weight:             1,
+				Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+				PickupDate:         tt.date,
+				PickupWindow:       tt.window,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.Nil(t, response)
+			assert.ErrorIs(t, err, schedule.ErrInvalidPickup)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCalculateShipping_Duties(t *testing.T) {
+	// Arrange
+	service := NewShippingServiceWithConfig(Config{Customs: customs.NewEstimator(customs.Config{
+		Countries: map[string]customs.Tariff{
+			"DE": {Currency: "EUR", DeMinimis: money.FromMinor(15000), DutyRate: 0.04, TaxRate: 0.19},
+		},
+	})})
+	req := &model.CalculateShippingRequest{
+		OriginZipcode:      "01310100",
+		DestinationZipcode: "10115000",
+		Weight:             1,
+		Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+		DestinationCountry: "DE",
+		HSCode:             "9503.00",
+		DeclaredValue:      money.FromMinor(20000),
+	}
+
+	// Act
+	response, err := service.CalculateShipping(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	customsValue := req.DeclaredValue + response.ShippingCost
+	duty := customsValue.MulRate(0.04)
+	tax := (customsValue + duty).MulRate(0.19)
+	assert.Equal(t, []model.DutyCharge{
+		{Name: model.DutyImportDuty, Rate: 0.04, Amount: duty},
+		{Name: model.DutyImportTax, Rate: 0.19, Amount: tax},
+	}, response.Breakdown.Duties)
+	assert.Equal(t, response.Breakdown.Total, response.ShippingCost)
+	assert.Equal(t, customsValue+duty+tax, response.Breakdown.LandedCost)
+}
+
+func TestCalculateShipping_DutiesNotEstimated(t *testing.T) {
+	tests := []struct {
+		name     string
+		country  string
+		isReturn bool
+	}{
+		{"domestic", "", false},
+		{"return", "DE", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingService()
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "01310100",
+				DestinationZipcode: "04547130",
+				Weight:             1,
+				Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+				DestinationCountry: tt.country,
+				IsReturn:           tt.isReturn,
+				HSCode:             "950300",
+				DeclaredValue:      money.FromMinor(20000),
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Nil(t, response.Breakdown.Duties)
+			assert.Zero(t, response.Breakdown.LandedCost)
+		})
+	}
+}
+
+func TestCalculateShipping_InvalidDeclaration(t *testing.T) {
+	tests := []struct {
+		name     string
+		country  string
+		currency string
+		hsCode   string
+		declared float64
+		wantErr  error
+	}{
+		{"missing declared value", "DE", "", "950300", 0, customs.ErrInvalidDeclaration},
+		{"missing hs code", "DE", "", "", 20000, customs.ErrInvalidDeclaration},
+		{"no tariff for the currency", "DE", "USD", "950300", 20000, customs.ErrNoTariff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingService()
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "01310100",
+				DestinationZipcode: "10115000",
+				Weight:             1,
+				Dimensions:         model.PackageDimensions{Length: 10, Width: 10, Height: 10},
+				DestinationCountry: tt.country,
+				Currency:           tt.currency,
+				HSCode:             tt.hsCode,
+				DeclaredValue:      money.FromMinor(tt.declared),
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.Nil(t, response)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCalculateShipping_Optimize(t *testing.T) {
+	tests := []struct {
+		name        string
+		optimize    string
+		wantService string
+		wantOrder   []string
+	}{
+		// Standard and express are both clamped to the price ceiling, so the faster one ranks first
+		{"cheapest", model.OptimizeCheapest, model.ServiceFreight, []string{model.ServiceFreight, model.ServiceExpress, model.ServiceStandard}},
+		{"fastest", model.OptimizeFastest, model.ServiceExpress, []string{model.ServiceExpress, model.ServiceStandard, model.ServiceFreight}},
+		{"not requested", "", model.ServiceStandard, []string{model.ServiceStandard, model.ServiceExpress, model.ServiceFreight}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := freightConfig()
+			rates := cfg.Currencies["BRL"]
+			rates.PriceLimits = []pricing.PriceLimit{{MinCost: money.FromMinor(500), MaxCost: money.FromMinor(500000)}}
+			cfg.Currencies["BRL"] = rates
+			service := NewShippingServiceWithConfig(Config{
+				Estimator: eta.NewEstimator(eta.Config{DefaultHandlingDays: 1}),
+				Pricing:   &cfg,
+			})
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "01310100",
+				DestinationZipcode: "04547130",
+				Weight:             200,
+				Dimensions:         model.PackageDimensions{Length: 20, Width: 20, Height: 20},
+				FreightClass:       "70",
+				Optimize:           tt.optimize,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			order := make([]string, len(response.ShippingOptions))
+			for i, option := range response.ShippingOptions {
+				order[i] = option.Service
+			}
+			assert.Equal(t, tt.wantOrder, order)
+			assert.Equal(t, tt.wantService, response.SelectedService)
+			selected, _ := quotedOption(response.ShippingOptions, tt.wantService)
+			assert.Equal(t, selected.Cost, response.ShippingCost)
+			assert.Equal(t, selected.Time, response.EstimatedDeliveryTime)
+			assert.Equal(t, response.ShippingCost, response.Breakdown.Total)
+			assert.Equal(t, tt.wantService == model.ServiceExpress, response.Breakdown.ExpressSurcharge > 0)
+		})
+	}
+}
+
+func TestCalculateShipping_OptimizeErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		optimize  string
+		isExpress bool
+		wantErr   error
+	}{
+		{"unknown objective", "greenest", false, ErrUnsupportedOptimization},
+		{"with is_express", model.OptimizeFastest, true, ErrOptimizeWithExpress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingService()
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "01310100",
+				DestinationZipcode: "04547130",
+				Weight:             1.0,
+				Dimensions:         model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				IsExpress:          tt.isExpress,
+				Optimize:           tt.optimize,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.Nil(t, response)
+			assert.ErrorIs(t, err, tt.wantErr)
+			var validationErr *ValidationError
+			if assert.ErrorAs(t, err, &validationErr) {
+				assert.Equal(t, "optimize", validationErr.Field)
+			}
+		})
+	}
+}
+
+// freightConfig returns the default pricing with freight rates in BRL
+func freightConfig() pricing.Config {
+	cfg := pricing.DefaultConfig()
+	rates := cfg.Currencies["BRL"]
+	rates.Freight = &pricing.FreightRates{
+		MinWeightKg:   50,
+		MaxWeightKg:   1000,
+		MaxVolumeCm3:  2000000,
+		Classes:       map[string]money.Amount{"70": money.FromMinor(150), "125": money.FromMinor(300)},
+		MinimumCharge: money.FromMinor(15000),
+		TransitDays:   6,
+	}
+	cfg.Currencies["BRL"] = rates
+	return cfg
+}
+
+func TestCalculateShipping_Freight(t *testing.T) {
+	tests := []struct {
+		name         string
+		dimensions   model.PackageDimensions
+		wantServices []string
+		wantCost     float64
+		wantFastest  bool
+	}{
+		{"additional option", model.PackageDimensions{Length: 20, Width: 20, Height: 20}, []string{model.ServiceStandard, model.ServiceExpress, model.ServiceFreight}, 0, false},
+		{"oversized package", model.PackageDimensions{Length: 120, Width: 100, Height: 100}, []string{model.ServiceFreight}, 30000, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := freightConfig()
+			service := NewShippingServiceWithConfig(Config{
+				Estimator: eta.NewEstimatorWithClock(eta.Config{DefaultHandlingDays: 1}, nil, determinism.FixedClock{Time: time.Date(2025, 3, 7, 13, 0, 0, 0, time.UTC)}),
+				Pricing:   &cfg,
+			})
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "01310100",
+				DestinationZipcode: "04547130",
+				Weight:             200,
+				Dimensions:         tt.dimensions,
+				FreightClass:       "70",
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantServices, response.AvailableServices)
+			freight := response.ShippingOptions[len(response.ShippingOptions)-1]
+			assert.Equal(t, model.ShippingOption{Service: model.ServiceFreight, Cost: money.FromMinor(30000), Time: "7 dias", EstimatedDays: 7, DeliveryDate: "2025-03-14", Cheapest: true, Fastest: tt.wantFastest}, freight)
+			if tt.wantCost != 0 {
+				assert.Equal(t, money.FromMinor(tt.wantCost), response.ShippingCost)
+				assert.Equal(t, money.FromMinor(tt.wantCost), response.Breakdown.BaseCost)
+				assert.Equal(t, "7 dias", response.EstimatedDeliveryTime)
+			} else {
+				assert.NotEqual(t, freight.Cost, response.ShippingCost)
+			}
+		})
+	}
+}
+
+func TestCalculateShipping_FreightNotOffered(t *testing.T) {
+	tests := []struct {
+		name     string
+		weight   float64
+		class    string
+		wantLen  int
+		currency string
+	}{
+		{"light package without class", 10, "", 2, ""},
+		{"heavy package without class or default class", 200, "", 2, ""},
+		{"currency without freight rates", 200, "", 2, "USD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := freightConfig()
+			service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "01310100",
+				DestinationZipcode: "04547130",
+				Weight:             tt.weight,
+				Dimensions:         model.PackageDimensions{Length: 20, Width: 20, Height: 20},
+				FreightClass:       tt.class,
+				Currency:           tt.currency,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, response.ShippingOptions, tt.wantLen)
+			assert.NotContains(t, response.AvailableServices, model.ServiceFreight)
+		})
+	}
+}
+
+func TestCalculateShipping_InvalidFreight(t *testing.T) {
+	tests := []struct {
+		name       string
+		weight     float64
+		dimensions model.PackageDimensions
+		class      string
+		currency   string
+		isExpress  bool
+		isReturn   bool
+		wantErr    error
+		wantMsg    string
+	}{
+		{"unknown class", 200, model.PackageDimensions{Length: 20, Width: 20, Height: 20}, "85", "", false, false,
+			pricing.ErrUnsupportedFreightClass, `invalid freight_class: unsupported freight class "85"`},
+		{"below the minimum weight", 10, model.PackageDimensions{Length: 20, Width: 20, Height: 20}, "70", "", false, false,
+			pricing.ErrFreightNotOffered, "weight must be between 50 and 1000 kg"},
+		{"currency without freight rates", 200, model.PackageDimensions{Length: 20, Width: 20, Height: 20}, "70", "USD", false, false,
+			pricing.ErrFreightNotOffered, "freight is not offered in USD"},
+		{"return", 200, model.PackageDimensions{Length: 20, Width: 20, Height: 20}, "70", "", false, true,
+			pricing.ErrFreightNotOffered, "freight is not offered for returns"},
+		{"oversized without freight", 10, model.PackageDimensions{Length: 120, Width: 100, Height: 100}, "", "", false, false,
+			nil, "invalid dimensions: package volume (1200000.00 cm³) exceeds maximum allowed volume (15000.00 cm³)"},
+		{"over the freight volume limit", 200, model.PackageDimensions{Length: 200, Width: 120, Height: 100}, "70", "", false, false,
+			nil, "invalid dimensions: package volume (2400000.00 cm³) exceeds maximum allowed volume (2000000.00 cm³)"},
+		{"oversized express", 200, model.PackageDimensions{Length: 120, Width: 100, Height: 100}, "70", "", true, false,
+			ErrFreightOnly, "invalid is_express"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := freightConfig()
+			service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:      "01310100",
+				DestinationZipcode: "04547130",
+				Weight:             tt.weight,
+				Dimensions:         tt.dimensions,
+				FreightClass:       tt.class,
+				Currency:           tt.currency,
+				IsExpress:          tt.isExpress,
+				IsReturn:           tt.isReturn,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.Nil(t, response)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.ErrorContains(t, err, tt.wantMsg)
+		})
+	}
+}
+
+func TestServiceability_ExpressRestricted(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	req := &model.ServiceabilityRequest{OriginZipcode: "01310100", DestinationZipcode: "04547130", PackageType: "dangerous"}
+
+	// Act
+	response, err := service.Serviceability(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, response.Services[1].Available)
+	assert.Equal(t, ErrExpressNotAllowed.Error(), response.Services[1].Restriction)
+}
+
+func TestServiceability_RestrictedAreas(t *testing.T) {
+	tests := []struct {
+		name            string
+		area            pricing.RestrictedArea
+		wantServiceable bool
+		wantAvailable   []bool
+	}{
+		{"destination not served", pricing.RestrictedArea{Name: "noronha", From: "53990000", To: "53990999"}, false, []bool{false, false}},
+		{"express not served", pricing.RestrictedArea{Name: "noronha", From: "53990000", To: "53990999", Services: []string{"express"}}, true, []bool{true, false}},
+		{"surcharged", pricing.RestrictedArea{Name: "noronha", From: "53990000", To: "53990999", SurchargeRate: 0.5}, true, []bool{true, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := pricing.DefaultConfig()
+			cfg.RestrictedAreas = []pricing.RestrictedArea{tt.area}
+			service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+			req := &model.ServiceabilityRequest{OriginZipcode: "01310100", DestinationZipcode: "53990-000"}
+
+			// Act
+			response, err := service.Serviceability(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantServiceable, response.Serviceable)
+			assert.Equal(t, tt.wantAvailable, []bool{response.Services[0].Available, response.Services[1].Available})
+		})
+	}
+}
+
+func TestServiceability_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	req := &model.ServiceabilityRequest{OriginZipcode: "123", DestinationZipcode: "04547130"}
+
+	// Act
+	response, err := service.Serviceability(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, response)
+	assert.ErrorContains(t, err, "invalid origin_zipcode")
+}
+
+// serviceLevels splits test details into the standard and express details buildResponse expects,
+// pricing express with the same freight as standard
+func serviceLevels(rates pricing.Rates, details *model.ShippingCalculationDetails) (*model.ShippingCalculationDetails, *model.ShippingCalculationDetails) {
+	standard := *details
+	standard.ExpressSurcharge = 0
+	express := standard
+	express.ExpressSurcharge = subtotalOf(&standard).MulRate(rates.ExpressSurchargeRate)
+	return &standard, &express
+}
+
+func TestCalculateShipping_Units(t *testing.T) {
+	tests := []struct {
+		name           string
+		weight         float64
+		weightUnit     string
+		length         float64
+		dimensionUnit  string
+		wantWeightKg   float64
+		wantDimensions float64
+	}{
+		{"default units", 1, "", 10, "", 1, 10},
+		{"grams and meters", 1000, "g", 0.1, "m", 1, 10},
+		{"pounds and inches", 5, "lb", 4, "in", 2.26796185, 10.16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingService()
+			req := shadowRequest()
+			req.Weight, req.WeightUnit = tt.weight, tt.weightUnit
+			req.Dimensions = model.PackageDimensions{Length: tt.length, Width: tt.length, Height: tt.length}
+			req.DimensionUnit = tt.dimensionUnit
+
+			canonical := shadowRequest()
+			canonical.Weight = tt.wantWeightKg
+			canonical.Dimensions = model.PackageDimensions{Length: tt.wantDimensions, Width: tt.wantDimensions, Height: tt.wantDimensions}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+			want, wantErr := service.CalculateShipping(context.Background(), canonical)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.NoError(t, wantErr)
+			assert.Equal(t, want.ShippingCost, response.ShippingCost)
+			if assert.NotNil(t, response.Package) {
+				assert.InDelta(t, tt.wantWeightKg, response.Package.WeightKg, 1e-6)
+				assert.InDelta(t, tt.wantDimensions, response.Package.DimensionsCm.Length, 1e-6)
+				assert.InDelta(t, tt.wantDimensions, response.Package.DimensionsCm.Height, 1e-6)
+			}
+			assert.Equal(t, tt.weight, req.Weight, "the request is not modified")
+		})
+	}
+}
+
+func TestCalculateShipping_InvalidUnits(t *testing.T) {
+	tests := []struct {
+		name          string
+		weightUnit    string
+		dimensionUnit string
+		wantError     string
+	}{
+		{"weight unit", "oz", "", "invalid weight_unit"},
+		{"dimension unit", "kg", "ft", "invalid dimension_unit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingService()
+			req := shadowRequest()
+			req.WeightUnit, req.DimensionUnit = tt.weightUnit, tt.dimensionUnit
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantError)
+			assert.Nil(t, response)
+		})
+	}
+}
+
+func TestSetPricing(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	cfg := pricing.DefaultConfig()
+	cfg.Version = "2025.04"
+	rates := cfg.Currencies["BRL"]
+	rates.BaseCost = money.FromMinor(2000)
+	cfg.Currencies["BRL"] = rates
+
+	// Act
+	err := service.SetPricing(cfg)
+	response, calculateErr := service.CalculateShipping(context.Background(), shadowRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, calculateErr)
+	assert.Equal(t, "2025.04", response.PricingVersion)
+	assert.Equal(t, money.FromMinor(2000), response.Breakdown.BaseCost)
+	assert.Equal(t, "2025.04", service.Capabilities(context.Background()).PricingVersion)
+}
+
+func TestSetPricing_UnregisteredStrategy(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	before := service.Pricing().VersionID()
+	cfg := pricing.DefaultConfig()
+	cfg.Strategies = map[string]string{pricing.LevelExpress: pricing.StrategyCarrier}
+
+	// Act
+	err := service.SetPricing(cfg)
+
+	// Assert
+	assert.ErrorIs(t, err, pricing.ErrUnsupportedStrategy)
+	assert.Equal(t, before, service.Pricing().VersionID())
+}
+
+func TestSetPricing_InvalidConfig(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	before := service.Pricing().VersionID()
+	cfg := pricing.DefaultConfig()
+	cfg.DefaultCountry = "AR"
+
+	// Act
+	err := service.SetPricing(cfg)
+
+	// Assert
+	assert.ErrorContains(t, err, `default_country "AR" is not configured`)
+	assert.Equal(t, before, service.Pricing().VersionID())
+}
+
+func TestSetPricing_ConcurrentQuotes(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	baseCosts := map[string]money.Amount{}
+	configs := make([]pricing.Config, 2)
+	for i := range configs {
+		configs[i] = pricing.DefaultConfig()
+		configs[i].Version = fmt.Sprintf("2025.0%d", i+1)
+		rates := configs[i].Currencies["BRL"]
+		rates.BaseCost = money.FromMinor(float64(1000 * (i + 1)))
+		configs[i].Currencies["BRL"] = rates
+		baseCosts[configs[i].Version] = rates.BaseCost
+	}
+	assert.NoError(t, service.SetPricing(configs[0]))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 200 {
+			_ = service.SetPricing(configs[i%2])
+		}
+	}()
+
+	// Act & Assert
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		response, err := service.CalculateShipping(context.Background(), shadowRequest())
+		if assert.NoError(t, err) {
+			assert.Equal(t, baseCosts[response.PricingVersion], response.Breakdown.BaseCost, "each quote is priced with a single snapshot")
+		}
+	}
+}
+
+func TestWithPinnedTable(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	before := service.Pricing().VersionID()
+	pinned, err := service.withPinnedTable(context.Background())
+	assert.NoError(t, err)
+	cfg := pricing.DefaultConfig()
+	cfg.Version = "2025.04"
+	assert.NoError(t, service.SetPricing(cfg))
+
+	// Act
+	pinnedResponse, pinnedErr := service.CalculateShipping(pinned, shadowRequest())
+	response, responseErr := service.CalculateShipping(context.Background(), shadowRequest())
+
+	// Assert
+	assert.NoError(t, pinnedErr)
+	assert.NoError(t, responseErr)
+	assert.Equal(t, before, pinnedResponse.PricingVersion)
+	assert.Equal(t, "2025.04", response.PricingVersion)
+}
+
+func TestCalculateShipping_Tenant(t *testing.T) {
+	acme := pricing.DefaultConfig()
+	acme.Version = "acme-2025.04"
+	rates := acme.Currencies["BRL"]
+	rates.BaseCost = money.FromMinor(2000)
+	acme.Currencies["BRL"] = rates
+
+	treatment := pricing.DefaultConfig()
+	treatment.Version = "volume-curve-b"
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		wantBaseCost   float64
+		wantVersion    string
+		wantExperiment bool
+		wantCapability string
+	}{
+		{"default tenant", tenant.Default, 1000, "volume-curve-b", true, pricing.DefaultConfig().VersionID()},
+		{"tenant with its own rates", "acme", 2000, "acme-2025.04", false, "acme-2025.04"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewShippingServiceWithConfig(Config{
+				Experiment: &experiment.Experiment{Name: "volume-curve", Fraction: 1, Treatment: treatment},
+				Tenants:    map[string]pricing.Config{"acme": acme},
+			})
+			ctx := experiment.WithSubject(context.Background(), "acme-store")
+			ctx = tenant.NewContext(ctx, tt.tenantID)
+
+			// Act
+			response, err := service.CalculateShipping(ctx, shadowRequest())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, money.FromMinor(tt.wantBaseCost), response.Breakdown.BaseCost)
+			assert.Equal(t, tt.wantVersion, response.PricingVersion)
+			assert.Equal(t, tt.wantExperiment, response.Experiment != nil)
+			assert.Equal(t, tt.wantCapability, service.Capabilities(ctx).PricingVersion)
+		})
+	}
+}
+
+func TestCalculateShipping_UnknownTenant(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	ctx := tenant.NewContext(context.Background(), "globex")
+
+	// Act
+	response, err := service.CalculateShipping(ctx, shadowRequest())
+
+	// Assert
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrUnknownTenant)
+	category, field := ClassifyError(err)
+	assert.Equal(t, ErrorValidation, category)
+	assert.Equal(t, "tenant", field)
+}
+
+// benchmarkRequest is a typical domestic quote of the calculation path benchmarks
+func benchmarkRequest() *model.CalculateShippingRequest {
+	return &model.CalculateShippingRequest{
+		OriginZipcode:      "01310-100",
+		DestinationZipcode: "20040-020",
+		Weight:             2.5,
+		Dimensions:         model.PackageDimensions{Length: 30, Width: 20, Height: 15},
+	}
+}
+
+func BenchmarkCalculateShipping(b *testing.B) {
+	benchmarks := []struct {
+		name   string
+		ctx    func() context.Context
+		mutate func(req *model.CalculateShippingRequest)
+	}{
+		{"standard", context.Background, func(req *model.CalculateShippingRequest) {}},
+		{"express", context.Background, func(req *model.CalculateShippingRequest) { req.IsExpress = true }},
+		{"optimized", context.Background, func(req *model.CalculateShippingRequest) { req.Optimize = model.OptimizeBalanced }},
+		{"dry run", func() context.Context {
+			ctx, _ := WithDryRun(context.Background())
+			return ctx
+		}, func(req *model.CalculateShippingRequest) {}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			service := NewShippingService()
+			req := benchmarkRequest()
+			bm.mutate(req)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				if _, err := service.CalculateShipping(bm.ctx(), req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateShipping_WeekendDelivery(t *testing.T) {
+	tests := []struct {
+		name          string
+		weekend       map[string]pricing.WeekendDelivery
+		allow         bool
+		now           time.Time
+		wantOptions   []model.ShippingOption
+		wantCost      money.Amount
+		wantSurcharge money.Amount
+	}{
+		{
+			name:  "not configured",
+			allow: true,
+			wantOptions: []model.ShippingOption{
+				{Service: "standard", Cost: money.FromMinor(1250), Time: "2 dias", EstimatedDays: 2, DeliveryDate: "2025-03-09", Cheapest: true},
+				{Service: "express", Cost: money.FromMinor(1875), Time: "1 dia", EstimatedDays: 1, DeliveryDate: "2025-03-08", Fastest: true},
+			},
+			wantCost: money.FromMinor(1875),
+		},
+		{
+			name:    "weekend delivery not allowed",
+			weekend: map[string]pricing.WeekendDelivery{"express": {Saturday: true, SurchargeRate: 0.2}},
+			wantOptions: []model.ShippingOption{
+				{Service: "standard", Cost: money.FromMinor(1250), Time: "4 dias", EstimatedDays: 4, DeliveryDate: "2025-03-11", Cheapest: true},
+				{Service: "express", Cost: money.FromMinor(1875), Time: "3 dias", EstimatedDays: 3, DeliveryDate: "2025-03-10", Fastest: true},
+			},
+			wantCost: money.FromMinor(1875),
+		},
+		{
+			name:    "weekend delivery allowed",
+			weekend: map[string]pricing.WeekendDelivery{"express": {Saturday: true, SurchargeRate: 0.2}},
+			allow:   true,
+			wantOptions: []model.ShippingOption{
+				{Service: "standard", Cost: money.FromMinor(1250), Time: "4 dias", EstimatedDays: 4, DeliveryDate: "2025-03-11", Cheapest: true},
+				{Service: "express", Cost: money.FromMinor(2125), Time: "1 dia", EstimatedDays: 1, DeliveryDate: "2025-03-08", WeekendDelivery: true, Fastest: true},
+			},
+			wantCost:      money.FromMinor(2125),
+			wantSurcharge: money.FromMinor(250),
+		},
+		{
+			name:    "no weekend in transit",
+			weekend: map[string]pricing.WeekendDelivery{"express": {Saturday: true, SurchargeRate: 0.2}},
+			allow:   true,
+			// Monday, 10:00 in São Paulo
+			now: time.Date(2025, 3, 3, 13, 0, 0, 0, time.UTC),
+			wantOptions: []model.ShippingOption{
+				{Service: "standard", Cost: money.FromMinor(1250), Time: "2 dias", EstimatedDays: 2, DeliveryDate: "2025-03-05", Cheapest: true},
+				{Service: "express", Cost: money.FromMinor(1875), Time: "1 dia", EstimatedDays: 1, DeliveryDate: "2025-03-04", Fastest: true},
+			},
+			wantCost: money.FromMinor(1875),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := pricing.DefaultConfig()
+			cfg.WeekendDelivery = tt.weekend
+			now := tt.now
+			if now.IsZero() {
+				// Friday, 10:00 in São Paulo
+				now = time.Date(2025, 3, 7, 13, 0, 0, 0, time.UTC)
+			}
+			service := NewShippingServiceWithConfig(Config{Pricing: &cfg, Clock: determinism.FixedClock{Time: now}})
+			req := &model.CalculateShippingRequest{
+				OriginZipcode:        "01310100",
+				DestinationZipcode:   "01311000",
+				Weight:               1.0,
+				Dimensions:           model.PackageDimensions{Length: 10.0, Width: 10.0, Height: 10.0},
+				IsExpress:            true,
+				AllowWeekendDelivery: tt.allow,
+			}
+
+			// Act
+			response, err := service.CalculateShipping(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOptions, response.ShippingOptions)
+			assert.Equal(t, tt.wantCost, response.ShippingCost)
+			assert.Equal(t, tt.wantSurcharge, response.Breakdown.WeekendSurcharge)
+		})
+	}
+}
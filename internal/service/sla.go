@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/manifest"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+	"go.uber.org/zap"
+)
+
+// slaBatchSize is how many delivered shipments are loaded at a time when building an SLA report
+const slaBatchSize = 500
+
+// SLA outcomes of a delivery, the sla.outcome attribute of the SLA metrics
+const (
+	SLAOnTime = "on_time"
+	SLALate   = "late"
+)
+
+// SLAReportRequest selects the delivered shipments of an SLA report
+type SLAReportRequest struct {
+	// From and To are the first and last delivery days, in UTC
+	From time.Time
+	To   time.Time
+	// Carrier and Tenant filter the shipments when not empty
+	Carrier string
+	Tenant  string
+}
+
+// delivery is a delivered shipment measured against the delivery date promised by its quote
+type delivery struct {
+	carrier     string
+	origin      string
+	destination string
+	// delayDays is how many days after the promised date the shipment was delivered, negative
+	// when delivered before it
+	delayDays int
+}
+
+// SLAService compares the delivery dates promised by the quotes with the delivery dates reported
+// by the carriers, by carrier and route
+type SLAService struct {
+	shipments repository.ShipmentRepository
+	quotes    repository.QuoteRepository
+	tracking  repository.TrackingRepository
+	carriers  manifest.Config
+	metrics   *telemetry.Metrics
+}
+
+// NewSLAService creates an SLA service attributing the shipments to the carriers of their service
+// level in carriers, recording the attainment of the deliveries observed in metrics
+func NewSLAService(shipments repository.ShipmentRepository, quotes repository.QuoteRepository, trackingRepository repository.TrackingRepository, carriers manifest.Config, metrics *telemetry.Metrics) *SLAService {
+	return &SLAService{
+		shipments: shipments,
+		quotes:    quotes,
+		tracking:  trackingRepository,
+		carriers:  carriers,
+		metrics:   metrics,
+	}
+}
+
+// Report returns the SLA attainment of the shipments delivered between req.From and req.To,
+// overall and by carrier and route. The promised date of a shipment is the quote day plus the
+// estimated days of its service level, and it is on time when delivered on that day or before
+func (s *SLAService) Report(ctx context.Context, req SLAReportRequest) (*model.SLAReport, error) {
+	carrier := strings.ToLower(strings.TrimSpace(req.Carrier))
+	from, until := req.From.UTC(), req.To.UTC().AddDate(0, 0, 1)
+
+	routes := make(map[[3]string]*model.SLARoute)
+	var overall model.SLAStats
+	var overallDelay, unmeasured int
+	delays := make(map[[3]string]int)
+
+	afterID := ""
+	for {
+		page, err := s.shipments.ListByStatus(ctx, model.TrackingStatusDelivered, afterID, slaBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list delivered shipments: %w", err)
+		}
+		for i := range page {
+			shipment := &page[i]
+			if req.Tenant != "" && shipmentTenant(shipment) != req.Tenant {
+				continue
+			}
+			if carrier != "" && s.carriers.Carrier(shipment.Service) != carrier {
+				continue
+			}
+			deliveredAt, err := s.deliveredAt(ctx, shipment.ID)
+			if err != nil {
+				return nil, err
+			}
+			if deliveredAt.IsZero() || deliveredAt.Before(from) || !deliveredAt.Before(until) {
+				continue
+			}
+
+			measured, ok, err := s.evaluate(ctx, shipment, deliveredAt)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				unmeasured++
+				continue
+			}
+			key := [3]string{measured.carrier, measured.origin, measured.destination}
+			route, found := routes[key]
+			if !found {
+				route = &model.SLARoute{Carrier: measured.carrier, Origin: measured.origin, Destination: measured.destination}
+				routes[key] = route
+			}
+			addDelivery(&route.SLAStats, measured.delayDays)
+			addDelivery(&overall, measured.delayDays)
+			if measured.delayDays > 0 {
+				delays[key] += measured.delayDays
+				overallDelay += measured.delayDays
+			}
+		}
+		if len(page) < slaBatchSize {
+			break
+		}
+		afterID = page[len(page)-1].ID
+	}
+
+	report := &model.SLAReport{
+		From:       from.Format(model.DayLayout),
+		To:         req.To.UTC().Format(model.DayLayout),
+		Routes:     make([]model.SLARoute, 0, len(routes)),
+		Unmeasured: unmeasured,
+	}
+	for key, route := range routes {
+		summarize(&route.SLAStats, delays[key])
+		report.Routes = append(report.Routes, *route)
+	}
+	sort.Slice(report.Routes, func(i, j int) bool {
+		a, b := report.Routes[i], report.Routes[j]
+		if a.Carrier != b.Carrier {
+			return a.Carrier < b.Carrier
+		}
+		if a.Origin != b.Origin {
+			return a.Origin < b.Origin
+		}
+		return a.Destination < b.Destination
+	})
+	summarize(&overall, overallDelay)
+	report.Overall = overall
+	return report, nil
+}
+
+// Observe records the SLA metrics of a shipment when an events.TrackingUpdated event reports its
+// first delivery event; other events are ignored. Failures are logged
+func (s *SLAService) Observe(ctx context.Context, event events.Event) {
+	if event.Type != events.TrackingUpdated {
+		return
+	}
+	shipmentTracking, ok := event.Data.(*model.ShipmentTracking)
+	if !ok || shipmentTracking.Status != model.TrackingStatusDelivered {
+		return
+	}
+	deliveredAt := firstDelivery(shipmentTracking.Events)
+	if latest := shipmentTracking.Events[len(shipmentTracking.Events)-1]; !latest.OccurredAt.Equal(deliveredAt) {
+		// The delivery was already reported by a previous event
+		return
+	}
+
+	shipment, err := s.shipments.Get(ctx, shipmentTracking.ShipmentID)
+	if err == nil {
+		var measured delivery
+		if measured, ok, err = s.evaluate(ctx, shipment, deliveredAt); err == nil && ok {
+			outcome := SLAOnTime
+			if measured.delayDays > 0 {
+				outcome = SLALate
+			}
+			s.metrics.RecordSLADelivery(ctx, measured.carrier, measured.origin, measured.destination, outcome, int64(measured.delayDays))
+		}
+	}
+	if err != nil {
+		logger.FromContext(ctx).Warn("Falha ao medir o prazo de entrega",
+			zap.String("shipment_id", shipmentTracking.ShipmentID),
+			zap.Error(err),
+		)
+	}
+}
+
+// deliveredAt returns when the shipment was first reported delivered, or the zero time
+func (s *SLAService) deliveredAt(ctx context.Context, shipmentID string) (time.Time, error) {
+	history, err := s.tracking.List(ctx, shipmentID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load tracking: %w", err)
+	}
+	return firstDelivery(history), nil
+}
+
+// evaluate measures a delivery against the date promised for the shipment at booking. It reports
+// false when there is no promised date: the quote did not estimate the delivery days of the service
+// level or, for shipments booked before the date was recorded, is no longer stored
+func (s *SLAService) evaluate(ctx context.Context, shipment *model.Shipment, deliveredAt time.Time) (delivery, bool, error) {
+	promisedValue := shipment.PromisedDate
+	if promisedValue == "" {
+		quote, err := s.quotes.Get(ctx, shipment.QuoteID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return delivery{}, false, nil
+		}
+		if err != nil {
+			return delivery{}, false, fmt.Errorf("failed to load quote: %w", err)
+		}
+		option, ok := quotedOption(quote.Response.ShippingOptions, shipment.Service)
+		if !ok {
+			return delivery{}, false, nil
+		}
+		promisedValue = promisedDate(quote, option, shipment.BookedAt)
+	}
+	if promisedValue == "" {
+		return delivery{}, false, nil
+	}
+	promised, err := time.Parse(model.DayLayout, promisedValue)
+	if err != nil {
+		return delivery{}, false, fmt.Errorf("invalid promised date of shipment %s: %w", shipment.ID, err)
+	}
+	return delivery{
+		carrier:     s.carriers.Carrier(shipment.Service),
+		origin:      routeEndpoint(shipment.Package.OriginZipcode, ""),
+		destination: routeEndpoint(shipment.Package.DestinationZipcode, shipment.Package.DestinationCountry),
+		delayDays:   int(math.Round(day(deliveredAt).Sub(promised).Hours() / 24)),
+	}, true, nil
+}
+
+// promisedDate is the delivery date promised by a quote for one of its options, the day of the
+// quote, or bookedAt for quotes stored without their creation time, plus the estimated days of the
+// option. It is empty when the option has no estimated days
+func promisedDate(quote *repository.Quote, option model.ShippingOption, bookedAt time.Time) string {
+	if option.EstimatedDays <= 0 {
+		return ""
+	}
+	quotedAt := quote.CreatedAt
+	if quotedAt.IsZero() {
+		quotedAt = bookedAt
+	}
+	return day(quotedAt).AddDate(0, 0, option.EstimatedDays).Format(model.DayLayout)
+}
+
+// firstDelivery returns the occurrence time of the first delivered event, or the zero time
+func firstDelivery(history []model.TrackingEvent) time.Time {
+	for _, event := range history {
+		if event.Status == model.TrackingStatusDelivered {
+			return event.OccurredAt
+		}
+	}
+	return time.Time{}
+}
+
+// day truncates t to the start of its day in UTC
+func day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// routeEndpoint is the state of a Brazilian zipcode or the country of an international address,
+// as the destination region of the shipment metrics
+func routeEndpoint(zipcodeValue, country string) string {
+	return DestinationRegion(&model.CalculateShippingRequest{DestinationZipcode: zipcodeValue, DestinationCountry: country})
+}
+
+// shipmentTenant is the tenant of a shipment; shipments booked before tenants were recorded
+// belong to the default tenant
+func shipmentTenant(shipment *model.Shipment) string {
+	if shipment.Tenant == "" {
+		return tenant.Default
+	}
+	return shipment.Tenant
+}
+
+// addDelivery counts a delivery in stats
+func addDelivery(stats *model.SLAStats, delayDays int) {
+	stats.Delivered++
+	if delayDays > 0 {
+		stats.Late++
+		return
+	}
+	stats.OnTime++
+}
+
+// summarize computes the attainment and the average delay of stats from the total delay of the
+// late deliveries, rounded to 4 and 2 decimals
+func summarize(stats *model.SLAStats, totalDelayDays int) {
+	if stats.Delivered > 0 {
+		stats.Attainment = math.Round(float64(stats.OnTime)/float64(stats.Delivered)*10000) / 10000
+	}
+	if stats.Late > 0 {
+		stats.AverageDelayDays = math.Round(float64(totalDelayDays)/float64(stats.Late)*100) / 100
+	}
+}
+
+// SLAPublisher publishes events to another publisher and records the SLA metrics of the deliveries
+// they report
+type SLAPublisher struct {
+	next events.Publisher
+	sla  *SLAService
+}
+
+// NewSLAPublisher creates a publisher wrapping next
+func NewSLAPublisher(next events.Publisher, sla *SLAService) *SLAPublisher {
+	return &SLAPublisher{next: next, sla: sla}
+}
+
+// Publish implements events.Publisher
+func (p *SLAPublisher) Publish(ctx context.Context, event events.Event) error {
+	err := p.next.Publish(ctx, event)
+	p.sla.Observe(ctx, event)
+	return err
+}
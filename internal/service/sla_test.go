@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/manifest"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var slaQuotedAt = time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+// newSLAService stores delivered shipments measured against their quotes:
+//   - s1: correios SP→RJ, promised on 03-06 and delivered on 03-06 (on time)
+//   - s2: jadlog SP→RJ, promised on 03-03 and delivered on 03-05 (2 days late)
+//   - s3: correios SP→RJ, without a stored quote (unmeasured)
+//   - s4: correios SP→US of tenant acme, promised on 03-07 and delivered on 03-10 (3 days late)
+//   - s5: correios SP→RJ, promised on 02-19 and delivered on 02-20, before the reported period
+//   - s6: booked and not delivered
+func newSLAService(t *testing.T) *SLAService {
+	t.Helper()
+	ctx := context.Background()
+	quotes := repository.NewMemoryQuoteRepository()
+	options := []model.ShippingOption{{Service: model.ServiceStandard, EstimatedDays: 5}, {Service: model.ServiceExpress, EstimatedDays: 2}}
+	for _, id := range []string{"q1", "q2", "q4", "q5", "q6"} {
+		createdAt := slaQuotedAt
+		if id == "q4" {
+			createdAt = slaQuotedAt.AddDate(0, 0, 1)
+		}
+		if id == "q5" {
+			createdAt = slaQuotedAt.AddDate(0, 0, -15)
+		}
+		assert.NoError(t, quotes.Save(ctx, &repository.Quote{ID: id, CreatedAt: createdAt, Response: model.CalculateShippingResponse{ShippingOptions: options}}))
+	}
+
+	domestic := model.ShipmentPackage{OriginZipcode: "01310100", DestinationZipcode: "20040020"}
+	international := model.ShipmentPackage{OriginZipcode: "01310100", DestinationZipcode: "10001", DestinationCountry: "US"}
+	shipments := repository.NewMemoryShipmentRepository()
+	trackingRepository := repository.NewMemoryTrackingRepository()
+	for _, entry := range []struct {
+		shipment    model.Shipment
+		deliveredAt time.Time
+	}{
+		{model.Shipment{ID: "s1", QuoteID: "q1", Service: model.ServiceStandard, Package: domestic}, time.Date(2025, 3, 6, 20, 0, 0, 0, time.UTC)},
+		{model.Shipment{ID: "s2", QuoteID: "q2", Service: model.ServiceExpress, Package: domestic}, time.Date(2025, 3, 5, 9, 0, 0, 0, time.UTC)},
+		{model.Shipment{ID: "s3", QuoteID: "q3", Service: model.ServiceStandard, Package: domestic}, time.Date(2025, 3, 5, 9, 0, 0, 0, time.UTC)},
+		{model.Shipment{ID: "s4", QuoteID: "q4", Service: model.ServiceStandard, Tenant: "acme", Package: international}, time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)},
+		{model.Shipment{ID: "s5", QuoteID: "q5", Service: model.ServiceStandard, Package: domestic}, time.Date(2025, 2, 20, 9, 0, 0, 0, time.UTC)},
+		{model.Shipment{ID: "s6", QuoteID: "q6", Service: model.ServiceStandard, Package: domestic}, time.Time{}},
+	} {
+		shipment := entry.shipment
+		shipment.Status = model.ShipmentStatusBooked
+		if !entry.deliveredAt.IsZero() {
+			shipment.Status = model.TrackingStatusDelivered
+			_, err := trackingRepository.Append(ctx, shipment.ID, []model.TrackingEvent{
+				{Status: model.TrackingStatusInTransit, OccurredAt: entry.deliveredAt.Add(-24 * time.Hour)},
+				{Status: model.TrackingStatusDelivered, OccurredAt: entry.deliveredAt},
+			})
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, shipments.Save(ctx, &shipment))
+	}
+
+	carriers := manifest.Config{Carriers: map[string]string{model.ServiceStandard: "correios", model.ServiceExpress: "jadlog"}}
+	return NewSLAService(shipments, quotes, trackingRepository, carriers, nil)
+}
+
+func TestSLAService_Report(t *testing.T) {
+	// Arrange
+	service := newSLAService(t)
+	req := SLAReportRequest{From: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	report, err := service.Report(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &model.SLAReport{
+		From:    "2025-03-01",
+		To:      "2025-03-31",
+		Overall: model.SLAStats{Delivered: 3, OnTime: 1, Late: 2, Attainment: 0.3333, AverageDelayDays: 2.5},
+		Routes: []model.SLARoute{
+			{Carrier: "correios", Origin: "SP", Destination: "RJ", SLAStats: model.SLAStats{Delivered: 1, OnTime: 1, Attainment: 1}},
+			{Carrier: "correios", Origin: "SP", Destination: "US", SLAStats: model.SLAStats{Delivered: 1, Late: 1, AverageDelayDays: 3}},
+			{Carrier: "jadlog", Origin: "SP", Destination: "RJ", SLAStats: model.SLAStats{Delivered: 1, Late: 1, AverageDelayDays: 2}},
+		},
+		Unmeasured: 1,
+	}, report)
+}
+
+func TestSLAService_ReportFilters(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        SLAReportRequest
+		wantRoutes []model.SLARoute
+	}{
+		{
+			"carrier",
+			SLAReportRequest{Carrier: " Jadlog "},
+			[]model.SLARoute{{Carrier: "jadlog", Origin: "SP", Destination: "RJ", SLAStats: model.SLAStats{Delivered: 1, Late: 1, AverageDelayDays: 2}}},
+		},
+		{
+			"tenant",
+			SLAReportRequest{Tenant: "acme"},
+			[]model.SLARoute{{Carrier: "correios", Origin: "SP", Destination: "US", SLAStats: model.SLAStats{Delivered: 1, Late: 1, AverageDelayDays: 3}}},
+		},
+		{
+			"period",
+			SLAReportRequest{From: time.Date(2025, 2, 20, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+			[]model.SLARoute{{Carrier: "correios", Origin: "SP", Destination: "RJ", SLAStats: model.SLAStats{Delivered: 1, Late: 1, AverageDelayDays: 1}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := newSLAService(t)
+			req := tt.req
+			if req.From.IsZero() {
+				req.From, req.To = time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+			}
+
+			// Act
+			report, err := service.Report(context.Background(), req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRoutes, report.Routes)
+		})
+	}
+}
+
+// failingQuoteRepository fails every lookup
+type failingQuoteRepository struct {
+	repository.QuoteRepository
+}
+
+func (failingQuoteRepository) Get(ctx context.Context, id string) (*repository.Quote, error) {
+	return nil, errors.New("store unavailable")
+}
+
+func TestSLAService_ReportQuoteStoreFailure(t *testing.T) {
+	// Arrange
+	service := newSLAService(t)
+	service.quotes = failingQuoteRepository{}
+
+	// Act
+	_, err := service.Report(context.Background(), SLAReportRequest{From: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)})
+
+	// Assert
+	assert.ErrorContains(t, err, "failed to load quote")
+}
+
+func TestSLAService_ReportPromisedDate(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	shipments := repository.NewMemoryShipmentRepository()
+	trackingRepository := repository.NewMemoryTrackingRepository()
+	domestic := model.ShipmentPackage{OriginZipcode: "01310100", DestinationZipcode: "20040020"}
+	// The quotes of the shipments expired and are no longer stored
+	for _, shipment := range []model.Shipment{
+		{ID: "s1", QuoteID: "q1", Service: model.ServiceStandard, PromisedDate: "2025-03-06", Package: domestic},
+		{ID: "s2", QuoteID: "q2", Service: model.ServiceStandard, PromisedDate: "2025-03-04", Package: domestic},
+	} {
+		shipment.Status = model.TrackingStatusDelivered
+		assert.NoError(t, shipments.Save(ctx, &shipment))
+		_, err := trackingRepository.Append(ctx, shipment.ID, []model.TrackingEvent{{Status: model.TrackingStatusDelivered, OccurredAt: time.Date(2025, 3, 6, 9, 0, 0, 0, time.UTC)}})
+		assert.NoError(t, err)
+	}
+	carriers := manifest.Config{Carriers: map[string]string{model.ServiceStandard: "correios"}}
+	service := NewSLAService(shipments, failingQuoteRepository{}, trackingRepository, carriers, nil)
+
+	// Act
+	report, err := service.Report(ctx, SLAReportRequest{From: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, model.SLAStats{Delivered: 2, OnTime: 1, Late: 1, Attainment: 0.5, AverageDelayDays: 2}, report.Overall)
+	assert.Zero(t, report.Unmeasured, "the promised date of the shipments does not need their quotes")
+}
+
+func TestSLAService_Observe(t *testing.T) {
+	deliveredAt := time.Date(2025, 3, 6, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		event    events.Event
+		wantGets int
+	}{
+		{
+			"first delivery",
+			events.Event{Type: events.TrackingUpdated, Data: &model.ShipmentTracking{ShipmentID: "s1", Status: model.TrackingStatusDelivered, Events: []model.TrackingEvent{{Status: model.TrackingStatusDelivered, OccurredAt: deliveredAt}}}},
+			1,
+		},
+		{
+			"delivery already reported",
+			events.Event{Type: events.TrackingUpdated, Data: &model.ShipmentTracking{ShipmentID: "s1", Status: model.TrackingStatusDelivered, Events: []model.TrackingEvent{
+				{Status: model.TrackingStatusDelivered, OccurredAt: deliveredAt},
+				{Status: model.TrackingStatusDelivered, OccurredAt: deliveredAt.Add(time.Hour)},
+			}}},
+			0,
+		},
+		{
+			"not delivered",
+			events.Event{Type: events.TrackingUpdated, Data: &model.ShipmentTracking{ShipmentID: "s1", Status: model.TrackingStatusInTransit, Events: []model.TrackingEvent{{Status: model.TrackingStatusInTransit, OccurredAt: deliveredAt}}}},
+			0,
+		},
+		{"other event", events.Event{Type: events.ShipmentBooked, Data: &model.Shipment{ID: "s1"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := newSLAService(t)
+			counting := &countingShipmentRepository{ShipmentRepository: service.shipments}
+			service.shipments = counting
+
+			// Act
+			service.Observe(context.Background(), tt.event)
+
+			// Assert
+			assert.Equal(t, tt.wantGets, counting.gets)
+		})
+	}
+}
+
+// countingShipmentRepository counts the shipments loaded
+type countingShipmentRepository struct {
+	repository.ShipmentRepository
+	gets int
+}
+
+func (r *countingShipmentRepository) Get(ctx context.Context, id string) (*model.Shipment, error) {
+	r.gets++
+	return r.ShipmentRepository.Get(ctx, id)
+}
+
+type stubPublisher struct {
+	published []events.Event
+	err       error
+}
+
+func (p *stubPublisher) Publish(ctx context.Context, event events.Event) error {
+	p.published = append(p.published, event)
+	return p.err
+}
+
+func TestSLAPublisher(t *testing.T) {
+	// Arrange
+	next := &stubPublisher{err: errors.New("broker down")}
+	service := newSLAService(t)
+	counting := &countingShipmentRepository{ShipmentRepository: service.shipments}
+	service.shipments = counting
+	publisher := NewSLAPublisher(next, service)
+	event := events.Event{Type: events.TrackingUpdated, Data: &model.ShipmentTracking{ShipmentID: "s1", Status: model.TrackingStatusDelivered, Events: []model.TrackingEvent{{Status: model.TrackingStatusDelivered, OccurredAt: time.Date(2025, 3, 6, 20, 0, 0, 0, time.UTC)}}}}
+
+	// Act
+	err := publisher.Publish(context.Background(), event)
+
+	// Assert
+	assert.EqualError(t, err, "broker down")
+	assert.Len(t, next.published, 1)
+	assert.Equal(t, 1, counting.gets, "the delivery is measured even when the broker fails")
+}
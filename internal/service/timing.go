@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+)
+
+// Stages of a quote timed by WithTiming
+const (
+	// StageValidation is the validation of the request and the resolution of its rates, up to the
+	// pricing of the freight
+	StageValidation = "validation"
+	// StageCache is the lookup and storage of the quote in the quote cache
+	StageCache = "cache"
+	// StageDistance is the pricing of the freight by the formula strategy, which measures the
+	// distance of the route
+	StageDistance = "distance"
+	// StageProvider is the pricing of the freight by the carrier strategy, which calls the carrier
+	// rate API
+	StageProvider = "provider"
+	// StagePricing is the pricing of the freight by the other strategies, e.g. the rate table
+	StagePricing = "pricing"
+	// StageDelivery is the estimation of the delivery days and dates
+	StageDelivery = "delivery"
+)
+
+type timingKey struct{}
+
+// StageTime is the time spent in a stage of a quote
+type StageTime struct {
+	Stage    string
+	Duration time.Duration
+}
+
+// QuoteTiming collects the time spent in each stage of a quote and, as in a dry run, the decisions
+// taken to price it. It is not safe for concurrent use
+type QuoteTiming struct {
+	stages []StageTime
+	trace  DecisionTrace
+}
+
+// WithTiming returns a context whose quotes record in the returned timing the time spent in each
+// stage and their decisions, e.g. to log the slow quotes. Unlike WithDryRun, the quotes keep the
+// side effects of the service
+func WithTiming(ctx context.Context) (context.Context, *QuoteTiming) {
+	timing := &QuoteTiming{}
+	return context.WithValue(ctx, timingKey{}, timing), timing
+}
+
+// withoutTiming returns a context whose quotes are not timed, for the calculations that outlive
+// the timed one, such as shadow pricing
+func withoutTiming(ctx context.Context) context.Context {
+	if timingFrom(ctx) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, timingKey{}, (*QuoteTiming)(nil))
+}
+
+// timingFrom returns the timing of a timed context, or nil
+func timingFrom(ctx context.Context) *QuoteTiming {
+	timing, _ := ctx.Value(timingKey{}).(*QuoteTiming)
+	return timing
+}
+
+// Stages returns the time spent in each stage, in the order they first ran; the time of a stage
+// run more than once, such as the pricing of each service level, is added up
+func (t *QuoteTiming) Stages() []StageTime {
+	return append([]StageTime{}, t.stages...)
+}
+
+// Steps returns the decisions recorded so far
+func (t *QuoteTiming) Steps() []model.DecisionStep {
+	return t.trace.Steps()
+}
+
+// since adds the time elapsed since start to a stage; it does nothing for untimed quotes, where
+// the timing is nil
+func (t *QuoteTiming) since(stage string, start time.Time) {
+	if t == nil {
+		return
+	}
+	elapsed := time.Since(start)
+	for i := range t.stages {
+		if t.stages[i].Stage == stage {
+			t.stages[i].Duration += elapsed
+			return
+		}
+	}
+	t.stages = append(t.stages, StageTime{Stage: stage, Duration: elapsed})
+}
+
+// strategyStage is the stage of the pricing of the freight with a strategy
+func strategyStage(strategy string) string {
+	switch strategy {
+	case pricing.StrategyFormula:
+		return StageDistance
+	case pricing.StrategyCarrier:
+		return StageProvider
+	default:
+		return StagePricing
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// stageNames returns the stages of a timing, in order
+func stageNames(timing *QuoteTiming) []string {
+	var names []string
+	for _, stage := range timing.Stages() {
+		names = append(names, stage.Stage)
+	}
+	return names
+}
+
+func TestCalculateShipping_Timing(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+	ctx, timing := WithTiming(context.Background())
+
+	// Act
+	_, err := service.CalculateShipping(ctx, shadowRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{StageValidation, StageDistance, StageDelivery}, stageNames(timing))
+	assert.Equal(t, []model.DecisionStep{
+		{Step: StepRateTable, Detail: "version " + cfg.VersionID()},
+		{Step: StepCurrency, Detail: "BRL for destination country BR"},
+		{Step: StepPackageType, Detail: "standard: surcharge rate 0, express prohibited false"},
+		{Step: StepDeliveryType, Detail: "home: cost adjustment rate 0"},
+		{Step: StepStrategy, Detail: "standard priced with formula"},
+		{Step: StepStrategy, Detail: "express priced with formula"},
+	}, timing.Steps())
+	assert.False(t, IsDryRun(ctx))
+}
+
+func TestCalculateShipping_Timing_ProviderStage(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	cfg.Strategies = map[string]string{pricing.LevelStandard: pricing.StrategyCarrier, pricing.LevelExpress: pricing.StrategyCarrier}
+	service := NewShippingService(
+		WithPricingConfig(cfg),
+		WithProviders(map[string]pricing.Strategy{pricing.StrategyCarrier: &countingStrategy{}}),
+	)
+	ctx, timing := WithTiming(context.Background())
+
+	// Act
+	_, err := service.CalculateShipping(ctx, optionsRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{StageValidation, StageProvider, StageDelivery}, stageNames(timing))
+}
+
+func TestCalculateShipping_Timing_Rejected(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+	req := optionsRequest()
+	req.Weight = -1
+	ctx, timing := WithTiming(context.Background())
+
+	// Act
+	_, err := service.CalculateShipping(ctx, req)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, []string{StageValidation}, stageNames(timing))
+}
+
+func TestCalculateShipping_Timing_Cached(t *testing.T) {
+	// Arrange
+	service := NewShippingService(WithCache(store.NewMemoryStore(), time.Minute))
+	_, err := service.CalculateShipping(context.Background(), optionsRequest())
+	assert.NoError(t, err)
+	ctx, timing := WithTiming(context.Background())
+
+	// Act
+	response, err := service.CalculateShipping(ctx, optionsRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{StageCache}, stageNames(timing))
+	assert.Equal(t, []model.DecisionStep{
+		{Step: StepCache, Detail: "served from the quote cache with version " + response.PricingVersion},
+	}, timing.Steps())
+}
+
+func TestStrategyStage(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     string
+	}{
+		{pricing.StrategyFormula, StageDistance},
+		{pricing.StrategyCarrier, StageProvider},
+		{pricing.StrategyTable, StagePricing},
+		{"partner", StagePricing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.want, strategyStage(tt.strategy))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+)
+
+// Steps of the decision trace of a dry-run or timed quote
+const (
+	StepRateTable    = "rate_table"
+	StepExperiment   = "experiment"
+	StepCurrency     = "currency"
+	StepPackageType  = "package_type"
+	StepDeliveryType = "delivery_type"
+	StepReturnPolicy = "return_policy"
+	StepPickup       = "pickup"
+	StepFreight      = "freight"
+	StepRestricted   = "restricted_area"
+	StepRemote       = "remote_area"
+	StepWeekend      = "weekend_delivery"
+	StepFuel         = "fuel_surcharge"
+	StepStrategy     = "strategy"
+	StepPriceLimit   = "price_limit"
+	StepOptimize     = "optimize"
+	StepTax          = "tax"
+	StepCustoms      = "customs"
+	StepCache        = "cache"
+)
+
+type decisionTraceKey struct{}
+
+// DecisionTrace collects the rules applied while pricing a quote, in order. It is not safe for
+// concurrent use
+type DecisionTrace struct {
+	steps []model.DecisionStep
+}
+
+// WithDryRun returns a context whose quotes are priced as a dry run: the calculation records its
+// decisions in the returned trace and skips the side effects of the service, such as shadow
+// pricing. Callers are responsible for not persisting, publishing or billing the quote
+func WithDryRun(ctx context.Context) (context.Context, *DecisionTrace) {
+	trace := &DecisionTrace{}
+	return context.WithValue(ctx, decisionTraceKey{}, trace), trace
+}
+
+// IsDryRun reports whether ctx prices quotes as a dry run
+func IsDryRun(ctx context.Context) bool {
+	_, ok := ctx.Value(decisionTraceKey{}).(*DecisionTrace)
+	return ok
+}
+
+// Steps returns the decisions recorded so far
+func (t *DecisionTrace) Steps() []model.DecisionStep {
+	return append([]model.DecisionStep{}, t.steps...)
+}
+
+// traceFrom returns the trace of a dry-run context, the trace of the timing of a timed context, or
+// nil
+func traceFrom(ctx context.Context) *DecisionTrace {
+	if trace, ok := ctx.Value(decisionTraceKey{}).(*DecisionTrace); ok {
+		return trace
+	}
+	if timing := timingFrom(ctx); timing != nil {
+		return &timing.trace
+	}
+	return nil
+}
+
+// record adds a decision to the trace; it does nothing outside dry runs, where the trace is nil.
+// The arguments are allocated even then, so the calls made for every service level check the
+// trace first
+func (t *DecisionTrace) record(step, format string, args ...any) {
+	if t == nil {
+		return
+	}
+	t.steps = append(t.steps, model.DecisionStep{Step: step, Detail: fmt.Sprintf(format, args...)})
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/experiment"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCalculateShipping_DryRunTrace(t *testing.T) {
+	// Arrange
+	cfg := pricing.DefaultConfig()
+	rates := cfg.Currencies["BRL"]
+	rates.PriceLimits = []pricing.PriceLimit{{MinCost: money.FromMinor(1500)}}
+	cfg.Currencies["BRL"] = rates
+	service := NewShippingServiceWithConfig(Config{Pricing: &cfg})
+	ctx, trace := WithDryRun(context.Background())
+
+	// Act
+	response, err := service.CalculateShipping(ctx, shadowRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMinor(1500), response.ShippingCost)
+	assert.Equal(t, []model.DecisionStep{
+		{Step: StepRateTable, Detail: "version " + cfg.VersionID()},
+		{Step: StepCurrency, Detail: "BRL for destination country BR"},
+		{Step: StepPackageType, Detail: "standard: surcharge rate 0, express prohibited false"},
+		{Step: StepDeliveryType, Detail: "home: cost adjustment rate 0"},
+		{Step: StepStrategy, Detail: "standard priced with formula"},
+		{Step: StepPriceLimit, Detail: "standard clamped to the floor of zone local: 1250 to 1500"},
+		{Step: StepStrategy, Detail: "express priced with formula"},
+	}, trace.Steps())
+	assert.True(t, IsDryRun(ctx))
+}
+
+func TestCalculateShipping_WithoutDryRun(t *testing.T) {
+	// Arrange
+	service := NewShippingService()
+
+	// Act
+	_, err := service.CalculateShipping(context.Background(), shadowRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, IsDryRun(context.Background()))
+}
+
+func TestCalculateShipping_DryRunSkipsShadow(t *testing.T) {
+	// Arrange
+	secondary := pricing.DefaultConfig()
+	rates := secondary.Currencies["BRL"]
+	rates.BaseCost = money.FromMinor(2000)
+	secondary.Currencies["BRL"] = rates
+	service := NewShippingServiceWithConfig(Config{Shadow: &experiment.Shadow{Pricing: secondary, Threshold: 0.05}})
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	ctx, _ := WithDryRun(logger.NewContext(context.Background(), zap.New(core)))
+
+	// Act
+	_, err := service.CalculateShipping(ctx, shadowRequest())
+	service.WaitShadow()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Zero(t, logs.Len())
+}
+
+func TestCalculateShipping_CacheWarmupSkipsShadow(t *testing.T) {
+	// Arrange
+	secondary := pricing.DefaultConfig()
+	rates := secondary.Currencies["BRL"]
+	rates.BaseCost = money.FromMinor(2000)
+	secondary.Currencies["BRL"] = rates
+	service := NewShippingServiceWithConfig(Config{Shadow: &experiment.Shadow{Pricing: secondary, Threshold: 0.05}})
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	ctx := WithCacheWarmup(logger.NewContext(context.Background(), zap.New(core)))
+
+	// Act
+	_, err := service.CalculateShipping(ctx, shadowRequest())
+	service.WaitShadow()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Zero(t, logs.Len())
+}
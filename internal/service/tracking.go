@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/tracking"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrShipmentNotFound is returned when the tracked shipment does not exist
+	ErrShipmentNotFound = errors.New("shipment not found")
+	// ErrInvalidTrackingEvent is returned when a tracking event has an unknown status or no occurrence time
+	ErrInvalidTrackingEvent = errors.New("invalid tracking event")
+)
+
+// maxStatusAttempts bounds the attempts to update the status of a shipment updated concurrently
+const maxStatusAttempts = 5
+
+// TrackingService stores the tracking events of shipments, pushed by carrier webhooks or
+// polled from a TrackingProvider
+type TrackingService struct {
+	shipments repository.ShipmentRepository
+	tracking  repository.TrackingRepository
+	provider  tracking.TrackingProvider
+	events    events.Publisher
+}
+
+// NewTrackingService creates a tracking service. When provider is not nil, the carrier is
+// polled every time the tracking of a shipment is requested
+func NewTrackingService(shipments repository.ShipmentRepository, trackingRepository repository.TrackingRepository, provider tracking.TrackingProvider, publisher events.Publisher) *TrackingService {
+	return &TrackingService{
+		shipments: shipments,
+		tracking:  trackingRepository,
+		provider:  provider,
+		events:    publisher,
+	}
+}
+
+// Get returns the tracking of a shipment, first polling the carrier for new events. Polling
+// failures are logged and the stored events are returned
+func (s *TrackingService) Get(ctx context.Context, shipmentID string) (*model.ShipmentTracking, error) {
+	shipment, err := s.shipment(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.provider != nil {
+		polled, err := s.provider.Track(ctx, shipment)
+		if err == nil {
+			polled, err = validTrackingEvents(polled)
+		}
+		if err != nil {
+			logger.FromContext(ctx).Warn("Falha ao consultar rastreamento na transportadora",
+				zap.String("shipment_id", shipment.ID),
+				zap.Error(err),
+			)
+		} else if len(polled) > 0 {
+			return s.record(ctx, shipment, polled)
+		}
+	}
+
+	history, err := s.tracking.List(ctx, shipment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tracking: %w", err)
+	}
+	return newShipmentTracking(shipment.ID, history), nil
+}
+
+// Record stores tracking events of a shipment pushed by the carrier and returns the updated
+// tracking. Events already stored are ignored
+func (s *TrackingService) Record(ctx context.Context, shipmentID string, trackingEvents []model.TrackingEvent) (*model.ShipmentTracking, error) {
+	if len(trackingEvents) == 0 {
+		return nil, fmt.Errorf("%w: events are required", ErrInvalidTrackingEvent)
+	}
+	trackingEvents, err := validTrackingEvents(trackingEvents)
+	if err != nil {
+		return nil, err
+	}
+	shipment, err := s.shipment(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.record(ctx, shipment, trackingEvents)
+}
+
+// record appends the events to the shipment history, updates the status of the shipment to the
+// status of the latest event and, when there were new events, publishes events.TrackingUpdated
+func (s *TrackingService) record(ctx context.Context, shipment *model.Shipment, trackingEvents []model.TrackingEvent) (*model.ShipmentTracking, error) {
+	zapLogger := logger.FromContext(ctx)
+
+	added, err := s.tracking.Append(ctx, shipment.ID, trackingEvents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save tracking events: %w", err)
+	}
+	history, err := s.tracking.List(ctx, shipment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tracking: %w", err)
+	}
+	result := newShipmentTracking(shipment.ID, history)
+	if added == 0 {
+		return result, nil
+	}
+
+	if err := s.updateStatus(ctx, shipment, result.Status); err != nil {
+		return nil, err
+	}
+
+	event := events.Event{Type: events.TrackingUpdated, Subject: shipment.ID, Tenant: shipment.Tenant, OccurredAt: history[len(history)-1].OccurredAt, Data: result}
+	if err := s.events.Publish(ctx, event); err != nil {
+		zapLogger.Warn("Falha ao publicar evento",
+			zap.String("event_type", event.Type),
+			zap.String("shipment_id", shipment.ID),
+			zap.Error(err),
+		)
+	}
+	return result, nil
+}
+
+// updateStatus moves the shipment to status, the status of its latest event. The status is compared
+// and set: when another update changed it first, the status is recomputed from the history stored
+// since, so that an earlier event recorded concurrently never overwrites the status of a later one
+func (s *TrackingService) updateStatus(ctx context.Context, shipment *model.Shipment, status string) error {
+	for attempt := 0; shipment.Status != status; attempt++ {
+		if attempt == maxStatusAttempts {
+			return fmt.Errorf("failed to update the status of shipment %s: updated concurrently %d times", shipment.ID, attempt)
+		}
+		updated, err := s.shipments.UpdateStatus(ctx, shipment.ID, shipment.Status, status)
+		if err != nil {
+			return fmt.Errorf("failed to update shipment status: %w", err)
+		}
+		if updated {
+			logger.FromContext(ctx).Info("Status do envio atualizado",
+				zap.String("shipment_id", shipment.ID),
+				zap.String("status_anterior", shipment.Status),
+				zap.String("status", status),
+			)
+			shipment.Status = status
+			return nil
+		}
+
+		if shipment, err = s.shipment(ctx, shipment.ID); err != nil {
+			return err
+		}
+		history, err := s.tracking.List(ctx, shipment.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load tracking: %w", err)
+		}
+		status = newShipmentTracking(shipment.ID, history).Status
+	}
+	return nil
+}
+
+// shipment loads a shipment, mapping a missing one to ErrShipmentNotFound
+func (s *TrackingService) shipment(ctx context.Context, id string) (*model.Shipment, error) {
+	shipment, err := s.shipments.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrShipmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load shipment: %w", err)
+	}
+	return shipment, nil
+}
+
+// validTrackingEvents normalizes the statuses and occurrence times of the events
+func validTrackingEvents(trackingEvents []model.TrackingEvent) ([]model.TrackingEvent, error) {
+	valid := make([]model.TrackingEvent, 0, len(trackingEvents))
+	for i, event := range trackingEvents {
+		event.Status = strings.ToLower(strings.TrimSpace(event.Status))
+		if !slices.Contains(model.TrackingStatuses, event.Status) {
+			return nil, fmt.Errorf("%w: events[%d].status must be one of %s", ErrInvalidTrackingEvent, i, strings.Join(model.TrackingStatuses, ", "))
+		}
+		if event.OccurredAt.IsZero() {
+			return nil, fmt.Errorf("%w: events[%d].occurred_at is required", ErrInvalidTrackingEvent, i)
+		}
+		event.OccurredAt = event.OccurredAt.UTC()
+		valid = append(valid, event)
+	}
+	return valid, nil
+}
+
+// newShipmentTracking builds the tracking of a shipment from its history, oldest event first
+func newShipmentTracking(shipmentID string, history []model.TrackingEvent) *model.ShipmentTracking {
+	result := &model.ShipmentTracking{
+		ShipmentID: shipmentID,
+		Status:     model.ShipmentStatusBooked,
+		Events:     history,
+	}
+	if len(history) > 0 {
+		result.Status = history[len(history)-1].Status
+	}
+	return result
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/tracking"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// stubTrackingProvider returns events, or fails with err when set
+type stubTrackingProvider struct {
+	events []model.TrackingEvent
+	err    error
+}
+
+func (p stubTrackingProvider) Track(ctx context.Context, shipment *model.Shipment) ([]model.TrackingEvent, error) {
+	return p.events, p.err
+}
+
+var (
+	postedAt  = time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+	postedEvt = model.TrackingEvent{Status: model.TrackingStatusPosted, Location: "São Paulo/SP", OccurredAt: postedAt}
+)
+
+func newTrackingService(t *testing.T, provider tracking.TrackingProvider, publisher *recordingPublisher) (*TrackingService, *repository.MemoryShipmentRepository) {
+	t.Helper()
+	shipments := repository.NewMemoryShipmentRepository()
+	_ = shipments.Save(context.Background(), &model.Shipment{ID: "s1", QuoteID: "q1", Status: model.ShipmentStatusBooked})
+	return NewTrackingService(shipments, repository.NewMemoryTrackingRepository(), provider, publisher), shipments
+}
+
+func TestTrackingRecord(t *testing.T) {
+	// Arrange
+	publisher := &recordingPublisher{}
+	service, shipments := newTrackingService(t, nil, publisher)
+	delivered := model.TrackingEvent{Status: " Delivered ", OccurredAt: postedAt.Add(48 * time.Hour).In(time.FixedZone("BRT", -3*60*60))}
+
+	// Act
+	result, err := service.Record(context.Background(), "s1", []model.TrackingEvent{delivered, postedEvt})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ShipmentTracking{
+		ShipmentID: "s1",
+		Status:     model.TrackingStatusDelivered,
+		Events: []model.TrackingEvent{
+			postedEvt,
+			{Status: model.TrackingStatusDelivered, OccurredAt: postedAt.Add(48 * time.Hour)},
+		},
+	}, result)
+
+	shipment, _ := shipments.Get(context.Background(), "s1")
+	assert.Equal(t, model.TrackingStatusDelivered, shipment.Status)
+
+	assert.Len(t, publisher.events, 1)
+	assert.Equal(t, events.TrackingUpdated, publisher.events[0].Type)
+	assert.Equal(t, "s1", publisher.events[0].Subject)
+	assert.Equal(t, result, publisher.events[0].Data)
+}
+
+func TestTrackingRecord_DuplicateEvents(t *testing.T) {
+	// Arrange
+	publisher := &recordingPublisher{}
+	service, _ := newTrackingService(t, nil, publisher)
+	_, _ = service.Record(context.Background(), "s1", []model.TrackingEvent{postedEvt})
+
+	// Act
+	result, err := service.Record(context.Background(), "s1", []model.TrackingEvent{postedEvt})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result.Events, 1)
+	assert.Len(t, publisher.events, 1, "a redelivered event is not published again")
+}
+
+func TestTrackingRecord_SameStatus(t *testing.T) {
+	// Arrange
+	publisher := &recordingPublisher{}
+	service, shipments := newTrackingService(t, nil, publisher)
+	first := model.TrackingEvent{Status: model.TrackingStatusInTransit, Location: "Campinas/SP", OccurredAt: postedAt}
+	second := model.TrackingEvent{Status: model.TrackingStatusInTransit, Location: "Resende/RJ", OccurredAt: postedAt.Add(6 * time.Hour)}
+	_, _ = service.Record(context.Background(), "s1", []model.TrackingEvent{first})
+
+	// Act
+	result, err := service.Record(context.Background(), "s1", []model.TrackingEvent{second})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, model.TrackingStatusInTransit, result.Status)
+	shipment, _ := shipments.Get(context.Background(), "s1")
+	assert.Equal(t, model.TrackingStatusInTransit, shipment.Status)
+	assert.Len(t, publisher.events, 2, "new events are published even without a status change")
+}
+
+// racingShipments runs race before the first status update, as a concurrent request would
+type racingShipments struct {
+	*repository.MemoryShipmentRepository
+	race func()
+}
+
+func (r *racingShipments) UpdateStatus(ctx context.Context, id, from, to string) (bool, error) {
+	if race := r.race; race != nil {
+		r.race = nil
+		race()
+	}
+	return r.MemoryShipmentRepository.UpdateStatus(ctx, id, from, to)
+}
+
+func TestTrackingRecord_ConcurrentStatusUpdate(t *testing.T) {
+	// Arrange
+	shipments := repository.NewMemoryShipmentRepository()
+	_ = shipments.Save(context.Background(), &model.Shipment{ID: "s1", QuoteID: "q1", Status: model.ShipmentStatusBooked})
+	trackingRepository := repository.NewMemoryTrackingRepository()
+	delivered := model.TrackingEvent{Status: model.TrackingStatusDelivered, OccurredAt: postedAt.Add(48 * time.Hour)}
+	racing := &racingShipments{MemoryShipmentRepository: shipments, race: func() {
+		// A later event is recorded while the posted event is being recorded
+		_, _ = trackingRepository.Append(context.Background(), "s1", []model.TrackingEvent{delivered})
+		_, _ = shipments.UpdateStatus(context.Background(), "s1", model.ShipmentStatusBooked, model.TrackingStatusDelivered)
+	}}
+	service := NewTrackingService(racing, trackingRepository, nil, &recordingPublisher{})
+
+	// Act
+	_, err := service.Record(context.Background(), "s1", []model.TrackingEvent{postedEvt})
+
+	// Assert
+	assert.NoError(t, err)
+	shipment, _ := shipments.Get(context.Background(), "s1")
+	assert.Equal(t, model.TrackingStatusDelivered, shipment.Status)
+}
+
+func TestTrackingRecord_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		shipmentID string
+		events     []model.TrackingEvent
+		wantErr    error
+	}{
+		{"no events", "s1", nil, ErrInvalidTrackingEvent},
+		{"unknown status", "s1", []model.TrackingEvent{{Status: "lost", OccurredAt: postedAt}}, ErrInvalidTrackingEvent},
+		{"missing occurrence time", "s1", []model.TrackingEvent{{Status: model.TrackingStatusPosted}}, ErrInvalidTrackingEvent},
+		{"unknown shipment", "missing", []model.TrackingEvent{postedEvt}, ErrShipmentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service, _ := newTrackingService(t, nil, &recordingPublisher{})
+
+			// Act
+			result, err := service.Record(context.Background(), tt.shipmentID, tt.events)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestTrackingGet(t *testing.T) {
+	t.Run("booked shipment without events", func(t *testing.T) {
+		// Arrange
+		service, _ := newTrackingService(t, nil, &recordingPublisher{})
+
+		// Act
+		result, err := service.Get(context.Background(), "s1")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, &model.ShipmentTracking{ShipmentID: "s1", Status: model.ShipmentStatusBooked, Events: []model.TrackingEvent{}}, result)
+	})
+
+	t.Run("polls the carrier", func(t *testing.T) {
+		// Arrange
+		publisher := &recordingPublisher{}
+		service, _ := newTrackingService(t, stubTrackingProvider{events: []model.TrackingEvent{postedEvt}}, publisher)
+
+		// Act
+		result, err := service.Get(context.Background(), "s1")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, model.TrackingStatusPosted, result.Status)
+		assert.Equal(t, []model.TrackingEvent{postedEvt}, result.Events)
+		assert.Len(t, publisher.events, 1)
+	})
+
+	t.Run("unknown shipment", func(t *testing.T) {
+		// Arrange
+		service, _ := newTrackingService(t, nil, &recordingPublisher{})
+
+		// Act
+		result, err := service.Get(context.Background(), "missing")
+
+		// Assert
+		assert.ErrorIs(t, err, ErrShipmentNotFound)
+		assert.Nil(t, result)
+	})
+}
+
+func TestTrackingGet_PollingFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider stubTrackingProvider
+	}{
+		{"carrier unavailable", stubTrackingProvider{err: errors.New("connection refused")}},
+		{"invalid carrier event", stubTrackingProvider{events: []model.TrackingEvent{{Status: "lost", OccurredAt: postedAt}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			core, logs := observer.New(zapcore.WarnLevel)
+			ctx := logger.NewContext(context.Background(), zap.New(core))
+			service, _ := newTrackingService(t, tt.provider, &recordingPublisher{})
+			_, _ = service.Record(ctx, "s1", []model.TrackingEvent{postedEvt})
+
+			// Act
+			result, err := service.Get(ctx, "s1")
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, []model.TrackingEvent{postedEvt}, result.Events)
+			assert.Equal(t, 1, logs.FilterMessage("Falha ao consultar rastreamento na transportadora").Len())
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Operation outcomes recorded in the store metrics
+const (
+	OutcomeOK    = "ok"
+	OutcomeMiss  = "miss"
+	OutcomeError = "error"
+)
+
+// InstrumentedStore traces and measures every operation of the underlying store
+type InstrumentedStore struct {
+	next    QuoteStore
+	backend string
+	tracer  trace.Tracer
+	metrics *telemetry.Metrics
+}
+
+// Instrument wraps a store with a span of tracer and the store metrics per operation, labelled
+// with backend. A nil tracer or metrics records nothing
+func Instrument(next QuoteStore, backend string, tracer trace.Tracer, metrics *telemetry.Metrics) *InstrumentedStore {
+	if tracer == nil {
+		tracer = noop.NewTracerProvider().Tracer("")
+	}
+	return &InstrumentedStore{next: next, backend: backend, tracer: tracer, metrics: metrics}
+}
+
+// Put implements QuoteStore
+func (s *InstrumentedStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ctx, done := s.start(ctx, "put")
+	err := s.next.Put(ctx, key, value, ttl)
+	done(err)
+	return err
+}
+
+// PutIfAbsent implements QuoteStore
+func (s *InstrumentedStore) PutIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	ctx, done := s.start(ctx, "put_if_absent")
+	stored, err := s.next.PutIfAbsent(ctx, key, value, ttl)
+	done(err)
+	return stored, err
+}
+
+// Get implements QuoteStore
+func (s *InstrumentedStore) Get(ctx context.Context, key string) ([]byte, error) {
+	ctx, done := s.start(ctx, "get")
+	value, err := s.next.Get(ctx, key)
+	done(err)
+	return value, err
+}
+
+// Delete implements QuoteStore
+func (s *InstrumentedStore) Delete(ctx context.Context, key string) error {
+	ctx, done := s.start(ctx, "delete")
+	err := s.next.Delete(ctx, key)
+	done(err)
+	return err
+}
+
+// Ping implements Pinger when the underlying store does; stores without a server are always reachable
+func (s *InstrumentedStore) Ping(ctx context.Context) error {
+	if pinger, ok := s.next.(Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// start opens the span of an operation; the returned function ends it and records its outcome
+func (s *InstrumentedStore) start(ctx context.Context, operation string) (context.Context, func(error)) {
+	startTime := time.Now()
+	ctx, span := s.tracer.Start(ctx, "quote_store."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("store.backend", s.backend),
+			attribute.String("store.operation", operation),
+		),
+	)
+
+	return ctx, func(err error) {
+		outcome := OutcomeOK
+		switch {
+		case errors.Is(err, ErrNotFound):
+			outcome = OutcomeMiss
+		case err != nil:
+			outcome = OutcomeError
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.SetAttributes(attribute.String("store.outcome", outcome))
+		span.End()
+		s.metrics.RecordQuoteStoreOperation(ctx, s.backend, operation, outcome, time.Since(startTime).Milliseconds())
+	}
+}
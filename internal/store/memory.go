@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the expired keys that are never read again are removed
+const sweepInterval = time.Minute
+
+// memoryEntry is a stored value and its expiration; the zero time never expires
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryStore is an in-process QuoteStore, safe for concurrent use. Expired keys are removed
+// when read or overwritten, and the writes sweep every expired key at most once per sweepInterval,
+// so that the keys never read again do not grow the memory without bound
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+	// nextSweep is when the next write sweeps the expired keys
+	nextSweep time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// Put implements QuoteStore
+func (s *MemoryStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	now := s.now()
+	entry := memoryEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !now.Before(s.nextSweep) {
+		s.sweep(now)
+	}
+	s.entries[key] = entry
+	return nil
+}
+
+// PutIfAbsent implements QuoteStore
+func (s *MemoryStore) PutIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	now := s.now()
+	entry := memoryEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !now.Before(s.nextSweep) {
+		s.sweep(now)
+	}
+	if stored, ok := s.entries[key]; ok && (stored.expiresAt.IsZero() || now.Before(stored.expiresAt)) {
+		return false, nil
+	}
+	s.entries[key] = entry
+	return true, nil
+}
+
+// sweep removes the expired keys; the caller must hold mu
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, entry := range s.entries {
+		if !entry.expiresAt.IsZero() && !now.Before(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	s.nextSweep = now.Add(sweepInterval)
+}
+
+// Get implements QuoteStore
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if !entry.expiresAt.IsZero() && !s.now().Before(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), entry.value...), nil
+}
+
+// Delete implements QuoteStore
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
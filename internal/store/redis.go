@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisClient is the subset of redis.Client used by RedisStore
+type redisClient interface {
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+	Ping(ctx context.Context) *redis.StatusCmd
+	Close() error
+}
+
+// RedisStore is a QuoteStore backed by Redis, sharing the stored values between instances. Keys
+// are prefixed with cfg.KeyPrefix and expire with Redis TTLs
+type RedisStore struct {
+	client redisClient
+	prefix string
+}
+
+// NewRedisStore connects to the Redis at cfg.RedisAddr, failing when it does not answer a PING
+// within cfg.Timeout
+func NewRedisStore(ctx context.Context, cfg Config) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:         cfg.RedisAddr,
+		Password:     cfg.RedisPassword,
+		DB:           cfg.RedisDB,
+		DialTimeout:  cfg.Timeout,
+		ReadTimeout:  cfg.Timeout,
+		WriteTimeout: cfg.Timeout,
+	})
+
+	store := &RedisStore{client: client, prefix: cfg.KeyPrefix}
+	pingCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	if err := store.Ping(pingCtx); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", cfg.RedisAddr, err)
+	}
+	return store, nil
+}
+
+// Ping checks that Redis answers
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+// Put implements QuoteStore
+func (s *RedisStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := s.client.Set(ctx, s.prefix+key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store key %s in redis: %w", key, err)
+	}
+	return nil
+}
+
+// PutIfAbsent implements QuoteStore with SET NX
+func (s *RedisStore) PutIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	stored, err := s.client.SetNX(ctx, s.prefix+key, value, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to store key %s in redis: %w", key, err)
+	}
+	return stored, nil
+}
+
+// Get implements QuoteStore
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key %s from redis: %w", key, err)
+	}
+	return value, nil
+}
+
+// Delete implements QuoteStore
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, s.prefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to delete key %s from redis: %w", key, err)
+	}
+	return nil
+}
+
+// Close closes the connections to Redis
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
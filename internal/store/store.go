@@ -0,0 +1,118 @@
+// Package store provides the key-value storage shared by quote retrieval, idempotency and caching,
+// backed by memory or Redis.
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Supported backends
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// ErrNotFound is returned when the key does not exist or has expired
+var ErrNotFound = errors.New("key not found")
+
+// QuoteStore stores opaque values by key, each expiring after its own TTL
+type QuoteStore interface {
+	// Put stores the value under key, replacing any previous value; a ttl of 0 never expires
+	Put(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// PutIfAbsent stores the value under key unless the key exists, reporting whether it was
+	// stored. The check and the write are atomic, also across the instances sharing the store
+	PutIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// Get returns the value stored under key, or ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the key; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// Pinger is implemented by stores backed by a server, to check that it is reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Config selects and configures the store backend
+type Config struct {
+	// Backend is memory or redis
+	Backend       string
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+	// KeyPrefix namespaces the keys of this service in a shared Redis
+	KeyPrefix string
+	// Timeout bounds each Redis operation
+	Timeout time.Duration
+	// QuoteTTL is how long calculated quotes are kept for retrieval and revalidation
+	QuoteTTL time.Duration
+}
+
+// ConfigFromEnv reads QUOTE_STORE (default memory), REDIS_ADDR (host:port, required for redis),
+// REDIS_PASSWORD, REDIS_DB (default 0), QUOTE_STORE_KEY_PREFIX (default shipping:),
+// QUOTE_STORE_TIMEOUT (default 500ms) and QUOTE_STORE_TTL (default 24h)
+func ConfigFromEnv() (Config, error) {
+	db, err := config.Int("REDIS_DB", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	timeout, err := config.Duration("QUOTE_STORE_TIMEOUT", 500*time.Millisecond)
+	if err != nil {
+		return Config{}, err
+	}
+	quoteTTL, err := config.Duration("QUOTE_STORE_TTL", 24*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+	if db < 0 {
+		return Config{}, fmt.Errorf("REDIS_DB must not be negative")
+	}
+	if timeout <= 0 || quoteTTL <= 0 {
+		return Config{}, fmt.Errorf("QUOTE_STORE_TIMEOUT and QUOTE_STORE_TTL must be positive")
+	}
+
+	cfg := Config{
+		Backend:       strings.ToLower(config.String("QUOTE_STORE", BackendMemory)),
+		RedisAddr:     config.String("REDIS_ADDR", ""),
+		RedisPassword: config.String("REDIS_PASSWORD", ""),
+		RedisDB:       db,
+		KeyPrefix:     config.String("QUOTE_STORE_KEY_PREFIX", "shipping:"),
+		Timeout:       timeout,
+		QuoteTTL:      quoteTTL,
+	}
+	switch cfg.Backend {
+	case BackendMemory:
+	case BackendRedis:
+		if cfg.RedisAddr == "" {
+			return Config{}, fmt.Errorf("REDIS_ADDR is required for the redis store")
+		}
+	default:
+		return Config{}, fmt.Errorf("QUOTE_STORE must be one of %s, %s, got %q", BackendMemory, BackendRedis, cfg.Backend)
+	}
+	return cfg, nil
+}
+
+// New creates the store of the configured backend, instrumented with the traces of tracer and
+// metrics. Redis
+// stores implement Pinger. The returned function closes the connection to the backend and must be
+// called on shutdown
+func New(ctx context.Context, cfg Config, tracer trace.Tracer, metrics *telemetry.Metrics) (QuoteStore, func() error, error) {
+	switch cfg.Backend {
+	case BackendRedis:
+		store, err := NewRedisStore(ctx, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return Instrument(store, BackendRedis, tracer, metrics), store.Close, nil
+	default:
+		return Instrument(NewMemoryStore(), BackendMemory, tracer, metrics), func() error { return nil }, nil
+	}
+}
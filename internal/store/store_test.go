@@ -0,0 +1,352 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// fakeRedis is an in-memory redisClient recording the TTL of every key
+type fakeRedis struct {
+	values map[string]string
+	ttls   map[string]time.Duration
+	err    error
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{values: map[string]string{}, ttls: map[string]time.Duration{}}
+}
+
+func (f *fakeRedis) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	if f.err != nil {
+		return redis.NewStatusResult("", f.err)
+	}
+	f.values[key] = string(value.([]byte))
+	f.ttls[key] = expiration
+	return redis.NewStatusResult("OK", nil)
+}
+
+func (f *fakeRedis) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	if f.err != nil {
+		return redis.NewBoolResult(false, f.err)
+	}
+	if _, ok := f.values[key]; ok {
+		return redis.NewBoolResult(false, nil)
+	}
+	f.values[key] = string(value.([]byte))
+	f.ttls[key] = expiration
+	return redis.NewBoolResult(true, nil)
+}
+
+func (f *fakeRedis) Get(ctx context.Context, key string) *redis.StringCmd {
+	if f.err != nil {
+		return redis.NewStringResult("", f.err)
+	}
+	value, ok := f.values[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(value, nil)
+}
+
+func (f *fakeRedis) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	if f.err != nil {
+		return redis.NewIntResult(0, f.err)
+	}
+	var deleted int64
+	for _, key := range keys {
+		if _, ok := f.values[key]; ok {
+			delete(f.values, key)
+			deleted++
+		}
+	}
+	return redis.NewIntResult(deleted, nil)
+}
+
+func (f *fakeRedis) Ping(ctx context.Context) *redis.StatusCmd {
+	if f.err != nil {
+		return redis.NewStatusResult("", f.err)
+	}
+	return redis.NewStatusResult("PONG", nil)
+}
+
+func (f *fakeRedis) Close() error {
+	return nil
+}
+
+func TestMemoryStore(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	s := NewMemoryStore()
+	value := []byte("quote")
+
+	// Act
+	putErr := s.Put(ctx, "q1", value, 0)
+	value[0] = 'Q'
+	got, getErr := s.Get(ctx, "q1")
+	deleteErr := s.Delete(ctx, "q1")
+	_, missingErr := s.Get(ctx, "q1")
+
+	// Assert
+	assert.NoError(t, putErr)
+	assert.NoError(t, getErr)
+	assert.Equal(t, []byte("quote"), got)
+	assert.NoError(t, deleteErr)
+	assert.ErrorIs(t, missingErr, ErrNotFound)
+	assert.NoError(t, s.Delete(ctx, "q1"))
+}
+
+func TestMemoryStore_TTL(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	_ = s.Put(ctx, "short", []byte("a"), time.Minute)
+	_ = s.Put(ctx, "forever", []byte("b"), 0)
+
+	// Act
+	_, beforeErr := s.Get(ctx, "short")
+	now = now.Add(time.Minute)
+	_, expiredErr := s.Get(ctx, "short")
+	_, foreverErr := s.Get(ctx, "forever")
+
+	// Assert
+	assert.NoError(t, beforeErr)
+	assert.ErrorIs(t, expiredErr, ErrNotFound)
+	assert.NoError(t, foreverErr)
+	assert.NotContains(t, s.entries, "short")
+}
+
+func TestMemoryStore_SweepsExpiredKeys(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	_ = s.Put(ctx, "short", []byte("a"), time.Second)
+	_ = s.Put(ctx, "long", []byte("b"), time.Hour)
+	_ = s.Put(ctx, "forever", []byte("c"), 0)
+
+	// Act
+	now = now.Add(30 * time.Second)
+	_ = s.Put(ctx, "before-sweep", []byte("d"), time.Second)
+	keysBeforeSweep := len(s.entries)
+	now = now.Add(sweepInterval)
+	_ = s.Put(ctx, "after-sweep", []byte("e"), time.Second)
+
+	// Assert
+	assert.Equal(t, 4, keysBeforeSweep, "expired keys are kept until the next sweep")
+	assert.NotContains(t, s.entries, "short", "expired keys are removed without being read")
+	assert.NotContains(t, s.entries, "before-sweep")
+	assert.Contains(t, s.entries, "long")
+	assert.Contains(t, s.entries, "forever")
+	assert.Contains(t, s.entries, "after-sweep")
+}
+
+func TestMemoryStore_PutIfAbsent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	// Act
+	first, firstErr := s.PutIfAbsent(ctx, "k1", []byte("a"), time.Minute)
+	second, secondErr := s.PutIfAbsent(ctx, "k1", []byte("b"), time.Minute)
+	now = now.Add(time.Minute)
+	afterExpiry, afterExpiryErr := s.PutIfAbsent(ctx, "k1", []byte("c"), time.Minute)
+	got, _ := s.Get(ctx, "k1")
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	assert.NoError(t, afterExpiryErr)
+	assert.True(t, first)
+	assert.False(t, second, "an existing key is kept")
+	assert.True(t, afterExpiry, "an expired key is replaced")
+	assert.Equal(t, []byte("c"), got)
+}
+
+func TestRedisStore(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	client := newFakeRedis()
+	s := &RedisStore{client: client, prefix: "shipping:"}
+
+	// Act
+	putErr := s.Put(ctx, "quote:q1", []byte("quote"), time.Hour)
+	got, getErr := s.Get(ctx, "quote:q1")
+	deleteErr := s.Delete(ctx, "quote:q1")
+	_, missingErr := s.Get(ctx, "quote:q1")
+	claimed, claimErr := s.PutIfAbsent(ctx, "seen:s1", []byte("1"), time.Minute)
+	reclaimed, reclaimErr := s.PutIfAbsent(ctx, "seen:s1", []byte("1"), time.Minute)
+
+	// Assert
+	assert.NoError(t, putErr)
+	assert.Equal(t, time.Hour, client.ttls["shipping:quote:q1"])
+	assert.NoError(t, getErr)
+	assert.Equal(t, []byte("quote"), got)
+	assert.NoError(t, deleteErr)
+	assert.ErrorIs(t, missingErr, ErrNotFound)
+	assert.NoError(t, claimErr)
+	assert.NoError(t, reclaimErr)
+	assert.True(t, claimed)
+	assert.False(t, reclaimed)
+	assert.Equal(t, time.Minute, client.ttls["shipping:seen:s1"])
+}
+
+func TestRedisStore_Errors(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	client := newFakeRedis()
+	client.err = errors.New("connection refused")
+	s := &RedisStore{client: client, prefix: "shipping:"}
+
+	// Act
+	putErr := s.Put(ctx, "q1", []byte("quote"), time.Hour)
+	_, getErr := s.Get(ctx, "q1")
+	deleteErr := s.Delete(ctx, "q1")
+	_, claimErr := s.PutIfAbsent(ctx, "q1", []byte("quote"), time.Hour)
+
+	// Assert
+	assert.ErrorContains(t, putErr, "failed to store key q1 in redis: connection refused")
+	assert.ErrorContains(t, claimErr, "failed to store key q1 in redis: connection refused")
+	assert.ErrorContains(t, getErr, "failed to load key q1 from redis: connection refused")
+	assert.NotErrorIs(t, getErr, ErrNotFound)
+	assert.ErrorContains(t, deleteErr, "failed to delete key q1 from redis: connection refused")
+}
+
+func TestInstrumentedStore_Ping(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	down := newFakeRedis()
+	down.err = errors.New("connection refused")
+
+	// Act
+	memoryErr := Instrument(NewMemoryStore(), BackendMemory, nil, nil).Ping(ctx)
+	upErr := Instrument(&RedisStore{client: newFakeRedis()}, BackendRedis, nil, nil).Ping(ctx)
+	downErr := Instrument(&RedisStore{client: down}, BackendRedis, nil, nil).Ping(ctx)
+
+	// Assert
+	assert.NoError(t, memoryErr)
+	assert.NoError(t, upErr)
+	assert.ErrorContains(t, downErr, "connection refused")
+}
+
+func TestInstrumentedStore(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	spans := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)).Tracer("test")
+	s := Instrument(NewMemoryStore(), BackendMemory, tracer, nil)
+
+	// Act
+	putErr := s.Put(ctx, "q1", []byte("quote"), time.Hour)
+	got, getErr := s.Get(ctx, "q1")
+	deleteErr := s.Delete(ctx, "q1")
+	_, missingErr := s.Get(ctx, "q1")
+
+	// Assert
+	assert.NoError(t, putErr)
+	assert.NoError(t, getErr)
+	assert.Equal(t, []byte("quote"), got)
+	assert.NoError(t, deleteErr)
+	assert.ErrorIs(t, missingErr, ErrNotFound)
+	var names []string
+	for _, span := range spans.Ended() {
+		names = append(names, span.Name())
+	}
+	assert.Equal(t, []string{"quote_store.put", "quote_store.get", "quote_store.delete", "quote_store.get"}, names)
+}
+
+func TestNew_Memory(t *testing.T) {
+	// Arrange
+	cfg := Config{Backend: BackendMemory}
+
+	// Act
+	s, closeStore, err := New(context.Background(), cfg, nil, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.IsType(t, &InstrumentedStore{}, s)
+	assert.NoError(t, closeStore())
+}
+
+func TestNew_RedisUnreachable(t *testing.T) {
+	// Arrange
+	cfg := Config{Backend: BackendRedis, RedisAddr: "127.0.0.1:1", Timeout: 100 * time.Millisecond}
+
+	// Act
+	_, _, err := New(context.Background(), cfg, nil, nil)
+
+	// Assert
+	assert.ErrorContains(t, err, "failed to connect to redis at 127.0.0.1:1")
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{},
+			want: Config{Backend: BackendMemory, KeyPrefix: "shipping:", Timeout: 500 * time.Millisecond, QuoteTTL: 24 * time.Hour},
+		},
+		{
+			name: "redis",
+			env: map[string]string{
+				"QUOTE_STORE":            "Redis",
+				"REDIS_ADDR":             "redis:6379",
+				"REDIS_PASSWORD":         "secret",
+				"REDIS_DB":               "2",
+				"QUOTE_STORE_KEY_PREFIX": "quotes:",
+				"QUOTE_STORE_TIMEOUT":    "1s",
+				"QUOTE_STORE_TTL":        "72h",
+			},
+			want: Config{
+				Backend:       BackendRedis,
+				RedisAddr:     "redis:6379",
+				RedisPassword: "secret",
+				RedisDB:       2,
+				KeyPrefix:     "quotes:",
+				Timeout:       time.Second,
+				QuoteTTL:      72 * time.Hour,
+			},
+		},
+		{name: "redis without address", env: map[string]string{"QUOTE_STORE": "redis"}, wantErr: "REDIS_ADDR is required"},
+		{name: "unknown backend", env: map[string]string{"QUOTE_STORE": "memcached"}, wantErr: `QUOTE_STORE must be one of memory, redis, got "memcached"`},
+		{name: "negative database", env: map[string]string{"REDIS_DB": "-1"}, wantErr: "REDIS_DB must not be negative"},
+		{name: "invalid timeout", env: map[string]string{"QUOTE_STORE_TIMEOUT": "0s"}, wantErr: "must be positive"},
+		{name: "invalid ttl", env: map[string]string{"QUOTE_STORE_TTL": "forever"}, wantErr: "QUOTE_STORE_TTL must be a duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for _, key := range []string{"QUOTE_STORE", "REDIS_ADDR", "REDIS_PASSWORD", "REDIS_DB", "QUOTE_STORE_KEY_PREFIX", "QUOTE_STORE_TIMEOUT", "QUOTE_STORE_TTL"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Package strictjson decodes JSON request bodies rejecting unknown fields, reporting the path of
+// the offending field and the closest known field, so that a typo such as "weigth" is not
+// silently ignored and priced as a zero weight.
+package strictjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// maxSuggestionDistance is the largest edit distance between an unknown field and the known field
+// suggested for it
+const maxSuggestionDistance = 2
+
+// UnknownFieldError reports a field of the body that the target type does not declare
+type UnknownFieldError struct {
+	// Field is the path of the field, e.g. "weigth" or "dimensions.lenght"
+	Field string
+	// Suggestion is the path of the closest known field, empty when none is close enough
+	Suggestion string
+}
+
+func (e *UnknownFieldError) Error() string {
+	if e.Suggestion == "" {
+		return fmt.Sprintf("unknown field %q", e.Field)
+	}
+	return fmt.Sprintf("unknown field %q, did you mean %q?", e.Field, e.Suggestion)
+}
+
+type strictKey struct{}
+
+// NewContext returns a context whose request bodies are decoded in strict mode when strict is true
+func NewContext(ctx context.Context, strict bool) context.Context {
+	return context.WithValue(ctx, strictKey{}, strict)
+}
+
+// FromContext reports whether request bodies of ctx are decoded in strict mode
+func FromContext(ctx context.Context) bool {
+	strict, _ := ctx.Value(strictKey{}).(bool)
+	return strict
+}
+
+// Decode decodes the JSON value of r into v, returning an *UnknownFieldError for the first field,
+// in document order of each object, that v does not declare. Field names match case-insensitively,
+// like encoding/json
+func Decode(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	return check(data, reflect.TypeOf(v), "")
+}
+
+// check walks the JSON value data against type t, looking for unknown object fields
+func check(data []byte, t reflect.Type, path string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		// Structs are checked even with a custom unmarshaler, which decodes their own fields;
+		// those encoded as other JSON values, like time.Time, are not objects and are skipped
+		keys, values, err := objectFields(data)
+		if err != nil || keys == nil {
+			return err
+		}
+		fields := jsonFields(t)
+		for i, key := range keys {
+			field, ok := lookup(fields, key)
+			if !ok {
+				return &UnknownFieldError{Field: join(path, key), Suggestion: suggest(fields, key, path)}
+			}
+			if err := check(values[i], field.typ, join(path, field.name)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return nil
+		}
+		for i, item := range items {
+			if err := check(item, t.Elem(), path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		var entries map[string]json.RawMessage
+		if json.Unmarshal(data, &entries) != nil {
+			return nil
+		}
+		for key, value := range entries {
+			if err := check(value, t.Elem(), join(path, key)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// field is a JSON field of a struct
+type field struct {
+	name string
+	typ  reflect.Type
+}
+
+// jsonFields lists the JSON fields of struct type t, including those of embedded structs
+func jsonFields(t reflect.Type) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, jsonFields(embedded)...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, field{name: name, typ: f.Type})
+	}
+	return fields
+}
+
+// lookup finds the field named key, preferring an exact match over a case-insensitive one
+func lookup(fields []field, key string) (field, bool) {
+	for _, f := range fields {
+		if f.name == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// suggest returns the path of the known field closest to key, or "" when none is within
+// maxSuggestionDistance edits
+func suggest(fields []field, key, path string) string {
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, f := range fields {
+		if d := distance(strings.ToLower(key), strings.ToLower(f.name)); d < bestDistance {
+			best, bestDistance = f.name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return join(path, best)
+}
+
+// distance is the optimal string alignment distance between a and b: the number of insertions,
+// deletions, substitutions and transpositions of adjacent characters that turn a into b
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// objectFields returns the keys of the JSON object data in document order with their values, or
+// nil keys when data is not an object
+func objectFields(data []byte) ([]string, []json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	token, err := dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, nil, nil
+	}
+	keys, values := []string{}, []json.RawMessage{}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		keys, values = append(keys, token.(string)), append(values, value)
+	}
+	return keys, values, nil
+}
+
+// join appends key to the path of its parent object
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,142 @@
+package strictjson
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type dimensions struct {
+	Length float64 `json:"length"`
+	Width  float64 `json:"width"`
+}
+
+type audit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type request struct {
+	audit
+	Weight     float64               `json:"weight"`
+	Dimensions dimensions            `json:"dimensions"`
+	Items      []*dimensions         `json:"items,omitempty"`
+	Labels     map[string]dimensions `json:"labels,omitempty"`
+	Ignored    string                `json:"-"`
+	Untagged   bool
+	internal   string
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		wantField      string
+		wantSuggestion string
+	}{
+		{"known fields", `{"weight": 1, "dimensions": {"length": 2}, "items": [{"width": 3}], "Untagged": true, "created_at": "2025-03-10T15:00:00Z"}`, "", ""},
+		{"case-insensitive match", `{"WEIGHT": 1, "untagged": true}`, "", ""},
+		{"transposed letters", `{"weigth": 1}`, "weigth", "weight"},
+		{"nested field", `{"dimensions": {"lenght": 2}}`, "dimensions.lenght", "dimensions.length"},
+		{"field of a slice item", `{"items": [{"width": 1}, {"widht": 2}]}`, "items[1].widht", "items[1].width"},
+		{"field of a map value", `{"labels": {"box": {"lengt": 1}}}`, "labels.box.lengt", "labels.box.length"},
+		{"no close field", `{"pickup_instructions": "ring twice"}`, "pickup_instructions", ""},
+		{"ignored field", `{"Ignored": "x"}`, "Ignored", ""},
+		{"unexported field", `{"internal": "x"}`, "internal", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var got request
+
+			// Act
+			err := Decode(strings.NewReader(tt.body), &got)
+
+			// Assert
+			if tt.wantField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var unknown *UnknownFieldError
+			if assert.ErrorAs(t, err, &unknown) {
+				assert.Equal(t, tt.wantField, unknown.Field)
+				assert.Equal(t, tt.wantSuggestion, unknown.Suggestion)
+			}
+		})
+	}
+}
+
+func TestDecode_Values(t *testing.T) {
+	// Arrange
+	var got request
+
+	// Act
+	err := Decode(strings.NewReader(`{"weight": 1.5, "dimensions": {"length": 20}}`), &got)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, got.Weight)
+	assert.Equal(t, 20.0, got.Dimensions.Length)
+}
+
+func TestDecode_InvalidJSON(t *testing.T) {
+	// Arrange
+	var got request
+
+	// Act
+	err := Decode(strings.NewReader(`{"weight": "heavy"}`), &got)
+
+	// Assert
+	assert.Error(t, err)
+	var unknown *UnknownFieldError
+	assert.NotErrorAs(t, err, &unknown)
+}
+
+func TestUnknownFieldError(t *testing.T) {
+	tests := []struct {
+		err  *UnknownFieldError
+		want string
+	}{
+		{&UnknownFieldError{Field: "weigth", Suggestion: "weight"}, `unknown field "weigth", did you mean "weight"?`},
+		{&UnknownFieldError{Field: "notes"}, `unknown field "notes"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.want, tt.err.Error())
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"weight", "weight", 0},
+		{"weigth", "weight", 1},
+		{"wieght", "weight", 1},
+		{"weght", "weight", 1},
+		{"lenght", "length", 1},
+		{"height", "weight", 1},
+		{"", "abc", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.want, distance(tt.a, tt.b))
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	// Act & Assert
+	assert.False(t, FromContext(context.Background()))
+	assert.True(t, FromContext(NewContext(context.Background(), true)))
+	assert.False(t, FromContext(NewContext(context.Background(), false)))
+}
@@ -0,0 +1,415 @@
+// Package subscription keeps the quotes of registered routes and packages up to date with the
+// pricing configuration in force, for clients following live freight prices by long polling.
+// Subscriptions are kept in the quote store, so that any instance sharing it serves their polls.
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"go.uber.org/zap"
+)
+
+const (
+	// pruneInterval is how often expired subscriptions are removed
+	pruneInterval = time.Minute
+	// recheckInterval is how often a waiting poll reads its subscription from the store, to see
+	// the changes published by other instances
+	recheckInterval = time.Second
+	// keyPrefix prefixes the subscription keys in the quote store
+	keyPrefix = "price-subscription:"
+)
+
+var (
+	// ErrNotFound is returned for unknown, expired or other tenants' subscriptions
+	ErrNotFound = errors.New("subscription not found")
+	// ErrLimitReached is returned by Subscribe when MaxSubscriptions are active
+	ErrLimitReached = errors.New("subscription limit reached")
+)
+
+// Config configures the price subscriptions
+type Config struct {
+	// MaxSubscriptions is how many subscriptions each instance may follow at once; 0 disables
+	// subscriptions
+	MaxSubscriptions int
+	// TTL is how long a subscription is kept after it was created or last polled
+	TTL time.Duration
+	// MaxWait bounds how long a poll waits for a price change
+	MaxWait time.Duration
+}
+
+// Enabled reports whether subscriptions are accepted
+func (c Config) Enabled() bool {
+	return c.MaxSubscriptions > 0
+}
+
+// ConfigFromEnv reads PRICE_SUBSCRIPTION_MAX (default 1000), PRICE_SUBSCRIPTION_TTL (default 10m)
+// and PRICE_SUBSCRIPTION_MAX_WAIT (default 30s)
+func ConfigFromEnv() (Config, error) {
+	maxSubscriptions, err := config.Int("PRICE_SUBSCRIPTION_MAX", 1000)
+	if err != nil {
+		return Config{}, err
+	}
+	if maxSubscriptions < 0 {
+		return Config{}, fmt.Errorf("PRICE_SUBSCRIPTION_MAX must not be negative, got %d", maxSubscriptions)
+	}
+	ttl, err := config.Duration("PRICE_SUBSCRIPTION_TTL", 10*time.Minute)
+	if err != nil {
+		return Config{}, err
+	}
+	if ttl <= 0 {
+		return Config{}, fmt.Errorf("PRICE_SUBSCRIPTION_TTL must be positive, got %s", ttl)
+	}
+	maxWait, err := config.Duration("PRICE_SUBSCRIPTION_MAX_WAIT", 30*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if maxWait <= 0 || maxWait >= ttl {
+		return Config{}, fmt.Errorf("PRICE_SUBSCRIPTION_MAX_WAIT must be positive and below PRICE_SUBSCRIPTION_TTL, got %s", maxWait)
+	}
+	return Config{MaxSubscriptions: maxSubscriptions, TTL: ttl, MaxWait: maxWait}, nil
+}
+
+// record is a registered route and package with its last quote, as kept in the quote store
+type record struct {
+	Tenant    string                           `json:"tenant"`
+	Request   model.CalculateShippingRequest   `json:"request"`
+	Version   int                              `json:"version"`
+	Quote     *model.CalculateShippingResponse `json:"quote"`
+	ExpiresAt time.Time                        `json:"expires_at"`
+}
+
+func (r *record) snapshot(id string) model.PriceSubscription {
+	return model.PriceSubscription{ID: id, Version: r.Version, ExpiresAt: r.ExpiresAt, Quote: r.Quote}
+}
+
+// subscription is a subscription followed by this instance, since it was created or polled here
+type subscription struct {
+	expiresAt time.Time
+	// changed is closed, and replaced, when this instance increases the version
+	changed chan struct{}
+}
+
+// Hub holds the subscriptions in the quote store and reprices the ones it follows when triggered.
+// It is safe for concurrent use
+type Hub struct {
+	cfg        Config
+	calculator service.ShippingServiceInterface
+	store      store.QuoteStore
+	logger     *zap.Logger
+	now        func() time.Time
+	trigger    chan struct{}
+
+	mu            sync.Mutex
+	subscriptions map[string]*subscription
+}
+
+// New creates a hub keeping the subscriptions in quoteStore and pricing them with calculator
+func New(cfg Config, calculator service.ShippingServiceInterface, quoteStore store.QuoteStore, logger *zap.Logger) *Hub {
+	return &Hub{
+		cfg:           cfg,
+		calculator:    calculator,
+		store:         quoteStore,
+		logger:        logger,
+		now:           time.Now,
+		trigger:       make(chan struct{}, 1),
+		subscriptions: make(map[string]*subscription),
+	}
+}
+
+// MaxWait returns the longest a poll may wait
+func (h *Hub) MaxWait() time.Duration {
+	return h.cfg.MaxWait
+}
+
+// Subscribe prices req as a dry run, with the tenant of ctx, and registers it for updates. It
+// returns the calculation error of an invalid request, or ErrLimitReached
+func (h *Hub) Subscribe(ctx context.Context, req *model.CalculateShippingRequest) (model.PriceSubscription, error) {
+	if !h.hasRoom() {
+		return model.PriceSubscription{}, ErrLimitReached
+	}
+	dryRun, _ := service.WithDryRun(ctx)
+	quote, err := h.calculator.CalculateShipping(dryRun, req)
+	if err != nil {
+		return model.PriceSubscription{}, err
+	}
+
+	id := uuid.NewString()
+	rec := &record{
+		Tenant:    tenant.FromContext(ctx),
+		Request:   *req,
+		Version:   1,
+		Quote:     quote,
+		ExpiresAt: h.now().Add(h.cfg.TTL),
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.subscriptions) >= h.cfg.MaxSubscriptions {
+		return model.PriceSubscription{}, ErrLimitReached
+	}
+	if err := h.save(ctx, id, rec); err != nil {
+		return model.PriceSubscription{}, err
+	}
+	h.subscriptions[id] = &subscription{expiresAt: rec.ExpiresAt, changed: make(chan struct{})}
+	return rec.snapshot(id), nil
+}
+
+// hasRoom reports whether a subscription may be added, removing the expired ones when full
+func (h *Hub) hasRoom() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.subscriptions) < h.cfg.MaxSubscriptions {
+		return true
+	}
+	h.pruneLocked()
+	return len(h.subscriptions) < h.cfg.MaxSubscriptions
+}
+
+// Poll returns the subscription as soon as its version is above since, waiting up to wait (capped
+// at MaxWait) or until ctx is done; the returned version equals since when the price did not
+// change. Every poll renews the subscription for TTL, and this instance follows it from then on
+func (h *Hub) Poll(ctx context.Context, id string, since int, wait time.Duration) (model.PriceSubscription, error) {
+	rec, err := h.load(ctx, id)
+	if err != nil {
+		return model.PriceSubscription{}, err
+	}
+	rec.ExpiresAt = h.now().Add(h.cfg.TTL)
+	if err := h.save(ctx, id, rec); err != nil {
+		return model.PriceSubscription{}, err
+	}
+	changed := h.follow(id, rec.ExpiresAt)
+
+	if rec.Version > since || wait <= 0 {
+		return rec.snapshot(id), nil
+	}
+	timer := time.NewTimer(min(wait, h.cfg.MaxWait))
+	defer timer.Stop()
+	recheck := time.NewTicker(recheckInterval)
+	defer recheck.Stop()
+	for {
+		select {
+		case <-changed:
+		case <-recheck.C:
+		case <-timer.C:
+			return rec.snapshot(id), nil
+		case <-ctx.Done():
+			return rec.snapshot(id), nil
+		}
+		current, err := h.load(ctx, id)
+		if err != nil {
+			// Unsubscribed, or the store failed, while waiting: the last read is still current
+			return rec.snapshot(id), nil
+		}
+		if current.Version > since {
+			return current.snapshot(id), nil
+		}
+		changed = h.follow(id, rec.ExpiresAt)
+	}
+}
+
+// follow records that this instance follows a subscription, returning its change channel
+func (h *Hub) follow(id string, expiresAt time.Time) chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sub, ok := h.subscriptions[id]
+	if !ok {
+		sub = &subscription{changed: make(chan struct{})}
+		h.subscriptions[id] = sub
+	}
+	if expiresAt.After(sub.expiresAt) {
+		sub.expiresAt = expiresAt
+	}
+	return sub.changed
+}
+
+// Unsubscribe removes a subscription
+func (h *Hub) Unsubscribe(ctx context.Context, id string) error {
+	if _, err := h.load(ctx, id); err != nil {
+		return err
+	}
+	if err := h.store.Delete(ctx, keyPrefix+id); err != nil {
+		return fmt.Errorf("failed to delete subscription %s: %w", id, err)
+	}
+	h.mu.Lock()
+	delete(h.subscriptions, id)
+	h.mu.Unlock()
+	return nil
+}
+
+// load returns an active subscription of the tenant of ctx from the store
+func (h *Hub) load(ctx context.Context, id string) (*record, error) {
+	rec, err := h.read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rec.Tenant != tenant.FromContext(ctx) {
+		return nil, ErrNotFound
+	}
+	return rec, nil
+}
+
+// read returns an active subscription of any tenant from the store
+func (h *Hub) read(ctx context.Context, id string) (*record, error) {
+	data, err := h.store.Get(ctx, keyPrefix+id)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription %s: %w", id, err)
+	}
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode subscription %s: %w", id, err)
+	}
+	if !h.now().Before(rec.ExpiresAt) {
+		return nil, ErrNotFound
+	}
+	return &rec, nil
+}
+
+// save writes a subscription to the store, to expire when it does
+func (h *Hub) save(ctx context.Context, id string, rec *record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode subscription %s: %w", id, err)
+	}
+	if err := h.store.Put(ctx, keyPrefix+id, data, rec.ExpiresAt.Sub(h.now())); err != nil {
+		return fmt.Errorf("failed to save subscription %s: %w", id, err)
+	}
+	return nil
+}
+
+// pruneLocked stops following the expired subscriptions. The caller holds h.mu
+func (h *Hub) pruneLocked() {
+	now := h.now()
+	for id, sub := range h.subscriptions {
+		if !now.Before(sub.expiresAt) {
+			delete(h.subscriptions, id)
+		}
+	}
+}
+
+// Trigger requests the subscriptions to be repriced, e.g. after the pricing configuration or the
+// fuel surcharge index changed. It does not block; requests made while one is pending are merged
+func (h *Hub) Trigger() {
+	select {
+	case h.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run reprices the subscriptions on every Trigger and removes the expired ones periodically,
+// until ctx is cancelled
+func (h *Hub) Run(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.trigger:
+			h.Refresh(ctx)
+		case <-ticker.C:
+			h.mu.Lock()
+			h.pruneLocked()
+			h.mu.Unlock()
+		}
+	}
+}
+
+// Refresh reprices, as a dry run, every subscription this instance follows and publishes the
+// quotes whose prices changed to the store and the polls, returning how many changed and how many
+// failed to be priced. A quote with the same prices is kept, even if priced with another pricing
+// version, so instances following the same subscription do not bump its version twice
+func (h *Hub) Refresh(ctx context.Context) (changed, failed int) {
+	h.mu.Lock()
+	h.pruneLocked()
+	pending := make([]string, 0, len(h.subscriptions))
+	for id := range h.subscriptions {
+		pending = append(pending, id)
+	}
+	h.mu.Unlock()
+
+	for _, id := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		rec, err := h.read(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			h.mu.Lock()
+			delete(h.subscriptions, id)
+			h.mu.Unlock()
+			continue
+		}
+		if err != nil {
+			failed++
+			h.logger.Warn("Failed to load price subscription", zap.String("subscription_id", id), zap.Error(err))
+			continue
+		}
+		dryRun, _ := service.WithDryRun(tenant.NewContext(ctx, rec.Tenant))
+		request := rec.Request
+		quote, err := h.calculator.CalculateShipping(dryRun, &request)
+		if err != nil {
+			failed++
+			h.logger.Warn("Failed to reprice price subscription", zap.String("subscription_id", id), zap.Error(err))
+			continue
+		}
+		if samePrices(rec.Quote, quote) {
+			continue
+		}
+
+		// Re-read the subscription so that a poll renewing it, or another instance repricing it,
+		// meanwhile is not undone
+		if current, err := h.read(ctx, id); err == nil {
+			rec = current
+		}
+		if samePrices(rec.Quote, quote) {
+			continue
+		}
+		rec.Version++
+		rec.Quote = quote
+		if err := h.save(ctx, id, rec); err != nil {
+			failed++
+			h.logger.Warn("Failed to save price subscription", zap.String("subscription_id", id), zap.Error(err))
+			continue
+		}
+		h.mu.Lock()
+		if sub, ok := h.subscriptions[id]; ok {
+			close(sub.changed)
+			sub.changed = make(chan struct{})
+		}
+		h.mu.Unlock()
+		changed++
+	}
+	h.logger.Info("Price subscriptions repriced",
+		zap.Int("subscriptions", len(pending)),
+		zap.Int("changed", changed),
+		zap.Int("failed", failed),
+	)
+	return changed, failed
+}
+
+// samePrices reports whether two quotes offer the same services at the same costs
+func samePrices(a, b *model.CalculateShippingResponse) bool {
+	if a.ShippingCost != b.ShippingCost || a.Currency != b.Currency || len(a.ShippingOptions) != len(b.ShippingOptions) {
+		return false
+	}
+	for i, option := range a.ShippingOptions {
+		other := b.ShippingOptions[i]
+		if option.Service != other.Service || option.Cost != other.Cost {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,308 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+var request = model.CalculateShippingRequest{
+	OriginZipcode:      "01310100",
+	DestinationZipcode: "20040020",
+	Weight:             2.5,
+	Dimensions:         model.PackageDimensions{Length: 30, Width: 20, Height: 15},
+}
+
+// priceCalculator quotes a standard option at its current cost, recording the tenants and dry runs
+type priceCalculator struct {
+	mu      sync.Mutex
+	cost    money.Amount
+	version string
+	err     error
+	tenants []string
+	dryRuns []bool
+}
+
+func (c *priceCalculator) CalculateShipping(ctx context.Context, req *model.CalculateShippingRequest) (*model.CalculateShippingResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tenants = append(c.tenants, tenant.FromContext(ctx))
+	c.dryRuns = append(c.dryRuns, service.IsDryRun(ctx))
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &model.CalculateShippingResponse{
+		PricingVersion:  c.version,
+		ShippingCost:    c.cost,
+		ShippingOptions: []model.ShippingOption{{Service: model.ServiceStandard, Cost: c.cost}},
+	}, nil
+}
+
+func (c *priceCalculator) set(cost money.Amount, version string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cost, c.version = cost, version
+}
+
+func newHub(t *testing.T, calculator *priceCalculator, maxSubscriptions int) *Hub {
+	return New(Config{MaxSubscriptions: maxSubscriptions, TTL: time.Minute, MaxWait: time.Second}, calculator, store.NewMemoryStore(), zaptest.NewLogger(t))
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{"defaults", map[string]string{}, Config{MaxSubscriptions: 1000, TTL: 10 * time.Minute, MaxWait: 30 * time.Second}, ""},
+		{
+			"custom values",
+			map[string]string{"PRICE_SUBSCRIPTION_MAX": "0", "PRICE_SUBSCRIPTION_TTL": "1h", "PRICE_SUBSCRIPTION_MAX_WAIT": "45s"},
+			Config{TTL: time.Hour, MaxWait: 45 * time.Second},
+			"",
+		},
+		{"negative max", map[string]string{"PRICE_SUBSCRIPTION_MAX": "-1"}, Config{}, "PRICE_SUBSCRIPTION_MAX"},
+		{"zero ttl", map[string]string{"PRICE_SUBSCRIPTION_TTL": "0s"}, Config{}, "PRICE_SUBSCRIPTION_TTL"},
+		{"wait above ttl", map[string]string{"PRICE_SUBSCRIPTION_TTL": "1m", "PRICE_SUBSCRIPTION_MAX_WAIT": "2m"}, Config{}, "PRICE_SUBSCRIPTION_MAX_WAIT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for _, key := range []string{"PRICE_SUBSCRIPTION_MAX", "PRICE_SUBSCRIPTION_TTL", "PRICE_SUBSCRIPTION_MAX_WAIT"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestHub_Subscribe(t *testing.T) {
+	// Arrange
+	calculator := &priceCalculator{cost: money.FromMinor(1500)}
+	hub := newHub(t, calculator, 1)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	// Act
+	update, err := hub.Subscribe(ctx, &request)
+	_, limitErr := hub.Subscribe(ctx, &request)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, update.ID)
+	assert.Equal(t, 1, update.Version)
+	assert.Equal(t, money.FromMinor(1500), update.Quote.ShippingCost)
+	assert.ErrorIs(t, limitErr, ErrLimitReached)
+	assert.Equal(t, []bool{true}, calculator.dryRuns, "the quote is not persisted")
+}
+
+func TestHub_Subscribe_InvalidRequest(t *testing.T) {
+	// Arrange
+	calculator := &priceCalculator{err: errors.New("invalid weight")}
+	hub := newHub(t, calculator, 1)
+
+	// Act
+	_, err := hub.Subscribe(context.Background(), &request)
+
+	// Assert
+	assert.EqualError(t, err, "invalid weight")
+	assert.Empty(t, hub.subscriptions)
+}
+
+func TestHub_Subscribe_ReplacesExpired(t *testing.T) {
+	// Arrange
+	calculator := &priceCalculator{cost: money.FromMinor(1500)}
+	hub := newHub(t, calculator, 1)
+	now := time.Now()
+	hub.now = func() time.Time { return now }
+	expired, _ := hub.Subscribe(context.Background(), &request)
+	now = now.Add(2 * time.Minute)
+
+	// Act
+	update, err := hub.Subscribe(context.Background(), &request)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEqual(t, expired.ID, update.ID)
+	assert.Len(t, hub.subscriptions, 1)
+}
+
+func TestHub_Poll(t *testing.T) {
+	t.Run("changed price", func(t *testing.T) {
+		// Arrange
+		calculator := &priceCalculator{cost: money.FromMinor(1500), version: "v1"}
+		hub := newHub(t, calculator, 10)
+		subscribed, _ := hub.Subscribe(context.Background(), &request)
+		polled := make(chan model.PriceSubscription, 1)
+		go func() {
+			update, _ := hub.Poll(context.Background(), subscribed.ID, subscribed.Version, time.Minute)
+			polled <- update
+		}()
+
+		// Act
+		calculator.set(money.FromMinor(1800), "v2")
+		changed, failed := hub.Refresh(context.Background())
+
+		// Assert
+		assert.Equal(t, 1, changed)
+		assert.Zero(t, failed)
+		select {
+		case update := <-polled:
+			assert.Equal(t, 2, update.Version)
+			assert.Equal(t, money.FromMinor(1800), update.Quote.ShippingCost)
+		case <-time.After(time.Second):
+			t.Fatal("poll was not woken by the price change")
+		}
+	})
+
+	t.Run("unchanged price", func(t *testing.T) {
+		// Arrange
+		calculator := &priceCalculator{cost: money.FromMinor(1500), version: "v1"}
+		hub := newHub(t, calculator, 10)
+		subscribed, _ := hub.Subscribe(context.Background(), &request)
+		calculator.set(money.FromMinor(1500), "v2")
+		changed, _ := hub.Refresh(context.Background())
+
+		// Act
+		update, err := hub.Poll(context.Background(), subscribed.ID, subscribed.Version, 10*time.Millisecond)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Zero(t, changed)
+		assert.Equal(t, 1, update.Version)
+		assert.Equal(t, "v1", update.Quote.PricingVersion, "a quote with the same prices is kept")
+	})
+
+	t.Run("behind the current version", func(t *testing.T) {
+		// Arrange
+		calculator := &priceCalculator{cost: money.FromMinor(1500)}
+		hub := newHub(t, calculator, 10)
+		subscribed, _ := hub.Subscribe(context.Background(), &request)
+
+		// Act
+		update, err := hub.Poll(context.Background(), subscribed.ID, 0, time.Minute)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, update.Version)
+	})
+
+	t.Run("other tenant", func(t *testing.T) {
+		// Arrange
+		calculator := &priceCalculator{cost: money.FromMinor(1500)}
+		hub := newHub(t, calculator, 10)
+		subscribed, _ := hub.Subscribe(tenant.NewContext(context.Background(), "acme"), &request)
+
+		// Act
+		_, err := hub.Poll(tenant.NewContext(context.Background(), "globex"), subscribed.ID, 0, 0)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		// Arrange
+		calculator := &priceCalculator{cost: money.FromMinor(1500)}
+		hub := newHub(t, calculator, 10)
+		now := time.Now()
+		hub.now = func() time.Time { return now }
+		subscribed, _ := hub.Subscribe(context.Background(), &request)
+		now = now.Add(2 * time.Minute)
+
+		// Act
+		_, err := hub.Poll(context.Background(), subscribed.ID, 0, 0)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestHub_SharedStore(t *testing.T) {
+	// Arrange
+	calculator := &priceCalculator{cost: money.FromMinor(1500), version: "v1"}
+	quoteStore := store.NewMemoryStore()
+	cfg := Config{MaxSubscriptions: 10, TTL: time.Minute, MaxWait: 5 * time.Second}
+	creator := New(cfg, calculator, quoteStore, zaptest.NewLogger(t))
+	other := New(cfg, calculator, quoteStore, zaptest.NewLogger(t))
+	subscribed, _ := creator.Subscribe(context.Background(), &request)
+	polled := make(chan model.PriceSubscription, 1)
+
+	// Act
+	current, err := other.Poll(context.Background(), subscribed.ID, 0, 0)
+	go func() {
+		update, _ := other.Poll(context.Background(), subscribed.ID, subscribed.Version, 5*time.Second)
+		polled <- update
+	}()
+	calculator.set(money.FromMinor(1800), "v2")
+	changed, _ := creator.Refresh(context.Background())
+
+	// Assert
+	assert.NoError(t, err, "another instance serves the subscription")
+	assert.Equal(t, subscribed.Version, current.Version)
+	assert.Equal(t, 1, changed)
+	select {
+	case update := <-polled:
+		assert.Equal(t, 2, update.Version)
+		assert.Equal(t, money.FromMinor(1800), update.Quote.ShippingCost)
+	case <-time.After(3 * time.Second):
+		t.Fatal("poll on another instance did not see the price change")
+	}
+	assert.NoError(t, other.Unsubscribe(context.Background(), subscribed.ID))
+	_, err = creator.Poll(context.Background(), subscribed.ID, 0, 0)
+	assert.ErrorIs(t, err, ErrNotFound, "unsubscribing on any instance removes it")
+}
+
+func TestHub_Refresh(t *testing.T) {
+	// Arrange
+	calculator := &priceCalculator{cost: money.FromMinor(1500)}
+	hub := newHub(t, calculator, 10)
+	subscribed, _ := hub.Subscribe(tenant.NewContext(context.Background(), "acme"), &request)
+	calculator.err = errors.New("carrier unavailable")
+
+	// Act
+	changed, failed := hub.Refresh(context.Background())
+	update, _ := hub.Poll(tenant.NewContext(context.Background(), "acme"), subscribed.ID, 0, 0)
+
+	// Assert
+	assert.Zero(t, changed)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, []string{"acme", "acme"}, calculator.tenants, "subscriptions are repriced with their tenant")
+	assert.Equal(t, money.FromMinor(1500), update.Quote.ShippingCost, "the last quote is kept on failures")
+}
+
+func TestHub_Unsubscribe(t *testing.T) {
+	// Arrange
+	calculator := &priceCalculator{cost: money.FromMinor(1500)}
+	hub := newHub(t, calculator, 10)
+	subscribed, _ := hub.Subscribe(context.Background(), &request)
+
+	// Act
+	err := hub.Unsubscribe(context.Background(), subscribed.ID)
+	_, pollErr := hub.Poll(context.Background(), subscribed.ID, 0, 0)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.ErrorIs(t, pollErr, ErrNotFound)
+	assert.ErrorIs(t, hub.Unsubscribe(context.Background(), subscribed.ID), ErrNotFound)
+}
@@ -0,0 +1,215 @@
+// Package tax computes the taxes embedded in the freight of Brazilian routes: ICMS, charged by the
+// states on interstate and intrastate transport, and ISS, charged by the municipalities on
+// transport within their limits.
+package tax
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+)
+
+// Country is the destination country whose routes are taxed
+const Country = "BR"
+
+// Taxes on freight
+const (
+	ICMS = "ICMS"
+	ISS  = "ISS"
+)
+
+// Municipality is a range of CEPs of a municipality; transport within it pays ISS instead of ICMS
+type Municipality struct {
+	// Name identifies the municipality in the computation, e.g. "sao-paulo"
+	Name string `json:"name"`
+	// From and To are the first and last CEPs of the municipality, inclusive
+	From string `json:"from"`
+	To   string `json:"to"`
+	// ISSRate is the ISS rate of the municipality on transport services
+	ISSRate float64 `json:"iss_rate"`
+}
+
+// Contains reports whether a zipcode is within the municipality
+func (m Municipality) Contains(cep string) bool {
+	normalized := zipcode.Normalize(cep)
+	return len(normalized) == zipcode.Length && normalized >= m.From && normalized <= m.To
+}
+
+// Config holds the ICMS rates by route and the ISS rates of the municipalities
+type Config struct {
+	// IntrastateRate is the ICMS rate of routes within a state without a rate in IntrastateRates
+	IntrastateRate float64 `json:"intrastate_rate"`
+	// IntrastateRates maps states, e.g. "RJ", to the ICMS rate of routes within them
+	IntrastateRates map[string]float64 `json:"intrastate_rates,omitempty"`
+	// InterstateRate is the ICMS rate of routes between states
+	InterstateRate float64 `json:"interstate_rate"`
+	// ReducedInterstateRate is the ICMS rate of routes from the South and Southeast, except
+	// Espírito Santo, to the North, Northeast, Midwest and Espírito Santo
+	ReducedInterstateRate float64 `json:"reduced_interstate_rate"`
+	// Municipalities are the CEP ranges whose internal routes pay ISS
+	Municipalities []Municipality `json:"municipalities,omitempty"`
+}
+
+// DefaultConfig returns simplified ICMS rates: 12% within a state and between states, and 7% from
+// the South and Southeast (except Espírito Santo) to the North, Northeast, Midwest and Espírito
+// Santo. No municipality is configured, so no route pays ISS
+func DefaultConfig() Config {
+	return Config{
+		IntrastateRate:        0.12,
+		InterstateRate:        0.12,
+		ReducedInterstateRate: 0.07,
+	}
+}
+
+// Validate checks that the rates are fractions and the states and CEP ranges are valid
+func (c Config) Validate() error {
+	for _, rate := range []float64{c.IntrastateRate, c.InterstateRate, c.ReducedInterstateRate} {
+		if rate < 0 || rate >= 1 {
+			return fmt.Errorf("intrastate_rate, interstate_rate and reduced_interstate_rate must be between 0 and 1, got %g", rate)
+		}
+	}
+	for state, rate := range c.IntrastateRates {
+		if !zipcode.IsState(state) {
+			return fmt.Errorf("intrastate_rates: unknown state %q", state)
+		}
+		if rate < 0 || rate >= 1 {
+			return fmt.Errorf("intrastate_rates: rate of %s must be between 0 and 1, got %g", state, rate)
+		}
+	}
+	names := make(map[string]bool, len(c.Municipalities))
+	for i, municipality := range c.Municipalities {
+		if municipality.Name == "" {
+			return fmt.Errorf("municipalities[%d]: name is required", i)
+		}
+		if names[municipality.Name] {
+			return fmt.Errorf("municipalities[%d]: duplicate municipality %q", i, municipality.Name)
+		}
+		names[municipality.Name] = true
+		for _, cep := range []string{municipality.From, municipality.To} {
+			if len(cep) != zipcode.Length || !zipcode.Numeric(cep) {
+				return fmt.Errorf("municipality %q: %q is not an 8-digit CEP", municipality.Name, cep)
+			}
+		}
+		if municipality.From > municipality.To {
+			return fmt.Errorf("municipality %q: from %s is after to %s", municipality.Name, municipality.From, municipality.To)
+		}
+		if municipality.ISSRate < 0 || municipality.ISSRate >= 1 {
+			return fmt.Errorf("municipality %q: iss_rate must be between 0 and 1, got %g", municipality.Name, municipality.ISSRate)
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads and validates the tax rates from a JSON file
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read tax config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse tax config: %w", err)
+	}
+	cfg = cfg.normalized()
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid tax config: %w", err)
+	}
+	return cfg, nil
+}
+
+// normalized upper-cases the states and normalizes the CEPs of the municipalities
+func (c Config) normalized() Config {
+	out := c
+	if c.IntrastateRates != nil {
+		out.IntrastateRates = make(map[string]float64, len(c.IntrastateRates))
+		for state, rate := range c.IntrastateRates {
+			out.IntrastateRates[strings.ToUpper(strings.TrimSpace(state))] = rate
+		}
+	}
+	out.Municipalities = nil
+	for _, municipality := range c.Municipalities {
+		municipality.Name = strings.TrimSpace(municipality.Name)
+		municipality.From = zipcode.Normalize(municipality.From)
+		municipality.To = zipcode.Normalize(municipality.To)
+		out.Municipalities = append(out.Municipalities, municipality)
+	}
+	return out
+}
+
+// Computation is the tax embedded in a freight value. Gross is the freight charged, Amount the tax
+// it includes and Net the freight without the tax
+type Computation struct {
+	Tax              string
+	Rate             float64
+	OriginState      string
+	DestinationState string
+	// Municipality is set for ISS, the municipality of the route
+	Municipality string
+	Gross        money.Amount
+	Amount       money.Amount
+	Net          money.Amount
+}
+
+// Calculator computes the taxes of routes with the rates of its configuration
+type Calculator struct {
+	cfg Config
+}
+
+// NewCalculator creates a calculator for the rates of cfg
+func NewCalculator(cfg Config) *Calculator {
+	return &Calculator{cfg: cfg}
+}
+
+// Compute returns the tax included in the gross freight of a route between Brazilian zipcodes: ISS
+// when both are within the same configured municipality, otherwise the ICMS of the route. It
+// reports false when the state of a zipcode cannot be resolved
+func (c *Calculator) Compute(originZipcode, destinationZipcode string, gross money.Amount) (Computation, bool) {
+	origin, destination := zipcode.State(originZipcode), zipcode.State(destinationZipcode)
+	if origin == "" || destination == "" {
+		return Computation{}, false
+	}
+
+	computation := Computation{Tax: ICMS, OriginState: origin, DestinationState: destination, Gross: gross}
+	switch municipality, ok := c.municipalityOf(originZipcode, destinationZipcode); {
+	case ok:
+		computation.Tax, computation.Rate, computation.Municipality = ISS, municipality.ISSRate, municipality.Name
+	case origin == destination:
+		computation.Rate = c.cfg.IntrastateRate
+		if rate, ok := c.cfg.IntrastateRates[origin]; ok {
+			computation.Rate = rate
+		}
+	case reducedRoute(origin, destination):
+		computation.Rate = c.cfg.ReducedInterstateRate
+	default:
+		computation.Rate = c.cfg.InterstateRate
+	}
+	// Both taxes are calculated "por dentro": the rate applies to the gross freight, tax included
+	computation.Amount = gross.MulRate(computation.Rate)
+	computation.Net = gross - computation.Amount
+	return computation, true
+}
+
+// municipalityOf returns the configured municipality containing both zipcodes
+func (c *Calculator) municipalityOf(originZipcode, destinationZipcode string) (Municipality, bool) {
+	for _, municipality := range c.cfg.Municipalities {
+		if municipality.Contains(originZipcode) && municipality.Contains(destinationZipcode) {
+			return municipality, true
+		}
+	}
+	return Municipality{}, false
+}
+
+// reducedRoute reports whether the reduced interstate rate applies: from the South and Southeast,
+// except Espírito Santo, to the North, Northeast, Midwest and Espírito Santo
+func reducedRoute(origin, destination string) bool {
+	originRegion := zipcode.StateMacroRegion(origin)
+	if origin == "ES" || (originRegion != zipcode.RegionSouth && originRegion != zipcode.RegionSoutheast) {
+		return false
+	}
+	destinationRegion := zipcode.StateMacroRegion(destination)
+	return destination == "ES" || (destinationRegion != zipcode.RegionSouth && destinationRegion != zipcode.RegionSoutheast)
+}
@@ -0,0 +1,126 @@
+package tax
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.IntrastateRates = map[string]float64{"RJ": 0.20}
+	cfg.Municipalities = []Municipality{{Name: "sao-paulo", From: "01000000", To: "05999999", ISSRate: 0.05}}
+	return cfg
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name             string
+		origin           string
+		destination      string
+		wantTax          string
+		wantRate         float64
+		wantStates       [2]string
+		wantMunicipality string
+		wantAmount       float64
+	}{
+		{"within a municipality", "01310-100", "04547-130", ISS, 0.05, [2]string{"SP", "SP"}, "sao-paulo", 100},
+		{"within a state", "01310-100", "13010-000", ICMS, 0.12, [2]string{"SP", "SP"}, "", 240},
+		{"within a state with its own rate", "20040-020", "24020-000", ICMS, 0.20, [2]string{"RJ", "RJ"}, "", 400},
+		{"between states", "01310-100", "20040-020", ICMS, 0.12, [2]string{"SP", "RJ"}, "", 240},
+		{"from the southeast to the northeast", "01310-100", "40010-000", ICMS, 0.07, [2]string{"SP", "BA"}, "", 140},
+		{"from the south to espirito santo", "80010-000", "29010-000", ICMS, 0.07, [2]string{"PR", "ES"}, "", 140},
+		{"from espirito santo to the north", "29010-000", "66010-000", ICMS, 0.12, [2]string{"ES", "PA"}, "", 240},
+		{"from the northeast to the southeast", "40010-000", "01310-100", ICMS, 0.12, [2]string{"BA", "SP"}, "", 240},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			calculator := NewCalculator(testConfig())
+
+			// Act
+			computation, ok := calculator.Compute(tt.origin, tt.destination, money.FromMinor(2000))
+
+			// Assert
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantTax, computation.Tax)
+			assert.Equal(t, tt.wantRate, computation.Rate)
+			assert.Equal(t, tt.wantStates, [2]string{computation.OriginState, computation.DestinationState})
+			assert.Equal(t, tt.wantMunicipality, computation.Municipality)
+			assert.Equal(t, money.FromMinor(2000), computation.Gross)
+			assert.Equal(t, money.FromMinor(tt.wantAmount), computation.Amount)
+			assert.Equal(t, computation.Gross-computation.Amount, computation.Net)
+		})
+	}
+}
+
+func TestCompute_UnknownState(t *testing.T) {
+	// Arrange
+	calculator := NewCalculator(DefaultConfig())
+
+	// Act
+	_, ok := calculator.Compute("00000-000", "01310-100", money.FromMinor(2000))
+
+	// Assert
+	assert.False(t, ok)
+}
+
+func TestValidate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *Config)
+		wantErr string
+	}{
+		{"percentage as a whole number", func(cfg *Config) { cfg.InterstateRate = 12 }, "must be between 0 and 1"},
+		{"negative rate", func(cfg *Config) { cfg.ReducedInterstateRate = -0.07 }, "must be between 0 and 1"},
+		{"unknown state", func(cfg *Config) { cfg.IntrastateRates = map[string]float64{"XX": 0.1} }, "unknown state"},
+		{"missing municipality name", func(cfg *Config) { cfg.Municipalities = []Municipality{{From: "01000000", To: "05999999"}} }, "name is required"},
+		{"partial CEP", func(cfg *Config) {
+			cfg.Municipalities = []Municipality{{Name: "sao-paulo", From: "01", To: "05999999"}}
+		}, "not an 8-digit CEP"},
+		{"inverted range", func(cfg *Config) {
+			cfg.Municipalities = []Municipality{{Name: "sao-paulo", From: "05999999", To: "01000000"}}
+		}, "is after"},
+		{"duplicate municipality", func(cfg *Config) {
+			cfg.Municipalities = []Municipality{{Name: "a", From: "01000000", To: "05999999"}, {Name: "a", From: "20000000", To: "23799999"}}
+		}, "duplicate municipality"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := DefaultConfig()
+			tt.modify(&cfg)
+
+			// Act
+			err := cfg.Validate()
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "tax.json")
+	content := `{
+		"intrastate_rate": 0.12,
+		"intrastate_rates": {"rj": 0.2},
+		"interstate_rate": 0.12,
+		"reduced_interstate_rate": 0.07,
+		"municipalities": [{"name": " sao-paulo ", "from": "01000-000", "to": "05999-999", "iss_rate": 0.05}]
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, testConfig(), cfg)
+}
@@ -0,0 +1,164 @@
+// Package tenant identifies the marketplace a request is quoted for. Each tenant is priced with
+// its own pricing configuration; requests without a tenant belong to the default tenant.
+package tenant
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+)
+
+// Default is the tenant of requests that do not identify one, priced with PRICING_CONFIG_PATH
+const Default = "default"
+
+// idPattern restricts tenant IDs to values safe as metric attributes and log fields
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+type tenantKey struct{}
+
+type apiKeyIDKey struct{}
+
+// NewContext returns a context carrying the tenant ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant ID of ctx, or Default
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(tenantKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
+
+// KeyID identifies an API key in usage reports and logs without revealing it: "sha256:" followed
+// by the first 12 hexadecimal digits of the SHA-256 of the key
+func KeyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "sha256:" + hex.EncodeToString(sum[:])[:12]
+}
+
+// NewAPIKeyContext returns a context carrying the KeyID of the API key the tenant was
+// authenticated with
+func NewAPIKeyContext(ctx context.Context, keyID string) context.Context {
+	return context.WithValue(ctx, apiKeyIDKey{}, keyID)
+}
+
+// APIKeyFromContext returns the KeyID of the API key of ctx, or "" for requests without a key of
+// their tenant
+func APIKeyFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(apiKeyIDKey{}).(string)
+	return id
+}
+
+// Tenant is a marketplace quoted with its own rates
+type Tenant struct {
+	// PricingConfigPath is the pricing configuration of the tenant, in the format of
+	// PRICING_CONFIG_PATH: rate tables, service levels, strategies and price limits
+	PricingConfigPath string `json:"pricing_config_path"`
+	// APIKeys identify the tenant in the X-API-Key header. A tenant with API keys can only be
+	// selected with one of them
+	APIKeys []string `json:"api_keys,omitempty"`
+	// MonthlyQuota is how many quote requests the tenant can make per calendar month (UTC);
+	// 0 is unlimited
+	MonthlyQuota int64 `json:"monthly_quota,omitempty"`
+}
+
+// Config holds the tenants besides Default, by ID
+type Config struct {
+	Tenants map[string]Tenant `json:"tenants"`
+}
+
+// LoadConfig reads the tenants from a JSON file
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read tenants config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse tenants config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid tenants config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Validate checks the tenant IDs, that every tenant has a pricing configuration and that no API
+// key is shared by two tenants
+func (c Config) Validate() error {
+	owners := make(map[string]string)
+	for _, id := range c.IDs() {
+		tenant := c.Tenants[id]
+		if id == Default {
+			return fmt.Errorf("tenant %q is reserved for requests without a tenant", Default)
+		}
+		if !idPattern.MatchString(id) {
+			return fmt.Errorf("tenant %q: id must be lowercase letters, digits, '-' or '_'", id)
+		}
+		if tenant.PricingConfigPath == "" {
+			return fmt.Errorf("tenant %q: pricing_config_path is required", id)
+		}
+		if tenant.MonthlyQuota < 0 {
+			return fmt.Errorf("tenant %q: monthly_quota must not be negative, got %d", id, tenant.MonthlyQuota)
+		}
+		for _, key := range tenant.APIKeys {
+			if key == "" {
+				return fmt.Errorf("tenant %q: api_keys must not be empty", id)
+			}
+			if owner, ok := owners[key]; ok {
+				return fmt.Errorf("tenant %q: an API key is already used by tenant %q", id, owner)
+			}
+			owners[key] = id
+		}
+	}
+	return nil
+}
+
+// IDs returns the configured tenant IDs, sorted
+func (c Config) IDs() []string {
+	ids := make([]string, 0, len(c.Tenants))
+	for id := range c.Tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Known reports whether id is Default or a configured tenant
+func (c Config) Known(id string) bool {
+	_, ok := c.Tenants[id]
+	return ok || id == Default
+}
+
+// MonthlyQuota returns the monthly quota of the tenant, 0 when unlimited. The default tenant is
+// unlimited
+func (c Config) MonthlyQuota(id string) int64 {
+	return c.Tenants[id].MonthlyQuota
+}
+
+// RequiresAPIKey reports whether the tenant can only be selected with one of its API keys
+func (c Config) RequiresAPIKey(id string) bool {
+	return len(c.Tenants[id].APIKeys) > 0
+}
+
+// ForAPIKey returns the tenant an API key belongs to. Every key is compared in constant time, so
+// the response time does not reveal which one matched
+func (c Config) ForAPIKey(key string) (string, bool) {
+	found := ""
+	for _, id := range c.IDs() {
+		for _, candidate := range c.Tenants[id].APIKeys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(candidate)) == 1 {
+				found = id
+			}
+		}
+	}
+	return found, found != ""
+}
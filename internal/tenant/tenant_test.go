@@ -0,0 +1,96 @@
+package tenant
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	// Act & Assert
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, "marketplace-b", FromContext(NewContext(context.Background(), "marketplace-b")))
+	assert.Equal(t, "", APIKeyFromContext(context.Background()))
+	assert.Equal(t, "sha256:abc", APIKeyFromContext(NewAPIKeyContext(context.Background(), "sha256:abc")))
+}
+
+func TestKeyID(t *testing.T) {
+	// Act
+	id := KeyID("key-b")
+
+	// Assert
+	assert.Regexp(t, `^sha256:[0-9a-f]{12}$`, id)
+	assert.Equal(t, id, KeyID("key-b"))
+	assert.NotEqual(t, id, KeyID("key-c"))
+}
+
+func TestLoadConfig(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "tenants.json")
+	content := `{"tenants": {"marketplace-b": {"pricing_config_path": "b.json", "api_keys": ["key-b"]}, "outlet": {"pricing_config_path": "outlet.json"}}}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	cfg, err := LoadConfig(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"marketplace-b", "outlet"}, cfg.IDs())
+	assert.Equal(t, Tenant{PricingConfigPath: "b.json", APIKeys: []string{"key-b"}}, cfg.Tenants["marketplace-b"])
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid json", `{"tenants": [`},
+		{"reserved id", `{"tenants": {"default": {"pricing_config_path": "b.json"}}}`},
+		{"invalid id", `{"tenants": {"Marketplace B": {"pricing_config_path": "b.json"}}}`},
+		{"missing pricing", `{"tenants": {"marketplace-b": {}}}`},
+		{"empty api key", `{"tenants": {"marketplace-b": {"pricing_config_path": "b.json", "api_keys": [""]}}}`},
+		{"negative quota", `{"tenants": {"marketplace-b": {"pricing_config_path": "b.json", "monthly_quota": -1}}}`},
+		{"shared api key", `{"tenants": {"a": {"pricing_config_path": "a.json", "api_keys": ["key"]}, "b": {"pricing_config_path": "b.json", "api_keys": ["key"]}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), "tenants.json")
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			// Act
+			_, err := LoadConfig(path)
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConfig_Lookups(t *testing.T) {
+	// Arrange
+	cfg := Config{Tenants: map[string]Tenant{
+		"marketplace-b": {PricingConfigPath: "b.json", APIKeys: []string{"key-b1", "key-b2"}},
+		"outlet":        {PricingConfigPath: "outlet.json", MonthlyQuota: 1000},
+	}}
+
+	// Act & Assert
+	assert.Equal(t, int64(1000), cfg.MonthlyQuota("outlet"))
+	assert.Zero(t, cfg.MonthlyQuota("marketplace-b"))
+	assert.Zero(t, cfg.MonthlyQuota(Default))
+	assert.True(t, cfg.Known(Default))
+	assert.True(t, cfg.Known("outlet"))
+	assert.False(t, cfg.Known("marketplace-c"))
+	assert.True(t, cfg.RequiresAPIKey("marketplace-b"))
+	assert.False(t, cfg.RequiresAPIKey("outlet"))
+
+	id, ok := cfg.ForAPIKey("key-b2")
+	assert.True(t, ok)
+	assert.Equal(t, "marketplace-b", id)
+	_, ok = cfg.ForAPIKey("key-c")
+	assert.False(t, ok)
+}
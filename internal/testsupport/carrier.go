@@ -0,0 +1,222 @@
+// Package testsupport provides fake carrier APIs for the contract tests of the provider
+// implementations: canned responses recorded from the carrier sandboxes, error modes and latency,
+// and golden files of the requests the providers send. It is only imported by tests.
+package testsupport
+
+import (
+	"bytes"
+	"embed"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+	"time"
+)
+
+//go:embed fixtures
+var fixtures embed.FS
+
+// Paths served by the fake carrier APIs, as the providers request them
+const (
+	CarrierRatesPath   = "/rates"
+	TrackingEventsPath = "/shipments/{id}/events"
+	LabelsPath         = "/labels"
+	ManifestsPath      = "/manifests"
+	ZipcodePath        = "/ws/{zipcode}/json/"
+)
+
+// Fixture returns the canned response fixtures/name, failing the test when it does not exist
+func Fixture(t testing.TB, name string) []byte {
+	t.Helper()
+	content, err := fixtures.ReadFile(path.Join("fixtures", name))
+	if err != nil {
+		t.Fatalf("testsupport: fixture %s: %v", name, err)
+	}
+	return content
+}
+
+// Request is a request received by a CarrierServer
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// Fault breaks the responses of a CarrierServer
+type Fault struct {
+	// Latency delays the responses, or until the client gives up
+	Latency time.Duration
+	// Status answers with this status code and Body instead of the canned response
+	Status int
+	Body   []byte
+	// Malformed answers 200 OK with a truncated JSON body
+	Malformed bool
+	// Reset closes the connection without answering
+	Reset bool
+	// Times is how many requests are affected; 0 affects every request until Heal
+	Times int
+}
+
+// CarrierServer is a fake carrier API. It records the requests received and answers them with the
+// handlers registered, unless a Fault is set. It is safe for concurrent use
+type CarrierServer struct {
+	*httptest.Server
+
+	mux      *http.ServeMux
+	mu       sync.Mutex
+	fault    *Fault
+	requests []Request
+}
+
+// NewCarrierServer starts a fake carrier API without routes, closed when the test ends
+func NewCarrierServer(t testing.TB) *CarrierServer {
+	t.Helper()
+	s := &CarrierServer{mux: http.NewServeMux()}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// NewCarrierRatesServer starts a fake carrier rate API quoting fixtures/rates.json at
+// CarrierRatesPath
+func NewCarrierRatesServer(t testing.TB) *CarrierServer {
+	t.Helper()
+	s := NewCarrierServer(t)
+	s.Respond(http.MethodPost+" "+CarrierRatesPath, http.StatusOK, Fixture(t, "rates.json"))
+	return s
+}
+
+// NewTrackingServer starts a fake tracking API answering every shipment with
+// fixtures/tracking_events.json at TrackingEventsPath
+func NewTrackingServer(t testing.TB) *CarrierServer {
+	t.Helper()
+	s := NewCarrierServer(t)
+	s.Respond(http.MethodGet+" "+TrackingEventsPath, http.StatusOK, Fixture(t, "tracking_events.json"))
+	return s
+}
+
+// NewLabelServer starts a fake label API generating fixtures/label.json at LabelsPath
+func NewLabelServer(t testing.TB) *CarrierServer {
+	t.Helper()
+	s := NewCarrierServer(t)
+	s.Respond(http.MethodPost+" "+LabelsPath, http.StatusCreated, Fixture(t, "label.json"))
+	return s
+}
+
+// NewManifestServer starts a fake manifest API accepting manifests with fixtures/manifest.json at
+// ManifestsPath
+func NewManifestServer(t testing.TB) *CarrierServer {
+	t.Helper()
+	s := NewCarrierServer(t)
+	s.Respond(http.MethodPost+" "+ManifestsPath, http.StatusOK, Fixture(t, "manifest.json"))
+	return s
+}
+
+// NewZipcodeServer starts a fake ViaCEP API at ZipcodePath answering the zipcodes with a fixture
+// in fixtures/viacep/{zipcode}.json, and the others as ViaCEP answers unknown zipcodes
+func NewZipcodeServer(t testing.TB) *CarrierServer {
+	t.Helper()
+	s := NewCarrierServer(t)
+	notFound := Fixture(t, "viacep/not_found.json")
+	s.Handle(http.MethodGet+" "+ZipcodePath, func(w http.ResponseWriter, r *http.Request) {
+		body, err := fixtures.ReadFile(path.Join("fixtures", "viacep", r.PathValue("zipcode")+".json"))
+		if err != nil {
+			body = notFound
+		}
+		writeBody(w, http.StatusOK, body)
+	})
+	return s
+}
+
+// Handle registers handler for pattern, an http.ServeMux pattern such as "GET /shipments/{id}/events"
+func (s *CarrierServer) Handle(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// Respond registers a canned JSON response for pattern
+func (s *CarrierServer) Respond(pattern string, status int, body []byte) {
+	s.Handle(pattern, func(w http.ResponseWriter, r *http.Request) {
+		writeBody(w, status, body)
+	})
+}
+
+// Fail breaks the next responses with fault, replacing the previous fault
+func (s *CarrierServer) Fail(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fault = &fault
+}
+
+// Heal removes the fault
+func (s *CarrierServer) Heal() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fault = nil
+}
+
+// Requests returns the requests received, in arrival order
+func (s *CarrierServer) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// serve records the request and answers it with the fault or the handler of its route
+func (s *CarrierServer) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone(), Body: body})
+	var fault *Fault
+	if s.fault != nil {
+		current := *s.fault
+		fault = &current
+		if s.fault.Times > 0 {
+			if s.fault.Times--; s.fault.Times == 0 {
+				s.fault = nil
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if fault == nil {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+	if fault.Latency > 0 {
+		timer := time.NewTimer(fault.Latency)
+		defer timer.Stop()
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+		}
+	}
+	switch {
+	case fault.Reset:
+		if hijacker, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				_ = conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	case fault.Malformed:
+		writeBody(w, http.StatusOK, []byte(`{"`))
+	case fault.Status != 0:
+		writeBody(w, fault.Status, fault.Body)
+	default:
+		s.mux.ServeHTTP(w, r)
+	}
+}
+
+// writeBody answers with a JSON body
+func writeBody(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
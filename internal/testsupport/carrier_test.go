@@ -0,0 +1,125 @@
+package testsupport
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0, ""
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestCarrierServer_Fault(t *testing.T) {
+	// Arrange
+	server := NewTrackingServer(t)
+	server.Fail(Fault{Status: http.StatusServiceUnavailable, Body: []byte(`{"error":"maintenance"}`), Times: 2})
+	url := server.URL + "/shipments/s1/events"
+
+	// Act
+	var statuses []int
+	for range 3 {
+		status, _ := get(t, url)
+		statuses = append(statuses, status)
+	}
+
+	// Assert
+	assert.Equal(t, []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK}, statuses)
+	assert.Len(t, server.Requests(), 3)
+}
+
+func TestCarrierServer_Heal(t *testing.T) {
+	// Arrange
+	server := NewManifestServer(t)
+	server.Fail(Fault{Reset: true})
+	status, _ := get(t, server.URL+ManifestsPath)
+	assert.Zero(t, status, "the connection is closed without a response")
+
+	// Act
+	server.Heal()
+	resp, err := http.Post(server.URL+ManifestsPath, "application/json", strings.NewReader(`{"manifest_id":"m1"}`))
+
+	// Assert
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	requests := server.Requests()
+	assert.Len(t, requests, 2)
+	assert.Equal(t, `{"manifest_id":"m1"}`, string(requests[1].Body))
+}
+
+func TestZipcodeServer(t *testing.T) {
+	// Arrange
+	server := NewZipcodeServer(t)
+
+	// Act
+	_, known := get(t, server.URL+"/ws/01310100/json/")
+	_, unknown := get(t, server.URL+"/ws/99999999/json/")
+
+	// Assert
+	assert.Contains(t, known, "Avenida Paulista")
+	assert.JSONEq(t, `{"erro": "true"}`, unknown)
+}
+
+// recordingTB records the errors reported by AssertGolden
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, format)
+}
+
+func TestAssertGolden(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "request.golden.json")
+	assert.NoError(t, os.WriteFile(path, []byte("{\n  \"cost\": 1830\n}\n"), 0o644))
+
+	tests := []struct {
+		name      string
+		got       string
+		wantError bool
+	}{
+		{"same document formatted differently", `{"cost":1830}`, false},
+		{"different document", `{"cost":1831}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &recordingTB{TB: t}
+
+			// Act
+			AssertGolden(recorder, path, []byte(tt.got))
+
+			// Assert
+			assert.Equal(t, tt.wantError, len(recorder.errors) > 0)
+		})
+	}
+}
+
+func TestAssertGolden_Update(t *testing.T) {
+	// Arrange
+	t.Setenv(UpdateGoldenEnv, "true")
+	path := filepath.Join(t.TempDir(), "testdata", "request.golden.json")
+
+	// Act
+	AssertGolden(t, path, []byte(`{"service":"express","weight":2}`))
+
+	// Assert
+	written, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"service\": \"express\",\n  \"weight\": 2\n}\n", string(written))
+}
@@ -0,0 +1,49 @@
+package testsupport
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// UpdateGoldenEnv rewrites the golden files with the values produced by the tests when set to
+// "true", e.g. UPDATE_GOLDEN=true go test ./internal/label/...
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// AssertGolden compares got with the golden file at path, relative to the package of the test
+// (e.g. "testdata/label_request.golden.json"). JSON is compared indented, so that formatting
+// changes do not break the contract and the diffs of the golden files stay readable
+func AssertGolden(t testing.TB, path string, got []byte) {
+	t.Helper()
+	got = indentJSON(got)
+
+	if os.Getenv(UpdateGoldenEnv) == "true" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("testsupport: golden file %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("testsupport: golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("testsupport: golden file %s: %v (run with %s=true to create it)", path, err, UpdateGoldenEnv)
+	}
+	if !bytes.Equal(indentJSON(want), got) {
+		t.Errorf("testsupport: %s does not match the golden file (run with %s=true to update it)\n--- want\n%s\n--- got\n%s", path, UpdateGoldenEnv, want, got)
+	}
+}
+
+// indentJSON indents a JSON document with a trailing newline, returning other content unchanged
+func indentJSON(content []byte) []byte {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bytes.TrimSpace(content), "", "  "); err != nil {
+		return content
+	}
+	indented.WriteByte('\n')
+	return indented.Bytes()
+}
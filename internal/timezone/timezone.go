@@ -0,0 +1,111 @@
+// Package timezone resolves the time zone of a zipcode, so that delivery dates are counted in the
+// local days of the origin and the destination.
+package timezone
+
+import (
+	"strings"
+	"time"
+
+	// The zones are embedded so that they resolve on hosts and images without a zoneinfo database
+	_ "time/tzdata"
+
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+)
+
+// Brasilia is the official time of Brazil, used for the zipcodes whose state is unknown
+const Brasilia = "America/Sao_Paulo"
+
+// stateZones maps each Brazilian state to its IANA zone; states split across zones use the zone
+// of their capital
+var stateZones = map[string]string{
+	"AC": "America/Rio_Branco",
+	"AL": "America/Maceio",
+	"AM": "America/Manaus",
+	"AP": "America/Belem",
+	"BA": "America/Bahia",
+	"CE": "America/Fortaleza",
+	"DF": Brasilia,
+	"ES": Brasilia,
+	"GO": Brasilia,
+	"MA": "America/Fortaleza",
+	"MG": Brasilia,
+	"MS": "America/Campo_Grande",
+	"MT": "America/Cuiaba",
+	"PA": "America/Belem",
+	"PB": "America/Fortaleza",
+	"PE": "America/Recife",
+	"PI": "America/Fortaleza",
+	"PR": Brasilia,
+	"RJ": Brasilia,
+	"RN": "America/Fortaleza",
+	"RO": "America/Porto_Velho",
+	"RR": "America/Boa_Vista",
+	"RS": Brasilia,
+	"SC": Brasilia,
+	"SE": "America/Maceio",
+	"SP": Brasilia,
+	"TO": "America/Araguaina",
+}
+
+// countryZones maps the destination countries outside Brazil to their IANA zone; countries
+// split across zones use the zone of their capital
+var countryZones = map[string]string{
+	"DE": "Europe/Berlin",
+	"ES": "Europe/Madrid",
+	"FR": "Europe/Paris",
+	"IT": "Europe/Rome",
+	"NL": "Europe/Amsterdam",
+	"PT": "Europe/Lisbon",
+	"US": "America/New_York",
+}
+
+// Resolver resolves the time zone of Brazilian zipcodes by their state and of the other countries
+// by country. It is safe for concurrent use
+type Resolver struct {
+	brasilia  *time.Location
+	states    map[string]*time.Location
+	countries map[string]*time.Location
+}
+
+// NewResolver creates a resolver with the zones of the Brazilian states and of the countries
+// quoted by the default pricing
+func NewResolver() *Resolver {
+	r := &Resolver{
+		brasilia:  mustLoad(Brasilia),
+		states:    make(map[string]*time.Location, len(stateZones)),
+		countries: make(map[string]*time.Location, len(countryZones)),
+	}
+	for state, name := range stateZones {
+		r.states[state] = mustLoad(name)
+	}
+	for country, name := range countryZones {
+		r.countries[country] = mustLoad(name)
+	}
+	return r
+}
+
+// Location returns the time zone of a zipcode in a country: the zone of the state for Brazilian
+// zipcodes, Brasília time when the state is unknown, the zone of the country elsewhere and UTC
+// for unknown countries
+func (r *Resolver) Location(country, value string) *time.Location {
+	country = strings.ToUpper(country)
+	if country == "BR" {
+		if location, ok := r.states[zipcode.State(value)]; ok {
+			return location
+		}
+		return r.brasilia
+	}
+	if location, ok := r.countries[country]; ok {
+		return location
+	}
+	return time.UTC
+}
+
+// mustLoad loads an embedded zone; the names are constants of this package
+func mustLoad(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
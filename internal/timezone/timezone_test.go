@@ -0,0 +1,55 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolver_Location(t *testing.T) {
+	// Arrange
+	resolver := NewResolver()
+
+	tests := []struct {
+		name     string
+		country  string
+		zipcode  string
+		expected string
+	}{
+		{"São Paulo", "BR", "01310-100", "America/Sao_Paulo"},
+		{"Manaus", "br", "69005-040", "America/Manaus"},
+		{"Rio Branco", "BR", "69900-000", "America/Rio_Branco"},
+		{"Recife", "BR", "50030-000", "America/Recife"},
+		{"unknown state", "BR", "00000-000", "America/Sao_Paulo"},
+		{"country zone", "PT", "1100-148", "Europe/Lisbon"},
+		{"unknown country", "JP", "100-0001", "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			location := resolver.Location(tt.country, tt.zipcode)
+
+			// Assert
+			assert.Equal(t, tt.expected, location.String())
+		})
+	}
+}
+
+func TestResolver_LocalDates(t *testing.T) {
+	// Arrange
+	resolver := NewResolver()
+	// 23:30 in São Paulo is already the next day in Lisbon, and still 22:30 in Manaus
+	instant := time.Date(2025, 3, 8, 2, 30, 0, 0, time.UTC)
+
+	// Act
+	saoPaulo := instant.In(resolver.Location("BR", "01310-100"))
+	manaus := instant.In(resolver.Location("BR", "69005-040"))
+	lisbon := instant.In(resolver.Location("PT", "1100-148"))
+
+	// Assert
+	assert.Equal(t, "2025-03-07 23:30", saoPaulo.Format("2006-01-02 15:04"))
+	assert.Equal(t, "2025-03-07 22:30", manaus.Format("2006-01-02 15:04"))
+	assert.Equal(t, "2025-03-08 02:30", lisbon.Format("2006-01-02 15:04"))
+}
@@ -0,0 +1,152 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+)
+
+// CarrierUpdate is a carrier webhook normalized into our tracking events
+type CarrierUpdate struct {
+	ShipmentID string
+	// Events are the events with a tracking status; carrier events without an equivalent
+	// status (e.g. customs notices) are dropped
+	Events []model.TrackingEvent
+}
+
+// Normalizer converts the webhook payload of a carrier into a CarrierUpdate
+type Normalizer func(body []byte) (*CarrierUpdate, error)
+
+// normalizers are the carriers whose webhook payloads are understood, by carrier name
+var normalizers = map[string]Normalizer{
+	"correios": normalizeCorreios,
+	"jadlog":   normalizeJadlog,
+	"generic":  normalizeGeneric,
+}
+
+// Normalize converts the webhook payload of carrier into a CarrierUpdate
+func Normalize(carrier string, body []byte) (*CarrierUpdate, error) {
+	normalize, ok := normalizers[carrier]
+	if !ok {
+		return nil, ErrUnknownCarrier
+	}
+	update, err := normalize(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s payload: %w", carrier, err)
+	}
+	if update.ShipmentID == "" {
+		return nil, fmt.Errorf("invalid %s payload: shipment id is required", carrier)
+	}
+	return update, nil
+}
+
+// correiosStatuses maps Correios event codes to tracking statuses
+var correiosStatuses = map[string]string{
+	"PO":  model.TrackingStatusPosted,
+	"RO":  model.TrackingStatusInTransit,
+	"DO":  model.TrackingStatusInTransit,
+	"OEC": model.TrackingStatusInTransit,
+	"BDE": model.TrackingStatusDelivered,
+}
+
+// correiosPayload is the Correios webhook: {"objeto": "...", "eventos": [{"codigo": "PO", ...}]}
+type correiosPayload struct {
+	Object string `json:"objeto"`
+	Events []struct {
+		Code        string    `json:"codigo"`
+		Description string    `json:"descricao"`
+		Date        time.Time `json:"data"`
+		Unit        struct {
+			City  string `json:"cidade"`
+			State string `json:"uf"`
+		} `json:"unidade"`
+	} `json:"eventos"`
+}
+
+func normalizeCorreios(body []byte) (*CarrierUpdate, error) {
+	var payload correiosPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	update := &CarrierUpdate{ShipmentID: payload.Object}
+	for _, event := range payload.Events {
+		status, ok := correiosStatuses[strings.ToUpper(event.Code)]
+		if !ok {
+			continue
+		}
+		location := event.Unit.City
+		if event.Unit.State != "" {
+			location += "/" + event.Unit.State
+		}
+		update.Events = append(update.Events, model.TrackingEvent{
+			Status:      status,
+			Description: event.Description,
+			Location:    location,
+			OccurredAt:  event.Date,
+		})
+	}
+	return update, nil
+}
+
+// jadlogStatuses maps Jadlog statuses to tracking statuses
+var jadlogStatuses = map[string]string{
+	"COLETADO":      model.TrackingStatusPosted,
+	"EM TRANSITO":   model.TrackingStatusInTransit,
+	"TRANSFERENCIA": model.TrackingStatusInTransit,
+	"EM ROTA":       model.TrackingStatusInTransit,
+	"ENTREGUE":      model.TrackingStatusDelivered,
+	"NAO ENTREGUE":  model.TrackingStatusDeliveryFailed,
+	"DEVOLVIDO":     model.TrackingStatusReturned,
+}
+
+// jadlogPayload is the Jadlog webhook, one event per call with a Unix timestamp:
+// {"shipmentId": "...", "status": "ENTREGUE", "timestamp": 1741629600, "local": "..."}
+type jadlogPayload struct {
+	ShipmentID string `json:"shipmentId"`
+	Status     string `json:"status"`
+	Timestamp  int64  `json:"timestamp"`
+	Location   string `json:"local"`
+	Note       string `json:"observacao"`
+}
+
+func normalizeJadlog(body []byte) (*CarrierUpdate, error) {
+	var payload jadlogPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	update := &CarrierUpdate{ShipmentID: payload.ShipmentID}
+	status, ok := jadlogStatuses[strings.ToUpper(strings.TrimSpace(payload.Status))]
+	if !ok {
+		return update, nil
+	}
+	if payload.Timestamp <= 0 {
+		return nil, fmt.Errorf("timestamp is required")
+	}
+	update.Events = []model.TrackingEvent{{
+		Status:      status,
+		Description: payload.Note,
+		Location:    payload.Location,
+		OccurredAt:  time.Unix(payload.Timestamp, 0).UTC(),
+	}}
+	return update, nil
+}
+
+// genericPayload is our own tracking format, for carriers that adopt it:
+// {"shipment_id": "...", "events": [{"status": "posted", "occurred_at": "..."}]}
+type genericPayload struct {
+	ShipmentID string                `json:"shipment_id"`
+	Events     []model.TrackingEvent `json:"events"`
+}
+
+func normalizeGeneric(body []byte) (*CarrierUpdate, error) {
+	var payload genericPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	return &CarrierUpdate{ShipmentID: payload.ShipmentID, Events: payload.Events}, nil
+}
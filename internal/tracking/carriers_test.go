@@ -0,0 +1,116 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		carrier string
+		body    string
+		want    *CarrierUpdate
+	}{
+		{
+			name:    "correios",
+			carrier: "correios",
+			body: `{"objeto":"s1","eventos":[` +
+				`{"codigo":"PO","descricao":"Objeto postado","data":"2025-03-10T15:00:00-03:00","unidade":{"cidade":"São Paulo","uf":"SP"}},` +
+				`{"codigo":"FC","descricao":"Objeto aguardando retirada","data":"2025-03-11T09:00:00-03:00"},` +
+				`{"codigo":"bde","descricao":"Objeto entregue","data":"2025-03-12T11:30:00-03:00","unidade":{"cidade":"Rio de Janeiro","uf":"RJ"}}]}`,
+			want: &CarrierUpdate{ShipmentID: "s1", Events: []model.TrackingEvent{
+				{Status: model.TrackingStatusPosted, Description: "Objeto postado", Location: "São Paulo/SP",
+					OccurredAt: time.Date(2025, 3, 10, 15, 0, 0, 0, time.FixedZone("", -3*60*60))},
+				{Status: model.TrackingStatusDelivered, Description: "Objeto entregue", Location: "Rio de Janeiro/RJ",
+					OccurredAt: time.Date(2025, 3, 12, 11, 30, 0, 0, time.FixedZone("", -3*60*60))},
+			}},
+		},
+		{
+			name:    "jadlog",
+			carrier: "jadlog",
+			body:    `{"shipmentId":"s1","status":"Em Transito","timestamp":1741629600,"local":"Curitiba - PR","observacao":"Transferência entre unidades"}`,
+			want: &CarrierUpdate{ShipmentID: "s1", Events: []model.TrackingEvent{{
+				Status:      model.TrackingStatusInTransit,
+				Description: "Transferência entre unidades",
+				Location:    "Curitiba - PR",
+				OccurredAt:  time.Unix(1741629600, 0).UTC(),
+			}}},
+		},
+		{
+			name:    "jadlog delivery exception",
+			carrier: "jadlog",
+			body:    `{"shipmentId":"s1","status":"NAO ENTREGUE","timestamp":1741629600,"observacao":"Destinatário ausente"}`,
+			want: &CarrierUpdate{ShipmentID: "s1", Events: []model.TrackingEvent{{
+				Status:      model.TrackingStatusDeliveryFailed,
+				Description: "Destinatário ausente",
+				OccurredAt:  time.Unix(1741629600, 0).UTC(),
+			}}},
+		},
+		{
+			name:    "jadlog status without equivalent",
+			carrier: "jadlog",
+			body:    `{"shipmentId":"s1","status":"AGUARDANDO COLETA","timestamp":1741629600}`,
+			want:    &CarrierUpdate{ShipmentID: "s1"},
+		},
+		{
+			name:    "generic",
+			carrier: "generic",
+			body:    `{"shipment_id":"s1","events":[{"status":"posted","occurred_at":"2025-03-10T18:00:00Z"}]}`,
+			want: &CarrierUpdate{ShipmentID: "s1", Events: []model.TrackingEvent{
+				{Status: model.TrackingStatusPosted, OccurredAt: time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			update, err := Normalize(tt.carrier, []byte(tt.body))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.ShipmentID, update.ShipmentID)
+			assert.Len(t, update.Events, len(tt.want.Events))
+			for i, event := range tt.want.Events {
+				assert.Equal(t, event.Status, update.Events[i].Status)
+				assert.Equal(t, event.Description, update.Events[i].Description)
+				assert.Equal(t, event.Location, update.Events[i].Location)
+				assert.True(t, event.OccurredAt.Equal(update.Events[i].OccurredAt))
+			}
+		})
+	}
+}
+
+func TestNormalize_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		carrier  string
+		body     string
+		wantErr  error
+		contains string
+	}{
+		{"unknown carrier", "fedex", `{}`, ErrUnknownCarrier, ""},
+		{"malformed payload", "correios", `{"objeto":`, nil, "invalid correios payload"},
+		{"missing shipment", "generic", `{"events":[]}`, nil, "shipment id is required"},
+		{"jadlog without timestamp", "jadlog", `{"shipmentId":"s1","status":"ENTREGUE"}`, nil, "timestamp is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			update, err := Normalize(tt.carrier, []byte(tt.body))
+
+			// Assert
+			assert.Nil(t, update)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.ErrorContains(t, err, tt.contains)
+			}
+		})
+	}
+}
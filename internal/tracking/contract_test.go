@@ -0,0 +1,61 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPProvider_Contract(t *testing.T) {
+	// Arrange
+	server := testsupport.NewTrackingServer(t)
+	provider := NewHTTPProvider(Config{ProviderURL: server.URL, Timeout: time.Second})
+
+	// Act
+	events, err := provider.Track(context.Background(), &model.Shipment{ID: "s 1"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TrackingEvent{
+		{Status: model.TrackingStatusPosted, Description: "Objeto postado", Location: "SAO PAULO/SP", OccurredAt: time.Date(2025, 3, 3, 14, 5, 0, 0, time.UTC)},
+		{Status: model.TrackingStatusInTransit, Description: "Objeto em transferência", Location: "CAJAMAR/SP", OccurredAt: time.Date(2025, 3, 4, 2, 40, 0, 0, time.UTC)},
+		{Status: model.TrackingStatusInTransit, Description: "Objeto saiu para entrega", Location: "RIO DE JANEIRO/RJ", OccurredAt: time.Date(2025, 3, 5, 9, 12, 0, 0, time.UTC)},
+	}, events)
+	requests := server.Requests()
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "/shipments/s 1/events", requests[0].Path, "the shipment ID is escaped in the path")
+	assert.Equal(t, "application/json", requests[0].Header.Get("Accept"))
+}
+
+func TestHTTPProvider_ContractFaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		fault   testsupport.Fault
+		wantErr string
+	}{
+		{"unavailable", testsupport.Fault{Status: http.StatusBadGateway}, "unexpected status 502"},
+		{"malformed", testsupport.Fault{Malformed: true}, "invalid response body"},
+		{"connection reset", testsupport.Fault{Reset: true}, "tracking: "},
+		{"slower than the timeout", testsupport.Fault{Latency: time.Second}, "Client.Timeout exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := testsupport.NewTrackingServer(t)
+			server.Fail(tt.fault)
+			provider := NewHTTPProvider(Config{ProviderURL: server.URL, Timeout: 50 * time.Millisecond})
+
+			// Act
+			_, err := provider.Track(context.Background(), &model.Shipment{ID: "s1"})
+
+			// Assert
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
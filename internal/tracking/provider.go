@@ -0,0 +1,131 @@
+// Package tracking fetches shipment status events from carriers and authenticates and normalizes
+// the events they push through webhooks.
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// TrackingProvider polls the carrier for the status events of a shipment. Events already
+// reported may be returned again; they are stored only once
+type TrackingProvider interface {
+	Track(ctx context.Context, shipment *model.Shipment) ([]model.TrackingEvent, error)
+}
+
+// Config configures carrier polling and the tracking webhook
+type Config struct {
+	// ProviderURL is the carrier tracking API, queried at {ProviderURL}/shipments/{id}/events;
+	// empty disables polling
+	ProviderURL string
+	Timeout     time.Duration
+	// WebhookSecret authenticates carriers pushing events in the X-Webhook-Secret header;
+	// empty disables the webhook
+	WebhookSecret string
+	// CarrierSecrets are the HMAC secrets of the carrier webhooks by carrier name; carriers
+	// without a secret cannot push events
+	CarrierSecrets map[string]string
+	// WebhookTolerance is the maximum age of a signed carrier webhook
+	WebhookTolerance time.Duration
+}
+
+// ConfigFromEnv reads TRACKING_PROVIDER_URL (default none), TRACKING_PROVIDER_TIMEOUT
+// (default 3s), TRACKING_WEBHOOK_SECRET (default none), TRACKING_CARRIER_SECRETS
+// ("carrier:secret,carrier:secret", default none) and TRACKING_WEBHOOK_TOLERANCE (default 5m)
+func ConfigFromEnv() (Config, error) {
+	timeout, err := config.Duration("TRACKING_PROVIDER_TIMEOUT", 3*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if timeout <= 0 {
+		return Config{}, fmt.Errorf("TRACKING_PROVIDER_TIMEOUT must be positive")
+	}
+	tolerance, err := config.Duration("TRACKING_WEBHOOK_TOLERANCE", 5*time.Minute)
+	if err != nil {
+		return Config{}, err
+	}
+	if tolerance <= 0 {
+		return Config{}, fmt.Errorf("TRACKING_WEBHOOK_TOLERANCE must be positive")
+	}
+
+	var carrierSecrets map[string]string
+	for _, entry := range config.List("TRACKING_CARRIER_SECRETS", nil) {
+		carrier, secret, ok := strings.Cut(entry, ":")
+		carrier = strings.ToLower(strings.TrimSpace(carrier))
+		if !ok || carrier == "" || secret == "" {
+			return Config{}, fmt.Errorf("TRACKING_CARRIER_SECRETS: entry must be formatted as carrier:secret")
+		}
+		if carrierSecrets == nil {
+			carrierSecrets = make(map[string]string)
+		}
+		carrierSecrets[carrier] = secret
+	}
+
+	return Config{
+		ProviderURL:      strings.TrimRight(config.String("TRACKING_PROVIDER_URL", ""), "/"),
+		Timeout:          timeout,
+		WebhookSecret:    config.String("TRACKING_WEBHOOK_SECRET", ""),
+		CarrierSecrets:   carrierSecrets,
+		WebhookTolerance: tolerance,
+	}, nil
+}
+
+// HTTPProvider polls a carrier tracking API
+type HTTPProvider struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewHTTPProvider creates a provider for the configured tracking API
+func NewHTTPProvider(cfg Config) *HTTPProvider {
+	return &HTTPProvider{
+		baseURL:    cfg.ProviderURL,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// eventsResponse is the body returned by the tracking API
+type eventsResponse struct {
+	Events []model.TrackingEvent `json:"events"`
+}
+
+// Track queries the API, propagating the trace context of ctx. A shipment unknown to the
+// carrier (404) has not been posted yet and has no events; bodies over 1 MiB are rejected
+func (p *HTTPProvider) Track(ctx context.Context, shipment *model.Shipment) ([]model.TrackingEvent, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/shipments/"+url.PathEscape(shipment.ID)+"/events", nil)
+	if err != nil {
+		return nil, fmt.Errorf("tracking: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("tracking: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, fmt.Errorf("tracking: unexpected status %d", resp.StatusCode)
+	}
+
+	var body eventsResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("tracking: invalid response body: %w", err)
+	}
+	return body.Events, nil
+}
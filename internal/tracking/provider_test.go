@@ -0,0 +1,146 @@
+package tracking
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTrackingServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/shipments/s1/events", r.URL.Path)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTrack(t *testing.T) {
+	// Arrange
+	server := newTrackingServer(t, http.StatusOK,
+		`{"events":[{"status":"posted","location":"São Paulo/SP","occurred_at":"2025-01-10T15:00:00Z"}]}`)
+	provider := NewHTTPProvider(Config{ProviderURL: server.URL, Timeout: time.Second})
+
+	// Act
+	events, err := provider.Track(context.Background(), &model.Shipment{ID: "s1"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TrackingEvent{{
+		Status:     model.TrackingStatusPosted,
+		Location:   "São Paulo/SP",
+		OccurredAt: time.Date(2025, 1, 10, 15, 0, 0, 0, time.UTC),
+	}}, events)
+}
+
+func TestTrack_NotPosted(t *testing.T) {
+	// Arrange
+	server := newTrackingServer(t, http.StatusNotFound, ``)
+	provider := NewHTTPProvider(Config{ProviderURL: server.URL, Timeout: time.Second})
+
+	// Act
+	events, err := provider.Track(context.Background(), &model.Shipment{ID: "s1"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestTrack_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		contains string
+	}{
+		{"server error", http.StatusInternalServerError, ``, "unexpected status 500"},
+		{"invalid body", http.StatusOK, `{`, "invalid response body"},
+		{"body too large", http.StatusOK, `{"events": [` + strings.Repeat(`{"status": "in_transit"},`, 1<<16) + `{}]}`, "invalid response body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := newTrackingServer(t, tt.status, tt.body)
+			provider := NewHTTPProvider(Config{ProviderURL: server.URL, Timeout: time.Second})
+
+			// Act
+			events, err := provider.Track(context.Background(), &model.Shipment{ID: "s1"})
+
+			// Assert
+			assert.Nil(t, events)
+			assert.ErrorContains(t, err, tt.contains)
+		})
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// Arrange
+		t.Setenv("TRACKING_PROVIDER_URL", "")
+		t.Setenv("TRACKING_PROVIDER_TIMEOUT", "")
+		t.Setenv("TRACKING_WEBHOOK_SECRET", "")
+		t.Setenv("TRACKING_CARRIER_SECRETS", "")
+		t.Setenv("TRACKING_WEBHOOK_TOLERANCE", "")
+
+		// Act
+		cfg, err := ConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Timeout: 3 * time.Second, WebhookTolerance: 5 * time.Minute}, cfg)
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		// Arrange
+		t.Setenv("TRACKING_PROVIDER_URL", "http://tracking.internal/")
+		t.Setenv("TRACKING_PROVIDER_TIMEOUT", "500ms")
+		t.Setenv("TRACKING_WEBHOOK_SECRET", "s3cret")
+		t.Setenv("TRACKING_CARRIER_SECRETS", "Correios:abc:123,jadlog:xyz")
+		t.Setenv("TRACKING_WEBHOOK_TOLERANCE", "1m")
+
+		// Act
+		cfg, err := ConfigFromEnv()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Config{
+			ProviderURL:      "http://tracking.internal",
+			Timeout:          500 * time.Millisecond,
+			WebhookSecret:    "s3cret",
+			CarrierSecrets:   map[string]string{"correios": "abc:123", "jadlog": "xyz"},
+			WebhookTolerance: time.Minute,
+		}, cfg)
+	})
+
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"invalid timeout", "TRACKING_PROVIDER_TIMEOUT", "0s"},
+		{"invalid tolerance", "TRACKING_WEBHOOK_TOLERANCE", "-1m"},
+		{"carrier without secret", "TRACKING_CARRIER_SECRETS", "correios"},
+		{"secret without carrier", "TRACKING_CARRIER_SECRETS", ":abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv(tt.key, tt.value)
+
+			// Act
+			_, err := ConfigFromEnv()
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
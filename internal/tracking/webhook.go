@@ -0,0 +1,103 @@
+package tracking
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+)
+
+// Carrier webhook headers. The signature is the hex-encoded HMAC-SHA256 of "{timestamp}.{body}"
+// with the secret of the carrier, optionally prefixed with "sha256="; the timestamp is in Unix seconds
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+)
+
+// seenKeyPrefix prefixes the keys of the accepted signatures in the quote store
+const seenKeyPrefix = "webhook-seen:"
+
+var (
+	// ErrUnknownCarrier is returned for carriers without a webhook secret or payload normalizer
+	ErrUnknownCarrier = errors.New("unknown carrier")
+	// ErrInvalidSignature is returned when the signature does not match or the timestamp is
+	// outside the tolerance
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrReplayedWebhook is returned when a signed webhook is received again
+	ErrReplayedWebhook = errors.New("webhook already received")
+)
+
+// WebhookVerifier authenticates carrier webhooks and rejects replays. The accepted signatures are
+// kept in the quote store until their timestamp falls outside the tolerance, so that a webhook
+// replayed to another instance, or after a restart, is rejected too. It is safe for concurrent use
+type WebhookVerifier struct {
+	secrets   map[string]string
+	tolerance time.Duration
+	seen      store.QuoteStore
+	now       func() time.Time
+}
+
+// NewWebhookVerifier creates a verifier for the carrier secrets of cfg, keeping the accepted
+// signatures in seen
+func NewWebhookVerifier(cfg Config, seen store.QuoteStore) *WebhookVerifier {
+	return &WebhookVerifier{
+		secrets:   cfg.CarrierSecrets,
+		tolerance: cfg.WebhookTolerance,
+		seen:      seen,
+		now:       time.Now,
+	}
+}
+
+// Verify checks the signature of a webhook body sent by carrier at timestamp. A webhook is
+// accepted only once: carriers retrying a delivery must sign it again with a new timestamp.
+// Failures of the quote store are returned as is
+func (v *WebhookVerifier) Verify(ctx context.Context, carrier, timestamp, signature string, body []byte) error {
+	secret, ok := v.secrets[carrier]
+	if !ok {
+		return ErrUnknownCarrier
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp", ErrInvalidSignature)
+	}
+	signedAt := time.Unix(seconds, 0)
+	now := v.now()
+	if now.Sub(signedAt) > v.tolerance || signedAt.Sub(now) > v.tolerance {
+		return fmt.Errorf("%w: timestamp outside the tolerance", ErrInvalidSignature)
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || !hmac.Equal(got, Sign(secret, timestamp, body)) {
+		return ErrInvalidSignature
+	}
+
+	// The signature is remembered while its timestamp is within the tolerance, after which the
+	// webhook is rejected as stale anyway; the extra second keeps the TTL positive at the edge of
+	// the tolerance, where a TTL of 0 would never expire
+	key := seenKeyPrefix + carrier + ":" + hex.EncodeToString(got)
+	stored, err := v.seen.PutIfAbsent(ctx, key, []byte(timestamp), signedAt.Add(v.tolerance).Sub(now)+time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook signature: %w", err)
+	}
+	if !stored {
+		return ErrReplayedWebhook
+	}
+	return nil
+}
+
+// Sign returns the HMAC-SHA256 signature of a webhook body sent at timestamp
+func Sign(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
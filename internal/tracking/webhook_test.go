@@ -0,0 +1,179 @@
+package tracking
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+var webhookNow = time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+
+func newTestVerifier() *WebhookVerifier {
+	return newTestVerifierWithStore(store.NewMemoryStore())
+}
+
+func newTestVerifierWithStore(seen store.QuoteStore) *WebhookVerifier {
+	verifier := NewWebhookVerifier(Config{CarrierSecrets: map[string]string{"jadlog": "s3cret"}, WebhookTolerance: 5 * time.Minute}, seen)
+	verifier.now = func() time.Time { return webhookNow }
+	return verifier
+}
+
+// recordingStore is a memory store recording the TTL of the keys stored if absent, or failing
+// them with err
+type recordingStore struct {
+	*store.MemoryStore
+	ttls map[string]time.Duration
+	err  error
+}
+
+func (s *recordingStore) PutIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	s.ttls[key] = ttl
+	return s.MemoryStore.PutIfAbsent(ctx, key, value, ttl)
+}
+
+func signature(secret string, signedAt time.Time, body string) (string, string) {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	return timestamp, "sha256=" + hex.EncodeToString(Sign(secret, timestamp, []byte(body)))
+}
+
+func TestVerify(t *testing.T) {
+	// Arrange
+	verifier := newTestVerifier()
+	body := `{"shipmentId":"s1","status":"ENTREGUE"}`
+	timestamp, sig := signature("s3cret", webhookNow.Add(-time.Minute), body)
+
+	// Act
+	err := verifier.Verify(context.Background(), "jadlog", timestamp, sig, []byte(body))
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestVerify_Errors(t *testing.T) {
+	body := `{"shipmentId":"s1","status":"ENTREGUE"}`
+	validTimestamp, validSig := signature("s3cret", webhookNow, body)
+	staleTimestamp, staleSig := signature("s3cret", webhookNow.Add(-6*time.Minute), body)
+	futureTimestamp, futureSig := signature("s3cret", webhookNow.Add(6*time.Minute), body)
+	_, wrongSecretSig := signature("guess", webhookNow, body)
+
+	tests := []struct {
+		name      string
+		carrier   string
+		timestamp string
+		signature string
+		body      string
+		wantErr   error
+	}{
+		{"unknown carrier", "correios", validTimestamp, validSig, body, ErrUnknownCarrier},
+		{"wrong secret", "jadlog", validTimestamp, wrongSecretSig, body, ErrInvalidSignature},
+		{"tampered body", "jadlog", validTimestamp, validSig, `{"shipmentId":"s2","status":"ENTREGUE"}`, ErrInvalidSignature},
+		{"malformed signature", "jadlog", validTimestamp, "sha256=zz", body, ErrInvalidSignature},
+		{"missing timestamp", "jadlog", "", validSig, body, ErrInvalidSignature},
+		{"stale timestamp", "jadlog", staleTimestamp, staleSig, body, ErrInvalidSignature},
+		{"future timestamp", "jadlog", futureTimestamp, futureSig, body, ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			verifier := newTestVerifier()
+
+			// Act
+			err := verifier.Verify(context.Background(), tt.carrier, tt.timestamp, tt.signature, []byte(tt.body))
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestVerify_Replay(t *testing.T) {
+	// Arrange
+	verifier := newTestVerifier()
+	body := `{"shipmentId":"s1","status":"ENTREGUE"}`
+	timestamp, sig := signature("s3cret", webhookNow, body)
+	resignedTimestamp, resignedSig := signature("s3cret", webhookNow.Add(time.Second), body)
+
+	// Act
+	first := verifier.Verify(context.Background(), "jadlog", timestamp, sig, []byte(body))
+	replayed := verifier.Verify(context.Background(), "jadlog", timestamp, sig, []byte(body))
+	resigned := verifier.Verify(context.Background(), "jadlog", resignedTimestamp, resignedSig, []byte(body))
+
+	// Assert
+	assert.NoError(t, first)
+	assert.ErrorIs(t, replayed, ErrReplayedWebhook)
+	assert.NoError(t, resigned, "a retry signed with a new timestamp is accepted")
+}
+
+func TestVerify_ReplayToAnotherInstance(t *testing.T) {
+	// Arrange
+	shared := store.NewMemoryStore()
+	first, second := newTestVerifierWithStore(shared), newTestVerifierWithStore(shared)
+	body := `{"shipmentId":"s1","status":"ENTREGUE"}`
+	timestamp, sig := signature("s3cret", webhookNow, body)
+
+	// Act
+	accepted := first.Verify(context.Background(), "jadlog", timestamp, sig, []byte(body))
+	replayed := second.Verify(context.Background(), "jadlog", timestamp, sig, []byte(body))
+
+	// Assert
+	assert.NoError(t, accepted)
+	assert.ErrorIs(t, replayed, ErrReplayedWebhook, "the instances share the accepted signatures")
+}
+
+func TestVerify_RemembersSignaturesWithinTheTolerance(t *testing.T) {
+	tests := []struct {
+		name     string
+		signedAt time.Time
+		wantTTL  time.Duration
+	}{
+		{"signed now", webhookNow, 5*time.Minute + time.Second},
+		{"signed a minute ago", webhookNow.Add(-time.Minute), 4*time.Minute + time.Second},
+		{"signed a minute ahead", webhookNow.Add(time.Minute), 6*time.Minute + time.Second},
+		{"at the edge of the tolerance", webhookNow.Add(-5 * time.Minute), time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			seen := &recordingStore{MemoryStore: store.NewMemoryStore(), ttls: map[string]time.Duration{}}
+			verifier := newTestVerifierWithStore(seen)
+			body := `{"shipmentId":"s1","status":"ENTREGUE"}`
+			timestamp, sig := signature("s3cret", tt.signedAt, body)
+
+			// Act
+			err := verifier.Verify(context.Background(), "jadlog", timestamp, sig, []byte(body))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, seen.ttls, 1)
+			for _, ttl := range seen.ttls {
+				assert.Equal(t, tt.wantTTL, ttl)
+			}
+		})
+	}
+}
+
+func TestVerify_StoreFailure(t *testing.T) {
+	// Arrange
+	verifier := newTestVerifierWithStore(&recordingStore{MemoryStore: store.NewMemoryStore(), err: errors.New("connection refused")})
+	body := `{"shipmentId":"s1","status":"ENTREGUE"}`
+	timestamp, sig := signature("s3cret", webhookNow, body)
+
+	// Act
+	err := verifier.Verify(context.Background(), "jadlog", timestamp, sig, []byte(body))
+
+	// Assert
+	assert.ErrorContains(t, err, "failed to record webhook signature: connection refused")
+	assert.NotErrorIs(t, err, ErrInvalidSignature)
+	assert.NotErrorIs(t, err, ErrReplayedWebhook)
+}
@@ -0,0 +1,61 @@
+package v1
+
+// PackingRequest is a shipment described by its items instead of its package: the route and
+// options are those of CalculateShippingRequest
+type PackingRequest struct {
+	OriginZipcode      string        `json:"origin_zipcode" xml:"origin_zipcode"`
+	DestinationZipcode string        `json:"destination_zipcode" xml:"destination_zipcode"`
+	IsExpress          bool          `json:"is_express" xml:"is_express"`
+	DestinationCountry string        `json:"destination_country,omitempty" xml:"destination_country,omitempty"`
+	Currency           string        `json:"currency,omitempty" xml:"currency,omitempty"`
+	PackageType        string        `json:"package_type,omitempty" xml:"package_type,omitempty"`
+	AdditionalServices []string      `json:"additional_services,omitempty" xml:"additional_services>service,omitempty"`
+	DeliveryType       string        `json:"delivery_type,omitempty" xml:"delivery_type,omitempty"`
+	PricingStrategy    string        `json:"pricing_strategy,omitempty" xml:"pricing_strategy,omitempty"`
+	Items              []PackingItem `json:"items" xml:"items>item"`
+}
+
+// PackingItem is a product to pack, in centimeters and kilograms; Quantity units of it are
+// packed (default: 1)
+type PackingItem struct {
+	SKU      string  `json:"sku" xml:"sku"`
+	Length   float64 `json:"length" xml:"length"`
+	Width    float64 `json:"width" xml:"width"`
+	Height   float64 `json:"height" xml:"height"`
+	Weight   float64 `json:"weight" xml:"weight"`
+	Quantity int     `json:"quantity,omitempty" xml:"quantity,omitempty"`
+}
+
+// PackingResponse lists the suggested boxes and the total cost of shipping them, in minor units
+type PackingResponse struct {
+	Currency     string      `json:"currency,omitempty" xml:"currency,omitempty"`
+	ShippingCost float64     `json:"shipping_cost" xml:"shipping_cost"`
+	BoxCount     int         `json:"box_count" xml:"box_count"`
+	Boxes        []PackedBox `json:"boxes" xml:"boxes>box"`
+}
+
+// PackedBox is a suggested box, its contents and its quote
+type PackedBox struct {
+	Box      PackingBox                 `json:"box" xml:"box"`
+	Items    []PackedItem               `json:"items" xml:"items>item"`
+	Weight   float64                    `json:"weight" xml:"weight"`
+	FillRate float64                    `json:"fill_rate" xml:"fill_rate"`
+	Quote    *CalculateShippingResponse `json:"quote" xml:"quote"`
+}
+
+// PackingBox is a box size of the catalog, with its inner dimensions in centimeters and the
+// maximum weight of its contents in kilograms (0 for no limit)
+type PackingBox struct {
+	ID        string  `json:"id" xml:"id"`
+	Name      string  `json:"name,omitempty" xml:"name,omitempty"`
+	Length    float64 `json:"length" xml:"length"`
+	Width     float64 `json:"width" xml:"width"`
+	Height    float64 `json:"height" xml:"height"`
+	MaxWeight float64 `json:"max_weight,omitempty" xml:"max_weight,omitempty"`
+}
+
+// PackedItem is the number of units of an item packed in a box
+type PackedItem struct {
+	SKU      string `json:"sku" xml:"sku"`
+	Quantity int    `json:"quantity" xml:"quantity"`
+}
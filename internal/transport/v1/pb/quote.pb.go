@@ -0,0 +1,1354 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: internal/transport/v1/pb/quote.proto
+
+package v1pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ShippingQuote is the response of POST /calculate
+type ShippingQuote struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuoteId               string                 `protobuf:"bytes,1,opt,name=quote_id,json=quoteId,proto3" json:"quote_id,omitempty"`
+	Currency              string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	PricingVersion        string                 `protobuf:"bytes,3,opt,name=pricing_version,json=pricingVersion,proto3" json:"pricing_version,omitempty"`
+	ExpiresAt             *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	ShippingCost          float64                `protobuf:"fixed64,5,opt,name=shipping_cost,json=shippingCost,proto3" json:"shipping_cost,omitempty"`
+	EstimatedDeliveryTime string                 `protobuf:"bytes,6,opt,name=estimated_delivery_time,json=estimatedDeliveryTime,proto3" json:"estimated_delivery_time,omitempty"`
+	AvailableServices     []string               `protobuf:"bytes,7,rep,name=available_services,json=availableServices,proto3" json:"available_services,omitempty"`
+	ShippingOptions       []*ShippingOption      `protobuf:"bytes,8,rep,name=shipping_options,json=shippingOptions,proto3" json:"shipping_options,omitempty"`
+	Breakdown             *CostBreakdown         `protobuf:"bytes,9,opt,name=breakdown,proto3" json:"breakdown,omitempty"`
+	SelectedService       string                 `protobuf:"bytes,10,opt,name=selected_service,json=selectedService,proto3" json:"selected_service,omitempty"`
+	Experiment            *ExperimentAssignment  `protobuf:"bytes,11,opt,name=experiment,proto3" json:"experiment,omitempty"`
+	Package               *PackageMeasures       `protobuf:"bytes,12,opt,name=package,proto3" json:"package,omitempty"`
+	FuelIndex             *FuelIndex             `protobuf:"bytes,13,opt,name=fuel_index,json=fuelIndex,proto3" json:"fuel_index,omitempty"`
+	Degraded              bool                   `protobuf:"varint,14,opt,name=degraded,proto3" json:"degraded,omitempty"`
+	DeliveryDate          string                 `protobuf:"bytes,15,opt,name=delivery_date,json=deliveryDate,proto3" json:"delivery_date,omitempty"`
+}
+
+func (x *ShippingQuote) Reset() {
+	*x = ShippingQuote{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShippingQuote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingQuote) ProtoMessage() {}
+
+func (x *ShippingQuote) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingQuote.ProtoReflect.Descriptor instead.
+func (*ShippingQuote) Descriptor() ([]byte, []int) {
+	return file_internal_transport_v1_pb_quote_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ShippingQuote) GetQuoteId() string {
+	if x != nil {
+		return x.QuoteId
+	}
+	return ""
+}
+
+func (x *ShippingQuote) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ShippingQuote) GetPricingVersion() string {
+	if x != nil {
+		return x.PricingVersion
+	}
+	return ""
+}
+
+func (x *ShippingQuote) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *ShippingQuote) GetShippingCost() float64 {
+	if x != nil {
+		return x.ShippingCost
+	}
+	return 0
+}
+
+func (x *ShippingQuote) GetEstimatedDeliveryTime() string {
+	if x != nil {
+		return x.EstimatedDeliveryTime
+	}
+	return ""
+}
+
+func (x *ShippingQuote) GetAvailableServices() []string {
+	if x != nil {
+		return x.AvailableServices
+	}
+	return nil
+}
+
+func (x *ShippingQuote) GetShippingOptions() []*ShippingOption {
+	if x != nil {
+		return x.ShippingOptions
+	}
+	return nil
+}
+
+func (x *ShippingQuote) GetBreakdown() *CostBreakdown {
+	if x != nil {
+		return x.Breakdown
+	}
+	return nil
+}
+
+func (x *ShippingQuote) GetSelectedService() string {
+	if x != nil {
+		return x.SelectedService
+	}
+	return ""
+}
+
+func (x *ShippingQuote) GetExperiment() *ExperimentAssignment {
+	if x != nil {
+		return x.Experiment
+	}
+	return nil
+}
+
+func (x *ShippingQuote) GetPackage() *PackageMeasures {
+	if x != nil {
+		return x.Package
+	}
+	return nil
+}
+
+func (x *ShippingQuote) GetFuelIndex() *FuelIndex {
+	if x != nil {
+		return x.FuelIndex
+	}
+	return nil
+}
+
+func (x *ShippingQuote) GetDegraded() bool {
+	if x != nil {
+		return x.Degraded
+	}
+	return false
+}
+
+func (x *ShippingQuote) GetDeliveryDate() string {
+	if x != nil {
+		return x.DeliveryDate
+	}
+	return ""
+}
+
+// ShippingOption is a shipping service option
+type ShippingOption struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Service         string  `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Cost            float64 `protobuf:"fixed64,2,opt,name=cost,proto3" json:"cost,omitempty"`
+	Time            string  `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	EstimatedDays   int32   `protobuf:"varint,4,opt,name=estimated_days,json=estimatedDays,proto3" json:"estimated_days,omitempty"`
+	Cheapest        bool    `protobuf:"varint,5,opt,name=cheapest,proto3" json:"cheapest,omitempty"`
+	Fastest         bool    `protobuf:"varint,6,opt,name=fastest,proto3" json:"fastest,omitempty"`
+	Token           string  `protobuf:"bytes,7,opt,name=token,proto3" json:"token,omitempty"`
+	DeliveryDate    string  `protobuf:"bytes,8,opt,name=delivery_date,json=deliveryDate,proto3" json:"delivery_date,omitempty"`
+	WeekendDelivery bool    `protobuf:"varint,9,opt,name=weekend_delivery,json=weekendDelivery,proto3" json:"weekend_delivery,omitempty"`
+}
+
+func (x *ShippingOption) Reset() {
+	*x = ShippingOption{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShippingOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingOption) ProtoMessage() {}
+
+func (x *ShippingOption) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingOption.ProtoReflect.Descriptor instead.
+func (*ShippingOption) Descriptor() ([]byte, []int) {
+	return file_internal_transport_v1_pb_quote_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ShippingOption) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *ShippingOption) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *ShippingOption) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *ShippingOption) GetEstimatedDays() int32 {
+	if x != nil {
+		return x.EstimatedDays
+	}
+	return 0
+}
+
+func (x *ShippingOption) GetCheapest() bool {
+	if x != nil {
+		return x.Cheapest
+	}
+	return false
+}
+
+func (x *ShippingOption) GetFastest() bool {
+	if x != nil {
+		return x.Fastest
+	}
+	return false
+}
+
+func (x *ShippingOption) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ShippingOption) GetDeliveryDate() string {
+	if x != nil {
+		return x.DeliveryDate
+	}
+	return ""
+}
+
+func (x *ShippingOption) GetWeekendDelivery() bool {
+	if x != nil {
+		return x.WeekendDelivery
+	}
+	return false
+}
+
+// CostBreakdown itemizes the cost of the selected service
+type CostBreakdown struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BaseCost                float64       `protobuf:"fixed64,1,opt,name=base_cost,json=baseCost,proto3" json:"base_cost,omitempty"`
+	WeightSurcharge         float64       `protobuf:"fixed64,2,opt,name=weight_surcharge,json=weightSurcharge,proto3" json:"weight_surcharge,omitempty"`
+	VolumeSurcharge         float64       `protobuf:"fixed64,3,opt,name=volume_surcharge,json=volumeSurcharge,proto3" json:"volume_surcharge,omitempty"`
+	PackageTypeSurcharge    float64       `protobuf:"fixed64,4,opt,name=package_type_surcharge,json=packageTypeSurcharge,proto3" json:"package_type_surcharge,omitempty"`
+	DeliveryTypeAdjustment  float64       `protobuf:"fixed64,5,opt,name=delivery_type_adjustment,json=deliveryTypeAdjustment,proto3" json:"delivery_type_adjustment,omitempty"`
+	ExpressSurcharge        float64       `protobuf:"fixed64,6,opt,name=express_surcharge,json=expressSurcharge,proto3" json:"express_surcharge,omitempty"`
+	ReturnAdjustment        float64       `protobuf:"fixed64,7,opt,name=return_adjustment,json=returnAdjustment,proto3" json:"return_adjustment,omitempty"`
+	PickupSurcharge         float64       `protobuf:"fixed64,8,opt,name=pickup_surcharge,json=pickupSurcharge,proto3" json:"pickup_surcharge,omitempty"`
+	RestrictedArea          string        `protobuf:"bytes,9,opt,name=restricted_area,json=restrictedArea,proto3" json:"restricted_area,omitempty"`
+	RestrictedAreaSurcharge float64       `protobuf:"fixed64,10,opt,name=restricted_area_surcharge,json=restrictedAreaSurcharge,proto3" json:"restricted_area_surcharge,omitempty"`
+	FuelSurcharge           float64       `protobuf:"fixed64,11,opt,name=fuel_surcharge,json=fuelSurcharge,proto3" json:"fuel_surcharge,omitempty"`
+	PriceLimit              string        `protobuf:"bytes,12,opt,name=price_limit,json=priceLimit,proto3" json:"price_limit,omitempty"`
+	PriceLimitAdjustment    float64       `protobuf:"fixed64,13,opt,name=price_limit_adjustment,json=priceLimitAdjustment,proto3" json:"price_limit_adjustment,omitempty"`
+	AdditionalServices      []*ServiceFee `protobuf:"bytes,14,rep,name=additional_services,json=additionalServices,proto3" json:"additional_services,omitempty"`
+	UnroundedTotal          float64       `protobuf:"fixed64,15,opt,name=unrounded_total,json=unroundedTotal,proto3" json:"unrounded_total,omitempty"`
+	RoundingAdjustment      float64       `protobuf:"fixed64,16,opt,name=rounding_adjustment,json=roundingAdjustment,proto3" json:"rounding_adjustment,omitempty"`
+	Total                   float64       `protobuf:"fixed64,17,opt,name=total,proto3" json:"total,omitempty"`
+	Duties                  []*DutyCharge `protobuf:"bytes,18,rep,name=duties,proto3" json:"duties,omitempty"`
+	LandedCost              float64       `protobuf:"fixed64,19,opt,name=landed_cost,json=landedCost,proto3" json:"landed_cost,omitempty"`
+	Tax                     *FreightTax   `protobuf:"bytes,20,opt,name=tax,proto3" json:"tax,omitempty"`
+	WeekendSurcharge        float64       `protobuf:"fixed64,21,opt,name=weekend_surcharge,json=weekendSurcharge,proto3" json:"weekend_surcharge,omitempty"`
+	RemoteAreas             []string      `protobuf:"bytes,22,rep,name=remote_areas,json=remoteAreas,proto3" json:"remote_areas,omitempty"`
+	RemoteAreaSurcharge     float64       `protobuf:"fixed64,23,opt,name=remote_area_surcharge,json=remoteAreaSurcharge,proto3" json:"remote_area_surcharge,omitempty"`
+}
+
+func (x *CostBreakdown) Reset() {
+	*x = CostBreakdown{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CostBreakdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostBreakdown) ProtoMessage() {}
+
+func (x *CostBreakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostBreakdown.ProtoReflect.Descriptor instead.
+func (*CostBreakdown) Descriptor() ([]byte, []int) {
+	return file_internal_transport_v1_pb_quote_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CostBreakdown) GetBaseCost() float64 {
+	if x != nil {
+		return x.BaseCost
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetWeightSurcharge() float64 {
+	if x != nil {
+		return x.WeightSurcharge
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetVolumeSurcharge() float64 {
+	if x != nil {
+		return x.VolumeSurcharge
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetPackageTypeSurcharge() float64 {
+	if x != nil {
+		return x.PackageTypeSurcharge
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetDeliveryTypeAdjustment() float64 {
+	if x != nil {
+		return x.DeliveryTypeAdjustment
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetExpressSurcharge() float64 {
+	if x != nil {
+		return x.ExpressSurcharge
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetReturnAdjustment() float64 {
+	if x != nil {
+		return x.ReturnAdjustment
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetPickupSurcharge() float64 {
+	if x != nil {
+		return x.PickupSurcharge
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetRestrictedArea() string {
+	if x != nil {
+		return x.RestrictedArea
+	}
+	return ""
+}
+
+func (x *CostBreakdown) GetRestrictedAreaSurcharge() float64 {
+	if x != nil {
+		return x.RestrictedAreaSurcharge
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetFuelSurcharge() float64 {
+	if x != nil {
+		return x.FuelSurcharge
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetPriceLimit() string {
+	if x != nil {
+		return x.PriceLimit
+	}
+	return ""
+}
+
+func (x *CostBreakdown) GetPriceLimitAdjustment() float64 {
+	if x != nil {
+		return x.PriceLimitAdjustment
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetAdditionalServices() []*ServiceFee {
+	if x != nil {
+		return x.AdditionalServices
+	}
+	return nil
+}
+
+func (x *CostBreakdown) GetUnroundedTotal() float64 {
+	if x != nil {
+		return x.UnroundedTotal
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetRoundingAdjustment() float64 {
+	if x != nil {
+		return x.RoundingAdjustment
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetDuties() []*DutyCharge {
+	if x != nil {
+		return x.Duties
+	}
+	return nil
+}
+
+func (x *CostBreakdown) GetLandedCost() float64 {
+	if x != nil {
+		return x.LandedCost
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetTax() *FreightTax {
+	if x != nil {
+		return x.Tax
+	}
+	return nil
+}
+
+func (x *CostBreakdown) GetWeekendSurcharge() float64 {
+	if x != nil {
+		return x.WeekendSurcharge
+	}
+	return 0
+}
+
+func (x *CostBreakdown) GetRemoteAreas() []string {
+	if x != nil {
+		return x.RemoteAreas
+	}
+	return nil
+}
+
+func (x *CostBreakdown) GetRemoteAreaSurcharge() float64 {
+	if x != nil {
+		return x.RemoteAreaSurcharge
+	}
+	return 0
+}
+
+// FreightTax is the ICMS or ISS included in the freight of a domestic route
+type FreightTax struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tax              string  `protobuf:"bytes,1,opt,name=tax,proto3" json:"tax,omitempty"`
+	Rate             float64 `protobuf:"fixed64,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	OriginState      string  `protobuf:"bytes,3,opt,name=origin_state,json=originState,proto3" json:"origin_state,omitempty"`
+	DestinationState string  `protobuf:"bytes,4,opt,name=destination_state,json=destinationState,proto3" json:"destination_state,omitempty"`
+	Municipality     string  `protobuf:"bytes,5,opt,name=municipality,proto3" json:"municipality,omitempty"`
+	Gross            float64 `protobuf:"fixed64,6,opt,name=gross,proto3" json:"gross,omitempty"`
+	Amount           float64 `protobuf:"fixed64,7,opt,name=amount,proto3" json:"amount,omitempty"`
+	Net              float64 `protobuf:"fixed64,8,opt,name=net,proto3" json:"net,omitempty"`
+}
+
+func (x *FreightTax) Reset() {
+	*x = FreightTax{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FreightTax) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreightTax) ProtoMessage() {}
+
+func (x *FreightTax) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreightTax.ProtoReflect.Descriptor instead.
+func (*FreightTax) Descriptor() ([]byte, []int) {
+	return file_internal_transport_v1_pb_quote_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FreightTax) GetTax() string {
+	if x != nil {
+		return x.Tax
+	}
+	return ""
+}
+
+func (x *FreightTax) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *FreightTax) GetOriginState() string {
+	if x != nil {
+		return x.OriginState
+	}
+	return ""
+}
+
+func (x *FreightTax) GetDestinationState() string {
+	if x != nil {
+		return x.DestinationState
+	}
+	return ""
+}
+
+func (x *FreightTax) GetMunicipality() string {
+	if x != nil {
+		return x.Municipality
+	}
+	return ""
+}
+
+func (x *FreightTax) GetGross() float64 {
+	if x != nil {
+		return x.Gross
+	}
+	return 0
+}
+
+func (x *FreightTax) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *FreightTax) GetNet() float64 {
+	if x != nil {
+		return x.Net
+	}
+	return 0
+}
+
+// DutyCharge is an estimated import duty or tax
+type DutyCharge struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string  `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Rate   float64 `protobuf:"fixed64,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	Amount float64 `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *DutyCharge) Reset() {
+	*x = DutyCharge{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DutyCharge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DutyCharge) ProtoMessage() {}
+
+func (x *DutyCharge) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DutyCharge.ProtoReflect.Descriptor instead.
+func (*DutyCharge) Descriptor() ([]byte, []int) {
+	return file_internal_transport_v1_pb_quote_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DutyCharge) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DutyCharge) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *DutyCharge) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+// ServiceFee is the fee charged for an additional service
+type ServiceFee struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Service string  `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Fee     float64 `protobuf:"fixed64,2,opt,name=fee,proto3" json:"fee,omitempty"`
+}
+
+func (x *ServiceFee) Reset() {
+	*x = ServiceFee{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServiceFee) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceFee) ProtoMessage() {}
+
+func (x *ServiceFee) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceFee.ProtoReflect.Descriptor instead.
+func (*ServiceFee) Descriptor() ([]byte, []int) {
+	return file_internal_transport_v1_pb_quote_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ServiceFee) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *ServiceFee) GetFee() float64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+// ExperimentAssignment identifies the pricing experiment arm a quote was assigned to
+type ExperimentAssignment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Arm  string `protobuf:"bytes,2,opt,name=arm,proto3" json:"arm,omitempty"`
+}
+
+func (x *ExperimentAssignment) Reset() {
+	*x = ExperimentAssignment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExperimentAssignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExperimentAssignment) ProtoMessage() {}
+
+func (x *ExperimentAssignment) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExperimentAssignment.ProtoReflect.Descriptor instead.
+func (*ExperimentAssignment) Descriptor() ([]byte, []int) {
+	return file_internal_transport_v1_pb_quote_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ExperimentAssignment) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExperimentAssignment) GetArm() string {
+	if x != nil {
+		return x.Arm
+	}
+	return ""
+}
+
+// PackageMeasures are the weight and dimensions of a package in kilograms and centimeters
+type PackageMeasures struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WeightKg     float64            `protobuf:"fixed64,1,opt,name=weight_kg,json=weightKg,proto3" json:"weight_kg,omitempty"`
+	DimensionsCm *PackageDimensions `protobuf:"bytes,2,opt,name=dimensions_cm,json=dimensionsCm,proto3" json:"dimensions_cm,omitempty"`
+}
+
+func (x *PackageMeasures) Reset() {
+	*x = PackageMeasures{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PackageMeasures) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackageMeasures) ProtoMessage() {}
+
+func (x *PackageMeasures) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackageMeasures.ProtoReflect.Descriptor instead.
+func (*PackageMeasures) Descriptor() ([]byte, []int) {
+	return file_internal_transport_v1_pb_quote_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PackageMeasures) GetWeightKg() float64 {
+	if x != nil {
+		return x.WeightKg
+	}
+	return 0
+}
+
+func (x *PackageMeasures) GetDimensionsCm() *PackageDimensions {
+	if x != nil {
+		return x.DimensionsCm
+	}
+	return nil
+}
+
+// PackageDimensions are package dimensions in centimeters
+type PackageDimensions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Length float64 `protobuf:"fixed64,1,opt,name=length,proto3" json:"length,omitempty"`
+	Width  float64 `protobuf:"fixed64,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height float64 `protobuf:"fixed64,3,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (x *PackageDimensions) Reset() {
+	*x = PackageDimensions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PackageDimensions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackageDimensions) ProtoMessage() {}
+
+func (x *PackageDimensions) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackageDimensions.ProtoReflect.Descriptor instead.
+func (*PackageDimensions) Descriptor() ([]byte, []int) {
+	return file_internal_transport_v1_pb_quote_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PackageDimensions) GetLength() float64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *PackageDimensions) GetWidth() float64 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *PackageDimensions) GetHeight() float64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+// FuelIndex is the rate and effective date of the fuel surcharge index a quote was priced with
+type FuelIndex struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rate          float64 `protobuf:"fixed64,1,opt,name=rate,proto3" json:"rate,omitempty"`
+	EffectiveDate string  `protobuf:"bytes,2,opt,name=effective_date,json=effectiveDate,proto3" json:"effective_date,omitempty"`
+}
+
+func (x *FuelIndex) Reset() {
+	*x = FuelIndex{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FuelIndex) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FuelIndex) ProtoMessage() {}
+
+func (x *FuelIndex) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_transport_v1_pb_quote_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FuelIndex.ProtoReflect.Descriptor instead.
+func (*FuelIndex) Descriptor() ([]byte, []int) {
+	return file_internal_transport_v1_pb_quote_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FuelIndex) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *FuelIndex) GetEffectiveDate() string {
+	if x != nil {
+		return x.EffectiveDate
+	}
+	return ""
+}
+
+var File_internal_transport_v1_pb_quote_proto protoreflect.FileDescriptor
+
+var file_internal_transport_v1_pb_quote_proto_rawDesc = []byte{
+	0x0a, 0x24, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x70, 0x6f, 0x72, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x62, 0x2f, 0x71, 0x75, 0x6f, 0x74, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x73, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67,
+	0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd6, 0x05, 0x0a, 0x0d, 0x53, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e,
+	0x67, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x49,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x27, 0x0a,
+	0x0f, 0x70, 0x72, 0x69, 0x63, 0x69, 0x6e, 0x67, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x72, 0x69, 0x63, 0x69, 0x6e, 0x67, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65,
+	0x73, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41,
+	0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f,
+	0x73, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x73, 0x68, 0x69, 0x70, 0x70, 0x69,
+	0x6e, 0x67, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x36, 0x0a, 0x17, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74,
+	0x65, 0x64, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x2d,
+	0x0a, 0x12, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x11, 0x61, 0x76, 0x61, 0x69,
+	0x6c, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x46, 0x0a,
+	0x10, 0x73, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x68, 0x69, 0x70, 0x70, 0x69,
+	0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0f, 0x73, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x64, 0x6f,
+	0x77, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x73, 0x68, 0x69, 0x70, 0x70,
+	0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x73, 0x74, 0x42, 0x72, 0x65, 0x61, 0x6b,
+	0x64, 0x6f, 0x77, 0x6e, 0x52, 0x09, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x64, 0x6f, 0x77, 0x6e, 0x12,
+	0x29, 0x0a, 0x10, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x65, 0x6c, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x41, 0x0a, 0x0a, 0x65, 0x78,
+	0x70, 0x65, 0x72, 0x69, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21,
+	0x2e, 0x73, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70,
+	0x65, 0x72, 0x69, 0x6d, 0x65, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e,
+	0x74, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x65, 0x72, 0x69, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x36, 0x0a,
+	0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c,
+	0x2e, 0x73, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x63,
+	0x6b, 0x61, 0x67, 0x65, 0x4d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x73, 0x52, 0x07, 0x70, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x66, 0x75, 0x65, 0x6c, 0x5f, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73, 0x68, 0x69, 0x70,
+	0x70, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x75, 0x65, 0x6c, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x52, 0x09, 0x66, 0x75, 0x65, 0x6c, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1a, 0x0a, 0x08,
+	0x64, 0x65, 0x67, 0x72, 0x61, 0x64, 0x65, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
+	0x64, 0x65, 0x67, 0x72, 0x61, 0x64, 0x65, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65, 0x6c, 0x69,
+	0x76, 0x65, 0x72, 0x79, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x44, 0x61, 0x74, 0x65, 0x22, 0x95, 0x02,
+	0x0a, 0x0e, 0x53, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f,
+	0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69,
+	0x6d, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x64, 0x61, 0x79, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x65, 0x73, 0x74, 0x69,
+	0x6d, 0x61, 0x74, 0x65, 0x64, 0x44, 0x61, 0x79, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65,
+	0x61, 0x70, 0x65, 0x73, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x63, 0x68, 0x65,
+	0x61, 0x70, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x66, 0x61, 0x73, 0x74, 0x65, 0x73, 0x74,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x66, 0x61, 0x73, 0x74, 0x65, 0x73, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72,
+	0x79, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x65,
+	0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x44, 0x61, 0x74, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x77, 0x65,
+	0x65, 0x6b, 0x65, 0x6e, 0x64, 0x5f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x77, 0x65, 0x65, 0x6b, 0x65, 0x6e, 0x64, 0x44, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x79, 0x22, 0x95, 0x08, 0x0a, 0x0d, 0x43, 0x6f, 0x73, 0x74, 0x42, 0x72,
+	0x65, 0x61, 0x6b, 0x64, 0x6f, 0x77, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x73, 0x65, 0x5f,
+	0x63, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x62, 0x61, 0x73, 0x65,
+	0x43, 0x6f, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x73,
+	0x75, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f,
+	0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x53, 0x75, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x12,
+	0x29, 0x0a, 0x10, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x73, 0x75, 0x72, 0x63, 0x68, 0x61,
+	0x72, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x76, 0x6f, 0x6c, 0x75, 0x6d,
+	0x65, 0x53, 0x75, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x12, 0x34, 0x0a, 0x16, 0x70, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x73, 0x75, 0x72, 0x63, 0x68,
+	0x61, 0x72, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x14, 0x70, 0x61, 0x63, 0x6b,
+	0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x53, 0x75, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65,
+	0x12, 0x38, 0x0a, 0x18, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x5f, 0x61, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x16, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x54, 0x79, 0x70, 0x65,
+	0x41, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x65, 0x78,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x73, 0x75, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x53, 0x75,
+	0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x72, 0x65, 0x74, 0x75, 0x72,
+	0x6e, 0x5f, 0x61, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x10, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x41, 0x64, 0x6a, 0x75, 0x73, 0x74,
+	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x69, 0x63, 0x6b, 0x75, 0x70, 0x5f, 0x73,
+	0x75, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f,
+	0x70, 0x69, 0x63, 0x6b, 0x75, 0x70, 0x53, 0x75, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x12,
+	0x27, 0x0a, 0x0f, 0x72, 0x65, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x72,
+	0x65, 0x61, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x72, 0x65, 0x73, 0x74, 0x72, 0x69,
+	0x63, 0x74, 0x65, 0x64, 0x41, 0x72, 0x65, 0x61, 0x12, 0x3a, 0x0a, 0x19, 0x72, 0x65, 0x73, 0x74,
+	0x72, 0x69, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x72, 0x65, 0x61, 0x5f, 0x73, 0x75, 0x72, 0x63,
+	0x68, 0x61, 0x72, 0x67, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x17, 0x72, 0x65, 0x73,
+	0x74, 0x72, 0x69, 0x63, 0x74, 0x65, 0x64, 0x41, 0x72, 0x65, 0x61, 0x53, 0x75, 0x72, 0x63, 0x68,
+	0x61, 0x72, 0x67, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x66, 0x75, 0x65, 0x6c, 0x5f, 0x73, 0x75, 0x72,
+	0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x66, 0x75,
+	0x65, 0x6c, 0x53, 0x75, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x70,
+	0x72, 0x69, 0x63, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x70, 0x72, 0x69, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x34, 0x0a, 0x16,
+	0x70, 0x72, 0x69, 0x63, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x61, 0x64, 0x6a, 0x75,
+	0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01, 0x52, 0x14, 0x70, 0x72,
+	0x69, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x41, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65,
+	0x6e, 0x74, 0x12, 0x48, 0x0a, 0x13, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x73, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x46, 0x65, 0x65, 0x52, 0x12, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f,
+	0x75, 0x6e, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x65, 0x64, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x75, 0x6e, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x65, 0x64,
+	0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x2f, 0x0a, 0x13, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x5f, 0x61, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x12, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x41, 0x64, 0x6a, 0x75,
+	0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18,
+	0x11, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x2f, 0x0a, 0x06,
+	0x64, 0x75, 0x74, 0x69, 0x65, 0x73, 0x18, 0x12, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73,
+	0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x75, 0x74, 0x79, 0x43,
+	0x68, 0x61, 0x72, 0x67, 0x65, 0x52, 0x06, 0x64, 0x75, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1f, 0x0a,
+	0x0b, 0x6c, 0x61, 0x6e, 0x64, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x13, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0a, 0x6c, 0x61, 0x6e, 0x64, 0x65, 0x64, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x29,
+	0x0a, 0x03, 0x74, 0x61, 0x78, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x68,
+	0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x72, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x54, 0x61, 0x78, 0x52, 0x03, 0x74, 0x61, 0x78, 0x12, 0x2b, 0x0a, 0x11, 0x77, 0x65, 0x65,
+	0x6b, 0x65, 0x6e, 0x64, 0x5f, 0x73, 0x75, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x18, 0x15,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x77, 0x65, 0x65, 0x6b, 0x65, 0x6e, 0x64, 0x53, 0x75, 0x72,
+	0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x5f, 0x61, 0x72, 0x65, 0x61, 0x73, 0x18, 0x16, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x41, 0x72, 0x65, 0x61, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x72, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x5f, 0x61, 0x72, 0x65, 0x61, 0x5f, 0x73, 0x75, 0x72, 0x63, 0x68, 0x61, 0x72,
+	0x67, 0x65, 0x18, 0x17, 0x20, 0x01, 0x28, 0x01, 0x52, 0x13, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x41, 0x72, 0x65, 0x61, 0x53, 0x75, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x22, 0xe6, 0x01,
+	0x0a, 0x0a, 0x46, 0x72, 0x65, 0x69, 0x67, 0x68, 0x74, 0x54, 0x61, 0x78, 0x12, 0x10, 0x0a, 0x03,
+	0x74, 0x61, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x78, 0x12, 0x12,
+	0x0a, 0x04, 0x72, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x72, 0x61,
+	0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x5f, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x10, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x69,
+	0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x69,
+	0x70, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x73, 0x73, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6e, 0x65, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x03, 0x6e, 0x65, 0x74, 0x22, 0x4c, 0x0a, 0x0a, 0x44, 0x75, 0x74, 0x79, 0x43, 0x68,
+	0x61, 0x72, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x74, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x72, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x22, 0x38, 0x0a, 0x0a, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x46,
+	0x65, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x66, 0x65, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x66, 0x65, 0x65, 0x22, 0x3c,
+	0x0a, 0x14, 0x45, 0x78, 0x70, 0x65, 0x72, 0x69, 0x6d, 0x65, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x69,
+	0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x72,
+	0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x61, 0x72, 0x6d, 0x22, 0x73, 0x0a, 0x0f,
+	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x4d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x73, 0x12,
+	0x1b, 0x0a, 0x09, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x6b, 0x67, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x08, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x4b, 0x67, 0x12, 0x43, 0x0a, 0x0d,
+	0x64, 0x69, 0x6d, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x63, 0x6d, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x73, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x44, 0x69, 0x6d, 0x65, 0x6e, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x0c, 0x64, 0x69, 0x6d, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x43,
+	0x6d, 0x22, 0x59, 0x0a, 0x11, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x44, 0x69, 0x6d, 0x65,
+	0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x14,
+	0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x77,
+	0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x46, 0x0a, 0x09,
+	0x46, 0x75, 0x65, 0x6c, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x72, 0x61, 0x74, 0x65, 0x12, 0x25, 0x0a,
+	0x0e, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x44, 0x61, 0x74, 0x65, 0x42, 0x48, 0x5a, 0x46, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x72, 0x62, 0x6f, 0x6e, 0x66, 0x61, 0x6e, 0x74, 0x69, 0x2f, 0x73, 0x68, 0x69,
+	0x70, 0x70, 0x69, 0x6e, 0x67, 0x2d, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72,
+	0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70,
+	0x6f, 0x72, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x62, 0x3b, 0x76, 0x31, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_internal_transport_v1_pb_quote_proto_rawDescOnce sync.Once
+	file_internal_transport_v1_pb_quote_proto_rawDescData = file_internal_transport_v1_pb_quote_proto_rawDesc
+)
+
+func file_internal_transport_v1_pb_quote_proto_rawDescGZIP() []byte {
+	file_internal_transport_v1_pb_quote_proto_rawDescOnce.Do(func() {
+		file_internal_transport_v1_pb_quote_proto_rawDescData = protoimpl.X.CompressGZIP(file_internal_transport_v1_pb_quote_proto_rawDescData)
+	})
+	return file_internal_transport_v1_pb_quote_proto_rawDescData
+}
+
+var file_internal_transport_v1_pb_quote_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_internal_transport_v1_pb_quote_proto_goTypes = []any{
+	(*ShippingQuote)(nil),         // 0: shipping.v1.ShippingQuote
+	(*ShippingOption)(nil),        // 1: shipping.v1.ShippingOption
+	(*CostBreakdown)(nil),         // 2: shipping.v1.CostBreakdown
+	(*FreightTax)(nil),            // 3: shipping.v1.FreightTax
+	(*DutyCharge)(nil),            // 4: shipping.v1.DutyCharge
+	(*ServiceFee)(nil),            // 5: shipping.v1.ServiceFee
+	(*ExperimentAssignment)(nil),  // 6: shipping.v1.ExperimentAssignment
+	(*PackageMeasures)(nil),       // 7: shipping.v1.PackageMeasures
+	(*PackageDimensions)(nil),     // 8: shipping.v1.PackageDimensions
+	(*FuelIndex)(nil),             // 9: shipping.v1.FuelIndex
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+}
+var file_internal_transport_v1_pb_quote_proto_depIdxs = []int32{
+	10, // 0: shipping.v1.ShippingQuote.expires_at:type_name -> google.protobuf.Timestamp
+	1,  // 1: shipping.v1.ShippingQuote.shipping_options:type_name -> shipping.v1.ShippingOption
+	2,  // 2: shipping.v1.ShippingQuote.breakdown:type_name -> shipping.v1.CostBreakdown
+	6,  // 3: shipping.v1.ShippingQuote.experiment:type_name -> shipping.v1.ExperimentAssignment
+	7,  // 4: shipping.v1.ShippingQuote.package:type_name -> shipping.v1.PackageMeasures
+	9,  // 5: shipping.v1.ShippingQuote.fuel_index:type_name -> shipping.v1.FuelIndex
+	5,  // 6: shipping.v1.CostBreakdown.additional_services:type_name -> shipping.v1.ServiceFee
+	4,  // 7: shipping.v1.CostBreakdown.duties:type_name -> shipping.v1.DutyCharge
+	3,  // 8: shipping.v1.CostBreakdown.tax:type_name -> shipping.v1.FreightTax
+	8,  // 9: shipping.v1.PackageMeasures.dimensions_cm:type_name -> shipping.v1.PackageDimensions
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_internal_transport_v1_pb_quote_proto_init() }
+func file_internal_transport_v1_pb_quote_proto_init() {
+	if File_internal_transport_v1_pb_quote_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_internal_transport_v1_pb_quote_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*ShippingQuote); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_transport_v1_pb_quote_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*ShippingOption); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_transport_v1_pb_quote_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*CostBreakdown); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_transport_v1_pb_quote_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*FreightTax); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_transport_v1_pb_quote_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*DutyCharge); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_transport_v1_pb_quote_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*ServiceFee); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_transport_v1_pb_quote_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*ExperimentAssignment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_transport_v1_pb_quote_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*PackageMeasures); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_transport_v1_pb_quote_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*PackageDimensions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_transport_v1_pb_quote_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*FuelIndex); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_internal_transport_v1_pb_quote_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_internal_transport_v1_pb_quote_proto_goTypes,
+		DependencyIndexes: file_internal_transport_v1_pb_quote_proto_depIdxs,
+		MessageInfos:      file_internal_transport_v1_pb_quote_proto_msgTypes,
+	}.Build()
+	File_internal_transport_v1_pb_quote_proto = out.File
+	file_internal_transport_v1_pb_quote_proto_rawDesc = nil
+	file_internal_transport_v1_pb_quote_proto_goTypes = nil
+	file_internal_transport_v1_pb_quote_proto_depIdxs = nil
+}
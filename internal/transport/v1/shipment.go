@@ -0,0 +1,46 @@
+package v1
+
+import "time"
+
+// BookShipmentRequest converts a quote into a shipment
+type BookShipmentRequest struct {
+	QuoteID string `json:"quote_id" xml:"quote_id"`
+	// Service is the quoted service level to ship with; empty uses the level selected in the quote
+	Service string `json:"service,omitempty" xml:"service,omitempty"`
+}
+
+// Shipment is a booked shipment, priced by the quote it was booked from; Cost is in minor units
+// of Currency
+type Shipment struct {
+	ID                    string          `json:"id" xml:"id"`
+	QuoteID               string          `json:"quote_id" xml:"quote_id"`
+	Status                string          `json:"status" xml:"status"`
+	Service               string          `json:"service" xml:"service"`
+	Tenant                string          `json:"tenant,omitempty" xml:"tenant,omitempty"`
+	Currency              string          `json:"currency,omitempty" xml:"currency,omitempty"`
+	Cost                  float64         `json:"cost" xml:"cost"`
+	EstimatedDeliveryTime string          `json:"estimated_delivery_time" xml:"estimated_delivery_time"`
+	PricingVersion        string          `json:"pricing_version,omitempty" xml:"pricing_version,omitempty"`
+	PromisedDate          string          `json:"promised_date,omitempty" xml:"promised_date,omitempty"`
+	Package               ShipmentPackage `json:"package" xml:"package"`
+	BookedAt              time.Time       `json:"booked_at" xml:"booked_at"`
+}
+
+// ShipmentPackage is the package and route of a shipment, as quoted, and its scheduled pickup
+type ShipmentPackage struct {
+	OriginZipcode      string            `json:"origin_zipcode" xml:"origin_zipcode"`
+	DestinationZipcode string            `json:"destination_zipcode" xml:"destination_zipcode"`
+	DestinationCountry string            `json:"destination_country,omitempty" xml:"destination_country,omitempty"`
+	Weight             float64           `json:"weight" xml:"weight"`
+	Dimensions         PackageDimensions `json:"dimensions" xml:"dimensions"`
+	PackageType        string            `json:"package_type,omitempty" xml:"package_type,omitempty"`
+	DeliveryType       string            `json:"delivery_type,omitempty" xml:"delivery_type,omitempty"`
+	AdditionalServices []string          `json:"additional_services,omitempty" xml:"additional_services>service,omitempty"`
+	IsReturn           bool              `json:"is_return,omitempty" xml:"is_return,omitempty"`
+	FreightClass       string            `json:"freight_class,omitempty" xml:"freight_class,omitempty"`
+	PricingStrategy    string            `json:"pricing_strategy,omitempty" xml:"pricing_strategy,omitempty"`
+	WeightUnit         string            `json:"weight_unit,omitempty" xml:"weight_unit,omitempty"`
+	DimensionUnit      string            `json:"dimension_unit,omitempty" xml:"dimension_unit,omitempty"`
+	PickupDate         string            `json:"pickup_date,omitempty" xml:"pickup_date,omitempty"`
+	PickupWindow       string            `json:"pickup_window,omitempty" xml:"pickup_window,omitempty"`
+}
@@ -0,0 +1,271 @@
+// Package v1 contains the transport models of the v1 shipping API contract.
+// These types define the wire format only; conversions to the internal domain
+// model live in the mapper package. The xml tags mirror the JSON names for the
+// clients that negotiate XML.
+package v1
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// CalculateShippingRequest represents the input for shipping calculation
+type CalculateShippingRequest struct {
+	OriginZipcode        string            `json:"origin_zipcode" xml:"origin_zipcode" validate:"required,zipcode"`
+	DestinationZipcode   string            `json:"destination_zipcode" xml:"destination_zipcode" validate:"required,zipcode"`
+	Weight               float64           `json:"weight" xml:"weight" validate:"required,gt=0"`
+	Dimensions           PackageDimensions `json:"dimensions" xml:"dimensions" validate:"required"`
+	IsExpress            bool              `json:"is_express" xml:"is_express"`
+	DestinationCountry   string            `json:"destination_country,omitempty" xml:"destination_country,omitempty"`
+	Currency             string            `json:"currency,omitempty" xml:"currency,omitempty"`
+	PackageType          string            `json:"package_type,omitempty" xml:"package_type,omitempty"`
+	AdditionalServices   []string          `json:"additional_services,omitempty" xml:"additional_services>service,omitempty"`
+	DeliveryType         string            `json:"delivery_type,omitempty" xml:"delivery_type,omitempty"`
+	PricingStrategy      string            `json:"pricing_strategy,omitempty" xml:"pricing_strategy,omitempty"`
+	IsReturn             bool              `json:"is_return,omitempty" xml:"is_return,omitempty"`
+	PickupDate           string            `json:"pickup_date,omitempty" xml:"pickup_date,omitempty"`
+	PickupWindow         string            `json:"pickup_window,omitempty" xml:"pickup_window,omitempty"`
+	HSCode               string            `json:"hs_code,omitempty" xml:"hs_code,omitempty"`
+	DeclaredValue        float64           `json:"declared_value,omitempty" xml:"declared_value,omitempty"`
+	FreightClass         string            `json:"freight_class,omitempty" xml:"freight_class,omitempty"`
+	WeightUnit           string            `json:"weight_unit,omitempty" xml:"weight_unit,omitempty"`
+	DimensionUnit        string            `json:"dimension_unit,omitempty" xml:"dimension_unit,omitempty"`
+	Optimize             string            `json:"optimize,omitempty" xml:"optimize,omitempty"`
+	AllowWeekendDelivery bool              `json:"allow_weekend_delivery,omitempty" xml:"allow_weekend_delivery,omitempty"`
+	// MissingFields lists the required fields absent from the decoded JSON, which the zero values
+	// of Weight and Dimensions cannot tell apart from fields sent as 0
+	MissingFields []string `json:"-" xml:"-"`
+}
+
+// UnmarshalJSON decodes the request, recording in MissingFields whether weight and the
+// dimensions were absent or null
+func (r *CalculateShippingRequest) UnmarshalJSON(data []byte) error {
+	type plain CalculateShippingRequest
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	var present struct {
+		Weight     *float64 `json:"weight"`
+		Dimensions *struct {
+			Length *float64 `json:"length"`
+			Width  *float64 `json:"width"`
+			Height *float64 `json:"height"`
+		} `json:"dimensions"`
+	}
+	if err := json.Unmarshal(data, &present); err != nil {
+		return err
+	}
+	r.MissingFields = nil
+	if present.Weight == nil {
+		r.MissingFields = append(r.MissingFields, "weight")
+	}
+	if present.Dimensions == nil {
+		r.MissingFields = append(r.MissingFields, "dimensions")
+		return nil
+	}
+	for _, dimension := range []struct {
+		name  string
+		value *float64
+	}{
+		{"dimensions.length", present.Dimensions.Length},
+		{"dimensions.width", present.Dimensions.Width},
+		{"dimensions.height", present.Dimensions.Height},
+	} {
+		if dimension.value == nil {
+			r.MissingFields = append(r.MissingFields, dimension.name)
+		}
+	}
+	return nil
+}
+
+// Missing reports whether a required field was absent from the decoded JSON; it implements
+// validator.Presence
+func (r *CalculateShippingRequest) Missing(path string) bool {
+	return slices.Contains(r.MissingFields, path)
+}
+
+// PackageDimensions represents package dimensions in centimeters
+type PackageDimensions struct {
+	Length float64 `json:"length" xml:"length" validate:"required,positive"`
+	Width  float64 `json:"width" xml:"width" validate:"required,positive"`
+	Height float64 `json:"height" xml:"height" validate:"required,positive"`
+}
+
+// CalculateShippingResponse represents the output of shipping calculation
+type CalculateShippingResponse struct {
+	QuoteID               string                `json:"quote_id,omitempty" xml:"quote_id,omitempty"`
+	Currency              string                `json:"currency,omitempty" xml:"currency,omitempty"`
+	PricingVersion        string                `json:"pricing_version,omitempty" xml:"pricing_version,omitempty"`
+	ExpiresAt             *time.Time            `json:"expires_at,omitempty" xml:"expires_at,omitempty"`
+	ShippingCost          float64               `json:"shipping_cost" xml:"shipping_cost"`
+	EstimatedDeliveryTime string                `json:"estimated_delivery_time" xml:"estimated_delivery_time"`
+	AvailableServices     []string              `json:"available_services" xml:"available_services>service"`
+	ShippingOptions       []ShippingOption      `json:"shipping_options" xml:"shipping_options>option"`
+	Breakdown             *CostBreakdown        `json:"breakdown,omitempty" xml:"breakdown,omitempty"`
+	DeliveryDate          string                `json:"delivery_date,omitempty" xml:"delivery_date,omitempty"`
+	SelectedService       string                `json:"selected_service,omitempty" xml:"selected_service,omitempty"`
+	Experiment            *ExperimentAssignment `json:"experiment,omitempty" xml:"experiment,omitempty"`
+	Package               *PackageMeasures      `json:"package,omitempty" xml:"package,omitempty"`
+	FuelIndex             *FuelIndex            `json:"fuel_index,omitempty" xml:"fuel_index,omitempty"`
+	Degraded              bool                  `json:"degraded,omitempty" xml:"degraded,omitempty"`
+}
+
+// FuelIndex is the rate and effective date of the fuel surcharge index a quote was priced with
+type FuelIndex struct {
+	Rate          float64 `json:"rate" xml:"rate"`
+	EffectiveDate string  `json:"effective_date" xml:"effective_date"`
+}
+
+// PackageMeasures are the weight and dimensions of a package in kilograms and centimeters
+type PackageMeasures struct {
+	WeightKg     float64           `json:"weight_kg" xml:"weight_kg"`
+	DimensionsCm PackageDimensions `json:"dimensions_cm" xml:"dimensions_cm"`
+}
+
+// QuoteRevalidation is the result of repricing a persisted quote
+type QuoteRevalidation struct {
+	QuoteID      string                     `json:"quote_id" xml:"quote_id"`
+	Expired      bool                       `json:"expired" xml:"expired"`
+	PriceChanged bool                       `json:"price_changed" xml:"price_changed"`
+	PreviousCost float64                    `json:"previous_cost" xml:"previous_cost"`
+	Quote        *CalculateShippingResponse `json:"quote" xml:"quote"`
+}
+
+// PriceSubscription is the quote of a subscribed route and package; Version increases every time
+// its price changes
+type PriceSubscription struct {
+	SubscriptionID string                     `json:"subscription_id" xml:"subscription_id"`
+	Version        int                        `json:"version" xml:"version"`
+	ExpiresAt      time.Time                  `json:"expires_at" xml:"expires_at"`
+	Quote          *CalculateShippingResponse `json:"quote" xml:"quote"`
+}
+
+// QuotePreview is a quote priced as a dry run, with the decisions taken to price it
+type QuotePreview struct {
+	Quote *CalculateShippingResponse `json:"quote" xml:"quote"`
+	Trace []DecisionStep             `json:"trace" xml:"trace>step"`
+}
+
+// DecisionStep is a rule applied while pricing a quote
+type DecisionStep struct {
+	Step   string `json:"step" xml:"step"`
+	Detail string `json:"detail" xml:"detail"`
+}
+
+// QuoteExplanation explains step by step how the selected service of a quote was priced
+type QuoteExplanation struct {
+	Quote     *CalculateShippingResponse `json:"quote" xml:"quote"`
+	Inputs    ExplainedInputs            `json:"inputs" xml:"inputs"`
+	Route     ExplainedRoute             `json:"route" xml:"route"`
+	Service   string                     `json:"service" xml:"service"`
+	Strategy  string                     `json:"strategy" xml:"strategy"`
+	Charges   []ExplainedCharge          `json:"charges" xml:"charges>charge"`
+	Decisions []DecisionStep             `json:"decisions" xml:"decisions>step"`
+}
+
+// ExplainedInputs are the request values as used by the calculation
+type ExplainedInputs struct {
+	OriginZipcode      string  `json:"origin_zipcode" xml:"origin_zipcode"`
+	DestinationZipcode string  `json:"destination_zipcode" xml:"destination_zipcode"`
+	DestinationCountry string  `json:"destination_country" xml:"destination_country"`
+	Currency           string  `json:"currency" xml:"currency"`
+	WeightKg           float64 `json:"weight_kg" xml:"weight_kg"`
+	VolumeCm3          float64 `json:"volume_cm3" xml:"volume_cm3"`
+	PackageType        string  `json:"package_type" xml:"package_type"`
+	DeliveryType       string  `json:"delivery_type" xml:"delivery_type"`
+	IsExpress          bool    `json:"is_express" xml:"is_express"`
+	IsReturn           bool    `json:"is_return" xml:"is_return"`
+}
+
+// ExplainedRoute is the route priced, with its zone and zipcode distance
+type ExplainedRoute struct {
+	OriginZipcode      string  `json:"origin_zipcode" xml:"origin_zipcode"`
+	DestinationZipcode string  `json:"destination_zipcode" xml:"destination_zipcode"`
+	Zone               string  `json:"zone" xml:"zone"`
+	Distance           float64 `json:"distance" xml:"distance"`
+}
+
+// ExplainedCharge is a line of the cost with the formula and parameters that produced it
+type ExplainedCharge struct {
+	Name       string             `json:"name" xml:"name"`
+	Amount     float64            `json:"amount" xml:"amount"`
+	Formula    string             `json:"formula" xml:"formula"`
+	Parameters map[string]float64 `json:"parameters,omitempty" xml:"-"`
+}
+
+// ExperimentAssignment identifies the pricing experiment arm a quote was assigned to
+type ExperimentAssignment struct {
+	Name string `json:"name" xml:"name"`
+	Arm  string `json:"arm" xml:"arm"`
+}
+
+// CostBreakdown itemizes the cost of the selected service
+type CostBreakdown struct {
+	BaseCost                float64      `json:"base_cost" xml:"base_cost"`
+	WeightSurcharge         float64      `json:"weight_surcharge" xml:"weight_surcharge"`
+	VolumeSurcharge         float64      `json:"volume_surcharge" xml:"volume_surcharge"`
+	PackageTypeSurcharge    float64      `json:"package_type_surcharge" xml:"package_type_surcharge"`
+	DeliveryTypeAdjustment  float64      `json:"delivery_type_adjustment" xml:"delivery_type_adjustment"`
+	ExpressSurcharge        float64      `json:"express_surcharge" xml:"express_surcharge"`
+	ReturnAdjustment        float64      `json:"return_adjustment,omitempty" xml:"return_adjustment,omitempty"`
+	PickupSurcharge         float64      `json:"pickup_surcharge,omitempty" xml:"pickup_surcharge,omitempty"`
+	RestrictedArea          string       `json:"restricted_area,omitempty" xml:"restricted_area,omitempty"`
+	RestrictedAreaSurcharge float64      `json:"restricted_area_surcharge,omitempty" xml:"restricted_area_surcharge,omitempty"`
+	RemoteAreas             []string     `json:"remote_areas,omitempty" xml:"remote_area,omitempty"`
+	RemoteAreaSurcharge     float64      `json:"remote_area_surcharge,omitempty" xml:"remote_area_surcharge,omitempty"`
+	WeekendSurcharge        float64      `json:"weekend_surcharge,omitempty" xml:"weekend_surcharge,omitempty"`
+	FuelSurcharge           float64      `json:"fuel_surcharge,omitempty" xml:"fuel_surcharge,omitempty"`
+	PriceLimit              string       `json:"price_limit,omitempty" xml:"price_limit,omitempty"`
+	PriceLimitAdjustment    float64      `json:"price_limit_adjustment,omitempty" xml:"price_limit_adjustment,omitempty"`
+	AdditionalServices      []ServiceFee `json:"additional_services,omitempty" xml:"additional_services>fee,omitempty"`
+	UnroundedTotal          float64      `json:"unrounded_total" xml:"unrounded_total"`
+	RoundingAdjustment      float64      `json:"rounding_adjustment,omitempty" xml:"rounding_adjustment,omitempty"`
+	Total                   float64      `json:"total" xml:"total"`
+	Duties                  []DutyCharge `json:"duties,omitempty" xml:"duties>duty,omitempty"`
+	LandedCost              float64      `json:"landed_cost,omitempty" xml:"landed_cost,omitempty"`
+	Tax                     *FreightTax  `json:"tax,omitempty" xml:"tax,omitempty"`
+}
+
+// FreightTax is the ICMS or ISS included in the freight of a domestic route
+type FreightTax struct {
+	Tax              string  `json:"tax" xml:"tax"`
+	Rate             float64 `json:"rate" xml:"rate"`
+	OriginState      string  `json:"origin_state" xml:"origin_state"`
+	DestinationState string  `json:"destination_state" xml:"destination_state"`
+	Municipality     string  `json:"municipality,omitempty" xml:"municipality,omitempty"`
+	Gross            float64 `json:"gross" xml:"gross"`
+	Amount           float64 `json:"amount" xml:"amount"`
+	Net              float64 `json:"net" xml:"net"`
+}
+
+// DutyCharge is an estimated import duty or tax
+type DutyCharge struct {
+	Name   string  `json:"name" xml:"name"`
+	Rate   float64 `json:"rate" xml:"rate"`
+	Amount float64 `json:"amount" xml:"amount"`
+}
+
+// ServiceFee is the fee charged for an additional service
+type ServiceFee struct {
+	Service string  `json:"service" xml:"service"`
+	Fee     float64 `json:"fee" xml:"fee"`
+}
+
+// ShippingOption represents a shipping service option
+type ShippingOption struct {
+	Service string  `json:"service" xml:"service"`
+	Cost    float64 `json:"cost" xml:"cost"`
+	Time    string  `json:"time" xml:"time"`
+	// EstimatedDays are the delivery days of Time
+	EstimatedDays int `json:"estimated_days" xml:"estimated_days"`
+	// DeliveryDate is the local date ("YYYY-MM-DD") of the delivery at the destination
+	DeliveryDate string `json:"delivery_date,omitempty" xml:"delivery_date,omitempty"`
+	// WeekendDelivery marks the options delivering on weekends, with their weekend surcharge
+	WeekendDelivery bool `json:"weekend_delivery,omitempty" xml:"weekend_delivery,omitempty"`
+	// Cheapest and Fastest mark the options with the lowest cost and the fewest delivery days
+	Cheapest bool `json:"cheapest,omitempty" xml:"cheapest,omitempty"`
+	Fastest  bool `json:"fastest,omitempty" xml:"fastest,omitempty"`
+	// Token is a signed quote token vouching for the service, cost and expiration of the option
+	Token string `json:"token,omitempty" xml:"token,omitempty"`
+}
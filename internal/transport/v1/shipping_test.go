@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateShippingRequest_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantMissing []string
+	}{
+		{"complete", `{"weight": 1, "dimensions": {"length": 10, "width": 10, "height": 10}}`, nil},
+		{"zero values are present", `{"weight": 0, "dimensions": {"length": 0, "width": 0, "height": 0}}`, nil},
+		{"empty body", `{}`, []string{"weight", "dimensions"}},
+		{"null weight", `{"weight": null, "dimensions": {"length": 10, "width": 10, "height": 10}}`, []string{"weight"}},
+		{"missing dimension", `{"weight": 1, "dimensions": {"length": 10, "height": 10}}`, []string{"dimensions.width"}},
+		{"empty dimensions", `{"weight": 1, "dimensions": {}}`, []string{"dimensions.length", "dimensions.width", "dimensions.height"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := CalculateShippingRequest{MissingFields: []string{"stale"}}
+
+			// Act
+			err := json.Unmarshal([]byte(tt.body), &req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMissing, req.MissingFields)
+		})
+	}
+}
+
+func TestCalculateShippingRequest_UnmarshalJSON_Fields(t *testing.T) {
+	// Arrange
+	var req CalculateShippingRequest
+
+	// Act
+	err := json.Unmarshal([]byte(`{"origin_zipcode": "01310100", "weight": 2.5, "dimensions": {"length": 30, "width": 20, "height": 15}, "is_express": true}`), &req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, CalculateShippingRequest{
+		OriginZipcode: "01310100",
+		Weight:        2.5,
+		Dimensions:    PackageDimensions{Length: 30, Width: 20, Height: 15},
+		IsExpress:     true,
+	}, req)
+}
+
+func TestCalculateShippingRequest_UnmarshalJSON_Invalid(t *testing.T) {
+	// Arrange
+	var req CalculateShippingRequest
+
+	// Act
+	err := json.Unmarshal([]byte(`{"weight": "heavy"}`), &req)
+
+	// Assert
+	assert.Error(t, err)
+}
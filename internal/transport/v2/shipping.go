@@ -0,0 +1,122 @@
+// Package v2 contains the transport models of the v2 shipping API contract. v2 reports every
+// amount as a Money, exact and tagged with its currency, itemizes the cost of the selected service
+// with those amounts and lists the service levels with their own price and delivery time. The
+// request is unchanged from v1. Conversions to the internal domain model live in the mapper package.
+package v2
+
+import (
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+)
+
+// CalculateShippingRequest represents the input for shipping calculation, as in v1
+type CalculateShippingRequest = v1.CalculateShippingRequest
+
+// Money is an amount of money in the minor units of its currency (e.g. cents), exact to four
+// decimal places
+type Money struct {
+	Amount   money.Amount `json:"amount"`
+	Currency string       `json:"currency"`
+}
+
+// IsZero reports whether the amount is zero, so that the charges tagged omitzero are left out
+// whatever their currency
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// CalculateShippingResponse represents the output of shipping calculation
+type CalculateShippingResponse struct {
+	QuoteID        string     `json:"quote_id,omitempty"`
+	PricingVersion string     `json:"pricing_version,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	// Total is the price of the selected service
+	Total                 Money          `json:"total"`
+	SelectedService       string         `json:"selected_service,omitempty"`
+	EstimatedDeliveryTime string         `json:"estimated_delivery_time"`
+	DeliveryDate          string         `json:"delivery_date,omitempty"`
+	ServiceLevels         []ServiceLevel `json:"service_levels"`
+	Breakdown             *CostBreakdown `json:"breakdown,omitempty"`
+	Experiment            *Experiment    `json:"experiment,omitempty"`
+	Package               *Package       `json:"package,omitempty"`
+	FuelIndex             *FuelIndex     `json:"fuel_index,omitempty"`
+	Degraded              bool           `json:"degraded,omitempty"`
+}
+
+// ServiceLevel is a shipping service offered for the quote
+type ServiceLevel struct {
+	Service       string `json:"service"`
+	Price         Money  `json:"price"`
+	DeliveryTime  string `json:"delivery_time"`
+	EstimatedDays int    `json:"estimated_days"`
+	// DeliveryDate is the local date ("YYYY-MM-DD") of the delivery at the destination
+	DeliveryDate string `json:"delivery_date,omitempty"`
+	// WeekendDelivery marks the levels delivering on weekends, with their weekend surcharge
+	WeekendDelivery bool `json:"weekend_delivery,omitempty"`
+	// Cheapest and Fastest mark the levels with the lowest price and the fewest delivery days
+	Cheapest bool `json:"cheapest,omitempty"`
+	Fastest  bool `json:"fastest,omitempty"`
+	// Token is a signed quote token vouching for the service, price and expiration of the level
+	Token string `json:"token,omitempty"`
+}
+
+// Experiment, Package and FuelIndex are unchanged from v1
+type (
+	Experiment = v1.ExperimentAssignment
+	Package    = v1.PackageMeasures
+	FuelIndex  = v1.FuelIndex
+)
+
+// CostBreakdown itemizes the cost of the selected service; charges that do not apply are omitted
+type CostBreakdown struct {
+	BaseCost                Money        `json:"base_cost"`
+	WeightSurcharge         Money        `json:"weight_surcharge"`
+	VolumeSurcharge         Money        `json:"volume_surcharge"`
+	PackageTypeSurcharge    Money        `json:"package_type_surcharge"`
+	DeliveryTypeAdjustment  Money        `json:"delivery_type_adjustment"`
+	ExpressSurcharge        Money        `json:"express_surcharge"`
+	ReturnAdjustment        Money        `json:"return_adjustment,omitzero"`
+	PickupSurcharge         Money        `json:"pickup_surcharge,omitzero"`
+	RestrictedArea          string       `json:"restricted_area,omitempty"`
+	RestrictedAreaSurcharge Money        `json:"restricted_area_surcharge,omitzero"`
+	RemoteAreas             []string     `json:"remote_areas,omitempty"`
+	RemoteAreaSurcharge     Money        `json:"remote_area_surcharge,omitzero"`
+	WeekendSurcharge        Money        `json:"weekend_surcharge,omitzero"`
+	FuelSurcharge           Money        `json:"fuel_surcharge,omitzero"`
+	PriceLimit              string       `json:"price_limit,omitempty"`
+	PriceLimitAdjustment    Money        `json:"price_limit_adjustment,omitzero"`
+	AdditionalServices      []ServiceFee `json:"additional_services,omitempty"`
+	UnroundedTotal          Money        `json:"unrounded_total"`
+	RoundingAdjustment      Money        `json:"rounding_adjustment,omitzero"`
+	Total                   Money        `json:"total"`
+	Duties                  []DutyCharge `json:"duties,omitempty"`
+	LandedCost              Money        `json:"landed_cost,omitzero"`
+	Tax                     *FreightTax  `json:"tax,omitempty"`
+}
+
+// FreightTax is the ICMS or ISS included in the freight of a domestic route
+type FreightTax struct {
+	Tax              string  `json:"tax"`
+	Rate             float64 `json:"rate"`
+	OriginState      string  `json:"origin_state"`
+	DestinationState string  `json:"destination_state"`
+	Municipality     string  `json:"municipality,omitempty"`
+	Gross            Money   `json:"gross"`
+	Amount           Money   `json:"amount"`
+	Net              Money   `json:"net"`
+}
+
+// DutyCharge is an estimated import duty or tax
+type DutyCharge struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount Money   `json:"amount"`
+}
+
+// ServiceFee is the fee charged for an additional service
+type ServiceFee struct {
+	Service string `json:"service"`
+	Fee     Money  `json:"fee"`
+}
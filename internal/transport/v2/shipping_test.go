@@ -0,0 +1,45 @@
+package v2
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney_MarshalJSON(t *testing.T) {
+	// Arrange
+	m := Money{Amount: money.FromMinor(1437.5), Currency: "BRL"}
+
+	// Act
+	data, err := json.Marshal(m)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount": 1437.5, "currency": "BRL"}`, string(data))
+}
+
+func TestCostBreakdown_OmitsZeroCharges(t *testing.T) {
+	// Arrange
+	brl := func(minor float64) Money { return Money{Amount: money.FromMinor(minor), Currency: "BRL"} }
+	breakdown := CostBreakdown{
+		BaseCost:         brl(1000),
+		ReturnAdjustment: brl(0),
+		FuelSurcharge:    brl(50),
+		UnroundedTotal:   brl(1050),
+		Total:            brl(1050),
+	}
+
+	// Act
+	data, err := json.Marshal(breakdown)
+
+	// Assert
+	assert.NoError(t, err)
+	var fields map[string]any
+	assert.NoError(t, json.Unmarshal(data, &fields))
+	assert.NotContains(t, fields, "return_adjustment")
+	assert.NotContains(t, fields, "landed_cost")
+	assert.Equal(t, map[string]any{"amount": 50.0, "currency": "BRL"}, fields["fuel_surcharge"])
+	assert.Equal(t, map[string]any{"amount": 0.0, "currency": ""}, fields["weight_surcharge"])
+}
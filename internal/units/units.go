@@ -0,0 +1,76 @@
+// Package units converts package weights and dimensions to the canonical units of the
+// calculation: kilograms and centimeters.
+package units
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Weight units
+const (
+	Kilogram = "kg"
+	Gram     = "g"
+	Pound    = "lb"
+)
+
+// Dimension units
+const (
+	Centimeter = "cm"
+	Meter      = "m"
+	Inch       = "in"
+)
+
+// kilogramsPer and centimetersPer are the conversion factors of each unit; pounds and inches are
+// the exact international definitions
+var (
+	kilogramsPer   = map[string]float64{Kilogram: 1, Gram: 0.001, Pound: 0.45359237}
+	centimetersPer = map[string]float64{Centimeter: 1, Meter: 100, Inch: 2.54}
+)
+
+// ToKilograms converts weight in unit (kg, g or lb; empty is kg) to kilograms
+func ToKilograms(weight float64, unit string) (float64, error) {
+	factor, ok := kilogramsPer[canonical(unit, Kilogram)]
+	if !ok {
+		return 0, fmt.Errorf("unknown weight unit %q: must be kg, g or lb", unit)
+	}
+	return weight * factor, nil
+}
+
+// ToCentimeters converts a length in unit (cm, m or in; empty is cm) to centimeters
+func ToCentimeters(length float64, unit string) (float64, error) {
+	factor, ok := centimetersPer[canonical(unit, Centimeter)]
+	if !ok {
+		return 0, fmt.Errorf("unknown dimension unit %q: must be cm, m or in", unit)
+	}
+	return length * factor, nil
+}
+
+// WeightUnits returns the accepted weight units, sorted
+func WeightUnits() []string {
+	return sortedKeys(kilogramsPer)
+}
+
+// DimensionUnits returns the accepted dimension units, sorted
+func DimensionUnits() []string {
+	return sortedKeys(centimetersPer)
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// canonical lowercases unit, defaulting to def when it is empty
+func canonical(unit, def string) string {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if unit == "" {
+		return def
+	}
+	return unit
+}
@@ -0,0 +1,77 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToKilograms(t *testing.T) {
+	tests := []struct {
+		name    string
+		weight  float64
+		unit    string
+		want    float64
+		wantErr bool
+	}{
+		{"default unit", 2.5, "", 2.5, false},
+		{"kilograms", 2.5, "kg", 2.5, false},
+		{"grams", 1500, "g", 1.5, false},
+		{"pounds", 10, "lb", 4.5359237, false},
+		{"case insensitive", 10, " LB ", 4.5359237, false},
+		{"unknown unit", 1, "oz", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := ToKilograms(tt.weight, tt.unit)
+
+			// Assert
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "unknown weight unit")
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestToCentimeters(t *testing.T) {
+	tests := []struct {
+		name    string
+		length  float64
+		unit    string
+		want    float64
+		wantErr bool
+	}{
+		{"default unit", 30, "", 30, false},
+		{"centimeters", 30, "cm", 30, false},
+		{"meters", 0.3, "m", 30, false},
+		{"inches", 12, "in", 30.48, false},
+		{"case insensitive", 12, "IN", 30.48, false},
+		{"unknown unit", 1, "ft", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := ToCentimeters(tt.length, tt.unit)
+
+			// Assert
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "unknown dimension unit")
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestAcceptedUnits(t *testing.T) {
+	// Act & Assert
+	assert.Equal(t, []string{"g", "kg", "lb"}, WeightUnits())
+	assert.Equal(t, []string{"cm", "in", "m"}, DimensionUnits())
+}
@@ -0,0 +1,134 @@
+// Package usage counts the quote requests of each tenant and API key, for usage-based invoicing,
+// and enforces the monthly quotas of the tenants.
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+)
+
+// ErrQuotaExceeded is returned by Check when the tenant has used its monthly quota
+var ErrQuotaExceeded = errors.New("monthly quota exceeded")
+
+// Quota is the monthly quota of a tenant and how much of it was used in the current month
+type Quota struct {
+	Tenant       string `json:"tenant"`
+	MonthlyQuota int64  `json:"monthly_quota"`
+	Used         int64  `json:"used"`
+	// ResetsAt is the start of the next month, when the quota is renewed
+	ResetsAt time.Time `json:"resets_at"`
+}
+
+// Meter records the quote requests of the tenant of each request and checks them against its
+// monthly quota. Months are calendar months in UTC
+type Meter struct {
+	repo    repository.UsageRepository
+	tenants tenant.Config
+	now     func() time.Time
+}
+
+// NewMeter creates a meter recording usage in repo, with the monthly quotas of tenants
+func NewMeter(repo repository.UsageRepository, tenants tenant.Config) *Meter {
+	return &Meter{repo: repo, tenants: tenants, now: time.Now}
+}
+
+// Check returns ErrQuotaExceeded when the tenant of ctx has a monthly quota and has used it. The
+// check and the following Record are not atomic, so concurrent requests may exceed the quota by
+// a few requests
+func (m *Meter) Check(ctx context.Context) error {
+	id := tenant.FromContext(ctx)
+	quota := m.tenants.MonthlyQuota(id)
+	if quota <= 0 {
+		return nil
+	}
+	now := m.now().UTC()
+	used, err := m.repo.Total(ctx, id, monthStart(now), now)
+	if err != nil {
+		return err
+	}
+	if used >= quota {
+		return fmt.Errorf("%w: tenant %s used its %d quote requests of the month", ErrQuotaExceeded, id, quota)
+	}
+	return nil
+}
+
+// Record counts quotes quote requests of the tenant and API key of ctx
+func (m *Meter) Record(ctx context.Context, quotes int64) error {
+	if quotes <= 0 {
+		return nil
+	}
+	return m.repo.Increment(ctx, tenant.FromContext(ctx), tenant.APIKeyFromContext(ctx), quotes, m.now())
+}
+
+// ResetAt returns when the monthly quotas are renewed: the start of the next month
+func (m *Meter) ResetAt() time.Time {
+	return monthStart(m.now().UTC()).AddDate(0, 1, 0)
+}
+
+// Daily returns the usage of the days from from to to, inclusive, of the tenant or, when tenantID
+// is empty, of every tenant
+func (m *Meter) Daily(ctx context.Context, tenantID string, from, to time.Time) ([]model.UsageRecord, error) {
+	return m.repo.Daily(ctx, tenantID, from, to)
+}
+
+// Quotas returns the quotas of the tenants with a monthly quota and their usage this month, sorted
+// by tenant; when tenantID is not empty, only the quota of that tenant
+func (m *Meter) Quotas(ctx context.Context, tenantID string) ([]Quota, error) {
+	now := m.now().UTC()
+	quotas := make([]Quota, 0)
+	for _, id := range m.tenants.IDs() {
+		quota := m.tenants.MonthlyQuota(id)
+		if quota <= 0 || tenantID != "" && id != tenantID {
+			continue
+		}
+		used, err := m.repo.Total(ctx, id, monthStart(now), now)
+		if err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, Quota{Tenant: id, MonthlyQuota: quota, Used: used, ResetsAt: m.ResetAt()})
+	}
+	return quotas, nil
+}
+
+type quoteCountKey struct{}
+
+// QuoteCount is how many quotes a request priced, as reported by its handler with SetQuotes
+type QuoteCount struct {
+	quotes   int64
+	reported bool
+}
+
+// WithQuoteCount returns a context whose handler may report with SetQuotes how many quotes the
+// request priced, e.g. the rows of a batch
+func WithQuoteCount(ctx context.Context) (context.Context, *QuoteCount) {
+	count := &QuoteCount{}
+	return context.WithValue(ctx, quoteCountKey{}, count), count
+}
+
+// SetQuotes reports how many quotes the request of ctx priced; it does nothing for contexts not
+// created by WithQuoteCount
+func SetQuotes(ctx context.Context, quotes int) {
+	if count, ok := ctx.Value(quoteCountKey{}).(*QuoteCount); ok {
+		count.quotes, count.reported = int64(quotes), true
+	}
+}
+
+// Quotes returns the quotes reported by the handler, or 1 when it reported none, as for the
+// requests pricing a single quote
+func (c *QuoteCount) Quotes() int64 {
+	if !c.reported {
+		return 1
+	}
+	return c.quotes
+}
+
+// monthStart returns the first instant of the month of t
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
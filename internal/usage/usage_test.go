@@ -0,0 +1,143 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+var usageNow = time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+
+var usageTenants = tenant.Config{Tenants: map[string]tenant.Tenant{
+	"acme":   {PricingConfigPath: "acme.json", MonthlyQuota: 2},
+	"globex": {PricingConfigPath: "globex.json"},
+}}
+
+func newTestMeter(repo repository.UsageRepository) *Meter {
+	meter := NewMeter(repo, usageTenants)
+	meter.now = func() time.Time { return usageNow }
+	return meter
+}
+
+// failingUsageRepository is a UsageRepository whose operations always fail
+type failingUsageRepository struct{}
+
+func (failingUsageRepository) Increment(ctx context.Context, tenantID, apiKey string, quotes int64, at time.Time) error {
+	return errors.New("storage unavailable")
+}
+
+func (failingUsageRepository) Daily(ctx context.Context, tenantID string, from, to time.Time) ([]model.UsageRecord, error) {
+	return nil, errors.New("storage unavailable")
+}
+
+func (failingUsageRepository) Total(ctx context.Context, tenantID string, from, to time.Time) (int64, error) {
+	return 0, errors.New("storage unavailable")
+}
+
+func TestMeter_RecordAndDaily(t *testing.T) {
+	// Arrange
+	meter := newTestMeter(repository.NewMemoryUsageRepository())
+	ctx := tenant.NewAPIKeyContext(tenant.NewContext(context.Background(), "acme"), "sha256:abc")
+
+	// Act
+	recordErr := meter.Record(ctx, 1)
+	_ = meter.Record(context.Background(), 1)
+	_ = meter.Record(ctx, 40)
+	_ = meter.Record(ctx, 0)
+	records, err := meter.Daily(context.Background(), "", usageNow, usageNow)
+
+	// Assert
+	assert.NoError(t, recordErr)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.UsageRecord{
+		{Day: "2025-03-10", Tenant: "acme", APIKey: "sha256:abc", Quotes: 41},
+		{Day: "2025-03-10", Tenant: tenant.Default, Quotes: 1},
+	}, records)
+}
+
+func TestQuoteCount(t *testing.T) {
+	// Arrange
+	ctx, count := WithQuoteCount(context.Background())
+	_, unreported := WithQuoteCount(context.Background())
+
+	// Act
+	SetQuotes(ctx, 250)
+	SetQuotes(context.Background(), 10)
+
+	// Assert
+	assert.Equal(t, int64(250), count.Quotes())
+	assert.Equal(t, int64(1), unreported.Quotes(), "requests count as one quote by default")
+}
+
+func TestMeter_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		tenantID string
+		previous []time.Time
+		wantErr  error
+	}{
+		{"under quota", "acme", []time.Time{usageNow}, nil},
+		{"quota used", "acme", []time.Time{usageNow, usageNow.AddDate(0, 0, -9)}, ErrQuotaExceeded},
+		{"usage of the previous month", "acme", []time.Time{usageNow.AddDate(0, -1, 0), usageNow.AddDate(0, 0, -10)}, nil},
+		{"tenant without quota", "globex", []time.Time{usageNow, usageNow, usageNow}, nil},
+		{"default tenant", tenant.Default, []time.Time{usageNow, usageNow, usageNow}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repo := repository.NewMemoryUsageRepository()
+			for _, at := range tt.previous {
+				_ = repo.Increment(context.Background(), tt.tenantID, "", 1, at)
+			}
+			meter := newTestMeter(repo)
+
+			// Act
+			err := meter.Check(tenant.NewContext(context.Background(), tt.tenantID))
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMeter_Check_RepositoryError(t *testing.T) {
+	// Arrange
+	meter := newTestMeter(failingUsageRepository{})
+
+	// Act
+	err := meter.Check(tenant.NewContext(context.Background(), "acme"))
+
+	// Assert
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrQuotaExceeded)
+}
+
+func TestMeter_Quotas(t *testing.T) {
+	// Arrange
+	repo := repository.NewMemoryUsageRepository()
+	_ = repo.Increment(context.Background(), "acme", "", 1, usageNow)
+	meter := newTestMeter(repo)
+	resetsAt := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	all, allErr := meter.Quotas(context.Background(), "")
+	globex, globexErr := meter.Quotas(context.Background(), "globex")
+
+	// Assert
+	assert.NoError(t, allErr)
+	assert.NoError(t, globexErr)
+	assert.Equal(t, []Quota{{Tenant: "acme", MonthlyQuota: 2, Used: 1, ResetsAt: resetsAt}}, all)
+	assert.Empty(t, globex)
+	assert.Equal(t, resetsAt, meter.ResetAt())
+}
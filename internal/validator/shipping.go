@@ -2,43 +2,52 @@ package validator
 
 import (
 	"fmt"
-	"strings"
-	"unicode"
+	"math"
+	"slices"
+
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
 )
 
 const (
-	maxVolumeCm3     = 15000.0
-	minWeight        = 0.0
-	zipcodeLength    = 8
-	minZipcodeLength = 4
+	// MaxVolumeCm3 is the maximum package volume accepted, in cm³
+	MaxVolumeCm3 = 15000.0
+	// MinZipcodeLength and ZipcodeLength bound the number of digits of a zipcode
+	MinZipcodeLength = zipcode.MinLength
+	ZipcodeLength    = zipcode.Length
+
+	minWeight = 0.0
 )
 
 // ValidateZipcode validates Brazilian zipcode format without using regex to avoid ReDoS vulnerabilities
-func ValidateZipcode(zipcode, fieldName string) error {
-	if zipcode == "" {
+func ValidateZipcode(value, fieldName string) error {
+	if value == "" {
 		return fmt.Errorf("%s is required", fieldName)
 	}
 
-	// Normalize zipcode (remove hyphens and spaces)
-	normalized := strings.ReplaceAll(strings.ReplaceAll(zipcode, "-", ""), " ", "")
-
-	// Validate length (must be at least 4 digits and at most 8 digits)
-	if len(normalized) < minZipcodeLength || len(normalized) > zipcodeLength {
+	// Validate separators-free length and digits (manual check to avoid regex backtracking)
+	if !zipcode.Valid(value) {
 		return fmt.Errorf("%s must be a valid zipcode format (4-8 digits)", fieldName)
 	}
 
-	// Validate that all characters are digits (manual check to avoid regex backtracking)
-	for _, char := range normalized {
-		if !unicode.IsDigit(char) {
-			return fmt.Errorf("%s must be a valid zipcode format (4-8 digits)", fieldName)
+	return nil
+}
+
+// ValidateRequired returns a "<field> is required" error for the first of fields listed in
+// missing, the fields absent from the request
+func ValidateRequired(missing []string, fields ...string) error {
+	for _, field := range fields {
+		if slices.Contains(missing, field) {
+			return fmt.Errorf("%s is required", field)
 		}
 	}
-
 	return nil
 }
 
-// ValidateWeight validates that weight is positive
+// ValidateWeight validates that weight is a positive finite number
 func ValidateWeight(weight float64) error {
+	if !isFinite(weight) {
+		return fmt.Errorf("weight must be a finite number")
+	}
 	if weight <= minWeight {
 		return fmt.Errorf("weight must be greater than 0")
 	}
@@ -47,24 +56,46 @@ func ValidateWeight(weight float64) error {
 
 // ValidateDimensions validates that dimensions are positive and volume doesn't exceed limit
 func ValidateDimensions(length, width, height float64) error {
-	if length <= 0 {
-		return fmt.Errorf("dimensions.length must be positive")
-	}
-	if width <= 0 {
-		return fmt.Errorf("dimensions.width must be positive")
-	}
-	if height <= 0 {
-		return fmt.Errorf("dimensions.height must be positive")
+	if err := ValidatePositiveDimensions(length, width, height); err != nil {
+		return err
 	}
+	return ValidateVolume(CalculateVolume(length, width, height), MaxVolumeCm3)
+}
 
-	volume := length * width * height
-	if volume > maxVolumeCm3 {
-		return fmt.Errorf("package volume (%.2f cm³) exceeds maximum allowed volume (%.2f cm³)", volume, maxVolumeCm3)
+// ValidatePositiveDimensions validates that dimensions are positive finite numbers
+func ValidatePositiveDimensions(length, width, height float64) error {
+	for _, dimension := range []struct {
+		name  string
+		value float64
+	}{
+		{"dimensions.length", length},
+		{"dimensions.width", width},
+		{"dimensions.height", height},
+	} {
+		if !isFinite(dimension.value) {
+			return fmt.Errorf("%s must be a finite number", dimension.name)
+		}
+		if dimension.value <= 0 {
+			return fmt.Errorf("%s must be positive", dimension.name)
+		}
 	}
+	return nil
+}
 
+// ValidateVolume validates that volume doesn't exceed maxVolume, e.g. MaxVolumeCm3 for parcels
+func ValidateVolume(volume, maxVolume float64) error {
+	if volume > maxVolume {
+		return fmt.Errorf("package volume (%.2f cm³) exceeds maximum allowed volume (%.2f cm³)", volume, maxVolume)
+	}
 	return nil
 }
 
+// isFinite reports whether value is neither NaN nor infinite, which would make every cost
+// derived from it meaningless
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
 // CalculateVolume calculates the volume in cm³ from dimensions
 func CalculateVolume(length, width, height float64) float64 {
 	return length * width * height
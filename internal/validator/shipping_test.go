@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,6 +28,11 @@ func TestValidateZipcode_ValidCases(t *testing.T) {
 			zipcode:   "12345 678",
 			fieldName: "origin_zipcode",
 		},
+		{
+			name:      "valid zipcode with dot and hyphen",
+			zipcode:   "01.310-100",
+			fieldName: "destination_zipcode",
+		},
 		{
 			name:      "valid zipcode with 4 digits",
 			zipcode:   "1414",
@@ -157,6 +163,16 @@ func TestValidateWeight_InvalidCases(t *testing.T) {
 			weight:      -1.0,
 			expectedErr: "weight must be greater than 0",
 		},
+		{
+			name:        "NaN weight",
+			weight:      math.NaN(),
+			expectedErr: "weight must be a finite number",
+		},
+		{
+			name:        "infinite weight",
+			weight:      math.Inf(1),
+			expectedErr: "weight must be a finite number",
+		},
 	}
 
 	for _, tt := range tests {
@@ -174,6 +190,55 @@ func TestValidateWeight_InvalidCases(t *testing.T) {
 	}
 }
 
+func TestValidateRequired(t *testing.T) {
+	tests := []struct {
+		name        string
+		missing     []string
+		fields      []string
+		expectedErr string
+	}{
+		{
+			name:    "nothing missing",
+			missing: nil,
+			fields:  []string{"weight"},
+		},
+		{
+			name:    "other field missing",
+			missing: []string{"dimensions"},
+			fields:  []string{"weight"},
+		},
+		{
+			name:        "field missing",
+			missing:     []string{"weight"},
+			fields:      []string{"weight"},
+			expectedErr: "weight is required",
+		},
+		{
+			name:        "first missing field reported",
+			missing:     []string{"dimensions.height", "dimensions.width"},
+			fields:      []string{"dimensions", "dimensions.length", "dimensions.width", "dimensions.height"},
+			expectedErr: "dimensions.width is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			// (no setup needed)
+
+			// Act
+			err := ValidateRequired(tt.missing, tt.fields...)
+
+			// Assert
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
 func TestValidateDimensions_ValidCases(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -231,6 +296,20 @@ func TestValidateDimensions_InvalidCases(t *testing.T) {
 			height:      10.0,
 			expectedErr: "dimensions.length must be positive",
 		},
+		{
+			name:        "NaN length",
+			length:      math.NaN(),
+			width:       10.0,
+			height:      10.0,
+			expectedErr: "dimensions.length must be a finite number",
+		},
+		{
+			name:        "infinite height",
+			length:      10.0,
+			width:       10.0,
+			height:      math.Inf(-1),
+			expectedErr: "dimensions.height must be a finite number",
+		},
 		{
 			name:        "zero width",
 			length:      10.0,
@@ -283,6 +362,49 @@ func TestValidateDimensions_InvalidCases(t *testing.T) {
 	}
 }
 
+func TestValidateVolume(t *testing.T) {
+	tests := []struct {
+		name        string
+		volume      float64
+		maxVolume   float64
+		expectedErr string
+	}{
+		{
+			name:      "volume within parcel limit",
+			volume:    15000.0,
+			maxVolume: MaxVolumeCm3,
+		},
+		{
+			name:        "volume over parcel limit",
+			volume:      18000.0,
+			maxVolume:   MaxVolumeCm3,
+			expectedErr: "package volume (18000.00 cm³) exceeds maximum allowed volume (15000.00 cm³)",
+		},
+		{
+			name:      "volume within a larger limit",
+			volume:    18000.0,
+			maxVolume: 2000000.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			// (no setup needed)
+
+			// Act
+			err := ValidateVolume(tt.volume, tt.maxVolume)
+
+			// Assert
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
 func TestCalculateVolume(t *testing.T) {
 	tests := []struct {
 		name     string
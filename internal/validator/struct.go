@@ -0,0 +1,265 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+)
+
+// Tag is the struct tag declaring the rules of a field, comma-separated and checked in order:
+//
+//	required  the field is present: a non-empty string, or a number or struct the request
+//	          decoded from JSON (see Presence); other kinds are always present
+//	zipcode   a zipcode of 4 to 8 digits, with or without separators; empty strings pass
+//	gt=N      a finite number greater than N
+//	positive  a finite number greater than 0
+//	max=N     a number up to N, or a string or slice of up to N elements
+//
+// Struct fields and slices of structs are validated recursively, with the field paths joined by
+// dots, e.g. "dimensions.length" or "items[2].weight"
+const Tag = "validate"
+
+// Presence is implemented by requests that record the fields absent from the decoded JSON, which
+// the zero values of numbers and structs cannot tell apart from fields sent as 0
+type Presence interface {
+	Missing(path string) bool
+}
+
+// FieldError is a field that breaks a rule, reported as "<field> <message>", e.g. "weight must
+// be greater than 0"
+type FieldError struct {
+	// Field is the path of the field, e.g. "dimensions.height"
+	Field string
+	// Rule is the rule broken, e.g. "gt"
+	Rule    string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// FieldErrors are the fields of a request that break their rules, in the order of the fields
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Struct validates the fields of v, a struct or a pointer to one, against their Tag rules. It
+// returns FieldErrors with the first rule broken by each field, or nil when all of them pass
+func Struct(v any) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	presence, _ := v.(Presence)
+	var errs FieldErrors
+	validateStruct(value, "", presence, &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// fieldRules are the rules of a struct field
+type fieldRules struct {
+	index int
+	name  string
+	rules []rule
+}
+
+// rule is a parsed rule of Tag; param is the number of gt and max
+type rule struct {
+	name  string
+	param float64
+}
+
+// structRules caches the parsed rules of each struct type
+var structRules sync.Map
+
+// rulesOf returns the fields of a struct type with rules or nested structs to validate
+func rulesOf(t reflect.Type) []fieldRules {
+	if cached, ok := structRules.Load(t); ok {
+		return cached.([]fieldRules)
+	}
+	var fields []fieldRules
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, fieldRules{index: i, name: name, rules: parseRules(t, field)})
+	}
+	structRules.Store(t, fields)
+	return fields
+}
+
+// parseRules parses the Tag of a field; unknown rules and malformed parameters are programming
+// errors and panic on the first validation of the type
+func parseRules(t reflect.Type, field reflect.StructField) []rule {
+	tag := field.Tag.Get(Tag)
+	if tag == "" {
+		return nil
+	}
+	var rules []rule
+	for _, part := range strings.Split(tag, ",") {
+		name, param, hasParam := strings.Cut(strings.TrimSpace(part), "=")
+		r := rule{name: name}
+		switch name {
+		case "required", "zipcode", "positive":
+			if hasParam {
+				panic(fmt.Sprintf("validator: rule %q of %s.%s takes no parameter", name, t, field.Name))
+			}
+		case "gt", "max":
+			value, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				panic(fmt.Sprintf("validator: rule %q of %s.%s needs a numeric parameter", name, t, field.Name))
+			}
+			r.param = value
+		default:
+			panic(fmt.Sprintf("validator: unknown rule %q of %s.%s", name, t, field.Name))
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// validateStruct validates the fields of a struct value, prefixing their paths with prefix
+func validateStruct(value reflect.Value, prefix string, presence Presence, errs *FieldErrors) {
+	for _, field := range rulesOf(value.Type()) {
+		path := field.name
+		if prefix != "" {
+			path = prefix + "." + field.name
+		}
+		fieldValue := value.Field(field.index)
+		if err := checkRules(fieldValue, path, field.rules, presence); err != nil {
+			*errs = append(*errs, err)
+			continue
+		}
+		validateNested(fieldValue, path, presence, errs)
+	}
+}
+
+// validateNested validates the structs a field holds, directly or in a slice
+func validateNested(value reflect.Value, path string, presence Presence, errs *FieldErrors) {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if !value.IsNil() {
+			validateNested(value.Elem(), path, presence, errs)
+		}
+	case reflect.Struct:
+		validateStruct(value, path, presence, errs)
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			validateNested(value.Index(i), fmt.Sprintf("%s[%d]", path, i), presence, errs)
+		}
+	}
+}
+
+// checkRules returns the error of the first rule the value breaks. A field reported missing
+// fails required and skips the other rules, as do empty optional strings
+func checkRules(value reflect.Value, path string, rules []rule, presence Presence) *FieldError {
+	for _, r := range rules {
+		if r.name == "required" {
+			if !isPresent(value, path, presence) {
+				return &FieldError{Field: path, Rule: r.name, Message: "is required"}
+			}
+			continue
+		}
+		if presence != nil && presence.Missing(path) {
+			return nil
+		}
+		if message, ok := check(value, r); !ok {
+			return &FieldError{Field: path, Rule: r.name, Message: message}
+		}
+	}
+	return nil
+}
+
+// isPresent reports whether a field was sent: strings must not be empty, and numbers and structs
+// must not be missing from the decoded request
+func isPresent(value reflect.Value, path string, presence Presence) bool {
+	if value.Kind() == reflect.String {
+		return value.Len() > 0
+	}
+	return presence == nil || !presence.Missing(path)
+}
+
+// check returns the message of a rule the value breaks; ok is true when it passes or does not
+// apply to the kind of the value
+func check(value reflect.Value, r rule) (message string, ok bool) {
+	switch r.name {
+	case "zipcode":
+		if value.Kind() == reflect.String && value.Len() > 0 && !zipcode.Valid(value.String()) {
+			return "must be a valid zipcode format (4-8 digits)", false
+		}
+	case "gt":
+		number, isNumber := numberOf(value)
+		if isNumber && !isFinite(number) {
+			return "must be a finite number", false
+		}
+		if isNumber && number <= r.param {
+			return "must be greater than " + formatParam(r.param), false
+		}
+	case "positive":
+		number, isNumber := numberOf(value)
+		if isNumber && !isFinite(number) {
+			return "must be a finite number", false
+		}
+		if isNumber && number <= 0 {
+			return "must be positive", false
+		}
+	case "max":
+		switch value.Kind() {
+		case reflect.String, reflect.Slice, reflect.Array:
+			if float64(value.Len()) > r.param {
+				return "must have at most " + formatParam(r.param) + " elements", false
+			}
+		default:
+			if number, isNumber := numberOf(value); isNumber && !(number <= r.param) {
+				return "must be at most " + formatParam(r.param), false
+			}
+		}
+	}
+	return "", true
+}
+
+// numberOf returns the value of a numeric field
+func numberOf(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	}
+	return 0, false
+}
+
+// formatParam formats the parameter of a rule without trailing zeros
+func formatParam(param float64) string {
+	return strconv.FormatFloat(param, 'f', -1, 64)
+}
@@ -0,0 +1,128 @@
+package validator
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDimensions struct {
+	Length float64 `json:"length" validate:"required,positive"`
+	Height float64 `json:"height" validate:"required,positive"`
+}
+
+type testItem struct {
+	Weight float64 `json:"weight" validate:"gt=0,max=30"`
+}
+
+type testRequest struct {
+	Zipcode    string         `json:"zipcode" validate:"required,zipcode"`
+	Optional   string         `json:"optional,omitempty" validate:"zipcode"`
+	Weight     float64        `json:"weight" validate:"required,gt=0"`
+	Dimensions testDimensions `json:"dimensions" validate:"required"`
+	Items      []testItem     `json:"items" validate:"max=2"`
+	Ignored    string         `json:"-" validate:"required"`
+	missing    []string
+}
+
+func (r *testRequest) Missing(path string) bool {
+	return slices.Contains(r.missing, path)
+}
+
+func validTestRequest() *testRequest {
+	return &testRequest{
+		Zipcode:    "01310-100",
+		Weight:     1,
+		Dimensions: testDimensions{Length: 10, Height: 10},
+		Items:      []testItem{{Weight: 1}},
+	}
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *testRequest)
+		want   []string
+	}{
+		{"valid request", func(r *testRequest) {}, nil},
+		{"missing string", func(r *testRequest) { r.Zipcode = "" }, []string{"zipcode is required"}},
+		{"invalid zipcode", func(r *testRequest) { r.Zipcode = "123" }, []string{"zipcode must be a valid zipcode format (4-8 digits)"}},
+		{"invalid optional zipcode", func(r *testRequest) { r.Optional = "abc" }, []string{"optional must be a valid zipcode format (4-8 digits)"}},
+		{"zero weight", func(r *testRequest) { r.Weight = 0 }, []string{"weight must be greater than 0"}},
+		{"NaN weight", func(r *testRequest) { r.Weight = math.NaN() }, []string{"weight must be a finite number"}},
+		{"infinite dimension", func(r *testRequest) { r.Dimensions.Height = math.Inf(1) }, []string{"dimensions.height must be a finite number"}},
+		{"NaN over the maximum", func(r *testRequest) { r.Items = []testItem{{Weight: math.NaN()}} }, []string{"items[0].weight must be a finite number"}},
+		{"missing weight", func(r *testRequest) { r.Weight, r.missing = 0, []string{"weight"} }, []string{"weight is required"}},
+		{"missing dimensions", func(r *testRequest) {
+			r.Dimensions, r.missing = testDimensions{}, []string{"dimensions", "dimensions.length", "dimensions.height"}
+		}, []string{"dimensions is required"}},
+		{"nested dimensions", func(r *testRequest) {
+			r.Dimensions.Length, r.Dimensions.Height, r.missing = -1, 0, []string{"dimensions.height"}
+		}, []string{"dimensions.length must be positive", "dimensions.height is required"}},
+		{"slice elements", func(r *testRequest) { r.Items = []testItem{{Weight: 1}, {Weight: 31}} }, []string{"items[1].weight must be at most 30"}},
+		{"slice length", func(r *testRequest) { r.Items = make([]testItem, 3) }, []string{"items must have at most 2 elements"}},
+		{"every invalid field", func(r *testRequest) { r.Zipcode, r.Weight = "", -1 }, []string{"zipcode is required", "weight must be greater than 0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := validTestRequest()
+			tt.modify(req)
+
+			// Act
+			err := Struct(req)
+
+			// Assert
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var fieldErrs FieldErrors
+			if assert.True(t, errors.As(err, &fieldErrs)) {
+				messages := make([]string, len(fieldErrs))
+				for i, fieldErr := range fieldErrs {
+					messages[i] = fieldErr.Error()
+				}
+				assert.Equal(t, tt.want, messages)
+			}
+		})
+	}
+}
+
+func TestStruct_WithoutPresence(t *testing.T) {
+	// Arrange
+	req := struct {
+		Dimensions testDimensions `json:"dimensions" validate:"required"`
+	}{}
+
+	// Act
+	err := Struct(req)
+
+	// Assert
+	assert.EqualError(t, err, "dimensions.length must be positive; dimensions.height must be positive")
+}
+
+func TestStruct_NotAStruct(t *testing.T) {
+	// Arrange
+	var nilRequest *testRequest
+
+	// Act & Assert
+	assert.NoError(t, Struct(nilRequest))
+	assert.NoError(t, Struct("text"))
+}
+
+func TestStruct_InvalidTag(t *testing.T) {
+	// Arrange
+	req := struct {
+		Weight float64 `validate:"gt=heavy"`
+	}{}
+
+	// Act & Assert
+	assert.PanicsWithValue(t, `validator: rule "gt" of struct { Weight float64 "validate:\"gt=heavy\"" }.Weight needs a numeric parameter`, func() {
+		_ = Struct(req)
+	})
+}
@@ -0,0 +1,102 @@
+package warmup
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+)
+
+// quotesTrackedPerTop bounds the quotes a tracker counts to this many per top quote, with a floor
+// of minTrackedQuotes
+const (
+	quotesTrackedPerTop = 50
+	minTrackedQuotes    = 1000
+)
+
+// trackedQuote is a quote counted by a tracker
+type trackedQuote struct {
+	quote Quote
+	count int
+}
+
+// Tracker counts the quotes of each request to learn the most quoted ones. When it counts too many
+// requests the counts are halved and the requests left without quotes are dropped, so recent
+// traffic outweighs older traffic. It is safe for concurrent use
+type Tracker struct {
+	mu     sync.Mutex
+	counts map[string]*trackedQuote
+	limit  int
+}
+
+// NewTracker creates a tracker learning the top quotes; it records nothing when top is 0
+func NewTracker(top int) *Tracker {
+	limit := 0
+	if top > 0 {
+		limit = max(top*quotesTrackedPerTop, minTrackedQuotes)
+	}
+	return &Tracker{counts: make(map[string]*trackedQuote), limit: limit}
+}
+
+// Record counts a quote of a request
+func (t *Tracker) Record(quote Quote) {
+	if t.limit == 0 || !validZipcode(zipcode.Normalize(quote.Request.OriginZipcode)) || !validZipcode(zipcode.Normalize(quote.Request.DestinationZipcode)) {
+		return
+	}
+	key, err := quote.key()
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tracked, ok := t.counts[key]
+	if !ok {
+		tracked = &trackedQuote{quote: quote}
+		t.counts[key] = tracked
+	}
+	tracked.count++
+	if len(t.counts) > t.limit {
+		for key, tracked := range t.counts {
+			if tracked.count /= 2; tracked.count == 0 {
+				delete(t.counts, key)
+			}
+		}
+	}
+}
+
+// Top returns the n most quoted requests, the most quoted first; ties are ordered by their key
+func (t *Tracker) Top(n int) []Quote {
+	type entry struct {
+		key   string
+		quote Quote
+		count int
+	}
+	t.mu.Lock()
+	entries := make([]entry, 0, len(t.counts))
+	for key, tracked := range t.counts {
+		entries = append(entries, entry{key: key, quote: tracked.quote, count: tracked.count})
+	}
+	t.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].key < entries[j].key
+	})
+	quotes := make([]Quote, 0, min(n, len(entries)))
+	for _, e := range entries[:min(n, len(entries))] {
+		quotes = append(quotes, e.quote)
+	}
+	return quotes
+}
+
+// key identifies the request of a quote and its tenant
+func (q Quote) key() (string, error) {
+	data, err := json.Marshal(q)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
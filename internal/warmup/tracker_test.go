@@ -0,0 +1,53 @@
+package warmup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker_Top(t *testing.T) {
+	// Arrange
+	tracker := NewTracker(2)
+	belo := Route{Origin: "01310100", Destination: "30130000"}.quote()
+	partial := Route{Origin: "0131", Destination: "04547130"}.quote()
+	for _, quote := range []Quote{rio.quote(), paulista.quote(), rio.quote(), belo, paulista.quote(), rio.quote(), partial} {
+		tracker.Record(quote)
+	}
+
+	// Act
+	top := tracker.Top(2)
+
+	// Assert
+	assert.Equal(t, []Quote{rio.quote(), paulista.quote()}, top)
+}
+
+func TestTracker_Disabled(t *testing.T) {
+	// Arrange
+	tracker := NewTracker(0)
+
+	// Act
+	tracker.Record(paulista.quote())
+
+	// Assert
+	assert.Empty(t, tracker.Top(5))
+}
+
+func TestTracker_Decay(t *testing.T) {
+	// Arrange
+	tracker := NewTracker(1)
+	tracker.limit = 2
+	belo := Route{Origin: "01310100", Destination: "30130000"}.quote()
+	for range 4 {
+		tracker.Record(paulista.quote())
+	}
+	tracker.Record(rio.quote())
+
+	// Act
+	tracker.Record(belo)
+
+	// Assert
+	key, _ := paulista.quote().key()
+	assert.Len(t, tracker.counts, 1)
+	assert.Equal(t, 2, tracker.counts[key].count)
+}
@@ -0,0 +1,286 @@
+// Package warmup prices the hot quotes in the background at startup and after pricing reloads, so
+// that the first quotes after a deploy or a reload are served from the quote cache instead of
+// paying for a cold cache and carrier connections. Hot quotes are the configured routes, priced
+// with a reference package, and the most quoted requests learned from the recent traffic, which
+// are kept in the quote store for the next instances to start with.
+package warmup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/rbonfanti/shipping-calculator/internal/zipcode"
+	"go.uber.org/zap"
+)
+
+const (
+	// quotesKey is the store key of the learned quotes
+	quotesKey = "warmup:quotes"
+	// quotesTTL is how long the learned quotes are kept without an instance saving them
+	quotesTTL = 7 * 24 * time.Hour
+	// saveInterval is how often the learned quotes are saved
+	saveInterval = 5 * time.Minute
+)
+
+// referencePackage is the package priced on every configured route
+var referencePackage = model.PackageDimensions{Length: 20, Width: 15, Height: 10}
+
+// Route is an origin and destination zipcode pair, normalized
+type Route struct {
+	Origin      string `json:"origin"`
+	Destination string `json:"destination"`
+}
+
+// quote returns the quote of the reference package on the route, for the default tenant
+func (r Route) quote() Quote {
+	return Quote{Tenant: tenant.Default, Request: model.CalculateShippingRequest{
+		OriginZipcode:      r.Origin,
+		DestinationZipcode: r.Destination,
+		Weight:             1,
+		Dimensions:         referencePackage,
+	}}
+}
+
+// Quote is a request warmed in the quote cache of its tenant
+type Quote struct {
+	Tenant  string                         `json:"tenant"`
+	Request model.CalculateShippingRequest `json:"request"`
+}
+
+// Config configures the warm-up
+type Config struct {
+	// Routes are always warmed, before the learned ones
+	Routes []Route
+	// TopRoutes is how many of the most quoted requests are learned and warmed; 0 disables
+	// learning
+	TopRoutes int
+	// Timeout bounds each warm-up
+	Timeout time.Duration
+}
+
+// Enabled reports whether there are quotes to warm
+func (c Config) Enabled() bool {
+	return len(c.Routes) > 0 || c.TopRoutes > 0
+}
+
+// ConfigFromEnv reads WARMUP_ROUTES (comma-separated origin:destination zipcodes, default none),
+// WARMUP_TOP_ROUTES (default 20) and WARMUP_TIMEOUT (default 30s)
+func ConfigFromEnv() (Config, error) {
+	topRoutes, err := config.Int("WARMUP_TOP_ROUTES", 20)
+	if err != nil {
+		return Config{}, err
+	}
+	if topRoutes < 0 {
+		return Config{}, fmt.Errorf("WARMUP_TOP_ROUTES must not be negative, got %d", topRoutes)
+	}
+	timeout, err := config.Duration("WARMUP_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if timeout <= 0 {
+		return Config{}, fmt.Errorf("WARMUP_TIMEOUT must be positive, got %s", timeout)
+	}
+	cfg := Config{TopRoutes: topRoutes, Timeout: timeout}
+	for _, value := range config.List("WARMUP_ROUTES", nil) {
+		route, err := parseRoute(value)
+		if err != nil {
+			return Config{}, fmt.Errorf("WARMUP_ROUTES: %w", err)
+		}
+		cfg.Routes = append(cfg.Routes, route)
+	}
+	return cfg, nil
+}
+
+// parseRoute parses an "origin:destination" route
+func parseRoute(value string) (Route, error) {
+	origin, destination, ok := strings.Cut(value, ":")
+	route := Route{Origin: zipcode.Normalize(origin), Destination: zipcode.Normalize(destination)}
+	if !ok || !validZipcode(route.Origin) || !validZipcode(route.Destination) {
+		return Route{}, fmt.Errorf("invalid route %q, expected origin:destination zipcodes", value)
+	}
+	return route, nil
+}
+
+func validZipcode(value string) bool {
+	return len(value) == zipcode.Length && zipcode.Numeric(value)
+}
+
+// Warmer warms the hot quotes and learns them from the quotes it tracks
+type Warmer struct {
+	cfg        Config
+	calculator service.ShippingServiceInterface
+	quotes     store.QuoteStore
+	tracker    *Tracker
+	logger     *zap.Logger
+	trigger    chan struct{}
+}
+
+// New creates a warmer pricing the hot quotes with calculator, which fills its quote cache, and
+// keeping the learned quotes in quotes
+func New(cfg Config, calculator service.ShippingServiceInterface, quotes store.QuoteStore, logger *zap.Logger) *Warmer {
+	return &Warmer{
+		cfg:        cfg,
+		calculator: calculator,
+		quotes:     quotes,
+		tracker:    NewTracker(cfg.TopRoutes),
+		logger:     logger,
+		trigger:    make(chan struct{}, 1),
+	}
+}
+
+// Track returns next recording the requests of its successful quotes, except dry runs and
+// degraded quotes
+func (w *Warmer) Track(next service.ShippingServiceInterface) service.ShippingServiceInterface {
+	return &trackedService{next: next, tracker: w.tracker}
+}
+
+// Trigger requests a warm-up, e.g. after the pricing configuration changed. It does not block;
+// requests made while a warm-up is pending are merged
+func (w *Warmer) Trigger() {
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run warms the quotes at once and then on every Trigger until ctx is cancelled, saving the
+// learned quotes periodically and on return
+func (w *Warmer) Run(ctx context.Context) {
+	ticker := time.NewTicker(saveInterval)
+	defer ticker.Stop()
+	defer func() {
+		saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+		defer cancel()
+		w.save(saveCtx)
+	}()
+
+	w.Warm(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.trigger:
+			w.Warm(ctx)
+		case <-ticker.C:
+			w.save(ctx)
+		}
+	}
+}
+
+// Warm prices the hot quotes, with their tenant, so that they are cached by the calculator,
+// returning how many quotes were warmed and how many failed
+func (w *Warmer) Warm(ctx context.Context) (warmed, failed int) {
+	ctx, cancel := context.WithTimeout(ctx, w.cfg.Timeout)
+	defer cancel()
+	start := time.Now()
+
+	for _, quote := range w.hotQuotes(ctx) {
+		if ctx.Err() != nil {
+			break
+		}
+		request := quote.Request
+		warmCtx := service.WithCacheWarmup(tenant.NewContext(ctx, quote.Tenant))
+		if _, err := w.calculator.CalculateShipping(warmCtx, &request); err != nil {
+			failed++
+			w.logger.Warn("Failed to warm quote",
+				zap.String("tenant", quote.Tenant),
+				zap.String("origin", quote.Request.OriginZipcode),
+				zap.String("destination", quote.Request.DestinationZipcode),
+				zap.Error(err),
+			)
+			continue
+		}
+		warmed++
+	}
+	w.logger.Info("Quotes warmed",
+		zap.Int("warmed", warmed),
+		zap.Int("failed", failed),
+		zap.Duration("duration", time.Since(start)),
+	)
+	return warmed, failed
+}
+
+// hotQuotes returns the quotes of the configured routes followed by the most quoted requests,
+// learned by this instance or, before it has learned any, saved by the previous ones
+func (w *Warmer) hotQuotes(ctx context.Context) []Quote {
+	learned := w.tracker.Top(w.cfg.TopRoutes)
+	if len(learned) == 0 && w.cfg.TopRoutes > 0 {
+		learned = w.load(ctx)
+	}
+	candidates := make([]Quote, 0, len(w.cfg.Routes)+len(learned))
+	for _, route := range w.cfg.Routes {
+		candidates = append(candidates, route.quote())
+	}
+	candidates = append(candidates, learned...)
+
+	seen := make(map[string]bool, len(candidates))
+	quotes := make([]Quote, 0, len(candidates))
+	for _, quote := range candidates {
+		key, err := quote.key()
+		if err != nil || seen[key] {
+			continue
+		}
+		seen[key] = true
+		quotes = append(quotes, quote)
+	}
+	return quotes
+}
+
+// load returns the learned quotes saved in the store, at most TopRoutes
+func (w *Warmer) load(ctx context.Context) []Quote {
+	data, err := w.quotes.Get(ctx, quotesKey)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	var quotes []Quote
+	if err == nil {
+		err = json.Unmarshal(data, &quotes)
+	}
+	if err != nil {
+		w.logger.Warn("Failed to load learned quotes", zap.Error(err))
+		return nil
+	}
+	if len(quotes) > w.cfg.TopRoutes {
+		quotes = quotes[:w.cfg.TopRoutes]
+	}
+	return quotes
+}
+
+// save stores the learned quotes, if any, for the next instances
+func (w *Warmer) save(ctx context.Context) {
+	quotes := w.tracker.Top(w.cfg.TopRoutes)
+	if len(quotes) == 0 {
+		return
+	}
+	data, err := json.Marshal(quotes)
+	if err == nil {
+		err = w.quotes.Put(ctx, quotesKey, data, quotesTTL)
+	}
+	if err != nil {
+		w.logger.Warn("Failed to save learned quotes", zap.Error(err))
+	}
+}
+
+// trackedService records the requests of the quotes of a calculator
+type trackedService struct {
+	next    service.ShippingServiceInterface
+	tracker *Tracker
+}
+
+// CalculateShipping implements service.ShippingServiceInterface
+func (s *trackedService) CalculateShipping(ctx context.Context, req *model.CalculateShippingRequest) (*model.CalculateShippingResponse, error) {
+	response, err := s.next.CalculateShipping(ctx, req)
+	if err == nil && !service.IsDryRun(ctx) && !service.IsDegraded(ctx) {
+		s.tracker.Record(Quote{Tenant: tenant.FromContext(ctx), Request: *req})
+	}
+	return response, err
+}
@@ -0,0 +1,188 @@
+package warmup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+var (
+	paulista = Route{Origin: "01310100", Destination: "04547130"}
+	rio      = Route{Origin: "01310100", Destination: "20040020"}
+)
+
+// recordingCalculator records the quotes it prices, failing the destinations in failing
+type recordingCalculator struct {
+	mu      sync.Mutex
+	quotes  []Quote
+	dryRuns []bool
+	failing map[string]bool
+}
+
+func (c *recordingCalculator) CalculateShipping(ctx context.Context, req *model.CalculateShippingRequest) (*model.CalculateShippingResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.quotes = append(c.quotes, Quote{Tenant: tenant.FromContext(ctx), Request: *req})
+	c.dryRuns = append(c.dryRuns, service.IsDryRun(ctx))
+	if c.failing[req.DestinationZipcode] {
+		return nil, errors.New("carrier unavailable")
+	}
+	return &model.CalculateShippingResponse{}, nil
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{"defaults", map[string]string{}, Config{TopRoutes: 20, Timeout: 30 * time.Second}, ""},
+		{
+			"custom values",
+			map[string]string{"WARMUP_ROUTES": "01310-100:04547-130, 01310100:20040020", "WARMUP_TOP_ROUTES": "0", "WARMUP_TIMEOUT": "5s"},
+			Config{Routes: []Route{paulista, rio}, Timeout: 5 * time.Second},
+			"",
+		},
+		{"route without destination", map[string]string{"WARMUP_ROUTES": "01310100"}, Config{}, "invalid route"},
+		{"partial zipcode", map[string]string{"WARMUP_ROUTES": "0131:04547130"}, Config{}, "invalid route"},
+		{"negative top routes", map[string]string{"WARMUP_TOP_ROUTES": "-1"}, Config{}, "WARMUP_TOP_ROUTES"},
+		{"zero timeout", map[string]string{"WARMUP_TIMEOUT": "0s"}, Config{}, "WARMUP_TIMEOUT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for _, key := range []string{"WARMUP_ROUTES", "WARMUP_TOP_ROUTES", "WARMUP_TIMEOUT"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestWarm(t *testing.T) {
+	// Arrange
+	calculator := &recordingCalculator{}
+	cfg := Config{Routes: []Route{paulista}, TopRoutes: 5, Timeout: time.Second}
+	warmer := New(cfg, calculator, store.NewMemoryStore(), zaptest.NewLogger(t))
+	tracked := warmer.Track(calculator)
+	acme := tenant.NewContext(context.Background(), "acme")
+	parcel := model.CalculateShippingRequest{OriginZipcode: "01310-100", DestinationZipcode: "04547-130", Weight: 2.5, Dimensions: referencePackage}
+	failing := model.CalculateShippingRequest{OriginZipcode: rio.Origin, DestinationZipcode: rio.Destination, Weight: 1}
+	for _, req := range []model.CalculateShippingRequest{parcel, failing, parcel, paulista.quote().Request} {
+		_, _ = tracked.CalculateShipping(acme, &req)
+	}
+	dryRun, _ := service.WithDryRun(context.Background())
+	_, _ = tracked.CalculateShipping(dryRun, &model.CalculateShippingRequest{OriginZipcode: "01310100", DestinationZipcode: "69005040"})
+	_, _ = tracked.CalculateShipping(service.WithDegradedPricing(context.Background()), &model.CalculateShippingRequest{OriginZipcode: "01310100", DestinationZipcode: "69005040"})
+	calculator.quotes, calculator.dryRuns = nil, nil
+	calculator.failing = map[string]bool{rio.Destination: true}
+
+	// Act
+	warmed, failed := warmer.Warm(context.Background())
+
+	// Assert
+	assert.Equal(t, 3, warmed)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, []Quote{
+		paulista.quote(),
+		{Tenant: "acme", Request: parcel},
+		{Tenant: "acme", Request: paulista.quote().Request},
+		{Tenant: "acme", Request: failing},
+	}, calculator.quotes, "the learned requests are replayed as quoted, with their tenant")
+	assert.Equal(t, []bool{false, false, false, false}, calculator.dryRuns, "warm quotes fill the quote cache")
+}
+
+func TestWarm_QuoteCache(t *testing.T) {
+	// Arrange
+	cache := store.NewMemoryStore()
+	calculator := service.NewShippingService(service.WithCache(cache, time.Minute))
+	warmer := New(Config{Routes: []Route{paulista}, Timeout: time.Second}, calculator, store.NewMemoryStore(), zaptest.NewLogger(t))
+	ctx, timing := service.WithTiming(context.Background())
+
+	// Act
+	warmed, _ := warmer.Warm(context.Background())
+	request := paulista.quote().Request
+	_, err := calculator.CalculateShipping(ctx, &request)
+
+	// Assert
+	assert.Equal(t, 1, warmed)
+	assert.NoError(t, err)
+	steps := timing.Steps()
+	if assert.NotEmpty(t, steps) {
+		assert.Equal(t, service.StepCache, steps[0].Step, "the first quote of a warmed route is served from the cache")
+	}
+}
+
+func TestWarm_SavedQuotes(t *testing.T) {
+	// Arrange
+	quotes := store.NewMemoryStore()
+	cfg := Config{TopRoutes: 5, Timeout: time.Second}
+	previous := New(cfg, &recordingCalculator{}, quotes, zaptest.NewLogger(t))
+	_, _ = previous.Track(&recordingCalculator{}).CalculateShipping(context.Background(), &model.CalculateShippingRequest{OriginZipcode: rio.Origin, DestinationZipcode: rio.Destination})
+	stopped, stop := context.WithCancel(context.Background())
+	stop()
+	previous.Run(stopped)
+	calculator := &recordingCalculator{}
+
+	// Act
+	warmed, failed := New(cfg, calculator, quotes, zaptest.NewLogger(t)).Warm(context.Background())
+
+	// Assert
+	want := []Quote{{Tenant: tenant.Default, Request: model.CalculateShippingRequest{OriginZipcode: rio.Origin, DestinationZipcode: rio.Destination}}}
+	assert.Equal(t, 1, warmed)
+	assert.Zero(t, failed)
+	assert.Equal(t, want, calculator.quotes)
+	data, err := quotes.Get(context.Background(), quotesKey)
+	assert.NoError(t, err)
+	var saved []Quote
+	assert.NoError(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, want, saved)
+}
+
+func TestRun_Trigger(t *testing.T) {
+	// Arrange
+	calculator := &recordingCalculator{}
+	warmer := New(Config{Routes: []Route{paulista}, Timeout: time.Second}, calculator, store.NewMemoryStore(), zaptest.NewLogger(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		warmer.Run(ctx)
+		close(done)
+	}()
+	warmed := func() int {
+		calculator.mu.Lock()
+		defer calculator.mu.Unlock()
+		return len(calculator.quotes)
+	}
+	assert.Eventually(t, func() bool { return warmed() == 1 }, time.Second, time.Millisecond)
+
+	// Act
+	warmer.Trigger()
+
+	// Assert
+	assert.Eventually(t, func() bool { return warmed() == 2 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+}
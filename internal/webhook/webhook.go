@@ -0,0 +1,484 @@
+// Package webhook notifies merchants of the events of their tenant with signed callbacks to the
+// URLs they subscribe. Failed deliveries are retried with exponential backoff, and the events that
+// cannot be delivered are kept in a dead-letter log.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/rbonfanti/shipping-calculator/internal/tracking"
+	"go.uber.org/zap"
+)
+
+// Callback headers, besides tracking.SignatureHeader and tracking.TimestampHeader, which are
+// signed as the carriers sign their webhooks: the hex-encoded HMAC-SHA256 of "{timestamp}.{body}"
+// with the subscription secret, prefixed with "sha256="
+const (
+	// EventHeader carries the event type
+	EventHeader = "X-Webhook-Event"
+	// IDHeader carries the event ID, the same on every attempt, for merchants to deduplicate
+	IDHeader = "X-Webhook-ID"
+)
+
+const (
+	// maxRetryBackoff caps the wait between attempts
+	maxRetryBackoff = time.Hour
+	// minSecretLength is the shortest secret accepted from merchants
+	minSecretLength = 16
+)
+
+// EventTypes are the events merchants can subscribe to
+var EventTypes = []string{events.QuoteCreated, events.ShipmentBooked, events.TrackingUpdated}
+
+var (
+	// ErrInvalidSubscription is returned when a subscription request is malformed
+	ErrInvalidSubscription = errors.New("invalid webhook subscription")
+	// ErrNotFound is returned for unknown or other tenants' subscriptions
+	ErrNotFound = errors.New("webhook subscription not found")
+	// ErrForbiddenAddress is returned when a callback URL resolves to a loopback, private,
+	// link-local or otherwise non-public address
+	ErrForbiddenAddress = errors.New("webhook address is not public")
+)
+
+// cgnat is the shared address space of carrier-grade NAT, not routable on the internet
+var cgnat = netip.MustParsePrefix("100.64.0.0/10")
+
+// publicAddress reports whether callbacks may be sent to an address; a variable so tests can
+// deliver to their loopback servers
+var publicAddress = func(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !cgnat.Contains(addr)
+}
+
+// newHTTPClient creates the client of the callbacks. The address is checked after DNS resolution,
+// on every connection, so a hostname cannot point the callbacks to the internal network, and
+// redirects are not followed: a 3xx is a rejection by the subscriber
+func newHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !publicAddress(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Config configures the delivery of the callbacks
+type Config struct {
+	// Timeout bounds each delivery attempt
+	Timeout time.Duration
+	// MaxAttempts is how many times an event is sent before it is dead-lettered
+	MaxAttempts int
+	// RetryBackoff is the wait before the second attempt; it doubles on every attempt up to 1 hour
+	RetryBackoff time.Duration
+	// Workers is how many callbacks are sent at once
+	Workers int
+	// QueueSize is how many deliveries may wait for a worker; events published with the queue full
+	// are dropped
+	QueueSize int
+}
+
+// ConfigFromEnv reads WEBHOOK_TIMEOUT (default 5s), WEBHOOK_MAX_ATTEMPTS (default 5),
+// WEBHOOK_RETRY_BACKOFF (default 1s), WEBHOOK_WORKERS (default 4) and WEBHOOK_QUEUE_SIZE (default 1000)
+func ConfigFromEnv() (Config, error) {
+	timeout, err := config.Duration("WEBHOOK_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if timeout <= 0 {
+		return Config{}, fmt.Errorf("WEBHOOK_TIMEOUT must be positive, got %s", timeout)
+	}
+	attempts, err := config.Int("WEBHOOK_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return Config{}, err
+	}
+	if attempts <= 0 {
+		return Config{}, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be positive, got %d", attempts)
+	}
+	backoff, err := config.Duration("WEBHOOK_RETRY_BACKOFF", time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if backoff <= 0 {
+		return Config{}, fmt.Errorf("WEBHOOK_RETRY_BACKOFF must be positive, got %s", backoff)
+	}
+	workers, err := config.Int("WEBHOOK_WORKERS", 4)
+	if err != nil {
+		return Config{}, err
+	}
+	if workers <= 0 {
+		return Config{}, fmt.Errorf("WEBHOOK_WORKERS must be positive, got %d", workers)
+	}
+	queueSize, err := config.Int("WEBHOOK_QUEUE_SIZE", 1000)
+	if err != nil {
+		return Config{}, err
+	}
+	if queueSize <= 0 {
+		return Config{}, fmt.Errorf("WEBHOOK_QUEUE_SIZE must be positive, got %d", queueSize)
+	}
+	return Config{Timeout: timeout, MaxAttempts: attempts, RetryBackoff: backoff, Workers: workers, QueueSize: queueSize}, nil
+}
+
+// delivery is an event to send to the subscriptions of its tenant or, once resolved, to one of them
+type delivery struct {
+	event events.Event
+	// body is the JSON-encoded event
+	body []byte
+	// subscription is nil until the subscriptions of the tenant are resolved by a worker
+	subscription *model.WebhookSubscription
+	attempts     int
+}
+
+// Dispatcher manages the webhook subscriptions of the tenants and delivers the events to them. It
+// is safe for concurrent use
+type Dispatcher struct {
+	cfg        Config
+	repo       repository.WebhookRepository
+	httpClient *http.Client
+	queue      chan *delivery
+	logger     *zap.Logger
+	now        func() time.Time
+	// retries tracks the deliveries waiting for their next attempt
+	retries sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher storing the subscriptions and dead letters in repo. Run
+// delivers the dispatched events
+func NewDispatcher(cfg Config, repo repository.WebhookRepository, logger *zap.Logger) *Dispatcher {
+	return &Dispatcher{
+		cfg:        cfg,
+		repo:       repo,
+		httpClient: newHTTPClient(cfg.Timeout),
+		queue:      make(chan *delivery, cfg.QueueSize),
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Subscribe registers an https URL for events of the tenant of ctx. URLs of a non-public IP address
+// are rejected here; hostnames are checked when each callback connects. The returned subscription
+// carries its secret, which is not returned again
+func (d *Dispatcher) Subscribe(ctx context.Context, req *model.CreateWebhookSubscriptionRequest) (*model.WebhookSubscription, error) {
+	target, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || target.Scheme != "https" || target.Hostname() == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute https URL", ErrInvalidSubscription)
+	}
+	if addr, err := netip.ParseAddr(target.Hostname()); err == nil && !publicAddress(addr) {
+		return nil, fmt.Errorf("%w: url must not point to a loopback, private or link-local address", ErrInvalidSubscription)
+	}
+	if strings.EqualFold(target.Hostname(), "localhost") {
+		return nil, fmt.Errorf("%w: url must not point to a loopback, private or link-local address", ErrInvalidSubscription)
+	}
+
+	eventTypes := make([]string, 0, len(req.Events))
+	for _, eventType := range req.Events {
+		eventType = strings.ToLower(strings.TrimSpace(eventType))
+		if !slices.Contains(EventTypes, eventType) {
+			return nil, fmt.Errorf("%w: unsupported event %q, must be one of %s", ErrInvalidSubscription, eventType, strings.Join(EventTypes, ", "))
+		}
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	if len(eventTypes) == 0 {
+		eventTypes = append(eventTypes, EventTypes...)
+	}
+
+	secret := strings.TrimSpace(req.Secret)
+	if secret == "" {
+		secret, err = newSecret()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(secret) < minSecretLength {
+		return nil, fmt.Errorf("%w: secret must have at least %d characters", ErrInvalidSubscription, minSecretLength)
+	}
+
+	subscription := &model.WebhookSubscription{
+		ID:        uuid.NewString(),
+		Tenant:    tenant.FromContext(ctx),
+		URL:       target.String(),
+		Events:    eventTypes,
+		Secret:    secret,
+		CreatedAt: d.now().UTC(),
+	}
+	if err := d.repo.Save(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+// Subscriptions returns the subscriptions of the tenant of ctx, without their secrets
+func (d *Dispatcher) Subscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	subscriptions, err := d.repo.ListByTenant(ctx, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	return subscriptions, nil
+}
+
+// Unsubscribe removes a subscription of the tenant of ctx
+func (d *Dispatcher) Unsubscribe(ctx context.Context, id string) error {
+	err := d.repo.Delete(ctx, tenant.FromContext(ctx), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// DeadLetters returns up to limit events that could not be delivered to the subscriptions of a
+// tenant, or of every tenant when tenantID is empty, the most recent first
+func (d *Dispatcher) DeadLetters(ctx context.Context, tenantID string, limit int) ([]model.WebhookDeadLetter, error) {
+	letters, err := d.repo.ListDeadLetters(ctx, tenantID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook dead letters: %w", err)
+	}
+	return letters, nil
+}
+
+// Dispatch queues an event of one of the EventTypes for the subscriptions of its tenant; other
+// events and events of no tenant are ignored. It does not block: the subscriptions are resolved
+// and notified by the workers of Run, and events dispatched with the queue full are dropped
+func (d *Dispatcher) Dispatch(event events.Event) {
+	if event.Tenant == "" || !slices.Contains(EventTypes, event.Type) {
+		return
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		d.logger.Warn("Failed to encode webhook event", zap.String("event_type", event.Type), zap.Error(err))
+		return
+	}
+	if !d.enqueue(&delivery{event: event, body: body}) {
+		d.logger.Warn("Webhook queue full, event dropped",
+			zap.String("event_id", event.ID),
+			zap.String("event_type", event.Type),
+			zap.String("tenant", event.Tenant),
+		)
+	}
+}
+
+// Run delivers the dispatched events with cfg.Workers workers until ctx is done. Deliveries
+// waiting for a retry when ctx is done are dead-lettered
+func (d *Dispatcher) Run(ctx context.Context) {
+	var workers sync.WaitGroup
+	for range d.cfg.Workers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case next := <-d.queue:
+					d.process(ctx, next)
+				}
+			}
+		}()
+	}
+	workers.Wait()
+	d.retries.Wait()
+}
+
+// enqueue queues a delivery unless the queue is full
+func (d *Dispatcher) enqueue(next *delivery) bool {
+	select {
+	case d.queue <- next:
+		return true
+	default:
+		return false
+	}
+}
+
+// process sends a delivery, first resolving the subscriptions of the event tenant
+func (d *Dispatcher) process(ctx context.Context, next *delivery) {
+	if next.subscription != nil {
+		d.attempt(ctx, next)
+		return
+	}
+
+	subscriptions, err := d.repo.ListByTenant(ctx, next.event.Tenant)
+	if err != nil {
+		d.logger.Warn("Failed to list webhook subscriptions",
+			zap.String("event_id", next.event.ID),
+			zap.String("tenant", next.event.Tenant),
+			zap.Error(err),
+		)
+		return
+	}
+	for i := range subscriptions {
+		if slices.Contains(subscriptions[i].Events, next.event.Type) {
+			d.attempt(ctx, &delivery{event: next.event, body: next.body, subscription: &subscriptions[i]})
+		}
+	}
+}
+
+// attempt sends a delivery to its subscription, scheduling a retry after a network error, 429 or
+// 5xx, and dead-lettering it once the attempts are exhausted or the subscriber rejects it
+func (d *Dispatcher) attempt(ctx context.Context, next *delivery) {
+	next.attempts++
+	err := d.send(ctx, next)
+	if err == nil {
+		return
+	}
+	var retryable *retryableError
+	if !errors.As(err, &retryable) || next.attempts >= d.cfg.MaxAttempts || ctx.Err() != nil {
+		d.deadLetter(ctx, next, err)
+		return
+	}
+
+	backoff := min(d.cfg.RetryBackoff<<(next.attempts-1), maxRetryBackoff)
+	d.retries.Add(1)
+	go func() {
+		defer d.retries.Done()
+		timer := time.NewTimer(backoff)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			d.deadLetter(ctx, next, fmt.Errorf("webhook: retry abandoned: %w", ctx.Err()))
+		case <-timer.C:
+			if !d.enqueue(next) {
+				d.deadLetter(ctx, next, errors.New("webhook queue full"))
+			}
+		}
+	}()
+}
+
+// retryableError is a failure worth retrying: a network error, 429 or 5xx
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+
+func (e *retryableError) Unwrap() error { return e.err }
+
+// send makes a single signed attempt
+func (d *Dispatcher) send(ctx context.Context, next *delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, next.subscription.URL, bytes.NewReader(next.body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	timestamp := strconv.FormatInt(d.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, next.event.Type)
+	req.Header.Set(IDHeader, next.event.ID)
+	req.Header.Set(tracking.TimestampHeader, timestamp)
+	req.Header.Set(tracking.SignatureHeader, "sha256="+hex.EncodeToString(tracking.Sign(next.subscription.Secret, timestamp, next.body)))
+
+	resp, err := d.httpClient.Do(req)
+	if errors.Is(err, ErrForbiddenAddress) {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	if err != nil {
+		return &retryableError{fmt.Errorf("webhook: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return &retryableError{fmt.Errorf("webhook: unexpected status %d", resp.StatusCode)}
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("webhook: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// deadLetter records a delivery that will not be attempted again
+func (d *Dispatcher) deadLetter(ctx context.Context, next *delivery, cause error) {
+	letter := &model.WebhookDeadLetter{
+		ID:             uuid.NewString(),
+		SubscriptionID: next.subscription.ID,
+		Tenant:         next.subscription.Tenant,
+		URL:            next.subscription.URL,
+		EventID:        next.event.ID,
+		EventType:      next.event.Type,
+		Event:          next.body,
+		Attempts:       next.attempts,
+		Error:          cause.Error(),
+		FailedAt:       d.now().UTC(),
+	}
+	d.logger.Warn("Webhook delivery failed, event dead-lettered",
+		zap.String("subscription_id", letter.SubscriptionID),
+		zap.String("event_id", letter.EventID),
+		zap.String("event_type", letter.EventType),
+		zap.Int("attempts", letter.Attempts),
+		zap.Error(cause),
+	)
+	// The dead letter is kept even when the delivery was abandoned on shutdown
+	if err := d.repo.AddDeadLetter(context.WithoutCancel(ctx), letter); err != nil {
+		d.logger.Error("Failed to save webhook dead letter", zap.String("event_id", letter.EventID), zap.Error(err))
+	}
+}
+
+// newSecret generates a random secret for a subscription
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// Publisher publishes events to another publisher and dispatches them to the webhook subscriptions
+type Publisher struct {
+	next       events.Publisher
+	dispatcher *Dispatcher
+}
+
+// NewPublisher creates a publisher wrapping next
+func NewPublisher(next events.Publisher, dispatcher *Dispatcher) *Publisher {
+	return &Publisher{next: next, dispatcher: dispatcher}
+}
+
+// Publish implements events.Publisher. The event ID is generated when empty, so that the broker
+// and the webhooks carry the same ID
+func (p *Publisher) Publish(ctx context.Context, event events.Event) error {
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	err := p.next.Publish(ctx, event)
+	p.dispatcher.Dispatch(event)
+	return err
+}
@@ -0,0 +1,392 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/repository"
+	"github.com/rbonfanti/shipping-calculator/internal/store"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	"github.com/rbonfanti/shipping-calculator/internal/tracking"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+const testSecret = "0123456789abcdef"
+
+var trackingEvent = events.Event{
+	ID:         "e1",
+	Type:       events.TrackingUpdated,
+	Subject:    "s1",
+	Tenant:     "acme",
+	OccurredAt: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC),
+	Data:       map[string]string{"status": "in_transit"},
+}
+
+func newTestDispatcher(t *testing.T, maxAttempts int) (*Dispatcher, *repository.MemoryWebhookRepository) {
+	t.Helper()
+	repo := repository.NewMemoryWebhookRepository()
+	cfg := Config{Timeout: time.Second, MaxAttempts: maxAttempts, RetryBackoff: time.Millisecond, Workers: 2, QueueSize: 10}
+	return NewDispatcher(cfg, repo, zaptest.NewLogger(t)), repo
+}
+
+// runDispatcher runs the dispatcher until the test ends
+func runDispatcher(t *testing.T, dispatcher *Dispatcher) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// newTestServer starts a TLS server the dispatcher trusts and may deliver to, although it listens
+// on a loopback address
+func newTestServer(t *testing.T, dispatcher *Dispatcher, handler http.Handler) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+	transport := dispatcher.httpClient.Transport.(*http.Transport)
+	transport.TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig
+	allowed := publicAddress
+	publicAddress = func(addr netip.Addr) bool { return addr.Unmap().IsLoopback() || allowed(addr) }
+	t.Cleanup(func() { publicAddress = allowed })
+	return server
+}
+
+func subscribe(t *testing.T, dispatcher *Dispatcher, url string, eventTypes ...string) *model.WebhookSubscription {
+	t.Helper()
+	subscription, err := dispatcher.Subscribe(tenant.NewContext(context.Background(), "acme"), &model.CreateWebhookSubscriptionRequest{URL: url, Events: eventTypes, Secret: testSecret})
+	assert.NoError(t, err)
+	return subscription
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	keys := []string{"WEBHOOK_TIMEOUT", "WEBHOOK_MAX_ATTEMPTS", "WEBHOOK_RETRY_BACKOFF", "WEBHOOK_WORKERS", "WEBHOOK_QUEUE_SIZE"}
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{"defaults", map[string]string{}, Config{Timeout: 5 * time.Second, MaxAttempts: 5, RetryBackoff: time.Second, Workers: 4, QueueSize: 1000}, ""},
+		{
+			"custom values",
+			map[string]string{"WEBHOOK_TIMEOUT": "2s", "WEBHOOK_MAX_ATTEMPTS": "3", "WEBHOOK_RETRY_BACKOFF": "10s", "WEBHOOK_WORKERS": "8", "WEBHOOK_QUEUE_SIZE": "50"},
+			Config{Timeout: 2 * time.Second, MaxAttempts: 3, RetryBackoff: 10 * time.Second, Workers: 8, QueueSize: 50},
+			"",
+		},
+		{"zero timeout", map[string]string{"WEBHOOK_TIMEOUT": "0s"}, Config{}, "WEBHOOK_TIMEOUT"},
+		{"zero attempts", map[string]string{"WEBHOOK_MAX_ATTEMPTS": "0"}, Config{}, "WEBHOOK_MAX_ATTEMPTS"},
+		{"zero backoff", map[string]string{"WEBHOOK_RETRY_BACKOFF": "0s"}, Config{}, "WEBHOOK_RETRY_BACKOFF"},
+		{"zero workers", map[string]string{"WEBHOOK_WORKERS": "0"}, Config{}, "WEBHOOK_WORKERS"},
+		{"zero queue", map[string]string{"WEBHOOK_QUEUE_SIZE": "0"}, Config{}, "WEBHOOK_QUEUE_SIZE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for _, key := range keys {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// Act
+			cfg, err := ConfigFromEnv()
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	// Arrange
+	dispatcher, _ := newTestDispatcher(t, 1)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	// Act
+	chosen, chosenErr := dispatcher.Subscribe(ctx, &model.CreateWebhookSubscriptionRequest{URL: "https://acme.example.com/hooks", Events: []string{" Tracking.Updated", "tracking.updated"}, Secret: testSecret})
+	all, allErr := dispatcher.Subscribe(ctx, &model.CreateWebhookSubscriptionRequest{URL: "https://acme.example.com/all"})
+	listed, listErr := dispatcher.Subscriptions(ctx)
+	otherTenant, _ := dispatcher.Subscriptions(context.Background())
+
+	// Assert
+	assert.NoError(t, chosenErr)
+	assert.Equal(t, "acme", chosen.Tenant)
+	assert.Equal(t, []string{events.TrackingUpdated}, chosen.Events)
+	assert.Equal(t, testSecret, chosen.Secret)
+	assert.NoError(t, allErr)
+	assert.Equal(t, EventTypes, all.Events, "every event type is notified by default")
+	assert.Len(t, all.Secret, 64, "a secret is generated when none is given")
+	assert.NoError(t, listErr)
+	assert.Len(t, listed, 2)
+	for _, subscription := range listed {
+		assert.Empty(t, subscription.Secret, "secrets are only returned on creation")
+	}
+	assert.Empty(t, otherTenant)
+}
+
+func TestSubscribe_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		req  model.CreateWebhookSubscriptionRequest
+	}{
+		{"missing url", model.CreateWebhookSubscriptionRequest{}},
+		{"relative url", model.CreateWebhookSubscriptionRequest{URL: "/hooks"}},
+		{"unsupported scheme", model.CreateWebhookSubscriptionRequest{URL: "ftp://acme.example.com/hooks"}},
+		{"plain http", model.CreateWebhookSubscriptionRequest{URL: "http://acme.example.com/hooks"}},
+		{"loopback address", model.CreateWebhookSubscriptionRequest{URL: "https://127.0.0.1:8080/hooks"}},
+		{"localhost", model.CreateWebhookSubscriptionRequest{URL: "https://localhost/hooks"}},
+		{"private address", model.CreateWebhookSubscriptionRequest{URL: "https://10.0.0.5/hooks"}},
+		{"link-local address", model.CreateWebhookSubscriptionRequest{URL: "https://169.254.169.254/latest/meta-data"}},
+		{"ipv6 loopback", model.CreateWebhookSubscriptionRequest{URL: "https://[::1]/hooks"}},
+		{"unsupported event", model.CreateWebhookSubscriptionRequest{URL: "https://acme.example.com/hooks", Events: []string{"pricing.config_changed"}}},
+		{"short secret", model.CreateWebhookSubscriptionRequest{URL: "https://acme.example.com/hooks", Secret: "short"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			dispatcher, _ := newTestDispatcher(t, 1)
+
+			// Act
+			_, err := dispatcher.Subscribe(context.Background(), &tt.req)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidSubscription)
+		})
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	// Arrange
+	dispatcher, _ := newTestDispatcher(t, 1)
+	subscription := subscribe(t, dispatcher, "https://acme.example.com/hooks")
+
+	// Act
+	otherTenantErr := dispatcher.Unsubscribe(context.Background(), subscription.ID)
+	err := dispatcher.Unsubscribe(tenant.NewContext(context.Background(), "acme"), subscription.ID)
+
+	// Assert
+	assert.ErrorIs(t, otherTenantErr, ErrNotFound)
+	assert.NoError(t, err)
+}
+
+func TestDispatch_SignedDelivery(t *testing.T) {
+	// Arrange
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	dispatcher, _ := newTestDispatcher(t, 1)
+	server := newTestServer(t, dispatcher, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	subscribe(t, dispatcher, server.URL, events.TrackingUpdated)
+	runDispatcher(t, dispatcher)
+
+	// Act
+	dispatcher.Dispatch(events.Event{ID: "q1", Type: events.QuoteCreated, Tenant: "acme"})
+	dispatcher.Dispatch(trackingEvent)
+
+	// Assert
+	req := <-received
+	body := <-bodies
+	assert.Equal(t, events.TrackingUpdated, req.Header.Get(EventHeader), "only the subscribed events are delivered")
+	assert.Equal(t, "e1", req.Header.Get(IDHeader))
+	verifier := tracking.NewWebhookVerifier(tracking.Config{CarrierSecrets: map[string]string{"acme": testSecret}, WebhookTolerance: time.Minute}, store.NewMemoryStore())
+	assert.NoError(t, verifier.Verify(context.Background(), "acme", req.Header.Get(tracking.TimestampHeader), req.Header.Get(tracking.SignatureHeader), body))
+	var delivered events.Event
+	assert.NoError(t, json.Unmarshal(body, &delivered))
+	assert.Equal(t, "s1", delivered.Subject)
+	assert.Equal(t, "acme", delivered.Tenant)
+}
+
+func TestDispatch_Retries(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []int
+		maxAttempts   int
+		wantAttempts  int32
+		wantDeadError string
+	}{
+		{"delivered after retries", []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}, 5, 3, ""},
+		{"attempts exhausted", []int{http.StatusInternalServerError}, 3, 3, "unexpected status 500"},
+		{"rejected by the subscriber", []int{http.StatusGone}, 5, 1, "unexpected status 410"},
+		{"redirects are not followed", []int{http.StatusFound}, 5, 1, "unexpected status 302"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var attempts atomic.Int32
+			done := make(chan struct{}, 10)
+			dispatcher, repo := newTestDispatcher(t, tt.maxAttempts)
+			server := newTestServer(t, dispatcher, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1))
+				w.WriteHeader(tt.statuses[min(n, len(tt.statuses))-1])
+				done <- struct{}{}
+			}))
+			subscription := subscribe(t, dispatcher, server.URL)
+			runDispatcher(t, dispatcher)
+
+			// Act
+			dispatcher.Dispatch(trackingEvent)
+
+			// Assert
+			for range tt.wantAttempts {
+				<-done
+			}
+			var letters []model.WebhookDeadLetter
+			assert.Eventually(t, func() bool {
+				letters, _ = repo.ListDeadLetters(context.Background(), "", 10)
+				return tt.wantDeadError == "" || len(letters) == 1
+			}, time.Second, time.Millisecond)
+			assert.Equal(t, tt.wantAttempts, attempts.Load())
+			if tt.wantDeadError == "" {
+				assert.Empty(t, letters)
+				return
+			}
+			assert.Equal(t, subscription.ID, letters[0].SubscriptionID)
+			assert.Equal(t, "acme", letters[0].Tenant)
+			assert.Equal(t, "e1", letters[0].EventID)
+			assert.Equal(t, int(tt.wantAttempts), letters[0].Attempts)
+			assert.Contains(t, letters[0].Error, tt.wantDeadError)
+			assert.Contains(t, string(letters[0].Event), `"subject":"s1"`)
+		})
+	}
+}
+
+func TestDispatch_ForbiddenAddress(t *testing.T) {
+	// Arrange
+	var attempts atomic.Int32
+	dispatcher, repo := newTestDispatcher(t, 5)
+	server := newTestServer(t, dispatcher, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+	}))
+	subscription := subscribe(t, dispatcher, server.URL)
+	publicAddress = func(netip.Addr) bool { return false }
+	runDispatcher(t, dispatcher)
+
+	// Act
+	dispatcher.Dispatch(trackingEvent)
+
+	// Assert
+	var letters []model.WebhookDeadLetter
+	assert.Eventually(t, func() bool {
+		letters, _ = repo.ListDeadLetters(context.Background(), "", 10)
+		return len(letters) == 1
+	}, time.Second, time.Millisecond)
+	assert.Zero(t, attempts.Load(), "the address is checked before connecting")
+	assert.Equal(t, subscription.ID, letters[0].SubscriptionID)
+	assert.Equal(t, 1, letters[0].Attempts, "forbidden addresses are not retried")
+	assert.Contains(t, letters[0].Error, ErrForbiddenAddress.Error())
+}
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"203.0.113.10", true},
+		{"2001:db8::1", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.want, publicAddress(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestDispatch_IgnoredEvents(t *testing.T) {
+	// Arrange
+	dispatcher, _ := newTestDispatcher(t, 1)
+
+	// Act
+	dispatcher.Dispatch(events.Event{Type: events.TrackingUpdated, Subject: "s1"})
+	dispatcher.Dispatch(events.Event{Type: events.PricingConfigChanged, Tenant: "acme"})
+
+	// Assert
+	assert.Empty(t, dispatcher.queue, "events of no tenant and unsupported types are not queued")
+}
+
+// failingPublisher fails every publish
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(ctx context.Context, event events.Event) error {
+	return errors.New("broker unavailable")
+}
+
+func TestPublisher(t *testing.T) {
+	// Arrange
+	next := events.NewMemoryPublisher()
+	dispatcher, _ := newTestDispatcher(t, 1)
+	publisher := NewPublisher(next, dispatcher)
+	event := trackingEvent
+	event.ID = ""
+
+	// Act
+	err := publisher.Publish(context.Background(), event)
+	failedErr := NewPublisher(failingPublisher{}, dispatcher).Publish(context.Background(), trackingEvent)
+
+	// Assert
+	assert.NoError(t, err)
+	published := next.Events()
+	assert.Len(t, published, 1)
+	assert.NotEmpty(t, published[0].ID)
+	queued := <-dispatcher.queue
+	assert.Equal(t, published[0].ID, queued.event.ID, "the broker and the webhooks carry the same event ID")
+	assert.EqualError(t, failedErr, "broker unavailable")
+	assert.Len(t, dispatcher.queue, 1, "events are dispatched even when the broker fails")
+}
+
+func TestDeadLetters(t *testing.T) {
+	// Arrange
+	dispatcher, repo := newTestDispatcher(t, 1)
+	for i := range 3 {
+		_ = repo.AddDeadLetter(context.Background(), &model.WebhookDeadLetter{ID: strconv.Itoa(i), Tenant: "acme"})
+	}
+
+	// Act
+	letters, err := dispatcher.DeadLetters(context.Background(), "acme", 2)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, letters, 2)
+	assert.Equal(t, "2", letters[0].ID)
+}
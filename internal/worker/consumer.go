@@ -0,0 +1,211 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rbonfanti/shipping-calculator/internal/config"
+	"github.com/segmentio/kafka-go"
+)
+
+// Supported queue brokers
+const (
+	BrokerKafka    = "kafka"
+	BrokerRabbitMQ = "rabbitmq"
+)
+
+// Config configures the queue quote jobs are consumed from
+type Config struct {
+	// Broker is kafka or rabbitmq
+	Broker       string
+	KafkaBrokers []string
+	KafkaTopic   string
+	// KafkaGroup is the consumer group shared by the workers, which split the partitions of the topic
+	KafkaGroup    string
+	RabbitMQURL   string
+	RabbitMQQueue string
+	// Concurrency is the number of jobs processed in parallel, each by its own consumer
+	Concurrency int
+	// RetryBackoff is the initial wait before retrying a job whose result could not be published;
+	// it doubles on every attempt up to one minute
+	RetryBackoff time.Duration
+}
+
+// ConfigFromEnv reads WORKER_BROKER (kafka or rabbitmq, required), WORKER_KAFKA_BROKERS (comma-separated
+// host:port, required for kafka), WORKER_KAFKA_TOPIC (default quote-jobs), WORKER_KAFKA_GROUP
+// (default shipping-calculator-worker), WORKER_RABBITMQ_URL (required for rabbitmq),
+// WORKER_RABBITMQ_QUEUE (default quote-jobs), WORKER_CONCURRENCY (default 4) and
+// WORKER_RETRY_BACKOFF (default 1s)
+func ConfigFromEnv() (Config, error) {
+	concurrency, err := config.Int("WORKER_CONCURRENCY", 4)
+	if err != nil {
+		return Config{}, err
+	}
+	if concurrency < 1 {
+		return Config{}, fmt.Errorf("WORKER_CONCURRENCY must be positive")
+	}
+	backoff, err := config.Duration("WORKER_RETRY_BACKOFF", time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if backoff <= 0 {
+		return Config{}, fmt.Errorf("WORKER_RETRY_BACKOFF must be positive")
+	}
+
+	cfg := Config{
+		Broker:        strings.ToLower(config.String("WORKER_BROKER", "")),
+		KafkaBrokers:  config.List("WORKER_KAFKA_BROKERS", nil),
+		KafkaTopic:    config.String("WORKER_KAFKA_TOPIC", "quote-jobs"),
+		KafkaGroup:    config.String("WORKER_KAFKA_GROUP", "shipping-calculator-worker"),
+		RabbitMQURL:   config.String("WORKER_RABBITMQ_URL", ""),
+		RabbitMQQueue: config.String("WORKER_RABBITMQ_QUEUE", "quote-jobs"),
+		Concurrency:   concurrency,
+		RetryBackoff:  backoff,
+	}
+	switch cfg.Broker {
+	case BrokerKafka:
+		if len(cfg.KafkaBrokers) == 0 {
+			return Config{}, fmt.Errorf("WORKER_KAFKA_BROKERS is required for the kafka broker")
+		}
+	case BrokerRabbitMQ:
+		if cfg.RabbitMQURL == "" {
+			return Config{}, fmt.Errorf("WORKER_RABBITMQ_URL is required for the rabbitmq broker")
+		}
+	default:
+		return Config{}, fmt.Errorf("WORKER_BROKER must be %s or %s, got %q", BrokerKafka, BrokerRabbitMQ, cfg.Broker)
+	}
+	return cfg, nil
+}
+
+// Delivery is a message received from the queue
+type Delivery struct {
+	Body []byte
+	// Headers carry the trace context of the producer
+	Headers map[string]string
+	// Ack removes the message from the queue; unacknowledged messages are redelivered when the
+	// consumer is closed
+	Ack func(ctx context.Context) error
+}
+
+// Consumer receives messages from a queue, one at a time
+type Consumer interface {
+	// Receive blocks until a message is available or ctx is done
+	Receive(ctx context.Context) (*Delivery, error)
+	Close() error
+}
+
+// NewConsumer creates a consumer of the configured broker
+func NewConsumer(cfg Config) (Consumer, error) {
+	if cfg.Broker == BrokerRabbitMQ {
+		return NewRabbitMQConsumer(cfg)
+	}
+	return NewKafkaConsumer(cfg), nil
+}
+
+// KafkaConsumer consumes a topic as a member of a consumer group, committing the offset of each
+// acknowledged message
+type KafkaConsumer struct {
+	reader *kafka.Reader
+}
+
+// NewKafkaConsumer creates a consumer of cfg.KafkaTopic in cfg.KafkaGroup
+func NewKafkaConsumer(cfg Config) *KafkaConsumer {
+	return &KafkaConsumer{reader: kafka.NewReader(kafka.ReaderConfig{
+		Brokers: cfg.KafkaBrokers,
+		GroupID: cfg.KafkaGroup,
+		Topic:   cfg.KafkaTopic,
+	})}
+}
+
+// Receive implements Consumer
+func (c *KafkaConsumer) Receive(ctx context.Context) (*Delivery, error) {
+	message, err := c.reader.FetchMessage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch kafka message: %w", err)
+	}
+	headers := make(map[string]string, len(message.Headers))
+	for _, header := range message.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	return &Delivery{
+		Body:    message.Value,
+		Headers: headers,
+		Ack: func(ctx context.Context) error {
+			return c.reader.CommitMessages(ctx, message)
+		},
+	}, nil
+}
+
+// Close implements Consumer
+func (c *KafkaConsumer) Close() error {
+	return c.reader.Close()
+}
+
+// RabbitMQConsumer consumes a durable queue, one unacknowledged message at a time
+type RabbitMQConsumer struct {
+	conn       *amqp.Connection
+	deliveries <-chan amqp.Delivery
+}
+
+// NewRabbitMQConsumer connects to cfg.RabbitMQURL and consumes cfg.RabbitMQQueue, declaring it if needed
+func NewRabbitMQConsumer(cfg Config) (*RabbitMQConsumer, error) {
+	conn, err := amqp.Dial(cfg.RabbitMQURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to rabbitmq: %w", err)
+	}
+	channel, err := conn.Channel()
+	if err == nil {
+		err = channel.Qos(1, 0, false)
+	}
+	if err == nil {
+		_, err = channel.QueueDeclare(cfg.RabbitMQQueue, true, false, false, false, nil)
+	}
+	var deliveries <-chan amqp.Delivery
+	if err == nil {
+		deliveries, err = channel.Consume(cfg.RabbitMQQueue, "", false, false, false, false, nil)
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to consume rabbitmq queue %s: %w", cfg.RabbitMQQueue, err)
+	}
+	return &RabbitMQConsumer{conn: conn, deliveries: deliveries}, nil
+}
+
+// Receive implements Consumer
+func (c *RabbitMQConsumer) Receive(ctx context.Context) (*Delivery, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case message, ok := <-c.deliveries:
+		if !ok {
+			return nil, errors.New("rabbitmq channel closed")
+		}
+		return newRabbitMQDelivery(message), nil
+	}
+}
+
+// Close implements Consumer
+func (c *RabbitMQConsumer) Close() error {
+	return c.conn.Close()
+}
+
+// newRabbitMQDelivery converts a RabbitMQ message, keeping its string headers
+func newRabbitMQDelivery(message amqp.Delivery) *Delivery {
+	headers := make(map[string]string, len(message.Headers))
+	for key, value := range message.Headers {
+		if s, ok := value.(string); ok {
+			headers[key] = s
+		}
+	}
+	return &Delivery{
+		Body:    message.Body,
+		Headers: headers,
+		Ack: func(ctx context.Context) error {
+			return message.Ack(false)
+		},
+	}
+}
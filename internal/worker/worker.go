@@ -0,0 +1,213 @@
+// Package worker computes quote requests consumed from a message queue, publishing the results as
+// events, so that high-volume callers can quote asynchronously instead of through the HTTP API.
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/events"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/mapper"
+	"github.com/rbonfanti/shipping-calculator/internal/model"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/tenant"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"github.com/rbonfanti/shipping-calculator/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+)
+
+// maxRetryBackoff caps the wait between attempts to publish a result
+const maxRetryBackoff = time.Minute
+
+// Calculator calculates shipping quotes
+type Calculator interface {
+	CalculateShipping(ctx context.Context, req *model.CalculateShippingRequest) (*model.CalculateShippingResponse, error)
+}
+
+// Job is a quote request consumed from the queue
+type Job struct {
+	// ID is chosen by the producer to correlate the job with its result
+	ID      string                      `json:"id"`
+	Request v1.CalculateShippingRequest `json:"request"`
+	// Tenant is the tenant whose rates price the job; empty for the default tenant. The queue is
+	// trusted, so no API key is required
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// Result is the data of the quote.job_completed event: either the quote or the reason it could
+// not be calculated
+type Result struct {
+	JobID string                        `json:"job_id"`
+	Quote *v1.CalculateShippingResponse `json:"quote,omitempty"`
+	Error string                        `json:"error,omitempty"`
+}
+
+// Processor calculates the quote of a job and publishes its result
+type Processor struct {
+	calculator Calculator
+	events     events.Publisher
+	tracer     trace.Tracer
+	metrics    *telemetry.Metrics
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// NewProcessor creates a processor calculating quotes with calculator and publishing the results to
+// publisher, tracing the jobs with tracer and recording their metrics in metrics. A nil tracer or
+// metrics records nothing
+func NewProcessor(calculator Calculator, publisher events.Publisher, tracer trace.Tracer, metrics *telemetry.Metrics, logger *zap.Logger) *Processor {
+	if tracer == nil {
+		tracer = noop.NewTracerProvider().Tracer("")
+	}
+	return &Processor{
+		calculator: calculator,
+		events:     publisher,
+		tracer:     tracer,
+		metrics:    metrics,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Process handles a delivery within the trace of its producer. Malformed jobs are logged and
+// dropped, and quotes that cannot be calculated are published as error results; only a failure
+// to publish the result is returned, so that the job is retried
+func (p *Processor) Process(ctx context.Context, delivery *Delivery) error {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(delivery.Headers))
+	ctx, span := p.tracer.Start(ctx, "worker.process_quote_job", trace.WithSpanKind(trace.SpanKindConsumer))
+	defer span.End()
+	ctx = logger.NewContext(ctx, logger.WithTracingFields(p.logger, ctx))
+
+	var job Job
+	if err := json.Unmarshal(delivery.Body, &job); err != nil || job.ID == "" {
+		if err == nil {
+			err = errors.New("job id is required")
+		}
+		span.RecordError(err)
+		logger.LogError(p.logger, ctx, "Pedido de cotação malformado descartado", err)
+		return nil
+	}
+	span.SetAttributes(attribute.String("job.id", job.ID))
+	if job.Tenant != "" {
+		ctx = tenant.NewContext(ctx, job.Tenant)
+	}
+
+	result := p.calculate(ctx, &job)
+	event := events.Event{Type: events.QuoteJobCompleted, Subject: job.ID, OccurredAt: p.now().UTC(), Data: result}
+	if err := p.events.Publish(ctx, event); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// calculate quotes a job, recording the same metrics as the HTTP API
+func (p *Processor) calculate(ctx context.Context, job *Job) Result {
+	startTime := time.Now()
+	req := mapper.RequestFromV1(&job.Request)
+
+	response, err := p.calculator.CalculateShipping(ctx, req)
+	if err != nil {
+		service.RecordCalculation(ctx, p.metrics, req, nil, err, time.Since(startTime))
+		logger.LogError(p.logger, ctx, "Erro no cálculo do pedido de cotação", err, zap.String("job_id", job.ID))
+		return Result{JobID: job.ID, Error: err.Error()}
+	}
+
+	service.RecordCalculation(ctx, p.metrics, req, response, nil, time.Since(startTime))
+	if response.Experiment != nil {
+		p.metrics.RecordPricingExperimentQuote(ctx, response.Experiment.Name, response.Experiment.Arm, response.ShippingCost.Minor())
+	}
+	logger.LogRequest(p.logger, ctx, "Pedido de cotação concluído",
+		zap.String("job_id", job.ID),
+		zap.Float64("custo_envio", response.ShippingCost.Minor()),
+		zap.String("versão_tarifas", response.PricingVersion),
+	)
+	return Result{JobID: job.ID, Quote: mapper.ResponseToV1(response)}
+}
+
+// Worker processes the jobs of a queue with a number of consumers in parallel
+type Worker struct {
+	consumers []Consumer
+	processor *Processor
+	cfg       Config
+	logger    *zap.Logger
+}
+
+// NewWorker creates a worker running one processing loop per consumer
+func NewWorker(consumers []Consumer, processor *Processor, cfg Config, logger *zap.Logger) *Worker {
+	return &Worker{
+		consumers: consumers,
+		processor: processor,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// Run processes jobs until ctx is cancelled, then waits for the jobs in progress
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, consumer := range w.consumers {
+		wg.Add(1)
+		go func(consumer Consumer) {
+			defer wg.Done()
+			w.consume(ctx, consumer)
+		}(consumer)
+	}
+	wg.Wait()
+}
+
+// consume receives, processes and acknowledges jobs one at a time. A job whose result cannot be
+// published is retried with exponential backoff and left unacknowledged if ctx is cancelled, so
+// that it is redelivered
+func (w *Worker) consume(ctx context.Context, consumer Consumer) {
+	for {
+		delivery, err := consumer.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			w.logger.Error("Failed to receive quote job", zap.Error(err))
+			if !sleep(ctx, w.cfg.RetryBackoff) {
+				return
+			}
+			continue
+		}
+
+		backoff := w.cfg.RetryBackoff
+		for {
+			err := w.processor.Process(ctx, delivery)
+			if err == nil {
+				break
+			}
+			w.logger.Error("Failed to publish quote job result", zap.Error(err), zap.Duration("retry_in", backoff))
+			if !sleep(ctx, backoff) {
+				return
+			}
+			backoff = min(backoff*2, maxRetryBackoff)
+		}
+		if err := delivery.Ack(ctx); err != nil {
+			w.logger.Error("Failed to acknowledge quote job", zap.Error(err))
+		}
+	}
+}
+
+// sleep waits for d, returning false if ctx is cancelled first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,105 @@
+package zipcode
+
+import "strconv"
+
+// Brazilian macro-regions, as defined by IBGE
+const (
+	RegionNorth     = "north"
+	RegionNortheast = "northeast"
+	RegionMidwest   = "midwest"
+	RegionSoutheast = "southeast"
+	RegionSouth     = "south"
+)
+
+// stateRange maps a range of 5-digit CEP prefixes to a state
+type stateRange struct {
+	from, to int
+	state    string
+}
+
+// stateRanges are the CEP ranges assigned to each Brazilian state by Correios
+var stateRanges = []stateRange{
+	{1000, 19999, "SP"},
+	{20000, 28999, "RJ"},
+	{29000, 29999, "ES"},
+	{30000, 39999, "MG"},
+	{40000, 48999, "BA"},
+	{49000, 49999, "SE"},
+	{50000, 56999, "PE"},
+	{57000, 57999, "AL"},
+	{58000, 58999, "PB"},
+	{59000, 59999, "RN"},
+	{60000, 63999, "CE"},
+	{64000, 64999, "PI"},
+	{65000, 65999, "MA"},
+	{66000, 68899, "PA"},
+	{68900, 68999, "AP"},
+	{69000, 69299, "AM"},
+	{69300, 69399, "RR"},
+	{69400, 69899, "AM"},
+	{69900, 69999, "AC"},
+	{70000, 72799, "DF"},
+	{72800, 72999, "GO"},
+	{73000, 73699, "DF"},
+	{73700, 76799, "GO"},
+	{76800, 76999, "RO"},
+	{77000, 77999, "TO"},
+	{78000, 78899, "MT"},
+	{79000, 79999, "MS"},
+	{80000, 87999, "PR"},
+	{88000, 89999, "SC"},
+	{90000, 99999, "RS"},
+}
+
+// macroRegions maps each state to its macro-region
+var macroRegions = map[string]string{
+	"AC": RegionNorth, "AM": RegionNorth, "AP": RegionNorth, "PA": RegionNorth, "RO": RegionNorth, "RR": RegionNorth, "TO": RegionNorth,
+	"AL": RegionNortheast, "BA": RegionNortheast, "CE": RegionNortheast, "MA": RegionNortheast, "PB": RegionNortheast,
+	"PE": RegionNortheast, "PI": RegionNortheast, "RN": RegionNortheast, "SE": RegionNortheast,
+	"DF": RegionMidwest, "GO": RegionMidwest, "MS": RegionMidwest, "MT": RegionMidwest,
+	"ES": RegionSoutheast, "MG": RegionSoutheast, "RJ": RegionSoutheast, "SP": RegionSoutheast,
+	"PR": RegionSouth, "RS": RegionSouth, "SC": RegionSouth,
+}
+
+// State returns the state of a Brazilian zipcode, or "" when it cannot be determined
+func State(zipcode string) string {
+	normalized := Normalize(zipcode)
+	if len(normalized) < 5 {
+		return ""
+	}
+	prefix, err := strconv.Atoi(normalized[:5])
+	if err != nil {
+		return ""
+	}
+	for _, r := range stateRanges {
+		if prefix >= r.from && prefix <= r.to {
+			return r.state
+		}
+	}
+	return ""
+}
+
+// MacroRegion returns the macro-region of a Brazilian zipcode, or "" when it cannot be determined
+func MacroRegion(zipcode string) string {
+	return StateMacroRegion(State(zipcode))
+}
+
+// StateMacroRegion returns the macro-region of a state, or "" for an unknown state
+func StateMacroRegion(state string) string {
+	return macroRegions[state]
+}
+
+// IsState reports whether code is a Brazilian state, e.g. "SP"
+func IsState(code string) bool {
+	_, ok := macroRegions[code]
+	return ok
+}
+
+// IsMacroRegion reports whether name is one of the Region constants
+func IsMacroRegion(name string) bool {
+	switch name {
+	case RegionNorth, RegionNortheast, RegionMidwest, RegionSoutheast, RegionSouth:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package zipcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState(t *testing.T) {
+	tests := []struct {
+		zipcode string
+		want    string
+	}{
+		{"01310-100", "SP"},
+		{"1310100", "SP"},
+		{"20040002", "RJ"},
+		{"40010 000", "BA"},
+		{"69301-000", "RR"},
+		{"69900000", "AC"},
+		{"70040-010", "DF"},
+		{"74000000", "GO"},
+		{"90010-000", "RS"},
+		{"00999000", ""},
+		{"123", ""},
+		{"abcde000", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zipcode, func(t *testing.T) {
+			// Act
+			result := State(tt.zipcode)
+
+			// Assert
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestMacroRegion(t *testing.T) {
+	tests := []struct {
+		zipcode string
+		want    string
+	}{
+		{"01310-100", RegionSoutheast},
+		{"20040-020", RegionSoutheast},
+		{"69005-040", RegionNorth},
+		{"49000-000", RegionNortheast},
+		{"70040-010", RegionMidwest},
+		{"90010-000", RegionSouth},
+		{"123", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zipcode, func(t *testing.T) {
+			// Act
+			result := MacroRegion(tt.zipcode)
+
+			// Assert
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestIsStateAndIsMacroRegion(t *testing.T) {
+	// Act & Assert
+	assert.True(t, IsState("SP"))
+	assert.False(t, IsState("sp"))
+	assert.True(t, IsMacroRegion(RegionNortheast))
+	assert.False(t, IsMacroRegion("SE"))
+}
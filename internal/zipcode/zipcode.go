@@ -0,0 +1,76 @@
+// Package zipcode normalizes Brazilian zipcodes (CEP) and exposes the parts of their structure
+// used for routing: region, sub-region and sector.
+package zipcode
+
+import "strings"
+
+const (
+	// Length is the number of digits of a complete CEP
+	Length = 8
+	// MinLength is the number of digits of the shortest zipcode accepted, for partial codes
+	MinLength = 4
+)
+
+// separators are removed from zipcodes: "01.310-100" and "01310 100" are 01310100
+var separators = strings.NewReplacer("-", "", ".", "", " ", "")
+
+// Strip removes the separators (hyphens, dots and spaces) and the surrounding whitespace from a
+// zipcode. Separators are removed first, so that whitespace they surround is trimmed too and
+// stripping is idempotent
+func Strip(zipcode string) string {
+	return strings.TrimSpace(separators.Replace(zipcode))
+}
+
+// Normalize strips the separators from a zipcode and restores the leading zero of CEPs stored as
+// numbers: the lowest CEP is 01000-000, so a 7-digit zipcode is a CEP that lost its leading zero
+// (1310100 is 01310100). Other zipcodes are returned stripped
+func Normalize(zipcode string) string {
+	stripped := Strip(zipcode)
+	if len(stripped) == Length-1 && Numeric(stripped) {
+		return "0" + stripped
+	}
+	return stripped
+}
+
+// Numeric reports whether a stripped zipcode is made only of ASCII digits
+func Numeric(stripped string) bool {
+	if stripped == "" {
+		return false
+	}
+	for i := 0; i < len(stripped); i++ {
+		if stripped[i] < '0' || stripped[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Valid reports whether a zipcode has MinLength to Length digits once stripped of separators
+func Valid(zipcode string) bool {
+	stripped := Strip(zipcode)
+	return len(stripped) >= MinLength && len(stripped) <= Length && Numeric(stripped)
+}
+
+// Region returns the postal region of a zipcode, its first digit (e.g. 0 for Greater São Paulo)
+func Region(zipcode string) string {
+	return Prefix(zipcode, 1)
+}
+
+// Subregion returns the postal sub-region of a zipcode, its first 2 digits
+func Subregion(zipcode string) string {
+	return Prefix(zipcode, 2)
+}
+
+// Sector returns the postal sector of a zipcode, its first 3 digits
+func Sector(zipcode string) string {
+	return Prefix(zipcode, 3)
+}
+
+// Prefix returns the first n digits of the normalized zipcode, or "" when it is shorter
+func Prefix(zipcode string, n int) string {
+	normalized := Normalize(zipcode)
+	if len(normalized) < n {
+		return ""
+	}
+	return normalized[:n]
+}
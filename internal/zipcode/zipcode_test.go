@@ -0,0 +1,113 @@
+package zipcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		zipcode string
+		want    string
+	}{
+		{"complete", "01310100", "01310100"},
+		{"hyphen", "01310-100", "01310100"},
+		{"dot and hyphen", "01.310-100", "01310100"},
+		{"spaces", " 01310 100 ", "01310100"},
+		{"lost leading zero", "1310100", "01310100"},
+		{"lost leading zero with hyphen", "1310-100", "01310100"},
+		{"partial", "1414", "1414"},
+		{"not numeric", "A1310100", "A1310100"},
+		{"7 characters not numeric", "131010X", "131010X"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := Normalize(tt.zipcode)
+
+			// Assert
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestStrip(t *testing.T) {
+	// Act
+	result := Strip(" 01.310-100 ")
+
+	// Assert
+	assert.Equal(t, "01310100", result)
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		zipcode string
+		want    bool
+	}{
+		{"01310100", true},
+		{"01.310-100", true},
+		{"1414", true},
+		{"141", false},
+		{"123456789", false},
+		{"0131A100", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zipcode, func(t *testing.T) {
+			// Act
+			result := Valid(tt.zipcode)
+
+			// Assert
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestRegions(t *testing.T) {
+	tests := []struct {
+		name          string
+		zipcode       string
+		wantRegion    string
+		wantSubregion string
+		wantSector    string
+	}{
+		{"complete", "04547-130", "0", "04", "045"},
+		{"lost leading zero", "4547130", "0", "04", "045"},
+		{"partial", "20", "2", "20", ""},
+		{"empty", "", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.wantRegion, Region(tt.zipcode))
+			assert.Equal(t, tt.wantSubregion, Subregion(tt.zipcode))
+			assert.Equal(t, tt.wantSector, Sector(tt.zipcode))
+		})
+	}
+}
+
+func FuzzNormalize(f *testing.F) {
+	for _, seed := range []string{"01310-100", "1310100", "01.310 100", "1414", "０１３１０１００", "٠١٣١٠١٠٠", "0131​0100", "0131𝟘100", "\xff\xfe", ""} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, zipcode string) {
+		normalized := Normalize(zipcode)
+
+		if Normalize(normalized) != normalized {
+			t.Fatalf("Normalize is not idempotent for %q: %q", zipcode, normalized)
+		}
+		if Valid(zipcode) && (!Numeric(normalized) || len(normalized) < MinLength || len(normalized) > Length) {
+			t.Fatalf("valid zipcode %q normalized to %q", zipcode, normalized)
+		}
+		if Valid(zipcode) && State(zipcode) == "" && len(normalized) == Length && normalized >= "01000000" {
+			t.Fatalf("complete zipcode %q has no state", zipcode)
+		}
+	})
+}
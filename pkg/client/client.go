@@ -0,0 +1,575 @@
+// Package client is a typed Go client for the shipping calculator API.
+//
+//	c, err := client.New("http://shipping-calculator:8080", client.WithAPIKey(key))
+//	quote, err := c.Calculate(ctx, &client.CalculateRequest{...})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// APIKeyHeader is the header carrying the API key
+const APIKeyHeader = "X-API-Key"
+
+// StrictSchemaHeader asks the API to reject unknown request fields instead of ignoring them
+const StrictSchemaHeader = "X-Strict-Schema"
+
+// ClientIDHeader identifies the calling client in the API metrics
+const ClientIDHeader = "X-Client-ID"
+
+// TenantHeader selects the tenant whose rates price the request
+const TenantHeader = "X-Tenant-ID"
+
+const (
+	defaultTimeout          = 10 * time.Second
+	defaultMaxRetries       = 2
+	defaultRetryBackoff     = 100 * time.Millisecond
+	defaultBatchConcurrency = 8
+)
+
+// CalculateRequest is the input of a shipping calculation
+type CalculateRequest struct {
+	OriginZipcode      string     `json:"origin_zipcode"`
+	DestinationZipcode string     `json:"destination_zipcode"`
+	Weight             float64    `json:"weight"`
+	Dimensions         Dimensions `json:"dimensions"`
+	IsExpress          bool       `json:"is_express"`
+	// DestinationCountry is the ISO 3166-1 alpha-2 destination country (default: BR)
+	DestinationCountry string `json:"destination_country,omitempty"`
+	// Currency is the ISO 4217 quote currency (default: currency of the destination country)
+	Currency string `json:"currency,omitempty"`
+	// PackageType is standard, fragile, perishable or dangerous (default: standard)
+	PackageType string `json:"package_type,omitempty"`
+	// AdditionalServices are optional services charged on top of freight: cod, signature, saturday_delivery
+	AdditionalServices []string `json:"additional_services,omitempty"`
+	// DeliveryType is home, pickup_point or locker (default: home)
+	DeliveryType string `json:"delivery_type,omitempty"`
+	// PricingStrategy is formula, table or carrier (default: the strategy configured for the service level)
+	PricingStrategy string `json:"pricing_strategy,omitempty"`
+	// IsReturn quotes the return of the package, from the destination back to the origin
+	IsReturn bool `json:"is_return,omitempty"`
+	// PickupDate ("YYYY-MM-DD") and PickupWindow (e.g. morning, afternoon) schedule the collection
+	// of the package; the delivery estimate starts on the pickup date
+	PickupDate   string `json:"pickup_date,omitempty"`
+	PickupWindow string `json:"pickup_window,omitempty"`
+	// HSCode and DeclaredValue (in minor units of the quote currency) describe the goods of
+	// international shipments; the import duties and taxes are estimated in the breakdown
+	HSCode        string  `json:"hs_code,omitempty"`
+	DeclaredValue float64 `json:"declared_value,omitempty"`
+	// FreightClass is the freight class ("50" to "500") of heavy shipments, quoted as an additional
+	// "freight" option when freight rates are configured
+	FreightClass string `json:"freight_class,omitempty"`
+	// WeightUnit (kg, g or lb; default kg) and DimensionUnit (cm, m or in; default cm) are the
+	// units of Weight and Dimensions
+	WeightUnit    string `json:"weight_unit,omitempty"`
+	DimensionUnit string `json:"dimension_unit,omitempty"`
+	// Optimize (cheapest, fastest or balanced) orders ShippingOptions by the objective and
+	// selects the first option instead of IsExpress
+	Optimize string `json:"optimize,omitempty"`
+	// AllowWeekendDelivery lets the service levels configured to deliver on Saturdays or Sundays
+	// count those days in their estimate, charging their weekend surcharge
+	AllowWeekendDelivery bool `json:"allow_weekend_delivery,omitempty"`
+}
+
+// Dimensions are the package dimensions in centimeters, unless the request sets DimensionUnit
+type Dimensions struct {
+	Length float64 `json:"length"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// CalculateResponse is the result of a shipping calculation. Costs are in minor units (cents) of Currency
+type CalculateResponse struct {
+	QuoteID        string `json:"quote_id,omitempty"`
+	Currency       string `json:"currency,omitempty"`
+	PricingVersion string `json:"pricing_version,omitempty"`
+	// ExpiresAt is when the quoted price stops being honored; use Revalidate to reprice the quote
+	ExpiresAt             *time.Time       `json:"expires_at,omitempty"`
+	ShippingCost          float64          `json:"shipping_cost"`
+	EstimatedDeliveryTime string           `json:"estimated_delivery_time"`
+	AvailableServices     []string         `json:"available_services"`
+	ShippingOptions       []ShippingOption `json:"shipping_options"`
+	Breakdown             *CostBreakdown   `json:"breakdown,omitempty"`
+	// DeliveryDate is the local date ("YYYY-MM-DD") at the destination of the delivery of
+	// SelectedService
+	DeliveryDate string `json:"delivery_date,omitempty"`
+	// SelectedService is the service level of ShippingCost and Breakdown
+	SelectedService string                `json:"selected_service,omitempty"`
+	Experiment      *ExperimentAssignment `json:"experiment,omitempty"`
+	// Package is the weight and dimensions priced, converted to kilograms and centimeters
+	Package *PackageMeasures `json:"package,omitempty"`
+	// FuelIndex is the fuel surcharge index the quote was priced with, charged in
+	// CostBreakdown.FuelSurcharge
+	FuelIndex *FuelIndex `json:"fuel_index,omitempty"`
+	// Degraded is set when the API priced the quote with its fallback formula while under load
+	Degraded bool `json:"degraded,omitempty"`
+}
+
+// FuelIndex is the rate and effective date ("YYYY-MM-DD") of a fuel surcharge index
+type FuelIndex struct {
+	Rate          float64 `json:"rate"`
+	EffectiveDate string  `json:"effective_date"`
+}
+
+// PackageMeasures are the weight and dimensions of a package in kilograms and centimeters
+type PackageMeasures struct {
+	WeightKg     float64    `json:"weight_kg"`
+	DimensionsCm Dimensions `json:"dimensions_cm"`
+}
+
+// ExperimentAssignment identifies the pricing experiment arm that priced the quote
+type ExperimentAssignment struct {
+	Name string `json:"name"`
+	Arm  string `json:"arm"`
+}
+
+// CostBreakdown itemizes the cost of the selected service; Total equals ShippingCost
+type CostBreakdown struct {
+	BaseCost               float64 `json:"base_cost"`
+	WeightSurcharge        float64 `json:"weight_surcharge"`
+	VolumeSurcharge        float64 `json:"volume_surcharge"`
+	PackageTypeSurcharge   float64 `json:"package_type_surcharge"`
+	DeliveryTypeAdjustment float64 `json:"delivery_type_adjustment"`
+	ExpressSurcharge       float64 `json:"express_surcharge"`
+	// ReturnAdjustment is the return discount (negative) or surcharge (positive) of return quotes
+	ReturnAdjustment float64 `json:"return_adjustment,omitempty"`
+	// PickupSurcharge is charged for scheduled pickups in premium windows or for the same day
+	PickupSurcharge float64 `json:"pickup_surcharge,omitempty"`
+	// RestrictedArea names the restricted area of the destination, charged RestrictedAreaSurcharge
+	RestrictedArea          string  `json:"restricted_area,omitempty"`
+	RestrictedAreaSurcharge float64 `json:"restricted_area_surcharge,omitempty"`
+	// RemoteAreas names the remote areas of the destination, charged RemoteAreaSurcharge together
+	RemoteAreas         []string `json:"remote_areas,omitempty"`
+	RemoteAreaSurcharge float64  `json:"remote_area_surcharge,omitempty"`
+	// WeekendSurcharge is charged when the selected service delivers on weekends
+	WeekendSurcharge float64 `json:"weekend_surcharge,omitempty"`
+	// FuelSurcharge is the fuel surcharge of FuelIndex, a fraction of the freight
+	FuelSurcharge float64 `json:"fuel_surcharge,omitempty"`
+	// PriceLimit is "floor" or "ceiling" when the freight was clamped to the minimum or maximum
+	// price of the route, and PriceLimitAdjustment the amount added or removed to reach it
+	PriceLimit           string       `json:"price_limit,omitempty"`
+	PriceLimitAdjustment float64      `json:"price_limit_adjustment,omitempty"`
+	AdditionalServices   []ServiceFee `json:"additional_services,omitempty"`
+	// UnroundedTotal is the cost before the rounding policy of the currency and
+	// RoundingAdjustment the difference rounding made to reach Total
+	UnroundedTotal     float64 `json:"unrounded_total"`
+	RoundingAdjustment float64 `json:"rounding_adjustment,omitempty"`
+	Total              float64 `json:"total"`
+	// Duties are the estimated import duty and tax, paid at the destination and not included in
+	// Total; LandedCost is the declared value plus Total and Duties
+	Duties     []DutyCharge `json:"duties,omitempty"`
+	LandedCost float64      `json:"landed_cost,omitempty"`
+	// Tax is the ICMS or ISS included in Total for domestic routes, when the API computes taxes
+	Tax *FreightTax `json:"tax,omitempty"`
+}
+
+// FreightTax is the ICMS or ISS ("ICMS" or "ISS") included in the freight: Gross is the freight
+// charged, Amount the tax it includes at Rate and Net the freight without the tax
+type FreightTax struct {
+	Tax              string  `json:"tax"`
+	Rate             float64 `json:"rate"`
+	OriginState      string  `json:"origin_state"`
+	DestinationState string  `json:"destination_state"`
+	// Municipality is the municipality of routes paying ISS
+	Municipality string  `json:"municipality,omitempty"`
+	Gross        float64 `json:"gross"`
+	Amount       float64 `json:"amount"`
+	Net          float64 `json:"net"`
+}
+
+// DutyCharge is an estimated import duty ("import_duty") or tax ("import_tax")
+type DutyCharge struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount float64 `json:"amount"`
+}
+
+// ServiceFee is the fee charged for an additional service
+type ServiceFee struct {
+	Service string  `json:"service"`
+	Fee     float64 `json:"fee"`
+}
+
+// ShippingOption is a quoted shipping service
+type ShippingOption struct {
+	Service string  `json:"service"`
+	Cost    float64 `json:"cost"`
+	Time    string  `json:"time"`
+	// EstimatedDays are the delivery days of Time
+	EstimatedDays int `json:"estimated_days"`
+	// DeliveryDate is the local date ("YYYY-MM-DD") of the delivery at the destination
+	DeliveryDate string `json:"delivery_date,omitempty"`
+	// WeekendDelivery marks the options delivering on weekends, with their weekend surcharge in Cost
+	WeekendDelivery bool `json:"weekend_delivery,omitempty"`
+	// Cheapest and Fastest mark the options with the lowest cost and the fewest delivery days
+	Cheapest bool `json:"cheapest,omitempty"`
+	Fastest  bool `json:"fastest,omitempty"`
+	// Token is a signed quote token vouching for the option, verified with pkg/quotetoken
+	Token string `json:"token,omitempty"`
+}
+
+// QuoteRevalidation is the result of repricing a quote with the current rates
+type QuoteRevalidation struct {
+	// QuoteID identifies the quote repriced; Quote.QuoteID identifies the new quote to book
+	QuoteID string `json:"quote_id"`
+	// Expired reports whether the quote had expired when it was revalidated
+	Expired bool `json:"expired"`
+	// PriceChanged reports whether Quote.ShippingCost differs from PreviousCost
+	PriceChanged bool               `json:"price_changed"`
+	PreviousCost float64            `json:"previous_cost"`
+	Quote        *CalculateResponse `json:"quote"`
+}
+
+// BookShipmentRequest books a shipment from a previous quote
+type BookShipmentRequest struct {
+	QuoteID string `json:"quote_id"`
+	// Service is the quoted service level to ship with (default: the level selected in the quote)
+	Service string `json:"service,omitempty"`
+}
+
+// Shipment is a shipment booked from a quote. Cost is in minor units (cents) of Currency
+type Shipment struct {
+	ID                    string          `json:"id"`
+	QuoteID               string          `json:"quote_id"`
+	Status                string          `json:"status"`
+	Service               string          `json:"service"`
+	Currency              string          `json:"currency,omitempty"`
+	Cost                  float64         `json:"cost"`
+	EstimatedDeliveryTime string          `json:"estimated_delivery_time"`
+	PricingVersion        string          `json:"pricing_version,omitempty"`
+	Package               ShipmentPackage `json:"package"`
+	BookedAt              time.Time       `json:"booked_at"`
+}
+
+// ShipmentPackage describes the shipped package and route, as quoted
+type ShipmentPackage struct {
+	OriginZipcode      string     `json:"origin_zipcode"`
+	DestinationZipcode string     `json:"destination_zipcode"`
+	DestinationCountry string     `json:"destination_country,omitempty"`
+	Weight             float64    `json:"weight"`
+	Dimensions         Dimensions `json:"dimensions"`
+	PackageType        string     `json:"package_type,omitempty"`
+	DeliveryType       string     `json:"delivery_type,omitempty"`
+	AdditionalServices []string   `json:"additional_services,omitempty"`
+}
+
+// ShipmentTracking is the tracking history of a shipment
+type ShipmentTracking struct {
+	ShipmentID string `json:"shipment_id"`
+	// Status is posted, in_transit or delivered, or booked while the carrier reported no events
+	Status string `json:"status"`
+	// Events are ordered by OccurredAt, oldest first
+	Events []TrackingEvent `json:"events"`
+}
+
+// TrackingEvent is a status update of a shipment reported by the carrier
+type TrackingEvent struct {
+	Status      string    `json:"status"`
+	Description string    `json:"description,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// BatchResult is the outcome of one request of CalculateBatch
+type BatchResult struct {
+	Response *CalculateResponse
+	Err      error
+}
+
+// ErrorCodeNotServiceable is the Code of an APIError for a destination the API does not serve
+const ErrorCodeNotServiceable = "NOT_SERVICEABLE"
+
+// APIError is returned when the API answers with a non-2xx status
+type APIError struct {
+	StatusCode int
+	Message    string
+	// Code classifies some errors, e.g. ErrorCodeNotServiceable; empty for the others
+	Code string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("shipping calculator: status %d: %s", e.StatusCode, e.Message)
+}
+
+// Temporary reports whether the request may succeed if retried
+func (e *APIError) Temporary() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// Client calls the shipping calculator API. It is safe for concurrent use
+type Client struct {
+	baseURL          *url.URL
+	httpClient       *http.Client
+	apiKey           string
+	strictSchema     bool
+	clientID         string
+	tenant           string
+	maxRetries       int
+	retryBackoff     time.Duration
+	batchConcurrency int
+	propagator       propagation.TextMapPropagator
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests (default: 10s timeout)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithAPIKey sends the API key in the X-API-Key header
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) { c.apiKey = apiKey }
+}
+
+// WithStrictSchema asks the API to reject requests with unknown fields, reporting the closest
+// known field, so that fields the API version does not support are not silently ignored
+func WithStrictSchema() Option {
+	return func(c *Client) { c.strictSchema = true }
+}
+
+// WithClientID sends the client ID in the X-Client-ID header, so that the API metrics of the
+// client can be told apart; IDs the API does not know are reported as "other"
+func WithClientID(clientID string) Option {
+	return func(c *Client) { c.clientID = clientID }
+}
+
+// WithTenant sends the tenant ID in the X-Tenant-ID header, pricing requests with the rates of
+// the tenant. Tenants with API keys also require WithAPIKey; a tenant's API key alone selects it
+func WithTenant(tenantID string) Option {
+	return func(c *Client) { c.tenant = tenantID }
+}
+
+// WithRetries sets how many times network errors and 429/502/503/504 responses are retried,
+// waiting backoff, 2*backoff, 4*backoff... between attempts (default: 2 retries, 100ms)
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+	}
+}
+
+// WithBatchConcurrency sets how many requests CalculateBatch sends in parallel (default: 8)
+func WithBatchConcurrency(n int) Option {
+	return func(c *Client) { c.batchConcurrency = n }
+}
+
+// WithPropagator sets the propagator injecting the trace context into requests
+// (default: the global OpenTelemetry propagator)
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *Client) { c.propagator = propagator }
+}
+
+// New creates a client for the API at baseURL
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:          parsed,
+		httpClient:       &http.Client{Timeout: defaultTimeout},
+		maxRetries:       defaultMaxRetries,
+		retryBackoff:     defaultRetryBackoff,
+		batchConcurrency: defaultBatchConcurrency,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxRetries < 0 {
+		return nil, errors.New("max retries must not be negative")
+	}
+	if c.batchConcurrency <= 0 {
+		return nil, errors.New("batch concurrency must be positive")
+	}
+	return c, nil
+}
+
+// Calculate quotes a single shipment
+func (c *Client) Calculate(ctx context.Context, req *CalculateRequest) (*CalculateResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	var response CalculateResponse
+	if err := c.post(ctx, "/calculate", body, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CalculateBatch quotes several shipments in parallel. Results are returned in the order of
+// the requests; a failed request does not affect the others
+func (c *Client) CalculateBatch(ctx context.Context, reqs []CalculateRequest) []BatchResult {
+	results := make([]BatchResult, len(reqs))
+	sem := make(chan struct{}, c.batchConcurrency)
+
+	var wg sync.WaitGroup
+	for i := range reqs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			response, err := c.Calculate(ctx, &reqs[i])
+			results[i] = BatchResult{Response: response, Err: err}
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// Revalidate reprices a previous quote with the current rates as a new quote, returned in
+// QuoteRevalidation.Quote with its own ID and expiration; the previous quote is left unchanged
+func (c *Client) Revalidate(ctx context.Context, quoteID string) (*QuoteRevalidation, error) {
+	var revalidation QuoteRevalidation
+	if err := c.post(ctx, "/quotes/"+url.PathEscape(quoteID)+"/revalidate", nil, &revalidation); err != nil {
+		return nil, err
+	}
+	return &revalidation, nil
+}
+
+// BookShipment books a shipment at the price of a previous quote. A quote can be booked only once:
+// booking it again, including a retry of a booking that succeeded, fails with a 409 APIError
+func (c *Client) BookShipment(ctx context.Context, req *BookShipmentRequest) (*Shipment, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	var shipment Shipment
+	if err := c.post(ctx, "/shipments", body, &shipment); err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+// Tracking returns the tracking history of a booked shipment
+func (c *Client) Tracking(ctx context.Context, shipmentID string) (*ShipmentTracking, error) {
+	var result ShipmentTracking
+	if err := c.get(ctx, "/shipments/"+url.PathEscape(shipmentID)+"/tracking", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// get fetches path, retrying temporary failures, and decodes the response into out
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	return c.send(ctx, http.MethodGet, path, nil, out)
+}
+
+// post sends the JSON body, retrying temporary failures, and decodes the response into out
+func (c *Client) post(ctx context.Context, path string, body []byte, out interface{}) error {
+	return c.send(ctx, http.MethodPost, path, body, out)
+}
+
+// send performs the request, retrying temporary failures, and decodes the response into out
+func (c *Client) send(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(c.retryBackoff << (attempt - 1))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		retry, err := c.do(ctx, method, path, body, out)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || ctx.Err() != nil {
+			break
+		}
+	}
+	return lastErr
+}
+
+// do performs a single attempt, reporting whether a failure is retryable
+func (c *Client) do(ctx context.Context, method, path string, body []byte, out interface{}) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL.String()+path, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	if method != http.MethodGet {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set(APIKeyHeader, c.apiKey)
+	}
+	if c.strictSchema {
+		req.Header.Set(StrictSchemaHeader, "true")
+	}
+	if c.clientID != "" {
+		req.Header.Set(ClientIDHeader, c.clientID)
+	}
+	if c.tenant != "" {
+		req.Header.Set(TenantHeader, c.tenant)
+	}
+	c.tracePropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("shipping calculator: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, code := errorMessage(resp.Body)
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: message, Code: code}
+		return apiErr.Temporary(), apiErr
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("shipping calculator: invalid response body: %w", err)
+	}
+	return false, nil
+}
+
+func (c *Client) tracePropagator() propagation.TextMapPropagator {
+	if c.propagator != nil {
+		return c.propagator
+	}
+	return otel.GetTextMapPropagator()
+}
+
+// errorMessage extracts the "error" and "code" fields of an error body, falling back to the raw
+// body as message
+func errorMessage(body io.Reader) (string, string) {
+	raw, _ := io.ReadAll(io.LimitReader(body, 4096))
+	var payload struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if err := json.Unmarshal(raw, &payload); err == nil && payload.Error != "" {
+		return payload.Error, payload.Code
+	}
+	return strings.TrimSpace(string(raw)), ""
+}
@@ -0,0 +1,448 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var testRequest = CalculateRequest{
+	OriginZipcode:      "12345678",
+	DestinationZipcode: "87654321",
+	Weight:             1,
+	Dimensions:         Dimensions{Length: 10, Width: 10, Height: 10},
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	opts = append([]Option{WithRetries(2, time.Millisecond)}, opts...)
+	c, err := New(server.URL, opts...)
+	assert.NoError(t, err)
+	return c
+}
+
+func TestCalculate(t *testing.T) {
+	// Arrange
+	var received CalculateRequest
+	var headers http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/calculate", r.URL.Path)
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"quote_id":"q-1","shipping_cost":1250,"estimated_delivery_time":"2 dias",` +
+			`"available_services":["standard","express"],"shipping_options":[{"service":"standard","cost":1250,"time":"2 dias"}]}`))
+	}, WithAPIKey("secret"))
+
+	// Act
+	response, err := c.Calculate(context.Background(), &testRequest)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, testRequest, received)
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	assert.Equal(t, "secret", headers.Get(APIKeyHeader))
+	assert.Equal(t, &CalculateResponse{
+		QuoteID:               "q-1",
+		ShippingCost:          1250,
+		EstimatedDeliveryTime: "2 dias",
+		AvailableServices:     []string{"standard", "express"},
+		ShippingOptions:       []ShippingOption{{Service: "standard", Cost: 1250, Time: "2 dias"}},
+	}, response)
+}
+
+func TestCalculate_StrictSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"lenient", nil, ""},
+		{"strict", []Option{WithStrictSchema()}, "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var header string
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header.Get(StrictSchemaHeader)
+				_, _ = w.Write([]byte(`{}`))
+			}, tt.opts...)
+
+			// Act
+			_, err := c.Calculate(context.Background(), &testRequest)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, header)
+		})
+	}
+}
+
+func TestCalculate_ClientID(t *testing.T) {
+	// Arrange
+	var header string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(ClientIDHeader)
+		_, _ = w.Write([]byte(`{}`))
+	}, WithClientID("checkout"))
+
+	// Act
+	_, err := c.Calculate(context.Background(), &testRequest)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "checkout", header)
+}
+
+func TestCalculate_Tenant(t *testing.T) {
+	// Arrange
+	var header string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(TenantHeader)
+		_, _ = w.Write([]byte(`{}`))
+	}, WithTenant("acme"))
+
+	// Act
+	_, err := c.Calculate(context.Background(), &testRequest)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", header)
+}
+
+func TestCalculate_PropagatesTraceContext(t *testing.T) {
+	// Arrange
+	var traceparent string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		_, _ = w.Write([]byte(`{}`))
+	}, WithPropagator(propagation.TraceContext{}))
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	// Act
+	_, err := c.Calculate(ctx, &testRequest)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", traceparent)
+}
+
+func TestCalculate_APIError(t *testing.T) {
+	// Arrange
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid weight: weight must be greater than 0"}`))
+	})
+
+	// Act
+	_, err := c.Calculate(context.Background(), &testRequest)
+
+	// Assert
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "invalid weight: weight must be greater than 0", apiErr.Message)
+	assert.False(t, apiErr.Temporary())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "client errors must not be retried")
+}
+
+func TestCalculate_APIErrorCode(t *testing.T) {
+	// Arrange
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"error":"destination is not serviceable by standard: restricted area noronha","code":"NOT_SERVICEABLE"}`))
+	})
+
+	// Act
+	_, err := c.Calculate(context.Background(), &testRequest)
+
+	// Assert
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+	assert.Equal(t, ErrorCodeNotServiceable, apiErr.Code)
+}
+
+func TestCalculate_RetriesTemporaryErrors(t *testing.T) {
+	// Arrange
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"shipping_cost":1250}`))
+	})
+
+	// Act
+	response, err := c.Calculate(context.Background(), &testRequest)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1250.0, response.ShippingCost)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestCalculate_RetriesExhausted(t *testing.T) {
+	// Arrange
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("bad gateway"))
+	})
+
+	// Act
+	_, err := c.Calculate(context.Background(), &testRequest)
+
+	// Assert
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "bad gateway", apiErr.Message)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestCalculate_ContextCancelledDuringBackoff(t *testing.T) {
+	// Arrange
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, WithRetries(5, time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Act
+	_, err := c.Calculate(ctx, &testRequest)
+
+	// Assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestCalculate_InvalidResponseBody(t *testing.T) {
+	// Arrange
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("not json"))
+	})
+
+	// Act
+	_, err := c.Calculate(context.Background(), &testRequest)
+
+	// Assert
+	assert.ErrorContains(t, err, "invalid response body")
+}
+
+func TestCalculateBatch(t *testing.T) {
+	// Arrange
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req CalculateRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Weight <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid weight"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(CalculateResponse{ShippingCost: req.Weight * 1000})
+	}, WithBatchConcurrency(2))
+
+	reqs := []CalculateRequest{testRequest, testRequest, testRequest}
+	reqs[1].Weight = 0
+	reqs[2].Weight = 2
+
+	// Act
+	results := c.CalculateBatch(context.Background(), reqs)
+
+	// Assert
+	assert.Len(t, results, 3)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, 1000.0, results[0].Response.ShippingCost)
+	assert.Error(t, results[1].Err)
+	assert.Nil(t, results[1].Response)
+	assert.NoError(t, results[2].Err)
+	assert.Equal(t, 2000.0, results[2].Response.ShippingCost)
+}
+
+func TestRevalidate(t *testing.T) {
+	// Arrange
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/quotes/q-1/revalidate", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"quote_id":"q-1","expired":true,"price_changed":true,"previous_cost":1250,` +
+			`"quote":{"quote_id":"q-1","expires_at":"2025-01-10T12:30:00Z","shipping_cost":1400}}`))
+	})
+
+	// Act
+	revalidation, err := c.Revalidate(context.Background(), "q-1")
+
+	// Assert
+	assert.NoError(t, err)
+	expiresAt := time.Date(2025, 1, 10, 12, 30, 0, 0, time.UTC)
+	assert.Equal(t, &QuoteRevalidation{
+		QuoteID:      "q-1",
+		Expired:      true,
+		PriceChanged: true,
+		PreviousCost: 1250,
+		Quote:        &CalculateResponse{QuoteID: "q-1", ExpiresAt: &expiresAt, ShippingCost: 1400},
+	}, revalidation)
+}
+
+func TestRevalidate_NotFound(t *testing.T) {
+	// Arrange
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"quote not found"}`))
+	})
+
+	// Act
+	revalidation, err := c.Revalidate(context.Background(), "missing")
+
+	// Assert
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "quote not found", apiErr.Message)
+	assert.Nil(t, revalidation)
+}
+
+func TestBookShipment(t *testing.T) {
+	// Arrange
+	var received BookShipmentRequest
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/shipments", r.URL.Path)
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"s-1","quote_id":"q-1","status":"booked","service":"express","currency":"BRL",` +
+			`"cost":2100,"estimated_delivery_time":"1 dia útil","package":{"origin_zipcode":"01310-100",` +
+			`"destination_zipcode":"20040-020","weight":2.5},"booked_at":"2025-01-10T12:00:00Z"}`))
+	})
+
+	// Act
+	shipment, err := c.BookShipment(context.Background(), &BookShipmentRequest{QuoteID: "q-1", Service: "express"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, BookShipmentRequest{QuoteID: "q-1", Service: "express"}, received)
+	assert.Equal(t, &Shipment{
+		ID:                    "s-1",
+		QuoteID:               "q-1",
+		Status:                "booked",
+		Service:               "express",
+		Currency:              "BRL",
+		Cost:                  2100,
+		EstimatedDeliveryTime: "1 dia útil",
+		Package:               ShipmentPackage{OriginZipcode: "01310-100", DestinationZipcode: "20040-020", Weight: 2.5},
+		BookedAt:              time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+	}, shipment)
+}
+
+func TestBookShipment_AlreadyBooked(t *testing.T) {
+	// Arrange
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error":"quote already booked"}`))
+	})
+
+	// Act
+	shipment, err := c.BookShipment(context.Background(), &BookShipmentRequest{QuoteID: "q-1"})
+
+	// Assert
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	assert.Equal(t, "quote already booked", apiErr.Message)
+	assert.Nil(t, shipment)
+}
+
+func TestTracking(t *testing.T) {
+	// Arrange
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/shipments/s-1/tracking", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"shipment_id":"s-1","status":"in_transit","events":[` +
+			`{"status":"posted","location":"São Paulo/SP","occurred_at":"2025-01-10T15:00:00Z"},` +
+			`{"status":"in_transit","occurred_at":"2025-01-11T08:00:00Z"}]}`))
+	})
+
+	// Act
+	result, err := c.Tracking(context.Background(), "s-1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &ShipmentTracking{
+		ShipmentID: "s-1",
+		Status:     "in_transit",
+		Events: []TrackingEvent{
+			{Status: "posted", Location: "São Paulo/SP", OccurredAt: time.Date(2025, 1, 10, 15, 0, 0, 0, time.UTC)},
+			{Status: "in_transit", OccurredAt: time.Date(2025, 1, 11, 8, 0, 0, 0, time.UTC)},
+		},
+	}, result)
+}
+
+func TestTracking_RetriesTemporaryErrors(t *testing.T) {
+	// Arrange
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"shipment_id":"s-1","status":"booked","events":[]}`))
+	}, WithRetries(1, time.Millisecond))
+
+	// Act
+	result, err := c.Tracking(context.Background(), "s-1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "booked", result.Status)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		opts    []Option
+	}{
+		{"invalid scheme", "ftp://example.com", nil},
+		{"missing scheme", "example.com", nil},
+		{"negative retries", "http://example.com", []Option{WithRetries(-1, 0)}},
+		{"zero concurrency", "http://example.com", []Option{WithBatchConcurrency(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			c, err := New(tt.baseURL, tt.opts...)
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, c)
+		})
+	}
+}
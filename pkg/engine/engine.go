@@ -0,0 +1,123 @@
+// Package engine embeds the shipping calculator in other Go programs: a request in, a quote out,
+// priced and estimated exactly as POST /calculate does, without running the HTTP service. The
+// engine has no side effects besides reading its configuration files: it does not log, record
+// telemetry, look zipcodes up or persist quotes.
+//
+//	e, err := engine.New(ctx, engine.Config{PricingConfigPath: "pricing.json"})
+//	quote, err := e.Calculate(ctx, &engine.Request{OriginZipcode: "01310100", ...})
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/determinism"
+	"github.com/rbonfanti/shipping-calculator/internal/eta"
+	"github.com/rbonfanti/shipping-calculator/internal/holiday"
+	"github.com/rbonfanti/shipping-calculator/internal/logger"
+	"github.com/rbonfanti/shipping-calculator/internal/mapper"
+	"github.com/rbonfanti/shipping-calculator/internal/pricing"
+	"github.com/rbonfanti/shipping-calculator/internal/schedule"
+	"github.com/rbonfanti/shipping-calculator/internal/service"
+	"github.com/rbonfanti/shipping-calculator/internal/timezone"
+	v1 "github.com/rbonfanti/shipping-calculator/internal/transport/v1"
+	"go.uber.org/zap"
+)
+
+// Request and quote types, the same as the body and the response of POST /calculate
+type (
+	Request              = v1.CalculateShippingRequest
+	Dimensions           = v1.PackageDimensions
+	Quote                = v1.CalculateShippingResponse
+	ShippingOption       = v1.ShippingOption
+	CostBreakdown        = v1.CostBreakdown
+	FreightTax           = v1.FreightTax
+	DutyCharge           = v1.DutyCharge
+	ServiceFee           = v1.ServiceFee
+	FuelIndex            = v1.FuelIndex
+	PackageMeasures      = v1.PackageMeasures
+	ExperimentAssignment = v1.ExperimentAssignment
+)
+
+// ValidationError is a request rejected because of the value of one of its fields, e.g. an
+// invalid zipcode; use errors.As to tell it from other calculation errors
+type ValidationError = service.ValidationError
+
+// Config configures an engine; the zero value prices with the built-in rates, without warehouse
+// handling time or holidays, at the current time
+type Config struct {
+	// PricingConfigPath is the file of the rates per currency and destination country, in the
+	// format of PRICING_CONFIG_PATH
+	PricingConfigPath string
+	// ETAConfigPath is the file of the warehouse handling times, in the format of ETA_CONFIG_PATH
+	ETAConfigPath string
+	// TransitMatrixPath is the CSV file of the transit days between states, in the format of
+	// TRANSIT_MATRIX_PATH
+	TransitMatrixPath string
+	// HolidayCalendarPath is the file of the holidays skipped by the delivery estimates, in the
+	// format of HOLIDAY_CALENDAR_PATH
+	HolidayCalendarPath string
+	// Now, when set, is the time the quotes are calculated at, for reproducible estimates
+	Now time.Time
+}
+
+// Engine calculates shipping quotes. It is safe for concurrent use
+type Engine struct {
+	service *service.ShippingService
+}
+
+// New creates an engine, loading the configuration files of cfg
+func New(ctx context.Context, cfg Config) (*Engine, error) {
+	pricingConfig := pricing.DefaultConfig()
+	if cfg.PricingConfigPath != "" {
+		var err error
+		if pricingConfig, err = pricing.LoadConfig(cfg.PricingConfigPath); err != nil {
+			return nil, err
+		}
+	}
+
+	etaConfig := eta.Config{}
+	if cfg.ETAConfigPath != "" {
+		var err error
+		if etaConfig, err = eta.LoadConfig(cfg.ETAConfigPath); err != nil {
+			return nil, err
+		}
+	}
+
+	var transitMatrix *eta.TransitMatrix
+	if cfg.TransitMatrixPath != "" {
+		var err error
+		if transitMatrix, err = eta.LoadTransitMatrix(cfg.TransitMatrixPath); err != nil {
+			return nil, err
+		}
+	}
+
+	calendar := holiday.NewCalendar()
+	if cfg.HolidayCalendarPath != "" {
+		calendar = holiday.NewCalendar(holiday.FileSource{Path: cfg.HolidayCalendarPath})
+		if err := calendar.Reload(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	clock := determinism.Config{Now: cfg.Now}.Clock()
+	return &Engine{
+		service: service.NewShippingServiceWithConfig(service.Config{
+			Estimator: eta.NewEstimatorWithClock(etaConfig, calendar, clock).WithTransitMatrix(transitMatrix).WithTimezones(timezone.NewResolver()),
+			Pricing:   &pricingConfig,
+			Scheduler: schedule.NewSchedulerWithClock(schedule.DefaultConfig(), clock),
+		}),
+	}, nil
+}
+
+// Calculate quotes req. Errors are returned as they would be by POST /calculate, e.g. a
+// *ValidationError for an invalid request
+func (e *Engine) Calculate(ctx context.Context, req *Request) (*Quote, error) {
+	// The service logs to the logger of the context, or to the global zap logger of the program
+	ctx = logger.NewContext(ctx, zap.NewNop())
+	response, err := e.service.CalculateShipping(ctx, mapper.RequestFromV1(req))
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ResponseToV1(response), nil
+}
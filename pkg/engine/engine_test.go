@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func testRequest() *Request {
+	return &Request{
+		OriginZipcode:      "12345678",
+		DestinationZipcode: "12345678",
+		Weight:             1,
+		Dimensions:         Dimensions{Length: 10, Width: 10, Height: 10},
+	}
+}
+
+func TestEngine_Calculate(t *testing.T) {
+	// Arrange
+	e, err := New(context.Background(), Config{})
+	assert.NoError(t, err)
+
+	// Act
+	quote, err := e.Calculate(context.Background(), testRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.InDelta(t, 1250.0, quote.ShippingCost, 0.001)
+	assert.Equal(t, "2 dias", quote.EstimatedDeliveryTime)
+	assert.Len(t, quote.ShippingOptions, 2)
+}
+
+func TestEngine_Calculate_Now(t *testing.T) {
+	// Arrange
+	cfg := Config{Now: time.Date(2025, 3, 7, 13, 0, 0, 0, time.UTC)}
+	first, err := New(context.Background(), cfg)
+	assert.NoError(t, err)
+	second, err := New(context.Background(), cfg)
+	assert.NoError(t, err)
+
+	// Act
+	firstQuote, firstErr := first.Calculate(context.Background(), testRequest())
+	secondQuote, secondErr := second.Calculate(context.Background(), testRequest())
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	assert.Equal(t, firstQuote, secondQuote)
+}
+
+func TestEngine_Calculate_ValidationError(t *testing.T) {
+	// Arrange
+	e, err := New(context.Background(), Config{})
+	assert.NoError(t, err)
+	req := testRequest()
+	req.OriginZipcode = "123"
+
+	// Act
+	quote, err := e.Calculate(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, quote)
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Equal(t, "origin_zipcode", validationErr.Field)
+	}
+}
+
+func TestEngine_Calculate_DoesNotLog(t *testing.T) {
+	// Arrange
+	core, logs := observer.New(zap.DebugLevel)
+	defer zap.ReplaceGlobals(zap.New(core))()
+	e, err := New(context.Background(), Config{})
+	assert.NoError(t, err)
+	req := testRequest()
+	req.Weight = -1
+
+	// Act
+	_, okErr := e.Calculate(context.Background(), testRequest())
+	_, invalidErr := e.Calculate(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, okErr)
+	assert.Error(t, invalidErr)
+	assert.Zero(t, logs.Len())
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		contains string
+	}{
+		{"missing pricing configuration", Config{PricingConfigPath: "/nonexistent/pricing.json"}, "pricing config"},
+		{"missing ETA configuration", Config{ETAConfigPath: "/nonexistent/eta.json"}, "eta config"},
+		{"missing transit matrix", Config{TransitMatrixPath: "/nonexistent/transit.csv"}, "transit matrix"},
+		{"missing holiday calendar", Config{HolidayCalendarPath: "/nonexistent/holidays.json"}, "holidays.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			e, err := New(context.Background(), tt.cfg)
+
+			// Assert
+			assert.Nil(t, e)
+			assert.ErrorContains(t, err, tt.contains)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// Package quotetoken signs and verifies quote tokens: HS256 JWTs embedding the quote ID, service,
+// price and expiration of a shipping option, so that services receiving a price from a client,
+// such as the order service, can check it was quoted without calling the shipping calculator.
+//
+//	signer, err := quotetoken.NewSigner([]quotetoken.Key{{ID: "2025-04", Secret: secret}})
+//	claims, err := signer.Verify(option.Token, time.Now())
+package quotetoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rbonfanti/shipping-calculator/internal/money"
+)
+
+// MinSecretSize is the minimum size of a key secret, the size of the HS256 hash
+const MinSecretSize = 32
+
+// Errors returned by Verify
+var (
+	// ErrMalformed is a token that is not a JWT signed with HS256
+	ErrMalformed = errors.New("malformed quote token")
+	// ErrUnknownKey is a token signed with a key that is not in the signer
+	ErrUnknownKey = errors.New("unknown quote token key")
+	// ErrInvalidSignature is a token whose signature does not match, e.g. because it was altered
+	ErrInvalidSignature = errors.New("invalid quote token signature")
+	// ErrExpired is a token of a quote that expired
+	ErrExpired = errors.New("quote token expired")
+)
+
+// PriceScale is the number of Price units in a minor currency unit, the precision of the prices
+// quoted by the shipping calculator
+const PriceScale = money.Scale
+
+// Price is an exact fixed-point price in 1/PriceScale of the minor unit (e.g. cents) of its
+// currency, so that R$ 15,50 is PriceFromMinor(1550)
+type Price int64
+
+// PriceFromMinor converts a number of minor units to a Price, rounding to the nearest 1/PriceScale
+// of a minor unit
+func PriceFromMinor(minor float64) Price {
+	return Price(money.FromMinor(minor))
+}
+
+// Minor returns the price in minor units
+func (p Price) Minor() float64 {
+	return money.Amount(p).Minor()
+}
+
+// String formats the price in minor units, e.g. "1437.5"
+func (p Price) String() string {
+	return money.Amount(p).String()
+}
+
+// Key is a named HMAC secret. The ID is sent in the kid header of the tokens, so that tokens
+// signed before a key rotation can still be verified
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// Claims are the quoted terms a token vouches for
+type Claims struct {
+	QuoteID string
+	// Tenant is the tenant the quote was priced for
+	Tenant   string
+	Service  string
+	Currency string
+	// Price is the price of Service in Currency, encoded in the token as minor units, e.g. 1550
+	// for R$ 15,50
+	Price    Price
+	IssuedAt time.Time
+	// ExpiresAt is when the quoted price stops being honored; zero when it does not expire
+	ExpiresAt time.Time
+}
+
+// header is the JOSE header of the tokens
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// payload is the JWT form of Claims, with the quote ID as subject and NumericDate times
+type payload struct {
+	Subject   string       `json:"sub"`
+	Tenant    string       `json:"tenant,omitempty"`
+	Service   string       `json:"service"`
+	Currency  string       `json:"currency"`
+	Price     money.Amount `json:"price"`
+	IssuedAt  int64        `json:"iat"`
+	ExpiresAt int64        `json:"exp,omitempty"`
+}
+
+// Signer signs tokens with its active key and verifies tokens signed with any of its keys
+type Signer struct {
+	activeID string
+	secrets  map[string][]byte
+}
+
+// NewSigner creates a signer with the keys. The first key is the active one, used to sign; the
+// remaining keys only verify, keeping the tokens signed before a rotation valid until they expire
+func NewSigner(keys []Key) (*Signer, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one quote token key is required")
+	}
+	secrets := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("quote token key id is required")
+		}
+		if len(key.Secret) < MinSecretSize {
+			return nil, fmt.Errorf("quote token key %q: secret must have at least %d bytes", key.ID, MinSecretSize)
+		}
+		if _, ok := secrets[key.ID]; ok {
+			return nil, fmt.Errorf("quote token key %q is repeated", key.ID)
+		}
+		secrets[key.ID] = key.Secret
+	}
+	return &Signer{activeID: keys[0].ID, secrets: secrets}, nil
+}
+
+// Sign returns the token of the claims, signed with the active key
+func (s *Signer) Sign(claims Claims) (string, error) {
+	encodedHeader, err := encodeSegment(header{Alg: "HS256", Typ: "JWT", Kid: s.activeID})
+	if err != nil {
+		return "", err
+	}
+	p := payload{
+		Subject:  claims.QuoteID,
+		Tenant:   claims.Tenant,
+		Service:  claims.Service,
+		Currency: claims.Currency,
+		Price:    money.Amount(claims.Price),
+		IssuedAt: claims.IssuedAt.Unix(),
+	}
+	if !claims.ExpiresAt.IsZero() {
+		p.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	encodedPayload, err := encodeSegment(p)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encodedHeader + "." + encodedPayload
+	return signingInput + "." + sign(s.secrets[s.activeID], signingInput), nil
+}
+
+// Verify checks the signature and expiration of a token at now and returns its claims
+func (s *Signer) Verify(token string, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrMalformed
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil || h.Alg != "HS256" {
+		return Claims{}, ErrMalformed
+	}
+	secret, ok := s.secrets[h.Kid]
+	if !ok {
+		return Claims{}, fmt.Errorf("%w: %q", ErrUnknownKey, h.Kid)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	expected, _ := base64.RawURLEncoding.DecodeString(sign(secret, parts[0]+"."+parts[1]))
+	if !hmac.Equal(signature, expected) {
+		return Claims{}, ErrInvalidSignature
+	}
+
+	var p payload
+	if err := decodeSegment(parts[1], &p); err != nil {
+		return Claims{}, ErrMalformed
+	}
+	claims := Claims{
+		QuoteID:  p.Subject,
+		Tenant:   p.Tenant,
+		Service:  p.Service,
+		Currency: p.Currency,
+		Price:    Price(p.Price),
+		IssuedAt: time.Unix(p.IssuedAt, 0).UTC(),
+	}
+	if p.ExpiresAt != 0 {
+		claims.ExpiresAt = time.Unix(p.ExpiresAt, 0).UTC()
+		if !now.Before(claims.ExpiresAt) {
+			return claims, ErrExpired
+		}
+	}
+	return claims, nil
+}
+
+// sign returns the base64url HMAC-SHA256 of the signing input
+func sign(secret []byte, signingInput string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func encodeSegment(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode quote token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
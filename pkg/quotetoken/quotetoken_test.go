@@ -0,0 +1,153 @@
+package quotetoken
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	tokenNow  = time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+	oldKey    = Key{ID: "2025-01", Secret: bytes.Repeat([]byte("a"), 32)}
+	activeKey = Key{ID: "2025-04", Secret: bytes.Repeat([]byte("b"), 32)}
+)
+
+func testClaims() Claims {
+	return Claims{
+		QuoteID:   "q1",
+		Tenant:    "acme",
+		Service:   "express",
+		Currency:  "BRL",
+		Price:     PriceFromMinor(1950),
+		IssuedAt:  tokenNow,
+		ExpiresAt: tokenNow.Add(30 * time.Minute),
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	// Arrange
+	signer, _ := NewSigner([]Key{activeKey, oldKey})
+
+	// Act
+	token, signErr := signer.Sign(testClaims())
+	claims, verifyErr := signer.Verify(token, tokenNow.Add(time.Minute))
+
+	// Assert
+	assert.NoError(t, signErr)
+	assert.NoError(t, verifyErr)
+	assert.Equal(t, testClaims(), claims)
+	assert.Len(t, strings.Split(token, "."), 3)
+}
+
+func TestSign_PriceInMinorUnits(t *testing.T) {
+	// Arrange
+	signer, _ := NewSigner([]Key{activeKey})
+	claims := testClaims()
+	claims.Price = PriceFromMinor(1437.5)
+
+	// Act
+	token, err := signer.Sign(claims)
+
+	// Assert
+	assert.NoError(t, err)
+	payload, _ := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[1])
+	assert.Contains(t, string(payload), `"price":1437.5`)
+	verified, _ := signer.Verify(token, tokenNow)
+	assert.Equal(t, PriceFromMinor(1437.5), verified.Price, "the price round-trips exactly")
+	assert.Equal(t, 1437.5, verified.Price.Minor())
+	assert.Equal(t, "1437.5", verified.Price.String())
+}
+
+func TestVerify_KeyRotation(t *testing.T) {
+	// Arrange
+	before, _ := NewSigner([]Key{oldKey})
+	after, _ := NewSigner([]Key{activeKey, oldKey})
+	retired, _ := NewSigner([]Key{activeKey})
+	token, _ := before.Sign(testClaims())
+
+	// Act
+	_, afterErr := after.Verify(token, tokenNow)
+	_, retiredErr := retired.Verify(token, tokenNow)
+
+	// Assert
+	assert.NoError(t, afterErr)
+	assert.ErrorIs(t, retiredErr, ErrUnknownKey)
+}
+
+func TestVerify_Errors(t *testing.T) {
+	signer, _ := NewSigner([]Key{activeKey})
+	token, _ := signer.Sign(testClaims())
+	parts := strings.Split(token, ".")
+	tampered, _ := NewSigner([]Key{{ID: activeKey.ID, Secret: bytes.Repeat([]byte("c"), 32)}})
+	forged, _ := tampered.Sign(testClaims())
+	cheaper := testClaims()
+	cheaper.Price = PriceFromMinor(1)
+	cheaperToken, _ := signer.Sign(cheaper)
+	withCheaperPayload := parts[0] + "." + strings.Split(cheaperToken, ".")[1] + "." + parts[2]
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT","kid":"2025-04"}`)) + "." + parts[1] + "."
+
+	tests := []struct {
+		name    string
+		token   string
+		now     time.Time
+		wantErr error
+	}{
+		{"altered price", withCheaperPayload, tokenNow, ErrInvalidSignature},
+		{"signed with another secret", forged, tokenNow, ErrInvalidSignature},
+		{"unsigned", unsigned, tokenNow, ErrMalformed},
+		{"not a jwt", "quote-q1", tokenNow, ErrMalformed},
+		{"expired", token, tokenNow.Add(30 * time.Minute), ErrExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := signer.Verify(tt.token, tt.now)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestVerify_WithoutExpiration(t *testing.T) {
+	// Arrange
+	signer, _ := NewSigner([]Key{activeKey})
+	claims := testClaims()
+	claims.ExpiresAt = time.Time{}
+	token, _ := signer.Sign(claims)
+
+	// Act
+	got, err := signer.Verify(token, tokenNow.AddDate(1, 0, 0))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, got.ExpiresAt.IsZero())
+}
+
+func TestNewSigner_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []Key
+	}{
+		{"no keys", nil},
+		{"missing id", []Key{{Secret: activeKey.Secret}}},
+		{"short secret", []Key{{ID: "k1", Secret: []byte("short")}}},
+		{"repeated id", []Key{activeKey, activeKey}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			signer, err := NewSigner(tt.keys)
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, signer)
+		})
+	}
+}
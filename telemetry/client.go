@@ -0,0 +1,25 @@
+package telemetry
+
+import "context"
+
+// Client labels of requests whose client is not identified or not one of the known clients
+const (
+	UnknownClient = "unknown"
+	OtherClient   = "other"
+)
+
+type clientIDKey struct{}
+
+// ContextWithClientID returns a context whose metrics are attributed to clientID
+func ContextWithClientID(ctx context.Context, clientID string) context.Context {
+	return context.WithValue(ctx, clientIDKey{}, clientID)
+}
+
+// ClientIDFromContext returns the client the metrics of ctx are attributed to, UnknownClient when
+// it was not identified
+func ClientIDFromContext(ctx context.Context) string {
+	if clientID, ok := ctx.Value(clientIDKey{}).(string); ok && clientID != "" {
+		return clientID
+	}
+	return UnknownClient
+}
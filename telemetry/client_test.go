@@ -0,0 +1,27 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIDFromContext(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"not identified", context.Background(), UnknownClient},
+		{"empty", ContextWithClientID(context.Background(), ""), UnknownClient},
+		{"identified", ContextWithClientID(context.Background(), "loja-1"), "loja-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.want, ClientIDFromContext(tt.ctx))
+		})
+	}
+}
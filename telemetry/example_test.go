@@ -9,7 +9,13 @@ import (
 	"time"
 )
 
-func ExampleRecordLatencyOperationA() {
+func ExampleMetrics_RecordLatencyOperationA() {
+	telemetry, err := New(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer telemetry.Shutdown(context.Background())
+
 	now := time.Now()
 	// operation to measure
 	func() {
@@ -18,41 +24,58 @@ func ExampleRecordLatencyOperationA() {
 
 	elapsed := time.Since(now).Milliseconds()
 
-	RecordLatencyOperationA(context.Background(), elapsed, "local")
+	telemetry.Metrics.RecordLatencyOperationA(context.Background(), elapsed, "local")
 }
 
-func ExampleRecordMemoryHeapServer() {
+func ExampleMetrics_RecordMemoryHeapServer() {
+	telemetry, err := New(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer telemetry.Shutdown(context.Background())
+
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
 
-	RecordMemoryHeapServer(context.Background(), int64(m.HeapAlloc/1024/1024))
+	telemetry.Metrics.RecordMemoryHeapServer(context.Background(), int64(m.HeapAlloc/1024/1024))
 }
 
-func ExampleRecordMemoryNoHeapServer() {
+func ExampleMetrics_RecordMemoryNoHeapServer() {
+	telemetry, err := New(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer telemetry.Shutdown(context.Background())
+
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
 
-	RecordMemoryNoHeapServer(context.Background(), int64(m.StackInuse/1024/1024))
+	telemetry.Metrics.RecordMemoryNoHeapServer(context.Background(), int64(m.StackInuse/1024/1024))
 }
 
-func ExampleIncrementHttpRequestHandled() {
+func ExampleMetrics_IncrementHttpRequestHandled() {
+	telemetry, err := New(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer telemetry.Shutdown(context.Background())
+
 	// custom server router
 	router := http.NewServeMux()
 
 	router.HandleFunc("/GET test", func(w http.ResponseWriter, r *http.Request) {
-		IncrementHttpRequestHandled(r.Context(), "GET", http.StatusOK)
+		telemetry.Metrics.IncrementHttpRequestHandled(r.Context(), "GET", http.StatusOK)
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("ok"))
 	})
 
 	router.HandleFunc("/POST test", func(w http.ResponseWriter, r *http.Request) {
-		IncrementHttpRequestHandled(r.Context(), "POST", http.StatusCreated)
+		telemetry.Metrics.IncrementHttpRequestHandled(r.Context(), "POST", http.StatusCreated)
 		w.WriteHeader(http.StatusCreated)
 		w.Write([]byte("created"))
 	})
 
-	err := http.ListenAndServe(":8080", router)
-	if err != nil {
+	if err := http.ListenAndServe(":8080", router); err != nil {
 		log.Fatal(err)
 	}
 }
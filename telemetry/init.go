@@ -2,30 +2,51 @@ package telemetry
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"sync"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// InitOpenTelemetry initializes OpenTelemetry with basic configuration
+// Telemetry holds the tracer and meter providers of the service and the instruments created with
+// them. It is created once at startup and its Tracer and Metrics are injected into the components
+// that record telemetry; the global OpenTelemetry providers are not used
+type Telemetry struct {
+	TracerProvider *sdktrace.TracerProvider
+	MeterProvider  *sdkmetric.MeterProvider
+	// Tracer starts the spans of the service
+	Tracer trace.Tracer
+	// Metrics records the metrics of the service
+	Metrics *Metrics
+
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+// New initializes OpenTelemetry with basic configuration
 // For production, configure OTLP exporters via environment variables:
 // - OTEL_EXPORTER_OTLP_ENDPOINT: OTLP endpoint URL
 // - OTEL_EXPORTER_OTLP_METRICS_ENDPOINT: Metrics endpoint URL (optional)
 // - OTEL_EXPORTER_OTLP_TRACES_ENDPOINT: Traces endpoint URL (optional)
 // - OTEL_SERVICE_NAME: Service name (defaults to APPLICATION_NAME or "shipping-calculator")
-func InitOpenTelemetry(ctx context.Context) (func() error, error) {
+func New(ctx context.Context) (*Telemetry, error) {
 	appName := os.Getenv("APPLICATION_NAME")
 	if appName == "" {
 		appName = "shipping-calculator"
 	}
 
-	// Set global propagator for distributed tracing
+	// Set global propagator for distributed tracing. It only selects the trace context headers,
+	// injected by the HTTP clients and extracted by the server and the worker
 	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
 		propagation.TraceContext{},
 		propagation.Baggage{},
@@ -38,7 +59,7 @@ func InitOpenTelemetry(ctx context.Context) (func() error, error) {
 		),
 	)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to create telemetry resource: %w", err)
 	}
 
 	// Create TracerProvider with default SDK (no exporter configured)
@@ -47,9 +68,14 @@ func InitOpenTelemetry(ctx context.Context) (func() error, error) {
 		sdktrace.WithResource(res),
 		sdktrace.WithSampler(sdktrace.AlwaysSample()),
 	)
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithResource(res))
 
-	// Set global TracerProvider
-	otel.SetTracerProvider(tp)
+	metrics, err := NewMetrics(mp.Meter(appName))
+	if err != nil {
+		_ = tp.Shutdown(ctx)
+		_ = mp.Shutdown(ctx)
+		return nil, err
+	}
 
 	// Log OpenTelemetry configuration
 	otlpEndpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
@@ -60,12 +86,23 @@ func InitOpenTelemetry(ctx context.Context) (func() error, error) {
 		log.Printf("OpenTelemetry initialized with OTLP endpoint: %s", otlpEndpoint)
 	}
 
-	// Return shutdown function to shutdown the TracerProvider
-	return func() error {
-		return tp.Shutdown(ctx)
+	return &Telemetry{
+		TracerProvider: tp,
+		MeterProvider:  mp,
+		Tracer:         tp.Tracer(appName),
+		Metrics:        metrics,
 	}, nil
 }
 
+// Shutdown flushes and shuts down the tracer and meter providers; later calls return the result of
+// the first
+func (t *Telemetry) Shutdown(ctx context.Context) error {
+	t.shutdownOnce.Do(func() {
+		t.shutdownErr = errors.Join(t.TracerProvider.Shutdown(ctx), t.MeterProvider.Shutdown(ctx))
+	})
+	return t.shutdownErr
+}
+
 // InjectTraceContext injects the trace context from the given context into the HTTP request headers.
 // This should be called before making outbound HTTP requests to propagate the trace_id to downstream services.
 //
@@ -8,7 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestInitOpenTelemetry_DefaultAppName(t *testing.T) {
+func TestNew_DefaultAppName(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
 	originalAppName := os.Getenv("APPLICATION_NAME")
@@ -16,16 +16,17 @@ func TestInitOpenTelemetry_DefaultAppName(t *testing.T) {
 	defer restoreEnvVar("APPLICATION_NAME", originalAppName)
 
 	// Act
-	shutdown, err := InitOpenTelemetry(ctx)
+	telemetry, err := New(ctx)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.NotNil(t, shutdown)
-	err = shutdown()
+	assert.NotNil(t, telemetry.Tracer)
+	assert.NotNil(t, telemetry.Metrics)
+	err = telemetry.Shutdown(ctx)
 	assert.NoError(t, err)
 }
 
-func TestInitOpenTelemetry_CustomAppName(t *testing.T) {
+func TestNew_CustomAppName(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
 	originalAppName := os.Getenv("APPLICATION_NAME")
@@ -33,16 +34,17 @@ func TestInitOpenTelemetry_CustomAppName(t *testing.T) {
 	defer restoreEnvVar("APPLICATION_NAME", originalAppName)
 
 	// Act
-	shutdown, err := InitOpenTelemetry(ctx)
+	telemetry, err := New(ctx)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.NotNil(t, shutdown)
-	err = shutdown()
+	assert.NotNil(t, telemetry.Tracer)
+	assert.NotNil(t, telemetry.Metrics)
+	err = telemetry.Shutdown(ctx)
 	assert.NoError(t, err)
 }
 
-func TestInitOpenTelemetry_WithOTLPEndpoint(t *testing.T) {
+func TestNew_WithOTLPEndpoint(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
 	originalEndpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
@@ -50,46 +52,49 @@ func TestInitOpenTelemetry_WithOTLPEndpoint(t *testing.T) {
 	defer restoreEnvVar("OTEL_EXPORTER_OTLP_ENDPOINT", originalEndpoint)
 
 	// Act
-	shutdown, err := InitOpenTelemetry(ctx)
+	telemetry, err := New(ctx)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.NotNil(t, shutdown)
-	err = shutdown()
+	assert.NotNil(t, telemetry.Tracer)
+	assert.NotNil(t, telemetry.Metrics)
+	err = telemetry.Shutdown(ctx)
 	assert.NoError(t, err)
 }
 
-func TestInitOpenTelemetry_ShutdownFunction(t *testing.T) {
+func TestNew_ShutdownFunction(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
 
 	// Act
-	shutdown, err := InitOpenTelemetry(ctx)
+	telemetry, err := New(ctx)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.NotNil(t, shutdown)
+	assert.NotNil(t, telemetry.Tracer)
+	assert.NotNil(t, telemetry.Metrics)
 
-	// Test shutdown function
-	err = shutdown()
+	// Test Shutdown
+	err = telemetry.Shutdown(ctx)
 	assert.NoError(t, err)
 
 	// Test multiple shutdown calls
-	err = shutdown()
+	err = telemetry.Shutdown(ctx)
 	assert.NoError(t, err)
 }
 
-func TestInitOpenTelemetry_ContextCancellation(t *testing.T) {
+func TestNew_ContextCancellation(t *testing.T) {
 	// Arrange
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 
 	// Act
-	shutdown, err := InitOpenTelemetry(ctx)
+	telemetry, err := New(ctx)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.NotNil(t, shutdown)
-	err = shutdown()
+	assert.NotNil(t, telemetry.Tracer)
+	assert.NotNil(t, telemetry.Metrics)
+	err = telemetry.Shutdown(ctx)
 	assert.NoError(t, err)
 }
@@ -2,28 +2,20 @@ package telemetry
 
 import (
 	"context"
-	"log"
-	"os"
-	"sync"
+	"fmt"
 
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 )
 
-var (
-	once     sync.Once
-	instance *instruments
-)
-
 // Package telemetry provides helper functions to record service metrics
 // using the OpenTelemetry Go SDK. The following code is an example of how a healthy metric helper should be implemented
 // Users may feel free to erase or change it to fit their needs
 //
 // Quick reference
 //
-//  1. Instruments should be created only once. Metric names should be attached to instruments and appear in this file exclusively. In the example:
+//  1. Instruments are created once, by NewMetrics, and the Metrics are injected into the components that record them. Metric names should be attached to instruments and appear in this file exclusively. In the example:
 //     - latencyOperationA   (Int64Histogram): internal operation latency in ms
 //     - memoryServer      (Int64Gauge): memory usage of the server
 //     - httpRequestHandled (Int64Counter)  : total HTTP requests processed
@@ -31,9 +23,9 @@ var (
 //  2. Usage example:
 //     start := time.Now()
 //     // ... business logic ...
-//     telemetry.RecordLatencyOperationA(ctx, time.Since(start).Milliseconds(), "generate_invoice")
+//     metrics.RecordLatencyOperationA(ctx, time.Since(start).Milliseconds(), "generate_invoice")
 //
-//     telemetry.IncrementHttpRequestHandled(ctx, r.Method, http.StatusOK)
+//     metrics.IncrementHttpRequestHandled(ctx, r.Method, http.StatusOK)
 //
 //  3. Conventions:
 //     • Instrument names are snake_case and describe *what* is measured.
@@ -41,15 +33,19 @@ var (
 //     • Always propagate the incoming context when recording.
 //
 // To extend:
-//   - Add a new instrument to the instruments struct.
-//   - Instantiate it inside getInstance() with its corresponding metric name and description.
-//   - Expose a helper function that records or adds values following the patterns in the examples.
+//   - Add a new instrument to the Metrics struct.
+//   - Instantiate it inside NewMetrics() with its corresponding metric name and description.
+//   - Expose a helper method that records or adds values following the patterns in the examples,
+//     returning early on a nil receiver.
 //   - Metric attributes should be passed as primitive arguments and then converted to OTel attributes inside the helper function (as shown in the examples)
 //
 // More info:
 //
 // OTel: https://opentelemetry.io/docs/specs/semconv/general/metrics
-type instruments struct {
+//
+// A nil *Metrics records nothing, so the packages can be used as a library and tested without
+// telemetry.
+type Metrics struct {
 	latencyOperationA                 metric.Int64Histogram
 	memoryServer                      metric.Int64Gauge
 	httpRequestHandled                metric.Int64Counter
@@ -57,115 +53,414 @@ type instruments struct {
 	shipmentCalculateTime             metric.Int64Histogram
 	shipmentCalculateCostDistribution metric.Float64Histogram
 	shipmentCalculateError            metric.Int64Counter
+	shipmentCalculatePanic            metric.Int64Counter
+	pricingExperimentAssignment       metric.Int64Counter
+	pricingExperimentCost             metric.Float64Histogram
+	pricingShadowComparison           metric.Int64Counter
+	pricingShadowDifference           metric.Float64Histogram
+	pricingReload                     metric.Int64Counter
+	overload                          metric.Int64Counter
+	bulkBatchSize                     metric.Int64Histogram
+	bulkItemTime                      metric.Int64Histogram
+	quoteStoreOperation               metric.Int64Counter
+	quoteStoreTime                    metric.Int64Histogram
+	dependencyUp                      metric.Int64Gauge
+	dependencyProbeTime               metric.Int64Histogram
+	runtimeGoroutines                 metric.Int64Gauge
+	runtimeGCPause                    metric.Int64Histogram
+	slaDelivery                       metric.Int64Counter
+	slaDelay                          metric.Int64Histogram
+}
+
+// NewMetrics creates the instruments of the service with meter
+func NewMetrics(meter metric.Meter) (*Metrics, error) {
+	metricPrefix := "shipping.calculate"
+
+	latencyOperationA, err := meter.Int64Histogram("latency_operation",
+		metric.WithDescription("The latency of the processed operation"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument latency_operation: %w", err)
+	}
+
+	memoryServer, err := meter.Int64Gauge("memory_server",
+		metric.WithDescription("The current memory server used"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument memory_server: %w", err)
+	}
+
+	httpRequestHandled, err := meter.Int64Counter("http_requests_total",
+		metric.WithDescription("The total number of HTTP requests"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument http_requests_total: %w", err)
+	}
+
+	shipmentCalculate, err := meter.Int64Counter(metricPrefix,
+		metric.WithDescription("Contador de cálculos solicitados"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix, err)
+	}
+
+	shipmentCalculateTime, err := meter.Int64Histogram(metricPrefix+".time",
+		metric.WithDescription("Tempo de resposta"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".time", err)
+	}
+
+	shipmentCalculateCostDistribution, err := meter.Float64Histogram(metricPrefix+".cost.distribution",
+		metric.WithDescription("Distribuição dos custos calculados"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".cost.distribution", err)
+	}
+
+	shipmentCalculateError, err := meter.Int64Counter(metricPrefix+".error",
+		metric.WithDescription("Contador de erros"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".error", err)
+	}
+
+	shipmentCalculatePanic, err := meter.Int64Counter(metricPrefix+".panic",
+		metric.WithDescription("Contador de pânicos recuperados"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".panic", err)
+	}
+
+	pricingExperimentAssignment, err := meter.Int64Counter(metricPrefix+".experiment",
+		metric.WithDescription("Contador de cotações por braço do experimento de preço"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".experiment", err)
+	}
+
+	pricingExperimentCost, err := meter.Float64Histogram(metricPrefix+".experiment.cost",
+		metric.WithDescription("Distribuição dos custos por braço do experimento de preço"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".experiment.cost", err)
+	}
+
+	pricingShadowComparison, err := meter.Int64Counter(metricPrefix+".shadow",
+		metric.WithDescription("Contador de comparações com o cálculo sombra por resultado"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".shadow", err)
+	}
+
+	pricingShadowDifference, err := meter.Float64Histogram(metricPrefix+".shadow.difference",
+		metric.WithDescription("Diferença relativa entre o custo sombra e o custo principal"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".shadow.difference", err)
+	}
+
+	pricingReload, err := meter.Int64Counter(metricPrefix+".pricing.reload",
+		metric.WithDescription("Contador de recargas das tarifas por origem e resultado"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".pricing.reload", err)
+	}
+
+	overload, err := meter.Int64Counter(metricPrefix+".overload",
+		metric.WithDescription("Contador de requisições degradadas ou rejeitadas por sobrecarga"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".overload", err)
+	}
+
+	bulkBatchSize, err := meter.Int64Histogram(metricPrefix+".bulk.size",
+		metric.WithDescription("Quantidade de linhas por lote"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".bulk.size", err)
+	}
+
+	bulkItemTime, err := meter.Int64Histogram(metricPrefix+".bulk.item.time",
+		metric.WithDescription("Tempo de cálculo de cada linha do lote"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".bulk.item.time", err)
+	}
+
+	quoteStoreOperation, err := meter.Int64Counter(metricPrefix+".store.operation",
+		metric.WithDescription("Contador de operações no armazenamento de cotações por resultado"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".store.operation", err)
+	}
+
+	quoteStoreTime, err := meter.Int64Histogram(metricPrefix+".store.time",
+		metric.WithDescription("Tempo das operações no armazenamento de cotações"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".store.time", err)
+	}
+
+	dependencyUp, err := meter.Int64Gauge(metricPrefix+".dependency.up",
+		metric.WithDescription("Disponibilidade das dependências (1 disponível, 0 indisponível)"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".dependency.up", err)
+	}
+
+	dependencyProbeTime, err := meter.Int64Histogram(metricPrefix+".dependency.probe.time",
+		metric.WithDescription("Tempo de resposta das verificações de dependências"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".dependency.probe.time", err)
+	}
+
+	runtimeGoroutines, err := meter.Int64Gauge(metricPrefix+".runtime.goroutines",
+		metric.WithDescription("Número de goroutines em execução"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".runtime.goroutines", err)
+	}
+
+	runtimeGCPause, err := meter.Int64Histogram(metricPrefix+".runtime.gc.pause",
+		metric.WithDescription("Duração das pausas do coletor de lixo, em microssegundos"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".runtime.gc.pause", err)
+	}
+
+	slaDelivery, err := meter.Int64Counter(metricPrefix+".sla.delivery",
+		metric.WithDescription("Contador de envios entregues por transportadora, rota e cumprimento do prazo prometido"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".sla.delivery", err)
+	}
+
+	slaDelay, err := meter.Int64Histogram(metricPrefix+".sla.delay",
+		metric.WithDescription("Atraso das entregas em relação ao prazo prometido, em dias (negativo quando adiantadas)"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instrument %s: %w", metricPrefix+".sla.delay", err)
+	}
+
+	return &Metrics{
+		latencyOperationA:                 latencyOperationA,
+		memoryServer:                      memoryServer,
+		httpRequestHandled:                httpRequestHandled,
+		shipmentCalculate:                 shipmentCalculate,
+		shipmentCalculateTime:             shipmentCalculateTime,
+		shipmentCalculateCostDistribution: shipmentCalculateCostDistribution,
+		shipmentCalculateError:            shipmentCalculateError,
+		shipmentCalculatePanic:            shipmentCalculatePanic,
+		pricingExperimentAssignment:       pricingExperimentAssignment,
+		pricingExperimentCost:             pricingExperimentCost,
+		pricingShadowComparison:           pricingShadowComparison,
+		pricingShadowDifference:           pricingShadowDifference,
+		pricingReload:                     pricingReload,
+		overload:                          overload,
+		bulkBatchSize:                     bulkBatchSize,
+		bulkItemTime:                      bulkItemTime,
+		quoteStoreOperation:               quoteStoreOperation,
+		quoteStoreTime:                    quoteStoreTime,
+		dependencyUp:                      dependencyUp,
+		dependencyProbeTime:               dependencyProbeTime,
+		runtimeGoroutines:                 runtimeGoroutines,
+		runtimeGCPause:                    runtimeGCPause,
+		slaDelivery:                       slaDelivery,
+		slaDelay:                          slaDelay,
+	}, nil
 }
 
-func getInstance() *instruments {
-	once.Do(func() {
-		appName := os.Getenv("APPLICATION_NAME")
-		if appName == "" {
-			appName = "shipping-calculator"
-		}
-
-		metricPrefix := "shipping.calculate"
-
-		meter := otel.Meter(appName)
-
-		latencyOperationA, err := meter.Int64Histogram("latency_operation",
-			metric.WithDescription("The latency of the processed operation"))
-		if err != nil {
-			log.Fatalf("Failed to create instrument histogram: %v", err)
-		}
-
-		memoryServer, err := meter.Int64Gauge("memory_server",
-			metric.WithDescription("The current memory server used"))
-		if err != nil {
-			log.Fatalf("Failed to create instrument gauge: %v", err)
-		}
-
-		httpRequestHandled, err := meter.Int64Counter("http_requests_total",
-			metric.WithDescription("The total number of HTTP requests"))
-		if err != nil {
-			log.Fatalf("Failed to create instrument counter: %v", err)
-		}
-
-		shipmentCalculate, err := meter.Int64Counter(metricPrefix,
-			metric.WithDescription("Contador de cálculos solicitados"))
-		if err != nil {
-			log.Fatalf("Failed to create instrument counter: %v", err)
-		}
-
-		shipmentCalculateTime, err := meter.Int64Histogram(metricPrefix+".time",
-			metric.WithDescription("Tempo de resposta"))
-		if err != nil {
-			log.Fatalf("Failed to create instrument histogram: %v", err)
-		}
-
-		shipmentCalculateCostDistribution, err := meter.Float64Histogram(metricPrefix+".cost.distribution",
-			metric.WithDescription("Distribuição dos custos calculados"))
-		if err != nil {
-			log.Fatalf("Failed to create instrument histogram: %v", err)
-		}
-
-		shipmentCalculateError, err := meter.Int64Counter(metricPrefix+".error",
-			metric.WithDescription("Contador de erros"))
-		if err != nil {
-			log.Fatalf("Failed to create instrument counter: %v", err)
-		}
-
-		instance = &instruments{
-			latencyOperationA:                 latencyOperationA,
-			memoryServer:                      memoryServer,
-			httpRequestHandled:                httpRequestHandled,
-			shipmentCalculate:                 shipmentCalculate,
-			shipmentCalculateTime:             shipmentCalculateTime,
-			shipmentCalculateCostDistribution: shipmentCalculateCostDistribution,
-			shipmentCalculateError:            shipmentCalculateError,
-		}
-	})
-
-	return instance
-}
-
-func RecordLatencyOperationA(ctx context.Context, latency int64, resource string) {
-	getInstance().latencyOperationA.Record(ctx, latency,
+func (m *Metrics) RecordLatencyOperationA(ctx context.Context, latency int64, resource string) {
+	if m == nil {
+		return
+	}
+	m.latencyOperationA.Record(ctx, latency,
 		metric.WithAttributes(attribute.String("resource", resource)))
 }
 
-func RecordMemoryHeapServer(ctx context.Context, amount int64) {
-	getInstance().memoryServer.Record(ctx, amount, metric.WithAttributes(
+func (m *Metrics) RecordMemoryHeapServer(ctx context.Context, amount int64) {
+	if m == nil {
+		return
+	}
+	m.memoryServer.Record(ctx, amount, metric.WithAttributes(
 		semconv.TypeHeap,
 		semconv.TelemetrySDKLanguageGo))
 }
 
-func RecordMemoryNoHeapServer(ctx context.Context, amount int64) {
-	getInstance().memoryServer.Record(ctx, amount, metric.WithAttributes(
+func (m *Metrics) RecordMemoryNoHeapServer(ctx context.Context, amount int64) {
+	if m == nil {
+		return
+	}
+	m.memoryServer.Record(ctx, amount, metric.WithAttributes(
 		semconv.TypeNonHeap,
 		semconv.TelemetrySDKLanguageGo))
 }
 
-func IncrementHttpRequestHandled(ctx context.Context, httpMethod string, status int) {
-	getInstance().httpRequestHandled.Add(ctx, 1, metric.WithAttributes(
+func (m *Metrics) IncrementHttpRequestHandled(ctx context.Context, httpMethod string, status int) {
+	if m == nil {
+		return
+	}
+	m.httpRequestHandled.Add(ctx, 1, metric.WithAttributes(
 		attribute.String("http.resource", "request"),
 		semconv.HTTPMethod(httpMethod),
 		semconv.HTTPStatusCodeKey.Int(status)))
 }
 
+// shipmentAttributes slices the shipment calculation metrics by service level, destination
+// region, client, tenant and result
+func shipmentAttributes(serviceLevel, destinationRegion, clientID, tenantID, result string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("shipment.service_level", serviceLevel),
+		attribute.String("shipment.destination_region", destinationRegion),
+		attribute.String("client.id", clientID),
+		attribute.String("tenant.id", tenantID),
+		attribute.String("shipment.result", result),
+	}
+}
+
 // IncrementShipmentCalculate increments the shipment calculation counter
-func IncrementShipmentCalculate(ctx context.Context) {
-	getInstance().shipmentCalculate.Add(ctx, 1)
+func (m *Metrics) IncrementShipmentCalculate(ctx context.Context, serviceLevel, destinationRegion, clientID, tenantID, result string) {
+	if m == nil {
+		return
+	}
+	m.shipmentCalculate.Add(ctx, 1, metric.WithAttributes(shipmentAttributes(serviceLevel, destinationRegion, clientID, tenantID, result)...))
 }
 
 // RecordShipmentCalculateTime records the time taken to calculate shipment
-func RecordShipmentCalculateTime(ctx context.Context, timeMs int64) {
-	getInstance().shipmentCalculateTime.Record(ctx, timeMs)
+func (m *Metrics) RecordShipmentCalculateTime(ctx context.Context, timeMs int64, serviceLevel, destinationRegion, clientID, tenantID, result string) {
+	if m == nil {
+		return
+	}
+	m.shipmentCalculateTime.Record(ctx, timeMs, metric.WithAttributes(shipmentAttributes(serviceLevel, destinationRegion, clientID, tenantID, result)...))
 }
 
 // RecordShipmentCalculateCostDistribution records the shipping cost distribution
-func RecordShipmentCalculateCostDistribution(ctx context.Context, cost float64) {
-	getInstance().shipmentCalculateCostDistribution.Record(ctx, cost)
+func (m *Metrics) RecordShipmentCalculateCostDistribution(ctx context.Context, cost float64, serviceLevel, destinationRegion, clientID, tenantID, result string) {
+	if m == nil {
+		return
+	}
+	m.shipmentCalculateCostDistribution.Record(ctx, cost, metric.WithAttributes(shipmentAttributes(serviceLevel, destinationRegion, clientID, tenantID, result)...))
+}
+
+// IncrementShipmentCalculateError increments the shipment calculation error counter, also sliced
+// by error category and, for validation errors, by the request field that failed
+func (m *Metrics) IncrementShipmentCalculateError(ctx context.Context, serviceLevel, destinationRegion, clientID, tenantID, result, category, field string) {
+	if m == nil {
+		return
+	}
+	attrs := append(shipmentAttributes(serviceLevel, destinationRegion, clientID, tenantID, result), attribute.String("error.category", category))
+	if field != "" {
+		attrs = append(attrs, attribute.String("error.field", field))
+	}
+	m.shipmentCalculateError.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+// IncrementShipmentCalculatePanic increments the recovered panic counter for the given route
+func (m *Metrics) IncrementShipmentCalculatePanic(ctx context.Context, httpMethod, route string) {
+	if m == nil {
+		return
+	}
+	m.shipmentCalculatePanic.Add(ctx, 1, metric.WithAttributes(
+		semconv.HTTPMethod(httpMethod),
+		semconv.HTTPRoute(route)))
+}
+
+// RecordPricingExperimentQuote counts a quote priced by a pricing experiment arm and records its cost
+func (m *Metrics) RecordPricingExperimentQuote(ctx context.Context, experiment, arm string, cost float64) {
+	if m == nil {
+		return
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("experiment.name", experiment),
+		attribute.String("experiment.arm", arm))
+	m.pricingExperimentAssignment.Add(ctx, 1, attrs)
+	m.pricingExperimentCost.Record(ctx, cost, attrs)
+}
+
+// IncrementPricingShadowComparison counts a shadow pricing comparison by outcome
+func (m *Metrics) IncrementPricingShadowComparison(ctx context.Context, outcome string) {
+	if m == nil {
+		return
+	}
+	m.pricingShadowComparison.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("shadow.outcome", outcome)))
+}
+
+// RecordPricingShadowDifference records the relative difference between the shadow and primary costs
+func (m *Metrics) RecordPricingShadowDifference(ctx context.Context, relativeDifference float64) {
+	if m == nil {
+		return
+	}
+	m.pricingShadowDifference.Record(ctx, relativeDifference)
+}
+
+// IncrementPricingReload counts a pricing configuration reload by source and outcome
+func (m *Metrics) IncrementPricingReload(ctx context.Context, source, outcome string) {
+	if m == nil {
+		return
+	}
+	m.pricingReload.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("reload.source", source),
+		attribute.String("reload.outcome", outcome)))
+}
+
+// IncrementOverload counts a request degraded or shed by the overload protection, by route and outcome
+func (m *Metrics) IncrementOverload(ctx context.Context, route, outcome string) {
+	if m == nil {
+		return
+	}
+	m.overload.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("http.route", route),
+		attribute.String("overload.outcome", outcome)))
+}
+
+// RecordBulkBatchSize records the number of rows of a bulk run
+func (m *Metrics) RecordBulkBatchSize(ctx context.Context, rows int64) {
+	if m == nil {
+		return
+	}
+	m.bulkBatchSize.Record(ctx, rows)
+}
+
+// RecordBulkItemTime records the time taken to quote a row of a bulk run
+func (m *Metrics) RecordBulkItemTime(ctx context.Context, timeMs int64) {
+	if m == nil {
+		return
+	}
+	m.bulkItemTime.Record(ctx, timeMs)
+}
+
+// RecordQuoteStoreOperation counts a quote store operation by backend, operation and outcome and records its time
+func (m *Metrics) RecordQuoteStoreOperation(ctx context.Context, backend, operation, outcome string, timeMs int64) {
+	if m == nil {
+		return
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("store.backend", backend),
+		attribute.String("store.operation", operation),
+		attribute.String("store.outcome", outcome))
+	m.quoteStoreOperation.Add(ctx, 1, attrs)
+	m.quoteStoreTime.Record(ctx, timeMs, attrs)
+}
+
+// RecordDependencyProbe records the availability and the probe time of a dependency
+func (m *Metrics) RecordDependencyProbe(ctx context.Context, dependency string, up bool, timeMs int64) {
+	if m == nil {
+		return
+	}
+	attrs := metric.WithAttributes(attribute.String("dependency.name", dependency))
+	var value int64
+	if up {
+		value = 1
+	}
+	m.dependencyUp.Record(ctx, value, attrs)
+	m.dependencyProbeTime.Record(ctx, timeMs, attrs)
+}
+
+// RecordGoroutines records the number of running goroutines
+func (m *Metrics) RecordGoroutines(ctx context.Context, count int64) {
+	if m == nil {
+		return
+	}
+	m.runtimeGoroutines.Record(ctx, count)
+}
+
+// RecordGCPause records the duration of a garbage collection pause, in microseconds
+func (m *Metrics) RecordGCPause(ctx context.Context, pauseUs int64) {
+	if m == nil {
+		return
+	}
+	m.runtimeGCPause.Record(ctx, pauseUs)
 }
 
-// IncrementShipmentCalculateError increments the shipment calculation error counter
-func IncrementShipmentCalculateError(ctx context.Context) {
-	getInstance().shipmentCalculateError.Add(ctx, 1)
+// RecordSLADelivery counts a delivered shipment by carrier, route and outcome (on_time or late) and
+// records its delay in days, negative when delivered before the promised date
+func (m *Metrics) RecordSLADelivery(ctx context.Context, carrier, origin, destination, outcome string, delayDays int64) {
+	if m == nil {
+		return
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("carrier.name", carrier),
+		attribute.String("route.origin", origin),
+		attribute.String("route.destination", destination))
+	m.slaDelivery.Add(ctx, 1, attrs, metric.WithAttributes(attribute.String("sla.outcome", outcome)))
+	m.slaDelay.Record(ctx, delayDays, attrs)
 }